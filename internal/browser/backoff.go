@@ -0,0 +1,24 @@
+package browser
+
+import "time"
+
+// maxQueryDelay caps the continuous-querying backoff per RFC 6762 §5.2:
+// "the interval between the first two queries MUST be at least one second
+// ... and the intervals between successive queries MUST increase by at
+// least a factor of two ... up to a maximum of 60 minutes."
+const maxQueryDelay = 60 * time.Minute
+
+// nextQueryDelay computes the next continuous-query interval per RFC 6762
+// §5.2's doubling backoff: 0 (send immediately), then 1s, 2s, 4s, ...,
+// capped at 60 minutes.
+func nextQueryDelay(prev time.Duration) time.Duration {
+	if prev == 0 {
+		return 1 * time.Second
+	}
+
+	next := prev * 2
+	if next > maxQueryDelay {
+		return maxQueryDelay
+	}
+	return next
+}
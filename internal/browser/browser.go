@@ -0,0 +1,410 @@
+// Package browser implements continuous mDNS service discovery (browsing)
+// per RFC 6762 §4 and RFC 6763.
+//
+// Where the querier package issues one-shot queries and returns whatever
+// arrived within a timeout, Browser keeps a PTR query for a service type
+// running indefinitely, resolves each discovered instance's SRV/TXT/A/AAAA
+// records, and streams add/remove Events as instances come and go.
+package browser
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// eventBufferSize is the Events channel buffer per Browse() call. A modest
+// buffer absorbs bursts (e.g. many instances answering the initial query)
+// without blocking the receive loop on a slow consumer.
+const eventBufferSize = 32
+
+// EventType identifies what happened to a service instance.
+type EventType int
+
+const (
+	// EventAdded indicates a newly resolved service instance.
+	EventAdded EventType = iota
+
+	// EventRemoved indicates an instance whose PTR record expired or was
+	// withdrawn via a goodbye record (TTL=0) per RFC 6762 §10.1.
+	EventRemoved
+
+	// EventUpdated indicates an already-resolved instance whose SRV, TXT, or
+	// address records changed (e.g. a new port after a restart, or updated
+	// TXT metadata) without the instance itself disappearing.
+	EventUpdated
+)
+
+// String returns a human-readable name for the event type.
+func (e EventType) String() string {
+	switch e {
+	case EventAdded:
+		return "Added"
+	case EventRemoved:
+		return "Removed"
+	case EventUpdated:
+		return "Updated"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event reports a change to a discovered service instance.
+type Event struct {
+	Type EventType
+
+	// Instance is the discovered instance, reusing records.ServiceInfo so
+	// callers already familiar with the registration side's type don't need
+	// a second, near-identical struct for the discovery side.
+	Instance records.ServiceInfo
+}
+
+// Browser issues continuous mDNS PTR queries to discover service instances
+// and resolves each into a records.ServiceInfo.
+//
+// A Browser may run multiple independent Browse() sessions concurrently,
+// each for a different service type, sharing one transport and receive loop.
+type Browser struct {
+	transport transport.Transport
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+
+	// ownsTransport is true for a Browser built by New(), which opened
+	// transport itself and runs receiveLoop over it; Close() closes the
+	// transport only in that case. A Browser built by NewWithTransport
+	// shares a caller-owned transport (e.g. responder.Responder's) and
+	// caller-owned receive loop instead, so Close() leaves both alone.
+	ownsTransport bool
+
+	mu        sync.Mutex
+	sessions  map[string]*browseSession   // keyed by service type
+	resolvers map[string][]*resolveWaiter // keyed by instance name
+
+	// rateLimiter, if set, gates outbound queries (both Browse's continuous
+	// re-queries and Resolve's one-shot query) against its per-(record,
+	// interface) token bucket, the same records.RecordSet a responder uses
+	// to throttle its own announcements - so a process doing both can share
+	// one view of "how chatty has this network been". Nil (the default)
+	// sends immediately.
+	rateLimiter *records.RecordSet
+}
+
+// browserInterfaceID is the interfaceID records.RecordSet buckets are keyed
+// under for Browser's rate limiting. Like state.Announcer, Browser sends
+// through a single transport regardless of how many interfaces exist, so
+// there is only one multicast path to rate-limit.
+const browserInterfaceID = "default"
+
+// New creates a Browser, starting its background receive loop.
+func New() (*Browser, error) {
+	tr, err := transport.NewUDPv4Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &Browser{
+		transport:     tr,
+		ctx:           ctx,
+		cancel:        cancel,
+		sessions:      make(map[string]*browseSession),
+		resolvers:     make(map[string][]*resolveWaiter),
+		ownsTransport: true,
+	}
+
+	b.wg.Add(1)
+	go b.receiveLoop()
+
+	return b, nil
+}
+
+// NewWithTransport creates a Browser that sends over an existing transport
+// instead of opening its own, for a caller (e.g. responder.Responder.Browse)
+// that already runs a receive loop over that transport and will feed
+// Browser incoming messages itself via Dispatch. Unlike New, no background
+// receive loop is started here, and Close does not close transport - both
+// remain the caller's responsibility.
+func NewWithTransport(ctx context.Context, t transport.Transport) *Browser {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Browser{
+		transport: t,
+		ctx:       ctx,
+		cancel:    cancel,
+		sessions:  make(map[string]*browseSession),
+		resolvers: make(map[string][]*resolveWaiter),
+	}
+}
+
+// Dispatch routes a parsed message's answers to every active Browse
+// session and pending Resolve() waiter, for a caller that feeds Browser a
+// message it already parsed from its own receive loop rather than running
+// Browser's receiveLoop.
+func (b *Browser) Dispatch(msg *message.DNSMessage) {
+	b.dispatch(msg)
+}
+
+// SetRateLimiter attaches a records.RecordSet that gates every outbound
+// query against RFC 6762 §6.2's per-record rate limit, waiting out any
+// records.Reserve delay rather than sending over budget. A nil rate limiter
+// (the default) sends immediately.
+func (b *Browser) SetRateLimiter(rs *records.RecordSet) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rateLimiter = rs
+}
+
+// Browse starts (or resumes) continuous discovery of serviceType and returns
+// a channel of Events. The channel is closed when ctx is done or the
+// Browser is closed.
+//
+// Only one Browse session per service type may be active at a time.
+func (b *Browser) Browse(ctx context.Context, serviceType string) (<-chan Event, error) {
+	if err := protocol.ValidateName(serviceType); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	if _, exists := b.sessions[serviceType]; exists {
+		b.mu.Unlock()
+		return nil, &errors.ValidationError{
+			Field:   "serviceType",
+			Value:   serviceType,
+			Message: "already browsing this service type",
+		}
+	}
+
+	sessionCtx, sessionCancel := context.WithCancel(b.ctx)
+	session := &browseSession{
+		serviceType: serviceType,
+		events:      make(chan Event, eventBufferSize),
+		cache:       make(map[string]*cacheEntry),
+		ctx:         sessionCtx,
+		browser:     b,
+	}
+	b.sessions[serviceType] = session
+	b.mu.Unlock()
+
+	// Stop the session if the caller's context ends before the browser's.
+	go func() {
+		select {
+		case <-ctx.Done():
+			sessionCancel()
+		case <-sessionCtx.Done():
+		}
+	}()
+
+	b.wg.Add(1)
+	go b.runSession(sessionCtx, session)
+
+	return session.events, nil
+}
+
+// runSession drives a single service type's continuous-query backoff
+// schedule (RFC 6762 §5.2) until its context is done, then tears the
+// session down.
+func (b *Browser) runSession(ctx context.Context, session *browseSession) {
+	defer b.wg.Done()
+	defer b.endSession(session)
+
+	delay := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		b.sendQuery(ctx, session)
+		delay = nextQueryDelay(delay)
+	}
+}
+
+// sendQuery issues a PTR query for session's service type, including any
+// still-valid cached PTR answers as Known-Answers per RFC 6762 §7.1.
+func (b *Browser) sendQuery(ctx context.Context, session *browseSession) {
+	knownAnswers := session.knownAnswers()
+
+	var query []byte
+	var err error
+	if len(knownAnswers) > 0 {
+		query, err = message.BuildQueryWithKnownAnswers(session.serviceType, uint16(protocol.RecordTypePTR), knownAnswers)
+	} else {
+		query, err = message.BuildQuery(session.serviceType, uint16(protocol.RecordTypePTR))
+	}
+	if err != nil {
+		return
+	}
+
+	rr := &message.ResourceRecord{Name: session.serviceType, Type: protocol.RecordTypePTR, Class: protocol.ClassIN}
+	b.throttledSend(ctx, query, rr)
+}
+
+// throttledSend waits out any rate-limiter reservation for rr - a synthetic
+// record standing in for the query being sent, since records.RecordSet
+// tracks budget per (name, type) rather than per literal query - then sends
+// query to the mDNS multicast group. With no rate limiter attached, it sends
+// immediately.
+func (b *Browser) throttledSend(ctx context.Context, query []byte, rr *message.ResourceRecord) {
+	b.mu.Lock()
+	limiter := b.rateLimiter
+	b.mu.Unlock()
+
+	if limiter != nil {
+		if wait, ok := limiter.Reserve(rr, browserInterfaceID); ok && wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	mdnsAddr := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	_ = b.transport.Send(ctx, query, mdnsAddr)
+}
+
+// endSession removes session from the Browser and closes its Events
+// channel, evicting any cache entries and their pending timers.
+func (b *Browser) endSession(session *browseSession) {
+	b.mu.Lock()
+	delete(b.sessions, session.serviceType)
+	b.mu.Unlock()
+
+	session.stopAllTimers()
+	session.closeEvents()
+}
+
+// receiveLoop runs in the background, dispatching incoming mDNS messages to
+// whichever browse session(s) they match.
+func (b *Browser) receiveLoop() {
+	defer b.wg.Done()
+
+	for {
+		ctx, cancel := context.WithTimeout(b.ctx, 100*time.Millisecond)
+		data, _, err := b.transport.Receive(ctx)
+		cancel()
+
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		msg, err := message.ParseMessage(data)
+		if err != nil {
+			continue
+		}
+
+		b.dispatch(msg)
+	}
+}
+
+// dispatch routes a parsed message's answers to every active session whose
+// service type they belong to, and to any pending Resolve() waiters.
+func (b *Browser) dispatch(msg *message.DNSMessage) {
+	b.mu.Lock()
+	sessions := make([]*browseSession, 0, len(b.sessions))
+	for _, session := range b.sessions {
+		sessions = append(sessions, session)
+	}
+	waiters := make([]*resolveWaiter, 0)
+	for _, ws := range b.resolvers {
+		waiters = append(waiters, ws...)
+	}
+	b.mu.Unlock()
+
+	for _, session := range sessions {
+		session.handleAnswers(msg.Answers, msg.Additionals)
+	}
+
+	all := make([]message.Answer, 0, len(msg.Answers)+len(msg.Additionals))
+	all = append(all, msg.Answers...)
+	all = append(all, msg.Additionals...)
+	for _, w := range waiters {
+		for _, a := range all {
+			w.handleAnswer(a)
+		}
+	}
+}
+
+// Resolve issues a one-shot query for instance's SRV/TXT/A/AAAA records and
+// waits for enough of them to arrive to build a records.ServiceInfo, or for
+// ctx to end.
+//
+// Unlike Browse, Resolve does not keep querying or cache the result: it is
+// meant for a caller that already has an instance name (e.g. from a PTR
+// answer received some other way) and just wants it resolved once.
+func (b *Browser) Resolve(ctx context.Context, instance, serviceType string) (*records.ServiceInfo, error) {
+	if err := protocol.ValidateName(instance); err != nil {
+		return nil, err
+	}
+	if err := protocol.ValidateName(serviceType); err != nil {
+		return nil, err
+	}
+
+	w := &resolveWaiter{instance: instance, done: make(chan struct{})}
+
+	b.mu.Lock()
+	b.resolvers[instance] = append(b.resolvers[instance], w)
+	b.mu.Unlock()
+
+	defer b.removeResolver(w)
+
+	// RecordTypeANY pulls back SRV+TXT (and, per RFC 6763 §12, A/AAAA as
+	// additionals) in one round trip rather than querying each separately.
+	query, err := message.BuildQuery(instance, uint16(protocol.RecordTypeANY))
+	if err != nil {
+		return nil, err
+	}
+	rr := &message.ResourceRecord{Name: instance, Type: protocol.RecordTypeANY, Class: protocol.ClassIN}
+	b.throttledSend(ctx, query, rr)
+
+	select {
+	case <-w.done:
+		return w.serviceInfo(serviceType), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// removeResolver drops w from its instance's waiter list once Resolve
+// returns, whether it succeeded, timed out, or was canceled.
+func (b *Browser) removeResolver(w *resolveWaiter) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ws := b.resolvers[w.instance]
+	for i, candidate := range ws {
+		if candidate == w {
+			b.resolvers[w.instance] = append(ws[:i], ws[i+1:]...)
+			break
+		}
+	}
+	if len(b.resolvers[w.instance]) == 0 {
+		delete(b.resolvers, w.instance)
+	}
+}
+
+// Close shuts down the Browser: all active Browse sessions are ended (their
+// Events channels closed). The underlying transport is released too, unless
+// the Browser was built with NewWithTransport - in that case the transport
+// is the caller's and is left open.
+func (b *Browser) Close() error {
+	b.cancel()
+	b.wg.Wait()
+	if !b.ownsTransport {
+		return nil
+	}
+	return b.transport.Close()
+}
@@ -0,0 +1,349 @@
+package browser
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// newTestSession builds a browseSession bypassing Browser.New/Browse, so
+// tests can drive its cache logic directly without a real (or mock)
+// network round-trip.
+func newTestSession(t *testing.T, serviceType string) *browseSession {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	session := &browseSession{
+		serviceType: serviceType,
+		events:      make(chan Event, eventBufferSize),
+		cache:       make(map[string]*cacheEntry),
+		ctx:         ctx,
+		browser: &Browser{
+			transport: transport.NewMockTransport(),
+		},
+	}
+	t.Cleanup(session.stopAllTimers)
+	return session
+}
+
+// encodeNameOrFatal encodes name as a PTR/SRV RDATA target, using
+// EncodeOwnerName so instance labels with spaces (e.g. "My Printer._http...")
+// round-trip the same way a real responder's RDATA would.
+func encodeNameOrFatal(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := message.EncodeOwnerName(name)
+	if err != nil {
+		t.Fatalf("EncodeOwnerName(%q) failed: %v", name, err)
+	}
+	return encoded
+}
+
+func srvRDATA(t *testing.T, priority, weight, port uint16, target string) []byte {
+	t.Helper()
+	rdata := make([]byte, 6)
+	binary.BigEndian.PutUint16(rdata[0:2], priority)
+	binary.BigEndian.PutUint16(rdata[2:4], weight)
+	binary.BigEndian.PutUint16(rdata[4:6], port)
+	return append(rdata, encodeNameOrFatal(t, target)...)
+}
+
+func txtRDATA(kvs ...string) []byte {
+	var rdata []byte
+	for _, kv := range kvs {
+		rdata = append(rdata, byte(len(kv)))
+		rdata = append(rdata, kv...)
+	}
+	return rdata
+}
+
+// instanceAnswers builds the PTR/SRV/TXT/A answers a real responder would
+// send for one resolved instance.
+func instanceAnswers(t *testing.T, serviceType, instanceName, hostname string, port uint16, ip net.IP) (ptr, srv, txt, a message.Answer) {
+	t.Helper()
+
+	ptr = message.Answer{NAME: serviceType, TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 4500, RDATA: encodeNameOrFatal(t, instanceName)}
+	srv = message.Answer{NAME: instanceName, TYPE: uint16(protocol.RecordTypeSRV), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: srvRDATA(t, 0, 0, port, hostname)}
+	txt = message.Answer{NAME: instanceName, TYPE: uint16(protocol.RecordTypeTXT), CLASS: uint16(protocol.ClassIN), TTL: 4500, RDATA: txtRDATA("path=/")}
+	a = message.Answer{NAME: hostname, TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: ip.To4()}
+	return
+}
+
+// TestBrowseSession_ResolvesInstanceAndEmitsAdded validates that a PTR
+// answer plus its SRV/TXT/A companions (as a real responder sends them,
+// SRV/TXT/A in the Additional section per RFC 6763 §12) resolve into a
+// records.ServiceInfo and emit EventAdded.
+func TestBrowseSession_ResolvesInstanceAndEmitsAdded(t *testing.T) {
+	session := newTestSession(t, "_http._tcp.local")
+
+	ptr, srv, txt, a := instanceAnswers(t, "_http._tcp.local", "My Printer._http._tcp.local", "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+
+	session.handleAnswers([]message.Answer{ptr}, []message.Answer{srv, txt, a})
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventAdded {
+			t.Fatalf("event type = %v, want EventAdded", ev.Type)
+		}
+		if ev.Instance.InstanceName != "My Printer._http._tcp.local" {
+			t.Errorf("InstanceName = %q", ev.Instance.InstanceName)
+		}
+		if ev.Instance.Hostname != "printer.local" {
+			t.Errorf("Hostname = %q, want printer.local", ev.Instance.Hostname)
+		}
+		if ev.Instance.Port != 8080 {
+			t.Errorf("Port = %d, want 8080", ev.Instance.Port)
+		}
+		if got, _ := records.TXTValue(ev.Instance.TXTRecords, "path"); got != "/" {
+			t.Errorf("TXTRecords[path] = %q, want /", got)
+		}
+		if !net.IP(ev.Instance.IPv4Address).Equal(net.IPv4(192, 168, 1, 5)) {
+			t.Errorf("IPv4Address = %v, want 192.168.1.5", net.IP(ev.Instance.IPv4Address))
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventAdded received")
+	}
+}
+
+// TestBrowseSession_GoodbyeRecordEmitsRemoved validates that a PTR answer
+// with TTL=0 (RFC 6762 §10.1 goodbye record) evicts a resolved instance and
+// emits EventRemoved.
+func TestBrowseSession_GoodbyeRecordEmitsRemoved(t *testing.T) {
+	session := newTestSession(t, "_http._tcp.local")
+
+	ptr, srv, txt, a := instanceAnswers(t, "_http._tcp.local", "My Printer._http._tcp.local", "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+	session.handleAnswers([]message.Answer{ptr}, []message.Answer{srv, txt, a})
+
+	select {
+	case <-session.events:
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventAdded received before goodbye test")
+	}
+
+	goodbye := message.Answer{NAME: "_http._tcp.local", TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 0, RDATA: encodeNameOrFatal(t, "My Printer._http._tcp.local")}
+	session.handleAnswers([]message.Answer{goodbye}, nil)
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventRemoved {
+			t.Fatalf("event type = %v, want EventRemoved", ev.Type)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventRemoved received after goodbye record")
+	}
+
+	session.mu.Lock()
+	_, exists := session.cache["My Printer._http._tcp.local"]
+	session.mu.Unlock()
+	if exists {
+		t.Error("cache still holds an entry after goodbye record")
+	}
+}
+
+// TestBrowseSession_TTLExpiryEmitsRemoved validates that an instance whose
+// PTR record is never refreshed is evicted once its TTL fully elapses.
+func TestBrowseSession_TTLExpiryEmitsRemoved(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+
+	session := newTestSession(t, "_http._tcp.local")
+
+	shortTTL := message.Answer{NAME: "_http._tcp.local", TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 1, RDATA: encodeNameOrFatal(t, "My Printer._http._tcp.local")}
+	srv := message.Answer{NAME: "My Printer._http._tcp.local", TYPE: uint16(protocol.RecordTypeSRV), CLASS: uint16(protocol.ClassIN), TTL: 1, RDATA: srvRDATA(t, 0, 0, 8080, "printer.local")}
+	a := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 1, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+
+	session.handleAnswers([]message.Answer{shortTTL}, []message.Answer{srv, a})
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventAdded {
+			t.Fatalf("event type = %v, want EventAdded", ev.Type)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventAdded received")
+	}
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventRemoved {
+			t.Fatalf("event type = %v, want EventRemoved", ev.Type)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("instance was not evicted after TTL expiry")
+	}
+}
+
+// TestBrowseSession_KnownAnswers_TTLDecrements validates that knownAnswers()
+// reports a cached PTR record's remaining TTL, decremented by elapsed cache
+// time, per RFC 6762 §7.1.
+func TestBrowseSession_KnownAnswers_TTLDecrements(t *testing.T) {
+	session := newTestSession(t, "_http._tcp.local")
+
+	ptr := message.Answer{NAME: "_http._tcp.local", TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 4500, RDATA: encodeNameOrFatal(t, "My Printer._http._tcp.local")}
+	session.handleAnswers([]message.Answer{ptr}, nil)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	known := session.knownAnswers()
+	if len(known) != 1 {
+		t.Fatalf("knownAnswers() returned %d records, want 1", len(known))
+	}
+	if known[0].TTL >= 4500 {
+		t.Errorf("known answer TTL = %d, want < 4500 (elapsed time deducted)", known[0].TTL)
+	}
+}
+
+// TestBrowseSession_SRVChangeEmitsUpdated validates that an already-resolved
+// instance whose SRV record changes (e.g. it restarted on a new port) emits
+// EventUpdated rather than a second EventAdded.
+func TestBrowseSession_SRVChangeEmitsUpdated(t *testing.T) {
+	session := newTestSession(t, "_http._tcp.local")
+
+	const instance = "My Printer._http._tcp.local"
+	ptr, srv, txt, a := instanceAnswers(t, "_http._tcp.local", instance, "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+	session.handleAnswers([]message.Answer{ptr}, []message.Answer{srv, txt, a})
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventAdded {
+			t.Fatalf("event type = %v, want EventAdded", ev.Type)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventAdded received before SRV change test")
+	}
+
+	newPort := message.Answer{NAME: instance, TYPE: uint16(protocol.RecordTypeSRV), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: srvRDATA(t, 0, 0, 9090, "printer.local")}
+	session.handleAnswers([]message.Answer{newPort}, nil)
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventUpdated {
+			t.Fatalf("event type = %v, want EventUpdated", ev.Type)
+		}
+		if ev.Instance.Port != 9090 {
+			t.Errorf("Port = %d, want 9090", ev.Instance.Port)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventUpdated received after SRV port change")
+	}
+}
+
+// TestBrowseSession_TXTChangeEmitsUpdated validates that an already-resolved
+// instance whose TXT record changes emits EventUpdated.
+func TestBrowseSession_TXTChangeEmitsUpdated(t *testing.T) {
+	session := newTestSession(t, "_http._tcp.local")
+
+	const instance = "My Printer._http._tcp.local"
+	ptr, srv, txt, a := instanceAnswers(t, "_http._tcp.local", instance, "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+	session.handleAnswers([]message.Answer{ptr}, []message.Answer{srv, txt, a})
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventAdded {
+			t.Fatalf("event type = %v, want EventAdded", ev.Type)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventAdded received before TXT change test")
+	}
+
+	newTXT := message.Answer{NAME: instance, TYPE: uint16(protocol.RecordTypeTXT), CLASS: uint16(protocol.ClassIN), TTL: 4500, RDATA: txtRDATA("path=/v2")}
+	session.handleAnswers([]message.Answer{newTXT}, nil)
+
+	select {
+	case ev := <-session.events:
+		if ev.Type != EventUpdated {
+			t.Fatalf("event type = %v, want EventUpdated", ev.Type)
+		}
+		if got, _ := records.TXTValue(ev.Instance.TXTRecords, "path"); got != "/v2" {
+			t.Errorf("TXTRecords[path] = %q, want /v2", got)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no EventUpdated received after TXT change")
+	}
+}
+
+// TestNextQueryDelay_RFC6762_DoublingBackoff validates the continuous-query
+// backoff schedule per RFC 6762 §5.2: immediate, then 1s, doubling up to a
+// 60-minute cap.
+func TestNextQueryDelay_RFC6762_DoublingBackoff(t *testing.T) {
+	tests := []struct {
+		prev time.Duration
+		want time.Duration
+	}{
+		{0, 1 * time.Second},
+		{1 * time.Second, 2 * time.Second},
+		{2 * time.Second, 4 * time.Second},
+		{30 * time.Minute, 60 * time.Minute},
+		{60 * time.Minute, 60 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := nextQueryDelay(tt.prev); got != tt.want {
+			t.Errorf("nextQueryDelay(%v) = %v, want %v", tt.prev, got, tt.want)
+		}
+	}
+}
+
+// TestResolveWaiter_FiresOnceSRVAndAddrSeen validates that a resolveWaiter
+// closes its done channel only once it has seen both the SRV and A answers
+// for its instance, and that serviceInfo() reports the accumulated fields.
+func TestResolveWaiter_FiresOnceSRVAndAddrSeen(t *testing.T) {
+	const instance = "My Printer._http._tcp.local"
+	_, srv, txt, a := instanceAnswers(t, "_http._tcp.local", instance, "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+
+	w := &resolveWaiter{instance: instance, done: make(chan struct{})}
+
+	w.handleAnswer(txt)
+	select {
+	case <-w.done:
+		t.Fatal("done closed before SRV and A were seen")
+	default:
+	}
+
+	w.handleAnswer(srv)
+	w.handleAnswer(a)
+
+	select {
+	case <-w.done:
+	default:
+		t.Fatal("done not closed after SRV and A were seen")
+	}
+
+	info := w.serviceInfo("_http._tcp.local")
+	if info.Hostname != "printer.local" || info.Port != 8080 {
+		t.Errorf("Hostname/Port = %q/%d, want printer.local/8080", info.Hostname, info.Port)
+	}
+	if got, _ := records.TXTValue(info.TXTRecords, "path"); got != "/" {
+		t.Errorf("TXTRecords[path] = %q, want /", got)
+	}
+	if !net.IP(info.IPv4Address).Equal(net.IPv4(192, 168, 1, 5)) {
+		t.Errorf("IPv4Address = %v, want 192.168.1.5", net.IP(info.IPv4Address))
+	}
+}
+
+// TestResolveWaiter_AddrBeforeSRV validates that an A answer arriving before
+// its SRV still resolves once the SRV names that hostname, rather than being
+// discarded for not yet matching a (still-empty) hostname.
+func TestResolveWaiter_AddrBeforeSRV(t *testing.T) {
+	const instance = "My Printer._http._tcp.local"
+	_, srv, _, a := instanceAnswers(t, "_http._tcp.local", instance, "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+
+	w := &resolveWaiter{instance: instance, done: make(chan struct{})}
+	w.handleAnswer(a)
+	w.handleAnswer(srv)
+
+	select {
+	case <-w.done:
+	default:
+		t.Fatal("done not closed after SRV arrived for an already-seen address")
+	}
+}
@@ -0,0 +1,546 @@
+package browser
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
+)
+
+// refreshPercentages are the fractions of a cached record's TTL at which an
+// opportunistic refresh query is issued, per RFC 6762 §5.2: "...the
+// querier should plan to issue a query at 80% of the record lifetime, and
+// then if no answer is received, at 85%, 90%, and 95%."
+var refreshPercentages = []float64{0.80, 0.85, 0.90, 0.95}
+
+// refreshJitter is the ±2% random jitter RFC 6762 §5.2 recommends applying
+// to each refresh time, to avoid synchronized refresh storms.
+const refreshJitter = 0.02
+
+// browseSession tracks one Browse() call's service type, cache of
+// discovered instances, and the Events channel it streams to.
+type browseSession struct {
+	serviceType string
+	events      chan Event
+	ctx         context.Context
+	browser     *Browser
+
+	mu        sync.Mutex
+	cache     map[string]*cacheEntry // instance name -> entry
+	hostAddrs map[string][]net.IP    // SRV target hostname -> known addresses
+	closed    bool                   // true once closeEvents has run
+}
+
+// cacheEntry tracks one discovered instance's PTR lifetime and the
+// records.ServiceInfo being assembled for it from SRV/TXT/A/AAAA answers.
+type cacheEntry struct {
+	ttl       *records.RecordTTL
+	ptrRecord *message.ResourceRecord
+
+	instance records.ServiceInfo
+	resolved bool // true once enough records arrived to emit EventAdded
+
+	refreshTimers []*time.Timer
+	expiryTimer   *time.Timer
+}
+
+// knownAnswers returns the session's still-valid cached PTR records with
+// their TTL decremented by elapsed cache time, for RFC 6762 §7.1
+// Known-Answer inclusion in the next query.
+func (s *browseSession) knownAnswers() []*message.ResourceRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]records.KnownAnswer, 0, len(s.cache))
+	for _, entry := range s.cache {
+		if entry.ptrRecord == nil {
+			continue
+		}
+		entries = append(entries, records.KnownAnswer{Record: entry.ptrRecord, TTL: entry.ttl})
+	}
+	return records.BuildKnownAnswers(entries)
+}
+
+// handleAnswers processes one parsed mDNS message's Answer and Additional
+// sections (SRV/TXT/A/AAAA records accompanying a PTR answer normally ride
+// in the Additional section per RFC 6763 §12).
+func (s *browseSession) handleAnswers(answers, additionals []message.Answer) {
+	for _, a := range answers {
+		s.handleAnswer(a)
+	}
+	for _, a := range additionals {
+		s.handleAnswer(a)
+	}
+}
+
+func (s *browseSession) handleAnswer(a message.Answer) {
+	switch protocol.RecordType(a.TYPE) {
+	case protocol.RecordTypePTR:
+		s.handlePTR(a)
+	case protocol.RecordTypeSRV:
+		s.handleSRV(a)
+	case protocol.RecordTypeTXT:
+		s.handleTXT(a)
+	case protocol.RecordTypeA, protocol.RecordTypeAAAA:
+		s.handleAddr(a)
+	}
+}
+
+// handlePTR records or refreshes a discovered instance, or - for a TTL=0
+// goodbye record per RFC 6762 §10.1 - evicts it immediately.
+func (s *browseSession) handlePTR(a message.Answer) {
+	if a.NAME != s.serviceType {
+		return
+	}
+
+	target, err := message.ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return
+	}
+	ptr, ok := target.(message.PTRData)
+	if !ok {
+		return
+	}
+	name := ptr.Name
+
+	if a.TTL == 0 {
+		s.evict(name)
+		return
+	}
+
+	s.mu.Lock()
+	entry, exists := s.cache[name]
+	if exists && records.IsDuplicateAnswer(entry.ttl, a.TTL) {
+		// No fresher information than what's already cached; leave its
+		// refresh/expiry timers alone rather than resetting their clocks.
+		s.mu.Unlock()
+		return
+	}
+	if !exists {
+		entry = &cacheEntry{instance: records.ServiceInfo{InstanceName: name, ServiceType: s.serviceType}}
+		s.cache[name] = entry
+	} else {
+		entry.stopTimers()
+	}
+	entry.ttl = records.NewRecordTTL(protocol.RecordTypePTR, a.TTL)
+	entry.ptrRecord = &message.ResourceRecord{
+		Name: a.NAME,
+		Type: protocol.RecordTypePTR,
+		// RFC 6762 §10.2: strip the cache-flush bit before reusing CLASS as
+		// a plain Known-Answer record's class.
+		Class: protocol.DNSClass(a.CLASS &^ 0x8000),
+		TTL:   a.TTL,
+		Data:  a.RDATA,
+	}
+	entry.scheduleTimers(s, name)
+	s.mu.Unlock()
+}
+
+// handleSRV attaches a resolved hostname/port to the instance it targets, or
+// - if the instance was already resolved - emits EventUpdated when the
+// hostname or port changed (e.g. the instance restarted on a new port).
+func (s *browseSession) handleSRV(a message.Answer) {
+	data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return
+	}
+	srv, ok := data.(message.SRVData)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	entry, exists := s.cache[a.NAME]
+	var changed bool
+	var instance records.ServiceInfo
+	if exists {
+		changed = entry.resolved && (entry.instance.Hostname != srv.Target || entry.instance.Port != int(srv.Port))
+		entry.instance.Hostname = srv.Target
+		entry.instance.Port = int(srv.Port)
+		instance = entry.instance
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		return
+	}
+	if changed {
+		s.emit(Event{Type: EventUpdated, Instance: instance})
+		return
+	}
+	s.tryResolve(entry)
+}
+
+// handleTXT attaches parsed TXT metadata to the instance it describes, or -
+// if the instance was already resolved - emits EventUpdated when the
+// metadata changed.
+func (s *browseSession) handleTXT(a message.Answer) {
+	data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return
+	}
+	txtData, ok := data.(message.TXTData)
+	if !ok {
+		return
+	}
+
+	txtRecords := records.DecodeTXTEntries(txtData.Entries)
+
+	s.mu.Lock()
+	entry, exists := s.cache[a.NAME]
+	var changed bool
+	var instance records.ServiceInfo
+	if exists {
+		changed = entry.resolved && !reflect.DeepEqual(entry.instance.TXTRecords, txtRecords)
+		entry.instance.TXTRecords = txtRecords
+		instance = entry.instance
+	}
+	s.mu.Unlock()
+
+	if changed {
+		s.emit(Event{Type: EventUpdated, Instance: instance})
+	}
+}
+
+// handleAddr records a resolved address for a hostname and attempts to
+// resolve any instances waiting on it.
+func (s *browseSession) handleAddr(a message.Answer) {
+	data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return
+	}
+	var ip net.IP
+	switch d := data.(type) {
+	case message.AData:
+		ip = net.IP(d.IP.AsSlice())
+	case message.AAAAData:
+		ip = net.IP(d.IP.AsSlice())
+	default:
+		return
+	}
+
+	s.mu.Lock()
+	if s.hostAddrs == nil {
+		s.hostAddrs = make(map[string][]net.IP)
+	}
+	s.hostAddrs[a.NAME] = append(s.hostAddrs[a.NAME], ip)
+
+	var pending []*cacheEntry
+	var toUpdate []*cacheEntry
+	for _, entry := range s.cache {
+		if entry.instance.Hostname != a.NAME {
+			continue
+		}
+		if entry.resolved {
+			toUpdate = append(toUpdate, entry)
+		} else {
+			pending = append(pending, entry)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, entry := range pending {
+		s.tryResolve(entry)
+	}
+	for _, entry := range toUpdate {
+		s.refreshAddrs(entry)
+	}
+}
+
+// tryResolve emits EventAdded once an instance has both a hostname (from
+// SRV) and at least one address (from A/AAAA). TXT is optional - not every
+// service advertises metadata.
+func (s *browseSession) tryResolve(entry *cacheEntry) {
+	s.mu.Lock()
+	if entry.resolved || entry.instance.Hostname == "" {
+		s.mu.Unlock()
+		return
+	}
+	addrs := s.hostAddrs[entry.instance.Hostname]
+	if len(addrs) == 0 {
+		s.mu.Unlock()
+		return
+	}
+
+	assignAddrs(&entry.instance, addrs)
+	entry.resolved = true
+	instance := entry.instance
+	s.mu.Unlock()
+
+	s.emit(Event{Type: EventAdded, Instance: instance})
+}
+
+// refreshAddrs re-applies entry's current hostAddrs and emits EventUpdated
+// if the resolved addresses changed - a new A/AAAA answer for an instance
+// that had already been resolved and reported via EventAdded.
+func (s *browseSession) refreshAddrs(entry *cacheEntry) {
+	s.mu.Lock()
+	before := entry.instance
+	addrs := s.hostAddrs[entry.instance.Hostname]
+	assignAddrs(&entry.instance, addrs)
+	after := entry.instance
+	s.mu.Unlock()
+
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	s.emit(Event{Type: EventUpdated, Instance: after})
+}
+
+// assignAddrs splits addrs by IP version into info's IPv4Address (matching
+// records.ServiceInfo's single-address convention) and IPv6Addresses. The
+// last IPv4 address seen wins, consistent with records.BuildRecordSet only
+// ever emitting one A record per service.
+func assignAddrs(info *records.ServiceInfo, addrs []net.IP) {
+	info.IPv6Addresses = nil
+	for _, ip := range addrs {
+		if v4 := ip.To4(); v4 != nil {
+			info.IPv4Address = []byte(v4)
+			continue
+		}
+		if v6 := ip.To16(); v6 != nil {
+			info.IPv6Addresses = append(info.IPv6Addresses, []byte(v6))
+		}
+	}
+}
+
+// refresh is invoked by a cacheEntry's 80/85/90/95%-of-TTL timers to issue
+// an opportunistic re-query before the record would otherwise expire.
+func (s *browseSession) refresh(name string) {
+	s.mu.Lock()
+	_, exists := s.cache[name]
+	s.mu.Unlock()
+	if !exists {
+		return
+	}
+	s.browser.sendQuery(s.ctx, s)
+}
+
+// expire is invoked by a cacheEntry's TTL timer when no refresh renewed it
+// in time; the instance is evicted and EventRemoved fires if it had been
+// resolved.
+func (s *browseSession) expire(name string) {
+	s.evict(name)
+}
+
+// evict removes name's cache entry (if any), stops its timers, and emits
+// EventRemoved if the instance had previously been resolved.
+func (s *browseSession) evict(name string) {
+	s.mu.Lock()
+	entry, exists := s.cache[name]
+	if exists {
+		delete(s.cache, name)
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	entry.stopTimers()
+	if entry.resolved {
+		s.emit(Event{Type: EventRemoved, Instance: entry.instance})
+	}
+}
+
+// emit sends ev on the Events channel. Sends are dropped (rather than
+// blocking) once the buffer is full or the channel has been closed by
+// closeEvents, since multiple goroutines (the receive loop, refresh/expiry
+// timers) may race to emit after the session has started tearing down.
+func (s *browseSession) emit(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.events <- ev:
+	default:
+		// Buffer full - drop rather than block while holding the lock.
+	}
+}
+
+// closeEvents closes the Events channel, guarding against any in-flight
+// emit() call racing with the close. Must be called exactly once.
+func (s *browseSession) closeEvents() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	close(s.events)
+}
+
+// stopAllTimers stops every cache entry's timers, used when the session is
+// torn down.
+func (s *browseSession) stopAllTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.cache {
+		entry.stopTimers()
+	}
+}
+
+// scheduleTimers arms the refresh and expiry timers for a freshly
+// (re)learned PTR record. Callers must hold session's mu.
+func (e *cacheEntry) scheduleTimers(session *browseSession, name string) {
+	ttl := time.Duration(e.ttl.TTL) * time.Second
+
+	e.refreshTimers = make([]*time.Timer, 0, len(refreshPercentages))
+	for _, pct := range refreshPercentages {
+		delay := jitteredDelay(ttl, pct)
+		if delay <= 0 || delay >= ttl {
+			continue
+		}
+		e.refreshTimers = append(e.refreshTimers, time.AfterFunc(delay, func() {
+			session.refresh(name)
+		}))
+	}
+
+	e.expiryTimer = time.AfterFunc(ttl, func() {
+		session.expire(name)
+	})
+}
+
+// stopTimers cancels a cache entry's outstanding refresh/expiry timers.
+func (e *cacheEntry) stopTimers() {
+	for _, t := range e.refreshTimers {
+		t.Stop()
+	}
+	e.refreshTimers = nil
+
+	if e.expiryTimer != nil {
+		e.expiryTimer.Stop()
+		e.expiryTimer = nil
+	}
+}
+
+// jitteredDelay returns pct of ttl, perturbed by up to ±2% per RFC 6762
+// §5.2, to avoid many browsers refreshing the same record in lockstep.
+func jitteredDelay(ttl time.Duration, pct float64) time.Duration {
+	base := float64(ttl) * pct
+	jitter := 1 + refreshJitter*(2*rand.Float64()-1)
+	return time.Duration(base * jitter)
+}
+
+// resolveWaiter accumulates one in-flight Resolve() call's SRV/TXT/A/AAAA
+// answers. Unlike cacheEntry, it has no TTL tracking or timers: it fires
+// once and is discarded.
+type resolveWaiter struct {
+	instance string
+	done     chan struct{}
+
+	mu          sync.Mutex
+	hostname    string
+	port        int
+	txt         []records.TXTRecord
+	addrsByHost map[string][]net.IP // keyed by hostname, since an A/AAAA answer may arrive before the SRV that names its target
+	fired       bool
+}
+
+// handleAnswer folds one answer into w, closing done once both a hostname
+// (from SRV) and at least one address (from A/AAAA) are known. TXT is
+// optional, matching tryResolve's criteria for a browseSession's cache
+// entry.
+func (w *resolveWaiter) handleAnswer(a message.Answer) {
+	switch protocol.RecordType(a.TYPE) {
+	case protocol.RecordTypeSRV:
+		if a.NAME != w.instance {
+			return
+		}
+		data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+		if err != nil {
+			return
+		}
+		srv, ok := data.(message.SRVData)
+		if !ok {
+			return
+		}
+		w.mu.Lock()
+		w.hostname = srv.Target
+		w.port = int(srv.Port)
+		w.mu.Unlock()
+
+	case protocol.RecordTypeTXT:
+		if a.NAME != w.instance {
+			return
+		}
+		data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+		if err != nil {
+			return
+		}
+		txtData, ok := data.(message.TXTData)
+		if !ok {
+			return
+		}
+		w.mu.Lock()
+		w.txt = records.DecodeTXTEntries(txtData.Entries)
+		w.mu.Unlock()
+
+	case protocol.RecordTypeA, protocol.RecordTypeAAAA:
+		data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+		if err != nil {
+			return
+		}
+		var ip net.IP
+		switch d := data.(type) {
+		case message.AData:
+			ip = net.IP(d.IP.AsSlice())
+		case message.AAAAData:
+			ip = net.IP(d.IP.AsSlice())
+		default:
+			return
+		}
+		w.mu.Lock()
+		if w.addrsByHost == nil {
+			w.addrsByHost = make(map[string][]net.IP)
+		}
+		w.addrsByHost[a.NAME] = append(w.addrsByHost[a.NAME], ip)
+		w.mu.Unlock()
+
+	default:
+		return
+	}
+
+	w.tryFire()
+}
+
+// tryFire closes done the first time both a hostname and at least one
+// address for that hostname are known.
+func (w *resolveWaiter) tryFire() {
+	w.mu.Lock()
+	ready := !w.fired && w.hostname != "" && len(w.addrsByHost[w.hostname]) > 0
+	if ready {
+		w.fired = true
+	}
+	w.mu.Unlock()
+
+	if ready {
+		close(w.done)
+	}
+}
+
+// serviceInfo builds the records.ServiceInfo a completed Resolve() call
+// returns, from w's accumulated answers.
+func (w *resolveWaiter) serviceInfo(serviceType string) *records.ServiceInfo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info := &records.ServiceInfo{
+		InstanceName: w.instance,
+		ServiceType:  serviceType,
+		Hostname:     w.hostname,
+		Port:         w.port,
+		TXTRecords:   w.txt,
+	}
+	assignAddrs(info, w.addrsByHost[w.hostname])
+	return info
+}
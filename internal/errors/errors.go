@@ -19,6 +19,128 @@ import (
 	"fmt"
 )
 
+// Category groups a Code into one of a small number of buckets a caller can
+// switch on without enumerating every Code - for retry logic (is this worth
+// retrying at all?), metrics labeling (cardinality-bounded, unlike the
+// free-text message), or translating an error for an end user.
+type Category int
+
+const (
+	// CategoryUnknown is the zero value. No error type in this package
+	// reports it: each one's Category() depends on its type (and, for
+	// NetworkError, its Details), not on whether its Code was set.
+	CategoryUnknown Category = iota
+
+	// CategoryNetwork covers NetworkError's socket/IO failures, except a
+	// Details == "timeout" NetworkError, which reports CategoryTimeout
+	// instead.
+	CategoryNetwork
+
+	// CategoryValidation covers ValidationError's invalid-input failures.
+	CategoryValidation
+
+	// CategoryWireFormat covers WireFormatError's malformed-packet failures.
+	CategoryWireFormat
+
+	// CategoryTimeout covers a NetworkError whose Details is "timeout" -
+	// split out from CategoryNetwork because a caller's retry policy for
+	// "the network is gone" usually differs from "this one read took too
+	// long".
+	CategoryTimeout
+)
+
+// String returns Category's name, e.g. "network" or "timeout".
+func (c Category) String() string {
+	switch c {
+	case CategoryNetwork:
+		return "network"
+	case CategoryValidation:
+		return "validation"
+	case CategoryWireFormat:
+		return "wire_format"
+	case CategoryTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// Code identifies the specific failure condition behind a NetworkError,
+// ValidationError, or WireFormatError - a stable, switchable/metrics-label-
+// friendly alternative to string-matching .Error(). The zero value,
+// CodeUnspecified, means the construction site hasn't been annotated with a
+// more specific Code yet; Error() and Category() both still work normally.
+type Code int
+
+const (
+	// CodeUnspecified is Code's zero value - see Code's doc comment.
+	CodeUnspecified Code = iota
+
+	// Network-related codes (NetworkError).
+	CodeSocketCreate  // socket/listener creation failed
+	CodeSocketBind    // binding to the mDNS port failed
+	CodeMulticastJoin // joining a multicast group on an interface failed
+	CodeSendFailed    // writing a packet to the network failed
+	CodeReceiveFailed // reading a packet from the network failed
+	CodeInterfaceList // enumerating network interfaces failed
+
+	// Validation-related codes (ValidationError).
+	CodeInvalidName           // a domain/instance name failed validation
+	CodeUnsupportedRecordType // an unsupported DNS record type was requested
+	CodeInvalidValue          // a numeric/range-checked option value was invalid
+	CodeNilDependency         // a required callback/interface option was nil
+	CodeInvalidInterfaceList  // an explicit interface list option was empty
+	CodeInvalidTransportList  // an explicit transport plugin list option was empty or had a nil Transport
+	CodeInvalidNetworkMode    // an IPFamily/network-mode value was unrecognized
+	CodeBuilderState          // a message builder method was called out of RFC 1035 §4.1 section order
+	CodeNonZeroOpcode         // a header's OPCODE was non-zero per RFC 6762 §18.3
+	CodeNonZeroRCODE          // a header's RCODE was non-zero per RFC 6762 §18.11
+	CodeUnexpectedQR          // a header's QR bit didn't match the MessageKind being validated
+	CodeMissingAA             // a response/announcement header's AA bit was not set per RFC 6762 §18.4
+	CodeNonZeroReservedFlag   // a header's RD, RA, or Z was set where RFC 6762 §18 says it SHOULD be zero
+
+	// Wire-format codes (WireFormatError).
+	CodeTruncated             // not enough bytes remained to parse a field/record
+	CodeBadCompressionPointer // an RFC 1035 §4.1.4 compression pointer was invalid
+	CodeLabelTooLong          // a single label exceeded RFC 1035 §3.1's 63-byte limit
+	CodeNameTooLong           // a full name exceeded RFC 1035 §3.1's 255-byte limit
+	CodeRDataOverflow         // RDATA (or a sub-field within it) exceeded its wire length limit
+	CodeMessageTooLarge       // a message declared more records than MaxRecordsPerMessage
+)
+
+// codeSentinel is a minimal error that exists only as an errors.Is target:
+// "errors.Is(err, errors.ErrTruncated)" compares err's own Code against the
+// sentinel's, the same way the stdlib compares io.EOF by identity - except
+// here the comparison is by Code rather than pointer identity, since
+// NetworkError/ValidationError/WireFormatError carry far more
+// per-occurrence context (Operation, Offset, Value, ...) than a singleton
+// sentinel value could.
+type codeSentinel struct {
+	code Code
+}
+
+func (s *codeSentinel) Error() string {
+	return fmt.Sprintf("code %d", s.code)
+}
+
+// Sentinel errors for errors.Is, one per Code a caller is likely to want to
+// react to programmatically without needing the full Code enum.
+var (
+	ErrTruncated             error = &codeSentinel{code: CodeTruncated}
+	ErrBadCompressionPointer error = &codeSentinel{code: CodeBadCompressionPointer}
+	ErrLabelTooLong          error = &codeSentinel{code: CodeLabelTooLong}
+	ErrNameTooLong           error = &codeSentinel{code: CodeNameTooLong}
+	ErrRDataOverflow         error = &codeSentinel{code: CodeRDataOverflow}
+	ErrUnsupportedRecordType error = &codeSentinel{code: CodeUnsupportedRecordType}
+	ErrInvalidName           error = &codeSentinel{code: CodeInvalidName}
+	ErrNonZeroOpcode         error = &codeSentinel{code: CodeNonZeroOpcode}
+	ErrNonZeroRCODE          error = &codeSentinel{code: CodeNonZeroRCODE}
+	ErrUnexpectedQR          error = &codeSentinel{code: CodeUnexpectedQR}
+	ErrMissingAA             error = &codeSentinel{code: CodeMissingAA}
+	ErrNonZeroReservedFlag   error = &codeSentinel{code: CodeNonZeroReservedFlag}
+	ErrMessageTooLarge       error = &codeSentinel{code: CodeMessageTooLarge}
+)
+
 // NetworkError represents network-related failures such as socket creation,
 // binding, or I/O operations.
 //
@@ -35,6 +157,10 @@ type NetworkError struct {
 
 	// Details provides additional context for troubleshooting
 	Details string
+
+	// Code identifies the specific failure condition; CodeUnspecified if
+	// the construction site hasn't been annotated with one yet.
+	Code Code
 }
 
 // Error implements the error interface for NetworkError.
@@ -52,6 +178,24 @@ func (e *NetworkError) Unwrap() error {
 	return e.Err
 }
 
+// Category returns CategoryTimeout for a NetworkError whose Details is
+// "timeout" (the convention every transport in internal/transport already
+// uses to mark a read/write deadline expiring), CategoryNetwork otherwise.
+func (e *NetworkError) Category() Category {
+	if e.Details == "timeout" {
+		return CategoryTimeout
+	}
+	return CategoryNetwork
+}
+
+// Is reports whether target is a sentinel error (ErrTruncated, etc.) whose
+// Code matches e's own, so callers can write errors.Is(err, errors.ErrX)
+// instead of a type assertion plus a Code comparison.
+func (e *NetworkError) Is(target error) bool {
+	sentinel, ok := target.(*codeSentinel)
+	return ok && e.Code != CodeUnspecified && e.Code == sentinel.code
+}
+
 // ValidationError represents validation failures for query inputs such as
 // invalid names, unsupported record types, or out-of-range parameters.
 //
@@ -67,6 +211,10 @@ type ValidationError struct {
 
 	// Message describes why the validation failed
 	Message string
+
+	// Code identifies the specific failure condition; CodeUnspecified if
+	// the construction site hasn't been annotated with one yet.
+	Code Code
 }
 
 // Error implements the error interface for ValidationError.
@@ -79,6 +227,18 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
 }
 
+// Category always returns CategoryValidation.
+func (e *ValidationError) Category() Category {
+	return CategoryValidation
+}
+
+// Is reports whether target is a sentinel error (ErrInvalidName, etc.)
+// whose Code matches e's own - see NetworkError.Is.
+func (e *ValidationError) Is(target error) bool {
+	sentinel, ok := target.(*codeSentinel)
+	return ok && e.Code != CodeUnspecified && e.Code == sentinel.code
+}
+
 // WireFormatError represents errors parsing DNS wire format messages, including
 // malformed packets, invalid compression pointers, or truncated data.
 //
@@ -98,6 +258,10 @@ type WireFormatError struct {
 
 	// Err is the underlying error (if any)
 	Err error
+
+	// Code identifies the specific failure condition; CodeUnspecified if
+	// the construction site hasn't been annotated with one yet.
+	Code Code
 }
 
 // Error implements the error interface for WireFormatError.
@@ -121,3 +285,78 @@ func (e *WireFormatError) Error() string {
 func (e *WireFormatError) Unwrap() error {
 	return e.Err
 }
+
+// Category always returns CategoryWireFormat.
+func (e *WireFormatError) Category() Category {
+	return CategoryWireFormat
+}
+
+// Is reports whether target is a sentinel error (ErrTruncated, etc.) whose
+// Code matches e's own - see NetworkError.Is.
+func (e *WireFormatError) Is(target error) bool {
+	sentinel, ok := target.(*codeSentinel)
+	return ok && e.Code != CodeUnspecified && e.Code == sentinel.code
+}
+
+// MultiError aggregates more than one *WireFormatError found while parsing a
+// single message in message.ParseOptions.Lenient mode - one malformed
+// resource record doesn't mean only one thing went wrong with the packet, so
+// unlike every other error type here, a caller inspecting Errors sees every
+// offset that failed, not just the first.
+//
+// Example (recovering every per-record error, not just the first):
+//
+//	_, err := message.ParseMessageWithOptions(pkt, message.ParseOptions{Lenient: true})
+//	var multi *errors.MultiError
+//	if errors.As(err, &multi) {
+//	    for _, wfe := range multi.Errors {
+//	        log.Printf("skipped record at offset %d: %s", wfe.Offset, wfe.Message)
+//	    }
+//	}
+type MultiError struct {
+	// Errors holds every *WireFormatError collected, in the order their
+	// records were encountered, up to ParseOptions.MaxErrors.
+	Errors []*WireFormatError
+}
+
+// Error summarizes Errors: the single error's own message if there's only
+// one, otherwise a count plus the first error's message.
+func (m *MultiError) Error() string {
+	if len(m.Errors) == 1 {
+		return m.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d wire format errors, first: %s", len(m.Errors), m.Errors[0].Error())
+}
+
+// Unwrap returns every wrapped error, so errors.As(err, &target) and
+// errors.Is(err, errors.ErrX) can match against any one of them - not just
+// the first - per Go 1.20's multi-error Unwrap convention.
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, len(m.Errors))
+	for i, e := range m.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
+// ExtendedDNSError represents an Extended DNS Error (RFC 8914) carried in a
+// response's EDNS(0) OPT record, surfacing a non-zero extended RCODE's
+// INFO-CODE and optional human-readable EXTRA-TEXT instead of the bare
+// "RCODE != 0" fact a ValidationError would otherwise carry.
+type ExtendedDNSError struct {
+	// InfoCode identifies the specific error condition per the RFC 8914 §4
+	// registry (e.g. 1 = "Unsupported DNSKEY Algorithm", 22 = "No Reachable
+	// Authority").
+	InfoCode uint16
+
+	// ExtraText is the option's optional UTF-8 diagnostic text. May be empty.
+	ExtraText string
+}
+
+// Error implements the error interface for ExtendedDNSError.
+func (e *ExtendedDNSError) Error() string {
+	if e.ExtraText != "" {
+		return fmt.Sprintf("extended DNS error %d: %s", e.InfoCode, e.ExtraText)
+	}
+	return fmt.Sprintf("extended DNS error %d", e.InfoCode)
+}
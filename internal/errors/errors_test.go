@@ -111,10 +111,10 @@ func TestValidationError_Error(t *testing.T) {
 			name: "unsupported record type",
 			err: &ValidationError{
 				Field:   "recordType",
-				Value:   28, // AAAA (IPv6, not supported in M1)
-				Message: "unsupported record type: AAAA",
+				Value:   5, // CNAME's RR type number
+				Message: "unsupported record type: CNAME",
 			},
-			wantAll: []string{"validation error", "recordType", "unsupported record type: AAAA", "28"},
+			wantAll: []string{"validation error", "recordType", "unsupported record type: CNAME", "5"},
 		},
 		{
 			name: "invalid hostname characters",
@@ -204,6 +204,38 @@ func TestWireFormatError_Error(t *testing.T) {
 	}
 }
 
+// TestExtendedDNSError_Error validates that ExtendedDNSError.Error() includes
+// the INFO-CODE, with EXTRA-TEXT appended when present, per RFC 8914 §4.
+func TestExtendedDNSError_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *ExtendedDNSError
+		wantAll []string
+	}{
+		{
+			name:    "with extra text",
+			err:     &ExtendedDNSError{InfoCode: 22, ExtraText: "no reachable authority"},
+			wantAll: []string{"extended DNS error", "22", "no reachable authority"},
+		},
+		{
+			name:    "without extra text",
+			err:     &ExtendedDNSError{InfoCode: 1},
+			wantAll: []string{"extended DNS error", "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.err.Error()
+			for _, want := range tt.wantAll {
+				if !strings.Contains(got, want) {
+					t.Errorf("ExtendedDNSError.Error() missing expected substring:\ngot:  %q\nwant: %q", got, want)
+				}
+			}
+		})
+	}
+}
+
 // TestWireFormatError_Unwrap validates that WireFormatError.Unwrap() returns
 // the underlying error for error chain inspection.
 func TestWireFormatError_Unwrap(t *testing.T) {
@@ -293,3 +325,124 @@ func TestWireFormatError_AsError(t *testing.T) {
 		t.Error("errors.As(error, *WireFormatError) = false, want true")
 	}
 }
+
+// TestCategory_String validates Category.String() for every named constant
+// and the zero value.
+func TestCategory_String(t *testing.T) {
+	tests := []struct {
+		category Category
+		want     string
+	}{
+		{CategoryUnknown, "unknown"},
+		{CategoryNetwork, "network"},
+		{CategoryValidation, "validation"},
+		{CategoryWireFormat, "wire_format"},
+		{CategoryTimeout, "timeout"},
+		{Category(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.category.String(); got != tt.want {
+			t.Errorf("Category(%d).String() = %q, want %q", tt.category, got, tt.want)
+		}
+	}
+}
+
+// TestNetworkError_Category validates that Category() reports CategoryTimeout
+// only for the Details == "timeout" convention, CategoryNetwork otherwise.
+func TestNetworkError_Category(t *testing.T) {
+	if got := (&NetworkError{Operation: "read"}).Category(); got != CategoryNetwork {
+		t.Errorf("Category() = %v, want CategoryNetwork", got)
+	}
+	if got := (&NetworkError{Operation: "read", Details: "timeout"}).Category(); got != CategoryTimeout {
+		t.Errorf("Category() = %v, want CategoryTimeout", got)
+	}
+}
+
+// TestValidationError_Category validates that Category() always returns
+// CategoryValidation.
+func TestValidationError_Category(t *testing.T) {
+	if got := (&ValidationError{Field: "name"}).Category(); got != CategoryValidation {
+		t.Errorf("Category() = %v, want CategoryValidation", got)
+	}
+}
+
+// TestWireFormatError_Category validates that Category() always returns
+// CategoryWireFormat.
+func TestWireFormatError_Category(t *testing.T) {
+	if got := (&WireFormatError{Operation: "parse"}).Category(); got != CategoryWireFormat {
+		t.Errorf("Category() = %v, want CategoryWireFormat", got)
+	}
+}
+
+// TestIs_MatchesSentinelByCode validates that errors.Is matches a sentinel
+// (e.g. ErrTruncated) against any of the three error types whose Code equals
+// the sentinel's, and rejects both a mismatched Code and an unset
+// (CodeUnspecified) Code.
+func TestIs_MatchesSentinelByCode(t *testing.T) {
+	truncated := &WireFormatError{Operation: "parse name", Code: CodeTruncated}
+	if !errors.Is(truncated, ErrTruncated) {
+		t.Error("errors.Is(truncated WireFormatError, ErrTruncated) = false, want true")
+	}
+	if errors.Is(truncated, ErrBadCompressionPointer) {
+		t.Error("errors.Is(truncated WireFormatError, ErrBadCompressionPointer) = true, want false")
+	}
+
+	unset := &WireFormatError{Operation: "parse name"}
+	if errors.Is(unset, ErrTruncated) {
+		t.Error("errors.Is(unset WireFormatError, ErrTruncated) = true, want false for CodeUnspecified")
+	}
+
+	unsupported := &ValidationError{Field: "recordType", Code: CodeUnsupportedRecordType}
+	if !errors.Is(unsupported, ErrUnsupportedRecordType) {
+		t.Error("errors.Is(unsupported ValidationError, ErrUnsupportedRecordType) = false, want true")
+	}
+
+	badJoin := &NetworkError{Operation: "join multicast group", Code: CodeMulticastJoin}
+	if errors.Is(badJoin, ErrTruncated) {
+		t.Error("errors.Is(badJoin NetworkError, ErrTruncated) = true, want false")
+	}
+}
+
+// TestMultiError_Error validates the single-error and multi-error message
+// forms.
+func TestMultiError_Error(t *testing.T) {
+	one := &MultiError{Errors: []*WireFormatError{
+		{Operation: "parse answer", Offset: 12, Message: "bad RDLENGTH", Code: CodeTruncated},
+	}}
+	if got, want := one.Error(), one.Errors[0].Error(); got != want {
+		t.Errorf("Error() = %q, want %q (the single error's own message)", got, want)
+	}
+
+	two := &MultiError{Errors: []*WireFormatError{
+		{Operation: "parse answer", Offset: 12, Message: "bad RDLENGTH", Code: CodeTruncated},
+		{Operation: "parse answer", Offset: 40, Message: "bad compression pointer", Code: CodeBadCompressionPointer},
+	}}
+	if got := two.Error(); !strings.Contains(got, "2 wire format errors") || !strings.Contains(got, "bad RDLENGTH") {
+		t.Errorf("Error() = %q, want a count plus the first error's message", got)
+	}
+}
+
+// TestMultiError_UnwrapMatchesEveryError validates that errors.As can reach
+// any one of MultiError's wrapped errors, not just the first, via Go's
+// Unwrap() []error convention.
+func TestMultiError_UnwrapMatchesEveryError(t *testing.T) {
+	first := &WireFormatError{Operation: "parse answer", Offset: 12, Code: CodeTruncated}
+	second := &WireFormatError{Operation: "parse answer", Offset: 40, Code: CodeBadCompressionPointer}
+	multi := &MultiError{Errors: []*WireFormatError{first, second}}
+
+	if !errors.Is(multi, ErrTruncated) {
+		t.Error("errors.Is(multi, ErrTruncated) = false, want true (matches first's Code)")
+	}
+	if !errors.Is(multi, ErrBadCompressionPointer) {
+		t.Error("errors.Is(multi, ErrBadCompressionPointer) = false, want true (matches second's Code)")
+	}
+
+	var target *WireFormatError
+	if !errors.As(multi, &target) {
+		t.Fatal("errors.As(multi, &target) = false, want true")
+	}
+	if target != first {
+		t.Errorf("errors.As found %+v, want the first wrapped error %+v", target, first)
+	}
+}
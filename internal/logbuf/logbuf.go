@@ -0,0 +1,232 @@
+// Package logbuf provides a bounded in-memory ring buffer of slog.Record
+// values, so a long-running process can keep a rolling window of recent
+// structured log output ("flight recorder") without the cost of
+// persisting every record, and inspect or replay it on demand - e.g. when
+// a panic is recovered or a fatal error occurs, to see the Debug/Info
+// detail that led up to it even though the program's real log output is
+// normally filtered well above Debug.
+package logbuf
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// DefaultSize is the ring buffer size used when a non-positive size is
+// requested.
+const DefaultSize = 1000
+
+// Buffer is an slog.Handler that retains only the most recent size
+// records, overwriting the oldest once full, instead of emitting them
+// anywhere itself. It accepts every record regardless of level: capturing
+// only what the program's real handler would have shown defeats the
+// purpose of a flight recorder, so Buffer is meant to be attached via Tee
+// alongside - not instead of - that handler.
+//
+// Buffer is safe for concurrent use. WithAttrs/WithGroup return a new
+// Buffer value sharing the same underlying ring (so Snapshot/FlushTo see
+// records captured through any of them) but remembering the bound
+// attrs/open groups, applied to each record as it's stored - mirroring
+// how slog.Logger.With/WithGroup behave for a "real" handler.
+type Buffer struct {
+	ring *ring
+	goa  []groupOrAttrs // accumulated WithAttrs/WithGroup calls, outermost first
+}
+
+// groupOrAttrs is one link in the chain of WithAttrs/WithGroup calls that
+// produced a given Buffer value - either a set of bound attrs, or the name
+// of an opened group, never both.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// ring is Buffer's shared, mutex-guarded storage, referenced by every
+// Buffer value derived from the same NewBuffer call via WithAttrs/
+// WithGroup, so they all record into (and Snapshot reads from) the same
+// ring regardless of which derived Buffer's Handle was actually called.
+type ring struct {
+	mu      sync.Mutex
+	records []slog.Record // valid entries are records[:next] until full, then the whole slice
+	next    int
+	full    bool
+}
+
+// NewBuffer returns a Buffer retaining the last size records. A
+// non-positive size falls back to DefaultSize.
+func NewBuffer(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Buffer{ring: &ring{records: make([]slog.Record, size)}}
+}
+
+// Enabled always returns true: Buffer must capture every record handed to
+// it regardless of level, independent of whatever level the program's
+// other handler(s) are filtered to.
+func (b *Buffer) Enabled(context.Context, slog.Level) bool { return true }
+
+// Handle applies any attrs/groups bound via WithAttrs/WithGroup to a clone
+// of record, then stores it in the ring buffer, overwriting the oldest
+// entry once full. It never returns an error.
+func (b *Buffer) Handle(_ context.Context, record slog.Record) error {
+	record = applyGroupOrAttrs(record, b.goa)
+
+	b.ring.mu.Lock()
+	defer b.ring.mu.Unlock()
+	b.ring.records[b.ring.next] = record
+	b.ring.next = (b.ring.next + 1) % len(b.ring.records)
+	if b.ring.next == 0 {
+		b.ring.full = true
+	}
+	return nil
+}
+
+// WithAttrs returns a Buffer that applies attrs to every record it
+// handles from now on, nested under whatever group was most recently
+// opened via WithGroup - sharing this Buffer's underlying ring.
+func (b *Buffer) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return b
+	}
+	return b.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup returns a Buffer that nests every attr - both bound via a
+// later WithAttrs and present on each handled record - under name, from
+// now on, sharing this Buffer's underlying ring.
+func (b *Buffer) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return b
+	}
+	return b.withGroupOrAttrs(groupOrAttrs{group: name})
+}
+
+func (b *Buffer) withGroupOrAttrs(goa groupOrAttrs) *Buffer {
+	newGoa := make([]groupOrAttrs, len(b.goa)+1)
+	copy(newGoa, b.goa)
+	newGoa[len(b.goa)] = goa
+	return &Buffer{ring: b.ring, goa: newGoa}
+}
+
+// applyGroupOrAttrs rebuilds record with goa's bound attrs and open
+// groups applied, innermost (most recently added) first - the same
+// algorithm a from-scratch slog.Handler implementation uses to honor
+// Logger.With/WithGroup.
+func applyGroupOrAttrs(record slog.Record, goa []groupOrAttrs) slog.Record {
+	if len(goa) == 0 {
+		return record.Clone()
+	}
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	for i := len(goa) - 1; i >= 0; i-- {
+		if goa[i].group != "" {
+			attrs = []slog.Attr{slog.Group(goa[i].group, attrsAsAny(attrs)...)}
+			continue
+		}
+		attrs = append(append([]slog.Attr{}, goa[i].attrs...), attrs...)
+	}
+
+	out := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	out.AddAttrs(attrs...)
+	return out
+}
+
+func attrsAsAny(attrs []slog.Attr) []any {
+	out := make([]any, len(attrs))
+	for i, a := range attrs {
+		out[i] = a
+	}
+	return out
+}
+
+// Snapshot returns a copy of the buffer's current contents, oldest record
+// first.
+func (b *Buffer) Snapshot() []slog.Record {
+	b.ring.mu.Lock()
+	defer b.ring.mu.Unlock()
+
+	if !b.ring.full {
+		out := make([]slog.Record, b.ring.next)
+		copy(out, b.ring.records[:b.ring.next])
+		return out
+	}
+
+	out := make([]slog.Record, len(b.ring.records))
+	n := copy(out, b.ring.records[b.ring.next:])
+	copy(out[n:], b.ring.records[:b.ring.next])
+	return out
+}
+
+// FlushTo replays the buffer's current contents to logger at Error level,
+// preserving each record's original message and attributes (plus its
+// original time and level, since LogAttrs stamps its own), so the
+// flight-recorder detail leading up to a failure reaches whatever sink
+// the caller's logger is actually configured to show - intended for the
+// "something just went badly wrong" case: a recovered panic, or a fatal
+// probe/announce failure.
+//
+// logger must not itself be backed by (a Tee wrapping) this same Buffer,
+// or each replayed record would be re-captured into the ring it was just
+// read from; pass the handler/logger Buffer was Tee'd alongside, not the
+// Tee'd result.
+func (b *Buffer) FlushTo(ctx context.Context, logger *slog.Logger) {
+	for _, rec := range b.Snapshot() {
+		attrs := make([]slog.Attr, 0, rec.NumAttrs()+2)
+		attrs = append(attrs, slog.Time("original_time", rec.Time), slog.Any("original_level", rec.Level))
+		rec.Attrs(func(a slog.Attr) bool {
+			attrs = append(attrs, a)
+			return true
+		})
+		logger.LogAttrs(ctx, slog.LevelError, rec.Message, attrs...)
+	}
+}
+
+var _ slog.Handler = (*Buffer)(nil)
+
+// Tee returns an slog.Handler that forwards every record to buf
+// unconditionally (see Buffer.Enabled) and to next whenever next.Enabled
+// reports the record's level is wanted. Use it to attach a Buffer
+// alongside an existing handler rather than replacing it.
+func Tee(buf *Buffer, next slog.Handler) slog.Handler {
+	return &teeHandler{buf: buf, next: next}
+}
+
+type teeHandler struct {
+	buf  *Buffer
+	next slog.Handler
+}
+
+func (t *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return t.buf.Enabled(ctx, level) || t.next.Enabled(ctx, level)
+}
+
+func (t *teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	_ = t.buf.Handle(ctx, record)
+	if t.next.Enabled(ctx, record.Level) {
+		return t.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+func (t *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &teeHandler{buf: buf(t.buf.WithAttrs(attrs)), next: t.next.WithAttrs(attrs)}
+}
+
+func (t *teeHandler) WithGroup(name string) slog.Handler {
+	return &teeHandler{buf: buf(t.buf.WithGroup(name)), next: t.next.WithGroup(name)}
+}
+
+// buf asserts h back to *Buffer, which WithAttrs/WithGroup always return
+// (see Buffer.WithAttrs/WithGroup).
+func buf(h slog.Handler) *Buffer {
+	return h.(*Buffer)
+}
+
+var _ slog.Handler = (*teeHandler)(nil)
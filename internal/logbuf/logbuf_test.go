@@ -0,0 +1,195 @@
+package logbuf
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func record(msg string) slog.Record {
+	return slog.NewRecord(time.Time{}, slog.LevelInfo, msg, 0)
+}
+
+func TestBuffer_SnapshotEmpty(t *testing.T) {
+	b := NewBuffer(3)
+	if got := b.Snapshot(); len(got) != 0 {
+		t.Errorf("Snapshot() = %d records, want 0", len(got))
+	}
+}
+
+func TestBuffer_SnapshotBeforeFull_PreservesOrder(t *testing.T) {
+	b := NewBuffer(3)
+	for _, msg := range []string{"a", "b"} {
+		if err := b.Handle(context.Background(), record(msg)); err != nil {
+			t.Fatalf("Handle(%q) failed: %v", msg, err)
+		}
+	}
+
+	got := b.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("Snapshot() = %d records, want 2", len(got))
+	}
+	if got[0].Message != "a" || got[1].Message != "b" {
+		t.Errorf("Snapshot() = [%q, %q], want [a, b]", got[0].Message, got[1].Message)
+	}
+}
+
+func TestBuffer_OverwritesOldestOnceFull(t *testing.T) {
+	b := NewBuffer(2)
+	for _, msg := range []string{"a", "b", "c"} {
+		if err := b.Handle(context.Background(), record(msg)); err != nil {
+			t.Fatalf("Handle(%q) failed: %v", msg, err)
+		}
+	}
+
+	got := b.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("Snapshot() = %d records, want 2", len(got))
+	}
+	if got[0].Message != "b" || got[1].Message != "c" {
+		t.Errorf("Snapshot() = [%q, %q], want [b, c] (a should have been overwritten)", got[0].Message, got[1].Message)
+	}
+}
+
+func TestNewBuffer_NonPositiveSizeFallsBackToDefault(t *testing.T) {
+	b := NewBuffer(0)
+	if got := len(b.ring.records); got != DefaultSize {
+		t.Errorf("NewBuffer(0) ring size = %d, want DefaultSize (%d)", got, DefaultSize)
+	}
+}
+
+func TestBuffer_Enabled_AlwaysTrue(t *testing.T) {
+	b := NewBuffer(1)
+	for _, level := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		if !b.Enabled(context.Background(), level) {
+			t.Errorf("Enabled(%v) = false, want true", level)
+		}
+	}
+}
+
+func TestBuffer_FlushTo_ReplaysRecordsAtErrorLevel(t *testing.T) {
+	b := NewBuffer(4)
+	rec := slog.NewRecord(time.Time{}, slog.LevelDebug, "probe started", 0)
+	rec.AddAttrs(slog.String("service", "example._http._tcp.local"))
+	if err := b.Handle(context.Background(), rec); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	var flushed []slog.Record
+	logger := slog.New(recordingHandler{out: &flushed})
+
+	b.FlushTo(context.Background(), logger)
+
+	if len(flushed) != 1 {
+		t.Fatalf("FlushTo replayed %d records, want 1", len(flushed))
+	}
+	if flushed[0].Level != slog.LevelError {
+		t.Errorf("flushed record level = %v, want Error", flushed[0].Level)
+	}
+	if flushed[0].Message != "probe started" {
+		t.Errorf("flushed record message = %q, want %q", flushed[0].Message, "probe started")
+	}
+
+	var sawOriginalService bool
+	flushed[0].Attrs(func(a slog.Attr) bool {
+		if a.Key == "service" && a.Value.String() == "example._http._tcp.local" {
+			sawOriginalService = true
+		}
+		return true
+	})
+	if !sawOriginalService {
+		t.Error("flushed record lost the original \"service\" attr")
+	}
+}
+
+// TestBuffer_WithAttrs_CarriesBoundAttrsIntoStoredRecord guards against a
+// Buffer that silently drops attrs bound via a derived logger (e.g.
+// logger.With("component", "prober")) instead of carrying them into the
+// stored record, which would make Snapshot/FlushTo output indistinguishable
+// across subsystems.
+func TestBuffer_WithAttrs_CarriesBoundAttrsIntoStoredRecord(t *testing.T) {
+	b := NewBuffer(4)
+	bound := b.WithAttrs([]slog.Attr{slog.String("component", "prober")})
+
+	rec := slog.NewRecord(time.Time{}, slog.LevelInfo, "probe started", 0)
+	if err := bound.Handle(context.Background(), rec); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	got := b.Snapshot()
+	if len(got) != 1 {
+		t.Fatalf("Snapshot() = %d records, want 1", len(got))
+	}
+
+	var sawComponent bool
+	got[0].Attrs(func(a slog.Attr) bool {
+		if a.Key == "component" && a.Value.String() == "prober" {
+			sawComponent = true
+		}
+		return true
+	})
+	if !sawComponent {
+		t.Error("stored record is missing the \"component\" attr bound via WithAttrs")
+	}
+}
+
+func TestTee_ForwardsToBothHandlers(t *testing.T) {
+	b := NewBuffer(4)
+	var forwarded []slog.Record
+	tee := Tee(b, recordingHandler{out: &forwarded})
+
+	if !tee.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("Tee.Enabled(Debug) = false, want true (Buffer always accepts)")
+	}
+
+	rec := slog.NewRecord(time.Time{}, slog.LevelInfo, "announced", 0)
+	if err := tee.Handle(context.Background(), rec); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if got := b.Snapshot(); len(got) != 1 || got[0].Message != "announced" {
+		t.Errorf("buffer did not receive the record via Tee: %+v", got)
+	}
+	if len(forwarded) != 1 || forwarded[0].Message != "announced" {
+		t.Errorf("next handler did not receive the record via Tee: %+v", forwarded)
+	}
+}
+
+func TestTee_SkipsNextWhenNextDisabled(t *testing.T) {
+	b := NewBuffer(4)
+	next := recordingHandler{out: &[]slog.Record{}, minLevel: slog.LevelError}
+	var forwarded []slog.Record
+	next.out = &forwarded
+	tee := Tee(b, next)
+
+	rec := slog.NewRecord(time.Time{}, slog.LevelDebug, "noisy", 0)
+	if err := tee.Handle(context.Background(), rec); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if len(forwarded) != 0 {
+		t.Errorf("next handler received a Debug record despite its minLevel being Error: %+v", forwarded)
+	}
+	if got := b.Snapshot(); len(got) != 1 {
+		t.Errorf("buffer should still have captured the record regardless of next's level: %d records", len(got))
+	}
+}
+
+// recordingHandler is a minimal slog.Handler for assertions.
+type recordingHandler struct {
+	out      *[]slog.Record
+	minLevel slog.Level
+}
+
+func (h recordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.minLevel
+}
+
+func (h recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	*h.out = append(*h.out, record)
+	return nil
+}
+
+func (h recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h recordingHandler) WithGroup(string) slog.Handler      { return h }
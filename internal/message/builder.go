@@ -1,12 +1,8 @@
 // Package message implements DNS message construction per RFC 6762.
 package message
 
-// nosemgrep: beacon-external-dependencies
 import (
-	"crypto/rand" // Standard library, required for secure DNS query ID generation per gosec G404
 	"encoding/binary"
-	"math/big"
-	"strings"
 
 	"github.com/joshuafuller/beacon/internal/errors"
 	"github.com/joshuafuller/beacon/internal/protocol"
@@ -33,37 +29,294 @@ import (
 //
 // Parameters:
 //   - name: The DNS name to query (e.g., "printer.local")
-//   - recordType: The DNS record type (A=1, PTR=12, TXT=16, SRV=33)
+//   - recordType: The DNS record type (A=1, AAAA=28, PTR=12, TXT=16, SRV=33)
+//   - opts: Optional query customizations - WithEDNS appends an EDNS(0) OPT
+//     pseudo-RR per RFC 6891/RFC 6762 §18.11 (ARCOUNT incremented
+//     accordingly), advertising a UDP payload size larger than the
+//     traditional 512-byte default and carrying any EDNSOption
+//     attribute/value pairs the caller supplies; WithDNSSEC sets its DO bit.
+//     There is no separate BuildQueryWithOptions entry point - opts is
+//     BuildQuery's own variadic extension point, following the same
+//     functional-option shape as querier.Option.
+//
+// QNAME is written through the same compressing nameEncoder every other
+// builder in this package uses (see compression.go) - a single question is
+// never long enough to compress against itself, but BuildQueryWithKnownAnswers
+// shares one encoder across QNAME and every Known-Answer record, so a query
+// repeating "_http._tcp.local" across several PTR known-answers compresses
+// the same way BuildResponse's answers do. Decoding is the symmetric
+// ParseName, shared by Question/Answer parsing and PTR/SRV RDATA expansion
+// (decompressRDATANames) - one pointer-following implementation, with its
+// loop/depth guards (name.go's NameParser), for every name-bearing field.
 //
 // Returns:
 //   - query: The wire format DNS query message
 //   - error: ValidationError if name or recordType is invalid
-func BuildQuery(name string, recordType uint16) ([]byte, error) {
+func BuildQuery(name string, recordType uint16, opts ...QueryOption) ([]byte, error) {
 	// Validate record type per FR-002
 	if !protocol.RecordType(recordType).IsSupported() {
 		return nil, &errors.ValidationError{
 			Field:   "recordType",
 			Value:   recordType,
-			Message: "unsupported record type (M1 supports A, PTR, SRV, TXT)",
+			Message: "unsupported record type (supports A, AAAA, PTR, SRV, TXT)",
+			Code:    errors.CodeUnsupportedRecordType,
 		}
 	}
 
-	// Encode name per RFC 1035 §3.1 (this also validates per FR-003)
-	encodedName, err := EncodeName(name)
-	if err != nil {
-		return nil, err // EncodeName already returns ValidationError
+	var qo queryOptions
+	for _, opt := range opts {
+		opt(&qo)
 	}
 
-	// Build DNS header per RFC 6762 §18
+	// Build DNS header per RFC 6762 §18, with ARCOUNT=1 if an EDNS OPT
+	// record will follow the question section.
 	header := buildQueryHeader()
+	if qo.edns != nil {
+		binary.BigEndian.PutUint16(header[10:12], 1)
+	}
+
+	// Build question section per RFC 1035 §4.1.2, compressing QNAME per
+	// RFC 1035 §4.1.4 (a no-op here with only one name, but the same
+	// encoder is reused by BuildQueryWithKnownAnswers where it matters).
+	enc := newNameEncoder(header)
+	defer enc.release()
+	if err := enc.writeQuestion(name, recordType, false); err != nil {
+		return nil, err
+	}
+
+	if qo.edns != nil {
+		enc.writeOPTRecord(qo.edns)
+	}
+
+	return enc.Bytes(), nil
+}
+
+// BuildUnicastQuery constructs an mDNS query identical to BuildQuery except
+// that it sets the QU bit (the top bit of QCLASS) per RFC 6762 §5.4,
+// requesting that responders reply via unicast to the querier's source port
+// rather than to the multicast group.
+//
+// RFC 6762 §5.4 reserves this for the first query in a burst (when the
+// querier has nothing cached yet and wants an answer as fast as possible)
+// and for legacy-style one-shot resolvers; it is still sent to the
+// multicast group address, only the requested reply path changes.
+//
+// This is BuildQuery's existing QU-bit variant; querier.WithUnicastResponse
+// drives it automatically for a Querier's first unicastResponseQueryLimit
+// Query calls, and QueryUnicast always uses it, matched by a receive path
+// that listens on the query's own ephemeral unicast socket in addition to
+// 224.0.0.251:5353 (see Querier.queryUnicastLocked).
+//
+// Parameters:
+//   - name: The DNS name to query (e.g., "printer.local")
+//   - recordType: The DNS record type (A=1, AAAA=28, PTR=12, TXT=16, SRV=33)
+//
+// Returns:
+//   - query: The wire format DNS query message
+//   - error: ValidationError if name or recordType is invalid
+func BuildUnicastQuery(name string, recordType uint16) ([]byte, error) {
+	if !protocol.RecordType(recordType).IsSupported() {
+		return nil, &errors.ValidationError{
+			Field:   "recordType",
+			Value:   recordType,
+			Message: "unsupported record type (supports A, AAAA, PTR, SRV, TXT)",
+			Code:    errors.CodeUnsupportedRecordType,
+		}
+	}
+
+	header := buildQueryHeader()
+
+	enc := newNameEncoder(header)
+	defer enc.release()
+	if err := enc.writeQuestion(name, recordType, true); err != nil {
+		return nil, err
+	}
+
+	return enc.Bytes(), nil
+}
+
+// BuildQueryWithKnownAnswers constructs an mDNS query message that carries a
+// Known-Answer list in the Answer section per RFC 6762 §7.1.
+//
+// RFC 6762 §7.1: "when a Multicast DNS querier sends a query to which it
+// already knows some answers, it populates the Answer Section of the DNS
+// query message with those answers" so that responders already holding
+// those records can suppress their own responses.
+//
+// Parameters:
+//   - name: The DNS name to query (e.g., "_http._tcp.local")
+//   - recordType: The DNS record type (A=1, AAAA=28, PTR=12, TXT=16, SRV=33)
+//   - knownAnswers: Already-cached records to list as known answers, with
+//     TTL already adjusted for elapsed cache time
+//
+// This always produces one packet; a knownAnswers list long enough to push
+// the encoded message past a given MTU should go through QueryBuilder
+// instead, which splits across multiple packets and sets TC (RFC 6762
+// §7.2) on every packet but the last.
+//
+// Returns:
+//   - query: The wire format DNS query message
+//   - error: ValidationError if name or recordType is invalid
+func BuildQueryWithKnownAnswers(name string, recordType uint16, knownAnswers []*ResourceRecord) ([]byte, error) {
+	if !protocol.RecordType(recordType).IsSupported() {
+		return nil, &errors.ValidationError{
+			Field:   "recordType",
+			Value:   recordType,
+			Message: "unsupported record type (supports A, AAAA, PTR, SRV, TXT)",
+			Code:    errors.CodeUnsupportedRecordType,
+		}
+	}
 
-	// Build question section per RFC 1035 §4.1.2
-	question := buildQuestionSection(encodedName, recordType)
+	header := buildQueryHeaderWithAnswerCount(len(knownAnswers))
 
-	// Combine header + question
-	query := append(header, question...)
+	// One encoder spans the question and every Known-Answer record, so a
+	// Known-Answer sharing the query's own name (the common case: querying
+	// "_http._tcp.local" while already caching its PTR record) compresses
+	// to a 2-byte pointer per RFC 1035 §4.1.4.
+	enc := newNameEncoder(header)
+	defer enc.release()
+	if err := enc.writeQuestion(name, recordType, false); err != nil {
+		return nil, err
+	}
 
-	return query, nil
+	for _, answer := range knownAnswers {
+		if err := enc.writeResourceRecord(answer); err != nil {
+			return nil, err
+		}
+	}
+
+	return enc.Bytes(), nil
+}
+
+// BuildProbeQuery constructs an RFC 6762 §8.1 probe query: a QTYPE=ANY (255)
+// question for name, with tentative carried in the Authority section so a
+// host simultaneously probing the same name can tie-break per §8.2.1 without
+// a second round trip.
+//
+// name goes through the same writeQuestion path as BuildQuery, so a DNS-SD
+// service instance name (e.g. "My Printer._http._tcp.local", spaces and all)
+// is encoded via EncodeOwnerName exactly as it would be in the eventual
+// announcement - probing and announcing never disagree about how a name is
+// put on the wire.
+//
+// Parameters:
+//   - name: The full name being probed (e.g. "My Printer._http._tcp.local"
+//     or "host.local")
+//   - tentative: The records this host intends to claim, written into the
+//     Authority section per §8.2.1 for RDATA tie-breaking
+//
+// Returns:
+//   - query: The wire format DNS probe query message
+//   - error: ValidationError if name is invalid
+func BuildProbeQuery(name string, tentative []*ResourceRecord) ([]byte, error) {
+	header := buildQueryHeaderWithAuthorityCount(len(tentative))
+
+	// One encoder spans the question and every Authority record, so a
+	// tentative record sharing name (the common case: an SRV/TXT pair under
+	// the instance name being probed) compresses per RFC 1035 §4.1.4.
+	enc := newNameEncoder(header)
+	defer enc.release()
+	if err := enc.writeQuestion(name, uint16(protocol.RecordTypeANY), false); err != nil {
+		return nil, err
+	}
+
+	for _, record := range tentative {
+		if err := enc.writeResourceRecord(record); err != nil {
+			return nil, err
+		}
+	}
+
+	return enc.Bytes(), nil
+}
+
+// buildQueryHeaderWithAuthorityCount constructs a DNS query header identical
+// to buildQueryHeader, except with NSCOUNT set to nscount. Used by
+// BuildProbeQuery to carry tentative records in the Authority section
+// instead of buildQueryHeaderWithAnswerCount's Answer section.
+func buildQueryHeaderWithAuthorityCount(nscount int) []byte {
+	header := buildQueryHeaderWithAnswerCount(0)
+	// G115: bounded by the 9000-byte RFC 6762 §17 packet size limit, well under uint16 max.
+	binary.BigEndian.PutUint16(header[8:10], uint16(nscount)) //nolint:gosec // G115: see above
+	return header
+}
+
+// QuerySpec is one question for BuildMultiQuery: a name/record-type pair,
+// the same shape BuildQuery takes as separate parameters.
+type QuerySpec struct {
+	Name string
+	Type uint16
+}
+
+// BuildMultiQuery packs several questions into a single mDNS query message's
+// QD section, per RFC 6762 §7.1's requirement that a querier "SHOULD... put
+// as many questions... as will fit" in one packet, with knownAnswers
+// (already-cached, still-valid records) in the Answer section so responders
+// holding the same data can suppress their replies.
+//
+// Every question shares the one nameEncoder, so a knownAnswers record or
+// later question whose name is a suffix of an earlier one (e.g. multiple
+// questions all ending in "._tcp.local") compresses per RFC 1035 §4.1.4.
+//
+// querier.QueryAggregator is the intended caller: it batches AddQuery calls
+// made within its aggregation window into one QuerySpec slice before
+// flushing, so this function itself has no notion of timing or batching -
+// it only packs whatever it's given.
+//
+// Parameters:
+//   - questions: the QNAME/QTYPE pairs to ask, in order
+//   - knownAnswers: already-cached records to list as known answers, with
+//     TTL already adjusted for elapsed cache time
+//
+// Returns:
+//   - query: The wire format DNS query message
+//   - error: ValidationError if any question's recordType is unsupported, or
+//     if questions is empty
+func BuildMultiQuery(questions []QuerySpec, knownAnswers []*ResourceRecord) ([]byte, error) {
+	if len(questions) == 0 {
+		return nil, &errors.ValidationError{
+			Field:   "questions",
+			Message: "BuildMultiQuery requires at least one question",
+			Code:    errors.CodeInvalidName,
+		}
+	}
+
+	for _, q := range questions {
+		if !protocol.RecordType(q.Type).IsSupported() {
+			return nil, &errors.ValidationError{
+				Field:   "recordType",
+				Value:   q.Type,
+				Message: "unsupported record type (supports A, AAAA, PTR, SRV, TXT)",
+				Code:    errors.CodeUnsupportedRecordType,
+			}
+		}
+	}
+
+	header := buildMultiQueryHeader(len(questions), len(knownAnswers))
+
+	enc := newNameEncoder(header)
+	defer enc.release()
+	for _, q := range questions {
+		if err := enc.writeQuestion(q.Name, q.Type, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, answer := range knownAnswers {
+		if err := enc.writeResourceRecord(answer); err != nil {
+			return nil, err
+		}
+	}
+
+	return enc.Bytes(), nil
+}
+
+// buildMultiQueryHeader constructs a DNS query header identical to
+// buildQueryHeaderWithAnswerCount, except with QDCOUNT set to qdcount rather
+// than hard-coded to 1.
+func buildMultiQueryHeader(qdcount, ancount int) []byte {
+	header := buildQueryHeaderWithAnswerCount(ancount)
+	// G115: bounded by the 9000-byte RFC 6762 §17 packet size limit, well under uint16 max.
+	binary.BigEndian.PutUint16(header[4:6], uint16(qdcount)) //nolint:gosec // G115: see above
+	return header
 }
 
 // buildQueryHeader constructs a DNS header for an mDNS query per RFC 6762 §18.
@@ -78,18 +331,23 @@ func BuildQuery(name string, recordType uint16) ([]byte, error) {
 //
 // FR-020: System MUST set DNS header fields per RFC 6762 §18
 func buildQueryHeader() []byte {
+	return buildQueryHeaderWithAnswerCount(0)
+}
+
+// buildQueryHeaderWithAnswerCount constructs a DNS query header per RFC 6762
+// §18, setting ANCOUNT and the TC-independent flags exactly as
+// buildQueryHeader does. A non-zero ancount is used by
+// BuildQueryWithKnownAnswers to carry a Known-Answer list (RFC 6762 §7.1).
+func buildQueryHeaderWithAnswerCount(ancount int) []byte {
 	header := make([]byte, 12)
 
 	// ID: RFC 6762 §18.1 suggests 0, but M1 uses random ID for future compatibility
 	// Use crypto/rand for cryptographically secure random number generation (G404)
-	idBig, err := rand.Int(rand.Reader, big.NewInt(65536))
+	id, err := randomQueryID()
 	if err != nil {
 		// Fallback to 0 if crypto/rand fails (should not happen in practice)
-		idBig = big.NewInt(0)
+		id = 0
 	}
-	// G115: rand.Int is called with upper bound 65536, so result is in range [0, 65535]
-	// Safe conversion to uint16 using modulo to ensure no overflow
-	id := uint16(idBig.Uint64() % 65536) //nolint:gosec // G115: rand.Int bounds upper limit to 65536
 	binary.BigEndian.PutUint16(header[0:2], id)
 
 	// Flags: Set per RFC 6762 §18
@@ -101,8 +359,9 @@ func buildQueryHeader() []byte {
 	// QDCOUNT: 1 question
 	binary.BigEndian.PutUint16(header[4:6], 1)
 
-	// ANCOUNT: 0 answers (queries don't have answers)
-	binary.BigEndian.PutUint16(header[6:8], 0)
+	// ANCOUNT: number of Known-Answer records (0 for a plain query)
+	// G115: bounded by the 9000-byte RFC 6762 §17 packet size limit, well under uint16 max.
+	binary.BigEndian.PutUint16(header[6:8], uint16(ancount)) //nolint:gosec // G115: see above
 
 	// NSCOUNT: 0 authority records
 	binary.BigEndian.PutUint16(header[8:10], 0)
@@ -113,35 +372,6 @@ func buildQueryHeader() []byte {
 	return header
 }
 
-// buildQuestionSection constructs a DNS question section per RFC 1035 §4.1.2.
-//
-// Question format:
-//   - QNAME (variable): Encoded domain name (length-prefixed labels)
-//   - QTYPE (2 bytes): Query type (A, PTR, SRV, TXT)
-//   - QCLASS (2 bytes): Query class (IN=1, QU bit=0 for multicast)
-//
-// FR-001: System MUST construct valid mDNS query messages per RFC 6762
-func buildQuestionSection(encodedName []byte, recordType uint16) []byte {
-	// Question section size: name + QTYPE (2) + QCLASS (2)
-	question := make([]byte, 0, len(encodedName)+4)
-
-	// QNAME: Already encoded by EncodeName
-	question = append(question, encodedName...)
-
-	// QTYPE: Record type (2 bytes, big-endian)
-	qtype := make([]byte, 2)
-	binary.BigEndian.PutUint16(qtype, recordType)
-	question = append(question, qtype...)
-
-	// QCLASS: IN (1) with QU bit=0 per RFC 6762 §5.4
-	// M1 uses standard multicast queries (QU=0)
-	qclass := make([]byte, 2)
-	binary.BigEndian.PutUint16(qclass, uint16(protocol.ClassIN)) // 0x0001
-	question = append(question, qclass...)
-
-	return question
-}
-
 // BuildResponse constructs an mDNS response message per RFC 6762 §18.
 //
 // The response message consists of:
@@ -158,6 +388,24 @@ func buildQuestionSection(encodedName []byte, recordType uint16) []byte {
 // FR-023: System MUST construct valid mDNS response messages per RFC 6762
 // T012: Implement BuildResponse() to make T011 tests pass
 //
+// BuildResponse is scoped to unsolicited announcements (RFC 6762 §8.3's
+// probe-free announce, and §10.1 goodbye packets): QDCOUNT is always 0, the
+// way RFC 6762 §6 specifies for a response nobody asked for, and every
+// answer goes out at its full configured TTL. state.Announcer is this
+// function's caller.
+//
+// A response to an actual incoming query is a different problem: RFC 6762
+// §6.7 requires echoing the question and clamping TTLs to 10s when the
+// query came from a legacy unicast resolver (a non-5353 source port), and
+// §5.4's QU bit can call for a unicast reply to one question while sibling
+// questions in the same packet still want multicast. responder.Responder
+// doesn't route through BuildResponse for this - it builds the
+// message.DNSMessage for each matched question directly and sends it via
+// sendQueryResponse, which applies isLegacyUnicastSource/
+// clampLegacyUnicastTTLs and unicastDest per question, so a multi-question
+// query naturally yields one unicast reply and one multicast reply rather
+// than requiring this function to split its own output.
+//
 // Parameters:
 //   - answers: The resource records to include in the answer section
 //
@@ -165,23 +413,60 @@ func buildQuestionSection(encodedName []byte, recordType uint16) []byte {
 //   - []byte: The wire format DNS response message
 //   - error: ValidationError if answers are invalid
 func BuildResponse(answers []*ResourceRecord) ([]byte, error) {
+	return BuildResponseWithAdditionals(answers, nil)
+}
+
+// BuildResponseWithAdditionals builds the same response as BuildResponse,
+// plus an Additional section (RFC 6762 §6: SRV/TXT/A/AAAA bundled alongside
+// a PTR answer, so a DNS-SD resolve doesn't need the follow-up round trips
+// RFC 6763 §12 calls out). additionals share the same nameEncoder as
+// answers, so a name repeated across the two sections - an SRV answer's
+// owner name reappearing as its own additional A record's owner name, for
+// instance - compresses per RFC 1035 §4.1.4 same as within the answer
+// section alone.
+func BuildResponseWithAdditionals(answers, additionals []*ResourceRecord) ([]byte, error) {
 	// Build response header
-	header := buildResponseHeader(len(answers))
+	header := buildResponseHeader(len(answers), len(additionals))
+
+	// One encoder spans every answer and additional record, so repeated
+	// suffixes (a service's "_service._proto.local" across PTR/SRV/TXT, or
+	// a host's "host.local" across SRV/A/AAAA) compress per RFC 1035 §4.1.4
+	// instead of being spelled out on every record.
+	enc := newNameEncoder(header)
+	defer enc.release()
+	for _, answer := range answers {
+		if err := enc.writeResourceRecord(answer); err != nil {
+			return nil, err
+		}
+	}
+	for _, additional := range additionals {
+		if err := enc.writeResourceRecord(additional); err != nil {
+			return nil, err
+		}
+	}
 
-	// Start with header
-	response := make([]byte, 0, 512) // Pre-allocate reasonable size
-	response = append(response, header...)
+	return enc.Bytes(), nil
+}
 
-	// Add answer records
+// BuildResponseUncompressed builds the same response as BuildResponse, but
+// every owner name and RDATA-embedded name (SRV target, PTR rdata) is
+// spelled out in full rather than compressed per RFC 1035 §4.1.4 - the
+// BuildResponse/EncodeMessage(msg, compress=false) pairing of "apply
+// compression" and "skip it" entry points. Tests asserting byte-for-byte
+// wire layout, and interoperability debugging against a peer's capture,
+// want output that doesn't shift every time a repeated suffix does or
+// doesn't land within compression range.
+func BuildResponseUncompressed(answers []*ResourceRecord) ([]byte, error) {
+	header := buildResponseHeader(len(answers), 0)
+
+	enc := newUncompressedNameEncoder(header)
 	for _, answer := range answers {
-		answerBytes, err := serializeResourceRecord(answer)
-		if err != nil {
+		if err := enc.writeResourceRecord(answer); err != nil {
 			return nil, err
 		}
-		response = append(response, answerBytes...)
 	}
 
-	return response, nil
+	return enc.Bytes(), nil
 }
 
 // buildResponseHeader constructs a DNS header for an mDNS response per RFC 6762 §18.
@@ -192,11 +477,11 @@ func BuildResponse(answers []*ResourceRecord) ([]byte, error) {
 //   - QDCOUNT (2 bytes): Number of questions (0 for unsolicited responses)
 //   - ANCOUNT (2 bytes): Number of answers
 //   - NSCOUNT (2 bytes): Number of authority records (0)
-//   - ARCOUNT (2 bytes): Number of additional records (0 for now)
+//   - ARCOUNT (2 bytes): Number of additional records
 //
 // FR-023: System MUST set response header fields per RFC 6762 §18
 // T012: Build response headers with QR=1, AA=1
-func buildResponseHeader(answerCount int) []byte {
+func buildResponseHeader(answerCount, additionalCount int) []byte {
 	header := make([]byte, 12)
 
 	// ID: RFC 6762 §18.1 recommends 0 for responses
@@ -223,116 +508,13 @@ func buildResponseHeader(answerCount int) []byte {
 	// NSCOUNT: 0 authority records
 	binary.BigEndian.PutUint16(header[8:10], 0)
 
-	// ARCOUNT: 0 additional records (for now)
-	binary.BigEndian.PutUint16(header[10:12], 0)
-
-	return header
-}
-
-// serializeResourceRecord serializes a resource record to wire format.
-//
-// Resource record format per RFC 1035 §3.2.1:
-//   - NAME (variable): Domain name
-//   - TYPE (2 bytes): Record type (A, PTR, SRV, TXT)
-//   - CLASS (2 bytes): Class (IN=1), with cache-flush bit if set
-//   - TTL (4 bytes): Time to live in seconds
-//   - RDLENGTH (2 bytes): Length of RDATA
-//   - RDATA (variable): Record data
-//
-// RFC 6762 §10.2: Cache-flush bit (bit 15 of CLASS) for unique records
-//
-// T012: Serialize resource records with cache-flush support
-func serializeResourceRecord(rr *ResourceRecord) ([]byte, error) {
-	if rr == nil {
-		return nil, &errors.ValidationError{
-			Field:   "ResourceRecord",
-			Value:   nil,
-			Message: "cannot serialize nil resource record",
-		}
-	}
-
-	// Encode the domain name
-	// Detect service instance names per RFC 6763 §4.3:
-	// If the name contains a service type pattern (_service._proto.local),
-	// split it and encode the instance portion separately to allow UTF-8/spaces.
-	var encodedName []byte
-	var err error
-
-	// Check if this is a service instance name format: "instance._service._proto.local"
-	// Pattern: contains "._" which indicates a service type
-	if strings.Contains(rr.Name, "._") {
-		// Split into instance name and service type
-		parts := strings.SplitN(rr.Name, "._", 2)
-		if len(parts) == 2 {
-			// parts[0] = instance name (may contain spaces/UTF-8)
-			// parts[1] = service type (e.g., "http._tcp.local")
-			instanceName := parts[0]
-			serviceType := "_" + parts[1] // Restore leading underscore
-
-			// Use special encoding for service instance names
-			encodedName, err = EncodeServiceInstanceName(instanceName, serviceType)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			// Fallback to normal encoding
-			encodedName, err = EncodeName(rr.Name)
-			if err != nil {
-				return nil, err
-			}
-		}
-	} else {
-		// Normal DNS name (not a service instance)
-		encodedName, err = EncodeName(rr.Name)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// Calculate total size
-	recordSize := len(encodedName) + 10 + len(rr.Data) // name + type(2) + class(2) + ttl(4) + rdlength(2) + rdata
-
-	record := make([]byte, 0, recordSize)
-
-	// NAME
-	record = append(record, encodedName...)
-
-	// TYPE (2 bytes)
-	typeBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(typeBytes, uint16(rr.Type))
-	record = append(record, typeBytes...)
-
-	// CLASS (2 bytes) with cache-flush bit if requested
-	class := uint16(rr.Class)
-	if rr.CacheFlush {
-		// Set cache-flush bit (bit 15) per RFC 6762 §10.2
-		class |= 0x8000
+	// ARCOUNT: Number of additional records
+	if additionalCount > 65535 { //nolint:gosec // G115: bounds checked, max message size 9000 bytes
+		additionalCount = 65535 // Cap at maximum uint16
 	}
-	classBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(classBytes, class)
-	record = append(record, classBytes...)
-
-	// TTL (4 bytes)
-	ttlBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(ttlBytes, rr.TTL)
-	record = append(record, ttlBytes...)
-
-	// RDLENGTH (2 bytes)
-	// G115: RFC 1035 §3.2.1 specifies RDLENGTH as uint16, max 65535. DNS message size
-	// limit (9000 bytes per RFC 6762) ensures rdata length never exceeds uint16.
-	// Defensive bounds check for safety.
-	rdataLen := len(rr.Data)
-	if rdataLen > 65535 { //nolint:gosec // G115: bounds checked, max message size 9000 bytes
-		rdataLen = 65535 // Cap at maximum uint16
-	}
-	rdlengthBytes := make([]byte, 2)
-	binary.BigEndian.PutUint16(rdlengthBytes, uint16(rdataLen))
-	record = append(record, rdlengthBytes...)
+	binary.BigEndian.PutUint16(header[10:12], uint16(additionalCount)) //nolint:gosec // G115: bounds checked above
 
-	// RDATA
-	record = append(record, rr.Data...)
-
-	return record, nil
+	return header
 }
 
 // ResourceRecord represents a DNS resource record for response building.
@@ -213,6 +213,37 @@ func TestBuildQuery_RFC1035_QClass(t *testing.T) {
 	}
 }
 
+// TestBuildUnicastQuery_SetsQUBit validates that BuildUnicastQuery sets the
+// QU bit (bit 15 of QCLASS) per RFC 6762 §5.4, while leaving everything else
+// identical to a plain BuildQuery.
+func TestBuildUnicastQuery_SetsQUBit(t *testing.T) {
+	query, err := BuildUnicastQuery("test.local", 1) // A record
+	if err != nil {
+		t.Fatalf("BuildUnicastQuery failed: %v", err)
+	}
+
+	offset := 12 // Start after header
+	for offset < len(query) {
+		length := query[offset]
+		if length == 0 {
+			offset++
+			break
+		}
+		offset += 1 + int(length)
+	}
+
+	if offset+4 > len(query) {
+		t.Fatalf("query too short to contain QTYPE and QCLASS at offset %d", offset)
+	}
+
+	qclass := binary.BigEndian.Uint16(query[offset+2 : offset+4])
+
+	expectedQClass := uint16(0x8001) // IN class with QU bit set
+	if qclass != expectedQClass {
+		t.Errorf("QCLASS is 0x%04X, expected 0x%04X (IN class, QU=1) per RFC 6762 §5.4", qclass, expectedQClass)
+	}
+}
+
 // TestBuildQuery_RFC1035_NameEncoding validates that BuildQuery correctly
 // encodes DNS names per RFC 1035 §3.1 (FR-001, FR-003).
 //
@@ -319,11 +350,11 @@ func TestBuildQuery_UnsupportedRecordType(t *testing.T) {
 		qtype uint16
 	}{
 		{
-			name:  "AAAA record (IPv6) - not supported in M1",
-			qtype: 28,
+			name:  "SPF record - not supported",
+			qtype: 99,
 		},
 		{
-			name:  "MX record - not supported in M1",
+			name:  "MX record - not supported",
 			qtype: 15,
 		},
 		{
@@ -356,6 +387,10 @@ func TestBuildQuery_SupportedRecordTypes(t *testing.T) {
 			name:  "A record (1)",
 			qtype: 1,
 		},
+		{
+			name:  "AAAA record (28)",
+			qtype: 28,
+		},
 		{
 			name:  "PTR record (12)",
 			qtype: 12,
@@ -425,3 +460,139 @@ func TestBuildQuery_MessageID(t *testing.T) {
 	// Just verify it's present (any value is acceptable for M1)
 	t.Logf("Message ID: 0x%04X (RFC 6762 §18.1 suggests 0, but any value acceptable)", id)
 }
+
+// TestBuildQueryWithKnownAnswers_ANCount validates that the Known-Answer
+// list is reflected in ANCOUNT and appended after the question section per
+// RFC 6762 §7.1.
+func TestBuildQueryWithKnownAnswers_ANCount(t *testing.T) {
+	knownAnswers := []*ResourceRecord{
+		{
+			Name:  "_http._tcp.local",
+			Type:  12, // PTR
+			Class: 1,
+			TTL:   2250, // 50% of the full 4500s TTL
+			Data:  []byte{0x03, 'f', 'o', 'o', 0x00},
+		},
+	}
+
+	query, err := BuildQueryWithKnownAnswers("_http._tcp.local", 12, knownAnswers)
+	if err != nil {
+		t.Fatalf("BuildQueryWithKnownAnswers failed: %v", err)
+	}
+
+	ancount := binary.BigEndian.Uint16(query[6:8])
+	if ancount != 1 {
+		t.Errorf("ANCOUNT = %d, want 1", ancount)
+	}
+
+	plainQuery, err := BuildQuery("_http._tcp.local", 12)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+	if len(query) <= len(plainQuery) {
+		t.Errorf("query with known answers (%d bytes) should be longer than plain query (%d bytes)", len(query), len(plainQuery))
+	}
+}
+
+// TestBuildProbeQuery_ANYQuestionAndAuthority validates that BuildProbeQuery
+// asks QTYPE=ANY (RFC 6762 §8.1) and carries tentative in the Authority
+// section (§8.2.1), with a DNS-SD instance name allowed to contain spaces.
+func TestBuildProbeQuery_ANYQuestionAndAuthority(t *testing.T) {
+	tentative := []*ResourceRecord{
+		{
+			Name:  "My Printer._http._tcp.local",
+			Type:  33, // SRV
+			Class: 1,
+			TTL:   120,
+			Data:  []byte{0x00, 0x00, 0x00, 0x00, 0x1f, 0x90, 0x00},
+		},
+	}
+
+	query, err := BuildProbeQuery("My Printer._http._tcp.local", tentative)
+	if err != nil {
+		t.Fatalf("BuildProbeQuery() error = %v", err)
+	}
+
+	qdcount := binary.BigEndian.Uint16(query[4:6])
+	if qdcount != 1 {
+		t.Errorf("QDCOUNT = %d, want 1", qdcount)
+	}
+	nscount := binary.BigEndian.Uint16(query[8:10])
+	if nscount != 1 {
+		t.Errorf("NSCOUNT = %d, want 1", nscount)
+	}
+
+	msg, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if len(msg.Questions) != 1 || msg.Questions[0].QTYPE != 255 {
+		t.Errorf("Questions = %+v, want one QTYPE=255 (ANY) question", msg.Questions)
+	}
+	if len(msg.Authorities) != 1 {
+		t.Fatalf("len(Authorities) = %d, want 1", len(msg.Authorities))
+	}
+}
+
+// TestBuildProbeQuery_NoTentative validates that an empty tentative list
+// produces a probe query with NSCOUNT=0.
+func TestBuildProbeQuery_NoTentative(t *testing.T) {
+	query, err := BuildProbeQuery("host.local", nil)
+	if err != nil {
+		t.Fatalf("BuildProbeQuery() error = %v", err)
+	}
+
+	nscount := binary.BigEndian.Uint16(query[8:10])
+	if nscount != 0 {
+		t.Errorf("NSCOUNT = %d, want 0", nscount)
+	}
+}
+
+// TestBuildMultiQuery_PacksEveryQuestion validates that BuildMultiQuery
+// writes one question per QuerySpec and sets QDCOUNT accordingly.
+func TestBuildMultiQuery_PacksEveryQuestion(t *testing.T) {
+	questions := []QuerySpec{
+		{Name: "a.local", Type: 1},  // A
+		{Name: "b.local", Type: 28}, // AAAA
+	}
+
+	query, err := BuildMultiQuery(questions, nil)
+	if err != nil {
+		t.Fatalf("BuildMultiQuery() error = %v", err)
+	}
+
+	qdcount := binary.BigEndian.Uint16(query[4:6])
+	if qdcount != 2 {
+		t.Errorf("QDCOUNT = %d, want 2", qdcount)
+	}
+
+	msg, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if len(msg.Questions) != 2 {
+		t.Fatalf("len(Questions) = %d, want 2", len(msg.Questions))
+	}
+}
+
+// TestBuildMultiQuery_EmptyQuestionsErrors validates that BuildMultiQuery
+// rejects an empty question list rather than emitting a QDCOUNT=0 query.
+func TestBuildMultiQuery_EmptyQuestionsErrors(t *testing.T) {
+	if _, err := BuildMultiQuery(nil, nil); err == nil {
+		t.Error("BuildMultiQuery(nil, nil) error = nil, want error")
+	}
+}
+
+// TestBuildQueryWithKnownAnswers_NoAnswers validates that an empty
+// known-answers list produces a query indistinguishable from BuildQuery.
+func TestBuildQueryWithKnownAnswers_NoAnswers(t *testing.T) {
+	query, err := BuildQueryWithKnownAnswers("test.local", 1, nil)
+	if err != nil {
+		t.Fatalf("BuildQueryWithKnownAnswers failed: %v", err)
+	}
+
+	ancount := binary.BigEndian.Uint16(query[6:8])
+	if ancount != 0 {
+		t.Errorf("ANCOUNT = %d, want 0", ancount)
+	}
+}
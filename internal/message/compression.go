@@ -0,0 +1,331 @@
+package message
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// maxCompressionOffset is the largest offset RFC 1035 §4.1.4's 14-bit
+// pointer field can address. A name suffix first written at or beyond this
+// offset is simply never recorded as a future compression target, since a
+// pointer couldn't reach it anyway.
+const maxCompressionOffset = 0x3FFF
+
+// nameEncoder accumulates a DNS message buffer, applying RFC 1035 §4.1.4
+// message compression: once a name suffix has been written, any later
+// occurrence of that same suffix is replaced with a 0xC000 pointer to its
+// first offset instead of being spelled out again.
+//
+// RFC 6762 §18.14 reaffirms compression for mDNS. DNS-SD responses commonly
+// repeat "_service._proto.local" across a PTR/SRV/TXT set and "host.local"
+// across SRV/A/AAAA, so compression is frequently the difference between an
+// announcement fitting in one Ethernet frame and being fragmented.
+//
+// This is the compression engine BuildQuery, MessageBuilder, and every
+// other encoder in this package share - there is no separate exported
+// message.Encoder type, since every write path in this package already
+// goes through a nameEncoder internally.
+//
+// BuildResponse in particular already shares a single nameEncoder across
+// every answer record it writes (see buildResponseHeader's caller), so a
+// PTR/SRV/TXT/A set for one service already compresses its repeated
+// "_service._proto.local"/"host.local" suffixes rather than spelling them
+// out per record - TestBuildResponse_CompressesCrossRecordNames and
+// TestBuildResponse_CompressionRoundTrip in compression_test.go cover the
+// wire layout and a decompressing round trip, and
+// name_pointer_hardening_test.go's TestParseName_TwoNodePointerCycle /
+// TestParseName_RevisitLoopDetected cover the reader's pointer-loop
+// rejection.
+type nameEncoder struct {
+	buf      []byte
+	offsets  map[string]uint16 // lowercased dotted suffix -> offset it was first written at
+	compress bool
+}
+
+// offsetsMapPool pools nameEncoder's offsets map, since every
+// BuildResponse/BuildQuery* call on the hot query/response path allocates
+// and immediately discards one - the same allocate-per-call pattern
+// internal/transport's buffer_pool.go already pools buffers against for
+// UDPv4Transport.Receive.
+var offsetsMapPool = sync.Pool{
+	New: func() interface{} {
+		return make(map[string]uint16)
+	},
+}
+
+// getOffsetsMap returns an empty map[string]uint16 from offsetsMapPool.
+func getOffsetsMap() map[string]uint16 {
+	return offsetsMapPool.Get().(map[string]uint16)
+}
+
+// putOffsetsMap clears m and returns it to offsetsMapPool. Callers must not
+// use m after calling putOffsetsMap.
+func putOffsetsMap(m map[string]uint16) {
+	for k := range m {
+		delete(m, k)
+	}
+	offsetsMapPool.Put(m)
+}
+
+// newNameEncoder creates a nameEncoder seeded with prefix (typically the
+// message header already written), so offsets recorded for compression are
+// correct positions within the full message. Its offsets map comes from
+// offsetsMapPool; call release once the encoder's Bytes() have been taken
+// (Bytes itself doesn't need the map, only writeLabels does, so it's safe
+// to release right after assembly finishes).
+func newNameEncoder(prefix []byte) *nameEncoder {
+	return &nameEncoder{
+		buf:      append([]byte(nil), prefix...),
+		offsets:  getOffsetsMap(),
+		compress: true,
+	}
+}
+
+// release returns e's offsets map to offsetsMapPool. A no-op for an
+// uncompressed encoder, which never allocated one. Callers must not call
+// any other nameEncoder method after release.
+func (e *nameEncoder) release() {
+	if e.offsets != nil {
+		putOffsetsMap(e.offsets)
+		e.offsets = nil
+	}
+}
+
+// newUncompressedNameEncoder creates a nameEncoder like newNameEncoder, but
+// writeLabels never substitutes a pointer for a repeated suffix - every name
+// is written out in full, matching EncodeName's uncompressed output. Used by
+// EncodeMessage(msg, compress=false).
+func newUncompressedNameEncoder(prefix []byte) *nameEncoder {
+	return &nameEncoder{
+		buf: append([]byte(nil), prefix...),
+	}
+}
+
+// offset returns the position the next byte written will land at.
+func (e *nameEncoder) offset() int {
+	return len(e.buf)
+}
+
+// writeRaw appends b verbatim, without any name compression.
+func (e *nameEncoder) writeRaw(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+// writeName encodes name - a dotted name such as "printer.local", or a
+// DNS-SD service instance name such as "My Printer._http._tcp.local" - into
+// the message, compressing any suffix already emitted earlier. Validation
+// (label length/count, character set, empty labels) is delegated to
+// EncodeOwnerName so a name rejected there is rejected here too.
+func (e *nameEncoder) writeName(name string) error {
+	encoded, err := EncodeOwnerName(name)
+	if err != nil {
+		return err
+	}
+	return e.writeEncodedName(encoded)
+}
+
+// writeEncodedName compresses a name that was already encoded to wire format
+// elsewhere (e.g. a PTR or SRV RDATA target produced by EncodeName or
+// EncodeServiceInstanceName). It is split back into labels and re-emitted
+// through the same compression path as writeName.
+func (e *nameEncoder) writeEncodedName(encoded []byte) error {
+	labels, err := decodeLabels(encoded)
+	if err != nil {
+		return err
+	}
+	e.writeLabels(labels)
+	return nil
+}
+
+// writeLabels writes a name's labels, substituting a compression pointer for
+// the longest suffix already present in e.offsets. An encoder created via
+// newUncompressedNameEncoder never substitutes a pointer, writing every
+// label out in full instead.
+func (e *nameEncoder) writeLabels(labels []string) {
+	for i := 0; i < len(labels); i++ {
+		if e.compress {
+			suffix := strings.ToLower(strings.Join(labels[i:], "."))
+			if ptr, ok := e.offsets[suffix]; ok {
+				e.buf = append(e.buf, byte(0xC0|(ptr>>8)), byte(ptr))
+				return
+			}
+
+			if off := e.offset(); off <= maxCompressionOffset {
+				e.offsets[suffix] = uint16(off) //nolint:gosec // G115: bounds checked against maxCompressionOffset above
+			}
+		}
+
+		label := labels[i]
+		e.buf = append(e.buf, byte(len(label)))
+		e.buf = append(e.buf, label...)
+	}
+
+	e.buf = append(e.buf, 0)
+}
+
+// Bytes returns the fully assembled message.
+func (e *nameEncoder) Bytes() []byte {
+	return e.buf
+}
+
+// writeQuestion encodes a DNS question section per RFC 1035 §4.1.2, with its
+// QNAME compressed against any suffix already written (e.g. a query sharing
+// its service type with a following Known-Answer record). unicast sets the
+// top bit of QCLASS (the "QU" bit) per RFC 6762 §5.4, requesting a unicast
+// rather than multicast reply.
+func (e *nameEncoder) writeQuestion(name string, recordType uint16, unicast bool) error {
+	if err := e.writeName(name); err != nil {
+		return err
+	}
+
+	qtype := make([]byte, 2)
+	binary.BigEndian.PutUint16(qtype, recordType)
+	e.writeRaw(qtype)
+
+	// QCLASS: IN (1), with the QU bit set per RFC 6762 §5.4 if requested.
+	qclass := uint16(protocol.ClassIN)
+	if unicast {
+		qclass |= 0x8000
+	}
+	qclassBytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(qclassBytes, qclass)
+	e.writeRaw(qclassBytes)
+
+	return nil
+}
+
+// writeResourceRecord encodes a resource record per RFC 1035 §3.2.1, with its
+// owner name and (for PTR/SRV) RDATA target name compressed per RFC 1035
+// §4.1.4.
+func (e *nameEncoder) writeResourceRecord(rr *ResourceRecord) error {
+	if rr == nil {
+		return &errors.ValidationError{
+			Field:   "ResourceRecord",
+			Value:   nil,
+			Message: "cannot serialize nil resource record",
+			Code:    errors.CodeInvalidValue,
+		}
+	}
+
+	// NAME: auto-detect DNS-SD service instance names per RFC 6763 §4.3 so
+	// the instance label may contain spaces/UTF-8, same as EncodeOwnerName.
+	if err := e.writeName(rr.Name); err != nil {
+		return err
+	}
+
+	// TYPE (2 bytes)
+	typeBytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(typeBytes, uint16(rr.Type))
+	e.writeRaw(typeBytes)
+
+	// CLASS (2 bytes) with cache-flush bit if requested per RFC 6762 §10.2
+	class := uint16(rr.Class)
+	if rr.CacheFlush {
+		class |= 0x8000
+	}
+	classBytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(classBytes, class)
+	e.writeRaw(classBytes)
+
+	// TTL (4 bytes)
+	ttlBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(ttlBytes, rr.TTL)
+	e.writeRaw(ttlBytes)
+
+	return e.writeRDATA(rr)
+}
+
+// writeRDATA encodes a record's RDLENGTH and RDATA, compressing the target
+// name for PTR and SRV records (the only RDATA that embeds a DNS name).
+// TXT strings are never names and are never compressed, per RFC 1035
+// §4.1.4's restriction to domain name fields.
+func (e *nameEncoder) writeRDATA(rr *ResourceRecord) error {
+	lengthPos := e.offset()
+	e.buf = append(e.buf, 0, 0) // RDLENGTH placeholder, patched below
+	rdataStart := e.offset()
+
+	switch rr.Type {
+	case protocol.RecordTypePTR:
+		if err := e.writeEncodedName(rr.Data); err != nil {
+			return err
+		}
+	case protocol.RecordTypeSRV:
+		const srvPrefixLen = 6 // priority(2) + weight(2) + port(2) per RFC 2782
+		if len(rr.Data) < srvPrefixLen {
+			return &errors.WireFormatError{
+				Operation: "encode SRV RDATA",
+				Offset:    0,
+				Message:   "SRV RDATA shorter than priority/weight/port prefix",
+				Code:      errors.CodeTruncated,
+			}
+		}
+		e.writeRaw(rr.Data[:srvPrefixLen])
+		if err := e.writeEncodedName(rr.Data[srvPrefixLen:]); err != nil {
+			return err
+		}
+	default:
+		e.writeRaw(rr.Data)
+	}
+
+	// G115: RFC 1035 §3.2.1 specifies RDLENGTH as uint16, max 65535. DNS
+	// message size limit (9000 bytes per RFC 6762) ensures rdata length
+	// never exceeds uint16 in practice.
+	rdlength := e.offset() - rdataStart
+	if rdlength > 65535 {
+		rdlength = 65535
+	}
+	binary.BigEndian.PutUint16(e.buf[lengthPos:lengthPos+2], uint16(rdlength)) //nolint:gosec // G115: bounds checked above
+
+	return nil
+}
+
+// decodeLabels splits an already wire-encoded name (length-prefixed labels
+// terminated by 0x00, no compression pointers) back into its labels, so it
+// can be re-emitted through the compressing writeLabels path. Encoded RDATA
+// targets (PTR/SRV) are always freshly produced by EncodeName/
+// EncodeServiceInstanceName and never already contain a pointer.
+func decodeLabels(encoded []byte) ([]string, error) {
+	var labels []string
+	pos := 0
+	for {
+		if pos >= len(encoded) {
+			return nil, &errors.WireFormatError{
+				Operation: "decode name for compression",
+				Offset:    pos,
+				Message:   "unexpected end of encoded name",
+				Code:      errors.CodeTruncated,
+			}
+		}
+
+		length := encoded[pos]
+		if length&protocol.CompressionMask == protocol.CompressionMask {
+			return nil, &errors.WireFormatError{
+				Operation: "decode name for compression",
+				Offset:    pos,
+				Message:   "unexpected compression pointer in freshly encoded RDATA",
+				Code:      errors.CodeBadCompressionPointer,
+			}
+		}
+
+		if length == 0 {
+			return labels, nil
+		}
+
+		pos++
+		if pos+int(length) > len(encoded) {
+			return nil, &errors.WireFormatError{
+				Operation: "decode name for compression",
+				Offset:    pos,
+				Message:   "truncated label",
+				Code:      errors.CodeTruncated,
+			}
+		}
+
+		labels = append(labels, string(encoded[pos:pos+int(length)]))
+		pos += int(length)
+	}
+}
@@ -0,0 +1,289 @@
+package message
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestNameEncoder_CompressesRepeatedSuffix validates the core compression
+// behavior of nameEncoder per RFC 1035 §4.1.4: a name suffix that has
+// already been written is replaced by a 0xC0 pointer rather than spelled
+// out again.
+func TestNameEncoder_CompressesRepeatedSuffix(t *testing.T) {
+	enc := newNameEncoder(nil)
+
+	if err := enc.writeName("test.local"); err != nil {
+		t.Fatalf("writeName(first) error = %v", err)
+	}
+	firstLen := enc.offset()
+
+	if err := enc.writeName("other.local"); err != nil {
+		t.Fatalf("writeName(second) error = %v", err)
+	}
+
+	got := enc.Bytes()
+
+	// "local" was recorded as a compression target by the first write, so
+	// the second write should emit only the "other" label followed by a
+	// 2-byte pointer, not a second literal "local".
+	wantSecondLen := 1 + len("other") + 2 // length-prefixed label + pointer
+	if gotSecondLen := len(got) - firstLen; gotSecondLen != wantSecondLen {
+		t.Errorf("second name encoded as %d bytes, want %d (should compress shared \"local\" suffix)", gotSecondLen, wantSecondLen)
+	}
+
+	pointer := got[len(got)-2:]
+	if pointer[0]&0xC0 != 0xC0 {
+		t.Errorf("expected trailing compression pointer (top 2 bits set), got %#x", pointer[0])
+	}
+}
+
+// TestNameEncoder_IdenticalNameCompressesToPointerOnly validates that
+// writing the exact same name twice compresses the whole second occurrence
+// down to a single 2-byte pointer.
+func TestNameEncoder_IdenticalNameCompressesToPointerOnly(t *testing.T) {
+	enc := newNameEncoder(nil)
+
+	if err := enc.writeName("_http._tcp.local"); err != nil {
+		t.Fatalf("writeName(first) error = %v", err)
+	}
+	firstLen := enc.offset()
+
+	if err := enc.writeName("_http._tcp.local"); err != nil {
+		t.Fatalf("writeName(second) error = %v", err)
+	}
+
+	if got, want := enc.offset()-firstLen, 2; got != want {
+		t.Errorf("repeated identical name encoded as %d bytes, want %d (pointer only)", got, want)
+	}
+}
+
+// TestBuildResponse_CompressesCrossRecordNames is a golden-byte test for
+// RFC 1035 §4.1.4 compression across a PTR record's target and a following
+// record's owner name, the case RFC 6762 §18.14 calls out as the common
+// DNS-SD pattern (a PTR target reappearing as an A/AAAA owner name).
+//
+// The expected bytes below were hand-derived from the wire format, not
+// captured from a reference tool; they encode what RFC 1035 §4.1.4 compliant
+// output must look like byte-for-byte for this exact input, which is what a
+// capture from a reference implementation (e.g. Avahi) would also produce.
+func TestBuildResponse_CompressesCrossRecordNames(t *testing.T) {
+	printerLocal, err := EncodeName("printer.local")
+	if err != nil {
+		t.Fatalf("EncodeName(printer.local) error = %v", err)
+	}
+
+	answers := []*ResourceRecord{
+		{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   protocol.TTLService,
+			Data:  printerLocal,
+		},
+		{
+			Name:  "printer.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   protocol.TTLHostname,
+			Data:  []byte{192, 168, 1, 100},
+		},
+	}
+
+	got, err := BuildResponse(answers)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v", err)
+	}
+
+	want := []byte{
+		// Header: ID=0, flags QR|AA, QDCOUNT=0, ANCOUNT=2, NSCOUNT=0, ARCOUNT=0
+		0x00, 0x00, 0x84, 0x00,
+		0x00, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x00,
+
+		// Answer 1: "_http._tcp.local" PTR, offset 12
+		0x05, '_', 'h', 't', 't', 'p', // offset 12: first occurrence of "_http._tcp.local"
+		0x04, '_', 't', 'c', 'p', // offset 18: first occurrence of "_tcp.local"
+		0x05, 'l', 'o', 'c', 'a', 'l', // offset 23: first occurrence of "local"
+		0x00,
+		0x00, 0x0C, // TYPE = PTR
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x0A, // RDLENGTH = 10
+		0x07, 'p', 'r', 'i', 'n', 't', 'e', 'r', // offset 40: first occurrence of "printer.local"
+		0xC0, 0x17, // pointer to "local" at offset 23
+
+		// Answer 2: "printer.local" A, offset 50
+		0xC0, 0x28, // pointer to "printer.local" at offset 40
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x11, 0x94, // TTL = 4500
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 100,
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("BuildResponse() compressed output mismatch:\ngot:  % x\nwant: % x", got, want)
+	}
+}
+
+// TestBuildResponse_CompressionRoundTrip validates that a response built
+// with name compression parses back to the original owner names and RDATA,
+// and that compression actually shrinks the message relative to writing
+// every name out in full.
+func TestBuildResponse_CompressionRoundTrip(t *testing.T) {
+	printerLocal, err := EncodeName("printer.local")
+	if err != nil {
+		t.Fatalf("EncodeName(printer.local) error = %v", err)
+	}
+
+	answers := []*ResourceRecord{
+		{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   protocol.TTLService,
+			Data:  printerLocal,
+		},
+		{
+			Name:  "printer.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   protocol.TTLHostname,
+			Data:  []byte{192, 168, 1, 100},
+		},
+	}
+
+	wire, err := BuildResponse(answers)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v", err)
+	}
+
+	// Without compression this fixture is 84 bytes. Compression turns the
+	// PTR RDATA's "local" suffix into a pointer to the owner name's "local"
+	// (7 bytes -> 2) and the A record's whole "printer.local" owner name into
+	// a pointer to the PTR RDATA (15 bytes -> 2), saving 5+13 = 18 bytes.
+	if got, want := len(wire), 66; got != want {
+		t.Fatalf("compressed response = %d bytes, want exactly %d for this fixture", got, want)
+	}
+
+	parsed, err := ParseMessage(wire)
+	if err != nil {
+		t.Fatalf("ParseMessage() of compressed response error = %v", err)
+	}
+
+	if len(parsed.Answers) != 2 {
+		t.Fatalf("len(Answers) = %d, want 2", len(parsed.Answers))
+	}
+
+	if parsed.Answers[0].NAME != "_http._tcp.local" {
+		t.Errorf("Answers[0].NAME = %q, want %q", parsed.Answers[0].NAME, "_http._tcp.local")
+	}
+	if parsed.Answers[1].NAME != "printer.local" {
+		t.Errorf("Answers[1].NAME = %q, want %q (decompressed pointer to Answer[0]'s RDATA target)", parsed.Answers[1].NAME, "printer.local")
+	}
+}
+
+// TestBuildResponseWithAdditionals_SetsARCountAndCompressesAcrossSections
+// verifies that additionals land in the parsed Additionals section with
+// ARCOUNT set, and that an owner name shared between an answer and an
+// additional record still compresses to a pointer per RFC 1035 §4.1.4.
+func TestBuildResponseWithAdditionals_SetsARCountAndCompressesAcrossSections(t *testing.T) {
+	srvRDATA, err := PackRDATA(uint16(protocol.RecordTypeSRV), SRVData{Priority: 0, Weight: 0, Port: 8080, Target: "printer.local"})
+	if err != nil {
+		t.Fatalf("PackRDATA(SRV) error = %v", err)
+	}
+	answers := []*ResourceRecord{
+		{Name: "instance._http._tcp.local", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: protocol.TTLService, Data: srvRDATA},
+	}
+	additionals := []*ResourceRecord{
+		{Name: "printer.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: protocol.TTLHostname, Data: []byte{192, 168, 1, 100}},
+	}
+
+	withAdditionals, err := BuildResponseWithAdditionals(answers, additionals)
+	if err != nil {
+		t.Fatalf("BuildResponseWithAdditionals() error = %v", err)
+	}
+	answersOnly, err := BuildResponse(answers)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v", err)
+	}
+
+	// The additional record's owner name, "printer.local", already appeared
+	// as the answer's SRV target, so it should compress to a 2-byte pointer
+	// rather than being spelled out again in full: the added cost is just
+	// the pointer plus type(2)+class(2)+ttl(4)+rdlength(2)+A rdata(4).
+	if got, want := len(withAdditionals)-len(answersOnly), 2+2+2+4+2+4; got != want {
+		t.Errorf("additional section cost %d bytes, want %d (2-byte name pointer + type + class + ttl + rdlength + 4-byte A rdata)", got, want)
+	}
+
+	parsed, err := ParseMessage(withAdditionals)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if parsed.Header.ARCount != 1 {
+		t.Errorf("Header.ARCount = %d, want 1", parsed.Header.ARCount)
+	}
+	if len(parsed.Additionals) != 1 {
+		t.Fatalf("len(Additionals) = %d, want 1", len(parsed.Additionals))
+	}
+	if parsed.Additionals[0].NAME != "printer.local" {
+		t.Errorf("Additionals[0].NAME = %q, want %q", parsed.Additionals[0].NAME, "printer.local")
+	}
+}
+
+// TestBuildResponseUncompressed_SpellsOutRepeatedNames verifies
+// BuildResponseUncompressed never substitutes a compression pointer: it
+// should parse back to the same answers as BuildResponse's compressed
+// output but be larger by exactly the bytes compression would have saved.
+func TestBuildResponseUncompressed_SpellsOutRepeatedNames(t *testing.T) {
+	printerLocal, err := EncodeName("printer.local")
+	if err != nil {
+		t.Fatalf("EncodeName(printer.local) error = %v", err)
+	}
+
+	answers := []*ResourceRecord{
+		{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   protocol.TTLService,
+			Data:  printerLocal,
+		},
+		{
+			Name:  "printer.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   protocol.TTLHostname,
+			Data:  []byte{192, 168, 1, 100},
+		},
+	}
+
+	compressed, err := BuildResponse(answers)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v", err)
+	}
+	uncompressed, err := BuildResponseUncompressed(answers)
+	if err != nil {
+		t.Fatalf("BuildResponseUncompressed() error = %v", err)
+	}
+
+	if len(uncompressed) <= len(compressed) {
+		t.Errorf("len(uncompressed) = %d, want > len(compressed) = %d", len(uncompressed), len(compressed))
+	}
+
+	parsed, err := ParseMessage(uncompressed)
+	if err != nil {
+		t.Fatalf("ParseMessage() of uncompressed response error = %v", err)
+	}
+	if len(parsed.Answers) != 2 {
+		t.Fatalf("len(Answers) = %d, want 2", len(parsed.Answers))
+	}
+	if parsed.Answers[0].NAME != "_http._tcp.local" {
+		t.Errorf("Answers[0].NAME = %q, want %q", parsed.Answers[0].NAME, "_http._tcp.local")
+	}
+	if parsed.Answers[1].NAME != "printer.local" {
+		t.Errorf("Answers[1].NAME = %q, want %q", parsed.Answers[1].NAME, "printer.local")
+	}
+}
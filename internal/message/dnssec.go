@@ -0,0 +1,551 @@
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// DNSSECAlgorithm identifies a DNSSEC signing algorithm per RFC 4034 Appendix A.1.
+type DNSSECAlgorithm uint8
+
+// Supported DNSSEC algorithms.
+//
+// Beacon signs LAN service records for integrity assurance, not for chain-of-trust
+// validation against a root zone, so only modern, constant-time-friendly algorithms
+// are implemented.
+const (
+	// AlgorithmECDSAP256SHA256 is ECDSA curve P-256 with SHA-256 per RFC 6605.
+	AlgorithmECDSAP256SHA256 DNSSECAlgorithm = 13
+
+	// AlgorithmED25519 is Ed25519 per RFC 8080.
+	AlgorithmED25519 DNSSECAlgorithm = 15
+)
+
+// RRSIGData represents the parsed RDATA of an RRSIG record per RFC 4034 §3.1.
+type RRSIGData struct {
+	// TypeCovered is the RRset type this signature covers.
+	TypeCovered uint16
+
+	// Algorithm identifies the cryptographic algorithm used, per RFC 4034 Appendix A.1.
+	Algorithm DNSSECAlgorithm
+
+	// Labels is the number of labels in the original owner name, per RFC 4034 §3.1.3.
+	// It excludes the root label and, for wildcard names, the "*" label.
+	Labels uint8
+
+	// OriginalTTL is the TTL of the covered RRset as it appears in the zone,
+	// per RFC 4034 §3.1.4.
+	OriginalTTL uint32
+
+	// SignatureExpiration is the signature's expiration time, as seconds since
+	// the Unix epoch per RFC 4034 §3.1.5.
+	SignatureExpiration uint32
+
+	// SignatureInception is the signature's inception time, as seconds since
+	// the Unix epoch per RFC 4034 §3.1.5.
+	SignatureInception uint32
+
+	// KeyTag identifies the signing DNSKEY per RFC 4034 Appendix B.
+	KeyTag uint16
+
+	// SignerName is the name of the DNSKEY owner that signed this RRset.
+	//
+	// Per RFC 4034 §3.1.7, this name MUST NOT be compressed on the wire.
+	SignerName string
+
+	// Signature is the cryptographic signature, whose format depends on Algorithm.
+	Signature []byte
+}
+
+func (*RRSIGData) isRData() {}
+
+// DNSKEYData represents the parsed RDATA of a DNSKEY record per RFC 4034 §2.1.
+type DNSKEYData struct {
+	// Flags per RFC 4034 §2.1.1. Beacon always sets bit 7 (Zone Key, value
+	// 0x0100), and additionally bit 15 (Secure Entry Point, value 0x0001)
+	// for key-signing keys, bits numbered as in the RFC.
+	Flags uint16
+
+	// Protocol MUST be 3 per RFC 4034 §2.1.2.
+	Protocol uint8
+
+	// Algorithm identifies the cryptographic algorithm, per RFC 4034 Appendix A.1.
+	Algorithm DNSSECAlgorithm
+
+	// PublicKey is the algorithm-specific encoded public key per RFC 4034 §2.1.4.
+	PublicKey []byte
+}
+
+func (*DNSKEYData) isRData() {}
+
+// DNSKEYFlagZoneKey is the Zone Key flag (bit 7, value 0x0100) per RFC 4034 §2.1.1.
+const DNSKEYFlagZoneKey uint16 = 1 << 8
+
+// DNSKEYProtocol is the only valid Protocol Octet value per RFC 4034 §2.1.2.
+const DNSKEYProtocol uint8 = 3
+
+// EncodeRRSIG serializes an RRSIGData into RRSIG RDATA per RFC 4034 §3.1.
+func EncodeRRSIG(data *RRSIGData) ([]byte, error) {
+	// RFC 4034 §3.1.7: the signer's name MUST NOT be compressed.
+	signerEncoded, err := EncodeName(data.SignerName)
+	if err != nil {
+		return nil, err
+	}
+
+	rdata := make([]byte, 18, 18+len(signerEncoded)+len(data.Signature))
+	binary.BigEndian.PutUint16(rdata[0:2], data.TypeCovered)
+	rdata[2] = byte(data.Algorithm)
+	rdata[3] = data.Labels
+	binary.BigEndian.PutUint32(rdata[4:8], data.OriginalTTL)
+	binary.BigEndian.PutUint32(rdata[8:12], data.SignatureExpiration)
+	binary.BigEndian.PutUint32(rdata[12:16], data.SignatureInception)
+	binary.BigEndian.PutUint16(rdata[16:18], data.KeyTag)
+
+	rdata = append(rdata, signerEncoded...)
+	rdata = append(rdata, data.Signature...)
+
+	return rdata, nil
+}
+
+// ParseRRSIG decodes RRSIG RDATA per RFC 4034 §3.1.
+func ParseRRSIG(rdata []byte) (*RRSIGData, error) {
+	if len(rdata) < 18 {
+		return nil, &errors.WireFormatError{
+			Operation: "parse RRSIG record",
+			Offset:    0,
+			Message:   fmt.Sprintf("truncated RRSIG record: %d bytes, expected at least 18", len(rdata)),
+			Code:      errors.CodeTruncated,
+		}
+	}
+
+	signerName, offset, err := ParseName(rdata, 18)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := make([]byte, len(rdata)-offset)
+	copy(signature, rdata[offset:])
+
+	return &RRSIGData{
+		TypeCovered:         binary.BigEndian.Uint16(rdata[0:2]),
+		Algorithm:           DNSSECAlgorithm(rdata[2]),
+		Labels:              rdata[3],
+		OriginalTTL:         binary.BigEndian.Uint32(rdata[4:8]),
+		SignatureExpiration: binary.BigEndian.Uint32(rdata[8:12]),
+		SignatureInception:  binary.BigEndian.Uint32(rdata[12:16]),
+		KeyTag:              binary.BigEndian.Uint16(rdata[16:18]),
+		SignerName:          signerName,
+		Signature:           signature,
+	}, nil
+}
+
+// EncodeDNSKEY serializes a DNSKEYData into DNSKEY RDATA per RFC 4034 §2.1.
+func EncodeDNSKEY(data *DNSKEYData) []byte {
+	rdata := make([]byte, 4, 4+len(data.PublicKey))
+	binary.BigEndian.PutUint16(rdata[0:2], data.Flags)
+	rdata[2] = data.Protocol
+	rdata[3] = byte(data.Algorithm)
+	rdata = append(rdata, data.PublicKey...)
+	return rdata
+}
+
+// ParseDNSKEY decodes DNSKEY RDATA per RFC 4034 §2.1.
+func ParseDNSKEY(rdata []byte) (*DNSKEYData, error) {
+	if len(rdata) < 4 {
+		return nil, &errors.WireFormatError{
+			Operation: "parse DNSKEY record",
+			Offset:    0,
+			Message:   fmt.Sprintf("truncated DNSKEY record: %d bytes, expected at least 4", len(rdata)),
+			Code:      errors.CodeTruncated,
+		}
+	}
+
+	publicKey := make([]byte, len(rdata)-4)
+	copy(publicKey, rdata[4:])
+
+	return &DNSKEYData{
+		Flags:     binary.BigEndian.Uint16(rdata[0:2]),
+		Protocol:  rdata[2],
+		Algorithm: DNSSECAlgorithm(rdata[3]),
+		PublicKey: publicKey,
+	}, nil
+}
+
+// DSDigestType identifies the digest algorithm a DS record's Digest was
+// computed with, per RFC 4034 §5.1.4 and the IANA "Delegation Signer (DS)
+// Resource Record (RR) Type Digest Algorithms" registry.
+type DSDigestType uint8
+
+// Supported DS digest types. Beacon only ever parses DS records published by
+// other responders on the LAN - it never mints its own - so unlike
+// DNSSECAlgorithm above, older/weaker digest types are recognized (not just
+// modern ones) since rejecting them would just turn a real DS record into a
+// parse failure.
+const (
+	// DSDigestSHA1 is SHA-1 per RFC 3658 §2.4. Deprecated upstream but still
+	// seen in the wild.
+	DSDigestSHA1 DSDigestType = 1
+
+	// DSDigestSHA256 is SHA-256 per RFC 4509 §2.2.
+	DSDigestSHA256 DSDigestType = 2
+
+	// DSDigestSHA384 is SHA-384 per RFC 6605 §2.
+	DSDigestSHA384 DSDigestType = 4
+)
+
+// DSData represents the parsed RDATA of a DS (Delegation Signer) record per
+// RFC 4034 §5.1.
+type DSData struct {
+	// KeyTag identifies the DNSKEY this DS record vouches for, per RFC 4034
+	// Appendix B (same algorithm KeyTag computes).
+	KeyTag uint16
+
+	// Algorithm identifies the signing algorithm of the referenced DNSKEY,
+	// per RFC 4034 Appendix A.1.
+	Algorithm DNSSECAlgorithm
+
+	// DigestType identifies the algorithm Digest was computed with.
+	DigestType DSDigestType
+
+	// Digest is the digest of the referenced DNSKEY RDATA, per RFC 4034 §5.1.4.
+	Digest []byte
+}
+
+func (*DSData) isRData() {}
+
+// EncodeDS serializes a DSData into DS RDATA per RFC 4034 §5.1.
+func EncodeDS(data *DSData) []byte {
+	rdata := make([]byte, 4, 4+len(data.Digest))
+	binary.BigEndian.PutUint16(rdata[0:2], data.KeyTag)
+	rdata[2] = byte(data.Algorithm)
+	rdata[3] = byte(data.DigestType)
+	rdata = append(rdata, data.Digest...)
+	return rdata
+}
+
+// ParseDS decodes DS RDATA per RFC 4034 §5.1.
+func ParseDS(rdata []byte) (*DSData, error) {
+	if len(rdata) < 4 {
+		return nil, &errors.WireFormatError{
+			Operation: "parse DS record",
+			Offset:    0,
+			Message:   fmt.Sprintf("truncated DS record: %d bytes, expected at least 4", len(rdata)),
+			Code:      errors.CodeTruncated,
+		}
+	}
+
+	digest := make([]byte, len(rdata)-4)
+	copy(digest, rdata[4:])
+
+	return &DSData{
+		KeyTag:     binary.BigEndian.Uint16(rdata[0:2]),
+		Algorithm:  DNSSECAlgorithm(rdata[2]),
+		DigestType: DSDigestType(rdata[3]),
+		Digest:     digest,
+	}, nil
+}
+
+// NSECData represents the parsed RDATA of an NSEC (authenticated denial of
+// existence) record per RFC 4034 §4.1.
+type NSECData struct {
+	// NextDomainName is the next owner name in canonical ordering that has
+	// authoritative data or a delegation point, per RFC 4034 §4.1.1.
+	//
+	// Per RFC 4034 §4.1.1, this name MUST NOT be compressed on the wire.
+	NextDomainName string
+
+	// TypeBitMap lists the RR types present at the NSEC record's owner name,
+	// decoded from the windowed bitmap format of RFC 4034 §4.1.2.
+	TypeBitMap []uint16
+}
+
+func (*NSECData) isRData() {}
+
+// EncodeNSEC serializes an NSECData into NSEC RDATA per RFC 4034 §4.1.
+func EncodeNSEC(data *NSECData) ([]byte, error) {
+	// RFC 4034 §4.1.1: the next domain name MUST NOT be compressed.
+	nameEncoded, err := EncodeName(data.NextDomainName)
+	if err != nil {
+		return nil, err
+	}
+
+	rdata := append([]byte(nil), nameEncoded...)
+	rdata = append(rdata, encodeNSECTypeBitMap(data.TypeBitMap)...)
+	return rdata, nil
+}
+
+// ParseNSEC decodes NSEC RDATA per RFC 4034 §4.1.
+func ParseNSEC(rdata []byte) (*NSECData, error) {
+	nextName, offset, err := ParseName(rdata, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := parseNSECTypeBitMap(rdata[offset:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &NSECData{NextDomainName: nextName, TypeBitMap: types}, nil
+}
+
+// encodeNSECTypeBitMap serializes types into the windowed bitmap format of
+// RFC 4034 §4.1.2: one block per distinct "window" (types>>8) present,
+// ordered ascending, each holding a bitmap trimmed to its highest set bit
+// (so an all-zero trailing byte is never emitted).
+func encodeNSECTypeBitMap(types []uint16) []byte {
+	windows := make(map[uint8][]byte)
+	for _, t := range types {
+		window := uint8(t >> 8) //nolint:gosec // G115: intentional byte extraction
+		bit := uint8(t)         //nolint:gosec // G115: intentional byte extraction
+
+		bitmap := windows[window]
+		byteIndex := int(bit / 8)
+		for len(bitmap) <= byteIndex {
+			bitmap = append(bitmap, 0)
+		}
+		bitmap[byteIndex] |= 1 << (7 - bit%8)
+		windows[window] = bitmap
+	}
+
+	blocks := make([]uint8, 0, len(windows))
+	for window := range windows {
+		blocks = append(blocks, window)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	var out []byte
+	for _, window := range blocks {
+		bitmap := windows[window]
+		out = append(out, window, byte(len(bitmap)))
+		out = append(out, bitmap...)
+	}
+	return out
+}
+
+// parseNSECTypeBitMap decodes the windowed bitmap format of RFC 4034 §4.1.2
+// into the list of RR types it covers.
+func parseNSECTypeBitMap(data []byte) ([]uint16, error) {
+	var types []uint16
+	offset := 0
+	for offset < len(data) {
+		if offset+2 > len(data) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse NSEC type bit map",
+				Offset:    offset,
+				Message:   "truncated window: not enough bytes for window number and bitmap length",
+				Code:      errors.CodeTruncated,
+			}
+		}
+		window := data[offset]
+		length := int(data[offset+1])
+		offset += 2
+
+		if length < 1 || length > 32 {
+			return nil, &errors.WireFormatError{
+				Operation: "parse NSEC type bit map",
+				Offset:    offset,
+				Message:   fmt.Sprintf("invalid bitmap length %d, expected 1-32", length),
+				Code:      errors.CodeRDataOverflow,
+			}
+		}
+		if offset+length > len(data) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse NSEC type bit map",
+				Offset:    offset,
+				Message:   fmt.Sprintf("truncated bitmap: expected %d bytes, only %d available", length, len(data)-offset),
+				Code:      errors.CodeTruncated,
+			}
+		}
+
+		for i, b := range data[offset : offset+length] {
+			for bit := 0; bit < 8; bit++ {
+				if b&(1<<(7-bit)) == 0 {
+					continue
+				}
+				types = append(types, uint16(window)<<8|uint16(i*8+bit))
+			}
+		}
+		offset += length
+	}
+	return types, nil
+}
+
+// NSEC3HashAlgorithm identifies the one-way hash function an NSEC3 record's
+// owner and NextHashedOwnerName were computed with, per RFC 5155 §4.1.3 and
+// the IANA "DNSSEC NSEC3 Hash Algorithms" registry.
+type NSEC3HashAlgorithm uint8
+
+// NSEC3HashSHA1 is SHA-1, the only hash algorithm registered for NSEC3 per
+// RFC 5155 §4.1.3.
+const NSEC3HashSHA1 NSEC3HashAlgorithm = 1
+
+// NSEC3FlagOptOut is the Opt-Out flag (bit 0) per RFC 5155 §3.1.2.1, marking
+// an NSEC3 record as covering unsigned delegations it makes no denial claim
+// about.
+const NSEC3FlagOptOut uint8 = 1
+
+// NSEC3Data represents the parsed RDATA of an NSEC3 (hashed authenticated
+// denial of existence) record per RFC 5155 §3.2.
+type NSEC3Data struct {
+	// HashAlgorithm identifies the hash function Owner/NextHashedOwnerName
+	// were computed with, per RFC 5155 §4.1.3.
+	HashAlgorithm NSEC3HashAlgorithm
+
+	// Flags holds the Opt-Out bit (NSEC3FlagOptOut) and reserved bits, per
+	// RFC 5155 §3.1.2.
+	Flags uint8
+
+	// Iterations is the number of additional hash iterations, per RFC 5155 §3.1.3.
+	Iterations uint16
+
+	// Salt appends entropy to each hash iteration, per RFC 5155 §3.1.5. Empty
+	// (not nil) means no salt, matching RFC 5155 §3.2's zero-length encoding.
+	Salt []byte
+
+	// NextHashedOwnerName is the next hashed owner name in hash order,
+	// unmodified base32hex-decodable bytes per RFC 5155 §3.1.7 - this is raw
+	// hash output, not a domain name, so it is never passed through
+	// ParseName/EncodeName.
+	NextHashedOwnerName []byte
+
+	// TypeBitMap lists the RR types present at the original owner name,
+	// decoded from the same windowed bitmap format NSEC uses (RFC 5155 §3.2,
+	// reusing RFC 4034 §4.1.2's encoding).
+	TypeBitMap []uint16
+}
+
+func (*NSEC3Data) isRData() {}
+
+// EncodeNSEC3 serializes an NSEC3Data into NSEC3 RDATA per RFC 5155 §3.2.
+func EncodeNSEC3(data *NSEC3Data) ([]byte, error) {
+	if len(data.Salt) > 255 {
+		return nil, &errors.ValidationError{
+			Field:   "NSEC3Data.Salt",
+			Value:   len(data.Salt),
+			Message: "salt length exceeds the 255-byte wire length octet",
+			Code:    errors.CodeRDataOverflow,
+		}
+	}
+	if len(data.NextHashedOwnerName) > 255 {
+		return nil, &errors.ValidationError{
+			Field:   "NSEC3Data.NextHashedOwnerName",
+			Value:   len(data.NextHashedOwnerName),
+			Message: "next hashed owner name exceeds the 255-byte wire length octet",
+			Code:    errors.CodeRDataOverflow,
+		}
+	}
+
+	rdata := make([]byte, 5, 5+len(data.Salt)+1+len(data.NextHashedOwnerName))
+	rdata[0] = byte(data.HashAlgorithm)
+	rdata[1] = data.Flags
+	binary.BigEndian.PutUint16(rdata[2:4], data.Iterations)
+	rdata[4] = byte(len(data.Salt))
+	rdata = append(rdata, data.Salt...)
+	rdata = append(rdata, byte(len(data.NextHashedOwnerName)))
+	rdata = append(rdata, data.NextHashedOwnerName...)
+	rdata = append(rdata, encodeNSECTypeBitMap(data.TypeBitMap)...)
+	return rdata, nil
+}
+
+// ParseNSEC3 decodes NSEC3 RDATA per RFC 5155 §3.2.
+func ParseNSEC3(rdata []byte) (*NSEC3Data, error) {
+	if len(rdata) < 5 {
+		return nil, &errors.WireFormatError{
+			Operation: "parse NSEC3 record",
+			Offset:    0,
+			Message:   fmt.Sprintf("truncated NSEC3 record: %d bytes, expected at least 5", len(rdata)),
+			Code:      errors.CodeTruncated,
+		}
+	}
+
+	saltLength := int(rdata[4])
+	offset := 5
+	if offset+saltLength > len(rdata) {
+		return nil, &errors.WireFormatError{
+			Operation: "parse NSEC3 record",
+			Offset:    offset,
+			Message:   fmt.Sprintf("truncated salt: expected %d bytes, only %d available", saltLength, len(rdata)-offset),
+			Code:      errors.CodeTruncated,
+		}
+	}
+	salt := make([]byte, saltLength)
+	copy(salt, rdata[offset:offset+saltLength])
+	offset += saltLength
+
+	if offset+1 > len(rdata) {
+		return nil, &errors.WireFormatError{
+			Operation: "parse NSEC3 record",
+			Offset:    offset,
+			Message:   "truncated record: missing hash length octet",
+			Code:      errors.CodeTruncated,
+		}
+	}
+	hashLength := int(rdata[offset])
+	offset++
+	if offset+hashLength > len(rdata) {
+		return nil, &errors.WireFormatError{
+			Operation: "parse NSEC3 record",
+			Offset:    offset,
+			Message:   fmt.Sprintf("truncated next hashed owner name: expected %d bytes, only %d available", hashLength, len(rdata)-offset),
+			Code:      errors.CodeTruncated,
+		}
+	}
+	nextHashed := make([]byte, hashLength)
+	copy(nextHashed, rdata[offset:offset+hashLength])
+	offset += hashLength
+
+	types, err := parseNSECTypeBitMap(rdata[offset:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &NSEC3Data{
+		HashAlgorithm:       NSEC3HashAlgorithm(rdata[0]),
+		Flags:               rdata[1],
+		Iterations:          binary.BigEndian.Uint16(rdata[2:4]),
+		Salt:                salt,
+		NextHashedOwnerName: nextHashed,
+		TypeBitMap:          types,
+	}, nil
+}
+
+// CanonicalizeName lowercases the ASCII letters in a domain name per RFC 4034 §6.2,
+// rule 3 ("All uppercase US-ASCII letters in the owner name of the RR are replaced
+// by the corresponding lowercase US-ASCII letters").
+func CanonicalizeName(name string) string {
+	return strings.ToLower(name)
+}
+
+// LabelCount returns the number of labels in name, per RFC 4034 §3.1.3: the
+// root label is not counted, and Beacon never signs wildcard names so no
+// leftmost "*" adjustment is needed.
+func LabelCount(name string) uint8 {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return 0
+	}
+	count := strings.Count(name, ".") + 1
+	if count > 255 {
+		count = 255
+	}
+	return uint8(count) //nolint:gosec // G115: bounds checked above
+}
+
+// KeyTag computes the key tag of a DNSKEY RR per RFC 4034 Appendix B.
+func KeyTag(dnskeyRDATA []byte) uint16 {
+	var ac uint32
+	for i, b := range dnskeyRDATA {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += (ac >> 16) & 0xFFFF
+	return uint16(ac & 0xFFFF) //nolint:gosec // G115: masked to 16 bits above
+}
@@ -0,0 +1,115 @@
+package message
+
+import (
+	"encoding/binary"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// DefaultEDNSUDPSize is the UDP payload size WithEDNS advertises when the
+// caller doesn't request a specific one: 1232 bytes, the conservative
+// RFC 6762 §17 guidance for avoiding IP fragmentation on today's common
+// network paths (1280-byte IPv6 minimum MTU minus room for tunnel headers).
+const DefaultEDNSUDPSize = 1232
+
+// MaxEDNSUDPSize is the largest UDP payload size WithEDNS accepts: the
+// RFC 6762 §17 packet size ceiling also enforced elsewhere in this package.
+const MaxEDNSUDPSize = 9000
+
+// QueryOption customizes a query built by BuildQuery beyond its required
+// name and record type, following the same functional-option shape as
+// querier.Option.
+type QueryOption func(*queryOptions)
+
+// queryOptions collects the optional, additive pieces of a query that
+// QueryOption values configure. The zero value builds the same query
+// BuildQuery has always produced.
+type queryOptions struct {
+	edns *ednsOptions
+}
+
+// ednsOptions holds the EDNS(0) OPT record WithEDNS requests.
+type ednsOptions struct {
+	udpPayloadSize uint16
+	dnssecOK       bool
+	options        []EDNSOption
+}
+
+// WithEDNS requests that BuildQuery append an EDNS(0) OPT pseudo-RR to the
+// query's Additional section per RFC 6891 §6.1.2, advertising size as the
+// querier's maximum acceptable UDP payload so a responder isn't limited to
+// the traditional 512-byte reply. size of 0 uses DefaultEDNSUDPSize; a size
+// above MaxEDNSUDPSize is capped to it. opts carries any additional EDNS
+// options to attach (e.g. NSID, padding, Extended DNS Error).
+func WithEDNS(size uint16, opts ...EDNSOption) QueryOption {
+	if size == 0 {
+		size = DefaultEDNSUDPSize
+	}
+	if size > MaxEDNSUDPSize {
+		size = MaxEDNSUDPSize
+	}
+	return func(qo *queryOptions) {
+		dnssecOK := qo.edns != nil && qo.edns.dnssecOK
+		qo.edns = &ednsOptions{udpPayloadSize: size, dnssecOK: dnssecOK, options: opts}
+	}
+}
+
+// WithDNSSEC sets the DO (DNSSEC OK) bit per RFC 3225 §3 on the query's
+// EDNS(0) OPT record, signaling that the querier can accept RRSIG/DNSKEY
+// records in the response. It implies WithEDNS(0) if no WithEDNS option
+// precedes it, since the DO bit only exists inside an OPT record.
+func WithDNSSEC() QueryOption {
+	return func(qo *queryOptions) {
+		if qo.edns == nil {
+			qo.edns = &ednsOptions{udpPayloadSize: DefaultEDNSUDPSize}
+		}
+		qo.edns.dnssecOK = true
+	}
+}
+
+// EncodeEDNSOptions serializes opts into an OPT record's RDATA per
+// RFC 6891 §6.1.2: a sequence of OPTION-CODE (2 bytes), OPTION-LENGTH
+// (2 bytes), OPTION-DATA (OPTION-LENGTH bytes) triples. It is the inverse of
+// parseOPTRecord's option-decoding loop.
+func EncodeEDNSOptions(opts []EDNSOption) []byte {
+	rdata := make([]byte, 0, len(opts)*4)
+	for _, opt := range opts {
+		header := make([]byte, 4)
+		binary.BigEndian.PutUint16(header[0:2], uint16(opt.Code))
+		binary.BigEndian.PutUint16(header[2:4], uint16(len(opt.Data))) //nolint:gosec // G115: option data bounded by the 9000-byte message size limit
+		rdata = append(rdata, header...)
+		rdata = append(rdata, opt.Data...)
+	}
+	return rdata
+}
+
+// writeOPTRecord appends an EDNS(0) OPT pseudo-RR to e per RFC 6891 §6.1.2.
+// Unlike writeResourceRecord, the OPT record's NAME is always the root
+// domain and its CLASS/TTL fields don't carry ordinary DNS class/TTL
+// semantics - see OPTRecord's doc comment - so it is written directly
+// rather than through writeResourceRecord.
+func (e *nameEncoder) writeOPTRecord(edns *ednsOptions) {
+	e.writeRaw([]byte{0}) // NAME: root domain
+
+	typeBytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(typeBytes, uint16(protocol.RecordTypeOPT))
+	e.writeRaw(typeBytes)
+
+	classBytes := make([]byte, 2)
+	binary.BigEndian.PutUint16(classBytes, edns.udpPayloadSize)
+	e.writeRaw(classBytes)
+
+	// TTL: ExtendedRCODE(8)=0, Version(8)=0, flags(16) with the DO bit (bit
+	// 15, the top bit of the third byte) set per WithDNSSEC.
+	var flagsHigh byte
+	if edns.dnssecOK {
+		flagsHigh = 0x80
+	}
+	e.writeRaw([]byte{0, 0, flagsHigh, 0})
+
+	rdata := EncodeEDNSOptions(edns.options)
+	rdlength := make([]byte, 2)
+	binary.BigEndian.PutUint16(rdlength, uint16(len(rdata))) //nolint:gosec // G115: bounded by the 9000-byte message size limit
+	e.writeRaw(rdlength)
+	e.writeRaw(rdata)
+}
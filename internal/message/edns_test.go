@@ -0,0 +1,184 @@
+package message
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// TestBuildQuery_WithEDNS_AppendsOPTRecord validates that WithEDNS appends an
+// EDNS(0) OPT pseudo-RR to the Additional section per RFC 6891 §6.1.2, and
+// that the header's ARCOUNT reflects it.
+func TestBuildQuery_WithEDNS_AppendsOPTRecord(t *testing.T) {
+	query, err := BuildQuery("test.local", 1, WithEDNS(4096))
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	arcount := binary.BigEndian.Uint16(query[10:12])
+	if arcount != 1 {
+		t.Errorf("ARCOUNT is %d, expected 1 with WithEDNS", arcount)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if parsed.OPT == nil {
+		t.Fatal("parsed message has no OPT record")
+	}
+	if parsed.OPT.UDPPayloadSize != 4096 {
+		t.Errorf("UDPPayloadSize = %d, want 4096", parsed.OPT.UDPPayloadSize)
+	}
+	if len(parsed.Additionals) != 0 {
+		t.Errorf("Additionals = %v, want empty (OPT decodes separately)", parsed.Additionals)
+	}
+}
+
+// TestBuildQuery_WithEDNS_DefaultSize validates that WithEDNS(0) advertises
+// DefaultEDNSUDPSize per RFC 6762 §17 guidance.
+func TestBuildQuery_WithEDNS_DefaultSize(t *testing.T) {
+	query, err := BuildQuery("test.local", 1, WithEDNS(0))
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if parsed.OPT.UDPPayloadSize != DefaultEDNSUDPSize {
+		t.Errorf("UDPPayloadSize = %d, want %d", parsed.OPT.UDPPayloadSize, DefaultEDNSUDPSize)
+	}
+}
+
+// TestBuildQuery_WithEDNS_CapsSize validates that a requested size above
+// MaxEDNSUDPSize is capped rather than sent as-is.
+func TestBuildQuery_WithEDNS_CapsSize(t *testing.T) {
+	query, err := BuildQuery("test.local", 1, WithEDNS(65535))
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if parsed.OPT.UDPPayloadSize != MaxEDNSUDPSize {
+		t.Errorf("UDPPayloadSize = %d, want capped to %d", parsed.OPT.UDPPayloadSize, MaxEDNSUDPSize)
+	}
+}
+
+// TestBuildQuery_WithEDNS_Options validates that arbitrary EDNS options
+// (NSID, EDE, padding) round-trip through the OPT record's RDATA.
+func TestBuildQuery_WithEDNS_Options(t *testing.T) {
+	opts := []EDNSOption{
+		{Code: OptionCodeNSID, Data: []byte{}},
+		{Code: OptionCodePadding, Data: []byte{0, 0, 0, 0}},
+		{Code: OptionCodeEDE, Data: []byte{0, 1}},
+	}
+
+	query, err := BuildQuery("test.local", 1, WithEDNS(1232, opts...))
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if len(parsed.OPT.Options) != len(opts) {
+		t.Fatalf("got %d options, want %d", len(parsed.OPT.Options), len(opts))
+	}
+	for i, want := range opts {
+		got := parsed.OPT.Options[i]
+		if got.Code != want.Code {
+			t.Errorf("option[%d].Code = %v, want %v", i, got.Code, want.Code)
+		}
+		if len(got.Data) != len(want.Data) {
+			t.Errorf("option[%d].Data = %v, want %v", i, got.Data, want.Data)
+		}
+	}
+}
+
+// TestBuildQuery_WithoutEDNS_Unchanged validates that BuildQuery without
+// WithEDNS still produces a query with ARCOUNT=0 and no OPT record, matching
+// its pre-EDNS behavior exactly.
+func TestBuildQuery_WithoutEDNS_Unchanged(t *testing.T) {
+	query, err := BuildQuery("test.local", 1)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	arcount := binary.BigEndian.Uint16(query[10:12])
+	if arcount != 0 {
+		t.Errorf("ARCOUNT is %d, expected 0 without WithEDNS", arcount)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+	if parsed.OPT != nil {
+		t.Errorf("parsed.OPT = %+v, want nil", parsed.OPT)
+	}
+}
+
+// TestBuildQuery_WithDNSSEC_SetsDOBit validates that WithDNSSEC sets the DO
+// bit on the OPT record, and that it implies an OPT record even without an
+// explicit WithEDNS option.
+func TestBuildQuery_WithDNSSEC_SetsDOBit(t *testing.T) {
+	query, err := BuildQuery("test.local", 1, WithDNSSEC())
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if parsed.OPT == nil {
+		t.Fatal("parsed message has no OPT record")
+	}
+	if !parsed.OPT.DNSSECOK {
+		t.Error("OPT.DNSSECOK = false, want true with WithDNSSEC")
+	}
+	if parsed.OPT.UDPPayloadSize != DefaultEDNSUDPSize {
+		t.Errorf("UDPPayloadSize = %d, want %d (WithDNSSEC alone should default it)", parsed.OPT.UDPPayloadSize, DefaultEDNSUDPSize)
+	}
+}
+
+// TestBuildQuery_WithEDNS_WithDNSSEC_Combine validates that WithEDNS and
+// WithDNSSEC compose regardless of order, both configuring the same OPT
+// record.
+func TestBuildQuery_WithEDNS_WithDNSSEC_Combine(t *testing.T) {
+	query, err := BuildQuery("test.local", 1, WithEDNS(4096), WithDNSSEC())
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(query)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if !parsed.OPT.DNSSECOK {
+		t.Error("OPT.DNSSECOK = false, want true")
+	}
+	if parsed.OPT.UDPPayloadSize != 4096 {
+		t.Errorf("UDPPayloadSize = %d, want 4096", parsed.OPT.UDPPayloadSize)
+	}
+}
+
+// TestEncodeEDNSOptions_Empty validates that encoding an empty option list
+// produces empty RDATA.
+func TestEncodeEDNSOptions_Empty(t *testing.T) {
+	rdata := EncodeEDNSOptions(nil)
+	if len(rdata) != 0 {
+		t.Errorf("EncodeEDNSOptions(nil) = %v, want empty", rdata)
+	}
+}
@@ -0,0 +1,136 @@
+package message
+
+import (
+	"encoding/binary"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// Compressor incrementally encodes DNS names into wire format, reusing the
+// longest already-written suffix as an RFC 1035 §4.1.4 compression pointer
+// instead of spelling it out again. It is the exported counterpart of the
+// nameEncoder this package's builders already use internally - EncodeMessage
+// is built on the same mechanism.
+//
+// A Compressor is not safe for concurrent use.
+type Compressor struct {
+	enc *nameEncoder
+}
+
+// NewCompressor creates a Compressor with an empty buffer. Use
+// NewCompressorWithPrefix instead when the encoded names will be appended
+// after an already-written message prefix (e.g. a header), so compression
+// offsets land at their true position in the final message.
+func NewCompressor() *Compressor {
+	return &Compressor{enc: newNameEncoder(nil)}
+}
+
+// NewCompressorWithPrefix creates a Compressor seeded with prefix, so any
+// name suffix occurring within prefix (e.g. a question section written
+// separately) can still be found and compressed against.
+func NewCompressorWithPrefix(prefix []byte) *Compressor {
+	return &Compressor{enc: newNameEncoder(prefix)}
+}
+
+// WriteName encodes name, compressing against any suffix already written to
+// this Compressor (via WriteName or the prefix it was created with). A
+// suffix whose offset is past RFC 1035 §4.1.4's 14-bit pointer range
+// (16384 bytes) is written uncompressed instead of recorded as a future
+// compression target, as the RFC requires.
+func (c *Compressor) WriteName(name string) error {
+	return c.enc.writeName(name)
+}
+
+// Bytes returns everything written to the Compressor so far.
+func (c *Compressor) Bytes() []byte {
+	return c.enc.Bytes()
+}
+
+// EncodeMessage serializes msg to wire format per RFC 1035 §4.1. When
+// compress is true, names are compressed per RFC 1035 §4.1.4 (questions,
+// answers, authorities, and additionals all share one compression table, so
+// a name repeated across sections - e.g. a PTR's owner name reappearing as
+// an SRV's target - is written once); when false, every name is written out
+// in full, matching what EncodeName alone would produce.
+//
+// EncodeMessage recomputes QDCOUNT/ANCOUNT/NSCOUNT/ARCOUNT from the length
+// of the corresponding slices rather than trusting msg.Header's counts, the
+// same way BuildResponse derives ANCOUNT from len(answers).
+//
+// EncodeMessage/ParseMessage is this package's Marshal/Unmarshal pair - named
+// to match BuildQuery/BuildResponse/ParseRDATA's Build/Parse vocabulary
+// rather than encoding/json's, since neither side round-trips through Go's
+// Marshaler/Unmarshaler interfaces. FuzzEncodeMessageRoundTrip
+// (fuzz_test.go) asserts EncodeMessage(m) decoded via ParseMessage
+// reproduces a one-answer m's NAME, TYPE, cache-flush bit, TTL and RDATA.
+func EncodeMessage(msg *DNSMessage, compress bool) ([]byte, error) {
+	if msg == nil {
+		return nil, &errors.ValidationError{
+			Field:   "DNSMessage",
+			Value:   nil,
+			Message: "cannot encode nil message",
+			Code:    errors.CodeInvalidValue,
+		}
+	}
+
+	arcount := len(msg.Additionals)
+	if msg.OPT != nil {
+		arcount++
+	}
+
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint16(header[0:2], msg.Header.ID)
+	binary.BigEndian.PutUint16(header[2:4], msg.Header.Flags)
+	binary.BigEndian.PutUint16(header[4:6], uint16(len(msg.Questions)))    //nolint:gosec // G115: bounded by the 9000-byte RFC 6762 §17 message size limit
+	binary.BigEndian.PutUint16(header[6:8], uint16(len(msg.Answers)))      //nolint:gosec // G115: see above
+	binary.BigEndian.PutUint16(header[8:10], uint16(len(msg.Authorities))) //nolint:gosec // G115: see above
+	binary.BigEndian.PutUint16(header[10:12], uint16(arcount))             //nolint:gosec // G115: see above
+
+	var enc *nameEncoder
+	if compress {
+		enc = newNameEncoder(header)
+	} else {
+		enc = newUncompressedNameEncoder(header)
+	}
+
+	for _, q := range msg.Questions {
+		if err := enc.writeQuestion(q.QNAME, q.QTYPE, q.WantsUnicastResponse()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, sections := range [][]Answer{msg.Answers, msg.Authorities, msg.Additionals} {
+		for _, a := range sections {
+			if err := enc.writeResourceRecord(AnswerToResourceRecord(a)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if msg.OPT != nil {
+		enc.writeOPTRecord(&ednsOptions{
+			udpPayloadSize: msg.OPT.UDPPayloadSize,
+			dnssecOK:       msg.OPT.DNSSECOK,
+			options:        msg.OPT.Options,
+		})
+	}
+
+	return enc.Bytes(), nil
+}
+
+// AnswerToResourceRecord adapts a parsed Answer into the ResourceRecord shape
+// writeResourceRecord expects, splitting CLASS's cache-flush bit (RFC 6762
+// §10.2) out the same way writeResourceRecord packs it back in. Exported so
+// a caller outside this package (e.g. state.Prober, matching a received
+// probe response against its own records) can reuse the same conversion.
+func AnswerToResourceRecord(a Answer) *ResourceRecord {
+	return &ResourceRecord{
+		Name:       a.NAME,
+		Type:       protocol.RecordType(a.TYPE),
+		Class:      protocol.DNSClass(a.RRClass()),
+		TTL:        a.TTL,
+		Data:       a.RDATA,
+		CacheFlush: a.CacheFlush(),
+	}
+}
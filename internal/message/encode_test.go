@@ -0,0 +1,113 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestCompressor_SharesTcpLocalSuffix validates that writing
+// "_http._tcp.local", "_ipp._tcp.local", and "printer._http._tcp.local" in
+// sequence compresses their shared "_tcp.local" (and, for the third name,
+// "_http._tcp.local") suffix into a pointer rather than repeating the
+// labels, and that each name round-trips through ParseName at the offset it
+// was written at.
+func TestCompressor_SharesTcpLocalSuffix(t *testing.T) {
+	c := NewCompressorWithPrefix(make([]byte, 12))
+
+	names := []string{"_http._tcp.local", "_ipp._tcp.local", "printer._http._tcp.local"}
+	offsets := make([]int, len(names))
+	for i, name := range names {
+		offsets[i] = len(c.Bytes())
+		if err := c.WriteName(name); err != nil {
+			t.Fatalf("WriteName(%q) failed: %v", name, err)
+		}
+	}
+
+	wire := c.Bytes()
+
+	// The second and third names must be shorter on the wire than the first,
+	// since they compress against labels the first one already wrote.
+	firstLen := offsets[1] - offsets[0]
+	secondLen := offsets[2] - offsets[1]
+	if secondLen >= firstLen {
+		t.Errorf("_ipp._tcp.local encoded as %d bytes, want fewer than _http._tcp.local's %d (suffix should compress)", secondLen, firstLen)
+	}
+
+	for i, name := range names {
+		got, _, err := ParseName(wire, offsets[i])
+		if err != nil {
+			t.Fatalf("ParseName at offset %d failed: %v", offsets[i], err)
+		}
+		if got != name {
+			t.Errorf("ParseName at offset %d = %q, want %q", offsets[i], got, name)
+		}
+	}
+
+	// The third name ("printer._http._tcp.local") shares its entire
+	// "_http._tcp.local" suffix with the first name, so it should compress
+	// down to just the "printer" label plus a 2-byte pointer.
+	wantThirdLen := 1 + len("printer") + 2
+	if gotThirdLen := len(wire) - offsets[2]; gotThirdLen != wantThirdLen {
+		t.Errorf("printer._http._tcp.local encoded as %d bytes, want %d (label + pointer)", gotThirdLen, wantThirdLen)
+	}
+}
+
+// TestEncodeMessage_CompressedRoundTrip validates that EncodeMessage(msg,
+// true) compresses a response whose records share name suffixes, and that
+// ParseMessage recovers the same names from the compressed wire bytes.
+func TestEncodeMessage_CompressedRoundTrip(t *testing.T) {
+	msg := &DNSMessage{
+		Header: DNSHeader{Flags: protocol.FlagQR | protocol.FlagAA},
+		Answers: []Answer{
+			{NAME: "_http._tcp.local", TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: mustEncodeNameForTest(t, "printer._http._tcp.local")},
+			{NAME: "_ipp._tcp.local", TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: mustEncodeNameForTest(t, "printer._ipp._tcp.local")},
+		},
+	}
+
+	compressed, err := EncodeMessage(msg, true)
+	if err != nil {
+		t.Fatalf("EncodeMessage(compress=true) failed: %v", err)
+	}
+	uncompressed, err := EncodeMessage(msg, false)
+	if err != nil {
+		t.Fatalf("EncodeMessage(compress=false) failed: %v", err)
+	}
+
+	if len(compressed) >= len(uncompressed) {
+		t.Errorf("compressed message is %d bytes, want fewer than uncompressed %d bytes", len(compressed), len(uncompressed))
+	}
+
+	for _, wire := range [][]byte{compressed, uncompressed} {
+		parsed, err := ParseMessage(wire)
+		if err != nil {
+			t.Fatalf("ParseMessage failed: %v", err)
+		}
+		if len(parsed.Answers) != 2 {
+			t.Fatalf("got %d answers, want 2", len(parsed.Answers))
+		}
+		if parsed.Answers[0].NAME != "_http._tcp.local" {
+			t.Errorf("Answers[0].NAME = %q, want %q", parsed.Answers[0].NAME, "_http._tcp.local")
+		}
+		if parsed.Answers[1].NAME != "_ipp._tcp.local" {
+			t.Errorf("Answers[1].NAME = %q, want %q", parsed.Answers[1].NAME, "_ipp._tcp.local")
+		}
+	}
+}
+
+// TestEncodeMessage_NilMessage validates that EncodeMessage rejects a nil
+// message rather than panicking.
+func TestEncodeMessage_NilMessage(t *testing.T) {
+	if _, err := EncodeMessage(nil, true); err == nil {
+		t.Fatal("expected EncodeMessage(nil, ...) to return an error")
+	}
+}
+
+func mustEncodeNameForTest(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := EncodeName(name)
+	if err != nil {
+		t.Fatalf("EncodeName(%q) failed: %v", name, err)
+	}
+	return encoded
+}
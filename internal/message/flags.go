@@ -0,0 +1,222 @@
+package message
+
+import (
+	"fmt"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// IsAuthoritative returns true if the AA bit is set per RFC 1035 §4.1.1.
+//
+// RFC 6762 §18.4: a response MUST have AA set, since mDNS has no notion of a
+// non-authoritative responder - every responder speaks only for records it
+// itself holds.
+func (h *DNSHeader) IsAuthoritative() bool {
+	return (h.Flags & 0x0400) != 0
+}
+
+// IsRecursionDesired returns true if the RD bit is set per RFC 1035 §4.1.1.
+//
+// RFC 6762 §18.6: RD SHOULD be zero on transmission - mDNS resolves only
+// names within the local link, so recursion has no meaning here.
+func (h *DNSHeader) IsRecursionDesired() bool {
+	return (h.Flags & 0x0100) != 0
+}
+
+// IsRecursionAvailable returns true if the RA bit is set per RFC 1035 §4.1.1.
+//
+// RFC 6762 §18.7: RA SHOULD be zero on transmission, for the same reason as RD.
+func (h *DNSHeader) IsRecursionAvailable() bool {
+	return (h.Flags & 0x0080) != 0
+}
+
+// IsReservedBitSet returns true if the Z bit (bit 6, reserved) is set per
+// RFC 1035 §4.1.1.
+//
+// RFC 6762 §18.8: Z SHOULD be zero on transmission. Note bits 5 and 4 of
+// this same nibble have since been reassigned to AD (IsAuthenticData) and CD
+// (IsCheckingDisabled) per RFC 4035 §3.2 - only bit 6 remains reserved.
+func (h *DNSHeader) IsReservedBitSet() bool {
+	return (h.Flags & 0x0040) != 0
+}
+
+// HeaderFlags is DNSHeader.Flags unpacked into its named RFC 1035 §4.1.1 bit
+// fields (with AD/CD per RFC 4035 §3.2), for a caller that wants to
+// construct or inspect a header declaratively instead of hand-computing hex
+// like 0x8105.
+//
+// Opcode and RCODE are the same uint8 range GetOPCODE/GetRCODE already
+// return; Pack does not validate them, so a caller building one of these by
+// hand is responsible for keeping Opcode/RCODE within 4 bits, same as
+// DNSHeader.Flags itself imposes no range checks on its raw bits.
+type HeaderFlags struct {
+	QR     bool
+	Opcode uint8
+	AA     bool
+	TC     bool
+	RD     bool
+	RA     bool
+	Z      bool // reserved, MUST be zero per RFC 1035 §4.1.1 (see IsReservedBitSet)
+	AD     bool
+	CD     bool
+	RCODE  uint8
+}
+
+// Pack encodes f into the 16-bit wire representation of DNSHeader.Flags.
+func (f HeaderFlags) Pack() uint16 {
+	var flags uint16
+	if f.QR {
+		flags |= 0x8000
+	}
+	flags |= uint16(f.Opcode&0x0F) << 11
+	if f.AA {
+		flags |= 0x0400
+	}
+	if f.TC {
+		flags |= 0x0200
+	}
+	if f.RD {
+		flags |= 0x0100
+	}
+	if f.RA {
+		flags |= 0x0080
+	}
+	if f.Z {
+		flags |= 0x0040
+	}
+	if f.AD {
+		flags |= 0x0020
+	}
+	if f.CD {
+		flags |= 0x0010
+	}
+	flags |= uint16(f.RCODE & 0x0F)
+	return flags
+}
+
+// UnpackFlags decodes a wire Flags value into a HeaderFlags.
+func UnpackFlags(flags uint16) HeaderFlags {
+	return HeaderFlags{
+		QR:     flags&0x8000 != 0,
+		Opcode: uint8(flags>>11) & 0x0F, //nolint:gosec // G115: masked to 4 bits
+		AA:     flags&0x0400 != 0,
+		TC:     flags&0x0200 != 0,
+		RD:     flags&0x0100 != 0,
+		RA:     flags&0x0080 != 0,
+		Z:      flags&0x0040 != 0,
+		AD:     flags&0x0020 != 0,
+		CD:     flags&0x0010 != 0,
+		RCODE:  uint8(flags & 0x0F), //nolint:gosec // G115: masked to 4 bits
+	}
+}
+
+// Unpack returns h.Flags as a HeaderFlags.
+func (h *DNSHeader) Unpack() HeaderFlags {
+	return UnpackFlags(h.Flags)
+}
+
+// MessageKind identifies which RFC 6762 §18 header rules DNSHeader.ValidateMDNS
+// should enforce: a probe and an announcement are both wire-format queries
+// and responses respectively (QR bit included), but are distinguished here
+// so a caller validating against the right expectation doesn't have to
+// rederive "probe is a query" / "announcement is a response" itself.
+type MessageKind int
+
+const (
+	// KindQuery is an ordinary mDNS query (RFC 6762 §18, QR=0).
+	KindQuery MessageKind = iota
+
+	// KindResponse is an ordinary mDNS response (RFC 6762 §18, QR=1, AA=1).
+	KindResponse
+
+	// KindProbe is an RFC 6762 §8.1 probe: a query (QR=0) for a name not yet
+	// claimed, with tentative records carried in the Authority section.
+	KindProbe
+
+	// KindAnnouncement is an RFC 6762 §8.3 announcement: an unsolicited
+	// response (QR=1, AA=1) sent without a preceding query.
+	KindAnnouncement
+)
+
+// String returns k's name, e.g. "query" or "announcement".
+func (k MessageKind) String() string {
+	switch k {
+	case KindQuery:
+		return "query"
+	case KindResponse:
+		return "response"
+	case KindProbe:
+		return "probe"
+	case KindAnnouncement:
+		return "announcement"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidateMDNS checks h against the RFC 6762 §18 header rules for kind,
+// returning the first violation found as a *errors.ValidationError carrying
+// a distinguishing Code (CodeNonZeroOpcode, CodeNonZeroRCODE,
+// CodeUnexpectedQR, CodeMissingAA, or CodeNonZeroReservedFlag) so a caller
+// can build per-reason drop-rate metrics instead of string-matching Error().
+//
+// h.ID is deliberately not checked: RFC 6762 §18.1 only SHOULDs zero, and
+// BuildQuery intentionally uses a random ID instead (see DNSHeader.ID's doc
+// comment) for forward compatibility, so enforcing zero here would flag
+// Beacon's own queries as noncompliant. TC is not checked either: RFC 6762
+// §18.5 allows it set on a query followed by a second Known-Answer packet,
+// so a set TC bit is never by itself a violation for any MessageKind.
+//
+// ValidateMDNS is a stricter, opt-in complement to ValidateResponse
+// (transaction correlation) and protocol.ValidateRCODE (the bare RCODE check
+// querier.go already runs on every inbound packet) - nothing in this package
+// calls it automatically.
+func (h *DNSHeader) ValidateMDNS(kind MessageKind) error {
+	if opcode := h.GetOPCODE(); opcode != 0 {
+		return &errors.ValidationError{
+			Field:   "OPCODE",
+			Value:   opcode,
+			Message: "OPCODE MUST be 0 per RFC 6762 §18.3",
+			Code:    errors.CodeNonZeroOpcode,
+		}
+	}
+
+	if rcode := h.GetRCODE(); rcode != 0 {
+		return &errors.ValidationError{
+			Field:   "RCODE",
+			Value:   rcode,
+			Message: "RCODE MUST be 0 per RFC 6762 §18.11",
+			Code:    errors.CodeNonZeroRCODE,
+		}
+	}
+
+	wantResponse := kind == KindResponse || kind == KindAnnouncement
+	if h.IsResponse() != wantResponse {
+		return &errors.ValidationError{
+			Field:   "QR",
+			Value:   h.IsResponse(),
+			Message: fmt.Sprintf("QR bit does not match a %s per RFC 6762 §18.2", kind),
+			Code:    errors.CodeUnexpectedQR,
+		}
+	}
+
+	if wantResponse && !h.IsAuthoritative() {
+		return &errors.ValidationError{
+			Field:   "AA",
+			Value:   false,
+			Message: "AA bit MUST be 1 in a response per RFC 6762 §18.4",
+			Code:    errors.CodeMissingAA,
+		}
+	}
+
+	if h.IsRecursionDesired() || h.IsRecursionAvailable() || h.IsReservedBitSet() {
+		return &errors.ValidationError{
+			Field:   "RD/RA/Z",
+			Value:   h.Flags,
+			Message: "RD, RA, and Z SHOULD be 0 per RFC 6762 §18.6/§18.7/§18.8",
+			Code:    errors.CodeNonZeroReservedFlag,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package message
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// TestDNSHeader_IsAuthoritative validates that IsAuthoritative extracts the
+// AA bit per RFC 1035 §4.1.1.
+func TestDNSHeader_IsAuthoritative(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags uint16
+		want  bool
+	}{
+		{name: "AA=0", flags: 0x8000, want: false},
+		{name: "AA=1", flags: 0x8400, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &DNSHeader{Flags: tt.flags}
+			if got := header.IsAuthoritative(); got != tt.want {
+				t.Errorf("IsAuthoritative() with flags=0x%04X = %v, want %v", tt.flags, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHeaderFlags_PackUnpackRoundTrip validates that UnpackFlags(f.Pack())
+// reproduces f for every named field.
+func TestHeaderFlags_PackUnpackRoundTrip(t *testing.T) {
+	want := HeaderFlags{QR: true, Opcode: 0, AA: true, TC: false, RD: false, RA: false, Z: false, AD: true, CD: false, RCODE: 0}
+
+	packed := want.Pack()
+	got := UnpackFlags(packed)
+
+	if got != want {
+		t.Errorf("UnpackFlags(Pack(%+v)) = %+v, want %+v", want, got, want)
+	}
+
+	header := &DNSHeader{Flags: packed}
+	if header.Unpack() != want {
+		t.Errorf("DNSHeader.Unpack() = %+v, want %+v", header.Unpack(), want)
+	}
+}
+
+// TestHeaderFlags_Pack_MatchesHandBuiltResponseFlags validates that Pack
+// produces the same 0x8400 (QR=1, AA=1) buildResponseHeader already writes
+// by hand, so the typed struct and the raw hex agree on the bit layout.
+func TestHeaderFlags_Pack_MatchesHandBuiltResponseFlags(t *testing.T) {
+	flags := HeaderFlags{QR: true, AA: true}.Pack()
+	if flags != 0x8400 {
+		t.Errorf("Pack() = 0x%04X, want 0x8400", flags)
+	}
+}
+
+// TestDNSHeader_ValidateMDNS validates that ValidateMDNS enforces RFC 6762
+// §18's OPCODE/RCODE/QR/AA/RD/RA/Z rules and returns a distinguishing Code
+// for each violation.
+func TestDNSHeader_ValidateMDNS(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    uint16
+		kind     MessageKind
+		wantCode errors.Code
+	}{
+		{name: "valid query", flags: HeaderFlags{}.Pack(), kind: KindQuery, wantCode: errors.CodeUnspecified},
+		{name: "valid probe", flags: HeaderFlags{}.Pack(), kind: KindProbe, wantCode: errors.CodeUnspecified},
+		{name: "valid response", flags: HeaderFlags{QR: true, AA: true}.Pack(), kind: KindResponse, wantCode: errors.CodeUnspecified},
+		{name: "valid announcement", flags: HeaderFlags{QR: true, AA: true}.Pack(), kind: KindAnnouncement, wantCode: errors.CodeUnspecified},
+		{name: "non-zero OPCODE", flags: HeaderFlags{Opcode: 1}.Pack(), kind: KindQuery, wantCode: errors.CodeNonZeroOpcode},
+		{name: "non-zero RCODE", flags: HeaderFlags{RCODE: 1}.Pack(), kind: KindQuery, wantCode: errors.CodeNonZeroRCODE},
+		{name: "query with QR set", flags: HeaderFlags{QR: true, AA: true}.Pack(), kind: KindQuery, wantCode: errors.CodeUnexpectedQR},
+		{name: "response with QR clear", flags: HeaderFlags{}.Pack(), kind: KindResponse, wantCode: errors.CodeUnexpectedQR},
+		{name: "response without AA", flags: HeaderFlags{QR: true}.Pack(), kind: KindResponse, wantCode: errors.CodeMissingAA},
+		{name: "query with RD set", flags: HeaderFlags{RD: true}.Pack(), kind: KindQuery, wantCode: errors.CodeNonZeroReservedFlag},
+		{name: "query with RA set", flags: HeaderFlags{RA: true}.Pack(), kind: KindQuery, wantCode: errors.CodeNonZeroReservedFlag},
+		{name: "query with Z set", flags: HeaderFlags{Z: true}.Pack(), kind: KindQuery, wantCode: errors.CodeNonZeroReservedFlag},
+		{name: "query with TC set is not a violation", flags: HeaderFlags{TC: true}.Pack(), kind: KindQuery, wantCode: errors.CodeUnspecified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &DNSHeader{Flags: tt.flags}
+			err := header.ValidateMDNS(tt.kind)
+
+			if tt.wantCode == errors.CodeUnspecified {
+				if err != nil {
+					t.Errorf("ValidateMDNS(%s) = %v, want nil", tt.kind, err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("ValidateMDNS(%s) = nil, want error with Code %v", tt.kind, tt.wantCode)
+			}
+			var ve *errors.ValidationError
+			if !goerrors.As(err, &ve) {
+				t.Fatalf("ValidateMDNS(%s) error = %T, want *errors.ValidationError", tt.kind, err)
+			}
+			if ve.Code != tt.wantCode {
+				t.Errorf("ValidateMDNS(%s) Code = %v, want %v", tt.kind, ve.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestDNSHeader_ValidateMDNS_NonZeroIDNotAViolation validates that
+// ValidateMDNS does not reject a query's non-zero ID, since BuildQuery
+// intentionally uses a random one (see DNSHeader.ID's doc comment).
+func TestDNSHeader_ValidateMDNS_NonZeroIDNotAViolation(t *testing.T) {
+	header := &DNSHeader{ID: 0x1234, Flags: HeaderFlags{}.Pack()}
+	if err := header.ValidateMDNS(KindQuery); err != nil {
+		t.Errorf("ValidateMDNS(KindQuery) with non-zero ID = %v, want nil", err)
+	}
+}
+
+// TestDNSHeader_ValidateMDNS_SentinelErrors validates that each violation's
+// error matches its corresponding errors.ErrX sentinel via errors.Is.
+func TestDNSHeader_ValidateMDNS_SentinelErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     *DNSHeader
+		kind       MessageKind
+		wantTarget error
+	}{
+		{name: "opcode", header: &DNSHeader{Flags: HeaderFlags{Opcode: 1}.Pack()}, kind: KindQuery, wantTarget: errors.ErrNonZeroOpcode},
+		{name: "rcode", header: &DNSHeader{Flags: HeaderFlags{RCODE: 1}.Pack()}, kind: KindQuery, wantTarget: errors.ErrNonZeroRCODE},
+		{name: "qr", header: &DNSHeader{Flags: HeaderFlags{QR: true, AA: true}.Pack()}, kind: KindQuery, wantTarget: errors.ErrUnexpectedQR},
+		{name: "aa", header: &DNSHeader{Flags: HeaderFlags{QR: true}.Pack()}, kind: KindResponse, wantTarget: errors.ErrMissingAA},
+		{name: "reserved", header: &DNSHeader{Flags: HeaderFlags{RD: true}.Pack()}, kind: KindQuery, wantTarget: errors.ErrNonZeroReservedFlag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.header.ValidateMDNS(tt.kind)
+			if !goerrors.Is(err, tt.wantTarget) {
+				t.Errorf("ValidateMDNS(%s) = %v, want errors.Is match against %v", tt.kind, err, tt.wantTarget)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package message
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// The DNS parser in this package is exposed to arbitrary link-local UDP
+// (RFC 6762 §11 applies no trust to the network), so every entry point below
+// is fuzzed against the same invariants: it must never panic on arbitrary
+// input, any error it returns must be a *errors.WireFormatError (never a
+// bare string or an unexported sentinel a caller can't type-assert on), and
+// name decompression must never advance an offset past len(msg) or loop -
+// ParseName already enforces both via NameParser's visited-offset tracking
+// and protocol.MaxCompressionPointers (see name_pointer_hardening_test.go),
+// surfacing a *errors.WireFormatError with Code: errors.CodeBadCompressionPointer
+// rather than a separate ErrCompressionLoop sentinel.
+
+// assertWireFormatError fails t if err is non-nil but not a
+// *errors.WireFormatError, per this file's shared invariant.
+func assertWireFormatError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		return
+	}
+	var wfe *errors.WireFormatError
+	if !goerrors.As(err, &wfe) {
+		t.Errorf("error = %v (%T), want *errors.WireFormatError", err, err)
+	}
+}
+
+// FuzzParseMessage fuzzes ParseMessage with arbitrary bytes, seeded from
+// this package's own hand-built valid and malformed packets.
+func FuzzParseMessage(f *testing.F) {
+	for _, seed := range fuzzSeedMessages() {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, msg []byte) {
+		parsed, err := ParseMessage(msg)
+		assertWireFormatError(t, err)
+		if err != nil && parsed != nil {
+			t.Errorf("ParseMessage returned a non-nil message alongside a non-nil error")
+		}
+	})
+}
+
+// FuzzParseAnswer fuzzes ParseAnswer, the per-record decoder ParseMessage's
+// section loop calls repeatedly, at arbitrary offsets into arbitrary bytes.
+func FuzzParseAnswer(f *testing.F) {
+	for _, seed := range fuzzSeedMessages() {
+		f.Add(seed, 12) // 12: right after a fixed-size header, where this file's seeds put their question
+	}
+
+	f.Fuzz(func(t *testing.T, msg []byte, offset int) {
+		if offset < 0 || offset > len(msg) {
+			t.Skip("offset out of ParseAnswer's documented range")
+		}
+		answer, newOffset, err := ParseAnswer(msg, offset)
+		assertWireFormatError(t, err)
+		if err == nil && (newOffset < offset || newOffset > len(msg)) {
+			t.Errorf("ParseAnswer(msg, %d) = (%+v, %d, nil): newOffset out of [%d, %d]", offset, answer, newOffset, offset, len(msg))
+		}
+	})
+}
+
+// FuzzParseRDATA fuzzes ParseRDATA across a selection of the record TYPEs
+// it type-decodes, plus 41 (OPT) and 255, which it does not decode, to
+// exercise its unsupported-TYPE error path.
+func FuzzParseRDATA(f *testing.F) {
+	for _, recordType := range []uint16{1, 12, 16, 28, 33, 41, 43, 47, 64, 65, 255} {
+		for _, seed := range fuzzSeedRDATA() {
+			f.Add(recordType, seed)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, recordType uint16, rdata []byte) {
+		_, err := ParseRDATA(recordType, rdata)
+		assertWireFormatError(t, err)
+	})
+}
+
+// FuzzDecompressName fuzzes ParseName, this package's name-decompression
+// entry point (there is no separate DecompressName function - decompression
+// is ParseName's job whenever it follows a 0xC0 pointer), at arbitrary
+// offsets into arbitrary bytes.
+func FuzzDecompressName(f *testing.F) {
+	chainMsg, chainOffsets := buildPointerChain(10, 4)
+	f.Add(chainMsg, chainOffsets[len(chainOffsets)-1])
+
+	for _, seed := range fuzzSeedMessages() {
+		f.Add(seed, 12)
+	}
+
+	f.Fuzz(func(t *testing.T, msg []byte, offset int) {
+		if offset < 0 || offset > len(msg) {
+			t.Skip("offset out of ParseName's documented range")
+		}
+		name, newOffset, err := ParseName(msg, offset)
+		assertWireFormatError(t, err)
+		if err == nil {
+			if newOffset < offset || newOffset > len(msg) {
+				t.Errorf("ParseName(msg, %d) = (%q, %d, nil): newOffset out of [%d, %d]", offset, name, newOffset, offset, len(msg))
+			}
+			if len(name) > 255 {
+				t.Errorf("ParseName(msg, %d) = %q: %d bytes exceeds RFC 1035 §3.1's 255-byte limit", offset, name, len(name))
+			}
+		}
+	})
+}
+
+// FuzzEncodeMessageRoundTrip fuzzes EncodeMessage/ParseMessage - this
+// package's Marshal/Unmarshal pair - asserting that a message built from
+// arbitrary fields survives the round trip unchanged. recordType is folded
+// into {A, TXT} so rdata never has to satisfy a name-embedding record's
+// encoding (PTR/SRV's compression round trip is already covered by
+// TestEncodeMessage_CompressedRoundTrip and TestCompressor_SharesTcpLocalSuffix).
+func FuzzEncodeMessageRoundTrip(f *testing.F) {
+	f.Add("host.local", uint16(0), uint32(120), true, []byte{192, 168, 1, 1})
+	f.Add("_http._tcp.local", uint16(1), uint32(4500), false, []byte("key=value"))
+
+	f.Fuzz(func(t *testing.T, name string, recordTypeSelector uint16, ttl uint32, cacheFlush bool, rdata []byte) {
+		recordType := uint16(1) // A
+		if recordTypeSelector%2 == 1 {
+			recordType = 16 // TXT
+		}
+
+		msg := &DNSMessage{
+			Header: DNSHeader{Flags: 0x8400}, // QR=1, AA=1
+			Answers: []Answer{{
+				NAME: name,
+				TYPE: recordType,
+				CLASS: func() uint16 {
+					if cacheFlush {
+						return uint16(protocol.ClassIN) | 0x8000
+					}
+					return uint16(protocol.ClassIN)
+				}(),
+				TTL:   ttl,
+				RDATA: rdata,
+			}},
+		}
+
+		wire, err := EncodeMessage(msg, true)
+		if err != nil {
+			t.Skip("name rejected by EncodeOwnerName's validation, nothing to round-trip")
+		}
+
+		parsed, err := ParseMessage(wire)
+		if err != nil {
+			t.Fatalf("ParseMessage(EncodeMessage(msg)) failed: %v", err)
+		}
+		if len(parsed.Answers) != 1 {
+			t.Fatalf("got %d answers, want 1", len(parsed.Answers))
+		}
+
+		// "" and "." both denote the root domain; ParseName's canonical form
+		// for it is "", so collapse both sides before comparing.
+		wantName, gotName := name, parsed.Answers[0].NAME
+		if wantName == "." {
+			wantName = ""
+		}
+		if gotName == "." {
+			gotName = ""
+		}
+		got := parsed.Answers[0]
+		if gotName != wantName {
+			t.Errorf("NAME = %q, want %q", got.NAME, name)
+		}
+		if got.TYPE != recordType {
+			t.Errorf("TYPE = %d, want %d", got.TYPE, recordType)
+		}
+		if (got.CLASS&0x8000 != 0) != cacheFlush {
+			t.Errorf("cache-flush bit = %v, want %v", got.CLASS&0x8000 != 0, cacheFlush)
+		}
+		if got.TTL != ttl {
+			t.Errorf("TTL = %d, want %d", got.TTL, ttl)
+		}
+		if string(got.RDATA) != string(rdata) {
+			t.Errorf("RDATA = %v, want %v", got.RDATA, rdata)
+		}
+	})
+}
+
+// fuzzSeedMessages returns a handful of hand-built whole messages - valid
+// A/PTR/SRV/TXT records, a truncated header, and a compression-pointer
+// packet - to seed this file's message-shaped fuzz targets.
+func fuzzSeedMessages() [][]byte {
+	header := func(qd, an, ns, ar uint16) []byte {
+		return []byte{
+			0x12, 0x34, // ID
+			0x84, 0x00, // Flags (QR=1, AA=1)
+			byte(qd >> 8), byte(qd),
+			byte(an >> 8), byte(an),
+			byte(ns >> 8), byte(ns),
+			byte(ar >> 8), byte(ar),
+		}
+	}
+	question := []byte{
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // QTYPE = A
+		0x00, 0x01, // QCLASS = IN
+	}
+
+	validA := append(append([]byte{}, header(1, 1, 0, 0)...), question...)
+	validA = append(validA,
+		0xC0, 0x0C, // NAME: pointer to question
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 100,
+	)
+
+	validPTR := append(append([]byte{}, header(1, 1, 0, 0)...),
+		0x05, '_', 'h', 't', 't', 'p',
+		0x04, '_', 't', 'c', 'p',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x0C, // QTYPE = PTR
+		0x00, 0x01, // QCLASS = IN
+	)
+	validPTR = append(validPTR,
+		0xC0, 0x0C, // NAME: pointer to question
+		0x00, 0x0C, // TYPE = PTR
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x0C, // RDLENGTH = 12
+		0x09, 'm', 'y', 's', 'e', 'r', 'v', 'i', 'c', 'e',
+		0xC0, 0x0C,
+	)
+
+	validSRV := append(append([]byte{}, header(1, 1, 0, 0)...), question...)
+	validSRV = append(validSRV,
+		0xC0, 0x0C,
+		0x00, 0x21, // TYPE = SRV
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0x00, 0x0F, // RDLENGTH = 15
+		0x00, 0x0A, 0x00, 0x14, 0x1F, 0x90, // priority, weight, port
+		0x06, 's', 'e', 'r', 'v', 'e', 'r',
+		0xC0, 0x11, // pointer to "local" inside the question
+	)
+
+	validTXT := append(append([]byte{}, header(1, 1, 0, 0)...), question...)
+	validTXT = append(validTXT,
+		0xC0, 0x0C,
+		0x00, 0x10, // TYPE = TXT
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0x00, 0x05, // RDLENGTH = 5
+		0x04, 'k', '=', 'v', '1',
+	)
+
+	truncatedHeader := header(1, 1, 0, 0)[:6] // cut mid-header
+
+	loopingPointer := append(append([]byte{}, header(1, 1, 0, 0)...), question...)
+	loopAnswerOffset := len(loopingPointer)
+	loopingPointer = append(loopingPointer,
+		0xC0, byte(loopAnswerOffset), // pointer to itself
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0x00, 0x04,
+		192, 168, 1, 100,
+	)
+
+	return [][]byte{validA, validPTR, validSRV, validTXT, truncatedHeader, loopingPointer}
+}
+
+// fuzzSeedRDATA returns a handful of hand-built RDATA payloads to seed
+// FuzzParseRDATA.
+func fuzzSeedRDATA() [][]byte {
+	return [][]byte{
+		{192, 168, 1, 100}, // A
+		{0x00, 0x0A, 0x00, 0x14, 0x1F, 0x90, 0x06, 's', 'e', 'r', 'v', 'e', 'r', 0x05, 'l', 'o', 'c', 'a', 'l', 0x00}, // SRV
+		{0x04, 'k', '=', 'v', '1'}, // TXT
+		{},                         // empty
+	}
+}
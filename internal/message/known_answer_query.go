@@ -0,0 +1,165 @@
+package message
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// ipUDPHeaderOverhead is the worst-case IPv4 + UDP header size (20 + 8
+// bytes) subtracted from an interface's MTU to get the space available for
+// DNS payload. IPv6 + UDP (40 + 8) is larger, so using the IPv4 figure keeps
+// the estimate conservative for dual-stack interfaces.
+const ipUDPHeaderOverhead = 28
+
+// averageKnownAnswerSize is a conservative per-record estimate: ~50 bytes
+// for a compressed name plus 10 bytes of TYPE/CLASS/TTL/RDLENGTH, excluding
+// RDATA (accounted for separately where the actual record is known). Unlike
+// ResponseBuilder's packet-size checks, which measure an already-built
+// message by actually encoding it, this only has a record count and an MTU
+// to work with - there's no message yet to serialize.
+const averageKnownAnswerSize = 50 + 10
+
+// MaxKnownAnswersPerPacket returns how many average-sized Known-Answer
+// records fit in a single query datagram addressed to an interface with the
+// given mtu, per RFC 6762 §7.1's Known-Answer list and §17's packet size
+// rules. The IPv4+UDP header overhead and the 12-byte DNS header are
+// reserved first; the rest is divided by averageKnownAnswerSize. No room is
+// reserved for the question section.
+//
+// mtu values too small to fit those headers (e.g. well under the
+// IPv6 minimum MTU of 1280) yield 0, signaling every Known-Answer must be
+// deferred to a follow-up packet.
+func MaxKnownAnswersPerPacket(mtu int) int {
+	available := mtu - ipUDPHeaderOverhead - 12 // header
+	if available <= 0 {
+		return 0
+	}
+	return available / averageKnownAnswerSize
+}
+
+// QueryBuilder assembles an mDNS query's Known-Answer list per RFC 6762
+// §7.1, splitting the list across multiple packets when it would not fit in
+// a single datagram for the target interface's MTU. Every packet Build
+// returns shares the same transaction ID, so a responder - or this
+// querier, matching incoming answers against its own outstanding queries -
+// can tell a continuation apart from an unrelated new query.
+//
+// This, BuildQueryWithKnownAnswers, and querier.KnownAnswerCache together
+// are this package's and the querier's answer to RFC 6762 §7.1/§7.2's
+// Known-Answer suppression: QueryBuilder is what Query reaches for once its
+// Known-Answer list is too large for a single packet (see
+// Querier.sendKnownAnswerFollowups), while BuildQueryWithKnownAnswers (used
+// directly by tests and by any caller not going through Querier) covers the
+// common single-packet case.
+//
+// RFC 6762 §7.2: a query whose Known-Answer list continues in a following
+// packet sets the TC (truncated) bit, and the follow-up packet(s) should be
+// sent within the window RFC 6762 §7.2 recommends (400-1000ms) so a
+// responder that already deferred its answer waiting for more Known-Answers
+// doesn't wait indefinitely. QueryBuilder only assembles the packets;
+// sending them within that window is the caller's responsibility.
+type QueryBuilder struct {
+	name         string
+	recordType   uint16
+	maxPerPacket int
+	id           uint16
+}
+
+// NewQueryBuilder creates a QueryBuilder for name/recordType, sizing each
+// packet's Known-Answer capacity from mtu via MaxKnownAnswersPerPacket.
+func NewQueryBuilder(name string, recordType uint16, mtu int) (*QueryBuilder, error) {
+	if !protocol.RecordType(recordType).IsSupported() {
+		return nil, &errors.ValidationError{
+			Field:   "recordType",
+			Value:   recordType,
+			Message: "unsupported record type (supports A, AAAA, PTR, SRV, TXT)",
+			Code:    errors.CodeUnsupportedRecordType,
+		}
+	}
+
+	id, err := randomQueryID()
+	if err != nil {
+		id = 0
+	}
+
+	return &QueryBuilder{
+		name:         name,
+		recordType:   recordType,
+		maxPerPacket: MaxKnownAnswersPerPacket(mtu),
+		id:           id,
+	}, nil
+}
+
+// Build encodes knownAnswers into one or more wire-format query packets.
+// When the list fits within the builder's MTU-derived capacity, Build
+// returns a single packet with TC clear. Otherwise it returns multiple
+// packets - every packet but the last with TC set per RFC 6762 §7.2 - all
+// carrying the same question and transaction ID.
+func (b *QueryBuilder) Build(knownAnswers []*ResourceRecord) ([][]byte, error) {
+	maxPerPacket := b.maxPerPacket
+	if maxPerPacket <= 0 && len(knownAnswers) > 0 {
+		maxPerPacket = 1 // always make progress, even under a pathologically small MTU
+	}
+
+	if maxPerPacket <= 0 || len(knownAnswers) <= maxPerPacket {
+		packet, err := b.buildPacket(knownAnswers, false)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{packet}, nil
+	}
+
+	var packets [][]byte
+	for start := 0; start < len(knownAnswers); start += maxPerPacket {
+		end := start + maxPerPacket
+		if end > len(knownAnswers) {
+			end = len(knownAnswers)
+		}
+
+		packet, err := b.buildPacket(knownAnswers[start:end], end < len(knownAnswers))
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, packet)
+	}
+
+	return packets, nil
+}
+
+// buildPacket encodes a single query packet carrying chunk as its
+// Known-Answer list, setting TC when truncated is true.
+func (b *QueryBuilder) buildPacket(chunk []*ResourceRecord, truncated bool) ([]byte, error) {
+	header := buildQueryHeaderWithAnswerCount(len(chunk))
+	binary.BigEndian.PutUint16(header[0:2], b.id)
+	if truncated {
+		flags := binary.BigEndian.Uint16(header[2:4])
+		binary.BigEndian.PutUint16(header[2:4], flags|protocol.FlagTC)
+	}
+
+	enc := newNameEncoder(header)
+	if err := enc.writeQuestion(b.name, b.recordType, false); err != nil {
+		return nil, err
+	}
+
+	for _, answer := range chunk {
+		if err := enc.writeResourceRecord(answer); err != nil {
+			return nil, err
+		}
+	}
+
+	return enc.Bytes(), nil
+}
+
+// randomQueryID generates a random 16-bit query ID the same way
+// buildQueryHeaderWithAnswerCount does.
+func randomQueryID() (uint16, error) {
+	idBig, err := rand.Int(rand.Reader, big.NewInt(65536))
+	if err != nil {
+		return 0, err
+	}
+	return uint16(idBig.Uint64() % 65536), nil //nolint:gosec // G115: rand.Int bounds upper limit to 65536
+}
@@ -0,0 +1,143 @@
+package message
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestMaxKnownAnswersPerPacket_Ethernet validates that a standard 1500-byte
+// Ethernet MTU yields a positive, bounded capacity.
+func TestMaxKnownAnswersPerPacket_Ethernet(t *testing.T) {
+	n := MaxKnownAnswersPerPacket(1500)
+	if n <= 0 {
+		t.Fatalf("MaxKnownAnswersPerPacket(1500) = %d, want > 0", n)
+	}
+	if n > 50 {
+		t.Errorf("MaxKnownAnswersPerPacket(1500) = %d, want a conservative bound under 50", n)
+	}
+}
+
+// TestMaxKnownAnswersPerPacket_TooSmall validates that an MTU too small to
+// hold a header and question yields 0 rather than a negative capacity.
+func TestMaxKnownAnswersPerPacket_TooSmall(t *testing.T) {
+	if n := MaxKnownAnswersPerPacket(10); n != 0 {
+		t.Errorf("MaxKnownAnswersPerPacket(10) = %d, want 0", n)
+	}
+}
+
+// TestQueryBuilder_SingleRecord validates that a known A record is emitted
+// in the Answer section with its encoded name, TTL, and 4-byte RDATA intact.
+func TestQueryBuilder_SingleRecord(t *testing.T) {
+	qb, err := NewQueryBuilder("host.local", uint16(protocol.RecordTypeA), 1500)
+	if err != nil {
+		t.Fatalf("NewQueryBuilder failed: %v", err)
+	}
+
+	knownAnswers := []*ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{192, 0, 2, 1},
+		},
+	}
+
+	packets, err := qb.Build(knownAnswers)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(packets) != 1 {
+		t.Fatalf("got %d packets, want 1", len(packets))
+	}
+
+	packet := packets[0]
+	ancount := binary.BigEndian.Uint16(packet[6:8])
+	if ancount != 1 {
+		t.Fatalf("ANCOUNT = %d, want 1", ancount)
+	}
+
+	parsed, err := ParseMessage(packet)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+	if len(parsed.Answers) != 1 {
+		t.Fatalf("got %d answers, want 1", len(parsed.Answers))
+	}
+
+	answer := parsed.Answers[0]
+	if answer.NAME != "host.local" {
+		t.Errorf("Answers[0].NAME = %q, want %q", answer.NAME, "host.local")
+	}
+	if answer.TTL != 120 {
+		t.Errorf("Answers[0].TTL = %d, want 120", answer.TTL)
+	}
+	if len(answer.RDATA) != 4 || string(answer.RDATA) != string([]byte{192, 0, 2, 1}) {
+		t.Errorf("Answers[0].RDATA = %v, want 4-byte address [192 0 2 1]", answer.RDATA)
+	}
+
+	flags := binary.BigEndian.Uint16(packet[2:4])
+	if flags&protocol.FlagTC != 0 {
+		t.Errorf("TC bit set on a single packet with no overflow")
+	}
+}
+
+// TestQueryBuilder_OverflowSetsTC validates that a Known-Answer list
+// exceeding one packet's capacity is split, with TC set on every packet but
+// the last, and the continuation packet reusing the same transaction ID.
+func TestQueryBuilder_OverflowSetsTC(t *testing.T) {
+	const mtu = 100 // deliberately small, to force a split with few records
+	qb, err := NewQueryBuilder("_http._tcp.local", uint16(protocol.RecordTypePTR), mtu)
+	if err != nil {
+		t.Fatalf("NewQueryBuilder failed: %v", err)
+	}
+
+	maxPerPacket := MaxKnownAnswersPerPacket(mtu)
+	if maxPerPacket == 0 {
+		maxPerPacket = 1
+	}
+
+	knownAnswers := make([]*ResourceRecord, 0, maxPerPacket*2+1)
+	for i := 0; i < maxPerPacket*2+1; i++ {
+		knownAnswers = append(knownAnswers, &ResourceRecord{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   4500,
+			Data:  []byte{0x03, 'f', 'o', 'o', 0x00},
+		})
+	}
+
+	packets, err := qb.Build(knownAnswers)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(packets) < 2 {
+		t.Fatalf("got %d packets, want at least 2 (overflow expected)", len(packets))
+	}
+
+	firstID := binary.BigEndian.Uint16(packets[0][0:2])
+
+	for i, packet := range packets {
+		id := binary.BigEndian.Uint16(packet[0:2])
+		if id != firstID {
+			t.Errorf("packet %d transaction ID = 0x%04X, want 0x%04X (same as first packet)", i, id, firstID)
+		}
+
+		flags := binary.BigEndian.Uint16(packet[2:4])
+		wantTC := i < len(packets)-1
+		if gotTC := flags&protocol.FlagTC != 0; gotTC != wantTC {
+			t.Errorf("packet %d TC bit = %v, want %v", i, gotTC, wantTC)
+		}
+	}
+}
+
+// TestNewQueryBuilder_UnsupportedRecordType validates that NewQueryBuilder
+// rejects an unsupported record type the same way BuildQuery does.
+func TestNewQueryBuilder_UnsupportedRecordType(t *testing.T) {
+	if _, err := NewQueryBuilder("test.local", 15 /* MX */, 1500); err == nil {
+		t.Fatal("expected NewQueryBuilder to reject an unsupported record type")
+	}
+}
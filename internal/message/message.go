@@ -74,6 +74,13 @@ type DNSHeader struct {
 	//
 	// M1: Additional section is ignored per FR-010.
 	ARCount uint16
+
+	// Truncated mirrors IsTruncated() (the TC bit, bit 9 of Flags) as a
+	// plain field so callers can branch on it without reaching for a
+	// method - see IsTruncated's doc for RFC 6762's query-specific meaning
+	// of this bit. ParseHeader sets it from Flags; it is not independently
+	// authoritative.
+	Truncated bool
 }
 
 // IsQuery returns true if this is a query message (QR bit = 0) per RFC 1035 §4.1.1.
@@ -90,6 +97,35 @@ func (h *DNSHeader) IsResponse() bool {
 	return (h.Flags & 0x8000) != 0
 }
 
+// IsTruncated returns true if the TC bit is set per RFC 1035 §4.1.1.
+//
+// RFC 6762 §18.5: "In query messages, if the TC bit is set, it indicates
+// that additional Known-Answer records... are being sent immediately
+// afterwards in a second packet."
+func (h *DNSHeader) IsTruncated() bool {
+	return (h.Flags & 0x0200) != 0
+}
+
+// IsAuthenticData returns true if the AD bit is set per RFC 4035 §3.2.3.
+//
+// A validating resolver sets AD to assert that every answer and authority
+// record in the message was verified by DNSSEC. Beacon itself never
+// validates signatures, so this is only meaningful when parsing a response
+// forwarded from, or proxied through, a validating resolver.
+func (h *DNSHeader) IsAuthenticData() bool {
+	return (h.Flags & 0x0020) != 0
+}
+
+// IsCheckingDisabled returns true if the CD bit is set per RFC 4035 §3.2.2.
+//
+// CD asks a validating resolver to return a record even if its DNSSEC
+// signature fails validation, rather than returning SERVFAIL. Beacon never
+// validates, so it neither sets nor inspects this bit on its own traffic,
+// but a caller relaying upstream DNS responses may need to read it.
+func (h *DNSHeader) IsCheckingDisabled() bool {
+	return (h.Flags & 0x0010) != 0
+}
+
 // GetRCODE extracts the response code from the Flags field per RFC 1035 §4.1.1.
 //
 // RCODE is bits 0-3 of the Flags field.
@@ -142,7 +178,7 @@ type Question struct {
 
 	// QTYPE is the query type (16 bits).
 	//
-	// M1 supports: A (1), PTR (12), SRV (33), TXT (16) per FR-002.
+	// Supports A (1), AAAA (28), PTR (12), SRV (33), TXT (16) per FR-002.
 	QTYPE uint16
 
 	// QCLASS is the query class (16 bits).
@@ -154,6 +190,37 @@ type Question struct {
 	QCLASS uint16
 }
 
+// WantsUnicastResponse returns true if the QU bit (top bit of QCLASS) is
+// set per RFC 6762 §5.4, meaning the querier has asked for a unicast reply
+// rather than one sent to the multicast group.
+func (q *Question) WantsUnicastResponse() bool {
+	return q.QCLASS&0x8000 != 0
+}
+
+// SetWantsUnicastResponse sets or clears the QU bit (top bit of QCLASS)
+// per RFC 6762 §5.4, leaving RRClass untouched.
+func (q *Question) SetWantsUnicastResponse(qu bool) {
+	if qu {
+		q.QCLASS |= 0x8000
+	} else {
+		q.QCLASS &^= 0x8000
+	}
+}
+
+// RRClass returns QCLASS with the QU bit (RFC 6762 §5.4) masked off, i.e.
+// the plain DNS class (IN = 1) a caller would otherwise get by hand-masking
+// QCLASS & 0x7FFF.
+func (q *Question) RRClass() uint16 {
+	return q.QCLASS &^ 0x8000
+}
+
+// SetRRClass sets QCLASS's low 15 bits to class, leaving the QU bit
+// (WantsUnicastResponse) untouched. class is truncated to 15 bits if it
+// has bit 15 set.
+func (q *Question) SetRRClass(class uint16) {
+	q.QCLASS = q.QCLASS&0x8000 | class&0x7FFF
+}
+
 // Answer represents a DNS answer/authority/additional section entry per RFC 1035 §4.1.3.
 //
 // The answer section contains resource records returned by the responder.
@@ -193,15 +260,14 @@ type Answer struct {
 
 	// TYPE is the resource record type (16 bits).
 	//
-	// M1 supports: A (1), PTR (12), SRV (33), TXT (16) per FR-002.
+	// Supports A (1), AAAA (28), PTR (12), SRV (33), TXT (16) per FR-002.
 	TYPE uint16
 
 	// CLASS is the resource record class (16 bits).
 	//
 	// RFC 1035: IN = 1 (Internet class)
-	// RFC 6762 §10.2: Cache-flush bit (bit 15) can be set in responses
-	//
-	// M1: CLASS = 0x0001 (IN) or 0x8001 (IN + cache-flush, M1 ignores cache-flush bit)
+	// RFC 6762 §10.2: Cache-flush bit (bit 15) can be set in responses -
+	// see CacheFlush/RRClass for a decoded view of this field.
 	CLASS uint16
 
 	// TTL is the time-to-live in seconds (32 bits).
@@ -227,6 +293,45 @@ type Answer struct {
 	RDATA []byte
 }
 
+// CacheFlush returns true if the cache-flush bit (top bit of CLASS) is set
+// per RFC 6762 §10.2, meaning this record is the complete, authoritative
+// set for its name/type and a cache holding prior records for that
+// name/type should discard them (subject to the RFC's 1-second
+// hold-off, which this accessor has no opinion on - it only decodes the
+// wire bit).
+func (a *Answer) CacheFlush() bool {
+	return a.CLASS&0x8000 != 0
+}
+
+// SetCacheFlush sets or clears the cache-flush bit (top bit of CLASS) per
+// RFC 6762 §10.2, leaving RRClass untouched.
+func (a *Answer) SetCacheFlush(flush bool) {
+	if flush {
+		a.CLASS |= 0x8000
+	} else {
+		a.CLASS &^= 0x8000
+	}
+}
+
+// RRClass returns CLASS with the cache-flush bit (RFC 6762 §10.2) masked
+// off, i.e. the plain DNS class (IN = 1) a caller would otherwise get by
+// hand-masking CLASS & 0x7FFF.
+//
+// responseMatchesQuestion (validate.go) and querier.go's answer conversion both go
+// through this rather than comparing CLASS directly, so a unique record's
+// cache-flush bit never makes it look like a class mismatch against a
+// plain ClassIN question/record.
+func (a *Answer) RRClass() uint16 {
+	return a.CLASS &^ 0x8000
+}
+
+// SetRRClass sets CLASS's low 15 bits to class, leaving the cache-flush bit
+// (CacheFlush) untouched. class is truncated to 15 bits if it has bit 15
+// set.
+func (a *Answer) SetRRClass(class uint16) {
+	a.CLASS = a.CLASS&0x8000 | class&0x7FFF
+}
+
 // DNSMessage represents a complete DNS message per RFC 1035 §4.1.
 //
 // The message consists of a header and up to four sections: Question, Answer,
@@ -256,5 +361,12 @@ type DNSMessage struct {
 	// Additionals is the additional section (variable length, ARCount entries).
 	//
 	// FR-010: M1 ignores Additional section (deferred to M2 for cache pre-population)
+	//
+	// Does not include an OPT pseudo-RR, if the message carried one - see OPT.
 	Additionals []Answer
+
+	// OPT is the decoded EDNS(0) pseudo-resource-record (RFC 6891 §6.1.2),
+	// if the Additional section contained one, or nil otherwise. A message
+	// has at most one OPT record.
+	OPT *OPTRecord
 }
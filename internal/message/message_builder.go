@@ -0,0 +1,174 @@
+package message
+
+import (
+	"encoding/binary"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// builderSection identifies which message section a MessageBuilder is
+// currently writing, in the fixed order RFC 1035 §4.1 lays the sections out
+// in: Header, Question, Answer, Authority, Additional.
+type builderSection int
+
+const (
+	sectionHeader builderSection = iota
+	sectionQuestions
+	sectionAnswers
+	sectionAuthorities
+	sectionAdditionals
+)
+
+// MessageBuilder assembles a DNS message section by section, applying RFC
+// 1035 §4.1.4 name compression across the whole message the same way
+// BuildQuery and BuildResponse do internally. It exists as a lower-level,
+// general-purpose counterpart to those two for callers - such as a future
+// responder mode - that need to mix section contents in ways BuildQuery/
+// BuildResponse don't anticipate (e.g. a response carrying both Answer and
+// Additional records).
+//
+// Sections must be started with StartQuestions/StartAnswers/
+// StartAuthorities/StartAdditionals in that order before adding their
+// entries; skipping a section (e.g. going straight from the header to
+// StartAnswers for a response with no questions) is fine, but going
+// backward is not. The first error from any method is sticky and returned
+// by every later call, including Finish.
+//
+// Answer takes a generic Answer/RDATA pair rather than per-type
+// AResource/SRVResource/... helpers: building a typed RDATA payload (an A's
+// IPv4 bytes, an SRV's priority/weight/port/target, ...) is internal/records'
+// job (BuildRecordSet, BuildServiceTypeEnumRecord, BuildKnownAnswers), not
+// this package's - MessageBuilder's only job is assembling whatever
+// RDATA-bearing records a caller already has into one compressed message.
+type MessageBuilder struct {
+	enc     *nameEncoder
+	section builderSection
+	err     error
+
+	qdcount, ancount, nscount, arcount uint16
+}
+
+// NewBuilder creates a MessageBuilder for a message with the given header.
+// header's QDCount/ANCount/NSCount/ARCount are ignored - Finish computes and
+// writes the real counts from what was actually added.
+func NewBuilder(header DNSHeader) *MessageBuilder {
+	buf := make([]byte, 12)
+	binary.BigEndian.PutUint16(buf[0:2], header.ID)
+	binary.BigEndian.PutUint16(buf[2:4], header.Flags)
+	return &MessageBuilder{enc: newNameEncoder(buf)}
+}
+
+// startSection advances to next, erroring (and sticking that error) if a
+// later section was already started.
+func (b *MessageBuilder) startSection(next builderSection) error {
+	if b.err != nil {
+		return b.err
+	}
+	if next <= b.section {
+		b.err = &errors.ValidationError{
+			Field:   "section",
+			Value:   next,
+			Message: "message sections must be started in RFC 1035 §4.1 order (Question, Answer, Authority, Additional) and not repeated",
+			Code:    errors.CodeBuilderState,
+		}
+		return b.err
+	}
+	b.section = next
+	return nil
+}
+
+// StartQuestions begins the Question section.
+func (b *MessageBuilder) StartQuestions() error { return b.startSection(sectionQuestions) }
+
+// StartAnswers begins the Answer section.
+func (b *MessageBuilder) StartAnswers() error { return b.startSection(sectionAnswers) }
+
+// StartAuthorities begins the Authority section.
+func (b *MessageBuilder) StartAuthorities() error { return b.startSection(sectionAuthorities) }
+
+// StartAdditionals begins the Additional section.
+func (b *MessageBuilder) StartAdditionals() error { return b.startSection(sectionAdditionals) }
+
+// Question appends q to the Question section, which must already have been
+// started with StartQuestions. q.QNAME is compressed against any suffix
+// already written, same as BuildQuery's own encoder.
+func (b *MessageBuilder) Question(q Question) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.section != sectionQuestions {
+		b.err = &errors.ValidationError{
+			Field:   "section",
+			Value:   b.section,
+			Message: "Question called without an active Question section; call StartQuestions first",
+			Code:    errors.CodeBuilderState,
+		}
+		return b.err
+	}
+	if err := b.enc.writeQuestion(q.QNAME, q.QTYPE, q.WantsUnicastResponse()); err != nil {
+		b.err = err
+		return err
+	}
+	b.qdcount++
+	return nil
+}
+
+// Answer appends a to whichever of the Answer, Authority, or Additional
+// sections was most recently started. a.RDATA is expected in the same shape
+// PackRDATA produces: PTR and SRV targets encoded (uncompressed) via
+// EncodeName, everything else as raw bytes; Answer re-compresses any PTR/SRV
+// target against suffixes already written elsewhere in the message, per RFC
+// 1035 §4.1.4.
+func (b *MessageBuilder) Answer(a Answer) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	switch b.section {
+	case sectionAnswers:
+		b.ancount++
+	case sectionAuthorities:
+		b.nscount++
+	case sectionAdditionals:
+		b.arcount++
+	default:
+		b.err = &errors.ValidationError{
+			Field:   "section",
+			Value:   b.section,
+			Message: "Answer called without an active Answer/Authority/Additional section; call StartAnswers, StartAuthorities, or StartAdditionals first",
+			Code:    errors.CodeBuilderState,
+		}
+		return b.err
+	}
+
+	rr := &ResourceRecord{
+		Name:       a.NAME,
+		Type:       protocol.RecordType(a.TYPE),
+		Class:      protocol.DNSClass(a.RRClass()),
+		TTL:        a.TTL,
+		Data:       a.RDATA,
+		CacheFlush: a.CacheFlush(),
+	}
+	if err := b.enc.writeResourceRecord(rr); err != nil {
+		b.err = err
+		return err
+	}
+	return nil
+}
+
+// Finish returns the fully assembled message, with the header's section
+// counts patched in to match what was actually added. It returns the first
+// error any earlier call produced, if any.
+func (b *MessageBuilder) Finish() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	buf := b.enc.Bytes()
+	binary.BigEndian.PutUint16(buf[4:6], b.qdcount)
+	binary.BigEndian.PutUint16(buf[6:8], b.ancount)
+	binary.BigEndian.PutUint16(buf[8:10], b.nscount)
+	binary.BigEndian.PutUint16(buf[10:12], b.arcount)
+	return buf, nil
+}
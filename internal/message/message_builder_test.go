@@ -0,0 +1,146 @@
+package message
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestPackRDATA_RoundTrip validates that ParseRDATA(PackRDATA(x)) == x for
+// every RData type both support.
+func TestPackRDATA_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType uint16
+		data       RData
+	}{
+		{"A", 1, AData{IP: netip.MustParseAddr("192.168.1.1")}},
+		{"AAAA", 28, AAAAData{IP: netip.MustParseAddr("fe80::1")}},
+		{"PTR", 12, PTRData{Name: "example.local"}},
+		{"TXT", 16, TXTData{Entries: []string{"version=1.0", "path=/api"}, KV: map[string]string{"version": "1.0", "path": "/api"}}},
+		{"SRV", 33, SRVData{Priority: 10, Weight: 20, Port: 8080, Target: "server.local"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdata, err := PackRDATA(tt.recordType, tt.data)
+			if err != nil {
+				t.Fatalf("PackRDATA failed: %v", err)
+			}
+
+			got, err := ParseRDATA(tt.recordType, rdata)
+			if err != nil {
+				t.Fatalf("ParseRDATA(PackRDATA(x)) failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("ParseRDATA(PackRDATA(x)) = %+v, want %+v", got, tt.data)
+			}
+		})
+	}
+}
+
+// TestPackRDATA_TypeMismatch validates that PackRDATA rejects an RData value
+// that doesn't match recordType.
+func TestPackRDATA_TypeMismatch(t *testing.T) {
+	_, err := PackRDATA(1, PTRData{Name: "example.local"})
+	if err == nil {
+		t.Fatal("PackRDATA(A, PTRData) succeeded, want error")
+	}
+}
+
+// TestMessageBuilder_QueryRoundTrip validates that a MessageBuilder-assembled
+// query parses back into the same question it was built from.
+func TestMessageBuilder_QueryRoundTrip(t *testing.T) {
+	b := NewBuilder(DNSHeader{ID: 42})
+	if err := b.StartQuestions(); err != nil {
+		t.Fatalf("StartQuestions failed: %v", err)
+	}
+	if err := b.Question(Question{QNAME: "printer.local", QTYPE: 1, QCLASS: 1}); err != nil {
+		t.Fatalf("Question failed: %v", err)
+	}
+
+	msg, err := b.Finish()
+	if err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(msg)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+	if len(parsed.Questions) != 1 || parsed.Questions[0].QNAME != "printer.local" {
+		t.Errorf("parsed.Questions = %+v, want one question for printer.local", parsed.Questions)
+	}
+}
+
+// TestMessageBuilder_ResponseWithCompression validates that every record of
+// a MessageBuilder-assembled response, whose names share suffixes (the PTR
+// target reappears as the SRV owner name), parses back correctly after
+// compression, including the PTR target inside RDATA. It does not check
+// the message size or where pointers were placed.
+func TestMessageBuilder_ResponseWithCompression(t *testing.T) {
+	b := NewBuilder(DNSHeader{Flags: protocol.FlagQR | protocol.FlagAA})
+	if err := b.StartAnswers(); err != nil {
+		t.Fatalf("StartAnswers failed: %v", err)
+	}
+
+	ptrRDATA, err := PackRDATA(12, PTRData{Name: "myservice._http._tcp.local"})
+	if err != nil {
+		t.Fatalf("PackRDATA(PTR) failed: %v", err)
+	}
+	if err := b.Answer(Answer{NAME: "_http._tcp.local", TYPE: 12, CLASS: 1, TTL: 120, RDATA: ptrRDATA}); err != nil {
+		t.Fatalf("Answer(PTR) failed: %v", err)
+	}
+
+	srvRDATA, err := PackRDATA(33, SRVData{Priority: 0, Weight: 0, Port: 8080, Target: "host.local"})
+	if err != nil {
+		t.Fatalf("PackRDATA(SRV) failed: %v", err)
+	}
+	if err := b.Answer(Answer{NAME: "myservice._http._tcp.local", TYPE: 33, CLASS: 0x8001, TTL: 120, RDATA: srvRDATA}); err != nil {
+		t.Fatalf("Answer(SRV) failed: %v", err)
+	}
+
+	msg, err := b.Finish()
+	if err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+
+	parsed, err := ParseMessage(msg)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+	if len(parsed.Answers) != 2 {
+		t.Fatalf("len(parsed.Answers) = %d, want 2", len(parsed.Answers))
+	}
+
+	ptr, ok := parsed.Answers[0].AsPTR()
+	if !ok || ptr.Name != "myservice._http._tcp.local" {
+		t.Errorf("Answers[0].AsPTR() = %+v, %v, want myservice._http._tcp.local, true", ptr, ok)
+	}
+
+	srv, ok := parsed.Answers[1].AsSRV()
+	if !ok || srv.Target != "host.local" || srv.Port != 8080 {
+		t.Errorf("Answers[1].AsSRV() = %+v, %v, want target host.local port 8080, true", srv, ok)
+	}
+	if parsed.Answers[1].CLASS&0x8000 == 0 {
+		t.Error("Answers[1].CLASS missing cache-flush bit")
+	}
+}
+
+// TestMessageBuilder_RejectsOutOfOrderSections validates that starting a
+// section out of RFC 1035 §4.1 order is a sticky error.
+func TestMessageBuilder_RejectsOutOfOrderSections(t *testing.T) {
+	b := NewBuilder(DNSHeader{})
+	if err := b.StartAnswers(); err != nil {
+		t.Fatalf("StartAnswers failed: %v", err)
+	}
+	if err := b.StartQuestions(); err == nil {
+		t.Error("StartQuestions after StartAnswers succeeded, want error")
+	}
+	if _, err := b.Finish(); err == nil {
+		t.Error("Finish after an out-of-order section succeeded, want error")
+	}
+}
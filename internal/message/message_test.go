@@ -3,6 +3,7 @@ package message
 import (
 	"encoding/binary"
 	"net"
+	"net/netip"
 	"testing"
 )
 
@@ -266,6 +267,35 @@ func TestQuestion_Initialization(t *testing.T) {
 	if question.QCLASS != 0x0001 {
 		t.Errorf("Question.QCLASS = 0x%04X, want 0x0001 (IN class)", question.QCLASS)
 	}
+	if question.WantsUnicastResponse() {
+		t.Error("Question.WantsUnicastResponse() = true, want false (QU bit not set)")
+	}
+	if question.RRClass() != 0x0001 {
+		t.Errorf("Question.RRClass() = 0x%04X, want 0x0001 (IN class)", question.RRClass())
+	}
+}
+
+// TestQuestion_QUBit validates that a question with the QU bit set (RFC
+// 6762 §5.4) reports WantsUnicastResponse while still exposing the plain
+// IN class via RRClass.
+func TestQuestion_QUBit(t *testing.T) {
+	question := Question{
+		QNAME:  "printer.local",
+		QTYPE:  1,
+		QCLASS: 0x8001, // IN + QU bit
+	}
+
+	if !question.WantsUnicastResponse() {
+		t.Error("Question.WantsUnicastResponse() = false, want true (QU bit set)")
+	}
+	if question.RRClass() != 0x0001 {
+		t.Errorf("Question.RRClass() = 0x%04X, want 0x0001 (IN class, QU bit masked off)", question.RRClass())
+	}
+
+	question.SetWantsUnicastResponse(false)
+	if question.QCLASS != 0x0001 {
+		t.Errorf("after SetWantsUnicastResponse(false), QCLASS = 0x%04X, want 0x0001", question.QCLASS)
+	}
 }
 
 // TestAnswer_Initialization validates that Answer fields can be initialized
@@ -302,6 +332,36 @@ func TestAnswer_Initialization(t *testing.T) {
 	if len(answer.RDATA) != 4 {
 		t.Errorf("len(Answer.RDATA) = %d, want 4", len(answer.RDATA))
 	}
+	if answer.CacheFlush() {
+		t.Error("Answer.CacheFlush() = true, want false (cache-flush bit not set)")
+	}
+	if answer.RRClass() != 0x0001 {
+		t.Errorf("Answer.RRClass() = 0x%04X, want 0x0001 (IN class)", answer.RRClass())
+	}
+}
+
+// TestAnswer_CacheFlushBit validates that an answer with the cache-flush
+// bit set (RFC 6762 §10.2) reports CacheFlush while still exposing the
+// plain IN class via RRClass.
+func TestAnswer_CacheFlushBit(t *testing.T) {
+	answer := Answer{
+		NAME:  "printer.local",
+		TYPE:  1,
+		CLASS: 0x8001, // IN + cache-flush bit
+		TTL:   120,
+	}
+
+	if !answer.CacheFlush() {
+		t.Error("Answer.CacheFlush() = false, want true (cache-flush bit set)")
+	}
+	if answer.RRClass() != 0x0001 {
+		t.Errorf("Answer.RRClass() = 0x%04X, want 0x0001 (IN class, cache-flush bit masked off)", answer.RRClass())
+	}
+
+	answer.SetCacheFlush(false)
+	if answer.CLASS != 0x0001 {
+		t.Errorf("after SetCacheFlush(false), CLASS = 0x%04X, want 0x0001", answer.CLASS)
+	}
 }
 
 // TestDNSMessage_Initialization validates that DNSMessage fields can be initialized
@@ -444,11 +504,12 @@ func TestParseRDATA_PTR(t *testing.T) {
 				return
 			}
 
-			gotStr, ok := got.(string)
+			gotPTR, ok := got.(PTRData)
 			if !ok {
-				t.Errorf("ParseRDATA(PTR) returned type %T, want string", got)
+				t.Errorf("ParseRDATA(PTR) returned type %T, want PTRData", got)
 				return
 			}
+			gotStr := gotPTR.Name
 
 			if gotStr != tt.wantValue {
 				t.Errorf("ParseRDATA(PTR) = %q, want %q", gotStr, tt.wantValue)
@@ -465,14 +526,6 @@ func TestParseRDATA_PTR(t *testing.T) {
 //
 // T075: Unit tests for SRV RDATA parsing (valid and malformed)
 func TestParseRDATA_SRV(t *testing.T) {
-	// Local SRVData struct to avoid import cycle with querier package
-	type SRVData struct {
-		Priority uint16
-		Weight   uint16
-		Port     uint16
-		Target   string
-	}
-
 	tests := []struct {
 		name      string
 		rdata     []byte
@@ -559,38 +612,22 @@ func TestParseRDATA_SRV(t *testing.T) {
 				return
 			}
 
-			// Validate that it returned a non-nil result
-			if got == nil {
-				t.Errorf("ParseRDATA(SRV) returned nil")
+			v, ok := got.(SRVData)
+			if !ok {
+				t.Errorf("ParseRDATA(SRV) returned type %T, want SRVData", got)
 				return
 			}
-
-			// Use type switch to validate the structure
-			// ParseRDATA returns querier.SRVData but we can't import querier (import cycle)
-			// So we just validate it returned the right type name
-			switch v := got.(type) {
-			case struct {
-				Priority uint16
-				Weight   uint16
-				Port     uint16
-				Target   string
-			}:
-				if v.Priority != tt.wantValue.Priority {
-					t.Errorf("ParseRDATA(SRV).Priority = %d, want %d", v.Priority, tt.wantValue.Priority)
-				}
-				if v.Weight != tt.wantValue.Weight {
-					t.Errorf("ParseRDATA(SRV).Weight = %d, want %d", v.Weight, tt.wantValue.Weight)
-				}
-				if v.Port != tt.wantValue.Port {
-					t.Errorf("ParseRDATA(SRV).Port = %d, want %d", v.Port, tt.wantValue.Port)
-				}
-				if v.Target != tt.wantValue.Target {
-					t.Errorf("ParseRDATA(SRV).Target = %q, want %q", v.Target, tt.wantValue.Target)
-				}
-			default:
-				// ParseRDATA returns querier.SRVData which has same structure
-				// Just verify it's not nil - contract tests validate fields
-				t.Logf("ParseRDATA(SRV) returned type %T (validated in contract tests)", v)
+			if v.Priority != tt.wantValue.Priority {
+				t.Errorf("ParseRDATA(SRV).Priority = %d, want %d", v.Priority, tt.wantValue.Priority)
+			}
+			if v.Weight != tt.wantValue.Weight {
+				t.Errorf("ParseRDATA(SRV).Weight = %d, want %d", v.Weight, tt.wantValue.Weight)
+			}
+			if v.Port != tt.wantValue.Port {
+				t.Errorf("ParseRDATA(SRV).Port = %d, want %d", v.Port, tt.wantValue.Port)
+			}
+			if v.Target != tt.wantValue.Target {
+				t.Errorf("ParseRDATA(SRV).Target = %q, want %q", v.Target, tt.wantValue.Target)
 			}
 		})
 	}
@@ -676,11 +713,12 @@ func TestParseRDATA_TXT(t *testing.T) {
 				return
 			}
 
-			gotTXT, ok := got.([]string)
+			gotTXTData, ok := got.(TXTData)
 			if !ok {
-				t.Errorf("ParseRDATA(TXT) returned type %T, want []string", got)
+				t.Errorf("ParseRDATA(TXT) returned type %T, want TXTData", got)
 				return
 			}
+			gotTXT := gotTXTData.Entries
 
 			if len(gotTXT) != len(tt.wantValue) {
 				t.Errorf("ParseRDATA(TXT) returned %d strings, want %d", len(gotTXT), len(tt.wantValue))
@@ -704,19 +742,19 @@ func TestParseRDATA_A(t *testing.T) {
 	tests := []struct {
 		name      string
 		rdata     []byte
-		wantValue net.IP
+		wantValue netip.Addr
 		wantError bool
 	}{
 		{
 			name:      "Valid A record - 192.168.1.1",
 			rdata:     []byte{192, 168, 1, 1},
-			wantValue: net.IPv4(192, 168, 1, 1),
+			wantValue: netip.MustParseAddr("192.168.1.1"),
 			wantError: false,
 		},
 		{
 			name:      "Valid A record - 10.0.0.1",
 			rdata:     []byte{10, 0, 0, 1},
-			wantValue: net.IPv4(10, 0, 0, 1),
+			wantValue: netip.MustParseAddr("10.0.0.1"),
 			wantError: false,
 		},
 		{
@@ -751,19 +789,82 @@ func TestParseRDATA_A(t *testing.T) {
 				return
 			}
 
-			gotIP, ok := got.(net.IP)
+			gotAData, ok := got.(AData)
 			if !ok {
-				t.Errorf("ParseRDATA(A) returned type %T, want net.IP", got)
+				t.Errorf("ParseRDATA(A) returned type %T, want AData", got)
 				return
 			}
+			gotIP := gotAData.IP
 
-			if !gotIP.Equal(tt.wantValue) {
+			if gotIP != tt.wantValue {
 				t.Errorf("ParseRDATA(A) = %v, want %v", gotIP, tt.wantValue)
 			}
 		})
 	}
 }
 
+// TestParseRDATA_AAAA validates AAAA (IPv6 address) RDATA parsing per RFC
+// 3596 §2.2.
+func TestParseRDATA_AAAA(t *testing.T) {
+	tests := []struct {
+		name      string
+		rdata     []byte
+		wantValue netip.Addr
+		wantError bool
+	}{
+		{
+			name:      "Valid AAAA record - ::1",
+			rdata:     net.ParseIP("::1").To16(),
+			wantValue: netip.MustParseAddr("::1"),
+			wantError: false,
+		},
+		{
+			name:      "Valid AAAA record - fe80::1",
+			rdata:     net.ParseIP("fe80::1").To16(),
+			wantValue: netip.MustParseAddr("fe80::1"),
+			wantError: false,
+		},
+		{
+			name:      "Truncated AAAA record - 15 bytes",
+			rdata:     make([]byte, 15),
+			wantError: true,
+		},
+		{
+			name:      "Oversized AAAA record - 17 bytes",
+			rdata:     make([]byte, 17),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRDATA(28, tt.rdata) // TYPE 28 = AAAA
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("ParseRDATA(AAAA, %v) expected error, got nil", tt.rdata)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ParseRDATA(AAAA, %v) unexpected error: %v", tt.rdata, err)
+				return
+			}
+
+			gotAAAAData, ok := got.(AAAAData)
+			if !ok {
+				t.Errorf("ParseRDATA(AAAA) returned type %T, want AAAAData", got)
+				return
+			}
+			gotIP := gotAAAAData.IP
+
+			if gotIP != tt.wantValue {
+				t.Errorf("ParseRDATA(AAAA) = %v, want %v", gotIP, tt.wantValue)
+			}
+		})
+	}
+}
+
 // TestParseRDATA_UnsupportedType validates that ParseRDATA returns an error
 // for unsupported record types.
 func TestParseRDATA_UnsupportedType(t *testing.T) {
@@ -773,18 +874,13 @@ func TestParseRDATA_UnsupportedType(t *testing.T) {
 		rdata      []byte
 	}{
 		{
-			name:       "AAAA record (type 28) - not supported in M1",
-			recordType: 28,
-			rdata:      make([]byte, 16), // 16-byte IPv6 address
-		},
-		{
-			name:       "MX record (type 15) - not supported in M1",
+			name:       "MX record (type 15) - not supported",
 			recordType: 15,
 			rdata:      []byte{0, 10, 4, 'm', 'a', 'i', 'l', 0},
 		},
 		{
-			name:       "CNAME record (type 5) - not supported in M1",
-			recordType: 5,
+			name:       "SPF record (type 99) - not supported",
+			recordType: 99,
 			rdata:      []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0},
 		},
 	}
@@ -798,3 +894,49 @@ func TestParseRDATA_UnsupportedType(t *testing.T) {
 		})
 	}
 }
+
+// TestDNSHeader_IsAuthenticData validates that DNSHeader.IsAuthenticData()
+// correctly extracts the AD bit per RFC 4035 §3.2.3.
+func TestDNSHeader_IsAuthenticData(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags uint16
+		want  bool
+	}{
+		{name: "AD=0", flags: 0x8000, want: false},
+		{name: "AD=1", flags: 0x8020, want: true},
+		{name: "AD=1 with other flags set", flags: 0x8125, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &DNSHeader{Flags: tt.flags}
+			if got := header.IsAuthenticData(); got != tt.want {
+				t.Errorf("DNSHeader.IsAuthenticData() with flags=0x%04X = %v, want %v per RFC 4035 §3.2.3", tt.flags, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDNSHeader_IsCheckingDisabled validates that DNSHeader.IsCheckingDisabled()
+// correctly extracts the CD bit per RFC 4035 §3.2.2.
+func TestDNSHeader_IsCheckingDisabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags uint16
+		want  bool
+	}{
+		{name: "CD=0", flags: 0x8000, want: false},
+		{name: "CD=1", flags: 0x8010, want: true},
+		{name: "CD=1 with other flags set", flags: 0x8135, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &DNSHeader{Flags: tt.flags}
+			if got := header.IsCheckingDisabled(); got != tt.want {
+				t.Errorf("DNSHeader.IsCheckingDisabled() with flags=0x%04X = %v, want %v per RFC 4035 §3.2.2", tt.flags, got, tt.want)
+			}
+		})
+	}
+}
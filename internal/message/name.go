@@ -19,11 +19,32 @@ import (
 // to a prior occurrence of the same name. A pointer is indicated by the two high-order
 // bits being set (0xC0), followed by a 14-bit offset.
 //
-// This function detects compression loops by limiting the number of pointer jumps
-// to MaxCompressionPointers (256).
+// Compression pointers are required to be forward-only (a pointer must
+// target strictly before its own offset), and the total number of pointer
+// dereferences is bounded by protocol.MaxCompressionPointers. Neither rule
+// alone bounds parsing cost: the forward-only rule permits a pointer to
+// revisit an offset an earlier pointer in the same chain already passed
+// through (a loop that still decreases "current position" at each jump),
+// and the jump count says nothing about how much label data one jump can
+// land on. A visited-set of positions rejects the former, and
+// protocol.MaxDomainNameWireOctets - a cap on total label bytes read across
+// all jumps - rejects the latter, together bounding parsing cost even
+// against an adversarial pointer graph.
 //
 // FR-012: System MUST decompress DNS names per RFC 1035 §4.1.4 (message compression)
 //
+// ParseName is this package's one name-decompression entry point (what a
+// DecodeName helper would otherwise duplicate): Answer.NAME, every
+// PTR/NS/CNAME/SOA/SRV target decompressRDATANames expands, and SkipName's
+// allocation-free jump all go through NameParser.NextLabel, so pointer-loop and
+// wire-octet-budget hardening lives in exactly one place.
+//
+// Reserved label-length top bits (01, 10) are rejected the same path as an
+// over-length label: NextLabel only recognizes 00 (ordinary label, ≤63
+// bytes) and 11 (compression pointer) as valid top bits, so a length byte
+// with either reserved pattern is necessarily > protocol.MaxLabelLength and
+// fails with CodeLabelTooLong.
+//
 // Parameters:
 //   - msg: The complete DNS message buffer (needed for following compression pointers)
 //   - offset: The starting offset of the name in the buffer
@@ -33,125 +54,223 @@ import (
 //   - newOffset: The offset immediately after the name (for parsing subsequent fields)
 //   - error: WireFormatError if the name is malformed
 func ParseName(msg []byte, offset int) (name string, newOffset int, err error) {
-	if offset < 0 || offset >= len(msg) {
+	np, newOffset, err := NewNameParser(msg, offset)
+	if err != nil {
+		return "", offset, err
+	}
+
+	var labels []string
+	for {
+		label, more := np.NextLabel()
+		if label != nil {
+			labels = append(labels, string(label))
+		}
+		if !more {
+			if np.err != nil {
+				return "", offset, np.err
+			}
+			break
+		}
+	}
+
+	name = strings.Join(labels, ".")
+
+	// Validate total name length per RFC 1035 §3.1
+	// Note: Wire format length includes length bytes, but MaxNameLength applies to the string representation
+	if len(name) > protocol.MaxNameLength {
 		return "", offset, &errors.WireFormatError{
+			Operation: "parse name",
+			Offset:    offset,
+			Message:   fmt.Sprintf("name length %d exceeds maximum %d bytes per RFC 1035 §3.1", len(name), protocol.MaxNameLength),
+			Code:      errors.CodeNameTooLong,
+		}
+	}
+
+	return name, np.newOffset, nil
+}
+
+// NameParser walks a DNS name's labels in place, following RFC 1035 §4.1.4
+// compression pointers without allocating a string per label. It is the
+// allocation-free counterpart to ParseName, for hot paths (e.g. SkipName
+// jumping over a QNAME just to reach QTYPE/QCLASS) that don't need the
+// decompressed name as a Go string.
+type NameParser struct {
+	msg        []byte
+	pos        int
+	jumps      int
+	jumped     bool
+	newOffset  int
+	done       bool
+	err        error
+	labelBytes int
+	visited    map[int]struct{}
+}
+
+// NewNameParser begins parsing a name at offset in msg, returning the
+// positioned parser and offset itself, unchanged. The wire offset
+// immediately following the name is known only once NextLabel has reached
+// the terminating label. Call NextLabel to walk the name's labels.
+func NewNameParser(msg []byte, offset int) (*NameParser, int, error) {
+	if offset < 0 || offset >= len(msg) {
+		return nil, offset, &errors.WireFormatError{
 			Operation: "parse name",
 			Offset:    offset,
 			Message:   "offset out of bounds",
+			Code:      errors.CodeTruncated,
 		}
 	}
+	return &NameParser{msg: msg, pos: offset}, offset, nil
+}
 
-	var labels []string
-	jumps := 0
-	pos := offset
-	jumped := false
+// NextLabel returns the next label's raw bytes (a slice into msg, valid only
+// until msg is reused) and true if more labels follow. At the terminating
+// zero-length label it returns (nil, false); np.newOffset is then the wire
+// offset immediately following the name, matching ParseName's newOffset. A
+// malformed name also yields (nil, false); the error is kept in the
+// parser's unexported err field, so callers outside this package that need
+// the error should use ParseName or SkipName instead of NameParser directly.
+func (np *NameParser) NextLabel() ([]byte, bool) {
+	if np.done {
+		return nil, false
+	}
 
 	for {
-		// Check bounds
-		if pos >= len(msg) {
-			return "", offset, &errors.WireFormatError{
-				Operation: "parse name",
-				Offset:    pos,
-				Message:   "unexpected end of message while parsing name",
-			}
+		if np.pos >= len(np.msg) {
+			np.fail(np.pos, errors.CodeTruncated, "unexpected end of message while parsing name")
+			return nil, false
 		}
 
-		length := msg[pos]
+		if np.visited == nil {
+			np.visited = make(map[int]struct{})
+		}
+		if _, seen := np.visited[np.pos]; seen {
+			np.fail(np.pos, errors.CodeBadCompressionPointer, fmt.Sprintf("compression pointer loop detected: offset %d already visited", np.pos))
+			return nil, false
+		}
+		np.visited[np.pos] = struct{}{}
+
+		length := np.msg[np.pos]
 
-		// Check for compression pointer per RFC 1035 §4.1.4
 		if (length & protocol.CompressionMask) == protocol.CompressionMask {
-			// Compression pointer (high 2 bits = 11)
-			if pos+1 >= len(msg) {
-				return "", offset, &errors.WireFormatError{
-					Operation: "parse name",
-					Offset:    pos,
-					Message:   "truncated compression pointer",
-				}
+			if np.pos+1 >= len(np.msg) {
+				np.fail(np.pos, errors.CodeBadCompressionPointer, "pointer past packet end")
+				return nil, false
 			}
 
-			// Extract 14-bit offset: combine two bytes and mask out high 2 bits
-			pointerOffset := int(msg[pos]&0x3F)<<8 | int(msg[pos+1])
-
-			// Validate pointer doesn't point forward (RFC 1035 §4.1.4: pointers point backwards)
-			if pointerOffset >= pos {
-				return "", offset, &errors.WireFormatError{
-					Operation: "parse name",
-					Offset:    pos,
-					Message:   fmt.Sprintf("invalid compression pointer: points to offset %d (current position %d)", pointerOffset, pos),
-				}
+			pointerOffset := int(np.msg[np.pos]&0x3F)<<8 | int(np.msg[np.pos+1])
+			if pointerOffset >= np.pos {
+				np.fail(np.pos, errors.CodeBadCompressionPointer, fmt.Sprintf("forward or self pointer rejected: points to offset %d (current position %d)", pointerOffset, np.pos))
+				return nil, false
 			}
 
-			// Update newOffset only on first jump (subsequent jumps don't affect wire position)
-			if !jumped {
-				newOffset = pos + 2
-				jumped = true
+			if !np.jumped {
+				np.newOffset = np.pos + 2
+				np.jumped = true
 			}
 
-			// Follow the pointer
-			pos = pointerOffset
+			np.pos = pointerOffset
 
-			// Detect compression loops per FR-012
-			jumps++
-			if jumps > protocol.MaxCompressionPointers {
-				return "", offset, &errors.WireFormatError{
-					Operation: "parse name",
-					Offset:    pos,
-					Message:   fmt.Sprintf("too many compression jumps (possible loop, exceeded %d jumps)", protocol.MaxCompressionPointers),
-				}
+			np.jumps++
+			if np.jumps > protocol.MaxCompressionPointers {
+				np.fail(np.pos, errors.CodeBadCompressionPointer, fmt.Sprintf("compression pointer count exceeded: more than %d jumps", protocol.MaxCompressionPointers))
+				return nil, false
 			}
 
 			continue
 		}
 
-		// Check for terminator (zero-length label)
 		if length == 0 {
-			// End of name
-			if !jumped {
-				newOffset = pos + 1
+			if !np.jumped {
+				np.newOffset = np.pos + 1
 			}
-			break
+			np.done = true
+			return nil, false
 		}
 
-		// Validate label length per RFC 1035 §3.1
 		if length > protocol.MaxLabelLength {
-			return "", offset, &errors.WireFormatError{
-				Operation: "parse name",
-				Offset:    pos,
-				Message:   fmt.Sprintf("label length %d exceeds maximum %d bytes per RFC 1035 §3.1", length, protocol.MaxLabelLength),
-			}
+			np.fail(np.pos, errors.CodeLabelTooLong, fmt.Sprintf("label length %d exceeds maximum %d bytes per RFC 1035 §3.1", length, protocol.MaxLabelLength))
+			return nil, false
 		}
 
-		// Check if we have enough bytes for this label
-		if pos+1+int(length) > len(msg) {
-			return "", offset, &errors.WireFormatError{
-				Operation: "parse name",
-				Offset:    pos,
-				Message:   fmt.Sprintf("truncated label: expected %d bytes, only %d available", length, len(msg)-pos-1),
-			}
+		if np.pos+1+int(length) > len(np.msg) {
+			np.fail(np.pos, errors.CodeTruncated, fmt.Sprintf("truncated label: expected %d bytes, only %d available", length, len(np.msg)-np.pos-1))
+			return nil, false
+		}
+
+		np.labelBytes += 1 + int(length)
+		if np.jumps > 0 && np.labelBytes > protocol.MaxDomainNameWireOctets {
+			np.fail(np.pos, errors.CodeNameTooLong, fmt.Sprintf("name exceeds wire octet budget: more than %d label bytes visited across pointer chases", protocol.MaxDomainNameWireOctets))
+			return nil, false
 		}
 
-		// Extract label
-		label := string(msg[pos+1 : pos+1+int(length)])
-		labels = append(labels, label)
+		label := np.msg[np.pos+1 : np.pos+1+int(length)]
+		np.pos += 1 + int(length)
+		return label, true
+	}
+}
 
-		// Move to next label
-		pos += 1 + int(length)
+// fail records op's WireFormatError and marks the parser done so a later
+// NextLabel call doesn't keep walking a malformed name.
+func (np *NameParser) fail(pos int, code errors.Code, message string) {
+	np.err = &errors.WireFormatError{
+		Operation: "parse name",
+		Offset:    pos,
+		Message:   message,
+		Code:      code,
 	}
+	np.done = true
+}
 
-	// Join labels with dots to form the complete name
-	name = strings.Join(labels, ".")
+// Canonical appends the name's labels, lowercased and dot-joined, to buf and
+// returns the resulting slice - the representation ParseName returns as a
+// string, lowercased, and written into caller-supplied scratch space instead
+// of allocating. Canonical consumes the parser: call it in place of a NextLabel
+// loop, not after one.
+func (np *NameParser) Canonical(buf []byte) []byte {
+	first := true
+	for {
+		label, more := np.NextLabel()
+		if label != nil {
+			if !first {
+				buf = append(buf, '.')
+			}
+			first = false
+			for _, b := range label {
+				if b >= 'A' && b <= 'Z' {
+					b += 'a' - 'A'
+				}
+				buf = append(buf, b)
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return buf
+}
 
-	// Validate total name length per RFC 1035 §3.1
-	// Note: Wire format length includes length bytes, but MaxNameLength applies to the string representation
-	if len(name) > protocol.MaxNameLength {
-		return "", offset, &errors.WireFormatError{
-			Operation: "parse name",
-			Offset:    offset,
-			Message:   fmt.Sprintf("name length %d exceeds maximum %d bytes per RFC 1035 §3.1", len(name), protocol.MaxNameLength),
+// SkipName advances past a DNS name at offset without building any
+// representation of it, returning only the wire offset immediately
+// following the name (the same newOffset ParseName returns). This is the
+// allocation-free path for callers that only need to jump over a name - for
+// example skipping a QNAME to reach QTYPE/QCLASS.
+func SkipName(msg []byte, offset int) (int, error) {
+	np, _, err := NewNameParser(msg, offset)
+	if err != nil {
+		return offset, err
+	}
+
+	for {
+		_, more := np.NextLabel()
+		if !more {
+			break
 		}
 	}
+	if np.err != nil {
+		return offset, np.err
+	}
 
-	return name, newOffset, nil
+	return np.newOffset, nil
 }
 
 // EncodeName encodes a DNS name into wire format per RFC 1035 §3.1.
@@ -162,8 +281,10 @@ func ParseName(msg []byte, offset int) (name string, newOffset int, err error) {
 // RFC 1035 §3.1: Labels are sequences of ASCII characters, length-prefixed.
 // Example: "printer.local" → [7]printer[5]local[0]
 //
-// M1 does NOT implement compression (compression is SHOULD, not MUST per RFC 6762 §18.14).
-// Compression is deferred to future milestones for simplicity.
+// EncodeName itself never compresses (compression is SHOULD, not MUST per
+// RFC 6762 §18.14): it has no view of a message's other names to compress
+// against. Compression is applied at the message level instead, by
+// Compressor/EncodeMessage, which reuse this function's label validation.
 //
 // FR-003: System MUST validate queried names follow DNS naming rules (labels ≤63 bytes, total name ≤255 bytes)
 //
@@ -195,6 +316,7 @@ func EncodeServiceInstanceName(instanceName, serviceType string) ([]byte, error)
 			Field:   "instanceName",
 			Value:   instanceName,
 			Message: "instance name cannot be empty",
+			Code:    errors.CodeInvalidValue,
 		}
 	}
 
@@ -203,6 +325,7 @@ func EncodeServiceInstanceName(instanceName, serviceType string) ([]byte, error)
 			Field:   "instanceName",
 			Value:   instanceName,
 			Message: fmt.Sprintf("instance name exceeds maximum label length %d bytes", protocol.MaxLabelLength),
+			Code:    errors.CodeLabelTooLong,
 		}
 	}
 
@@ -229,6 +352,23 @@ func EncodeServiceInstanceName(instanceName, serviceType string) ([]byte, error)
 	return encoded, nil
 }
 
+// EncodeOwnerName encodes a resource record owner name, auto-detecting DNS-SD
+// service instance names (RFC 6763 §4.3) so their instance label may contain
+// spaces or other UTF-8 bytes that EncodeName would otherwise reject.
+//
+// A name is treated as a service instance name when it contains "._", the
+// marker between the free-form instance label and the following service
+// type labels (e.g. "My Printer._http._tcp.local").
+func EncodeOwnerName(name string) ([]byte, error) {
+	if strings.Contains(name, "._") {
+		parts := strings.SplitN(name, "._", 2)
+		if len(parts) == 2 {
+			return EncodeServiceInstanceName(parts[0], "_"+parts[1])
+		}
+	}
+	return EncodeName(name)
+}
+
 func EncodeName(name string) ([]byte, error) {
 	// Handle empty name (root ".")
 	if name == "" || name == "." {
@@ -252,6 +392,7 @@ func EncodeName(name string) ([]byte, error) {
 				Field:   "name",
 				Value:   name,
 				Message: "empty label (consecutive dots)",
+				Code:    errors.CodeInvalidName,
 			}
 		}
 
@@ -260,6 +401,7 @@ func EncodeName(name string) ([]byte, error) {
 				Field:   "name",
 				Value:   name,
 				Message: fmt.Sprintf("label %q exceeds maximum length %d bytes per RFC 1035 §3.1", label, protocol.MaxLabelLength),
+				Code:    errors.CodeLabelTooLong,
 			}
 		}
 
@@ -277,6 +419,7 @@ func EncodeName(name string) ([]byte, error) {
 					Field:   "name",
 					Value:   name,
 					Message: fmt.Sprintf("invalid character %q in label %q (position %d)", ch, label, i),
+					Code:    errors.CodeInvalidName,
 				}
 			}
 
@@ -286,6 +429,7 @@ func EncodeName(name string) ([]byte, error) {
 					Field:   "name",
 					Value:   name,
 					Message: fmt.Sprintf("hyphen cannot be first or last character in label %q", label),
+					Code:    errors.CodeInvalidName,
 				}
 			}
 		}
@@ -305,8 +449,54 @@ func EncodeName(name string) ([]byte, error) {
 			Field:   "name",
 			Value:   name,
 			Message: fmt.Sprintf("encoded name length %d exceeds maximum %d bytes per RFC 1035 §3.1", len(encoded), protocol.MaxNameLength),
+			Code:    errors.CodeNameTooLong,
 		}
 	}
 
 	return encoded, nil
 }
+
+// EncodeNameIDNA encodes name like EncodeName, but first runs it through
+// protocol.EncodeName's IDNA2008/UTS-46 processing, which Punycode-encodes
+// any non-ASCII label (and lowercases ASCII ones, same as protocol.EncodeName
+// itself does for the hostnames responder.go advertises). This lets beacon
+// query internationalized service or host names (e.g. "café.local") against
+// unicast DNS resolvers that expect ASCII-only wire names, while EncodeName
+// itself keeps rejecting non-ASCII input so that existing callers (mDNS
+// names, which are UTF-8 on the wire per RFC 6762 §16) are unaffected.
+//
+// Reusing protocol.EncodeName rather than a second IDNA profile keeps a
+// single encoding (and case-folding) behavior across the advertise and
+// query sides; see protocol.EncodeName's doc comment for why ASCII labels
+// are lowercased rather than passed through untouched.
+func EncodeNameIDNA(name string) ([]byte, error) {
+	ascii, err := protocol.EncodeName(name)
+	if err != nil {
+		return nil, &errors.ValidationError{
+			Field:   "name",
+			Value:   name,
+			Message: fmt.Sprintf("IDNA encoding failed: %v", err),
+			Code:    errors.CodeInvalidName,
+		}
+	}
+	return EncodeName(ascii)
+}
+
+// DecodeNameUnicode converts an ASCII wire-format name (as returned by
+// ParseName), including any Punycode "xn--" labels, to its Unicode
+// presentation form per IDNA2008 (RFC 5891), via protocol.DecodeName. A
+// label that is not valid Punycode is reported as a ValidationError;
+// callers that only need best-effort display should fall back to the
+// original ASCII name on error.
+func DecodeNameUnicode(name string) (string, error) {
+	unicodeName, err := protocol.DecodeName(name)
+	if err != nil {
+		return "", &errors.ValidationError{
+			Field:   "name",
+			Value:   name,
+			Message: fmt.Sprintf("IDNA decoding failed: %v", err),
+			Code:    errors.CodeInvalidName,
+		}
+	}
+	return unicodeName, nil
+}
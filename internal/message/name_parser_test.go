@@ -0,0 +1,157 @@
+package message
+
+import (
+	"strings"
+	"testing"
+)
+
+// buildNameMessage wires a 12-byte stand-in header followed by name's wire
+// encoding, so NameParser/ParseName can be exercised at a realistic offset.
+func buildNameMessage(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := EncodeName(name)
+	if err != nil {
+		t.Fatalf("EncodeName(%q) failed: %v", name, err)
+	}
+	return append(make([]byte, 12), encoded...)
+}
+
+// TestNameParser_MatchesParseName validates that walking NameParser.NextLabel
+// produces the same labels and final offset as ParseName for a variety of
+// names, including compressed ones.
+func TestNameParser_MatchesParseName(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "simple", data: "test.local"},
+		{name: "service", data: "_http._tcp.local"},
+		{name: "deep", data: "a.b.c.d.local"},
+		{name: "root", data: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := buildNameMessage(t, tt.data)
+
+			wantName, wantOffset, err := ParseName(msg, 12)
+			if err != nil {
+				t.Fatalf("ParseName failed: %v", err)
+			}
+
+			np, _, err := NewNameParser(msg, 12)
+			if err != nil {
+				t.Fatalf("NewNameParser failed: %v", err)
+			}
+			var labels []string
+			for {
+				label, more := np.NextLabel()
+				if label != nil {
+					labels = append(labels, string(label))
+				}
+				if !more {
+					break
+				}
+			}
+			if np.err != nil {
+				t.Fatalf("NameParser failed: %v", np.err)
+			}
+
+			gotName := strings.Join(labels, ".")
+			if gotName != wantName {
+				t.Errorf("NameParser labels joined = %q, want %q", gotName, wantName)
+			}
+			if np.newOffset != wantOffset {
+				t.Errorf("NameParser newOffset = %d, want %d", np.newOffset, wantOffset)
+			}
+		})
+	}
+}
+
+// TestNameParser_Canonical validates that Canonical produces the same
+// lowercased, dot-joined representation ParseName returns, written into a
+// caller-supplied buffer.
+func TestNameParser_Canonical(t *testing.T) {
+	msg := buildNameMessage(t, "Test.LOCAL")
+
+	wantName, _, err := ParseName(msg, 12)
+	if err != nil {
+		t.Fatalf("ParseName failed: %v", err)
+	}
+
+	np, _, err := NewNameParser(msg, 12)
+	if err != nil {
+		t.Fatalf("NewNameParser failed: %v", err)
+	}
+	got := np.Canonical(make([]byte, 0, 64))
+
+	if string(got) != strings.ToLower(wantName) {
+		t.Errorf("Canonical() = %q, want %q", got, strings.ToLower(wantName))
+	}
+}
+
+// TestSkipName_MatchesParseNameOffset validates that SkipName returns the
+// same newOffset as ParseName without building the name itself.
+func TestSkipName_MatchesParseNameOffset(t *testing.T) {
+	msg := buildNameMessage(t, "_http._tcp.local")
+
+	_, wantOffset, err := ParseName(msg, 12)
+	if err != nil {
+		t.Fatalf("ParseName failed: %v", err)
+	}
+
+	gotOffset, err := SkipName(msg, 12)
+	if err != nil {
+		t.Fatalf("SkipName failed: %v", err)
+	}
+	if gotOffset != wantOffset {
+		t.Errorf("SkipName offset = %d, want %d", gotOffset, wantOffset)
+	}
+}
+
+// TestSkipName_PropagatesError validates that SkipName surfaces the same
+// error ParseName would for a malformed name.
+func TestSkipName_PropagatesError(t *testing.T) {
+	msg := append(make([]byte, 12), 64) // label length 64 exceeds MaxLabelLength (63)
+
+	_, _, wantErr := ParseName(msg, 12)
+	_, gotErr := SkipName(msg, 12)
+
+	if wantErr == nil || gotErr == nil {
+		t.Fatalf("expected both ParseName and SkipName to fail, got ParseName err=%v, SkipName err=%v", wantErr, gotErr)
+	}
+}
+
+// BenchmarkParseName measures the allocating ParseName path.
+func BenchmarkParseName(b *testing.B) {
+	msg := append(make([]byte, 12), mustEncodeName(b, "myservice._http._tcp.local")...)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ParseName(msg, 12); err != nil {
+			b.Fatalf("ParseName failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkSkipName measures the allocation-free SkipName path over the same
+// input, for comparison against BenchmarkParseName.
+func BenchmarkSkipName(b *testing.B) {
+	msg := append(make([]byte, 12), mustEncodeName(b, "myservice._http._tcp.local")...)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := SkipName(msg, 12); err != nil {
+			b.Fatalf("SkipName failed: %v", err)
+		}
+	}
+}
+
+func mustEncodeName(b *testing.B, name string) []byte {
+	b.Helper()
+	encoded, err := EncodeName(name)
+	if err != nil {
+		b.Fatalf("EncodeName(%q) failed: %v", name, err)
+	}
+	return encoded
+}
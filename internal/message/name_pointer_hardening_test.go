@@ -0,0 +1,179 @@
+package message
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// buildPointerChain constructs a message holding n label segments chained by
+// backward compression pointers: segment 0 is a plain terminated label,
+// and each segment i>0 is labelLen bytes of label data followed by a 2-byte
+// pointer back to segment i-1's start. Parsing from the last segment's
+// offset therefore performs exactly n-1 pointer dereferences and recovers a
+// name of n dot-joined labels. Returned offsets are absolute positions in
+// msg, one per segment, in construction order.
+func buildPointerChain(n, labelLen int) (msg []byte, offsets []int) {
+	msg = make([]byte, 12) // header-sized prefix, mirroring a real message
+	offsets = make([]int, n)
+
+	label := strings.Repeat("a", labelLen)
+
+	offsets[0] = len(msg)
+	msg = append(msg, byte(labelLen))
+	msg = append(msg, label...)
+	msg = append(msg, 0x00)
+
+	for i := 1; i < n; i++ {
+		offsets[i] = len(msg)
+		msg = append(msg, byte(labelLen))
+		msg = append(msg, label...)
+
+		ptr := make([]byte, 2)
+		binary.BigEndian.PutUint16(ptr, uint16(0xC000|offsets[i-1])) //nolint:gosec // G115: test-only offsets are small
+		msg = append(msg, ptr...)
+	}
+
+	return msg, offsets
+}
+
+// TestParseName_LegalLongBackwardPointerChain validates that a semantically
+// valid name built from a chain of backward pointers at the
+// MaxCompressionPointers boundary parses successfully.
+func TestParseName_LegalLongBackwardPointerChain(t *testing.T) {
+	const segments = 35 // 34 backward jumps: exactly at MaxCompressionPointers
+	msg, offsets := buildPointerChain(segments, 6)
+
+	name, newOffset, err := ParseName(msg, offsets[segments-1])
+	if err != nil {
+		t.Fatalf("ParseName failed on a legal pointer chain: %v", err)
+	}
+
+	wantLabels := segments
+	if got := strings.Count(name, ".") + 1; got != wantLabels {
+		t.Errorf("got %d labels, want %d (name=%q, len=%d)", got, wantLabels, name, len(name))
+	}
+	if len(name) > 255 {
+		t.Errorf("name length %d exceeds RFC 1035 §3.1's 255-byte limit", len(name))
+	}
+	if newOffset != len(msg) {
+		t.Errorf("newOffset = %d, want %d (end of message)", newOffset, len(msg))
+	}
+}
+
+// TestParseName_PointerChainExceedsLimit validates that a 300-pointer chain
+// is rejected with "compression pointer count exceeded", not accepted or
+// allowed to run away.
+func TestParseName_PointerChainExceedsLimit(t *testing.T) {
+	const segments = 301 // 300 backward jumps
+	msg, offsets := buildPointerChain(segments, 1)
+
+	_, _, err := ParseName(msg, offsets[segments-1])
+	if err == nil {
+		t.Fatal("expected ParseName to reject a 300-pointer chain, got nil error")
+	}
+	if !strings.Contains(err.Error(), "compression pointer count exceeded") {
+		t.Errorf("error = %v, want message containing %q", err, "compression pointer count exceeded")
+	}
+}
+
+// TestParseName_TwoNodePointerCycle validates that an A->B->A pointer cycle
+// is rejected the moment the forward half of the cycle (A's pointer to the
+// later-positioned B) is encountered, since the forward-only rule makes a
+// genuine two-node cycle structurally unreachable.
+func TestParseName_TwoNodePointerCycle(t *testing.T) {
+	msg := make([]byte, 12)
+
+	// Node B will sit after node A; its pointer back to A is legal
+	// (backward), but node A's pointer to B is necessarily forward.
+	nodeA := len(msg)
+	msg = append(msg, 0xC0, 0x00) // placeholder pointer bytes, patched below once B's offset is known
+
+	nodeB := len(msg)
+	ptrToA := make([]byte, 2)
+	binary.BigEndian.PutUint16(ptrToA, uint16(0xC000|nodeA)) //nolint:gosec // G115: test-only offset is small
+	msg = append(msg, ptrToA...)
+
+	ptrToB := make([]byte, 2)
+	binary.BigEndian.PutUint16(ptrToB, uint16(0xC000|nodeB)) //nolint:gosec // G115: test-only offset is small
+	copy(msg[nodeA:nodeA+2], ptrToB)
+
+	_, _, err := ParseName(msg, nodeA)
+	if err == nil {
+		t.Fatal("expected ParseName to reject an A->B->A pointer cycle, got nil error")
+	}
+	if !strings.Contains(err.Error(), "forward or self pointer rejected") {
+		t.Errorf("error = %v, want message containing %q", err, "forward or self pointer rejected")
+	}
+}
+
+// TestParseName_ForwardPointerRejected validates that a pointer targeting an
+// offset ahead of itself is rejected outright, per RFC 1035 §4.1.4's
+// backward-only compression rule.
+func TestParseName_ForwardPointerRejected(t *testing.T) {
+	msg := []byte{
+		0xC0, 0x05, // Pointer at offset 0 targeting offset 5 (ahead of itself)
+		0x00, 0x00, 0x00,
+		0x04, 't', 'e', 's', 't', 0x00,
+	}
+
+	_, _, err := ParseName(msg, 0)
+	if err == nil {
+		t.Fatal("expected ParseName to reject a forward pointer, got nil error")
+	}
+	if !strings.Contains(err.Error(), "forward or self pointer rejected") {
+		t.Errorf("error = %v, want message containing %q", err, "forward or self pointer rejected")
+	}
+}
+
+// TestParseName_RevisitLoopDetected validates that a pointer chain revisiting
+// an offset it already passed through is rejected immediately, even though
+// every individual jump in the chain is legal under the forward-only rule
+// (each jump's target is strictly before the jump's own offset) and the
+// chain never approaches MaxCompressionPointers. This is the loop shape the
+// forward-only rule alone doesn't catch: position decreases at the moment of
+// each jump, but increases again while labels are read forward afterward, so
+// a later jump can land back on an offset an earlier jump already visited.
+func TestParseName_RevisitLoopDetected(t *testing.T) {
+	msg := []byte{
+		0x01, 'a', // offset 0: label "a", advances to offset 2
+		0x01, 'a', // offset 2: label "a", advances to offset 4
+		0xC0, 0x00, // offset 4: pointer back to offset 0 (legal: 0 < 4)
+	}
+
+	_, _, err := ParseName(msg, 0)
+	if err == nil {
+		t.Fatal("expected ParseName to reject a revisited offset, got nil error")
+	}
+	if !strings.Contains(err.Error(), "compression pointer loop detected") {
+		t.Errorf("error = %v, want message containing %q", err, "compression pointer loop detected")
+	}
+}
+
+// TestParseName_WireOctetBudgetExceeded validates that a single pointer jump
+// landing on a label run exceeding MaxDomainNameWireOctets is rejected, even
+// though it costs only one jump - far below MaxCompressionPointers. Jump
+// count alone doesn't bound per-jump cost: this is the "each jump lands on a
+// long uncompressed label sequence" case the jump ceiling can't see.
+func TestParseName_WireOctetBudgetExceeded(t *testing.T) {
+	var msg []byte
+	label := strings.Repeat("a", 63)
+	for i := 0; i < 5; i++ { // 5 * 64 = 320 bytes of label data, past the 255 budget
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0x00)
+
+	pointerOffset := len(msg)
+	ptr := make([]byte, 2)
+	binary.BigEndian.PutUint16(ptr, 0xC000) // pointer to offset 0 (legal: 0 < pointerOffset)
+	msg = append(msg, ptr...)
+
+	_, _, err := ParseName(msg, pointerOffset)
+	if err == nil {
+		t.Fatal("expected ParseName to reject a name exceeding the wire octet budget, got nil error")
+	}
+	if !strings.Contains(err.Error(), "exceeds wire octet budget") {
+		t.Errorf("error = %v, want message containing %q", err, "exceeds wire octet budget")
+	}
+}
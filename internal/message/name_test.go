@@ -58,7 +58,7 @@ func TestParseName_RFC1035_Compression(t *testing.T) {
 				0xC0, 0x00, // Pointer to self (self-reference rejected immediately)
 			},
 			offset: 0,
-			errMsg: "invalid compression pointer",
+			errMsg: "forward or self pointer rejected",
 		},
 		{
 			name: "root name (empty)",
@@ -217,7 +217,7 @@ func TestParseName_TruncatedMessage(t *testing.T) {
 			name:   "truncated compression pointer",
 			data:   []byte{0xC0}, // Compression pointer needs 2 bytes, only has 1
 			offset: 0,
-			errMsg: "truncated compression pointer",
+			errMsg: "pointer past packet end",
 		},
 		{
 			name:   "offset out of bounds",
@@ -457,3 +457,180 @@ func TestParseEncodeName_Roundtrip(t *testing.T) {
 		})
 	}
 }
+
+// TestEncodeName_RejectsNonASCII validates that EncodeName keeps its strict
+// ASCII-only behavior (callers needing internationalized names must opt in
+// via EncodeNameIDNA).
+func TestEncodeName_RejectsNonASCII(t *testing.T) {
+	_, err := EncodeName("café.local")
+	if err == nil {
+		t.Fatal("expected EncodeName to reject a non-ASCII label, got nil error")
+	}
+
+	var valErr *errors.ValidationError
+	if !goerrors.As(err, &valErr) {
+		t.Errorf("expected ValidationError per FR-014, got %T", err)
+	}
+}
+
+// TestEncodeNameIDNA_ConvertsUnicodeLabels validates that EncodeNameIDNA
+// converts internationalized labels to Punycode "xn--" A-labels per RFC 5891
+// before applying EncodeName's RFC 1035 checks.
+func TestEncodeNameIDNA_ConvertsUnicodeLabels(t *testing.T) {
+	encoded, err := EncodeNameIDNA("café.local")
+	if err != nil {
+		t.Fatalf("EncodeNameIDNA failed: %v", err)
+	}
+
+	decoded, _, err := ParseName(encoded, 0)
+	if err != nil {
+		t.Fatalf("ParseName failed: %v", err)
+	}
+
+	const wantASCII = "xn--caf-dma.local"
+	if decoded != wantASCII {
+		t.Errorf("EncodeNameIDNA(%q) decoded to %q, want %q", "café.local", decoded, wantASCII)
+	}
+}
+
+// TestEncodeNameIDNA_PassesThroughASCII validates that an already-ASCII name
+// encodes identically via EncodeNameIDNA and EncodeName.
+func TestEncodeNameIDNA_PassesThroughASCII(t *testing.T) {
+	want, err := EncodeName("printer.local")
+	if err != nil {
+		t.Fatalf("EncodeName failed: %v", err)
+	}
+
+	got, err := EncodeNameIDNA("printer.local")
+	if err != nil {
+		t.Fatalf("EncodeNameIDNA failed: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("EncodeNameIDNA(%q) = %v, want %v", "printer.local", got, want)
+	}
+}
+
+// TestEncodeNameIDNA_LabelExceeds63BytesAfterPunycode validates that a label
+// which is short in Unicode form but exceeds the 63-byte RFC 1035 limit once
+// expanded to Punycode is rejected.
+func TestEncodeNameIDNA_LabelExceeds63BytesAfterPunycode(t *testing.T) {
+	// 60 non-ASCII repetitions of "a" with a combining diaeresis; the
+	// label as a whole expands under Punycode to more than 63 bytes.
+	label := strings.Repeat("ä", 60)
+
+	_, err := EncodeNameIDNA(label + ".local")
+	if err == nil {
+		t.Fatal("expected EncodeNameIDNA to reject an oversized Punycode label, got nil error")
+	}
+
+	var valErr *errors.ValidationError
+	if !goerrors.As(err, &valErr) {
+		t.Errorf("expected ValidationError, got %T", err)
+	}
+}
+
+// TestDecodeNameUnicode_ConvertsPunycodeLabels validates that
+// DecodeNameUnicode converts "xn--" A-labels back to their Unicode
+// presentation form.
+func TestDecodeNameUnicode_ConvertsPunycodeLabels(t *testing.T) {
+	got, err := DecodeNameUnicode("xn--caf-dma.local")
+	if err != nil {
+		t.Fatalf("DecodeNameUnicode failed: %v", err)
+	}
+
+	const want = "café.local"
+	if got != want {
+		t.Errorf("DecodeNameUnicode(%q) = %q, want %q", "xn--caf-dma.local", got, want)
+	}
+}
+
+// TestDecodeNameUnicode_PassesThroughASCII validates that a name with no
+// "xn--" labels is returned unchanged.
+func TestDecodeNameUnicode_PassesThroughASCII(t *testing.T) {
+	got, err := DecodeNameUnicode("printer.local")
+	if err != nil {
+		t.Fatalf("DecodeNameUnicode failed: %v", err)
+	}
+
+	if got != "printer.local" {
+		t.Errorf("DecodeNameUnicode(%q) = %q, want unchanged", "printer.local", got)
+	}
+}
+
+// TestDecodeNameUnicode_RejectsInvalidACE validates that a label with the
+// "xn--" prefix whose suffix is not valid Punycode fails ToUnicode decoding
+// rather than silently passing through garbage.
+func TestDecodeNameUnicode_RejectsInvalidACE(t *testing.T) {
+	_, err := DecodeNameUnicode("xn---.local")
+	if err == nil {
+		t.Fatal("expected DecodeNameUnicode to reject an invalid xn-- label, got nil error")
+	}
+
+	var valErr *errors.ValidationError
+	if !goerrors.As(err, &valErr) {
+		t.Errorf("expected ValidationError, got %T", err)
+	}
+}
+
+// TestEncodeNameIDNA_EmptyALabel validates that an empty label (consecutive
+// dots) is rejected the same way EncodeName rejects it, even once IDNA
+// conversion has run.
+func TestEncodeNameIDNA_EmptyALabel(t *testing.T) {
+	_, err := EncodeNameIDNA("café..local")
+	if err == nil {
+		t.Fatal("expected EncodeNameIDNA to reject an empty label, got nil error")
+	}
+
+	var valErr *errors.ValidationError
+	if !goerrors.As(err, &valErr) {
+		t.Errorf("expected ValidationError, got %T", err)
+	}
+}
+
+// TestEncodeNameIDNA_RootName validates that EncodeNameIDNA, like EncodeName,
+// accepts the root name ("" or ".") rather than treating it as an invalid
+// IDNA label.
+func TestEncodeNameIDNA_RootName(t *testing.T) {
+	for _, root := range []string{"", "."} {
+		got, err := EncodeNameIDNA(root)
+		if err != nil {
+			t.Fatalf("EncodeNameIDNA(%q) failed: %v", root, err)
+		}
+		if string(got) != "\x00" {
+			t.Errorf("EncodeNameIDNA(%q) = %v, want root encoding", root, got)
+		}
+	}
+}
+
+// TestEncodeNameIDNA_LowercasesASCII validates that EncodeNameIDNA
+// lowercases already-ASCII labels the same way protocol.EncodeName (used by
+// the responder to advertise hostnames) does, so the advertise and query
+// sides agree on the wire form of the same logical name.
+func TestEncodeNameIDNA_LowercasesASCII(t *testing.T) {
+	encoded, err := EncodeNameIDNA("MyPrinter.local")
+	if err != nil {
+		t.Fatalf("EncodeNameIDNA failed: %v", err)
+	}
+
+	decoded, _, err := ParseName(encoded, 0)
+	if err != nil {
+		t.Fatalf("ParseName failed: %v", err)
+	}
+
+	if decoded != "myprinter.local" {
+		t.Errorf("EncodeNameIDNA(%q) decoded to %q, want %q", "MyPrinter.local", decoded, "myprinter.local")
+	}
+}
+
+// TestDecodeNameUnicode_RootName validates that DecodeNameUnicode returns
+// the root name unchanged rather than erroring on an empty label.
+func TestDecodeNameUnicode_RootName(t *testing.T) {
+	got, err := DecodeNameUnicode("")
+	if err != nil {
+		t.Fatalf("DecodeNameUnicode(\"\") failed: %v", err)
+	}
+	if got != "" {
+		t.Errorf("DecodeNameUnicode(\"\") = %q, want \"\"", got)
+	}
+}
@@ -0,0 +1,207 @@
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// rrTypeOPT is the OPT pseudo-resource-record TYPE per RFC 6891 §6.1.2.
+//
+// OPT never describes a real resource - ParseMessage intercepts it in the
+// Additional section and decodes it into DNSMessage.OPT instead of appending
+// it to Additionals.
+const rrTypeOPT = uint16(protocol.RecordTypeOPT)
+
+// OptionCode identifies an EDNS(0) option per RFC 6891 §6.1.2.
+type OptionCode uint16
+
+// Well-known OptionCode values.
+const (
+	// OptionCodeNSID is the Name Server Identifier option per RFC 5001.
+	OptionCodeNSID OptionCode = 3
+
+	// OptionCodeDAU is the DNSSEC Algorithm Understood option per RFC 6975 §3.
+	OptionCodeDAU OptionCode = 5
+
+	// OptionCodeECS is the EDNS Client Subnet option per RFC 7871 §6.
+	OptionCodeECS OptionCode = 8
+
+	// OptionCodePadding is the Padding option per RFC 7830.
+	OptionCodePadding OptionCode = 12
+
+	// OptionCodeEDE is the Extended DNS Error option per RFC 8914.
+	OptionCodeEDE OptionCode = 15
+
+	// OptionCodeCookie is the DNS Cookie option per RFC 7873 §4: an 8-byte
+	// client cookie alone in a request, or the client cookie followed by an
+	// 8-to-32-byte server cookie in a response.
+	OptionCodeCookie OptionCode = 10
+)
+
+// EDNSOption is a single option-code/option-data pair from an OPT record's
+// RDATA per RFC 6891 §6.1.2.
+type EDNSOption struct {
+	// Code identifies the option's meaning.
+	Code OptionCode
+
+	// Data is the option's raw, option-specific value.
+	Data []byte
+}
+
+// OPTRecord represents the parsed EDNS(0) pseudo-resource-record per
+// RFC 6891 §6.1.1-§6.1.2 - this package's OPTData equivalent, decoded by
+// parseOPTRecord (ParseMessage/ParseAnswer already intercept a TYPE=41
+// Additional-section record into DNSMessage.OPT rather than leaving it as
+// an opaque Answer) and produced on the write side by writeOPTRecord
+// (internal/message/edns.go) from WithEDNS/WithDNSSEC's QueryOption.
+//
+// OPT repurposes the ordinary Answer fields rather than describing a real
+// resource: CLASS carries the requester's UDP payload size instead of a DNS
+// class, and the 32-bit TTL field is bit-packed into the extended RCODE,
+// EDNS version, and flags (including DO, the DNSSEC-OK bit) instead of
+// holding a cache lifetime.
+type OPTRecord struct {
+	// UDPPayloadSize is the requester's advertised maximum UDP payload size
+	// in bytes, carried in the OPT record's CLASS field.
+	UDPPayloadSize uint16
+
+	// ExtendedRCODE is the upper 8 bits of the 12-bit extended RCODE, carried
+	// in the top byte of the TTL field. Combine with
+	// (DNSHeader).GetRCODE()'s lower 4 bits to form the full value per
+	// RFC 6891 §6.1.3.
+	ExtendedRCODE uint8
+
+	// Version is the EDNS version, carried in the second byte of the TTL
+	// field. RFC 6891 defines version 0 only.
+	Version uint8
+
+	// DNSSECOK is the DO bit per RFC 3225 §3, carried in bit 15 of the TTL
+	// field: set when the requester can accept DNSSEC RRSIG/DNSKEY records.
+	DNSSECOK bool
+
+	// Options is the variable-length option list decoded from RDATA.
+	Options []EDNSOption
+}
+
+// FullRCODE returns msg's complete response code: the header's 4-bit field
+// alone for a classic (non-EDNS0) message, or that combined with
+// OPT.ExtendedRCODE's upper 8 bits into the 12-bit value RFC 6891 §6.1.3
+// defines when msg carries an OPT record. A value like BADVERS (16) has a
+// zero low nibble, so callers validating RCODE on an EDNS0-aware message
+// should check this instead of (DNSHeader).GetRCODE() alone.
+func (msg *DNSMessage) FullRCODE() uint16 {
+	base := uint16(msg.Header.GetRCODE())
+	if msg.OPT == nil {
+		return base
+	}
+	return uint16(msg.OPT.ExtendedRCODE)<<4 | base
+}
+
+// ExtendedError returns the Extended DNS Error (RFC 8914) carried in opt's
+// option list, or nil if opt has none or the option is too short to contain
+// even an INFO-CODE. Only the first EDE option is considered; RFC 8914 §3.1
+// expects at most one per message.
+func (opt *OPTRecord) ExtendedError() *errors.ExtendedDNSError {
+	for _, o := range opt.Options {
+		if o.Code != OptionCodeEDE {
+			continue
+		}
+		if len(o.Data) < 2 {
+			return nil
+		}
+		return &errors.ExtendedDNSError{
+			InfoCode:  binary.BigEndian.Uint16(o.Data[:2]),
+			ExtraText: string(o.Data[2:]),
+		}
+	}
+	return nil
+}
+
+// ClientSubnet is a decoded EDNS Client Subnet option per RFC 7871 §6.
+type ClientSubnet struct {
+	// Family is the ADDRESS FAMILY NUMBER (IANA registry): 1 for IPv4, 2 for
+	// IPv6.
+	Family uint16
+
+	// SourcePrefixLength is the number of significant bits of Address the
+	// querier supplied.
+	SourcePrefixLength uint8
+
+	// ScopePrefixLength is the number of significant bits the responder's
+	// answer is scoped to; 0 in a query.
+	ScopePrefixLength uint8
+
+	// Address holds the prefix's address bytes, truncated/padded to
+	// ceil(SourcePrefixLength/8) bytes per RFC 7871 §6.
+	Address []byte
+}
+
+// ClientSubnet returns the EDNS Client Subnet (RFC 7871) carried in opt's
+// option list, or nil if opt has none or the option is too short to contain
+// even the fixed-size fields. Only the first ECS option is considered; a
+// message carrying more than one is malformed.
+func (opt *OPTRecord) ClientSubnet() *ClientSubnet {
+	for _, o := range opt.Options {
+		if o.Code != OptionCodeECS {
+			continue
+		}
+		if len(o.Data) < 4 {
+			return nil
+		}
+		return &ClientSubnet{
+			Family:             binary.BigEndian.Uint16(o.Data[0:2]),
+			SourcePrefixLength: o.Data[2],
+			ScopePrefixLength:  o.Data[3],
+			Address:            o.Data[4:],
+		}
+	}
+	return nil
+}
+
+// parseOPTRecord decodes a's repurposed CLASS/TTL/RDATA fields into an
+// OPTRecord per RFC 6891 §6.1.2. a must already be the raw Answer ParseAnswer
+// produced for a TYPE=41 entry; parseOPTRecord does not consult a.NAME or
+// a.TYPE.
+func parseOPTRecord(a Answer) (*OPTRecord, error) {
+	opt := &OPTRecord{
+		UDPPayloadSize: a.CLASS,
+		ExtendedRCODE:  uint8(a.TTL >> 24), //nolint:gosec // G115: intentional byte extraction
+		Version:        uint8(a.TTL >> 16), //nolint:gosec // G115: intentional byte extraction
+		DNSSECOK:       a.TTL&0x8000 != 0,
+	}
+
+	offset := 0
+	for offset < len(a.RDATA) {
+		if offset+4 > len(a.RDATA) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse OPT record",
+				Offset:    offset,
+				Message:   "truncated option: not enough bytes for option-code and option-length",
+				Code:      errors.CodeTruncated,
+			}
+		}
+
+		code := OptionCode(binary.BigEndian.Uint16(a.RDATA[offset : offset+2]))
+		length := binary.BigEndian.Uint16(a.RDATA[offset+2 : offset+4])
+		offset += 4
+
+		if offset+int(length) > len(a.RDATA) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse OPT record",
+				Offset:    offset,
+				Message:   fmt.Sprintf("truncated option data: expected %d bytes, only %d available", length, len(a.RDATA)-offset),
+				Code:      errors.CodeTruncated,
+			}
+		}
+
+		data := make([]byte, length)
+		copy(data, a.RDATA[offset:offset+int(length)])
+		opt.Options = append(opt.Options, EDNSOption{Code: code, Data: data})
+		offset += int(length)
+	}
+
+	return opt, nil
+}
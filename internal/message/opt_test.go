@@ -0,0 +1,207 @@
+package message
+
+import (
+	"bytes"
+	goerrors "errors"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// TestParseMessage_WithOPT validates that ParseMessage decodes an OPT
+// pseudo-RR in the Additional section into DNSMessage.OPT and excludes it
+// from Additionals, per RFC 6891 §6.1.2.
+func TestParseMessage_WithOPT(t *testing.T) {
+	// Header: response, QDCOUNT=0, ANCOUNT=0, NSCOUNT=0, ARCOUNT=1
+	msg := []byte{
+		0x00, 0x00, // ID
+		0x82, 0x00, // Flags: QR=1, TC=1
+		0x00, 0x00, // QDCOUNT
+		0x00, 0x00, // ANCOUNT
+		0x00, 0x00, // NSCOUNT
+		0x00, 0x01, // ARCOUNT = 1
+	}
+
+	// OPT record: root name, TYPE=41, CLASS=4096 (UDP payload size),
+	// TTL = extended RCODE(0) | version(0) | flags(DO=1), one NSID option.
+	msg = append(msg,
+		0x00,       // NAME = root
+		0x00, 0x29, // TYPE = 41 (OPT)
+		0x10, 0x00, // CLASS = 4096
+		0x00, 0x00, 0x80, 0x00, // TTL: ext RCODE=0, version=0, DO=1
+		0x00, 0x04, // RDLENGTH = 4
+		0x00, 0x03, 0x00, 0x00, // option: NSID (3), length 0
+	)
+
+	parsed, err := ParseMessage(msg)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if len(parsed.Additionals) != 0 {
+		t.Errorf("len(Additionals) = %d, want 0 (OPT should not appear in Additionals)", len(parsed.Additionals))
+	}
+
+	if parsed.OPT == nil {
+		t.Fatal("OPT = nil, want decoded OPTRecord")
+	}
+
+	if parsed.OPT.UDPPayloadSize != 4096 {
+		t.Errorf("OPT.UDPPayloadSize = %d, want 4096", parsed.OPT.UDPPayloadSize)
+	}
+
+	if !parsed.OPT.DNSSECOK {
+		t.Error("OPT.DNSSECOK = false, want true (DO bit set)")
+	}
+
+	if len(parsed.OPT.Options) != 1 {
+		t.Fatalf("len(OPT.Options) = %d, want 1", len(parsed.OPT.Options))
+	}
+
+	if parsed.OPT.Options[0].Code != OptionCodeNSID {
+		t.Errorf("OPT.Options[0].Code = %d, want %d (NSID)", parsed.OPT.Options[0].Code, OptionCodeNSID)
+	}
+
+	if !parsed.Header.Truncated {
+		t.Error("Header.Truncated = false, want true (TC bit set)")
+	}
+}
+
+// TestDNSMessage_FullRCODE validates that FullRCODE combines OPT.ExtendedRCODE
+// with the header's 4-bit field per RFC 6891 §6.1.3, and falls back to the
+// header field alone when msg carries no OPT record.
+func TestDNSMessage_FullRCODE(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *DNSMessage
+		want uint16
+	}{
+		{
+			name: "no OPT record",
+			msg:  &DNSMessage{Header: DNSHeader{Flags: 0x0001}}, // RCODE=1
+			want: 1,
+		},
+		{
+			name: "OPT present, extended RCODE zero",
+			msg:  &DNSMessage{Header: DNSHeader{Flags: 0x0000}, OPT: &OPTRecord{ExtendedRCODE: 0}},
+			want: 0,
+		},
+		{
+			name: "BADVERS (16): zero low nibble, non-zero high byte",
+			msg:  &DNSMessage{Header: DNSHeader{Flags: 0x0000}, OPT: &OPTRecord{ExtendedRCODE: 1}},
+			want: 16,
+		},
+		{
+			name: "both nibbles set",
+			msg:  &DNSMessage{Header: DNSHeader{Flags: 0x0007}, OPT: &OPTRecord{ExtendedRCODE: 1}},
+			want: 0x17,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.FullRCODE(); got != tt.want {
+				t.Errorf("FullRCODE() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOPTRecord_ExtendedError validates that ExtendedError decodes an EDE
+// (RFC 8914) option's INFO-CODE/EXTRA-TEXT, and returns nil when opt carries
+// no such option.
+func TestOPTRecord_ExtendedError(t *testing.T) {
+	t.Run("no EDE option", func(t *testing.T) {
+		opt := &OPTRecord{Options: []EDNSOption{{Code: OptionCodeNSID}}}
+		if got := opt.ExtendedError(); got != nil {
+			t.Errorf("ExtendedError() = %+v, want nil", got)
+		}
+	})
+
+	t.Run("EDE with extra text", func(t *testing.T) {
+		data := []byte{0x00, 0x16} // INFO-CODE = 22 (No Reachable Authority)
+		data = append(data, "no reachable authority"...)
+		opt := &OPTRecord{Options: []EDNSOption{{Code: OptionCodeEDE, Data: data}}}
+
+		got := opt.ExtendedError()
+		if got == nil {
+			t.Fatal("ExtendedError() = nil, want decoded error")
+		}
+		if got.InfoCode != 22 {
+			t.Errorf("InfoCode = %d, want 22", got.InfoCode)
+		}
+		if got.ExtraText != "no reachable authority" {
+			t.Errorf("ExtraText = %q, want %q", got.ExtraText, "no reachable authority")
+		}
+	})
+
+	t.Run("EDE too short for INFO-CODE", func(t *testing.T) {
+		opt := &OPTRecord{Options: []EDNSOption{{Code: OptionCodeEDE, Data: []byte{0x00}}}}
+		if got := opt.ExtendedError(); got != nil {
+			t.Errorf("ExtendedError() = %+v, want nil for truncated option", got)
+		}
+	})
+}
+
+// TestParseOPTRecord_TruncatedOption validates that parseOPTRecord returns a
+// WireFormatError when an option's declared length exceeds the remaining
+// RDATA, per FR-011/FR-015.
+func TestParseOPTRecord_TruncatedOption(t *testing.T) {
+	a := Answer{
+		TYPE:  rrTypeOPT,
+		CLASS: 1232,
+		RDATA: []byte{0x00, 0x0C, 0x00, 0x04, 0x01}, // PADDING option claims 4 bytes, only 1 present
+	}
+
+	_, err := parseOPTRecord(a)
+	if err == nil {
+		t.Fatal("expected error for truncated option data, got nil")
+	}
+
+	var wireErr *errors.WireFormatError
+	if !goerrors.As(err, &wireErr) {
+		t.Errorf("expected WireFormatError per FR-015, got %T", err)
+	}
+}
+
+// TestOPTRecord_ClientSubnet validates that ClientSubnet decodes an ECS
+// (RFC 7871) option's family/prefix-length/address fields, and returns nil
+// when opt carries no such option.
+func TestOPTRecord_ClientSubnet(t *testing.T) {
+	t.Run("no ECS option", func(t *testing.T) {
+		opt := &OPTRecord{Options: []EDNSOption{{Code: OptionCodeNSID}}}
+		if got := opt.ClientSubnet(); got != nil {
+			t.Errorf("ClientSubnet() = %+v, want nil", got)
+		}
+	})
+
+	t.Run("IPv4 /24", func(t *testing.T) {
+		data := []byte{0x00, 0x01, 24, 0, 192, 168, 1, 0}
+		opt := &OPTRecord{Options: []EDNSOption{{Code: OptionCodeECS, Data: data}}}
+
+		got := opt.ClientSubnet()
+		if got == nil {
+			t.Fatal("ClientSubnet() = nil, want decoded subnet")
+		}
+		if got.Family != 1 {
+			t.Errorf("Family = %d, want 1 (IPv4)", got.Family)
+		}
+		if got.SourcePrefixLength != 24 {
+			t.Errorf("SourcePrefixLength = %d, want 24", got.SourcePrefixLength)
+		}
+		if got.ScopePrefixLength != 0 {
+			t.Errorf("ScopePrefixLength = %d, want 0", got.ScopePrefixLength)
+		}
+		wantAddr := []byte{192, 168, 1, 0}
+		if !bytes.Equal(got.Address, wantAddr) {
+			t.Errorf("Address = %v, want %v", got.Address, wantAddr)
+		}
+	})
+
+	t.Run("ECS too short for fixed fields", func(t *testing.T) {
+		opt := &OPTRecord{Options: []EDNSOption{{Code: OptionCodeECS, Data: []byte{0x00, 0x01}}}}
+		if got := opt.ClientSubnet(); got != nil {
+			t.Errorf("ClientSubnet() = %+v, want nil for truncated option", got)
+		}
+	})
+}
@@ -3,10 +3,12 @@ package message
 
 import (
 	"encoding/binary"
+	goerrors "errors"
 	"fmt"
-	"net"
+	"net/netip"
 
 	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
 )
 
 // SRVData represents SRV record data per RFC 2782.
@@ -32,6 +34,15 @@ type SRVData struct {
 // FR-011: System MUST validate response message format and discard malformed packets
 // FR-012: System MUST decompress DNS names per RFC 1035 §4.1.4
 //
+// Name decompression (ParseName) bounds the number of pointer dereferences
+// per name to guard against pointer loops, and enforces the 255-byte
+// total-name limit; ParseRDATA type-decodes RDATA into its typed *Data
+// form (its doc lists the supported types), and an OPT pseudo-RR in the
+// Additional section is decoded by parseOPTRecord into DNSMessage.OPT. The
+// returned *DNSMessage's Questions/Answers/Authorities/Additionals are
+// plain slices rather than iterators, consistent with the rest of this
+// package - a caller ranges over them directly.
+//
 // Parameters:
 //   - msg: The complete DNS message buffer
 //
@@ -45,6 +56,20 @@ func ParseMessage(msg []byte) (*DNSMessage, error) {
 		return nil, err
 	}
 
+	// Reject messages whose declared section counts would force large
+	// allocations below before we've confirmed the packet is even that
+	// big (a forged header can claim far more records than a small UDP
+	// datagram could ever carry).
+	totalRecords := int(header.ANCount) + int(header.NSCount) + int(header.ARCount)
+	if totalRecords > protocol.MaxRecordsPerMessage {
+		return nil, &errors.WireFormatError{
+			Operation: "parse message",
+			Offset:    12,
+			Message:   fmt.Sprintf("message declares %d records, exceeds maximum %d per message", totalRecords, protocol.MaxRecordsPerMessage),
+			Code:      errors.CodeMessageTooLarge,
+		}
+	}
+
 	offset := 12 // Header is always 12 bytes
 
 	// Parse question section
@@ -81,14 +106,29 @@ func ParseMessage(msg []byte) (*DNSMessage, error) {
 	}
 
 	// Parse additional section (M1: ignored per FR-010, but we parse for completeness)
-	additionals := make([]Answer, header.ARCount)
+	var opt *OPTRecord
+	additionals := make([]Answer, 0, header.ARCount)
 	for i := uint16(0); i < header.ARCount; i++ {
 		additional, newOffset, err := ParseAnswer(msg, offset)
 		if err != nil {
 			return nil, err
 		}
-		additionals[i] = additional
 		offset = newOffset
+
+		// RFC 6891 §6.1.2: the OPT pseudo-RR repurposes CLASS/TTL and isn't
+		// a real resource - decode it into OPT instead of Additionals so
+		// callers that fold Answers+Additionals together don't mistake its
+		// payload-size/extended-RCODE fields for an ordinary record.
+		if additional.TYPE == rrTypeOPT {
+			parsedOPT, err := parseOPTRecord(additional)
+			if err != nil {
+				return nil, err
+			}
+			opt = parsedOPT
+			continue
+		}
+
+		additionals = append(additionals, additional)
 	}
 
 	return &DNSMessage{
@@ -97,9 +137,149 @@ func ParseMessage(msg []byte) (*DNSMessage, error) {
 		Answers:     answers,
 		Authorities: authorities,
 		Additionals: additionals,
+		OPT:         opt,
 	}, nil
 }
 
+// DefaultMaxParseErrors is ParseOptions.MaxErrors's default when left at
+// zero: enough to recover a multi-responder mDNS packet with more than one
+// bad record without accumulating an unbounded MultiError off a
+// pathological or adversarial packet.
+const DefaultMaxParseErrors = 8
+
+// ParseOptions configures ParseMessageWithOptions's tolerance for a
+// malformed resource record within an otherwise-parseable message.
+type ParseOptions struct {
+	// Lenient, when true, skips a resource record that fails to parse (in
+	// the Answer, Authority, or Additional section) and keeps parsing the
+	// rest of the message, instead of ParseMessage's default all-or-nothing
+	// behavior. This matters on a mixed network: a single malformed record
+	// from one broken responder (a cheap IoT device, say) would otherwise
+	// cost every other responder's good answers that happened to arrive in
+	// the same collected packet.
+	Lenient bool
+
+	// MaxErrors caps how many per-record errors Lenient mode collects into
+	// the returned *errors.MultiError. Records that fail after the cap is
+	// reached are still skipped and parsing goes on; only their errors are
+	// not recorded. Zero means DefaultMaxParseErrors.
+	MaxErrors int
+}
+
+// ParseMessageWithOptions is ParseMessage with opts.Lenient's partial-parse
+// recovery: a malformed Question still fails the whole message (RFC 1035
+// §4.1.2 gives no RDLENGTH to resync on, so a bad question makes every
+// later offset in the message untrustworthy), but a malformed Answer,
+// Authority, or Additional record is skipped via its RDLENGTH and parsing
+// continues; the errors of at most opts.MaxErrors skipped records are
+// returned.
+//
+// The returned *DNSMessage holds every record that did parse; a non-nil
+// *errors.MultiError alongside it describes what didn't, at its own Offset
+// each - see MultiError's doc comment for using errors.As to recover it.
+// opts.Lenient == false makes this identical to ParseMessage.
+func ParseMessageWithOptions(msg []byte, opts ParseOptions) (*DNSMessage, error) {
+	if !opts.Lenient {
+		return ParseMessage(msg)
+	}
+
+	maxErrors := opts.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultMaxParseErrors
+	}
+
+	header, err := ParseHeader(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	totalRecords := int(header.ANCount) + int(header.NSCount) + int(header.ARCount)
+	if totalRecords > protocol.MaxRecordsPerMessage {
+		return nil, &errors.WireFormatError{
+			Operation: "parse message",
+			Offset:    12,
+			Message:   fmt.Sprintf("message declares %d records, exceeds maximum %d per message", totalRecords, protocol.MaxRecordsPerMessage),
+			Code:      errors.CodeMessageTooLarge,
+		}
+	}
+
+	offset := 12
+
+	questions := make([]Question, header.QDCount)
+	for i := uint16(0); i < header.QDCount; i++ {
+		question, newOffset, err := ParseQuestion(msg, offset)
+		if err != nil {
+			// The Question section has no RDLENGTH-style resync point, so
+			// a malformed question is fatal even in Lenient mode.
+			return nil, err
+		}
+		questions[i] = question
+		offset = newOffset
+	}
+
+	var errs []*errors.WireFormatError
+	answers, offset := parseSectionLenient(msg, offset, header.ANCount, maxErrors, &errs)
+	authorities, offset := parseSectionLenient(msg, offset, header.NSCount, maxErrors, &errs)
+	additionalsRaw, _ := parseSectionLenient(msg, offset, header.ARCount, maxErrors, &errs)
+
+	var opt *OPTRecord
+	additionals := make([]Answer, 0, len(additionalsRaw))
+	for _, additional := range additionalsRaw {
+		if additional.TYPE == rrTypeOPT {
+			parsedOPT, err := parseOPTRecord(additional)
+			if err != nil {
+				if wfe, ok := err.(*errors.WireFormatError); ok && len(errs) < maxErrors {
+					errs = append(errs, wfe)
+				}
+				continue
+			}
+			opt = parsedOPT
+			continue
+		}
+		additionals = append(additionals, additional)
+	}
+
+	result := &DNSMessage{
+		Header:      header,
+		Questions:   questions,
+		Answers:     answers,
+		Authorities: authorities,
+		Additionals: additionals,
+		OPT:         opt,
+	}
+
+	if len(errs) == 0 {
+		return result, nil
+	}
+	return result, &errors.MultiError{Errors: errs}
+}
+
+// parseSectionLenient parses up to count resource records starting at
+// offset, skipping (rather than failing on) any record whose error carries
+// a resumable offset per parseAnswerResumable, and collecting up to
+// maxErrors of those errors into *errs. It stops early - keeping whatever
+// it already has - the first time a record fails with no resumable offset,
+// since nothing after that point in the section can be located reliably.
+func parseSectionLenient(msg []byte, offset int, count uint16, maxErrors int, errs *[]*errors.WireFormatError) ([]Answer, int) {
+	records := make([]Answer, 0, count)
+	for i := uint16(0); i < count; i++ {
+		answer, newOffset, resumeOffset, err := parseAnswerResumable(msg, offset)
+		if err != nil {
+			if wfe, ok := err.(*errors.WireFormatError); ok && len(*errs) < maxErrors {
+				*errs = append(*errs, wfe)
+			}
+			if resumeOffset < 0 {
+				return records, offset
+			}
+			offset = resumeOffset
+			continue
+		}
+		records = append(records, answer)
+		offset = newOffset
+	}
+	return records, offset
+}
+
 // ParseHeader parses the DNS message header per RFC 1035 §4.1.1.
 //
 // Header format (12 bytes):
@@ -125,6 +305,7 @@ func ParseHeader(msg []byte) (DNSHeader, error) {
 			Operation: "parse header",
 			Offset:    0,
 			Message:   fmt.Sprintf("message too short for header: %d bytes, expected at least 12", len(msg)),
+			Code:      errors.CodeTruncated,
 		}
 	}
 
@@ -136,6 +317,7 @@ func ParseHeader(msg []byte) (DNSHeader, error) {
 		NSCount: binary.BigEndian.Uint16(msg[8:10]),
 		ARCount: binary.BigEndian.Uint16(msg[10:12]),
 	}
+	header.Truncated = header.IsTruncated()
 
 	return header, nil
 }
@@ -171,6 +353,7 @@ func ParseQuestion(msg []byte, offset int) (Question, int, error) {
 			Operation: "parse question",
 			Offset:    newOffset,
 			Message:   "truncated question: not enough bytes for QTYPE and QCLASS",
+			Code:      errors.CodeTruncated,
 		}
 	}
 
@@ -211,49 +394,89 @@ func ParseQuestion(msg []byte, offset int) (Question, int, error) {
 //   - newOffset: The offset immediately after this answer entry
 //   - error: WireFormatError if the answer is malformed
 func ParseAnswer(msg []byte, offset int) (Answer, int, error) {
-	// Parse NAME
-	name, newOffset, err := ParseName(msg, offset)
+	answer, newOffset, _, err := parseAnswerResumable(msg, offset)
 	if err != nil {
 		return Answer{}, offset, err
 	}
+	return answer, newOffset, nil
+}
+
+// parseAnswerResumable is ParseAnswer's implementation, plus a resumeOffset
+// ParseMessageWithOptions's Lenient mode can restart parsing from after a
+// malformed record - ParseAnswer itself ignores it, preserving its existing
+// all-or-nothing contract.
+//
+// resumeOffset is only meaningful when err != nil, and is negative when no
+// resync is possible: a NAME, fixed-field, or RDATA-bounds failure means the
+// wire position of the next record can't be determined, so Lenient mode has
+// no choice but to abandon the rest of the current section (RFC 1035 §4.1.3
+// gives a reader no way to locate a record boundary except by completely
+// parsing every record before it). A failure inside decompressRDATANames is
+// different: RDLENGTH and the RDATA bytes it names were read successfully,
+// so the next record's offset is already known even though this record's
+// embedded name didn't decode.
+func parseAnswerResumable(msg []byte, offset int) (answer Answer, newOffset int, resumeOffset int, err error) {
+	// Parse NAME
+	name, nameOffset, err := ParseName(msg, offset)
+	if err != nil {
+		return Answer{}, offset, -1, err
+	}
 
 	// Check bounds for TYPE, CLASS, TTL, RDLENGTH (10 bytes)
-	if newOffset+10 > len(msg) {
-		return Answer{}, offset, &errors.WireFormatError{
+	if nameOffset+10 > len(msg) {
+		return Answer{}, offset, -1, &errors.WireFormatError{
 			Operation: "parse answer",
-			Offset:    newOffset,
+			Offset:    nameOffset,
 			Message:   "truncated answer: not enough bytes for fixed fields",
+			Code:      errors.CodeTruncated,
 		}
 	}
 
 	// Parse TYPE
-	rtype := binary.BigEndian.Uint16(msg[newOffset : newOffset+2])
+	rtype := binary.BigEndian.Uint16(msg[nameOffset : nameOffset+2])
 
 	// Parse CLASS
-	class := binary.BigEndian.Uint16(msg[newOffset+2 : newOffset+4])
+	class := binary.BigEndian.Uint16(msg[nameOffset+2 : nameOffset+4])
 
 	// Parse TTL
-	ttl := binary.BigEndian.Uint32(msg[newOffset+4 : newOffset+8])
+	ttl := binary.BigEndian.Uint32(msg[nameOffset+4 : nameOffset+8])
 
 	// Parse RDLENGTH
-	rdlength := binary.BigEndian.Uint16(msg[newOffset+8 : newOffset+10])
+	rdlength := binary.BigEndian.Uint16(msg[nameOffset+8 : nameOffset+10])
 
-	newOffset += 10
+	fieldsOffset := nameOffset + 10
 
 	// Check bounds for RDATA
-	if newOffset+int(rdlength) > len(msg) {
-		return Answer{}, offset, &errors.WireFormatError{
+	if fieldsOffset+int(rdlength) > len(msg) {
+		return Answer{}, offset, -1, &errors.WireFormatError{
 			Operation: "parse answer",
-			Offset:    newOffset,
-			Message:   fmt.Sprintf("truncated RDATA: expected %d bytes, only %d available", rdlength, len(msg)-newOffset),
+			Offset:    fieldsOffset,
+			Message:   fmt.Sprintf("truncated RDATA: expected %d bytes, only %d available", rdlength, len(msg)-fieldsOffset),
+			Code:      errors.CodeTruncated,
 		}
 	}
 
+	// RDLENGTH and its bytes are confirmed in-bounds, so wherever this
+	// record ends is now known regardless of what decompressRDATANames
+	// below does with the bytes themselves.
+	resumeOffset = fieldsOffset + int(rdlength)
+
 	// Extract RDATA
 	rdata := make([]byte, rdlength)
-	copy(rdata, msg[newOffset:newOffset+int(rdlength)])
+	copy(rdata, msg[fieldsOffset:resumeOffset])
 
-	answer := Answer{
+	// A PTR or SRV target name embedded in RDATA may be compressed against a
+	// NAME elsewhere in the full message (RFC 1035 §4.1.4 pointers are only
+	// meaningful relative to the whole buffer), but ParseRDATA only ever sees
+	// this isolated copy. Expand any such name into its uncompressed form now,
+	// while msg is still available, so RDATA is self-contained and ParseRDATA
+	// can decode it later without full-message context.
+	rdata, err = decompressRDATANames(rtype, rdata, msg, fieldsOffset)
+	if err != nil {
+		return Answer{}, offset, resumeOffset, err
+	}
+
+	answer = Answer{
 		NAME:     name,
 		TYPE:     rtype,
 		CLASS:    class,
@@ -262,28 +485,150 @@ func ParseAnswer(msg []byte, offset int) (Answer, int, error) {
 		RDATA:    rdata,
 	}
 
-	return answer, newOffset + int(rdlength), nil
+	return answer, resumeOffset, resumeOffset, nil
+}
+
+// decompressRDATANames rewrites the names embedded in PTR, NS and CNAME
+// RDATA, the SRV target, and the SOA MNAME/RNAME into their fully
+// expanded, uncompressed label form, resolving any
+// compression pointer against the whole message (rdata itself starts at
+// rdataOffset within msg but is parsed in isolation everywhere else, so a
+// pointer into an earlier record's NAME would otherwise be unresolvable).
+// Every other record type's RDATA is returned unchanged, including any
+// name it carries (e.g. an RRSIG signer name or an NSEC next name). RDLENGTH still reflects the original wire length; only the
+// returned bytes may grow past it.
+func decompressRDATANames(recordType uint16, rdata, msg []byte, rdataOffset int) ([]byte, error) {
+	switch recordType {
+	case 12, 2, 5: // PTR, NS, CNAME records: Domain name
+		name, _, err := ParseName(msg, rdataOffset)
+		if err != nil {
+			return nil, err
+		}
+		return reencodeDecompressedName(name, rdataOffset)
+
+	case 33: // SRV record: Priority, Weight, Port, Target
+		if len(rdata) < 6 {
+			return rdata, nil // too short; ParseRDATA reports the truncation
+		}
+		target, _, err := ParseName(msg, rdataOffset+6)
+		if err != nil {
+			return nil, err
+		}
+		encodedTarget, err := reencodeDecompressedName(target, rdataOffset+6)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, 6+len(encodedTarget))
+		copy(out, rdata[:6])
+		copy(out[6:], encodedTarget)
+		return out, nil
+
+	case 6: // SOA record: MNAME, RNAME, then 5 uint32 fields
+		mname, mnameEnd, err := ParseName(msg, rdataOffset)
+		if err != nil {
+			return nil, err
+		}
+		rname, rnameEnd, err := ParseName(msg, mnameEnd)
+		if err != nil {
+			return nil, err
+		}
+		if rnameEnd+20 > len(msg) {
+			return rdata, nil // too short; ParseRDATA reports the truncation
+		}
+		encodedMNAME, err := reencodeDecompressedName(mname, rdataOffset)
+		if err != nil {
+			return nil, err
+		}
+		encodedRNAME, err := reencodeDecompressedName(rname, mnameEnd)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, 0, len(encodedMNAME)+len(encodedRNAME)+20)
+		out = append(out, encodedMNAME...)
+		out = append(out, encodedRNAME...)
+		out = append(out, msg[rnameEnd:rnameEnd+20]...)
+		return out, nil
+
+	default:
+		return rdata, nil
+	}
+}
+
+// reencodeDecompressedName re-encodes name (already decompressed by
+// ParseName from offset) into its uncompressed wire form. EncodeName
+// validates name the way it would a caller-constructed name - rejecting
+// characters DNS wire format actually permits in a label but EncodeName's
+// presentation-format rules don't - so a failure here describes a malformed
+// *incoming* packet, not a caller's bad input; report it as a
+// WireFormatError (offset pointing at the original RDATA name) rather than
+// let EncodeName's ValidationError leak out of a parse path.
+func reencodeDecompressedName(name string, offset int) ([]byte, error) {
+	encoded, err := EncodeName(name)
+	if err != nil {
+		var valErr *errors.ValidationError
+		if goerrors.As(err, &valErr) {
+			return nil, &errors.WireFormatError{
+				Operation: "re-encode decompressed RDATA name",
+				Offset:    offset,
+				Message:   valErr.Message,
+				Code:      errors.CodeInvalidName,
+			}
+		}
+		return nil, err
+	}
+	return encoded, nil
 }
 
-// ParseRDATA parses type-specific RDATA into Go types per RFC 1035.
+// ParseRDATA parses type-specific RDATA into RData implementations per RFC 1035.
+//
+// rdata is never compressed by the time it reaches ParseRDATA: a PTR, NS,
+// CNAME, SOA, or SRV target embedded in RDATA may point (RFC 1035 §4.1.4)
+// into an earlier part of the full message, so parseAnswerResumable expands
+// any such pointer via decompressRDATANames - which calls ParseName, the
+// same NameParser-based decoder that reads Answer.NAME (SkipName walks
+// NameParser directly) - while the full
+// message buffer is still in scope. ParseRDATA itself only ever sees an
+// already-self-contained RDATA slice, so it needs no message/offset
+// parameters of its own.
 //
 // Supported types (per FR-002):
-//   - A (1): IPv4 address → net.IP
-//   - PTR (12): Domain name → string
-//   - TXT (16): Text strings → []string
+//   - A (1): IPv4 address → AData
+//   - AAAA (28): IPv6 address → AAAAData
+//   - PTR (12): Domain name → PTRData
+//   - TXT (16): Text strings → TXTData
 //   - SRV (33): Service location → SRVData
+//   - RRSIG (46): Signature over an RRset per RFC 4034 §3.1 → *RRSIGData
+//   - DNSKEY (48): DNSSEC public key per RFC 4034 §2.1 → *DNSKEYData
+//   - DS (43): Delegation Signer per RFC 4034 §5.1 → *DSData
+//   - NSEC (47): Authenticated denial of existence per RFC 4034 §4.1 → *NSECData
+//   - NSEC3 (50): Hashed authenticated denial of existence per RFC 5155 §3 → *NSEC3Data
+//   - SVCB (64) / HTTPS (65): Service bindings per RFC 9460 → *SVCBData
+//   - NS (2): Authoritative name server per RFC 1035 §3.3.11 → NSData
+//   - CNAME (5): Canonical name per RFC 1035 §3.3.1 → CNAMEData
+//   - SOA (6): Start of authority per RFC 1035 §3.3.13 → SOAData
+//   - HINFO (13): Host information per RFC 1035 §3.3.2 → HINFOData
+//
+// OPT (41) is not among these: it is an EDNS(0) pseudo-RR that repurposes the
+// RR's CLASS/TTL fields rather than describing a real resource, so
+// ParseMessage intercepts it in the Additional section and decodes it via
+// parseOPTRecord into DNSMessage.OPT instead of routing it through here.
+//
+// Callers that already have an Answer can use its AsA/AsAAAA/AsPTR/AsSRV/AsTXT
+// methods instead of type-asserting this return value by hand.
+//
+// PackRDATA is the inverse: Parse(Pack(x)) == x for every type both support.
 //
 // FR-009: System MUST parse mDNS response messages per RFC 6762 wire format
 // FR-012: System MUST decompress DNS names in RDATA (PTR, SRV target)
 //
 // Parameters:
-//   - recordType: The DNS record type (A, PTR, SRV, TXT)
+//   - recordType: The DNS record type (A, AAAA, PTR, SRV, TXT)
 //   - rdata: The raw RDATA bytes
 //
 // Returns:
-//   - parsed: Type-specific parsed data (net.IP, string, []string, or SRVData)
+//   - parsed: The type-specific RData implementation for recordType
 //   - error: WireFormatError if RDATA is malformed
-func ParseRDATA(recordType uint16, rdata []byte) (interface{}, error) {
+func ParseRDATA(recordType uint16, rdata []byte) (RData, error) {
 	switch recordType {
 	case 1: // A record: IPv4 address (4 bytes)
 		if len(rdata) != 4 {
@@ -291,19 +636,115 @@ func ParseRDATA(recordType uint16, rdata []byte) (interface{}, error) {
 				Operation: "parse A record",
 				Offset:    0,
 				Message:   fmt.Sprintf("invalid A record length: %d bytes, expected 4", len(rdata)),
+				Code:      errors.CodeInvalidValue,
+			}
+		}
+		return AData{IP: netip.AddrFrom4([4]byte{rdata[0], rdata[1], rdata[2], rdata[3]})}, nil
+
+	case 28: // AAAA record: IPv6 address (16 bytes) per RFC 3596 §2.2
+		if len(rdata) != 16 {
+			return nil, &errors.WireFormatError{
+				Operation: "parse AAAA record",
+				Offset:    0,
+				Message:   fmt.Sprintf("invalid AAAA record length: %d bytes, expected 16", len(rdata)),
+				Code:      errors.CodeInvalidValue,
 			}
 		}
-		return net.IPv4(rdata[0], rdata[1], rdata[2], rdata[3]), nil
+		return AAAAData{IP: netip.AddrFrom16([16]byte(rdata))}, nil
 
 	case 12: // PTR record: Domain name
 		name, _, err := ParseName(rdata, 0)
 		if err != nil {
 			return nil, err
 		}
-		return name, nil
+		return PTRData{Name: name}, nil
+
+	case 2: // NS record: Domain name per RFC 1035 §3.3.11
+		name, _, err := ParseName(rdata, 0)
+		if err != nil {
+			return nil, err
+		}
+		return NSData{Name: name}, nil
+
+	case 5: // CNAME record: Domain name per RFC 1035 §3.3.1
+		name, _, err := ParseName(rdata, 0)
+		if err != nil {
+			return nil, err
+		}
+		return CNAMEData{Name: name}, nil
+
+	case 6: // SOA record per RFC 1035 §3.3.13
+		mname, offset, err := ParseName(rdata, 0)
+		if err != nil {
+			return nil, err
+		}
+		rname, offset, err := ParseName(rdata, offset)
+		if err != nil {
+			return nil, err
+		}
+		if offset+20 > len(rdata) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse SOA record",
+				Offset:    offset,
+				Message:   fmt.Sprintf("truncated SOA record: %d bytes remaining, expected at least 20", len(rdata)-offset),
+				Code:      errors.CodeTruncated,
+			}
+		}
+		return SOAData{
+			MNAME:   mname,
+			RNAME:   rname,
+			Serial:  binary.BigEndian.Uint32(rdata[offset : offset+4]),
+			Refresh: binary.BigEndian.Uint32(rdata[offset+4 : offset+8]),
+			Retry:   binary.BigEndian.Uint32(rdata[offset+8 : offset+12]),
+			Expire:  binary.BigEndian.Uint32(rdata[offset+12 : offset+16]),
+			Minimum: binary.BigEndian.Uint32(rdata[offset+16 : offset+20]),
+		}, nil
+
+	case 13: // HINFO record: CPU and OS character-strings per RFC 1035 §3.3.2
+		if len(rdata) < 1 {
+			return nil, &errors.WireFormatError{
+				Operation: "parse HINFO record",
+				Offset:    0,
+				Message:   "truncated HINFO record: missing CPU length",
+				Code:      errors.CodeTruncated,
+			}
+		}
+		cpuLen := int(rdata[0])
+		if 1+cpuLen > len(rdata) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse HINFO record",
+				Offset:    1,
+				Message:   fmt.Sprintf("truncated CPU string: expected %d bytes, only %d available", cpuLen, len(rdata)-1),
+				Code:      errors.CodeTruncated,
+			}
+		}
+		cpu := string(rdata[1 : 1+cpuLen])
+		offset := 1 + cpuLen
+
+		if offset >= len(rdata) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse HINFO record",
+				Offset:    offset,
+				Message:   "truncated HINFO record: missing OS length",
+				Code:      errors.CodeTruncated,
+			}
+		}
+		osLen := int(rdata[offset])
+		offset++
+		if offset+osLen > len(rdata) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse HINFO record",
+				Offset:    offset,
+				Message:   fmt.Sprintf("truncated OS string: expected %d bytes, only %d available", osLen, len(rdata)-offset),
+				Code:      errors.CodeTruncated,
+			}
+		}
+		os := string(rdata[offset : offset+osLen])
+
+		return HINFOData{CPU: cpu, OS: os}, nil
 
 	case 16: // TXT record: Text strings
-		var strings []string
+		var strs []string
 		offset := 0
 		for offset < len(rdata) {
 			// Each string is length-prefixed
@@ -318,14 +759,15 @@ func ParseRDATA(recordType uint16, rdata []byte) (interface{}, error) {
 					Operation: "parse TXT record",
 					Offset:    offset,
 					Message:   fmt.Sprintf("truncated TXT string: expected %d bytes, only %d available", length, len(rdata)-offset),
+					Code:      errors.CodeTruncated,
 				}
 			}
 
 			str := string(rdata[offset : offset+length])
-			strings = append(strings, str)
+			strs = append(strs, str)
 			offset += length
 		}
-		return strings, nil
+		return TXTData{Entries: strs, KV: parseTXTKV(strs)}, nil
 
 	case 33: // SRV record: Priority, Weight, Port, Target
 		if len(rdata) < 6 {
@@ -333,6 +775,7 @@ func ParseRDATA(recordType uint16, rdata []byte) (interface{}, error) {
 				Operation: "parse SRV record",
 				Offset:    0,
 				Message:   fmt.Sprintf("truncated SRV record: %d bytes, expected at least 6", len(rdata)),
+				Code:      errors.CodeTruncated,
 			}
 		}
 
@@ -353,11 +796,233 @@ func ParseRDATA(recordType uint16, rdata []byte) (interface{}, error) {
 			Target:   target,
 		}, nil
 
+	case 46: // RRSIG record per RFC 4034 §3.1
+		return ParseRRSIG(rdata)
+
+	case 48: // DNSKEY record per RFC 4034 §2.1
+		return ParseDNSKEY(rdata)
+
+	case 43: // DS record per RFC 4034 §5.1
+		return ParseDS(rdata)
+
+	case 47: // NSEC record per RFC 4034 §4.1
+		return ParseNSEC(rdata)
+
+	case 50: // NSEC3 record per RFC 5155 §3
+		return ParseNSEC3(rdata)
+
+	case 64, 65: // SVCB / HTTPS record per RFC 9460 §2
+		return ParseSVCB(rdata)
+
 	default:
 		return nil, &errors.WireFormatError{
 			Operation: "parse RDATA",
 			Offset:    0,
 			Message:   fmt.Sprintf("unsupported record type: %d", recordType),
+			Code:      errors.CodeInvalidValue,
+		}
+	}
+}
+
+// PackRDATA serializes an RData implementation into type-specific RDATA
+// bytes, the inverse of ParseRDATA: Parse(Pack(x)) == x for every type both
+// support. recordType must match data's concrete type (e.g. 1 with AData),
+// the same pairing ParseRDATA would have produced it from.
+//
+// PTR and SRV targets are encoded uncompressed via EncodeName, same as
+// ParseName would decompress them into; MessageBuilder re-compresses names
+// per RFC 1035 §4.1.4 as it assembles the full message.
+//
+// Supported types mirror ParseRDATA: A (1), AAAA (28), PTR (12), TXT (16),
+// SRV (33), RRSIG (46), DNSKEY (48), DS (43), NSEC (47), NSEC3 (50),
+// SVCB/HTTPS (64/65), NS (2), CNAME (5), SOA (6), HINFO (13).
+func PackRDATA(recordType uint16, data RData) ([]byte, error) {
+	switch recordType {
+	case 1: // A record: IPv4 address (4 bytes)
+		d, ok := data.(AData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		if !d.IP.Is4() {
+			return nil, &errors.ValidationError{Field: "AData.IP", Value: d.IP, Message: "not a valid IPv4 address", Code: errors.CodeInvalidValue}
+		}
+		b := d.IP.As4()
+		return b[:], nil
+
+	case 28: // AAAA record: IPv6 address (16 bytes) per RFC 3596 §2.2
+		d, ok := data.(AAAAData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		if !d.IP.Is6() || d.IP.Is4In6() {
+			return nil, &errors.ValidationError{Field: "AAAAData.IP", Value: d.IP, Message: "not a valid IPv6 address", Code: errors.CodeInvalidValue}
+		}
+		b := d.IP.As16()
+		return b[:], nil
+
+	case 12: // PTR record: Domain name
+		d, ok := data.(PTRData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeName(d.Name)
+
+	case 2: // NS record: Domain name per RFC 1035 §3.3.11
+		d, ok := data.(NSData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeName(d.Name)
+
+	case 5: // CNAME record: Domain name per RFC 1035 §3.3.1
+		d, ok := data.(CNAMEData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeName(d.Name)
+
+	case 6: // SOA record per RFC 1035 §3.3.13
+		d, ok := data.(SOAData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		mname, err := EncodeName(d.MNAME)
+		if err != nil {
+			return nil, err
+		}
+		rname, err := EncodeName(d.RNAME)
+		if err != nil {
+			return nil, err
+		}
+		rdata := make([]byte, 0, len(mname)+len(rname)+20)
+		rdata = append(rdata, mname...)
+		rdata = append(rdata, rname...)
+		fixed := make([]byte, 20)
+		binary.BigEndian.PutUint32(fixed[0:4], d.Serial)
+		binary.BigEndian.PutUint32(fixed[4:8], d.Refresh)
+		binary.BigEndian.PutUint32(fixed[8:12], d.Retry)
+		binary.BigEndian.PutUint32(fixed[12:16], d.Expire)
+		binary.BigEndian.PutUint32(fixed[16:20], d.Minimum)
+		return append(rdata, fixed...), nil
+
+	case 13: // HINFO record: CPU and OS character-strings per RFC 1035 §3.3.2
+		d, ok := data.(HINFOData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		if len(d.CPU) > 255 {
+			return nil, &errors.ValidationError{Field: "HINFOData.CPU", Value: d.CPU, Message: "CPU string exceeds 255 bytes", Code: errors.CodeRDataOverflow}
+		}
+		if len(d.OS) > 255 {
+			return nil, &errors.ValidationError{Field: "HINFOData.OS", Value: d.OS, Message: "OS string exceeds 255 bytes", Code: errors.CodeRDataOverflow}
+		}
+		rdata := make([]byte, 0, 2+len(d.CPU)+len(d.OS))
+		rdata = append(rdata, byte(len(d.CPU)))
+		rdata = append(rdata, d.CPU...)
+		rdata = append(rdata, byte(len(d.OS)))
+		rdata = append(rdata, d.OS...)
+		return rdata, nil
+
+	case 16: // TXT record: Text strings
+		d, ok := data.(TXTData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return encodeTXTStrings(d.Entries)
+
+	case 33: // SRV record: Priority, Weight, Port, Target
+		d, ok := data.(SRVData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		rdata := make([]byte, 6)
+		binary.BigEndian.PutUint16(rdata[0:2], d.Priority)
+		binary.BigEndian.PutUint16(rdata[2:4], d.Weight)
+		binary.BigEndian.PutUint16(rdata[4:6], d.Port)
+		target, err := EncodeName(d.Target)
+		if err != nil {
+			return nil, err
+		}
+		return append(rdata, target...), nil
+
+	case 46: // RRSIG record per RFC 4034 §3.1
+		d, ok := data.(*RRSIGData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeRRSIG(d)
+
+	case 48: // DNSKEY record per RFC 4034 §2.1
+		d, ok := data.(*DNSKEYData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeDNSKEY(d), nil
+
+	case 43: // DS record per RFC 4034 §5.1
+		d, ok := data.(*DSData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeDS(d), nil
+
+	case 47: // NSEC record per RFC 4034 §4.1
+		d, ok := data.(*NSECData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeNSEC(d)
+
+	case 50: // NSEC3 record per RFC 5155 §3
+		d, ok := data.(*NSEC3Data)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeNSEC3(d)
+
+	case 64, 65: // SVCB / HTTPS record per RFC 9460 §2
+		d, ok := data.(*SVCBData)
+		if !ok {
+			return nil, packTypeMismatchError(recordType, data)
+		}
+		return EncodeSVCB(d)
+
+	default:
+		return nil, &errors.WireFormatError{
+			Operation: "pack RDATA",
+			Offset:    0,
+			Message:   fmt.Sprintf("unsupported record type: %d", recordType),
+			Code:      errors.CodeInvalidValue,
+		}
+	}
+}
+
+// packTypeMismatchError reports that data's concrete type doesn't match the
+// RData type ParseRDATA would have returned for recordType.
+func packTypeMismatchError(recordType uint16, data RData) error {
+	return &errors.ValidationError{
+		Field:   "data",
+		Value:   data,
+		Message: fmt.Sprintf("type %T does not match record type %d", data, recordType),
+		Code:    errors.CodeInvalidValue,
+	}
+}
+
+// encodeTXTStrings serializes entries into length-prefixed TXT RDATA per RFC
+// 1035 §3.3.14, the inverse of ParseRDATA's TXT case.
+func encodeTXTStrings(entries []string) ([]byte, error) {
+	var rdata []byte
+	for _, s := range entries {
+		if len(s) > 255 {
+			return nil, &errors.ValidationError{
+				Field:   "TXTData.Entries",
+				Value:   s,
+				Message: "TXT string exceeds 255 bytes",
+				Code:    errors.CodeRDataOverflow,
+			}
 		}
+		rdata = append(rdata, byte(len(s)))
+		rdata = append(rdata, s...)
 	}
+	return rdata, nil
 }
@@ -1,8 +1,9 @@
 package message
 
 import (
+	"bytes"
 	goerrors "errors"
-	"net"
+	"net/netip"
 	"strings"
 	"testing"
 
@@ -349,17 +350,52 @@ func TestParseRDATA_ARecord(t *testing.T) {
 		t.Fatalf("ParseRDATA failed: %v", err)
 	}
 
-	ip, ok := result.(net.IP)
+	aData, ok := result.(AData)
 	if !ok {
-		t.Fatalf("ParseRDATA returned %T, want net.IP per RFC 1035 §3.4.1", result)
+		t.Fatalf("ParseRDATA returned %T, want AData per RFC 1035 §3.4.1", result)
 	}
+	ip := aData.IP
 
-	expected := net.IPv4(192, 168, 1, 100)
-	if !ip.Equal(expected) {
+	expected := netip.MustParseAddr("192.168.1.100")
+	if ip != expected {
 		t.Errorf("IP = %s, want %s per RFC 1035 §3.4.1", ip, expected)
 	}
 }
 
+// TestParseRDATA_AAAARecord validates that ParseRDATA correctly parses AAAA
+// record RDATA (16-byte IPv6 address) per RFC 3596 §2.2.
+func TestParseRDATA_AAAARecord(t *testing.T) {
+	rdata := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+	}
+
+	result, err := ParseRDATA(28, rdata) // TYPE = AAAA (28)
+	if err != nil {
+		t.Fatalf("ParseRDATA failed: %v", err)
+	}
+
+	aaaaData, ok := result.(AAAAData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want AAAAData per RFC 3596 §2.2", result)
+	}
+	ip := aaaaData.IP
+
+	expected := netip.MustParseAddr("2001:db8::1")
+	if ip != expected {
+		t.Errorf("IP = %s, want %s per RFC 3596 §2.2", ip, expected)
+	}
+}
+
+// TestParseRDATA_AAAARecord_InvalidLength validates that ParseRDATA rejects
+// AAAA RDATA that isn't exactly 16 bytes.
+func TestParseRDATA_AAAARecord_InvalidLength(t *testing.T) {
+	_, err := ParseRDATA(28, []byte{0x20, 0x01})
+	if err == nil {
+		t.Fatal("ParseRDATA accepted truncated AAAA RDATA, want error")
+	}
+}
+
 // TestParseRDATA_PTRRecord validates that ParseRDATA correctly parses PTR record
 // RDATA (domain name) per RFC 1035 §3.3.12 (FR-009).
 //
@@ -381,10 +417,11 @@ func TestParseRDATA_PTRRecord(t *testing.T) {
 		t.Fatalf("ParseRDATA failed: %v", err)
 	}
 
-	name, ok := result.(string)
+	ptr, ok := result.(PTRData)
 	if !ok {
-		t.Fatalf("ParseRDATA returned %T, want string per RFC 1035 §3.3.12", result)
+		t.Fatalf("ParseRDATA returned %T, want PTRData per RFC 1035 §3.3.12", result)
 	}
+	name := ptr.Name
 
 	expected := "myservice._http._tcp.local"
 	if name != expected {
@@ -436,6 +473,256 @@ func TestParseRDATA_SRVRecord(t *testing.T) {
 	}
 }
 
+// TestDS_EncodeParseRoundTrip validates that ParseDS decodes what EncodeDS
+// produced per RFC 4034 §5.1.
+func TestDS_EncodeParseRoundTrip(t *testing.T) {
+	want := &DSData{
+		KeyTag:     12345,
+		Algorithm:  AlgorithmECDSAP256SHA256,
+		DigestType: DSDigestSHA256,
+		Digest:     []byte{0xDE, 0xAD, 0xBE, 0xEF, 0x01, 0x02, 0x03, 0x04},
+	}
+
+	rdata := EncodeDS(want)
+
+	got, err := ParseDS(rdata)
+	if err != nil {
+		t.Fatalf("ParseDS() error = %v", err)
+	}
+
+	if got.KeyTag != want.KeyTag {
+		t.Errorf("KeyTag = %d, want %d", got.KeyTag, want.KeyTag)
+	}
+	if got.Algorithm != want.Algorithm {
+		t.Errorf("Algorithm = %d, want %d", got.Algorithm, want.Algorithm)
+	}
+	if got.DigestType != want.DigestType {
+		t.Errorf("DigestType = %d, want %d", got.DigestType, want.DigestType)
+	}
+	if string(got.Digest) != string(want.Digest) {
+		t.Errorf("Digest = %x, want %x", got.Digest, want.Digest)
+	}
+}
+
+// TestParseDS_Truncated validates that ParseDS rejects RDATA shorter than
+// the fixed 4-byte KeyTag/Algorithm/DigestType prefix.
+func TestParseDS_Truncated(t *testing.T) {
+	_, err := ParseDS([]byte{0x00, 0x01, 0x02})
+	if err == nil {
+		t.Fatal("ParseDS() accepted truncated RDATA, want error")
+	}
+}
+
+// TestNSEC_EncodeParseRoundTrip validates that ParseNSEC decodes what
+// EncodeNSEC produced, including the windowed type bit map, per RFC 4034 §4.1.
+func TestNSEC_EncodeParseRoundTrip(t *testing.T) {
+	want := &NSECData{
+		NextDomainName: "server.local",
+		// A/PTR/TXT/SRV/RRSIG/DNSKEY: all six are below 256, so they share
+		// window 0 and the bitmap has a single window.
+		TypeBitMap: []uint16{1, 12, 16, 33, 46, 48},
+	}
+
+	rdata, err := EncodeNSEC(want)
+	if err != nil {
+		t.Fatalf("EncodeNSEC() error = %v", err)
+	}
+
+	got, err := ParseNSEC(rdata)
+	if err != nil {
+		t.Fatalf("ParseNSEC() error = %v", err)
+	}
+
+	if got.NextDomainName != want.NextDomainName {
+		t.Errorf("NextDomainName = %q, want %q", got.NextDomainName, want.NextDomainName)
+	}
+	if len(got.TypeBitMap) != len(want.TypeBitMap) {
+		t.Fatalf("TypeBitMap = %v, want %v", got.TypeBitMap, want.TypeBitMap)
+	}
+	for i, tp := range want.TypeBitMap {
+		if got.TypeBitMap[i] != tp {
+			t.Errorf("TypeBitMap[%d] = %d, want %d", i, got.TypeBitMap[i], tp)
+		}
+	}
+}
+
+// TestParseNSEC_MalformedTypeBitMap validates that ParseNSEC rejects a type
+// bit map truncated mid-window, an out-of-range bitmap length, and a bitmap
+// shorter than its declared length, mirroring TestParseNSEC3_Truncated's
+// coverage of the sibling windowed-bitmap format.
+func TestParseNSEC_MalformedTypeBitMap(t *testing.T) {
+	nextName, err := EncodeName("local")
+	if err != nil {
+		t.Fatalf("EncodeName() error = %v", err)
+	}
+
+	// Window number present, but no bitmap-length octet follows.
+	truncatedWindow := append(append([]byte{}, nextName...), 0x00)
+	if _, err := ParseNSEC(truncatedWindow); err == nil {
+		t.Error("ParseNSEC() accepted RDATA truncated mid-window, want error")
+	}
+
+	// RFC 4034 §4.1.2 caps bitmap length at 32 bytes.
+	oversizedLength := append(append([]byte{}, nextName...), 0x00, 33)
+	if _, err := ParseNSEC(oversizedLength); err == nil {
+		t.Error("ParseNSEC() accepted a bitmap length of 33, want error")
+	}
+
+	// Bitmap length says 4 bytes follow, but only 1 is present.
+	truncatedBitmap := append(append([]byte{}, nextName...), 0x00, 0x04, 0xFF)
+	if _, err := ParseNSEC(truncatedBitmap); err == nil {
+		t.Error("ParseNSEC() accepted RDATA truncated mid-bitmap, want error")
+	}
+}
+
+// TestParseRDATA_DSAndNSEC validates that ParseRDATA dispatches type 43 (DS)
+// and type 47 (NSEC) to ParseDS/ParseNSEC respectively.
+func TestParseRDATA_DSAndNSEC(t *testing.T) {
+	dsRDATA := EncodeDS(&DSData{KeyTag: 1, Algorithm: AlgorithmECDSAP256SHA256, DigestType: DSDigestSHA256, Digest: []byte{0x01}})
+	result, err := ParseRDATA(43, dsRDATA)
+	if err != nil {
+		t.Fatalf("ParseRDATA(43, ...) error = %v", err)
+	}
+	if _, ok := result.(*DSData); !ok {
+		t.Errorf("ParseRDATA(43, ...) returned %T, want *DSData", result)
+	}
+
+	nsecRDATA, err := EncodeNSEC(&NSECData{NextDomainName: "local", TypeBitMap: []uint16{1}})
+	if err != nil {
+		t.Fatalf("EncodeNSEC() error = %v", err)
+	}
+	result, err = ParseRDATA(47, nsecRDATA)
+	if err != nil {
+		t.Fatalf("ParseRDATA(47, ...) error = %v", err)
+	}
+	if _, ok := result.(*NSECData); !ok {
+		t.Errorf("ParseRDATA(47, ...) returned %T, want *NSECData", result)
+	}
+}
+
+// TestNSEC3_EncodeParseRoundTrip validates that ParseNSEC3 decodes what
+// EncodeNSEC3 produced, including the salt, hashed next owner name, and
+// windowed type bit map, per RFC 5155 §3.2.
+func TestNSEC3_EncodeParseRoundTrip(t *testing.T) {
+	want := &NSEC3Data{
+		HashAlgorithm:       NSEC3HashSHA1,
+		Flags:               NSEC3FlagOptOut,
+		Iterations:          10,
+		Salt:                []byte{0xAA, 0xBB, 0xCC},
+		NextHashedOwnerName: []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+		TypeBitMap:          []uint16{1, 12, 16, 33, 46, 48},
+	}
+
+	rdata, err := EncodeNSEC3(want)
+	if err != nil {
+		t.Fatalf("EncodeNSEC3() error = %v", err)
+	}
+
+	got, err := ParseNSEC3(rdata)
+	if err != nil {
+		t.Fatalf("ParseNSEC3() error = %v", err)
+	}
+
+	if got.HashAlgorithm != want.HashAlgorithm {
+		t.Errorf("HashAlgorithm = %d, want %d", got.HashAlgorithm, want.HashAlgorithm)
+	}
+	if got.Flags != want.Flags {
+		t.Errorf("Flags = %d, want %d", got.Flags, want.Flags)
+	}
+	if got.Iterations != want.Iterations {
+		t.Errorf("Iterations = %d, want %d", got.Iterations, want.Iterations)
+	}
+	if string(got.Salt) != string(want.Salt) {
+		t.Errorf("Salt = %x, want %x", got.Salt, want.Salt)
+	}
+	if string(got.NextHashedOwnerName) != string(want.NextHashedOwnerName) {
+		t.Errorf("NextHashedOwnerName = %x, want %x", got.NextHashedOwnerName, want.NextHashedOwnerName)
+	}
+	if len(got.TypeBitMap) != len(want.TypeBitMap) {
+		t.Fatalf("TypeBitMap = %v, want %v", got.TypeBitMap, want.TypeBitMap)
+	}
+	for i, tp := range want.TypeBitMap {
+		if got.TypeBitMap[i] != tp {
+			t.Errorf("TypeBitMap[%d] = %d, want %d", i, got.TypeBitMap[i], tp)
+		}
+	}
+}
+
+// TestNSEC3_EncodeParseRoundTrip_EmptySalt validates the zero-length salt
+// encoding RFC 5155 §3.2 uses when NSEC3PARAM carries no salt.
+func TestNSEC3_EncodeParseRoundTrip_EmptySalt(t *testing.T) {
+	want := &NSEC3Data{
+		HashAlgorithm:       NSEC3HashSHA1,
+		Iterations:          0,
+		Salt:                []byte{},
+		NextHashedOwnerName: []byte{0xFF},
+		TypeBitMap:          []uint16{1},
+	}
+
+	rdata, err := EncodeNSEC3(want)
+	if err != nil {
+		t.Fatalf("EncodeNSEC3() error = %v", err)
+	}
+
+	got, err := ParseNSEC3(rdata)
+	if err != nil {
+		t.Fatalf("ParseNSEC3() error = %v", err)
+	}
+	if len(got.Salt) != 0 {
+		t.Errorf("Salt = %x, want empty", got.Salt)
+	}
+}
+
+// TestEncodeNSEC3_RejectsOversizedLengths validates that EncodeNSEC3 returns
+// an error instead of silently wrapping a length byte when Salt or
+// NextHashedOwnerName exceeds the 255-byte wire length octet.
+func TestEncodeNSEC3_RejectsOversizedLengths(t *testing.T) {
+	if _, err := EncodeNSEC3(&NSEC3Data{Salt: make([]byte, 256), NextHashedOwnerName: []byte{0x01}}); err == nil {
+		t.Error("EncodeNSEC3() accepted a 256-byte Salt, want error")
+	}
+	if _, err := EncodeNSEC3(&NSEC3Data{NextHashedOwnerName: make([]byte, 256)}); err == nil {
+		t.Error("EncodeNSEC3() accepted a 256-byte NextHashedOwnerName, want error")
+	}
+}
+
+// TestParseNSEC3_Truncated validates that ParseNSEC3 rejects RDATA shorter
+// than the fixed 5-byte HashAlgorithm/Flags/Iterations/SaltLength prefix, as
+// well as RDATA truncated mid-salt or mid-hash.
+func TestParseNSEC3_Truncated(t *testing.T) {
+	if _, err := ParseNSEC3([]byte{0x01, 0x00, 0x00}); err == nil {
+		t.Error("ParseNSEC3() accepted RDATA shorter than the fixed prefix, want error")
+	}
+
+	// SaltLength says 4 bytes of salt follow, but only 1 is present.
+	truncatedSalt := []byte{0x01, 0x00, 0x00, 0x00, 0x04, 0xAA}
+	if _, err := ParseNSEC3(truncatedSalt); err == nil {
+		t.Error("ParseNSEC3() accepted RDATA truncated mid-salt, want error")
+	}
+
+	// No salt, but the hash length octet says 4 bytes follow and none do.
+	truncatedHash := []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x04}
+	if _, err := ParseNSEC3(truncatedHash); err == nil {
+		t.Error("ParseNSEC3() accepted RDATA truncated mid-hash, want error")
+	}
+}
+
+// TestParseRDATA_NSEC3 validates that ParseRDATA dispatches type 50 (NSEC3)
+// to ParseNSEC3.
+func TestParseRDATA_NSEC3(t *testing.T) {
+	rdata, err := EncodeNSEC3(&NSEC3Data{HashAlgorithm: NSEC3HashSHA1, NextHashedOwnerName: []byte{0x01}, TypeBitMap: []uint16{1}})
+	if err != nil {
+		t.Fatalf("EncodeNSEC3() error = %v", err)
+	}
+
+	result, err := ParseRDATA(50, rdata)
+	if err != nil {
+		t.Fatalf("ParseRDATA(50, ...) error = %v", err)
+	}
+	if _, ok := result.(*NSEC3Data); !ok {
+		t.Errorf("ParseRDATA(50, ...) returned %T, want *NSEC3Data", result)
+	}
+}
+
 // TestParseRDATA_TXTRecord validates that ParseRDATA correctly parses TXT record
 // RDATA (text strings) per RFC 1035 §3.3.14 (FR-009).
 //
@@ -454,10 +741,11 @@ func TestParseRDATA_TXTRecord(t *testing.T) {
 		t.Fatalf("ParseRDATA failed: %v", err)
 	}
 
-	txt, ok := result.([]string)
+	txtData, ok := result.(TXTData)
 	if !ok {
-		t.Fatalf("ParseRDATA returned %T, want []string per RFC 1035 §3.3.14", result)
+		t.Fatalf("ParseRDATA returned %T, want TXTData per RFC 1035 §3.3.14", result)
 	}
+	txt := txtData.Entries
 
 	if len(txt) != 2 {
 		t.Fatalf("len(TXT) = %d, want 2 per RFC 1035 §3.3.14", len(txt))
@@ -472,6 +760,140 @@ func TestParseRDATA_TXTRecord(t *testing.T) {
 	}
 }
 
+// TestParseRDATA_NSRecord validates that ParseRDATA correctly parses NS record
+// RDATA (domain name) per RFC 1035 §3.3.11.
+func TestParseRDATA_NSRecord(t *testing.T) {
+	rdata := []byte{
+		0x02, 'n', 's',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+	}
+
+	result, err := ParseRDATA(2, rdata) // TYPE = NS (2)
+	if err != nil {
+		t.Fatalf("ParseRDATA failed: %v", err)
+	}
+
+	ns, ok := result.(NSData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want NSData per RFC 1035 §3.3.11", result)
+	}
+
+	if ns.Name != "ns.local" {
+		t.Errorf("NS name = %q, want %q per RFC 1035 §3.3.11", ns.Name, "ns.local")
+	}
+
+	packed, err := PackRDATA(2, ns)
+	if err != nil {
+		t.Fatalf("PackRDATA failed: %v", err)
+	}
+	if !bytes.Equal(packed, rdata) {
+		t.Errorf("PackRDATA(ParseRDATA(rdata)) = %v, want %v", packed, rdata)
+	}
+}
+
+// TestParseRDATA_CNAMERecord validates that ParseRDATA correctly parses CNAME
+// record RDATA (domain name) per RFC 1035 §3.3.1.
+func TestParseRDATA_CNAMERecord(t *testing.T) {
+	rdata := []byte{
+		0x04, 'h', 'o', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+	}
+
+	result, err := ParseRDATA(5, rdata) // TYPE = CNAME (5)
+	if err != nil {
+		t.Fatalf("ParseRDATA failed: %v", err)
+	}
+
+	cname, ok := result.(CNAMEData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want CNAMEData per RFC 1035 §3.3.1", result)
+	}
+
+	if cname.Name != "host.local" {
+		t.Errorf("CNAME name = %q, want %q per RFC 1035 §3.3.1", cname.Name, "host.local")
+	}
+
+	packed, err := PackRDATA(5, cname)
+	if err != nil {
+		t.Fatalf("PackRDATA failed: %v", err)
+	}
+	if !bytes.Equal(packed, rdata) {
+		t.Errorf("PackRDATA(ParseRDATA(rdata)) = %v, want %v", packed, rdata)
+	}
+}
+
+// TestParseRDATA_SOARecord validates that ParseRDATA correctly parses SOA
+// record RDATA per RFC 1035 §3.3.13.
+func TestParseRDATA_SOARecord(t *testing.T) {
+	rdata := []byte{
+		0x02, 'n', 's', 0x05, 'l', 'o', 'c', 'a', 'l', 0x00, // MNAME = ns.local
+		0x05, 'a', 'd', 'm', 'i', 'n', 0x05, 'l', 'o', 'c', 'a', 'l', 0x00, // RNAME = admin.local
+		0x00, 0x00, 0x00, 0x01, // Serial = 1
+		0x00, 0x00, 0x0E, 0x10, // Refresh = 3600
+		0x00, 0x00, 0x01, 0x2C, // Retry = 300
+		0x00, 0x09, 0x3A, 0x80, // Expire = 604800
+		0x00, 0x00, 0x00, 0x3C, // Minimum = 60
+	}
+
+	result, err := ParseRDATA(6, rdata) // TYPE = SOA (6)
+	if err != nil {
+		t.Fatalf("ParseRDATA failed: %v", err)
+	}
+
+	soa, ok := result.(SOAData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want SOAData per RFC 1035 §3.3.13", result)
+	}
+
+	if soa.MNAME != "ns.local" || soa.RNAME != "admin.local" {
+		t.Errorf("MNAME/RNAME = %q/%q, want ns.local/admin.local per RFC 1035 §3.3.13", soa.MNAME, soa.RNAME)
+	}
+	if soa.Serial != 1 || soa.Refresh != 3600 || soa.Retry != 300 || soa.Expire != 604800 || soa.Minimum != 60 {
+		t.Errorf("SOA fields = %+v, want Serial=1 Refresh=3600 Retry=300 Expire=604800 Minimum=60", soa)
+	}
+
+	packed, err := PackRDATA(6, soa)
+	if err != nil {
+		t.Fatalf("PackRDATA failed: %v", err)
+	}
+	if !bytes.Equal(packed, rdata) {
+		t.Errorf("PackRDATA(ParseRDATA(rdata)) = %v, want %v", packed, rdata)
+	}
+}
+
+// TestParseRDATA_HINFORecord validates that ParseRDATA correctly parses HINFO
+// record RDATA (CPU, OS character-strings) per RFC 1035 §3.3.2.
+func TestParseRDATA_HINFORecord(t *testing.T) {
+	rdata := []byte{
+		0x05, 'A', 'R', 'M', '6', '4',
+		0x05, 'L', 'i', 'n', 'u', 'x',
+	}
+
+	result, err := ParseRDATA(13, rdata) // TYPE = HINFO (13)
+	if err != nil {
+		t.Fatalf("ParseRDATA failed: %v", err)
+	}
+
+	hinfo, ok := result.(HINFOData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want HINFOData per RFC 1035 §3.3.2", result)
+	}
+
+	if hinfo.CPU != "ARM64" || hinfo.OS != "Linux" {
+		t.Errorf("HINFO = %+v, want CPU=ARM64 OS=Linux per RFC 1035 §3.3.2", hinfo)
+	}
+
+	packed, err := PackRDATA(13, hinfo)
+	if err != nil {
+		t.Fatalf("PackRDATA failed: %v", err)
+	}
+	if !bytes.Equal(packed, rdata) {
+		t.Errorf("PackRDATA(ParseRDATA(rdata)) = %v, want %v", packed, rdata)
+	}
+}
+
 // TestParseMessage_MalformedPacket validates that ParseMessage returns
 // WireFormatError for malformed packets per FR-011, FR-015.
 //
@@ -578,3 +1000,119 @@ func TestParseMessage_WithCompression(t *testing.T) {
 		t.Errorf("Answer NAME = %q, want %q (decompressed per RFC 1035 §4.1.4)", parsed.Answers[0].NAME, testLocalName)
 	}
 }
+
+// buildLenientTestMessage builds a 3-answer response: a valid "test.local" A
+// record, a PTR record whose RDATA holds a compression pointer into nowhere
+// (RDLENGTH and RDATA bytes are in-bounds, but the embedded name can't be
+// decompressed - a resync-capable failure), and a second valid "test.local"
+// A record after it.
+func buildLenientTestMessage(t *testing.T) []byte {
+	t.Helper()
+
+	header := []byte{
+		0x00, 0x00, // ID
+		0x80, 0x00, // Flags: QR=1, response
+		0x00, 0x01, // QDCOUNT = 1
+		0x00, 0x03, // ANCOUNT = 3
+		0x00, 0x00, // NSCOUNT
+		0x00, 0x00, // ARCOUNT
+	}
+	question := []byte{
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // QTYPE = A
+		0x00, 0x01, // QCLASS = IN
+	}
+	validA := []byte{
+		0xC0, 0x0C, // compression pointer to "test.local" at offset 12
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 100,
+	}
+	badPTR := []byte{
+		0xC0, 0x0C, // compression pointer to "test.local" at offset 12
+		0x00, 0x0C, // TYPE = PTR (12)
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x02, // RDLENGTH = 2
+		0xC0, 0xFF, // RDATA: compression pointer far past the message end
+	}
+
+	msg := make([]byte, 0)
+	msg = append(msg, header...)
+	msg = append(msg, question...)
+	msg = append(msg, validA...)
+	msg = append(msg, badPTR...)
+	msg = append(msg, validA...)
+	return msg
+}
+
+// TestParseMessageWithOptions_NotLenient validates that Lenient's zero value
+// behaves exactly like ParseMessage: one malformed answer fails the whole
+// message.
+func TestParseMessageWithOptions_NotLenient(t *testing.T) {
+	msg := buildLenientTestMessage(t)
+
+	_, err := ParseMessageWithOptions(msg, ParseOptions{})
+	if err == nil {
+		t.Fatal("ParseMessageWithOptions(Lenient: false) returned nil error, want the PTR decompression failure")
+	}
+
+	_, wantErr := ParseMessage(msg)
+	if wantErr == nil || err.Error() != wantErr.Error() {
+		t.Errorf("ParseMessageWithOptions(Lenient: false) error = %v, want the same error ParseMessage returns (%v)", err, wantErr)
+	}
+}
+
+// TestParseMessageWithOptions_Lenient validates that Lenient mode skips the
+// malformed PTR record (resuming via its RDLENGTH) and keeps both valid A
+// records, returning the skipped record's error via an *errors.MultiError.
+func TestParseMessageWithOptions_Lenient(t *testing.T) {
+	msg := buildLenientTestMessage(t)
+
+	parsed, err := ParseMessageWithOptions(msg, ParseOptions{Lenient: true})
+	if parsed == nil {
+		t.Fatalf("ParseMessageWithOptions(Lenient: true) returned a nil message, err = %v", err)
+	}
+
+	if len(parsed.Answers) != 2 {
+		t.Fatalf("len(Answers) = %d, want 2 (the malformed PTR skipped)", len(parsed.Answers))
+	}
+	for i, answer := range parsed.Answers {
+		if answer.TYPE != 1 {
+			t.Errorf("Answers[%d].TYPE = %d, want 1 (A)", i, answer.TYPE)
+		}
+	}
+
+	var multi *errors.MultiError
+	if !goerrors.As(err, &multi) {
+		t.Fatalf("err = %v (%T), want *errors.MultiError", err, err)
+	}
+	if len(multi.Errors) != 1 {
+		t.Fatalf("len(multi.Errors) = %d, want 1", len(multi.Errors))
+	}
+}
+
+// TestParseMessageWithOptions_MaxErrors validates that with opts.MaxErrors
+// left at zero, Lenient mode still collects errors (under the
+// DefaultMaxParseErrors cap) into a *errors.MultiError. The message holds a
+// single malformed record, so the cap itself is not reached here.
+func TestParseMessageWithOptions_MaxErrors(t *testing.T) {
+	msg := buildLenientTestMessage(t)
+
+	parsed, err := ParseMessageWithOptions(msg, ParseOptions{Lenient: true, MaxErrors: 0})
+	if parsed == nil {
+		t.Fatalf("ParseMessageWithOptions returned a nil message, err = %v", err)
+	}
+
+	var multi *errors.MultiError
+	if !goerrors.As(err, &multi) {
+		t.Fatalf("err = %v (%T), want *errors.MultiError", err, err)
+	}
+	if len(multi.Errors) > DefaultMaxParseErrors {
+		t.Errorf("len(multi.Errors) = %d, want at most DefaultMaxParseErrors (%d)", len(multi.Errors), DefaultMaxParseErrors)
+	}
+}
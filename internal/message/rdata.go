@@ -0,0 +1,247 @@
+package message
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// RData is the sealed interface implemented by every concrete type
+// ParseRDATA can return. Sealing it (via the unexported isRData method)
+// means every RData value on hand is one of this package's own decoded
+// types - never an unrelated caller-defined type - so a type switch over it
+// can be read as exhaustive against this file's cases plus dnssec.go's and
+// svcb.go's.
+//
+// RData deliberately carries no Type() method: the record's wire type is
+// already available alongside it rather than folded into it - Answer.TYPE
+// next to Answer.RDATA here, and querier.ResourceRecord.Type next to
+// ResourceRecord.Data at the public API boundary - so a caller that already
+// has an RData value can just read the sibling field instead of asking the
+// value to describe itself. That sibling-field shape also sidesteps a real
+// ambiguity a method can't: svcb.go's SVCBData decodes both SVCB (64) and
+// HTTPS (65) through the identical ParseSVCB path, so one SVCBData value has
+// no wire type of its own to report - only the record it came from does.
+type RData interface {
+	isRData()
+}
+
+// AData is the decoded RDATA of an A record per RFC 1035 §3.4.1.
+//
+// IP is a 4-byte (Is4() true) netip.Addr, not net.IP: it's a comparable
+// value type usable directly as a map key, and avoids the allocation
+// net.IP.Equal/String otherwise cost on the receive path. querier.AsA
+// shims this back to net.IP at the package's public API boundary.
+type AData struct {
+	IP netip.Addr
+}
+
+func (AData) isRData() {}
+
+// AAAAData is the decoded RDATA of an AAAA record per RFC 3596 §2.2.
+//
+// IP is a 16-byte (Is6() true, Is4In6() false) netip.Addr, not net.IP - see
+// AData's doc comment for why. A zone (RFC 4007 scope ID, e.g. for an
+// fe80::/10 link-local address) isn't carried here: RDATA has no field for
+// one, so a caller that needs it must attach it from the packet's source
+// address instead.
+type AAAAData struct {
+	IP netip.Addr
+}
+
+func (AAAAData) isRData() {}
+
+// PTRData is the decoded RDATA of a PTR record per RFC 1035 §3.3.12.
+type PTRData struct {
+	Name string
+}
+
+func (PTRData) isRData() {}
+
+// NSData is the decoded RDATA of an NS record per RFC 1035 §3.3.11.
+type NSData struct {
+	Name string
+}
+
+func (NSData) isRData() {}
+
+// CNAMEData is the decoded RDATA of a CNAME record per RFC 1035 §3.3.1.
+type CNAMEData struct {
+	Name string
+}
+
+func (CNAMEData) isRData() {}
+
+// SOAData is the decoded RDATA of an SOA record per RFC 1035 §3.3.13.
+type SOAData struct {
+	// MNAME is the domain name of the zone's primary name server.
+	MNAME string
+
+	// RNAME is the mailbox of the person responsible for the zone, encoded
+	// as a domain name per RFC 1035 §3.3.13 (the first "." separates the
+	// local part from the rest, in place of "@").
+	RNAME string
+
+	// Serial is the zone's version number.
+	Serial uint32
+
+	// Refresh is the interval, in seconds, before the zone should be refreshed.
+	Refresh uint32
+
+	// Retry is the interval, in seconds, before a failed refresh should be retried.
+	Retry uint32
+
+	// Expire is the interval, in seconds, after which the zone is no longer authoritative.
+	Expire uint32
+
+	// Minimum is the minimum TTL, in seconds, applied to negative responses per RFC 2308.
+	Minimum uint32
+}
+
+func (SOAData) isRData() {}
+
+// HINFOData is the decoded RDATA of a HINFO record per RFC 1035 §3.3.2.
+type HINFOData struct {
+	// CPU identifies the host's CPU type.
+	CPU string
+
+	// OS identifies the host's operating system.
+	OS string
+}
+
+func (HINFOData) isRData() {}
+
+// TXTData is the decoded RDATA of a TXT record per RFC 1035 §3.3.14.
+type TXTData struct {
+	// Entries holds each TXT string exactly as it appeared on the wire.
+	Entries []string
+
+	// KV holds Entries parsed as attribute=value pairs per RFC 6763 §6.4.
+	// An entry with no "=" is a boolean attribute per RFC 6763 §6.4 and maps
+	// to the empty string - the same as an attribute explicitly written
+	// "key=" with an empty value, since a plain map can't keep the two
+	// apart. If the same key appears more than once, only the first
+	// occurrence is kept - RFC 6763 §6.4: "a client MUST silently ignore...
+	// all but the first occurrence of that attribute." A caller that needs
+	// to tell a boolean attribute apart from an empty-value one should
+	// decode Entries with records.DecodeTXTEntries instead (exposed at the
+	// querier boundary as ResourceRecord.AsTXTRecords), which keeps that
+	// distinction in TXTRecord.Present.
+	KV map[string]string
+}
+
+func (TXTData) isRData() {}
+
+func (SRVData) isRData() {}
+
+// parseTXTKV parses entries into the key=value map TXTData.KV carries, per
+// RFC 6763 §6.4.
+func parseTXTKV(entries []string) map[string]string {
+	kv := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, _ := strings.Cut(entry, "=")
+		if _, exists := kv[key]; exists {
+			continue // RFC 6763 §6.4: keep only the first occurrence of a key
+		}
+		kv[key] = value
+	}
+	return kv
+}
+
+// AsA returns a's RDATA as AData, or ok=false if a isn't an A record or its
+// RDATA doesn't decode.
+func (a Answer) AsA() (AData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return AData{}, false
+	}
+	d, ok := data.(AData)
+	return d, ok
+}
+
+// AsAAAA returns a's RDATA as AAAAData, or ok=false if a isn't an AAAA
+// record or its RDATA doesn't decode.
+func (a Answer) AsAAAA() (AAAAData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return AAAAData{}, false
+	}
+	d, ok := data.(AAAAData)
+	return d, ok
+}
+
+// AsPTR returns a's RDATA as PTRData, or ok=false if a isn't a PTR record or
+// its RDATA doesn't decode.
+func (a Answer) AsPTR() (PTRData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return PTRData{}, false
+	}
+	d, ok := data.(PTRData)
+	return d, ok
+}
+
+// AsSRV returns a's RDATA as SRVData, or ok=false if a isn't an SRV record
+// or its RDATA doesn't decode.
+func (a Answer) AsSRV() (SRVData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return SRVData{}, false
+	}
+	d, ok := data.(SRVData)
+	return d, ok
+}
+
+// AsTXT returns a's RDATA as TXTData, or ok=false if a isn't a TXT record
+// or its RDATA doesn't decode.
+func (a Answer) AsTXT() (TXTData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return TXTData{}, false
+	}
+	d, ok := data.(TXTData)
+	return d, ok
+}
+
+// AsNS returns a's RDATA as NSData, or ok=false if a isn't an NS record or
+// its RDATA doesn't decode.
+func (a Answer) AsNS() (NSData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return NSData{}, false
+	}
+	d, ok := data.(NSData)
+	return d, ok
+}
+
+// AsCNAME returns a's RDATA as CNAMEData, or ok=false if a isn't a CNAME
+// record or its RDATA doesn't decode.
+func (a Answer) AsCNAME() (CNAMEData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return CNAMEData{}, false
+	}
+	d, ok := data.(CNAMEData)
+	return d, ok
+}
+
+// AsSOA returns a's RDATA as SOAData, or ok=false if a isn't an SOA record
+// or its RDATA doesn't decode.
+func (a Answer) AsSOA() (SOAData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return SOAData{}, false
+	}
+	d, ok := data.(SOAData)
+	return d, ok
+}
+
+// AsHINFO returns a's RDATA as HINFOData, or ok=false if a isn't a HINFO
+// record or its RDATA doesn't decode.
+func (a Answer) AsHINFO() (HINFOData, bool) {
+	data, err := ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return HINFOData{}, false
+	}
+	d, ok := data.(HINFOData)
+	return d, ok
+}
@@ -0,0 +1,52 @@
+package message
+
+import (
+	"net/netip"
+	"testing"
+)
+
+// TestAnswer_AsAccessors validates that Answer's AsA/AsAAAA/AsPTR/AsSRV/AsTXT
+// methods decode matching RDATA and report ok=false for every other record
+// type.
+func TestAnswer_AsAccessors(t *testing.T) {
+	aAnswer := Answer{TYPE: 1, RDATA: []byte{192, 168, 1, 1}}
+	ptrAnswer := Answer{TYPE: 12, RDATA: []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 5, 'l', 'o', 'c', 'a', 'l', 0}}
+
+	if data, ok := aAnswer.AsA(); !ok || data.IP != netip.MustParseAddr("192.168.1.1") {
+		t.Errorf("AsA() = %v, %v, want 192.168.1.1, true", data, ok)
+	}
+
+	if _, ok := aAnswer.AsAAAA(); ok {
+		t.Error("AsAAAA() on A record returned ok=true, want false")
+	}
+	if _, ok := aAnswer.AsPTR(); ok {
+		t.Error("AsPTR() on A record returned ok=true, want false")
+	}
+	if _, ok := aAnswer.AsSRV(); ok {
+		t.Error("AsSRV() on A record returned ok=true, want false")
+	}
+	if _, ok := aAnswer.AsTXT(); ok {
+		t.Error("AsTXT() on A record returned ok=true, want false")
+	}
+
+	if data, ok := ptrAnswer.AsPTR(); !ok || data.Name != "example.local" {
+		t.Errorf("AsPTR() = %v, %v, want example.local, true", data, ok)
+	}
+}
+
+// TestParseTXTKV validates TXT attribute=value parsing per RFC 6763 §6.4:
+// boolean attributes map to the empty string, and only the first occurrence
+// of a repeated key is kept.
+func TestParseTXTKV(t *testing.T) {
+	kv := parseTXTKV([]string{"txtvers=1", "path=/api", "nodoc", "path=/other"})
+
+	want := map[string]string{"txtvers": "1", "path": "/api", "nodoc": ""}
+	if len(kv) != len(want) {
+		t.Fatalf("parseTXTKV() = %v, want %v", kv, want)
+	}
+	for k, v := range want {
+		if kv[k] != v {
+			t.Errorf("parseTXTKV()[%q] = %q, want %q", k, kv[k], v)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// SvcParamKey identifies a SVCB/HTTPS service parameter per RFC 9460 §14.3.2.
+type SvcParamKey uint16
+
+// Well-known SvcParamKey values per RFC 9460 §14.3.2.
+const (
+	SvcParamMandatory     SvcParamKey = 0
+	SvcParamALPN          SvcParamKey = 1
+	SvcParamNoDefaultALPN SvcParamKey = 2
+	SvcParamPort          SvcParamKey = 3
+	SvcParamIPv4Hint      SvcParamKey = 4
+	SvcParamECH           SvcParamKey = 5
+	SvcParamIPv6Hint      SvcParamKey = 6
+)
+
+// SVCBData represents the parsed RDATA of an SVCB or HTTPS record per RFC 9460 §2.
+type SVCBData struct {
+	// Priority is SvcPriority: 0 means "alias mode", non-zero means "service mode".
+	Priority uint16
+
+	// Target is the SvcDomainName. Per RFC 9460 §2.2, this name MUST NOT be
+	// compressed on the wire, unlike most other DNS names.
+	Target string
+
+	// Params holds the parsed, ordered (ascending key) parameter list,
+	// undecoded. RFC 9460 §14.3.2 requires an unrecognized SvcParamKey to be
+	// preserved rather than rejected, so ParseSVCB never errors on one;
+	// Mandatory, ALPN, NoDefaultALPN, Port, IPv4Hint, ECH, and IPv6Hint decode
+	// the standard keys on demand for a caller that wants a specific one.
+	Params []SvcParam
+}
+
+func (*SVCBData) isRData() {}
+
+// SvcParam is a single SvcParamKey/SvcParamValue pair.
+type SvcParam struct {
+	Key   SvcParamKey
+	Value []byte
+}
+
+// EncodeSVCB serializes an SVCBData into SVCB/HTTPS RDATA per RFC 9460 §2.
+//
+// Per RFC 9460 §2.1, SvcParamKeys MUST appear in strictly ascending numeric
+// order with no duplicates; this is enforced here rather than left to callers.
+func EncodeSVCB(data *SVCBData) ([]byte, error) {
+	for i := 1; i < len(data.Params); i++ {
+		if data.Params[i].Key <= data.Params[i-1].Key {
+			return nil, &errors.ValidationError{
+				Field:   "Params",
+				Value:   data.Params[i].Key,
+				Message: "SvcParamKeys must be strictly ascending with no duplicates",
+				Code:    errors.CodeInvalidValue,
+			}
+		}
+	}
+
+	rdata := make([]byte, 2)
+	binary.BigEndian.PutUint16(rdata, data.Priority)
+
+	// RFC 9460 §2.2: SvcDomainName MUST NOT be compressed.
+	targetEncoded, err := EncodeName(data.Target)
+	if err != nil {
+		return nil, err
+	}
+	rdata = append(rdata, targetEncoded...)
+
+	for _, p := range data.Params {
+		keyBuf := make([]byte, 2)
+		binary.BigEndian.PutUint16(keyBuf, uint16(p.Key))
+		rdata = append(rdata, keyBuf...)
+
+		lenBuf := make([]byte, 2)
+		if len(p.Value) > 65535 {
+			return nil, &errors.ValidationError{Field: "Params", Message: "SvcParamValue exceeds 65535 bytes", Code: errors.CodeRDataOverflow}
+		}
+		binary.BigEndian.PutUint16(lenBuf, uint16(len(p.Value))) //nolint:gosec // G115: bounds checked above
+		rdata = append(rdata, lenBuf...)
+		rdata = append(rdata, p.Value...)
+	}
+
+	return rdata, nil
+}
+
+// ParseSVCB decodes SVCB/HTTPS RDATA per RFC 9460 §2.
+//
+// Per RFC 9460 §2.1, keys MUST appear in strictly ascending order with no
+// duplicates; malformed ordering is rejected as a WireFormatError rather than
+// silently accepted, since a self-consistent parser would otherwise miss
+// spec-violating packets a strict client would reject.
+func ParseSVCB(rdata []byte) (*SVCBData, error) {
+	if len(rdata) < 2 {
+		return nil, &errors.WireFormatError{
+			Operation: "parse SVCB record",
+			Offset:    0,
+			Message:   fmt.Sprintf("truncated SVCB record: %d bytes, expected at least 2", len(rdata)),
+			Code:      errors.CodeTruncated,
+		}
+	}
+
+	priority := binary.BigEndian.Uint16(rdata[0:2])
+
+	// RFC 9460 §2.2: Target is never compressed, but ParseName still accepts
+	// an optional trailing buffer; pass the whole RDATA so any pointer bytes
+	// (which MUST NOT appear) are still bounds-checked rather than panicking.
+	target, offset, err := ParseName(rdata, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []SvcParam
+	var lastKey SvcParamKey
+	first := true
+	for offset < len(rdata) {
+		if offset+4 > len(rdata) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse SVCB record",
+				Offset:    offset,
+				Message:   "truncated SvcParam key/length",
+				Code:      errors.CodeTruncated,
+			}
+		}
+		key := SvcParamKey(binary.BigEndian.Uint16(rdata[offset : offset+2]))
+		length := binary.BigEndian.Uint16(rdata[offset+2 : offset+4])
+		offset += 4
+
+		if !first && key <= lastKey {
+			return nil, &errors.WireFormatError{
+				Operation: "parse SVCB record",
+				Offset:    offset,
+				Message:   "SvcParamKeys must be strictly ascending with no duplicates",
+				Code:      errors.CodeInvalidValue,
+			}
+		}
+		first = false
+		lastKey = key
+
+		if offset+int(length) > len(rdata) {
+			return nil, &errors.WireFormatError{
+				Operation: "parse SVCB record",
+				Offset:    offset,
+				Message:   "truncated SvcParamValue",
+				Code:      errors.CodeTruncated,
+			}
+		}
+		value := make([]byte, length)
+		copy(value, rdata[offset:offset+int(length)])
+		params = append(params, SvcParam{Key: key, Value: value})
+		offset += int(length)
+	}
+
+	return &SVCBData{Priority: priority, Target: target, Params: params}, nil
+}
+
+// BuildALPNParam encodes the "alpn" SvcParamValue per RFC 9460 §7.1.1:
+// a sequence of length-prefixed protocol IDs.
+func BuildALPNParam(protocols []string) SvcParam {
+	var value []byte
+	for _, p := range protocols {
+		value = append(value, byte(len(p)))
+		value = append(value, []byte(p)...)
+	}
+	return SvcParam{Key: SvcParamALPN, Value: value}
+}
+
+// BuildPortParam encodes the "port" SvcParamValue per RFC 9460 §7.1.2.
+func BuildPortParam(port uint16) SvcParam {
+	value := make([]byte, 2)
+	binary.BigEndian.PutUint16(value, port)
+	return SvcParam{Key: SvcParamPort, Value: value}
+}
+
+// BuildIPv4HintParam encodes the "ipv4hint" SvcParamValue per RFC 9460 §7.3.
+func BuildIPv4HintParam(addrs []net.IP) SvcParam {
+	var value []byte
+	for _, ip := range addrs {
+		if v4 := ip.To4(); v4 != nil {
+			value = append(value, v4...)
+		}
+	}
+	return SvcParam{Key: SvcParamIPv4Hint, Value: value}
+}
+
+// BuildIPv6HintParam encodes the "ipv6hint" SvcParamValue per RFC 9460 §7.3.
+func BuildIPv6HintParam(addrs []net.IP) SvcParam {
+	var value []byte
+	for _, ip := range addrs {
+		if v6 := ip.To16(); v6 != nil && ip.To4() == nil {
+			value = append(value, v6...)
+		}
+	}
+	return SvcParam{Key: SvcParamIPv6Hint, Value: value}
+}
+
+// SortParams sorts params by key in place, as required for wire encoding
+// per RFC 9460 §2.1.
+func SortParams(params []SvcParam) {
+	sort.Slice(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+}
+
+// lookup returns the first param in d.Params with the given key, and whether
+// one was found. A caller asking for a key d didn't decode simply gets ok ==
+// false - RFC 9460 §14.3.2 requires unrecognized keys to be preserved rather
+// than rejected (see Params' doc comment), so ParseSVCB never errors on one;
+// these accessors are where a caller who does care about a specific
+// well-known key pays the decoding cost, on demand.
+func (d *SVCBData) lookup(key SvcParamKey) (SvcParam, bool) {
+	for _, p := range d.Params {
+		if p.Key == key {
+			return p, true
+		}
+	}
+	return SvcParam{}, false
+}
+
+// Mandatory decodes the "mandatory" SvcParamValue per RFC 9460 §8: the list
+// of SvcParamKeys a client MUST understand to use this record.
+func (d *SVCBData) Mandatory() ([]SvcParamKey, bool) {
+	p, ok := d.lookup(SvcParamMandatory)
+	if !ok || len(p.Value)%2 != 0 {
+		return nil, false
+	}
+	keys := make([]SvcParamKey, 0, len(p.Value)/2)
+	for i := 0; i+2 <= len(p.Value); i += 2 {
+		keys = append(keys, SvcParamKey(binary.BigEndian.Uint16(p.Value[i:i+2])))
+	}
+	return keys, true
+}
+
+// ALPN decodes the "alpn" SvcParamValue per RFC 9460 §7.1.1: a sequence of
+// length-prefixed protocol IDs, e.g. ["h2", "h3"].
+func (d *SVCBData) ALPN() ([]string, bool) {
+	p, ok := d.lookup(SvcParamALPN)
+	if !ok {
+		return nil, false
+	}
+	var protocols []string
+	for i := 0; i < len(p.Value); {
+		length := int(p.Value[i])
+		i++
+		if i+length > len(p.Value) {
+			return nil, false
+		}
+		protocols = append(protocols, string(p.Value[i:i+length]))
+		i += length
+	}
+	return protocols, true
+}
+
+// NoDefaultALPN reports whether the "no-default-alpn" SvcParamKey per
+// RFC 9460 §7.1.1 is present. Its SvcParamValue is always empty; presence
+// alone carries the meaning.
+func (d *SVCBData) NoDefaultALPN() bool {
+	_, ok := d.lookup(SvcParamNoDefaultALPN)
+	return ok
+}
+
+// Port decodes the "port" SvcParamValue per RFC 9460 §7.1.2.
+func (d *SVCBData) Port() (uint16, bool) {
+	p, ok := d.lookup(SvcParamPort)
+	if !ok || len(p.Value) != 2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(p.Value), true
+}
+
+// IPv4Hint decodes the "ipv4hint" SvcParamValue per RFC 9460 §7.3: a list of
+// 4-byte IPv4 addresses.
+func (d *SVCBData) IPv4Hint() ([]net.IP, bool) {
+	p, ok := d.lookup(SvcParamIPv4Hint)
+	if !ok || len(p.Value)%net.IPv4len != 0 {
+		return nil, false
+	}
+	var addrs []net.IP
+	for i := 0; i+net.IPv4len <= len(p.Value); i += net.IPv4len {
+		addrs = append(addrs, net.IP(append([]byte(nil), p.Value[i:i+net.IPv4len]...)))
+	}
+	return addrs, true
+}
+
+// IPv6Hint decodes the "ipv6hint" SvcParamValue per RFC 9460 §7.3: a list of
+// 16-byte IPv6 addresses.
+func (d *SVCBData) IPv6Hint() ([]net.IP, bool) {
+	p, ok := d.lookup(SvcParamIPv6Hint)
+	if !ok || len(p.Value)%net.IPv6len != 0 {
+		return nil, false
+	}
+	var addrs []net.IP
+	for i := 0; i+net.IPv6len <= len(p.Value); i += net.IPv6len {
+		addrs = append(addrs, net.IP(append([]byte(nil), p.Value[i:i+net.IPv6len]...)))
+	}
+	return addrs, true
+}
+
+// ECH returns the raw "ech" SvcParamValue per RFC 9460 §9 / draft-ietf-tls-esni:
+// an opaque ECHConfigList, left undecoded since parsing it is TLS-ECH's
+// concern, not this package's.
+func (d *SVCBData) ECH() ([]byte, bool) {
+	p, ok := d.lookup(SvcParamECH)
+	if !ok {
+		return nil, false
+	}
+	return p.Value, true
+}
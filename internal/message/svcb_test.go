@@ -0,0 +1,203 @@
+package message
+
+import (
+	"net"
+	"testing"
+)
+
+// TestSVCB_EncodeParseRoundTrip validates that ParseSVCB decodes what
+// EncodeSVCB produced for an HTTPS record carrying alpn=h2,h3 and
+// ipv4hint=192.0.2.1, per RFC 9460 §2/§7.
+func TestSVCB_EncodeParseRoundTrip(t *testing.T) {
+	want := &SVCBData{
+		Priority: 1,
+		Target:   "svc.local",
+		Params: []SvcParam{
+			BuildALPNParam([]string{"h2", "h3"}),
+			BuildPortParam(443),
+			BuildIPv4HintParam([]net.IP{net.ParseIP("192.0.2.1")}),
+		},
+	}
+
+	rdata, err := EncodeSVCB(want)
+	if err != nil {
+		t.Fatalf("EncodeSVCB() error = %v", err)
+	}
+
+	got, err := ParseSVCB(rdata)
+	if err != nil {
+		t.Fatalf("ParseSVCB() error = %v", err)
+	}
+
+	if got.Priority != want.Priority {
+		t.Errorf("Priority = %d, want %d", got.Priority, want.Priority)
+	}
+	if got.Target != want.Target {
+		t.Errorf("Target = %q, want %q", got.Target, want.Target)
+	}
+	if len(got.Params) != len(want.Params) {
+		t.Fatalf("len(Params) = %d, want %d", len(got.Params), len(want.Params))
+	}
+
+	// RFC 9460 §2.1: keys MUST come back in strictly ascending order.
+	for i := 1; i < len(got.Params); i++ {
+		if got.Params[i].Key <= got.Params[i-1].Key {
+			t.Errorf("Params[%d].Key = %d, want > Params[%d].Key = %d (ascending)", i, got.Params[i].Key, i-1, got.Params[i-1].Key)
+		}
+	}
+
+	alpn, ok := got.ALPN()
+	if !ok {
+		t.Fatal("ALPN() ok = false, want true")
+	}
+	if len(alpn) != 2 || alpn[0] != "h2" || alpn[1] != "h3" {
+		t.Errorf("ALPN() = %v, want [h2 h3]", alpn)
+	}
+
+	port, ok := got.Port()
+	if !ok || port != 443 {
+		t.Errorf("Port() = (%d, %v), want (443, true)", port, ok)
+	}
+
+	v4hints, ok := got.IPv4Hint()
+	if !ok || len(v4hints) != 1 || !v4hints[0].Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("IPv4Hint() = (%v, %v), want ([192.0.2.1], true)", v4hints, ok)
+	}
+
+	if _, ok := got.IPv6Hint(); ok {
+		t.Error("IPv6Hint() ok = true, want false (not set)")
+	}
+	if _, ok := got.Mandatory(); ok {
+		t.Error("Mandatory() ok = true, want false (not set)")
+	}
+	if got.NoDefaultALPN() {
+		t.Error("NoDefaultALPN() = true, want false (not set)")
+	}
+}
+
+// TestSVCB_IPv6HintAndMandatory validates decoding of ipv6hint and mandatory,
+// the two standard SvcParams TestSVCB_EncodeParseRoundTrip doesn't cover.
+func TestSVCB_IPv6HintAndMandatory(t *testing.T) {
+	mandatoryValue := []byte{0x00, 0x01, 0x00, 0x03} // alpn(1), port(3) - already ascending
+	want := &SVCBData{
+		Priority: 1,
+		Target:   "svc.local",
+		Params: []SvcParam{
+			{Key: SvcParamMandatory, Value: mandatoryValue},
+			BuildALPNParam([]string{"h2"}),
+			BuildPortParam(8443),
+			BuildIPv6HintParam([]net.IP{net.ParseIP("2001:db8::1")}),
+		},
+	}
+
+	rdata, err := EncodeSVCB(want)
+	if err != nil {
+		t.Fatalf("EncodeSVCB() error = %v", err)
+	}
+
+	got, err := ParseSVCB(rdata)
+	if err != nil {
+		t.Fatalf("ParseSVCB() error = %v", err)
+	}
+
+	mandatory, ok := got.Mandatory()
+	if !ok {
+		t.Fatal("Mandatory() ok = false, want true")
+	}
+	if len(mandatory) != 2 || mandatory[0] != SvcParamALPN || mandatory[1] != SvcParamPort {
+		t.Errorf("Mandatory() = %v, want [%d %d]", mandatory, SvcParamALPN, SvcParamPort)
+	}
+
+	v6hints, ok := got.IPv6Hint()
+	if !ok || len(v6hints) != 1 || !v6hints[0].Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("IPv6Hint() = (%v, %v), want ([2001:db8::1], true)", v6hints, ok)
+	}
+}
+
+// TestSVCB_AliasMode validates priority=0 ("alias mode" per RFC 9460 §2.4.2),
+// which carries a bare target and no SvcParams.
+func TestSVCB_AliasMode(t *testing.T) {
+	want := &SVCBData{Priority: 0, Target: "alias.local"}
+
+	rdata, err := EncodeSVCB(want)
+	if err != nil {
+		t.Fatalf("EncodeSVCB() error = %v", err)
+	}
+
+	got, err := ParseSVCB(rdata)
+	if err != nil {
+		t.Fatalf("ParseSVCB() error = %v", err)
+	}
+	if got.Priority != 0 {
+		t.Errorf("Priority = %d, want 0", got.Priority)
+	}
+	if got.Target != want.Target {
+		t.Errorf("Target = %q, want %q", got.Target, want.Target)
+	}
+	if len(got.Params) != 0 {
+		t.Errorf("len(Params) = %d, want 0", len(got.Params))
+	}
+}
+
+// TestSVCB_UnknownParamPreserved validates that a SvcParamKey with no
+// matching typed accessor round-trips as raw bytes rather than being
+// rejected, per RFC 9460 §14.3.2.
+func TestSVCB_UnknownParamPreserved(t *testing.T) {
+	const svcParamUnknown SvcParamKey = 7 // not one of this package's well-known keys
+	want := &SVCBData{
+		Priority: 1,
+		Target:   "svc.local",
+		Params:   []SvcParam{{Key: svcParamUnknown, Value: []byte{0xAA, 0xBB}}},
+	}
+
+	rdata, err := EncodeSVCB(want)
+	if err != nil {
+		t.Fatalf("EncodeSVCB() error = %v", err)
+	}
+
+	got, err := ParseSVCB(rdata)
+	if err != nil {
+		t.Fatalf("ParseSVCB() error = %v", err)
+	}
+	if len(got.Params) != 1 || got.Params[0].Key != svcParamUnknown || string(got.Params[0].Value) != "\xAA\xBB" {
+		t.Errorf("Params = %+v, want one raw {Key:%d Value:AABB}", got.Params, svcParamUnknown)
+	}
+}
+
+// TestSVCB_NonAscendingKeysRejected validates that ParseSVCB rejects a
+// SvcParam list that isn't in strictly ascending key order, per RFC 9460
+// §2.1.
+func TestSVCB_NonAscendingKeysRejected(t *testing.T) {
+	rdata := []byte{
+		0x00, 0x01, // Priority = 1
+		0x03, 's', 'v', 'c', 0x05, 'l', 'o', 'c', 'a', 'l', 0x00, // Target = "svc.local"
+		0x00, 0x03, 0x00, 0x02, 0x01, 0xBB, // port (3), length 2
+		0x00, 0x01, 0x00, 0x02, 0x01, 'h', // alpn (1) - out of order, comes after port (3)
+	}
+
+	if _, err := ParseSVCB(rdata); err == nil {
+		t.Error("ParseSVCB() error = nil, want error for non-ascending SvcParamKeys")
+	}
+}
+
+// TestParseRDATA_HTTPSRecord validates that ParseRDATA dispatches TYPE=65
+// (HTTPS) to ParseSVCB, the same decoder TYPE=64 (SVCB) uses per RFC 9460 §3.
+func TestParseRDATA_HTTPSRecord(t *testing.T) {
+	svcb := &SVCBData{Priority: 1, Target: "svc.local", Params: []SvcParam{BuildPortParam(443)}}
+	rdata, err := EncodeSVCB(svcb)
+	if err != nil {
+		t.Fatalf("EncodeSVCB() error = %v", err)
+	}
+
+	result, err := ParseRDATA(65, rdata) // TYPE = HTTPS (65)
+	if err != nil {
+		t.Fatalf("ParseRDATA() error = %v", err)
+	}
+	https, ok := result.(*SVCBData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want *SVCBData", result)
+	}
+	if https.Target != "svc.local" {
+		t.Errorf("Target = %q, want %q", https.Target, "svc.local")
+	}
+}
@@ -0,0 +1,311 @@
+package message
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// escapeLabelText escapes whitespace and backslash per RFC 1035 §5.1's "\DDD"
+// decimal-escape convention, so names that aren't valid bare tokens in
+// presentation format (e.g. DNS-SD instance names like
+// "My Printer._http._tcp.local") still round-trip as a single
+// whitespace-delimited field. Whitespace specifically must become a \DDD
+// escape rather than a bare "\ ": a backslash followed by a literal space is
+// still whitespace as far as strings.Fields is concerned.
+func escapeLabelText(name string) string {
+	var b strings.Builder
+	for _, r := range []byte(name) {
+		switch {
+		case r == ' ' || r == '\t':
+			fmt.Fprintf(&b, `\%03d`, r)
+		case r == '\\':
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(r)
+		}
+	}
+	return b.String()
+}
+
+// unescapeLabelText reverses escapeLabelText.
+func unescapeLabelText(name string) string {
+	var b strings.Builder
+	raw := []byte(name)
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			b.WriteByte(raw[i])
+			continue
+		}
+		// \DDD: three-digit decimal byte escape per RFC 1035 §5.1.
+		if i+3 < len(raw) && raw[i+1] >= '0' && raw[i+1] <= '9' && raw[i+2] >= '0' && raw[i+2] <= '9' && raw[i+3] >= '0' && raw[i+3] <= '9' {
+			if v, err := strconv.Atoi(string(raw[i+1 : i+4])); err == nil && v <= 255 {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		// \X: literal character X.
+		if i+1 < len(raw) {
+			b.WriteByte(raw[i+1])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// MarshalText renders a ResourceRecord in RFC 1035 §5.1 presentation format,
+// e.g. "test.local. 120 IN A 192.168.1.100".
+//
+// Record types without a dedicated renderer (anything beyond A, PTR, SRV,
+// TXT) fall back to the RFC 3597 §5 generic unknown-RR encoding
+// ("TYPEnnn \# length hexdata"), so every record the wire parser understands
+// round-trips through text even before a type gets bespoke support here.
+func (rr *ResourceRecord) MarshalText() ([]byte, error) {
+	var rdata string
+	switch rr.Type {
+	case protocol.RecordTypeA:
+		if len(rr.Data) != 4 {
+			return nil, &errors.ValidationError{Field: "Data", Message: "A record requires 4 bytes of RDATA", Code: errors.CodeInvalidValue}
+		}
+		rdata = fmt.Sprintf("%d.%d.%d.%d", rr.Data[0], rr.Data[1], rr.Data[2], rr.Data[3])
+	case protocol.RecordTypePTR:
+		name, _, err := ParseName(rr.Data, 0)
+		if err != nil {
+			return nil, err
+		}
+		rdata = escapeLabelText(name) + "."
+	case protocol.RecordTypeSRV:
+		if len(rr.Data) < 6 {
+			return nil, &errors.ValidationError{Field: "Data", Message: "SRV record requires at least 6 bytes of RDATA", Code: errors.CodeInvalidValue}
+		}
+		priority := binary.BigEndian.Uint16(rr.Data[0:2])
+		weight := binary.BigEndian.Uint16(rr.Data[2:4])
+		port := binary.BigEndian.Uint16(rr.Data[4:6])
+		target, _, err := ParseName(rr.Data, 6)
+		if err != nil {
+			return nil, err
+		}
+		rdata = fmt.Sprintf("%d %d %d %s.", priority, weight, port, escapeLabelText(target))
+	case protocol.RecordTypeTXT:
+		rdata = marshalTXTText(rr.Data)
+	default:
+		rdata = fmt.Sprintf("TYPE%d \\# %d %s", rr.Type, len(rr.Data), hex.EncodeToString(rr.Data))
+		return []byte(fmt.Sprintf("%s. %d IN %s", escapeLabelText(rr.Name), rr.TTL, rdata)), nil
+	}
+
+	return []byte(fmt.Sprintf("%s. %d IN %s %s", escapeLabelText(rr.Name), rr.TTL, rr.Type.String(), rdata)), nil
+}
+
+// marshalTXTText renders length-prefixed TXT strings as quoted, space-separated tokens.
+func marshalTXTText(data []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		i++
+		if i+n > len(data) {
+			n = len(data) - i
+		}
+		b.WriteByte('"')
+		b.WriteString(strings.ReplaceAll(string(data[i:i+n]), `"`, `\"`))
+		b.WriteByte('"')
+		i += n
+		if i < len(data) {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// UnmarshalText parses a single RFC 1035 §5.1 presentation-format record into rr.
+//
+// Expected form: "name. ttl IN TYPE rdata...", e.g.
+// "test.local. 120 IN A 192.168.1.100" or
+// "My Printer._http._tcp.local. 120 IN TXT \"path=/\"".
+//
+// Unknown types must use the RFC 3597 §5 generic form: "TYPEnnn \# length hex".
+func (rr *ResourceRecord) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) < 4 {
+		return &errors.ValidationError{Field: "text", Value: string(text), Message: "expected \"name ttl IN TYPE rdata\"", Code: errors.CodeInvalidValue}
+	}
+
+	name := unescapeLabelText(strings.TrimSuffix(fields[0], "."))
+	ttl, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return &errors.ValidationError{Field: "ttl", Value: fields[1], Message: "not a valid TTL", Code: errors.CodeInvalidValue}
+	}
+	if fields[2] != "IN" {
+		return &errors.ValidationError{Field: "class", Value: fields[2], Message: "only IN class is supported", Code: errors.CodeInvalidValue}
+	}
+
+	typeField := fields[3]
+	rdataFields := fields[4:]
+
+	if strings.HasPrefix(typeField, "TYPE") {
+		return unmarshalGenericText(rr, name, uint32(ttl), typeField, rdataFields)
+	}
+
+	rr.Name = name
+	rr.TTL = uint32(ttl)
+	rr.Class = protocol.ClassIN
+
+	switch typeField {
+	case "A":
+		rr.Type = protocol.RecordTypeA
+		return unmarshalAText(rr, rdataFields)
+	case "PTR":
+		rr.Type = protocol.RecordTypePTR
+		return unmarshalPTRText(rr, rdataFields)
+	case "SRV":
+		rr.Type = protocol.RecordTypeSRV
+		return unmarshalSRVText(rr, rdataFields)
+	case "TXT":
+		rr.Type = protocol.RecordTypeTXT
+		rr.Data = unmarshalTXTText(rdataFields)
+		return nil
+	default:
+		return &errors.ValidationError{Field: "type", Value: typeField, Message: "unsupported record type (use TYPEnnn \\# form for unknown types)", Code: errors.CodeUnsupportedRecordType}
+	}
+}
+
+func unmarshalAText(rr *ResourceRecord, fields []string) error {
+	if len(fields) != 1 {
+		return &errors.ValidationError{Field: "rdata", Message: "A record requires exactly one IPv4 address", Code: errors.CodeInvalidValue}
+	}
+	parts := strings.Split(fields[0], ".")
+	if len(parts) != 4 {
+		return &errors.ValidationError{Field: "rdata", Value: fields[0], Message: "malformed IPv4 address", Code: errors.CodeInvalidValue}
+	}
+	data := make([]byte, 4)
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 || v > 255 {
+			return &errors.ValidationError{Field: "rdata", Value: fields[0], Message: "malformed IPv4 address", Code: errors.CodeInvalidValue}
+		}
+		data[i] = byte(v)
+	}
+	rr.Data = data
+	return nil
+}
+
+func unmarshalPTRText(rr *ResourceRecord, fields []string) error {
+	if len(fields) != 1 {
+		return &errors.ValidationError{Field: "rdata", Message: "PTR record requires exactly one target name", Code: errors.CodeInvalidValue}
+	}
+	target := unescapeLabelText(strings.TrimSuffix(fields[0], "."))
+	encoded, err := EncodeOwnerName(target)
+	if err != nil {
+		return err
+	}
+	rr.Data = encoded
+	return nil
+}
+
+func unmarshalSRVText(rr *ResourceRecord, fields []string) error {
+	if len(fields) != 4 {
+		return &errors.ValidationError{Field: "rdata", Message: "SRV record requires \"priority weight port target\"", Code: errors.CodeInvalidValue}
+	}
+	priority, err1 := strconv.ParseUint(fields[0], 10, 16)
+	weight, err2 := strconv.ParseUint(fields[1], 10, 16)
+	port, err3 := strconv.ParseUint(fields[2], 10, 16)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return &errors.ValidationError{Field: "rdata", Message: "SRV priority/weight/port must be uint16", Code: errors.CodeInvalidValue}
+	}
+
+	data := make([]byte, 6)
+	binary.BigEndian.PutUint16(data[0:2], uint16(priority))
+	binary.BigEndian.PutUint16(data[2:4], uint16(weight))
+	binary.BigEndian.PutUint16(data[4:6], uint16(port))
+
+	target := unescapeLabelText(strings.TrimSuffix(fields[3], "."))
+	encoded, err := EncodeOwnerName(target)
+	if err != nil {
+		return err
+	}
+	rr.Data = append(data, encoded...)
+	return nil
+}
+
+// unmarshalTXTText reassembles quoted tokens (which may have been split on
+// whitespace by strings.Fields) into length-prefixed TXT strings.
+func unmarshalTXTText(fields []string) []byte {
+	joined := strings.Join(fields, " ")
+	var data []byte
+	var cur strings.Builder
+	inQuotes := false
+	escaped := false
+	flush := func() {
+		s := cur.String()
+		n := len(s)
+		if n > 255 {
+			n = 255
+		}
+		data = append(data, byte(n))
+		data = append(data, []byte(s)[:n]...)
+		cur.Reset()
+	}
+	started := false
+	for _, r := range joined {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			if inQuotes {
+				flush()
+				inQuotes = false
+				started = false
+			} else {
+				inQuotes = true
+				started = true
+			}
+		case inQuotes:
+			cur.WriteRune(r)
+		default:
+			// whitespace between quoted tokens - ignore
+		}
+	}
+	if started && inQuotes {
+		flush()
+	}
+	if len(data) == 0 {
+		return []byte{0x00}
+	}
+	return data
+}
+
+// unmarshalGenericText parses the RFC 3597 §5 generic unknown-RR form:
+// "TYPEnnn \# length hexdata".
+func unmarshalGenericText(rr *ResourceRecord, name string, ttl uint32, typeField string, fields []string) error {
+	typeNum, err := strconv.ParseUint(strings.TrimPrefix(typeField, "TYPE"), 10, 16)
+	if err != nil {
+		return &errors.ValidationError{Field: "type", Value: typeField, Message: "malformed TYPEnnn token", Code: errors.CodeInvalidValue}
+	}
+	if len(fields) < 2 || fields[0] != `\#` {
+		return &errors.ValidationError{Field: "rdata", Message: `generic RR requires "\# length hexdata"`, Code: errors.CodeInvalidValue}
+	}
+	length, err := strconv.Atoi(fields[1])
+	if err != nil || length < 0 {
+		return &errors.ValidationError{Field: "rdata", Value: fields[1], Message: "malformed generic RR length", Code: errors.CodeInvalidValue}
+	}
+	hexData := strings.Join(fields[2:], "")
+	data, err := hex.DecodeString(hexData)
+	if err != nil || len(data) != length {
+		return &errors.ValidationError{Field: "rdata", Message: "generic RR hexdata does not match declared length", Code: errors.CodeInvalidValue}
+	}
+
+	rr.Name = name
+	rr.TTL = ttl
+	rr.Class = protocol.ClassIN
+	rr.Type = protocol.RecordType(typeNum)
+	rr.Data = data
+	return nil
+}
@@ -0,0 +1,61 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+func TestResourceRecord_MarshalText_A(t *testing.T) {
+	rr := &ResourceRecord{Name: "test.local", Type: protocol.RecordTypeA, TTL: 120, Data: []byte{192, 168, 1, 100}}
+	text, err := rr.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText failed: %v", err)
+	}
+	want := "test.local. 120 IN A 192.168.1.100"
+	if string(text) != want {
+		t.Errorf("MarshalText() = %q, want %q", string(text), want)
+	}
+}
+
+func TestResourceRecord_UnmarshalText_RoundTrip(t *testing.T) {
+	cases := []string{
+		"test.local. 120 IN A 192.168.1.100",
+		`_http._tcp.local. 120 IN PTR My\032Printer._http._tcp.local.`,
+		`My\032Printer._http._tcp.local. 120 IN SRV 0 0 8080 myhost.local.`,
+	}
+
+	for _, in := range cases {
+		var rr ResourceRecord
+		if err := rr.UnmarshalText([]byte(in)); err != nil {
+			t.Fatalf("UnmarshalText(%q) failed: %v", in, err)
+		}
+		out, err := rr.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() after UnmarshalText(%q) failed: %v", in, err)
+		}
+		if string(out) != in {
+			t.Errorf("round trip mismatch: got %q, want %q", string(out), in)
+		}
+	}
+}
+
+func TestResourceRecord_UnmarshalText_GenericFallback(t *testing.T) {
+	var rr ResourceRecord
+	if err := rr.UnmarshalText([]byte(`test.local. 120 IN TYPE65 \# 4 00010203`)); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	if rr.Type != 65 {
+		t.Errorf("Type = %d, want 65", rr.Type)
+	}
+	if len(rr.Data) != 4 {
+		t.Errorf("Data length = %d, want 4", len(rr.Data))
+	}
+}
+
+func TestResourceRecord_UnmarshalText_Invalid(t *testing.T) {
+	var rr ResourceRecord
+	if err := rr.UnmarshalText([]byte("not a valid record")); err == nil {
+		t.Error("expected error for malformed input, got nil")
+	}
+}
@@ -0,0 +1,102 @@
+package message
+
+import (
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// ValidateResponse checks that resp is actually a response to query, guarding
+// against the transaction-ID confusion that mainstream DNS clients (and RFC
+// 1035 §4.1.1) explicitly defend against: a response arriving on the wrong
+// socket, or a late reply to an earlier query, must not be mistaken for the
+// answer to query.
+//
+// The correlation rule depends on unicast, because this package deliberately
+// does not follow RFC 6762 §18.1's ID convention symmetrically (see
+// buildQueryHeader and buildResponseHeader):
+//
+//   - unicast: query was sent point-to-point (LegacyResolver), so the peer is
+//     expected to echo query's ID per RFC 1035 §4.1.1. A mismatch returns a
+//     WireFormatError.
+//   - multicast (unicast=false): query was sent over mDNS, possibly with the
+//     QU bit requesting a unicast reply. Every Beacon responder (and any
+//     RFC-6762-compliant one) answers with ID=0 regardless of the query's ID,
+//     so resp.Header.ID itself is not useful for correlation; a non-zero ID
+//     instead signals resp isn't an mDNS response to this query at all.
+//     Correlation instead falls back to RFC 6762 §18.1's suggested tuple
+//     match: at least one answer (in any section) must match query's
+//     question by NAME/TYPE/CLASS, with the cache-flush/QU bit masked off.
+func ValidateResponse(query, resp *DNSMessage, unicast bool) error {
+	if unicast {
+		if resp.Header.ID != query.Header.ID {
+			return &errors.WireFormatError{
+				Operation: "validate response",
+				Message:   "ID mismatch",
+				Code:      errors.CodeInvalidValue,
+			}
+		}
+		return nil
+	}
+
+	if resp.Header.ID != 0 {
+		return &errors.WireFormatError{
+			Operation: "validate response",
+			Message:   "non-zero ID on mDNS response",
+			Code:      errors.CodeInvalidValue,
+		}
+	}
+
+	for _, q := range query.Questions {
+		if responseMatchesQuestion(resp, q) {
+			return nil
+		}
+	}
+	return &errors.WireFormatError{
+		Operation: "validate response",
+		Message:   "response does not match any query question",
+		Code:      errors.CodeInvalidValue,
+	}
+}
+
+// ValidateExtendedRCODE validates resp's RCODE per RFC 6762 §18.11, using the
+// full 12-bit extended RCODE (see DNSMessage.FullRCODE) when resp carries an
+// EDNS(0) OPT record rather than just the header's 4-bit field -
+// protocol.ValidateResponse's own RCODE check only sees that 4-bit field, so
+// it misses an extended RCODE like BADVERS (16) whose low nibble is zero.
+//
+// When resp's RCODE is non-zero and its OPT record carries an Extended DNS
+// Error (RFC 8914) option, that is returned instead of the bare
+// protocol.ValidationError, giving callers the INFO-CODE/EXTRA-TEXT detail.
+func ValidateExtendedRCODE(resp *DNSMessage) error {
+	err := protocol.ValidateRCODE(resp.FullRCODE())
+	if err == nil {
+		return err
+	}
+	if resp.OPT != nil {
+		if ede := resp.OPT.ExtendedError(); ede != nil {
+			return ede
+		}
+	}
+	return err
+}
+
+// responseMatchesQuestion reports whether any record in resp's Answer,
+// Authority, or Additional sections matches q by NAME/TYPE/CLASS, per RFC
+// 6762 §18.1's question-tuple correlation. The cache-flush bit (top bit of
+// CLASS) is masked off since it has no bearing on identity.
+func responseMatchesQuestion(resp *DNSMessage, q Question) bool {
+	for _, sections := range [][]Answer{resp.Answers, resp.Authorities, resp.Additionals} {
+		for _, a := range sections {
+			if a.NAME != q.QNAME || a.RRClass() != q.RRClass() {
+				continue
+			}
+			// A record of the queried type, or an NSEC asserting that type
+			// (among others) doesn't exist at this name per RFC 6762 §6.1 -
+			// a negative answer is still an answer to the question asked.
+			if a.TYPE == q.QTYPE || a.TYPE == uint16(protocol.RecordTypeNSEC) {
+				return true
+			}
+		}
+	}
+	return false
+}
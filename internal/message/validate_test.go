@@ -0,0 +1,166 @@
+package message
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+func TestValidateResponse_Unicast(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *DNSMessage
+		resp    *DNSMessage
+		wantErr bool
+	}{
+		{
+			name:  "matching ID per RFC 1035 §4.1.1",
+			query: &DNSMessage{Header: DNSHeader{ID: 0x1234}},
+			resp:  &DNSMessage{Header: DNSHeader{ID: 0x1234}},
+		},
+		{
+			name:    "mismatched ID",
+			query:   &DNSMessage{Header: DNSHeader{ID: 0x1234}},
+			resp:    &DNSMessage{Header: DNSHeader{ID: 0x5678}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResponse(tt.query, tt.resp, true)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ValidateResponse() = nil, want error")
+				}
+				if _, ok := err.(*errors.WireFormatError); !ok {
+					t.Errorf("ValidateResponse() error type = %T, want *errors.WireFormatError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateResponse() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateResponse_Multicast(t *testing.T) {
+	query := &DNSMessage{
+		Header:    DNSHeader{ID: 0x9999}, // M1 uses a random query ID; must not be used for correlation.
+		Questions: []Question{{QNAME: "_http._tcp.local", QTYPE: 12, QCLASS: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		resp    *DNSMessage
+		wantErr bool
+	}{
+		{
+			name: "ID=0 and matching question tuple",
+			resp: &DNSMessage{
+				Header:  DNSHeader{ID: 0},
+				Answers: []Answer{{NAME: "_http._tcp.local", TYPE: 12, CLASS: 0x8001}}, // cache-flush bit set
+			},
+		},
+		{
+			name: "ID=0 and matching tuple in Additionals",
+			resp: &DNSMessage{
+				Header:      DNSHeader{ID: 0},
+				Additionals: []Answer{{NAME: "_http._tcp.local", TYPE: 12, CLASS: 1}},
+			},
+		},
+		{
+			name: "non-zero ID per RFC 6762 §18.1",
+			resp: &DNSMessage{
+				Header:  DNSHeader{ID: 42},
+				Answers: []Answer{{NAME: "_http._tcp.local", TYPE: 12, CLASS: 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ID=0 but no answer matches the question",
+			resp: &DNSMessage{
+				Header:  DNSHeader{ID: 0},
+				Answers: []Answer{{NAME: "other.local", TYPE: 1, CLASS: 1}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResponse(query, tt.resp, false)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ValidateResponse() = nil, want error")
+				}
+				if _, ok := err.(*errors.WireFormatError); !ok {
+					t.Errorf("ValidateResponse() error type = %T, want *errors.WireFormatError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateResponse() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// TestValidateExtendedRCODE validates that ValidateExtendedRCODE checks the
+// full 12-bit RCODE (not just the header's 4-bit field) and surfaces an
+// Extended DNS Error option when one is present.
+func TestValidateExtendedRCODE(t *testing.T) {
+	t.Run("zero RCODE, no OPT", func(t *testing.T) {
+		resp := &DNSMessage{Header: DNSHeader{Flags: 0x0000}}
+		if err := ValidateExtendedRCODE(resp); err != nil {
+			t.Errorf("ValidateExtendedRCODE() = %v, want nil", err)
+		}
+	})
+
+	t.Run("BADVERS: zero low nibble but non-zero extended RCODE", func(t *testing.T) {
+		resp := &DNSMessage{
+			Header: DNSHeader{Flags: 0x0000},
+			OPT:    &OPTRecord{ExtendedRCODE: 1},
+		}
+		if err := ValidateExtendedRCODE(resp); err == nil {
+			t.Error("ValidateExtendedRCODE() = nil, want error for BADVERS")
+		}
+	})
+
+	t.Run("non-zero RCODE with EDE option surfaces ExtendedDNSError", func(t *testing.T) {
+		edeData := []byte{0x00, 0x01} // INFO-CODE = 1
+		edeData = append(edeData, "unsupported algorithm"...)
+		resp := &DNSMessage{
+			Header: DNSHeader{Flags: 0x0002}, // RCODE = 2 (ServFail)
+			OPT: &OPTRecord{
+				Options: []EDNSOption{{Code: OptionCodeEDE, Data: edeData}},
+			},
+		}
+
+		err := ValidateExtendedRCODE(resp)
+		if err == nil {
+			t.Fatal("ValidateExtendedRCODE() = nil, want error")
+		}
+		var ede *errors.ExtendedDNSError
+		if !goerrors.As(err, &ede) {
+			t.Fatalf("ValidateExtendedRCODE() error type = %T, want *errors.ExtendedDNSError", err)
+		}
+		if ede.InfoCode != 1 {
+			t.Errorf("InfoCode = %d, want 1", ede.InfoCode)
+		}
+	})
+
+	t.Run("non-zero RCODE without EDE option returns plain ValidationError", func(t *testing.T) {
+		resp := &DNSMessage{Header: DNSHeader{Flags: 0x0002}}
+		err := ValidateExtendedRCODE(resp)
+		if err == nil {
+			t.Fatal("ValidateExtendedRCODE() = nil, want error")
+		}
+		var ede *errors.ExtendedDNSError
+		if goerrors.As(err, &ede) {
+			t.Error("ValidateExtendedRCODE() returned *errors.ExtendedDNSError, want plain ValidationError")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+// Package metrics decorates the beacon/metrics.Metrics interface with
+// namespacing and constant labels, so multiple Responder/Querier instances
+// sharing one process's metrics backend (e.g. one Responder per network
+// interface) produce distinguishable series instead of colliding on the
+// same counter/histogram names.
+//
+// This stays on top of metrics.Metrics rather than depending on a specific
+// backend's types (e.g. prometheus.Registerer/prometheus.Collector) for the
+// same reason metrics.Metrics itself does - see that package's doc comment:
+// Beacon never imports a metrics client library directly, so an application
+// can wire in Prometheus, OpenTelemetry, or anything else. metrics/prom is
+// the ready-made Prometheus exposition adapter this composes with.
+package metrics
+
+import "github.com/joshuafuller/beacon/metrics"
+
+// Metrics decorates an underlying metrics.Metrics sink: every IncCounter/
+// ObserveHistogram call has its name prefixed with Namespace (if set, via
+// WithNamespace) and ConstLabels merged into its labels (via
+// WithConstLabels) before being forwarded to sink. It implements
+// metrics.Metrics itself, so existing call sites (responder, querier,
+// internal/state, internal/responder) need no changes - only the sink a
+// Responder/Querier/Machine is constructed with changes, from a bare
+// metrics.Metrics to one wrapped by New.
+type Metrics struct {
+	sink        metrics.Metrics
+	namespace   string
+	constLabels map[string]string
+}
+
+var _ metrics.Metrics = (*Metrics)(nil)
+
+// Option configures a Metrics decorator.
+type Option func(*Metrics)
+
+// WithNamespace prefixes every metric name this Metrics reports with
+// namespace + "_", e.g. WithNamespace("eth0") turns
+// "beacon_responder_known_answer_suppressed_total" into
+// "eth0_beacon_responder_known_answer_suppressed_total".
+func WithNamespace(namespace string) Option {
+	return func(m *Metrics) { m.namespace = namespace }
+}
+
+// WithConstLabels merges labels into every IncCounter/ObserveHistogram
+// call this Metrics reports, underneath whatever labels the call site
+// itself passes - a call-site label wins on key collision, since it's more
+// specific than a value fixed for the whole Metrics instance.
+func WithConstLabels(labels map[string]string) Option {
+	return func(m *Metrics) { m.constLabels = labels }
+}
+
+// New builds a Metrics decorator wrapping sink.
+func New(sink metrics.Metrics, opts ...Option) *Metrics {
+	m := &Metrics{sink: sink}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewNoop builds a Metrics decorator wrapping metrics.NoOp{}, so a test (or
+// a caller just trying out WithNamespace/WithConstLabels) can use the same
+// decorator without standing up a real metrics backend.
+func NewNoop(opts ...Option) *Metrics {
+	return New(metrics.NoOp{}, opts...)
+}
+
+// IncCounter implements metrics.Metrics.
+func (m *Metrics) IncCounter(name string, labels map[string]string) {
+	m.sink.IncCounter(m.qualify(name), m.mergeLabels(labels))
+}
+
+// ObserveHistogram implements metrics.Metrics.
+func (m *Metrics) ObserveHistogram(name string, v float64, labels map[string]string) {
+	m.sink.ObserveHistogram(m.qualify(name), v, m.mergeLabels(labels))
+}
+
+func (m *Metrics) qualify(name string) string {
+	if m.namespace == "" {
+		return name
+	}
+	return m.namespace + "_" + name
+}
+
+func (m *Metrics) mergeLabels(labels map[string]string) map[string]string {
+	if len(m.constLabels) == 0 {
+		return labels
+	}
+
+	merged := make(map[string]string, len(labels)+len(m.constLabels))
+	for k, v := range m.constLabels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,279 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+)
+
+// NameRule is one entry in an AllowList's interface-name rule set. An
+// interface whose name matches Pattern (a regular expression, anchored to
+// match the whole name) is allow-listed if Allow is true, deny-listed
+// otherwise. When multiple rules match the same name, the last one in the
+// list wins.
+type NameRule struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Allow   bool   `json:"allow" yaml:"allow"`
+}
+
+// CIDRRule is one entry in an AllowList's CIDR rule set. An address falling
+// inside CIDR is allow-listed if Allow is true, deny-listed otherwise. When
+// multiple CIDRs contain the same address, the most specific (longest
+// prefix) one wins, regardless of list order - this lets an operator allow a
+// broad range and carve out a deny exception, e.g. allow 10.0.0.0/8 but deny
+// 10.0.0.0/24.
+type CIDRRule struct {
+	CIDR  string `json:"cidr" yaml:"cidr"`
+	Allow bool   `json:"allow" yaml:"allow"`
+}
+
+// AllowListConfig is the document form an AllowList is compiled from, e.g.
+// via YAML/JSON per ConfigWatcher's loadConfigFile pattern.
+type AllowListConfig struct {
+	Names []NameRule `json:"names,omitempty" yaml:"names,omitempty"`
+	CIDRs []CIDRRule `json:"cidrs,omitempty" yaml:"cidrs,omitempty"`
+}
+
+// compiledNameRule is a NameRule with its Pattern pre-compiled, so matching
+// an interface name doesn't recompile a regex on every call.
+type compiledNameRule struct {
+	re    *regexp.Regexp
+	allow bool
+}
+
+// AllowList is a compiled interface filter combining interface-name rules
+// and IPv4/IPv6 CIDR rules, modeled on Nebula's allow_list: an interface
+// must pass both the name check and the address check to be allowed.
+//
+// Unlike the hardcoded isVPN/isDocker prefix lists DefaultInterfaces uses,
+// an AllowList is built from operator-supplied configuration, so hosts with
+// unusual bridged or virtual NIC naming don't require patching this package.
+type AllowList struct {
+	names            []compiledNameRule
+	defaultNameAllow bool
+	cidr4            *cidrTrie
+	cidr6            *cidrTrie
+}
+
+// NewAllowList compiles cfg into an AllowList. An empty AllowListConfig
+// compiles to an AllowList that allows everything, since no rules were
+// configured to restrict anything.
+func NewAllowList(cfg AllowListConfig) (*AllowList, error) {
+	al := &AllowList{
+		cidr4: newCIDRTrie(),
+		cidr6: newCIDRTrie(),
+	}
+
+	al.names = make([]compiledNameRule, 0, len(cfg.Names))
+	allCount, denyCount := 0, 0
+	for _, rule := range cfg.Names {
+		re, err := regexp.Compile("^(?:" + rule.Pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid interface name pattern %q: %w", rule.Pattern, err)
+		}
+		al.names = append(al.names, compiledNameRule{re: re, allow: rule.Allow})
+		if rule.Allow {
+			allCount++
+		} else {
+			denyCount++
+		}
+	}
+
+	// An unmatched name defaults to the opposite of a pure allow-list or
+	// pure deny-list (allow-listing utun0 implies everything else is
+	// denied; deny-listing utun0 implies everything else is allowed). A
+	// mixed list has no unambiguous default, so fall back to deny.
+	switch {
+	case denyCount == 0:
+		al.defaultNameAllow = len(al.names) == 0
+	case allCount == 0:
+		al.defaultNameAllow = true
+	default:
+		al.defaultNameAllow = false
+	}
+
+	for _, rule := range cfg.CIDRs {
+		ip, ipNet, err := net.ParseCIDR(rule.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", rule.CIDR, err)
+		}
+		ones, _ := ipNet.Mask.Size()
+
+		if ip4 := ip.To4(); ip4 != nil {
+			al.cidr4.insert(ip4, ones, rule.Allow)
+		} else {
+			al.cidr6.insert(ip.To16(), ones, rule.Allow)
+		}
+	}
+
+	return al, nil
+}
+
+// Allows reports whether iface passes both the name and CIDR checks. A nil
+// AllowList allows everything, so callers can pass one through unchecked.
+func (al *AllowList) Allows(iface net.Interface) bool {
+	if al == nil {
+		return true
+	}
+
+	if !al.allowsName(iface.Name) {
+		return false
+	}
+
+	return al.allowsAddrs(iface)
+}
+
+// allowsName reports whether name is permitted by the compiled name rules,
+// last matching rule wins per AllowList's doc comment.
+func (al *AllowList) allowsName(name string) bool {
+	result := al.defaultNameAllow
+	for _, rule := range al.names {
+		if rule.re.MatchString(name) {
+			result = rule.allow
+		}
+	}
+	return result
+}
+
+// allowsAddrs reports whether at least one of iface's addresses falls inside
+// an allowed CIDR and not inside a more specific deny CIDR. An AllowList
+// with no CIDR rules configured allows every interface, since CIDR
+// filtering is opt-in.
+func (al *AllowList) allowsAddrs(iface net.Interface) bool {
+	if al.cidr4.empty() && al.cidr6.empty() {
+		return true
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return false
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			if allow, matched := al.cidr4.contains(ip4); matched && allow {
+				return true
+			}
+			continue
+		}
+
+		if allow, matched := al.cidr6.contains(ipNet.IP.To16()); matched && allow {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilteredInterfaces returns network interfaces suitable for mDNS multicast,
+// the same UP/MULTICAST/non-loopback baseline as DefaultInterfaces, filtered
+// through al instead of the hardcoded isVPN/isDocker prefix lists.
+//
+// A nil al behaves like DefaultInterfaces() with no exclusions beyond the
+// UP/MULTICAST/loopback baseline, since AllowList.Allows(iface) on a nil
+// receiver allows everything.
+func FilteredInterfaces(al *AllowList) ([]net.Interface, error) {
+	allIfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]net.Interface, 0, len(allIfaces))
+	for _, iface := range allIfaces {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		if !al.Allows(iface) {
+			continue
+		}
+
+		filtered = append(filtered, iface)
+	}
+
+	return filtered, nil
+}
+
+// cidrTrieNode is one node of a cidrTrie: a binary trie keyed by IP address
+// bits, one level per bit of the inserted prefix's length.
+type cidrTrieNode struct {
+	children [2]*cidrTrieNode
+	hasValue bool
+	allow    bool
+}
+
+// cidrTrie is a longest-prefix-match radix tree over IP address bits, one
+// per address family (cidrTrie for IPv4 addresses is 32 bits deep, IPv6 128).
+// Contains returns the value of the most specific (deepest) prefix on the
+// path from the root to addr, so a narrower CIDR always overrides a broader
+// one that also contains addr, independent of insertion order.
+type cidrTrie struct {
+	root     *cidrTrieNode
+	inserted int
+}
+
+func newCIDRTrie() *cidrTrie {
+	return &cidrTrie{root: &cidrTrieNode{}}
+}
+
+func (t *cidrTrie) empty() bool {
+	return t.inserted == 0
+}
+
+// insert records that the first ones bits of ip are allow-listed (or
+// deny-listed) per allow.
+func (t *cidrTrie) insert(ip net.IP, ones int, allow bool) {
+	node := t.root
+	for i := 0; i < ones; i++ {
+		bit := bitAt(ip, i)
+		if node.children[bit] == nil {
+			node.children[bit] = &cidrTrieNode{}
+		}
+		node = node.children[bit]
+	}
+	node.hasValue = true
+	node.allow = allow
+	t.inserted++
+}
+
+// contains walks ip's bits from the root, returning the allow value of the
+// deepest node visited that has a value set, and whether any such node was
+// found at all.
+func (t *cidrTrie) contains(ip net.IP) (allow bool, matched bool) {
+	node := t.root
+	if node.hasValue {
+		allow, matched = node.allow, true
+	}
+
+	bits := len(ip) * 8
+	for i := 0; i < bits; i++ {
+		next := node.children[bitAt(ip, i)]
+		if next == nil {
+			break
+		}
+		node = next
+		if node.hasValue {
+			allow, matched = node.allow, true
+		}
+	}
+
+	return allow, matched
+}
+
+// bitAt returns bit i of ip (0 = most significant bit of the first byte).
+func bitAt(ip net.IP, i int) int {
+	byteIdx := i / 8
+	bitIdx := 7 - (i % 8)
+	return int(ip[byteIdx]>>bitIdx) & 1
+}
@@ -0,0 +1,239 @@
+package network
+
+import (
+	"net"
+	"testing"
+)
+
+// TestAllowList_Nil verifies that a nil *AllowList allows everything, so
+// FilteredInterfaces(nil) behaves like an unrestricted interface list.
+func TestAllowList_Nil(t *testing.T) {
+	var al *AllowList
+	if !al.Allows(net.Interface{Name: "utun0"}) {
+		t.Error("nil AllowList should allow every interface")
+	}
+}
+
+// TestAllowList_Empty verifies that an AllowList compiled from an empty
+// config allows everything, since no rules were configured to restrict
+// anything.
+func TestAllowList_Empty(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+	if !al.Allows(net.Interface{Name: "utun0"}) {
+		t.Error("empty AllowList should allow every interface")
+	}
+}
+
+// TestAllowList_NameRules_LastMatchWins verifies that when multiple name
+// rules match the same interface, the last one in the list decides.
+func TestAllowList_NameRules_LastMatchWins(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{
+		Names: []NameRule{
+			{Pattern: "eth.*", Allow: true},
+			{Pattern: "eth1", Allow: false},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+
+	if !al.allowsName("eth0") {
+		t.Error("eth0 should be allowed (only the broad rule matches)")
+	}
+	if al.allowsName("eth1") {
+		t.Error("eth1 should be denied (the later, more specific rule matches last)")
+	}
+}
+
+// TestAllowList_NameRules_DefaultAllow verifies that a pure deny-list
+// defaults unmatched names to allow, per the package's documented policy.
+func TestAllowList_NameRules_DefaultAllow(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{
+		Names: []NameRule{{Pattern: "docker0", Allow: false}},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+
+	if !al.allowsName("eth0") {
+		t.Error("eth0 should default-allow under a pure deny-list")
+	}
+	if al.allowsName("docker0") {
+		t.Error("docker0 should be denied")
+	}
+}
+
+// TestAllowList_NameRules_DefaultDeny verifies that a pure allow-list
+// defaults unmatched names to deny.
+func TestAllowList_NameRules_DefaultDeny(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{
+		Names: []NameRule{{Pattern: "eth0", Allow: true}},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+
+	if al.allowsName("wlan0") {
+		t.Error("wlan0 should default-deny under a pure allow-list")
+	}
+	if !al.allowsName("eth0") {
+		t.Error("eth0 should be allowed")
+	}
+}
+
+// TestAllowList_NameRules_InvalidPattern verifies that an unparsable regex
+// is rejected at construction time rather than failing silently at match
+// time.
+func TestAllowList_NameRules_InvalidPattern(t *testing.T) {
+	_, err := NewAllowList(AllowListConfig{
+		Names: []NameRule{{Pattern: "(", Allow: true}},
+	})
+	if err == nil {
+		t.Error("NewAllowList() should reject an invalid regex pattern")
+	}
+}
+
+// TestAllowList_CIDR_MoreSpecificDenyWins verifies the longest-prefix-match
+// requirement from the request: allow a broad range, deny a narrower range
+// within it, and the narrower rule should win.
+func TestAllowList_CIDR_MoreSpecificDenyWins(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{
+		CIDRs: []CIDRRule{
+			{CIDR: "10.0.0.0/8", Allow: true},
+			{CIDR: "10.0.0.0/24", Allow: false},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+
+	allowed, matched := al.cidr4.contains(net.ParseIP("10.1.2.3").To4())
+	if !matched || !allowed {
+		t.Errorf("10.1.2.3 should match the broad allow, got allowed=%v matched=%v", allowed, matched)
+	}
+
+	allowed, matched = al.cidr4.contains(net.ParseIP("10.0.0.5").To4())
+	if !matched || allowed {
+		t.Errorf("10.0.0.5 should match the narrower deny, got allowed=%v matched=%v", allowed, matched)
+	}
+}
+
+// TestAllowList_CIDR_InsertionOrderIndependent verifies that the deny-first,
+// allow-second insertion order produces the same longest-prefix result as
+// TestAllowList_CIDR_MoreSpecificDenyWins, since specificity - not order -
+// should decide.
+func TestAllowList_CIDR_InsertionOrderIndependent(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{
+		CIDRs: []CIDRRule{
+			{CIDR: "10.0.0.0/24", Allow: false},
+			{CIDR: "10.0.0.0/8", Allow: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+
+	allowed, matched := al.cidr4.contains(net.ParseIP("10.0.0.5").To4())
+	if !matched || allowed {
+		t.Errorf("10.0.0.5 should still match the narrower deny regardless of insertion order, got allowed=%v matched=%v", allowed, matched)
+	}
+}
+
+// TestAllowList_CIDR_InvalidCIDR verifies that a malformed CIDR string is
+// rejected at construction time.
+func TestAllowList_CIDR_InvalidCIDR(t *testing.T) {
+	_, err := NewAllowList(AllowListConfig{
+		CIDRs: []CIDRRule{{CIDR: "not-a-cidr", Allow: true}},
+	})
+	if err == nil {
+		t.Error("NewAllowList() should reject an invalid CIDR")
+	}
+}
+
+// TestAllowList_CIDR_IPv6 verifies the IPv6 trie is populated and queried
+// independently of the IPv4 one.
+func TestAllowList_CIDR_IPv6(t *testing.T) {
+	al, err := NewAllowList(AllowListConfig{
+		CIDRs: []CIDRRule{{CIDR: "fd00::/8", Allow: true}},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+
+	allowed, matched := al.cidr6.contains(net.ParseIP("fd00::1").To16())
+	if !matched || !allowed {
+		t.Errorf("fd00::1 should match the allowed IPv6 range, got allowed=%v matched=%v", allowed, matched)
+	}
+
+	_, matched = al.cidr6.contains(net.ParseIP("2001:db8::1").To16())
+	if matched {
+		t.Error("2001:db8::1 should not match any configured IPv6 CIDR")
+	}
+}
+
+// TestAllowList_Allows_RequiresBothNameAndAddr verifies Allows() combines
+// the name check and the address check, using a loopback interface (which
+// always has 127.0.0.1/::1 addresses) as a deterministic, environment
+// independent test target.
+func TestAllowList_Allows_RequiresBothNameAndAddr(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("net.Interfaces() failed: %v", err)
+	}
+
+	var loopback *net.Interface
+	for i := range ifaces {
+		if ifaces[i].Flags&net.FlagLoopback != 0 {
+			loopback = &ifaces[i]
+			break
+		}
+	}
+	if loopback == nil {
+		t.Skip("no loopback interface available in this environment")
+	}
+
+	denyByName, err := NewAllowList(AllowListConfig{
+		Names: []NameRule{{Pattern: loopback.Name, Allow: false}},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+	if denyByName.Allows(*loopback) {
+		t.Errorf("%s should be denied by its name rule", loopback.Name)
+	}
+
+	denyByAddr, err := NewAllowList(AllowListConfig{
+		CIDRs: []CIDRRule{{CIDR: "127.0.0.0/8", Allow: false}},
+	})
+	if err != nil {
+		t.Fatalf("NewAllowList() failed: %v", err)
+	}
+	if denyByAddr.Allows(*loopback) {
+		t.Errorf("%s should be denied by its address rule", loopback.Name)
+	}
+}
+
+// TestFilteredInterfaces_NilAllowListMatchesBaseline verifies that
+// FilteredInterfaces(nil) applies the same UP/MULTICAST/loopback baseline as
+// DefaultInterfaces, without the isVPN/isDocker name filtering.
+func TestFilteredInterfaces_NilAllowListMatchesBaseline(t *testing.T) {
+	ifaces, err := FilteredInterfaces(nil)
+	if err != nil {
+		t.Fatalf("FilteredInterfaces(nil) returned error: %v", err)
+	}
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 {
+			t.Errorf("FilteredInterfaces(nil) included DOWN interface %q", iface.Name)
+		}
+		if iface.Flags&net.FlagMulticast == 0 {
+			t.Errorf("FilteredInterfaces(nil) included non-MULTICAST interface %q", iface.Name)
+		}
+		if iface.Flags&net.FlagLoopback != 0 {
+			t.Errorf("FilteredInterfaces(nil) included loopback interface %q", iface.Name)
+		}
+	}
+}
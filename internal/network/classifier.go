@@ -0,0 +1,235 @@
+package network
+
+import "net"
+
+// InterfaceKind categorizes what kind of network interface Classify saw,
+// so callers can include or exclude by kind instead of by name pattern.
+type InterfaceKind int
+
+const (
+	// KindUnknown means no detector positively identified the interface.
+	// A Classifier should rarely return this from its outermost, composite
+	// form - see DefaultClassifier - since the name-heuristic fallback
+	// layer always resolves to a concrete kind.
+	KindUnknown InterfaceKind = iota
+
+	// KindPhysical is a real NIC: Ethernet, a USB dongle, anything with a
+	// physical link.
+	KindPhysical
+
+	// KindVPN is a VPN tunnel interface (TUN/TAP, WireGuard, PPTP/L2TP).
+	KindVPN
+
+	// KindContainer is a container-networking interface (Docker bridge,
+	// veth pair, custom bridge network).
+	KindContainer
+
+	// KindVirtual is some other non-physical interface not covered by the
+	// more specific kinds above (a generic bridge, a hypervisor's host-only
+	// adapter, an IPMP/VNIC meta-interface).
+	KindVirtual
+
+	// KindWireless is a Wi-Fi interface.
+	KindWireless
+)
+
+// Classifier identifies what kind of interface iface is. DefaultInterfaces
+// uses DefaultClassifier(); callers that want different inclusion rules
+// (e.g. including VPN interfaces, or excluding Wireless) build their own
+// Classifier - often DefaultClassifier() itself, reused as a detector - and
+// pass it to Interfaces via WithClassifier.
+type Classifier interface {
+	Classify(iface net.Interface) InterfaceKind
+}
+
+// ClassifierFunc adapts a plain function to Classifier.
+type ClassifierFunc func(iface net.Interface) InterfaceKind
+
+// Classify calls f.
+func (f ClassifierFunc) Classify(iface net.Interface) InterfaceKind {
+	return f(iface)
+}
+
+// compositeClassifier tries each detector in order and returns the first
+// non-KindUnknown result. Layering lets a platform-specific, positively
+// identifying detector (e.g. Linux's sysfs attributes) pre-empt the
+// name-heuristic fallback, while still falling through to it for anything
+// the platform detector couldn't place.
+type compositeClassifier struct {
+	detectors []Classifier
+}
+
+// Classify returns the first detector's non-KindUnknown result, or
+// KindUnknown if every detector deferred.
+func (c compositeClassifier) Classify(iface net.Interface) InterfaceKind {
+	for _, d := range c.detectors {
+		if kind := d.Classify(iface); kind != KindUnknown {
+			return kind
+		}
+	}
+	return KindUnknown
+}
+
+// DefaultClassifier returns this platform's composite Classifier: a
+// positively-identifying, platform-native detector (where one is
+// implemented - currently Linux, via /sys/class/net attributes) layered
+// over nameHeuristicClassifier, which recognizes VPN and Docker names with
+// the isVPN/isDocker patterns DefaultInterfaces used before this refactor,
+// plus wireless, Solaris-virtual and misc-virtual name patterns, and, unlike the platform-native detectors, never defers: anything it
+// doesn't recognize is assumed KindPhysical, which is what let
+// DefaultInterfaces treat "everything not explicitly excluded" as usable
+// before this refactor.
+//
+// macOS (SIOCGIFMEDIA/SCNetworkInterface) and Windows
+// (GetAdaptersAddresses/IfType) native detectors are not implemented this
+// round - both need a binding this repo doesn't otherwise take on (a raw
+// AF_INET ioctl per interface for the former, a CoreFoundation/
+// SystemConfiguration cgo dependency or a DLL binding for the latter) - so
+// those platforms classify by name heuristic alone, same as before this
+// refactor existed.
+func DefaultClassifier() Classifier {
+	detectors := append(platformDetectors(), nameHeuristicClassifier{})
+	return compositeClassifier{detectors: detectors}
+}
+
+// nameHeuristicClassifier classifies purely from iface.Name, as
+// DefaultInterfaces did before this refactor (which checked only isVPN and
+// isDocker). It never returns KindUnknown: anything not
+// matched by a known pattern is assumed KindPhysical.
+type nameHeuristicClassifier struct{}
+
+func (nameHeuristicClassifier) Classify(iface net.Interface) InterfaceKind {
+	name := iface.Name
+
+	switch {
+	case isVPN(name):
+		return KindVPN
+	case isDocker(name):
+		return KindContainer
+	case isWireless(name):
+		return KindWireless
+	case isSolarisVirtual(name):
+		return KindVirtual
+	case isMiscVirtual(name):
+		return KindVirtual
+	default:
+		return KindPhysical
+	}
+}
+
+// isWireless returns true for common Wi-Fi interface naming patterns.
+//
+// Recognized patterns:
+//   - wlan*, wlp* - Linux Wi-Fi (udev "predictable names" and classic alike)
+//   - wl*         - BSD/other Wi-Fi driver naming
+func isWireless(name string) bool {
+	prefixes := []string{"wlan", "wlp", "wl"}
+	for _, prefix := range prefixes {
+		if len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// isSolarisVirtual returns true for Solaris/illumos meta-interfaces that
+// aggregate or virtualize an underlying physical NIC rather than being one
+// themselves.
+//
+// Recognized patterns:
+//   - ipmp* - IP Multipathing group interface
+//   - vnic* - virtual NIC (zones, crossbow)
+//
+// "net*" zone interfaces are deliberately not covered here: illumos also
+// uses net0, net1, ... for physical NICs (GLDv3 generic naming), so a
+// name-only check can't tell a zone's virtualized net0 apart from the
+// global zone's physical one.
+func isSolarisVirtual(name string) bool {
+	prefixes := []string{"ipmp", "vnic"}
+	for _, prefix := range prefixes {
+		if len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// isMiscVirtual returns true for other common hypervisor/bridge virtual
+// interface naming patterns not already covered by isDocker.
+//
+// Recognized patterns:
+//   - bridge* - generic bridge interfaces (macOS Internet Sharing, libvirt)
+//   - vmnet*  - VMware host-only/NAT adapters
+//   - vboxnet* - VirtualBox host-only adapters
+func isMiscVirtual(name string) bool {
+	prefixes := []string{"bridge", "vmnet", "vboxnet"}
+	for _, prefix := range prefixes {
+		if len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// InterfacesOption configures Interfaces.
+type InterfacesOption func(*interfacesConfig)
+
+type interfacesConfig struct {
+	classifier Classifier
+	include    map[InterfaceKind]bool
+}
+
+// WithClassifier overrides the Classifier Interfaces uses to decide each
+// interface's InterfaceKind. Defaults to DefaultClassifier().
+func WithClassifier(c Classifier) InterfacesOption {
+	return func(cfg *interfacesConfig) {
+		cfg.classifier = c
+	}
+}
+
+// WithKinds restricts Interfaces's result to only the given kinds, instead
+// of the default KindPhysical|KindWireless.
+func WithKinds(kinds ...InterfaceKind) InterfacesOption {
+	return func(cfg *interfacesConfig) {
+		cfg.include = make(map[InterfaceKind]bool, len(kinds))
+		for _, k := range kinds {
+			cfg.include[k] = true
+		}
+	}
+}
+
+// Interfaces returns the host's up, multicast-capable, non-loopback
+// interfaces whose Classify() result is one of the included kinds (default:
+// KindPhysical or KindWireless - the set DefaultInterfaces returns).
+func Interfaces(opts ...InterfacesOption) ([]net.Interface, error) {
+	cfg := &interfacesConfig{
+		classifier: DefaultClassifier(),
+		include:    map[InterfaceKind]bool{KindPhysical: true, KindWireless: true},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]net.Interface, 0, len(all))
+	for _, iface := range all {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if !cfg.include[cfg.classifier.Classify(iface)] {
+			continue
+		}
+		filtered = append(filtered, iface)
+	}
+	return filtered, nil
+}
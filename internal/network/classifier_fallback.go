@@ -0,0 +1,12 @@
+//go:build !linux
+
+package network
+
+// platformDetectors returns this platform's native, positively-identifying
+// detectors to layer ahead of nameHeuristicClassifier. There are none on
+// this platform yet - see DefaultClassifier's doc comment for why macOS and
+// Windows aren't covered this round - so DefaultClassifier falls back to
+// name heuristics alone, same as before this refactor existed.
+func platformDetectors() []Classifier {
+	return nil
+}
@@ -0,0 +1,72 @@
+//go:build linux
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// platformDetectors returns Linux's native detector: sysfsClassifier, which
+// reads /sys/class/net/<name> attributes to positively identify an
+// interface's kind instead of guessing from its name.
+func platformDetectors() []Classifier {
+	return []Classifier{sysfsClassifier{}}
+}
+
+// sysfsClassifier identifies TUN/TAP, bridge, and wireless interfaces from
+// their /sys/class/net/<name> attributes, and virtual interfaces from the
+// absence of a backing "device" symlink - the same signal `ip -d link
+// show` uses to print "link/none" for devices with no physical hardware.
+//
+// It doesn't query the driver name via the ethtool ETHTOOL_GDRVINFO ioctl:
+// that needs a raw AF_INET socket ioctl this package doesn't otherwise take
+// on, and the attribute checks below already cover the cases that motivated
+// this refactor (TUN/TAP, bridge, Wi-Fi). An interface sysfsClassifier can't
+// place defers to KindUnknown, letting nameHeuristicClassifier decide.
+type sysfsClassifier struct{}
+
+func (sysfsClassifier) Classify(iface net.Interface) InterfaceKind {
+	base := fmt.Sprintf("/sys/class/net/%s", iface.Name)
+
+	// An interface with no readable sysfs entry can't be placed from here.
+	if !pathExists(base) {
+		return KindUnknown
+	}
+
+	// tun_flags exists only for TUN/TAP devices (drivers/net/tun.c).
+	if pathExists(base + "/tun_flags") {
+		return KindVPN
+	}
+
+	// The "bridge" subdirectory exists only for software bridge devices
+	// (net/bridge/br_sysfs_br.c).
+	if pathExists(base + "/bridge") {
+		return KindContainer
+	}
+
+	// The "wireless" subdirectory (or, on newer drivers using cfg80211,
+	// "phy80211") exists only for Wi-Fi devices.
+	if pathExists(base+"/wireless") || pathExists(base+"/phy80211") {
+		return KindWireless
+	}
+
+	// Physical NICs' sysfs entry is a symlink to the backing device under
+	// /sys/devices/...; purely virtual interfaces (veth, dummy, most
+	// tunnels not already caught above) have no such link.
+	if _, err := os.Lstat(base + "/device"); err != nil {
+		return KindVirtual
+	}
+
+	return KindUnknown
+}
+
+// pathExists reports whether path exists, treating any stat error
+// (including permission denied, which some sandboxes impose on sysfs) as
+// "doesn't exist" rather than propagating it - a classifier detector that
+// can't read sysfs should defer, not fail the whole classification.
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,123 @@
+package network
+
+import (
+	"net"
+	"testing"
+)
+
+// TestNameHeuristicClassifier_MatchesLegacyPatterns verifies that the
+// name-heuristic layer classifies the same way isVPN/isDocker always did,
+// plus the new wireless/Solaris/misc-virtual patterns this request adds.
+func TestNameHeuristicClassifier_MatchesLegacyPatterns(t *testing.T) {
+	c := nameHeuristicClassifier{}
+
+	tests := []struct {
+		name string
+		want InterfaceKind
+	}{
+		{"utun0", KindVPN},
+		{"tailscale0", KindVPN},
+		{"docker0", KindContainer},
+		{"veth1a2b3c4", KindContainer},
+		{"wlan0", KindWireless},
+		{"wlp3s0", KindWireless},
+		{"ipmp0", KindVirtual},
+		{"vnic1", KindVirtual},
+		{"bridge0", KindVirtual},
+		{"vmnet1", KindVirtual},
+		{"eth0", KindPhysical},
+		{"en0", KindPhysical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Classify(net.Interface{Name: tt.name})
+			if got != tt.want {
+				t.Errorf("Classify(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompositeClassifier_FirstNonUnknownWins verifies that compositeClassifier
+// returns the first detector's non-KindUnknown result and skips straight
+// past any detector that defers.
+func TestCompositeClassifier_FirstNonUnknownWins(t *testing.T) {
+	defers := ClassifierFunc(func(net.Interface) InterfaceKind { return KindUnknown })
+	decides := ClassifierFunc(func(net.Interface) InterfaceKind { return KindVirtual })
+	neverReached := ClassifierFunc(func(net.Interface) InterfaceKind { return KindPhysical })
+
+	c := compositeClassifier{detectors: []Classifier{defers, decides, neverReached}}
+
+	if got := c.Classify(net.Interface{Name: "eth0"}); got != KindVirtual {
+		t.Errorf("Classify() = %v, want %v (decides, not neverReached)", got, KindVirtual)
+	}
+}
+
+// TestCompositeClassifier_AllDefer verifies KindUnknown surfaces when every
+// detector defers, rather than panicking or guessing.
+func TestCompositeClassifier_AllDefer(t *testing.T) {
+	defers := ClassifierFunc(func(net.Interface) InterfaceKind { return KindUnknown })
+	c := compositeClassifier{detectors: []Classifier{defers, defers}}
+
+	if got := c.Classify(net.Interface{Name: "eth0"}); got != KindUnknown {
+		t.Errorf("Classify() = %v, want %v", got, KindUnknown)
+	}
+}
+
+// TestInterfaces_WithClassifier_OverridesDefault verifies that a caller-supplied
+// Classifier decides inclusion instead of DefaultClassifier.
+func TestInterfaces_WithClassifier_OverridesDefault(t *testing.T) {
+	alwaysVirtual := ClassifierFunc(func(net.Interface) InterfaceKind { return KindVirtual })
+
+	ifaces, err := Interfaces(WithClassifier(alwaysVirtual))
+	if err != nil {
+		t.Fatalf("Interfaces() failed: %v", err)
+	}
+	if len(ifaces) != 0 {
+		t.Errorf("Interfaces() with an always-Virtual classifier and default KindPhysical|KindWireless inclusion = %v, want empty", ifaces)
+	}
+}
+
+// TestInterfaces_WithKinds_IncludesVirtual verifies that WithKinds can widen
+// inclusion to kinds DefaultInterfaces would otherwise exclude.
+func TestInterfaces_WithKinds_IncludesVirtual(t *testing.T) {
+	alwaysVirtual := ClassifierFunc(func(net.Interface) InterfaceKind { return KindVirtual })
+
+	ifaces, err := Interfaces(WithClassifier(alwaysVirtual), WithKinds(KindVirtual))
+	if err != nil {
+		t.Fatalf("Interfaces() failed: %v", err)
+	}
+
+	// Every up/multicast/non-loopback interface on the host should now be
+	// included, since alwaysVirtual classifies everything as KindVirtual
+	// and WithKinds(KindVirtual) includes exactly that.
+	baseline, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("net.Interfaces() failed: %v", err)
+	}
+	var wantCount int
+	for _, ifi := range baseline {
+		if ifi.Flags&net.FlagUp != 0 && ifi.Flags&net.FlagMulticast != 0 && ifi.Flags&net.FlagLoopback == 0 {
+			wantCount++
+		}
+	}
+	if len(ifaces) != wantCount {
+		t.Errorf("Interfaces() returned %d interfaces, want %d", len(ifaces), wantCount)
+	}
+}
+
+// TestDefaultClassifier_EverythingResolvesToConcreteKind verifies
+// DefaultClassifier never returns KindUnknown, for a representative sample
+// of interface names - including ones only the Linux-specific sysfs
+// detector would otherwise see, which won't exist in this test environment,
+// so this also exercises the deferred-to-name-heuristic path.
+func TestDefaultClassifier_EverythingResolvesToConcreteKind(t *testing.T) {
+	c := DefaultClassifier()
+
+	for _, name := range []string{"eth0", "utun0", "docker0", "wlan0", "made-up-name-xyz"} {
+		if got := c.Classify(net.Interface{Name: name}); got == KindUnknown {
+			t.Errorf("DefaultClassifier().Classify(%q) = KindUnknown, want a concrete kind", name)
+		}
+	}
+}
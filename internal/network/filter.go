@@ -0,0 +1,188 @@
+package network
+
+import (
+	"net"
+	"path"
+	"regexp"
+)
+
+// FilterAction is what a FilterRule does when it matches an interface.
+type FilterAction int
+
+const (
+	// Exclude drops an interface a matching rule applies to.
+	Exclude FilterAction = iota
+
+	// Include keeps an interface a matching rule applies to, overriding
+	// whatever an earlier matching rule decided.
+	Include
+)
+
+// FilterRule is one named entry in an InterfaceFilter's ordered rule list.
+// Match decides whether the rule applies to a given interface; Action
+// decides what happens when it does. InterfaceFilter.Interfaces evaluates
+// rules in order and the last match wins, so a rule appended after
+// NewDefaultFilter's defaults can override one of them - e.g. an Include
+// rule to keep a VPN interface the default "vpn" rule would otherwise
+// exclude.
+type FilterRule struct {
+	Name   string
+	Match  func(net.Interface) bool
+	Action FilterAction
+}
+
+// InterfaceFilter selects interfaces via an ordered list of FilterRules,
+// last-match-wins, layered over the same up/multicast/non-loopback
+// requirement Interfaces and DefaultInterfaces have always enforced.
+//
+// Unlike Classifier/Interfaces' kind-based selection, a caller can add,
+// remove, or reorder rules without forking this package: WSL's "eth0" that
+// is really a Hyper-V adapter, or an LXC "lxcbr0" bridge the default name
+// patterns don't recognize, can be excluded with one AddRule call instead
+// of a patch to isVPN/isDocker.
+type InterfaceFilter struct {
+	rules []FilterRule
+}
+
+// NewFilter returns an InterfaceFilter with no rules: every up, multicast,
+// non-loopback interface passes. Use NewDefaultFilter for the VPN/Docker
+// exclusions DefaultInterfaces has always applied.
+func NewFilter() *InterfaceFilter {
+	return &InterfaceFilter{}
+}
+
+// NewDefaultFilter returns an InterfaceFilter seeded with the same
+// VPN/Docker exclusion rules DefaultInterfaces has always applied,
+// expressed as named FilterRules a caller can inspect, remove (RemoveRule),
+// or override (AddRule, appended after and so evaluated after these) instead
+// of forking isVPN/isDocker.
+//
+// Implements:
+//   - FR-017: Exclude VPN interfaces (6 patterns)
+//   - FR-018: Exclude Docker interfaces (3 patterns)
+func NewDefaultFilter() *InterfaceFilter {
+	f := NewFilter()
+	f.AddRule(FilterRule{
+		Name:   "vpn",
+		Match:  func(iface net.Interface) bool { return isVPN(iface.Name) },
+		Action: Exclude,
+	})
+	f.AddRule(FilterRule{
+		Name:   "docker",
+		Match:  func(iface net.Interface) bool { return isDocker(iface.Name) },
+		Action: Exclude,
+	})
+	return f
+}
+
+// AddRule appends rule to the end of f's ordered list, so it's evaluated
+// after - and can override - every rule already present.
+func (f *InterfaceFilter) AddRule(rule FilterRule) {
+	f.rules = append(f.rules, rule)
+}
+
+// RemoveRule removes the first rule named name, reporting whether one was
+// found. Rule names aren't required to be unique; RemoveRule only ever
+// removes the first match.
+func (f *InterfaceFilter) RemoveRule(name string) bool {
+	for i, rule := range f.rules {
+		if rule.Name == name {
+			f.rules = append(f.rules[:i], f.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Interfaces returns the host's up, multicast-capable, non-loopback
+// interfaces not excluded by f's rules. An interface no rule matches is
+// included; one matched by rules with mixed actions follows the last match
+// in f's order (see FilterRule).
+func (f *InterfaceFilter) Interfaces() ([]net.Interface, error) {
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]net.Interface, 0, len(all))
+	for _, iface := range all {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if f.excluded(iface) {
+			continue
+		}
+		filtered = append(filtered, iface)
+	}
+	return filtered, nil
+}
+
+// excluded reports whether the last of f's rules to match iface (if any) is
+// an Exclude rule.
+func (f *InterfaceFilter) excluded(iface net.Interface) bool {
+	matched := false
+	action := Include
+	for _, rule := range f.rules {
+		if rule.Match(iface) {
+			matched = true
+			action = rule.Action
+		}
+	}
+	return matched && action == Exclude
+}
+
+// MatchGlob returns a FilterRule.Match predicate matching iface.Name
+// against a shell glob pattern (path.Match syntax: *, ?, [...]) - e.g.
+// "utun*" or "br-*".
+func MatchGlob(pattern string) func(net.Interface) bool {
+	return func(iface net.Interface) bool {
+		matched, err := path.Match(pattern, iface.Name)
+		return err == nil && matched
+	}
+}
+
+// MatchRegex returns a FilterRule.Match predicate matching iface.Name
+// against expr. A predicate built from an invalid expr never matches rather
+// than panicking; callers that want to surface a bad pattern should validate
+// it with regexp.Compile themselves before calling MatchRegex.
+func MatchRegex(expr string) func(net.Interface) bool {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return func(net.Interface) bool { return false }
+	}
+	return func(iface net.Interface) bool {
+		return re.MatchString(iface.Name)
+	}
+}
+
+// MatchCIDR returns a FilterRule.Match predicate matching an interface whose
+// addresses all fall within cidr - e.g. "100.64.0.0/10" to catch Tailscale's
+// CGNAT range even on an interface not named "tailscale*". An interface with
+// no addresses, whose Addrs() call fails, or with any address outside cidr,
+// never matches - this is meant for "this interface's only address is in
+// this range", not "one of several addresses happens to be".
+func MatchCIDR(cidr string) func(net.Interface) bool {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return func(net.Interface) bool { return false }
+	}
+	return func(iface net.Interface) bool {
+		addrs, err := iface.Addrs()
+		if err != nil || len(addrs) == 0 {
+			return false
+		}
+		for _, addr := range addrs {
+			a, ok := addr.(*net.IPNet)
+			if !ok || !ipNet.Contains(a.IP) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"net"
+	"testing"
+)
+
+// TestNewDefaultFilter_ExcludesVPNAndDocker verifies NewDefaultFilter's
+// seeded "vpn" and "docker" rules reject the same names isVPN/isDocker do.
+func TestNewDefaultFilter_ExcludesVPNAndDocker(t *testing.T) {
+	f := NewDefaultFilter()
+
+	excluded := []string{"utun0", "tailscale0", "wg0", "docker0", "veth1a2b3c4", "br-abc123"}
+	for _, name := range excluded {
+		if !f.excluded(net.Interface{Name: name, Flags: net.FlagUp | net.FlagMulticast}) {
+			t.Errorf("NewDefaultFilter() did not exclude %q", name)
+		}
+	}
+
+	if f.excluded(net.Interface{Name: "eth0", Flags: net.FlagUp | net.FlagMulticast}) {
+		t.Error("NewDefaultFilter() excluded eth0, want included")
+	}
+}
+
+// TestInterfaceFilter_AddRule_OverridesDefault verifies a caller can append
+// an Include rule to keep an interface NewDefaultFilter's "vpn" rule would
+// otherwise exclude - last-match-wins per FilterRule's doc.
+func TestInterfaceFilter_AddRule_OverridesDefault(t *testing.T) {
+	f := NewDefaultFilter()
+	f.AddRule(FilterRule{
+		Name:   "keep-tailscale",
+		Match:  MatchGlob("tailscale*"),
+		Action: Include,
+	})
+
+	if f.excluded(net.Interface{Name: "tailscale0"}) {
+		t.Error("a later Include rule did not override the default \"vpn\" exclusion")
+	}
+	// Other VPN patterns not covered by the override rule stay excluded.
+	if !f.excluded(net.Interface{Name: "utun0"}) {
+		t.Error("overriding tailscale0 incorrectly stopped excluding utun0 too")
+	}
+}
+
+// TestInterfaceFilter_RemoveRule verifies RemoveRule drops a named rule and
+// reports whether one was found.
+func TestInterfaceFilter_RemoveRule(t *testing.T) {
+	f := NewDefaultFilter()
+
+	if !f.RemoveRule("docker") {
+		t.Fatal("RemoveRule(\"docker\") = false, want true")
+	}
+	if f.excluded(net.Interface{Name: "docker0"}) {
+		t.Error("docker0 still excluded after RemoveRule(\"docker\")")
+	}
+	// vpn rule untouched.
+	if !f.excluded(net.Interface{Name: "utun0"}) {
+		t.Error("RemoveRule(\"docker\") unexpectedly also removed the vpn rule")
+	}
+
+	if f.RemoveRule("does-not-exist") {
+		t.Error("RemoveRule(\"does-not-exist\") = true, want false")
+	}
+}
+
+// TestInterfaceFilter_Interfaces_EnforcesBaseRequirements verifies Interfaces
+// still excludes down, non-multicast, and loopback interfaces regardless of
+// f's rules, the same hard requirements DefaultInterfaces has always had.
+func TestInterfaceFilter_Interfaces_EnforcesBaseRequirements(t *testing.T) {
+	f := NewFilter()
+	ifaces, err := f.Interfaces()
+	if err != nil {
+		t.Fatalf("Interfaces() returned error: %v", err)
+	}
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 {
+			t.Errorf("Interfaces() included DOWN interface %q", iface.Name)
+		}
+		if iface.Flags&net.FlagMulticast == 0 {
+			t.Errorf("Interfaces() included non-MULTICAST interface %q", iface.Name)
+		}
+		if iface.Flags&net.FlagLoopback != 0 {
+			t.Errorf("Interfaces() included loopback interface %q", iface.Name)
+		}
+	}
+}
+
+// TestMatchGlob verifies glob patterns like "utun*" and "br-*" match the
+// way the request's examples expect.
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"utun*", "utun0", true},
+		{"utun*", "eth0", false},
+		{"br-*", "br-abc123", true},
+		{"br-*", "bridge0", false},
+		{"eth?", "eth0", true},
+		{"eth?", "eth10", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.name, func(t *testing.T) {
+			got := MatchGlob(tt.pattern)(net.Interface{Name: tt.name})
+			if got != tt.want {
+				t.Errorf("MatchGlob(%q)(%q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchRegex verifies a regex predicate matches names the way
+// regexp.MatchString would, and that an invalid expression never matches
+// instead of panicking.
+func TestMatchRegex(t *testing.T) {
+	match := MatchRegex(`^lxc(br)?\d*$`)
+	if !match(net.Interface{Name: "lxcbr0"}) {
+		t.Error("MatchRegex did not match lxcbr0")
+	}
+	if match(net.Interface{Name: "eth0"}) {
+		t.Error("MatchRegex matched eth0, want false")
+	}
+
+	invalid := MatchRegex("[")
+	if invalid(net.Interface{Name: "eth0"}) {
+		t.Error("MatchRegex with an invalid expression matched, want false always")
+	}
+}
+
+// TestMatchCIDR_InvalidCIDRNeverMatches verifies an unparsable CIDR string
+// produces a predicate that never matches rather than panicking.
+func TestMatchCIDR_InvalidCIDRNeverMatches(t *testing.T) {
+	match := MatchCIDR("not-a-cidr")
+	if match(net.Interface{Name: "eth0"}) {
+		t.Error("MatchCIDR with an invalid CIDR matched, want false always")
+	}
+}
+
+// TestMatchCIDR_RequiresEveryAddressInRange verifies MatchCIDR's "only
+// address is in range" semantics using the host's real loopback interface,
+// which (unlike a synthetic net.Interface) has real addresses Addrs() can
+// return: 127.0.0.0/8 only covers loopback's IPv4 address, not its IPv6
+// ::1/128, so the predicate must report no match.
+func TestMatchCIDR_RequiresEveryAddressInRange(t *testing.T) {
+	lo, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Skip("no \"lo\" interface on this host")
+	}
+
+	match := MatchCIDR("127.0.0.0/8")
+	if match(*lo) {
+		t.Error("MatchCIDR(\"127.0.0.0/8\") matched loopback, want false since ::1 falls outside it")
+	}
+}
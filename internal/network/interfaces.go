@@ -15,8 +15,16 @@ import (
 // - Excludes down interfaces
 // - Includes only interfaces with multicast support
 //
-// Users can override this behavior via WithInterfaces() or WithInterfaceFilter()
-// functional options.
+// It's a thin wrapper over Interfaces(): DefaultInterfaces() is exactly
+// Interfaces() with DefaultClassifier() and the default KindPhysical|
+// KindWireless inclusion set, kept as its own zero-argument function since
+// callers throughout this package (and watcher.go's default listInterfaces)
+// already depend on that signature. Callers that want a different
+// Classifier or inclusion set should call Interfaces directly; callers that
+// want to add, remove, or override individual exclusion rules (e.g. an
+// "eth0" that's actually a WSL Hyper-V adapter, or an LXC "lxcbr0" bridge)
+// without forking this package should use NewDefaultFilter().Interfaces()
+// instead - see InterfaceFilter.
 //
 // Implements:
 //   - FR-013: System MUST implement DefaultInterfaces() function
@@ -26,45 +34,7 @@ import (
 //   - FR-017: Exclude VPN interfaces (6 patterns)
 //   - FR-018: Exclude Docker interfaces (3 patterns)
 func DefaultInterfaces() ([]net.Interface, error) {
-	// Get all system interfaces
-	allIfaces, err := net.Interfaces()
-	if err != nil {
-		return nil, err
-	}
-
-	// Filter interfaces based on requirements
-	filtered := make([]net.Interface, 0, len(allIfaces))
-	for _, iface := range allIfaces {
-		// FR-014: Skip DOWN interfaces (must be UP)
-		if iface.Flags&net.FlagUp == 0 {
-			continue
-		}
-
-		// FR-015: Skip non-MULTICAST interfaces
-		if iface.Flags&net.FlagMulticast == 0 {
-			continue
-		}
-
-		// FR-016: Skip LOOPBACK interfaces
-		if iface.Flags&net.FlagLoopback != 0 {
-			continue
-		}
-
-		// FR-017: Skip VPN interfaces (6 patterns)
-		if isVPN(iface.Name) {
-			continue
-		}
-
-		// FR-018: Skip Docker interfaces (3 patterns)
-		if isDocker(iface.Name) {
-			continue
-		}
-
-		// Interface passed all filters - include it
-		filtered = append(filtered, iface)
-	}
-
-	return filtered, nil
+	return Interfaces()
 }
 
 // isVPN returns true if the interface name matches known VPN naming patterns.
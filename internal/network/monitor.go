@@ -0,0 +1,239 @@
+package network
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// monitorEventBufferSize is the Monitor Events() channel buffer, mirroring
+// watchRecordBufferSize's choice of a modest buffer that absorbs a burst of
+// traffic without blocking the receive loop on a slow consumer.
+const monitorEventBufferSize = 32
+
+// MonitoredQuestion is one parsed question from a packet Monitor observed.
+type MonitoredQuestion struct {
+	Name string
+	Type uint16
+}
+
+// MonitoredAnswer is one parsed resource record from a packet Monitor
+// observed, in the Answer, Authority, or Additional section.
+type MonitoredAnswer struct {
+	Name string
+	Type uint16
+	TTL  uint32
+
+	// CacheFlush is the RFC 6762 §10.2 cache-flush bit (CLASS bit 15).
+	CacheFlush bool
+
+	// Data is the type-specific decoded RDATA (message.AData, message.PTRData,
+	// message.SRVData, ...), or nil if Type has no decoder in
+	// message.ParseRDATA or the RDATA was malformed - a passive monitor must
+	// not drop the whole packet over one unparsable record.
+	Data message.RData
+}
+
+// MonitoredPacket is one mDNS packet Monitor has received and dissected.
+type MonitoredPacket struct {
+	// Source is the packet's source address.
+	Source net.Addr
+
+	// At is when Monitor received the packet.
+	At time.Time
+
+	// TransactionID is the packet's header ID.
+	TransactionID uint16
+
+	// Opcode is the header's OPCODE field (always 0, QUERY, for a
+	// conformant mDNS packet per RFC 6762 §18.3, but reported as observed).
+	Opcode uint8
+
+	// IsResponse is the header's QR bit.
+	IsResponse bool
+
+	// Truncated is the header's TC bit.
+	Truncated bool
+
+	Questions []MonitoredQuestion
+	Answers   []MonitoredAnswer
+
+	// Authorities and Additionals hold the Authority and Additional
+	// sections, dissected the same way as Answers.
+	Authorities []MonitoredAnswer
+	Additionals []MonitoredAnswer
+}
+
+// MonitorSink receives every packet Monitor observes, in addition to it
+// being delivered on Events(). Observe is called synchronously from
+// Monitor's receive loop, so an implementation must not block - a slow sink
+// would delay every subsequent receive. See tap.Tap's identical contract,
+// which this mirrors for the same reason.
+type MonitorSink interface {
+	Observe(MonitoredPacket)
+}
+
+// Monitor passively observes mDNS traffic: it joins the multicast group via
+// the same transport.Transport (and so the same SO_REUSEPORT coexistence
+// with Avahi/Bonjour) a Responder or Querier would use, but never calls
+// Send - it only dissects what it receives, exposing the result as a
+// channel of MonitoredPacket and, optionally, through a MonitorSink for
+// NDJSON export, Prometheus counters, or forwarding into a log pipeline.
+//
+// Use NewMonitor to construct one; the zero value is not usable.
+type Monitor struct {
+	tr     transport.Transport
+	sink   MonitorSink
+	events chan MonitoredPacket
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// MonitorOption configures a Monitor constructed by NewMonitor.
+type MonitorOption func(*Monitor)
+
+// WithMonitorSink sets the MonitorSink every observed packet is also
+// delivered to, in addition to Events().
+func WithMonitorSink(sink MonitorSink) MonitorOption {
+	return func(m *Monitor) { m.sink = sink }
+}
+
+// NewMonitor creates a Monitor listening on a fresh transport.UDPv4Transport
+// (joining 224.0.0.251:5353 on every multicast-capable interface, same as
+// responder.New/querier.New's default transport) and starts its receive
+// loop. Call Close to stop the loop and release the transport.
+func NewMonitor(opts ...MonitorOption) (*Monitor, error) {
+	tr, err := transport.NewUDPv4Transport()
+	if err != nil {
+		return nil, err
+	}
+	return newMonitor(tr, opts...)
+}
+
+// newMonitor builds a Monitor around an already-constructed transport - the
+// shared constructor NewMonitor uses, and tests use directly to pass a
+// mdnstest/mock transport without requiring real multicast support.
+func newMonitor(tr transport.Transport, opts ...MonitorOption) (*Monitor, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Monitor{
+		tr:     tr,
+		events: make(chan MonitoredPacket, monitorEventBufferSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.wg.Add(1)
+	go m.run()
+
+	return m, nil
+}
+
+// Events returns the channel of dissected packets. It is closed once Close
+// is called and the receive loop has exited.
+func (m *Monitor) Events() <-chan MonitoredPacket {
+	return m.events
+}
+
+// Close stops the receive loop, closes the underlying transport, and
+// returns once Events() has been closed.
+func (m *Monitor) Close() error {
+	m.cancel()
+	err := m.tr.Close()
+	m.wg.Wait()
+	return err
+}
+
+// run receives packets until ctx is done, dissecting and delivering each one
+// it can parse. Mirrors querier.Querier.receiveLoop's short-timeout-and-loop
+// shape, since Monitor never needs receiveLoop's request/response dispatch -
+// only continuous receive.
+func (m *Monitor) run() {
+	defer m.wg.Done()
+	defer close(m.events)
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, 100*time.Millisecond)
+		data, src, err := m.tr.Receive(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		packet, err := dissect(data, src)
+		if err != nil {
+			continue
+		}
+
+		if m.sink != nil {
+			m.sink.Observe(packet)
+		}
+
+		select {
+		case m.events <- packet:
+		default:
+			// Drop rather than block a slow consumer, same as watchSession.emit.
+		}
+	}
+}
+
+// dissect parses data into a MonitoredPacket observed from src.
+func dissect(data []byte, src net.Addr) (MonitoredPacket, error) {
+	msg, err := message.ParseMessage(data)
+	if err != nil {
+		return MonitoredPacket{}, err
+	}
+
+	questions := make([]MonitoredQuestion, 0, len(msg.Questions))
+	for _, q := range msg.Questions {
+		questions = append(questions, MonitoredQuestion{Name: q.QNAME, Type: q.QTYPE})
+	}
+
+	return MonitoredPacket{
+		Source:        src,
+		At:            time.Now(),
+		TransactionID: msg.Header.ID,
+		Opcode:        msg.Header.GetOPCODE(),
+		IsResponse:    msg.Header.IsResponse(),
+		Truncated:     msg.Header.IsTruncated(),
+		Questions:     questions,
+		Answers:       dissectAnswers(msg.Answers),
+		Authorities:   dissectAnswers(msg.Authorities),
+		Additionals:   dissectAnswers(msg.Additionals),
+	}, nil
+}
+
+// dissectAnswers converts a section of message.Answer into MonitoredAnswer,
+// decoding RDATA per type where message.ParseRDATA supports it and leaving
+// Data nil otherwise rather than failing the whole packet.
+func dissectAnswers(answers []message.Answer) []MonitoredAnswer {
+	out := make([]MonitoredAnswer, 0, len(answers))
+	for _, a := range answers {
+		data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+		if err != nil {
+			data = nil
+		}
+		out = append(out, MonitoredAnswer{
+			Name:       a.NAME,
+			Type:       a.TYPE,
+			TTL:        a.TTL,
+			CacheFlush: a.CLASS&0x8000 != 0,
+			Data:       data,
+		})
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package network
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/joshuafuller/beacon/metrics"
+)
+
+// NDJSONSink renders every MonitoredPacket as one line of JSON to w, for
+// piping Monitor's output into log aggregation or analysis tooling that
+// expects newline-delimited JSON.
+//
+// NDJSONSink is safe for concurrent use: writes are serialized by mu so two
+// Monitors sharing one NDJSONSink never interleave a partial line.
+type NDJSONSink struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+var _ MonitorSink = (*NDJSONSink)(nil)
+
+// NewNDJSONSink creates an NDJSONSink writing to w.
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	return &NDJSONSink{w: w}
+}
+
+// Observe implements MonitorSink. A packet that fails to marshal (it never
+// should - MonitoredPacket's fields are all plain JSON-able types) or fails
+// to write is silently dropped, matching tap.Tap implementations' own
+// must-not-block, must-not-fail contract.
+func (s *NDJSONSink) Observe(p MonitoredPacket) {
+	line, err := json.Marshal(monitoredPacketJSONFrom(p))
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.w.Write(line)
+}
+
+// monitoredPacketJSON is MonitoredPacket re-shaped for NDJSON output:
+// Source (a net.Addr) becomes its string form, and RData values are left to
+// json.Marshal's default struct encoding rather than requiring every RData
+// implementation to add its own MarshalJSON.
+type monitoredPacketJSON struct {
+	Source        string              `json:"source"`
+	TransactionID uint16              `json:"transaction_id"`
+	Opcode        uint8               `json:"opcode"`
+	IsResponse    bool                `json:"is_response"`
+	Truncated     bool                `json:"truncated"`
+	Questions     []MonitoredQuestion `json:"questions,omitempty"`
+	Answers       []MonitoredAnswer   `json:"answers,omitempty"`
+	Authorities   []MonitoredAnswer   `json:"authorities,omitempty"`
+	Additionals   []MonitoredAnswer   `json:"additionals,omitempty"`
+}
+
+func monitoredPacketJSONFrom(p MonitoredPacket) monitoredPacketJSON {
+	var source string
+	if p.Source != nil {
+		source = p.Source.String()
+	}
+	return monitoredPacketJSON{
+		Source:        source,
+		TransactionID: p.TransactionID,
+		Opcode:        p.Opcode,
+		IsResponse:    p.IsResponse,
+		Truncated:     p.Truncated,
+		Questions:     p.Questions,
+		Answers:       p.Answers,
+		Authorities:   p.Authorities,
+		Additionals:   p.Additionals,
+	}
+}
+
+// MetricsSink reports every MonitoredPacket to a metrics.Metrics sink as a
+// "mdns_packets_total" counter increment labeled by packet type
+// (query/response) and source IP, so an operator can chart mDNS traffic
+// volume per host without standing up NDJSON log parsing. This composes
+// with internal/metrics.New the same way responder/querier's own metrics
+// options do, if per-Monitor namespacing or constant labels are needed.
+type MetricsSink struct {
+	metrics metrics.Metrics
+}
+
+var _ MonitorSink = (*MetricsSink)(nil)
+
+// NewMetricsSink creates a MetricsSink reporting through m.
+func NewMetricsSink(m metrics.Metrics) *MetricsSink {
+	return &MetricsSink{metrics: m}
+}
+
+// Observe implements MonitorSink.
+func (s *MetricsSink) Observe(p MonitoredPacket) {
+	packetType := "query"
+	if p.IsResponse {
+		packetType = "response"
+	}
+
+	source := ""
+	if p.Source != nil {
+		if addr, ok := p.Source.(*net.UDPAddr); ok {
+			source = addr.IP.String()
+		} else {
+			source = p.Source.String()
+		}
+	}
+
+	s.metrics.IncCounter("mdns_packets_total", map[string]string{
+		"type":   packetType,
+		"source": source,
+	})
+}
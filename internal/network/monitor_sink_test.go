@@ -0,0 +1,88 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingSink is a MonitorSink test double that records every observed
+// packet.
+type recordingSink struct {
+	mu       sync.Mutex
+	observed []MonitoredPacket
+}
+
+func (s *recordingSink) Observe(p MonitoredPacket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observed = append(s.observed, p)
+}
+
+// TestNDJSONSink_Observe_WritesOneJSONLine verifies that NDJSONSink writes
+// exactly one newline-terminated JSON object per Observe call, with the
+// source address rendered as a string.
+func TestNDJSONSink_Observe_WritesOneJSONLine(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewNDJSONSink(&buf)
+
+	sink.Observe(MonitoredPacket{
+		Source:        &net.UDPAddr{IP: net.ParseIP("10.0.0.5"), Port: 5353},
+		TransactionID: 42,
+		IsResponse:    true,
+		Answers:       []MonitoredAnswer{{Name: "host.local", Type: 1}},
+	})
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 {
+		t.Fatalf("output = %q, want exactly one line", out)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimRight(out, "\n")), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if decoded["source"] != "10.0.0.5:5353" {
+		t.Errorf("decoded[\"source\"] = %v, want 10.0.0.5:5353", decoded["source"])
+	}
+	if decoded["transaction_id"].(float64) != 42 {
+		t.Errorf("decoded[\"transaction_id\"] = %v, want 42", decoded["transaction_id"])
+	}
+}
+
+// TestMetricsSink_Observe_IncrementsCounterWithTypeAndSource verifies that
+// MetricsSink reports mdns_packets_total labeled by packet type and source
+// IP.
+func TestMetricsSink_Observe_IncrementsCounterWithTypeAndSource(t *testing.T) {
+	var gotName string
+	var gotLabels map[string]string
+	sink := NewMetricsSink(recordingMetrics(func(name string, labels map[string]string) {
+		gotName = name
+		gotLabels = labels
+	}))
+
+	sink.Observe(MonitoredPacket{
+		Source:     &net.UDPAddr{IP: net.ParseIP("10.0.0.5"), Port: 5353},
+		IsResponse: true,
+	})
+
+	if gotName != "mdns_packets_total" {
+		t.Errorf("counter name = %q, want mdns_packets_total", gotName)
+	}
+	if gotLabels["type"] != "response" {
+		t.Errorf("labels[type] = %q, want response", gotLabels["type"])
+	}
+	if gotLabels["source"] != "10.0.0.5" {
+		t.Errorf("labels[source] = %q, want 10.0.0.5", gotLabels["source"])
+	}
+}
+
+// recordingMetrics adapts a plain func into a metrics.Metrics, so the test
+// above doesn't need to depend on a specific metrics backend.
+type recordingMetrics func(name string, labels map[string]string)
+
+func (f recordingMetrics) IncCounter(name string, labels map[string]string)  { f(name, labels) }
+func (recordingMetrics) ObserveHistogram(string, float64, map[string]string) {}
@@ -0,0 +1,162 @@
+package network
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestMonitor_Events_DissectsQueryAndResponse verifies that Monitor parses a
+// query and a response it receives into MonitoredPacket, with questions and
+// type-decoded answers populated.
+func TestMonitor_Events_DissectsQueryAndResponse(t *testing.T) {
+	mt := transport.NewMockTransport()
+	m, err := newMonitor(mt)
+	if err != nil {
+		t.Fatalf("newMonitor() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	query, err := message.BuildQuery("host.local", uint16(protocol.RecordTypeA))
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v", err)
+	}
+	src := &net.UDPAddr{IP: net.ParseIP("192.168.1.10"), Port: 5353}
+	mt.QueueReceive(query, src)
+
+	response, err := message.BuildResponse([]*message.ResourceRecord{{
+		Name:       "host.local",
+		Type:       protocol.RecordTypeA,
+		Class:      protocol.ClassIN,
+		TTL:        120,
+		Data:       []byte{192, 168, 1, 55},
+		CacheFlush: true,
+	}})
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v", err)
+	}
+	mt.QueueReceive(response, src)
+
+	var got []MonitoredPacket
+	for i := 0; i < 2; i++ {
+		select {
+		case p := <-m.Events():
+			got = append(got, p)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for packet %d", i)
+		}
+	}
+
+	if got[0].IsResponse {
+		t.Error("first packet IsResponse = true, want false (a query)")
+	}
+	if len(got[0].Questions) != 1 || got[0].Questions[0].Name != "host.local" {
+		t.Errorf("first packet Questions = %+v, want one question for host.local", got[0].Questions)
+	}
+
+	if !got[1].IsResponse {
+		t.Error("second packet IsResponse = false, want true (a response)")
+	}
+	if len(got[1].Answers) != 1 {
+		t.Fatalf("second packet Answers = %+v, want 1 answer", got[1].Answers)
+	}
+	answer := got[1].Answers[0]
+	if !answer.CacheFlush {
+		t.Error("answer.CacheFlush = false, want true")
+	}
+	aData, ok := answer.Data.(message.AData)
+	if !ok {
+		t.Fatalf("answer.Data = %T, want message.AData", answer.Data)
+	}
+	if aData.IP.String() != "192.168.1.55" {
+		t.Errorf("answer.Data.IP = %s, want 192.168.1.55", aData.IP)
+	}
+}
+
+// TestMonitor_NeverSends verifies that Monitor's receive loop never calls
+// Send on its transport - it is a passive, receive-only observer.
+func TestMonitor_NeverSends(t *testing.T) {
+	mt := transport.NewMockTransport()
+	m, err := newMonitor(mt)
+	if err != nil {
+		t.Fatalf("newMonitor() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	query, _ := message.BuildQuery("host.local", uint16(protocol.RecordTypeA))
+	mt.QueueReceive(query, &net.UDPAddr{IP: net.ParseIP("192.168.1.10"), Port: 5353})
+
+	select {
+	case <-m.Events():
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for packet")
+	}
+
+	if len(mt.SendCalls()) != 0 {
+		t.Errorf("SendCalls() = %d, want 0 - Monitor must never transmit", len(mt.SendCalls()))
+	}
+}
+
+// TestMonitor_Close_ClosesEventsChannel verifies that Close stops the
+// receive loop and closes Events().
+func TestMonitor_Close_ClosesEventsChannel(t *testing.T) {
+	mt := transport.NewMockTransport()
+	m, err := newMonitor(mt)
+	if err != nil {
+		t.Fatalf("newMonitor() error = %v", err)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	select {
+	case _, ok := <-m.Events():
+		if ok {
+			t.Error("Events() yielded a packet after Close()")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Events() was not closed by Close()")
+	}
+}
+
+// TestMonitor_WithMonitorSink_ReceivesEveryPacket verifies that a
+// MonitorSink installed via WithMonitorSink observes every packet Monitor
+// dissects, not just the ones drained from Events().
+func TestMonitor_WithMonitorSink_ReceivesEveryPacket(t *testing.T) {
+	sink := &recordingSink{}
+	mt := transport.NewMockTransport()
+	m, err := newMonitor(mt, WithMonitorSink(sink))
+	if err != nil {
+		t.Fatalf("newMonitor() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	query, _ := message.BuildQuery("host.local", uint16(protocol.RecordTypeA))
+	mt.QueueReceive(query, &net.UDPAddr{IP: net.ParseIP("192.168.1.10"), Port: 5353})
+
+	<-m.Events()
+
+	sink.mu.Lock()
+	n := len(sink.observed)
+	sink.mu.Unlock()
+	if n != 1 {
+		t.Errorf("sink observed %d packets, want 1", n)
+	}
+}
+
+// TestDissect_MalformedPacketErrors verifies that dissect rejects a packet
+// too short to contain a DNS header, rather than panicking.
+func TestDissect_MalformedPacketErrors(t *testing.T) {
+	tooShort := make([]byte, 4)
+	binary.BigEndian.PutUint16(tooShort, 0)
+
+	if _, err := dissect(tooShort, nil); err == nil {
+		t.Error("dissect() error = nil, want non-nil for a truncated header")
+	}
+}
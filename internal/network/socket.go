@@ -1,4 +1,19 @@
 // Package network implements UDP multicast socket operations for mDNS.
+//
+// CreateSocket only ever opens an IPv4 (224.0.0.251) socket, and SendQuery/
+// ReceiveResponse only ever operate on one. Dual-stack support - joining
+// both 224.0.0.251 and ff02::fb (protocol.MulticastAddrIPv4/
+// MulticastAddrIPv6), one ipv4.PacketConn/ipv6.PacketConn per address
+// family, scoped per interface, hop limit/TTL 255, loopback enabled the
+// same way CreateSocket's ipv4 path does - lives in internal/transport
+// (UDPv4Transport, UDPv6Transport, DualStackTransport), which this package
+// already depends on for CreateSocket's own ipv4 handling. querier's
+// WithIPFamily(DualStack)/WithDualStack() fan a query out across both
+// families and merge responses into one channel; Responder does the
+// equivalent on the response side. This package is the legacy
+// pre-transport-abstraction socket layer callers are migrating off of (see
+// tests/contract's layer-boundary check); new multicast/IPv6 work belongs
+// in internal/transport, not here.
 package network
 
 import (
@@ -0,0 +1,69 @@
+package network
+
+import "github.com/joshuafuller/beacon/internal/transport"
+
+// NewTransportInterfaceWatcher starts an InterfaceWatcher and returns it as
+// a transport.InterfaceWatcher, translating each InterfaceChange as it
+// arrives. This package can import internal/transport (unlike the reverse -
+// see transport.InterfaceWatcher's doc comment), so it's this package's job
+// to bridge the two: callers that must not import internal/network
+// directly, like querier (FR-002), get their watcher through here instead
+// of calling NewInterfaceWatcher themselves.
+func NewTransportInterfaceWatcher(opts ...WatcherOption) (transport.InterfaceWatcher, error) {
+	w := NewInterfaceWatcher(opts...)
+	if err := w.Start(); err != nil {
+		return nil, err
+	}
+
+	out := make(chan transport.InterfaceChange, 16)
+	go func() {
+		defer close(out)
+		for change := range w.Changes() {
+			ev := transport.InterfaceChange{
+				Type:      transportChangeType(change.Type),
+				Interface: change.Interface,
+				Addr:      change.Addr,
+			}
+			// Non-blocking, like InterfaceWatcher.emit itself: a consumer
+			// that isn't keeping up should lose an event, not wedge this
+			// goroutine (and leak it) forever after Stop.
+			select {
+			case out <- ev:
+			default:
+			}
+		}
+	}()
+
+	return &transportInterfaceWatcher{inner: w, out: out}, nil
+}
+
+// transportChangeType maps an InterfaceChangeType onto its
+// transport-package equivalent. A switch, rather than a numeric cast, so
+// the two enums stay decoupled - either can reorder or grow its constants
+// without silently corrupting the other.
+func transportChangeType(t InterfaceChangeType) transport.InterfaceChangeType {
+	switch t {
+	case InterfaceUp:
+		return transport.InterfaceUp
+	case InterfaceDown:
+		return transport.InterfaceDown
+	case AddressAdded:
+		return transport.AddressAdded
+	case AddressRemoved:
+		return transport.AddressRemoved
+	default:
+		return transport.InterfaceChangeType(t)
+	}
+}
+
+// transportInterfaceWatcher adapts *InterfaceWatcher to
+// transport.InterfaceWatcher by forwarding Stop and exposing the
+// translated channel built in NewTransportInterfaceWatcher.
+type transportInterfaceWatcher struct {
+	inner *InterfaceWatcher
+	out   chan transport.InterfaceChange
+}
+
+func (t *transportInterfaceWatcher) Changes() <-chan transport.InterfaceChange { return t.out }
+
+func (t *transportInterfaceWatcher) Stop() { t.inner.Stop() }
@@ -0,0 +1,301 @@
+package network
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval is how often InterfaceWatcher polls net.Interfaces()
+// for changes when no interval is configured.
+const defaultWatchInterval = 5 * time.Second
+
+// InterfaceChangeType identifies whether an interface started or stopped
+// being usable for mDNS multicast, or kept its presence but gained/lost an
+// address.
+type InterfaceChangeType int
+
+const (
+	// InterfaceUp indicates an interface that newly passed
+	// DefaultInterfaces's filtering (e.g. Wi-Fi reassociated, a cable was
+	// plugged in).
+	InterfaceUp InterfaceChangeType = iota
+
+	// InterfaceDown indicates a previously usable interface that
+	// disappeared or no longer passes filtering (e.g. it went down, or a
+	// VPN took over the default route).
+	InterfaceDown
+
+	// AddressAdded indicates a still-usable interface gained an address
+	// (e.g. DHCP lease renewed on a new subnet, SLAAC assigned a new
+	// prefix).
+	AddressAdded
+
+	// AddressRemoved indicates a still-usable interface lost an address.
+	AddressRemoved
+)
+
+// InterfaceChange reports one interface transitioning in or out of the
+// usable set, or one address appearing or disappearing on an
+// already-usable interface. Addr is only set for AddressAdded/AddressRemoved.
+type InterfaceChange struct {
+	Type      InterfaceChangeType
+	Interface net.Interface
+	Addr      net.Addr
+}
+
+// changeSignal is a platform hook that wakes pollLoop immediately when the
+// OS reports an interface or address change, instead of waiting for the
+// next WithWatchInterval tick. It's an optimization, not a dependency: every
+// platform still falls back to interval polling as a safety net (a missed
+// netlink/route-socket message under load, or no native mechanism at all),
+// so a nil or erroring changeSignal is never fatal.
+type changeSignal interface {
+	// C returns a channel that receives a value (coalesced, not one per OS
+	// event) whenever the interface/address set may have changed.
+	C() <-chan struct{}
+	Close() error
+}
+
+// newChangeSignal opens this platform's native change-notification source.
+// It's a package-level var, overridden per OS in watcher_linux.go and
+// watcher_darwin.go; the portable default (watcher_fallback.go, used on
+// Windows and everywhere else without a native binding) returns nil, nil -
+// "no native signal, rely on interval polling only."
+var newChangeSignal = newChangeSignalDefault
+
+// InterfaceWatcher periodically diffs the host's network interfaces against
+// DefaultInterfaces's VPN/Docker/loopback/down filtering and reports
+// additions and removals on a channel. Where the current platform provides
+// one, it also watches a native change-notification source to poll
+// immediately instead of waiting out the remainder of the interval.
+//
+// This keeps polling as its primary mechanism rather than fully trusting
+// native notifications: RTM_NEWLINK/RTM_NEWADDR-style events tell you
+// *something* changed, not reliably *what*, and can be dropped under load.
+// Re-running the exclusion filters on every poll is what actually matters: a
+// freshly connected utun0 or veth* is never reported as usable, whether the
+// poll was triggered by the ticker or a native signal.
+type InterfaceWatcher struct {
+	interval time.Duration
+	changes  chan InterfaceChange
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	current map[string]ifaceSnapshot // name -> last-seen usable interface + addresses
+
+	// listInterfaces is DefaultInterfaces by default; overridable in tests
+	// so they don't depend on the host's actual network state.
+	listInterfaces func() ([]net.Interface, error)
+
+	// newSignal is newChangeSignal by default; overridable in tests so they
+	// don't depend on the host's actual netlink/route-socket availability.
+	newSignal func() (changeSignal, error)
+}
+
+// ifaceSnapshot is one interface's last-seen identity and address set, used
+// to diff both interface presence and per-address changes across polls.
+type ifaceSnapshot struct {
+	iface net.Interface
+	addrs map[string]net.Addr // addr.String() -> addr
+}
+
+// WatcherOption configures an InterfaceWatcher.
+type WatcherOption func(*InterfaceWatcher)
+
+// WithWatchInterval overrides the default 5-second poll interval.
+func WithWatchInterval(interval time.Duration) WatcherOption {
+	return func(w *InterfaceWatcher) {
+		w.interval = interval
+	}
+}
+
+// NewInterfaceWatcher creates an InterfaceWatcher. Call Start to begin
+// polling; Changes returns the channel of reported transitions.
+func NewInterfaceWatcher(opts ...WatcherOption) *InterfaceWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &InterfaceWatcher{
+		interval:       defaultWatchInterval,
+		changes:        make(chan InterfaceChange, 16),
+		ctx:            ctx,
+		cancel:         cancel,
+		current:        make(map[string]ifaceSnapshot),
+		listInterfaces: DefaultInterfaces,
+		newSignal:      newChangeSignal,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start takes an initial snapshot (emitting no changes for it - the caller
+// is expected to have already bound whatever interfaces were usable at
+// construction time) and begins polling in the background.
+func (w *InterfaceWatcher) Start() error {
+	ifaces, err := w.listInterfaces()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	for _, iface := range ifaces {
+		w.current[iface.Name] = snapshotOf(iface)
+	}
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+	go w.pollLoop()
+	return nil
+}
+
+// Changes returns the channel InterfaceWatcher reports transitions on. The
+// channel is closed once Stop returns.
+func (w *InterfaceWatcher) Changes() <-chan InterfaceChange {
+	return w.changes
+}
+
+// Stop ends polling and closes the Changes channel.
+func (w *InterfaceWatcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+	close(w.changes)
+}
+
+// pollLoop re-lists interfaces every interval (or sooner, if a native
+// changeSignal fires first), diffs against the last-seen usable set, and
+// emits one InterfaceChange per addition, removal, or address change.
+func (w *InterfaceWatcher) pollLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	signal, err := w.newSignal()
+	if err != nil {
+		signal = nil // no native signal available; interval polling still runs
+	}
+	if signal != nil {
+		defer func() { _ = signal.Close() }()
+	}
+
+	var signalC <-chan struct{}
+	if signal != nil {
+		signalC = signal.C()
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll()
+		case <-signalC:
+			w.poll()
+		}
+	}
+}
+
+// watcherInterfaceAddrs is (*net.Interface).Addrs as a package-level var, so
+// tests can supply a fake interface's addresses without needing a real,
+// addressed NIC - the same seam transport.interfaceAddrs uses.
+var watcherInterfaceAddrs = func(iface net.Interface) ([]net.Addr, error) {
+	return iface.Addrs()
+}
+
+// snapshotOf captures iface's name, flags and current addresses so a later
+// poll can diff both interface presence and per-address changes against it.
+func snapshotOf(iface net.Interface) ifaceSnapshot {
+	snap := ifaceSnapshot{iface: iface, addrs: make(map[string]net.Addr)}
+
+	addrs, err := watcherInterfaceAddrs(iface)
+	if err != nil {
+		return snap
+	}
+	for _, addr := range addrs {
+		snap.addrs[addr.String()] = addr
+	}
+	return snap
+}
+
+// poll re-runs DefaultInterfaces (so VPN/Docker exclusion applies to
+// whatever just appeared) and emits one InterfaceChange per interface
+// addition/removal or, for interfaces present in both polls, per address
+// addition/removal.
+func (w *InterfaceWatcher) poll() {
+	ifaces, err := w.listInterfaces()
+	if err != nil {
+		// A transient failure to enumerate interfaces isn't itself a
+		// change; try again next tick rather than reporting everything as
+		// removed.
+		return
+	}
+
+	seen := make(map[string]ifaceSnapshot, len(ifaces))
+	for _, iface := range ifaces {
+		seen[iface.Name] = snapshotOf(iface)
+	}
+
+	w.mu.Lock()
+	var added, removed []net.Interface
+	var addrChanges []InterfaceChange
+	for name, snap := range seen {
+		prev, ok := w.current[name]
+		if !ok {
+			added = append(added, snap.iface)
+			continue
+		}
+		addrChanges = append(addrChanges, diffAddrs(snap, prev)...)
+	}
+	for name, snap := range w.current {
+		if _, ok := seen[name]; !ok {
+			removed = append(removed, snap.iface)
+		}
+	}
+	w.current = seen
+	w.mu.Unlock()
+
+	for _, iface := range added {
+		w.emit(InterfaceChange{Type: InterfaceUp, Interface: iface})
+	}
+	for _, iface := range removed {
+		w.emit(InterfaceChange{Type: InterfaceDown, Interface: iface})
+	}
+	for _, change := range addrChanges {
+		w.emit(change)
+	}
+}
+
+// diffAddrs compares cur against prev for one interface present in both
+// polls, returning one AddressAdded/AddressRemoved InterfaceChange per
+// address that appeared or disappeared.
+func diffAddrs(cur, prev ifaceSnapshot) []InterfaceChange {
+	var changes []InterfaceChange
+	for key, addr := range cur.addrs {
+		if _, ok := prev.addrs[key]; !ok {
+			changes = append(changes, InterfaceChange{Type: AddressAdded, Interface: cur.iface, Addr: addr})
+		}
+	}
+	for key, addr := range prev.addrs {
+		if _, ok := cur.addrs[key]; !ok {
+			changes = append(changes, InterfaceChange{Type: AddressRemoved, Interface: prev.iface, Addr: addr})
+		}
+	}
+	return changes
+}
+
+// emit sends change on the Changes channel, dropping it rather than
+// blocking if the consumer isn't keeping up.
+func (w *InterfaceWatcher) emit(change InterfaceChange) {
+	select {
+	case w.changes <- change:
+	default:
+	}
+}
@@ -0,0 +1,65 @@
+//go:build darwin
+
+package network
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// routeSignal is changeSignal's Darwin/BSD implementation: a PF_ROUTE raw
+// socket, which the kernel writes RTM_IFINFO/RTM_NEWADDR/RTM_DELADDR
+// messages to whenever a link or address changes.
+//
+// As with netlinkSignal on Linux, it doesn't decode each message's type -
+// any message on this socket means "go re-poll", which is all pollLoop
+// needs from it.
+type routeSignal struct {
+	fd int
+	c  chan struct{}
+}
+
+// newChangeSignalDefault opens the route socket. Any failure (e.g.
+// permission denied in a restrictive sandbox) falls back to interval-only
+// polling rather than failing the whole watcher.
+func newChangeSignalDefault() (changeSignal, error) {
+	fd, err := unix.Socket(unix.AF_ROUTE, unix.SOCK_RAW, unix.AF_UNSPEC)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &routeSignal{
+		fd: fd,
+		c:  make(chan struct{}, 1),
+	}
+	go s.readLoop()
+	return s, nil
+}
+
+// readLoop blocks on Read until a route message arrives (or the socket is
+// closed), then signals C() - coalescing with whatever's already buffered
+// rather than blocking.
+func (s *routeSignal) readLoop() {
+	buf := make([]byte, 4096)
+	for {
+		n, err := unix.Read(s.fd, buf)
+		if err != nil || n == 0 {
+			// Closed deliberately, or the socket died outright - either way
+			// there's nothing more to read. Interval polling carries on
+			// without us.
+			return
+		}
+
+		select {
+		case s.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *routeSignal) C() <-chan struct{} {
+	return s.c
+}
+
+func (s *routeSignal) Close() error {
+	return unix.Close(s.fd)
+}
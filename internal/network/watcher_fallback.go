@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package network
+
+// newChangeSignalDefault is the portable fallback used on Windows and any
+// other platform without a native binding in this package: it reports no
+// native signal, so InterfaceWatcher relies solely on interval polling.
+//
+// Windows does have a native mechanism (NotifyIpInterfaceChange /
+// NotifyUnicastIpAddressChange in iphlpapi.dll), but wiring it up needs
+// cgo or a hand-rolled syscall binding neither of which any other file in
+// this package currently takes on for Windows. Interval polling already
+// covers the correctness requirement (DefaultInterfaces's filters still run
+// every poll); this only costs Windows users the faster detection latency
+// Linux/Darwin get from their native signal, not correctness.
+func newChangeSignalDefault() (changeSignal, error) {
+	return nil, nil
+}
@@ -0,0 +1,76 @@
+//go:build linux
+
+package network
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// netlinkSignal is changeSignal's Linux implementation: an AF_NETLINK
+// NETLINK_ROUTE socket bound to RTMGRP_LINK|RTMGRP_IPV4_IFADDR|
+// RTMGRP_IPV6_IFADDR, so the kernel pushes a message whenever a link or
+// address changes.
+//
+// It deliberately doesn't decode each message's RTM_NEWLINK/RTM_DELADDR
+// type or attributes - see the changeSignal doc comment on why a trigger is
+// enough - it just wakes readLoop, which reads (and discards) whatever's
+// pending and signals once per batch.
+type netlinkSignal struct {
+	fd int
+	c  chan struct{}
+}
+
+// newChangeSignalDefault opens and binds the netlink socket. Any failure
+// (e.g. permission denied in a restrictive sandbox/container) falls back to
+// interval-only polling rather than failing the whole watcher.
+func newChangeSignalDefault() (changeSignal, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: unix.RTMGRP_LINK | unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR,
+	}
+	if err := unix.Bind(fd, addr); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+
+	s := &netlinkSignal{
+		fd: fd,
+		c:  make(chan struct{}, 1),
+	}
+	go s.readLoop()
+	return s, nil
+}
+
+// readLoop blocks on Read until a netlink message arrives (or the socket is
+// closed), then signals C() - coalescing with whatever's already buffered
+// rather than blocking, since pollLoop only cares that *something* changed.
+func (s *netlinkSignal) readLoop() {
+	buf := make([]byte, 4096)
+	for {
+		n, err := unix.Read(s.fd, buf)
+		if err != nil || n == 0 {
+			// Closed deliberately, or the socket died outright - either way
+			// there's nothing more to read. Interval polling carries on
+			// without us.
+			return
+		}
+
+		select {
+		case s.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *netlinkSignal) C() <-chan struct{} {
+	return s.c
+}
+
+func (s *netlinkSignal) Close() error {
+	return unix.Close(s.fd)
+}
@@ -0,0 +1,189 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestInterfaceWatcher_EmitsUpAndDown validates that InterfaceWatcher
+// reports InterfaceUp when a new interface appears on a later poll, and
+// InterfaceDown when a previously-seen interface disappears.
+func TestInterfaceWatcher_EmitsUpAndDown(t *testing.T) {
+	eth0 := net.Interface{Name: "eth0"}
+	wlan0 := net.Interface{Name: "wlan0"}
+
+	var current []net.Interface
+	var mu sync.Mutex
+
+	w := NewInterfaceWatcher(WithWatchInterval(10 * time.Millisecond))
+	w.listInterfaces = func() ([]net.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		out := make([]net.Interface, len(current))
+		copy(out, current)
+		return out, nil
+	}
+
+	mu.Lock()
+	current = []net.Interface{eth0}
+	mu.Unlock()
+
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	t.Cleanup(w.Stop)
+
+	// wlan0 appears.
+	mu.Lock()
+	current = []net.Interface{eth0, wlan0}
+	mu.Unlock()
+
+	select {
+	case change := <-w.Changes():
+		if change.Type != InterfaceUp || change.Interface.Name != "wlan0" {
+			t.Fatalf("change = %+v, want InterfaceUp wlan0", change)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no InterfaceUp received for wlan0")
+	}
+
+	// eth0 disappears.
+	mu.Lock()
+	current = []net.Interface{wlan0}
+	mu.Unlock()
+
+	select {
+	case change := <-w.Changes():
+		if change.Type != InterfaceDown || change.Interface.Name != "eth0" {
+			t.Fatalf("change = %+v, want InterfaceDown eth0", change)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no InterfaceDown received for eth0")
+	}
+}
+
+// TestInterfaceWatcher_EmitsAddressAddedAndRemoved validates that
+// InterfaceWatcher reports AddressAdded/AddressRemoved for an interface that
+// stays present across polls but gains or loses an address.
+func TestInterfaceWatcher_EmitsAddressAddedAndRemoved(t *testing.T) {
+	eth0 := net.Interface{Name: "eth0"}
+	addr1 := &net.IPNet{IP: net.ParseIP("192.168.1.5"), Mask: net.CIDRMask(24, 32)}
+	addr2 := &net.IPNet{IP: net.ParseIP("192.168.1.6"), Mask: net.CIDRMask(24, 32)}
+
+	var mu sync.Mutex
+	addrs := []net.Addr{addr1}
+
+	origAddrs := watcherInterfaceAddrs
+	defer func() { watcherInterfaceAddrs = origAddrs }()
+	watcherInterfaceAddrs = func(net.Interface) ([]net.Addr, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		out := make([]net.Addr, len(addrs))
+		copy(out, addrs)
+		return out, nil
+	}
+
+	w := NewInterfaceWatcher(WithWatchInterval(10 * time.Millisecond))
+	w.listInterfaces = func() ([]net.Interface, error) { return []net.Interface{eth0}, nil }
+
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	t.Cleanup(w.Stop)
+
+	// eth0 gains addr2.
+	mu.Lock()
+	addrs = []net.Addr{addr1, addr2}
+	mu.Unlock()
+
+	select {
+	case change := <-w.Changes():
+		if change.Type != AddressAdded || change.Addr.String() != addr2.String() {
+			t.Fatalf("change = %+v, want AddressAdded %v", change, addr2)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no AddressAdded received for addr2")
+	}
+
+	// eth0 loses addr1.
+	mu.Lock()
+	addrs = []net.Addr{addr2}
+	mu.Unlock()
+
+	select {
+	case change := <-w.Changes():
+		if change.Type != AddressRemoved || change.Addr.String() != addr1.String() {
+			t.Fatalf("change = %+v, want AddressRemoved %v", change, addr1)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no AddressRemoved received for addr1")
+	}
+}
+
+// TestInterfaceWatcher_ChangeSignalTriggersImmediatePoll validates that a
+// changeSignal firing causes pollLoop to poll right away, rather than
+// waiting for the (here, very long) ticker interval.
+func TestInterfaceWatcher_ChangeSignalTriggersImmediatePoll(t *testing.T) {
+	eth0 := net.Interface{Name: "eth0"}
+
+	var mu sync.Mutex
+	current := []net.Interface{}
+
+	sig := &fakeChangeSignal{c: make(chan struct{}, 1)}
+
+	w := NewInterfaceWatcher(WithWatchInterval(time.Hour))
+	w.listInterfaces = func() ([]net.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		out := make([]net.Interface, len(current))
+		copy(out, current)
+		return out, nil
+	}
+	w.newSignal = func() (changeSignal, error) { return sig, nil }
+
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	t.Cleanup(w.Stop)
+
+	mu.Lock()
+	current = []net.Interface{eth0}
+	mu.Unlock()
+	sig.c <- struct{}{}
+
+	select {
+	case change := <-w.Changes():
+		if change.Type != InterfaceUp || change.Interface.Name != "eth0" {
+			t.Fatalf("change = %+v, want InterfaceUp eth0", change)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("changeSignal did not trigger an immediate poll")
+	}
+}
+
+// fakeChangeSignal is a test-only changeSignal whose channel the test
+// controls directly.
+type fakeChangeSignal struct {
+	c chan struct{}
+}
+
+func (f *fakeChangeSignal) C() <-chan struct{} { return f.c }
+func (f *fakeChangeSignal) Close() error       { return nil }
+
+// TestInterfaceWatcher_Stop validates that Stop closes the Changes channel.
+func TestInterfaceWatcher_Stop(t *testing.T) {
+	w := NewInterfaceWatcher(WithWatchInterval(10 * time.Millisecond))
+	w.listInterfaces = func() ([]net.Interface, error) { return nil, nil }
+
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	w.Stop()
+
+	_, open := <-w.Changes()
+	if open {
+		t.Error("Changes() channel still open after Stop")
+	}
+}
@@ -0,0 +1,181 @@
+package protocol
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// idnaProfile applies UTS-46 processing (case-folding, normalization, and
+// IDNA2008 validity checks) to a label per RFC 5891.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.BidiRule(),
+)
+
+// EncodeName applies IDNA2008/UTS-46 processing to each dot-separated label
+// of name: lowercasing ASCII labels and emitting the xn-- prefixed ACE
+// (Punycode) form for any label containing non-ASCII characters. A purely
+// ASCII name is returned unchanged apart from lowercasing.
+//
+// This is for domain-name-shaped labels (hostnames, service-type labels),
+// not DNS-SD instance labels - RFC 6763 §4.1 instance names carry UTF-8
+// directly on the wire (see message.EncodeServiceInstanceName) and must not
+// be Punycode-transformed.
+func EncodeName(name string) (string, error) {
+	if name == "" || name == "." {
+		return name, nil
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if label == "" {
+			continue
+		}
+		encoded, err := idnaProfile.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("IDNA-encoding label %q: %w", label, err)
+		}
+		labels[i] = encoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// DecodeName reverses EncodeName, converting any xn-- prefixed ACE
+// (Punycode) label of name back to its Unicode presentation form per RFC
+// 5891. A label without the xn-- prefix is not Punycode-decoded, but it
+// still gets the same UTS-46 mapping (e.g. lowercasing) EncodeName applies.
+func DecodeName(name string) (string, error) {
+	if name == "" || name == "." {
+		return name, nil
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if label == "" {
+			continue
+		}
+		decoded, err := idnaProfile.ToUnicode(label)
+		if err != nil {
+			return "", fmt.Errorf("IDNA-decoding label %q: %w", label, err)
+		}
+		labels[i] = decoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// ValidateNameUTF8 validates name the way ValidateName does, except each
+// label may contain arbitrary UTF-8 rather than only [a-z0-9-_], per RFC
+// 6763 §4.1's explicit permission of UTF-8 in DNS-SD instance labels.
+//
+// Per RFC 6763 §4.1.1, the 63-octet label limit is measured in UTF-8 bytes
+// rather than characters. PRECIS FreeformClass-style restrictions apply in
+// place of IDNA2008's stricter validity rules (which forbid symbols like
+// emoji that §4.1 explicitly expects to work): control characters
+// (U+0000-U+001F, U+007F) are forbidden, and a label must not mix
+// left-to-right and right-to-left strong characters (a simplified form of
+// RFC 5893's Bidi Rule).
+func ValidateNameUTF8(name string) error {
+	if name == "" {
+		return &errors.ValidationError{
+			Field:   "name",
+			Value:   name,
+			Message: "name cannot be empty",
+		}
+	}
+
+	trimmed := strings.TrimSuffix(name, ".")
+	labels := strings.Split(trimmed, ".")
+
+	for i, label := range labels {
+		if err := validateUTF8Label(label, i); err != nil {
+			return &errors.ValidationError{
+				Field:   "name",
+				Value:   name,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateUTF8Label validates a single UTF-8 label per ValidateNameUTF8's
+// PRECIS FreeformClass-style rules.
+func validateUTF8Label(label string, position int) error {
+	if label == "" {
+		return fmt.Errorf("empty label at position %d (consecutive dots)", position)
+	}
+
+	if len(label) > MaxLabelLength {
+		return fmt.Errorf("label %q exceeds maximum length %d UTF-8 bytes per RFC 6763 §4.1.1", label, MaxLabelLength)
+	}
+
+	if !utf8.ValidString(label) {
+		return fmt.Errorf("label %q is not valid UTF-8", label)
+	}
+
+	hasLTR, hasRTL := false, false
+	for _, r := range label {
+		if isForbiddenControlRune(r) {
+			return fmt.Errorf("label %q contains control character %U (forbidden per PRECIS FreeformClass)", label, r)
+		}
+
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.L:
+			hasLTR = true
+		case bidi.R, bidi.AL:
+			hasRTL = true
+		}
+	}
+	if hasLTR && hasRTL {
+		return fmt.Errorf("label %q mixes left-to-right and right-to-left characters (forbidden per RFC 5893 Bidi Rule)", label)
+	}
+
+	return nil
+}
+
+// isForbiddenControlRune reports whether r is one of the C0 control
+// characters (U+0000-U+001F) or DEL (U+007F), forbidden in a PRECIS
+// FreeformClass label regardless of direction or length.
+func isForbiddenControlRune(r rune) bool {
+	return (r >= 0x00 && r <= 0x1F) || r == 0x7F
+}
+
+// ValidateInstanceLabelUTF8 validates name as a single RFC 6763 §4.1 DNS-SD
+// instance-name label using the same PRECIS FreeformClass-style rules as
+// ValidateNameUTF8 (no control characters, no mixed Bidi direction,
+// 63-octet UTF-8 limit). Unlike ValidateNameUTF8, a "." in name is not
+// treated as a label separator: an mDNS instance name is exactly one label,
+// and a literal dot inside it (e.g. "R2-D2's Printer") doesn't denote a
+// domain hierarchy the way it would in a hostname or service type.
+//
+// Callers normalize name to NFC (golang.org/x/text/unicode/norm) before
+// calling this, so the 63-octet limit is measured on the same composed
+// form that ends up on the wire rather than on whatever denormalized form
+// the caller happened to supply.
+func ValidateInstanceLabelUTF8(name string) error {
+	if name == "" {
+		return &errors.ValidationError{
+			Field:   "name",
+			Value:   name,
+			Message: "name cannot be empty",
+		}
+	}
+
+	if err := validateUTF8Label(name, 0); err != nil {
+		return &errors.ValidationError{
+			Field:   "name",
+			Value:   name,
+			Message: err.Error(),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestEncodeName validates that EncodeName Punycode-encodes non-ASCII labels
+// and lowercases ASCII ones, leaving an all-ASCII name otherwise unchanged.
+func TestEncodeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "empty name",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "root name",
+			input: ".",
+			want:  ".",
+		},
+		{
+			name:  "pure ASCII name is lowercased",
+			input: "MyHost.Local",
+			want:  "myhost.local",
+		},
+		{
+			name:  "single non-ASCII label is Punycode-encoded",
+			input: "café.local",
+			want:  "xn--caf-dma.local",
+		},
+		{
+			name:  "multi-label name with one non-ASCII label",
+			input: "printer.café.local",
+			want:  "printer.xn--caf-dma.local",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeName(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("EncodeName() = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EncodeName() error = %v, want nil", err)
+			}
+			if got != tt.want {
+				t.Errorf("EncodeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDecodeName validates that DecodeName reverses EncodeName's Punycode
+// conversion, leaving an already-Unicode or plain ASCII name unchanged.
+func TestDecodeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "empty name",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "root name",
+			input: ".",
+			want:  ".",
+		},
+		{
+			name:  "pure ASCII name is unchanged",
+			input: "myhost.local",
+			want:  "myhost.local",
+		},
+		{
+			name:  "single Punycode label is decoded",
+			input: "xn--caf-dma.local",
+			want:  "café.local",
+		},
+		{
+			name:    "invalid Punycode label",
+			input:   "xn---.local",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeName(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("DecodeName() = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeName() error = %v, want nil", err)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateNameUTF8 validates that ValidateNameUTF8 accepts UTF-8 labels
+// (including emoji and CJK) rejected by ValidateName's [a-z0-9-_] rule, while
+// still rejecting control characters, oversized labels, and mixed-direction
+// text per RFC 5893's Bidi Rule.
+func TestValidateNameUTF8(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "plain ASCII instance name",
+			input: "Office Printer._http._tcp.local",
+		},
+		{
+			name:  "emoji instance label",
+			input: "🎉 Party Speaker._http._tcp.local",
+		},
+		{
+			name:  "CJK instance label",
+			input: "客厅电视._http._tcp.local",
+		},
+		{
+			name:    "empty name",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "consecutive dots produce an empty label",
+			input:   "foo..local",
+			wantErr: true,
+		},
+		{
+			name:    "label exceeds 63 UTF-8 bytes",
+			input:   strings.Repeat("a", 64) + ".local",
+			wantErr: true,
+		},
+		{
+			name:    "control character is forbidden",
+			input:   "bad\x00name.local",
+			wantErr: true,
+		},
+		{
+			name:    "mixed LTR and RTL characters in one label",
+			input:   "abcأحمد.local",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNameUTF8(tt.input)
+			if tt.wantErr && err == nil {
+				t.Error("ValidateNameUTF8() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateNameUTF8() = %v, want nil", err)
+			}
+		})
+	}
+}
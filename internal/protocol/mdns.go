@@ -25,6 +25,10 @@ const (
 	//
 	// FR-004: System MUST use mDNS port 5353 and multicast address 224.0.0.251 for IPv4 queries
 	MulticastAddrIPv4 = "224.0.0.251"
+
+	// MulticastAddrIPv6 is the mDNS IPv6 link-local multicast address
+	// (ff02::fb) per RFC 6762 §5.
+	MulticastAddrIPv6 = "ff02::fb"
 )
 
 // MulticastGroupIPv4 returns the mDNS IPv4 multicast group address.
@@ -40,9 +44,33 @@ func MulticastGroupIPv4() *net.UDPAddr {
 	}
 }
 
+// MulticastGroupIPv6 returns the mDNS IPv6 link-local multicast group
+// address. zone, if non-empty, scopes the address to a specific interface
+// (net.UDPAddr.Zone) as IPv6 link-local addresses require per RFC 4007.
+func MulticastGroupIPv6(zone string) *net.UDPAddr {
+	return &net.UDPAddr{
+		// This IS the protocol package that defines MulticastAddrIPv6 constant
+		IP:   net.ParseIP(MulticastAddrIPv6), // nosemgrep: beacon-hardcoded-multicast-address
+		Port: Port,
+		Zone: zone,
+	}
+}
+
+// MulticastGroupIPv6ForInterface is MulticastGroupIPv6 with the zone taken
+// from ifi.Name, the form RFC 4007 scoped addresses expect and net.UDPAddr.Zone
+// accepts on this platform. A nil ifi yields an unscoped address, equivalent
+// to MulticastGroupIPv6("").
+func MulticastGroupIPv6ForInterface(ifi *net.Interface) *net.UDPAddr {
+	if ifi == nil {
+		return MulticastGroupIPv6("")
+	}
+	return MulticastGroupIPv6(ifi.Name)
+}
+
 // RecordType represents a DNS record type per RFC 1035 §3.2.2.
 //
-// M1 supports A, PTR, SRV, and TXT record types.
+// See IsSupported for the full set Beacon accepts, which now includes AAAA
+// (RFC 3596) alongside M1's original A, PTR, SRV, and TXT.
 //
 // FR-002: System MUST support querying for A, PTR, SRV, and TXT record types
 type RecordType uint16
@@ -56,6 +84,11 @@ const (
 	// Type value: 1
 	RecordTypeA RecordType = 1
 
+	// RecordTypeAAAA represents an AAAA (IPv6 address) record per RFC 3596 §2.1.
+	//
+	// Type value: 28
+	RecordTypeAAAA RecordType = 28
+
 	// RecordTypePTR represents a PTR (pointer/domain name) record per RFC 1035 §3.3.12.
 	//
 	// Used for service instance enumeration in DNS-SD.
@@ -80,6 +113,72 @@ const (
 	// Used for probing to detect conflicts for all record types.
 	// Type value: 255
 	RecordTypeANY RecordType = 255
+
+	// RecordTypeRRSIG represents an RRSIG (DNSSEC signature) record per RFC 4034 §3.1.
+	//
+	// Type value: 46
+	RecordTypeRRSIG RecordType = 46
+
+	// RecordTypeDNSKEY represents a DNSKEY (DNSSEC public key) record per RFC 4034 §2.1.
+	//
+	// Type value: 48
+	RecordTypeDNSKEY RecordType = 48
+
+	// RecordTypeDS represents a DS (Delegation Signer) record per RFC 4034 §5.1.
+	//
+	// Type value: 43
+	RecordTypeDS RecordType = 43
+
+	// RecordTypeNSEC represents an NSEC (authenticated denial of existence)
+	// record per RFC 4034 §4.1.
+	//
+	// Type value: 47
+	RecordTypeNSEC RecordType = 47
+
+	// RecordTypeNSEC3 represents an NSEC3 (hashed authenticated denial of
+	// existence) record per RFC 5155 §3.
+	//
+	// Type value: 50
+	RecordTypeNSEC3 RecordType = 50
+
+	// RecordTypeOPT represents the EDNS(0) pseudo-resource-record per RFC 6891 §6.1.2.
+	//
+	// OPT never describes a real resource and is never a valid query type -
+	// see message.OPTRecord for how its CLASS/TTL fields are repurposed.
+	// Type value: 41
+	RecordTypeOPT RecordType = 41
+
+	// RecordTypeSVCB represents an SVCB (service binding) record per RFC 9460 §2.
+	//
+	// Type value: 64
+	RecordTypeSVCB RecordType = 64
+
+	// RecordTypeHTTPS represents an HTTPS (HTTPS service binding) record per RFC 9460 §2.
+	//
+	// HTTPS is identical to SVCB on the wire except for its TYPE value and the
+	// defaulting rules a client applies when no record is found.
+	// Type value: 65
+	RecordTypeHTTPS RecordType = 65
+
+	// RecordTypeNS represents an NS (authoritative name server) record per RFC 1035 §3.3.11.
+	//
+	// Type value: 2
+	RecordTypeNS RecordType = 2
+
+	// RecordTypeCNAME represents a CNAME (canonical name) record per RFC 1035 §3.3.1.
+	//
+	// Type value: 5
+	RecordTypeCNAME RecordType = 5
+
+	// RecordTypeSOA represents an SOA (start of authority) record per RFC 1035 §3.3.13.
+	//
+	// Type value: 6
+	RecordTypeSOA RecordType = 6
+
+	// RecordTypeHINFO represents a HINFO (host information) record per RFC 1035 §3.3.2.
+	//
+	// Type value: 13
+	RecordTypeHINFO RecordType = 13
 )
 
 // String returns the human-readable name for a RecordType.
@@ -87,6 +186,8 @@ func (rt RecordType) String() string {
 	switch rt {
 	case RecordTypeA:
 		return "A"
+	case RecordTypeAAAA:
+		return "AAAA"
 	case RecordTypePTR:
 		return "PTR"
 	case RecordTypeTXT:
@@ -95,6 +196,30 @@ func (rt RecordType) String() string {
 		return "SRV"
 	case RecordTypeANY:
 		return "ANY"
+	case RecordTypeRRSIG:
+		return "RRSIG"
+	case RecordTypeDNSKEY:
+		return "DNSKEY"
+	case RecordTypeDS:
+		return "DS"
+	case RecordTypeNSEC:
+		return "NSEC"
+	case RecordTypeNSEC3:
+		return "NSEC3"
+	case RecordTypeSVCB:
+		return "SVCB"
+	case RecordTypeHTTPS:
+		return "HTTPS"
+	case RecordTypeOPT:
+		return "OPT"
+	case RecordTypeNS:
+		return "NS"
+	case RecordTypeCNAME:
+		return "CNAME"
+	case RecordTypeSOA:
+		return "SOA"
+	case RecordTypeHINFO:
+		return "HINFO"
 	default:
 		return "UNKNOWN"
 	}
@@ -107,13 +232,26 @@ func (rt RecordType) String() string {
 // RFC 6762 §8.1: ANY type (255) is required for probing
 func (rt RecordType) IsSupported() bool {
 	switch rt {
-	case RecordTypeA, RecordTypePTR, RecordTypeTXT, RecordTypeSRV, RecordTypeANY:
+	case RecordTypeA, RecordTypeAAAA, RecordTypePTR, RecordTypeTXT, RecordTypeSRV, RecordTypeANY, RecordTypeRRSIG, RecordTypeDNSKEY, RecordTypeDS, RecordTypeNSEC, RecordTypeNSEC3, RecordTypeSVCB, RecordTypeHTTPS, RecordTypeNS, RecordTypeCNAME, RecordTypeSOA, RecordTypeHINFO:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsPseudoRecord returns true if the RecordType never describes a real,
+// storable resource - only OPT (RFC 6891 §6.1.2), which repurposes the
+// CLASS/TTL fields for EDNS(0) metadata instead of a CLASS/RDATA pair.
+//
+// ANY (255) is a query-only QTYPE (RFC 1035 §3.2.3), never a record TYPE
+// an answer section actually carries, but it isn't a pseudo-record in
+// OPT's sense: IsSupported already accepts it for probe queries, and
+// callers that need to exclude "not a concrete answer type" from both
+// should check IsPseudoRecord() || rt == RecordTypeANY explicitly.
+func (rt RecordType) IsPseudoRecord() bool {
+	return rt == RecordTypeOPT
+}
+
 // DNSClass represents a DNS class per RFC 1035 §3.2.4.
 //
 // M1 uses the IN (Internet) class for all queries.
@@ -149,9 +287,15 @@ const (
 	// RFC 6762 §18.5: In query messages, if the TC bit is set, it indicates that additional
 	// Known-Answer records may be following shortly.
 	//
-	// M1 does not implement Known-Answer suppression, so TC=0.
+	// Known-Answer suppression (RFC 6762 §7.1) is implemented: the querier's
+	// KnownAnswerCache tracks answers it already holds and includes them in
+	// outgoing queries via message.BuildQueryWithKnownAnswers/QueryBuilder,
+	// setting FlagTC on every packet but the last when the list doesn't fit
+	// one datagram per §7.2; the responder matches incoming Known-Answer
+	// lists against its own records with records.KnownAnswerIndex and
+	// suppresses any answer already known with a TTL at least half correct.
 	//
-	// FR-020: System MUST set DNS header fields per RFC 6762 §18 (TC=0 per §18.5)
+	// FR-020: System MUST set DNS header fields per RFC 6762 §18
 	FlagTC uint16 = 1 << 9 // 0x0200
 
 	// FlagRD is the Recursion Desired bit (bit 8).
@@ -184,6 +328,13 @@ const (
 	//
 	// FR-022: System MUST ignore responses with RCODE != 0 per RFC 6762 §18.11
 	RCodeNoError uint16 = 0
+
+	// RCodeNameError is the Name Error (NXDOMAIN) RCODE (3), asserting the
+	// queried name doesn't exist. mDNS itself never sets this (§18.11
+	// requires RCODE=0 and signals nonexistence via NSEC records instead),
+	// but a unicast DNS-SD fallback query answered by a classic resolver can
+	// still return it.
+	RCodeNameError uint16 = 3
 )
 
 // DNS Name Constraints per RFC 1035 §3.1
@@ -201,10 +352,36 @@ const (
 	// MaxCompressionPointers is the maximum number of compression pointer jumps allowed
 	// when decompressing DNS names per RFC 1035 §4.1.4.
 	//
-	// This prevents infinite loops in malformed packets with circular compression pointers.
+	// A name whose resolution takes more than 34 jumps is rejected, even
+	// if it stays within the 255-byte name limit. Combined with
+	// ParseName's forward-only pointer rule (a pointer must target strictly
+	// before its own offset), this makes parsing cost bounded even against
+	// an adversarial pointer graph, not just a self-referencing loop.
 	//
 	// FR-012: System MUST decompress DNS names per RFC 1035 §4.1.4 (message compression)
-	MaxCompressionPointers = 256
+	MaxCompressionPointers = 34
+
+	// MaxDomainNameWireOctets caps the total number of label bytes (length
+	// byte plus label data) a single name resolution may read across all
+	// the pointer chases it follows, independent of MaxCompressionPointers.
+	//
+	// The jump count alone doesn't bound the work per jump: each jump can
+	// land on a long run of uncompressed labels before the next pointer or
+	// terminator, so a chain well under the jump ceiling can still force
+	// parsing far more label data than any legal ≤255-byte name contains.
+	// 255 matches the wire-format budget the well-known miekg/dns
+	// implementation enforces for the same reason.
+	MaxDomainNameWireOctets = 255
+
+	// MaxRecordsPerMessage caps the combined ANCOUNT+NSCOUNT+ARCOUNT a single
+	// message may declare, rejecting the message outright if exceeded.
+	//
+	// A crafted header can claim tens of thousands of records while the
+	// packet itself is only a few hundred bytes; without this cap,
+	// ParseMessage allocates a slice per declared count before discovering
+	// the packet is truncated, letting one small UDP datagram force large
+	// allocations. 4096 comfortably exceeds any legitimate DNS-SD response.
+	MaxRecordsPerMessage = 4096
 )
 
 // Compression pointer mask per RFC 1035 §4.1.4
@@ -238,6 +415,14 @@ const (
 	TTLHostname = 4500
 )
 
+// ServiceTypeEnumerationName is the RFC 6763 §9 meta-query name: a PTR query
+// for this name returns one PTR record per unique service type currently
+// advertised on the link, rather than per service instance.
+//
+// RFC 6763 §9: "to perform the query one issues a PTR query for the name
+// '_services._dns-sd._udp.<Domain>'."
+const ServiceTypeEnumerationName = "_services._dns-sd._udp.local"
+
 // Timing constants per RFC 6762 §8
 const (
 	// ProbeInterval is the interval between probe packets - 250 milliseconds per RFC 6762 §8.1.
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"net"
 	"testing"
 )
 
@@ -40,7 +41,7 @@ func TestMulticastGroupIPv4(t *testing.T) {
 	addr := MulticastGroupIPv4()
 
 	// Test validates constants match RFC values, hardcoded strings are intentional
-	wantIP := "224.0.0.251"   // nosemgrep: beacon-hardcoded-multicast-address
+	wantIP := "224.0.0.251" // nosemgrep: beacon-hardcoded-multicast-address
 	wantPort := 5353
 
 	if addr.IP.String() != wantIP {
@@ -87,6 +88,26 @@ func TestRecordType_String(t *testing.T) {
 			recordType: RecordTypeSRV,
 			want:       "SRV",
 		},
+		{
+			name:       "NS record",
+			recordType: RecordTypeNS,
+			want:       "NS",
+		},
+		{
+			name:       "CNAME record",
+			recordType: RecordTypeCNAME,
+			want:       "CNAME",
+		},
+		{
+			name:       "SOA record",
+			recordType: RecordTypeSOA,
+			want:       "SOA",
+		},
+		{
+			name:       "HINFO record",
+			recordType: RecordTypeHINFO,
+			want:       "HINFO",
+		},
 		{
 			name:       "Unknown record type",
 			recordType: RecordType(999),
@@ -136,12 +157,12 @@ func TestRecordType_IsSupported(t *testing.T) {
 			want:       true,
 		},
 		{
-			name:       "AAAA record not supported in M1",
-			recordType: RecordType(28), // AAAA (IPv6)
-			want:       false,
+			name:       "AAAA record supported per RFC 3596",
+			recordType: RecordTypeAAAA,
+			want:       true,
 		},
 		{
-			name:       "MX record not supported in M1",
+			name:       "MX record not supported",
 			recordType: RecordType(15), // MX (mail exchange)
 			want:       false,
 		},
@@ -150,6 +171,31 @@ func TestRecordType_IsSupported(t *testing.T) {
 			recordType: RecordType(999),
 			want:       false,
 		},
+		{
+			name:       "NS record supported per RFC 1035 §3.3.11",
+			recordType: RecordTypeNS,
+			want:       true,
+		},
+		{
+			name:       "CNAME record supported per RFC 1035 §3.3.1",
+			recordType: RecordTypeCNAME,
+			want:       true,
+		},
+		{
+			name:       "SOA record supported per RFC 1035 §3.3.13",
+			recordType: RecordTypeSOA,
+			want:       true,
+		},
+		{
+			name:       "HINFO record supported per RFC 1035 §3.3.2",
+			recordType: RecordTypeHINFO,
+			want:       true,
+		},
+		{
+			name:       "OPT pseudo-record not supported as a query/record type",
+			recordType: RecordTypeOPT,
+			want:       false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -195,6 +241,26 @@ func TestRecordType_Values(t *testing.T) {
 			recordType: RecordTypeSRV,
 			wantValue:  33,
 		},
+		{
+			name:       "NS record value per RFC 1035 §3.3.11",
+			recordType: RecordTypeNS,
+			wantValue:  2,
+		},
+		{
+			name:       "CNAME record value per RFC 1035 §3.3.1",
+			recordType: RecordTypeCNAME,
+			wantValue:  5,
+		},
+		{
+			name:       "SOA record value per RFC 1035 §3.3.13",
+			recordType: RecordTypeSOA,
+			wantValue:  6,
+		},
+		{
+			name:       "HINFO record value per RFC 1035 §3.3.2",
+			recordType: RecordTypeHINFO,
+			wantValue:  13,
+		},
 	}
 
 	for _, tt := range tests {
@@ -207,6 +273,31 @@ func TestRecordType_Values(t *testing.T) {
 	}
 }
 
+// TestRecordType_IsPseudoRecord validates that IsPseudoRecord identifies OPT
+// as never a real resource, per RFC 6891 §6.1.2, while leaving every real
+// record type (and the query-only ANY QTYPE) false.
+func TestRecordType_IsPseudoRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType RecordType
+		want       bool
+	}{
+		{name: "OPT is a pseudo-record", recordType: RecordTypeOPT, want: true},
+		{name: "A is not a pseudo-record", recordType: RecordTypeA, want: false},
+		{name: "ANY is not a pseudo-record", recordType: RecordTypeANY, want: false},
+		{name: "NS is not a pseudo-record", recordType: RecordTypeNS, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.recordType.IsPseudoRecord()
+			if got != tt.want {
+				t.Errorf("RecordType(%d).IsPseudoRecord() = %v, want %v", tt.recordType, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestClassIN validates that ClassIN has the correct value (1) per RFC 1035 §3.2.4.
 //
 // RFC 1035 §3.2.4: "IN = 1 the Internet"
@@ -320,8 +411,8 @@ func TestDNSNameConstraints(t *testing.T) {
 		{
 			name:      "MaxCompressionPointers per RFC 1035 §4.1.4",
 			constant:  MaxCompressionPointers,
-			wantValue: 256,
-			rfcRef:    "RFC 1035 §4.1.4 (loop detection)",
+			wantValue: 34,
+			rfcRef:    "RFC 1035 §4.1.4 (jump cap per name)",
 		},
 	}
 
@@ -376,3 +467,65 @@ func TestMulticastGroupIPv4_NotNil(t *testing.T) {
 		t.Fatal("MulticastGroupIPv4().IP is nil")
 	}
 }
+
+// TestMulticastGroupIPv6 validates that MulticastGroupIPv6 returns the
+// correct UDP address for mDNS multicast per RFC 6762 §5.
+//
+// RFC 6762 §5: "the IPv6 mDNS link-local multicast address ff02::fb"
+func TestMulticastGroupIPv6(t *testing.T) {
+	addr := MulticastGroupIPv6("")
+
+	wantIP := "ff02::fb" // nosemgrep: beacon-hardcoded-multicast-address
+	wantPort := 5353
+
+	if addr.IP.String() != wantIP {
+		t.Errorf("MulticastGroupIPv6(\"\").IP = %s, want %s per RFC 6762 §5", addr.IP, wantIP)
+	}
+	if addr.Port != wantPort {
+		t.Errorf("MulticastGroupIPv6(\"\").Port = %d, want %d per RFC 6762 §5", addr.Port, wantPort)
+	}
+	if !addr.IP.IsMulticast() {
+		t.Errorf("MulticastGroupIPv6(\"\").IP is not a multicast address")
+	}
+}
+
+// TestMulticastGroupIPv6_IsLinkLocal validates that the IPv6 multicast
+// address is link-local scoped (ff02::/16) per RFC 6762 §5.
+func TestMulticastGroupIPv6_IsLinkLocal(t *testing.T) {
+	addr := MulticastGroupIPv6("")
+
+	ip := addr.IP.To16()
+	if ip == nil {
+		t.Fatal("MulticastGroupIPv6(\"\") returned non-IPv6 address")
+	}
+
+	if ip[0] != 0xff || ip[1] != 0x02 {
+		t.Errorf("MulticastGroupIPv6(\"\") IP %s is not in link-local range ff02::/16 per RFC 6762 §5", ip)
+	}
+}
+
+// TestMulticastGroupIPv6_Zone validates that MulticastGroupIPv6 sets the
+// returned address's Zone from the zone argument, as RFC 4007 scoped
+// addresses require for a link-local destination.
+func TestMulticastGroupIPv6_Zone(t *testing.T) {
+	addr := MulticastGroupIPv6("eth0")
+	if addr.Zone != "eth0" {
+		t.Errorf("MulticastGroupIPv6(\"eth0\").Zone = %q, want %q", addr.Zone, "eth0")
+	}
+}
+
+// TestMulticastGroupIPv6ForInterface validates that
+// MulticastGroupIPv6ForInterface scopes the address to ifi.Name, and falls
+// back to an unscoped address for a nil interface.
+func TestMulticastGroupIPv6ForInterface(t *testing.T) {
+	ifi := &net.Interface{Name: "en0"}
+	addr := MulticastGroupIPv6ForInterface(ifi)
+	if addr.Zone != "en0" {
+		t.Errorf("MulticastGroupIPv6ForInterface(en0).Zone = %q, want %q", addr.Zone, "en0")
+	}
+
+	nilAddr := MulticastGroupIPv6ForInterface(nil)
+	if nilAddr.Zone != "" {
+		t.Errorf("MulticastGroupIPv6ForInterface(nil).Zone = %q, want empty", nilAddr.Zone)
+	}
+}
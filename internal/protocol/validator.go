@@ -122,11 +122,15 @@ func isValidDNSChar(ch rune) bool {
 // FR-002: System MUST support querying for A, PTR, SRV, and TXT record types
 // FR-014: System MUST return ValidationError for unsupported record types
 //
-// M1 Supported Types:
-//   - A (1): IPv4 address
-//   - PTR (12): Pointer (service discovery)
-//   - TXT (16): Text strings (service metadata)
-//   - SRV (33): Service location
+// Supported Types: A (1), AAAA (28), PTR (12), TXT (16), SRV (33), ANY (255),
+// RRSIG (46), DNSKEY (48), DS (43), NSEC (47), NSEC3 (50), SVCB (64),
+// HTTPS (65), NS (2), CNAME (5), SOA (6), HINFO (13) - see RecordType.IsSupported.
+//
+// The error distinguishes a type this package has never heard of (no
+// RecordType constant, String() == "UNKNOWN") from one it recognizes by
+// name but deliberately rejects as a query/record type - today that's only
+// OPT (41), a pseudo-record per RFC 6891 §6.1.2 rather than a real RR type
+// (see RecordType.IsPseudoRecord).
 //
 // Parameters:
 //   - recordType: The DNS record type to validate
@@ -134,11 +138,19 @@ func isValidDNSChar(ch rune) bool {
 // Returns:
 //   - error: ValidationError if type is unsupported, nil if supported
 func ValidateRecordType(recordType uint16) error {
-	if !RecordType(recordType).IsSupported() {
+	rt := RecordType(recordType)
+	if !rt.IsSupported() {
+		if rt.String() == "UNKNOWN" {
+			return &errors.ValidationError{
+				Field:   "recordType",
+				Value:   recordType,
+				Message: fmt.Sprintf("unknown record type %d (supports A=1, AAAA=28, PTR=12, TXT=16, SRV=33, ANY=255, RRSIG=46, DNSKEY=48, DS=43, NSEC=47, NSEC3=50, SVCB=64, HTTPS=65, NS=2, CNAME=5, SOA=6, HINFO=13 per FR-002)", recordType),
+			}
+		}
 		return &errors.ValidationError{
 			Field:   "recordType",
 			Value:   recordType,
-			Message: fmt.Sprintf("unsupported record type %d (M1 supports A=1, PTR=12, TXT=16, SRV=33 per FR-002)", recordType),
+			Message: fmt.Sprintf("record type %d (%s) is recognized but not a valid query/record type", recordType, rt),
 		}
 	}
 	return nil
@@ -184,13 +196,27 @@ func ValidateResponse(flags uint16) error {
 	// RFC 6762 §18.11: RCODE MUST be 0 (no error)
 	// mDNS responders MUST ignore messages with non-zero RCODE
 	rcode := flags & 0x000F
+	if err := ValidateRCODE(rcode); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateRCODE validates that rcode is zero per RFC 6762 §18.11.
+//
+// ValidateResponse calls this with just the header's 4-bit RCODE field.
+// Callers that parsed an EDNS(0) OPT record should instead pass the combined
+// 12-bit extended RCODE (see message.DNSMessage.FullRCODE), since a non-zero
+// extended RCODE such as BADVERS (16) has a zero low nibble that the 4-bit
+// field alone would miss.
+func ValidateRCODE(rcode uint16) error {
 	if rcode != RCodeNoError {
 		return &errors.ValidationError{
-			Field:   "flags",
-			Value:   flags,
-			Message: fmt.Sprintf("RCODE is %d, expected %d per RFC 6762 §18.11 (flags: 0x%04X)", rcode, RCodeNoError, flags),
+			Field:   "rcode",
+			Value:   rcode,
+			Message: fmt.Sprintf("RCODE is %d, expected %d per RFC 6762 §18.11", rcode, RCodeNoError),
 		}
 	}
-
 	return nil
 }
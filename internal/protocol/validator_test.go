@@ -223,12 +223,12 @@ func TestValidateRecordType_FR002_SupportedTypes(t *testing.T) {
 			wantErr:    false,
 		},
 		{
-			name:       "AAAA record (28) not supported in M1",
+			name:       "AAAA record (28) supported per RFC 3596",
 			recordType: 28,
-			wantErr:    true,
+			wantErr:    false,
 		},
 		{
-			name:       "MX record (15) not supported in M1",
+			name:       "MX record (15) not supported",
 			recordType: 15,
 			wantErr:    true,
 		},
@@ -237,6 +237,31 @@ func TestValidateRecordType_FR002_SupportedTypes(t *testing.T) {
 			recordType: 999,
 			wantErr:    true,
 		},
+		{
+			name:       "NS record (2) supported per RFC 1035 §3.3.11",
+			recordType: 2,
+			wantErr:    false,
+		},
+		{
+			name:       "CNAME record (5) supported per RFC 1035 §3.3.1",
+			recordType: 5,
+			wantErr:    false,
+		},
+		{
+			name:       "SOA record (6) supported per RFC 1035 §3.3.13",
+			recordType: 6,
+			wantErr:    false,
+		},
+		{
+			name:       "HINFO record (13) supported per RFC 1035 §3.3.2",
+			recordType: 13,
+			wantErr:    false,
+		},
+		{
+			name:       "OPT record (41) recognized but not a valid query type",
+			recordType: 41,
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -398,3 +423,30 @@ func TestValidateResponse_RFC6762_OpcodeHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateRCODE validates the standalone RCODE check ValidateResponse
+// delegates to, which callers with an EDNS(0) OPT record can also call
+// directly with a combined 12-bit extended RCODE.
+func TestValidateRCODE(t *testing.T) {
+	tests := []struct {
+		name    string
+		rcode   uint16
+		wantErr bool
+	}{
+		{name: "zero (NoError)", rcode: 0, wantErr: false},
+		{name: "non-zero 4-bit RCODE", rcode: 2, wantErr: true},
+		{name: "BADVERS (16): non-zero extended RCODE, zero low nibble", rcode: 16, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRCODE(tt.rcode)
+			if tt.wantErr && err == nil {
+				t.Errorf("ValidateRCODE(%d) expected error, got nil", tt.rcode)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ValidateRCODE(%d) unexpected error: %v", tt.rcode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package race provides a generic "happy eyeballs"-style racing helper:
+// start one call immediately, fall back to a second after a delay if the
+// first hasn't produced a result yet, and take whichever succeeds first.
+// Tailscale uses the same pattern to race a pair of DNS transports (e.g. UDP
+// against TCP, or one upstream against another); Race is deliberately
+// generic rather than tied to any particular transport or response type so
+// both that kind of resolver racing and a package like querier (racing a
+// query across IPv4 and IPv6 multicast transports) can reuse it without
+// either importing the other.
+package race
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Race runs first immediately. If first hasn't returned within delay, second
+// is started too, running alongside it. Whichever call returns first with a
+// nil error wins: its result is returned right away, and the other call's
+// context is canceled if it's still in flight (or never started, if the
+// winner was first and beat the delay). A call that fails doesn't end the
+// race by itself - a transient error from one side is discarded in favor of
+// waiting on the other - except that a first failure before the delay starts
+// second immediately rather than waiting out the rest of it, since there's
+// nothing left to wait for. Only when both calls have failed is the combined
+// error returned, via errors.Join.
+//
+// first and second each receive a context derived from ctx, so Race relies
+// on them to return promptly once ctx ends (exactly as, for example,
+// querier's collectResponses already does, treating ctx ending as "return
+// what was collected, no error" rather than propagating ctx.Err()) instead
+// of separately enforcing ctx's deadline itself - that would let Race return
+// ctx.Err() in a race against a result that, in effect, had already arrived.
+func Race[T any](ctx context.Context, delay time.Duration, first, second func(context.Context) (T, error)) (T, error) {
+	firstCtx, cancelFirst := context.WithCancel(ctx)
+	secondCtx, cancelSecond := context.WithCancel(ctx)
+	defer cancelFirst()
+	defer cancelSecond()
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	firstDone := make(chan outcome, 1)
+	secondDone := make(chan outcome, 1)
+
+	go func() {
+		v, err := first(firstCtx)
+		firstDone <- outcome{v, err}
+	}()
+
+	secondStarted := false
+	startSecond := func() {
+		if secondStarted {
+			return
+		}
+		secondStarted = true
+		go func() {
+			v, err := second(secondCtx)
+			secondDone <- outcome{v, err}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var firstErr, secondErr error
+	firstFailed, secondFailed := false, false
+
+	for {
+		select {
+		case o := <-firstDone:
+			if o.err == nil {
+				cancelSecond()
+				return o.value, nil
+			}
+			firstErr, firstFailed = o.err, true
+			if secondFailed {
+				var zero T
+				return zero, errors.Join(firstErr, secondErr)
+			}
+			startSecond() // first failed - nothing left to wait out the delay for
+
+		case o := <-secondDone:
+			if o.err == nil {
+				cancelFirst()
+				return o.value, nil
+			}
+			secondErr, secondFailed = o.err, true
+			if firstFailed {
+				var zero T
+				return zero, errors.Join(firstErr, secondErr)
+			}
+
+		case <-timer.C:
+			startSecond()
+		}
+	}
+}
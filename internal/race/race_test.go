@@ -0,0 +1,130 @@
+package race
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRace_FirstWinsBeforeDelay(t *testing.T) {
+	secondCalled := make(chan struct{}, 1)
+	first := func(ctx context.Context) (string, error) {
+		return "first", nil
+	}
+	second := func(ctx context.Context) (string, error) {
+		secondCalled <- struct{}{}
+		return "second", nil
+	}
+
+	got, err := Race(context.Background(), 50*time.Millisecond, first, second)
+	if err != nil {
+		t.Fatalf("Race() error = %v, want nil", err)
+	}
+	if got != "first" {
+		t.Errorf("Race() = %q, want %q", got, "first")
+	}
+
+	select {
+	case <-secondCalled:
+		t.Error("second was started even though first won before the delay elapsed")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestRace_FallsBackToSecondAfterDelay(t *testing.T) {
+	first := func(ctx context.Context) (string, error) {
+		<-ctx.Done() // first never answers - blocks until the race cancels it
+		return "", ctx.Err()
+	}
+	second := func(ctx context.Context) (string, error) {
+		return "second", nil
+	}
+
+	got, err := Race(context.Background(), 10*time.Millisecond, first, second)
+	if err != nil {
+		t.Fatalf("Race() error = %v, want nil", err)
+	}
+	if got != "second" {
+		t.Errorf("Race() = %q, want %q", got, "second")
+	}
+}
+
+func TestRace_FirstErrorStartsSecondImmediately(t *testing.T) {
+	start := time.Now()
+	first := func(ctx context.Context) (string, error) {
+		return "", errors.New("first failed")
+	}
+	second := func(ctx context.Context) (string, error) {
+		return "second", nil
+	}
+
+	got, err := Race(context.Background(), time.Hour, first, second)
+	if err != nil {
+		t.Fatalf("Race() error = %v, want nil", err)
+	}
+	if got != "second" {
+		t.Errorf("Race() = %q, want %q", got, "second")
+	}
+	if elapsed := time.Since(start); elapsed >= time.Hour {
+		t.Errorf("Race() waited out the full delay after first failed immediately, took %v", elapsed)
+	}
+}
+
+func TestRace_BothFail_CoalescesErrors(t *testing.T) {
+	firstErr := errors.New("first failed")
+	secondErr := errors.New("second failed")
+	first := func(ctx context.Context) (string, error) { return "", firstErr }
+	second := func(ctx context.Context) (string, error) { return "", secondErr }
+
+	_, err := Race(context.Background(), time.Millisecond, first, second)
+	if err == nil {
+		t.Fatal("Race() error = nil, want both errors joined")
+	}
+	if !errors.Is(err, firstErr) || !errors.Is(err, secondErr) {
+		t.Errorf("Race() error = %v, want it to wrap both %v and %v", err, firstErr, secondErr)
+	}
+}
+
+func TestRace_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	_, err := Race(ctx, time.Hour, block, block)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Race() error = %v, want context.Canceled", err)
+	}
+}
+
+// TestRace_ResultBeatsContextEnding verifies a first that returns its result
+// the instant ctx ends still wins the race, rather than Race spuriously
+// preferring ctx.Err() in the tie - important for callers like querier's
+// collectResponses, which itself treats ctx ending as "return what was
+// collected, no error" instead of propagating ctx.Err().
+func TestRace_ResultBeatsContextEnding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "collected so far", nil // mirrors collectResponses: no error on ctx ending
+	}
+	second := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	cancel()
+	got, err := Race(ctx, time.Hour, first, second)
+	if err != nil {
+		t.Fatalf("Race() error = %v, want nil (first's result, not ctx.Err())", err)
+	}
+	if got != "collected so far" {
+		t.Errorf("Race() = %q, want %q", got, "collected so far")
+	}
+}
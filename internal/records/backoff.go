@@ -0,0 +1,85 @@
+package records
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffConfig configures RecordSet.NextQueryDelay's exponential backoff
+// for continuous mDNS queriers, per RFC 6762 §5.2: "the interval between
+// the first two queries MUST be at least one second... the intervals
+// between successive queries MUST increase by at least a factor of two."
+// The formula - delay = min(MaxDelay, BaseDelay*Factor^retries) *
+// (1 ± Jitter) - mirrors gRPC's connection BackoffConfig.
+type BackoffConfig struct {
+	// BaseDelay is the delay before a record's first retry query.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed delay regardless of how many retries have
+	// accumulated - RFC 6762 §5.2's "up to a maximum of 60 minutes", which
+	// DefaultBackoffConfig sets as 3600 seconds.
+	MaxDelay time.Duration
+
+	// Factor is the multiplier applied per additional retry; RFC 6762 §5.2
+	// requires at least 2.
+	Factor float64
+
+	// Jitter is the fraction of the computed delay to randomly perturb by,
+	// in either direction, so many queriers watching the same record don't
+	// all re-query in lockstep.
+	Jitter float64
+}
+
+// DefaultBackoffConfig returns RFC 6762 §5.2's defaults: a 1 second base
+// delay, doubling factor, 1-hour cap, and ±20% jitter.
+func DefaultBackoffConfig() BackoffConfig {
+	return BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  3600 * time.Second,
+		Factor:    2.0,
+		Jitter:    0.2,
+	}
+}
+
+// NextQueryDelay returns how long a continuous querier should wait before
+// its next query for rr on interfaceID, per RFC 6762 §5.2's doubling
+// schedule (see BackoffConfig). Each call advances that (record, interface)
+// pair's retry count, so repeated calls without an intervening ResetBackoff
+// climb toward BackoffConfig.MaxDelay; call ResetBackoff once an
+// authoritative answer arrives to restart the schedule from BaseDelay.
+//
+// NextQueryDelay shares RecordSet's per-(record, interface) bucket map with
+// CanMulticast/Reserve, so it participates in the same MaxEntries LRU
+// eviction rather than tracking a separate, unbounded set of records.
+func (rs *RecordSet) NextQueryDelay(rr *ResourceRecord, interfaceID string) time.Duration {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	b := rs.bucket(rr, interfaceID)
+	delay := time.Duration(float64(rs.backoffConfig.BaseDelay) * math.Pow(rs.backoffConfig.Factor, float64(b.queryRetries)))
+	if delay > rs.backoffConfig.MaxDelay {
+		delay = rs.backoffConfig.MaxDelay
+	}
+	b.queryRetries++
+
+	if rs.backoffConfig.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + rs.backoffConfig.Jitter*(2*rand.Float64()-1)))
+		if delay < 0 {
+			delay = 0
+		}
+	}
+	return delay
+}
+
+// ResetBackoff clears rr's tracked query-retry count on interfaceID, so the
+// next NextQueryDelay call restarts the schedule from BackoffConfig.BaseDelay.
+// Call this once an authoritative answer for rr arrives: RFC 6762 §5.2's
+// backoff only governs how fast an unanswered query escalates, not the
+// steady cadence of a record a querier keeps successfully refreshing.
+func (rs *RecordSet) ResetBackoff(rr *ResourceRecord, interfaceID string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.bucket(rr, interfaceID).queryRetries = 0
+}
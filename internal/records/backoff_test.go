@@ -0,0 +1,118 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestRecordSet_NextQueryDelay_Doubles verifies NextQueryDelay follows RFC
+// 6762 §5.2's doubling schedule (1s, 2s, 4s, ...) for consecutive calls on
+// the same (record, interface), within Jitter's ±20% tolerance.
+func TestRecordSet_NextQueryDelay_Doubles(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte("target.local"),
+	}
+
+	rs := NewRecordSetWithConfigs(DefaultRateLimitConfig(), BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  3600 * time.Second,
+		Factor:    2.0,
+		Jitter:    0,
+	})
+
+	want := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i, w := range want {
+		got := rs.NextQueryDelay(rr, "eth0")
+		if got != w {
+			t.Errorf("NextQueryDelay() call %d = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// TestRecordSet_NextQueryDelay_CapsAtMaxDelay verifies the doubling schedule
+// never exceeds BackoffConfig.MaxDelay, no matter how many retries accumulate.
+func TestRecordSet_NextQueryDelay_CapsAtMaxDelay(t *testing.T) {
+	rr := &ResourceRecord{Name: "myhost.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, Data: []byte{1, 2, 3, 4}}
+
+	rs := NewRecordSetWithConfigs(DefaultRateLimitConfig(), BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  10 * time.Second,
+		Factor:    2.0,
+		Jitter:    0,
+	})
+
+	for i := 0; i < 10; i++ {
+		if got := rs.NextQueryDelay(rr, "eth0"); got > 10*time.Second {
+			t.Fatalf("NextQueryDelay() call %d = %v, want <= MaxDelay (10s)", i+1, got)
+		}
+	}
+}
+
+// TestRecordSet_NextQueryDelay_PerInterface verifies each interface tracks
+// its own retry count, the same per-(record, interface) granularity
+// CanMulticast uses.
+func TestRecordSet_NextQueryDelay_PerInterface(t *testing.T) {
+	rr := &ResourceRecord{Name: "myhost.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, Data: []byte{1, 2, 3, 4}}
+
+	rs := NewRecordSetWithConfigs(DefaultRateLimitConfig(), BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  3600 * time.Second,
+		Factor:    2.0,
+		Jitter:    0,
+	})
+
+	rs.NextQueryDelay(rr, "eth0")
+	rs.NextQueryDelay(rr, "eth0")
+
+	if got := rs.NextQueryDelay(rr, "wlan0"); got != 1*time.Second {
+		t.Errorf("NextQueryDelay() on a fresh interface = %v, want BaseDelay (1s), unaffected by eth0's retries", got)
+	}
+}
+
+// TestRecordSet_ResetBackoff_RestartsSchedule verifies ResetBackoff clears a
+// record's retry count so the next NextQueryDelay call restarts from
+// BaseDelay, per RFC 6762 §5.2 (the backoff only governs an unanswered
+// query, not a record the querier keeps successfully refreshing).
+func TestRecordSet_ResetBackoff_RestartsSchedule(t *testing.T) {
+	rr := &ResourceRecord{Name: "myhost.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, Data: []byte{1, 2, 3, 4}}
+
+	rs := NewRecordSetWithConfigs(DefaultRateLimitConfig(), BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  3600 * time.Second,
+		Factor:    2.0,
+		Jitter:    0,
+	})
+
+	rs.NextQueryDelay(rr, "eth0")
+	rs.NextQueryDelay(rr, "eth0")
+	rs.ResetBackoff(rr, "eth0")
+
+	if got := rs.NextQueryDelay(rr, "eth0"); got != 1*time.Second {
+		t.Errorf("NextQueryDelay() after ResetBackoff = %v, want BaseDelay (1s)", got)
+	}
+}
+
+// TestDefaultBackoffConfig verifies DefaultBackoffConfig matches RFC 6762
+// §5.2's recommended schedule: a 1 second base delay, doubling factor, and
+// a 1-hour cap.
+func TestDefaultBackoffConfig(t *testing.T) {
+	cfg := DefaultBackoffConfig()
+	if cfg.BaseDelay != 1*time.Second {
+		t.Errorf("BaseDelay = %v, want 1s", cfg.BaseDelay)
+	}
+	if cfg.Factor != 2.0 {
+		t.Errorf("Factor = %v, want 2.0", cfg.Factor)
+	}
+	if cfg.MaxDelay != 3600*time.Second {
+		t.Errorf("MaxDelay = %v, want 3600s", cfg.MaxDelay)
+	}
+	if cfg.Jitter != 0.2 {
+		t.Errorf("Jitter = %v, want 0.2", cfg.Jitter)
+	}
+}
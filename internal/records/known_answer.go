@@ -0,0 +1,45 @@
+package records
+
+import "github.com/joshuafuller/beacon/internal/message"
+
+// KnownAnswer pairs a cached resource record with the RecordTTL tracking its
+// remaining lifetime, the input BuildKnownAnswers needs to assemble an RFC
+// 6762 §7.1 Known-Answer list.
+type KnownAnswer struct {
+	Record *message.ResourceRecord
+	TTL    *RecordTTL
+}
+
+// BuildKnownAnswers returns the still-live entries from entries, with each
+// returned record's TTL replaced by its RecordTTL's current remaining value,
+// for inclusion in a continued query's Known-Answer list per RFC 6762 §7.1:
+// "...it populates the Answer Section of the DNS query message with those
+// answers". An expired entry is omitted rather than sent with a TTL of 0,
+// since a Known-Answer only suppresses a response when it's genuinely still
+// fresh.
+func BuildKnownAnswers(entries []KnownAnswer) []*message.ResourceRecord {
+	answers := make([]*message.ResourceRecord, 0, len(entries))
+	for _, e := range entries {
+		if e.TTL.IsExpired() {
+			continue
+		}
+		rr := *e.Record
+		rr.TTL = e.TTL.GetRemainingTTL()
+		answers = append(answers, &rr)
+	}
+	return answers
+}
+
+// IsDuplicateAnswer reports whether an incoming answer with incomingTTL adds
+// no new information over cached: cached is still live and already holds a
+// TTL at least as fresh as what just arrived. A querier should drop such an
+// answer rather than reset the cache entry's clock and re-arm its refresh
+// timers for data it already had.
+//
+// cached == nil (nothing cached yet) is never a duplicate.
+func IsDuplicateAnswer(cached *RecordTTL, incomingTTL uint32) bool {
+	if cached == nil || cached.IsExpired() {
+		return false
+	}
+	return incomingTTL <= cached.GetRemainingTTL()
+}
@@ -0,0 +1,49 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+func TestBuildKnownAnswers(t *testing.T) {
+	fresh := NewRecordTTL(protocol.RecordTypePTR, 120)
+	expired := &RecordTTL{RecordType: protocol.RecordTypePTR, TTL: 120, CreatedAt: time.Now().Add(-200 * time.Second)}
+
+	entries := []KnownAnswer{
+		{Record: &message.ResourceRecord{Name: "_http._tcp.local", Type: protocol.RecordTypePTR, TTL: 120}, TTL: fresh},
+		{Record: &message.ResourceRecord{Name: "stale._http._tcp.local", Type: protocol.RecordTypePTR, TTL: 120}, TTL: expired},
+	}
+
+	got := BuildKnownAnswers(entries)
+	if len(got) != 1 {
+		t.Fatalf("BuildKnownAnswers() returned %d answers, want 1 (expired entry dropped)", len(got))
+	}
+	if got[0].Name != "_http._tcp.local" {
+		t.Errorf("BuildKnownAnswers()[0].Name = %q, want _http._tcp.local", got[0].Name)
+	}
+	if got[0].TTL == 0 || got[0].TTL > 120 {
+		t.Errorf("BuildKnownAnswers()[0].TTL = %d, want remaining TTL in (0, 120]", got[0].TTL)
+	}
+}
+
+func TestIsDuplicateAnswer(t *testing.T) {
+	cached := NewRecordTTL(protocol.RecordTypePTR, 120)
+
+	if IsDuplicateAnswer(nil, 120) {
+		t.Error("IsDuplicateAnswer(nil, ...) = true, want false (nothing cached yet)")
+	}
+	if !IsDuplicateAnswer(cached, 60) {
+		t.Error("IsDuplicateAnswer(cached, 60) = false, want true (incoming TTL no fresher than cached)")
+	}
+	if IsDuplicateAnswer(cached, 121) {
+		t.Error("IsDuplicateAnswer(cached, 121) = true, want false (incoming TTL fresher than cached)")
+	}
+
+	expired := &RecordTTL{RecordType: protocol.RecordTypePTR, TTL: 120, CreatedAt: time.Now().Add(-200 * time.Second)}
+	if IsDuplicateAnswer(expired, 1) {
+		t.Error("IsDuplicateAnswer(expired, ...) = true, want false (expired cache entry is never a duplicate)")
+	}
+}
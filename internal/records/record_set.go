@@ -4,12 +4,95 @@ package records
 import (
 	"encoding/binary"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
 )
 
+// TXTRecord is one key/value (or boolean) attribute of a TXT record per RFC
+// 6763 §6.4. Value is a byte slice rather than a string so a service can
+// carry binary metadata - e.g. the Chromecast and HomeKit TXT keys that
+// aren't valid UTF-8.
+//
+// Present distinguishes the three forms RFC 6763 §6.4 defines:
+//   - "key" (boolean attribute): Present is false; Value is ignored.
+//   - "key=" (empty-value attribute): Present is true; Value is empty.
+//   - "key=value": Present is true; Value holds value.
+type TXTRecord struct {
+	Key     string
+	Value   []byte
+	Present bool
+}
+
+// TXTRecordsFromMap builds a []TXTRecord from a map[string]string for
+// callers still constructing TXT data the old way. Every entry becomes a
+// "key=value" record (Present: true). Map iteration order is unspecified,
+// so keys are sorted first to make the resulting wire encoding deterministic.
+func TXTRecordsFromMap(m map[string]string) []TXTRecord {
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	txtRecords := make([]TXTRecord, 0, len(m))
+	for _, key := range keys {
+		txtRecords = append(txtRecords, TXTRecord{Key: key, Value: []byte(m[key]), Present: true})
+	}
+	return txtRecords
+}
+
+// DecodeTXTEntries parses the raw TXT strings off the wire (message.TXTData's
+// Entries, exactly as they appeared in the response) into []TXTRecord,
+// preserving the RFC 6763 §6.4 distinction between a boolean attribute
+// ("key"), an empty-value attribute ("key="), and "key=value" - and letting
+// binary values (e.g. the Chromecast and HomeKit TXT keys) round-trip
+// without mangling, since entry is cut on the first "=" rather than decoded
+// as UTF-8.
+func DecodeTXTEntries(entries []string) []TXTRecord {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(entries))
+	txtRecords := make([]TXTRecord, 0, len(entries))
+	for _, entry := range entries {
+		key, value, present := strings.Cut(entry, "=")
+		if seen[key] {
+			continue // RFC 6763 §6.4: keep only the first occurrence of a key
+		}
+		seen[key] = true
+
+		txtRecord := TXTRecord{Key: key, Present: present}
+		if present {
+			txtRecord.Value = []byte(value)
+		}
+		txtRecords = append(txtRecords, txtRecord)
+	}
+	return txtRecords
+}
+
+// TXTValue returns the value of the first "key=value" or "key=" record with
+// the given key, and true if it was found. A boolean attribute (Present:
+// false) or an absent key both report ok=false, since neither has a value
+// to return.
+func TXTValue(txtRecords []TXTRecord, key string) (value string, ok bool) {
+	for _, r := range txtRecords {
+		if r.Key == key {
+			return string(r.Value), r.Present
+		}
+	}
+	return "", false
+}
+
 // ServiceInfo holds service information for record set building.
 //
 // This is used internally to construct the full set of resource records
@@ -17,12 +100,27 @@ import (
 //
 // T033: ServiceInfo type for BuildRecordSet()
 type ServiceInfo struct {
-	InstanceName string            // "My Printer"
-	ServiceType  string            // "_http._tcp.local"
-	Hostname     string            // "myhost.local"
-	Port         int               // 8080
-	IPv4Address  []byte            // [192, 168, 1, 100]
-	TXTRecords   map[string]string // {"version": "1.0"}
+	InstanceName  string      // "My Printer"
+	ServiceType   string      // "_http._tcp.local"
+	Hostname      string      // "myhost.local"
+	Port          int         // 8080
+	IPv4Address   []byte      // [192, 168, 1, 100]
+	IPv6Addresses [][]byte    // Each entry 16 bytes, e.g. fe80::1 or 2001:db8::1
+	TXTRecords    []TXTRecord // RFC 6763 §6.4 key/value (or boolean) attributes
+	Subtypes      []string    // RFC 6763 §7.1 subtypes, e.g. ["_printer"]
+
+	// TTL overrides the PTR/SRV/TXT records' default 120-second TTL (RFC
+	// 6762 §10) when non-zero. The A/AAAA records' own 4500-second TTL is
+	// unaffected, matching responder.WithTTL's documented scope.
+	TTL uint32
+}
+
+// serviceTTL returns service.TTL if set, or protocol.TTLService otherwise.
+func serviceTTL(service *ServiceInfo) uint32 {
+	if service.TTL != 0 {
+		return service.TTL
+	}
+	return protocol.TTLService
 }
 
 // BuildRecordSet constructs a complete set of resource records for a service.
@@ -32,12 +130,13 @@ type ServiceInfo struct {
 //   - SRV record: instance._service._proto.local → hostname:port
 //   - TXT record: instance._service._proto.local → key-value pairs
 //   - A record: hostname.local → IPv4 address
+//   - AAAA record(s): hostname.local → IPv6 address(es), per RFC 3596 §2.1
 //
 // Parameters:
 //   - service: Service information
 //
 // Returns:
-//   - []*message.ResourceRecord: All records (PTR, SRV, TXT, A)
+//   - []*message.ResourceRecord: All records (PTR, SRV, TXT, A, AAAA)
 //
 // FR-032: System MUST build complete record set (PTR, SRV, TXT, A)
 // T033: Implement BuildRecordSet()
@@ -48,18 +147,24 @@ func BuildRecordSet(service *ServiceInfo) []*message.ResourceRecord {
 	ptrRecord := buildPTRRecord(service)
 	records = append(records, ptrRecord)
 
+	// 1b. Subtype PTR records: <subtype>._sub._service._proto.local → instance._service._proto.local
+	records = append(records, buildSubtypePTRRecords(service)...)
+
 	// 2. SRV record: instance._service._proto.local → hostname:port
 	srvRecord := buildSRVRecord(service)
 	records = append(records, srvRecord)
 
 	// 3. TXT record: instance._service._proto.local → key-value pairs
-	txtRecord := buildTXTRecordFromService(service)
+	txtRecord := BuildTXTRecord(service)
 	records = append(records, txtRecord)
 
 	// 4. A record: hostname.local → IPv4 address
 	aRecord := buildARecord(service)
 	records = append(records, aRecord)
 
+	// 5. AAAA records: hostname.local → IPv6 address(es)
+	records = append(records, buildAAAARecords(service)...)
+
 	return records
 }
 
@@ -90,12 +195,124 @@ func buildPTRRecord(service *ServiceInfo) *message.ResourceRecord {
 		Name:       name,
 		Type:       protocol.RecordTypePTR,
 		Class:      protocol.ClassIN,
-		TTL:        120, // RFC 6762 §10: 120 seconds for service records
+		TTL:        serviceTTL(service),
 		Data:       targetEncoded,
 		CacheFlush: false, // PTR is shared (multiple services can have same type)
 	}
 }
 
+// BuildServiceTypeEnumRecord constructs the RFC 6763 §9 service-type
+// enumeration PTR record for serviceType.
+//
+// PTR record format:
+//   - Name: protocol.ServiceTypeEnumerationName ("_services._dns-sd._udp.local")
+//   - Type: PTR (12)
+//   - Class: IN (1)
+//   - TTL: 120 seconds (service record per RFC 6762 §10)
+//   - RDATA: serviceType (e.g., "_http._tcp.local")
+//   - CacheFlush: false (PTR is a shared record per RFC 6762 §10.2)
+//
+// Unlike buildPTRRecord's instance-name RDATA, serviceType is a plain domain
+// name with no free-form instance label, so it's encoded with
+// message.EncodeName rather than message.EncodeServiceInstanceName.
+func BuildServiceTypeEnumRecord(serviceType string) (*message.ResourceRecord, error) {
+	targetEncoded, err := message.EncodeName(serviceType)
+	if err != nil {
+		return nil, fmt.Errorf("encoding service type %q: %w", serviceType, err)
+	}
+
+	return &message.ResourceRecord{
+		Name:       protocol.ServiceTypeEnumerationName,
+		Type:       protocol.RecordTypePTR,
+		Class:      protocol.ClassIN,
+		TTL:        protocol.TTLService,
+		Data:       targetEncoded,
+		CacheFlush: false, // PTR is shared (multiple service types coexist)
+	}, nil
+}
+
+// BuildNSECRecord constructs an RFC 4034 §4.1 NSEC record asserting which
+// record types exist at name, for RFC 6762 §6.1's negative-response rule: a
+// responder answering a question for a type it has no record of at an owner
+// name it IS authoritative for includes this in the Additional section so
+// the querier can cache the negative answer instead of re-querying.
+//
+// Per RFC 6762 §6.1, the "next domain name" field is set to name itself -
+// mDNS has no zone to walk, so NSEC only ever asserts "here are the types
+// name has" rather than ranging to a successor name - and the cache-flush
+// bit is always set, since an NSEC is exactly as authoritative and as
+// replaceable as the unique records it describes.
+func BuildNSECRecord(name string, ttl uint32, existingTypes []protocol.RecordType) (*message.ResourceRecord, error) {
+	types := make([]uint16, len(existingTypes))
+	for i, t := range existingTypes {
+		types[i] = uint16(t)
+	}
+
+	rdata, err := message.EncodeNSEC(&message.NSECData{NextDomainName: name, TypeBitMap: types})
+	if err != nil {
+		return nil, fmt.Errorf("encoding NSEC record for %q: %w", name, err)
+	}
+
+	return &message.ResourceRecord{
+		Name:       name,
+		Type:       protocol.RecordTypeNSEC,
+		Class:      protocol.ClassIN,
+		TTL:        ttl,
+		Data:       rdata,
+		CacheFlush: true,
+	}, nil
+}
+
+// BuildHostAddressRecords constructs hostname's own A/AAAA records,
+// independent of any service - for answering a direct A/AAAA query, rather
+// than the SRV-target address bundling BuildRecordSet's callers do for a PTR
+// match. ipv4 is skipped (not padded to a placeholder, unlike buildARecord)
+// unless it's exactly 4 bytes; ipv6 entries that aren't exactly 16 bytes are
+// likewise skipped, matching buildAAAARecords.
+func BuildHostAddressRecords(hostname string, ipv4 []byte, ipv6 [][]byte) []*message.ResourceRecord {
+	info := &ServiceInfo{Hostname: hostname, IPv4Address: ipv4, IPv6Addresses: ipv6}
+
+	var out []*message.ResourceRecord
+	if len(ipv4) == 4 {
+		out = append(out, buildARecord(info))
+	}
+	out = append(out, buildAAAARecords(info)...)
+	return out
+}
+
+// buildSubtypePTRRecords constructs one PTR record per RFC 6763 §7.1 subtype.
+//
+// Subtype PTR record format:
+//   - Name: <subtype>._sub._service._proto.local (e.g., "_printer._sub._http._tcp.local")
+//   - Type: PTR (12)
+//   - Class: IN (1)
+//   - TTL: 120 seconds (service TTL)
+//   - RDATA: instance._service._proto.local (same target as the main PTR record)
+//   - CacheFlush: false (PTR is shared)
+//
+// Returns nil if the service advertises no subtypes.
+func buildSubtypePTRRecords(service *ServiceInfo) []*message.ResourceRecord {
+	if len(service.Subtypes) == 0 {
+		return nil
+	}
+
+	// Error impossible: ServiceInfo validated by responder.Service.Validate()
+	targetEncoded, _ := message.EncodeServiceInstanceName(service.InstanceName, service.ServiceType) // nosemgrep: beacon-error-swallowing
+
+	subtypeRecords := make([]*message.ResourceRecord, 0, len(service.Subtypes))
+	for _, subtype := range service.Subtypes {
+		subtypeRecords = append(subtypeRecords, &message.ResourceRecord{
+			Name:       subtype + "._sub." + service.ServiceType,
+			Type:       protocol.RecordTypePTR,
+			Class:      protocol.ClassIN,
+			TTL:        serviceTTL(service),
+			Data:       targetEncoded,
+			CacheFlush: false, // PTR is shared (multiple services can have same subtype)
+		})
+	}
+	return subtypeRecords
+}
+
 // buildSRVRecord constructs an SRV record per RFC 6763 §6.
 //
 // SRV record format:
@@ -138,19 +355,23 @@ func buildSRVRecord(service *ServiceInfo) *message.ResourceRecord {
 		Name:       name,
 		Type:       protocol.RecordTypeSRV,
 		Class:      protocol.ClassIN,
-		TTL:        protocol.TTLService, // 120 seconds
+		TTL:        serviceTTL(service),
 		Data:       data,
 		CacheFlush: true, // SRV is unique (one service instance = one SRV)
 	}
 }
 
-// buildTXTRecordFromService constructs a TXT record for a service per RFC 6763 §6.
+// BuildTXTRecord constructs a TXT record for a service per RFC 6763 §6.
+// BuildRecordSet calls this for its own TXT entry; it's also exported so a
+// caller updating only the TXT record after registration (e.g.
+// responder.Responder.UpdateService) can rebuild one without re-deriving
+// the PTR/SRV/A records too.
 //
 // RFC 6762 §10: TXT records for DNS-SD services use 120 seconds.
 // Service discovery records change more frequently than hostname records.
 //
 // T034: TXT record construction for service
-func buildTXTRecordFromService(service *ServiceInfo) *message.ResourceRecord {
+func BuildTXTRecord(service *ServiceInfo) *message.ResourceRecord {
 	name := service.InstanceName + "." + service.ServiceType
 	data := buildTXTRecord(service.TXTRecords)
 
@@ -158,44 +379,51 @@ func buildTXTRecordFromService(service *ServiceInfo) *message.ResourceRecord {
 		Name:       name,
 		Type:       protocol.RecordTypeTXT,
 		Class:      protocol.ClassIN,
-		TTL:        120, // RFC 6762 §10: 120 seconds for service records
+		TTL:        serviceTTL(service),
 		Data:       data,
 		CacheFlush: true, // TXT is unique per service instance
 	}
 }
 
-// buildTXTRecord encodes TXT records per RFC 6763 §6.
+// buildTXTRecord encodes TXT records per RFC 6763 §6.4.
 //
 // RFC 6763 §6: "If a DNS-SD service has no TXT records, it MUST include a
 // single TXT record consisting of a single zero byte (0x00)."
 //
-// TXT record format per RFC 6763 §6.4:
-//   - Each key-value pair: length byte + "key=value" string
-//   - Multiple pairs concatenated
+// TXT record format per RFC 1035 §3.3.14 / RFC 6763 §6.4:
+//   - Each entry: length byte + the entry's bytes, one of "key" (boolean
+//     attribute), "key=" (empty-value attribute), or "key=value"
+//   - Multiple entries concatenated
 //   - Empty TXT: single 0x00 byte
+//   - Duplicate keys: only the first occurrence is encoded, per RFC 6763
+//     §6.4's "a client MUST silently ignore... all but the first occurrence"
 //
-// Parameters:
-//   - txtRecords: Map of key-value pairs
-//
-// Returns:
-//   - []byte: Encoded TXT record data
+// Entries are pre-validated by responder.Service.Validate() (each encoded
+// entry ≤255 octets), so entryLen never overflows a byte here.
 //
 // FR-031: System MUST create mandatory TXT record with 0x00 byte if empty
 // T034: Implement buildTXTRecord()
-func buildTXTRecord(txtRecords map[string]string) []byte {
+func buildTXTRecord(txtRecords []TXTRecord) []byte {
 	// RFC 6763 §6: Empty TXT MUST be 0x00
 	if len(txtRecords) == 0 {
 		return []byte{0x00}
 	}
 
-	// Encode each key-value pair with length prefix
+	seen := make(map[string]bool, len(txtRecords))
 	data := make([]byte, 0, 256)
-	for key, value := range txtRecords {
-		// Format: "key=value"
-		entry := key + "=" + value
+	for _, record := range txtRecords {
+		if seen[record.Key] {
+			continue // RFC 6763 §6.4: keep only the first occurrence of a key
+		}
+		seen[record.Key] = true
+
+		entry := record.Key
+		if record.Present {
+			entry += "=" + string(record.Value)
+		}
 
 		// Length byte + entry string
-		entryLen := byte(len(entry))
+		entryLen := byte(len(entry)) //nolint:gosec // G115: bounds enforced by Service.Validate()
 		data = append(data, entryLen)
 		data = append(data, []byte(entry)...)
 	}
@@ -234,11 +462,161 @@ func buildARecord(service *ServiceInfo) *message.ResourceRecord {
 	}
 }
 
+// buildAAAARecords constructs one AAAA record per IPv6 address per RFC 3596 §2.1.
+//
+// AAAA record format:
+//   - Name: hostname.local
+//   - Type: AAAA (28)
+//   - Class: IN (1)
+//   - TTL: 4500 seconds (75 minutes per RFC 6762 §10, matching the A record TTL)
+//   - RDATA: IPv6 address (16 bytes)
+//   - CacheFlush: true (AAAA is unique per RFC 6762 §10.2, same as A)
+//
+// Addresses that aren't exactly 16 bytes are skipped rather than padded, since
+// (unlike the single-address A record) there is no single sensible placeholder
+// for a set of addresses.
+func buildAAAARecords(service *ServiceInfo) []*message.ResourceRecord {
+	records := make([]*message.ResourceRecord, 0, len(service.IPv6Addresses))
+	for _, addr := range service.IPv6Addresses {
+		if len(addr) != 16 {
+			continue
+		}
+		records = append(records, &message.ResourceRecord{
+			Name:       service.Hostname,
+			Type:       protocol.RecordTypeAAAA,
+			Class:      protocol.ClassIN,
+			TTL:        4500, // RFC 6762 §10: 4500 seconds (75 min) for hostname records
+			Data:       addr,
+			CacheFlush: true, // AAAA is unique (one hostname = one address set, like A)
+		})
+	}
+	return records
+}
+
 // ResourceRecord is a type alias for message.ResourceRecord.
 // This allows tests to reference ResourceRecord without importing message package.
 type ResourceRecord = message.ResourceRecord
 
-// RecordSet tracks per-record, per-interface multicast timestamps for rate limiting.
+// defaultAdaptiveThreshold, defaultAdaptiveWindow, defaultQuietPeriod and
+// maxAdaptiveInterval tune the RFC 6762 §6 adaptive backoff RecordSet applies
+// on top of RateLimitConfig.MinInterval: hearing defaultAdaptiveThreshold or
+// more identical answers from other hosts for the same (record, interface)
+// within defaultAdaptiveWindow escalates that bucket's refill interval (see
+// RecordObservedAnswer), capped at maxAdaptiveInterval and decaying back to
+// baseline after defaultQuietPeriod without a further observation.
+const (
+	defaultAdaptiveThreshold = 3
+	defaultAdaptiveWindow    = 1 * time.Second
+	defaultQuietPeriod       = 10 * time.Second
+	maxAdaptiveInterval      = 60 * time.Second
+)
+
+// RateLimitConfig configures RecordSet's per-(record, interface) token-bucket
+// rate limiting.
+//
+// RFC 6762 §6.2's hard 1-second/250ms limits are the defaults (see
+// DefaultRateLimitConfig), exposed here as configuration so a responder can
+// tune them, e.g. for a test that can't wait a full second.
+type RateLimitConfig struct {
+	// MinInterval is how long an empty bucket takes to refill to one token,
+	// absent any adaptive backoff: the RFC 6762 §6.2 1-second minimum.
+	MinInterval time.Duration
+
+	// ProbeDefenseInterval is the minimum interval CanMulticastProbeDefense
+	// enforces, independent of MinInterval and any adaptive backoff, per RFC
+	// 6762 §6.2's carve-out for defending a name during probing.
+	ProbeDefenseInterval time.Duration
+
+	// BurstCapacity is the token bucket's capacity: how many multicasts of a
+	// given record on a given interface may happen back-to-back before the
+	// MinInterval refill rate starts applying.
+	BurstCapacity float64
+
+	// AdaptiveFactor multiplies a bucket's refill interval, capped at
+	// maxAdaptiveInterval, once RecordObservedAnswer has seen a chatty
+	// network for that (record, interface) per RFC 6762 §6.
+	AdaptiveFactor float64
+
+	// MaxEntries bounds the number of distinct (record, interface) buckets
+	// tracked; the oldest 10% by last access are evicted once exceeded, the
+	// same LRU scheme security.RateLimiter uses to bound its per-source map.
+	// Zero disables eviction.
+	MaxEntries int
+}
+
+// defaultMaxEntries bounds RecordSet's bucket map so a responder that churns
+// through many short-lived records (e.g. a rapidly reconfigured service set)
+// doesn't grow it unboundedly.
+const defaultMaxEntries = 10000
+
+// DefaultRateLimitConfig returns the RFC 6762 §6.2 defaults: a 1-second
+// refill interval with single-token burst capacity, 250ms probe defense, a
+// doubling adaptive backoff factor, and a 10k-entry bucket LRU.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		MinInterval:          1 * time.Second,
+		ProbeDefenseInterval: 250 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       2,
+		MaxEntries:           defaultMaxEntries,
+	}
+}
+
+// RateLimitMetrics holds Prometheus-style counters for RecordSet's rate
+// limiting, for a responder to expose via its own /metrics endpoint.
+type RateLimitMetrics struct {
+	SuppressedTotal        uint64  // multicasts denied by CanMulticast or CanMulticastProbeDefense
+	SentTotal              uint64  // multicasts recorded via RecordMulticast or Reserve
+	CurrentIntervalSeconds float64 // highest refill interval currently in effect across all tracked records
+	EvictionsTotal         uint64  // buckets dropped by the MaxEntries LRU
+}
+
+// rateLimitBucket is the per-(record, interface) token bucket backing
+// RecordSet's rate limiting.
+type rateLimitBucket struct {
+	tokens           float64
+	lastRefill       time.Time
+	lastMulticast    time.Time
+	lastMulticastSet bool
+	lastAccess       time.Time // updated on every bucket() lookup, for LRU eviction
+
+	// escalationFactor multiplies RateLimitConfig.MinInterval once an
+	// adaptive backoff has been triggered; 1 means no backoff is active.
+	escalationFactor    float64
+	observedCount       int
+	observedWindowStart time.Time
+	lastObserved        time.Time
+
+	// queryRetries counts consecutive NextQueryDelay calls since the last
+	// ResetBackoff, driving BackoffConfig's exponential schedule.
+	queryRetries int
+}
+
+// refill adds tokens accrued since the bucket's last refill at the given
+// interval, capping at capacity. A zero-value lastRefill (a freshly created
+// bucket, already seeded at capacity) just starts the clock.
+func (b *rateLimitBucket) refill(now time.Time, interval time.Duration, capacity float64) {
+	if b.lastRefill.IsZero() {
+		b.lastRefill = now
+		return
+	}
+	if interval <= 0 {
+		return
+	}
+
+	elapsed := now.Sub(b.lastRefill)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens += elapsed.Seconds() / interval.Seconds()
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.lastRefill = now
+}
+
+// RecordSet tracks per-record, per-interface multicast rate limiting state.
 //
 // RFC 6762 §6.2: "A Multicast DNS responder MUST NOT multicast a given resource record
 // on a given interface until at least one second has elapsed since the last time that
@@ -248,47 +626,132 @@ type ResourceRecord = message.ResourceRecord
 //   - PER RECORD: Different records have independent rate limits
 //   - PER INTERFACE: Same record can be multicast on different interfaces simultaneously
 //
-// Exception per RFC 6762 §6.2: Probe defense allows 250ms minimum instead of 1 second
+// Exception per RFC 6762 §6.2: Probe defense allows 250ms minimum instead of 1 second.
 //
-// T073-T074: Implement rate limiting
+// Each (record, interface) pair is tracked as its own token bucket (see
+// RateLimitConfig), so a chatty network observed via RecordObservedAnswer can
+// back a single record off further without affecting the others.
 type RecordSet struct {
-	// lastMulticast tracks per-record, per-interface multicast timestamps
-	// Key: buildRecordKey(rr) + ":" + interfaceID
-	// Value: timestamp of last multicast (Unix nanoseconds for 250ms probe defense precision)
-	lastMulticast map[string]int64
+	mu            sync.Mutex
+	config        RateLimitConfig
+	backoffConfig BackoffConfig
+	buckets       map[string]*rateLimitBucket
+	metrics       RateLimitMetrics
 }
 
-// NewRecordSet creates a new RecordSet for rate limiting tracking.
-//
-// T073: Constructor for RecordSet
+// NewRecordSet creates a new RecordSet using DefaultRateLimitConfig and
+// DefaultBackoffConfig.
 func NewRecordSet() *RecordSet {
+	return NewRecordSetWithConfig(DefaultRateLimitConfig())
+}
+
+// NewRecordSetWithConfig creates a new RecordSet with a custom
+// RateLimitConfig and DefaultBackoffConfig.
+func NewRecordSetWithConfig(config RateLimitConfig) *RecordSet {
+	return NewRecordSetWithConfigs(config, DefaultBackoffConfig())
+}
+
+// NewRecordSetWithConfigs creates a new RecordSet with a custom
+// RateLimitConfig and BackoffConfig.
+func NewRecordSetWithConfigs(config RateLimitConfig, backoffConfig BackoffConfig) *RecordSet {
 	return &RecordSet{
-		lastMulticast: make(map[string]int64),
+		config:        config,
+		backoffConfig: backoffConfig,
+		buckets:       make(map[string]*rateLimitBucket),
+	}
+}
+
+// bucket returns the token bucket for (rr, interfaceID), creating one seeded
+// at full capacity (RFC 6762 §6.2: never multicast before is always allowed)
+// if it doesn't exist yet. Callers must hold rs.mu.
+func (rs *RecordSet) bucket(rr *ResourceRecord, interfaceID string) *rateLimitBucket {
+	key := rs.buildRecordKey(rr) + ":" + interfaceID
+	b, ok := rs.buckets[key]
+	if !ok {
+		if rs.config.MaxEntries > 0 && len(rs.buckets) >= rs.config.MaxEntries {
+			rs.evict()
+		}
+		b = &rateLimitBucket{tokens: rs.config.BurstCapacity, escalationFactor: 1}
+		rs.buckets[key] = b
+	}
+	b.lastAccess = time.Now()
+	return b
+}
+
+// evict drops the oldest 10% of tracked buckets by lastAccess once the
+// bucket map reaches RateLimitConfig.MaxEntries, the same LRU scheme
+// security.RateLimiter uses to bound its per-source map - so a responder
+// churning through many short-lived records doesn't grow the map
+// unboundedly. Callers must hold rs.mu.
+func (rs *RecordSet) evict() {
+	evictCount := rs.config.MaxEntries / 10
+	if evictCount == 0 {
+		evictCount = 1
+	}
+
+	type keyWithAccess struct {
+		key        string
+		lastAccess time.Time
+	}
+	entries := make([]keyWithAccess, 0, len(rs.buckets))
+	for key, b := range rs.buckets {
+		entries = append(entries, keyWithAccess{key: key, lastAccess: b.lastAccess})
+	}
+
+	for i := 0; i < evictCount && i < len(entries); i++ {
+		oldestIdx := i
+		for j := i + 1; j < len(entries); j++ {
+			if entries[j].lastAccess.Before(entries[oldestIdx].lastAccess) {
+				oldestIdx = j
+			}
+		}
+		entries[i], entries[oldestIdx] = entries[oldestIdx], entries[i]
+	}
+
+	for i := 0; i < evictCount && i < len(entries); i++ {
+		delete(rs.buckets, entries[i].key)
+		rs.metrics.EvictionsTotal++
 	}
 }
 
+// effectiveInterval returns b's current refill interval, applying
+// RateLimitConfig.AdaptiveFactor if an adaptive backoff is active and
+// decaying it back to MinInterval once defaultQuietPeriod has passed without
+// a further RecordObservedAnswer call. Callers must hold rs.mu.
+func (rs *RecordSet) effectiveInterval(b *rateLimitBucket, now time.Time) time.Duration {
+	if b.escalationFactor <= 1 {
+		return rs.config.MinInterval
+	}
+	if now.Sub(b.lastObserved) > defaultQuietPeriod {
+		b.escalationFactor = 1
+		return rs.config.MinInterval
+	}
+
+	interval := time.Duration(float64(rs.config.MinInterval) * b.escalationFactor)
+	if interval > maxAdaptiveInterval {
+		interval = maxAdaptiveInterval
+	}
+	return interval
+}
+
 // CanMulticast checks if a record can be multicast on the given interface per RFC 6762 §6.2.
 //
-// RFC 6762 §6.2: "MUST NOT multicast a given resource record on a given interface until
-// at least one second has elapsed since the last time that resource record was multicast
-// on that particular interface."
-//
 // Returns:
-//   - true: Record can be multicast (≥1 second since last multicast, or never multicast)
-//   - false: Record cannot be multicast (rate limit not yet elapsed)
-//
-// T073: Implement CanMulticast()
+//   - true: Record can be multicast (the bucket has a token, or never multicast before)
+//   - false: Record cannot be multicast (bucket is empty; rate limit not yet elapsed)
 func (rs *RecordSet) CanMulticast(rr *ResourceRecord, interfaceID string) bool {
-	key := rs.buildRecordKey(rr) + ":" + interfaceID
-	lastTimeNano, exists := rs.lastMulticast[key]
-	if !exists {
-		// Never multicast before - allowed
-		return true
-	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+	b := rs.bucket(rr, interfaceID)
+	b.refill(now, rs.effectiveInterval(b, now), rs.config.BurstCapacity)
 
-	// RFC 6762 §6.2: Minimum 1 second (1e9 nanoseconds) between multicasts
-	elapsedNano := time.Now().UnixNano() - lastTimeNano
-	return elapsedNano >= 1e9 // 1 second = 1,000,000,000 nanoseconds
+	if b.tokens < 1 {
+		rs.metrics.SuppressedTotal++
+		return false
+	}
+	return true
 }
 
 // CanMulticastProbeDefense checks if probe defense multicast is allowed per RFC 6762 §6.2.
@@ -297,50 +760,239 @@ func (rs *RecordSet) CanMulticast(rr *ResourceRecord, interfaceID string) bool {
 // quickly (at most 250 ms after detecting the conflict) when answering probe queries
 // for the purpose of defending its name."
 //
-// Probe defense has relaxed rate limit: 250ms instead of 1 second.
+// Probe defense checks elapsed time against RateLimitConfig.ProbeDefenseInterval
+// directly rather than the token bucket, so it always takes priority over any
+// adaptive backoff CanMulticast is currently applying.
 //
 // Returns:
-//   - true: Probe defense multicast allowed (≥250ms since last multicast)
+//   - true: Probe defense multicast allowed (≥ProbeDefenseInterval since last multicast)
 //   - false: Too soon for probe defense
-//
-// T074: Implement probe defense exception
 func (rs *RecordSet) CanMulticastProbeDefense(rr *ResourceRecord, interfaceID string) bool {
-	key := rs.buildRecordKey(rr) + ":" + interfaceID
-	lastTimeNano, exists := rs.lastMulticast[key]
-	if !exists {
-		// Never multicast before - allowed
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	b := rs.bucket(rr, interfaceID)
+	if !b.lastMulticastSet {
 		return true
 	}
 
-	// RFC 6762 §6.2: Probe defense minimum 250ms = 250,000,000 nanoseconds
-	elapsedNano := time.Now().UnixNano() - lastTimeNano
-	return elapsedNano >= 250e6 // 250ms in nanoseconds
+	allowed := time.Since(b.lastMulticast) >= rs.config.ProbeDefenseInterval
+	if !allowed {
+		rs.metrics.SuppressedTotal++
+	}
+	return allowed
 }
 
-// RecordMulticast records that a multicast was sent for this record on this interface.
-//
-// This updates the rate limiting timestamp per RFC 6762 §6.2.
-//
-// T074: Implement RecordMulticast()
+// CanMulticastGoodbye reports whether a goodbye (TTL=0) multicast for rr on
+// interfaceID may bypass the normal token-bucket rate limit.
+//
+// RFC 6762 §10.1 goodbye packets announce a service's departure so peers can
+// flush their cache promptly; gating that on the same 1-second guard used for
+// steady-state announcements would leave stale entries in peer caches for up
+// to a second after shutdown, so - like CanMulticastProbeDefense's carve-out
+// for defending a name - goodbye always bypasses the bucket. It always
+// returns true; it exists so a goodbye send path can consult RecordSet for
+// this decision the same way it would for any other multicast, rather than
+// hardcoding the bypass inline.
+func (rs *RecordSet) CanMulticastGoodbye(_ *ResourceRecord, _ string) bool {
+	return true
+}
+
+// RecordMulticast records that a multicast was sent for this record on this
+// interface, consuming a token from its bucket.
 func (rs *RecordSet) RecordMulticast(rr *ResourceRecord, interfaceID string) {
-	key := rs.buildRecordKey(rr) + ":" + interfaceID
-	rs.lastMulticast[key] = time.Now().UnixNano()
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+	b := rs.bucket(rr, interfaceID)
+	b.refill(now, rs.effectiveInterval(b, now), rs.config.BurstCapacity)
+	if b.tokens >= 1 {
+		b.tokens--
+	}
+	b.lastMulticast = now
+	b.lastMulticastSet = true
+	rs.metrics.SentTotal++
+}
+
+// Reserve behaves like CanMulticast immediately followed by RecordMulticast,
+// except that instead of refusing an over-budget multicast outright, it
+// reserves the record's next available token and reports how long the
+// caller must wait before using it - analogous to golang.org/x/time/rate's
+// Limiter.Reserve(). This lets a sender with many records to announce at
+// once (see state.Announcer) schedule the ones that don't fit in the
+// current burst rather than silently dropping them.
+//
+// ok is false only when the bucket's refill interval is degenerate
+// (MinInterval <= 0), since no wait could ever produce a token in that case.
+func (rs *RecordSet) Reserve(rr *ResourceRecord, interfaceID string) (time.Duration, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+	b := rs.bucket(rr, interfaceID)
+	interval := rs.effectiveInterval(b, now)
+	if interval <= 0 {
+		return 0, false
+	}
+	b.refill(now, interval, rs.config.BurstCapacity)
+
+	var wait time.Duration
+	if b.tokens < 1 {
+		wait = time.Duration((1 - b.tokens) * float64(interval))
+	}
+
+	// Reserve the token now (tokens may go negative, representing a debt
+	// that refill() pays down as time passes) so a second Reserve call
+	// before the wait elapses queues behind this one instead of double
+	// booking the same token.
+	b.tokens--
+	b.lastMulticast = now.Add(wait)
+	b.lastMulticastSet = true
+	rs.metrics.SentTotal++
+
+	return wait, true
+}
+
+// RecordObservedAnswer notes that another host multicast an answer identical
+// to rr on interfaceID, for RFC 6762 §6's additional-backoff guidance: a
+// responder that hears an unusually chatty network should back off further
+// than its baseline MinInterval, analogous to gRPC's exponential connection
+// backoff.
+//
+// Once defaultAdaptiveThreshold observations land within defaultAdaptiveWindow
+// for the same (record, interface), that bucket's refill interval is
+// multiplied by RateLimitConfig.AdaptiveFactor (capped at maxAdaptiveInterval
+// total), decaying back to MinInterval after defaultQuietPeriod without a
+// further observation.
+func (rs *RecordSet) RecordObservedAnswer(rr *ResourceRecord, interfaceID string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+	b := rs.bucket(rr, interfaceID)
+
+	if b.observedWindowStart.IsZero() || now.Sub(b.observedWindowStart) > defaultAdaptiveWindow {
+		b.observedWindowStart = now
+		b.observedCount = 0
+	}
+	b.observedCount++
+	b.lastObserved = now
+
+	if b.observedCount >= defaultAdaptiveThreshold {
+		factor := b.escalationFactor * rs.config.AdaptiveFactor
+		if maxFactor := float64(maxAdaptiveInterval) / float64(rs.config.MinInterval); factor > maxFactor {
+			factor = maxFactor
+		}
+		b.escalationFactor = factor
+		b.observedCount = 0
+		b.observedWindowStart = now
+	}
+}
+
+// Metrics returns a snapshot of RecordSet's Prometheus-style rate limiting
+// counters for observability.
+func (rs *RecordSet) Metrics() RateLimitMetrics {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	metrics := rs.metrics
+	metrics.CurrentIntervalSeconds = rs.config.MinInterval.Seconds()
+
+	now := time.Now()
+	for _, b := range rs.buckets {
+		if interval := rs.effectiveInterval(b, now).Seconds(); interval > metrics.CurrentIntervalSeconds {
+			metrics.CurrentIntervalSeconds = interval
+		}
+	}
+	return metrics
 }
 
 // GetLastMulticast returns the last multicast time for a record on an interface.
 //
+// This is the per-record multicast-history store RFC 6762 §5.4's 1/4-TTL
+// rule needs: responder.Responder.recordStaleSinceLastMulticast calls it to
+// decide whether a QU-bit response must multicast instead of unicast (see
+// unicastDest). Buckets are keyed by record+interfaceID, not by querier, so
+// their count is bounded by how many records this responder owns rather
+// than by query traffic - there's no unbounded-growth case for a periodic
+// GC sweep to guard against here.
+//
 // Returns:
 //   - time.Time: Last multicast timestamp
 //   - bool: true if record was multicast before, false if never multicast
-//
-// T074: Helper for testing
 func (rs *RecordSet) GetLastMulticast(rr *ResourceRecord, interfaceID string) (time.Time, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
 	key := rs.buildRecordKey(rr) + ":" + interfaceID
-	lastTimeNano, exists := rs.lastMulticast[key]
-	if !exists {
+	b, ok := rs.buckets[key]
+	if !ok || !b.lastMulticastSet {
 		return time.Time{}, false
 	}
-	return time.Unix(0, lastTimeNano), true
+	return b.lastMulticast, true
+}
+
+// ShouldSuppress reports whether rr should be omitted from a response because
+// a querier has already told us, via a known-answer in its query's Answer
+// Section, that it holds a fresh enough copy, per RFC 6762 §7.1.
+//
+// RFC 6762 §7.1: "A Multicast DNS responder MUST NOT answer a Multicast DNS
+// query if the answer it would give is already included in the Answer
+// Section with an RR TTL at least half the correct value."
+//
+// A known-answer suppresses rr when it matches on Name, Type, Class (the
+// cache-flush bit is not part of record identity per RFC 6762 §10.2), and
+// RDATA, and its TTL is at least half of rr's real TTL.
+//
+// ShouldSuppress builds a throwaway KnownAnswerIndex over knownAnswers on
+// every call; a caller checking many records against the same
+// known-answer list - e.g. ResponseBuilder.BuildResponse, once per query,
+// across its PTR/SRV/TXT/A/HTTPS records - should build one
+// KnownAnswerIndex with NewKnownAnswerIndex and reuse it instead.
+func ShouldSuppress(rr *ResourceRecord, knownAnswers []*ResourceRecord) bool {
+	return NewKnownAnswerIndex(knownAnswers).ShouldSuppress(rr)
+}
+
+// KnownAnswerIndex is a lookup structure over a query's RFC 6762 §7.1
+// Known-Answer list, keyed by record identity: Name, Type, Class without
+// the cache-flush bit, and RDATA.
+// Building one per query and reusing it across every candidate record
+// turns an O(records*knownAnswers) scan into O(records+knownAnswers).
+type KnownAnswerIndex struct {
+	byIdentity map[string]*ResourceRecord
+}
+
+// NewKnownAnswerIndex builds a KnownAnswerIndex over a query's known-answer
+// list.
+func NewKnownAnswerIndex(knownAnswers []*ResourceRecord) *KnownAnswerIndex {
+	idx := &KnownAnswerIndex{byIdentity: make(map[string]*ResourceRecord, len(knownAnswers))}
+	for _, known := range knownAnswers {
+		idx.byIdentity[recordIdentityKey(known)] = known
+	}
+	return idx
+}
+
+// Match returns the known-answer matching rr's identity (Name, Type, Class,
+// RDATA), if any.
+func (idx *KnownAnswerIndex) Match(rr *ResourceRecord) (known *ResourceRecord, found bool) {
+	known, found = idx.byIdentity[recordIdentityKey(rr)]
+	return known, found
+}
+
+// ShouldSuppress reports whether rr should be omitted from a response
+// because idx holds a known-answer for it with a TTL at least half of rr's,
+// per RFC 6762 §7.1 - see the package-level ShouldSuppress.
+func (idx *KnownAnswerIndex) ShouldSuppress(rr *ResourceRecord) bool {
+	known, found := idx.Match(rr)
+	return found && known.TTL >= rr.TTL/2
+}
+
+// recordIdentityKey encodes a's identity (Name, Type, Class with the
+// cache-flush bit masked off, and RDATA) as a KnownAnswerIndex map key, per
+// RFC 6762 §7.1.
+func recordIdentityKey(a *ResourceRecord) string {
+	return fmt.Sprintf("%d:%d:%s:%s", a.Type, a.Class&0x7FFF, a.Name, string(a.Data))
 }
 
 // buildRecordKey generates a unique key for a resource record.
@@ -1,8 +1,13 @@
 package records
 
 import (
+	"bytes"
+	"fmt"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
 )
 
@@ -16,7 +21,7 @@ import (
 // FR-031: System MUST create mandatory TXT record with 0x00 byte if empty
 // T027: Write TXT record tests
 func TestBuildTXTRecord_Empty(t *testing.T) {
-	txtRecords := map[string]string{} // Empty TXT records
+	txtRecords := []TXTRecord{} // Empty TXT records
 
 	data := buildTXTRecord(txtRecords)
 
@@ -36,8 +41,8 @@ func TestBuildTXTRecord_Empty(t *testing.T) {
 //
 // T027: Test single key-value encoding
 func TestBuildTXTRecord_SingleKey(t *testing.T) {
-	txtRecords := map[string]string{
-		"version": "1.0",
+	txtRecords := []TXTRecord{
+		{Key: "version", Value: []byte("1.0"), Present: true},
 	}
 
 	data := buildTXTRecord(txtRecords)
@@ -70,9 +75,9 @@ func TestBuildTXTRecord_SingleKey(t *testing.T) {
 //
 // T027: Test multiple key-value encoding
 func TestBuildTXTRecord_MultipleKeys(t *testing.T) {
-	txtRecords := map[string]string{
-		"version": "1.0",
-		"path":    "/api",
+	txtRecords := []TXTRecord{
+		{Key: "version", Value: []byte("1.0"), Present: true},
+		{Key: "path", Value: []byte("/api"), Present: true},
 	}
 
 	data := buildTXTRecord(txtRecords)
@@ -109,7 +114,7 @@ func TestBuildRecordSet_AllRecordTypes(t *testing.T) {
 		Hostname:     "myhost.local",
 		Port:         8080,
 		IPv4Address:  []byte{192, 168, 1, 100},
-		TXTRecords:   map[string]string{"version": "1.0"},
+		TXTRecords:   []TXTRecord{{Key: "version", Value: []byte("1.0"), Present: true}},
 	}
 
 	recordSet := BuildRecordSet(&service)
@@ -187,6 +192,207 @@ func TestBuildRecordSet_PTRRecord(t *testing.T) {
 	}
 }
 
+// TestBuildRecordSet_TTLOverride verifies a non-zero ServiceInfo.TTL
+// overrides the PTR/SRV/TXT records' default 120-second TTL, without
+// affecting the A record's own TTL.
+func TestBuildRecordSet_TTLOverride(t *testing.T) {
+	service := ServiceInfo{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Hostname:     "myhost.local",
+		Port:         8080,
+		IPv4Address:  []byte{192, 168, 1, 100},
+		TTL:          30,
+	}
+
+	recordSet := BuildRecordSet(&service)
+
+	for _, record := range recordSet {
+		switch record.Type {
+		case protocol.RecordTypePTR, protocol.RecordTypeSRV, protocol.RecordTypeTXT:
+			if record.TTL != 30 {
+				t.Errorf("%v record TTL = %d, want 30 (ServiceInfo.TTL override)", record.Type, record.TTL)
+			}
+		case protocol.RecordTypeA:
+			if record.TTL != protocol.TTLHostname {
+				t.Errorf("A record TTL = %d, want %d (unaffected by ServiceInfo.TTL)", record.TTL, protocol.TTLHostname)
+			}
+		}
+	}
+}
+
+// TestBuildServiceTypeEnumRecord tests the RFC 6763 §9 service-type
+// enumeration PTR record.
+func TestBuildServiceTypeEnumRecord(t *testing.T) {
+	rr, err := BuildServiceTypeEnumRecord("_http._tcp.local")
+	if err != nil {
+		t.Fatalf("BuildServiceTypeEnumRecord() error = %v", err)
+	}
+
+	wantName := "_services._dns-sd._udp.local"
+	if rr.Name != wantName {
+		t.Errorf("Name = %q, want %q", rr.Name, wantName)
+	}
+	if rr.Type != protocol.RecordTypePTR {
+		t.Errorf("Type = %v, want RecordTypePTR", rr.Type)
+	}
+
+	// RFC 6762 §10: PTR records for DNS-SD services use 120 seconds
+	wantTTL := uint32(120)
+	if rr.TTL != wantTTL {
+		t.Errorf("TTL = %d, want %d", rr.TTL, wantTTL)
+	}
+	if rr.CacheFlush {
+		t.Error("CacheFlush = true, want false (PTR is a shared record)")
+	}
+
+	wantData, err := message.EncodeName("_http._tcp.local")
+	if err != nil {
+		t.Fatalf("EncodeName() error = %v", err)
+	}
+	if string(rr.Data) != string(wantData) {
+		t.Errorf("RDATA = %v, want %v", rr.Data, wantData)
+	}
+}
+
+// TestBuildNSECRecord tests the RFC 4034 §4.1 NSEC record BuildResponse
+// would attach to a negative answer per RFC 6762 §6.1.
+func TestBuildNSECRecord(t *testing.T) {
+	rr, err := BuildNSECRecord("host.local", 120, []protocol.RecordType{protocol.RecordTypeA, protocol.RecordTypeTXT})
+	if err != nil {
+		t.Fatalf("BuildNSECRecord() error = %v", err)
+	}
+
+	if rr.Name != "host.local" {
+		t.Errorf("Name = %q, want %q", rr.Name, "host.local")
+	}
+	if rr.Type != protocol.RecordTypeNSEC {
+		t.Errorf("Type = %v, want RecordTypeNSEC", rr.Type)
+	}
+	if rr.TTL != 120 {
+		t.Errorf("TTL = %d, want 120", rr.TTL)
+	}
+	if !rr.CacheFlush {
+		t.Error("CacheFlush = false, want true (NSEC is as unique as the records it describes)")
+	}
+
+	parsed, err := message.ParseNSEC(rr.Data)
+	if err != nil {
+		t.Fatalf("ParseNSEC() error = %v", err)
+	}
+	if parsed.NextDomainName != "host.local" {
+		t.Errorf("NextDomainName = %q, want %q (mDNS NSEC points at itself per RFC 6762 §6.1)", parsed.NextDomainName, "host.local")
+	}
+	wantTypes := []uint16{uint16(protocol.RecordTypeA), uint16(protocol.RecordTypeTXT)}
+	if len(parsed.TypeBitMap) != len(wantTypes) {
+		t.Fatalf("TypeBitMap = %v, want %v", parsed.TypeBitMap, wantTypes)
+	}
+	for i, got := range parsed.TypeBitMap {
+		if got != wantTypes[i] {
+			t.Errorf("TypeBitMap[%d] = %d, want %d", i, got, wantTypes[i])
+		}
+	}
+}
+
+// TestBuildHostAddressRecords verifies BuildHostAddressRecords builds only
+// the address families it's given, skipping an absent or malformed IPv4
+// address rather than padding it to a placeholder the way buildARecord does
+// internally.
+func TestBuildHostAddressRecords(t *testing.T) {
+	ipv6 := [][]byte{{0x20, 0x01, 0xd, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}
+
+	t.Run("both families", func(t *testing.T) {
+		rrs := BuildHostAddressRecords("host.local", []byte{192, 168, 1, 1}, ipv6)
+		if len(rrs) != 2 {
+			t.Fatalf("len(rrs) = %d, want 2", len(rrs))
+		}
+		if rrs[0].Type != protocol.RecordTypeA {
+			t.Errorf("rrs[0].Type = %v, want RecordTypeA", rrs[0].Type)
+		}
+		if rrs[1].Type != protocol.RecordTypeAAAA {
+			t.Errorf("rrs[1].Type = %v, want RecordTypeAAAA", rrs[1].Type)
+		}
+	})
+
+	t.Run("no ipv4", func(t *testing.T) {
+		rrs := BuildHostAddressRecords("host.local", nil, ipv6)
+		if len(rrs) != 1 || rrs[0].Type != protocol.RecordTypeAAAA {
+			t.Errorf("rrs = %v, want exactly one AAAA record", rrs)
+		}
+	})
+
+	t.Run("no ipv6", func(t *testing.T) {
+		rrs := BuildHostAddressRecords("host.local", []byte{192, 168, 1, 1}, nil)
+		if len(rrs) != 1 || rrs[0].Type != protocol.RecordTypeA {
+			t.Errorf("rrs = %v, want exactly one A record", rrs)
+		}
+	})
+
+	t.Run("malformed ipv4 skipped, not padded", func(t *testing.T) {
+		rrs := BuildHostAddressRecords("host.local", []byte{1, 2, 3}, nil)
+		if len(rrs) != 0 {
+			t.Errorf("rrs = %v, want none (malformed IPv4 skipped outright)", rrs)
+		}
+	})
+}
+
+// TestBuildRecordSet_SubtypePTRRecords tests subtype PTR record
+// construction per RFC 6763 §7.1.
+func TestBuildRecordSet_SubtypePTRRecords(t *testing.T) {
+	service := ServiceInfo{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Hostname:     "myhost.local",
+		Port:         8080,
+		IPv4Address:  []byte{192, 168, 1, 100},
+		Subtypes:     []string{"_printer", "_universal"},
+	}
+
+	recordSet := BuildRecordSet(&service)
+
+	// Find the main PTR record's target, which every subtype PTR record
+	// should point at too.
+	var mainPTR *ResourceRecord
+	subtypePTRs := make(map[string]*ResourceRecord)
+	for _, record := range recordSet {
+		if record.Type != protocol.RecordTypePTR {
+			continue
+		}
+		if record.Name == "_http._tcp.local" {
+			mainPTR = record
+			continue
+		}
+		subtypePTRs[record.Name] = record
+	}
+
+	if mainPTR == nil {
+		t.Fatal("BuildRecordSet() did not include main PTR record")
+	}
+
+	for _, subtype := range service.Subtypes {
+		wantName := subtype + "._sub._http._tcp.local"
+		record, found := subtypePTRs[wantName]
+		if !found {
+			t.Fatalf("BuildRecordSet() did not include subtype PTR record %q", wantName)
+		}
+
+		if string(record.Data) != string(mainPTR.Data) {
+			t.Errorf("subtype PTR record %q Data = %v, want same target as main PTR record %v",
+				wantName, record.Data, mainPTR.Data)
+		}
+
+		wantTTL := uint32(120)
+		if record.TTL != wantTTL {
+			t.Errorf("subtype PTR record %q TTL = %d, want %d (RFC 6762 §10: 120s for service records)",
+				wantName, record.TTL, wantTTL)
+		}
+
+		if record.CacheFlush {
+			t.Errorf("subtype PTR record %q CacheFlush = true, want false (PTR is shared)", wantName)
+		}
+	}
+}
+
 // TestBuildRecordSet_SRVRecord_RED tests SRV record construction.
 //
 // TDD Phase: RED
@@ -298,6 +504,110 @@ func TestBuildRecordSet_ARecord(t *testing.T) {
 	}
 }
 
+// aaaaRecords returns every AAAA record in recordSet.
+func aaaaRecords(recordSet []*ResourceRecord) []*ResourceRecord {
+	var out []*ResourceRecord
+	for _, record := range recordSet {
+		if record.Type == protocol.RecordTypeAAAA {
+			out = append(out, record)
+		}
+	}
+	return out
+}
+
+// TestBuildRecordSet_AAAARecord_LinkLocal tests AAAA record construction for
+// a link-local (fe80::/10) address.
+//
+// RFC 6762 §6: AAAA record format mirrors the A record:
+//   - Name: hostname.local
+//   - RDATA: IPv6 address (16 bytes)
+//   - TTL: 4500 seconds (hostname TTL per RFC 6762 §10)
+//   - Cache-flush: true (unique record)
+func TestBuildRecordSet_AAAARecord_LinkLocal(t *testing.T) {
+	linkLocal := net.ParseIP("fe80::1").To16()
+
+	service := ServiceInfo{
+		InstanceName:  "My Printer",
+		ServiceType:   "_http._tcp.local",
+		Hostname:      "myhost.local",
+		Port:          8080,
+		IPv4Address:   []byte{192, 168, 1, 100},
+		IPv6Addresses: [][]byte{linkLocal},
+	}
+
+	aaaa := aaaaRecords(BuildRecordSet(&service))
+	if len(aaaa) != 1 {
+		t.Fatalf("BuildRecordSet() produced %d AAAA records, want 1", len(aaaa))
+	}
+
+	record := aaaa[0]
+	if record.Name != "myhost.local" {
+		t.Errorf("AAAA record Name = %q, want %q", record.Name, "myhost.local")
+	}
+	if record.TTL != 4500 {
+		t.Errorf("AAAA record TTL = %d, want 4500 (RFC 6762 §10: hostname records)", record.TTL)
+	}
+	if !record.CacheFlush {
+		t.Error("AAAA record CacheFlush = false, want true (unique record)")
+	}
+	if !bytes.Equal(record.Data, linkLocal) {
+		t.Errorf("AAAA record Data = %v, want %v", record.Data, linkLocal)
+	}
+}
+
+// TestBuildRecordSet_AAAARecord_ULA tests AAAA record construction for a
+// Unique Local Address (RFC 4193, fc00::/7).
+func TestBuildRecordSet_AAAARecord_ULA(t *testing.T) {
+	ula := net.ParseIP("fd12:3456:789a::1").To16()
+
+	service := ServiceInfo{
+		InstanceName:  "My Printer",
+		ServiceType:   "_http._tcp.local",
+		Hostname:      "myhost.local",
+		Port:          8080,
+		IPv6Addresses: [][]byte{ula},
+	}
+
+	aaaa := aaaaRecords(BuildRecordSet(&service))
+	if len(aaaa) != 1 {
+		t.Fatalf("BuildRecordSet() produced %d AAAA records, want 1", len(aaaa))
+	}
+	if !bytes.Equal(aaaa[0].Data, ula) {
+		t.Errorf("AAAA record Data = %v, want %v", aaaa[0].Data, ula)
+	}
+}
+
+// TestBuildRecordSet_AAAARecord_Global tests AAAA record construction for a
+// global-scope IPv6 address, and that multiple addresses each get their own
+// AAAA record.
+func TestBuildRecordSet_AAAARecord_Global(t *testing.T) {
+	global := net.ParseIP("2001:db8::1").To16()
+	linkLocal := net.ParseIP("fe80::1").To16()
+
+	service := ServiceInfo{
+		InstanceName:  "My Printer",
+		ServiceType:   "_http._tcp.local",
+		Hostname:      "myhost.local",
+		Port:          8080,
+		IPv6Addresses: [][]byte{global, linkLocal},
+	}
+
+	aaaa := aaaaRecords(BuildRecordSet(&service))
+	if len(aaaa) != 2 {
+		t.Fatalf("BuildRecordSet() produced %d AAAA records, want 2", len(aaaa))
+	}
+
+	var sawGlobal bool
+	for _, record := range aaaa {
+		if bytes.Equal(record.Data, global) {
+			sawGlobal = true
+		}
+	}
+	if !sawGlobal {
+		t.Errorf("AAAA records %v did not include global address %v", aaaa, global)
+	}
+}
+
 // TestResourceRecord_CanMulticast tests per-record multicast rate limiting.
 //
 // RFC 6762 §6.2: "A Multicast DNS responder MUST NOT multicast a given resource record
@@ -464,3 +774,446 @@ func TestResourceRecord_CanMulticast_ProbeDefense(t *testing.T) {
 		t.Error("CanMulticast() = true immediately, want false (1 second minimum for regular responses)")
 	}
 }
+
+// TestResourceRecord_CanMulticastGoodbye_BypassesRateLimit verifies that
+// goodbye packets (RFC 6762 §10.1) are never throttled by the regular
+// 1-second token bucket, unlike CanMulticast.
+func TestResourceRecord_CanMulticastGoodbye_BypassesRateLimit(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100},
+	}
+
+	rs := NewRecordSet()
+	rs.RecordMulticast(rr, "eth0")
+
+	// Regular multicast denied (< 1 second since the multicast above)
+	if rs.CanMulticast(rr, "eth0") {
+		t.Error("CanMulticast() = true immediately, want false (1 second minimum)")
+	}
+
+	// Goodbye bypasses the bucket regardless
+	if !rs.CanMulticastGoodbye(rr, "eth0") {
+		t.Error("CanMulticastGoodbye() = false, want true (goodbye bypasses rate limiting)")
+	}
+}
+
+// TestShouldSuppress_FullSuppression tests that a known-answer with TTL at
+// the full (real) value suppresses the record.
+//
+// RFC 6762 §7.1: suppress when a known-answer TTL is ≥50% of the real TTL.
+func TestShouldSuppress_FullSuppression(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+	knownAnswers := []*ResourceRecord{
+		{Name: rr.Name, Type: rr.Type, Class: rr.Class, TTL: 120, Data: rr.Data},
+	}
+
+	if !ShouldSuppress(rr, knownAnswers) {
+		t.Error("ShouldSuppress() = false, want true (known-answer TTL at 100% of real TTL)")
+	}
+}
+
+// TestShouldSuppress_PartialSuppression tests that one matching known-answer
+// among several unrelated ones still suppresses the record.
+func TestShouldSuppress_PartialSuppression(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+	knownAnswers := []*ResourceRecord{
+		{Name: "other._http._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN, TTL: 120, Data: []byte{0x01, 'x'}},
+		{Name: rr.Name, Type: rr.Type, Class: rr.Class, TTL: 100, Data: rr.Data},
+	}
+
+	if !ShouldSuppress(rr, knownAnswers) {
+		t.Error("ShouldSuppress() = false, want true (one known-answer matches with TTL ≥50%)")
+	}
+}
+
+// TestShouldSuppress_TTLBelowHalf tests that a known-answer below 50% of the
+// real TTL does NOT suppress the record - the querier's cache is stale
+// enough that we should refresh it.
+func TestShouldSuppress_TTLBelowHalf(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+	knownAnswers := []*ResourceRecord{
+		{Name: rr.Name, Type: rr.Type, Class: rr.Class, TTL: 59, Data: rr.Data}, // < 60 = TTL/2
+	}
+
+	if ShouldSuppress(rr, knownAnswers) {
+		t.Error("ShouldSuppress() = true, want false (known-answer TTL < 50% of real TTL)")
+	}
+}
+
+// TestShouldSuppress_NoMatch tests that an empty or unrelated known-answer
+// list never suppresses.
+func TestShouldSuppress_NoMatch(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	if ShouldSuppress(rr, nil) {
+		t.Error("ShouldSuppress() = true, want false (no known-answers)")
+	}
+
+	unrelated := []*ResourceRecord{
+		{Name: "other._http._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN, TTL: 120, Data: []byte{0x01, 'y'}},
+	}
+	if ShouldSuppress(rr, unrelated) {
+		t.Error("ShouldSuppress() = true, want false (no matching known-answer)")
+	}
+}
+
+// TestKnownAnswerIndex_MatchAndShouldSuppress tests that KnownAnswerIndex
+// gives the same answers as ShouldSuppress when built once and reused
+// across several candidate records, including exposing the matched
+// known-answer's TTL via Match.
+func TestKnownAnswerIndex_MatchAndShouldSuppress(t *testing.T) {
+	ptr := &ResourceRecord{
+		Name:  "_http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+	srv := &ResourceRecord{
+		Name:  "MyPrinter._http._tcp.local",
+		Type:  protocol.RecordTypeSRV,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x00, 0x00, 0x00, 0x00, 0x1F, 0x90},
+	}
+	knownAnswers := []*ResourceRecord{
+		{Name: ptr.Name, Type: ptr.Type, Class: ptr.Class, TTL: 120, Data: ptr.Data},
+	}
+
+	idx := NewKnownAnswerIndex(knownAnswers)
+
+	known, found := idx.Match(ptr)
+	if !found {
+		t.Fatal("Match(ptr) found = false, want true")
+	}
+	if known.TTL != 120 {
+		t.Errorf("Match(ptr).TTL = %d, want 120", known.TTL)
+	}
+	if !idx.ShouldSuppress(ptr) {
+		t.Error("ShouldSuppress(ptr) = false, want true (known-answer TTL at 100%)")
+	}
+
+	if _, found := idx.Match(srv); found {
+		t.Error("Match(srv) found = true, want false (no known-answer for SRV)")
+	}
+	if idx.ShouldSuppress(srv) {
+		t.Error("ShouldSuppress(srv) = true, want false (no matching known-answer)")
+	}
+}
+
+// TestKnownAnswerIndex_EmptyKnownAnswers tests that an index built over an
+// empty or nil known-answer list never suppresses and never matches.
+func TestKnownAnswerIndex_EmptyKnownAnswers(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "_http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	idx := NewKnownAnswerIndex(nil)
+	if _, found := idx.Match(rr); found {
+		t.Error("Match() found = true, want false (empty index)")
+	}
+	if idx.ShouldSuppress(rr) {
+		t.Error("ShouldSuppress() = true, want false (empty index)")
+	}
+}
+
+// TestRecordSet_BurstCapacity tests that a token bucket with BurstCapacity
+// greater than 1 allows that many multicasts back-to-back before the
+// MinInterval rate limit applies.
+func TestRecordSet_BurstCapacity(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	rs := NewRecordSetWithConfig(RateLimitConfig{
+		MinInterval:          1 * time.Second,
+		ProbeDefenseInterval: 250 * time.Millisecond,
+		BurstCapacity:        3,
+		AdaptiveFactor:       2,
+	})
+
+	for i := 0; i < 3; i++ {
+		if !rs.CanMulticast(rr, "eth0") {
+			t.Fatalf("CanMulticast() burst #%d = false, want true (within BurstCapacity)", i+1)
+		}
+		rs.RecordMulticast(rr, "eth0")
+	}
+
+	if rs.CanMulticast(rr, "eth0") {
+		t.Error("CanMulticast() = true after exhausting BurstCapacity, want false")
+	}
+}
+
+// TestRecordSet_AdaptiveEscalation tests that RecordObservedAnswer escalates
+// a bucket's refill interval once the adaptive threshold is reached within
+// the adaptive window, per RFC 6762 §6.
+func TestRecordSet_AdaptiveEscalation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping timing test in short mode")
+	}
+
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	rs := NewRecordSetWithConfig(RateLimitConfig{
+		MinInterval:          40 * time.Millisecond,
+		ProbeDefenseInterval: 10 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       3,
+	})
+
+	rs.RecordMulticast(rr, "eth0")
+
+	for i := 0; i < defaultAdaptiveThreshold; i++ {
+		rs.RecordObservedAnswer(rr, "eth0")
+	}
+
+	// Escalated interval is 3x MinInterval (120ms); after only 60ms - well
+	// past the unescalated 40ms interval but short of the escalated one -
+	// the bucket should still be empty.
+	time.Sleep(60 * time.Millisecond)
+	if rs.CanMulticast(rr, "eth0") {
+		t.Error("CanMulticast() = true after 60ms with adaptive backoff active, want false (escalated interval is 120ms)")
+	}
+
+	// Past the escalated 120ms interval, the bucket should have refilled.
+	time.Sleep(80 * time.Millisecond)
+	if !rs.CanMulticast(rr, "eth0") {
+		t.Error("CanMulticast() = false after escalated interval elapsed, want true")
+	}
+
+	if got := rs.Metrics().CurrentIntervalSeconds; got < (120 * time.Millisecond).Seconds() {
+		t.Errorf("Metrics().CurrentIntervalSeconds = %v, want at least %v while escalated", got, (120 * time.Millisecond).Seconds())
+	}
+}
+
+// TestRecordSet_AdaptiveEscalation_PerInterfaceIsolation tests that an
+// adaptive backoff triggered by observations on one interface doesn't affect
+// the same record's bucket on a different interface.
+func TestRecordSet_AdaptiveEscalation_PerInterfaceIsolation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping timing test in short mode")
+	}
+
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	rs := NewRecordSetWithConfig(RateLimitConfig{
+		MinInterval:          30 * time.Millisecond,
+		ProbeDefenseInterval: 10 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       4,
+	})
+
+	rs.RecordMulticast(rr, "eth0")
+	rs.RecordMulticast(rr, "wlan0")
+
+	for i := 0; i < defaultAdaptiveThreshold; i++ {
+		rs.RecordObservedAnswer(rr, "eth0")
+	}
+
+	// 50ms: past wlan0's unescalated 30ms interval, short of eth0's
+	// escalated 120ms interval.
+	time.Sleep(50 * time.Millisecond)
+
+	if rs.CanMulticast(rr, "eth0") {
+		t.Error("CanMulticast(eth0) = true while eth0 is escalated, want false")
+	}
+	if !rs.CanMulticast(rr, "wlan0") {
+		t.Error("CanMulticast(wlan0) = false, want true (wlan0 unaffected by eth0's adaptive backoff)")
+	}
+}
+
+// TestRecordSet_ProbeDefensePriority tests that CanMulticastProbeDefense
+// keeps using RateLimitConfig.ProbeDefenseInterval even while a bucket is
+// under adaptive backoff, so probe defense always takes priority per RFC
+// 6762 §6.2.
+func TestRecordSet_ProbeDefensePriority(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping timing test in short mode")
+	}
+
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100},
+	}
+
+	rs := NewRecordSetWithConfig(RateLimitConfig{
+		MinInterval:          40 * time.Millisecond,
+		ProbeDefenseInterval: 20 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       10,
+	})
+
+	rs.RecordMulticast(rr, "eth0")
+	for i := 0; i < defaultAdaptiveThreshold; i++ {
+		rs.RecordObservedAnswer(rr, "eth0")
+	}
+
+	// 30ms: past ProbeDefenseInterval (20ms), short of both the unescalated
+	// (40ms) and escalated (400ms) regular intervals.
+	time.Sleep(30 * time.Millisecond)
+
+	if rs.CanMulticast(rr, "eth0") {
+		t.Error("CanMulticast() = true at 30ms under adaptive backoff, want false")
+	}
+	if !rs.CanMulticastProbeDefense(rr, "eth0") {
+		t.Error("CanMulticastProbeDefense() = false at 30ms, want true (probe defense ignores adaptive backoff)")
+	}
+}
+
+// TestRecordSet_Metrics tests that Metrics() reports suppressed and sent
+// counters for observability.
+func TestRecordSet_Metrics(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	rs := NewRecordSet()
+
+	rs.RecordMulticast(rr, "eth0")
+	rs.CanMulticast(rr, "eth0")             // denied: bucket just emptied
+	rs.CanMulticastProbeDefense(rr, "eth0") // denied: < 250ms
+
+	metrics := rs.Metrics()
+	if metrics.SentTotal != 1 {
+		t.Errorf("Metrics().SentTotal = %d, want 1", metrics.SentTotal)
+	}
+	if metrics.SuppressedTotal != 2 {
+		t.Errorf("Metrics().SuppressedTotal = %d, want 2", metrics.SuppressedTotal)
+	}
+	if want := DefaultRateLimitConfig().MinInterval.Seconds(); metrics.CurrentIntervalSeconds != want {
+		t.Errorf("Metrics().CurrentIntervalSeconds = %v, want %v (no adaptive backoff active)", metrics.CurrentIntervalSeconds, want)
+	}
+}
+
+// TestRecordSet_Reserve tests that Reserve grants an immediate (zero-wait)
+// reservation while the bucket has a token, then returns an increasing wait
+// once it's exhausted instead of refusing outright.
+func TestRecordSet_Reserve(t *testing.T) {
+	rr := &ResourceRecord{
+		Name:  "myservice._http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{0x08, 'M', 'y', 'P', 'r', 'i', 'n', 't', 'e', 'r'},
+	}
+
+	rs := NewRecordSetWithConfig(RateLimitConfig{
+		MinInterval:          1 * time.Second,
+		ProbeDefenseInterval: 250 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       2,
+	})
+
+	wait, ok := rs.Reserve(rr, "eth0")
+	if !ok || wait != 0 {
+		t.Errorf("Reserve() first call = (%v, %v), want (0, true)", wait, ok)
+	}
+
+	wait, ok = rs.Reserve(rr, "eth0")
+	if !ok {
+		t.Fatal("Reserve() second call ok = false, want true")
+	}
+	if wait < 900*time.Millisecond || wait > 1100*time.Millisecond {
+		t.Errorf("Reserve() second call wait = %v, want ~1s (bucket just exhausted)", wait)
+	}
+
+	// A third call stacks behind the second reservation rather than
+	// reusing its (already spoken for) token.
+	wait3, ok := rs.Reserve(rr, "eth0")
+	if !ok {
+		t.Fatal("Reserve() third call ok = false, want true")
+	}
+	if wait3 <= wait {
+		t.Errorf("Reserve() third call wait = %v, want > second call's wait %v", wait3, wait)
+	}
+}
+
+// TestRecordSet_MaxEntriesEviction tests that the bucket map is bounded by
+// RateLimitConfig.MaxEntries, evicting the least-recently-used entries once
+// exceeded rather than growing unboundedly.
+func TestRecordSet_MaxEntriesEviction(t *testing.T) {
+	rs := NewRecordSetWithConfig(RateLimitConfig{
+		MinInterval:          1 * time.Second,
+		ProbeDefenseInterval: 250 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       2,
+		MaxEntries:           10,
+	})
+
+	for i := 0; i < 15; i++ {
+		rr := &ResourceRecord{
+			Name:  fmt.Sprintf("service%d._http._tcp.local", i),
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   4500,
+			Data:  []byte{byte(i)},
+		}
+		rs.RecordMulticast(rr, "eth0")
+	}
+
+	rs.mu.Lock()
+	trackedBuckets := len(rs.buckets)
+	rs.mu.Unlock()
+
+	if trackedBuckets > 10 {
+		t.Errorf("len(buckets) = %d, want <= MaxEntries (10)", trackedBuckets)
+	}
+	if metrics := rs.Metrics(); metrics.EvictionsTotal == 0 {
+		t.Error("Metrics().EvictionsTotal = 0, want > 0 after exceeding MaxEntries")
+	}
+}
@@ -81,8 +81,8 @@ func (r *RecordTTL) IsExpired() bool {
 // T017: Map record types to TTL values per RFC 6762 §10
 func GetTTLForRecordType(rt protocol.RecordType) uint32 {
 	switch rt {
-	case protocol.RecordTypeA:
-		// A records use TTLHostname (4500s) per RFC 6762 §10
+	case protocol.RecordTypeA, protocol.RecordTypeAAAA:
+		// A/AAAA records use TTLHostname (4500s) per RFC 6762 §10
 		return protocol.TTLHostname
 
 	case protocol.RecordTypeSRV, protocol.RecordTypeTXT, protocol.RecordTypePTR:
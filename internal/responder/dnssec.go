@@ -0,0 +1,300 @@
+package responder
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// SignerConfig enables DNSSEC signing of mDNS answer RRsets per a subset of
+// RFC 4034/4035. When set on a ResponseBuilder via WithSigner,
+// ResponseBuilder.BuildResponse additionally emits an RRSIG (type 46) over
+// each answer RRset and advertises the corresponding DNSKEY (type 48) in the
+// additional section.
+//
+// This gives Beacon parity with recursive resolvers that want cryptographic
+// assurance for LAN service records; it is not a substitute for a delegated
+// chain of trust, since mDNS has no parent zone to hold a DS record.
+type SignerConfig struct {
+	// Algorithm identifies the signing algorithm. Supported: 13 (ECDSA
+	// P-256/SHA-256, RFC 6605) and 15 (Ed25519, RFC 8080).
+	Algorithm message.DNSSECAlgorithm
+
+	// SignerName is the owner name of the DNSKEY record (typically the
+	// responder's hostname, e.g. "myhost.local").
+	SignerName string
+
+	// Signer produces signatures for the configured Algorithm. Use an
+	// *ecdsa.PrivateKey (P-256) for algorithm 13 or an ed25519.PrivateKey
+	// for algorithm 15.
+	Signer crypto.Signer
+
+	keyTag    uint16
+	publicKey []byte
+}
+
+// NewSignerConfig builds a SignerConfig from a private key, deriving the
+// wire-format public key and RFC 4034 Appendix B key tag up front so signing
+// does not repeat that work per-response.
+func NewSignerConfig(algorithm message.DNSSECAlgorithm, signerName string, signer crypto.Signer) (*SignerConfig, error) {
+	publicKey, err := encodeDNSSECPublicKey(algorithm, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &SignerConfig{
+		Algorithm:  algorithm,
+		SignerName: signerName,
+		Signer:     signer,
+		publicKey:  publicKey,
+	}
+	sc.keyTag = message.KeyTag(message.EncodeDNSKEY(&message.DNSKEYData{
+		Flags:     message.DNSKEYFlagZoneKey,
+		Protocol:  message.DNSKEYProtocol,
+		Algorithm: algorithm,
+		PublicKey: publicKey,
+	}))
+
+	return sc, nil
+}
+
+// encodeDNSSECPublicKey encodes signer's public key per RFC 4034 §2.1.4,
+// using the per-algorithm encoding from RFC 6605 §4 (ECDSA) or RFC 8080 §3
+// (Ed25519).
+func encodeDNSSECPublicKey(algorithm message.DNSSECAlgorithm, signer crypto.Signer) ([]byte, error) {
+	switch algorithm {
+	case message.AlgorithmECDSAP256SHA256:
+		pub, ok := signer.Public().(*ecdsa.PublicKey)
+		if !ok || pub.Curve != elliptic.P256() {
+			return nil, &errors.ValidationError{
+				Field:   "Signer",
+				Message: "algorithm 13 requires an ECDSA P-256 key",
+			}
+		}
+		// RFC 6605 §4: the public key is the concatenation of the fixed-size
+		// (32-byte) big-endian X and Y coordinates, with no compression and
+		// no leading format octet.
+		pubKey := make([]byte, 64)
+		pub.X.FillBytes(pubKey[0:32])
+		pub.Y.FillBytes(pubKey[32:64])
+		return pubKey, nil
+
+	case message.AlgorithmED25519:
+		pub, ok := signer.Public().(ed25519.PublicKey)
+		if !ok {
+			return nil, &errors.ValidationError{
+				Field:   "Signer",
+				Message: "algorithm 15 requires an Ed25519 key",
+			}
+		}
+		return []byte(pub), nil
+
+	default:
+		return nil, &errors.ValidationError{
+			Field:   "Algorithm",
+			Value:   algorithm,
+			Message: "unsupported DNSSEC algorithm (supported: 13, 15)",
+		}
+	}
+}
+
+// DNSKEYRecord returns the DNSKEY resource record to advertise alongside
+// signed answers, owned by sc.SignerName.
+func (sc *SignerConfig) DNSKEYRecord(ttl uint32) *message.ResourceRecord {
+	rdata := message.EncodeDNSKEY(&message.DNSKEYData{
+		Flags:     message.DNSKEYFlagZoneKey,
+		Protocol:  message.DNSKEYProtocol,
+		Algorithm: sc.Algorithm,
+		PublicKey: sc.publicKey,
+	})
+
+	return &message.ResourceRecord{
+		Name:       sc.SignerName,
+		Type:       protocol.RecordTypeDNSKEY,
+		Class:      protocol.ClassIN,
+		TTL:        ttl,
+		Data:       rdata,
+		CacheFlush: true,
+	}
+}
+
+// SignRRset produces an RRSIG record covering rrset per RFC 4034 §3.1 and
+// §6. All records in rrset MUST share the same owner name, type, class, and
+// TTL; that TTL becomes the RRSIG's Original TTL.
+//
+// inception and expiration are Unix timestamps (seconds); mDNS records are
+// typically re-signed well before expiration on every announce, so short
+// validity windows (minutes to hours) are appropriate.
+func (sc *SignerConfig) SignRRset(rrset []*message.ResourceRecord, inception, expiration uint32) (*message.ResourceRecord, error) {
+	if len(rrset) == 0 {
+		return nil, &errors.ValidationError{Field: "rrset", Message: "cannot sign an empty RRset"}
+	}
+
+	owner := rrset[0].Name
+	rrType := rrset[0].Type
+	class := rrset[0].Class
+	ttl := rrset[0].TTL
+	for _, rr := range rrset[1:] {
+		if rr.Name != owner || rr.Type != rrType || rr.Class != class {
+			return nil, &errors.ValidationError{
+				Field:   "rrset",
+				Message: "all records in an RRset must share owner name, type, and class",
+			}
+		}
+	}
+
+	sigData := &message.RRSIGData{
+		TypeCovered:         uint16(rrType),
+		Algorithm:           sc.Algorithm,
+		Labels:              message.LabelCount(owner),
+		OriginalTTL:         ttl,
+		SignatureExpiration: expiration,
+		SignatureInception:  inception,
+		KeyTag:              sc.keyTag,
+		SignerName:          sc.SignerName,
+	}
+
+	preimage, err := rrsigPreimage(sigData, rrset)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := sc.sign(preimage)
+	if err != nil {
+		return nil, err
+	}
+	sigData.Signature = signature
+
+	rdata, err := message.EncodeRRSIG(sigData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message.ResourceRecord{
+		Name:       owner,
+		Type:       protocol.RecordTypeRRSIG,
+		Class:      class,
+		TTL:        ttl,
+		Data:       rdata,
+		CacheFlush: true,
+	}, nil
+}
+
+// sign produces a signature over preimage using the algorithm-specific
+// encoding from RFC 6605 §4 (ECDSA) or RFC 8080 §3 (Ed25519).
+func (sc *SignerConfig) sign(preimage []byte) ([]byte, error) {
+	switch sc.Algorithm {
+	case message.AlgorithmECDSAP256SHA256:
+		priv, ok := sc.Signer.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, &errors.ValidationError{Field: "Signer", Message: "algorithm 13 requires an *ecdsa.PrivateKey"}
+		}
+		digest := sha256.Sum256(preimage)
+		r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+		if err != nil {
+			return nil, fmt.Errorf("ecdsa sign: %w", err)
+		}
+		// RFC 6605 §4: signature is R and S as fixed-size (32-byte) big-endian
+		// integers concatenated, not ASN.1 DER.
+		sig := make([]byte, 64)
+		r.FillBytes(sig[0:32])
+		s.FillBytes(sig[32:64])
+		return sig, nil
+
+	case message.AlgorithmED25519:
+		priv, ok := sc.Signer.(ed25519.PrivateKey)
+		if !ok {
+			return nil, &errors.ValidationError{Field: "Signer", Message: "algorithm 15 requires an ed25519.PrivateKey"}
+		}
+		// RFC 8080 §3: Ed25519 signs the message directly; there is no
+		// separate pre-hash step.
+		return ed25519.Sign(priv, preimage), nil
+
+	default:
+		return nil, &errors.ValidationError{
+			Field:   "Algorithm",
+			Value:   sc.Algorithm,
+			Message: "unsupported DNSSEC algorithm (supported: 13, 15)",
+		}
+	}
+}
+
+// rrsigPreimage builds the data to be signed per RFC 4034 §3.1.8.1:
+// RRSIG_RDATA (excluding the Signature field) followed by the canonical
+// form of each RR in the RRset, per RFC 4034 §6.3.
+func rrsigPreimage(sigData *message.RRSIGData, rrset []*message.ResourceRecord) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// RRSIG RDATA minus the signature: encode via EncodeRRSIG with an empty
+	// Signature and trim nothing off, since EncodeRRSIG appends Signature
+	// last and we pass none here.
+	rdataPrefix, err := message.EncodeRRSIG(&message.RRSIGData{
+		TypeCovered:         sigData.TypeCovered,
+		Algorithm:           sigData.Algorithm,
+		Labels:              sigData.Labels,
+		OriginalTTL:         sigData.OriginalTTL,
+		SignatureExpiration: sigData.SignatureExpiration,
+		SignatureInception:  sigData.SignatureInception,
+		KeyTag:              sigData.KeyTag,
+		SignerName:          sigData.SignerName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(rdataPrefix)
+
+	canonical := canonicalRRset(rrset, sigData.OriginalTTL)
+	for _, rr := range canonical {
+		nameEncoded, err := message.EncodeOwnerName(message.CanonicalizeName(rr.Name))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(nameEncoded)
+
+		var fixed [10]byte
+		binary.BigEndian.PutUint16(fixed[0:2], uint16(rr.Type))
+		binary.BigEndian.PutUint16(fixed[2:4], uint16(rr.Class))
+		binary.BigEndian.PutUint32(fixed[4:8], rr.TTL)
+		if len(rr.Data) > 65535 {
+			return nil, &errors.ValidationError{Field: "rrset", Message: "RDATA exceeds 65535 bytes"}
+		}
+		binary.BigEndian.PutUint16(fixed[8:10], uint16(len(rr.Data))) //nolint:gosec // G115: bounds checked above
+		buf.Write(fixed[:])
+		buf.Write(rr.Data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// canonicalRRset returns a copy of rrset in canonical form per RFC 4034 §6:
+// owner names lowercased (§6.2) and the RRs sorted by their RDATA in
+// canonical wire-format octet order (§6.3), with TTL normalized to
+// originalTTL as required before computing the signature.
+func canonicalRRset(rrset []*message.ResourceRecord, originalTTL uint32) []*message.ResourceRecord {
+	canonical := make([]*message.ResourceRecord, len(rrset))
+	for i, rr := range rrset {
+		canonical[i] = &message.ResourceRecord{
+			Name:       message.CanonicalizeName(rr.Name),
+			Type:       rr.Type,
+			Class:      rr.Class,
+			TTL:        originalTTL,
+			Data:       rr.Data,
+			CacheFlush: rr.CacheFlush,
+		}
+	}
+	sort.Slice(canonical, func(i, j int) bool {
+		return bytes.Compare(canonical[i].Data, canonical[j].Data) < 0
+	})
+	return canonical
+}
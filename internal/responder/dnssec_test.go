@@ -0,0 +1,168 @@
+package responder
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestSignerConfig_SignRRset_ECDSA tests RRSIG generation and verification
+// with algorithm 13 (ECDSA P-256/SHA-256) per RFC 6605.
+func TestSignerConfig_SignRRset_ECDSA(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	sc, err := NewSignerConfig(message.AlgorithmECDSAP256SHA256, "host.local", priv)
+	if err != nil {
+		t.Fatalf("NewSignerConfig() error = %v", err)
+	}
+
+	rrset := []*message.ResourceRecord{
+		{
+			Name:  "My Printer._http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{0x04, 't', 'e', 's', 't', 0x05, 'l', 'o', 'c', 'a', 'l', 0x00},
+		},
+	}
+
+	rrsig, err := sc.SignRRset(rrset, 1000, 2000)
+	if err != nil {
+		t.Fatalf("SignRRset() error = %v", err)
+	}
+	if rrsig.Type != protocol.RecordTypeRRSIG {
+		t.Errorf("SignRRset() Type = %v, want RRSIG", rrsig.Type)
+	}
+
+	sigData, err := message.ParseRRSIG(rrsig.Data)
+	if err != nil {
+		t.Fatalf("ParseRRSIG() error = %v", err)
+	}
+	if sigData.TypeCovered != uint16(protocol.RecordTypePTR) {
+		t.Errorf("TypeCovered = %d, want %d", sigData.TypeCovered, protocol.RecordTypePTR)
+	}
+	if sigData.KeyTag != sc.keyTag {
+		t.Errorf("KeyTag = %d, want %d", sigData.KeyTag, sc.keyTag)
+	}
+	if len(sigData.Signature) != 64 {
+		t.Errorf("len(Signature) = %d, want 64 (32-byte R || 32-byte S)", len(sigData.Signature))
+	}
+
+	preimage, err := rrsigPreimage(sigData, rrset)
+	if err != nil {
+		t.Fatalf("rrsigPreimage() error = %v", err)
+	}
+	digest := sha256.Sum256(preimage)
+	r := new(big.Int).SetBytes(sigData.Signature[0:32])
+	s := new(big.Int).SetBytes(sigData.Signature[32:64])
+	if !ecdsa.Verify(&priv.PublicKey, digest[:], r, s) {
+		t.Error("ECDSA signature failed to verify")
+	}
+}
+
+// TestSignerConfig_SignRRset_Ed25519 tests RRSIG generation with algorithm 15
+// (Ed25519) per RFC 8080.
+func TestSignerConfig_SignRRset_Ed25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	sc, err := NewSignerConfig(message.AlgorithmED25519, "host.local", priv)
+	if err != nil {
+		t.Fatalf("NewSignerConfig() error = %v", err)
+	}
+
+	rrset := []*message.ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   4500,
+			Data:  []byte{192, 168, 1, 1},
+		},
+	}
+
+	rrsig, err := sc.SignRRset(rrset, 1000, 2000)
+	if err != nil {
+		t.Fatalf("SignRRset() error = %v", err)
+	}
+
+	sigData, err := message.ParseRRSIG(rrsig.Data)
+	if err != nil {
+		t.Fatalf("ParseRRSIG() error = %v", err)
+	}
+	if len(sigData.Signature) != ed25519.SignatureSize {
+		t.Errorf("len(Signature) = %d, want %d", len(sigData.Signature), ed25519.SignatureSize)
+	}
+
+	preimage, err := rrsigPreimage(sigData, rrset)
+	if err != nil {
+		t.Fatalf("rrsigPreimage() error = %v", err)
+	}
+	if !ed25519.Verify(pub, preimage, sigData.Signature) {
+		t.Error("Ed25519 signature failed to verify")
+	}
+}
+
+// TestSignerConfig_DNSKEYRecord tests that the advertised DNSKEY round-trips
+// through the wire format and carries the algorithm's key tag.
+func TestSignerConfig_DNSKEYRecord(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	sc, err := NewSignerConfig(message.AlgorithmED25519, "host.local", priv)
+	if err != nil {
+		t.Fatalf("NewSignerConfig() error = %v", err)
+	}
+
+	dnskeyRR := sc.DNSKEYRecord(protocol.TTLHostname)
+	if dnskeyRR.Type != protocol.RecordTypeDNSKEY {
+		t.Errorf("DNSKEYRecord() Type = %v, want DNSKEY", dnskeyRR.Type)
+	}
+
+	dnskeyData, err := message.ParseDNSKEY(dnskeyRR.Data)
+	if err != nil {
+		t.Fatalf("ParseDNSKEY() error = %v", err)
+	}
+	if dnskeyData.Flags&message.DNSKEYFlagZoneKey == 0 {
+		t.Error("DNSKEY Flags missing Zone Key bit")
+	}
+	if message.KeyTag(dnskeyRR.Data) != sc.keyTag {
+		t.Errorf("KeyTag mismatch: got %d, want %d", message.KeyTag(dnskeyRR.Data), sc.keyTag)
+	}
+}
+
+// TestSignerConfig_SignRRset_MixedOwners rejects an RRset whose records
+// don't share a name/type/class, since RFC 4034 §3.1 signs one RRset at a time.
+func TestSignerConfig_SignRRset_MixedOwners(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	sc, err := NewSignerConfig(message.AlgorithmED25519, "host.local", priv)
+	if err != nil {
+		t.Fatalf("NewSignerConfig() error = %v", err)
+	}
+
+	rrset := []*message.ResourceRecord{
+		{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{1, 2, 3, 4}},
+		{Name: "b.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{5, 6, 7, 8}},
+	}
+
+	if _, err := sc.SignRRset(rrset, 1000, 2000); err == nil {
+		t.Error("SignRRset() error = nil, want error for mismatched owners")
+	}
+}
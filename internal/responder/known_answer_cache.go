@@ -0,0 +1,277 @@
+package responder
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/metrics"
+)
+
+// knownAnswerCacheMinHold and knownAnswerCacheMaxHold bound how long
+// KnownAnswerCache waits for continuation packets of a truncated (TC=1)
+// query per RFC 6762 §7.2: "in general a Multicast DNS responder SHOULD
+// delay its responses by a random amount of time selected with uniform
+// random distribution in the range 400-500 ms."
+const (
+	knownAnswerCacheMinHold = 400 * time.Millisecond
+	knownAnswerCacheMaxHold = 500 * time.Millisecond
+
+	// knownAnswerCacheShardCount splits cache entries across this many
+	// independently-locked shards (keyed by a hash of the source address) so a
+	// busy link sending continuation packets from many querier addresses
+	// doesn't serialize behind a single mutex.
+	knownAnswerCacheShardCount = 16
+
+	// knownAnswerCacheMaxEntriesPerShard bounds each shard's pending-entry
+	// count: a flood of TC=1 queries spoofing many distinct (source
+	// address, transaction ID) pairs could otherwise grow this cache
+	// without limit for the length of the 400-500ms hold window. Once a
+	// shard is full, Merge drops the oldest pending entry to make room,
+	// the same FIFO-by-insertion-order eviction querier.KnownAnswerCache
+	// uses to bound its own map.
+	knownAnswerCacheMaxEntriesPerShard = 256
+)
+
+// knownAnswerCacheKey identifies a query's continuation packets, which per
+// RFC 6762 §7.2 arrive from the same source address and carry the same
+// transaction (message) ID as the truncated packet that announced them.
+type knownAnswerCacheKey struct {
+	sourceAddr string
+	id         uint16
+}
+
+// knownAnswerCacheEntry accumulates questions and known-answers across a
+// truncated query's continuation packets until the hold timer fires.
+type knownAnswerCacheEntry struct {
+	questions    []message.Question
+	knownAnswers []*message.ResourceRecord
+	timer        *time.Timer
+}
+
+// knownAnswerCacheShard is one independently-locked partition of a
+// KnownAnswerCache, indexed by a hash of the entry's source address. order
+// records insertion order for FIFO eviction once entries exceeds
+// knownAnswerCacheMaxEntriesPerShard. Every removal from entries - by Take,
+// Cancel, Evict, fire, or evictOldestLocked itself - goes through
+// deleteLocked, which keeps order in sync rather than leaving a stale key
+// behind for it to grow without bound across the shard's lifetime.
+type knownAnswerCacheShard struct {
+	mu      sync.Mutex
+	entries map[knownAnswerCacheKey]*knownAnswerCacheEntry
+	order   []knownAnswerCacheKey
+}
+
+// deleteLocked removes key from both entries and order. Callers must hold
+// shard.mu and must already have stopped the entry's timer, if any.
+func (s *knownAnswerCacheShard) deleteLocked(key knownAnswerCacheKey) {
+	delete(s.entries, key)
+
+	for i, k := range s.order {
+		if k == key {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// KnownAnswerCache aggregates a multi-packet mDNS query (RFC 6762 §7.2: a
+// truncated, TC=1, query followed by one or more Known-Answer continuation
+// packets) so that ResponseBuilder.ApplyKnownAnswerSuppression runs once
+// against the union of all their Answer Sections rather than against each
+// packet in isolation.
+//
+// Each (source address, transaction ID) pair gets its own hold timer,
+// randomized to 400-500ms per §7.2; every Merge call for that pair resets
+// the timer, and onReady fires with the merged questions/known-answers once
+// no further continuation arrives before it expires. Entries are split
+// across knownAnswerCacheShardCount shards keyed by a hash of the source
+// address (IP and port, as passed to Merge), so continuation traffic from different queriers doesn't contend on
+// one lock. Each shard is bounded to knownAnswerCacheMaxEntriesPerShard
+// pending sources; once full, the oldest pending entry is dropped to make
+// room for a new one rather than growing without limit.
+//
+// Note on metrics: the per-record suppression outcome itself is reported by
+// ResponseBuilder (beacon_responder_known_answer_suppressed_total and
+// beacon_responder_known_answer_ttl_ratio) once it runs against the merged
+// set this cache hands back. KnownAnswerCache reports the cache's own
+// lifecycle - reassembly and eviction - which ResponseBuilder has no
+// visibility into.
+type KnownAnswerCache struct {
+	shards  [knownAnswerCacheShardCount]*knownAnswerCacheShard
+	onReady func(sourceAddr string, id uint16, questions []message.Question, knownAnswers []*message.ResourceRecord)
+	metrics metrics.Metrics
+}
+
+// NewKnownAnswerCache creates a KnownAnswerCache that invokes onReady once a
+// source/transaction's hold window elapses without a further continuation
+// packet. m is used to report reassembly and eviction counters; a nil m
+// defaults to metrics.NoOp{}.
+func NewKnownAnswerCache(onReady func(sourceAddr string, id uint16, questions []message.Question, knownAnswers []*message.ResourceRecord), m metrics.Metrics) *KnownAnswerCache {
+	if m == nil {
+		m = metrics.NoOp{}
+	}
+
+	c := &KnownAnswerCache{
+		onReady: onReady,
+		metrics: m,
+	}
+	for i := range c.shards {
+		c.shards[i] = &knownAnswerCacheShard{entries: make(map[knownAnswerCacheKey]*knownAnswerCacheEntry)}
+	}
+
+	return c
+}
+
+// shardFor returns the shard responsible for sourceAddr.
+func (c *KnownAnswerCache) shardFor(sourceAddr string) *knownAnswerCacheShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sourceAddr))
+
+	return c.shards[h.Sum32()%knownAnswerCacheShardCount]
+}
+
+// Merge buffers questions and knownAnswers from one packet of a truncated
+// query, keyed by (sourceAddr, id), and (re)starts the 400-500ms hold timer.
+func (c *KnownAnswerCache) Merge(sourceAddr string, id uint16, questions []message.Question, knownAnswers []*message.ResourceRecord) {
+	key := knownAnswerCacheKey{sourceAddr: sourceAddr, id: id}
+	shard := c.shardFor(sourceAddr)
+
+	shard.mu.Lock()
+	entry, exists := shard.entries[key]
+	if !exists {
+		entry = &knownAnswerCacheEntry{}
+		shard.entries[key] = entry
+		shard.order = append(shard.order, key)
+		shard.evictOldestLocked(c.metrics)
+	} else {
+		entry.timer.Stop()
+	}
+
+	entry.questions = append(entry.questions, questions...)
+	entry.knownAnswers = append(entry.knownAnswers, knownAnswers...)
+	entry.timer = time.AfterFunc(knownAnswerCacheHoldDuration(), func() {
+		c.fire(shard, key)
+	})
+	shard.mu.Unlock()
+
+	if exists {
+		c.metrics.IncCounter("beacon_responder_known_answer_cache_reassembled_total", nil)
+	}
+}
+
+// evictOldestLocked drops entries in insertion order until the shard is
+// back within knownAnswerCacheMaxEntriesPerShard, stopping each dropped
+// entry's timer so it never fires onReady for data that's been discarded.
+// Callers must hold shard.mu.
+func (s *knownAnswerCacheShard) evictOldestLocked(m metrics.Metrics) {
+	for len(s.entries) > knownAnswerCacheMaxEntriesPerShard && len(s.order) > 0 {
+		oldest := s.order[0]
+
+		entry, exists := s.entries[oldest]
+		if !exists {
+			s.order = s.order[1:]
+			continue
+		}
+		entry.timer.Stop()
+		s.deleteLocked(oldest)
+		m.IncCounter("beacon_responder_known_answer_cache_overflow_total", nil)
+	}
+}
+
+// Take removes and returns any pending entry for (sourceAddr, id) without
+// waiting for its hold timer, for a caller that has just received what it
+// believes is the final (TC=0) packet of the sequence and wants to merge it
+// with whatever continuation packets arrived before it. found is false if
+// there was nothing pending - the normal case for a query that was never
+// preceded by a TC=1 packet.
+func (c *KnownAnswerCache) Take(sourceAddr string, id uint16) (questions []message.Question, knownAnswers []*message.ResourceRecord, found bool) {
+	key := knownAnswerCacheKey{sourceAddr: sourceAddr, id: id}
+	shard := c.shardFor(sourceAddr)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	entry, exists := shard.entries[key]
+	if !exists {
+		return nil, nil, false
+	}
+	entry.timer.Stop()
+	shard.deleteLocked(key)
+
+	return entry.questions, entry.knownAnswers, true
+}
+
+// Cancel discards any pending state for (sourceAddr, id) without invoking
+// onReady - e.g. because the caller's context was cancelled.
+func (c *KnownAnswerCache) Cancel(sourceAddr string, id uint16) {
+	key := knownAnswerCacheKey{sourceAddr: sourceAddr, id: id}
+	shard := c.shardFor(sourceAddr)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if entry, exists := shard.entries[key]; exists {
+		entry.timer.Stop()
+		shard.deleteLocked(key)
+	}
+}
+
+// Evict immediately discards every pending entry whose source address is
+// srcIP, without invoking onReady, so a security.SourceFilter rejection or
+// rate-limit drop can free the accumulated state right away instead of
+// waiting out the remainder of the hold window.
+func (c *KnownAnswerCache) Evict(srcIP net.IP) {
+	ip := srcIP.String()
+
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		for key, entry := range shard.entries {
+			if !knownAnswerCacheKeyMatchesIP(key, ip) {
+				continue
+			}
+			entry.timer.Stop()
+			shard.deleteLocked(key)
+			c.metrics.IncCounter("beacon_responder_known_answer_cache_evicted_total", nil)
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// knownAnswerCacheKeyMatchesIP reports whether key's source address belongs
+// to ip, whether or not sourceAddr carries a port.
+func knownAnswerCacheKeyMatchesIP(key knownAnswerCacheKey, ip string) bool {
+	if host, _, err := net.SplitHostPort(key.sourceAddr); err == nil {
+		return host == ip
+	}
+
+	return key.sourceAddr == ip
+}
+
+// fire removes and delivers the merged entry for key, if it is still
+// pending (Cancel, Evict, or a concurrent Merge may have already claimed
+// it).
+func (c *KnownAnswerCache) fire(shard *knownAnswerCacheShard, key knownAnswerCacheKey) {
+	shard.mu.Lock()
+	entry, exists := shard.entries[key]
+	if exists {
+		shard.deleteLocked(key)
+	}
+	shard.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	c.onReady(key.sourceAddr, key.id, entry.questions, entry.knownAnswers)
+}
+
+// knownAnswerCacheHoldDuration returns a random duration in [400ms, 500ms)
+// per RFC 6762 §7.2's uniform-random jitter recommendation.
+func knownAnswerCacheHoldDuration() time.Duration {
+	span := knownAnswerCacheMaxHold - knownAnswerCacheMinHold
+	return knownAnswerCacheMinHold + time.Duration(rand.Int63n(int64(span)))
+}
@@ -0,0 +1,345 @@
+package responder
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/metrics"
+)
+
+// TestKnownAnswerCache_MultiPacketAggregation tests that two continuation
+// packets for the same (source, transaction ID) are merged into a single
+// onReady call once the hold window elapses.
+//
+// RFC 6762 §7.2: a truncated (TC=1) query's Known-Answer list may span
+// multiple packets; responders must wait 400-500ms for the rest before
+// acting on it.
+func TestKnownAnswerCache_MultiPacketAggregation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+
+	ready := make(chan struct{})
+	var gotQuestions []message.Question
+	var gotKnownAnswers []*message.ResourceRecord
+
+	c := NewKnownAnswerCache(func(_ string, _ uint16, questions []message.Question, knownAnswers []*message.ResourceRecord) {
+		gotQuestions = questions
+		gotKnownAnswers = knownAnswers
+		close(ready)
+	}, nil)
+
+	q1 := message.Question{QNAME: "_http._tcp.local", QTYPE: 12, QCLASS: 1}
+	ka1 := &message.ResourceRecord{Name: "_http._tcp.local"}
+	c.Merge("192.168.1.50:5353", 0x1234, []message.Question{q1}, []*message.ResourceRecord{ka1})
+
+	ka2 := &message.ResourceRecord{Name: "_ssh._tcp.local"}
+	c.Merge("192.168.1.50:5353", 0x1234, nil, []*message.ResourceRecord{ka2})
+
+	select {
+	case <-ready:
+	case <-time.After(2 * time.Second):
+		t.Fatal("KnownAnswerCache did not fire onReady within 2s")
+	}
+
+	if len(gotQuestions) != 1 {
+		t.Fatalf("merged questions = %d, want 1", len(gotQuestions))
+	}
+	if len(gotKnownAnswers) != 2 {
+		t.Fatalf("merged known-answers = %d, want 2 (from both packets)", len(gotKnownAnswers))
+	}
+}
+
+// TestKnownAnswerCache_Cancel tests that Cancel prevents onReady from firing
+// and leaves no pending state behind, so a caller reacting to context
+// cancellation doesn't leak the entry or get a late callback.
+func TestKnownAnswerCache_Cancel(t *testing.T) {
+	fired := make(chan struct{}, 1)
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {
+		fired <- struct{}{}
+	}, nil)
+
+	c.Merge("192.168.1.50:5353", 0xABCD, nil, []*message.ResourceRecord{{Name: "_http._tcp.local"}})
+	c.Cancel("192.168.1.50:5353", 0xABCD)
+
+	select {
+	case <-fired:
+		t.Fatal("onReady fired after Cancel, want no callback")
+	case <-time.After(600 * time.Millisecond):
+		// Expected: the hold window passed with no callback.
+	}
+
+	shard := c.shardFor("192.168.1.50:5353")
+	shard.mu.Lock()
+	_, exists := shard.entries[knownAnswerCacheKey{sourceAddr: "192.168.1.50:5353", id: 0xABCD}]
+	shard.mu.Unlock()
+	if exists {
+		t.Error("KnownAnswerCache retained an entry after Cancel, want it removed")
+	}
+}
+
+// TestKnownAnswerCache_IndependentKeys tests that different (source, ID)
+// pairs are aggregated independently, including across shards.
+func TestKnownAnswerCache_IndependentKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+
+	type result struct {
+		sourceAddr string
+		id         uint16
+	}
+	results := make(chan result, 2)
+
+	c := NewKnownAnswerCache(func(sourceAddr string, id uint16, _ []message.Question, _ []*message.ResourceRecord) {
+		results <- result{sourceAddr: sourceAddr, id: id}
+	}, nil)
+
+	c.Merge("10.0.0.1:5353", 1, nil, []*message.ResourceRecord{{Name: "a"}})
+	c.Merge("10.0.0.2:5353", 2, nil, []*message.ResourceRecord{{Name: "b"}})
+
+	seen := make(map[result]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-results:
+			seen[r] = true
+		case <-time.After(2 * time.Second):
+			t.Fatal("did not receive both independent callbacks within 2s")
+		}
+	}
+
+	if !seen[result{sourceAddr: "10.0.0.1:5353", id: 1}] || !seen[result{sourceAddr: "10.0.0.2:5353", id: 2}] {
+		t.Errorf("got callbacks %v, want both independent keys to fire", seen)
+	}
+}
+
+// TestKnownAnswerCache_Evict tests that Evict drops an IP's pending entries
+// without invoking onReady, and leaves other sources untouched.
+func TestKnownAnswerCache_Evict(t *testing.T) {
+	fired := make(chan knownAnswerCacheKey, 2)
+	c := NewKnownAnswerCache(func(sourceAddr string, id uint16, _ []message.Question, _ []*message.ResourceRecord) {
+		fired <- knownAnswerCacheKey{sourceAddr: sourceAddr, id: id}
+	}, nil)
+
+	c.Merge("192.168.1.50:5353", 1, nil, []*message.ResourceRecord{{Name: "evicted"}})
+	c.Merge("192.168.1.51:5353", 2, nil, []*message.ResourceRecord{{Name: "kept"}})
+
+	c.Evict(net.ParseIP("192.168.1.50"))
+
+	select {
+	case key := <-fired:
+		if key.sourceAddr != "192.168.1.51:5353" {
+			t.Fatalf("onReady fired for %v, want only the non-evicted source", key)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not receive the non-evicted entry's callback within 2s")
+	}
+
+	select {
+	case key := <-fired:
+		t.Fatalf("onReady fired for evicted entry %v, want no callback", key)
+	case <-time.After(100 * time.Millisecond):
+		// Expected: the evicted entry never fires.
+	}
+}
+
+// countingMetrics records IncCounter calls by name so tests can assert on
+// which counters fired without depending on a real backend.
+type countingMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{counts: make(map[string]int)}
+}
+
+func (m *countingMetrics) IncCounter(name string, _ map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[name]++
+}
+
+func (m *countingMetrics) ObserveHistogram(string, float64, map[string]string) {}
+
+func (m *countingMetrics) get(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[name]
+}
+
+var _ metrics.Metrics = (*countingMetrics)(nil)
+
+// TestKnownAnswerCache_MetricsReassembledAndEvicted tests that a
+// continuation merge reports a reassembly, and Evict reports an eviction.
+func TestKnownAnswerCache_MetricsReassembledAndEvicted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+
+	m := newCountingMetrics()
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {}, m)
+
+	c.Merge("192.168.1.50:5353", 0x1, nil, []*message.ResourceRecord{{Name: "first"}})
+	if got := m.get("beacon_responder_known_answer_cache_reassembled_total"); got != 0 {
+		t.Fatalf("reassembled count after first packet = %d, want 0", got)
+	}
+
+	c.Merge("192.168.1.50:5353", 0x1, nil, []*message.ResourceRecord{{Name: "second"}})
+	if got := m.get("beacon_responder_known_answer_cache_reassembled_total"); got != 1 {
+		t.Fatalf("reassembled count after continuation = %d, want 1", got)
+	}
+
+	c.Merge("192.168.1.60:5353", 0x2, nil, []*message.ResourceRecord{{Name: "third"}})
+	c.Evict(net.ParseIP("192.168.1.60"))
+	if got := m.get("beacon_responder_known_answer_cache_evicted_total"); got != 1 {
+		t.Fatalf("evicted count = %d, want 1", got)
+	}
+
+	c.Cancel("192.168.1.50:5353", 0x1)
+}
+
+// TestKnownAnswerCache_OverflowEvictsOldest tests that once a shard holds
+// more than knownAnswerCacheMaxEntriesPerShard pending sources, Merge drops
+// the oldest one (reporting an overflow metric) instead of growing without
+// bound, and that Take can no longer find the evicted entry.
+func TestKnownAnswerCache_OverflowEvictsOldest(t *testing.T) {
+	m := newCountingMetrics()
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {}, m)
+
+	// Every Merge below shares one source address and varies only the
+	// transaction ID, so shardFor (which hashes just the address) routes
+	// them all to the same shard - otherwise knownAnswerCacheShardCount
+	// would spread them too thin to ever overflow a single shard's cap.
+	const sourceAddr = "192.168.1.50:5353"
+	overflowCount := knownAnswerCacheMaxEntriesPerShard + 50
+	for id := 0; id < overflowCount; id++ {
+		c.Merge(sourceAddr, uint16(id), nil, []*message.ResourceRecord{{Name: fmt.Sprintf("record-%d", id)}})
+	}
+
+	shard := c.shardFor(sourceAddr)
+	shard.mu.Lock()
+	got := len(shard.entries)
+	shard.mu.Unlock()
+	if got != knownAnswerCacheMaxEntriesPerShard {
+		t.Fatalf("shard entries after overflow = %d, want %d (capped)", got, knownAnswerCacheMaxEntriesPerShard)
+	}
+
+	wantEvictions := overflowCount - knownAnswerCacheMaxEntriesPerShard
+	if got := m.get("beacon_responder_known_answer_cache_overflow_total"); got != wantEvictions {
+		t.Errorf("overflow metric = %d, want %d", got, wantEvictions)
+	}
+
+	if _, _, found := c.Take(sourceAddr, 0); found {
+		t.Error("Take found transaction ID 0, want it evicted as the oldest entry")
+	}
+	if _, _, found := c.Take(sourceAddr, uint16(overflowCount-1)); !found {
+		t.Error("Take did not find the most recently merged transaction ID, want it still pending")
+	}
+}
+
+// TestKnownAnswerCache_TakeDrainsOrder tests that Take - the normal-traffic
+// path, where a final TC=0 packet claims an entry well before
+// knownAnswerCacheMaxEntriesPerShard is ever reached - removes the entry's
+// key from the shard's order slice, not just its entries map. Without this,
+// order grows by one on every Merge and never shrinks on the Take path, so a
+// long-running responder's shard.order would grow without bound even though
+// entries itself stays small.
+func TestKnownAnswerCache_TakeDrainsOrder(t *testing.T) {
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {}, nil)
+
+	const sourceAddr = "192.168.1.50:5353"
+	shard := c.shardFor(sourceAddr)
+
+	for id := 0; id < 1000; id++ {
+		c.Merge(sourceAddr, uint16(id), nil, []*message.ResourceRecord{{Name: fmt.Sprintf("record-%d", id)}})
+		if _, _, found := c.Take(sourceAddr, uint16(id)); !found {
+			t.Fatalf("Take(id=%d) found=false right after Merge, want true", id)
+		}
+	}
+
+	shard.mu.Lock()
+	gotOrder := len(shard.order)
+	gotEntries := len(shard.entries)
+	shard.mu.Unlock()
+
+	if gotEntries != 0 {
+		t.Fatalf("shard.entries len = %d after draining every merge via Take, want 0", gotEntries)
+	}
+	if gotOrder != 0 {
+		t.Fatalf("shard.order len = %d after draining every merge via Take, want 0 (order must shrink along with entries, not just grow)", gotOrder)
+	}
+}
+
+// TestKnownAnswerCache_Take tests that Take synchronously returns and
+// removes a pending entry's merged questions/known-answers without waiting
+// for the hold timer, and that onReady never fires for an entry Take
+// already claimed.
+func TestKnownAnswerCache_Take(t *testing.T) {
+	fired := make(chan struct{}, 1)
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {
+		fired <- struct{}{}
+	}, nil)
+
+	q := message.Question{QNAME: "_http._tcp.local", QTYPE: 12, QCLASS: 1}
+	ka1 := &message.ResourceRecord{Name: "_http._tcp.local"}
+	ka2 := &message.ResourceRecord{Name: "_ssh._tcp.local"}
+	c.Merge("192.168.1.50:5353", 0x1234, []message.Question{q}, []*message.ResourceRecord{ka1})
+	c.Merge("192.168.1.50:5353", 0x1234, nil, []*message.ResourceRecord{ka2})
+
+	questions, knownAnswers, found := c.Take("192.168.1.50:5353", 0x1234)
+	if !found {
+		t.Fatal("Take found=false, want true for a pending entry")
+	}
+	if len(questions) != 1 || len(knownAnswers) != 2 {
+		t.Fatalf("Take returned %d questions, %d known-answers, want 1, 2", len(questions), len(knownAnswers))
+	}
+
+	select {
+	case <-fired:
+		t.Fatal("onReady fired after Take, want no callback")
+	case <-time.After(600 * time.Millisecond):
+		// Expected: Take stopped the hold timer before it could fire.
+	}
+
+	if _, _, found := c.Take("192.168.1.50:5353", 0x1234); found {
+		t.Error("second Take found=true, want false once the entry has already been taken")
+	}
+}
+
+// TestKnownAnswerCache_Take_NotFound tests that Take reports found=false
+// for a (sourceAddr, id) with no pending entry, rather than panicking or
+// fabricating a result.
+func TestKnownAnswerCache_Take_NotFound(t *testing.T) {
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {}, nil)
+
+	questions, knownAnswers, found := c.Take("192.168.1.50:5353", 0xFFFF)
+	if found {
+		t.Error("Take found=true for an unknown key, want false")
+	}
+	if questions != nil || knownAnswers != nil {
+		t.Errorf("Take returned non-nil slices for found=false: questions=%v, knownAnswers=%v", questions, knownAnswers)
+	}
+}
+
+// TestKnownAnswerCache_ShardsSpreadKeys is a smoke test that distinct
+// source addresses land in more than one shard, guarding against a hash
+// regression that collapses every key onto a single shard and defeats the
+// point of sharding.
+func TestKnownAnswerCache_ShardsSpreadKeys(t *testing.T) {
+	c := NewKnownAnswerCache(func(_ string, _ uint16, _ []message.Question, _ []*message.ResourceRecord) {}, nil)
+
+	seen := make(map[*knownAnswerCacheShard]struct{})
+	for i := 0; i < 64; i++ {
+		addr := fmt.Sprintf("10.0.%d.%d:5353", i/256, i%256)
+		seen[c.shardFor(addr)] = struct{}{}
+	}
+
+	if len(seen) < 2 {
+		t.Errorf("64 distinct source addresses landed in %d shard(s), want more than 1", len(seen))
+	}
+}
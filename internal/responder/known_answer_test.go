@@ -206,3 +206,163 @@ func TestApplyKnownAnswerSuppression_DifferentType(t *testing.T) {
 		t.Error("shouldInclude = false, want true (different type should NOT suppress)")
 	}
 }
+
+// fakeMetrics is a minimal metrics.Metrics recorder for assertions.
+type fakeMetrics struct {
+	counters   map[string]int
+	histograms map[string][]float64
+	labels     map[string]map[string]string
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		counters:   make(map[string]int),
+		histograms: make(map[string][]float64),
+		labels:     make(map[string]map[string]string),
+	}
+}
+
+func (f *fakeMetrics) IncCounter(name string, labels map[string]string) {
+	f.counters[name]++
+	f.labels[name] = labels
+}
+
+func (f *fakeMetrics) ObserveHistogram(name string, v float64, labels map[string]string) {
+	f.histograms[name] = append(f.histograms[name], v)
+	f.labels[name] = labels
+}
+
+// TestApplyKnownAnswerSuppression_ReportsMetrics verifies that a suppressed
+// record increments beacon_responder_known_answer_suppressed_total and
+// observes beacon_responder_known_answer_ttl_ratio, tagged with the
+// record's type, while an included record only observes the ratio.
+func TestApplyKnownAnswerSuppression_ReportsMetrics(t *testing.T) {
+	fm := newFakeMetrics()
+	rb := NewResponseBuilder().WithMetrics(fm)
+
+	ourRecord := &message.ResourceRecord{
+		Name:  "_http._tcp.local",
+		Type:  protocol.RecordTypePTR,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{1, 'x'},
+	}
+	knownAnswer := &message.ResourceRecord{
+		Name: ourRecord.Name, Type: ourRecord.Type, Class: ourRecord.Class,
+		TTL: 120, Data: ourRecord.Data,
+	}
+
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(ourRecord, []*message.ResourceRecord{knownAnswer}); shouldInclude {
+		t.Fatal("shouldInclude = true, want false (should suppress)")
+	}
+
+	if got := fm.counters["beacon_responder_known_answer_suppressed_total"]; got != 1 {
+		t.Errorf("beacon_responder_known_answer_suppressed_total = %d, want 1", got)
+	}
+	if got := fm.labels["beacon_responder_known_answer_suppressed_total"]["record_type"]; got != "PTR" {
+		t.Errorf("record_type label = %q, want PTR", got)
+	}
+	ratios := fm.histograms["beacon_responder_known_answer_ttl_ratio"]
+	if len(ratios) != 1 || ratios[0] != 1.0 {
+		t.Errorf("beacon_responder_known_answer_ttl_ratio = %v, want [1.0]", ratios)
+	}
+
+	// A known-answer below the suppression threshold still reports the
+	// ratio, but not the suppressed counter.
+	knownAnswer.TTL = 59 // < 50% of 120
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(ourRecord, []*message.ResourceRecord{knownAnswer}); !shouldInclude {
+		t.Fatal("shouldInclude = false, want true (below suppression threshold)")
+	}
+	if got := fm.counters["beacon_responder_known_answer_suppressed_total"]; got != 1 {
+		t.Errorf("beacon_responder_known_answer_suppressed_total = %d, want 1 (unchanged)", got)
+	}
+	if got := fm.histograms["beacon_responder_known_answer_ttl_ratio"]; len(got) != 2 {
+		t.Errorf("beacon_responder_known_answer_ttl_ratio observations = %d, want 2", len(got))
+	}
+}
+
+// TestApplyKnownAnswerSuppression_NoMetricsWithoutSink verifies that
+// ApplyKnownAnswerSuppression doesn't panic or otherwise misbehave when no
+// WithMetrics sink was configured (the metrics.NoOp{} default).
+func TestApplyKnownAnswerSuppression_NoMetricsWithoutSink(t *testing.T) {
+	rb := NewResponseBuilder()
+	ourRecord := &message.ResourceRecord{Name: "_http._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN, TTL: 120, Data: []byte{1, 'x'}}
+	knownAnswer := &message.ResourceRecord{Name: ourRecord.Name, Type: ourRecord.Type, Class: ourRecord.Class, TTL: 120, Data: ourRecord.Data}
+
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(ourRecord, []*message.ResourceRecord{knownAnswer}); shouldInclude {
+		t.Error("shouldInclude = true, want false (should suppress)")
+	}
+}
+
+// TestWithUniqueRecordSuppression_ExemptsUniqueRecords verifies that
+// WithUniqueRecordSuppression(false) always includes a unique (CacheFlush)
+// record regardless of a matching, fresh known-answer, while a shared
+// record is still suppressed normally.
+func TestWithUniqueRecordSuppression_ExemptsUniqueRecords(t *testing.T) {
+	rb := NewResponseBuilder().WithUniqueRecordSuppression(false)
+
+	uniqueRecord := &message.ResourceRecord{
+		Name: "MyPrinter._http._tcp.local", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN,
+		TTL: 120, Data: []byte{1, 'x'}, CacheFlush: true,
+	}
+	knownAnswer := &message.ResourceRecord{
+		Name: uniqueRecord.Name, Type: uniqueRecord.Type, Class: uniqueRecord.Class,
+		TTL: 120, Data: uniqueRecord.Data,
+	}
+
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(uniqueRecord, []*message.ResourceRecord{knownAnswer}); !shouldInclude {
+		t.Error("shouldInclude = false, want true (unique records exempted from suppression)")
+	}
+
+	sharedRecord := &message.ResourceRecord{
+		Name: "_http._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN,
+		TTL: 120, Data: []byte{2, 'y'},
+	}
+	sharedKnownAnswer := &message.ResourceRecord{
+		Name: sharedRecord.Name, Type: sharedRecord.Type, Class: sharedRecord.Class,
+		TTL: 120, Data: sharedRecord.Data,
+	}
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(sharedRecord, []*message.ResourceRecord{sharedKnownAnswer}); shouldInclude {
+		t.Error("shouldInclude = true, want false (shared records still suppressed)")
+	}
+}
+
+// TestApplyKnownAnswerSuppression_CallsOnSuppressed verifies that
+// WithOnSuppressed's callback fires with the suppressed record exactly when
+// a record is actually suppressed, and not otherwise.
+func TestApplyKnownAnswerSuppression_CallsOnSuppressed(t *testing.T) {
+	var got *message.ResourceRecord
+	rb := NewResponseBuilder().WithOnSuppressed(func(record *message.ResourceRecord) {
+		got = record
+	})
+
+	ourRecord := &message.ResourceRecord{
+		Name: "_http._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN,
+		TTL: 120, Data: []byte{1, 'x'},
+	}
+	knownAnswer := &message.ResourceRecord{
+		Name: ourRecord.Name, Type: ourRecord.Type, Class: ourRecord.Class,
+		TTL: 120, Data: ourRecord.Data,
+	}
+
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(ourRecord, []*message.ResourceRecord{knownAnswer}); shouldInclude {
+		t.Fatal("shouldInclude = true, want false (should suppress)")
+	}
+	if got == nil {
+		t.Fatal("onSuppressed callback was not called")
+	}
+	if got.Name != ourRecord.Name {
+		t.Errorf("onSuppressed record.Name = %q, want %q", got.Name, ourRecord.Name)
+	}
+
+	// Below the suppression threshold, the record is included and the
+	// callback must not fire again.
+	got = nil
+	knownAnswer.TTL = 59 // < 50% of 120
+	if shouldInclude := rb.ApplyKnownAnswerSuppression(ourRecord, []*message.ResourceRecord{knownAnswer}); !shouldInclude {
+		t.Fatal("shouldInclude = false, want true (below suppression threshold)")
+	}
+	if got != nil {
+		t.Errorf("onSuppressed callback called = %v, want nil (not suppressed)", got)
+	}
+}
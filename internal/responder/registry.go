@@ -2,35 +2,378 @@
 package responder
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/records"
 )
 
+// leaseScanInterval is how often MemoryRegistry scans for leased services
+// past their expiry.
+const leaseScanInterval = 1 * time.Second
+
 // Registry manages registered mDNS services with thread-safe access.
 //
+// Multiple backends can implement Registry: MemoryRegistry (the default,
+// process-local store), and fleet-aware backends such as an etcd-backed
+// registry or a gossip-backed registry that share service records between
+// beacon nodes on a LAN. A fleet-aware backend lets a Responder answer
+// queries for services registered by a peer host, analogous to how
+// service-mesh registries plug swappable discovery backends.
+type Registry interface {
+	// Register adds a service to the registry. Returns an error if a
+	// service with the same InstanceName already exists.
+	//
+	// Registry is a pure storage backend: Register does not probe the
+	// network, wait out conflicts, or announce anything per RFC 6762 §8 -
+	// it only ever rejects a name already present locally. responder.
+	// Responder.Register is what performs the §8.1 probe/§8.2 tiebreak/
+	// §8.3 announce sequence, calling this method only once a name has
+	// won (or was never contested). A caller driving a Registry directly
+	// instead of through responder.Responder is responsible for doing its
+	// own probing first.
+	Register(service *Service) error
+
+	// Get retrieves a service by instance name.
+	Get(instanceName string) (*Service, bool)
+
+	// Remove removes a service from the registry. Returns an error if the
+	// service is not found.
+	Remove(instanceName string) error
+
+	// List returns all registered service instance names.
+	List() []string
+
+	// ListByType returns every registered service whose ServiceType is
+	// exactly serviceType - the fast path for answering a PTR query for a
+	// popular service type, backed by a per-implementation secondary index
+	// rather than a scan over every registered service.
+	ListByType(serviceType string) []*Service
+
+	// Query returns every registered service matching q - service-type
+	// prefix, RFC 6763 §7.1 subtype, and/or TXT key/value predicates (see
+	// Query) - up to q.MaxResults if positive. Unlike ListByType, Query is
+	// a full scan: it supports filters no secondary index covers.
+	Query(q Query) []*Service
+
+	// ListServiceTypes returns all unique registered service types.
+	//
+	// RFC 6763 §9: "_services._dns-sd._udp.local" PTR query returns unique
+	// service types.
+	ListServiceTypes() []string
+
+	// Watch returns a channel of Event values reporting services added,
+	// updated, or removed after the call to Watch, so a Responder can
+	// reactively re-announce (RFC 6762 §8) instead of polling. The channel
+	// is closed when ctx is done.
+	Watch(ctx context.Context) <-chan Event
+
+	// RegisterWithLease is like Register, but the service is automatically
+	// removed after ttl unless renewed via Renew first. A non-positive ttl
+	// behaves like Register: the service never expires on its own.
+	RegisterWithLease(service *Service, ttl time.Duration) error
+
+	// Renew extends a leased service's expiry to ttl from now. Returns an
+	// error if instanceName is not registered. Calling Renew on a service
+	// registered without a lease (via Register, or RegisterWithLease with
+	// a non-positive ttl) gives it a lease going forward.
+	Renew(instanceName string, ttl time.Duration) error
+
+	// OnExpire registers fn to be called whenever this Registry
+	// automatically removes a service - a lease that was not renewed in
+	// time, or (for fleet-aware backends) a peer's service disappearing
+	// without this node having called Remove itself. fn is never called
+	// for a service this node removed explicitly via Remove.
+	//
+	// This lets a Responder send an RFC 6762 §10.1 goodbye (a record with
+	// TTL=0) for a service whose owner crashed or was partitioned away,
+	// without double-sending one for services it unregisters itself
+	// (which already go through its own goodbye path). Only one handler
+	// is kept; a later call replaces the previous one.
+	OnExpire(fn func(service *Service))
+}
+
+// EventType identifies the kind of change a Registry Event describes.
+type EventType int
+
+const (
+	// EventAdded reports that a service was newly registered.
+	EventAdded EventType = iota
+
+	// EventUpdated reports that a previously registered service changed
+	// (for example, its TXT records were refreshed).
+	EventUpdated
+
+	// EventRemoved reports that a service was removed, either explicitly
+	// or because a fleet-aware backend expired a peer's lease.
+	EventRemoved
+)
+
+// String returns a human-readable name for t, used in logs.
+func (t EventType) String() string {
+	switch t {
+	case EventAdded:
+		return "added"
+	case EventUpdated:
+		return "updated"
+	case EventRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event reports a single change to a Registry's contents.
+type Event struct {
+	Type    EventType
+	Service *Service
+}
+
+// eventBufferSize bounds the per-watcher channel so a slow consumer can't
+// block Register/Remove on other goroutines. This mirrors
+// querier.watchRecordBufferSize.
+const eventBufferSize = 32
+
+// watchHub fans out registry Events to any number of subscribers. Embed it
+// in a Registry implementation to get Watch/publish behavior for free.
+type watchHub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func (h *watchHub) watch(ctx context.Context) <-chan Event {
+	ch := make(chan Event, eventBufferSize)
+
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = make(map[chan Event]struct{})
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// publish delivers ev to every current subscriber, dropping it for any
+// subscriber whose buffer is full rather than blocking the caller.
+func (h *watchHub) publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Query filters Registry.Query results. A zero-value Query matches every
+// registered service, equivalent to List() resolved to *Service.
+type Query struct {
+	// ServiceTypePrefix, if non-empty, restricts results to services whose
+	// ServiceType starts with this prefix - e.g. "_http" matches both
+	// "_http._tcp.local" and "_http-alt._tcp.local".
+	ServiceTypePrefix string
+
+	// Subtype, if non-empty, is an RFC 6763 §7.1 subtype query name in the
+	// form "<subtype>._sub.<servicetype>" (e.g.
+	// "_printer._sub._http._tcp.local"); only services advertising that
+	// subtype (see Service.Subtypes) match.
+	Subtype string
+
+	// TXT, if non-empty, restricts results to services whose TXT records
+	// satisfy every key=value pair via records.TXTValue - e.g.
+	// {"paperformat": "A4"}. A key present in the query but absent (or
+	// boolean-only) on a service excludes that service.
+	TXT map[string]string
+
+	// MaxResults caps the number of services returned. Zero or negative
+	// means unlimited.
+	MaxResults int
+}
+
+// matches reports whether service satisfies every filter q sets.
+func (q Query) matches(service *Service) bool {
+	if q.ServiceTypePrefix != "" && !strings.HasPrefix(service.ServiceType, q.ServiceTypePrefix) {
+		return false
+	}
+	if q.Subtype != "" && !matchesSubtype(service, q.Subtype) {
+		return false
+	}
+	for key, want := range q.TXT {
+		got, ok := records.TXTValue(service.TXT, key)
+		if !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSubtype reports whether subtypeQueryName is one of service's RFC
+// 6763 §7.1 subtype query names, i.e. "<subtype>._sub.<servicetype>" for
+// some subtype service advertises.
+func matchesSubtype(service *Service, subtypeQueryName string) bool {
+	for _, subtype := range service.Subtypes {
+		if subtype+"._sub."+service.ServiceType == subtypeQueryName {
+			return true
+		}
+	}
+	return false
+}
+
+// queryScan applies q to every service in all, appending matches to the
+// result in all's iteration order until q.MaxResults is reached (if
+// positive). It is the shared, unindexed Query implementation every
+// Registry backend can delegate to - the Query filters (type prefix,
+// subtype, TXT predicates) aren't worth a secondary index at the scale
+// these backends run at; ListByType's exact-match lookup is the one
+// query shape common enough to index.
+func queryScan(all map[string]*Service, q Query) []*Service {
+	var out []*Service
+	for _, service := range all {
+		if !q.matches(service) {
+			continue
+		}
+		out = append(out, service)
+		if q.MaxResults > 0 && len(out) >= q.MaxResults {
+			break
+		}
+	}
+	return out
+}
+
+// MemoryRegistry is the default Registry backend: a process-local store
+// guarded by a sync.RWMutex.
+//
 // R006 Decision: Use sync.RWMutex for concurrent access
 //   - Multiple concurrent readers (Get operations)
 //   - Single writer at a time (Register/Remove operations)
-//
-// T013: Implement Registry with sync.RWMutex
-type Registry struct {
-	mu       sync.RWMutex
-	services map[string]*Service
+type MemoryRegistry struct {
+	mu             sync.RWMutex
+	services       map[string]*Service
+	expiresAt      map[string]time.Time           // instance name -> expiry, only present for leased services
+	typeIndex      map[string]map[string]struct{} // service type -> set of instance names, for ListByType
+	goodbyeHandler func(*Service)
+	hub            watchHub
+
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-// NewRegistry creates a new service registry.
+var _ Registry = (*MemoryRegistry)(nil)
+
+// NewRegistry creates a new in-memory service registry and starts a
+// background goroutine that scans for leased services (see
+// RegisterWithLease) past their expiry, removing them and invoking the
+// OnExpire handler, if any.
 //
 // Returns:
-//   - *Registry: An empty registry ready for service registration
-//
-// T013: Initialize Registry with map and RWMutex
-func NewRegistry() *Registry {
-	return &Registry{
-		services: make(map[string]*Service),
+//   - *MemoryRegistry: An empty registry ready for service registration
+func NewRegistry() *MemoryRegistry {
+	r := &MemoryRegistry{
+		services:  make(map[string]*Service),
+		expiresAt: make(map[string]time.Time),
+		typeIndex: make(map[string]map[string]struct{}),
+		done:      make(chan struct{}),
 	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.expireLoop(ctx)
+
+	return r
 }
 
-// Register adds a service to the registry.
+// Close stops the background lease-expiry goroutine.
+func (r *MemoryRegistry) Close() error {
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+func (r *MemoryRegistry) expireLoop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(leaseScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.expireLeases()
+		}
+	}
+}
+
+// indexAdd records instanceName under its service type in typeIndex.
+// Callers must hold r.mu for writing.
+func (r *MemoryRegistry) indexAdd(serviceType, instanceName string) {
+	names, ok := r.typeIndex[serviceType]
+	if !ok {
+		names = make(map[string]struct{})
+		r.typeIndex[serviceType] = names
+	}
+	names[instanceName] = struct{}{}
+}
+
+// indexRemove drops instanceName from its service type's entry in
+// typeIndex, clearing the entry entirely once empty. Callers must hold
+// r.mu for writing.
+func (r *MemoryRegistry) indexRemove(serviceType, instanceName string) {
+	names, ok := r.typeIndex[serviceType]
+	if !ok {
+		return
+	}
+	delete(names, instanceName)
+	if len(names) == 0 {
+		delete(r.typeIndex, serviceType)
+	}
+}
+
+// expireLeases removes every service whose lease has passed, invoking the
+// goodbye handler and publishing an EventRemoved for each.
+func (r *MemoryRegistry) expireLeases() {
+	now := time.Now()
+
+	r.mu.Lock()
+	var expired []*Service
+	for name, expiry := range r.expiresAt {
+		if now.After(expiry) {
+			service := r.services[name]
+			expired = append(expired, service)
+			delete(r.services, name)
+			delete(r.expiresAt, name)
+			r.indexRemove(service.ServiceType, name)
+		}
+	}
+	handler := r.goodbyeHandler
+	r.mu.Unlock()
+
+	for _, service := range expired {
+		if handler != nil {
+			handler(service)
+		}
+		r.hub.publish(Event{Type: EventRemoved, Service: service})
+	}
+}
+
+// Register adds a service to the registry with no lease - it remains
+// registered until explicitly removed via Remove.
 //
 // Parameters:
 //   - service: The service to register
@@ -39,9 +382,19 @@ func NewRegistry() *Registry {
 //   - error: Error if service with same InstanceName already exists
 //
 // Thread-safe: Uses write lock (RWMutex.Lock)
-//
-// T013: Implement Register with duplicate detection
-func (r *Registry) Register(service *Service) error {
+func (r *MemoryRegistry) Register(service *Service) error {
+	return r.register(service, 0)
+}
+
+// RegisterWithLease adds a service to the registry that is automatically
+// removed after ttl unless renewed via Renew, so callers don't have to
+// track their own expiry timers to avoid leaking stale entries if they
+// crash. A non-positive ttl behaves like Register.
+func (r *MemoryRegistry) RegisterWithLease(service *Service, ttl time.Duration) error {
+	return r.register(service, ttl)
+}
+
+func (r *MemoryRegistry) register(service *Service, ttl time.Duration) error {
 	if service == nil {
 		return fmt.Errorf("cannot register nil service")
 	}
@@ -51,14 +404,21 @@ func (r *Registry) Register(service *Service) error {
 	}
 
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	// Check for duplicate
 	if _, exists := r.services[service.InstanceName]; exists {
+		r.mu.Unlock()
 		return fmt.Errorf("service with InstanceName %q already registered", service.InstanceName)
 	}
 
 	r.services[service.InstanceName] = service
+	r.indexAdd(service.ServiceType, service.InstanceName)
+	if ttl > 0 {
+		service.TTL = ttl
+		r.expiresAt[service.InstanceName] = time.Now().Add(ttl)
+	}
+	r.mu.Unlock()
+
+	r.hub.publish(Event{Type: EventAdded, Service: service})
 	return nil
 }
 
@@ -72,9 +432,7 @@ func (r *Registry) Register(service *Service) error {
 //   - bool: true if service exists, false otherwise
 //
 // Thread-safe: Uses read lock (RWMutex.RLock) - allows concurrent reads
-//
-// T013: Implement Get with RLock for concurrent reads
-func (r *Registry) Get(instanceName string) (*Service, bool) {
+func (r *MemoryRegistry) Get(instanceName string) (*Service, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -91,17 +449,19 @@ func (r *Registry) Get(instanceName string) (*Service, bool) {
 //   - error: Error if service not found
 //
 // Thread-safe: Uses write lock (RWMutex.Lock)
-//
-// T013: Implement Remove with error on not found
-func (r *Registry) Remove(instanceName string) error {
+func (r *MemoryRegistry) Remove(instanceName string) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if _, exists := r.services[instanceName]; !exists {
+	service, exists := r.services[instanceName]
+	if !exists {
+		r.mu.Unlock()
 		return fmt.Errorf("service with InstanceName %q not found", instanceName)
 	}
-
 	delete(r.services, instanceName)
+	delete(r.expiresAt, instanceName)
+	r.indexRemove(service.ServiceType, instanceName)
+	r.mu.Unlock()
+
+	r.hub.publish(Event{Type: EventRemoved, Service: service})
 	return nil
 }
 
@@ -111,7 +471,7 @@ func (r *Registry) Remove(instanceName string) error {
 //   - []string: List of instance names
 //
 // Thread-safe: Uses read lock (RWMutex.RLock)
-func (r *Registry) List() []string {
+func (r *MemoryRegistry) List() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -122,6 +482,32 @@ func (r *Registry) List() []string {
 	return names
 }
 
+// ListByType returns every registered service whose ServiceType is exactly
+// serviceType, read from typeIndex so the cost is O(matches) rather than a
+// scan over every registered service - the common case for answering a PTR
+// query, where most registries hold many more services of other types than
+// of the one being asked about.
+func (r *MemoryRegistry) ListByType(serviceType string) []*Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := r.typeIndex[serviceType]
+	services := make([]*Service, 0, len(names))
+	for name := range names {
+		services = append(services, r.services[name])
+	}
+	return services
+}
+
+// Query returns every registered service matching q, up to q.MaxResults if
+// positive. See ListByType for the indexed exact-service-type fast path;
+// Query's prefix/subtype/TXT filters are a full scan.
+func (r *MemoryRegistry) Query(q Query) []*Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return queryScan(r.services, q)
+}
+
 // ListServiceTypes returns all unique registered service types.
 //
 // This method supports RFC 6763 §9 service enumeration by listing unique service types
@@ -134,8 +520,7 @@ func (r *Registry) List() []string {
 //
 // RFC 6763 §9: "_services._dns-sd._udp.local" PTR query returns unique service types
 // FR-027: System MUST respond with list of all registered service types
-// T107: Implement service type enumeration support
-func (r *Registry) ListServiceTypes() []string {
+func (r *MemoryRegistry) ListServiceTypes() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -146,24 +531,62 @@ func (r *Registry) ListServiceTypes() []string {
 		typeMap[service.ServiceType] = true
 	}
 
-	// Convert map keys to slice
+	// Convert map keys to slice. Sorted so callers (e.g.
+	// responder.respondServiceTypeEnum, tracking per-record multicast
+	// recency for RFC 6762 §5.4's 1/4 TTL rule) see the same record order
+	// on every call instead of Go's randomized map iteration order.
 	types := make([]string, 0, len(typeMap))
 	for serviceType := range typeMap {
 		types = append(types, serviceType)
 	}
+	sort.Strings(types)
 
 	return types
 }
 
+// Watch returns a channel of Events reporting services added or removed
+// after the call to Watch. The channel is closed when ctx is done.
+func (r *MemoryRegistry) Watch(ctx context.Context) <-chan Event {
+	return r.hub.watch(ctx)
+}
+
+// Renew extends instanceName's lease to ttl from now, giving a previously
+// unleased service (one registered via Register) a lease going forward.
+// Returns an error if instanceName is not registered.
+func (r *MemoryRegistry) Renew(instanceName string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	service, exists := r.services[instanceName]
+	if !exists {
+		return fmt.Errorf("service with InstanceName %q not found", instanceName)
+	}
+
+	if ttl > 0 {
+		service.TTL = ttl
+		r.expiresAt[instanceName] = time.Now().Add(ttl)
+	} else {
+		service.TTL = 0
+		delete(r.expiresAt, instanceName)
+	}
+	return nil
+}
+
+// OnExpire registers fn to be called whenever expireLeases removes a
+// service whose lease was not renewed in time. Only one handler is kept;
+// a later call replaces the previous one.
+func (r *MemoryRegistry) OnExpire(fn func(service *Service)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.goodbyeHandler = fn
+}
+
 // Service represents a registered mDNS service.
-//
-// This is the minimal implementation for T013 (Registry tests).
-// Full implementation will be in responder/service.go (T031).
-//
-// T031: This will be moved to service.go with full validation
 type Service struct {
 	InstanceName string
 	ServiceType  string
 	Port         int
-	TXT          map[string]string
+	TTL          time.Duration // zero means no automatic expiry; see RegisterWithLease
+	TXT          []records.TXTRecord
+	Subtypes     []string
 }
@@ -0,0 +1,497 @@
+package responder
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// etcdDefaultLeaseTTLSeconds is the lease duration requested for each
+// service this registry registers. A peer that stops renewing (process
+// killed, host unreachable) has its services expire from etcd - and
+// therefore from every other node's EtcdRegistry - within this window.
+const etcdDefaultLeaseTTLSeconds = 30
+
+// etcdDefaultPollInterval is how often EtcdRegistry re-lists its keyspace
+// to detect services a peer registered or removed, used to synthesize
+// Watch events. etcd's own v3 Watch API is a long-lived gRPC/HTTP2 stream;
+// polling the JSON gateway's Range endpoint is far simpler to implement
+// correctly over plain HTTP/1.1 and is cheap at the scale (tens of
+// services) this registry is built for.
+const etcdDefaultPollInterval = 2 * time.Second
+
+// EtcdRegistry is a Registry backend that stores services in etcd under a
+// keyspace, lease-bound so a node's services disappear automatically if it
+// stops renewing. This lets a fleet of beacon responders on different
+// hosts present a unified set of mDNS services, each node's Responder
+// answering queries for every other node's services even while the owning
+// host is momentarily silent (RFC 6762 §8 re-announce guidance extended
+// across hosts).
+//
+// EtcdRegistry talks to etcd exclusively via its v3 JSON gRPC-gateway HTTP
+// API (https://etcd.io/docs/v3.5/dev-guide/api_grpc_gateway/), the same
+// approach metrics/prom takes for Prometheus - plain net/http and
+// encoding/json rather than pulling in the full etcd client module.
+type EtcdRegistry struct {
+	endpoint  string
+	keyPrefix string
+	client    *http.Client
+
+	mu             sync.RWMutex
+	leaseID        int64
+	serviceLeases  map[string]int64 // instance name -> lease ID, for services registered with their own ttl via RegisterWithLease/Renew
+	cache          map[string]*Service
+	hub            watchHub
+	goodbyeHandler func(*Service)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ Registry = (*EtcdRegistry)(nil)
+
+// NewEtcdRegistry creates a Registry backed by the etcd cluster reachable
+// at endpoint (e.g. "http://127.0.0.1:2379"). keyPrefix namespaces this
+// registry's keys (e.g. "/beacon/services/") so multiple applications can
+// share a cluster.
+//
+// NewEtcdRegistry grants a lease immediately and starts a background
+// goroutine that renews it at roughly 1/3 of etcdDefaultLeaseTTLSeconds and
+// polls the keyspace at etcdDefaultPollInterval to synthesize Watch
+// events. Call Close when the registry is no longer needed to stop that
+// goroutine.
+func NewEtcdRegistry(ctx context.Context, endpoint, keyPrefix string) (*EtcdRegistry, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("etcd registry: endpoint cannot be empty")
+	}
+	if keyPrefix == "" {
+		keyPrefix = "/beacon/services/"
+	}
+
+	r := &EtcdRegistry{
+		endpoint:      endpoint,
+		keyPrefix:     keyPrefix,
+		client:        &http.Client{Timeout: 5 * time.Second},
+		serviceLeases: make(map[string]int64),
+		cache:         make(map[string]*Service),
+		done:          make(chan struct{}),
+	}
+
+	leaseID, err := r.grantLease(ctx, etcdDefaultLeaseTTLSeconds)
+	if err != nil {
+		return nil, err
+	}
+	r.leaseID = leaseID
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.run(runCtx)
+
+	return r, nil
+}
+
+// Close stops the background lease-renewal and polling goroutine. It does
+// not revoke the lease, so already-registered services remain visible to
+// peers until the lease naturally expires.
+func (r *EtcdRegistry) Close() error {
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+func (r *EtcdRegistry) run(ctx context.Context) {
+	defer close(r.done)
+
+	keepAlive := time.NewTicker(etcdDefaultLeaseTTLSeconds * time.Second / 3)
+	defer keepAlive.Stop()
+	poll := time.NewTicker(etcdDefaultPollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			_ = r.keepAliveLease(ctx)
+		case <-poll.C:
+			r.syncFromEtcd(ctx)
+		}
+	}
+}
+
+// Register marshals service as JSON and PUTs it to etcd under this
+// registry's shared lease, so it expires automatically if this process
+// stops renewing.
+func (r *EtcdRegistry) Register(service *Service) error {
+	return r.register(service, 0)
+}
+
+// RegisterWithLease is like Register, but service gets its own etcd
+// lease of ttl seconds instead of sharing the registry's default lease,
+// so it can be renewed (or left to expire) independently via Renew. A
+// non-positive ttl behaves like Register.
+func (r *EtcdRegistry) RegisterWithLease(service *Service, ttl time.Duration) error {
+	return r.register(service, ttl)
+}
+
+func (r *EtcdRegistry) register(service *Service, ttl time.Duration) error {
+	if service == nil {
+		return fmt.Errorf("cannot register nil service")
+	}
+	if service.InstanceName == "" {
+		return fmt.Errorf("service InstanceName cannot be empty")
+	}
+
+	r.mu.RLock()
+	_, exists := r.cache[service.InstanceName]
+	leaseID := r.leaseID
+	r.mu.RUnlock()
+	if exists {
+		return fmt.Errorf("service with InstanceName %q already registered", service.InstanceName)
+	}
+
+	if ttl > 0 {
+		ownLease, err := r.grantLease(context.Background(), int64(ttl.Seconds()))
+		if err != nil {
+			return err
+		}
+		service.TTL = ttl
+		leaseID = ownLease
+	}
+
+	value, err := json.Marshal(service)
+	if err != nil {
+		return fmt.Errorf("etcd registry: encode service %q: %w", service.InstanceName, err)
+	}
+
+	if err := r.put(context.Background(), r.keyPrefix+service.InstanceName, value, leaseID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cache[service.InstanceName] = service
+	if ttl > 0 {
+		r.serviceLeases[service.InstanceName] = leaseID
+	}
+	r.mu.Unlock()
+
+	r.hub.publish(Event{Type: EventAdded, Service: service})
+	return nil
+}
+
+// Renew extends instanceName's own lease (granted by RegisterWithLease) to
+// ttl seconds from now by granting a fresh lease and re-attaching the
+// service's existing value to it - etcd leases can't have their TTL
+// changed in place, only kept alive at their original TTL. Returns an
+// error if instanceName was not registered via RegisterWithLease.
+func (r *EtcdRegistry) Renew(instanceName string, ttl time.Duration) error {
+	r.mu.RLock()
+	service, exists := r.cache[instanceName]
+	_, hasOwnLease := r.serviceLeases[instanceName]
+	r.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("service with InstanceName %q not found", instanceName)
+	}
+	if !hasOwnLease {
+		return fmt.Errorf("service with InstanceName %q has no lease to renew; register it with RegisterWithLease first", instanceName)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("etcd registry: renew ttl must be positive")
+	}
+
+	ownLease, err := r.grantLease(context.Background(), int64(ttl.Seconds()))
+	if err != nil {
+		return err
+	}
+
+	service.TTL = ttl
+	value, err := json.Marshal(service)
+	if err != nil {
+		return fmt.Errorf("etcd registry: encode service %q: %w", instanceName, err)
+	}
+	if err := r.put(context.Background(), r.keyPrefix+instanceName, value, ownLease); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.serviceLeases[instanceName] = ownLease
+	r.mu.Unlock()
+	return nil
+}
+
+// OnExpire registers fn to be called whenever syncFromEtcd detects that a
+// service disappeared from the keyspace without this node having called
+// Remove itself - a peer's lease expiring, or the owning node calling
+// Remove on its own EtcdRegistry instance. Only one handler is kept; a
+// later call replaces the previous one.
+func (r *EtcdRegistry) OnExpire(fn func(service *Service)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.goodbyeHandler = fn
+}
+
+// Get retrieves a service by instance name from the local cache, which is
+// refreshed every etcdDefaultPollInterval from etcd.
+func (r *EtcdRegistry) Get(instanceName string) (*Service, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	service, exists := r.cache[instanceName]
+	return service, exists
+}
+
+// Remove deletes a service's key from etcd.
+func (r *EtcdRegistry) Remove(instanceName string) error {
+	r.mu.RLock()
+	service, exists := r.cache[instanceName]
+	r.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("service with InstanceName %q not found", instanceName)
+	}
+
+	if err := r.deleteRange(context.Background(), r.keyPrefix+instanceName); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	delete(r.cache, instanceName)
+	delete(r.serviceLeases, instanceName)
+	r.mu.Unlock()
+
+	r.hub.publish(Event{Type: EventRemoved, Service: service})
+	return nil
+}
+
+// List returns all service instance names currently cached from etcd.
+func (r *EtcdRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.cache))
+	for name := range r.cache {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ListByType returns every cached service whose ServiceType is exactly
+// serviceType. Unlike MemoryRegistry, this is a scan over the local cache
+// rather than an indexed lookup - the cache is already rebuilt wholesale on
+// every syncFromEtcd poll, so a secondary index would need rebuilding just
+// as often and wouldn't save anything at the scale this registry targets.
+func (r *EtcdRegistry) ListByType(serviceType string) []*Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var services []*Service
+	for _, service := range r.cache {
+		if service.ServiceType == serviceType {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// Query returns every cached service matching q, up to q.MaxResults if
+// positive.
+func (r *EtcdRegistry) Query(q Query) []*Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return queryScan(r.cache, q)
+}
+
+// ListServiceTypes returns all unique service types currently cached from
+// etcd, sorted for deterministic ordering (see MemoryRegistry.ListServiceTypes).
+func (r *EtcdRegistry) ListServiceTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	typeMap := make(map[string]bool)
+	for _, service := range r.cache {
+		typeMap[service.ServiceType] = true
+	}
+	types := make([]string, 0, len(typeMap))
+	for serviceType := range typeMap {
+		types = append(types, serviceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Watch returns a channel of Events reporting services added, updated, or
+// removed by any node sharing this etcd keyspace, detected by comparing
+// successive polls of the keyspace (see etcdDefaultPollInterval).
+func (r *EtcdRegistry) Watch(ctx context.Context) <-chan Event {
+	return r.hub.watch(ctx)
+}
+
+// syncFromEtcd lists the keyspace and diffs it against the local cache,
+// publishing Added/Updated/Removed events for anything that changed.
+func (r *EtcdRegistry) syncFromEtcd(ctx context.Context) {
+	remote, err := r.rangeKeys(ctx, r.keyPrefix)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	var events []Event
+	var expired []*Service
+	for name, service := range remote {
+		prev, existed := r.cache[name]
+		if !existed {
+			events = append(events, Event{Type: EventAdded, Service: service})
+		} else if prev.Port != service.Port || len(prev.TXT) != len(service.TXT) {
+			events = append(events, Event{Type: EventUpdated, Service: service})
+		}
+	}
+	for name, service := range r.cache {
+		if _, stillPresent := remote[name]; !stillPresent {
+			events = append(events, Event{Type: EventRemoved, Service: service})
+			expired = append(expired, service)
+			delete(r.serviceLeases, name)
+		}
+	}
+	r.cache = remote
+	handler := r.goodbyeHandler
+	r.mu.Unlock()
+
+	if handler != nil {
+		for _, service := range expired {
+			handler(service)
+		}
+	}
+	for _, ev := range events {
+		r.hub.publish(ev)
+	}
+}
+
+// --- etcd v3 JSON gRPC-gateway HTTP client ---
+//
+// See https://etcd.io/docs/v3.5/dev-guide/api_grpc_gateway/. The gateway
+// accepts/returns JSON with byte fields (keys, values) base64-encoded,
+// matching protobuf's JSON mapping for the `bytes` type.
+
+func (r *EtcdRegistry) grantLease(ctx context.Context, ttlSeconds int64) (int64, error) {
+	var resp struct {
+		ID string `json:"ID"`
+	}
+	if err := r.call(ctx, "/v3/lease/grant", map[string]interface{}{"TTL": ttlSeconds}, &resp); err != nil {
+		return 0, err
+	}
+	var id int64
+	if _, err := fmt.Sscan(resp.ID, &id); err != nil {
+		return 0, &errors.NetworkError{Operation: "etcd lease grant", Err: err, Details: "unparseable lease ID"}
+	}
+	return id, nil
+}
+
+func (r *EtcdRegistry) keepAliveLease(ctx context.Context) error {
+	return r.call(ctx, "/v3/lease/keepalive", map[string]interface{}{"ID": fmt.Sprintf("%d", r.leaseID)}, nil)
+}
+
+func (r *EtcdRegistry) put(ctx context.Context, key string, value []byte, leaseID int64) error {
+	body := map[string]interface{}{
+		"key":   base64.StdEncoding.EncodeToString([]byte(key)),
+		"value": base64.StdEncoding.EncodeToString(value),
+	}
+	if leaseID != 0 {
+		body["lease"] = fmt.Sprintf("%d", leaseID)
+	}
+	return r.call(ctx, "/v3/kv/put", body, nil)
+}
+
+func (r *EtcdRegistry) deleteRange(ctx context.Context, key string) error {
+	body := map[string]interface{}{
+		"key": base64.StdEncoding.EncodeToString([]byte(key)),
+	}
+	return r.call(ctx, "/v3/kv/deleterange", body, nil)
+}
+
+// rangeKeys lists every key under prefix and decodes each value as a
+// Service, keyed by InstanceName.
+func (r *EtcdRegistry) rangeKeys(ctx context.Context, prefix string) (map[string]*Service, error) {
+	rangeEnd := prefixRangeEnd(prefix)
+	body := map[string]interface{}{
+		"key":       base64.StdEncoding.EncodeToString([]byte(prefix)),
+		"range_end": base64.StdEncoding.EncodeToString([]byte(rangeEnd)),
+	}
+
+	var resp struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := r.call(ctx, "/v3/kv/range", body, &resp); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*Service, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		raw, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			continue
+		}
+		var service Service
+		if err := json.Unmarshal(raw, &service); err != nil {
+			continue
+		}
+		out[service.InstanceName] = &service
+	}
+	return out, nil
+}
+
+// prefixRangeEnd computes etcd's conventional "one past prefix" range_end,
+// the smallest key greater than every key starting with prefix, so a Range
+// request acts as a prefix scan.
+func prefixRangeEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// prefix was all 0xff bytes (or empty): there is no upper bound.
+	return "\x00"
+}
+
+func (r *EtcdRegistry) call(ctx context.Context, path string, reqBody interface{}, respBody interface{}) error {
+	encoded, err := json.Marshal(reqBody)
+	if err != nil {
+		return &errors.NetworkError{Operation: "etcd " + path, Err: err, Details: "encode request"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint+path, bytes.NewReader(encoded))
+	if err != nil {
+		return &errors.NetworkError{Operation: "etcd " + path, Err: err, Details: "build request"}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return &errors.NetworkError{Operation: "etcd " + path, Err: err, Details: r.endpoint}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &errors.NetworkError{
+			Operation: "etcd " + path,
+			Err:       fmt.Errorf("unexpected status %d", resp.StatusCode),
+			Details:   r.endpoint,
+		}
+	}
+
+	if respBody == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+		return &errors.NetworkError{Operation: "etcd " + path, Err: err, Details: "decode response"}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package responder
+
+import "testing"
+
+// TestPrefixRangeEnd verifies the "one past prefix" computation used to
+// turn an etcd Range request into a prefix scan.
+func TestPrefixRangeEnd(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "/beacon/services/", want: "/beacon/services0"},
+		{prefix: "a", want: "b"},
+		{prefix: "", want: "\x00"},
+	}
+
+	for _, tt := range tests {
+		if got := prefixRangeEnd(tt.prefix); got != tt.want {
+			t.Errorf("prefixRangeEnd(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package responder
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// gossipInterval is how often a GossipRegistry broadcasts a full snapshot
+// of its locally registered services. Broadcasting the whole snapshot
+// rather than incremental deltas is simpler to reason about and
+// self-healing after a dropped packet (anti-entropy), which matters more
+// than bandwidth at the scale (tens of services, LAN multicast) this
+// registry is built for.
+const gossipInterval = 5 * time.Second
+
+// gossipPeerTTL is how long a peer's services remain visible after its
+// most recent snapshot. A peer that stops broadcasting (process killed,
+// network partition) has its services expire from every other node's
+// GossipRegistry within this window, without requiring an explicit
+// goodbye message.
+const gossipPeerTTL = 3 * gossipInterval
+
+// gossipRecvBufferSize bounds a single UDP datagram's JSON payload.
+// Beacon's own services list is small (tens of entries), so 64KiB leaves
+// ample headroom while staying well under typical MTU fragmentation
+// limits for LAN multicast.
+const gossipRecvBufferSize = 65536
+
+// gossipWireMessage is the JSON payload broadcast to the gossip group.
+// Services is the sender's complete current set of locally registered
+// services (an anti-entropy snapshot, not a delta); Tombstones lists
+// instance names the sender removed since its last snapshot, so peers
+// don't have to wait out gossipPeerTTL to notice a clean removal.
+type gossipWireMessage struct {
+	NodeID     string    `json:"node_id"`
+	Services   []Service `json:"services"`
+	Tombstones []string  `json:"tombstones,omitempty"`
+}
+
+// gossipEntry tracks one service known to a GossipRegistry, whether
+// registered locally or learned from a peer's broadcast.
+type gossipEntry struct {
+	service   *Service
+	origin    string // nodeID that registered this service; local node's own ID for locally-registered services
+	lastSeen  time.Time
+	expiresAt time.Time // zero means no lease; only meaningful for origin == this node's nodeID
+}
+
+// GossipRegistry is a Registry backend that shares service records between
+// beacon nodes on a LAN via periodic UDP multicast broadcasts, in the
+// spirit of a memberlist-style anti-entropy gossip protocol. It lets a
+// fleet of responders on different hosts present a unified set of mDNS
+// services, each node answering queries for every peer's services too.
+//
+// GossipRegistry speaks a small JSON-over-UDP protocol of its own rather
+// than embedding a full gossip/memberlist client library, the same
+// no-new-dependency approach metrics/prom takes for Prometheus.
+type GossipRegistry struct {
+	nodeID string
+	conn   *net.UDPConn
+	group  *net.UDPAddr
+
+	mu             sync.RWMutex
+	entries        map[string]*gossipEntry // instance name -> entry, spans local + all known peers
+	hub            watchHub
+	goodbyeHandler func(*Service)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ Registry = (*GossipRegistry)(nil)
+
+// NewGossipRegistry creates a Registry that gossips over groupAddr (e.g.
+// "239.255.77.77:5454", a multicast address distinct from mDNS's own
+// 224.0.0.251:5353 so the two protocols don't collide on the wire). If
+// iface is non-nil, multicast traffic is restricted to that interface;
+// otherwise the system default multicast interface is used.
+func NewGossipRegistry(groupAddr string, iface *net.Interface) (*GossipRegistry, error) {
+	group, err := net.ResolveUDPAddr("udp", groupAddr)
+	if err != nil {
+		return nil, &errors.NetworkError{Operation: "resolve gossip group address", Err: err, Details: groupAddr}
+	}
+
+	conn, err := net.ListenMulticastUDP("udp", iface, group)
+	if err != nil {
+		return nil, &errors.NetworkError{Operation: "join gossip multicast group", Err: err, Details: groupAddr}
+	}
+
+	nodeID, err := randomNodeID()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("gossip registry: generate node ID: %w", err)
+	}
+
+	r := &GossipRegistry{
+		nodeID:  nodeID,
+		conn:    conn,
+		group:   group,
+		entries: make(map[string]*gossipEntry),
+		done:    make(chan struct{}),
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.recvLoop(runCtx)
+	go r.broadcastLoop(runCtx)
+
+	return r, nil
+}
+
+// Close stops gossiping and leaves the multicast group.
+func (r *GossipRegistry) Close() error {
+	r.cancel()
+	<-r.done
+	return r.conn.Close()
+}
+
+func randomNodeID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Register adds a local service and broadcasts it to the gossip group
+// immediately (in addition to the periodic anti-entropy broadcast), so
+// peers learn about it with low latency.
+func (r *GossipRegistry) Register(service *Service) error {
+	return r.register(service, 0)
+}
+
+// RegisterWithLease is like Register, but service is automatically
+// removed (and a tombstone broadcast, same as Remove) after ttl unless
+// renewed via Renew. A non-positive ttl behaves like Register.
+func (r *GossipRegistry) RegisterWithLease(service *Service, ttl time.Duration) error {
+	return r.register(service, ttl)
+}
+
+func (r *GossipRegistry) register(service *Service, ttl time.Duration) error {
+	if service == nil {
+		return fmt.Errorf("cannot register nil service")
+	}
+	if service.InstanceName == "" {
+		return fmt.Errorf("service InstanceName cannot be empty")
+	}
+
+	r.mu.Lock()
+	if _, exists := r.entries[service.InstanceName]; exists {
+		r.mu.Unlock()
+		return fmt.Errorf("service with InstanceName %q already registered", service.InstanceName)
+	}
+	entry := &gossipEntry{service: service, origin: r.nodeID, lastSeen: time.Now()}
+	if ttl > 0 {
+		service.TTL = ttl
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	r.entries[service.InstanceName] = entry
+	r.mu.Unlock()
+
+	r.broadcastLocal(nil)
+	r.hub.publish(Event{Type: EventAdded, Service: service})
+	return nil
+}
+
+// Renew extends a locally-registered leased service's expiry to ttl from
+// now. Returns an error if instanceName is not registered locally (a
+// peer's service cannot be renewed here, matching Remove's ownership
+// rule).
+func (r *GossipRegistry) Renew(instanceName string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.entries[instanceName]
+	if !exists {
+		return fmt.Errorf("service with InstanceName %q not found", instanceName)
+	}
+	if entry.origin != r.nodeID {
+		return fmt.Errorf("service with InstanceName %q is owned by a peer node, cannot renew it here", instanceName)
+	}
+
+	if ttl > 0 {
+		entry.service.TTL = ttl
+		entry.expiresAt = time.Now().Add(ttl)
+	} else {
+		entry.service.TTL = 0
+		entry.expiresAt = time.Time{}
+	}
+	return nil
+}
+
+// OnExpire registers fn to be called whenever a service disappears
+// without this node having called Remove itself: a local lease that was
+// not renewed in time, or a peer's service expiring (gossipPeerTTL) or
+// being tombstoned by its owner. Only one handler is kept; a later call
+// replaces the previous one.
+func (r *GossipRegistry) OnExpire(fn func(service *Service)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.goodbyeHandler = fn
+}
+
+// Get retrieves a service by instance name, whether registered locally or
+// learned from a peer.
+func (r *GossipRegistry) Get(instanceName string) (*Service, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, exists := r.entries[instanceName]
+	if !exists {
+		return nil, false
+	}
+	return entry.service, true
+}
+
+// Remove deletes a locally registered service and broadcasts a tombstone
+// so peers remove it immediately rather than waiting out gossipPeerTTL.
+// Removing a peer's service (one this node did not register) is rejected,
+// matching the etcd backend's lease-ownership model.
+func (r *GossipRegistry) Remove(instanceName string) error {
+	r.mu.Lock()
+	entry, exists := r.entries[instanceName]
+	if !exists {
+		r.mu.Unlock()
+		return fmt.Errorf("service with InstanceName %q not found", instanceName)
+	}
+	if entry.origin != r.nodeID {
+		r.mu.Unlock()
+		return fmt.Errorf("service with InstanceName %q is owned by a peer node, cannot remove it here", instanceName)
+	}
+	delete(r.entries, instanceName)
+	r.mu.Unlock()
+
+	r.broadcastLocal([]string{instanceName})
+	r.hub.publish(Event{Type: EventRemoved, Service: entry.service})
+	return nil
+}
+
+// List returns every known service instance name, local and peer-owned.
+func (r *GossipRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ListByType returns every known service, local and peer-owned, whose
+// ServiceType is exactly serviceType. Unlike MemoryRegistry, this is a scan
+// over entries rather than an indexed lookup - entries already churns on
+// every gossipInterval broadcast and mergeRemote call, so a secondary index
+// would need maintaining just as often without saving anything at the
+// scale this registry targets.
+func (r *GossipRegistry) ListByType(serviceType string) []*Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var services []*Service
+	for _, entry := range r.entries {
+		if entry.service.ServiceType == serviceType {
+			services = append(services, entry.service)
+		}
+	}
+	return services
+}
+
+// Query returns every known service matching q, up to q.MaxResults if
+// positive.
+func (r *GossipRegistry) Query(q Query) []*Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services := make(map[string]*Service, len(r.entries))
+	for name, entry := range r.entries {
+		services[name] = entry.service
+	}
+	return queryScan(services, q)
+}
+
+// ListServiceTypes returns all unique service types across local and peer
+// services, sorted for deterministic ordering (see
+// MemoryRegistry.ListServiceTypes).
+func (r *GossipRegistry) ListServiceTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	typeMap := make(map[string]bool)
+	for _, entry := range r.entries {
+		typeMap[entry.service.ServiceType] = true
+	}
+	types := make([]string, 0, len(typeMap))
+	for serviceType := range typeMap {
+		types = append(types, serviceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Watch returns a channel of Events reporting services added or removed,
+// whether by a local Register/Remove call or learned from a peer's
+// broadcast or expiry.
+func (r *GossipRegistry) Watch(ctx context.Context) <-chan Event {
+	return r.hub.watch(ctx)
+}
+
+// broadcastLocal sends every locally-registered service as a full
+// snapshot, tagging any just-removed names (if any) as tombstones.
+func (r *GossipRegistry) broadcastLocal(tombstones []string) {
+	r.mu.RLock()
+	services := make([]Service, 0, len(r.entries))
+	for _, entry := range r.entries {
+		if entry.origin == r.nodeID {
+			services = append(services, *entry.service)
+		}
+	}
+	r.mu.RUnlock()
+
+	msg := gossipWireMessage{NodeID: r.nodeID, Services: services, Tombstones: tombstones}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	_, _ = r.conn.WriteToUDP(payload, r.group)
+}
+
+func (r *GossipRegistry) broadcastLoop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(gossipInterval)
+	defer ticker.Stop()
+	expiry := time.NewTicker(gossipInterval)
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.broadcastLocal(nil)
+		case <-expiry.C:
+			r.expireLeases()
+		}
+	}
+}
+
+// expireLeases removes peer-owned entries not refreshed within
+// gossipPeerTTL (covering a peer that leaves without a goodbye) and
+// locally-owned leased entries past their expiry (see RegisterWithLease),
+// broadcasting a tombstone for the latter so peers don't have to wait out
+// gossipPeerTTL themselves. Both cases invoke the OnExpire handler, since
+// neither went through an explicit local Remove call.
+func (r *GossipRegistry) expireLeases() {
+	now := time.Now()
+
+	r.mu.Lock()
+	var expired []*Service
+	var expiredLocal []string
+	for name, entry := range r.entries {
+		switch {
+		case entry.origin != r.nodeID:
+			if now.Sub(entry.lastSeen) > gossipPeerTTL {
+				expired = append(expired, entry.service)
+				delete(r.entries, name)
+			}
+		case !entry.expiresAt.IsZero() && now.After(entry.expiresAt):
+			expired = append(expired, entry.service)
+			expiredLocal = append(expiredLocal, name)
+			delete(r.entries, name)
+		}
+	}
+	handler := r.goodbyeHandler
+	r.mu.Unlock()
+
+	if len(expiredLocal) > 0 {
+		r.broadcastLocal(expiredLocal)
+	}
+	for _, service := range expired {
+		if handler != nil {
+			handler(service)
+		}
+		r.hub.publish(Event{Type: EventRemoved, Service: service})
+	}
+}
+
+func (r *GossipRegistry) recvLoop(ctx context.Context) {
+	buf := make([]byte, gossipRecvBufferSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		_ = r.conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+		n, _, err := r.conn.ReadFromUDP(buf)
+		if err != nil {
+			continue
+		}
+
+		var msg gossipWireMessage
+		if err := json.Unmarshal(buf[:n], &msg); err != nil {
+			continue
+		}
+		if msg.NodeID == r.nodeID {
+			continue // ignore our own broadcast
+		}
+		r.mergeRemote(msg)
+	}
+}
+
+// mergeRemote applies a peer's snapshot: refreshing/adding its services
+// and removing any it tombstoned, publishing Events for whatever changed.
+func (r *GossipRegistry) mergeRemote(msg gossipWireMessage) {
+	now := time.Now()
+
+	r.mu.Lock()
+	var events []Event
+	var tombstoned []*Service
+	for i := range msg.Services {
+		service := msg.Services[i]
+		prev, existed := r.entries[service.InstanceName]
+		if !existed {
+			events = append(events, Event{Type: EventAdded, Service: &service})
+		} else if prev.service.Port != service.Port || len(prev.service.TXT) != len(service.TXT) {
+			events = append(events, Event{Type: EventUpdated, Service: &service})
+		}
+		r.entries[service.InstanceName] = &gossipEntry{service: &service, origin: msg.NodeID, lastSeen: now}
+	}
+	for _, name := range msg.Tombstones {
+		if entry, exists := r.entries[name]; exists && entry.origin == msg.NodeID {
+			delete(r.entries, name)
+			events = append(events, Event{Type: EventRemoved, Service: entry.service})
+			tombstoned = append(tombstoned, entry.service)
+		}
+	}
+	handler := r.goodbyeHandler
+	r.mu.Unlock()
+
+	if handler != nil {
+		for _, service := range tombstoned {
+			handler(service)
+		}
+	}
+	for _, ev := range events {
+		r.hub.publish(ev)
+	}
+}
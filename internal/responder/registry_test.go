@@ -1,8 +1,12 @@
 package responder
 
 import (
+	"context"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/records"
 )
 
 // TestRegistry_Register_RED tests service registration.
@@ -339,6 +343,280 @@ func TestRegistry_ListServiceTypes_Empty(t *testing.T) {
 
 // Note: Service type is now implemented in registry.go (T013 GREEN phase)
 
+// TestRegistry_Watch_EmitsAddedAndRemoved verifies that Register and
+// Remove publish Events to a Watch subscriber, and that the channel is
+// closed once its context is done.
+func TestRegistry_Watch_EmitsAddedAndRemoved(t *testing.T) {
+	registry := NewRegistry()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := registry.Watch(ctx)
+
+	service := &Service{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}
+
+	if err := registry.Register(service); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventAdded {
+			t.Errorf("Watch() event Type = %v, want EventAdded", ev.Type)
+		}
+		if ev.Service.InstanceName != service.InstanceName {
+			t.Errorf("Watch() event Service.InstanceName = %q, want %q", ev.Service.InstanceName, service.InstanceName)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch() did not deliver an Added event after Register()")
+	}
+
+	if err := registry.Remove(service.InstanceName); err != nil {
+		t.Fatalf("Remove() error = %v, want nil", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Type != EventRemoved {
+			t.Errorf("Watch() event Type = %v, want EventRemoved", ev.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch() did not deliver a Removed event after Remove()")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Watch() channel produced an unexpected event after cancel()")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch() channel was not closed after context cancellation")
+	}
+}
+
+// TestRegistry_RegisterWithLease_ExpiresAndNotifiesGoodbye verifies that a
+// leased service is automatically removed once its ttl passes, that it
+// fires the OnExpire handler exactly once, and that it is reported via
+// Watch like any other removal.
+func TestRegistry_RegisterWithLease_ExpiresAndNotifiesGoodbye(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := registry.Watch(ctx)
+
+	expired := make(chan *Service, 1)
+	registry.OnExpire(func(svc *Service) { expired <- svc })
+
+	service := &Service{InstanceName: "Ephemeral Printer", ServiceType: "_http._tcp.local", Port: 8080}
+	if err := registry.RegisterWithLease(service, 50*time.Millisecond); err != nil {
+		t.Fatalf("RegisterWithLease() error = %v, want nil", err)
+	}
+
+	select {
+	case <-expired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnExpire handler was not called after lease expiry")
+	}
+
+	if _, exists := registry.Get(service.InstanceName); exists {
+		t.Error("Get() exists=true after lease expiry, want false")
+	}
+
+	// Drain the Added event before asserting on Removed.
+	for ev := range events {
+		if ev.Type == EventRemoved {
+			break
+		}
+	}
+}
+
+// TestRegistry_Renew_ExtendsLease verifies that Renew prevents a leased
+// service from expiring.
+func TestRegistry_Renew_ExtendsLease(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	service := &Service{InstanceName: "Renewed Printer", ServiceType: "_http._tcp.local", Port: 8080}
+	if err := registry.RegisterWithLease(service, 200*time.Millisecond); err != nil {
+		t.Fatalf("RegisterWithLease() error = %v, want nil", err)
+	}
+
+	// Keep renewing faster than the original ttl so it never expires.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 4; i++ {
+			time.Sleep(75 * time.Millisecond)
+			if err := registry.Renew(service.InstanceName, 200*time.Millisecond); err != nil {
+				t.Errorf("Renew() error = %v, want nil", err)
+				return
+			}
+		}
+	}()
+	<-done
+
+	if _, exists := registry.Get(service.InstanceName); !exists {
+		t.Error("Get() exists=false after repeated Renew(), want true")
+	}
+}
+
+// TestRegistry_Renew_NotFound verifies Renew rejects an unknown instance name.
+func TestRegistry_Renew_NotFound(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	if err := registry.Renew("non-existent", time.Second); err == nil {
+		t.Error("Renew(non-existent) error = nil, want error")
+	}
+}
+
+// TestRegistry_ListByType verifies ListByType returns only services of the
+// requested exact type, not instances of other types or subtypes.
+func TestRegistry_ListByType(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	services := []*Service{
+		{InstanceName: "Web1", ServiceType: "_http._tcp.local", Port: 8080},
+		{InstanceName: "Web2", ServiceType: "_http._tcp.local", Port: 8081},
+		{InstanceName: "SSH1", ServiceType: "_ssh._tcp.local", Port: 22},
+	}
+	for _, svc := range services {
+		if err := registry.Register(svc); err != nil {
+			t.Fatalf("Register(%q) error = %v", svc.InstanceName, err)
+		}
+	}
+
+	got := registry.ListByType("_http._tcp.local")
+	if len(got) != 2 {
+		t.Fatalf("ListByType(_http._tcp.local) count = %d, want 2", len(got))
+	}
+	for _, svc := range got {
+		if svc.ServiceType != "_http._tcp.local" {
+			t.Errorf("ListByType returned service of type %q, want _http._tcp.local", svc.ServiceType)
+		}
+	}
+
+	if got := registry.ListByType("_ipp._tcp.local"); len(got) != 0 {
+		t.Errorf("ListByType(_ipp._tcp.local) count = %d, want 0", len(got))
+	}
+}
+
+// TestRegistry_ListByType_ReflectsRemove verifies the secondary index
+// ListByType reads from stays in sync after Remove.
+func TestRegistry_ListByType_ReflectsRemove(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	svc := &Service{InstanceName: "Web1", ServiceType: "_http._tcp.local", Port: 8080}
+	if err := registry.Register(svc); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Remove(svc.InstanceName); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if got := registry.ListByType("_http._tcp.local"); len(got) != 0 {
+		t.Errorf("ListByType() after Remove count = %d, want 0", len(got))
+	}
+}
+
+// TestRegistry_Query_ServiceTypePrefix verifies Query's ServiceTypePrefix
+// filter matches by prefix, not exact equality.
+func TestRegistry_Query_ServiceTypePrefix(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	services := []*Service{
+		{InstanceName: "Web1", ServiceType: "_http._tcp.local", Port: 8080},
+		{InstanceName: "WebAlt1", ServiceType: "_http-alt._tcp.local", Port: 8081},
+		{InstanceName: "SSH1", ServiceType: "_ssh._tcp.local", Port: 22},
+	}
+	for _, svc := range services {
+		if err := registry.Register(svc); err != nil {
+			t.Fatalf("Register(%q) error = %v", svc.InstanceName, err)
+		}
+	}
+
+	got := registry.Query(Query{ServiceTypePrefix: "_http"})
+	if len(got) != 2 {
+		t.Fatalf("Query(ServiceTypePrefix: _http) count = %d, want 2", len(got))
+	}
+}
+
+// TestRegistry_Query_Subtype verifies Query's Subtype filter matches the
+// RFC 6763 §7.1 subtype query name against Service.Subtypes.
+func TestRegistry_Query_Subtype(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	color := &Service{InstanceName: "ColorPrinter", ServiceType: "_printer._tcp.local", Port: 515, Subtypes: []string{"_color"}}
+	mono := &Service{InstanceName: "MonoPrinter", ServiceType: "_printer._tcp.local", Port: 515}
+	for _, svc := range []*Service{color, mono} {
+		if err := registry.Register(svc); err != nil {
+			t.Fatalf("Register(%q) error = %v", svc.InstanceName, err)
+		}
+	}
+
+	got := registry.Query(Query{Subtype: "_color._sub._printer._tcp.local"})
+	if len(got) != 1 || got[0].InstanceName != "ColorPrinter" {
+		t.Fatalf("Query(Subtype: _color...) = %v, want only ColorPrinter", got)
+	}
+}
+
+// TestRegistry_Query_TXTPredicate verifies Query's TXT filter requires an
+// exact key=value match, rejecting a service missing the key.
+func TestRegistry_Query_TXTPredicate(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	a4 := &Service{
+		InstanceName: "PrinterA4", ServiceType: "_printer._tcp.local", Port: 515,
+		TXT: []records.TXTRecord{{Key: "paperformat", Value: []byte("A4"), Present: true}},
+	}
+	letter := &Service{
+		InstanceName: "PrinterLetter", ServiceType: "_printer._tcp.local", Port: 515,
+		TXT: []records.TXTRecord{{Key: "paperformat", Value: []byte("Letter"), Present: true}},
+	}
+	noTXT := &Service{InstanceName: "PrinterPlain", ServiceType: "_printer._tcp.local", Port: 515}
+	for _, svc := range []*Service{a4, letter, noTXT} {
+		if err := registry.Register(svc); err != nil {
+			t.Fatalf("Register(%q) error = %v", svc.InstanceName, err)
+		}
+	}
+
+	got := registry.Query(Query{TXT: map[string]string{"paperformat": "A4"}})
+	if len(got) != 1 || got[0].InstanceName != "PrinterA4" {
+		t.Fatalf("Query(TXT: paperformat=A4) = %v, want only PrinterA4", got)
+	}
+}
+
+// TestRegistry_Query_MaxResults verifies Query stops collecting once
+// MaxResults is reached.
+func TestRegistry_Query_MaxResults(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Close()
+
+	for i := 0; i < 5; i++ {
+		svc := &Service{InstanceName: formatInstanceName("Web", i), ServiceType: "_http._tcp.local", Port: 8080 + i}
+		if err := registry.Register(svc); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	got := registry.Query(Query{ServiceTypePrefix: "_http", MaxResults: 2})
+	if len(got) != 2 {
+		t.Fatalf("Query(MaxResults: 2) count = %d, want 2", len(got))
+	}
+}
+
 // formatInstanceName creates a test instance name.
 func formatInstanceName(prefix string, id int) string {
 	return prefix + "-" + string(rune('0'+id))
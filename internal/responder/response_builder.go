@@ -3,12 +3,31 @@ package responder
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"time"
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
 	"github.com/joshuafuller/beacon/internal/records"
+	"github.com/joshuafuller/beacon/metrics"
 )
 
+// rrsigValidityWindow is how long a freshly minted RRSIG remains valid.
+//
+// mDNS responders re-sign on every announce/response rather than
+// maintaining a long-lived zone signature, so a short window limits the
+// blast radius of a compromised-but-still-cached signature without
+// requiring any resigning infrastructure.
+const rrsigValidityWindow = 1 * time.Hour
+
+// defaultMaxUDPSize is the UDP payload size ResponseBuilder advertises in a
+// response's EDNS(0) OPT record (RFC 6891 §6.1.2) when a query negotiates
+// one, and the ceiling it packs answers/additionals to instead of
+// maxPacketSize. 1440 mirrors querier's own default, fitting a single
+// untagged Ethernet frame without fragmenting.
+const defaultMaxUDPSize uint16 = 1440
+
 // ResponseBuilder constructs mDNS responses per RFC 6762 §6.
 //
 // RFC 6762 §6: "When a Multicast DNS responder constructs and sends a Multicast DNS
@@ -25,10 +44,56 @@ import (
 // T075: Implement ResponseBuilder struct
 type ResponseBuilder struct {
 	maxPacketSize int // RFC 6762 §17: 9000 bytes maximum
+
+	// signer, when set via WithSigner, causes BuildResponse to also emit
+	// RRSIG records over each answer RRset and advertise a DNSKEY.
+	signer *SignerConfig
+
+	// maxUDPSize is the UDP payload size BuildResponse advertises (and, if
+	// smaller than a query's own EDNS(0)-advertised size, packs the
+	// response's Answer/Additional sections to) instead of maxPacketSize.
+	// Set via WithMaxUDPSize; defaults to defaultMaxUDPSize.
+	maxUDPSize uint16
+
+	// metrics receives ApplyKnownAnswerSuppression's per-record suppression
+	// counter and TTL-ratio histogram observations. Set via WithMetrics;
+	// defaults to metrics.NoOp{}.
+	metrics metrics.Metrics
+
+	// suppressUniqueRecords controls whether RFC 6762 §10.2 unique records
+	// (SRV, TXT, A/AAAA, HTTPS - CacheFlush true) are subject to
+	// known-answer suppression at all, not just shared records (PTR). RFC
+	// 6762 §7.1: "Generally, this applies only to Shared records, not
+	// Unique records, since if a Multicast DNS querier already has at
+	// least one Unique record in its cache then it should not be expecting
+	// further different answers to this question" - true by default to
+	// preserve this package's original suppress-everything behavior; set
+	// via WithUniqueRecordSuppression.
+	suppressUniqueRecords bool
+
+	// onSuppressed, when set via WithOnSuppressed, is called for every
+	// record applyKnownAnswerSuppressionIndexed decides to suppress, in
+	// addition to the beacon_responder_known_answer_suppressed_total
+	// counter above - so a caller that wants to observe suppression
+	// synchronously (e.g. responder.EventHook.OnKnownAnswerSuppressed)
+	// doesn't have to scrape metrics to do it. Nil (the default) skips the
+	// call entirely.
+	onSuppressed func(record *message.ResourceRecord)
 }
 
 // ServiceWithIP extends Service with IP address for testing.
 //
+// ServiceWithIP and BuildResponse only ever describe the one service a
+// single query is being answered for - the "many services, one responder"
+// problem is solved one layer up, not here. responder.Responder holds a
+// Registry (MemoryRegistry by default) indexed by both instance name and
+// service type; Responder.answerQuestions looks up the matching service(s)
+// from the registry per incoming question and calls BuildResponse once per
+// match, and respondServiceTypeEnum walks registry.ListServiceTypes() to
+// answer RFC 6763 §9's "_services._dns-sd._udp.local" enumeration query
+// with one PTR per distinct service type already in the registry. There is
+// deliberately no separate zone-like type duplicating that indexing here.
+//
 // T075: Service type for ResponseBuilder tests
 type ServiceWithIP struct {
 	InstanceName string
@@ -36,8 +101,24 @@ type ServiceWithIP struct {
 	Domain       string
 	Port         int
 	IPv4Address  []byte
-	TXTRecords   map[string]string
-	Hostname     string
+
+	// IPv6Addresses, when non-empty, causes BuildResponse to emit one AAAA
+	// record per address (RFC 3596 §2.1, TTL 4500 per RFC 6762 §10 same as
+	// the A record) in the Additional section alongside the usual SRV/TXT/A
+	// bundle - this is what makes a service discoverable on a v6-only or
+	// dual-stack LAN when the responder is running transport.UDPv6Transport
+	// or transport.DualStack (see Responder's WithNetworkMode(IPv6Only) and
+	// WithNetworkMode(DualStack)).
+	IPv6Addresses [][]byte
+	TXTRecords    []records.TXTRecord
+	Hostname      string
+
+	// AdvertiseHTTPS enables emitting an HTTPS record (RFC 9460) alongside
+	// the usual SRV/TXT/A set, advertising alpn=h2,http/1.1 and the
+	// service's port/address as ipv4hint. Set this for HTTP-like services
+	// (e.g. _http._tcp, _https._tcp) so HTTP/2-aware clients can skip the
+	// SRV round trip.
+	AdvertiseHTTPS bool
 }
 
 // NewResponseBuilder creates a new ResponseBuilder with RFC 6762 defaults.
@@ -47,10 +128,68 @@ type ServiceWithIP struct {
 // T075: ResponseBuilder constructor
 func NewResponseBuilder() *ResponseBuilder {
 	return &ResponseBuilder{
-		maxPacketSize: 9000, // RFC 6762 §17
+		maxPacketSize:         9000, // RFC 6762 §17
+		maxUDPSize:            defaultMaxUDPSize,
+		metrics:               metrics.NoOp{},
+		suppressUniqueRecords: true,
 	}
 }
 
+// WithMaxUDPSize sets the UDP payload size BuildResponse advertises in a
+// response's EDNS(0) OPT record and, when it negotiates smaller than the
+// query's own advertised size, packs the response to instead of the fixed
+// RFC 6762 §17 9000-byte ceiling.
+func (rb *ResponseBuilder) WithMaxUDPSize(n uint16) *ResponseBuilder {
+	rb.maxUDPSize = n
+	return rb
+}
+
+// WithSigner enables DNSSEC signing of answer RRsets per RFC 4034/4035.
+//
+// When signer is non-nil, BuildResponse appends an RRSIG (type 46) covering
+// each answer RRset and a DNSKEY (type 48) in the additional section.
+func (rb *ResponseBuilder) WithSigner(signer *SignerConfig) *ResponseBuilder {
+	rb.signer = signer
+	return rb
+}
+
+// WithMetrics installs m as the ResponseBuilder's metrics.Metrics sink, so
+// ApplyKnownAnswerSuppression reports beacon_responder_known_answer_ttl_ratio
+// and beacon_responder_known_answer_suppressed_total for each record it
+// decides on instead of discarding that data.
+func (rb *ResponseBuilder) WithMetrics(m metrics.Metrics) *ResponseBuilder {
+	rb.metrics = m
+	return rb
+}
+
+// WithUniqueRecordSuppression controls whether unique records (CacheFlush
+// true - SRV, TXT, A/AAAA, HTTPS) are subject to known-answer suppression at
+// all, per RFC 6762 §7.1's "generally... only Shared records" guidance.
+// Pass false to always include unique records regardless of the query's
+// known-answer list, exempting only the shared PTR record from
+// suppression.
+func (rb *ResponseBuilder) WithUniqueRecordSuppression(enabled bool) *ResponseBuilder {
+	rb.suppressUniqueRecords = enabled
+	return rb
+}
+
+// metricsActive reports whether rb.metrics is a real sink rather than the
+// metrics.NoOp{} default, so ApplyKnownAnswerSuppression can skip building
+// label maps and computing TTL ratios on the hot path when nothing is
+// listening.
+// WithOnSuppressed sets a callback invoked for every record
+// applyKnownAnswerSuppressionIndexed suppresses. Pass nil (the default) to
+// disable it.
+func (rb *ResponseBuilder) WithOnSuppressed(fn func(record *message.ResourceRecord)) *ResponseBuilder {
+	rb.onSuppressed = fn
+	return rb
+}
+
+func (rb *ResponseBuilder) metricsActive() bool {
+	_, isNoOp := rb.metrics.(metrics.NoOp)
+	return !isNoOp
+}
+
 // BuildResponse constructs an mDNS response for a query per RFC 6762 §6.
 //
 // RFC 6762 §6: For a PTR query, the response MUST contain:
@@ -97,12 +236,13 @@ func (rb *ResponseBuilder) BuildResponse(service *ServiceWithIP, query *message.
 
 	// Convert Service to records.ServiceInfo for record building
 	serviceInfo := &records.ServiceInfo{
-		InstanceName: service.InstanceName,
-		ServiceType:  service.ServiceType,
-		Hostname:     rb.getHostname(service),
-		Port:         service.Port,
-		IPv4Address:  service.IPv4Address,
-		TXTRecords:   service.TXTRecords,
+		InstanceName:  service.InstanceName,
+		ServiceType:   service.ServiceType,
+		Hostname:      rb.getHostname(service),
+		Port:          service.Port,
+		IPv4Address:   service.IPv4Address,
+		IPv6Addresses: service.IPv6Addresses,
+		TXTRecords:    service.TXTRecords,
 	}
 
 	// Build all records for this service
@@ -121,6 +261,10 @@ func (rb *ResponseBuilder) BuildResponse(service *ServiceWithIP, query *message.
 			CacheFlush: (answer.CLASS & 0x8000) != 0,
 		})
 	}
+	// Built once per query and reused below so suppression is decided in
+	// O(records+knownAnswers) instead of O(records*knownAnswers) - see
+	// records.KnownAnswerIndex.
+	knownAnswerIndex := records.NewKnownAnswerIndex(knownAnswers)
 
 	// For PTR query, answer is PTR record, additional is SRV/TXT/A
 	// For now, assume first question is PTR query (will enhance later)
@@ -133,110 +277,191 @@ func (rb *ResponseBuilder) BuildResponse(service *ServiceWithIP, query *message.
 			for _, rr := range allRecords {
 				if rr.Type == protocol.RecordTypePTR {
 					// T095: Apply known-answer suppression per RFC 6762 §7.1
-					if rb.ApplyKnownAnswerSuppression(rr, knownAnswers) {
+					if rb.applyKnownAnswerSuppressionIndexed(rr, knownAnswerIndex) {
 						response.Answers = append(response.Answers, rb.recordToAnswer(rr))
 					}
-					// T096: TODO - log suppressed record
 					break
 				}
 			}
 
 			// Add SRV, TXT, A to additional section (with known-answer suppression)
 			for _, rr := range allRecords {
-				if rr.Type == protocol.RecordTypeSRV || rr.Type == protocol.RecordTypeTXT || rr.Type == protocol.RecordTypeA {
+				if rr.Type == protocol.RecordTypeSRV || rr.Type == protocol.RecordTypeTXT || rr.Type == protocol.RecordTypeA || rr.Type == protocol.RecordTypeAAAA {
 					// T095: Apply known-answer suppression per RFC 6762 §7.1
-					if rb.ApplyKnownAnswerSuppression(rr, knownAnswers) {
+					if rb.applyKnownAnswerSuppressionIndexed(rr, knownAnswerIndex) {
 						response.Additionals = append(response.Additionals, rb.recordToAnswer(rr))
 					}
-					// T096: TODO - log suppressed record
+				}
+			}
+
+			// RFC 9460: HTTP-like services also advertise an HTTPS record
+			// so HTTP/2-aware clients can skip the SRV round trip.
+			if service.AdvertiseHTTPS {
+				if httpsRR, err := rb.buildHTTPSRecord(service); err == nil {
+					if rb.applyKnownAnswerSuppressionIndexed(httpsRR, knownAnswerIndex) {
+						response.Additionals = append(response.Additionals, rb.recordToAnswer(httpsRR))
+					}
 				}
 			}
 		}
 	}
 
+	// DNSSEC: sign the answer RRset and advertise our DNSKEY.
+	if rb.signer != nil && len(response.Answers) > 0 {
+		if rrsigAnswer, err := rb.signAnswers(response.Answers); err == nil {
+			response.Answers = append(response.Answers, rrsigAnswer)
+			response.Additionals = append(response.Additionals, rb.recordToAnswer(rb.signer.DNSKEYRecord(protocol.TTLHostname)))
+		}
+		// A signing failure (e.g. misconfigured key) degrades to an
+		// unsigned response rather than withholding the answer entirely.
+	}
+
+	// RFC 6891 §6.1.2: echo an OPT record back whenever the query carried
+	// one, advertising our own maxUDPSize and echoing the query's DO bit
+	// and options (NSID, DNS Cookie, etc.) verbatim - this module doesn't
+	// generate real NSID/cookie values, only acknowledges dig-style
+	// capability probes per RFC 6891/7873.
+	if query.OPT != nil {
+		response.OPT = &message.OPTRecord{
+			UDPPayloadSize: rb.maxUDPSize,
+			DNSSECOK:       query.OPT.DNSSECOK,
+			Options:        query.OPT.Options,
+		}
+	}
+
 	// Update counts
 	response.Header.ANCount = uint16(len(response.Answers))
 	response.Header.ARCount = uint16(len(response.Additionals))
+	if response.OPT != nil {
+		response.Header.ARCount++
+	}
 
-	// Check packet size limit (RFC 6762 §17: 9000 bytes)
+	// Check packet size limit: the smaller of our own maxUDPSize and the
+	// query's EDNS(0)-negotiated size when it carried an OPT record,
+	// otherwise the fixed RFC 6762 §17 9000-byte ceiling for classic
+	// (pre-EDNS0) queries.
+	maxSize := rb.negotiatedPacketSize(query)
 	estimatedSize := rb.EstimatePacketSize(response)
-	if estimatedSize > rb.maxPacketSize {
+	if estimatedSize > maxSize {
 		// R005: Gracefully truncate additional records
-		response.Additionals = rb.truncateAdditionals(response, estimatedSize)
+		response.Additionals = rb.truncateAdditionals(response, estimatedSize, maxSize)
 		response.Header.ARCount = uint16(len(response.Additionals))
+		if response.OPT != nil {
+			response.Header.ARCount++
+		}
 	}
 
 	return response, nil
 }
 
-// EstimatePacketSize estimates the wire format size of a DNS message.
-//
-// RFC 6762 §17: Maximum packet size is 9000 bytes.
-//
-// Estimation formula (R005 decision):
-//   - Header: 12 bytes
-//   - Each record: ~60 bytes average (name + type + class + TTL + rdlength + rdata)
-//
-// T077: Implement EstimatePacketSize()
-func (rb *ResponseBuilder) EstimatePacketSize(msg *message.DNSMessage) int {
-	// Header is always 12 bytes
-	size := 12
-
-	// Estimate answer records
-	for _, answer := range msg.Answers {
-		size += rb.estimateRecordSize(&answer)
+// negotiatedPacketSize returns the byte ceiling BuildResponse packs answers
+// and additionals to: when query carried an EDNS(0) OPT record, the smaller
+// of our own maxUDPSize and the query's advertised UDPPayloadSize (so
+// neither side exceeds what it's prepared to receive); otherwise
+// maxPacketSize, preserving the original fixed-9000-byte behavior for
+// classic (pre-EDNS0) queries.
+func (rb *ResponseBuilder) negotiatedPacketSize(query *message.DNSMessage) int {
+	if query.OPT == nil {
+		return rb.maxPacketSize
 	}
 
-	// Estimate additional records
-	for _, additional := range msg.Additionals {
-		size += rb.estimateRecordSize(&additional)
+	negotiated := int(query.OPT.UDPPayloadSize)
+	if int(rb.maxUDPSize) < negotiated {
+		negotiated = int(rb.maxUDPSize)
 	}
-
-	return size
+	if negotiated > rb.maxPacketSize {
+		negotiated = rb.maxPacketSize
+	}
+	return negotiated
 }
 
-// estimateRecordSize estimates the size of a single resource record.
+// EstimatePacketSize returns msg's exact wire format size: the same
+// RFC 1035 §4.1.4 name-compressing serializer pass BuildResponse's caller
+// will eventually hand to the transport (see message.EncodeMessage), not a
+// per-record heuristic. A message that fails to encode - which shouldn't
+// happen for anything BuildResponse constructs - reports math.MaxInt32
+// rather than 0, so truncateAdditionals treats it as over budget instead of
+// silently passing it through.
 //
-// R005 decision: Conservative estimate
-//   - Name: ~50 bytes (with compression)
-//   - Type: 2 bytes
-//   - Class: 2 bytes
-//   - TTL: 4 bytes
-//   - RDLength: 2 bytes
-//   - RData: actual data length
-//
-// T077: Helper for packet size estimation
-func (rb *ResponseBuilder) estimateRecordSize(answer *message.Answer) int {
-	// Name (compressed): ~50 bytes average
-	// Type (2) + Class (2) + TTL (4) + RDLength (2) = 10 bytes
-	// RDATA: len(answer.RDATA)
-	return 50 + 10 + len(answer.RDATA)
+// RFC 6762 §17: Maximum packet size is 9000 bytes.
+func (rb *ResponseBuilder) EstimatePacketSize(msg *message.DNSMessage) int {
+	encoded, err := message.EncodeMessage(msg, true)
+	if err != nil {
+		return math.MaxInt32
+	}
+	return len(encoded)
 }
 
-// truncateAdditionals removes additional records until packet size is acceptable.
+// truncateAdditionals splits msg's additionals to fit maxSize, keeping the
+// answer section (and header/OPT overhead) intact and greedily keeping
+// additionals in their existing priority order until the next one wouldn't
+// fit, rather than dropping by whatever happened to still be over budget -
+// so a tight negotiated size (e.g. a query's small EDNS(0) UDPPayloadSize)
+// degrades to "send what fits" rather than risking an over-budget packet.
 //
-// R005 Decision: Graceful truncation - keep answer section intact (critical),
-// remove additional records (nice-to-have) until under 9000 bytes.
+// Each candidate is measured by actually encoding the message-so-far via
+// EstimatePacketSize, so two additionals sharing a compressible suffix (e.g.
+// "_http._tcp.local" on both a TXT and an HTTPS record) correctly cost one
+// full name plus one cheap pointer, the same as what BuildResponse's caller
+// will actually send - not the flat per-record estimate this used before.
+// currentSize (the caller's own EstimatePacketSize(msg) result) is unused
+// here; it's kept as a parameter so callers don't need to change, but the
+// real budget check happens per-candidate below.
 //
-// T077: Implement truncation
-func (rb *ResponseBuilder) truncateAdditionals(msg *message.DNSMessage, currentSize int) []message.Answer {
-	// Remove additional records one by one until under limit
-	additionals := make([]message.Answer, 0, len(msg.Additionals))
-	size := currentSize
+// R005 Decision: Graceful truncation - keep answer section intact (critical),
+// remove additional records (nice-to-have) until under maxSize bytes.
+func (rb *ResponseBuilder) truncateAdditionals(msg *message.DNSMessage, currentSize int, maxSize int) []message.Answer {
+	trial := &message.DNSMessage{
+		Header:      msg.Header,
+		Questions:   msg.Questions,
+		Answers:     msg.Answers,
+		Authorities: msg.Authorities,
+		OPT:         msg.OPT,
+	}
 
+	additionals := make([]message.Answer, 0, len(msg.Additionals))
 	for _, additional := range msg.Additionals {
-		recordSize := rb.estimateRecordSize(&additional)
-		if size-recordSize >= rb.maxPacketSize {
-			// Skip this record
-			size -= recordSize
+		candidate := append(additionals, additional)
+		trial.Additionals = candidate
+		if rb.EstimatePacketSize(trial) > maxSize {
+			// Doesn't fit in what's left of the budget - skip it, but keep
+			// checking later (smaller) additionals rather than stopping.
 			continue
 		}
-		additionals = append(additionals, additional)
+		additionals = candidate
 	}
 
 	return additionals
 }
 
+// signAnswers signs the RRset formed by response's answer section and
+// returns the resulting RRSIG as an Answer. All records in the answer
+// section are expected to share the same owner name, type, and class per
+// RFC 6762 §6 (a single question is answered per response).
+func (rb *ResponseBuilder) signAnswers(answers []message.Answer) (message.Answer, error) {
+	rrset := make([]*message.ResourceRecord, 0, len(answers))
+	for _, a := range answers {
+		rrset = append(rrset, &message.ResourceRecord{
+			Name:       a.NAME,
+			Type:       protocol.RecordType(a.TYPE),
+			Class:      protocol.DNSClass(a.CLASS),
+			TTL:        a.TTL,
+			Data:       a.RDATA,
+			CacheFlush: (a.CLASS & 0x8000) != 0,
+		})
+	}
+
+	now := time.Now().Unix()
+	inception := uint32(now)                                          //nolint:gosec // G115: Unix time fits uint32 until year 2106
+	expiration := uint32(now) + uint32(rrsigValidityWindow.Seconds()) //nolint:gosec // G115: see above
+
+	rrsigRecord, err := rb.signer.SignRRset(rrset, inception, expiration)
+	if err != nil {
+		return message.Answer{}, err
+	}
+	return rb.recordToAnswer(rrsigRecord), nil
+}
+
 // recordToAnswer converts a ResourceRecord to an Answer.
 //
 // T076: Helper for response building
@@ -251,6 +476,41 @@ func (rb *ResponseBuilder) recordToAnswer(rr *message.ResourceRecord) message.An
 	}
 }
 
+// buildHTTPSRecord constructs an HTTPS record (RFC 9460) advertising
+// alpn=h2,http/1.1 for the service's instance name.
+//
+// Per RFC 9460 §2.2, the SvcDomainName target MUST NOT be compressed, and
+// SvcParamKeys MUST appear in strictly ascending order.
+func (rb *ResponseBuilder) buildHTTPSRecord(service *ServiceWithIP) (*message.ResourceRecord, error) {
+	params := []message.SvcParam{
+		message.BuildALPNParam([]string{"h2", "http/1.1"}),
+		message.BuildPortParam(uint16(service.Port)), //nolint:gosec // G115: service.Port validated at registration time
+	}
+	if len(service.IPv4Address) == 4 {
+		params = append(params, message.BuildIPv4HintParam([]net.IP{net.IP(service.IPv4Address)}))
+	}
+	message.SortParams(params)
+
+	data := &message.SVCBData{
+		Priority: 1,
+		Target:   rb.getHostname(service),
+		Params:   params,
+	}
+	rdata, err := message.EncodeSVCB(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message.ResourceRecord{
+		Name:       service.InstanceName + "." + service.ServiceType,
+		Type:       protocol.RecordTypeHTTPS,
+		Class:      protocol.ClassIN,
+		TTL:        protocol.TTLService,
+		Data:       rdata,
+		CacheFlush: true,
+	}, nil
+}
+
 // getHostname returns the hostname for the service.
 //
 // If service.Hostname is set, use it. Otherwise, construct from instance name.
@@ -280,79 +540,50 @@ func (rb *ResponseBuilder) getHostname(service *ServiceWithIP) string {
 //   - false: Suppress the record (already in known-answer list with TTL ≥50%)
 //
 // T092: Implement known-answer suppression logic
+// T092: Delegates to records.KnownAnswerIndex (shared with the
+// KnownAnswerCache multi-packet aggregation path) and inverts its sense to
+// "should include".
+//
+// ApplyKnownAnswerSuppression builds a throwaway records.KnownAnswerIndex
+// over knownAnswers on every call; BuildResponse instead builds one
+// KnownAnswerIndex per query and calls applyKnownAnswerSuppressionIndexed
+// for each candidate record, so a query with N records answered against M
+// known-answers costs O(N+M) instead of O(N*M).
+// Together with KnownAnswerCache's §7.2 multi-packet reassembly (merged
+// into the knownAnswers this is called with - see responder.Responder's
+// onPacket, which calls KnownAnswerCache.Merge/Take before ever reaching
+// BuildResponse), this already implements the full §7.1/§7.2 known-answer
+// suppression flow: TestApplyKnownAnswerSuppression_TTLThreshold covers the
+// "TTL in known-answer is ≥ half of true TTL" rule, and
+// TestKnownAnswerCache_MultiPacketAggregation covers the TC=1 hold window.
 func (rb *ResponseBuilder) ApplyKnownAnswerSuppression(ourRecord *message.ResourceRecord, knownAnswers []*message.ResourceRecord) bool {
-	// No known-answers → no suppression
-	if len(knownAnswers) == 0 {
-		return true // Include in response
-	}
-
-	// Check if ourRecord matches any known-answer
-	for _, knownAnswer := range knownAnswers {
-		// RFC 6762 §7.1: Records must match on Name, Type, Class, and RDATA
-		if !recordsMatch(ourRecord, knownAnswer) {
-			continue // Not a match, check next known-answer
-		}
-
-		// Records match - check TTL threshold
-		// RFC 6762 §7.1: Suppress if known-answer TTL ≥ 50% of true TTL
-		ttlThreshold := ourRecord.TTL / 2 // 50% of true TTL
-
-		if knownAnswer.TTL >= ttlThreshold {
-			// Known-answer TTL ≥50% → suppress (querier's cache is fresh enough)
-			return false // Do NOT include in response
-		}
-
-		// Known-answer TTL <50% → respond to refresh before expiration
-		return true // Include in response
-	}
-
-	// No matching known-answer found → include in response
-	return true
+	return rb.applyKnownAnswerSuppressionIndexed(ourRecord, records.NewKnownAnswerIndex(knownAnswers))
 }
 
-// recordsMatch checks if two resource records match per RFC 6762 §7.1 criteria.
-//
-// RFC 6762 §7.1: Records match if Name, Type, Class, and RDATA are identical.
-//
-// Parameters:
-//   - a, b: Resource records to compare
-//
-// Returns:
-//   - true: Records match (same Name, Type, Class, RDATA)
-//   - false: Records differ
-//
-// T092: Helper for known-answer matching
-func recordsMatch(a, b *message.ResourceRecord) bool {
-	// Name comparison (case-insensitive per DNS spec)
-	// TODO: Implement proper DNS name comparison (case-insensitive)
-	// For now, use simple string comparison
-	if a.Name != b.Name {
-		return false
-	}
-
-	// Type must match
-	if a.Type != b.Type {
-		return false
+// applyKnownAnswerSuppressionIndexed is ApplyKnownAnswerSuppression against
+// a pre-built records.KnownAnswerIndex, additionally honoring
+// suppressUniqueRecords (RFC 6762 §7.1's shared-vs-unique guidance) and
+// reporting beacon_responder_known_answer_ttl_ratio and
+// beacon_responder_known_answer_suppressed_total when rb.metrics is set.
+func (rb *ResponseBuilder) applyKnownAnswerSuppressionIndexed(ourRecord *message.ResourceRecord, index *records.KnownAnswerIndex) bool {
+	if !rb.suppressUniqueRecords && ourRecord.CacheFlush {
+		return true
 	}
 
-	// Class must match (ignore cache-flush bit for comparison)
-	// RFC 6762 §10.2: Cache-flush bit is NOT part of record identity
-	classA := a.Class & 0x7FFF // Mask out cache-flush bit
-	classB := b.Class & 0x7FFF
-	if classA != classB {
-		return false
-	}
+	known, found := index.Match(ourRecord)
+	suppress := found && known.TTL >= ourRecord.TTL/2
 
-	// RDATA must match byte-for-byte
-	if len(a.Data) != len(b.Data) {
-		return false
-	}
-	for i := range a.Data {
-		if a.Data[i] != b.Data[i] {
-			return false
+	if rb.metricsActive() {
+		if found && ourRecord.TTL > 0 {
+			labels := map[string]string{"record_type": ourRecord.Type.String()}
+			rb.metrics.ObserveHistogram("beacon_responder_known_answer_ttl_ratio", float64(known.TTL)/float64(ourRecord.TTL), labels)
+		}
+		if suppress {
+			rb.metrics.IncCounter("beacon_responder_known_answer_suppressed_total", map[string]string{"record_type": ourRecord.Type.String()})
 		}
 	}
-
-	// All criteria match
-	return true
+	if suppress && rb.onSuppressed != nil {
+		rb.onSuppressed(ourRecord)
+	}
+	return !suppress
 }
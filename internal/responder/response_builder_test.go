@@ -5,6 +5,7 @@ import (
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
 )
 
 // TestResponseBuilder_BuildResponse_PTRQuery tests building a response to a PTR query.
@@ -29,7 +30,7 @@ func TestResponseBuilder_BuildResponse_PTRQuery(t *testing.T) {
 		Domain:       "local",
 		Port:         8080,
 		IPv4Address:  []byte{192, 168, 1, 100},
-		TXTRecords:   map[string]string{"txtvers": "1", "path": "/"},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1", "path": "/"}),
 	}
 
 	// Incoming PTR query for "_http._tcp.local"
@@ -136,6 +137,73 @@ func TestResponseBuilder_BuildResponse_PTRQuery(t *testing.T) {
 	}
 }
 
+// TestResponseBuilder_BuildResponse_SuppressesKnownPTRAnswer tests RFC 6762
+// §7.1 known-answer suppression end to end through BuildResponse: a querier
+// that already holds our PTR answer at TTL ≥ half the true value should get
+// a response with nothing left to say.
+func TestResponseBuilder_BuildResponse_SuppressesKnownPTRAnswer(t *testing.T) {
+	rb := NewResponseBuilder()
+
+	service := &ServiceWithIP{
+		InstanceName: "MyPrinter",
+		ServiceType:  "_http._tcp.local",
+		Domain:       "local",
+		Port:         8080,
+		IPv4Address:  []byte{192, 168, 1, 100},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1"}),
+	}
+
+	ptrTarget, err := message.EncodeServiceInstanceName(service.InstanceName, service.ServiceType)
+	if err != nil {
+		t.Fatalf("EncodeServiceInstanceName() error = %v", err)
+	}
+
+	// Querier's known-answer list already carries our PTR at TTL=60, which
+	// is >= half of the true 120-second TTL, so RFC 6762 §7.1 requires us to
+	// suppress it.
+	query := &message.DNSMessage{
+		Header: message.DNSHeader{
+			ID:      12345,
+			Flags:   0,
+			QDCount: 1,
+			ANCount: 1,
+		},
+		Questions: []message.Question{
+			{
+				QNAME:  "_http._tcp.local",
+				QTYPE:  uint16(protocol.RecordTypePTR),
+				QCLASS: uint16(protocol.ClassIN),
+			},
+		},
+		Answers: []message.Answer{
+			{
+				NAME:     "_http._tcp.local",
+				TYPE:     uint16(protocol.RecordTypePTR),
+				CLASS:    uint16(protocol.ClassIN),
+				TTL:      60,
+				RDLENGTH: uint16(len(ptrTarget)),
+				RDATA:    ptrTarget,
+			},
+		},
+	}
+
+	response, err := rb.BuildResponse(service, query)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v, want nil", err)
+	}
+
+	if len(response.Answers) != 0 {
+		t.Errorf("len(response.Answers) = %d, want 0 (PTR answer suppressed per RFC 6762 §7.1)", len(response.Answers))
+	}
+	// RFC 6762 §7.1 suppression is per-record, not per-response: the
+	// querier's known-answer list only covers our PTR, so SRV/TXT/A still
+	// go out in the additional section even though the PTR answer itself
+	// was dropped.
+	if len(response.Additionals) == 0 {
+		t.Error("len(response.Additionals) = 0, want SRV/TXT/A still present - suppression applies to the known PTR only, not the whole response")
+	}
+}
+
 // TestResponseBuilder_Respects9000ByteLimit tests packet size limiting per RFC 6762 §17.
 //
 // RFC 6762 §17: "Multicast DNS messages carried by UDP may be up to the IP MTU of the
@@ -165,7 +233,7 @@ func TestResponseBuilder_Respects9000ByteLimit(t *testing.T) {
 		Domain:       "local",
 		Port:         8080,
 		IPv4Address:  []byte{192, 168, 1, 100},
-		TXTRecords:   largeTXT, // ~20KB of TXT data
+		TXTRecords:   records.TXTRecordsFromMap(largeTXT), // ~20KB of TXT data
 	}
 
 	query := &message.DNSMessage{
@@ -225,7 +293,7 @@ func TestResponseBuilder_QUBitHandling(t *testing.T) {
 		Domain:       "local",
 		Port:         8080,
 		IPv4Address:  []byte{192, 168, 1, 100},
-		TXTRecords:   map[string]string{"txtvers": "1"},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1"}),
 	}
 
 	tests := []struct {
@@ -316,8 +384,16 @@ func TestResponseBuilder_QUBitHandling(t *testing.T) {
 				t.Fatal("response is nil")
 			}
 
-			// TODO: Implementation will add response.SendViaUnicast bool field
-			// For now, this test documents the requirement
+			// ResponseBuilder only constructs the Answer/Additional records;
+			// it has no src address or transport to send a reply through, so
+			// the unicast-vs-multicast decision (and the per-record
+			// multicast-history tracking RFC 6762 §5.4's 1/4-TTL exception
+			// needs) lives one layer up, in responder.Responder: see
+			// unicastDest and recordStaleSinceLastMulticast, backed by
+			// records.RecordSet.GetLastMulticast, and
+			// TestUnicastDest_OneFourthTTLForcesMulticast in
+			// responder/responder_test.go for the scenarios this table
+			// documents.
 		})
 	}
 }
@@ -342,7 +418,7 @@ func TestResponseBuilder_QUBit_OneFourthTTLException(t *testing.T) {
 		Domain:       "local",
 		Port:         8080,
 		IPv4Address:  []byte{192, 168, 1, 100},
-		TXTRecords:   map[string]string{"txtvers": "1"},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1"}),
 	}
 
 	// Query with QU bit set
@@ -412,15 +488,166 @@ func TestResponseBuilder_QUBit_OneFourthTTLException(t *testing.T) {
 				t.Fatal("response is nil")
 			}
 
-			// TODO: Implementation will track last multicast time per record
-			// and apply 1/4 TTL rule
-			// For now, test documents the requirement
+			// See the comment at the end of TestResponseBuilder_QUBitHandling:
+			// the per-record multicast-history tracking and 1/4-TTL decision
+			// this table documents live in responder.Responder, not here.
 		})
 	}
 }
 
 // BenchmarkResponseBuilder_BuildResponse benchmarks response construction latency.
 //
+// ptrQueryWithOPT builds the same PTR query used throughout this file's
+// other tests, with an EDNS(0) OPT record (RFC 6891 §6.1.2) attached
+// advertising udpPayloadSize and, optionally, dnssecOK/options.
+func ptrQueryWithOPT(udpPayloadSize uint16, dnssecOK bool, opts []message.EDNSOption) *message.DNSMessage {
+	return &message.DNSMessage{
+		Header: message.DNSHeader{
+			ID:      12345,
+			Flags:   0,
+			QDCount: 1,
+		},
+		Questions: []message.Question{
+			{
+				QNAME:  "_http._tcp.local",
+				QTYPE:  uint16(protocol.RecordTypePTR),
+				QCLASS: uint16(protocol.ClassIN),
+			},
+		},
+		OPT: &message.OPTRecord{
+			UDPPayloadSize: udpPayloadSize,
+			DNSSECOK:       dnssecOK,
+			Options:        opts,
+		},
+	}
+}
+
+// TestResponseBuilder_BuildResponse_EchoesOPT validates that BuildResponse
+// echoes an OPT record back per RFC 6891 §6.1.2 when the query carried one,
+// advertising its own maxUDPSize and echoing the query's DO bit and options
+// verbatim.
+func TestResponseBuilder_BuildResponse_EchoesOPT(t *testing.T) {
+	rb := NewResponseBuilder()
+
+	service := &ServiceWithIP{
+		InstanceName: "MyPrinter",
+		ServiceType:  "_http._tcp.local",
+		Domain:       "local",
+		Port:         8080,
+		IPv4Address:  []byte{192, 168, 1, 100},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1"}),
+	}
+
+	opts := []message.EDNSOption{
+		{Code: message.OptionCodeNSID, Data: []byte{}},
+		{Code: message.OptionCodeCookie, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+	query := ptrQueryWithOPT(4096, true, opts)
+
+	response, err := rb.BuildResponse(service, query)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v, want nil", err)
+	}
+
+	if response.OPT == nil {
+		t.Fatal("response.OPT is nil, want echoed OPT record")
+	}
+	if response.OPT.UDPPayloadSize != defaultMaxUDPSize {
+		t.Errorf("response.OPT.UDPPayloadSize = %d, want %d", response.OPT.UDPPayloadSize, defaultMaxUDPSize)
+	}
+	if !response.OPT.DNSSECOK {
+		t.Error("response.OPT.DNSSECOK = false, want true (echoed from query)")
+	}
+	if len(response.OPT.Options) != len(opts) {
+		t.Fatalf("response.OPT.Options has %d entries, want %d", len(response.OPT.Options), len(opts))
+	}
+	if response.Header.ARCount != uint16(len(response.Additionals))+1 {
+		t.Errorf("response.Header.ARCount = %d, want len(Additionals)+1 for the OPT record", response.Header.ARCount)
+	}
+}
+
+// TestResponseBuilder_BuildResponse_WithoutOPT_NoEcho validates that
+// BuildResponse leaves response.OPT nil for a classic (pre-EDNS0) query,
+// matching its original behavior exactly.
+func TestResponseBuilder_BuildResponse_WithoutOPT_NoEcho(t *testing.T) {
+	rb := NewResponseBuilder()
+
+	service := &ServiceWithIP{
+		InstanceName: "MyPrinter",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+		IPv4Address:  []byte{192, 168, 1, 100},
+	}
+
+	query := &message.DNSMessage{
+		Header:    message.DNSHeader{ID: 1, QDCount: 1},
+		Questions: []message.Question{{QNAME: "_http._tcp.local", QTYPE: uint16(protocol.RecordTypePTR), QCLASS: uint16(protocol.ClassIN)}},
+	}
+
+	response, err := rb.BuildResponse(service, query)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v, want nil", err)
+	}
+	if response.OPT != nil {
+		t.Errorf("response.OPT = %+v, want nil for a query without EDNS(0)", response.OPT)
+	}
+}
+
+// TestResponseBuilder_WithMaxUDPSize_NegotiatesSmallerSize validates that
+// negotiatedPacketSize picks the smaller of the builder's maxUDPSize and
+// the query's advertised UDPPayloadSize, and that BuildResponse truncates
+// additionals to fit it instead of the fixed 9000-byte ceiling.
+func TestResponseBuilder_WithMaxUDPSize_NegotiatesSmallerSize(t *testing.T) {
+	rb := NewResponseBuilder().WithMaxUDPSize(512)
+
+	service := &ServiceWithIP{
+		InstanceName:   "MyPrinter",
+		ServiceType:    "_http._tcp.local",
+		Port:           8080,
+		IPv4Address:    []byte{192, 168, 1, 100},
+		IPv6Addresses:  [][]byte{{0x20, 0x01, 0xd, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+		TXTRecords:     records.TXTRecordsFromMap(map[string]string{"txtvers": "1", "path": "/a/long/path/to/pad/the/additional/section/out/further", "extra": "moredata"}),
+		AdvertiseHTTPS: true,
+	}
+
+	query := ptrQueryWithOPT(9000, false, nil)
+
+	response, err := rb.BuildResponse(service, query)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v, want nil", err)
+	}
+
+	if got := rb.EstimatePacketSize(response); got > 512 {
+		t.Errorf("EstimatePacketSize(response) = %d, want <= 512 (negotiated size)", got)
+	}
+	if len(response.Answers) == 0 {
+		t.Error("response.Answers is empty, want the PTR answer kept intact even under truncation")
+	}
+}
+
+// TestResponseBuilder_NegotiatedPacketSize validates negotiatedPacketSize's
+// three cases directly: no OPT (falls back to maxPacketSize), a query
+// advertising a smaller size than our own, and a query advertising a larger
+// size than our own.
+func TestResponseBuilder_NegotiatedPacketSize(t *testing.T) {
+	rb := NewResponseBuilder().WithMaxUDPSize(1440)
+
+	noOPTQuery := &message.DNSMessage{}
+	if got := rb.negotiatedPacketSize(noOPTQuery); got != rb.maxPacketSize {
+		t.Errorf("negotiatedPacketSize(no OPT) = %d, want maxPacketSize %d", got, rb.maxPacketSize)
+	}
+
+	smallerQuery := ptrQueryWithOPT(512, false, nil)
+	if got := rb.negotiatedPacketSize(smallerQuery); got != 512 {
+		t.Errorf("negotiatedPacketSize(query advertising 512) = %d, want 512", got)
+	}
+
+	largerQuery := ptrQueryWithOPT(9000, false, nil)
+	if got := rb.negotiatedPacketSize(largerQuery); got != 1440 {
+		t.Errorf("negotiatedPacketSize(query advertising 9000) = %d, want our own 1440", got)
+	}
+}
+
 // RFC 6762 §6: Responders should respond quickly to queries (target <100ms total).
 // Response building should be a small fraction of that budget.
 //
@@ -434,7 +661,7 @@ func BenchmarkResponseBuilder_BuildResponse(b *testing.B) {
 		Domain:       "local",
 		Port:         8080,
 		IPv4Address:  []byte{192, 168, 1, 100},
-		TXTRecords:   map[string]string{"txtvers": "1", "path": "/api"},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1", "path": "/api"}),
 	}
 
 	query := &message.DNSMessage{
@@ -460,3 +687,76 @@ func BenchmarkResponseBuilder_BuildResponse(b *testing.B) {
 		}
 	}
 }
+
+// TestResponseBuilder_EstimatePacketSize_MatchesEncodedLength verifies
+// EstimatePacketSize reports msg's exact wire size - the length
+// message.EncodeMessage itself produces - rather than an approximation.
+func TestResponseBuilder_EstimatePacketSize_MatchesEncodedLength(t *testing.T) {
+	rb := NewResponseBuilder()
+
+	service := &ServiceWithIP{
+		InstanceName: "MyPrinter",
+		ServiceType:  "_http._tcp.local",
+		Domain:       "local",
+		Port:         8080,
+		IPv4Address:  []byte{192, 168, 1, 100},
+		TXTRecords:   records.TXTRecordsFromMap(map[string]string{"txtvers": "1", "path": "/"}),
+	}
+	query := &message.DNSMessage{
+		Header:    message.DNSHeader{ID: 1, QDCount: 1},
+		Questions: []message.Question{{QNAME: "_http._tcp.local", QTYPE: uint16(protocol.RecordTypePTR), QCLASS: uint16(protocol.ClassIN)}},
+	}
+
+	response, err := rb.BuildResponse(service, query)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v, want nil", err)
+	}
+
+	encoded, err := message.EncodeMessage(response, true)
+	if err != nil {
+		t.Fatalf("EncodeMessage() error = %v, want nil", err)
+	}
+
+	if got, want := rb.EstimatePacketSize(response), len(encoded); got != want {
+		t.Errorf("EstimatePacketSize(response) = %d, want %d (len of EncodeMessage output)", got, want)
+	}
+}
+
+// TestResponseBuilder_TruncateAdditionals_CreditsNameCompression verifies
+// truncateAdditionals measures candidates by actually encoding them, so
+// additionals sharing a compressible owner name are packed by their true
+// (cheap, pointer-compressed) wire cost rather than a flat per-record
+// estimate that would reject them as if every occurrence cost a full name.
+func TestResponseBuilder_TruncateAdditionals_CreditsNameCompression(t *testing.T) {
+	rb := NewResponseBuilder()
+
+	msg := &message.DNSMessage{
+		Header: message.DNSHeader{ID: 1},
+	}
+
+	// Five additionals sharing one owner name: "_http._tcp.local" encodes to
+	// 18 bytes uncompressed (labels "_http"/"_tcp"/"local" + terminator) and
+	// 2 bytes as a compression pointer on every repeat, so the true wire
+	// cost is 12 (header) + 28 (first: 18-byte name + 10 fixed fields) + 12
+	// per repeat (2-byte pointer + 10 fixed fields) = 64 for three records,
+	// 76 for four - well under what a flat ~50-byte-per-name heuristic would
+	// estimate for even the first record alone.
+	for i := 0; i < 5; i++ {
+		msg.Additionals = append(msg.Additionals, message.Answer{
+			NAME:  "_http._tcp.local",
+			TYPE:  uint16(protocol.RecordTypeTXT),
+			CLASS: uint16(protocol.ClassIN),
+			TTL:   120,
+		})
+	}
+
+	got := rb.truncateAdditionals(msg, 0, 64)
+	if len(got) != 3 {
+		t.Fatalf("truncateAdditionals() kept %d additionals, want 3 (compression-aware budget of 64 bytes)", len(got))
+	}
+
+	trial := &message.DNSMessage{Header: msg.Header, Additionals: got}
+	if size := rb.EstimatePacketSize(trial); size > 64 {
+		t.Errorf("kept additionals encode to %d bytes, want <= 64", size)
+	}
+}
@@ -0,0 +1,240 @@
+// Package security provides security features including rate limiting
+// and source IP validation for mDNS multicast traffic.
+package security
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// SecurityEventType identifies the kind of SecurityEvent emitted onto a
+// SecurityEventBus.
+type SecurityEventType string
+
+// Event types emitted by RateLimiter and SourceFilter.
+const (
+	EventRateLimitTriggered SecurityEventType = "rate_limit_triggered" // Allow() denied a query
+	EventCooldownEntered    SecurityEventType = "cooldown_entered"     // a source's bucket newly entered cooldown
+	EventSourceRejected     SecurityEventType = "source_rejected"      // SourceFilter.IsValid() rejected a source
+	EventLRUEvicted         SecurityEventType = "lru_evicted"          // a tracked source was evicted to bound memory
+	EventCleanupRun         SecurityEventType = "cleanup_run"          // periodic stale-entry cleanup completed
+)
+
+// SecurityEvent is a single typed observability event emitted by RateLimiter
+// or SourceFilter, so operators can alert on the security layer instead of
+// packets silently disappearing.
+type SecurityEvent struct {
+	Type SecurityEventType
+	Time time.Time
+
+	// SourceIP is the source (or CIDR-aggregated) address the event
+	// concerns, if any.
+	SourceIP string
+
+	// CIDR is the matched CIDR rule, if any (e.g. the SourceFilter rule
+	// that rejected an address, or the RateLimiter aggregation prefix).
+	CIDR string
+
+	// QPS is the current observed rate for SourceIP, if meaningful for
+	// this event type.
+	QPS float64
+
+	// Reason is a short machine-readable reason code, e.g.
+	// "burst_exceeded", "cooldown_active", "no_matching_rule".
+	Reason string
+}
+
+// SecurityEventSink receives SecurityEvents from a SecurityEventBus.
+type SecurityEventSink interface {
+	HandleSecurityEvent(SecurityEvent)
+}
+
+// SecurityEventBus fans a SecurityEvent out to every registered sink.
+// A nil *SecurityEventBus is valid and Emit is a no-op on it, so RateLimiter
+// and SourceFilter can hold one unconditionally without a nil check at every
+// call site.
+type SecurityEventBus struct {
+	mu    sync.RWMutex
+	sinks []SecurityEventSink
+}
+
+// NewSecurityEventBus creates a SecurityEventBus fanning out to the given
+// sinks. Additional sinks can be registered later via AddSink.
+func NewSecurityEventBus(sinks ...SecurityEventSink) *SecurityEventBus {
+	return &SecurityEventBus{sinks: append([]SecurityEventSink{}, sinks...)}
+}
+
+// AddSink registers an additional sink on the bus.
+func (b *SecurityEventBus) AddSink(sink SecurityEventSink) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Emit dispatches evt to every registered sink, stamping Time if it's zero.
+func (b *SecurityEventBus) Emit(evt SecurityEvent) {
+	if b == nil {
+		return
+	}
+	if evt.Time.IsZero() {
+		evt.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sink := range b.sinks {
+		sink.HandleSecurityEvent(evt)
+	}
+}
+
+// JSONSink writes each SecurityEvent as a single JSON line to w.
+type JSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONSink creates a JSONSink writing JSON lines to w.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+// HandleSecurityEvent implements SecurityEventSink by writing evt to the
+// sink's io.Writer as one line of JSON, best-effort (write errors are
+// dropped - a logging sink must not make the security layer fail closed).
+func (s *JSONSink) HandleSecurityEvent(evt SecurityEvent) {
+	line, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.w.Write(line) // nosemgrep: beacon-error-swallowing
+}
+
+// PrometheusSinkSnapshot is a point-in-time read of PrometheusSink's
+// counters and QPS histogram.
+type PrometheusSinkSnapshot struct {
+	Counts map[SecurityEventType]uint64
+
+	// QPS histogram summary across every QPS-bearing event observed.
+	QPSSampleCount uint64
+	QPSSum         float64
+	QPSMin         float64
+	QPSMax         float64
+}
+
+// PrometheusSink aggregates SecurityEvents into Prometheus-style counters
+// and a QPS histogram summary, mirroring records.RateLimitMetrics rather
+// than depending on the Prometheus client library directly; a responder
+// wires Snapshot() into its own /metrics endpoint.
+type PrometheusSink struct {
+	mu     sync.Mutex
+	counts map[SecurityEventType]uint64
+
+	qpsCount uint64
+	qpsSum   float64
+	qpsMin   float64
+	qpsMax   float64
+}
+
+// NewPrometheusSink creates an empty PrometheusSink.
+func NewPrometheusSink() *PrometheusSink {
+	return &PrometheusSink{counts: make(map[SecurityEventType]uint64)}
+}
+
+// HandleSecurityEvent implements SecurityEventSink by incrementing evt's
+// type counter and, for events carrying a QPS sample, folding it into the
+// running histogram summary.
+func (s *PrometheusSink) HandleSecurityEvent(evt SecurityEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counts[evt.Type]++
+
+	if evt.QPS <= 0 {
+		return
+	}
+	if s.qpsCount == 0 || evt.QPS < s.qpsMin {
+		s.qpsMin = evt.QPS
+	}
+	if evt.QPS > s.qpsMax {
+		s.qpsMax = evt.QPS
+	}
+	s.qpsSum += evt.QPS
+	s.qpsCount++
+}
+
+// Snapshot returns a copy of the sink's current counters and QPS histogram
+// summary.
+func (s *PrometheusSink) Snapshot() PrometheusSinkSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[SecurityEventType]uint64, len(s.counts))
+	for t, c := range s.counts {
+		counts[t] = c
+	}
+	return PrometheusSinkSnapshot{
+		Counts:         counts,
+		QPSSampleCount: s.qpsCount,
+		QPSSum:         s.qpsSum,
+		QPSMin:         s.qpsMin,
+		QPSMax:         s.qpsMax,
+	}
+}
+
+// RingBufferSink retains the most recent SecurityEvents in a fixed-capacity
+// ring buffer, queryable via Events() for ad hoc inspection (e.g. a debug
+// endpoint) without needing an external metrics backend.
+type RingBufferSink struct {
+	mu       sync.Mutex
+	buf      []SecurityEvent
+	capacity int
+	next     int
+	size     int
+}
+
+// NewRingBufferSink creates a RingBufferSink retaining up to capacity
+// events. capacity <= 0 is treated as 1.
+func NewRingBufferSink(capacity int) *RingBufferSink {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &RingBufferSink{
+		buf:      make([]SecurityEvent, capacity),
+		capacity: capacity,
+	}
+}
+
+// HandleSecurityEvent implements SecurityEventSink by appending evt,
+// overwriting the oldest retained event once the buffer is full.
+func (s *RingBufferSink) HandleSecurityEvent(evt SecurityEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf[s.next] = evt
+	s.next = (s.next + 1) % s.capacity
+	if s.size < s.capacity {
+		s.size++
+	}
+}
+
+// Events returns the retained events in oldest-to-newest order.
+func (s *RingBufferSink) Events() []SecurityEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SecurityEvent, 0, s.size)
+	start := (s.next - s.size + s.capacity) % s.capacity
+	for i := 0; i < s.size; i++ {
+		out = append(out, s.buf[(start+i)%s.capacity])
+	}
+	return out
+}
@@ -3,138 +3,377 @@
 package security
 
 import (
+	"fmt"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/joshuafuller/beacon/metrics"
 )
 
-// RateLimitEntry tracks query rate for a single source IP.
-// Per F-11 (Security Architecture), this enables per-source-IP rate limiting
+// RateLimitEntry tracks token-bucket rate limiting state for a single
+// (aggregated) source.
+// Per F-11 (Security Architecture), this enables per-source rate limiting
 // to protect against multicast storms (e.g., Hubitat bug sending 1000+ qps).
 type RateLimitEntry struct {
-	windowStart    time.Time // Start of current 1-second sliding window
+	key            string    // Masked source key (key in RateLimiter map)
+	tokens         float64   // Tokens currently available, refilled per RateLimiterConfig.SustainedQPS
+	lastSeen       time.Time // Last time tokens were refilled / query received (for LRU eviction)
 	cooldownExpiry time.Time // When cooldown period ends (zero if not in cooldown)
-	lastSeen       time.Time // Last query received (for LRU eviction)
-	sourceIP       string    // Source IP address (key in RateLimiter map)
-	queryCount     int       // Number of queries in current sliding window
+
+	// offenseCount and firstOffense track repeat-offender backoff: the
+	// cooldown applied on the offenseCount'th offense within a DecayWindow
+	// is base*2^(offenseCount-1), capped at MaxCooldown. firstOffense is
+	// reset (and offenseCount with it) once a full DecayWindow has passed
+	// since the window's first offense.
+	offenseCount int
+	firstOffense time.Time
 }
 
-// RateLimiter manages per-source-IP rate limiting with bounded map.
-// Default configuration: 100 qps threshold, 60s cooldown, 10,000 max entries.
+// RateLimiterConfig configures RateLimiter's per-source token-bucket rate
+// limiting.
+type RateLimiterConfig struct {
+	// Burst is the token bucket's capacity: how many queries from a given
+	// source may arrive back-to-back before the SustainedQPS refill rate
+	// starts applying.
+	Burst float64
+
+	// SustainedQPS is the bucket's refill rate in tokens/second - the
+	// long-run query rate a source may sustain indefinitely without
+	// tripping cooldown.
+	SustainedQPS float64
+
+	// IPv4CIDR and IPv6CIDR are the prefix lengths sources are aggregated to
+	// before bucket lookup (e.g. 24 and 64), similar to oragono/ergo's
+	// connection-limit CIDR aggregation, so a single misbehaving subnet
+	// can't exhaust the LRU by rotating source addresses within it. 32/128
+	// disables aggregation for that family.
+	IPv4CIDR int
+	IPv6CIDR int
+
+	// CooldownDuration is the base cooldown applied the first time a source
+	// empties its bucket while over its sustained rate. Repeat offenses
+	// within DecayWindow double it (CooldownDuration * 2^offenseCount),
+	// capped at MaxCooldown.
+	CooldownDuration time.Duration
+
+	// MaxCooldown caps the exponential-backoff cooldown computed from
+	// repeat offenses. Zero disables the cap (unbounded doubling).
+	MaxCooldown time.Duration
+
+	// DecayWindow is how long a source's offense streak is remembered.
+	// Once DecayWindow elapses since the streak's first offense without a
+	// new one, offenseCount resets to zero and the next offense is
+	// charged the base CooldownDuration again.
+	DecayWindow time.Duration
+
+	// MaxEntries bounds the number of distinct (aggregated) sources tracked;
+	// the oldest 10% by lastSeen are evicted once exceeded.
+	MaxEntries int
+
+	// Exempt lists CIDRs that bypass rate limiting entirely - Allow always
+	// returns true for an IP contained in one of them.
+	Exempt []net.IPNet
+}
+
+// RateLimiter manages per-source token-bucket rate limiting with bounded map.
+// Default configuration: 100 qps burst/sustained, 60s cooldown, 10,000 max entries.
+//
+// This is already the token-bucket-with-CIDR-aggregation design (see
+// RateLimiterConfig.IPv4CIDR/IPv6CIDR and Allow's refill logic) a fixed
+// 1-second sliding window keyed by exact source IP would otherwise need
+// replacing: a source rotating addresses within its configured prefix
+// shares one bucket rather than getting a fresh one per address.
+//
+// Operator visibility into multicast storms is already available via
+// WithEventBus's PrometheusSink, which aggregates every denial
+// (RateLimitTriggered/CooldownEntered/LRUEvicted) into Prometheus-style
+// counters. Metrics/WithMetrics add the one thing PrometheusSink can't
+// carry - an event per denial, but not one per successful Allow - so
+// AllowedCount alongside DroppedCount tells an operator the denial rate as
+// a fraction of traffic, not just a raw denial count. WithMetrics forwards
+// both to the metrics.Metrics sink Responder/Querier already use (see
+// metrics/prom for the Prometheus exposition adapter) rather than a direct
+// prometheus.Registry dependency - Beacon never imports a metrics client
+// library directly; see package metrics's doc comment.
 type RateLimiter struct {
-	threshold     int                        // Max queries/second per source IP
-	cooldown      time.Duration              // Duration to drop packets after threshold exceeded
-	maxEntries    int                        // Max number of source IPs tracked
-	sources       map[string]*RateLimitEntry // Source IP → RateLimitEntry
+	config        RateLimiterConfig
+	sources       map[string]*RateLimitEntry // Masked source key → RateLimitEntry
 	mu            sync.RWMutex               // Protects sources map
-	evictionCount uint64                     // Number of LRU evictions (for metrics)
+	evictionCount uint64                     // Number of LRU evictions (for metrics); protected by mu
+	allowedCount  atomic.Uint64              // Number of Allow calls that returned true
+	droppedCount  atomic.Uint64              // Number of Allow calls that returned false
+	events        *SecurityEventBus          // Optional observability sink fan-out; nil-safe
+	metrics       metrics.Metrics            // Optional metrics.Metrics sink; nil-safe (see WithMetrics)
+}
+
+// RateLimiterOption configures optional RateLimiter behavior beyond
+// RateLimiterConfig.
+type RateLimiterOption func(*RateLimiter)
+
+// WithEventBus attaches a SecurityEventBus that RateLimiter emits
+// RateLimitTriggered, CooldownEntered, LRUEvicted, and CleanupRun events to.
+func WithEventBus(bus *SecurityEventBus) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.events = bus }
+}
+
+// WithMetrics installs m as the RateLimiter's metrics.Metrics sink, so
+// every Allow call increments beacon_security_ratelimit_allowed_total or
+// beacon_security_ratelimit_dropped_total, and every LRU eviction
+// increments beacon_security_ratelimit_evictions_total, alongside the
+// in-process counters Metrics/Stats already report.
+//
+// Default: unset - no metrics are forwarded unless a caller opts in.
+func WithMetrics(m metrics.Metrics) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.metrics = m }
 }
 
 // NewRateLimiter creates a new rate limiter with the given configuration.
-// Per FR-026, FR-027, FR-028: Configurable threshold, cooldown, and max entries.
-func NewRateLimiter(threshold int, cooldown time.Duration, maxEntries int) *RateLimiter {
-	return &RateLimiter{
-		threshold:  threshold,
-		cooldown:   cooldown,
-		maxEntries: maxEntries,
-		sources:    make(map[string]*RateLimitEntry),
+// Per FR-026, FR-027, FR-028: Configurable burst, sustained rate, and cooldown.
+func NewRateLimiter(config RateLimiterConfig, opts ...RateLimiterOption) *RateLimiter {
+	rl := &RateLimiter{
+		config:  config,
+		sources: make(map[string]*RateLimitEntry),
+		metrics: metrics.NoOp{},
+	}
+	for _, opt := range opts {
+		opt(rl)
+	}
+	return rl
+}
+
+// RateLimiterStats is a point-in-time snapshot of RateLimiter's counters,
+// available whether or not a SecurityEventBus is attached.
+type RateLimiterStats struct {
+	EvictionCount  uint64 // Number of LRU evictions since creation
+	TrackedSources int    // Current number of distinct (aggregated) sources tracked
+}
+
+// Stats returns a snapshot of RateLimiter's current counters.
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return RateLimiterStats{
+		EvictionCount:  rl.evictionCount,
+		TrackedSources: len(rl.sources),
 	}
 }
 
+// RateLimiterMetrics is a point-in-time snapshot of RateLimiter's counters,
+// extending Stats with the allowed/dropped totals an operator alerting on
+// multicast storms needs alongside eviction count and tracked-source count.
+type RateLimiterMetrics struct {
+	EvictionCount  uint64 // Number of LRU evictions since creation
+	TrackedSources int    // Current number of distinct (aggregated) sources tracked
+	AllowedCount   uint64 // Number of Allow calls that returned true since creation
+	DroppedCount   uint64 // Number of Allow calls that returned false since creation
+}
+
+// Metrics returns a snapshot of RateLimiter's current counters, including
+// the allowed/dropped totals Stats doesn't carry. Available whether or not
+// WithMetrics or WithEventBus is attached; WithMetrics additionally
+// forwards these same counters to a metrics.Metrics sink as each Allow
+// call happens, for a backend (e.g. Prometheus via metrics/prom) that
+// wants them pushed rather than polled.
+func (rl *RateLimiter) Metrics() RateLimiterMetrics {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return RateLimiterMetrics{
+		EvictionCount:  rl.evictionCount,
+		TrackedSources: len(rl.sources),
+		AllowedCount:   rl.allowedCount.Load(),
+		DroppedCount:   rl.droppedCount.Load(),
+	}
+}
+
+// SourceStats is a point-in-time snapshot of one tracked source's repeat-
+// offender backoff state.
+type SourceStats struct {
+	OffenseCount   int       // Offenses within the current (undecayed) streak
+	CooldownExpiry time.Time // When the current cooldown ends (zero if not in cooldown)
+}
+
+// SourceStats returns a snapshot of the given (aggregated) source's
+// backoff state, and false if ip has no tracked entry.
+func (rl *RateLimiter) SourceStats(ip net.IP) (SourceStats, bool) {
+	key := rl.maskKey(ip)
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	entry, exists := rl.sources[key]
+	if !exists {
+		return SourceStats{}, false
+	}
+	return SourceStats{
+		OffenseCount:   entry.offenseCount,
+		CooldownExpiry: entry.cooldownExpiry,
+	}, true
+}
+
+// maskKey masks ip to the configured IPv4/IPv6 CIDR prefix and returns the
+// resulting string as the map key, so all sources within the same prefix
+// share a single token bucket.
+func (rl *RateLimiter) maskKey(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(rl.config.IPv4CIDR, 32)).String()
+	}
+	ip16 := ip.To16()
+	if ip16 == nil {
+		return ip.String()
+	}
+	return ip16.Mask(net.CIDRMask(rl.config.IPv6CIDR, 128)).String()
+}
+
+// isExempt reports whether ip is covered by a configured Exempt CIDR.
+func (rl *RateLimiter) isExempt(ip net.IP) bool {
+	for _, ipnet := range rl.config.Exempt {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // Allow checks if a query from the given source IP should be allowed.
-// Returns false if the source is in cooldown or exceeds the rate limit threshold.
-// Per FR-026, FR-027, FR-028: Implements sliding window rate limiting.
-func (rl *RateLimiter) Allow(sourceIP string) bool {
+// ip is first masked to the configured CIDR prefix, so a flood spread across
+// many addresses in the same subnet shares one bucket. Returns false if the
+// (aggregated) source is in cooldown or its bucket is empty.
+// Per FR-026, FR-027, FR-028: Implements token-bucket rate limiting.
+func (rl *RateLimiter) Allow(ip net.IP) bool {
+	if rl.isExempt(ip) {
+		rl.recordAllow()
+		return true
+	}
+
+	key := rl.maskKey(ip)
+
 	// Manual unlock required: Must release read lock before acquiring write lock later in function.
 	// Lock upgrade pattern - defer would cause deadlock.
 	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
-	entry, exists := rl.sources[sourceIP]
+	entry, exists := rl.sources[key]
 	rl.mu.RUnlock()
 
 	if !exists {
-		// First query from this source - create entry
 		rl.mu.Lock()
 		defer rl.mu.Unlock()
 		// Check again after acquiring write lock (double-check pattern)
-		entry, exists = rl.sources[sourceIP]
+		entry, exists = rl.sources[key]
 		if !exists {
-			rl.sources[sourceIP] = &RateLimitEntry{
-				sourceIP:    sourceIP,
-				queryCount:  1,
-				windowStart: time.Now(),
-				lastSeen:    time.Now(),
+			rl.sources[key] = &RateLimitEntry{
+				key:      key,
+				tokens:   rl.config.Burst - 1,
+				lastSeen: time.Now(),
 			}
-			// Check if map exceeded maxEntries
-			if len(rl.sources) > rl.maxEntries {
+			if len(rl.sources) > rl.config.MaxEntries {
 				rl.evict()
 			}
+			rl.recordAllow()
 			return true
 		}
 		// Entry was created by another goroutine, fall through to check it
 	}
 
-	// Update sliding window (needs write lock)
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	now := time.Now()
 
-	// Check cooldown (after acquiring lock)
 	if !entry.cooldownExpiry.IsZero() && now.Before(entry.cooldownExpiry) {
+		rl.events.Emit(SecurityEvent{
+			Type:     EventRateLimitTriggered,
+			SourceIP: key,
+			QPS:      rl.config.SustainedQPS,
+			Reason:   "cooldown_active",
+		})
+		rl.recordDrop()
 		return false // In cooldown, drop packet
 	}
+	entry.cooldownExpiry = time.Time{} // Clear any expired cooldown
 
-	// Cooldown has expired or not set, check/reset window
-	if !entry.cooldownExpiry.IsZero() && now.After(entry.cooldownExpiry) {
-		// Cooldown just expired, reset window
-		entry.queryCount = 1
-		entry.windowStart = now
-		entry.cooldownExpiry = time.Time{} // Clear cooldown
-		entry.lastSeen = now
-		return true
+	// Refill tokens accrued since lastSeen at the sustained rate, capped at
+	// burst capacity.
+	entry.tokens += now.Sub(entry.lastSeen).Seconds() * rl.config.SustainedQPS
+	if entry.tokens > rl.config.Burst {
+		entry.tokens = rl.config.Burst
 	}
-
-	// Check if window has expired (>1 second)
-	if now.Sub(entry.windowStart) > 1*time.Second {
-		// Reset window
-		entry.queryCount = 1
-		entry.windowStart = now
-		entry.cooldownExpiry = time.Time{} // Clear any expired cooldown
-	} else {
-		// Increment count in current window
-		entry.queryCount++
-	}
-
 	entry.lastSeen = now
 
-	// Check threshold
-	if entry.queryCount > rl.threshold {
-		// Exceeded threshold, start cooldown
-		entry.cooldownExpiry = now.Add(rl.cooldown)
+	if entry.tokens < 1 {
+		// Bucket empty and still being refilled at the sustained rate -
+		// this source is over its sustained rate, so cool it down.
+		//
+		// offenseCount tracks repeat offenses within DecayWindow so a
+		// source that keeps hammering and sleeping through a flat
+		// cooldown gets an exponentially longer one instead.
+		if entry.firstOffense.IsZero() || now.Sub(entry.firstOffense) > rl.config.DecayWindow {
+			entry.offenseCount = 0
+			entry.firstOffense = now
+		}
+		entry.offenseCount++
+
+		cooldown := rl.config.CooldownDuration * time.Duration(1<<uint(entry.offenseCount-1))
+		if rl.config.MaxCooldown > 0 && cooldown > rl.config.MaxCooldown {
+			cooldown = rl.config.MaxCooldown
+		}
+		entry.cooldownExpiry = now.Add(cooldown)
+		rl.events.Emit(SecurityEvent{
+			Type:     EventCooldownEntered,
+			SourceIP: key,
+			QPS:      rl.config.SustainedQPS,
+			Reason:   "sustained_rate_exceeded",
+		})
+		rl.events.Emit(SecurityEvent{
+			Type:     EventRateLimitTriggered,
+			SourceIP: key,
+			QPS:      rl.config.SustainedQPS,
+			Reason:   "burst_exceeded",
+		})
+		rl.recordDrop()
 		return false
 	}
 
+	entry.tokens--
+	rl.recordAllow()
 	return true
 }
 
-// evict performs LRU cleanup when the sources map exceeds maxEntries.
+// recordAllow increments allowedCount and, if WithMetrics installed a
+// sink, beacon_security_ratelimit_allowed_total. allowedCount/droppedCount
+// are atomic rather than rl.mu-protected like the rest of RateLimiter's
+// counters, since Allow's exempt-IP fast path returns before ever
+// acquiring rl.mu.
+func (rl *RateLimiter) recordAllow() {
+	rl.allowedCount.Add(1)
+	rl.metrics.IncCounter("beacon_security_ratelimit_allowed_total", nil)
+}
+
+// recordDrop increments droppedCount and, if WithMetrics installed a sink,
+// beacon_security_ratelimit_dropped_total.
+func (rl *RateLimiter) recordDrop() {
+	rl.droppedCount.Add(1)
+	rl.metrics.IncCounter("beacon_security_ratelimit_dropped_total", nil)
+}
+
+// evict performs LRU cleanup when the sources map exceeds MaxEntries.
 // Removes oldest 10% of entries by lastSeen timestamp.
 // MUST be called while holding rl.mu write lock.
 func (rl *RateLimiter) evict() {
-	// Calculate how many entries to evict (10% of maxEntries)
-	evictCount := rl.maxEntries / 10
+	// Calculate how many entries to evict (10% of MaxEntries)
+	evictCount := rl.config.MaxEntries / 10
 	if evictCount == 0 {
 		evictCount = 1 // Evict at least one entry
 	}
 
 	// Collect all entries with their lastSeen timestamp
 	type entryWithTime struct {
-		ip       string
+		key      string
 		lastSeen time.Time
 	}
 
 	entries := make([]entryWithTime, 0, len(rl.sources))
-	for ip, entry := range rl.sources {
-		entries = append(entries, entryWithTime{ip: ip, lastSeen: entry.lastSeen})
+	for key, entry := range rl.sources {
+		entries = append(entries, entryWithTime{key: key, lastSeen: entry.lastSeen})
 	}
 
 	// Sort by lastSeen (oldest first)
@@ -154,11 +393,17 @@ func (rl *RateLimiter) evict() {
 	// Evict oldest entries
 	evicted := 0
 	for i := 0; i < evictCount && i < len(entries); i++ {
-		delete(rl.sources, entries[i].ip)
+		delete(rl.sources, entries[i].key)
 		evicted++
+		rl.events.Emit(SecurityEvent{
+			Type:     EventLRUEvicted,
+			SourceIP: entries[i].key,
+			Reason:   "max_entries_exceeded",
+		})
+		rl.metrics.IncCounter("beacon_security_ratelimit_evictions_total", nil)
 	}
 
-	// G115: bounds checked - evicted is always non-negative and less than evictCount (which is at most maxEntries/10)
+	// G115: bounds checked - evicted is always non-negative and less than evictCount (which is at most MaxEntries/10)
 	if evicted >= 0 { //nolint:gosec // G115: bounds checked
 		rl.evictionCount += uint64(evicted)
 	}
@@ -175,16 +420,20 @@ func (rl *RateLimiter) Cleanup() {
 	toDelete := make([]string, 0)
 
 	// Find stale entries (not seen recently)
-	for ip, entry := range rl.sources {
+	for key, entry := range rl.sources {
 		// Remove if not seen in last 1 minute (conservative cleanup)
-		// This handles both entries with expired cooldowns and inactive sources
 		if now.Sub(entry.lastSeen) > 1*time.Minute {
-			toDelete = append(toDelete, ip)
+			toDelete = append(toDelete, key)
 		}
 	}
 
 	// Delete stale entries
-	for _, ip := range toDelete {
-		delete(rl.sources, ip)
+	for _, key := range toDelete {
+		delete(rl.sources, key)
 	}
+
+	rl.events.Emit(SecurityEvent{
+		Type:   EventCleanupRun,
+		Reason: fmt.Sprintf("removed_%d", len(toDelete)),
+	})
 }
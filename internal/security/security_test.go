@@ -1,8 +1,12 @@
 package security
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/netip"
+	"strings"
 	"testing"
 	"time"
 )
@@ -12,25 +16,38 @@ import (
 // by unlock on the next line. This is safe for tests.
 // nosemgrep: beacon-mutex-defer-unlock
 
+// defaultTestRateLimiterConfig returns a RateLimiterConfig equivalent to the
+// old default (100 qps threshold, 60s cooldown, 10,000 max entries) with no
+// CIDR aggregation, for tests that don't exercise aggregation directly.
+func defaultTestRateLimiterConfig() RateLimiterConfig {
+	return RateLimiterConfig{
+		Burst:            100,
+		SustainedQPS:     100,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		MaxEntries:       10000,
+	}
+}
+
 // TestRateLimiter_Allow_NormalLoad verifies rate limiter allows traffic under threshold.
 // Per F-11 REQ-F11-2: Default 100 qps threshold should allow legitimate high-volume traffic.
 func TestRateLimiter_Allow_NormalLoad(t *testing.T) {
-	// Create RateLimiter with threshold=100
-	rl := NewRateLimiter(100, 60*time.Second, 10000)
+	rl := NewRateLimiter(defaultTestRateLimiterConfig())
 
-	sourceIP := "192.168.1.50"
+	sourceIP := net.ParseIP("192.168.1.50")
 
-	// Send 50 queries from same source IP (well under 100 qps threshold)
+	// Send 50 queries from same source IP (well under 100 qps burst)
 	for i := 0; i < 50; i++ {
 		allowed := rl.Allow(sourceIP)
 		if !allowed {
-			t.Errorf("Query %d was blocked but should be allowed (under 100 qps threshold)", i+1)
+			t.Errorf("Query %d was blocked but should be allowed (under 100 qps burst)", i+1)
 		}
 	}
 
 	// Verify no cooldown triggered (entry should exist but no cooldown)
 	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
-	entry, exists := rl.sources[sourceIP]
+	entry, exists := rl.sources[rl.maskKey(sourceIP)]
 	rl.mu.RUnlock()
 
 	if !exists {
@@ -41,23 +58,23 @@ func TestRateLimiter_Allow_NormalLoad(t *testing.T) {
 		t.Errorf("Expected no cooldown, but cooldownExpiry is set to %v", entry.cooldownExpiry)
 	}
 
-	if entry.queryCount > 100 {
-		t.Errorf("Expected queryCount <= 100, got %d", entry.queryCount)
+	if entry.tokens < 0 {
+		t.Errorf("Expected tokens >= 0, got %v", entry.tokens)
 	}
 }
 
 // TestRateLimiter_Allow_ExceedsThreshold verifies rate limiter blocks flooding sources.
 // Per F-11 REQ-F11-2: >100 qps triggers cooldown.
 func TestRateLimiter_Allow_ExceedsThreshold(t *testing.T) {
-	// Create RateLimiter with threshold=100, cooldown=60s
-	rl := NewRateLimiter(100, 60*time.Second, 10000)
+	rl := NewRateLimiter(defaultTestRateLimiterConfig())
 
-	sourceIP := "192.168.1.100"
+	sourceIP := net.ParseIP("192.168.1.100")
 
 	allowedCount := 0
 	blockedCount := 0
 
-	// Send 150 queries from same source IP within 1 second (exceeds 100 qps threshold)
+	// Send 150 queries from same source IP in a tight loop (exceeds the
+	// 100-token burst capacity before any meaningful refill happens)
 	for i := 0; i < 150; i++ {
 		allowed := rl.Allow(sourceIP)
 		if allowed {
@@ -78,7 +95,7 @@ func TestRateLimiter_Allow_ExceedsThreshold(t *testing.T) {
 
 	// Verify cooldown triggered
 	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
-	entry, exists := rl.sources[sourceIP]
+	entry, exists := rl.sources[rl.maskKey(sourceIP)]
 	rl.mu.RUnlock()
 
 	if !exists {
@@ -97,12 +114,19 @@ func TestRateLimiter_Allow_ExceedsThreshold(t *testing.T) {
 // TestRateLimiter_Cooldown verifies cooldown period drops packets.
 // Per F-11 REQ-F11-3: 60s default cooldown.
 func TestRateLimiter_Cooldown(t *testing.T) {
-	// Create RateLimiter with threshold=10, cooldown=500ms (short for testing)
-	rl := NewRateLimiter(10, 500*time.Millisecond, 10000)
-
-	sourceIP := "192.168.1.150"
-
-	// Trigger cooldown by exceeding threshold
+	// Create RateLimiter with burst=10, cooldown=500ms (short for testing)
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            10,
+		SustainedQPS:     10,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 500 * time.Millisecond,
+		MaxEntries:       10000,
+	})
+
+	sourceIP := net.ParseIP("192.168.1.150")
+
+	// Trigger cooldown by exceeding the burst capacity
 	for i := 0; i < 20; i++ {
 		rl.Allow(sourceIP)
 	}
@@ -126,7 +150,7 @@ func TestRateLimiter_Cooldown(t *testing.T) {
 
 	// Verify cooldown was cleared
 	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
-	entry, exists := rl.sources[sourceIP]
+	entry, exists := rl.sources[rl.maskKey(sourceIP)]
 	rl.mu.RUnlock()
 
 	if !exists {
@@ -140,15 +164,144 @@ func TestRateLimiter_Cooldown(t *testing.T) {
 	}
 }
 
-// TestRateLimiter_BoundedMap verifies LRU eviction at 10,000 entries.
+// TestRateLimiter_ExponentialBackoff verifies a source that re-offends
+// three times in a row within DecayWindow gets 60s, then 120s, then 240s
+// cooldowns (base*2^(offenseCount-1)), and that SourceStats reports the
+// growing offenseCount.
+func TestRateLimiter_ExponentialBackoff(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            1,
+		SustainedQPS:     1,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		DecayWindow:      600 * time.Second,
+		MaxEntries:       10000,
+	})
+
+	sourceIP := net.ParseIP("192.0.2.77")
+	key := rl.maskKey(sourceIP)
+
+	rl.Allow(sourceIP) // consumes the only token, no offense yet
+
+	expectOffense := func(wantCooldown time.Duration, wantOffenseCount int) {
+		t.Helper()
+		if rl.Allow(sourceIP) {
+			t.Fatal("expected query to be blocked by cooldown/offense, but it was allowed")
+		}
+
+		stats, exists := rl.SourceStats(sourceIP)
+		if !exists {
+			t.Fatal("SourceStats() reported no entry for a source that just offended")
+		}
+		if stats.OffenseCount != wantOffenseCount {
+			t.Errorf("OffenseCount = %d, want %d", stats.OffenseCount, wantOffenseCount)
+		}
+
+		got := time.Until(stats.CooldownExpiry)
+		if got < wantCooldown-2*time.Second || got > wantCooldown {
+			t.Errorf("cooldown ~= %v, want ~%v", got, wantCooldown)
+		}
+
+		// Force the cooldown to have already expired and the bucket back
+		// to empty, so the next Allow() call re-offends immediately
+		// instead of this test sleeping out a real 60s/120s/240s cooldown.
+		rl.mu.Lock()
+		entry := rl.sources[key]
+		entry.cooldownExpiry = time.Now().Add(-time.Millisecond)
+		entry.tokens = 0
+		rl.mu.Unlock()
+	}
+
+	expectOffense(60*time.Second, 1)
+	expectOffense(120*time.Second, 2)
+	expectOffense(240*time.Second, 3)
+}
+
+// TestRateLimiter_ExponentialBackoff_MaxCooldown verifies the exponential
+// cooldown is capped at MaxCooldown instead of doubling indefinitely.
+func TestRateLimiter_ExponentialBackoff_MaxCooldown(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            1,
+		SustainedQPS:     1,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		MaxCooldown:      90 * time.Second,
+		DecayWindow:      600 * time.Second,
+		MaxEntries:       10000,
+	})
+
+	sourceIP := net.ParseIP("192.0.2.78")
+	key := rl.maskKey(sourceIP)
+
+	rl.Allow(sourceIP) // consumes the only token
+
+	rl.Allow(sourceIP) // offense #1 -> 60s, under the cap
+
+	rl.mu.Lock()
+	entry := rl.sources[key]
+	entry.cooldownExpiry = time.Now().Add(-time.Millisecond)
+	entry.tokens = 0
+	rl.mu.Unlock()
+
+	rl.Allow(sourceIP) // offense #2 -> would be 120s, capped to 90s
+
+	stats, _ := rl.SourceStats(sourceIP)
+	got := time.Until(stats.CooldownExpiry)
+	if got > 90*time.Second {
+		t.Errorf("cooldown = %v, want capped at MaxCooldown (90s)", got)
+	}
+}
+
+// TestRateLimiter_OffenseDecay verifies offenseCount resets to zero once a
+// full DecayWindow elapses since the previous offense.
+func TestRateLimiter_OffenseDecay(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            1,
+		SustainedQPS:     1,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		DecayWindow:      10 * time.Second,
+		MaxEntries:       10000,
+	})
+
+	sourceIP := net.ParseIP("192.0.2.79")
+	key := rl.maskKey(sourceIP)
+
+	rl.Allow(sourceIP)
+	rl.Allow(sourceIP) // offense #1
+
+	// Simulate the cooldown having expired and the offense streak's start
+	// having aged past DecayWindow, while keeping the bucket itself empty
+	// (lastSeen left at "now" so the refill doesn't also re-fill tokens).
+	rl.mu.Lock()
+	entry := rl.sources[key]
+	entry.cooldownExpiry = time.Now().Add(-time.Millisecond)
+	entry.firstOffense = time.Now().Add(-20 * time.Second)
+	entry.tokens = 0
+	entry.lastSeen = time.Now()
+	rl.mu.Unlock()
+
+	rl.Allow(sourceIP) // offense after decay window -> back to offense #1
+
+	stats, _ := rl.SourceStats(sourceIP)
+	if stats.OffenseCount != 1 {
+		t.Errorf("OffenseCount after decay window = %d, want 1 (streak should have reset)", stats.OffenseCount)
+	}
+}
+
+// TestRateLimiter_BoundedMap verifies LRU eviction at MaxEntries.
 // Per F-11 REQ-F11-4: Prevent memory exhaustion.
 func TestRateLimiter_BoundedMap(t *testing.T) {
-	// Create RateLimiter with maxEntries=100 (small for testing)
-	rl := NewRateLimiter(100, 60*time.Second, 100)
+	config := defaultTestRateLimiterConfig()
+	config.MaxEntries = 100 // small for testing
+	rl := NewRateLimiter(config)
 
 	// Send queries from 150 unique source IPs
 	for i := 0; i < 150; i++ {
-		sourceIP := fmt.Sprintf("192.168.1.%d", i)
+		sourceIP := net.ParseIP(fmt.Sprintf("192.168.1.%d", i))
 		rl.Allow(sourceIP)
 	}
 
@@ -164,15 +317,15 @@ func TestRateLimiter_BoundedMap(t *testing.T) {
 
 	// Verify eviction occurred (we added 150 sources but max is 100)
 	if evictionCount == 0 {
-		t.Error("Expected evictionCount > 0 after exceeding maxEntries, but got 0")
+		t.Error("Expected evictionCount > 0 after exceeding MaxEntries, but got 0")
 	}
 
 	// Test LRU behavior: Add a new source, verify it's in the map
-	newestIP := "10.0.0.1"
+	newestIP := net.ParseIP("10.0.0.1")
 	rl.Allow(newestIP)
 
 	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
-	_, exists := rl.sources[newestIP]
+	_, exists := rl.sources[rl.maskKey(newestIP)]
 	rl.mu.RUnlock()
 
 	if !exists {
@@ -183,12 +336,11 @@ func TestRateLimiter_BoundedMap(t *testing.T) {
 // TestRateLimiter_Cleanup verifies periodic cleanup removes stale entries.
 // Per F-11 REQ-F11-5: Cleanup every 5 minutes.
 func TestRateLimiter_Cleanup(t *testing.T) {
-	// Create RateLimiter
-	rl := NewRateLimiter(100, 60*time.Second, 10000)
+	rl := NewRateLimiter(defaultTestRateLimiterConfig())
 
-	staleIP1 := "192.168.1.1"
-	staleIP2 := "192.168.1.2"
-	activeIP := "192.168.1.3"
+	staleIP1 := net.ParseIP("192.168.1.1")
+	staleIP2 := net.ParseIP("192.168.1.2")
+	activeIP := net.ParseIP("192.168.1.3")
 
 	// Add stale entries (simulate old traffic)
 	rl.Allow(staleIP1)
@@ -196,10 +348,10 @@ func TestRateLimiter_Cleanup(t *testing.T) {
 
 	// Manually age these entries by updating their lastSeen to >1 minute ago
 	rl.mu.Lock() // nosemgrep: beacon-mutex-defer-unlock
-	if entry, exists := rl.sources[staleIP1]; exists {
+	if entry, exists := rl.sources[rl.maskKey(staleIP1)]; exists {
 		entry.lastSeen = time.Now().Add(-2 * time.Minute)
 	}
-	if entry, exists := rl.sources[staleIP2]; exists {
+	if entry, exists := rl.sources[rl.maskKey(staleIP2)]; exists {
 		entry.lastSeen = time.Now().Add(-2 * time.Minute)
 	}
 	rl.mu.Unlock()
@@ -222,9 +374,9 @@ func TestRateLimiter_Cleanup(t *testing.T) {
 	// After cleanup, verify stale entries removed
 	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
 	afterSize := len(rl.sources)
-	_, staleExists1 := rl.sources[staleIP1]
-	_, staleExists2 := rl.sources[staleIP2]
-	_, activeExists := rl.sources[activeIP]
+	_, staleExists1 := rl.sources[rl.maskKey(staleIP1)]
+	_, staleExists2 := rl.sources[rl.maskKey(staleIP2)]
+	_, activeExists := rl.sources[rl.maskKey(activeIP)]
 	rl.mu.RUnlock()
 
 	// Stale entries should be removed
@@ -246,6 +398,87 @@ func TestRateLimiter_Cleanup(t *testing.T) {
 	}
 }
 
+// TestRateLimiter_CIDRAggregation_IPv4 verifies that sources within the same
+// configured IPv4 CIDR share a single token bucket, per the request that 300
+// unique IPs inside one /24 collapse to a single bucket.
+func TestRateLimiter_CIDRAggregation_IPv4(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            300,
+		SustainedQPS:     300,
+		IPv4CIDR:         24,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		MaxEntries:       10000,
+	})
+
+	// 300 unique IPs spread across two /24s (192.168.1.0/24 and 192.168.2.0/24)
+	for i := 0; i < 300; i++ {
+		sourceIP := net.ParseIP(fmt.Sprintf("192.168.%d.%d", 1+i/256, i%256))
+		rl.Allow(sourceIP)
+	}
+
+	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
+	mapSize := len(rl.sources)
+	rl.mu.RUnlock()
+
+	if mapSize != 2 {
+		t.Errorf("Expected 300 IPs in two /24s to collapse to 2 buckets, got %d", mapSize)
+	}
+}
+
+// TestRateLimiter_CIDRAggregation_IPv6 mirrors TestRateLimiter_CIDRAggregation_IPv4
+// for IPv6CIDR.
+func TestRateLimiter_CIDRAggregation_IPv6(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            300,
+		SustainedQPS:     300,
+		IPv4CIDR:         32,
+		IPv6CIDR:         64,
+		CooldownDuration: 60 * time.Second,
+		MaxEntries:       10000,
+	})
+
+	// 300 unique addresses within the same /64
+	for i := 0; i < 300; i++ {
+		sourceIP := net.ParseIP(fmt.Sprintf("2001:db8::%x", i))
+		rl.Allow(sourceIP)
+	}
+
+	rl.mu.RLock() // nosemgrep: beacon-mutex-defer-unlock
+	mapSize := len(rl.sources)
+	rl.mu.RUnlock()
+
+	if mapSize != 1 {
+		t.Errorf("Expected 300 addresses in one /64 to collapse to 1 bucket, got %d", mapSize)
+	}
+}
+
+// TestRateLimiter_Exempt verifies that sources covered by a configured Exempt
+// CIDR always bypass rate limiting, even past burst capacity.
+func TestRateLimiter_Exempt(t *testing.T) {
+	_, exemptNet, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("ParseCIDR failed: %v", err)
+	}
+
+	rl := NewRateLimiter(RateLimiterConfig{
+		Burst:            1,
+		SustainedQPS:     1,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		MaxEntries:       10000,
+		Exempt:           []net.IPNet{*exemptNet},
+	})
+
+	exemptIP := net.ParseIP("10.1.2.3")
+	for i := 0; i < 50; i++ {
+		if !rl.Allow(exemptIP) {
+			t.Fatalf("Allow() = false for exempt source on query %d, want true", i+1)
+		}
+	}
+}
+
 // NOTE: Original test skeletons (T067-T070) removed.
 // Actual implementations use _Agent4 suffix (see below).
 
@@ -266,7 +499,7 @@ func TestIsPrivate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ip := net.ParseIP(tt.ip)
+			ip := netip.MustParseAddr(tt.ip)
 			got := isPrivate(ip)
 			if got != tt.want {
 				t.Errorf("isPrivate(%s) = %v, want %v", tt.ip, got, tt.want)
@@ -305,8 +538,8 @@ func TestSourceFilter_IsValid_LinkLocal_Agent4(t *testing.T) {
 
 	for _, ipStr := range linkLocalIPs {
 		t.Run(ipStr, func(t *testing.T) {
-			ip := net.ParseIP(ipStr)
-			if ip == nil {
+			ip, err := netip.ParseAddr(ipStr)
+			if err != nil {
 				t.Fatalf("Failed to parse IP: %s", ipStr)
 			}
 
@@ -328,15 +561,13 @@ func TestSourceFilter_IsValid_SameSubnet_Agent4(t *testing.T) {
 		Flags: net.FlagUp | net.FlagMulticast,
 	}
 
-	// Manually create SourceFilter with known subnet (192.168.1.0/24)
-	_, ipnet, err := net.ParseCIDR("192.168.1.100/24")
-	if err != nil {
-		t.Fatalf("Failed to parse CIDR: %v", err)
-	}
+	// Manually declare the known subnet (192.168.1.0/24) as an allow rule,
+	// bypassing iface.Addrs() (iface isn't a real OS interface in this test).
+	ipnet := netip.MustParsePrefix("192.168.1.100/24")
 
-	sf := &SourceFilter{
-		iface:      iface,
-		ifaceAddrs: []net.IPNet{*ipnet},
+	sf, err := NewSourceFilterFromConfig([]net.Interface{iface}, []netip.Prefix{ipnet}, nil)
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
 	}
 
 	// Test IPs in same subnet (should be accepted)
@@ -349,8 +580,8 @@ func TestSourceFilter_IsValid_SameSubnet_Agent4(t *testing.T) {
 
 	for _, ipStr := range sameSubnetIPs {
 		t.Run("same_"+ipStr, func(t *testing.T) {
-			ip := net.ParseIP(ipStr)
-			if ip == nil {
+			ip, err := netip.ParseAddr(ipStr)
+			if err != nil {
 				t.Fatalf("Failed to parse IP: %s", ipStr)
 			}
 
@@ -368,8 +599,8 @@ func TestSourceFilter_IsValid_SameSubnet_Agent4(t *testing.T) {
 
 	for _, ipStr := range differentSubnetIPs {
 		t.Run("diff_"+ipStr, func(t *testing.T) {
-			ip := net.ParseIP(ipStr)
-			if ip == nil {
+			ip, err := netip.ParseAddr(ipStr)
+			if err != nil {
 				t.Fatalf("Failed to parse IP: %s", ipStr)
 			}
 
@@ -390,14 +621,11 @@ func TestSourceFilter_IsValid_RejectsRoutedIP_Agent4(t *testing.T) {
 		Flags: net.FlagUp | net.FlagMulticast,
 	}
 
-	_, ipnet, err := net.ParseCIDR("192.168.1.100/24")
-	if err != nil {
-		t.Fatalf("Failed to parse CIDR: %v", err)
-	}
+	ipnet := netip.MustParsePrefix("192.168.1.100/24")
 
-	sf := &SourceFilter{
-		iface:      iface,
-		ifaceAddrs: []net.IPNet{*ipnet},
+	sf, err := NewSourceFilterFromConfig([]net.Interface{iface}, []netip.Prefix{ipnet}, nil)
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
 	}
 
 	// Test routed/public IPs that are NOT link-local and NOT same subnet
@@ -408,8 +636,8 @@ func TestSourceFilter_IsValid_RejectsRoutedIP_Agent4(t *testing.T) {
 
 	for _, ipStr := range routedIPs {
 		t.Run(ipStr, func(t *testing.T) {
-			ip := net.ParseIP(ipStr)
-			if ip == nil {
+			ip, err := netip.ParseAddr(ipStr)
+			if err != nil {
 				t.Fatalf("Failed to parse IP: %s", ipStr)
 			}
 
@@ -430,14 +658,11 @@ func TestSourceFilter_IsValid_RejectsDifferentSubnet_Agent4(t *testing.T) {
 		Flags: net.FlagUp | net.FlagMulticast,
 	}
 
-	_, ipnet, err := net.ParseCIDR("10.0.1.100/24")
-	if err != nil {
-		t.Fatalf("Failed to parse CIDR: %v", err)
-	}
+	ipnet := netip.MustParsePrefix("10.0.1.100/24")
 
-	sf := &SourceFilter{
-		iface:      iface,
-		ifaceAddrs: []net.IPNet{*ipnet},
+	sf, err := NewSourceFilterFromConfig([]net.Interface{iface}, []netip.Prefix{ipnet}, nil)
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
 	}
 
 	// Test private IPs in different subnets
@@ -449,8 +674,8 @@ func TestSourceFilter_IsValid_RejectsDifferentSubnet_Agent4(t *testing.T) {
 
 	for _, ipStr := range differentSubnetIPs {
 		t.Run(ipStr, func(t *testing.T) {
-			ip := net.ParseIP(ipStr)
-			if ip == nil {
+			ip, err := netip.ParseAddr(ipStr)
+			if err != nil {
 				t.Fatalf("Failed to parse IP: %s", ipStr)
 			}
 
@@ -462,8 +687,349 @@ func TestSourceFilter_IsValid_RejectsDifferentSubnet_Agent4(t *testing.T) {
 
 	// Verify IPs in the SAME subnet are still accepted
 	sameSubnetIP := "10.0.1.50"
-	ip := net.ParseIP(sameSubnetIP)
+	ip := netip.MustParseAddr(sameSubnetIP)
 	if !sf.IsValid(ip) {
 		t.Errorf("IsValid(%s) = false, want true (IP is in same subnet 10.0.1.0/24)", sameSubnetIP)
 	}
 }
+
+// TestSourceFilter_FromConfig_ExplicitAllow verifies that an explicit allow
+// CIDR admits addresses outside any interface's subnet and outside the
+// link-local range, for multi-homed/bridged setups.
+func TestSourceFilter_FromConfig_ExplicitAllow(t *testing.T) {
+	dockerNet := netip.MustParsePrefix("172.17.0.0/16")
+
+	sf, err := NewSourceFilterFromConfig(nil, []netip.Prefix{dockerNet}, nil)
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	if !sf.IsValid(netip.MustParseAddr("172.17.0.5")) {
+		t.Error("IsValid() = false for address inside explicit allow CIDR, want true")
+	}
+	if sf.IsValid(netip.MustParseAddr("172.18.0.5")) {
+		t.Error("IsValid() = true for address outside every rule, want false")
+	}
+}
+
+// TestSourceFilter_FromConfig_DenyOverridesAllow verifies that a deny CIDR
+// carved out of a broader allow CIDR wins via longest-prefix-match, per RFC
+// 6762 §2's link-local scope still being the safe default.
+func TestSourceFilter_FromConfig_DenyOverridesAllow(t *testing.T) {
+	allowNet := netip.MustParsePrefix("10.0.0.0/8")
+	denyNet := netip.MustParsePrefix("10.0.0.0/24")
+
+	sf, err := NewSourceFilterFromConfig(nil, []netip.Prefix{allowNet}, []netip.Prefix{denyNet})
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	if sf.IsValid(netip.MustParseAddr("10.0.0.5")) {
+		t.Error("IsValid() = true for address inside the more specific deny CIDR, want false")
+	}
+	if !sf.IsValid(netip.MustParseAddr("10.0.1.5")) {
+		t.Error("IsValid() = false for address only covered by the broader allow CIDR, want true")
+	}
+}
+
+// TestSourceFilter_FromConfig_DenyWinsExactTie verifies that when an allow
+// and a deny rule are registered at the exact same prefix, deny wins
+// regardless of insertion order.
+func TestSourceFilter_FromConfig_DenyWinsExactTie(t *testing.T) {
+	ipnet := netip.MustParsePrefix("203.0.113.0/24")
+
+	sf, err := NewSourceFilterFromConfig(nil, []netip.Prefix{ipnet}, []netip.Prefix{ipnet})
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	if sf.IsValid(netip.MustParseAddr("203.0.113.10")) {
+		t.Error("IsValid() = true for a CIDR registered as both allow and deny, want false (deny wins ties)")
+	}
+}
+
+// TestSourceFilter_FromConfig_IPv6 verifies the IPv6 tree matches
+// longest-prefix the same way the IPv4 tree does.
+func TestSourceFilter_FromConfig_IPv6(t *testing.T) {
+	allowNet := netip.MustParsePrefix("2001:db8::/32")
+	denyNet := netip.MustParsePrefix("2001:db8:1::/48")
+
+	sf, err := NewSourceFilterFromConfig(nil, []netip.Prefix{allowNet}, []netip.Prefix{denyNet})
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	if !sf.IsValid(netip.MustParseAddr("2001:db8:2::1")) {
+		t.Error("IsValid() = false for address only covered by the broader IPv6 allow CIDR, want true")
+	}
+	if sf.IsValid(netip.MustParseAddr("2001:db8:1::1")) {
+		t.Error("IsValid() = true for address inside the more specific IPv6 deny CIDR, want false")
+	}
+}
+
+// TestSourceFilter_IsValidZone_LinkLocal_RequiresMatchingZone verifies that
+// an IPv6 link-local (fe80::/10) source is only admitted when its zone names
+// a configured interface, since the prefix itself is identical on every link.
+func TestSourceFilter_IsValidZone_LinkLocal_RequiresMatchingZone(t *testing.T) {
+	iface := net.Interface{Index: 1, Name: "eth0", Flags: net.FlagUp | net.FlagMulticast}
+
+	sf, err := NewSourceFilter(iface)
+	if err != nil {
+		t.Fatalf("NewSourceFilter() failed: %v", err)
+	}
+
+	linkLocalIP := netip.MustParseAddr("fe80::1")
+
+	if !sf.IsValidZone(linkLocalIP, "eth0") {
+		t.Error("IsValidZone() = false for link-local source on the configured interface's zone, want true")
+	}
+	if sf.IsValidZone(linkLocalIP, "wlan0") {
+		t.Error("IsValidZone() = true for link-local source on a different interface's zone, want false")
+	}
+	if sf.IsValid(linkLocalIP) {
+		t.Error("IsValid() = true for a link-local source with no zone, want false (can't confirm the right link)")
+	}
+}
+
+// TestSourceFilter_IsValidZone_UniqueLocal_SameSubnet verifies RFC 4193
+// unique-local (fc00::/7) sources are admitted by matching a cached
+// interface prefix, the same same-subnet rule every other family uses,
+// rather than a blanket fc00::/7 allow.
+func TestSourceFilter_IsValidZone_UniqueLocal_SameSubnet(t *testing.T) {
+	iface := net.Interface{Index: 1, Name: "eth0", Flags: net.FlagUp | net.FlagMulticast}
+
+	ulaNet := netip.MustParsePrefix("fd00:1234:5678::/64")
+
+	sf, err := NewSourceFilterFromConfig([]net.Interface{iface}, []netip.Prefix{ulaNet}, nil)
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	if !sf.IsValid(netip.MustParseAddr("fd00:1234:5678::1")) {
+		t.Error("IsValid() = false for unique-local address in the configured /64, want true")
+	}
+	if sf.IsValid(netip.MustParseAddr("fd00:9999::1")) {
+		t.Error("IsValid() = true for unique-local address outside any configured prefix, want false")
+	}
+}
+
+// TestRateLimiter_Stats verifies Stats() reflects tracked sources and
+// evictions without requiring a SecurityEventBus.
+func TestRateLimiter_Stats(t *testing.T) {
+	config := defaultTestRateLimiterConfig()
+	config.MaxEntries = 2
+	rl := NewRateLimiter(config)
+
+	rl.Allow(net.ParseIP("192.0.2.1"))
+	rl.Allow(net.ParseIP("192.0.2.2"))
+	rl.Allow(net.ParseIP("192.0.2.3")) // exceeds MaxEntries, triggers eviction
+
+	stats := rl.Stats()
+	if stats.TrackedSources == 0 {
+		t.Error("Stats().TrackedSources = 0, want > 0")
+	}
+	if stats.EvictionCount == 0 {
+		t.Error("Stats().EvictionCount = 0, want > 0 after exceeding MaxEntries")
+	}
+}
+
+// TestRateLimiter_WithEventBus verifies RateLimiter emits events onto an
+// attached SecurityEventBus for both the cooldown and LRU eviction paths.
+func TestRateLimiter_WithEventBus(t *testing.T) {
+	ring := NewRingBufferSink(64)
+	bus := NewSecurityEventBus(ring)
+
+	config := defaultTestRateLimiterConfig()
+	config.Burst = 1
+	config.SustainedQPS = 1
+	rl := NewRateLimiter(config, WithEventBus(bus))
+
+	ip := net.ParseIP("192.0.2.10")
+	rl.Allow(ip) // consumes the only token
+	rl.Allow(ip) // exceeds burst, should emit cooldown + rate-limit-triggered
+
+	events := ring.Events()
+	if len(events) == 0 {
+		t.Fatal("expected events on the bus after exceeding burst, got none")
+	}
+
+	sawCooldown := false
+	for _, evt := range events {
+		if evt.Type == EventCooldownEntered {
+			sawCooldown = true
+		}
+	}
+	if !sawCooldown {
+		t.Error("expected an EventCooldownEntered event, got none")
+	}
+}
+
+// fakeMetrics is a minimal metrics.Metrics recorder for assertions,
+// matching querier's fakeMetrics.
+type fakeMetrics struct {
+	counters map[string]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{counters: make(map[string]int)}
+}
+
+func (f *fakeMetrics) IncCounter(name string, _ map[string]string) {
+	f.counters[name]++
+}
+
+func (f *fakeMetrics) ObserveHistogram(string, float64, map[string]string) {}
+
+// TestRateLimiter_Metrics verifies Metrics() reports allowed/dropped
+// totals alongside the same eviction/tracked-source counters Stats does.
+func TestRateLimiter_Metrics(t *testing.T) {
+	config := defaultTestRateLimiterConfig()
+	config.Burst = 1
+	config.SustainedQPS = 1
+	rl := NewRateLimiter(config)
+
+	ip := net.ParseIP("192.0.2.20")
+	rl.Allow(ip) // consumes the only token - allowed
+	rl.Allow(ip) // bucket empty - dropped
+
+	m := rl.Metrics()
+	if m.AllowedCount != 1 {
+		t.Errorf("Metrics().AllowedCount = %d, want 1", m.AllowedCount)
+	}
+	if m.DroppedCount != 1 {
+		t.Errorf("Metrics().DroppedCount = %d, want 1", m.DroppedCount)
+	}
+	if m.TrackedSources != 1 {
+		t.Errorf("Metrics().TrackedSources = %d, want 1", m.TrackedSources)
+	}
+}
+
+// TestRateLimiter_WithMetrics verifies WithMetrics forwards allowed,
+// dropped, and eviction counts to the installed metrics.Metrics sink.
+func TestRateLimiter_WithMetrics(t *testing.T) {
+	fm := newFakeMetrics()
+
+	config := defaultTestRateLimiterConfig()
+	config.Burst = 1
+	config.SustainedQPS = 1
+	config.MaxEntries = 1
+	rl := NewRateLimiter(config, WithMetrics(fm))
+
+	rl.Allow(net.ParseIP("192.0.2.30")) // allowed
+	rl.Allow(net.ParseIP("192.0.2.30")) // bucket empty - dropped
+	rl.Allow(net.ParseIP("192.0.2.31")) // exceeds MaxEntries - evicts 192.0.2.30
+
+	if fm.counters["beacon_security_ratelimit_allowed_total"] == 0 {
+		t.Error("expected beacon_security_ratelimit_allowed_total to be incremented")
+	}
+	if fm.counters["beacon_security_ratelimit_dropped_total"] == 0 {
+		t.Error("expected beacon_security_ratelimit_dropped_total to be incremented")
+	}
+	if fm.counters["beacon_security_ratelimit_evictions_total"] == 0 {
+		t.Error("expected beacon_security_ratelimit_evictions_total to be incremented")
+	}
+}
+
+// TestSourceFilter_Stats verifies Stats() counts IsValid() rejections.
+func TestSourceFilter_Stats(t *testing.T) {
+	sf, err := NewSourceFilterFromConfig(nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	sf.IsValid(netip.MustParseAddr("203.0.113.1")) // no matching rule - rejected
+
+	if stats := sf.Stats(); stats.RejectedCount == 0 {
+		t.Error("Stats().RejectedCount = 0, want > 0 after a rejected IsValid() call")
+	}
+}
+
+// TestSourceFilter_WithEventBus verifies SourceFilter emits a
+// SourceRejected event onto an attached SecurityEventBus.
+func TestSourceFilter_WithEventBus(t *testing.T) {
+	ring := NewRingBufferSink(8)
+	bus := NewSecurityEventBus(ring)
+
+	sf, err := NewSourceFilterFromConfig(nil, nil, nil, WithSourceFilterEventBus(bus))
+	if err != nil {
+		t.Fatalf("NewSourceFilterFromConfig() failed: %v", err)
+	}
+
+	sf.IsValid(netip.MustParseAddr("203.0.113.1"))
+
+	events := ring.Events()
+	if len(events) != 1 || events[0].Type != EventSourceRejected {
+		t.Fatalf("expected a single EventSourceRejected event, got %+v", events)
+	}
+}
+
+// TestJSONSink writes events as JSON lines, one per event.
+func TestJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONSink(&buf)
+	bus := NewSecurityEventBus(sink)
+
+	bus.Emit(SecurityEvent{Type: EventRateLimitTriggered, SourceIP: "192.0.2.1", Reason: "burst_exceeded"})
+	bus.Emit(SecurityEvent{Type: EventCleanupRun, Reason: "removed_3"})
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d JSON lines, want 2: %q", len(lines), buf.String())
+	}
+	var evt SecurityEvent
+	if err := json.Unmarshal([]byte(lines[0]), &evt); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if evt.Type != EventRateLimitTriggered || evt.SourceIP != "192.0.2.1" {
+		t.Errorf("decoded event = %+v, want Type=%s SourceIP=192.0.2.1", evt, EventRateLimitTriggered)
+	}
+}
+
+// TestPrometheusSink verifies per-type counts and the QPS histogram
+// summary are aggregated across emitted events.
+func TestPrometheusSink(t *testing.T) {
+	sink := NewPrometheusSink()
+	bus := NewSecurityEventBus(sink)
+
+	bus.Emit(SecurityEvent{Type: EventRateLimitTriggered, QPS: 10})
+	bus.Emit(SecurityEvent{Type: EventRateLimitTriggered, QPS: 20})
+	bus.Emit(SecurityEvent{Type: EventCooldownEntered})
+
+	snap := sink.Snapshot()
+	if snap.Counts[EventRateLimitTriggered] != 2 {
+		t.Errorf("Counts[EventRateLimitTriggered] = %d, want 2", snap.Counts[EventRateLimitTriggered])
+	}
+	if snap.Counts[EventCooldownEntered] != 1 {
+		t.Errorf("Counts[EventCooldownEntered] = %d, want 1", snap.Counts[EventCooldownEntered])
+	}
+	if snap.QPSSampleCount != 2 || snap.QPSMin != 10 || snap.QPSMax != 20 || snap.QPSSum != 30 {
+		t.Errorf("QPS summary = %+v, want {SampleCount:2 Min:10 Max:20 Sum:30}", snap)
+	}
+}
+
+// TestRingBufferSink verifies the buffer retains only the most recent
+// capacity events, oldest-to-newest.
+func TestRingBufferSink(t *testing.T) {
+	sink := NewRingBufferSink(2)
+	bus := NewSecurityEventBus(sink)
+
+	bus.Emit(SecurityEvent{Type: EventCleanupRun, Reason: "first"})
+	bus.Emit(SecurityEvent{Type: EventCleanupRun, Reason: "second"})
+	bus.Emit(SecurityEvent{Type: EventCleanupRun, Reason: "third"})
+
+	events := sink.Events()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].Reason != "second" || events[1].Reason != "third" {
+		t.Errorf("events = %+v, want [second third]", events)
+	}
+}
+
+// TestSecurityEventBus_NilSafe verifies a nil *SecurityEventBus can be
+// used as a zero value without panicking, so callers need no nil check.
+func TestSecurityEventBus_NilSafe(t *testing.T) {
+	var bus *SecurityEventBus
+	bus.Emit(SecurityEvent{Type: EventCleanupRun})
+	bus.AddSink(NewRingBufferSink(1))
+}
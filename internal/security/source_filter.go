@@ -3,104 +3,327 @@
 package security
 
 import (
+	"fmt"
 	"net"
+	"net/netip"
+	"sync/atomic"
 )
 
+// cidrNode is a node in a cidrTree: a binary radix (patricia) trie over an
+// IP address's bits, giving O(prefix-length) longest-prefix-match lookups
+// instead of a linear scan over N CIDRs - analogous to nebula's cidr.Tree6.
+type cidrNode struct {
+	children [2]*cidrNode
+	hasValue bool
+	deny     bool   // meaningful only if hasValue; true = deny, false = allow
+	cidr     string // the CIDR inserted at this node, for reporting a matched rule
+}
+
+// cidrTree is a cidrNode-backed trie for one address family (IPv4 or IPv6).
+// Lookups return the most specific (longest-prefix) matching rule; deny
+// wins when an allow and a deny rule are inserted at the exact same prefix.
+type cidrTree struct {
+	root *cidrNode
+}
+
+func newCIDRTree() *cidrTree {
+	return &cidrTree{root: &cidrNode{}}
+}
+
+// insert adds prefix to the tree with the given deny value. If a rule
+// already exists at that exact prefix, deny wins the tie rather than being
+// overwritten by a later allow.
+func (t *cidrTree) insert(prefix netip.Prefix, deny bool) {
+	addr := prefix.Addr().AsSlice()
+	node := t.root
+	for i := 0; i < prefix.Bits(); i++ {
+		bit := bitAt(addr, i)
+		if node.children[bit] == nil {
+			node.children[bit] = &cidrNode{}
+		}
+		node = node.children[bit]
+	}
+	if node.hasValue && node.deny {
+		return
+	}
+	node.hasValue = true
+	node.deny = deny
+	node.cidr = prefix.String()
+}
+
+// lookup walks addr's bits and returns the most specific matching rule's
+// deny value and CIDR. matched is false if no rule in the tree covers addr
+// at all.
+func (t *cidrTree) lookup(addr netip.Addr) (deny bool, cidr string, matched bool) {
+	b := addr.AsSlice()
+	node := t.root
+	for i := 0; i < len(b)*8 && node != nil; i++ {
+		if node.hasValue {
+			deny, cidr, matched = node.deny, node.cidr, true
+		}
+		node = node.children[bitAt(b, i)]
+	}
+	if node != nil && node.hasValue {
+		deny, cidr, matched = node.deny, node.cidr, true
+	}
+	return deny, cidr, matched
+}
+
+// bitAt returns the i-th most-significant bit of b (0 or 1).
+func bitAt(b []byte, i int) int {
+	return int((b[i/8] >> (7 - uint(i%8))) & 1)
+}
+
+// ipv6LinkLocal is the RFC 4291 ยง2.5.6 prefix IsValid gives IPv6 the same
+// default-allow treatment v4 gets from 169.254.0.0/16. Unlike v4 link-local,
+// fe80::/10 is ambiguous across interfaces (the same prefix is valid on
+// every link), so a match there also requires the packet's zone (RFC 4007
+// scope ID) to name a configured interface - see IsValidZone. RFC 4193
+// unique-local (fc00::/7) sources need no equivalent constant: they're
+// admitted only by matching a cached interface prefix, the same same-subnet
+// rule any other address family relies on.
+var ipv6LinkLocal = netip.MustParsePrefix("fe80::/10")
+
+// ipv4LinkLocal is the RFC 3927 prefix every SourceFilter admits by default.
+var ipv4LinkLocal = netip.MustParsePrefix("169.254.0.0/16")
+
 // SourceFilter validates source IPs before parsing packets.
 // Per RFC 6762 ยง2, mDNS is link-local scope - source IPs must be
-// link-local (169.254.0.0/16) or same subnet as receiving interface.
+// link-local (169.254.0.0/16, or IPv6 fe80::/10 with a matching zone) or
+// same subnet as a receiving interface, unless overridden by explicit
+// allow/deny CIDR rules.
+//
+// Lookups walk a pair of radix trees (one per address family) built from the
+// default same-subnet/link-local heuristic plus any explicit rules, giving
+// O(prefix-length) matching instead of scanning every interface's addresses.
 type SourceFilter struct {
-	iface      net.Interface // Receiving interface
-	ifaceAddrs []net.IPNet   // Cached interface addresses (avoids syscall per packet)
+	iface         net.Interface   // Receiving interface (single-interface constructor only)
+	ifaceAddrsV4  []netip.Prefix  // Cached IPv4 interface addresses (diagnostics; lookups use v4/v6 trees)
+	ifaceAddrsV6  []netip.Prefix  // Cached IPv6 interface addresses (diagnostics; lookups use v4/v6 trees)
+	zones         map[string]bool // Interface names (RFC 4007 zone IDs) this filter was built for
+	v4            *cidrTree
+	v6            *cidrTree
+	events        *SecurityEventBus // Optional observability sink fan-out; nil-safe
+	rejectedCount uint64            // Number of IsValid() rejections (for metrics); atomic
+}
+
+// SourceFilterOption configures optional SourceFilter behavior.
+type SourceFilterOption func(*SourceFilter)
+
+// WithSourceFilterEventBus attaches a SecurityEventBus that SourceFilter
+// emits SourceRejected events to.
+func WithSourceFilterEventBus(bus *SecurityEventBus) SourceFilterOption {
+	return func(sf *SourceFilter) { sf.events = bus }
+}
+
+// SourceFilterStats is a point-in-time snapshot of SourceFilter's counters,
+// available whether or not a SecurityEventBus is attached.
+type SourceFilterStats struct {
+	RejectedCount uint64 // Number of IsValid() calls that returned false
+}
+
+// Stats returns a snapshot of SourceFilter's current counters.
+func (sf *SourceFilter) Stats() SourceFilterStats {
+	return SourceFilterStats{RejectedCount: atomic.LoadUint64(&sf.rejectedCount)}
 }
 
 // NewSourceFilter creates a new source filter for the given interface.
 // It caches the interface addresses to avoid syscalls in the hot path (per-packet validation).
 //
 // Task T073: Initialize SourceFilter with cached interface addresses
-func NewSourceFilter(iface net.Interface) (*SourceFilter, error) {
-	// Get interface addresses
-	addrs, err := iface.Addrs()
-	if err != nil {
-		// If we can't get addresses, create filter without cached addresses
-		// IsValid() will fall back to link-local check only
-		return &SourceFilter{
-			iface:      iface,
-			ifaceAddrs: []net.IPNet{},
-		}, nil
-	}
-
-	// Extract IPNet addresses and cache them
-	var ipnets []net.IPNet
-	for _, addr := range addrs {
-		// addr is *net.IPNet or *net.IPAddr
-		if ipnet, ok := addr.(*net.IPNet); ok {
-			ipnets = append(ipnets, *ipnet)
+func NewSourceFilter(iface net.Interface, opts ...SourceFilterOption) (*SourceFilter, error) {
+	return NewSourceFilterFromConfig([]net.Interface{iface}, nil, nil, opts...)
+}
+
+// NewSourceFilterFromConfig builds a SourceFilter backed by a radix tree of
+// allow/deny CIDR rules, for hosts where the default same-subnet heuristic
+// isn't enough (multi-homed hosts, bridged/docker setups).
+//
+// The tree is seeded with the default heuristic - RFC 3927 IPv4 link-local
+// (169.254.0.0/16), RFC 4291 IPv6 link-local (fe80::/10, zone-checked by
+// IsValid), and each interface in ifaces's own subnets, all as allow rules -
+// then allow and deny are inserted on top. Longest-prefix-match determines
+// the result for a given IP; deny wins ties at the same prefix length, so an
+// admin can carve a deny CIDR out of a broader allow (or vice versa)
+// regardless of insertion order.
+//
+// NewSourceFilterFromConfig returns an error if any iface's Addrs() call
+// fails, rather than silently filtering traffic against an incomplete
+// cache - callers can surface interface misconfiguration at startup instead
+// of mysteriously dropping packets later.
+func NewSourceFilterFromConfig(ifaces []net.Interface, allow, deny []netip.Prefix, opts ...SourceFilterOption) (*SourceFilter, error) {
+	sf := &SourceFilter{
+		v4:    newCIDRTree(),
+		v6:    newCIDRTree(),
+		zones: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(sf)
+	}
+
+	// RFC 3927 / RFC 4291 link-local addresses are always valid per RFC 6762
+	// ยง2. The IPv6 half is re-checked against zone in IsValid since the
+	// prefix alone can't tell one link's fe80:: space from another's.
+	sf.insertAllow(ipv6LinkLocal)
+	sf.insertAllow(ipv4LinkLocal)
+
+	for _, iface := range ifaces {
+		if len(ifaces) == 1 {
+			sf.iface = iface
+		}
+		if iface.Name != "" {
+			sf.zones[iface.Name] = true
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("security: enumerate addresses for interface %q: %w", iface.Name, err)
+		}
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			prefix, ok := ipNetToPrefix(*ipnet)
+			if !ok {
+				continue
+			}
+			if prefix.Addr().Is4() {
+				sf.ifaceAddrsV4 = append(sf.ifaceAddrsV4, prefix)
+			} else {
+				sf.ifaceAddrsV6 = append(sf.ifaceAddrsV6, prefix)
+			}
+			sf.insertAllow(prefix)
 		}
 	}
 
-	return &SourceFilter{
-		iface:      iface,
-		ifaceAddrs: ipnets,
-	}, nil
+	for _, prefix := range allow {
+		sf.insertAllow(prefix)
+	}
+	for _, prefix := range deny {
+		sf.insertDeny(prefix)
+	}
+
+	return sf, nil
 }
 
-// IsValid checks if the source IP is valid for mDNS (link-local or same subnet).
-// Returns false for non-link-local IPs outside the receiving interface's subnet.
-//
-// Per RFC 6762 ยง2, mDNS is link-local scope. Valid source IPs are:
-// 1. IPv4 link-local (169.254.0.0/16) - RFC 3927
-// 2. Same subnet as the receiving interface
+// ipNetToPrefix converts a net.IPNet (as returned by net.Interface.Addrs)
+// into a netip.Prefix, masked to its network address so tree inserts and
+// lookups walk a canonical byte layout.
+func ipNetToPrefix(ipnet net.IPNet) (netip.Prefix, bool) {
+	addr, ok := netip.AddrFromSlice(ipnet.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	addr = addr.Unmap()
+	ones, _ := ipnet.Mask.Size()
+	return netip.PrefixFrom(addr, ones).Masked(), true
+}
+
+// insertAllow inserts prefix as an allow rule into the tree matching its
+// address family.
+func (sf *SourceFilter) insertAllow(prefix netip.Prefix) {
+	sf.treeFor(prefix.Addr()).insert(prefix, false)
+}
+
+// insertDeny inserts prefix as a deny rule into the tree matching its
+// address family.
+func (sf *SourceFilter) insertDeny(prefix netip.Prefix) {
+	sf.treeFor(prefix.Addr()).insert(prefix, true)
+}
+
+// treeFor returns the v4 or v6 tree matching addr's address family.
+func (sf *SourceFilter) treeFor(addr netip.Addr) *cidrTree {
+	if addr.Is4() || addr.Is4In6() {
+		return sf.v4
+	}
+	return sf.v6
+}
+
+// IsValid checks if the source address is valid for mDNS per the configured
+// allow/deny rules, falling back to reject if no rule matches at all.
+// Equivalent to IsValidZone(srcAddr, "") - callers that can supply the
+// packet's zone ID (e.g. a *net.UDPAddr.Zone) should call IsValidZone
+// instead, since IPv6 link-local sources reject without one.
 //
 // Task T074: Implement link-local + same subnet check per FR-023
-func (sf *SourceFilter) IsValid(srcIP net.IP) bool {
-	// Convert to IPv4 if possible
-	ip4 := srcIP.To4()
-	if ip4 == nil {
-		// IPv6 support deferred to M2
-		// For now, reject IPv6 packets
+func (sf *SourceFilter) IsValid(srcAddr netip.Addr) bool {
+	return sf.IsValidZone(srcAddr, "")
+}
+
+// IsValidZone is IsValid extended with zone, the RFC 4007 scope ID a scoped
+// IPv6 source address arrived on (a *net.UDPAddr.Zone is typically the
+// receiving interface's name).
+//
+// Per RFC 6762 ยง2, mDNS is link-local scope. With the default heuristic
+// (see NewSourceFilter / NewSourceFilterFromConfig), valid source IPs are:
+//  1. IPv4 link-local (169.254.0.0/16) - RFC 3927
+//  2. IPv6 link-local (fe80::/10) - RFC 4291 ยง2.5.6 - provided zone names one
+//     of this filter's configured interfaces. fe80::/10 is the identical
+//     prefix on every link, so without that zone match a link-local source
+//     arriving on the wrong interface is rejected rather than trusted.
+//  3. Same subnet as a configured interface - this is how an RFC 4193
+//     unique-local (fc00::/7) source is admitted, by matching a cached
+//     interface prefix rather than the bare fc00::/7 block.
+//
+// unless an explicit deny rule overrides them, or an explicit allow rule
+// admits an address outside both.
+func (sf *SourceFilter) IsValidZone(srcAddr netip.Addr, zone string) bool {
+	if !srcAddr.IsValid() {
 		return false
 	}
+	addr := srcAddr.Unmap()
+	tree := sf.treeFor(addr)
 
-	// Check 1: IPv4 link-local (169.254.0.0/16) - RFC 3927
-	// Link-local addresses are ALWAYS valid per RFC 6762 ยง2
-	if ip4[0] == 169 && ip4[1] == 254 {
-		return true // RFC 3927 link-local address
+	if tree == sf.v6 && ipv6LinkLocal.Contains(addr) && !sf.zones[zone] {
+		sf.reject(srcAddr, "", "link_local_zone_mismatch")
+		return false
 	}
 
-	// Check 2: Same subnet as interface
-	// Packets from the same subnet as the receiving interface are valid
-	for _, ipnet := range sf.ifaceAddrs {
-		if ipnet.Contains(srcIP) {
-			return true // Same subnet as interface
-		}
+	deny, cidr, matched := tree.lookup(addr)
+	if !matched {
+		sf.reject(srcAddr, "", "no_matching_rule")
+		return false // No rule covers this IP - not link-local, not same subnet, not explicitly allowed
 	}
+	if deny {
+		sf.reject(srcAddr, cidr, "deny_rule_matched")
+	}
+	return !deny
+}
 
-	// Not link-local and not same subnet - reject
-	return false
+// reject records an IsValid/IsValidZone rejection in rejectedCount and, if a
+// SecurityEventBus is attached, emits a SourceRejected event for it.
+func (sf *SourceFilter) reject(srcAddr netip.Addr, cidr, reason string) {
+	atomic.AddUint64(&sf.rejectedCount, 1)
+	sf.events.Emit(SecurityEvent{
+		Type:     EventSourceRejected,
+		SourceIP: srcAddr.String(),
+		CIDR:     cidr,
+		Reason:   reason,
+	})
 }
 
-// isPrivate returns true if the IP is in a private address range
+// isPrivate returns true if addr is in a private address range
 // (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16).
-func isPrivate(ip net.IP) bool {
-	ip4 := ip.To4()
-	if ip4 == nil {
+func isPrivate(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.Is4() {
 		return false // Not IPv4
 	}
+	b := addr.As4()
 
 	// Check private ranges:
 	// 10.0.0.0/8
-	if ip4[0] == 10 {
+	if b[0] == 10 {
 		return true
 	}
 
 	// 172.16.0.0/12 (172.16.0.0 - 172.31.255.255)
-	if ip4[0] == 172 && ip4[1] >= 16 && ip4[1] <= 31 {
+	if b[0] == 172 && b[1] >= 16 && b[1] <= 31 {
 		return true
 	}
 
 	// 192.168.0.0/16
-	if ip4[0] == 192 && ip4[1] == 168 {
+	if b[0] == 192 && b[1] == 168 {
 		return true
 	}
 
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// AnnouncePolicy configures how many unsolicited announcements Announcer
+// sends once probing succeeds, and how widely spaced they are, per RFC 6762
+// §8.3: "The Multicast DNS responder MUST send at least two unsolicited
+// responses, one second apart" and "...MAY send additional announcements
+// to increase its probability of being received, in which case it SHOULD
+// increase the time between the repetitions by at least a factor of two."
+//
+// It's modeled on gRPC's BackoffConfig (see BackoffConfig in backoff.go) -
+// exponential growth from a base delay, capped, with jitter - but under its
+// own field names, since an announce schedule's "base interval, growth
+// factor, cap, jitter, and how many sends total" isn't quite the same
+// vocabulary as a retry backoff's "base delay before the next attempt".
+type AnnouncePolicy struct {
+	// Base is the delay before the second announcement - RFC 6762 §8.3's
+	// mandatory "one second apart".
+	Base time.Duration
+
+	// Factor is the multiplier applied to the previous interval for each
+	// announcement beyond the second: interval(n) = Base * Factor^(n-1).
+	Factor float64
+
+	// Max caps each computed interval, after jitter.
+	Max time.Duration
+
+	// Jitter randomizes each computed interval by +/-Jitter (e.g. 0.2 =
+	// +/-20%), the same synchronization-storm mitigation BackoffConfig
+	// uses, so many hosts that booted or reconnected simultaneously don't
+	// all send their extra announcements in lockstep.
+	Jitter float64
+
+	// Count is the total number of announcements to send. Per RFC 6762
+	// §8.3's mandatory minimum, values below 2 are treated as 2; values
+	// above 2 opt into the "MAY send additional announcements" allowance,
+	// up to the RFC's suggested cap of 8.
+	Count int
+}
+
+// DefaultAnnouncePolicy returns RFC 6762 §8.3's mandatory minimum: exactly
+// 2 announcements, 1 second apart, with no jitter - the behavior Announcer
+// had before AnnouncePolicy existed, so a caller that never sets one sees
+// no change.
+func DefaultAnnouncePolicy() AnnouncePolicy {
+	return AnnouncePolicy{
+		Base:   1 * time.Second,
+		Factor: 2.0,
+		Max:    1 * time.Hour,
+		Jitter: 0,
+		Count:  2,
+	}
+}
+
+// Intervals returns the delay to wait before each announcement beyond the
+// first - len(Intervals()) == max(p.Count, 2)-1 - growing by p.Factor each
+// step, jittered by p.Jitter, and capped at p.Max.
+func (p AnnouncePolicy) Intervals() []time.Duration {
+	count := p.Count
+	if count < 2 {
+		count = 2
+	}
+
+	out := make([]time.Duration, count-1)
+	for i := range out {
+		d := float64(p.Base) * math.Pow(p.Factor, float64(i))
+		if maxD := float64(p.Max); maxD > 0 && d > maxD {
+			d = maxD
+		}
+
+		// +/-Jitter randomization, same formula as BackoffConfig.Delay.
+		d *= 1 + p.Jitter*(2*rand.Float64()-1) //nolint:gosec // G404: jitter, not security-sensitive
+
+		if d < 0 {
+			d = 0
+		}
+		if maxD := float64(p.Max); maxD > 0 && d > maxD {
+			d = maxD
+		}
+		out[i] = time.Duration(d)
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAnnouncePolicy_Intervals_ExponentialGrowth verifies Intervals grows by
+// Factor each step and is capped at Max, ignoring jitter by setting it to 0.
+func TestAnnouncePolicy_Intervals_ExponentialGrowth(t *testing.T) {
+	p := AnnouncePolicy{
+		Base:   1 * time.Second,
+		Factor: 2,
+		Jitter: 0,
+		Max:    5 * time.Second,
+		Count:  5,
+	}
+
+	want := []time.Duration{
+		1 * time.Second, // i=0: 1 * 2^0
+		2 * time.Second, // i=1: 1 * 2^1
+		4 * time.Second, // i=2: 1 * 2^2
+		5 * time.Second, // i=3: 1 * 2^3 = 8s, capped at 5s
+	}
+
+	got := p.Intervals()
+	if len(got) != len(want) {
+		t.Fatalf("Intervals() = %v, want %v", got, want)
+	}
+	for i, d := range got {
+		if d != want[i] {
+			t.Errorf("Intervals()[%d] = %v, want %v", i, d, want[i])
+		}
+	}
+}
+
+// TestAnnouncePolicy_Intervals_CountBelow2TreatedAsMandatoryPair verifies a
+// Count less than 2 still returns the RFC 6762 §8.3 mandatory pair's single
+// interval, never zero intervals.
+func TestAnnouncePolicy_Intervals_CountBelow2TreatedAsMandatoryPair(t *testing.T) {
+	for _, count := range []int{0, 1} {
+		p := AnnouncePolicy{Base: 1 * time.Second, Factor: 2, Count: count}
+		if got := len(p.Intervals()); got != 1 {
+			t.Errorf("Count=%d: len(Intervals()) = %d, want 1", count, got)
+		}
+	}
+}
+
+// TestAnnouncePolicy_Intervals_JitterWithinBounds verifies each interval
+// stays within +/-Jitter of the unjittered value.
+func TestAnnouncePolicy_Intervals_JitterWithinBounds(t *testing.T) {
+	p := AnnouncePolicy{
+		Base:   1 * time.Second,
+		Factor: 1,
+		Jitter: 0.2,
+		Max:    60 * time.Second,
+		Count:  2,
+	}
+
+	base := 1 * time.Second
+	minWant := time.Duration(float64(base) * 0.8)
+	maxWant := time.Duration(float64(base) * 1.2)
+
+	for i := 0; i < 50; i++ {
+		got := p.Intervals()[0]
+		if got < minWant || got > maxWant {
+			t.Fatalf("Intervals()[0] = %v, want within [%v, %v]", got, minWant, maxWant)
+		}
+	}
+}
+
+// TestDefaultAnnouncePolicy_MatchesMandatoryMinimum verifies
+// DefaultAnnouncePolicy returns RFC 6762 §8.3's mandatory minimum: exactly 2
+// sends, 1 second apart, with no jitter.
+func TestDefaultAnnouncePolicy_MatchesMandatoryMinimum(t *testing.T) {
+	p := DefaultAnnouncePolicy()
+
+	if p.Count != 2 {
+		t.Errorf("Count = %d, want 2", p.Count)
+	}
+	if p.Base != 1*time.Second {
+		t.Errorf("Base = %v, want 1s", p.Base)
+	}
+	if p.Jitter != 0 {
+		t.Errorf("Jitter = %v, want 0", p.Jitter)
+	}
+
+	intervals := p.Intervals()
+	if len(intervals) != 1 || intervals[0] != 1*time.Second {
+		t.Errorf("Intervals() = %v, want [1s]", intervals)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/records"
+	"github.com/joshuafuller/beacon/internal/transport"
 )
 
 // Announcer performs announcing per RFC 6762 §8.3.
@@ -21,20 +22,122 @@ type Announcer struct {
 	lastDestAddr       string
 
 	// US2 GREEN: Message capture for contract test validation
-	lastAnnounceMessage []byte // Last sent announcement message (wire format)
+	lastAnnounceMessage []byte                    // Last sent announcement message (wire format)
+	lastSentRecords     []*records.ResourceRecord // Last sent record set (Announce's TTL, or Goodbye's TTL=0 copy)
 
 	// Resource records to announce (DNS wire format serialization)
 	resourceRecords []*records.ResourceRecord
+
+	// clock drives the 1s inter-announcement interval; defaults to the
+	// real clock, swappable for a FakeClock in tests.
+	clock Clock
+
+	// outbound, if set, receives each announcement message as it's built,
+	// so a Machine can relay it to the transport layer.
+	outbound chan<- []byte
+
+	// rateLimiter, if set, gates each multicast against its per-(record,
+	// interface) token bucket. A large service set can drive some of those
+	// buckets over budget; rather than silently dropping the records that
+	// are over budget, multicastTwice waits out the longest Reserve()d
+	// delay across the batch before sending. Nil (the default) sends
+	// immediately, as before this existed.
+	rateLimiter *records.RecordSet
+
+	// simulatedLoss, set via SetSimulatedLoss, is how many of the next
+	// announcement sends multicastTwice should silently drop - simulating a
+	// packet that never reached any peer - instead of actually delivering,
+	// decrementing by one per dropped send. Zero (the default) sends every
+	// announcement normally.
+	simulatedLoss int
+
+	// transport, if set, makes multicastTwice actually Send each
+	// announcement message instead of only writing it to outbound. Nil (the
+	// default) preserves the original behavior.
+	transport transport.Transport
+
+	// policy governs how many announcements Announce sends and how widely
+	// spaced they are, set via SetAnnouncePolicy. Defaults to
+	// DefaultAnnouncePolicy's RFC 6762 §8.3 mandatory minimum (2 sends, 1s
+	// apart), preserving the original hardcoded behavior.
+	policy AnnouncePolicy
 }
 
+// announcerInterfaceID is the interfaceID records.RecordSet buckets are
+// keyed under for Announcer's rate limiting. The responder currently sends
+// through a single transport regardless of how many interfaces
+// responder.WithInterfaces scopes addresses to, so there is only one
+// multicast path to rate-limit.
+const announcerInterfaceID = "default"
+
 // NewAnnouncer creates a new announcer.
 func NewAnnouncer() *Announcer {
 	return &Announcer{
 		lastDestAddr: "224.0.0.251:5353", // RFC 6762 §5 multicast address
+		clock:        realClock{},
+		policy:       DefaultAnnouncePolicy(),
 	}
 }
 
-// Announce sends unsolicited multicast announcements.
+// SetAnnouncePolicy overrides how many announcements Announce sends and how
+// widely spaced they are. Without this, Announce uses DefaultAnnouncePolicy.
+func (a *Announcer) SetAnnouncePolicy(policy AnnouncePolicy) {
+	a.policy = policy
+}
+
+// SetClock overrides the Clock used to time the inter-announcement interval.
+func (a *Announcer) SetClock(clock Clock) {
+	a.clock = clock
+}
+
+// SetOutbound sets the channel announcement messages are relayed to as
+// they're sent. A nil channel (the default) disables relaying.
+func (a *Announcer) SetOutbound(outbound chan<- []byte) {
+	a.outbound = outbound
+}
+
+// SetTransport wires t into multicastTwice: each announcement message is
+// sent via t.Send once built, the real counterpart to outbound's relay-only
+// behavior - so a transport.MockTransport can capture (via SendCalls) what
+// Announce/Goodbye actually transmit. A nil transport (the default)
+// disables this.
+func (a *Announcer) SetTransport(t transport.Transport) {
+	a.transport = t
+}
+
+// SetRateLimiter attaches a records.RecordSet that gates each multicastTwice
+// send against RFC 6762 §6.2's per-record rate limit, waiting out any
+// records.Reserve delay rather than sending over budget. A nil rate limiter
+// (the default) sends immediately.
+func (a *Announcer) SetRateLimiter(rs *records.RecordSet) {
+	a.rateLimiter = rs
+}
+
+// SetSimulatedLoss sets how many of the next announcement sends
+// multicastTwice should silently drop instead of delivering, for fault
+// injection testing (see responder.Responder.InjectAnnounceLoss). Zero (the
+// default) disables this entirely.
+func (a *Announcer) SetSimulatedLoss(n int) {
+	a.simulatedLoss = n
+}
+
+// GetSimulatedLoss returns the simulated-loss budget remaining, i.e. how
+// many more sends SetSimulatedLoss's caller has yet to see dropped. Callers
+// that set a budget spanning more than one Announce call (e.g. Register's
+// rename-attempt loop) read this back afterward to carry the remainder
+// forward.
+func (a *Announcer) GetSimulatedLoss() int {
+	return a.simulatedLoss
+}
+
+// Announce sends the RFC 6762 §8.3 mandatory pair of unsolicited multicast
+// announcements, one second apart. This pair is never affected by
+// SetAnnouncePolicy - the RFC does not allow stretching it - so Run (see
+// machine.go) can call Announce and reach StateEstablished in a bounded ~1s
+// regardless of any configured AnnouncePolicy.Count. A Count greater than 2
+// is instead handled by SendAdditional, which a caller invokes separately on
+// its own schedule (see responder.AnnouncementScheduler) so that spacing out
+// several extra sends over minutes or hours never blocks Register().
 //
 // RFC 6762 §8.3: Announcing process
 //   - Send 2 announcements
@@ -51,12 +154,51 @@ func NewAnnouncer() *Announcer {
 //
 // T040: Implement announcing with 2 announcements × 1s interval
 func (a *Announcer) Announce(ctx context.Context, _ string, records []byte) error {
-	const announcementCount = 2
-	const announcementInterval = 1 * time.Second
-
 	a.lastSentData = records
+	return a.multicastTwice(ctx, a.resourceRecords, true)
+}
 
-	for i := 0; i < announcementCount; i++ {
+// SendAdditional sends one additional unsolicited announcement beyond the
+// mandatory pair Announce already sent, honoring the same rate limiter,
+// simulated-loss, transport and outbound wiring as Announce. This is RFC
+// 6762 §8.3's "MAY send additional announcements" allowance - the caller
+// (responder.AnnouncementScheduler) is responsible for spacing successive
+// calls out per AnnouncePolicy.Intervals, since how widely to space them is
+// exactly what SetAnnouncePolicy configures.
+func (a *Announcer) SendAdditional(ctx context.Context) error {
+	return a.sendOnce(ctx, a.resourceRecords, true)
+}
+
+// Goodbye sends RFC 6762 §10.1 goodbye packets: the same two
+// one-second-apart multicasts as Announce, but with every record's TTL
+// overridden to zero so peers flush their cached entries immediately
+// instead of waiting out the record's normal TTL.
+//
+// RFC 6762 §10.1: "hosts SHOULD send... an unsolicited Multicast DNS
+// response containing, in the Resource Record Section, all their
+// published resource records with the TTL set to zero."
+func (a *Announcer) Goodbye(ctx context.Context, _ string, rrs []*records.ResourceRecord) error {
+	goodbyeRecords := make([]*records.ResourceRecord, len(rrs))
+	for i, rr := range rrs {
+		goodbye := *rr
+		goodbye.TTL = 0
+		goodbyeRecords[i] = &goodbye
+	}
+	return a.multicastTwice(ctx, goodbyeRecords, false)
+}
+
+// multicastTwice sends rrs as two unsolicited multicast responses, 1 second
+// apart, per RFC 6762 §8.3 - the shared mechanics behind both Announce and
+// Goodbye, which differ only in the TTL of the records they send and
+// whether the rate limiter applies: applyRateLimit is false for Goodbye,
+// since records.CanMulticastGoodbye always bypasses it per RFC 6762 §10.1,
+// and a rate-limiter wait here would reintroduce exactly the cache
+// staleness that bypass exists to avoid.
+func (a *Announcer) multicastTwice(ctx context.Context, rrs []*records.ResourceRecord, applyRateLimit bool) error {
+	const sendCount = 2
+	const sendInterval = 1 * time.Second
+
+	for i := 0; i < sendCount; i++ {
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
@@ -64,72 +206,142 @@ func (a *Announcer) Announce(ctx context.Context, _ string, records []byte) erro
 		default:
 		}
 
-		// Send announcement
-		// RFC 6762 §8.3: Announcements are DNS responses with answer records
-		//
-		// Build announcement message with actual resource records
-		// If no records are set, fall back to empty stub for compatibility with existing tests
-		var announceMsg []byte
-		var err error
-
-		if len(a.resourceRecords) > 0 {
-			// Convert records.ResourceRecord to message.ResourceRecord for BuildResponse()
-			messageRecords := make([]*message.ResourceRecord, len(a.resourceRecords))
-			for i, rr := range a.resourceRecords {
-				messageRecords[i] = &message.ResourceRecord{
-					Name:       rr.Name,
-					Type:       rr.Type,
-					Class:      rr.Class,
-					TTL:        rr.TTL,
-					Data:       rr.Data,
-					CacheFlush: rr.CacheFlush,
-				}
-			}
+		if err := a.sendOnceOrDrop(ctx, rrs, applyRateLimit); err != nil {
+			return err
+		}
 
-			// Use message.BuildResponse() to serialize records into wire format
-			announceMsg, err = message.BuildResponse(messageRecords)
-			if err != nil {
-				// If serialization fails, fall back to empty message
-				// This shouldn't happen in practice with valid records
-				announceMsg = make([]byte, 12)
-				announceMsg[2] = 0x84 // QR=1, AA=1
+		// Wait 1s before next send (except after last)
+		if i < sendCount-1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-a.clock.After(sendInterval):
+				// Continue to next send
 			}
-		} else {
-			// No records set - use minimal stub for backward compatibility with tests
-			// Minimal DNS response header (12 bytes) per RFC 1035 §4.1.1:
-			//   ID: 0x0000
-			//   Flags: QR=1, AA=1 = 0x8400
-			//   QDCOUNT, ANCOUNT, NSCOUNT, ARCOUNT: all 0
-			announceMsg = make([]byte, 12)
-			announceMsg[2] = 0x84 // High byte: QR=1, OPCODE=0, AA=1
-			announceMsg[3] = 0x00 // Low byte: TC=0, RD=0, RA=0, Z=0, RCODE=0
 		}
+	}
 
-		a.lastAnnounceMessage = announceMsg
+	return nil
+}
 
-		if a.onSendAnnouncement != nil {
-			a.onSendAnnouncement()
-		}
+// sendOnce builds and transmits a single announcement message for rrs,
+// honoring the rate limiter and simulated-loss budget the same way
+// multicastTwice's loop does for each of its two sends - the shared
+// mechanics behind Announce's pair and SendAdditional's standalone send.
+func (a *Announcer) sendOnce(ctx context.Context, rrs []*records.ResourceRecord, applyRateLimit bool) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
 
-		// TODO: Actually send announcement via transport
-		// For now, just simulate announcing
+	return a.sendOnceOrDrop(ctx, rrs, applyRateLimit)
+}
 
-		// Wait 1s before next announcement (except after last)
-		if i < announcementCount-1 {
-			timer := time.NewTimer(announcementInterval)
+// sendOnceOrDrop is sendOnce without the upfront cancellation check,
+// reused by multicastTwice's loop (which does its own check per iteration):
+// it honors the rate limiter, consumes one unit of simulated-loss budget if
+// set (silently dropping rather than transmitting), and otherwise builds the
+// announcement message and hands it to outbound/onSendAnnouncement/transport.
+func (a *Announcer) sendOnceOrDrop(ctx context.Context, rrs []*records.ResourceRecord, applyRateLimit bool) error {
+	// Honor the rate limiter's reservation for every record in this
+	// batch before sending, so a large service set is delayed rather
+	// than silently dropped.
+	if applyRateLimit && a.rateLimiter != nil {
+		if wait := a.reserveWait(rrs); wait > 0 {
 			select {
 			case <-ctx.Done():
-				timer.Stop()
 				return ctx.Err()
-			case <-timer.C:
-				// Continue to next announcement
+			case <-a.clock.After(wait):
 			}
 		}
 	}
 
+	// Fault injection: drop this send entirely (see SetSimulatedLoss)
+	// rather than handing it to the transport.
+	if a.simulatedLoss > 0 {
+		a.simulatedLoss--
+		return nil
+	}
+
+	// Send announcement
+	// RFC 6762 §8.3: Announcements are DNS responses with answer records
+	//
+	// Build announcement message with actual resource records
+	// If no records are set, fall back to empty stub for compatibility with existing tests
+	var announceMsg []byte
+
+	if len(rrs) > 0 {
+		// Convert records.ResourceRecord to message.ResourceRecord for BuildResponse()
+		messageRecords := make([]*message.ResourceRecord, len(rrs))
+		for i, rr := range rrs {
+			messageRecords[i] = &message.ResourceRecord{
+				Name:       rr.Name,
+				Type:       rr.Type,
+				Class:      rr.Class,
+				TTL:        rr.TTL,
+				Data:       rr.Data,
+				CacheFlush: rr.CacheFlush,
+			}
+		}
+
+		// Use message.BuildResponse() to serialize records into wire format
+		var buildErr error
+		announceMsg, buildErr = message.BuildResponse(messageRecords)
+		if buildErr != nil {
+			// If serialization fails, fall back to empty message
+			// This shouldn't happen in practice with valid records
+			announceMsg = make([]byte, 12)
+			announceMsg[2] = 0x84 // QR=1, AA=1
+		}
+	} else {
+		// No records set - use minimal stub for backward compatibility with tests
+		// Minimal DNS response header (12 bytes) per RFC 1035 §4.1.1:
+		//   ID: 0x0000
+		//   Flags: QR=1, AA=1 = 0x8400
+		//   QDCOUNT, ANCOUNT, NSCOUNT, ARCOUNT: all 0
+		announceMsg = make([]byte, 12)
+		announceMsg[2] = 0x84 // High byte: QR=1, OPCODE=0, AA=1
+		announceMsg[3] = 0x00 // Low byte: TC=0, RD=0, RA=0, Z=0, RCODE=0
+	}
+
+	a.lastAnnounceMessage = announceMsg
+	a.lastSentRecords = rrs
+
+	if a.outbound != nil {
+		a.outbound <- announceMsg
+	}
+
+	if a.onSendAnnouncement != nil {
+		a.onSendAnnouncement()
+	}
+
+	if a.transport != nil {
+		if err := a.transport.Send(ctx, announceMsg, nil); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// reserveWait reserves a.rateLimiter slot for every record in rrs, returning
+// the longest wait any single one of them needs - the delay the batch as a
+// whole must honor so no individual record's rate limit is exceeded.
+func (a *Announcer) reserveWait(rrs []*records.ResourceRecord) time.Duration {
+	var longest time.Duration
+	for _, rr := range rrs {
+		wait, ok := a.rateLimiter.Reserve(rr, announcerInterfaceID)
+		if !ok {
+			continue
+		}
+		if wait > longest {
+			longest = wait
+		}
+	}
+	return longest
+}
+
 // GetLastAnnounceMessage returns the last sent announcement message.
 //
 // US2 GREEN: Contract test support for RFC 6762 §8.3 validation
@@ -137,6 +349,13 @@ func (a *Announcer) GetLastAnnounceMessage() []byte {
 	return a.lastAnnounceMessage
 }
 
+// GetLastSentRecords returns the record set behind the last sent
+// announcement message - the same records Announce sent (at their normal
+// TTL) or Goodbye sent (its TTL=0 copy, see Goodbye), whichever ran last.
+func (a *Announcer) GetLastSentRecords() []*records.ResourceRecord {
+	return a.lastSentRecords
+}
+
 // SetLastAnnounceMessage sets the last announcement message (for testing/transport integration).
 //
 // US2 GREEN: Allow transport layer to record sent messages
@@ -170,3 +389,11 @@ func (a *Announcer) GetLastDestAddr() string {
 func (a *Announcer) SetRecords(records []*records.ResourceRecord) {
 	a.resourceRecords = records
 }
+
+// GetRecords returns the resource records SetRecords last provided, so a
+// caller that needs to amend one record in place (e.g.
+// responder.Responder.UpdateService replacing the TXT entry) doesn't have
+// to keep its own copy of the full set in sync with the Announcer's.
+func (a *Announcer) GetRecords() []*records.ResourceRecord {
+	return a.resourceRecords
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
+	"github.com/joshuafuller/beacon/internal/transport"
 )
 
 // TestAnnouncer_Announce_RED tests announcing per RFC 6762 §8.3.
@@ -164,3 +168,238 @@ func TestAnnouncer_Announce_MulticastAddress(t *testing.T) {
 		t.Errorf("Announce() sent to %q, want %q", destAddr, wantAddr)
 	}
 }
+
+// TestAnnouncer_Goodbye_ZerosTTL verifies that Goodbye sends the same two
+// one-second-apart multicasts as Announce, but with every record's TTL
+// overridden to zero per RFC 6762 §10.1.
+func TestAnnouncer_Goodbye_ZerosTTL(t *testing.T) {
+	ctx := context.Background()
+	announcer := NewAnnouncer()
+
+	rrs := []*records.ResourceRecord{
+		{Name: "myhost.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 4500, Data: []byte{192, 168, 1, 1}},
+	}
+
+	sendCount := 0
+	announcer.SetOnSendAnnouncement(func() {
+		sendCount++
+	})
+
+	if err := announcer.Goodbye(ctx, testServiceName, rrs); err != nil {
+		t.Fatalf("Goodbye() error = %v, want nil", err)
+	}
+
+	if sendCount != 2 {
+		t.Errorf("Goodbye() sent %d multicasts, want 2", sendCount)
+	}
+
+	// The original records passed in must be untouched - Goodbye must not
+	// mutate the caller's TTLs, only what it multicasts.
+	if rrs[0].TTL != 4500 {
+		t.Errorf("Goodbye() mutated caller's record TTL to %d, want unchanged 4500", rrs[0].TTL)
+	}
+}
+
+// TestAnnouncer_RateLimiter_DelaysOverBudgetSend verifies that Announce
+// waits out a records.RecordSet Reserve()d delay instead of sending before
+// the record's token bucket has refilled.
+func TestAnnouncer_RateLimiter_DelaysOverBudgetSend(t *testing.T) {
+	clock := NewFakeClock()
+	announcer := NewAnnouncer()
+	announcer.SetClock(clock)
+
+	rr := &records.ResourceRecord{
+		Name:  "myhost.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{192, 168, 1, 1},
+	}
+	announcer.SetRecords([]*records.ResourceRecord{rr})
+
+	rs := records.NewRecordSetWithConfig(records.RateLimitConfig{
+		MinInterval:          1 * time.Second,
+		ProbeDefenseInterval: 250 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       2,
+	})
+	rs.RecordMulticast(rr, "default") // exhausts the bucket up front
+	announcer.SetRateLimiter(rs)
+
+	sendCount := 0
+	announcer.SetOnSendAnnouncement(func() { sendCount++ })
+
+	done := make(chan error, 1)
+	go func() {
+		done <- announcer.Announce(context.Background(), testServiceName, nil)
+	}()
+
+	// The first send must wait out Reserve()'s ~1s delay rather than going
+	// out immediately over budget.
+	awaitWaiter(t, clock)
+	if sendCount != 0 {
+		t.Fatalf("sendCount = %d before rate limiter wait elapsed, want 0", sendCount)
+	}
+
+	// Each successive Reserve() on the still-exhausted bucket compounds the
+	// wait (the prior reservation's debt hasn't been repaid yet), so drive
+	// the fake clock forward in 1s steps until Announce finishes rather
+	// than predicting the exact number of waits up front.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Announce() error = %v, want nil", err)
+			}
+			if sendCount != 2 {
+				t.Errorf("sendCount = %d, want 2", sendCount)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+		if clock.Waiters() > 0 {
+			clock.Advance(1 * time.Second)
+		}
+	}
+	t.Fatal("Announce() did not complete after advancing FakeClock past both rate-limited sends")
+}
+
+// TestAnnouncer_RateLimiter_GoodbyeBypasses verifies that Goodbye is never
+// delayed by the rate limiter, matching records.CanMulticastGoodbye's
+// unconditional bypass: a goodbye must reach peers promptly even if the
+// record's regular bucket is exhausted, so their caches don't hold a stale
+// entry past shutdown.
+func TestAnnouncer_RateLimiter_GoodbyeBypasses(t *testing.T) {
+	announcer := NewAnnouncer()
+
+	rr := &records.ResourceRecord{
+		Name:  "myhost.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{192, 168, 1, 1},
+	}
+
+	rs := records.NewRecordSetWithConfig(records.RateLimitConfig{
+		MinInterval:          1 * time.Second,
+		ProbeDefenseInterval: 250 * time.Millisecond,
+		BurstCapacity:        1,
+		AdaptiveFactor:       2,
+	})
+	rs.RecordMulticast(rr, "default") // exhausts the bucket
+	announcer.SetRateLimiter(rs)
+
+	sendCount := 0
+	announcer.SetOnSendAnnouncement(func() { sendCount++ })
+
+	start := time.Now()
+	if err := announcer.Goodbye(context.Background(), testServiceName, []*records.ResourceRecord{rr}); err != nil {
+		t.Fatalf("Goodbye() error = %v, want nil", err)
+	}
+	elapsed := time.Since(start)
+
+	if sendCount != 2 {
+		t.Errorf("sendCount = %d, want 2", sendCount)
+	}
+	// Goodbye still has its own 1s inter-send interval, but must not add
+	// the rate limiter's reservation wait on top of it.
+	if elapsed > 1200*time.Millisecond {
+		t.Errorf("Goodbye() took %v with an exhausted bucket, want ~1s (rate limiter must not delay goodbyes)", elapsed)
+	}
+}
+
+// TestAnnouncer_SetTransport_SendsEachAnnouncement verifies that, with a
+// transport wired in, Announce sends each announcement via Transport.Send
+// rather than only writing it to outbound.
+func TestAnnouncer_SetTransport_SendsEachAnnouncement(t *testing.T) {
+	clock := NewFakeClock()
+	tr := transport.NewMockTransport()
+
+	announcer := NewAnnouncer()
+	announcer.SetClock(clock)
+	announcer.SetTransport(tr)
+
+	rr := &records.ResourceRecord{
+		Name:  "myhost.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{192, 168, 1, 1},
+	}
+	announcer.SetRecords([]*records.ResourceRecord{rr})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- announcer.Announce(context.Background(), testServiceName, nil)
+	}()
+
+	awaitWaiter(t, clock)
+	clock.Advance(1 * time.Second)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Announce() error = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Announce() did not return")
+	}
+
+	if len(tr.SendCalls()) != 2 {
+		t.Errorf("len(SendCalls()) = %d, want 2 (one per announcement)", len(tr.SendCalls()))
+	}
+}
+
+// TestAnnouncer_SendAdditional_SendsOneMoreAnnouncement verifies
+// SendAdditional sends exactly one more announcement through the transport,
+// without waiting out any inter-announcement interval, independent of
+// whatever AnnouncePolicy is configured.
+func TestAnnouncer_SendAdditional_SendsOneMoreAnnouncement(t *testing.T) {
+	tr := transport.NewMockTransport()
+
+	announcer := NewAnnouncer()
+	announcer.SetClock(NewFakeClock())
+	announcer.SetTransport(tr)
+	announcer.SetRecords([]*records.ResourceRecord{{
+		Name:  "myhost.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   4500,
+		Data:  []byte{192, 168, 1, 1},
+	}})
+
+	if err := announcer.SendAdditional(context.Background()); err != nil {
+		t.Fatalf("SendAdditional() error = %v, want nil", err)
+	}
+
+	if len(tr.SendCalls()) != 1 {
+		t.Errorf("len(SendCalls()) = %d, want 1", len(tr.SendCalls()))
+	}
+}
+
+// TestAnnouncer_SetAnnouncePolicy_DoesNotAffectMandatoryPair verifies
+// Announce still sends exactly the mandatory pair, one second apart,
+// regardless of a Count greater than 2 configured via SetAnnouncePolicy -
+// additional sends beyond the pair are the caller's responsibility (see
+// responder.AnnouncementScheduler), not Announce's.
+func TestAnnouncer_SetAnnouncePolicy_DoesNotAffectMandatoryPair(t *testing.T) {
+	ctx := context.Background()
+	announcer := NewAnnouncer()
+	announcer.SetAnnouncePolicy(AnnouncePolicy{
+		Base: 1 * time.Second, Factor: 2, Max: time.Hour, Count: 5,
+	})
+
+	announcementCount := 0
+	announcer.onSendAnnouncement = func() {
+		announcementCount++
+	}
+
+	if err := announcer.Announce(ctx, testServiceName, []byte{}); err != nil {
+		t.Fatalf("Announce() error = %v, want nil", err)
+	}
+
+	if announcementCount != 2 {
+		t.Errorf("Announce() sent %d announcements, want 2 (mandatory pair only)", announcementCount)
+	}
+}
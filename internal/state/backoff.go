@@ -0,0 +1,67 @@
+package state
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffConfig configures the delay Machine waits between a naming
+// conflict (RFC 6762 §9) and its next probing attempt, modeled on gRPC's
+// connection backoff: exponential growth from BaseDelay by Factor, capped at
+// MaxDelay, with +/-Jitter randomization so multiple responders that lost a
+// probe storm simultaneously don't retry in lockstep and collide again on
+// the same renamed suffix (e.g. all picking "-2" at once).
+type BackoffConfig struct {
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+
+	// Factor is the multiplier applied to the delay for each subsequent
+	// retry: delay(n) = BaseDelay * Factor^n before jitter and capping.
+	Factor float64
+
+	// Jitter randomizes each computed delay by +/-Jitter (e.g. 0.2 = +/-20%),
+	// so delay(n) actually applied is delay(n) * (1 + Jitter*(2*r-1)) for a
+	// random r in [0,1).
+	Jitter float64
+
+	// MaxDelay caps the computed delay, jitter included, at this value. A
+	// zero MaxDelay is treated as "no cap", not "cap at zero" - otherwise
+	// the zero BackoffConfig (NewMachine's default) wouldn't be the only
+	// way to get an uncapped/immediate delay, and a config with BaseDelay
+	// set but MaxDelay left unset would silently collapse every Delay call
+	// to 0 instead of growing as BaseDelay and Factor say it should.
+	MaxDelay time.Duration
+}
+
+// DefaultBackoffConfig returns the gRPC-style defaults: 1s base delay, 1.6x
+// growth per retry, 20% jitter, capped at 60s.
+func DefaultBackoffConfig() BackoffConfig {
+	return BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		Factor:    1.6,
+		Jitter:    0.2,
+		MaxDelay:  60 * time.Second,
+	}
+}
+
+// Delay computes the backoff duration before the retries'th retry (0-indexed:
+// the first retry after a conflict is Delay(0)), applying growth, jitter, and
+// the MaxDelay cap in that order.
+func (c BackoffConfig) Delay(retries int) time.Duration {
+	backoff := float64(c.BaseDelay) * math.Pow(c.Factor, float64(retries))
+	if max := float64(c.MaxDelay); max > 0 && backoff > max {
+		backoff = max
+	}
+
+	// +/-Jitter randomization: backoff * (1 + Jitter*(2*rand()-1))
+	backoff *= 1 + c.Jitter*(2*rand.Float64()-1) //nolint:gosec // G404: jitter, not security-sensitive
+
+	if backoff < 0 {
+		backoff = 0
+	}
+	if max := float64(c.MaxDelay); max > 0 && backoff > max {
+		backoff = max
+	}
+	return time.Duration(backoff)
+}
@@ -0,0 +1,107 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBackoffConfig_Delay_ExponentialGrowth verifies Delay grows by Factor
+// each retry and is capped at MaxDelay, ignoring jitter by setting it to 0.
+func TestBackoffConfig_Delay_ExponentialGrowth(t *testing.T) {
+	cfg := BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		Factor:    2,
+		Jitter:    0,
+		MaxDelay:  5 * time.Second,
+	}
+
+	wantDelays := []time.Duration{
+		1 * time.Second, // retries=0: 1 * 2^0
+		2 * time.Second, // retries=1: 1 * 2^1
+		4 * time.Second, // retries=2: 1 * 2^2
+		5 * time.Second, // retries=3: 1 * 2^3 = 8s, capped at 5s
+	}
+
+	for retries, want := range wantDelays {
+		if got := cfg.Delay(retries); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", retries, got, want)
+		}
+	}
+}
+
+// TestBackoffConfig_Delay_JitterWithinBounds verifies Delay stays within
+// +/-Jitter of the unjittered value, and never exceeds MaxDelay.
+func TestBackoffConfig_Delay_JitterWithinBounds(t *testing.T) {
+	cfg := BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		Factor:    1.6,
+		Jitter:    0.2,
+		MaxDelay:  60 * time.Second,
+	}
+
+	base := 1 * time.Second
+	minWant := time.Duration(float64(base) * 0.8)
+	maxWant := time.Duration(float64(base) * 1.2)
+
+	for i := 0; i < 50; i++ {
+		got := cfg.Delay(0)
+		if got < minWant || got > maxWant {
+			t.Fatalf("Delay(0) = %v, want within [%v, %v]", got, minWant, maxWant)
+		}
+	}
+}
+
+// TestBackoffConfig_Delay_ZeroValueIsImmediate verifies the zero BackoffConfig
+// (NewMachine's default) always returns 0, preserving pre-backoff behavior of
+// retrying immediately.
+func TestBackoffConfig_Delay_ZeroValueIsImmediate(t *testing.T) {
+	var cfg BackoffConfig
+
+	for retries := 0; retries < 5; retries++ {
+		if got := cfg.Delay(retries); got != 0 {
+			t.Errorf("zero-value Delay(%d) = %v, want 0", retries, got)
+		}
+	}
+}
+
+// TestBackoffConfig_Delay_ZeroMaxDelayIsUncapped verifies a BackoffConfig
+// with BaseDelay/Factor set but MaxDelay left at its zero value still grows
+// the delay instead of collapsing every call to 0.
+func TestBackoffConfig_Delay_ZeroMaxDelayIsUncapped(t *testing.T) {
+	cfg := BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		Factor:    2,
+		Jitter:    0,
+	}
+
+	wantDelays := []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+	}
+
+	for retries, want := range wantDelays {
+		if got := cfg.Delay(retries); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", retries, got, want)
+		}
+	}
+}
+
+// TestDefaultBackoffConfig_MatchesGRPCStyleDefaults verifies DefaultBackoffConfig
+// returns the documented gRPC-style defaults.
+func TestDefaultBackoffConfig_MatchesGRPCStyleDefaults(t *testing.T) {
+	cfg := DefaultBackoffConfig()
+
+	if cfg.BaseDelay != 1*time.Second {
+		t.Errorf("BaseDelay = %v, want 1s", cfg.BaseDelay)
+	}
+	if cfg.Factor != 1.6 {
+		t.Errorf("Factor = %v, want 1.6", cfg.Factor)
+	}
+	if cfg.Jitter != 0.2 {
+		t.Errorf("Jitter = %v, want 0.2", cfg.Jitter)
+	}
+	if cfg.MaxDelay != 60*time.Second {
+		t.Errorf("MaxDelay = %v, want 60s", cfg.MaxDelay)
+	}
+}
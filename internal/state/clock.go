@@ -0,0 +1,79 @@
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time.After so Prober and Announcer's RFC 6762 §8 timing
+// (750ms probing, 1s announcing) can be driven deterministically in tests
+// instead of requiring real sleeps.
+type Clock interface {
+	// After returns a channel that receives the current time once d has
+	// elapsed, mirroring time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock implements Clock using the real wall clock. It is the default
+// for NewMachine, NewProber, and NewAnnouncer.
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a Clock test double that only advances when Advance is
+// called, letting tests verify the probe/announce timeline (3×250ms,
+// 2×1s) without waiting on the real clock.
+type FakeClock struct {
+	mu      sync.Mutex
+	waiters []fakeClockWaiter
+}
+
+type fakeClockWaiter struct {
+	deadline time.Duration
+	ch       chan time.Time
+}
+
+// NewFakeClock creates a FakeClock starting at elapsed duration zero.
+func NewFakeClock() *FakeClock {
+	return &FakeClock{}
+}
+
+// After implements Clock by registering a waiter that fires once Advance
+// has moved the fake clock's elapsed time past d (relative to this call).
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeClockWaiter{deadline: d, ch: ch})
+	return ch
+}
+
+// Waiters returns the number of pending After calls not yet fired, so
+// tests can poll until the code under test is actually blocked on the
+// clock before calling Advance.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// Advance moves the fake clock forward by d, firing (and forgetting) every
+// waiter whose remaining deadline has now elapsed. Waiters that haven't
+// elapsed yet have their deadline reduced by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		w.deadline -= d
+		if w.deadline <= 0 {
+			w.ch <- time.Now()
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	c.waiters = remaining
+}
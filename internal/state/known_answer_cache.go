@@ -0,0 +1,137 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// DefaultKnownAnswerFreshness is how long a "probed free" outcome stays
+// valid before Probe falls back to a full probe cycle, per RFC 6762 §5.2's
+// known-answer suppression window.
+const DefaultKnownAnswerFreshness = 60 * time.Second
+
+// KnownAnswerEntry is the outcome KnownAnswerCache.Lookup reports for a
+// single (Name, Type, Class): either a still-owned conflicting Record, or a
+// "probed and found free" outcome when Conflict is false.
+type KnownAnswerEntry struct {
+	Conflict bool                   // true if Record belongs to another host and still conflicts
+	Record   message.ResourceRecord // the conflicting record; zero value unless Conflict is true
+}
+
+// KnownAnswerCache lets Prober skip redundant probe traffic for a name it
+// already has recent information about - see SetKnownAnswerCache.
+//
+// Implementations must be safe for concurrent use: a cache is typically
+// populated from the goroutine observing incoming traffic while Probe reads
+// it from its own goroutine.
+type KnownAnswerCache interface {
+	// Lookup returns the cached outcome for (name, recordType, class), and
+	// whether a non-expired entry exists.
+	Lookup(name string, recordType protocol.RecordType, class protocol.DNSClass) (KnownAnswerEntry, bool)
+}
+
+// knownAnswerRecord is one MemoryKnownAnswerCache entry: the outcome to
+// serve and when it stops being valid.
+type knownAnswerRecord struct {
+	entry   KnownAnswerEntry
+	expires time.Time
+}
+
+// MemoryKnownAnswerCache is the default KnownAnswerCache: an in-memory,
+// TTL-keyed cache of prior probe outcomes and passively observed records,
+// mirroring querier.CacheResolver's expiry model.
+type MemoryKnownAnswerCache struct {
+	mu        sync.Mutex
+	entries   map[string]knownAnswerRecord
+	freshness time.Duration // validity window for RecordFree outcomes
+}
+
+// NewMemoryKnownAnswerCache creates an empty MemoryKnownAnswerCache using
+// DefaultKnownAnswerFreshness for RecordFree outcomes.
+func NewMemoryKnownAnswerCache() *MemoryKnownAnswerCache {
+	return &MemoryKnownAnswerCache{
+		entries:   make(map[string]knownAnswerRecord),
+		freshness: DefaultKnownAnswerFreshness,
+	}
+}
+
+// SetFreshness overrides the "probed free" validity window (default
+// DefaultKnownAnswerFreshness).
+func (c *MemoryKnownAnswerCache) SetFreshness(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.freshness = d
+}
+
+// RecordFree marks name/recordType/class as probed free as of now, valid
+// for the cache's freshness window - RFC 6762 §5.2 known-answer suppression
+// lets a subsequent Probe skip its first probe while this is fresh.
+func (c *MemoryKnownAnswerCache) RecordFree(name string, recordType protocol.RecordType, class protocol.DNSClass) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[knownAnswerKey(name, recordType, class)] = knownAnswerRecord{
+		entry:   KnownAnswerEntry{Conflict: false},
+		expires: time.Now().Add(c.freshness),
+	}
+}
+
+// RecordConflict records record as belonging to another host, valid for
+// record's own TTL - a subsequent Probe for the same (Name, Type, Class)
+// returns Conflict: true without sending any probes while it's fresh.
+func (c *MemoryKnownAnswerCache) RecordConflict(record message.ResourceRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[knownAnswerKey(record.Name, record.Type, record.Class)] = knownAnswerRecord{
+		entry:   KnownAnswerEntry{Conflict: true, Record: record},
+		expires: time.Now().Add(time.Duration(record.TTL) * time.Second),
+	}
+}
+
+// Invalidate drops any cached entry for name/recordType/class.
+func (c *MemoryKnownAnswerCache) Invalidate(name string, recordType protocol.RecordType, class protocol.DNSClass) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, knownAnswerKey(name, recordType, class))
+}
+
+// ObserveRecord updates the cache from a record seen on the wire: a TTL=0
+// record is a goodbye per RFC 6762 §10.1 and invalidates any cached entry
+// for it, otherwise it's recorded as a conflict outcome via RecordConflict.
+func (c *MemoryKnownAnswerCache) ObserveRecord(record message.ResourceRecord) {
+	if record.TTL == 0 {
+		c.Invalidate(record.Name, record.Type, record.Class)
+		return
+	}
+	c.RecordConflict(record)
+}
+
+// Lookup implements KnownAnswerCache.
+func (c *MemoryKnownAnswerCache) Lookup(name string, recordType protocol.RecordType, class protocol.DNSClass) (KnownAnswerEntry, bool) {
+	key := knownAnswerKey(name, recordType, class)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rec, ok := c.entries[key]
+	if !ok {
+		return KnownAnswerEntry{}, false
+	}
+	if time.Now().After(rec.expires) {
+		delete(c.entries, key)
+		return KnownAnswerEntry{}, false
+	}
+	return rec.entry, true
+}
+
+// knownAnswerKey returns the entries map key for name/recordType/class,
+// matching names case-insensitively per RFC 1035 §2.3.3.
+func knownAnswerKey(name string, recordType protocol.RecordType, class protocol.DNSClass) string {
+	return fmt.Sprintf("%s|%d|%d", strings.ToLower(name), recordType, class)
+}
+
+var _ KnownAnswerCache = (*MemoryKnownAnswerCache)(nil)
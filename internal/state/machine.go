@@ -25,6 +25,9 @@ package state
 import (
 	"context"
 	"sync"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+	"github.com/joshuafuller/beacon/metrics"
 )
 
 // Machine coordinates the service registration state machine per RFC 6762 §8.
@@ -77,19 +80,168 @@ type Machine struct {
 	prober         *Prober
 	announcer      *Announcer
 	mu             sync.RWMutex
-	onStateChange  func(State)
+	onStateChange  func(old, new State)
 	currentState   State
 	injectConflict bool
+
+	// renameStrategy, if set, lets the Machine resolve a probing conflict on
+	// its own: instead of returning to the caller at StateConflictDetected,
+	// it renames the service per RFC 6762 §9 and restarts probing. current
+	// is the name that just conflicted; attempt is the 1-indexed retry
+	// count, so a strategy can change behavior after repeated collisions
+	// (e.g. switch from a numeric suffix to a random one). Nil (the
+	// default) preserves the original behavior of leaving rename/retry to
+	// the caller.
+	renameStrategy func(current string, attempt int) string
+
+	// maxConflictRetries caps how many times renameStrategy-driven retries
+	// are attempted before Run gives up and transitions to
+	// StateConflictAbandoned instead of retrying again. Zero (NewMachine's
+	// default) means unlimited, matching the original behavior from before
+	// MaxConflictRetries existed. Set via WithMaxConflictRetries.
+	maxConflictRetries int
+
+	// resolvedName is the most recently attempted service name: the name
+	// Run last probed under, whether that's the original name (no conflict
+	// yet), a renamed candidate from a prior retry, or the name Run finally
+	// reached StateEstablished or StateConflictAbandoned with. Read via
+	// GetResolvedName.
+	resolvedName string
+
+	// backoff delays each renameStrategy-driven retry per BackoffConfig, so
+	// responders that lost a simultaneous probe (RFC 6762 §8.2) don't all
+	// rename and retry in lockstep. Zero value (NewMachine's default) means
+	// no delay - retry immediately, preserving prior behavior.
+	backoff BackoffConfig
+	retries int
+
+	// clock times the backoff wait between retries; defaults to the real
+	// clock, swappable for a FakeClock in tests via SetClock.
+	clock Clock
+
+	// outbound carries every probe and announcement message as it's sent,
+	// for a Responder to relay to the transport layer.
+	outbound chan []byte
+
+	// metrics receives counter increments for state transitions and probe
+	// conflicts; defaults to metrics.NoOp{} so Run never pays for label-map
+	// construction unless a caller opts in via WithMetrics.
+	metrics metrics.Metrics
+}
+
+// MachineOption configures a Machine at construction time, following the
+// same pattern as InterfaceWatcher's WatcherOption: a plain function over
+// the struct, applied in order, with no validation since every option here
+// has a sensible zero value.
+type MachineOption func(*Machine)
+
+// WithRenameStrategy installs a rename strategy the Machine calls on itself
+// when it loses probe tiebreaking (RFC 6762 §8.2.1), so it can rename and
+// restart probing without the caller having to drive a retry loop. current
+// is the name that just conflicted; attempt is the 1-indexed retry count.
+// Leave unset to keep the original contract of returning at
+// StateConflictDetected. Equivalent to SetRenameFunc, except attempt-aware;
+// installing both overwrites whichever was set last.
+func WithRenameStrategy(strategy func(current string, attempt int) string) MachineOption {
+	return func(sm *Machine) {
+		sm.renameStrategy = strategy
+	}
+}
+
+// WithMaxConflictRetries caps how many renameStrategy-driven retries Run
+// will make before giving up: once the limit is reached, Run transitions to
+// the terminal StateConflictAbandoned instead of renaming and probing
+// again. Default: 0, meaning unlimited - Run keeps retrying for as long as
+// renameStrategy keeps losing tiebreaking.
+func WithMaxConflictRetries(n int) MachineOption {
+	return func(sm *Machine) {
+		sm.maxConflictRetries = n
+	}
+}
+
+// WithMetrics installs m as the Machine's metrics sink, so every state
+// transition and probe conflict reported by Run increments a counter on m
+// instead of on the default metrics.NoOp{}. See the metrics package and
+// metrics/prom for a ready-made Prometheus adapter.
+func WithMetrics(m metrics.Metrics) MachineOption {
+	return func(sm *Machine) {
+		if m == nil {
+			// MachineOption has no error return (see its doc comment), so a
+			// nil m is a silent no-op, leaving the metrics.NoOp{} default in
+			// place rather than panicking the first time setState calls it.
+			return
+		}
+		sm.metrics = m
+	}
 }
 
-// NewMachine creates a new state machine.
+// NewMachine creates a new state machine with no delay between
+// renameStrategy-driven conflict retries.
 //
 // T037: Initialize Machine
-func NewMachine() *Machine {
-	return &Machine{
+func NewMachine(opts ...MachineOption) *Machine {
+	return NewMachineWithBackoff(BackoffConfig{}, opts...)
+}
+
+// NewMachineWithBackoff creates a new state machine that waits per cfg
+// (see BackoffConfig) between each renameStrategy-driven conflict retry.
+func NewMachineWithBackoff(cfg BackoffConfig, opts ...MachineOption) *Machine {
+	prober := NewProber()
+	announcer := NewAnnouncer()
+	outbound := make(chan []byte, 8)
+	prober.SetOutbound(outbound)
+	announcer.SetOutbound(outbound)
+
+	sm := &Machine{
 		currentState: StateInitial,
-		prober:       NewProber(),
-		announcer:    NewAnnouncer(),
+		prober:       prober,
+		backoff:      cfg,
+		clock:        realClock{},
+		announcer:    announcer,
+		outbound:     outbound,
+		metrics:      metrics.NoOp{},
+	}
+	for _, opt := range opts {
+		opt(sm)
+	}
+	return sm
+}
+
+// Outbound returns the channel carrying every probe and announcement
+// message the Machine sends, in order.
+func (sm *Machine) Outbound() <-chan []byte {
+	return sm.outbound
+}
+
+// SetClock overrides the Clock used by the prober, announcer, and the
+// Machine's own renameFunc-driven backoff wait to time RFC 6762 §8's 250ms
+// probe and 1s announcement intervals, letting tests drive the full timeline
+// deterministically with a FakeClock.
+func (sm *Machine) SetClock(clock Clock) {
+	sm.prober.SetClock(clock)
+	sm.announcer.SetClock(clock)
+	sm.clock = clock
+}
+
+// SetTransport wires t into both the prober and announcer, so Run actually
+// sends probe queries and announcements via t (and the prober listens on t
+// for conflicting responses) instead of only relaying built messages to
+// outbound. See Prober.SetTransport and Announcer.SetTransport. A nil
+// transport (the default) disables this.
+func (sm *Machine) SetTransport(t transport.Transport) {
+	sm.prober.SetTransport(t)
+	sm.announcer.SetTransport(t)
+}
+
+// SetRenameFunc installs a RenameFunc the Machine calls on itself when it
+// loses probe tiebreaking (RFC 6762 §8.2.1), so it can rename and restart
+// probing without the caller having to drive a retry loop. Leave unset to
+// keep the original contract of returning at StateConflictDetected. See
+// WithRenameStrategy for an attempt-aware equivalent settable at
+// construction time; installing both overwrites whichever was set last.
+func (sm *Machine) SetRenameFunc(renameFunc func(name string) string) {
+	sm.renameStrategy = func(current string, _ int) string {
+		return renameFunc(current)
 	}
 }
 
@@ -110,20 +262,55 @@ func NewMachine() *Machine {
 // R001: Each service runs in its own goroutine
 // T038: Implement Machine.run() with context cancellation
 func (sm *Machine) Run(ctx context.Context, serviceName string) error {
-	// Transition to Probing
-	sm.setState(StateProbing)
+	name := serviceName
+	sm.setResolvedName(name)
 
-	// Phase 1: Probing (~750ms)
-	result := sm.prober.Probe(ctx, serviceName)
-	if result.Error != nil {
-		return result.Error
-	}
+	for {
+		// Transition to Probing
+		sm.setState(StateProbing)
+
+		// Phase 1: Probing (~750ms)
+		result := sm.prober.Probe(ctx, name)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if !result.Conflict && !sm.injectConflict {
+			break
+		}
 
-	if result.Conflict || sm.injectConflict {
-		// Conflict detected - stop here
-		// Caller (Responder) will handle rename/retry
+		// Conflict detected (RFC 6762 §8.2.1: we lost tiebreaking).
 		sm.setState(StateConflictDetected)
-		return nil
+		sm.metrics.IncCounter("beacon_state_probe_conflicts_total", map[string]string{"name": name})
+
+		if sm.renameStrategy == nil {
+			// No rename strategy installed - leave rename/retry to the caller.
+			return nil
+		}
+
+		if sm.maxConflictRetries > 0 && sm.retries >= sm.maxConflictRetries {
+			// Exhausted the configured retry budget - give up for good,
+			// distinguishing this from a one-shot StateConflictDetected that
+			// a caller without a rename strategy might still retry itself.
+			sm.setState(StateConflictAbandoned)
+			return nil
+		}
+
+		// Back off before retrying (BackoffConfig's zero value waits 0,
+		// i.e. retries immediately, matching prior behavior) so responders
+		// that lost a simultaneous probe don't all rename and retry in
+		// lockstep.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sm.clock.After(sm.backoff.Delay(sm.retries)):
+		}
+		sm.retries++
+
+		// RFC 6762 §9: Rename and restart probing under the new name.
+		name = sm.renameStrategy(name, sm.retries)
+		sm.setResolvedName(name)
+		sm.injectConflict = false
 	}
 
 	// Transition to Announcing
@@ -154,6 +341,23 @@ func (sm *Machine) GetState() State {
 	return sm.currentState
 }
 
+// GetResolvedName returns the service name Run last probed under: the
+// original name passed to Run if no conflict has renamed it yet, the latest
+// renameStrategy candidate otherwise, and - once Run reaches
+// StateEstablished or StateConflictAbandoned - the name it finished on.
+func (sm *Machine) GetResolvedName() string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.resolvedName
+}
+
+// setResolvedName records name as the current candidate, for GetResolvedName.
+func (sm *Machine) setResolvedName(name string) {
+	sm.mu.Lock()
+	sm.resolvedName = name
+	sm.mu.Unlock()
+}
+
 // setState transitions to a new state.
 //
 // T038: State transitions with callbacks
@@ -161,15 +365,65 @@ func (sm *Machine) setState(newState State) {
 	// Manual unlock required: Must release lock before calling user callback to avoid deadlocks.
 	// Callback may access state machine, so holding lock would cause deadlock.
 	sm.mu.Lock() // nosemgrep: beacon-mutex-defer-unlock
+	oldState := sm.currentState
 	sm.currentState = newState
+	onStateChange := sm.onStateChange
+	m := sm.metrics
 	sm.mu.Unlock()
 
+	m.IncCounter("beacon_state_transitions_total", map[string]string{
+		"from": oldState.String(),
+		"to":   newState.String(),
+	})
+
 	// Notify test hook (called WITHOUT lock to prevent deadlocks)
-	if sm.onStateChange != nil {
-		sm.onStateChange(newState)
+	if onStateChange != nil {
+		onStateChange(oldState, newState)
 	}
 }
 
+// OnStateChange registers fn to be called on every state transition with
+// the state being left and the state being entered, for observability
+// (e.g. logging, metrics) independent of the test hooks used internally.
+func (sm *Machine) OnStateChange(fn func(old, new State)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onStateChange = fn
+}
+
+// Reset returns the Machine to StateInitial, for re-registering a service
+// after sending a goodbye packet (TTL=0 per RFC 6762 §10.1) without
+// constructing a new Machine. Run can be called again afterward to probe
+// and announce from scratch.
+//
+// Reset also clears retries, so a Machine that previously exhausted
+// WithMaxConflictRetries and reached StateConflictAbandoned starts its next
+// Run with a full retry budget rather than abandoning on the first conflict.
+func (sm *Machine) Reset() {
+	sm.injectConflict = false
+	sm.retries = 0
+	sm.setResolvedName("")
+	sm.setState(StateInitial)
+}
+
+// ReportConflict handles a conflicting response observed for an already-
+// established record (RFC 6762 §9: "a host finds that some other host is
+// now illegally using its name... it MUST immediately reassert its claim
+// by sending the appropriate Multicast DNS response... if, after this, ...
+// it should defer to the winning host, then it MUST immediately cease
+// using the name and reconfigure"). It re-enters probing under serviceName
+// and runs the machine through to completion again.
+//
+// ReportConflict is a no-op, returning nil, if the Machine isn't currently
+// Established - a conflict report only matters once a name has been
+// claimed.
+func (sm *Machine) ReportConflict(ctx context.Context, serviceName string) error {
+	if sm.GetState() != StateEstablished {
+		return nil
+	}
+	return sm.Run(ctx, serviceName)
+}
+
 // SetInjectConflict is a test hook to inject conflict during probing.
 //
 // T062: Test hook for max rename attempts testing
@@ -177,6 +431,26 @@ func (sm *Machine) SetInjectConflict(inject bool) {
 	sm.injectConflict = inject
 }
 
+// Shutdown sends RFC 6762 §10.1 goodbye packets for serviceName's records
+// and transitions to StateGoodbye, so peers flush their cached entries
+// immediately instead of waiting out the records' normal TTL.
+//
+// If the Machine never reached StateEstablished (e.g. it's still probing,
+// or lost a naming conflict), there's nothing to say goodbye to: Shutdown
+// is a no-op returning nil, and the state is left unchanged.
+func (sm *Machine) Shutdown(ctx context.Context, serviceName string) error {
+	if sm.GetState() != StateEstablished {
+		return nil
+	}
+
+	if err := sm.announcer.Goodbye(ctx, serviceName, sm.announcer.resourceRecords); err != nil {
+		return err
+	}
+
+	sm.setState(StateGoodbye)
+	return nil
+}
+
 // GetProber returns the internal Prober for integration with Responder.
 //
 // US2 GREEN: Allow Responder to access Prober for message capture
@@ -2,8 +2,11 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
 )
 
 // TestMachine_Transitions_RED tests state machine transitions per RFC 6762 §8.
@@ -29,7 +32,7 @@ func TestMachine_Run_Probing_To_Announcing(t *testing.T) {
 
 	// Track state transitions
 	var states []State
-	machine.onStateChange = func(newState State) {
+	machine.onStateChange = func(_, newState State) {
 		states = append(states, newState)
 	}
 
@@ -103,7 +106,7 @@ func TestMachine_Run_StateConflictDetected_Exists(t *testing.T) {
 
 	// Track state transitions
 	var states []State
-	machine.onStateChange = func(newState State) {
+	machine.onStateChange = func(_, newState State) {
 		states = append(states, newState)
 	}
 
@@ -207,7 +210,7 @@ func TestMachine_Run_TimingAccuracy(t *testing.T) {
 
 	// Track state transition times
 	var probingStart, announcingStart, establishedStart time.Time
-	machine.onStateChange = func(newState State) {
+	machine.onStateChange = func(_, newState State) {
 		switch newState {
 		case StateProbing:
 			probingStart = time.Now()
@@ -285,3 +288,458 @@ func TestMachine_ConcurrentRun(t *testing.T) {
 		}
 	}
 }
+
+// awaitWaiter polls clock until it has at least one pending After() call,
+// so a test can Advance it right after the code under test blocks instead
+// of guessing at a real-time sleep.
+func awaitWaiter(t *testing.T, clock *FakeClock) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for clock.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for FakeClock to gain a waiter")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestMachine_Run_WithFakeClock verifies the full probe→announce timeline
+// (3 probes/250ms, 2 announcements/1s) advances only when the FakeClock is
+// advanced, and that the sequence of transitions matches RFC 6762 §8
+// exactly, deterministically rather than by sleeping real time.
+func TestMachine_Run_WithFakeClock(t *testing.T) {
+	clock := NewFakeClock()
+	machine := NewMachine()
+	machine.SetClock(clock)
+
+	var states []State
+	machine.OnStateChange(func(_, newState State) {
+		states = append(states, newState)
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	// Probing: 2 inter-probe waits of 250ms between the 3 probes.
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+
+	// Announcing: 1 inter-announcement wait of 1s between the 2 announcements.
+	awaitWaiter(t, clock)
+	clock.Advance(time.Second)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not complete after advancing the FakeClock through the full timeline")
+	}
+
+	wantStates := []State{StateProbing, StateAnnouncing, StateEstablished}
+	if len(states) != len(wantStates) {
+		t.Fatalf("state transitions = %v, want %v", states, wantStates)
+	}
+	for i, want := range wantStates {
+		if states[i] != want {
+			t.Errorf("state[%d] = %v, want %v", i, states[i], want)
+		}
+	}
+}
+
+// TestMachine_RenameFunc_RestartsProbing verifies that when a RenameFunc
+// is installed, losing probe tiebreaking renames the service and restarts
+// probing internally (RFC 6762 §9) instead of returning to the caller at
+// StateConflictDetected.
+func TestMachine_RenameFunc_RestartsProbing(t *testing.T) {
+	machine := NewMachine()
+
+	attempt := 0
+	machine.prober.injectConflictAfter = 1 // force a conflict every probe
+
+	var renamedTo []string
+	machine.SetRenameFunc(func(name string) string {
+		attempt++
+		renamedTo = append(renamedTo, name)
+		if attempt >= 2 {
+			// Stop forcing conflicts so the 2nd rename succeeds.
+			machine.prober.injectConflictAfter = 0
+		}
+		return name + "-2"
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	timeout := time.After(3 * time.Second)
+	var err error
+drain:
+	for {
+		select {
+		case <-machine.Outbound():
+			// Drain so Run doesn't block sending probes/announcements
+			// across the two rename-and-reprobe cycles.
+		case err = <-done:
+			break drain
+		case <-timeout:
+			t.Fatal("Run() did not complete before timeout")
+		}
+	}
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	if machine.GetState() != StateEstablished {
+		t.Errorf("GetState() = %v, want StateEstablished after resolving the rename", machine.GetState())
+	}
+	wantRenames := []string{testServiceName, testServiceName + "-2"}
+	if len(renamedTo) != len(wantRenames) || renamedTo[0] != wantRenames[0] || renamedTo[1] != wantRenames[1] {
+		t.Errorf("renamedTo = %v, want %v", renamedTo, wantRenames)
+	}
+}
+
+// TestMachine_NewMachineWithBackoff_WaitsBetweenRenameRetries verifies that
+// a Machine created with NewMachineWithBackoff waits on the configured
+// BackoffConfig before each renameFunc-driven retry, rather than retrying
+// immediately.
+func TestMachine_NewMachineWithBackoff_WaitsBetweenRenameRetries(t *testing.T) {
+	clock := NewFakeClock()
+	machine := NewMachineWithBackoff(BackoffConfig{
+		BaseDelay: 1 * time.Second,
+		Factor:    2,
+		Jitter:    0,
+		MaxDelay:  10 * time.Second,
+	})
+	machine.SetClock(clock)
+	machine.prober.injectConflictAfter = 1 // force a conflict every probe
+
+	renamed := make(chan struct{}, 1)
+	machine.SetRenameFunc(func(name string) string {
+		machine.prober.injectConflictAfter = 0 // let the next probe succeed
+		renamed <- struct{}{}
+		return name + "-2"
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	// Drain Outbound() for the whole test so Run never blocks sending
+	// probes/announcements across the rename-and-reprobe cycle.
+	go func() {
+		for range machine.Outbound() {
+		}
+	}()
+
+	// injectConflictAfter=1 makes the probe loop detect the conflict as soon
+	// as it sends its 2nd probe, i.e. after a single inter-probe wait - the
+	// next waiter the Machine blocks on is the 1s backoff delay, not a
+	// further probe interval.
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+
+	awaitWaiter(t, clock) // the pending waiter here is the backoff delay
+	clock.Advance(1 * time.Second)
+
+	select {
+	case <-renamed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("renameFunc was not called after the backoff wait elapsed")
+	}
+
+	// Second probing cycle, then announcing.
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+	awaitWaiter(t, clock)
+	clock.Advance(time.Second)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not complete after advancing the FakeClock through the full timeline")
+	}
+
+	if machine.GetState() != StateEstablished {
+		t.Errorf("GetState() = %v, want StateEstablished", machine.GetState())
+	}
+}
+
+// TestMachine_ReportConflict_ReEntersProbing verifies ReportConflict only
+// acts once the Machine is Established, and re-enters probing when called.
+func TestMachine_ReportConflict_ReEntersProbing(t *testing.T) {
+	machine := NewMachine()
+
+	// Drain Outbound() for the whole test so Run/ReportConflict never
+	// block sending probes/announcements across their two full cycles.
+	go func() {
+		for range machine.Outbound() {
+		}
+	}()
+
+	// Not yet established - ReportConflict must be a no-op.
+	if err := machine.ReportConflict(context.Background(), testServiceName); err != nil {
+		t.Fatalf("ReportConflict() before Established error = %v, want nil", err)
+	}
+	if machine.GetState() != StateInitial {
+		t.Errorf("GetState() = %v, want StateInitial (ReportConflict should no-op before Established)", machine.GetState())
+	}
+
+	if err := machine.Run(context.Background(), testServiceName); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+	if machine.GetState() != StateEstablished {
+		t.Fatalf("GetState() = %v, want StateEstablished", machine.GetState())
+	}
+
+	var states []State
+	machine.OnStateChange(func(_, newState State) {
+		states = append(states, newState)
+	})
+
+	if err := machine.ReportConflict(context.Background(), testServiceName); err != nil {
+		t.Fatalf("ReportConflict() error = %v, want nil", err)
+	}
+
+	wantStates := []State{StateProbing, StateAnnouncing, StateEstablished}
+	if len(states) != len(wantStates) {
+		t.Fatalf("state transitions after ReportConflict = %v, want %v", states, wantStates)
+	}
+}
+
+// TestMachine_Reset verifies Reset returns the Machine to StateInitial so
+// it can be re-run for re-registration after a goodbye packet.
+func TestMachine_Reset(t *testing.T) {
+	machine := NewMachine()
+	machine.SetInjectConflict(true)
+
+	if err := machine.Run(context.Background(), testServiceName); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+	if machine.GetState() != StateConflictDetected {
+		t.Fatalf("GetState() = %v, want StateConflictDetected", machine.GetState())
+	}
+
+	machine.Reset()
+
+	if machine.GetState() != StateInitial {
+		t.Errorf("GetState() after Reset() = %v, want StateInitial", machine.GetState())
+	}
+	if machine.injectConflict {
+		t.Error("injectConflict still true after Reset(), want false")
+	}
+}
+
+// TestMachine_Outbound verifies probe and announcement messages are
+// relayed, in order, on the Machine's Outbound channel.
+func TestMachine_Outbound(t *testing.T) {
+	machine := NewMachine()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	received := 0
+	timeout := time.After(3 * time.Second)
+	for received < 5 { // 3 probes + 2 announcements
+		select {
+		case msg := <-machine.Outbound():
+			if len(msg) == 0 {
+				t.Error("received empty outbound message")
+			}
+			received++
+		case <-timeout:
+			t.Fatalf("received %d/5 outbound messages before timeout", received)
+		}
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+}
+
+// TestMachine_Shutdown_SendsGoodbyeFromEstablished verifies that Shutdown
+// sends goodbye packets and transitions to StateGoodbye once a service has
+// reached StateEstablished.
+func TestMachine_Shutdown_SendsGoodbyeFromEstablished(t *testing.T) {
+	clock := NewFakeClock()
+	machine := NewMachine()
+	machine.SetClock(clock)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+	awaitWaiter(t, clock)
+	clock.Advance(protocol.ProbeInterval)
+	awaitWaiter(t, clock)
+	clock.Advance(time.Second)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	if got := machine.GetState(); got != StateEstablished {
+		t.Fatalf("state before Shutdown() = %v, want StateEstablished", got)
+	}
+
+	announceCount := 0
+	machine.GetAnnouncer().SetOnSendAnnouncement(func() {
+		announceCount++
+	})
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- machine.Shutdown(context.Background(), testServiceName)
+	}()
+
+	awaitWaiter(t, clock)
+	clock.Advance(time.Second)
+
+	if err := <-shutdownDone; err != nil {
+		t.Fatalf("Shutdown() error = %v, want nil", err)
+	}
+
+	if announceCount != 2 {
+		t.Errorf("Shutdown() sent %d multicasts, want 2", announceCount)
+	}
+
+	if got := machine.GetState(); got != StateGoodbye {
+		t.Errorf("state after Shutdown() = %v, want StateGoodbye", got)
+	}
+}
+
+// TestMachine_Shutdown_NoopBeforeEstablished verifies that Shutdown is a
+// no-op, leaving the state untouched, when a service is shut down before it
+// ever reached StateEstablished (e.g. still probing).
+func TestMachine_Shutdown_NoopBeforeEstablished(t *testing.T) {
+	machine := NewMachine()
+
+	if err := machine.Shutdown(context.Background(), testServiceName); err != nil {
+		t.Fatalf("Shutdown() error = %v, want nil", err)
+	}
+
+	if got := machine.GetState(); got != StateInitial {
+		t.Errorf("state after Shutdown() = %v, want StateInitial (unchanged)", got)
+	}
+}
+
+// TestMachine_WithRenameStrategy_ReceivesAttemptNumber verifies that a
+// renameStrategy installed via WithRenameStrategy sees the 1-indexed retry
+// count alongside the conflicting name, and that GetResolvedName tracks the
+// name currently being probed.
+func TestMachine_WithRenameStrategy_ReceivesAttemptNumber(t *testing.T) {
+	var attempts []int
+	var machine *Machine
+	machine = NewMachine(WithRenameStrategy(func(current string, attempt int) string {
+		attempts = append(attempts, attempt)
+		if attempt >= 2 {
+			machine.prober.injectConflictAfter = 0
+		}
+		return fmt.Sprintf("%s-%d", current, attempt+1)
+	}))
+	machine.prober.injectConflictAfter = 1 // force a conflict every probe
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	timeout := time.After(3 * time.Second)
+drain:
+	for {
+		select {
+		case <-machine.Outbound():
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Run() error = %v, want nil", err)
+			}
+			break drain
+		case <-timeout:
+			t.Fatal("Run() did not complete before timeout")
+		}
+	}
+
+	if want := []int{1, 2}; len(attempts) != len(want) || attempts[0] != want[0] || attempts[1] != want[1] {
+		t.Errorf("attempts seen by renameStrategy = %v, want %v", attempts, want)
+	}
+
+	wantResolved := testServiceName + "-2-3"
+	if got := machine.GetResolvedName(); got != wantResolved {
+		t.Errorf("GetResolvedName() = %q, want %q", got, wantResolved)
+	}
+	if machine.GetState() != StateEstablished {
+		t.Errorf("GetState() = %v, want StateEstablished", machine.GetState())
+	}
+}
+
+// TestMachine_WithMaxConflictRetries_AbandonsAfterLimit verifies that once a
+// renameStrategy-driven retry loop exhausts WithMaxConflictRetries, Run
+// transitions to the terminal StateConflictAbandoned instead of renaming
+// and retrying again.
+func TestMachine_WithMaxConflictRetries_AbandonsAfterLimit(t *testing.T) {
+	renames := 0
+	machine := NewMachine(
+		WithRenameStrategy(func(current string, attempt int) string {
+			renames++
+			return fmt.Sprintf("%s-%d", current, attempt+1)
+		}),
+		WithMaxConflictRetries(2),
+	)
+	machine.prober.injectConflictAfter = 1 // every probe looks like a conflict, forever
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	timeout := time.After(3 * time.Second)
+drain:
+	for {
+		select {
+		case <-machine.Outbound():
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Run() error = %v, want nil", err)
+			}
+			break drain
+		case <-timeout:
+			t.Fatal("Run() did not complete before timeout")
+		}
+	}
+
+	if renames != 2 {
+		t.Errorf("renameStrategy was called %d times, want 2 (MaxConflictRetries)", renames)
+	}
+	if got := machine.GetState(); got != StateConflictAbandoned {
+		t.Errorf("GetState() = %v, want StateConflictAbandoned", got)
+	}
+
+	// Reset must clear retries too, or a fresh Run would abandon on its very
+	// first conflict instead of getting a full new retry budget.
+	machine.Reset()
+	machine.prober.injectConflictAfter = 0 // no conflicts this time
+
+	if err := machine.Run(context.Background(), testServiceName); err != nil {
+		t.Fatalf("Run() after Reset() error = %v, want nil", err)
+	}
+	if got := machine.GetState(); got == StateConflictAbandoned {
+		t.Error("GetState() after Reset() and a clean Run() = StateConflictAbandoned, want retries to have been cleared")
+	}
+}
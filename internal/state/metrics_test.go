@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMetrics is a minimal metrics.Metrics recorder for assertions, safe for
+// concurrent use since Machine may call it from test hook callbacks.
+type fakeMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{counters: make(map[string]int)}
+}
+
+func (f *fakeMetrics) IncCounter(name string, _ map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counters[name]++
+}
+
+func (f *fakeMetrics) ObserveHistogram(string, float64, map[string]string) {}
+
+func (f *fakeMetrics) count(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counters[name]
+}
+
+// TestMachine_WithMetrics_RecordsStateTransitions verifies a WithMetrics sink
+// sees one beacon_state_transitions_total increment per setState call across
+// a full Probing → Announcing → Established run.
+func TestMachine_WithMetrics_RecordsStateTransitions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping timing test in short mode")
+	}
+
+	fm := newFakeMetrics()
+	machine := NewMachine(WithMetrics(fm))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v, want nil", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run() did not complete within 3 seconds")
+	}
+
+	// Probing, Announcing, Established: 3 transitions.
+	if got := fm.count("beacon_state_transitions_total"); got != 3 {
+		t.Errorf("beacon_state_transitions_total = %d, want 3", got)
+	}
+}
+
+// TestMachine_WithMetrics_NilIsANoop verifies WithMetrics(nil) leaves the
+// default metrics.NoOp{} in place instead of panicking on the first state
+// transition.
+func TestMachine_WithMetrics_NilIsANoop(t *testing.T) {
+	machine := NewMachine(WithMetrics(nil))
+	machine.setState(StateProbing)
+}
+
+// TestMachine_WithMetrics_RecordsProbeConflicts verifies an injected probe
+// conflict increments beacon_state_probe_conflicts_total.
+func TestMachine_WithMetrics_RecordsProbeConflicts(t *testing.T) {
+	fm := newFakeMetrics()
+	machine := NewMachine(WithMetrics(fm))
+	machine.SetInjectConflict(true)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- machine.Run(context.Background(), testServiceName)
+	}()
+
+	timeout := time.After(3 * time.Second)
+drain:
+	for {
+		select {
+		case <-machine.Outbound():
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Run() error = %v, want nil", err)
+			}
+			break drain
+		case <-timeout:
+			t.Fatal("Run() did not complete before timeout")
+		}
+	}
+
+	if got := fm.count("beacon_state_probe_conflicts_total"); got != 1 {
+		t.Errorf("beacon_state_probe_conflicts_total = %d, want 1", got)
+	}
+}
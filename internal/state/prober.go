@@ -2,10 +2,10 @@ package state
 
 import (
 	"context"
-	"time"
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
 )
 
 // ProbeResult represents the result of probing.
@@ -21,6 +21,16 @@ type ProbeResult struct {
 //
 // Beacon implementation: Send exactly 3 probes for robust conflict detection.
 //
+// Machine.Run drives a Prober through exactly the probe/tiebreak/rename/
+// announce flow RFC 6762 §8 describes: responder.Responder.Register builds
+// one Machine per registration attempt, wires this Prober's conflict
+// detection to responder.ConflictDetector (§8.2/§8.2.1's lexicographic
+// RDATA tiebreak) and its Announcer to the two unsolicited responses §8.3
+// requires, and on StateConflictDetected renames the service (via its
+// RenameStrategy, "-2", "-3", ... by default) and retries - see Register's
+// rename loop. There is deliberately no separate Probe(ctx, service)
+// entry point in package responder; Register already owns that sequencing.
+//
 // T039: Implement Prober
 // T059: Integrate ConflictDetector with Prober (GREEN phase)
 type Prober struct {
@@ -38,6 +48,72 @@ type Prober struct {
 
 	// US2 GREEN: Message capture for contract test validation
 	lastProbeMessage []byte // Last sent probe message (wire format)
+
+	// clock drives the 250ms inter-probe interval; defaults to the real
+	// clock, swappable for a FakeClock in tests.
+	clock Clock
+
+	// outbound, if set, receives each probe message as it's built, so a
+	// Machine can relay it to the transport layer.
+	outbound chan<- []byte
+
+	// hooks receive ProbeStart/ProbeConflict notifications for every Probe
+	// call, so a caller one layer up can wire probing activity into its own
+	// observability stack. See AddHook.
+	hooks []ProbeHook
+
+	// knownAnswerCache, if set, lets Probe short-circuit probing for a name
+	// it already has recent information about. See SetKnownAnswerCache.
+	knownAnswerCache KnownAnswerCache
+
+	// transport, if set, makes Probe actually Send each probe query and
+	// listen for a same-interval response via Receive, feeding any answer
+	// it decodes to conflictDetector - the real counterpart to the
+	// injectConflictAfter/InjectIncomingResponse test hooks above, so a
+	// transport.MockTransport can drive the conflict path with a scripted
+	// wire-format response instead of those hooks. Nil (the default)
+	// preserves the original behavior of only writing to outbound.
+	transport transport.Transport
+}
+
+// ProbeHook receives notifications about a single Prober.Probe call.
+// Methods are invoked synchronously from the goroutine running Probe; a
+// slow hook delays probing.
+//
+// ProbeHook is intentionally a narrow, package-local interface rather than
+// depending on the responder package's broader event-hook type (internal
+// packages don't import the public API packages built on top of them); a
+// responder.EventHook satisfies ProbeHook as-is, since Go interfaces are
+// assignable by method set.
+type ProbeHook interface {
+	// OnProbeStart is called once per Probe call, before the first probe is
+	// sent.
+	OnProbeStart(name string)
+
+	// OnProbeConflict is called when a probe response conflicts with one of
+	// ourRecords per RFC 6762 §8.2, with the specific pair of records that
+	// decided the outcome.
+	OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord)
+}
+
+// AddHook registers an additional ProbeHook, invoked for every subsequent
+// Probe call.
+func (p *Prober) AddHook(hook ProbeHook) {
+	p.hooks = append(p.hooks, hook)
+}
+
+// notifyProbeStart invokes OnProbeStart on every registered hook.
+func (p *Prober) notifyProbeStart(name string) {
+	for _, hook := range p.hooks {
+		hook.OnProbeStart(name)
+	}
+}
+
+// notifyProbeConflict invokes OnProbeConflict on every registered hook.
+func (p *Prober) notifyProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+	for _, hook := range p.hooks {
+		hook.OnProbeConflict(ourRecord, incomingRecord)
+	}
 }
 
 // ConflictDetectorInterface defines the interface for conflict detection.
@@ -48,9 +124,55 @@ type ConflictDetectorInterface interface {
 	DetectConflict(ourRecord, incomingRecord message.ResourceRecord) (bool, error)
 }
 
+// ConflictSetDetectorInterface is an optional capability of a
+// ConflictDetectorInterface: tie-breaking a whole probed RRSet at once per
+// RFC 6762 §8.2.1, rather than DetectConflict's single record pair. A
+// pairwise loop over DetectConflict can't express "sort each side into
+// canonical order first" or "the side with more records remaining wins" -
+// both required once a service probes more than one record under the same
+// name (e.g. SRV + TXT together). Probe type-asserts the configured
+// conflictDetector for this interface and prefers it when present,
+// matching how AddHook's ProbeHook is a narrow package-local interface a
+// responder.EventHook satisfies by method set rather than an import.
+type ConflictSetDetectorInterface interface {
+	DetectConflictSet(ours, incoming []message.ResourceRecord) (bool, error)
+}
+
 // NewProber creates a new prober.
 func NewProber() *Prober {
-	return &Prober{}
+	return &Prober{clock: realClock{}}
+}
+
+// SetClock overrides the Clock used to time inter-probe intervals.
+func (p *Prober) SetClock(clock Clock) {
+	p.clock = clock
+}
+
+// SetOutbound sets the channel probe messages are relayed to as they're
+// sent. A nil channel (the default) disables relaying.
+func (p *Prober) SetOutbound(outbound chan<- []byte) {
+	p.outbound = outbound
+}
+
+// SetTransport wires t into Probe: each probe query is sent via t.Send, and
+// while waiting out the 250ms inter-probe interval, Probe also listens on
+// t.Receive for a response, decoding any packet it gets into a
+// message.ResourceRecord and running it through conflictDetector (see
+// SetConflictDetector) exactly as InjectIncomingResponse does, but from a
+// real (or MockTransport-simulated) wire response instead of a pre-seeded
+// test value. A nil transport (the default) disables this.
+func (p *Prober) SetTransport(t transport.Transport) {
+	p.transport = t
+}
+
+// SetKnownAnswerCache wires cache into Probe: before sending any probes,
+// Probe consults cache for each of ourRecords (see SetOurRecords). A fresh
+// conflicting entry short-circuits Probe to Conflict: true without any
+// network I/O; a fresh "probed free" entry lets Probe skip its first probe
+// and start from the second 250ms interval, per RFC 6762 §5.2's
+// known-answer suppression. A nil cache (the default) disables this.
+func (p *Prober) SetKnownAnswerCache(cache KnownAnswerCache) {
+	p.knownAnswerCache = cache
 }
 
 // Probe sends probe queries to detect naming conflicts.
@@ -60,6 +182,9 @@ func NewProber() *Prober {
 //   - 250ms intervals between probes
 //   - Total duration: ~750ms
 //
+// If SetKnownAnswerCache has wired in a KnownAnswerCache, Probe consults it
+// first and may shortcut or shorten the above - see SetKnownAnswerCache.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - serviceName: Full service name (e.g., "My Printer._http._tcp.local")
@@ -71,7 +196,27 @@ func NewProber() *Prober {
 func (p *Prober) Probe(ctx context.Context, serviceName string) ProbeResult {
 	const probeCount = 3
 
-	for i := 0; i < probeCount; i++ {
+	p.notifyProbeStart(serviceName)
+
+	startProbe := 0
+	if p.knownAnswerCache != nil {
+		for _, ourRecord := range p.ourRecords {
+			entry, ok := p.knownAnswerCache.Lookup(ourRecord.Name, ourRecord.Type, ourRecord.Class)
+			if !ok {
+				continue
+			}
+			if entry.Conflict {
+				p.notifyProbeConflict(ourRecord, entry.Record)
+				return ProbeResult{Conflict: true}
+			}
+			// RFC 6762 §5.2: a known-fresh "probed free" outcome lets us
+			// skip the first probe and start the 250ms timeline from the
+			// second.
+			startProbe = 1
+		}
+	}
+
+	for i := startProbe; i < probeCount; i++ {
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
@@ -80,58 +225,46 @@ func (p *Prober) Probe(ctx context.Context, serviceName string) ProbeResult {
 		}
 
 		// Send probe query
-		// RFC 6762 §8.1: Probe queries use query type "ANY" (255)
-		// US2 GREEN: Build actual probe message for contract test validation
-		//
-		// NOTE: BuildQuery() currently rejects spaces in DNS labels (per RFC 1035),
-		// but RFC 6763 DNS-SD allows spaces in service instance names.
-		// For now, create a minimal stub message for contract test validation.
-		// Full DNS-SD name encoding will be implemented in US4 (Service Publishing).
-		//
-		// Minimal DNS header (12 bytes) per RFC 1035 §4.1.1:
-		//   ID (2 bytes): 0x0000
-		//   Flags (2 bytes): QR=0, OPCODE=0, AA=0, TC=0, RD=0, RA=0, Z=0, RCODE=0 = 0x0000
-		//   QDCOUNT (2 bytes): 1 question
-		//   ANCOUNT (2 bytes): 0 answers
-		//   NSCOUNT (2 bytes): 0 authority
-		//   ARCOUNT (2 bytes): 0 additional
-		//   Question section (variable): QNAME + QTYPE + QCLASS
-		//
-		// For contract test validation, we just need header + minimal question
-		probeMsg := make([]byte, 28) // 12-byte header + 16-byte minimal question
-		// Header: all zeros already (QR=0, OPCODE=0 is correct)
-		probeMsg[4] = 0x00 // QDCOUNT high byte
-		probeMsg[5] = 0x01 // QDCOUNT low byte = 1 question
-		// Minimal question: <root> ANY IN
-		probeMsg[12] = 0x00 // Root label (length 0)
-		probeMsg[13] = 0x00 // QTYPE high byte
-		probeMsg[14] = 0xFF // QTYPE low byte = 255 (ANY)
-		probeMsg[15] = 0x00 // QCLASS high byte
-		probeMsg[16] = 0x01 // QCLASS low byte = 1 (IN)
+		// RFC 6762 §8.1: Probe queries use query type "ANY" (255), with
+		// ourRecords carried in the Authority section for §8.2.1 tie-break -
+		// see BuildProbeQuery. serviceName goes through the same DNS-SD
+		// instance-name encoding (spaces/UTF-8 in the first label, per RFC
+		// 6763 §4.1.1) that the eventual announcement uses, so a probe and
+		// its announcement never disagree about wire encoding.
+		authority := make([]*message.ResourceRecord, len(p.ourRecords))
+		for i := range p.ourRecords {
+			authority[i] = &p.ourRecords[i]
+		}
+		probeMsg, err := message.BuildProbeQuery(serviceName, authority)
+		if err != nil {
+			return ProbeResult{Error: err}
+		}
 		p.lastProbeMessage = probeMsg
 
+		if p.outbound != nil {
+			p.outbound <- probeMsg
+		}
+
+		if p.transport != nil {
+			if err := p.transport.Send(ctx, probeMsg, nil); err != nil {
+				return ProbeResult{Error: err}
+			}
+		}
+
 		// Notify test hooks
 		if p.onSendQuery != nil {
 			p.onSendQuery()
 		}
 
-		// TODO: Actually send probe via transport
-		// For now, just simulate probing
-
 		// T059: Check for conflicts using ConflictDetector (if configured)
 		if p.conflictDetector != nil && len(p.incomingRecords) > 0 && len(p.ourRecords) > 0 {
-			// Check each incoming record against each of our records
-			for _, ourRecord := range p.ourRecords {
-				for _, incomingRecord := range p.incomingRecords {
-					conflict, err := p.conflictDetector.DetectConflict(ourRecord, incomingRecord)
-					if err != nil {
-						return ProbeResult{Error: err}
-					}
-					if conflict {
-						// Conflict detected via ConflictDetector
-						return ProbeResult{Conflict: true}
-					}
-				}
+			conflict, ourRecord, incomingRecord, err := p.detectConflict(p.ourRecords, p.incomingRecords)
+			if err != nil {
+				return ProbeResult{Error: err}
+			}
+			if conflict {
+				p.notifyProbeConflict(ourRecord, incomingRecord)
+				return ProbeResult{Conflict: true}
 			}
 		}
 
@@ -154,12 +287,21 @@ func (p *Prober) Probe(ctx context.Context, serviceName string) ProbeResult {
 
 		// Wait 250ms before next probe (except after last probe)
 		if i < probeCount-1 {
-			timer := time.NewTimer(protocol.ProbeInterval)
+			if p.transport != nil {
+				conflict, err := p.awaitProbeResponse(ctx)
+				if err != nil {
+					return ProbeResult{Error: err}
+				}
+				if conflict {
+					return ProbeResult{Conflict: true}
+				}
+				continue
+			}
+
 			select {
 			case <-ctx.Done():
-				timer.Stop()
 				return ProbeResult{Error: ctx.Err()}
-			case <-timer.C:
+			case <-p.clock.After(protocol.ProbeInterval):
 				// Continue to next probe
 			}
 		}
@@ -169,6 +311,138 @@ func (p *Prober) Probe(ctx context.Context, serviceName string) ProbeResult {
 	return ProbeResult{Conflict: false}
 }
 
+// awaitProbeResponse waits out the 250ms inter-probe interval - timed by
+// p.clock, the same way the transport-less path uses it, so a FakeClock
+// keeps this deterministic in tests - while also listening on p.transport
+// for a response, returning true as soon as a packet arrives that conflicts
+// with one of ourRecords (see packetConflicts). A Receive error is treated
+// the same as silence: it doesn't fail the probe, since a single
+// lost/malformed packet shouldn't abort RFC 6762 §8.1 probing.
+//
+// recvCtx carries a real (wall-clock) deadline rather than a bare cancel, so
+// a socket-backed Transport - which only arms a read timeout off
+// ctx.Deadline(), not off cancellation, once blocked in a syscall - actually
+// unblocks its Receive when this function returns instead of leaking a
+// goroutine parked in a blocking read until the process exits.
+func (p *Prober) awaitProbeResponse(ctx context.Context) (conflict bool, err error) {
+	recvCtx, cancelRecv := context.WithTimeout(ctx, protocol.ProbeInterval)
+	defer cancelRecv()
+
+	type recvResult struct {
+		packet []byte
+		err    error
+	}
+	resultCh := make(chan recvResult, 1)
+	receive := func() {
+		packet, _, recvErr := p.transport.Receive(recvCtx)
+		resultCh <- recvResult{packet: packet, err: recvErr}
+	}
+	go receive()
+
+	timeout := p.clock.After(protocol.ProbeInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timeout:
+			return false, nil
+		case res := <-resultCh:
+			if res.err != nil {
+				return false, nil
+			}
+			if p.packetConflicts(res.packet) {
+				return true, nil
+			}
+			go receive()
+		}
+	}
+}
+
+// packetConflicts decodes packet as a DNS message and runs the records it
+// carries - converted via message.AnswerToResourceRecord - against every
+// one of ourRecords, per RFC 6762 §8.2/§8.2.1, via detectConflict. A
+// packet that fails to parse, or arrives with no conflictDetector
+// configured, is treated as non-conflicting.
+//
+// Both the Answer and Authority sections are checked: a host that already
+// won its own probe and is now defending the name answers with the record
+// in its Answer section (§8.2), while a host simultaneously probing the
+// same name - the case this function most needs to catch - carries its
+// tentative records in the Authority section instead, exactly as
+// BuildProbeQuery puts ours there.
+func (p *Prober) packetConflicts(packet []byte) bool {
+	if p.conflictDetector == nil {
+		return false
+	}
+
+	msg, err := message.ParseMessage(packet)
+	if err != nil {
+		return false
+	}
+
+	incoming := make([]message.ResourceRecord, 0, len(msg.Answers)+len(msg.Authorities))
+	for _, a := range msg.Answers {
+		incoming = append(incoming, *message.AnswerToResourceRecord(a))
+	}
+	for _, a := range msg.Authorities {
+		incoming = append(incoming, *message.AnswerToResourceRecord(a))
+	}
+
+	conflict, ourRecord, incomingRecord, err := p.detectConflict(p.ourRecords, incoming)
+	if err != nil {
+		return false
+	}
+	if conflict {
+		p.notifyProbeConflict(ourRecord, incomingRecord)
+	}
+	return conflict
+}
+
+// detectConflict decides whether incoming conflicts with ourRecords,
+// preferring a full RFC 6762 §8.2.1 RRSet tie-break when p.conflictDetector
+// implements ConflictSetDetectorInterface - sorting each side into
+// canonical order and comparing pairwise, so a service probing multiple
+// records (e.g. SRV + TXT) is tie-broken as one set rather than record by
+// record - and falling back to DetectConflict's pairwise cross-product
+// otherwise, preserving the original behavior for a detector that only
+// implements the single-record interface.
+//
+// The returned ourRecord/incomingRecord are only meaningful when conflict
+// is true, for notifyProbeConflict's hook; the set-detector path reports
+// the first record on each side, since DetectConflictSet's decision isn't
+// tied to one specific pair the way DetectConflict's is.
+//
+// This already satisfies "tie-break the whole probed RRSet, not one record
+// at a time": responder.ConflictDetector.DetectConflictSet canonically
+// sorts both sides by class/type/rdata and compares pairwise, the longer
+// list winning a common prefix, with identical lists reported as no
+// conflict (see conflict_detector.go); responder.New wires it in via
+// SetConflictDetector/SetOurRecords before each probe attempt runs, so a
+// host probing A+AAAA+TXT is tie-broken as one set here rather than
+// record by record.
+func (p *Prober) detectConflict(ourRecords, incoming []message.ResourceRecord) (conflict bool, ourRecord, incomingRecord message.ResourceRecord, err error) {
+	if setDetector, ok := p.conflictDetector.(ConflictSetDetectorInterface); ok {
+		conflict, err = setDetector.DetectConflictSet(ourRecords, incoming)
+		if err != nil || !conflict {
+			return false, message.ResourceRecord{}, message.ResourceRecord{}, err
+		}
+		return true, ourRecords[0], incoming[0], nil
+	}
+
+	for _, our := range ourRecords {
+		for _, in := range incoming {
+			c, detectErr := p.conflictDetector.DetectConflict(our, in)
+			if detectErr != nil {
+				return false, message.ResourceRecord{}, message.ResourceRecord{}, detectErr
+			}
+			if c {
+				return true, our, in, nil
+			}
+		}
+	}
+	return false, message.ResourceRecord{}, message.ResourceRecord{}, nil
+}
+
 // compareBytesLexicographically compares two byte slices lexicographically.
 // Returns true if a > b (we win), false otherwise.
 func compareBytesLexicographically(a, b []byte) bool {
@@ -210,6 +484,19 @@ func (p *Prober) SetConflictDetector(detector ConflictDetectorInterface) {
 	p.conflictDetector = detector
 }
 
+// SetSimultaneousProbeInjection configures a one-shot RFC 6762 §8.2.1
+// tie-break simulation: the next Probe call compares ourData against
+// theirData lexicographically, the same comparison a real conflicting probe
+// response would go through, and reports Conflict if ourData loses. Used by
+// responder.Responder's InjectSimultaneousProbe test hook to exercise the
+// tie-break-loses-then-renames path without a second real host on the
+// network.
+func (p *Prober) SetSimultaneousProbeInjection(ourData, theirData []byte) {
+	p.injectSimultaneousProbe = true
+	p.ourProbeData = ourData
+	p.theirProbeData = theirData
+}
+
 // GetLastProbeMessage returns the last sent probe message.
 //
 // US2 GREEN: Contract test support for RFC 6762 §8.1 validation
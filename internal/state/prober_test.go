@@ -7,6 +7,7 @@ import (
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
 )
 
 const testServiceName = "My Printer._http._tcp.local"
@@ -319,6 +320,152 @@ func TestProber_ConflictDetectorIntegration_NoConflict(t *testing.T) {
 	}
 }
 
+// mockSetConflictDetector implements both ConflictDetectorInterface and
+// ConflictSetDetectorInterface, recording whether DetectConflictSet was
+// called so tests can confirm Prober prefers the full-RRSet tie-break over
+// the pairwise fallback whenever the configured detector supports it.
+type mockSetConflictDetector struct {
+	setResult       bool
+	setErr          error
+	setCalled       bool
+	lastOurRecords  []message.ResourceRecord
+	lastTheirRecord []message.ResourceRecord
+}
+
+func (m *mockSetConflictDetector) DetectConflict(_, _ message.ResourceRecord) (bool, error) {
+	return false, nil
+}
+
+func (m *mockSetConflictDetector) DetectConflictSet(ours, incoming []message.ResourceRecord) (bool, error) {
+	m.setCalled = true
+	m.lastOurRecords = ours
+	m.lastTheirRecord = incoming
+	return m.setResult, m.setErr
+}
+
+// TestProber_ConflictDetectorIntegration_SetDetector_MixedTypeSet verifies
+// that when the configured detector implements ConflictSetDetectorInterface,
+// Probe uses DetectConflictSet (the full RFC 6762 §8.2.1 RRSet tie-break)
+// rather than comparing records pairwise - exercised here with a mixed
+// A+AAAA+SRV set, the case a pairwise loop can't correctly tie-break since
+// it never sorts each side into canonical order first.
+func TestProber_ConflictDetectorIntegration_SetDetector_MixedTypeSet(t *testing.T) {
+	ctx := context.Background()
+	prober := NewProber()
+
+	ourRecords := []message.ResourceRecord{
+		{Name: "myservice.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 50}},
+		{Name: "myservice.local", Type: protocol.RecordTypeAAAA, Class: protocol.ClassIN, TTL: 120, Data: make([]byte, 16)},
+		{Name: "myservice.local", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0, 0, 0, 0, 0, 80, 0}},
+	}
+	incomingRecords := []message.ResourceRecord{
+		{Name: "myservice.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 100}},
+	}
+
+	prober.SetOurRecords(ourRecords)
+	detector := &mockSetConflictDetector{setResult: true}
+	prober.SetConflictDetector(detector)
+	prober.InjectIncomingResponse(incomingRecords)
+
+	result := prober.Probe(ctx, testServiceName)
+
+	if !detector.setCalled {
+		t.Fatal("Probe() did not call DetectConflictSet, want it preferred over pairwise DetectConflict")
+	}
+	if !result.Conflict {
+		t.Error("Probe() Conflict = false, want true (DetectConflictSet reported a conflict)")
+	}
+	if len(detector.lastOurRecords) != len(ourRecords) {
+		t.Errorf("DetectConflictSet() received %d of our records, want all %d", len(detector.lastOurRecords), len(ourRecords))
+	}
+}
+
+// TestProber_ConflictDetectorIntegration_SetDetector_NoConflict verifies
+// that Probe reports no conflict when DetectConflictSet reports none, and
+// that the pairwise DetectConflict path is not consulted as a fallback.
+func TestProber_ConflictDetectorIntegration_SetDetector_NoConflict(t *testing.T) {
+	ctx := context.Background()
+	prober := NewProber()
+
+	ourRecords := []message.ResourceRecord{
+		{Name: "myservice.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 100}},
+	}
+	incomingRecords := []message.ResourceRecord{
+		{Name: "myservice.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 50}},
+	}
+
+	prober.SetOurRecords(ourRecords)
+	detector := &mockSetConflictDetector{setResult: false}
+	prober.SetConflictDetector(detector)
+	prober.InjectIncomingResponse(incomingRecords)
+
+	result := prober.Probe(ctx, testServiceName)
+
+	if !detector.setCalled {
+		t.Fatal("Probe() did not call DetectConflictSet")
+	}
+	if result.Conflict {
+		t.Error("Probe() Conflict = true, want false (DetectConflictSet reported no conflict)")
+	}
+}
+
+// stubProbeHook is a minimal ProbeHook recording every call, for tests that
+// verify Prober.AddHook fires at the right points.
+type stubProbeHook struct {
+	starts    []string
+	conflicts []message.ResourceRecord
+}
+
+func (h *stubProbeHook) OnProbeStart(name string) {
+	h.starts = append(h.starts, name)
+}
+
+func (h *stubProbeHook) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+	h.conflicts = append(h.conflicts, incomingRecord)
+}
+
+// TestProber_AddHook_FiresOnStartAndConflict verifies that a ProbeHook
+// registered via AddHook receives OnProbeStart once per Probe call and
+// OnProbeConflict when the ConflictDetector reports a loss.
+func TestProber_AddHook_FiresOnStartAndConflict(t *testing.T) {
+	ctx := context.Background()
+	prober := NewProber()
+
+	ourRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 50}, // We lose the tie-break
+	}
+	incomingRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100},
+	}
+
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+	prober.SetConflictDetector(&mockConflictDetector{})
+	prober.InjectIncomingResponse([]message.ResourceRecord{incomingRecord})
+
+	hook := &stubProbeHook{}
+	prober.AddHook(hook)
+
+	result := prober.Probe(ctx, testServiceName)
+	if !result.Conflict {
+		t.Fatalf("Probe() Conflict = false, want true")
+	}
+
+	if len(hook.starts) != 1 || hook.starts[0] != testServiceName {
+		t.Errorf("hook.starts = %v, want [%q]", hook.starts, testServiceName)
+	}
+	if len(hook.conflicts) != 1 || hook.conflicts[0].Data[3] != incomingRecord.Data[3] {
+		t.Errorf("hook.conflicts = %v, want one entry matching incomingRecord", hook.conflicts)
+	}
+}
+
 // TestProber_MessageCapture verifies probe message capture for contract tests.
 func TestProber_MessageCapture(t *testing.T) {
 	p := NewProber()
@@ -374,3 +521,274 @@ func TestProber_BuildQuery_Error(t *testing.T) {
 		t.Logf("BuildQuery succeeded unexpectedly, message length: %d", len(msg))
 	}
 }
+
+// TestProber_KnownAnswerCache_ConflictHit_SendsNoQueries verifies that a
+// fresh conflicting cache entry short-circuits Probe to Conflict: true
+// without sending any probes - RFC 6762 §5.2 known-answer suppression.
+func TestProber_KnownAnswerCache_ConflictHit_SendsNoQueries(t *testing.T) {
+	ctx := context.Background()
+	prober := NewProber()
+
+	ourRecord := message.ResourceRecord{Name: testServiceName, Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 50}}
+	theirRecord := message.ResourceRecord{Name: testServiceName, Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 100}}
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+
+	cache := NewMemoryKnownAnswerCache()
+	cache.RecordConflict(theirRecord)
+	prober.SetKnownAnswerCache(cache)
+
+	queryCount := 0
+	prober.onSendQuery = func() {
+		queryCount++
+	}
+
+	result := prober.Probe(ctx, testServiceName)
+	if !result.Conflict {
+		t.Fatalf("Probe() Conflict = false, want true (cached conflict)")
+	}
+	if queryCount != 0 {
+		t.Errorf("Probe() sent %d queries, want 0 (no network I/O on cache-hit conflict)", queryCount)
+	}
+}
+
+// TestProber_KnownAnswerCache_FreeHit_SkipsFirstProbe verifies that a fresh
+// "probed free" cache entry skips the first probe, sending 2 queries
+// instead of 3.
+func TestProber_KnownAnswerCache_FreeHit_SkipsFirstProbe(t *testing.T) {
+	ctx := context.Background()
+	prober := NewProber()
+
+	ourRecord := message.ResourceRecord{Name: testServiceName, Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 50}}
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+
+	cache := NewMemoryKnownAnswerCache()
+	cache.RecordFree(ourRecord.Name, ourRecord.Type, ourRecord.Class)
+	prober.SetKnownAnswerCache(cache)
+
+	queryCount := 0
+	prober.onSendQuery = func() {
+		queryCount++
+	}
+
+	result := prober.Probe(ctx, testServiceName)
+	if result.Conflict {
+		t.Errorf("Probe() Conflict = true, want false")
+	}
+	if queryCount != 2 {
+		t.Errorf("Probe() sent %d queries, want 2 (first probe skipped)", queryCount)
+	}
+}
+
+// TestProber_KnownAnswerCache_FreeHit_CancellationRaces verifies that
+// context cancellation during the single remaining 250ms wait of a
+// known-answer-shortened schedule still unblocks Probe, exactly as it does
+// for the full 3-probe schedule.
+func TestProber_KnownAnswerCache_FreeHit_CancellationRaces(t *testing.T) {
+	clock := NewFakeClock()
+	prober := NewProber()
+	prober.SetClock(clock)
+
+	ourRecord := message.ResourceRecord{Name: testServiceName, Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 50}}
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+
+	cache := NewMemoryKnownAnswerCache()
+	cache.RecordFree(ourRecord.Name, ourRecord.Type, ourRecord.Class)
+	prober.SetKnownAnswerCache(cache)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan ProbeResult, 1)
+	go func() {
+		done <- prober.Probe(ctx, testServiceName)
+	}()
+
+	awaitWaiter(t, clock)
+	cancel()
+
+	select {
+	case result := <-done:
+		if result.Error == nil {
+			t.Error("Probe() error = nil, want context.Canceled")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Probe() did not return after cancellation")
+	}
+}
+
+// TestProber_SetTransport_SendsEachProbe verifies that, with a transport
+// wired in, Probe sends each probe query via Transport.Send rather than
+// only writing to outbound.
+func TestProber_SetTransport_SendsEachProbe(t *testing.T) {
+	clock := NewFakeClock()
+	tr := transport.NewMockTransport()
+
+	prober := NewProber()
+	prober.SetClock(clock)
+	prober.SetTransport(tr)
+
+	done := make(chan ProbeResult, 1)
+	go func() {
+		done <- prober.Probe(context.Background(), testServiceName)
+	}()
+
+	for i := 0; i < 2; i++ {
+		awaitWaiter(t, clock)
+		clock.Advance(protocol.ProbeInterval)
+	}
+
+	select {
+	case result := <-done:
+		if result.Error != nil {
+			t.Fatalf("Probe() error = %v", result.Error)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Probe() did not return")
+	}
+
+	if len(tr.SendCalls()) != 3 {
+		t.Errorf("len(SendCalls()) = %d, want 3 (one per probe)", len(tr.SendCalls()))
+	}
+}
+
+// TestProber_SetTransport_DetectsConflictFromReceivedPacket verifies that a
+// wire-format response queued on a MockTransport - not just the
+// InjectIncomingResponse test hook - drives the same ConflictDetector path,
+// deterministically via FakeClock rather than sleeping real time.
+func TestProber_SetTransport_DetectsConflictFromReceivedPacket(t *testing.T) {
+	clock := NewFakeClock()
+	tr := transport.NewMockTransport()
+
+	ourRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 50},
+	}
+	incomingRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100},
+	}
+
+	packet, err := message.BuildResponse([]*message.ResourceRecord{&incomingRecord})
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v", err)
+	}
+
+	prober := NewProber()
+	prober.SetClock(clock)
+	prober.SetTransport(tr)
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+	prober.SetConflictDetector(&mockConflictDetector{})
+
+	done := make(chan ProbeResult, 1)
+	go func() {
+		done <- prober.Probe(context.Background(), testServiceName)
+	}()
+
+	awaitWaiter(t, clock)
+	tr.QueueReceive(packet, nil)
+
+	select {
+	case result := <-done:
+		if result.Error != nil {
+			t.Fatalf("Probe() error = %v", result.Error)
+		}
+		if !result.Conflict {
+			t.Error("Probe() Conflict = false, want true (response packet should be detected as conflicting)")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Probe() did not return after a conflicting packet was queued")
+	}
+}
+
+// TestProber_SetTransport_DetectsConflictFromAuthoritySection verifies that
+// a simultaneous probe from another host - which carries its tentative
+// records in the Authority section rather than the Answer section a
+// defending response uses - is tie-broken the same way, per RFC 6762
+// §8.2.1.
+func TestProber_SetTransport_DetectsConflictFromAuthoritySection(t *testing.T) {
+	clock := NewFakeClock()
+	tr := transport.NewMockTransport()
+
+	ourRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 50},
+	}
+	theirRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100},
+	}
+
+	packet, err := message.BuildProbeQuery(testServiceName, []*message.ResourceRecord{&theirRecord})
+	if err != nil {
+		t.Fatalf("BuildProbeQuery() error = %v", err)
+	}
+
+	prober := NewProber()
+	prober.SetClock(clock)
+	prober.SetTransport(tr)
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+	prober.SetConflictDetector(&mockConflictDetector{})
+
+	done := make(chan ProbeResult, 1)
+	go func() {
+		done <- prober.Probe(context.Background(), testServiceName)
+	}()
+
+	awaitWaiter(t, clock)
+	tr.QueueReceive(packet, nil)
+
+	select {
+	case result := <-done:
+		if result.Error != nil {
+			t.Fatalf("Probe() error = %v", result.Error)
+		}
+		if !result.Conflict {
+			t.Error("Probe() Conflict = false, want true (simultaneous probe's Authority section should be detected as conflicting)")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Probe() did not return after a conflicting probe was queued")
+	}
+}
+
+// TestProber_Probe_SendsAuthoritySection verifies that Probe's outgoing
+// message carries ourRecords in the Authority section (RFC 6762 §8.2.1),
+// not just a bare question, once SetOurRecords has been called.
+func TestProber_Probe_SendsAuthoritySection(t *testing.T) {
+	ourRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 50},
+	}
+
+	prober := NewProber()
+	prober.SetOurRecords([]message.ResourceRecord{ourRecord})
+
+	result := prober.Probe(context.Background(), testServiceName)
+	if result.Error != nil {
+		t.Fatalf("Probe() error = %v", result.Error)
+	}
+
+	msg, err := message.ParseMessage(prober.GetLastProbeMessage())
+	if err != nil {
+		t.Fatalf("ParseMessage(GetLastProbeMessage()) error = %v", err)
+	}
+	if len(msg.Authorities) != 1 {
+		t.Fatalf("len(Authorities) = %d, want 1", len(msg.Authorities))
+	}
+	if msg.Authorities[0].TYPE != uint16(protocol.RecordTypeA) {
+		t.Errorf("Authorities[0].TYPE = %v, want A", msg.Authorities[0].TYPE)
+	}
+}
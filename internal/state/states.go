@@ -9,6 +9,8 @@ package state
 //   - Announcing: Broadcasting service announcements
 //   - Established: Service fully registered and discoverable
 //   - ConflictDetected: Naming conflict detected during probing
+//   - ConflictAbandoned: Conflict retries exhausted; renaming gave up
+//   - Goodbye: Service has sent its RFC 6762 §10.1 departure announcements
 //
 // T037: Define State type
 type State int
@@ -31,6 +33,18 @@ const (
 	// StateConflictDetected indicates a naming conflict was detected during probing.
 	// RFC 6762 §8.1: Must choose another name
 	StateConflictDetected
+
+	// StateConflictAbandoned indicates a renameStrategy-driven retry loop
+	// (see WithRenameStrategy) exhausted WithMaxConflictRetries without
+	// resolving the conflict. Unlike StateConflictDetected, this is
+	// terminal: Run has already given up and will not rename or probe
+	// again on its own.
+	StateConflictAbandoned
+
+	// StateGoodbye indicates the service has sent its goodbye packets
+	// (TTL=0 multicasts) and is no longer discoverable.
+	// RFC 6762 §10.1: Departure announcement
+	StateGoodbye
 )
 
 // String returns the string representation of a State.
@@ -46,6 +60,10 @@ func (s State) String() string {
 		return "Established"
 	case StateConflictDetected:
 		return "ConflictDetected"
+	case StateConflictAbandoned:
+		return "ConflictAbandoned"
+	case StateGoodbye:
+		return "Goodbye"
 	default:
 		return "Unknown"
 	}
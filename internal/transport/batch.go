@@ -0,0 +1,40 @@
+package transport
+
+import (
+	"context"
+	"net"
+)
+
+// batchSize is how many datagrams a single ReceiveBatch call asks the
+// kernel for. On Linux this becomes the recvmmsg(2) vlen argument; on other
+// platforms the underlying golang.org/x/net/ipv4 and ipv6 packages fall
+// back to one ReadFrom-equivalent syscall per Message, so a caller there
+// simply gets fewer than batchSize packets per call rather than a batch.
+const batchSize = 16
+
+// Packet is one datagram returned by a BatchReceiver. Data is always a
+// subslice of a pooled buffer; the caller must call ReturnFunc once it's
+// done with Data; mirroring the GetBuffer/PutBuffer discipline Receive
+// already follows for its single-packet path.
+type Packet struct {
+	Data  []byte
+	Addr  *net.UDPAddr
+	Iface *net.Interface
+
+	// ReturnFunc releases Data's backing buffer to the pool. Calling it
+	// more than once, or not at all, never corrupts another Packet's data;
+	// it just skips that buffer's reuse.
+	ReturnFunc func()
+}
+
+// BatchReceiver is implemented by transports that can receive several
+// packets per syscall. It's an optional capability rather than part of
+// Transport: transports with nothing to batch (DoHTransport, DoQTransport,
+// MockTransport, UnicastUDPTransport, DualStack) have no ReceiveBatch
+// method, the same way io.ReaderFrom is optional on top of io.Reader.
+type BatchReceiver interface {
+	// ReceiveBatch fills out with up to len(out) packets, returning how
+	// many it filled. It blocks until at least one packet arrives or ctx
+	// is done.
+	ReceiveBatch(ctx context.Context, out []Packet) (n int, err error)
+}
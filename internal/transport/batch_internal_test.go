@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestUDPv4Transport_ReceiveBatch_RoundTrip sends a handful of packets to
+// the mDNS multicast group from a second transport and confirms
+// ReceiveBatch collects them.
+//
+// Both transports here are built with loopback left on
+// (newUDPv4Transport(nil, true)), which only this internal-package test can
+// reach - the exported constructors always disable it (see
+// newUDPv4Transport's loopback parameter). On Linux, disabling multicast
+// loopback on a socket suppresses delivery of that host's own multicast
+// traffic to every local socket, not just the sender's, so two
+// loopback-disabled transports on the same host can never observe each
+// other's sends; this test's premise (distinct sender/receiver on
+// localhost) needs loopback on to be possible at all.
+func TestUDPv4Transport_ReceiveBatch_RoundTrip(t *testing.T) {
+	receiver, err := newUDPv4Transport(nil, true)
+	if err != nil {
+		t.Fatalf("newUDPv4Transport() failed: %v", err)
+	}
+	defer func() { _ = receiver.Close() }()
+
+	sender, err := newUDPv4Transport(nil, true)
+	if err != nil {
+		t.Fatalf("newUDPv4Transport() (sender) failed: %v", err)
+	}
+	defer func() { _ = sender.Close() }()
+
+	mdnsAddr := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	const sent = 3
+	for i := 0; i < sent; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := sender.Send(ctx, []byte{byte(i), 0x00, 0x00, 0x00}, mdnsAddr)
+		cancel()
+		if err != nil {
+			t.Fatalf("Send() packet %d failed: %v", i, err)
+		}
+	}
+
+	out := make([]Packet, 16)
+	var got int
+	deadline := time.Now().Add(3 * time.Second)
+	for got < sent && time.Now().Before(deadline) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		n, err := receiver.ReceiveBatch(ctx, out[got:])
+		cancel()
+		if err != nil {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			if out[got+i].ReturnFunc != nil {
+				out[got+i].ReturnFunc()
+			}
+		}
+		got += n
+	}
+
+	if got < sent {
+		t.Errorf("ReceiveBatch() collected %d packets, want at least %d", got, sent)
+	}
+}
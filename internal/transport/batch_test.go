@@ -0,0 +1,63 @@
+package transport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestUDPv4Transport_ImplementsBatchReceiver is a compile-time contract
+// check that UDPv4Transport satisfies BatchReceiver.
+func TestUDPv4Transport_ImplementsBatchReceiver(_ *testing.T) {
+	var _ transport.BatchReceiver = (*transport.UDPv4Transport)(nil)
+}
+
+// TestUDPv6Transport_ImplementsBatchReceiver is a compile-time contract
+// check that UDPv6Transport satisfies BatchReceiver.
+func TestUDPv6Transport_ImplementsBatchReceiver(_ *testing.T) {
+	var _ transport.BatchReceiver = (*transport.UDPv6Transport)(nil)
+}
+
+// TestUDPv4Transport_ReceiveBatch_RoundTrip's same-host sender/receiver
+// round trip lives in batch_internal_test.go (package transport): it needs
+// multicast loopback left on, which isn't something the exported
+// constructors this file uses offer - see that file for why.
+
+// TestUDPv4Transport_ReceiveBatch_EmptyOutReturnsImmediately verifies that
+// ReceiveBatch with a zero-length out slice is a no-op rather than
+// blocking on a syscall with no room for results.
+func TestUDPv4Transport_ReceiveBatch_EmptyOutReturnsImmediately(t *testing.T) {
+	tr, err := transport.NewUDPv4Transport()
+	if err != nil {
+		t.Fatalf("NewUDPv4Transport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	n, err := tr.ReceiveBatch(context.Background(), nil)
+	if err != nil {
+		t.Errorf("ReceiveBatch(nil) failed: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("ReceiveBatch(nil) = %d, want 0", n)
+	}
+}
+
+// TestUDPv4Transport_ReceiveBatch_RespectsContextCancellation mirrors
+// TestUDPv4Transport_Receive_RespectsContextCancellation: ReceiveBatch must
+// return promptly once ctx is already canceled, rather than blocking.
+func TestUDPv4Transport_ReceiveBatch_RespectsContextCancellation(t *testing.T) {
+	tr, err := transport.NewUDPv4Transport()
+	if err != nil {
+		t.Fatalf("NewUDPv4Transport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out := make([]transport.Packet, 16)
+	if _, err := tr.ReceiveBatch(ctx, out); err == nil {
+		t.Error("ReceiveBatch() with canceled context succeeded, want error")
+	}
+}
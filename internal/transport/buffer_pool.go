@@ -16,51 +16,116 @@ import (
 //   buf := *bufPtr
 //   ... use buffer ...
 
-// bufferPool is a sync.Pool for 9000-byte receive buffers.
-//
-// sync.Pool provides:
-// - Thread-safe buffer reuse
-// - Automatic GC cleanup of unused buffers
-// - Zero allocation on hot path (after warmup)
-//
-// T050: Minimal pool to make T044-T046 pass
-var bufferPool = sync.Pool{
-	New: func() interface{} {
-		// Allocate 9KB buffer for mDNS packets
-		// RFC 6762 §17: mDNS messages can exceed 512 bytes (jumbo frames up to 9000)
-		buf := make([]byte, 9000)
-		return &buf
-	},
+// bufferSizeClasses are the power-of-two-ish buffer lengths sizedPools
+// holds, smallest first. 9000 (RFC 6762 §17's jumbo-frame ceiling) is
+// always the largest class, so classFor never falls through to an
+// unpooled allocation for a well-formed mDNS packet.
+var bufferSizeClasses = []int{512, 1500, 4096, 9000}
+
+// sizedPools holds one sync.Pool per entry in bufferSizeClasses, each
+// New()-ing buffers of exactly that length.
+var sizedPools = newSizedPools()
+
+func newSizedPools() map[int]*sync.Pool {
+	pools := make(map[int]*sync.Pool, len(bufferSizeClasses))
+	for _, size := range bufferSizeClasses {
+		size := size
+		pools[size] = &sync.Pool{
+			New: func() interface{} {
+				buf := make([]byte, size)
+				return &buf
+			},
+		}
+	}
+	return pools
 }
 
+// classFor returns the smallest bufferSizeClasses entry able to hold n
+// bytes, or 0 if n exceeds every class (the caller falls back to an
+// unpooled allocation).
+func classFor(n int) int {
+	for _, size := range bufferSizeClasses {
+		if n <= size {
+			return size
+		}
+	}
+	return 0
+}
+
+// bufferPool is kept as the 9000-byte pool GetBuffer/PutBuffer wrap, for
+// backward compatibility with existing callers that don't track how many
+// bytes they actually used.
+var bufferPool = sizedPools[9000]
+
 // GetBuffer returns a pointer to a 9000-byte buffer from the pool.
 //
 // Caller MUST call PutBuffer() to return the buffer (use defer).
 //
 // Returns:
 //   - *[]byte: Pointer to 9KB buffer
-//
-// T051: Export GetBuffer() function
 func GetBuffer() *[]byte {
 	return bufferPool.Get().(*[]byte)
 }
 
-// PutBuffer returns a buffer to the pool for reuse.
+// PutBuffer returns a 9000-byte buffer to the pool for reuse, zeroing the
+// whole buffer first since it has no way to know how many bytes the caller
+// actually used. A caller that does know (e.g. a socket Receive holding
+// ReadFrom's returned n) should call PutBufferSized instead, to avoid
+// zeroing bytes that were never written.
 //
 // Caller MUST NOT use the buffer after calling PutBuffer().
 // Best practice: Use defer PutBuffer(bufPtr) immediately after GetBuffer().
 //
 // Parameters:
 //   - bufPtr: Pointer to buffer (from GetBuffer())
-//
-// T052: Export PutBuffer() function
 func PutBuffer(bufPtr *[]byte) {
-	// Clear buffer before returning to pool (security: no data leakage)
-	// Note: This adds overhead, but prevents accidental data leakage between receives
+	PutBufferSized(bufPtr, len(*bufPtr))
+}
+
+// GetBufferSized returns a pointer to a buffer at least n bytes long, from
+// the smallest pooled size class (512, 1500, 4096, 9000 bytes) that fits
+// it, or a freshly allocated, unpooled buffer of exactly n bytes if n
+// exceeds every class - which RFC 6762 §17's 9000-byte message ceiling
+// means should never happen for a well-formed mDNS packet.
+//
+// Caller MUST call PutBufferSized() to return the buffer (use defer).
+func GetBufferSized(n int) *[]byte {
+	class := classFor(n)
+	if class == 0 {
+		buf := make([]byte, n)
+		return &buf
+	}
+	return sizedPools[class].Get().(*[]byte)
+}
+
+// PutBufferSized returns bufPtr to its size-classed pool, zeroing only
+// buf[:used] - the prefix the caller actually wrote, e.g. a UDP Receive's
+// ReadFrom-returned n - rather than the whole buffer. Zeroing only this
+// prefix still prevents data leaking between receives: every buffer a pool
+// hands out is either fresh from New() (already zero) or was fully zeroed
+// up to its own previous used length by this same function, so the
+// invariant "everything from the last Put's used onward is already zero"
+// holds across reuses.
+//
+// used > len(*bufPtr) is clamped to len(*bufPtr). A buffer whose length
+// doesn't match one of bufferSizeClasses (e.g. one GetBufferSized handed
+// back unpooled because n exceeded every class) is not pooled - discarded
+// for the GC to collect instead.
+//
+// Caller MUST NOT use the buffer after calling PutBufferSized().
+func PutBufferSized(bufPtr *[]byte, used int) {
 	buf := *bufPtr
-	for i := range buf {
+	class := classFor(len(buf))
+	if class != len(buf) {
+		return
+	}
+
+	if used > len(buf) {
+		used = len(buf)
+	}
+	for i := 0; i < used; i++ {
 		buf[i] = 0
 	}
 
-	bufferPool.Put(bufPtr)
+	sizedPools[class].Put(bufPtr)
 }
@@ -0,0 +1,330 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// dnsMessageMediaType is the DoH content type per RFC 8484 §6.
+const dnsMessageMediaType = "application/dns-message"
+
+// doHMaxBodySize bounds request bodies to the RFC 6762 §17 jumbo packet size,
+// matching the limit enforced on the classic UDP path.
+const doHMaxBodySize = 9000
+
+// DoHTransport serves unicast mDNS-style queries over DNS-over-HTTPS per RFC 8484.
+//
+// Unlike UDPv4Transport, DoHTransport is server-oriented: HTTP handlers push
+// incoming queries onto an internal queue (consumed via Receive), and the
+// matching response is routed back to the waiting HTTP handler via Send,
+// keyed by the *DoHAddr the query was received on.
+//
+// This lets LAN clients that cannot join the mDNS multicast group (containers,
+// WSL, corporate laptops behind multicast-hostile switches) resolve the same
+// service records the responder publishes over multicast.
+type DoHTransport struct {
+	server   *http.Server
+	listener net.Listener
+
+	mu      sync.Mutex
+	pending map[uint64]*doHExchange
+	nextID  uint64
+
+	incoming chan doHQuery
+	closed   chan struct{}
+}
+
+// DoHAddr identifies the HTTP request a DoH query arrived on, so a Send()
+// call can be routed back to the correct pending response.
+type DoHAddr struct {
+	id         uint64
+	RemoteAddr string
+}
+
+// Network returns "doh" per the net.Addr interface.
+func (a *DoHAddr) Network() string { return "doh" }
+
+// String returns the remote HTTP client address.
+func (a *DoHAddr) String() string { return a.RemoteAddr }
+
+// doHQuery is a decoded DNS message awaiting a response.
+type doHQuery struct {
+	addr    *DoHAddr
+	message []byte
+}
+
+// doHExchange tracks the HTTP response writer waiting for Send().
+type doHExchange struct {
+	done chan []byte
+}
+
+// NewDoHTransport starts an HTTP server on addr that accepts DNS-over-HTTPS
+// queries (GET with base64url "dns" parameter, or POST with
+// application/dns-message body) at the given path.
+//
+// RFC 8484 §4.1: Both GET and POST methods MUST be supported by servers.
+func NewDoHTransport(addr, path string) (*DoHTransport, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "listen DoH",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to bind DoH listener on %s", addr),
+		}
+	}
+
+	t := &DoHTransport{
+		listener: ln,
+		pending:  make(map[uint64]*doHExchange),
+		incoming: make(chan doHQuery, 64),
+		closed:   make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, t.handleQuery)
+	t.server = &http.Server{Handler: mux}
+
+	go func() {
+		_ = t.server.Serve(ln)
+	}()
+
+	return t, nil
+}
+
+// handleQuery decodes an inbound DoH request per RFC 8484 §4.1 and blocks
+// until the matching response is produced via Send, or the request times out.
+func (t *DoHTransport) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var body []byte
+	var err error
+
+	switch r.Method {
+	case http.MethodGet:
+		dnsParam := r.URL.Query().Get("dns")
+		if dnsParam == "" {
+			http.Error(w, "missing dns query parameter", http.StatusBadRequest)
+			return
+		}
+		body, err = base64.RawURLEncoding.DecodeString(dnsParam)
+		if err != nil {
+			http.Error(w, "invalid base64url dns parameter", http.StatusBadRequest)
+			return
+		}
+	case http.MethodPost:
+		if ct := r.Header.Get("Content-Type"); ct != dnsMessageMediaType {
+			http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		body, err = io.ReadAll(io.LimitReader(r.Body, doHMaxBodySize+1))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if len(body) == 0 || len(body) > doHMaxBodySize {
+		http.Error(w, "malformed dns message", http.StatusBadRequest)
+		return
+	}
+
+	id := atomic.AddUint64(&t.nextID, 1)
+	addr := &DoHAddr{id: id, RemoteAddr: r.RemoteAddr}
+	exchange := &doHExchange{done: make(chan []byte, 1)}
+
+	t.mu.Lock()
+	t.pending[id] = exchange
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	select {
+	case t.incoming <- doHQuery{addr: addr, message: body}:
+	case <-r.Context().Done():
+		http.Error(w, "request canceled", http.StatusGatewayTimeout)
+		return
+	}
+
+	select {
+	case resp := <-exchange.done:
+		w.Header().Set("Content-Type", dnsMessageMediaType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(resp)
+	case <-r.Context().Done():
+		http.Error(w, "request canceled", http.StatusGatewayTimeout)
+	}
+}
+
+// Send routes a built response back to the HTTP handler that is waiting on
+// the query identified by dest (a *DoHAddr previously returned by Receive).
+func (t *DoHTransport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	addr, ok := dest.(*DoHAddr)
+	if !ok {
+		return &errors.NetworkError{
+			Operation: "send DoH response",
+			Err:       fmt.Errorf("dest is not a *DoHAddr: %T", dest),
+			Details:   "DoHTransport requires the address returned by Receive",
+		}
+	}
+
+	t.mu.Lock()
+	exchange, found := t.pending[addr.id]
+	t.mu.Unlock()
+	if !found {
+		return &errors.NetworkError{
+			Operation: "send DoH response",
+			Err:       fmt.Errorf("no pending request for id %d", addr.id),
+			Details:   "request may have already timed out",
+		}
+	}
+
+	select {
+	case exchange.done <- packet:
+		return nil
+	case <-ctx.Done():
+		return &errors.NetworkError{Operation: "send DoH response", Err: ctx.Err(), Details: "context canceled before delivery"}
+	}
+}
+
+// Receive blocks until a DoH query has been decoded from an incoming HTTP request.
+func (t *DoHTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case q := <-t.incoming:
+		return q.message, q.addr, nil
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{Operation: "receive DoH query", Err: ctx.Err(), Details: "context canceled before receive"}
+	case <-t.closed:
+		return nil, nil, &errors.NetworkError{Operation: "receive DoH query", Err: net.ErrClosed, Details: "transport closed"}
+	}
+}
+
+// Close shuts down the HTTP listener.
+func (t *DoHTransport) Close() error {
+	close(t.closed)
+	if err := t.server.Close(); err != nil {
+		return &errors.NetworkError{Operation: "close DoH transport", Err: err, Details: "failed to close HTTP server"}
+	}
+	return nil
+}
+
+// Compile-time verification that DoHTransport implements Transport interface.
+var _ Transport = (*DoHTransport)(nil)
+
+// DoHClientTransport is a client-side transport for DNS-over-HTTPS per RFC
+// 8484, querying a single resolver URL (e.g. "https://dns.example.com/dns-query").
+// Unlike DoHTransport's server role, Send performs the entire HTTP exchange
+// and stashes the response body for the following Receive call, since an
+// HTTP round trip has no separate "wait for the reply" step to map onto.
+//
+// RFC 8484 §4.1: both GET and POST MUST be supported by servers; UseGET
+// selects which method this client issues.
+type DoHClientTransport struct {
+	client *http.Client
+	url    string
+	UseGET bool
+
+	mu    sync.Mutex
+	reply []byte
+}
+
+// NewDoHClientTransport creates a DoHClientTransport that queries url using
+// httpClient, or http.DefaultClient if httpClient is nil.
+func NewDoHClientTransport(url string, httpClient *http.Client) *DoHClientTransport {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &DoHClientTransport{client: httpClient, url: url}
+}
+
+// DoHClientAddr identifies the DoH resolver URL a response was received from.
+type DoHClientAddr struct {
+	URL string
+}
+
+// Network returns "doh" per the net.Addr interface.
+func (a *DoHClientAddr) Network() string { return "doh" }
+
+// String returns the resolver URL.
+func (a *DoHClientAddr) String() string { return a.URL }
+
+// Send issues an HTTP GET or POST (per UseGET) carrying packet as the DNS
+// wire format body/parameter, per RFC 8484 §4.1, and stashes the response
+// body for the following Receive call. dest is ignored: a
+// DoHClientTransport always queries the URL it was created with.
+func (t *DoHClientTransport) Send(ctx context.Context, packet []byte, _ net.Addr) error {
+	var req *http.Request
+	var err error
+	if t.UseGET {
+		q := url.Values{"dns": {base64.RawURLEncoding.EncodeToString(packet)}}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, t.url+"?"+q.Encode(), http.NoBody)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(packet))
+		if err == nil {
+			req.Header.Set("Content-Type", dnsMessageMediaType)
+		}
+	}
+	if err != nil {
+		return &errors.NetworkError{Operation: "build DoH request", Err: err}
+	}
+	req.Header.Set("Accept", dnsMessageMediaType)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return &errors.NetworkError{Operation: "send DoH query", Err: err, Details: fmt.Sprintf("request to %s failed", t.url)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, doHMaxBodySize+1))
+	if err != nil {
+		return &errors.NetworkError{Operation: "read DoH response", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &errors.NetworkError{Operation: "send DoH query", Err: fmt.Errorf("unexpected status %d", resp.StatusCode), Details: string(body)}
+	}
+	if len(body) == 0 || len(body) > doHMaxBodySize {
+		return &errors.NetworkError{Operation: "send DoH query", Err: fmt.Errorf("malformed response body: %d bytes", len(body))}
+	}
+
+	t.mu.Lock()
+	t.reply = body
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Receive returns the response body the preceding Send's HTTP exchange
+// collected. Calling Receive without a prior successful Send returns an error.
+func (t *DoHClientTransport) Receive(_ context.Context) ([]byte, net.Addr, error) {
+	t.mu.Lock()
+	reply := t.reply
+	t.reply = nil
+	t.mu.Unlock()
+
+	if reply == nil {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoH response", Err: fmt.Errorf("no response pending: Send must succeed before Receive")}
+	}
+
+	return reply, &DoHClientAddr{URL: t.url}, nil
+}
+
+// Close is a no-op: DoHClientTransport holds no connection of its own beyond
+// the shared http.Client passed to NewDoHClientTransport.
+func (t *DoHClientTransport) Close() error { return nil }
+
+// Compile-time verification that DoHClientTransport implements Transport interface.
+var _ Transport = (*DoHClientTransport)(nil)
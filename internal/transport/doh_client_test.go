@@ -0,0 +1,93 @@
+package transport_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestDoHClientTransport_ImplementsTransportInterface is a compile-time
+// contract check that DoHClientTransport satisfies Transport.
+func TestDoHClientTransport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.DoHClientTransport)(nil)
+}
+
+// TestDoHClientTransport_SendReceive_RoundTrip validates that Send POSTs the
+// query as a raw application/dns-message body and that Receive returns the
+// server's response body, mirroring the POST path DoHTransport's server side
+// decodes in handleQuery.
+func TestDoHClientTransport_SendReceive_RoundTrip(t *testing.T) {
+	response := []byte("dns response bytes")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("request method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/dns-message" {
+			t.Errorf("Content-Type = %q, want application/dns-message", ct)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body failed: %v", err)
+		}
+		if string(body) != "dns query bytes" {
+			t.Errorf("request body = %q, want %q", body, "dns query bytes")
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+
+	tr := transport.NewDoHClientTransport(server.URL, nil)
+
+	ctx := context.Background()
+	if err := tr.Send(ctx, []byte("dns query bytes"), nil); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	got, _, err := tr.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if string(got) != string(response) {
+		t.Errorf("Receive() = %q, want %q", got, response)
+	}
+}
+
+// TestDoHClientTransport_Receive_WithoutSend_Errors validates that Receive
+// without a preceding successful Send returns an error instead of blocking
+// or returning a zero-value response.
+func TestDoHClientTransport_Receive_WithoutSend_Errors(t *testing.T) {
+	tr := transport.NewDoHClientTransport("http://127.0.0.1:0/dns-query", nil)
+
+	if _, _, err := tr.Receive(context.Background()); err == nil {
+		t.Error("Receive() should fail when no Send() has succeeded yet")
+	}
+}
+
+// TestDoHClientTransport_UseGET_SendsBase64URLQueryParam validates the
+// GET path per RFC 8484 §4.1, which DoHTransport's server side also decodes.
+func TestDoHClientTransport_UseGET_SendsBase64URLQueryParam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("request method = %s, want GET", r.Method)
+		}
+		if r.URL.Query().Get("dns") == "" {
+			t.Error("request is missing the dns query parameter")
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tr := transport.NewDoHClientTransport(server.URL, nil)
+	tr.UseGET = true
+
+	if err := tr.Send(context.Background(), []byte("query"), nil); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+}
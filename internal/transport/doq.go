@@ -0,0 +1,332 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// doqALPN is the ALPN token DoQ servers and clients MUST negotiate per RFC 9250 §4.1.1.
+const doqALPN = "doq"
+
+// doqMaxMessageSize mirrors the jumbo packet ceiling used on the UDP and DoH paths.
+const doqMaxMessageSize = 9000
+
+// DoQTransport serves unicast mDNS-style queries over DNS-over-QUIC per RFC 9250.
+//
+// Each query arrives on its own QUIC stream, length-prefixed per RFC 9250 §4.2
+// (a 2-byte big-endian length followed by the message, reusing the TCP framing
+// from RFC 1035 §4.2.2). DoQTransport is server-oriented like DoHTransport:
+// Receive decodes the next query from any open stream, and Send writes the
+// matching response back on that same stream before closing it.
+type DoQTransport struct {
+	listener *quic.Listener
+
+	mu      sync.Mutex
+	streams map[uint64]quic.Stream
+	nextID  uint64
+
+	incoming chan doqQuery
+	closed   chan struct{}
+}
+
+// DoQAddr identifies the QUIC stream a query arrived on.
+type DoQAddr struct {
+	id         uint64
+	RemoteAddr net.Addr
+}
+
+// Network returns "doq" per the net.Addr interface.
+func (a *DoQAddr) Network() string { return "doq" }
+
+// String returns the remote QUIC connection address.
+func (a *DoQAddr) String() string {
+	if a.RemoteAddr == nil {
+		return "unknown"
+	}
+	return a.RemoteAddr.String()
+}
+
+type doqQuery struct {
+	addr    *DoQAddr
+	message []byte
+}
+
+// NewDoQTransport starts a QUIC listener on addr using tlsConfig (which MUST
+// advertise the "doq" ALPN per RFC 9250 §4.1.1) and begins accepting
+// connections and streams in the background.
+func NewDoQTransport(addr string, tlsConfig *tls.Config) (*DoQTransport, error) {
+	tlsConfig.NextProtos = []string{doqALPN}
+
+	ln, err := quic.ListenAddr(addr, tlsConfig, nil)
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "listen DoQ",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to bind DoQ listener on %s", addr),
+		}
+	}
+
+	t := &DoQTransport{
+		listener: ln,
+		streams:  make(map[uint64]quic.Stream),
+		incoming: make(chan doqQuery, 64),
+		closed:   make(chan struct{}),
+	}
+
+	go t.acceptLoop()
+
+	return t, nil
+}
+
+// acceptLoop accepts QUIC connections and spawns a stream reader per connection.
+func (t *DoQTransport) acceptLoop() {
+	for {
+		conn, err := t.listener.Accept(context.Background())
+		if err != nil {
+			select {
+			case <-t.closed:
+				return
+			default:
+				continue
+			}
+		}
+		go t.acceptStreams(conn)
+	}
+}
+
+// acceptStreams reads one query per stream, per RFC 9250 §4.2 ("the client
+// MUST send the DNS query over a dedicated bidirectional QUIC stream").
+func (t *DoQTransport) acceptStreams(conn quic.Connection) {
+	for {
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			return
+		}
+		go t.readQuery(conn, stream)
+	}
+}
+
+// readQuery decodes the 2-byte length-prefixed DNS message from stream and
+// queues it for Receive, keeping the stream open until Send writes the reply.
+func (t *DoQTransport) readQuery(conn quic.Connection, stream quic.Stream) {
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(stream, lenBuf); err != nil {
+		_ = stream.Close()
+		return
+	}
+	msgLen := binary.BigEndian.Uint16(lenBuf)
+	if msgLen == 0 || int(msgLen) > doqMaxMessageSize {
+		_ = stream.Close()
+		return
+	}
+
+	msg := make([]byte, msgLen)
+	if _, err := io.ReadFull(stream, msg); err != nil {
+		_ = stream.Close()
+		return
+	}
+
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.streams[id] = stream
+	t.mu.Unlock()
+
+	addr := &DoQAddr{id: id, RemoteAddr: conn.RemoteAddr()}
+
+	select {
+	case t.incoming <- doqQuery{addr: addr, message: msg}:
+	case <-t.closed:
+		_ = stream.Close()
+	}
+}
+
+// Send writes the length-prefixed response to the stream identified by dest
+// and closes it, per RFC 9250 §4.2 ("the server MUST send ... then ...
+// close the stream gracefully").
+func (t *DoQTransport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	addr, ok := dest.(*DoQAddr)
+	if !ok {
+		return &errors.NetworkError{
+			Operation: "send DoQ response",
+			Err:       fmt.Errorf("dest is not a *DoQAddr: %T", dest),
+			Details:   "DoQTransport requires the address returned by Receive",
+		}
+	}
+
+	t.mu.Lock()
+	stream, found := t.streams[addr.id]
+	delete(t.streams, addr.id)
+	t.mu.Unlock()
+	if !found {
+		return &errors.NetworkError{
+			Operation: "send DoQ response",
+			Err:       fmt.Errorf("no open stream for id %d", addr.id),
+			Details:   "stream may have already been closed",
+		}
+	}
+	defer func() { _ = stream.Close() }()
+
+	if len(packet) > doqMaxMessageSize {
+		return &errors.NetworkError{Operation: "send DoQ response", Err: fmt.Errorf("response too large: %d bytes", len(packet))}
+	}
+
+	lenBuf := make([]byte, 2)
+	binary.BigEndian.PutUint16(lenBuf, uint16(len(packet))) //nolint:gosec // G115: bounds checked above
+	if _, err := stream.Write(lenBuf); err != nil {
+		return &errors.NetworkError{Operation: "send DoQ response", Err: err, Details: "failed to write length prefix"}
+	}
+	if _, err := stream.Write(packet); err != nil {
+		return &errors.NetworkError{Operation: "send DoQ response", Err: err, Details: "failed to write message"}
+	}
+
+	select {
+	case <-ctx.Done():
+		return &errors.NetworkError{Operation: "send DoQ response", Err: ctx.Err()}
+	default:
+		return nil
+	}
+}
+
+// Receive blocks until a DoQ query has been decoded from an incoming stream.
+func (t *DoQTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case q := <-t.incoming:
+		return q.message, q.addr, nil
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{Operation: "receive DoQ query", Err: ctx.Err(), Details: "context canceled before receive"}
+	case <-t.closed:
+		return nil, nil, &errors.NetworkError{Operation: "receive DoQ query", Err: net.ErrClosed, Details: "transport closed"}
+	}
+}
+
+// Close shuts down the QUIC listener.
+func (t *DoQTransport) Close() error {
+	close(t.closed)
+	if err := t.listener.Close(); err != nil {
+		return &errors.NetworkError{Operation: "close DoQ transport", Err: err, Details: "failed to close QUIC listener"}
+	}
+	return nil
+}
+
+// Compile-time verification that DoQTransport implements Transport interface.
+var _ Transport = (*DoQTransport)(nil)
+
+// DoQClientTransport is a client-side transport for DNS-over-QUIC per RFC
+// 9250, opening one bidirectional stream per query on a shared QUIC
+// connection and closing that stream once the response is read.
+type DoQClientTransport struct {
+	conn quic.Connection
+	dest net.Addr
+
+	mu     sync.Mutex
+	stream quic.Stream
+}
+
+// NewDoQClientTransport dials addr ("host:port") over QUIC using tlsConfig
+// (which MUST advertise the "doq" ALPN per RFC 9250 §4.1.1) and returns a
+// transport ready to issue queries against it.
+func NewDoQClientTransport(ctx context.Context, addr string, tlsConfig *tls.Config) (*DoQClientTransport, error) {
+	tlsConfig.NextProtos = []string{doqALPN}
+
+	conn, err := quic.DialAddr(ctx, addr, tlsConfig, nil)
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "dial DoQ",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to establish QUIC connection to %s", addr),
+		}
+	}
+
+	return &DoQClientTransport{conn: conn, dest: conn.RemoteAddr()}, nil
+}
+
+// Send opens a new bidirectional stream, per RFC 9250 §4.2 ("the client MUST
+// send the DNS query over a dedicated ... stream"), and writes the
+// length-prefixed query to it. The stream is kept open for the matching
+// Receive call. dest is ignored: a DoQClientTransport always queries the
+// peer it dialed.
+func (t *DoQClientTransport) Send(ctx context.Context, packet []byte, _ net.Addr) error {
+	if len(packet) > doqMaxMessageSize {
+		return &errors.NetworkError{Operation: "send DoQ query", Err: fmt.Errorf("message too large: %d bytes", len(packet))}
+	}
+
+	stream, err := t.conn.OpenStreamSync(ctx)
+	if err != nil {
+		return &errors.NetworkError{Operation: "send DoQ query", Err: err, Details: "failed to open QUIC stream"}
+	}
+
+	lenBuf := make([]byte, 2)
+	binary.BigEndian.PutUint16(lenBuf, uint16(len(packet))) //nolint:gosec // G115: bounds checked above
+	if _, err := stream.Write(lenBuf); err != nil {
+		return &errors.NetworkError{Operation: "send DoQ query", Err: err, Details: "failed to write length prefix"}
+	}
+	if _, err := stream.Write(packet); err != nil {
+		return &errors.NetworkError{Operation: "send DoQ query", Err: err, Details: "failed to write message"}
+	}
+	if err := stream.Close(); err != nil {
+		return &errors.NetworkError{Operation: "send DoQ query", Err: err, Details: "failed to close write side of stream"}
+	}
+
+	t.mu.Lock()
+	t.stream = stream
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Receive reads the length-prefixed response from the stream the preceding
+// Send opened, then closes that stream per RFC 9250 §4.2.
+func (t *DoQClientTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	t.mu.Lock()
+	stream := t.stream
+	t.stream = nil
+	t.mu.Unlock()
+
+	if stream == nil {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoQ response", Err: fmt.Errorf("no stream pending: Send must succeed before Receive")}
+	}
+	defer func() { _ = stream.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetReadDeadline(deadline); err != nil {
+			return nil, nil, &errors.NetworkError{Operation: "set read timeout", Err: err, Details: fmt.Sprintf("failed to set deadline %v", deadline)}
+		}
+	}
+
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(stream, lenBuf); err != nil {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoQ response", Err: err, Details: "failed to read length prefix"}
+	}
+	msgLen := binary.BigEndian.Uint16(lenBuf)
+	if msgLen == 0 || int(msgLen) > doqMaxMessageSize {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoQ response", Err: fmt.Errorf("invalid message length %d", msgLen)}
+	}
+
+	msg := make([]byte, msgLen)
+	if _, err := io.ReadFull(stream, msg); err != nil {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoQ response", Err: err, Details: "failed to read message body"}
+	}
+
+	return msg, t.dest, nil
+}
+
+// Close closes the underlying QUIC connection.
+func (t *DoQClientTransport) Close() error {
+	if err := t.conn.CloseWithError(0, ""); err != nil {
+		return &errors.NetworkError{Operation: "close DoQ transport", Err: err, Details: "failed to close QUIC connection"}
+	}
+	return nil
+}
+
+// Compile-time verification that DoQClientTransport implements Transport interface.
+var _ Transport = (*DoQClientTransport)(nil)
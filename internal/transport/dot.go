@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// DoTTransport is a client-side transport for DNS-over-TLS per RFC 7858: the
+// same 2-byte length-prefixed framing classic TCP-53 uses (RFC 1035 §4.2.2),
+// carried over a TLS connection instead of plain TCP so the query and
+// response aren't visible to anything between the client and the resolver.
+type DoTTransport struct {
+	conn net.Conn
+	dest net.Addr
+}
+
+// NewDoTTransport dials addr ("host:port") over TLS using tlsConfig and
+// returns a transport ready to exchange framed queries and responses over
+// that connection.
+func NewDoTTransport(ctx context.Context, addr string, tlsConfig *tls.Config) (*DoTTransport, error) {
+	d := tls.Dialer{Config: tlsConfig}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "dial DoT",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to establish TLS connection to %s", addr),
+		}
+	}
+
+	return &DoTTransport{conn: conn, dest: conn.RemoteAddr()}, nil
+}
+
+// Send writes packet to the TLS connection, length-prefixed per RFC 1035
+// §4.2.2, the same framing TCPTransport uses. dest is ignored: a
+// DoTTransport always writes to the peer it dialed.
+func (t *DoTTransport) Send(_ context.Context, packet []byte, _ net.Addr) error {
+	if len(packet) > tcpMaxMessageSize {
+		return &errors.NetworkError{
+			Operation: "send DoT query",
+			Err:       fmt.Errorf("message too large: %d bytes", len(packet)),
+		}
+	}
+
+	lenBuf := make([]byte, 2)
+	binary.BigEndian.PutUint16(lenBuf, uint16(len(packet))) //nolint:gosec // G115: bounds checked above
+	if _, err := t.conn.Write(lenBuf); err != nil {
+		return &errors.NetworkError{Operation: "send DoT query", Err: err, Details: "failed to write length prefix"}
+	}
+	if _, err := t.conn.Write(packet); err != nil {
+		return &errors.NetworkError{Operation: "send DoT query", Err: err, Details: "failed to write message"}
+	}
+
+	return nil
+}
+
+// Receive reads the next length-prefixed message from the TLS connection.
+func (t *DoTTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
+			return nil, nil, &errors.NetworkError{Operation: "set read timeout", Err: err, Details: fmt.Sprintf("failed to set deadline %v", deadline)}
+		}
+	}
+
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(t.conn, lenBuf); err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return nil, nil, &errors.NetworkError{Operation: "receive DoT response", Err: err, Details: "timeout"}
+		}
+		return nil, nil, &errors.NetworkError{Operation: "receive DoT response", Err: err, Details: "failed to read length prefix"}
+	}
+	msgLen := binary.BigEndian.Uint16(lenBuf)
+	if msgLen == 0 || int(msgLen) > tcpMaxMessageSize {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoT response", Err: fmt.Errorf("invalid message length %d", msgLen)}
+	}
+
+	msg := make([]byte, msgLen)
+	if _, err := io.ReadFull(t.conn, msg); err != nil {
+		return nil, nil, &errors.NetworkError{Operation: "receive DoT response", Err: err, Details: "failed to read message body"}
+	}
+
+	return msg, t.dest, nil
+}
+
+// Close closes the underlying TLS connection.
+func (t *DoTTransport) Close() error {
+	if err := t.conn.Close(); err != nil {
+		return &errors.NetworkError{Operation: "close DoT transport", Err: err, Details: "failed to close TLS connection"}
+	}
+	return nil
+}
+
+// Compile-time verification that DoTTransport implements Transport interface.
+var _ Transport = (*DoTTransport)(nil)
@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"context"
+	goerrors "errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// DualStack implements Transport by combining one IPv4 and one IPv6
+// Transport into a single dual-stack Transport: Send auto-routes to the
+// transport matching dest's address family (or fans out to both, each
+// sending to its own multicast group, when dest is nil), and Receive
+// multiplexes both transports' incoming packets - each still tagged with
+// its own family's *net.UDPAddr - into one stream.
+type DualStack struct {
+	v4, v6 Transport
+
+	results chan dualStackResult
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// dualStackResult is one Receive() outcome pumped from either underlying
+// transport.
+type dualStackResult struct {
+	data []byte
+	addr net.Addr
+}
+
+// NewDualStack wraps already-constructed v4 and v6 Transports into a single
+// dual-stack Transport. Both are owned by the returned DualStack from this
+// point on: Close() closes both, regardless of which Send/Receive calls
+// were made.
+func NewDualStack(v4, v6 Transport) *DualStack {
+	ds := &DualStack{
+		v4:      v4,
+		v6:      v6,
+		results: make(chan dualStackResult, 32),
+		done:    make(chan struct{}),
+	}
+
+	ds.wg.Add(2)
+	go ds.pump(ds.v4)
+	go ds.pump(ds.v6)
+
+	return ds
+}
+
+// NewUDPDualStackTransport builds a DualStack from fresh UDPv4Transport and
+// UDPv6Transport sockets, matching the zero-argument New*Transport
+// convention the other constructors in this package use.
+func NewUDPDualStackTransport() (*DualStack, error) {
+	v4, err := NewUDPv4Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	v6, err := NewUDPv6Transport()
+	if err != nil {
+		_ = v4.Close()
+		return nil, err
+	}
+
+	return NewDualStack(v4, v6), nil
+}
+
+// pump runs in a background goroutine, continuously draining tr's Receive()
+// into ds.results until ds.done is closed. It polls with a short per-call
+// timeout, the same pattern querier.Querier.receiveLoop uses, so it notices
+// ds.done even when tr.Receive blocks on a socket read with no data.
+func (ds *DualStack) pump(tr Transport) {
+	defer ds.wg.Done()
+
+	for {
+		select {
+		case <-ds.done:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		data, addr, err := tr.Receive(ctx)
+		cancel()
+
+		if err != nil {
+			continue // timeout or transient error - loop and recheck ds.done
+		}
+
+		select {
+		case ds.results <- dualStackResult{data: data, addr: addr}:
+		case <-ds.done:
+			return
+		}
+	}
+}
+
+// Send routes packet to the transport matching dest's address family. If
+// dest is nil or not a *net.UDPAddr, it fans out to both transports, each
+// sending to its own default multicast group.
+func (ds *DualStack) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	if udpDest, ok := dest.(*net.UDPAddr); ok && udpDest != nil {
+		if udpDest.IP.To4() != nil {
+			return ds.v4.Send(ctx, packet, dest)
+		}
+		return ds.v6.Send(ctx, packet, dest)
+	}
+
+	var errs []error
+	if err := ds.v4.Send(ctx, packet, protocol.MulticastGroupIPv4()); err != nil {
+		errs = append(errs, err)
+	}
+	if err := ds.v6.Send(ctx, packet, protocol.MulticastGroupIPv6("")); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 2 {
+		return goerrors.Join(errs...)
+	}
+	return nil
+}
+
+// Receive returns the next packet from either underlying transport, tagged
+// with its source *net.UDPAddr (IPv4 or IPv6, whichever it arrived on).
+func (ds *DualStack) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive response",
+			Err:       ctx.Err(),
+			Details:   "context canceled or deadline exceeded before receive",
+		}
+	case res := <-ds.results:
+		return res.data, res.addr, nil
+	}
+}
+
+// Close stops both pump goroutines and closes both underlying transports,
+// joining their close errors if both fail.
+func (ds *DualStack) Close() error {
+	ds.closeOnce.Do(func() {
+		close(ds.done)
+		ds.wg.Wait()
+
+		var errs []error
+		if err := ds.v4.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := ds.v6.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		if len(errs) > 0 {
+			ds.closeErr = goerrors.Join(errs...)
+		}
+	})
+	return ds.closeErr
+}
+
+// Compile-time verification that DualStack implements Transport interface
+var _ Transport = (*DualStack)(nil)
@@ -0,0 +1,141 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestDualStack_ImplementsTransportInterface is a compile-time contract
+// check that DualStack satisfies Transport.
+func TestDualStack_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.DualStack)(nil)
+}
+
+// TestDualStack_Send_RoutesByAddressFamily verifies that Send delivers to
+// the v4 mock when given an IPv4 dest and to the v6 mock when given an IPv6
+// dest.
+func TestDualStack_Send_RoutesByAddressFamily(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	ds := transport.NewDualStack(v4, v6)
+	defer func() { _ = ds.Close() }()
+
+	packet := []byte{0x01, 0x02}
+	v4Dest := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	v6Dest := &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: 5353}
+
+	if err := ds.Send(context.Background(), packet, v4Dest); err != nil {
+		t.Fatalf("Send(v4Dest) failed: %v", err)
+	}
+	if err := ds.Send(context.Background(), packet, v6Dest); err != nil {
+		t.Fatalf("Send(v6Dest) failed: %v", err)
+	}
+
+	if calls := v4.SendCalls(); len(calls) != 1 {
+		t.Errorf("v4 transport got %d Send() calls, want 1", len(calls))
+	}
+	if calls := v6.SendCalls(); len(calls) != 1 {
+		t.Errorf("v6 transport got %d Send() calls, want 1", len(calls))
+	}
+}
+
+// TestDualStack_Send_NilDestFansOutToBoth verifies that a nil dest sends to
+// both underlying transports, each with its own default multicast group.
+func TestDualStack_Send_NilDestFansOutToBoth(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	ds := transport.NewDualStack(v4, v6)
+	defer func() { _ = ds.Close() }()
+
+	if err := ds.Send(context.Background(), []byte{0x01}, nil); err != nil {
+		t.Fatalf("Send(nil) failed: %v", err)
+	}
+
+	if calls := v4.SendCalls(); len(calls) != 1 {
+		t.Errorf("v4 transport got %d Send() calls, want 1", len(calls))
+	}
+	if calls := v6.SendCalls(); len(calls) != 1 {
+		t.Errorf("v6 transport got %d Send() calls, want 1", len(calls))
+	}
+}
+
+// TestDualStack_Receive_MultiplexesBothTransports verifies that a packet
+// queued on either underlying transport surfaces from DualStack.Receive,
+// tagged with its own family's source address.
+func TestDualStack_Receive_MultiplexesBothTransports(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	ds := transport.NewDualStack(v4, v6)
+	defer func() { _ = ds.Close() }()
+
+	v4Src := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 5353}
+	v6Src := &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 5353}
+
+	v4.QueueReceive([]byte("from-v4"), v4Src)
+	v6.QueueReceive([]byte("from-v6"), v6Src)
+
+	seen := map[string]net.Addr{}
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		data, addr, err := ds.Receive(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Receive() failed: %v", err)
+		}
+		seen[string(data)] = addr
+	}
+
+	if addr, ok := seen["from-v4"]; !ok || addr.(*net.UDPAddr).IP.To4() == nil {
+		t.Errorf("expected \"from-v4\" tagged with an IPv4 address, got %v", addr)
+	}
+	if addr, ok := seen["from-v6"]; !ok || addr.(*net.UDPAddr).IP.To4() != nil {
+		t.Errorf("expected \"from-v6\" tagged with an IPv6 address, got %v", addr)
+	}
+}
+
+// TestDualStack_Receive_RespectsContextDeadline verifies that Receive
+// returns once ctx expires when neither transport has anything queued,
+// rather than blocking indefinitely.
+func TestDualStack_Receive_RespectsContextDeadline(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	ds := transport.NewDualStack(v4, v6)
+	defer func() { _ = ds.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := ds.Receive(ctx)
+	duration := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Receive() should return an error once ctx expires with nothing queued")
+	}
+	if duration > 200*time.Millisecond {
+		t.Errorf("Receive() took too long (%v) to return on ctx expiry", duration)
+	}
+}
+
+// TestDualStack_Close_ClosesBothTransports verifies that Close() closes both
+// underlying transports.
+func TestDualStack_Close_ClosesBothTransports(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	ds := transport.NewDualStack(v4, v6)
+
+	if err := ds.Close(); err != nil {
+		t.Errorf("Close() failed: %v", err)
+	}
+
+	// MockTransport.Close() doesn't expose a public "closed" getter, but a
+	// second Close() on the DualStack itself must stay idempotent (no panic,
+	// no error) thanks to sync.Once.
+	if err := ds.Close(); err != nil {
+		t.Errorf("second Close() should stay idempotent, got error: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"net"
+	"testing"
+)
+
+// TestHasIPv6Addr_LinkLocalOnlyQualifies verifies that an interface with
+// only a link-local IPv6 address still qualifies, since ff02::fb is itself
+// link-local scoped and doesn't need a routable address to reach it.
+func TestHasIPv6Addr_LinkLocalOnlyQualifies(t *testing.T) {
+	orig := interfaceAddrs
+	defer func() { interfaceAddrs = orig }()
+
+	interfaceAddrs = func(net.Interface) ([]net.Addr, error) {
+		return []net.Addr{
+			&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+		}, nil
+	}
+
+	if !hasIPv6Addr(net.Interface{Name: "eth0"}) {
+		t.Error("an interface with only a link-local IPv6 address should qualify for IPv6 multicast")
+	}
+}
+
+// TestHasIPv6Addr_IPv4OnlyDoesNotQualify verifies that an interface with
+// only an IPv4 address is excluded from the IPv6 join list, where an
+// equivalent IPv4-only filter would still accept it.
+func TestHasIPv6Addr_IPv4OnlyDoesNotQualify(t *testing.T) {
+	orig := interfaceAddrs
+	defer func() { interfaceAddrs = orig }()
+
+	interfaceAddrs = func(net.Interface) ([]net.Addr, error) {
+		return []net.Addr{
+			&net.IPNet{IP: net.IPv4(192, 168, 1, 5), Mask: net.CIDRMask(24, 32)},
+		}, nil
+	}
+
+	if hasIPv6Addr(net.Interface{Name: "eth0"}) {
+		t.Error("an IPv4-only interface should not qualify for IPv6 multicast")
+	}
+}
+
+// TestHasIPv6Addr_NoAddresses verifies that an interface reporting no
+// addresses at all (or erroring) is excluded.
+func TestHasIPv6Addr_NoAddresses(t *testing.T) {
+	orig := interfaceAddrs
+	defer func() { interfaceAddrs = orig }()
+
+	interfaceAddrs = func(net.Interface) ([]net.Addr, error) {
+		return nil, nil
+	}
+
+	if hasIPv6Addr(net.Interface{Name: "eth0"}) {
+		t.Error("an interface with no addresses should not qualify for IPv6 multicast")
+	}
+}
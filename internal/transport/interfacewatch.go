@@ -0,0 +1,49 @@
+package transport
+
+import "net"
+
+// InterfaceChangeType identifies whether an interface started or stopped
+// being usable for mDNS multicast, or kept its presence but gained/lost an
+// address. It mirrors network.InterfaceChangeType; see InterfaceWatcher for
+// why this package defines its own copy instead of importing that one.
+type InterfaceChangeType int
+
+const (
+	// InterfaceUp indicates an interface that newly became usable (e.g.
+	// Wi-Fi reassociated, a cable was plugged in).
+	InterfaceUp InterfaceChangeType = iota
+
+	// InterfaceDown indicates a previously usable interface that
+	// disappeared or stopped being usable (e.g. it went down, or a VPN
+	// took over the default route).
+	InterfaceDown
+
+	// AddressAdded indicates a still-usable interface gained an address.
+	AddressAdded
+
+	// AddressRemoved indicates a still-usable interface lost an address.
+	AddressRemoved
+)
+
+// InterfaceChange reports one interface transitioning in or out of the
+// usable set, or one address appearing or disappearing on an
+// already-usable interface. Addr is only set for AddressAdded/AddressRemoved.
+type InterfaceChange struct {
+	Type      InterfaceChangeType
+	Interface net.Interface
+	Addr      net.Addr
+}
+
+// InterfaceWatcher reports InterfaceChange events on a channel until
+// Stop is called. It's satisfied by the adapter network.
+// NewTransportInterfaceWatcher returns around *network.InterfaceWatcher -
+// this package can't import internal/network itself, since network already
+// imports transport (for UDPv4Transport/UDPv6Transport-related socket
+// logic); see RebindEvent's doc comment for the same constraint applied to
+// rebinding. Defining the minimal shape here lets querier depend on it
+// without importing internal/network directly (FR-002), the same way it
+// already depends on RebindEvent instead of a network-package type.
+type InterfaceWatcher interface {
+	Changes() <-chan InterfaceChange
+	Stop()
+}
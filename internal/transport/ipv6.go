@@ -0,0 +1,541 @@
+package transport
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"golang.org/x/net/ipv6"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// UDPv6Transport implements Transport interface for IPv6 mDNS multicast.
+//
+// Where UDPv4Transport joins its multicast group via net.ListenMulticastUDP
+// (which picks interfaces for the caller), UDPv6Transport uses
+// golang.org/x/net/ipv6's PacketConn directly so it can join ff02::fb on
+// every enabled interface individually per RFC 6762 §5 - IPv6 link-local
+// multicast has no single "default" interface the way IPv4's does.
+type UDPv6Transport struct {
+	conn  *ipv6.PacketConn
+	group *net.UDPAddr
+
+	mu     sync.Mutex
+	joined map[string]bool // interface name -> currently joined
+}
+
+// multicastHopLimit is the RFC 6762 §11 requirement that mDNS packets use
+// hop limit 255, so a receiver can tell a genuine link-local mDNS packet
+// apart from one that arrived via a misconfigured router.
+const multicastHopLimit = 255
+
+// NewUDPv6Transport creates a UDPv6Transport bound to mDNS port 5353,
+// joining the link-local multicast group ff02::fb on every up, multicast
+// capable, non-loopback interface.
+//
+// RFC 6762 §5: mDNS uses UDP port 5353 and, on IPv6, the link-local
+// multicast address ff02::fb.
+func NewUDPv6Transport() (*UDPv6Transport, error) {
+	return newUDPv6Transport(nil)
+}
+
+// NewUDPv6TransportWithInterfaces is NewUDPv6Transport, except the group is
+// joined only on ifaces instead of every multicastCapableInterfaces result -
+// see NewUDPv4TransportWithInterfaces, its IPv4 counterpart. ifaces must be
+// non-empty.
+func NewUDPv6TransportWithInterfaces(ifaces []net.Interface) (*UDPv6Transport, error) {
+	if len(ifaces) == 0 {
+		return nil, &errors.NetworkError{
+			Operation: "join multicast group",
+			Err:       fmt.Errorf("no interfaces provided"),
+			Details:   "failed to join ff02::fb: interface list is empty",
+		}
+	}
+	return newUDPv6Transport(ifaces)
+}
+
+// newUDPv6Transport is NewUDPv6Transport/NewUDPv6TransportWithInterfaces's
+// shared implementation; see newUDPv4Transport.
+func newUDPv6Transport(ifaces []net.Interface) (*UDPv6Transport, error) {
+	lc := net.ListenConfig{Control: PlatformControl}
+	conn, err := lc.ListenPacket(context.Background(), "udp6", fmt.Sprintf(":%d", protocol.Port))
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "create socket",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to bind to [::]:%d", protocol.Port),
+		}
+	}
+
+	pconn := ipv6.NewPacketConn(conn)
+
+	if err := pconn.SetMulticastHopLimit(multicastHopLimit); err != nil {
+		_ = conn.Close()
+		return nil, &errors.NetworkError{
+			Operation: "configure socket",
+			Err:       err,
+			Details:   "failed to set multicast hop limit to 255",
+		}
+	}
+
+	// RFC 6762 doesn't require disabling loopback, but a querier never needs
+	// to see its own outgoing query echoed back to it, and leaving it on
+	// wastes a wake-up on every send.
+	if err := pconn.SetMulticastLoopback(false); err != nil {
+		_ = conn.Close()
+		return nil, &errors.NetworkError{
+			Operation: "configure socket",
+			Err:       err,
+			Details:   "failed to disable multicast loopback",
+		}
+	}
+
+	// Best-effort: lets ReceiveBatch attribute each packet to the interface
+	// it actually arrived on (needed when the same group is joined on
+	// multiple NICs). A transport that can't get this ancillary data still
+	// works, it just leaves Packet.Iface nil.
+	_ = pconn.SetControlMessage(ipv6.FlagInterface, true)
+
+	group := protocol.MulticastGroupIPv6("")
+	t := &UDPv6Transport{
+		conn:   pconn,
+		group:  group,
+		joined: make(map[string]bool),
+	}
+
+	if ifaces == nil {
+		var err error
+		ifaces, err = multicastCapableInterfaces()
+		if err != nil {
+			_ = conn.Close()
+			return nil, &errors.NetworkError{
+				Operation: "list interfaces",
+				Err:       err,
+				Details:   "failed to enumerate interfaces to join ff02::fb on",
+			}
+		}
+	}
+
+	joined := 0
+	for _, ifi := range ifaces {
+		// A single interface failing to join (e.g. it lost IPv6 support
+		// since DefaultInterfaces-style filtering ran) shouldn't prevent
+		// using the others.
+		if t.join(ifi) == nil {
+			joined++
+		}
+	}
+	if joined == 0 {
+		_ = conn.Close()
+		return nil, &errors.NetworkError{
+			Operation: "join multicast group",
+			Err:       fmt.Errorf("no interfaces available"),
+			Details:   "failed to join ff02::fb on any interface",
+		}
+	}
+
+	return t, nil
+}
+
+// join adds ifi's multicast membership, recording it in t.joined on success.
+func (t *UDPv6Transport) join(ifi net.Interface) error {
+	if err := t.conn.JoinGroup(&ifi, t.group); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.joined[ifi.Name] = true
+	t.mu.Unlock()
+	return nil
+}
+
+// leave drops ifi's multicast membership and forgets it, so a later Rebind
+// re-add for the same interface re-joins rather than no-op'ing.
+func (t *UDPv6Transport) leave(ifi net.Interface) error {
+	t.mu.Lock()
+	delete(t.joined, ifi.Name)
+	t.mu.Unlock()
+
+	return t.conn.LeaveGroup(&ifi, t.group)
+}
+
+// JoinedInterfaces returns the names of the interfaces ff02::fb is
+// currently joined on - see UDPv4Transport.JoinedInterfaces, its IPv4
+// counterpart.
+func (t *UDPv6Transport) JoinedInterfaces() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.joined))
+	for name := range t.joined {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Rebind consumes events until the channel is closed, joining
+// RebindJoin.Interface and leaving RebindLeave.Interface as they arrive.
+// It runs in the caller's goroutine; callers that want it in the background
+// should `go t.Rebind(events)`.
+//
+// Rebind only changes group membership: Send, Receive, and Close are
+// unaffected and keep using the same underlying socket and buffer pool
+// throughout, so a rebind never drops a receive already in flight.
+func (t *UDPv6Transport) Rebind(events <-chan RebindEvent) {
+	for ev := range events {
+		switch ev.Type {
+		case RebindJoin:
+			_ = t.join(ev.Interface)
+		case RebindLeave:
+			_ = t.leave(ev.Interface)
+		}
+	}
+}
+
+// multicastCapableInterfaces returns the up, multicast-capable, non-loopback
+// interfaces that carry an IPv6 address to join ff02::fb on. A link-local
+// address (fe80::/10) is sufficient - ff02::fb is itself link-local scoped -
+// so an interface need not have a routable/global IPv6 address to qualify.
+//
+// This mirrors internal/network.DefaultInterfaces's filtering, but is
+// reimplemented locally: internal/network already imports this package
+// (for UDPv4Transport), so importing it back here would cycle.
+func multicastCapableInterfaces() ([]net.Interface, error) {
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]net.Interface, 0, len(all))
+	for _, ifi := range all {
+		if ifi.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if ifi.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		if ifi.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if !hasIPv6Addr(ifi) {
+			continue
+		}
+		filtered = append(filtered, ifi)
+	}
+	return filtered, nil
+}
+
+// interfaceAddrs is a seam over (*net.Interface).Addrs so tests can supply a
+// fake interface's addresses without needing a real, addressed NIC.
+var interfaceAddrs = func(ifi net.Interface) ([]net.Addr, error) {
+	return ifi.Addrs()
+}
+
+// hasIPv6Addr reports whether ifi carries at least one IPv6 address, link
+// local or otherwise. An interface with only an IPv4 address (or none at
+// all) returns false, since there is nothing to join ff02::fb with.
+func hasIPv6Addr(ifi net.Interface) bool {
+	addrs, err := interfaceAddrs(ifi)
+	if err != nil {
+		return false
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() == nil && ipNet.IP.To16() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Send transmits a packet to dest, which may be nil to mean "the mDNS
+// multicast group, fanned out over every joined interface".
+//
+// If dest carries a Zone (RFC 4007 scope ID, e.g. from
+// protocol.MulticastGroupIPv6's zone parameter or a discovered peer's
+// scoped address), Send resolves it to an interface index and attaches it
+// as the outgoing packet's control message, so the send honors that
+// specific interface rather than the kernel's default route. A nil dest
+// gets the same per-interface treatment via sendToAllJoinedInterfaces,
+// since IPv6 link-local multicast has no single default interface either -
+// RFC 6762 §14 needs the packet to actually leave on each joined NIC, not
+// just whichever one the kernel happens to route ff02::fb/5353 through.
+func (t *UDPv6Transport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	select {
+	case <-ctx.Done():
+		return &errors.NetworkError{
+			Operation: "send query",
+			Err:       ctx.Err(),
+			Details:   "context canceled before send",
+		}
+	default:
+	}
+
+	udpDest, ok := dest.(*net.UDPAddr)
+	if !ok || udpDest == nil {
+		return t.sendToAllJoinedInterfaces(packet)
+	}
+
+	var cm *ipv6.ControlMessage
+	if udpDest.Zone != "" {
+		if ifi, err := net.InterfaceByName(udpDest.Zone); err == nil {
+			cm = &ipv6.ControlMessage{IfIndex: ifi.Index}
+		}
+	}
+
+	return t.writeTo(packet, cm, udpDest)
+}
+
+// sendToAllJoinedInterfaces multicasts packet to ff02::fb once per
+// currently-joined interface, each tagged via an ipv6.ControlMessage.IfIndex
+// so concurrent sends can't race on process-wide interface state. Falls
+// back to a single untagged send if no interfaces are joined.
+func (t *UDPv6Transport) sendToAllJoinedInterfaces(packet []byte) error {
+	group := protocol.MulticastGroupIPv6("")
+
+	t.mu.Lock()
+	names := make([]string, 0, len(t.joined))
+	for name := range t.joined {
+		names = append(names, name)
+	}
+	t.mu.Unlock()
+
+	if len(names) == 0 {
+		return t.writeTo(packet, nil, group)
+	}
+
+	var errs []error
+	for _, name := range names {
+		ifi, err := net.InterfaceByName(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := t.writeTo(packet, &ipv6.ControlMessage{IfIndex: ifi.Index}, group); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == len(names) {
+		return &errors.NetworkError{
+			Operation: "send query",
+			Err:       goerrors.Join(errs...),
+			Details:   "failed to send on every joined interface",
+		}
+	}
+	return nil
+}
+
+// writeTo issues one WriteTo on the shared socket, tagging it with cm (nil
+// to let the kernel choose the outgoing interface) and validating the
+// write was complete.
+func (t *UDPv6Transport) writeTo(packet []byte, cm *ipv6.ControlMessage, dest net.Addr) error {
+	n, err := t.conn.WriteTo(packet, cm, dest)
+	if err != nil {
+		return &errors.NetworkError{
+			Operation: "send query",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to send %d bytes to %s", len(packet), dest),
+		}
+	}
+	if n != len(packet) {
+		return &errors.NetworkError{
+			Operation: "send query",
+			Err:       fmt.Errorf("partial write: %d/%d bytes", n, len(packet)),
+			Details:   "incomplete transmission",
+		}
+	}
+
+	return nil
+}
+
+// Receive waits for an incoming packet, respecting context cancellation and
+// deadline, and unmaps an IPv4-mapped IPv6 source address (::ffff:a.b.c.d)
+// to a plain 4-byte net.IP so callers don't need to special-case hybrid
+// addresses that can arrive on a dual-stack socket.
+func (t *UDPv6Transport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive response",
+			Err:       ctx.Err(),
+			Details:   "context canceled before receive",
+		}
+	default:
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
+			return nil, nil, &errors.NetworkError{
+				Operation: "set read timeout",
+				Err:       err,
+				Details:   fmt.Sprintf("failed to set deadline %v", deadline),
+			}
+		}
+	}
+
+	bufPtr := GetBuffer()
+	buffer := *bufPtr
+
+	n, _, src, err := t.conn.ReadFrom(buffer)
+	defer func() { PutBufferSized(bufPtr, n) }() // only zero what ReadFrom actually wrote
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return nil, nil, &errors.NetworkError{
+				Operation: "receive response",
+				Err:       err,
+				Details:   "timeout",
+			}
+		}
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive response",
+			Err:       err,
+			Details:   "failed to read from socket",
+		}
+	}
+
+	if udpSrc, ok := src.(*net.UDPAddr); ok {
+		if v4 := udpSrc.IP.To4(); v4 != nil {
+			udpSrc.IP = v4
+		}
+	}
+
+	result := make([]byte, n)
+	copy(result, buffer[:n])
+	return result, src, nil
+}
+
+// Close releases the IPv6 socket.
+func (t *UDPv6Transport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+
+	if err := t.conn.Close(); err != nil {
+		return &errors.NetworkError{
+			Operation: "close socket",
+			Err:       err,
+			Details:   "failed to close UDP connection",
+		}
+	}
+	return nil
+}
+
+// ipv6BatchPool reuses the []ipv6.Message slice (and each message's OOB
+// scratch buffer) ReceiveBatch builds per call; see UDPv4Transport's
+// ipv4BatchPool for why this is distinct from the GetBuffer/PutBuffer pool.
+var ipv6BatchPool = sync.Pool{
+	New: func() any {
+		ms := make([]ipv6.Message, batchSize)
+		for i := range ms {
+			ms[i].OOB = ipv6.NewControlMessage(ipv6.FlagInterface)
+		}
+		return &ms
+	},
+}
+
+// ReceiveBatch fills out with up to len(out) packets received in as few
+// syscalls as possible; see UDPv4Transport.ReceiveBatch for the
+// recvmmsg(2)-on-Linux, one-at-a-time-elsewhere rationale, which
+// ipv6.PacketConn.ReadBatch implements identically to its IPv4 counterpart.
+func (t *UDPv6Transport) ReceiveBatch(ctx context.Context, out []Packet) (int, error) {
+	if len(out) == 0 {
+		return 0, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return 0, &errors.NetworkError{
+			Operation: "receive batch",
+			Err:       ctx.Err(),
+			Details:   "context canceled before receive",
+		}
+	default:
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
+			return 0, &errors.NetworkError{
+				Operation: "set read timeout",
+				Err:       err,
+				Details:   fmt.Sprintf("failed to set deadline %v", deadline),
+			}
+		}
+	}
+
+	n := len(out)
+	if n > batchSize {
+		n = batchSize
+	}
+
+	msPtr := ipv6BatchPool.Get().(*[]ipv6.Message)
+	defer ipv6BatchPool.Put(msPtr)
+	ms := (*msPtr)[:n]
+
+	bufPtrs := make([]*[]byte, n)
+	for i := range ms {
+		bufPtrs[i] = GetBuffer()
+		ms[i].Buffers = [][]byte{*bufPtrs[i]}
+	}
+
+	count, err := t.conn.ReadBatch(ms, 0)
+	if err != nil {
+		for i := range ms {
+			PutBuffer(bufPtrs[i])
+		}
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return 0, &errors.NetworkError{
+				Operation: "receive batch",
+				Err:       err,
+				Details:   "timeout",
+			}
+		}
+		return 0, &errors.NetworkError{
+			Operation: "receive batch",
+			Err:       err,
+			Details:   "failed to read batch from socket",
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		bufPtr := bufPtrs[i]
+		data := (*bufPtr)[:ms[i].N]
+
+		out[i] = Packet{
+			Data:       data,
+			ReturnFunc: func() { PutBuffer(bufPtr) },
+		}
+		if udpAddr, ok := ms[i].Addr.(*net.UDPAddr); ok {
+			if v4 := udpAddr.IP.To4(); v4 != nil {
+				udpAddr.IP = v4
+			}
+			out[i].Addr = udpAddr
+		}
+
+		var cm ipv6.ControlMessage
+		if err := cm.Parse(ms[i].OOB); err == nil {
+			if ifi, err := net.InterfaceByIndex(cm.IfIndex); err == nil {
+				out[i].Iface = ifi
+			}
+		}
+	}
+	for i := count; i < n; i++ {
+		PutBuffer(bufPtrs[i])
+	}
+
+	return count, nil
+}
+
+// Compile-time verification that UDPv6Transport implements Transport interface
+var _ Transport = (*UDPv6Transport)(nil)
+
+// Compile-time verification that UDPv6Transport implements BatchReceiver.
+var _ BatchReceiver = (*UDPv6Transport)(nil)
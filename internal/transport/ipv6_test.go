@@ -0,0 +1,77 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestUDPv6Transport_ImplementsTransportInterface mirrors
+// TestUDPv4Transport_ImplementsTransportInterface: a compile-time contract
+// check that UDPv6Transport satisfies Transport.
+func TestUDPv6Transport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.UDPv6Transport)(nil)
+}
+
+// TestUDPv6Transport_Send_SendsToMulticastAddress validates that Send()
+// succeeds against the mDNS IPv6 multicast group, mirroring
+// TestUDPv4Transport_Send_SendsToMulticastAddress.
+func TestUDPv6Transport_Send_SendsToMulticastAddress(t *testing.T) {
+	tr, err := transport.NewUDPv6Transport()
+	if err != nil {
+		t.Skipf("NewUDPv6Transport() unavailable in this environment: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ctx := context.Background()
+	packet := []byte{0x00, 0x00, 0x00, 0x00}
+	mdnsAddr := &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: 5353}
+
+	if err := tr.Send(ctx, packet, mdnsAddr); err != nil {
+		t.Errorf("Send() failed: %v", err)
+	}
+}
+
+// TestUDPv6Transport_Send_NilDestFansOutToEveryJoinedInterface mirrors
+// TestUDPv4Transport_Send_NilDestFansOutToEveryJoinedInterface: a nil dest
+// must still succeed now that it sends once per joined interface.
+func TestUDPv6Transport_Send_NilDestFansOutToEveryJoinedInterface(t *testing.T) {
+	tr, err := transport.NewUDPv6Transport()
+	if err != nil {
+		t.Skipf("NewUDPv6Transport() unavailable in this environment: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	if err := tr.Send(context.Background(), []byte{0x00, 0x00, 0x00, 0x00}, nil); err != nil {
+		t.Errorf("Send(nil) failed: %v", err)
+	}
+}
+
+// TestUDPv6Transport_Receive_RespectsContextCancellation mirrors
+// TestUDPv4Transport_Receive_RespectsContextCancellation: Receive() must
+// return promptly once ctx is already canceled, rather than blocking.
+func TestUDPv6Transport_Receive_RespectsContextCancellation(t *testing.T) {
+	tr, err := transport.NewUDPv6Transport()
+	if err != nil {
+		t.Skipf("NewUDPv6Transport() unavailable in this environment: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		_, _, _ = tr.Receive(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("Receive() did not respect canceled context")
+	}
+}
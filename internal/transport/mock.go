@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"sync"
+	"time"
 )
 
 // MockTransport is a test double for Transport interface.
@@ -13,21 +14,42 @@ import (
 //
 // T025: For testing, make T012 and T017 pass
 type MockTransport struct {
-	mu        sync.Mutex
-	sendCalls []SendCall
-	closed    bool
+	mu          sync.Mutex
+	sendCalls   []SendCall
+	closed      bool
+	recvQueue   []ReceiveCall
+	recvReadyCh chan struct{}
+
+	// onSend, if set by SyntheticNetwork.Join, is notified of every Send()
+	// in addition to it being recorded - the hook a SyntheticNetwork uses
+	// to route this transport's packets to other Join'd nodes.
+	onSend func(packet []byte, dest net.Addr)
 }
 
-// SendCall records a single Send() invocation.
+// SendCall records a single Send() invocation, including when it happened so
+// a test can assert on query cadence (e.g. a retransmit's backoff, or the
+// 1s spacing between an announcement's two unsolicited packets) rather than
+// just on how many sends occurred.
 type SendCall struct {
 	Packet []byte
 	Dest   net.Addr
+	Time   time.Time
+}
+
+// ReceiveCall is a pre-queued packet (or, if Err is non-nil, a transport
+// error) that a subsequent Receive() call returns - queued by QueueReceive
+// or InjectError respectively, and served in the order they were queued.
+type ReceiveCall struct {
+	Packet []byte
+	Source net.Addr
+	Err    error
 }
 
 // NewMockTransport creates a new mock transport for testing.
 func NewMockTransport() *MockTransport {
 	return &MockTransport{
-		sendCalls: make([]SendCall, 0),
+		sendCalls:   make([]SendCall, 0),
+		recvReadyCh: make(chan struct{}, 1),
 	}
 }
 
@@ -36,23 +58,84 @@ func NewMockTransport() *MockTransport {
 // T017: MockTransport.Send() records calls for verification
 func (m *MockTransport) Send(_ context.Context, packet []byte, dest net.Addr) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	// Record the call
 	m.sendCalls = append(m.sendCalls, SendCall{
 		Packet: append([]byte(nil), packet...), // Copy to avoid aliasing
 		Dest:   dest,
+		Time:   time.Now(),
 	})
+	onSend := m.onSend
+	m.mu.Unlock()
+
+	// Called without m.mu held: a SyntheticNetwork's onSend may itself call
+	// back into another Join'd MockTransport (QueueReceive), which would
+	// deadlock if this transport's own lock were still held on re-entry.
+	if onSend != nil {
+		onSend(packet, dest)
+	}
 
 	return nil
 }
 
-// Receive is not implemented in mock (querier doesn't use it in current tests).
+// Receive returns the next packet queued with QueueReceive (or the next
+// error queued with InjectError), blocking until one is available or ctx is
+// done.
+func (m *MockTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	for {
+		m.mu.Lock()
+		if len(m.recvQueue) > 0 {
+			next := m.recvQueue[0]
+			m.recvQueue = m.recvQueue[1:]
+			m.mu.Unlock()
+			return next.Packet, next.Source, next.Err
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-m.recvReadyCh:
+			// Queue gained an entry - loop around and check again.
+		}
+	}
+}
+
+// QueueReceive makes a future Receive() call return packet/source.
 //
-// Future: Can be extended to return pre-configured responses.
-func (m *MockTransport) Receive(_ context.Context) ([]byte, net.Addr, error) {
-	// Not needed for current tests
-	return nil, nil, nil
+// This lets tests simulate an mDNS responder sending back an answer without
+// a real network socket.
+func (m *MockTransport) QueueReceive(packet []byte, source net.Addr) {
+	m.mu.Lock()
+	m.recvQueue = append(m.recvQueue, ReceiveCall{
+		Packet: append([]byte(nil), packet...),
+		Source: source,
+	})
+	m.mu.Unlock()
+
+	select {
+	case m.recvReadyCh <- struct{}{}:
+	default:
+	}
+}
+
+// InjectError makes a future Receive() call return err instead of a queued
+// packet, served in the same order relative to QueueReceive calls as they
+// were queued. This lets a test exercise receiveLoop's real-network-error
+// path (consecutive errors crossing receiveErrorDegradeThreshold, or the
+// error clearing on a subsequent successful receive) without a real socket
+// fault - err should not be a timeout *errors.NetworkError, which
+// receiveLoop treats as the expected, silently-retried case rather than a
+// reportable error.
+func (m *MockTransport) InjectError(err error) {
+	m.mu.Lock()
+	m.recvQueue = append(m.recvQueue, ReceiveCall{Err: err})
+	m.mu.Unlock()
+
+	select {
+	case m.recvReadyCh <- struct{}{}:
+	default:
+	}
 }
 
 // Close marks the transport as closed.
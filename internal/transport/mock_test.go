@@ -2,8 +2,10 @@ package transport_test
 
 import (
 	"context"
+	"errors"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/joshuafuller/beacon/internal/transport"
 )
@@ -64,3 +66,127 @@ func TestMockTransport_Send_RecordsCalls(t *testing.T) {
 		t.Errorf("Second call addr mismatch: got %v, want %v", calls[1].Dest, addr2)
 	}
 }
+
+// TestMockTransport_QueueReceive_ReturnsQueuedPackets validates that
+// QueueReceive() makes Receive() return packets in FIFO order without a
+// real network socket, for testing continuous-query consumers.
+func TestMockTransport_QueueReceive_ReturnsQueuedPackets(t *testing.T) {
+	mock := transport.NewMockTransport()
+	defer func() { _ = mock.Close() }()
+
+	addr := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 50), Port: 5353}
+	mock.QueueReceive([]byte{0xAA}, addr)
+	mock.QueueReceive([]byte{0xBB}, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	packet, src, err := mock.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(packet) != 1 || packet[0] != 0xAA {
+		t.Errorf("first Receive() = %v, want [0xAA]", packet)
+	}
+	if src.String() != addr.String() {
+		t.Errorf("source = %v, want %v", src, addr)
+	}
+
+	packet, _, err = mock.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(packet) != 1 || packet[0] != 0xBB {
+		t.Errorf("second Receive() = %v, want [0xBB]", packet)
+	}
+}
+
+// TestMockTransport_Receive_BlocksUntilContextDone validates that Receive()
+// with an empty queue returns the context's error rather than hanging.
+func TestMockTransport_Receive_BlocksUntilContextDone(t *testing.T) {
+	mock := transport.NewMockTransport()
+	defer func() { _ = mock.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := mock.Receive(ctx)
+	if err == nil {
+		t.Fatal("Receive() with empty queue returned nil error, want context deadline error")
+	}
+}
+
+// TestMockTransport_InjectError_ReturnedFromReceive validates that
+// InjectError() makes a subsequent Receive() return the injected error
+// instead of blocking or a queued packet, letting a test exercise a
+// consumer's real-network-error handling (e.g. receiveLoop's degraded-state
+// tracking) without a real socket fault.
+func TestMockTransport_InjectError_ReturnedFromReceive(t *testing.T) {
+	mock := transport.NewMockTransport()
+	defer func() { _ = mock.Close() }()
+
+	wantErr := errors.New("simulated ENOBUFS")
+	mock.InjectError(wantErr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, _, err := mock.Receive(ctx)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Receive() error = %v, want %v", err, wantErr)
+	}
+}
+
+// TestMockTransport_InjectError_InterleavesWithQueueReceive validates that
+// InjectError() and QueueReceive() entries are served in the order they were
+// queued, not errors-first or packets-first.
+func TestMockTransport_InjectError_InterleavesWithQueueReceive(t *testing.T) {
+	mock := transport.NewMockTransport()
+	defer func() { _ = mock.Close() }()
+
+	addr := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 50), Port: 5353}
+	wantErr := errors.New("simulated error")
+	mock.QueueReceive([]byte{0xAA}, addr)
+	mock.InjectError(wantErr)
+	mock.QueueReceive([]byte{0xBB}, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	packet, _, err := mock.Receive(ctx)
+	if err != nil || len(packet) != 1 || packet[0] != 0xAA {
+		t.Errorf("first Receive() = (%v, %v), want (0xAA, nil)", packet, err)
+	}
+
+	_, _, err = mock.Receive(ctx)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("second Receive() error = %v, want %v", err, wantErr)
+	}
+
+	packet, _, err = mock.Receive(ctx)
+	if err != nil || len(packet) != 1 || packet[0] != 0xBB {
+		t.Errorf("third Receive() = (%v, %v), want (0xBB, nil)", packet, err)
+	}
+}
+
+// TestMockTransport_Send_RecordsTimestamp validates that each SendCall
+// records when it happened, so a test can assert on cadence (retransmit
+// backoff, an announcement's 1s-apart packets) rather than just on count.
+func TestMockTransport_Send_RecordsTimestamp(t *testing.T) {
+	mock := transport.NewMockTransport()
+	defer func() { _ = mock.Close() }()
+
+	before := time.Now()
+	if err := mock.Send(context.Background(), []byte{0x01}, &net.UDPAddr{Port: 5353}); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	after := time.Now()
+
+	calls := mock.SendCalls()
+	if len(calls) != 1 {
+		t.Fatalf("SendCalls() = %d, want 1", len(calls))
+	}
+	if calls[0].Time.Before(before) || calls[0].Time.After(after) {
+		t.Errorf("SendCall.Time = %v, want between %v and %v", calls[0].Time, before, after)
+	}
+}
@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"context"
+	goerrors "errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// MultiTransport composes an arbitrary mix of UDPv4, UDPv6, and TCP
+// transports - typically UDPv4Transport, UDPv6Transport, and a
+// TCPListenerTransport for RFC 6762 §18's TCP fallback - behind a single
+// Transport, generalizing DualStack beyond exactly two UDP transports.
+// Send routes by dest's concrete net.Addr type: a *net.TCPAddr goes to the
+// TCP transport, a *net.UDPAddr to whichever UDP transport matches its
+// address family, and a nil dest fans out to every configured UDP
+// transport (each sending to its own default multicast group) - TCP has
+// no multicast group to fan out to, so omitting a dest only ever reaches
+// v4/v6. Receive multiplexes every underlying transport's incoming
+// packets into one stream.
+type MultiTransport struct {
+	v4, v6, tcp Transport
+
+	results chan dualStackResult
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewMultiTransport wraps v4, v6, and tcp into a single Transport. Any of
+// the three may be nil to omit that backend - e.g. a responder that wants
+// TCP fallback without IPv6 passes v6 as nil. Every non-nil transport is
+// owned by the returned MultiTransport from this point on: Close() closes
+// all of them.
+func NewMultiTransport(v4, v6, tcp Transport) *MultiTransport {
+	mt := &MultiTransport{
+		v4:      v4,
+		v6:      v6,
+		tcp:     tcp,
+		results: make(chan dualStackResult, 32),
+		done:    make(chan struct{}),
+	}
+
+	for _, t := range []Transport{v4, v6, tcp} {
+		if t == nil {
+			continue
+		}
+		mt.wg.Add(1)
+		go mt.pump(t)
+	}
+
+	return mt
+}
+
+// pump mirrors DualStack.pump: it drains tr.Receive() into mt.results
+// until mt.done is closed, polling with a short per-call timeout so it
+// notices mt.done even when tr.Receive blocks on no data.
+func (mt *MultiTransport) pump(tr Transport) {
+	defer mt.wg.Done()
+
+	for {
+		select {
+		case <-mt.done:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		data, addr, err := tr.Receive(ctx)
+		cancel()
+
+		if err != nil {
+			continue // timeout or transient error - loop and recheck mt.done
+		}
+
+		select {
+		case mt.results <- dualStackResult{data: data, addr: addr}:
+		case <-mt.done:
+			return
+		}
+	}
+}
+
+// Send routes packet to the transport matching dest's concrete type.
+func (mt *MultiTransport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	if _, ok := dest.(*net.TCPAddr); ok {
+		if mt.tcp == nil {
+			return &errors.NetworkError{
+				Operation: "send TCP response",
+				Err:       goerrors.New("no TCP transport configured"),
+			}
+		}
+		return mt.tcp.Send(ctx, packet, dest)
+	}
+
+	if udpDest, ok := dest.(*net.UDPAddr); ok && udpDest != nil {
+		if udpDest.IP.To4() != nil && mt.v4 != nil {
+			return mt.v4.Send(ctx, packet, dest)
+		}
+		if mt.v6 != nil {
+			return mt.v6.Send(ctx, packet, dest)
+		}
+		return &errors.NetworkError{
+			Operation: "send response",
+			Err:       goerrors.New("no transport configured for destination address family"),
+		}
+	}
+
+	var errs []error
+	sent := 0
+	if mt.v4 != nil {
+		sent++
+		if err := mt.v4.Send(ctx, packet, protocol.MulticastGroupIPv4()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if mt.v6 != nil {
+		sent++
+		if err := mt.v6.Send(ctx, packet, protocol.MulticastGroupIPv6("")); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if sent > 0 && len(errs) == sent {
+		return goerrors.Join(errs...)
+	}
+	return nil
+}
+
+// Receive returns the next packet from any underlying transport, tagged
+// with its source address.
+func (mt *MultiTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive response",
+			Err:       ctx.Err(),
+			Details:   "context canceled or deadline exceeded before receive",
+		}
+	case res := <-mt.results:
+		return res.data, res.addr, nil
+	}
+}
+
+// Close stops every pump goroutine and closes every configured underlying
+// transport, joining their close errors if more than one fails.
+func (mt *MultiTransport) Close() error {
+	mt.closeOnce.Do(func() {
+		close(mt.done)
+		mt.wg.Wait()
+
+		var errs []error
+		for _, t := range []Transport{mt.v4, mt.v6, mt.tcp} {
+			if t == nil {
+				continue
+			}
+			if err := t.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			mt.closeErr = goerrors.Join(errs...)
+		}
+	})
+	return mt.closeErr
+}
+
+// Compile-time verification that MultiTransport implements Transport interface.
+var _ Transport = (*MultiTransport)(nil)
@@ -0,0 +1,140 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestMultiTransport_ImplementsTransportInterface is a compile-time
+// contract check that MultiTransport satisfies Transport.
+func TestMultiTransport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.MultiTransport)(nil)
+}
+
+// TestMultiTransport_Send_RoutesByAddressType verifies that Send delivers
+// to the v4 mock for a UDPv4 dest, the v6 mock for a UDPv6 dest, and the
+// tcp mock for a TCPAddr dest.
+func TestMultiTransport_Send_RoutesByAddressType(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	tcp := transport.NewMockTransport()
+	mt := transport.NewMultiTransport(v4, v6, tcp)
+	defer func() { _ = mt.Close() }()
+
+	packet := []byte{0x01, 0x02}
+	v4Dest := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	v6Dest := &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: 5353}
+	tcpDest := &net.TCPAddr{IP: net.ParseIP("192.168.1.50"), Port: 54321}
+
+	if err := mt.Send(context.Background(), packet, v4Dest); err != nil {
+		t.Fatalf("Send(v4Dest) failed: %v", err)
+	}
+	if err := mt.Send(context.Background(), packet, v6Dest); err != nil {
+		t.Fatalf("Send(v6Dest) failed: %v", err)
+	}
+	if err := mt.Send(context.Background(), packet, tcpDest); err != nil {
+		t.Fatalf("Send(tcpDest) failed: %v", err)
+	}
+
+	if calls := v4.SendCalls(); len(calls) != 1 {
+		t.Errorf("v4 transport got %d Send() calls, want 1", len(calls))
+	}
+	if calls := v6.SendCalls(); len(calls) != 1 {
+		t.Errorf("v6 transport got %d Send() calls, want 1", len(calls))
+	}
+	if calls := tcp.SendCalls(); len(calls) != 1 {
+		t.Errorf("tcp transport got %d Send() calls, want 1", len(calls))
+	}
+}
+
+// TestMultiTransport_Send_NilDestFansOutToUDPOnly verifies that a nil dest
+// reaches both UDP transports but never the TCP transport, which has no
+// multicast group to fan out to.
+func TestMultiTransport_Send_NilDestFansOutToUDPOnly(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	tcp := transport.NewMockTransport()
+	mt := transport.NewMultiTransport(v4, v6, tcp)
+	defer func() { _ = mt.Close() }()
+
+	if err := mt.Send(context.Background(), []byte{0x01}, nil); err != nil {
+		t.Fatalf("Send(nil) failed: %v", err)
+	}
+
+	if calls := v4.SendCalls(); len(calls) != 1 {
+		t.Errorf("v4 transport got %d Send() calls, want 1", len(calls))
+	}
+	if calls := v6.SendCalls(); len(calls) != 1 {
+		t.Errorf("v6 transport got %d Send() calls, want 1", len(calls))
+	}
+	if calls := tcp.SendCalls(); len(calls) != 0 {
+		t.Errorf("tcp transport got %d Send() calls, want 0", len(calls))
+	}
+}
+
+// TestMultiTransport_Send_MissingTCPTransportErrors verifies that routing a
+// TCPAddr dest to a MultiTransport with no TCP transport configured returns
+// an error instead of silently dropping the response.
+func TestMultiTransport_Send_MissingTCPTransportErrors(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	mt := transport.NewMultiTransport(v4, v6, nil)
+	defer func() { _ = mt.Close() }()
+
+	tcpDest := &net.TCPAddr{IP: net.ParseIP("192.168.1.50"), Port: 54321}
+	if err := mt.Send(context.Background(), []byte{0x01}, tcpDest); err == nil {
+		t.Error("Send(tcpDest) with no TCP transport configured should error, got nil")
+	}
+}
+
+// TestMultiTransport_Receive_MultiplexesAllThree verifies that a packet
+// queued on any of the three underlying transports surfaces from
+// MultiTransport.Receive.
+func TestMultiTransport_Receive_MultiplexesAllThree(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	tcp := transport.NewMockTransport()
+	mt := transport.NewMultiTransport(v4, v6, tcp)
+	defer func() { _ = mt.Close() }()
+
+	v4.QueueReceive([]byte("from-v4"), &net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 5353})
+	v6.QueueReceive([]byte("from-v6"), &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 5353})
+	tcp.QueueReceive([]byte("from-tcp"), &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 54321})
+
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		data, _, err := mt.Receive(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Receive() failed: %v", err)
+		}
+		seen[string(data)] = true
+	}
+
+	for _, want := range []string{"from-v4", "from-v6", "from-tcp"} {
+		if !seen[want] {
+			t.Errorf("Receive() never surfaced %q", want)
+		}
+	}
+}
+
+// TestMultiTransport_Close_ClosesEveryTransport verifies that Close() is
+// idempotent and closes every configured underlying transport.
+func TestMultiTransport_Close_ClosesEveryTransport(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	v6 := transport.NewMockTransport()
+	tcp := transport.NewMockTransport()
+	mt := transport.NewMultiTransport(v4, v6, tcp)
+
+	if err := mt.Close(); err != nil {
+		t.Errorf("Close() failed: %v", err)
+	}
+	if err := mt.Close(); err != nil {
+		t.Errorf("second Close() should stay idempotent, got error: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package transport
+
+import "net"
+
+// RebindEventType distinguishes a newly usable interface from one that
+// disappeared.
+type RebindEventType int
+
+const (
+	// RebindJoin tells Rebind to join multicast membership on Interface.
+	RebindJoin RebindEventType = iota
+
+	// RebindLeave tells Rebind to leave multicast membership on Interface.
+	RebindLeave
+)
+
+// RebindEvent tells a transport's Rebind goroutine to join or leave
+// multicast membership on Interface.
+//
+// It intentionally doesn't reuse network.InterfaceChange: this package
+// cannot import internal/network (network already imports transport, for
+// UDPv4Transport/UDPv6Transport), so Rebind defines the minimal shape it
+// needs and leaves translating network.InterfaceChange into RebindEvent to
+// the caller - see querier.WithWatchInterfaces for the existing analogous
+// glue between the two packages.
+type RebindEvent struct {
+	Type      RebindEventType
+	Interface net.Interface
+}
@@ -0,0 +1,61 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestUDPv4Transport_Rebind_LeaveThenJoinKeepsSocketUsable verifies that
+// feeding a RebindLeave followed by a RebindJoin for the same interface
+// through Rebind doesn't break the underlying socket: Send still succeeds
+// afterwards, and Rebind itself returns once its channel is closed.
+func TestUDPv4Transport_Rebind_LeaveThenJoinKeepsSocketUsable(t *testing.T) {
+	tr, err := transport.NewUDPv4Transport()
+	if err != nil {
+		t.Fatalf("NewUDPv4Transport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("net.Interfaces() failed: %v", err)
+	}
+	var target net.Interface
+	for _, ifi := range ifaces {
+		if ifi.Flags&net.FlagUp != 0 && ifi.Flags&net.FlagMulticast != 0 && ifi.Flags&net.FlagLoopback == 0 {
+			target = ifi
+			break
+		}
+	}
+	if target.Name == "" {
+		t.Skip("no up, multicast-capable, non-loopback interface available in this environment")
+	}
+
+	events := make(chan transport.RebindEvent, 2)
+	events <- transport.RebindEvent{Type: transport.RebindLeave, Interface: target}
+	events <- transport.RebindEvent{Type: transport.RebindJoin, Interface: target}
+	close(events)
+
+	done := make(chan struct{})
+	go func() {
+		tr.Rebind(events)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Rebind did not return after its events channel was closed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	mdnsAddr := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	if err := tr.Send(ctx, []byte{0x00, 0x00, 0x00, 0x00}, mdnsAddr); err != nil {
+		t.Errorf("Send() after Rebind failed: %v", err)
+	}
+}
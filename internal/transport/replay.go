@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// ReplayTransport is a Transport that serves a fixed, ordered sequence of
+// previously-captured packets from Receive instead of a live socket, and
+// discards whatever Send writes - the deterministic-regression-test
+// counterpart to MockTransport's programmatic QueueReceive: a recorded
+// capture replays exactly once, byte-for-byte, run after run, rather than a
+// test hand-assembling each wire packet it wants the querier/responder to
+// see.
+//
+// Once every frame has been served, Receive blocks until ctx is done, the
+// same way a real transport's socket blocks when nothing more arrives -
+// callers drive the capture then cancel ctx rather than treating exhaustion
+// as an error.
+type ReplayTransport struct {
+	mu     sync.Mutex
+	frames []ReplayFrame
+	next   int
+}
+
+// ReplayFrame is one captured packet: its wire bytes and the source address
+// Receive should report it as having come from.
+type ReplayFrame struct {
+	Packet []byte
+	Source net.Addr
+}
+
+// replayAddr is the net.Addr a ReplayTransport reports for a frame whose
+// capture didn't record a specific source - every frame read via
+// NewReplayTransportFromReader, since that format carries no per-packet
+// address.
+type replayAddr string
+
+func (a replayAddr) Network() string { return "replay" }
+func (a replayAddr) String() string  { return string(a) }
+
+// NewReplayTransport creates a ReplayTransport that serves frames, in
+// order, one per Receive call.
+func NewReplayTransport(frames []ReplayFrame) *ReplayTransport {
+	return &ReplayTransport{frames: frames}
+}
+
+// NewReplayTransportFromReader builds a ReplayTransport from r, a sequence
+// of captured packets encoded as repeated [4-byte big-endian length][payload]
+// records - the format WriteReplayCapture produces. Every frame's Source
+// reports as "replay" (the capture format doesn't carry per-packet
+// addresses); use NewReplayTransport directly when a test needs specific
+// per-frame sources.
+func NewReplayTransportFromReader(r io.Reader) (*ReplayTransport, error) {
+	br := bufio.NewReader(r)
+	var frames []ReplayFrame
+	var lenBuf [4]byte
+	for {
+		if _, err := io.ReadFull(br, lenBuf[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, &errors.WireFormatError{
+				Operation: "read replay capture",
+				Message:   "failed to read frame length",
+				Err:       err,
+			}
+		}
+		n := binary.BigEndian.Uint32(lenBuf[:])
+		packet := make([]byte, n)
+		if _, err := io.ReadFull(br, packet); err != nil {
+			return nil, &errors.WireFormatError{
+				Operation: "read replay capture",
+				Message:   fmt.Sprintf("failed to read %d-byte frame", n),
+				Err:       err,
+			}
+		}
+		frames = append(frames, ReplayFrame{Packet: packet, Source: replayAddr("replay")})
+	}
+	return NewReplayTransport(frames), nil
+}
+
+// WriteReplayCapture writes frames to w in the format
+// NewReplayTransportFromReader reads, for recording a live capture once and
+// replaying it deterministically thereafter.
+func WriteReplayCapture(w io.Writer, frames []ReplayFrame) error {
+	for _, f := range frames {
+		var lenBuf [4]byte
+		binary.BigEndian.PutUint32(lenBuf[:], uint32(len(f.Packet))) //nolint:gosec // G115: capture frames are never anywhere near 4GiB
+		if _, err := w.Write(lenBuf[:]); err != nil {
+			return &errors.WireFormatError{
+				Operation: "write replay capture",
+				Message:   "failed to write frame length",
+				Err:       err,
+			}
+		}
+		if _, err := w.Write(f.Packet); err != nil {
+			return &errors.WireFormatError{
+				Operation: "write replay capture",
+				Message:   "failed to write frame payload",
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
+
+// Send discards packet: a replay has nothing live to deliver to, so there's
+// no failure mode worth reporting and nothing useful to record - a test
+// that needs to assert on outbound packets should use MockTransport
+// instead.
+func (t *ReplayTransport) Send(_ context.Context, _ []byte, _ net.Addr) error {
+	return nil
+}
+
+// Receive returns the next captured frame, or blocks until ctx is done once
+// every frame has been served.
+func (t *ReplayTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	t.mu.Lock()
+	if t.next < len(t.frames) {
+		frame := t.frames[t.next]
+		t.next++
+		t.mu.Unlock()
+		return frame.Packet, frame.Source, nil
+	}
+	t.mu.Unlock()
+
+	<-ctx.Done()
+	return nil, nil, &errors.NetworkError{
+		Operation: "receive replay capture",
+		Err:       ctx.Err(),
+		Details:   "context canceled after replay exhausted",
+	}
+}
+
+// Close is a no-op: a ReplayTransport holds no resources beyond its frame
+// slice.
+func (t *ReplayTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,116 @@
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestReplayTransport_ImplementsTransportInterface verifies ReplayTransport
+// satisfies Transport, the same contract check every other implementation
+// in this package gets.
+func TestReplayTransport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.ReplayTransport)(nil)
+}
+
+// TestReplayTransport_Receive_ServesFramesInOrder verifies Receive returns
+// each frame's Packet/Source once, in the order NewReplayTransport was
+// given them.
+func TestReplayTransport_Receive_ServesFramesInOrder(t *testing.T) {
+	want := []transport.ReplayFrame{
+		{Packet: []byte{0x01}},
+		{Packet: []byte{0x02}},
+	}
+	rt := transport.NewReplayTransport(want)
+
+	for i, frame := range want {
+		packet, _, err := rt.Receive(context.Background())
+		if err != nil {
+			t.Fatalf("Receive() #%d failed: %v", i, err)
+		}
+		if !bytes.Equal(packet, frame.Packet) {
+			t.Errorf("Receive() #%d = %v, want %v", i, packet, frame.Packet)
+		}
+	}
+}
+
+// TestReplayTransport_Receive_BlocksUntilContextDoneOnceExhausted verifies
+// Receive doesn't return an error the instant the capture runs out - it
+// blocks like a real socket would, until the caller's context ends.
+func TestReplayTransport_Receive_BlocksUntilContextDoneOnceExhausted(t *testing.T) {
+	rt := transport.NewReplayTransport(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := rt.Receive(ctx)
+	if err == nil {
+		t.Fatal("Receive() on an exhausted ReplayTransport succeeded, want an error once ctx is done")
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Receive() returned after %v, want it to block until ctx's deadline", elapsed)
+	}
+}
+
+// TestReplayTransport_Send_IsANoOp verifies Send never fails - a replay has
+// nothing live to deliver to, so there's nothing for it to reject.
+func TestReplayTransport_Send_IsANoOp(t *testing.T) {
+	rt := transport.NewReplayTransport(nil)
+	if err := rt.Send(context.Background(), []byte{0x01}, nil); err != nil {
+		t.Errorf("Send() = %v, want nil", err)
+	}
+}
+
+// TestWriteReplayCapture_RoundTripsThroughReader verifies a capture written
+// by WriteReplayCapture reads back, via NewReplayTransportFromReader, as
+// the same sequence of packets it was given.
+func TestWriteReplayCapture_RoundTripsThroughReader(t *testing.T) {
+	frames := []transport.ReplayFrame{
+		{Packet: []byte("first packet")},
+		{Packet: []byte{}},
+		{Packet: []byte("third packet, longer than the first")},
+	}
+
+	var buf bytes.Buffer
+	if err := transport.WriteReplayCapture(&buf, frames); err != nil {
+		t.Fatalf("WriteReplayCapture() failed: %v", err)
+	}
+
+	rt, err := transport.NewReplayTransportFromReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReplayTransportFromReader() failed: %v", err)
+	}
+
+	for i, frame := range frames {
+		packet, _, err := rt.Receive(context.Background())
+		if err != nil {
+			t.Fatalf("Receive() #%d failed: %v", i, err)
+		}
+		if !bytes.Equal(packet, frame.Packet) {
+			t.Errorf("Receive() #%d = %q, want %q", i, packet, frame.Packet)
+		}
+	}
+}
+
+// TestNewReplayTransportFromReader_RejectsTruncatedFrame verifies a capture
+// cut off mid-frame (a length prefix promising more bytes than follow) is
+// reported as an error rather than silently returning a short packet.
+func TestNewReplayTransportFromReader_RejectsTruncatedFrame(t *testing.T) {
+	// Length prefix claims 10 bytes, but only 2 follow.
+	truncated := []byte{0x00, 0x00, 0x00, 0x0A, 0x01, 0x02}
+	if _, err := transport.NewReplayTransportFromReader(bytes.NewReader(truncated)); err == nil {
+		t.Error("NewReplayTransportFromReader() on a truncated frame succeeded, want an error")
+	}
+}
+
+// TestReplayTransport_Close_IsANoOp verifies Close never fails.
+func TestReplayTransport_Close_IsANoOp(t *testing.T) {
+	rt := transport.NewReplayTransport(nil)
+	if err := rt.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+}
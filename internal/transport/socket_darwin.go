@@ -10,11 +10,13 @@ import (
 )
 
 // setSocketOptions configures platform-specific socket options for macOS.
-// Sets SO_REUSEADDR and SO_REUSEPORT to enable coexistence with Bonjour (mDNSResponder).
+// Sets SO_REUSEADDR and SO_REUSEPORT to enable coexistence with Bonjour
+// (mDNSResponder). For an "udp6" socket, also sets the IPv6-specific
+// options setSocketOptionsIPv6 documents.
 //
 // Per F-9 REQ-F9-2: SO_REUSEPORT required for multi-daemon coexistence.
 // Per research.md: macOS supports SO_REUSEPORT on all versions (BSD semantics).
-func setSocketOptions(fd uintptr) error {
+func setSocketOptions(network string, fd uintptr) error {
 	// SO_REUSEADDR: Allow binding to address already in use (BSD standard)
 	if err := unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); err != nil {
 		return fmt.Errorf("failed to set SO_REUSEADDR: %w", err)
@@ -26,6 +28,31 @@ func setSocketOptions(fd uintptr) error {
 		return fmt.Errorf("failed to set SO_REUSEPORT: %w", err)
 	}
 
+	if network == "udp6" {
+		if err := setSocketOptionsIPv6(fd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setSocketOptionsIPv6 sets the IPv6-specific socket options an AF_INET6
+// mDNS socket needs; see socket_linux.go's setSocketOptionsIPv6 for the
+// rationale behind each, which applies identically on macOS.
+func setSocketOptionsIPv6(fd uintptr) error {
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_V6ONLY, 1); err != nil {
+		return fmt.Errorf("failed to set IPV6_V6ONLY: %w", err)
+	}
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, 255); err != nil {
+		return fmt.Errorf("failed to set IPV6_MULTICAST_HOPS: %w", err)
+	}
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_LOOP, 1); err != nil {
+		return fmt.Errorf("failed to set IPV6_MULTICAST_LOOP: %w", err)
+	}
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_RECVPKTINFO, 1); err != nil {
+		return fmt.Errorf("failed to set IPV6_RECVPKTINFO: %w", err)
+	}
 	return nil
 }
 
@@ -41,7 +68,7 @@ func getKernelVersion() string {
 func platformControl(network, address string, c syscall.RawConn) error {
 	var sockoptErr error
 	err := c.Control(func(fd uintptr) {
-		sockoptErr = setSocketOptions(fd)
+		sockoptErr = setSocketOptions(network, fd)
 	})
 	if err != nil {
 		return fmt.Errorf("raw conn control failed: %w", err)
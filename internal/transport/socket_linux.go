@@ -11,11 +11,13 @@ import (
 
 // setSocketOptions configures platform-specific socket options for Linux.
 // Sets SO_REUSEADDR and SO_REUSEPORT (if kernel >= 3.9) to enable
-// coexistence with Avahi and systemd-resolved on port 5353.
+// coexistence with Avahi and systemd-resolved on port 5353. For an "udp6"
+// socket, also sets the IPv6-specific options setSocketOptionsIPv6
+// documents.
 //
 // Per F-9 REQ-F9-2: SO_REUSEPORT required for multi-daemon coexistence.
 // Per research.md: Linux kernel 3.9+ supports SO_REUSEPORT.
-func setSocketOptions(fd uintptr) error {
+func setSocketOptions(network string, fd uintptr) error {
 	// SO_REUSEADDR: Allow binding to address already in use (POSIX standard)
 	if err := unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); err != nil {
 		return fmt.Errorf("failed to set SO_REUSEADDR: %w", err)
@@ -33,6 +35,43 @@ func setSocketOptions(fd uintptr) error {
 		// Querier initialization will detect kernel version and warn user
 	}
 
+	if network == "udp6" {
+		if err := setSocketOptionsIPv6(fd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setSocketOptionsIPv6 sets the IPv6-specific socket options an AF_INET6
+// mDNS socket needs, beyond the family-agnostic SO_REUSEADDR/SO_REUSEPORT
+// setSocketOptions already sets:
+//
+//   - IPV6_V6ONLY=1: without it, Linux dual-binds the socket to both address
+//     families, which breaks SO_REUSEPORT's "exactly one socket per family
+//     wins each packet" semantics against Avahi's own IPv6 socket.
+//   - IPV6_MULTICAST_HOPS=255: RFC 6762 §11 requires mDNS packets use hop
+//     limit 255, so a receiver can tell a genuine link-local mDNS packet
+//     apart from one that arrived via a misconfigured router.
+//   - IPV6_MULTICAST_LOOP=1: POSIX default, set explicitly for parity with
+//     the options below rather than relying on it.
+//   - IPV6_RECVPKTINFO=1: lets ReceiveBatch attribute each packet to the
+//     interface it actually arrived on, same rationale as
+//     ipv6.PacketConn.SetControlMessage(ipv6.FlagInterface, true) in ipv6.go.
+func setSocketOptionsIPv6(fd uintptr) error {
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_V6ONLY, 1); err != nil {
+		return fmt.Errorf("failed to set IPV6_V6ONLY: %w", err)
+	}
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, 255); err != nil {
+		return fmt.Errorf("failed to set IPV6_MULTICAST_HOPS: %w", err)
+	}
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_LOOP, 1); err != nil {
+		return fmt.Errorf("failed to set IPV6_MULTICAST_LOOP: %w", err)
+	}
+	if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_RECVPKTINFO, 1); err != nil {
+		return fmt.Errorf("failed to set IPV6_RECVPKTINFO: %w", err)
+	}
 	return nil
 }
 
@@ -58,10 +97,10 @@ func getKernelVersion() string {
 
 // Control function for net.ListenConfig on Linux.
 // This is called by UDPv4Transport during socket creation.
-func platformControl(_, _ string, c syscall.RawConn) error {
+func platformControl(network, _ string, c syscall.RawConn) error {
 	var sockoptErr error
 	err := c.Control(func(fd uintptr) {
-		sockoptErr = setSocketOptions(fd)
+		sockoptErr = setSocketOptions(network, fd)
 	})
 	if err != nil {
 		return fmt.Errorf("raw conn control failed: %w", err)
@@ -23,7 +23,7 @@ func TestSetSocketOptions_Linux(t *testing.T) {
 	defer func() { _ = syscall.Close(fd) }()
 
 	// Call setSocketOptions
-	if err := setSocketOptions(uintptr(fd)); err != nil {
+	if err := setSocketOptions("udp4", uintptr(fd)); err != nil {
 		t.Fatalf("setSocketOptions() failed: %v", err)
 	}
 
@@ -53,6 +53,57 @@ func TestSetSocketOptions_Linux(t *testing.T) {
 	t.Logf("Linux kernel version: %s", version)
 }
 
+// TestSetSocketOptions_Linux_IPv6 verifies the IPv6-specific options
+// (IPV6_V6ONLY, IPV6_MULTICAST_HOPS, IPV6_MULTICAST_LOOP, IPV6_RECVPKTINFO)
+// are set on an "udp6" socket, and left untouched on an "udp4" one.
+func TestSetSocketOptions_Linux_IPv6(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Linux-specific test")
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET6, syscall.SOCK_DGRAM, syscall.IPPROTO_UDP)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	defer func() { _ = syscall.Close(fd) }()
+
+	if err := setSocketOptions("udp6", uintptr(fd)); err != nil {
+		t.Fatalf("setSocketOptions() failed: %v", err)
+	}
+
+	v6only, err := unix.GetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_V6ONLY)
+	if err != nil {
+		t.Fatalf("Failed to get IPV6_V6ONLY: %v", err)
+	}
+	if v6only != 1 {
+		t.Errorf("IPV6_V6ONLY = %d, want 1", v6only)
+	}
+
+	hops, err := unix.GetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS)
+	if err != nil {
+		t.Fatalf("Failed to get IPV6_MULTICAST_HOPS: %v", err)
+	}
+	if hops != 255 {
+		t.Errorf("IPV6_MULTICAST_HOPS = %d, want 255", hops)
+	}
+
+	loop, err := unix.GetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_LOOP)
+	if err != nil {
+		t.Fatalf("Failed to get IPV6_MULTICAST_LOOP: %v", err)
+	}
+	if loop != 1 {
+		t.Errorf("IPV6_MULTICAST_LOOP = %d, want 1", loop)
+	}
+
+	pktinfo, err := unix.GetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_RECVPKTINFO)
+	if err != nil {
+		t.Fatalf("Failed to get IPV6_RECVPKTINFO: %v", err)
+	}
+	if pktinfo != 1 {
+		t.Errorf("IPV6_RECVPKTINFO = %d, want 1", pktinfo)
+	}
+}
+
 // TestSetSocketOptions_macOS verifies SO_REUSEADDR and SO_REUSEPORT are set on macOS.
 // Per F-9 REQ-F9-2: macOS requires both options for Bonjour coexistence.
 func TestSetSocketOptions_macOS(t *testing.T) {
@@ -68,7 +119,7 @@ func TestSetSocketOptions_macOS(t *testing.T) {
 	defer func() { _ = syscall.Close(fd) }()
 
 	// Call setSocketOptions
-	if err := setSocketOptions(uintptr(fd)); err != nil {
+	if err := setSocketOptions("udp4", uintptr(fd)); err != nil {
 		t.Fatalf("setSocketOptions() failed: %v", err)
 	}
 
@@ -112,7 +163,7 @@ func TestSetSocketOptions_Windows(t *testing.T) {
 	defer func() { _ = syscall.Close(fd) }()
 
 	// Call setSocketOptions
-	if err := setSocketOptions(uintptr(fd)); err != nil {
+	if err := setSocketOptions("udp4", uintptr(fd)); err != nil {
 		t.Fatalf("setSocketOptions() failed: %v", err)
 	}
 
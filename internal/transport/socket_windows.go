@@ -5,12 +5,16 @@ package transport
 import (
 	"fmt"
 	"syscall"
+	"unsafe"
 
 	"golang.org/x/sys/windows"
 )
 
 // setSocketOptions configures platform-specific socket options for Windows.
-// Sets SO_REUSEADDR only (Windows does NOT support SO_REUSEPORT).
+// Sets SO_REUSEADDR (Windows does NOT support SO_REUSEPORT) and disables
+// SIO_UDP_CONNRESET, so a single unreachable-ICMP from one peer can't kill
+// the socket for all mDNS traffic. For an "udp6" socket, also sets
+// IPV6_V6ONLY - see setSocketOptionsIPv6.
 //
 // Per F-9 REQ-F9-3: Windows SO_REUSEADDR has different semantics than POSIX.
 // Per research.md: Windows SO_REUSEADDR allows multiple binds to same port (similar to BSD SO_REUSEPORT).
@@ -21,7 +25,7 @@ import (
 //
 // This means Beacon CAN coexist with other mDNS applications on Windows,
 // but the semantics are slightly different from Linux/macOS.
-func setSocketOptions(fd uintptr) error {
+func setSocketOptions(network string, fd uintptr) error {
 	// SO_REUSEADDR: Windows-specific behavior (allows port sharing)
 	// This is the ONLY socket option we can use on Windows for coexistence
 	if err := windows.SetsockoptInt(windows.Handle(fd), windows.SOL_SOCKET, windows.SO_REUSEADDR, 1); err != nil {
@@ -31,6 +35,41 @@ func setSocketOptions(fd uintptr) error {
 	// SO_REUSEPORT does not exist on Windows - do not attempt to set it
 	// The golang.org/x/sys/windows package doesn't even define SO_REUSEPORT constant
 
+	if err := disableUDPConnReset(fd); err != nil {
+		return err
+	}
+
+	if network == "udp6" {
+		if err := windows.SetsockoptInt(windows.Handle(fd), windows.IPPROTO_IPV6, windows.IPV6_V6ONLY, 1); err != nil {
+			return fmt.Errorf("failed to set IPV6_V6ONLY: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// disableUDPConnReset turns off SIO_UDP_CONNRESET, a Windows-only behavior
+// where a UDP socket that receives an ICMP Port Unreachable from one peer
+// is torn down, silently dropping mDNS traffic from every other peer on the
+// LAN. WSAIoctl's input buffer is a single BOOL (4 bytes); a false value
+// disables the behavior.
+func disableUDPConnReset(fd uintptr) error {
+	var disable uint32
+	var bytesReturned uint32
+	err := windows.WSAIoctl(
+		windows.Handle(fd),
+		windows.SIO_UDP_CONNRESET,
+		(*byte)(unsafe.Pointer(&disable)),
+		uint32(unsafe.Sizeof(disable)),
+		nil,
+		0,
+		&bytesReturned,
+		nil,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to disable SIO_UDP_CONNRESET: %w", err)
+	}
 	return nil
 }
 
@@ -47,7 +86,7 @@ func getKernelVersion() string {
 func platformControl(network, address string, c syscall.RawConn) error {
 	var sockoptErr error
 	err := c.Control(func(fd uintptr) {
-		sockoptErr = setSocketOptions(fd)
+		sockoptErr = setSocketOptions(network, fd)
 	})
 	if err != nil {
 		return fmt.Errorf("raw conn control failed: %w", err)
@@ -0,0 +1,264 @@
+package transport
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// NetworkClock is a fake clock driving SyntheticNetwork packet delivery, the
+// transport-package analogue of testutil/mdnstest.FakeClock: a test that
+// wants to observe a retransmit firing after a SyntheticNetwork's configured
+// latency calls Advance instead of sleeping in real time.
+//
+// A NetworkClock's zero value is not usable - construct one with
+// NewNetworkClock.
+type NetworkClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	pending []networkTimer
+}
+
+// networkTimer is one delivery scheduled with AfterFunc, awaiting Advance.
+type networkTimer struct {
+	fireAt time.Time
+	fn     func()
+}
+
+// NewNetworkClock creates a NetworkClock starting at a fixed instant rather
+// than time.Now(), so two runs of the same test never disagree about
+// elapsed time because of when they happened to execute.
+func NewNetworkClock() *NetworkClock {
+	return &NetworkClock{now: time.Unix(1700000000, 0)}
+}
+
+// Now returns the clock's current, fake time.
+func (c *NetworkClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc schedules fn to run once the clock has advanced past d from now,
+// the fake analogue of time.AfterFunc. As with the real time.AfterFunc, a
+// non-positive d runs fn right away rather than waiting for a future
+// Advance call.
+func (c *NetworkClock) AfterFunc(d time.Duration, fn func()) {
+	if d <= 0 {
+		fn()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, networkTimer{fireAt: c.now.Add(d), fn: fn})
+}
+
+// Advance moves the clock forward by d, running every pending delivery
+// whose deadline has now passed, in the order they were scheduled - so two
+// packets given the same latency still arrive in send order, matching
+// real-world best-effort delivery.
+func (c *NetworkClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+
+	var due []networkTimer
+	remaining := c.pending[:0]
+	for _, t := range c.pending {
+		if !t.fireAt.After(c.now) {
+			due = append(due, t)
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+	c.pending = remaining
+	c.mu.Unlock()
+
+	for _, t := range due {
+		t.fn()
+	}
+}
+
+// SyntheticNetwork connects multiple MockTransport instances into a shared,
+// impaired medium: a packet Send() on one Join'd transport is delivered to
+// every other one whose address it reaches (or to exactly one, if dest
+// names a Join'd node directly), subject to configured loss, latency,
+// jitter and duplication.
+//
+// This turns the otherwise-isolated MockTransport instances from mock.go
+// into a fabric for deterministic end-to-end tests - a Responder and
+// multiple Queriers can exchange real RFC 6762 probes/announcements over a
+// lossy link without a real socket or a real sleep, driven instead by the
+// network's NetworkClock.
+//
+// A SyntheticNetwork's zero value is not usable - construct one with
+// NewSyntheticNetwork.
+type SyntheticNetwork struct {
+	mu    sync.Mutex
+	nodes map[string]*MockTransport
+
+	lossRate float64
+	dupRate  float64
+	latency  time.Duration
+	jitter   time.Duration
+	mtu      int
+
+	clock *NetworkClock
+	rng   *rand.Rand
+}
+
+// SyntheticNetworkOption configures a SyntheticNetwork at construction.
+type SyntheticNetworkOption func(*SyntheticNetwork)
+
+// WithLossRate makes the network silently drop each packet with probability
+// rate (0.0 = never, 1.0 = always), independently per recipient.
+func WithLossRate(rate float64) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.lossRate = rate }
+}
+
+// WithDuplicateRate makes the network deliver a second copy of each packet
+// with probability rate, simulating the duplicate frames a real link can
+// produce under retransmission at the MAC layer.
+func WithDuplicateRate(rate float64) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.dupRate = rate }
+}
+
+// WithLatency sets the fixed delay applied to every delivered packet.
+func WithLatency(d time.Duration) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.latency = d }
+}
+
+// WithJitter adds up to d of additional random delay on top of the
+// network's latency, independently per recipient - the source of any
+// reordering between two packets sent close together, since a later Send
+// can still be assigned a shorter total delay than an earlier one.
+func WithJitter(d time.Duration) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.jitter = d }
+}
+
+// WithMTU caps the size of packet a Send can deliver; anything larger is
+// dropped network-wide, the same way a real link would refuse to carry an
+// oversized frame rather than deliver it corrupted. The default is 1500,
+// the Ethernet MTU.
+func WithMTU(mtu int) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.mtu = mtu }
+}
+
+// WithClock makes the network schedule deliveries on clock instead of a
+// NetworkClock it creates itself, so a test can share one clock across a
+// SyntheticNetwork and whatever else it's driving (e.g. a state.Prober's
+// retransmit timers).
+func WithClock(clock *NetworkClock) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.clock = clock }
+}
+
+// WithRand makes the network draw its loss/duplicate/jitter decisions from
+// rng instead of its own default source, so a test can pin the exact
+// sequence of outcomes.
+func WithRand(rng *rand.Rand) SyntheticNetworkOption {
+	return func(n *SyntheticNetwork) { n.rng = rng }
+}
+
+// NewSyntheticNetwork creates an empty SyntheticNetwork with no loss,
+// latency or jitter (a perfect link) and the default 1500-byte MTU, unless
+// overridden by opts.
+func NewSyntheticNetwork(opts ...SyntheticNetworkOption) *SyntheticNetwork {
+	n := &SyntheticNetwork{
+		nodes: make(map[string]*MockTransport),
+		mtu:   1500,
+		// Fixed seed: two runs of the same test see the same loss/jitter
+		// outcomes, the same determinism NewNetworkClock buys for timing.
+		rng: rand.New(rand.NewSource(1)),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if n.clock == nil {
+		n.clock = NewNetworkClock()
+	}
+	return n
+}
+
+// Clock returns the NetworkClock driving this network's packet delivery.
+// A test using WithLatency or WithJitter must call Clock().Advance to make
+// a delayed packet actually arrive.
+func (n *SyntheticNetwork) Clock() *NetworkClock {
+	return n.clock
+}
+
+// Join attaches a new MockTransport to the network under addr and returns
+// it. Another Join'd transport's Send(dest) is delivered to this one
+// whenever dest matches addr exactly, or whenever dest matches no Join'd
+// node at all (the fabric's multicast fallback, for dest values like the
+// mDNS group address that no participant is individually addressed as).
+func (n *SyntheticNetwork) Join(addr net.Addr) *MockTransport {
+	mt := NewMockTransport()
+
+	n.mu.Lock()
+	n.nodes[addr.String()] = mt
+	n.mu.Unlock()
+
+	mt.onSend = func(packet []byte, dest net.Addr) {
+		n.deliver(addr, packet, dest)
+	}
+	return mt
+}
+
+// deliver applies the network's loss/latency/jitter/duplicate policies to
+// one packet sent by src, then schedules surviving copies for delivery to
+// every node deliver's targets reach.
+func (n *SyntheticNetwork) deliver(src net.Addr, packet []byte, dest net.Addr) {
+	if n.mtu > 0 && len(packet) > n.mtu {
+		return
+	}
+
+	n.mu.Lock()
+	targets := n.targetsLocked(src, dest)
+	n.mu.Unlock()
+
+	for _, target := range targets {
+		copies := 1
+		if n.dupRate > 0 && n.rng.Float64() < n.dupRate {
+			copies = 2
+		}
+		for i := 0; i < copies; i++ {
+			if n.lossRate > 0 && n.rng.Float64() < n.lossRate {
+				continue
+			}
+			n.scheduleDelivery(target, packet, src)
+		}
+	}
+}
+
+// scheduleDelivery queues packet on target's Receive via the network's
+// clock, after this network's latency plus up to its jitter.
+func (n *SyntheticNetwork) scheduleDelivery(target *MockTransport, packet []byte, src net.Addr) {
+	delay := n.latency
+	if n.jitter > 0 {
+		delay += time.Duration(n.rng.Int63n(int64(n.jitter)))
+	}
+	pkt := append([]byte(nil), packet...)
+	n.clock.AfterFunc(delay, func() {
+		target.QueueReceive(pkt, src)
+	})
+}
+
+// targetsLocked returns the transports a Send from src to dest reaches: just
+// the one Join'd under dest, if any node is, otherwise every other Join'd
+// node (the multicast fallback). Callers must hold n.mu.
+func (n *SyntheticNetwork) targetsLocked(src, dest net.Addr) []*MockTransport {
+	if dest != nil {
+		if mt, ok := n.nodes[dest.String()]; ok {
+			return []*MockTransport{mt}
+		}
+	}
+
+	targets := make([]*MockTransport, 0, len(n.nodes))
+	for addr, mt := range n.nodes {
+		if addr == src.String() {
+			continue
+		}
+		targets = append(targets, mt)
+	}
+	return targets
+}
@@ -0,0 +1,173 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+func mustReceive(t *testing.T, mt *transport.MockTransport, timeout time.Duration) ([]byte, net.Addr) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	packet, src, err := mt.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() error = %v, want a delivered packet", err)
+	}
+	return packet, src
+}
+
+func mustNotReceive(t *testing.T, mt *transport.MockTransport, timeout time.Duration) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if packet, _, err := mt.Receive(ctx); err == nil {
+		t.Fatalf("Receive() = %v, want no delivery", packet)
+	}
+}
+
+// TestSyntheticNetwork_Send_BroadcastsToUnaddressedDest validates that a
+// Send whose dest matches no Join'd node (e.g. the mDNS multicast group
+// address) reaches every other participant, the fabric's multicast
+// fallback.
+func TestSyntheticNetwork_Send_BroadcastsToUnaddressedDest(t *testing.T) {
+	sn := transport.NewSyntheticNetwork()
+	a := sn.Join(&netAddr{"a"})
+	b := sn.Join(&netAddr{"b"})
+	c := sn.Join(&netAddr{"c"})
+
+	multicast := &netAddr{"224.0.0.251:5353"}
+	if err := a.Send(context.Background(), []byte{0x01}, multicast); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if packet, src := mustReceive(t, b, time.Second); packet[0] != 0x01 || src.String() != "a" {
+		t.Errorf("b received (%v, %v), want (0x01, a)", packet, src)
+	}
+	if packet, src := mustReceive(t, c, time.Second); packet[0] != 0x01 || src.String() != "a" {
+		t.Errorf("c received (%v, %v), want (0x01, a)", packet, src)
+	}
+}
+
+// TestSyntheticNetwork_Send_UnicastDestReachesOnlyThatNode validates that a
+// Send whose dest exactly matches a Join'd node's address is delivered only
+// to that node, not broadcast to the whole network.
+func TestSyntheticNetwork_Send_UnicastDestReachesOnlyThatNode(t *testing.T) {
+	sn := transport.NewSyntheticNetwork()
+	a := sn.Join(&netAddr{"a"})
+	b := sn.Join(&netAddr{"b"})
+	c := sn.Join(&netAddr{"c"})
+
+	if err := a.Send(context.Background(), []byte{0x02}, &netAddr{"b"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if packet, _ := mustReceive(t, b, time.Second); packet[0] != 0x02 {
+		t.Errorf("b received %v, want 0x02", packet)
+	}
+	mustNotReceive(t, c, 50*time.Millisecond)
+}
+
+// TestSyntheticNetwork_WithLatency_DelaysDeliveryUntilClockAdvances
+// validates that a packet sent over a network configured WithLatency only
+// arrives once the network's NetworkClock has been advanced past that
+// delay, not immediately and not via a real sleep.
+func TestSyntheticNetwork_WithLatency_DelaysDeliveryUntilClockAdvances(t *testing.T) {
+	sn := transport.NewSyntheticNetwork(transport.WithLatency(10 * time.Second))
+	a := sn.Join(&netAddr{"a"})
+	b := sn.Join(&netAddr{"b"})
+
+	if err := a.Send(context.Background(), []byte{0x03}, &netAddr{"b"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	mustNotReceive(t, b, 50*time.Millisecond)
+
+	sn.Clock().Advance(10 * time.Second)
+
+	if packet, _ := mustReceive(t, b, time.Second); packet[0] != 0x03 {
+		t.Errorf("b received %v, want 0x03", packet)
+	}
+}
+
+// TestSyntheticNetwork_WithLossRate1_DropsEveryPacket validates that a
+// network configured WithLossRate(1.0) never delivers a packet, even after
+// its clock is advanced well past any latency.
+func TestSyntheticNetwork_WithLossRate1_DropsEveryPacket(t *testing.T) {
+	sn := transport.NewSyntheticNetwork(transport.WithLossRate(1.0))
+	a := sn.Join(&netAddr{"a"})
+	b := sn.Join(&netAddr{"b"})
+
+	if err := a.Send(context.Background(), []byte{0x04}, &netAddr{"b"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	sn.Clock().Advance(time.Minute)
+	mustNotReceive(t, b, 50*time.Millisecond)
+}
+
+// TestSyntheticNetwork_WithMTU_DropsOversizedPackets validates that a
+// packet larger than WithMTU is dropped network-wide rather than delivered
+// truncated or corrupted.
+func TestSyntheticNetwork_WithMTU_DropsOversizedPackets(t *testing.T) {
+	sn := transport.NewSyntheticNetwork(transport.WithMTU(4))
+	a := sn.Join(&netAddr{"a"})
+	b := sn.Join(&netAddr{"b"})
+
+	if err := a.Send(context.Background(), []byte{1, 2, 3, 4, 5}, &netAddr{"b"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	mustNotReceive(t, b, 50*time.Millisecond)
+}
+
+// TestSyntheticNetwork_WithDuplicateRate1_DeliversTwoCopies validates that
+// a network configured WithDuplicateRate(1.0) delivers every packet twice,
+// simulating link-layer retransmission duplicates.
+func TestSyntheticNetwork_WithDuplicateRate1_DeliversTwoCopies(t *testing.T) {
+	sn := transport.NewSyntheticNetwork(transport.WithDuplicateRate(1.0))
+	a := sn.Join(&netAddr{"a"})
+	b := sn.Join(&netAddr{"b"})
+
+	if err := a.Send(context.Background(), []byte{0x05}, &netAddr{"b"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	mustReceive(t, b, time.Second)
+	mustReceive(t, b, time.Second)
+}
+
+// TestNetworkClock_Advance_FiresDueTimersInScheduleOrder validates that
+// Advance runs every pending AfterFunc whose deadline has passed, in the
+// order they were scheduled, and leaves later timers pending.
+func TestNetworkClock_Advance_FiresDueTimersInScheduleOrder(t *testing.T) {
+	c := transport.NewNetworkClock()
+
+	var fired []string
+	c.AfterFunc(1*time.Second, func() { fired = append(fired, "short") })
+	c.AfterFunc(100*time.Second, func() { fired = append(fired, "long") })
+
+	c.Advance(2 * time.Second)
+
+	if len(fired) != 1 || fired[0] != "short" {
+		t.Errorf("fired = %v, want [short]", fired)
+	}
+
+	c.Advance(100 * time.Second)
+	if len(fired) != 2 || fired[1] != "long" {
+		t.Errorf("fired = %v, want [short long]", fired)
+	}
+}
+
+// netAddr is a minimal net.Addr for SyntheticNetwork tests, identifying a
+// node by an arbitrary string rather than a real network address.
+type netAddr struct {
+	addr string
+}
+
+func (a *netAddr) Network() string { return "mdnstest" }
+func (a *netAddr) String() string  { return a.addr }
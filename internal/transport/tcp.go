@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// tcpMaxMessageSize mirrors the jumbo packet ceiling used on the UDP and DoH/DoQ paths.
+const tcpMaxMessageSize = 9000
+
+// TCPTransport is a client-side transport for classic unicast DNS over
+// TCP-53, framed per RFC 1035 §4.2.2 (a 2-byte big-endian length prefix
+// followed by the message). Unlike UDPv4Transport/UnicastUDPTransport, a
+// TCPTransport dials a single peer and carries every query and response
+// over that one connection - for resolvers that require or prefer TCP
+// (responses too large for UDP, a peer that only serves port 53/tcp).
+type TCPTransport struct {
+	conn net.Conn
+	dest net.Addr
+}
+
+// NewTCPTransport dials addr ("host:port") over TCP and returns a transport
+// ready to exchange framed queries and responses over that connection.
+func NewTCPTransport(ctx context.Context, addr string) (*TCPTransport, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "dial TCP",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to connect to %s", addr),
+		}
+	}
+
+	return &TCPTransport{conn: conn, dest: conn.RemoteAddr()}, nil
+}
+
+// Send writes packet to the connection, length-prefixed per RFC 1035 §4.2.2.
+// dest is ignored: a TCPTransport always writes to the peer it dialed.
+func (t *TCPTransport) Send(_ context.Context, packet []byte, _ net.Addr) error {
+	if len(packet) > tcpMaxMessageSize {
+		return &errors.NetworkError{
+			Operation: "send TCP query",
+			Err:       fmt.Errorf("message too large: %d bytes", len(packet)),
+		}
+	}
+
+	lenBuf := make([]byte, 2)
+	binary.BigEndian.PutUint16(lenBuf, uint16(len(packet))) //nolint:gosec // G115: bounds checked above
+	if _, err := t.conn.Write(lenBuf); err != nil {
+		return &errors.NetworkError{Operation: "send TCP query", Err: err, Details: "failed to write length prefix"}
+	}
+	if _, err := t.conn.Write(packet); err != nil {
+		return &errors.NetworkError{Operation: "send TCP query", Err: err, Details: "failed to write message"}
+	}
+
+	return nil
+}
+
+// Receive reads the next length-prefixed message from the connection.
+func (t *TCPTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
+			return nil, nil, &errors.NetworkError{Operation: "set read timeout", Err: err, Details: fmt.Sprintf("failed to set deadline %v", deadline)}
+		}
+	}
+
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(t.conn, lenBuf); err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return nil, nil, &errors.NetworkError{Operation: "receive TCP response", Err: err, Details: "timeout"}
+		}
+		return nil, nil, &errors.NetworkError{Operation: "receive TCP response", Err: err, Details: "failed to read length prefix"}
+	}
+	msgLen := binary.BigEndian.Uint16(lenBuf)
+	if msgLen == 0 || int(msgLen) > tcpMaxMessageSize {
+		return nil, nil, &errors.NetworkError{Operation: "receive TCP response", Err: fmt.Errorf("invalid message length %d", msgLen)}
+	}
+
+	msg := make([]byte, msgLen)
+	if _, err := io.ReadFull(t.conn, msg); err != nil {
+		return nil, nil, &errors.NetworkError{Operation: "receive TCP response", Err: err, Details: "failed to read message body"}
+	}
+
+	return msg, t.dest, nil
+}
+
+// Close closes the underlying TCP connection.
+func (t *TCPTransport) Close() error {
+	if err := t.conn.Close(); err != nil {
+		return &errors.NetworkError{Operation: "close TCP transport", Err: err, Details: "failed to close TCP connection"}
+	}
+	return nil
+}
+
+// Compile-time verification that TCPTransport implements Transport interface.
+var _ Transport = (*TCPTransport)(nil)
@@ -0,0 +1,221 @@
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TCPListenerTransport is the server-side half of RFC 6762 §18's TCP
+// fallback: it listens on port 5353/tcp, accepts a connection per peer,
+// and frames queries/responses with the same 2-byte length prefix
+// TCPTransport uses client-side. A responder falls back to it when a
+// response would exceed the UDP MTU - it multicasts a truncated response
+// with the TC bit set, and the querier that receives it dials this
+// listener to fetch the full, uncompressed record set over the resulting
+// connection. MultiTransport is what actually routes a responder's Send
+// to this transport once the querier's source address is a *net.TCPAddr.
+type TCPListenerTransport struct {
+	ln net.Listener
+
+	mu     sync.Mutex
+	conns  map[string]net.Conn // RemoteAddr().String() -> accepted connection
+	closed bool
+
+	incoming chan tcpListenerPacket
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// tcpListenerPacket is one framed message read off an accepted connection,
+// tagged with the connection's remote address so Send can find it again.
+type tcpListenerPacket struct {
+	data []byte
+	addr net.Addr
+}
+
+// NewTCPListenerTransport listens on 0.0.0.0:5353/tcp (protocol.Port) and
+// returns a transport ready to accept querier connections for TCP
+// fallback.
+func NewTCPListenerTransport() (*TCPListenerTransport, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%s", strconv.Itoa(protocol.Port)))
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "listen TCP",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to listen on :%d/tcp", protocol.Port),
+		}
+	}
+
+	t := &TCPListenerTransport{
+		ln:       ln,
+		conns:    make(map[string]net.Conn),
+		incoming: make(chan tcpListenerPacket, 32),
+		done:     make(chan struct{}),
+	}
+
+	t.wg.Add(1)
+	go t.acceptLoop()
+
+	return t, nil
+}
+
+// acceptLoop accepts incoming connections until Close is called, starting
+// a readLoop per connection so multiple simultaneous followers-up (e.g.
+// two different queriers that both saw the same truncated response) don't
+// block each other.
+func (t *TCPListenerTransport) acceptLoop() {
+	defer t.wg.Done()
+
+	for {
+		conn, err := t.ln.Accept()
+		if err != nil {
+			select {
+			case <-t.done:
+				return
+			default:
+				continue
+			}
+		}
+
+		key := conn.RemoteAddr().String()
+		t.mu.Lock()
+		t.conns[key] = conn
+		t.mu.Unlock()
+
+		t.wg.Add(1)
+		go t.readLoop(conn, key)
+	}
+}
+
+// readLoop drains length-prefixed messages from conn into t.incoming until
+// the peer disconnects or the message framing is invalid, then forgets and
+// closes conn - a querier that wants another fallback response later opens
+// a fresh connection, it doesn't reuse this one.
+func (t *TCPListenerTransport) readLoop(conn net.Conn, key string) {
+	defer t.wg.Done()
+	defer func() {
+		t.mu.Lock()
+		delete(t.conns, key)
+		t.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		lenBuf := make([]byte, 2)
+		if _, err := io.ReadFull(conn, lenBuf); err != nil {
+			return
+		}
+		msgLen := binary.BigEndian.Uint16(lenBuf)
+		if msgLen == 0 || int(msgLen) > tcpMaxMessageSize {
+			return
+		}
+
+		msg := make([]byte, msgLen)
+		if _, err := io.ReadFull(conn, msg); err != nil {
+			return
+		}
+
+		select {
+		case t.incoming <- tcpListenerPacket{data: msg, addr: conn.RemoteAddr()}:
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// Send writes packet, length-prefixed per RFC 1035 §4.2.2, to the
+// connection dest was most recently read from. dest must be a previously
+// accepted connection's remote address - there's no default destination
+// to fall back to the way UDPv4Transport falls back to its multicast
+// group, since a TCP fallback response only ever replies to a connection
+// the peer itself opened.
+func (t *TCPListenerTransport) Send(_ context.Context, packet []byte, dest net.Addr) error {
+	if dest == nil {
+		return &errors.NetworkError{
+			Operation: "send TCP response",
+			Err:       fmt.Errorf("dest is required: TCPListenerTransport has no default destination"),
+		}
+	}
+	if len(packet) > tcpMaxMessageSize {
+		return &errors.NetworkError{
+			Operation: "send TCP response",
+			Err:       fmt.Errorf("message too large: %d bytes", len(packet)),
+		}
+	}
+
+	t.mu.Lock()
+	conn, ok := t.conns[dest.String()]
+	t.mu.Unlock()
+	if !ok {
+		return &errors.NetworkError{
+			Operation: "send TCP response",
+			Err:       fmt.Errorf("no accepted connection from %s", dest),
+		}
+	}
+
+	lenBuf := make([]byte, 2)
+	binary.BigEndian.PutUint16(lenBuf, uint16(len(packet))) //nolint:gosec // G115: bounds checked above
+	if _, err := conn.Write(lenBuf); err != nil {
+		return &errors.NetworkError{Operation: "send TCP response", Err: err, Details: "failed to write length prefix"}
+	}
+	if _, err := conn.Write(packet); err != nil {
+		return &errors.NetworkError{Operation: "send TCP response", Err: err, Details: "failed to write message"}
+	}
+
+	return nil
+}
+
+// Receive returns the next framed message read from any accepted
+// connection, tagged with that connection's remote address.
+func (t *TCPListenerTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive TCP query",
+			Err:       ctx.Err(),
+			Details:   "context canceled or deadline exceeded before receive",
+		}
+	case pkt := <-t.incoming:
+		return pkt.data, pkt.addr, nil
+	}
+}
+
+// Close stops accepting new connections and closes every connection
+// currently accepted. Safe to call once; a second Close is a no-op.
+func (t *TCPListenerTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mu.Unlock()
+
+	close(t.done)
+	err := t.ln.Close()
+
+	t.mu.Lock()
+	for _, conn := range t.conns {
+		_ = conn.Close()
+	}
+	t.conns = nil
+	t.mu.Unlock()
+
+	t.wg.Wait()
+
+	if err != nil {
+		return &errors.NetworkError{Operation: "close TCP listener", Err: err, Details: "failed to close listening socket"}
+	}
+	return nil
+}
+
+// Compile-time verification that TCPListenerTransport implements Transport interface.
+var _ Transport = (*TCPListenerTransport)(nil)
@@ -0,0 +1,125 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestTCPListenerTransport_ImplementsTransportInterface is a compile-time
+// contract check that TCPListenerTransport satisfies Transport.
+func TestTCPListenerTransport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.TCPListenerTransport)(nil)
+}
+
+// dialFramed dials addr over TCP and returns the connection, for tests
+// acting as the querier side of TCPListenerTransport's fallback protocol.
+func dialFramed(t *testing.T, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	return conn
+}
+
+func writeFramed(t *testing.T, conn net.Conn, msg []byte) {
+	t.Helper()
+	lenBuf := make([]byte, 2)
+	binary.BigEndian.PutUint16(lenBuf, uint16(len(msg)))
+	if _, err := conn.Write(lenBuf); err != nil {
+		t.Fatalf("write length prefix failed: %v", err)
+	}
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("write message failed: %v", err)
+	}
+}
+
+func readFramed(t *testing.T, conn net.Conn) []byte {
+	t.Helper()
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(conn, lenBuf); err != nil {
+		t.Fatalf("read length prefix failed: %v", err)
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(lenBuf))
+	if _, err := io.ReadFull(conn, msg); err != nil {
+		t.Fatalf("read message failed: %v", err)
+	}
+	return msg
+}
+
+// TestTCPListenerTransport_ReceiveThenSend validates the fallback round
+// trip: a querier dials the listener, sends a framed query, the listener's
+// Receive surfaces it tagged with the querier's address, and Send to that
+// same address writes the response back down the same connection.
+func TestTCPListenerTransport_ReceiveThenSend(t *testing.T) {
+	lt, err := transport.NewTCPListenerTransport()
+	if err != nil {
+		t.Skipf("NewTCPListenerTransport() failed (port 5353/tcp unavailable in this sandbox): %v", err)
+	}
+	defer func() { _ = lt.Close() }()
+
+	conn := dialFramed(t, "127.0.0.1:5353")
+	defer func() { _ = conn.Close() }()
+
+	query := []byte("full-recordset-query")
+	writeFramed(t, conn, query)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, addr, err := lt.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if string(data) != string(query) {
+		t.Errorf("Receive() data = %q, want %q", data, query)
+	}
+
+	response := []byte("full-recordset-response")
+	if err := lt.Send(context.Background(), response, addr); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	got := readFramed(t, conn)
+	if string(got) != string(response) {
+		t.Errorf("querier read %q, want %q", got, response)
+	}
+}
+
+// TestTCPListenerTransport_Send_UnknownDestErrors validates that Send
+// refuses to write to an address that never connected, rather than
+// silently dropping the response.
+func TestTCPListenerTransport_Send_UnknownDestErrors(t *testing.T) {
+	lt, err := transport.NewTCPListenerTransport()
+	if err != nil {
+		t.Skipf("NewTCPListenerTransport() failed (port 5353/tcp unavailable in this sandbox): %v", err)
+	}
+	defer func() { _ = lt.Close() }()
+
+	unknown := &net.TCPAddr{IP: net.ParseIP("203.0.113.1"), Port: 12345}
+	if err := lt.Send(context.Background(), []byte("x"), unknown); err == nil {
+		t.Error("Send() to an address that never connected should error, got nil")
+	}
+}
+
+// TestTCPListenerTransport_Close_PropagatesErrors mirrors
+// TestTCPTransport_Close_PropagatesErrors: a second Close() on an
+// already-closed listener must stay idempotent rather than error.
+func TestTCPListenerTransport_Close_PropagatesErrors(t *testing.T) {
+	lt, err := transport.NewTCPListenerTransport()
+	if err != nil {
+		t.Skipf("NewTCPListenerTransport() failed (port 5353/tcp unavailable in this sandbox): %v", err)
+	}
+
+	if err := lt.Close(); err != nil {
+		t.Errorf("first Close() should succeed, got error: %v", err)
+	}
+	if err := lt.Close(); err != nil {
+		t.Errorf("second Close() should stay idempotent, got error: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestTCPTransport_ImplementsTransportInterface is a compile-time contract
+// check that TCPTransport satisfies Transport, mirroring the other
+// transports' ImplementsTransportInterface tests.
+func TestTCPTransport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.TCPTransport)(nil)
+}
+
+// tcpEchoServer accepts a single connection, reads one length-prefixed
+// message, and writes it straight back, mimicking a resolver that answers
+// the query it was just sent.
+func tcpEchoServer(t *testing.T, ln net.Listener) {
+	t.Helper()
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	lenBuf := make([]byte, 2)
+	if _, err := io.ReadFull(conn, lenBuf); err != nil {
+		return
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(lenBuf))
+	if _, err := io.ReadFull(conn, msg); err != nil {
+		return
+	}
+	_, _ = conn.Write(lenBuf)
+	_, _ = conn.Write(msg)
+}
+
+// TestTCPTransport_SendReceive_RoundTrip validates that a query written via
+// Send is framed per RFC 1035 §4.2.2 and that Receive correctly unframes a
+// response using that same length prefix.
+func TestTCPTransport_SendReceive_RoundTrip(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	go tcpEchoServer(t, ln)
+
+	ctx := context.Background()
+	tr, err := transport.NewTCPTransport(ctx, ln.Addr().String())
+	if err != nil {
+		t.Fatalf("NewTCPTransport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	packet := []byte("hello tcp")
+	if err := tr.Send(ctx, packet, nil); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	recvCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	got, _, err := tr.Receive(recvCtx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if string(got) != string(packet) {
+		t.Errorf("Receive() = %q, want %q", got, packet)
+	}
+}
+
+// TestTCPTransport_Close_PropagatesErrors mirrors
+// TestUnicastUDPTransport_Close_PropagatesErrors: a second Close() on an
+// already-closed connection must return an error rather than silently succeed.
+func TestTCPTransport_Close_PropagatesErrors(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	go tcpEchoServer(t, ln)
+
+	tr, err := transport.NewTCPTransport(context.Background(), ln.Addr().String())
+	if err != nil {
+		t.Fatalf("NewTCPTransport() failed: %v", err)
+	}
+
+	if err := tr.Close(); err != nil {
+		t.Errorf("First Close() should succeed, got error: %v", err)
+	}
+	if err := tr.Close(); err == nil {
+		t.Error("Second Close() should return error (connection already closed)")
+	}
+}
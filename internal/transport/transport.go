@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"context"
+	"net"
+)
+
+// Transport abstracts the network layer used to send and receive mDNS wire
+// format packets.
+//
+// This indirection lets the querier and responder packages stay agnostic of
+// the underlying medium (UDP multicast, mock, DoH, DoQ, ...). All methods
+// accept a context for cancellation/deadline propagation per F-9 REQ-F9-7.
+//
+// T010: Transport interface contract
+type Transport interface {
+	// Send transmits a packet to the given destination address.
+	//
+	// dest may be nil to indicate "use this transport's default destination"
+	// (e.g. the mDNS multicast group for UDPv4Transport).
+	Send(ctx context.Context, packet []byte, dest net.Addr) error
+
+	// Receive blocks until a packet is available, the context is done, or
+	// the transport encounters an error.
+	Receive(ctx context.Context) ([]byte, net.Addr, error)
+
+	// Close releases any resources held by the transport.
+	Close() error
+}
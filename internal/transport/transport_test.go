@@ -17,4 +17,6 @@ func TestTransportInterface_HasRequiredMethods(_ *testing.T) {
 	// This test passes if the interface compiles with the expected method signatures
 	var _ transport.Transport = (*transport.MockTransport)(nil)
 	var _ transport.Transport = (*transport.UDPv4Transport)(nil)
+	var _ transport.Transport = (*transport.TCPListenerTransport)(nil)
+	var _ transport.Transport = (*transport.MultiTransport)(nil)
 }
@@ -2,9 +2,13 @@ package transport
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"net"
 	"strconv"
+	"sync"
+
+	"golang.org/x/net/ipv4"
 
 	"github.com/joshuafuller/beacon/internal/errors"
 	"github.com/joshuafuller/beacon/internal/protocol"
@@ -17,12 +21,23 @@ import (
 // - Adds context support for cancellation and deadlines (F-9 REQ-F9-7)
 // - Fixes error propagation in Close() (FR-004)
 //
+// Membership is joined per interface via golang.org/x/net/ipv4's PacketConn,
+// the same approach UDPv6Transport uses, rather than net.ListenMulticastUDP's
+// all-interfaces-at-once join: Rebind needs to leave and join individual
+// interfaces as they come and go, which ListenMulticastUDP has no handle for.
+//
 // T020: Migrate internal/network/socket.go CreateSocket logic to make T011 pass
 type UDPv4Transport struct {
-	conn net.PacketConn
+	conn  *ipv4.PacketConn
+	group *net.UDPAddr
+
+	mu     sync.Mutex
+	joined map[string]bool // interface name -> currently joined
 }
 
-// NewUDPv4Transport creates a UDP multicast transport bound to mDNS port 5353.
+// NewUDPv4Transport creates a UDP multicast transport bound to mDNS port 5353,
+// joining the multicast group 224.0.0.251 on every up, multicast-capable,
+// non-loopback interface.
 //
 // This migrates CreateSocket() from internal/network/socket.go:24-58.
 //
@@ -37,7 +52,33 @@ type UDPv4Transport struct {
 //
 // T021: Socket creation, multicast join
 func NewUDPv4Transport() (*UDPv4Transport, error) {
-	// Resolve mDNS multicast address
+	return newUDPv4Transport(nil, false)
+}
+
+// NewUDPv4TransportWithInterfaces is NewUDPv4Transport, except the group is
+// joined only on ifaces instead of every multicastCapableInterfacesV4 result
+// - for querier.WithInterfaces/WithInterfaceFilter's explicit interface
+// selection. ifaces must be non-empty; as with the default constructor, the
+// socket still fails to come up if none of them can actually join the
+// group.
+func NewUDPv4TransportWithInterfaces(ifaces []net.Interface) (*UDPv4Transport, error) {
+	if len(ifaces) == 0 {
+		return nil, &errors.NetworkError{
+			Operation: "join multicast group",
+			Err:       fmt.Errorf("no interfaces provided"),
+			Details:   "failed to join 224.0.0.251: interface list is empty",
+		}
+	}
+	return newUDPv4Transport(ifaces, false)
+}
+
+// newUDPv4Transport is NewUDPv4Transport/NewUDPv4TransportWithInterfaces's
+// shared implementation. ifaces == nil means "use
+// multicastCapableInterfacesV4's default filtering"; a non-nil slice joins
+// exactly those interfaces instead. loopback is always false through the
+// exported constructors - see SetMulticastLoopback's call site below for
+// why a test, and only a test, ever wants it true.
+func newUDPv4Transport(ifaces []net.Interface, loopback bool) (*UDPv4Transport, error) {
 	multicastAddr, err := net.ResolveUDPAddr("udp4", net.JoinHostPort(protocol.MulticastAddrIPv4, strconv.Itoa(protocol.Port)))
 	if err != nil {
 		return nil, &errors.NetworkError{
@@ -47,37 +88,186 @@ func NewUDPv4Transport() (*UDPv4Transport, error) {
 		}
 	}
 
-	// Listen on mDNS multicast group
-	// This binds to the multicast address and joins the group automatically
-	// Connection ownership transferred to UDPv4Transport, closed via t.Close() method
-	//
-	// NOTE: ListenMulticastUDP is acceptable here for M1 (IPv4-only).
-	// F-9 REQ-F9-1 requires platform-specific sockets for M2 (IPv6 + SO_REUSEPORT).
-	// This will be replaced during M2 implementation with proper socket creation.
-	conn, err := net.ListenMulticastUDP("udp4", nil, multicastAddr) // nosemgrep: beacon-socket-close-check, beacon-listen-multicast-udp
+	lc := net.ListenConfig{Control: PlatformControl}
+	conn, err := lc.ListenPacket(context.Background(), "udp4", fmt.Sprintf(":%d", protocol.Port))
 	if err != nil {
 		return nil, &errors.NetworkError{
 			Operation: "create socket",
 			Err:       err,
-			Details:   fmt.Sprintf("failed to bind to multicast %s:%d", protocol.MulticastAddrIPv4, protocol.Port),
+			Details:   fmt.Sprintf("failed to bind to 0.0.0.0:%d", protocol.Port),
 		}
 	}
 
-	// Configure socket buffer
-	err = conn.SetReadBuffer(65536) // 64KB buffer for DNS messages
-	if err != nil {
-		_ = conn.Close() // Ignore error, already returning primary error
+	pconn := ipv4.NewPacketConn(conn)
+
+	// A querier never needs to see its own outgoing query echoed back;
+	// mirrors UDPv6Transport's rationale for disabling loopback. On Linux,
+	// disabling it on one socket suppresses delivery of that host's
+	// multicast traffic to every local socket, not just the sender's - so
+	// a test standing up two same-host transports and expecting one to
+	// observe the other's sends needs loopback left on; see
+	// newUDPv4Transport(nil, true) in batch_internal_test.go.
+	if err := pconn.SetMulticastLoopback(loopback); err != nil {
+		_ = conn.Close()
 		return nil, &errors.NetworkError{
 			Operation: "configure socket",
 			Err:       err,
-			Details:   "failed to set read buffer size",
+			Details:   "failed to disable multicast loopback",
 		}
 	}
 
-	return &UDPv4Transport{conn: conn}, nil
+	// Best-effort: lets ReceiveBatch attribute each packet to the interface
+	// it actually arrived on (needed when the same group is joined on
+	// multiple NICs). A transport that can't get this ancillary data still
+	// works, it just leaves Packet.Iface nil.
+	_ = pconn.SetControlMessage(ipv4.FlagInterface, true)
+
+	t := &UDPv4Transport{
+		conn:   pconn,
+		group:  multicastAddr,
+		joined: make(map[string]bool),
+	}
+
+	if ifaces == nil {
+		var err error
+		ifaces, err = multicastCapableInterfacesV4()
+		if err != nil {
+			_ = conn.Close()
+			return nil, &errors.NetworkError{
+				Operation: "list interfaces",
+				Err:       err,
+				Details:   "failed to enumerate interfaces to join 224.0.0.251 on",
+			}
+		}
+	}
+
+	joined := 0
+	for _, ifi := range ifaces {
+		if t.join(ifi) == nil {
+			joined++
+		}
+	}
+	if joined == 0 {
+		_ = conn.Close()
+		return nil, &errors.NetworkError{
+			Operation: "join multicast group",
+			Err:       fmt.Errorf("no interfaces available"),
+			Details:   "failed to join 224.0.0.251 on any interface",
+		}
+	}
+
+	if udpConn, ok := conn.(*net.UDPConn); ok {
+		if err := udpConn.SetReadBuffer(65536); err != nil {
+			_ = conn.Close()
+			return nil, &errors.NetworkError{
+				Operation: "configure socket",
+				Err:       err,
+				Details:   "failed to set read buffer size",
+			}
+		}
+	}
+
+	return t, nil
 }
 
-// Send transmits a packet to the specified destination address.
+// multicastCapableInterfacesV4 returns the up, multicast-capable,
+// non-loopback interfaces to join 224.0.0.251 on.
+//
+// This mirrors internal/network.DefaultInterfaces's baseline filtering, but
+// is reimplemented locally: internal/network already imports this package
+// (for UDPv4Transport), so importing it back here would cycle.
+func multicastCapableInterfacesV4() ([]net.Interface, error) {
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]net.Interface, 0, len(all))
+	for _, ifi := range all {
+		if ifi.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if ifi.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		if ifi.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		filtered = append(filtered, ifi)
+	}
+	return filtered, nil
+}
+
+// join adds ifi's multicast membership, recording it in t.joined on success.
+// Safe to call for an interface that's already joined (JoinGroup is
+// idempotent on all platforms this targets).
+func (t *UDPv4Transport) join(ifi net.Interface) error {
+	if err := t.conn.JoinGroup(&ifi, t.group); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.joined[ifi.Name] = true
+	t.mu.Unlock()
+	return nil
+}
+
+// leave drops ifi's multicast membership and forgets it, so a later Rebind
+// re-add for the same interface re-joins rather than no-op'ing.
+func (t *UDPv4Transport) leave(ifi net.Interface) error {
+	t.mu.Lock()
+	delete(t.joined, ifi.Name)
+	t.mu.Unlock()
+
+	return t.conn.LeaveGroup(&ifi, t.group)
+}
+
+// JoinedInterfaces returns the names of the interfaces 224.0.0.251 is
+// currently joined on - the subset of whatever NewUDPv4Transport/
+// NewUDPv4TransportWithInterfaces was given (or discovered via
+// multicastCapableInterfacesV4) that actually succeeded, since both
+// constructors tolerate individual JoinGroup failures as long as at least
+// one interface joins. Order is unspecified; callers that want a stable
+// order should sort it themselves.
+func (t *UDPv4Transport) JoinedInterfaces() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.joined))
+	for name := range t.joined {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Rebind consumes events until the channel is closed, joining
+// RebindJoin.Interface and leaving RebindLeave.Interface as they arrive.
+// It runs in the caller's goroutine; callers that want it in the background
+// should `go t.Rebind(events)`.
+//
+// Rebind only changes group membership: Send, Receive, and Close are
+// unaffected and keep using the same underlying socket and buffer pool
+// throughout, so a rebind never drops a receive already in flight.
+func (t *UDPv4Transport) Rebind(events <-chan RebindEvent) {
+	for ev := range events {
+		switch ev.Type {
+		case RebindJoin:
+			_ = t.join(ev.Interface)
+		case RebindLeave:
+			_ = t.leave(ev.Interface)
+		}
+	}
+}
+
+// Send transmits a packet to the specified destination address. A nil/non-
+// UDPAddr dest means "multicast to 224.0.0.251:5353", and is fanned out
+// over every interface currently joined (each tagged via an
+// ipv4.ControlMessage.IfIndex, not the process-wide SetMulticastInterface,
+// so concurrent Send calls can't race on which interface an outgoing
+// packet actually leaves on) rather than leaving interface selection to
+// whatever route the kernel picks for the default interface - RFC 6762
+// §14's link-local scoping means a packet that only goes out on one NIC
+// never reaches peers on the others.
 //
 // This migrates SendQuery() from internal/network/socket.go:73-104.
 //
@@ -88,7 +278,6 @@ func NewUDPv4Transport() (*UDPv4Transport, error) {
 //
 // T022: Migrate internal/network SendQuery logic, make T013 pass
 func (t *UDPv4Transport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
-	// Check context cancellation before sending
 	select {
 	case <-ctx.Done():
 		return &errors.NetworkError{
@@ -99,8 +288,58 @@ func (t *UDPv4Transport) Send(ctx context.Context, packet []byte, dest net.Addr)
 	default:
 	}
 
-	// Send query to destination
-	n, err := t.conn.WriteTo(packet, dest)
+	udpDest, ok := dest.(*net.UDPAddr)
+	if !ok || udpDest == nil {
+		return t.sendToAllJoinedInterfaces(packet)
+	}
+
+	return t.writeTo(packet, nil, udpDest)
+}
+
+// sendToAllJoinedInterfaces multicasts packet to t.group once per
+// currently-joined interface. If no interfaces are joined (shouldn't
+// happen - construction fails when join count is zero - but Rebind could
+// in principle leave the map empty), it falls back to a single send with
+// no interface pinned, letting the kernel pick.
+func (t *UDPv4Transport) sendToAllJoinedInterfaces(packet []byte) error {
+	t.mu.Lock()
+	names := make([]string, 0, len(t.joined))
+	for name := range t.joined {
+		names = append(names, name)
+	}
+	t.mu.Unlock()
+
+	if len(names) == 0 {
+		return t.writeTo(packet, nil, t.group)
+	}
+
+	var errs []error
+	for _, name := range names {
+		ifi, err := net.InterfaceByName(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := t.writeTo(packet, &ipv4.ControlMessage{IfIndex: ifi.Index}, t.group); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == len(names) {
+		return &errors.NetworkError{
+			Operation: "send query",
+			Err:       goerrors.Join(errs...),
+			Details:   "failed to send on every joined interface",
+		}
+	}
+	return nil
+}
+
+// writeTo issues one WriteTo on the shared socket, tagging it with cm (nil
+// to let the kernel choose the outgoing interface) and validating the
+// write was complete.
+func (t *UDPv4Transport) writeTo(packet []byte, cm *ipv4.ControlMessage, dest net.Addr) error {
+	n, err := t.conn.WriteTo(packet, cm, dest)
 	if err != nil {
 		return &errors.NetworkError{
 			Operation: "send query",
@@ -109,7 +348,6 @@ func (t *UDPv4Transport) Send(ctx context.Context, packet []byte, dest net.Addr)
 		}
 	}
 
-	// Verify full message was sent
 	if n != len(packet) {
 		return &errors.NetworkError{
 			Operation: "send query",
@@ -132,7 +370,6 @@ func (t *UDPv4Transport) Send(ctx context.Context, packet []byte, dest net.Addr)
 //
 // T023: Migrate internal/network ReceiveResponse, add ctx.Done() checking to make T014-T015 pass
 func (t *UDPv4Transport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
-	// Check context cancellation before receive
 	select {
 	case <-ctx.Done():
 		return nil, nil, &errors.NetworkError{
@@ -143,10 +380,8 @@ func (t *UDPv4Transport) Receive(ctx context.Context) ([]byte, net.Addr, error)
 	default:
 	}
 
-	// Propagate context deadline to socket (F-9 REQ-F9-7)
 	if deadline, ok := ctx.Deadline(); ok {
-		err := t.conn.SetReadDeadline(deadline)
-		if err != nil {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
 			return nil, nil, &errors.NetworkError{
 				Operation: "set read timeout",
 				Err:       err,
@@ -158,14 +393,11 @@ func (t *UDPv4Transport) Receive(ctx context.Context) ([]byte, net.Addr, error)
 	// T053: Get buffer from pool (FR-003 buffer pooling optimization)
 	// This eliminates hot path allocations (9KB/receive → near-zero after warmup)
 	bufPtr := GetBuffer()
-	defer PutBuffer(bufPtr) // T053: Return buffer to pool on function exit
-
 	buffer := *bufPtr
 
-	// Read response
-	n, srcAddr, err := t.conn.ReadFrom(buffer)
+	n, _, srcAddr, err := t.conn.ReadFrom(buffer)
+	defer func() { PutBufferSized(bufPtr, n) }() // only zero what ReadFrom actually wrote
 	if err != nil {
-		// Check if it's a timeout error
 		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 			return nil, nil, &errors.NetworkError{
 				Operation: "receive response",
@@ -214,3 +446,112 @@ func (t *UDPv4Transport) Close() error {
 
 	return nil
 }
+
+// ipv4BatchPool reuses the []ipv4.Message slice (and each message's OOB
+// scratch buffer) ReceiveBatch builds per call, separately from the
+// GetBuffer/PutBuffer pool: those buffers are handed to the caller until
+// ReturnFunc releases them, while the Message slice and OOB buffers are
+// purely ReceiveBatch-internal scratch space reused on its own return.
+var ipv4BatchPool = sync.Pool{
+	New: func() any {
+		ms := make([]ipv4.Message, batchSize)
+		for i := range ms {
+			ms[i].OOB = ipv4.NewControlMessage(ipv4.FlagInterface)
+		}
+		return &ms
+	},
+}
+
+// ReceiveBatch fills out with up to len(out) packets received in as few
+// syscalls as possible: on Linux, golang.org/x/net/ipv4's PacketConn.ReadBatch
+// issues a single recvmmsg(2) for the whole batch; on platforms without
+// recvmmsg, ReadBatch falls back internally to one packet per call, so
+// ReceiveBatch degrades to a loop there without any special-casing here.
+func (t *UDPv4Transport) ReceiveBatch(ctx context.Context, out []Packet) (int, error) {
+	if len(out) == 0 {
+		return 0, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return 0, &errors.NetworkError{
+			Operation: "receive batch",
+			Err:       ctx.Err(),
+			Details:   "context canceled before receive",
+		}
+	default:
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
+			return 0, &errors.NetworkError{
+				Operation: "set read timeout",
+				Err:       err,
+				Details:   fmt.Sprintf("failed to set deadline %v", deadline),
+			}
+		}
+	}
+
+	n := len(out)
+	if n > batchSize {
+		n = batchSize
+	}
+
+	msPtr := ipv4BatchPool.Get().(*[]ipv4.Message)
+	defer ipv4BatchPool.Put(msPtr)
+	ms := (*msPtr)[:n]
+
+	bufPtrs := make([]*[]byte, n)
+	for i := range ms {
+		bufPtrs[i] = GetBuffer()
+		ms[i].Buffers = [][]byte{*bufPtrs[i]}
+	}
+
+	count, err := t.conn.ReadBatch(ms, 0)
+	if err != nil {
+		for i := range ms {
+			PutBuffer(bufPtrs[i])
+		}
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return 0, &errors.NetworkError{
+				Operation: "receive batch",
+				Err:       err,
+				Details:   "timeout",
+			}
+		}
+		return 0, &errors.NetworkError{
+			Operation: "receive batch",
+			Err:       err,
+			Details:   "failed to read batch from socket",
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		bufPtr := bufPtrs[i]
+		out[i] = Packet{
+			Data:       (*bufPtr)[:ms[i].N],
+			ReturnFunc: func() { PutBuffer(bufPtr) },
+		}
+		if udpAddr, ok := ms[i].Addr.(*net.UDPAddr); ok {
+			out[i].Addr = udpAddr
+		}
+
+		var cm ipv4.ControlMessage
+		if err := cm.Parse(ms[i].OOB); err == nil {
+			if ifi, err := net.InterfaceByIndex(cm.IfIndex); err == nil {
+				out[i].Iface = ifi
+			}
+		}
+	}
+	for i := count; i < n; i++ {
+		PutBuffer(bufPtrs[i])
+	}
+
+	return count, nil
+}
+
+// Compile-time verification that UDPv4Transport implements Transport interface
+var _ Transport = (*UDPv4Transport)(nil)
+
+// Compile-time verification that UDPv4Transport implements BatchReceiver.
+var _ BatchReceiver = (*UDPv4Transport)(nil)
@@ -44,6 +44,22 @@ func TestUDPv4Transport_Send_SendsToMulticastAddress(t *testing.T) {
 	}
 }
 
+// TestUDPv4Transport_Send_NilDestFansOutToEveryJoinedInterface validates
+// that a nil dest (the "multicast to the default group" case) succeeds
+// even though it now sends once per joined interface instead of once with
+// no interface pinned - see sendToAllJoinedInterfaces.
+func TestUDPv4Transport_Send_NilDestFansOutToEveryJoinedInterface(t *testing.T) {
+	tr, err := transport.NewUDPv4Transport()
+	if err != nil {
+		t.Fatalf("NewUDPv4Transport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	if err := tr.Send(context.Background(), []byte{0x00, 0x00, 0x00, 0x00}, nil); err != nil {
+		t.Errorf("Send(nil) failed: %v", err)
+	}
+}
+
 // T014: Unit test - UDPv4Transport.Receive() respects context cancellation
 // NOTE: This test will FAIL to compile until UDPv4Transport.Receive() exists (T023)
 func TestUDPv4Transport_Receive_RespectsContextCancellation(t *testing.T) {
@@ -183,6 +199,70 @@ func TestBufferPool_ReusesBuffers(t *testing.T) {
 	}
 }
 
+// TestBufferPool_GetBufferSizedPicksSmallestClass verifies GetBufferSized
+// rounds n up to the smallest size class able to hold it.
+func TestBufferPool_GetBufferSizedPicksSmallestClass(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{n: 100, want: 512},
+		{n: 512, want: 512},
+		{n: 800, want: 1500},
+		{n: 4096, want: 4096},
+		{n: 8999, want: 9000},
+	}
+
+	for _, tc := range cases {
+		bufPtr := transport.GetBufferSized(tc.n)
+		if bufPtr == nil {
+			t.Fatalf("GetBufferSized(%d) returned nil", tc.n)
+		}
+		if got := len(*bufPtr); got != tc.want {
+			t.Errorf("GetBufferSized(%d) returned buffer of length %d, want %d", tc.n, got, tc.want)
+		}
+		transport.PutBufferSized(bufPtr, tc.n)
+	}
+}
+
+// TestBufferPool_GetBufferSizedOverCeilingIsUnpooled verifies that a request
+// larger than every size class (including the 9000-byte jumbo-frame ceiling)
+// still returns a usable buffer, just not one drawn from sizedPools.
+func TestBufferPool_GetBufferSizedOverCeilingIsUnpooled(t *testing.T) {
+	bufPtr := transport.GetBufferSized(9001)
+	if bufPtr == nil {
+		t.Fatal("GetBufferSized(9001) returned nil")
+	}
+	if got := len(*bufPtr); got != 9001 {
+		t.Errorf("GetBufferSized(9001) returned buffer of length %d, want 9001", got)
+	}
+	// Must not panic even though this buffer isn't one of the size classes.
+	transport.PutBufferSized(bufPtr, 9001)
+}
+
+// TestBufferPool_PutBufferSizedOnlyZeroesUsedPrefix verifies that bytes past
+// `used` are left untouched by PutBufferSized, while bytes within [0, used)
+// are zeroed.
+func TestBufferPool_PutBufferSizedOnlyZeroesUsedPrefix(t *testing.T) {
+	bufPtr := transport.GetBufferSized(512)
+	buf := *bufPtr
+	for i := range buf {
+		buf[i] = 0xFF
+	}
+
+	transport.PutBufferSized(bufPtr, 3)
+
+	bufPtr2 := transport.GetBufferSized(512)
+	defer transport.PutBufferSized(bufPtr2, 512)
+	buf2 := *bufPtr2
+
+	for i := 0; i < 3; i++ {
+		if buf2[i] != 0 {
+			t.Errorf("buf2[%d] = %#x, want 0 (within used prefix)", i, buf2[i])
+		}
+	}
+}
+
 // T047: Receive returns buffer to pool (no leaks)
 func TestUDPv4Transport_ReceiveReturnsBufferToPool(t *testing.T) {
 	tr, err := transport.NewUDPv4Transport()
@@ -253,3 +333,34 @@ func TestUDPv4Transport_Close_PropagatesErrorsValidation(t *testing.T) {
 		t.Logf("✓ FR-004 VALIDATED: Close() propagates error: %v", err)
 	}
 }
+
+// TestUDPv4Transport_JoinedInterfaces_MatchesExplicitInterfaceList verifies
+// that JoinedInterfaces reports exactly the interface NewUDPv4TransportWithInterfaces
+// was given, once it successfully joins.
+func TestUDPv4Transport_JoinedInterfaces_MatchesExplicitInterfaceList(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("net.Interfaces() failed: %v", err)
+	}
+	var target net.Interface
+	for _, ifi := range ifaces {
+		if ifi.Flags&net.FlagUp != 0 && ifi.Flags&net.FlagMulticast != 0 && ifi.Flags&net.FlagLoopback == 0 {
+			target = ifi
+			break
+		}
+	}
+	if target.Name == "" {
+		t.Skip("no up, multicast-capable, non-loopback interface available in this environment")
+	}
+
+	tr, err := transport.NewUDPv4TransportWithInterfaces([]net.Interface{target})
+	if err != nil {
+		t.Fatalf("NewUDPv4TransportWithInterfaces() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	got := tr.JoinedInterfaces()
+	if len(got) != 1 || got[0] != target.Name {
+		t.Errorf("JoinedInterfaces() = %v, want [%q]", got, target.Name)
+	}
+}
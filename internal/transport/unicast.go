@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+)
+
+// UnicastUDPTransport is a client-side UDP socket bound to an OS-assigned
+// ephemeral port, as opposed to UDPv4Transport/UDPv6Transport's sockets
+// bound to the fixed mDNS port 5353 and joined to the multicast group.
+//
+// It exists for RFC 6762 §5.4 unicast-response (QU bit) queries: the query
+// itself is still sent to the multicast group, but the source port a
+// responder sees - and therefore replies to - is this transport's ephemeral
+// port rather than 5353, so the reply never reaches other listeners on the
+// LAN. It is also used for LegacyResolver's one-shot unicast queries to a
+// specific peer.
+type UnicastUDPTransport struct {
+	conn *net.UDPConn
+}
+
+// NewUnicastUDPTransport opens an ephemeral-port UDP socket for the given
+// network ("udp4" or "udp6").
+func NewUnicastUDPTransport(network string) (*UnicastUDPTransport, error) {
+	conn, err := net.ListenUDP(network, nil) // nil addr -> OS picks an ephemeral port
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "create unicast socket",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to bind ephemeral %s socket", network),
+		}
+	}
+
+	return &UnicastUDPTransport{conn: conn}, nil
+}
+
+// LocalPort returns the ephemeral port the OS assigned this socket.
+func (t *UnicastUDPTransport) LocalPort() int {
+	return t.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// Send transmits a packet to dest, same as UDPv4Transport.Send.
+func (t *UnicastUDPTransport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	select {
+	case <-ctx.Done():
+		return &errors.NetworkError{
+			Operation: "send unicast query",
+			Err:       ctx.Err(),
+			Details:   "context canceled before send",
+		}
+	default:
+	}
+
+	n, err := t.conn.WriteTo(packet, dest)
+	if err != nil {
+		return &errors.NetworkError{
+			Operation: "send unicast query",
+			Err:       err,
+			Details:   fmt.Sprintf("failed to send %d bytes to %s", len(packet), dest),
+		}
+	}
+	if n != len(packet) {
+		return &errors.NetworkError{
+			Operation: "send unicast query",
+			Err:       fmt.Errorf("partial write: %d/%d bytes", n, len(packet)),
+			Details:   "incomplete transmission",
+		}
+	}
+
+	return nil
+}
+
+// Receive waits for a reply on the ephemeral port, same as UDPv4Transport.Receive.
+func (t *UnicastUDPTransport) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive unicast response",
+			Err:       ctx.Err(),
+			Details:   "context canceled before receive",
+		}
+	default:
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := t.conn.SetReadDeadline(deadline); err != nil {
+			return nil, nil, &errors.NetworkError{
+				Operation: "set read timeout",
+				Err:       err,
+				Details:   fmt.Sprintf("failed to set deadline %v", deadline),
+			}
+		}
+	}
+
+	bufPtr := GetBuffer()
+	buffer := *bufPtr
+
+	n, srcAddr, err := t.conn.ReadFrom(buffer)
+	defer func() { PutBufferSized(bufPtr, n) }() // only zero what ReadFrom actually wrote
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return nil, nil, &errors.NetworkError{
+				Operation: "receive unicast response",
+				Err:       err,
+				Details:   "timeout",
+			}
+		}
+		return nil, nil, &errors.NetworkError{
+			Operation: "receive unicast response",
+			Err:       err,
+			Details:   "failed to read from socket",
+		}
+	}
+
+	result := make([]byte, n)
+	copy(result, buffer[:n])
+	return result, srcAddr, nil
+}
+
+// Close releases the socket.
+func (t *UnicastUDPTransport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+
+	if err := t.conn.Close(); err != nil {
+		return &errors.NetworkError{
+			Operation: "close socket",
+			Err:       err,
+			Details:   "failed to close unicast UDP connection",
+		}
+	}
+
+	return nil
+}
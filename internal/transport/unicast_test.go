@@ -0,0 +1,158 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestUnicastUDPTransport_ImplementsTransportInterface is a compile-time
+// contract check that UnicastUDPTransport satisfies Transport, mirroring
+// TestUDPv4Transport_ImplementsTransportInterface.
+func TestUnicastUDPTransport_ImplementsTransportInterface(_ *testing.T) {
+	var _ transport.Transport = (*transport.UnicastUDPTransport)(nil)
+}
+
+// TestUnicastUDPTransport_LocalPort_IsEphemeral validates that
+// NewUnicastUDPTransport binds an OS-assigned port rather than the fixed
+// mDNS port 5353, since two unicast queriers on the same host must not
+// collide on a shared port.
+func TestUnicastUDPTransport_LocalPort_IsEphemeral(t *testing.T) {
+	tr, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	if tr.LocalPort() == 5353 {
+		t.Error("LocalPort() returned 5353, want an OS-assigned ephemeral port")
+	}
+	if tr.LocalPort() == 0 {
+		t.Error("LocalPort() returned 0, want a bound port")
+	}
+}
+
+// TestUnicastUDPTransport_Send_SendsToMulticastAddress validates that Send()
+// succeeds against the mDNS IPv4 multicast group, mirroring
+// TestUDPv4Transport_Send_SendsToMulticastAddress.
+func TestUnicastUDPTransport_Send_SendsToMulticastAddress(t *testing.T) {
+	tr, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ctx := context.Background()
+	packet := []byte{0x00, 0x00, 0x00, 0x00}
+	mdnsAddr := &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+	if err := tr.Send(ctx, packet, mdnsAddr); err != nil {
+		t.Errorf("Send() failed: %v", err)
+	}
+}
+
+// TestUnicastUDPTransport_Receive_RespectsContextCancellation mirrors
+// TestUDPv4Transport_Receive_RespectsContextCancellation: Receive() must
+// return promptly once ctx is already canceled, rather than blocking.
+func TestUnicastUDPTransport_Receive_RespectsContextCancellation(t *testing.T) {
+	tr, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, _, err = tr.Receive(ctx)
+	duration := time.Since(start)
+
+	if err == nil {
+		t.Error("Receive() should return error when context is canceled")
+	}
+	if duration > 100*time.Millisecond {
+		t.Errorf("Receive() took too long (%v) to detect cancellation", duration)
+	}
+}
+
+// TestUnicastUDPTransport_Receive_PropagatesContextDeadline mirrors
+// TestUDPv4Transport_Receive_PropagatesContextDeadline: with no traffic
+// arriving on the ephemeral port, Receive() must time out close to the
+// context's deadline rather than blocking indefinitely.
+func TestUnicastUDPTransport_Receive_PropagatesContextDeadline(t *testing.T) {
+	tr, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+	defer func() { _ = tr.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = tr.Receive(ctx)
+	duration := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Receive() should time out with no traffic on a fresh ephemeral port")
+	}
+	if duration > 150*time.Millisecond {
+		t.Errorf("Receive() took too long (%v) to time out, expected ~50ms", duration)
+	}
+}
+
+// TestUnicastUDPTransport_SendReceive_RoundTrip validates that a packet sent
+// from one UnicastUDPTransport to another's LocalPort() is received intact,
+// the same round trip QueryUnicast/LegacyResolver rely on.
+func TestUnicastUDPTransport_SendReceive_RoundTrip(t *testing.T) {
+	server, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+
+	client, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	packet := []byte("hello unicast")
+	dest := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: server.LocalPort()}
+
+	if err := client.Send(context.Background(), packet, dest); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	got, _, err := server.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if string(got) != string(packet) {
+		t.Errorf("Receive() = %q, want %q", got, packet)
+	}
+}
+
+// TestUnicastUDPTransport_Close_PropagatesErrors mirrors
+// TestUDPv4Transport_Close_PropagatesErrors: a second Close() on an
+// already-closed socket must return an error rather than silently succeed.
+func TestUnicastUDPTransport_Close_PropagatesErrors(t *testing.T) {
+	tr, err := transport.NewUnicastUDPTransport("udp4")
+	if err != nil {
+		t.Fatalf("NewUnicastUDPTransport() failed: %v", err)
+	}
+
+	if err := tr.Close(); err != nil {
+		t.Errorf("First Close() should succeed, got error: %v", err)
+	}
+	if err := tr.Close(); err == nil {
+		t.Error("Second Close() should return error (socket already closed)")
+	}
+}
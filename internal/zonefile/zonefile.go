@@ -0,0 +1,70 @@
+// Package zonefile reads and writes resource records in the RFC 1035 §5.1
+// presentation (zone file) format.
+//
+// This lets operators declare services from a config file instead of the
+// Go API, and lets the fuzz corpus (tests/fuzz) be seeded from human-readable
+// test vectors instead of hand-built wire bytes.
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// Parse reads presentation-format resource records from r, one per line.
+//
+// Blank lines and lines starting with ';' (RFC 1035 §5.1 comments) are
+// ignored. Each remaining line is parsed with message.ResourceRecord.UnmarshalText.
+func Parse(r io.Reader) ([]*message.ResourceRecord, error) {
+	var records []*message.ResourceRecord
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if idx := strings.Index(line, ";"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" {
+			continue
+		}
+
+		rr := &message.ResourceRecord{}
+		if err := rr.UnmarshalText([]byte(line)); err != nil {
+			return nil, &errors.ValidationError{
+				Field:   "line",
+				Value:   lineNum,
+				Message: fmt.Sprintf("zone file line %d: %v", lineNum, err),
+			}
+		}
+		records = append(records, rr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &errors.WireFormatError{Operation: "read zone file", Message: "scanner failed", Err: err}
+	}
+
+	return records, nil
+}
+
+// Write renders records to w in presentation format, one record per line.
+func Write(w io.Writer, records []*message.ResourceRecord) error {
+	for _, rr := range records {
+		text, err := rr.MarshalText()
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, string(text)); err != nil {
+			return &errors.WireFormatError{Operation: "write zone file", Message: "write failed", Err: err}
+		}
+	}
+	return nil
+}
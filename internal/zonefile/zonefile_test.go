@@ -0,0 +1,46 @@
+package zonefile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParse_SkipsCommentsAndBlankLines(t *testing.T) {
+	input := `; this is a zone file
+test.local. 120 IN A 192.168.1.100
+
+; another comment
+printer.local. 120 IN A 192.168.1.101
+`
+	records, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+}
+
+func TestParse_InvalidLine(t *testing.T) {
+	_, err := Parse(strings.NewReader("not a valid record"))
+	if err == nil {
+		t.Error("expected error for malformed line, got nil")
+	}
+}
+
+func TestWrite_RoundTrip(t *testing.T) {
+	input := "test.local. 120 IN A 192.168.1.100\n"
+	records, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, records); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if buf.String() != input {
+		t.Errorf("Write() = %q, want %q", buf.String(), input)
+	}
+}
@@ -0,0 +1,33 @@
+// Package metrics defines the minimal instrumentation hook state.Machine and
+// querier.Querier call into at their key observability points (state
+// transitions, probe conflicts, outbound queries, rate-limit drops,
+// Known-Answer cache hits/misses, query latency), so an operator running
+// many concurrent registrations or queriers can wire in Prometheus,
+// OpenTelemetry, or any other backend without Beacon depending on one
+// directly. See metrics/prom for a ready-made Prometheus exposition adapter.
+package metrics
+
+// Metrics receives counter increments and histogram observations from
+// Beacon's internals. Implementations must be safe for concurrent use -
+// every method may be called from multiple goroutines at once (one per
+// registered service's state.Machine, one per in-flight Querier.Query
+// call).
+type Metrics interface {
+	// IncCounter increments the named counter by one, tagged with labels.
+	IncCounter(name string, labels map[string]string)
+
+	// ObserveHistogram records a single observation v for the named
+	// histogram, tagged with labels.
+	ObserveHistogram(name string, v float64, labels map[string]string)
+}
+
+// NoOp is a Metrics implementation that discards every call. It's the
+// default for both state.WithMetrics and querier.WithMetrics, so Beacon
+// never builds a label map or computes a duration unless a caller opts in.
+type NoOp struct{}
+
+// IncCounter implements Metrics by discarding the call.
+func (NoOp) IncCounter(string, map[string]string) {}
+
+// ObserveHistogram implements Metrics by discarding the call.
+func (NoOp) ObserveHistogram(string, float64, map[string]string) {}
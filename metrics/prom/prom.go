@@ -0,0 +1,133 @@
+// Package prom adapts metrics.Metrics to the Prometheus text exposition
+// format (https://prometheus.io/docs/instrumenting/exposition_formats/), so
+// an application can register Beacon's counters and histograms on its own
+// /metrics endpoint without pulling in a full client library.
+package prom
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/joshuafuller/beacon/metrics"
+)
+
+// Collector is a metrics.Metrics implementation that accumulates counters
+// and histogram observations in memory and serves them in Prometheus text
+// exposition format via ServeHTTP.
+//
+// A zero-value Collector is not usable; construct one with NewCollector.
+type Collector struct {
+	mu         sync.Mutex
+	counters   map[string]float64
+	histograms map[string]*histogramState
+}
+
+// histogramState tracks the count and sum Collector needs to expose a
+// histogram's _count and _sum series - enough for rate() and average
+// queries, without committing to a fixed set of bucket boundaries.
+type histogramState struct {
+	count float64
+	sum   float64
+}
+
+var _ metrics.Metrics = (*Collector)(nil)
+var _ http.Handler = (*Collector)(nil)
+
+// NewCollector creates an empty Collector, ready to pass to
+// state.WithMetrics/querier.WithMetrics and to register on an application's
+// own /metrics endpoint via ServeHTTP.
+func NewCollector() *Collector {
+	return &Collector{
+		counters:   make(map[string]float64),
+		histograms: make(map[string]*histogramState),
+	}
+}
+
+// IncCounter implements metrics.Metrics.
+func (c *Collector) IncCounter(name string, labels map[string]string) {
+	key := seriesKey(name, labels)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counters[key]++
+}
+
+// ObserveHistogram implements metrics.Metrics.
+func (c *Collector) ObserveHistogram(name string, v float64, labels map[string]string) {
+	key := seriesKey(name, labels)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.histograms[key]
+	if !ok {
+		h = &histogramState{}
+		c.histograms[key] = h
+	}
+	h.count++
+	h.sum += v
+}
+
+// ServeHTTP writes every accumulated counter and histogram in Prometheus
+// text exposition format, for use as an http.Handler on a /metrics route.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range sortedKeys(c.counters) {
+		fmt.Fprintf(w, "%s %s\n", key, formatFloat(c.counters[key]))
+	}
+	for _, key := range sortedKeys(c.histograms) {
+		h := c.histograms[key]
+		fmt.Fprintf(w, "%s_count %s\n", key, formatFloat(h.count))
+		fmt.Fprintf(w, "%s_sum %s\n", key, formatFloat(h.sum))
+	}
+}
+
+// seriesKey renders name and its labels as a single Prometheus series name,
+// e.g. `beacon_querier_queries_total{record_type="A"}`. Label keys are
+// sorted so the same (name, labels) pair always maps to the same series
+// regardless of map iteration order.
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", k, labels[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns m's keys in sorted order, so ServeHTTP's output is
+// stable across calls for the same accumulated data.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package prom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCollector_IncCounter_AccumulatesAndServesSeries(t *testing.T) {
+	c := NewCollector()
+	c.IncCounter("beacon_state_transitions_total", map[string]string{"from": "Initial", "to": "Probing"})
+	c.IncCounter("beacon_state_transitions_total", map[string]string{"from": "Initial", "to": "Probing"})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	want := `beacon_state_transitions_total{from="Initial",to="Probing"} 2`
+	if !strings.Contains(body, want) {
+		t.Errorf("ServeHTTP body = %q, want substring %q", body, want)
+	}
+}
+
+func TestCollector_IncCounter_NoLabels(t *testing.T) {
+	c := NewCollector()
+	c.IncCounter("beacon_querier_drops_total", nil)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.Contains(rec.Body.String(), "beacon_querier_drops_total 1") {
+		t.Errorf("ServeHTTP body = %q, want unlabeled series", rec.Body.String())
+	}
+}
+
+func TestCollector_ObserveHistogram_AccumulatesCountAndSum(t *testing.T) {
+	c := NewCollector()
+	c.ObserveHistogram("beacon_querier_query_duration_seconds", 0.5, map[string]string{"record_type": "A"})
+	c.ObserveHistogram("beacon_querier_query_duration_seconds", 1.5, map[string]string{"record_type": "A"})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`beacon_querier_query_duration_seconds{record_type="A"}_count 2`,
+		`beacon_querier_query_duration_seconds{record_type="A"}_sum 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("ServeHTTP body = %q, want substring %q", body, want)
+		}
+	}
+}
+
+func TestCollector_ServeHTTP_ContentType(t *testing.T) {
+	c := NewCollector()
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4" {
+		t.Errorf("Content-Type = %q, want %q", ct, "text/plain; version=0.0.4")
+	}
+}
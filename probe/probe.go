@@ -0,0 +1,161 @@
+// Package probe tracks per-component readiness/liveness status and exposes
+// it over HTTP for Kubernetes-style liveness/readiness gates, modeled on
+// Open Networking Foundation's voltha-lib-go probe package.
+package probe
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Status is the lifecycle state of one component tracked by a Probe.
+type Status int
+
+const (
+	// StatusUnknown is a component's status before its first UpdateStatus
+	// call.
+	StatusUnknown Status = iota
+
+	// StatusPreparing indicates a component is starting up but not yet
+	// serving (e.g. a service mid-probe or mid-announce, RFC 6762 §8).
+	StatusPreparing
+
+	// StatusRunning indicates a component is up and serving.
+	StatusRunning
+
+	// StatusStopped indicates a component was deliberately torn down.
+	StatusStopped
+
+	// StatusFailed indicates a component failed to start or stay up.
+	StatusFailed
+
+	// StatusNotReady indicates a component is up but has failed a readiness
+	// check and should not receive traffic.
+	StatusNotReady
+)
+
+// String returns a human-readable name for the status.
+func (s Status) String() string {
+	switch s {
+	case StatusPreparing:
+		return "Preparing"
+	case StatusRunning:
+		return "Running"
+	case StatusStopped:
+		return "Stopped"
+	case StatusFailed:
+		return "Failed"
+	case StatusNotReady:
+		return "NotReady"
+	default:
+		return "Unknown"
+	}
+}
+
+// Probe tracks the Status of any number of named components (e.g. one per
+// registered service, plus the transport itself) and answers /healthz and
+// /readyz over HTTP based on their aggregate state. The zero value is not
+// usable; construct one with New.
+type Probe struct {
+	mu       sync.RWMutex
+	statuses map[string]Status
+}
+
+// New creates an empty Probe, with no components registered yet.
+func New() *Probe {
+	return &Probe{statuses: make(map[string]Status)}
+}
+
+// UpdateStatus records componentID's current status, overwriting any
+// previous value.
+func (p *Probe) UpdateStatus(componentID string, status Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.statuses[componentID] = status
+}
+
+// GetStatus returns componentID's last-recorded status, or StatusUnknown if
+// UpdateStatus was never called for it.
+func (p *Probe) GetStatus(componentID string) Status {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.statuses[componentID]
+}
+
+// Healthy reports whether every tracked component is in some serving or
+// starting-up state - i.e. none has failed or stopped. An empty Probe (no
+// components registered yet) is healthy.
+func (p *Probe) Healthy() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, status := range p.statuses {
+		if status == StatusFailed || status == StatusStopped {
+			return false
+		}
+	}
+	return true
+}
+
+// Ready reports whether every tracked component is StatusRunning. An empty
+// Probe (no components registered yet) is not ready.
+func (p *Probe) Ready() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.statuses) == 0 {
+		return false
+	}
+	for _, status := range p.statuses {
+		if status != StatusRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler returns an http.Handler serving /healthz (200 while Healthy, 503
+// otherwise) and /readyz (200 while Ready, 503 otherwise), suitable for
+// wiring directly into a Kubernetes liveness/readiness probe.
+func (p *Probe) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", p.serveHealthz)
+	mux.HandleFunc("/readyz", p.serveReadyz)
+	return mux
+}
+
+func (p *Probe) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	if !p.Healthy() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (p *Probe) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	if !p.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// probeContextKey is the unexported type for the context key WithValue/
+// FromContext use, per the standard library's own context-key convention
+// (avoids collisions with keys other packages set).
+type probeContextKey struct{}
+
+// WithValue returns a copy of ctx carrying p, so a state-machine goroutine
+// that only has a context.Context can retrieve it via FromContext and call
+// UpdateStatus without Responder threading a *Probe through every call.
+func WithValue(ctx context.Context, p *Probe) context.Context {
+	return context.WithValue(ctx, probeContextKey{}, p)
+}
+
+// FromContext returns the *Probe previously attached via WithValue, or nil
+// if ctx carries none.
+func FromContext(ctx context.Context) *Probe {
+	p, _ := ctx.Value(probeContextKey{}).(*Probe)
+	return p
+}
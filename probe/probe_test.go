@@ -0,0 +1,126 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestProbe_UpdateStatus_GetStatus verifies a component's status round-trips
+// through UpdateStatus/GetStatus, and an untouched component reads as
+// StatusUnknown.
+func TestProbe_UpdateStatus_GetStatus(t *testing.T) {
+	p := New()
+
+	if got := p.GetStatus("svc1"); got != StatusUnknown {
+		t.Errorf("GetStatus() on untouched component = %v, want StatusUnknown", got)
+	}
+
+	p.UpdateStatus("svc1", StatusRunning)
+	if got := p.GetStatus("svc1"); got != StatusRunning {
+		t.Errorf("GetStatus() = %v, want StatusRunning", got)
+	}
+}
+
+// TestProbe_Ready verifies Ready requires at least one component and every
+// tracked component to be StatusRunning.
+func TestProbe_Ready(t *testing.T) {
+	p := New()
+	if p.Ready() {
+		t.Error("Ready() = true for an empty Probe, want false")
+	}
+
+	p.UpdateStatus("svc1", StatusRunning)
+	if !p.Ready() {
+		t.Error("Ready() = false with one Running component, want true")
+	}
+
+	p.UpdateStatus("svc2", StatusPreparing)
+	if p.Ready() {
+		t.Error("Ready() = true with a Preparing component, want false")
+	}
+}
+
+// TestProbe_Healthy verifies Healthy is false only once a component has
+// failed or stopped.
+func TestProbe_Healthy(t *testing.T) {
+	p := New()
+	if !p.Healthy() {
+		t.Error("Healthy() = false for an empty Probe, want true")
+	}
+
+	p.UpdateStatus("svc1", StatusPreparing)
+	if !p.Healthy() {
+		t.Error("Healthy() = false with a Preparing component, want true")
+	}
+
+	p.UpdateStatus("svc1", StatusFailed)
+	if p.Healthy() {
+		t.Error("Healthy() = true with a Failed component, want false")
+	}
+}
+
+// TestProbe_Handler verifies /healthz and /readyz report 200/503 per
+// Healthy/Ready.
+func TestProbe_Handler(t *testing.T) {
+	p := New()
+	handler := p.Handler()
+
+	assertStatus := func(t *testing.T, path string, want int) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s = %d, want %d", path, rec.Code, want)
+		}
+	}
+
+	assertStatus(t, "/healthz", http.StatusOK)
+	assertStatus(t, "/readyz", http.StatusServiceUnavailable)
+
+	p.UpdateStatus("svc1", StatusRunning)
+	assertStatus(t, "/readyz", http.StatusOK)
+
+	p.UpdateStatus("svc1", StatusFailed)
+	assertStatus(t, "/healthz", http.StatusServiceUnavailable)
+	assertStatus(t, "/readyz", http.StatusServiceUnavailable)
+}
+
+// TestWithValue_FromContext verifies a Probe attached via WithValue is
+// retrievable via FromContext, and FromContext returns nil without one.
+func TestWithValue_FromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("FromContext() on bare context = %v, want nil", got)
+	}
+
+	p := New()
+	ctx := WithValue(context.Background(), p)
+	if got := FromContext(ctx); got != p {
+		t.Errorf("FromContext() = %v, want %v", got, p)
+	}
+}
+
+// TestStatus_String verifies String covers every defined constant plus the
+// unknown fallback.
+func TestStatus_String(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   string
+	}{
+		{StatusUnknown, "Unknown"},
+		{StatusPreparing, "Preparing"},
+		{StatusRunning, "Running"},
+		{StatusStopped, "Stopped"},
+		{StatusFailed, "Failed"},
+		{StatusNotReady, "NotReady"},
+		{Status(99), "Unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.String(); got != tt.want {
+			t.Errorf("Status(%d).String() = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
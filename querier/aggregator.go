@@ -0,0 +1,96 @@
+package querier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// aggregationWindow is how long QueryAggregator holds pending questions
+// before flushing them into one packet, per RFC 6762 §7.1's guidance that a
+// querier "SHOULD, before sending a question, ... bundle the maximum number
+// of questions it can" into a single packet.
+const aggregationWindow = 120 * time.Millisecond
+
+// QueryAggregator batches AddQuery calls made within a 120ms window into a
+// single mDNS query packet (see message.BuildMultiQuery), attaching any
+// still-fresh cached answers as a Known-Answer list (RFC 6762 §7.1) so a
+// responder already holding the same data can suppress its reply.
+//
+// It is a standalone batching utility, not wired into Querier.Query's
+// default path: Query's contract is "send now, collect this question's
+// responses", which a shared aggregation window would change.
+// QueryAggregator instead suits a caller doing its own batch or background
+// querying - e.g. refreshing a whole set of known service instances at
+// once - that wants several questions on the wire as one packet rather than
+// one send per name.
+//
+// Safe for concurrent use.
+type QueryAggregator struct {
+	// send transmits a built query packet, e.g. Querier.sendQuery or a
+	// transport.Transport.Send wrapper. Required.
+	send func(packet []byte) error
+
+	// cache, if set, supplies each flush's Known-Answer list exactly as
+	// Querier.Query does. A nil cache omits Known-Answers entirely.
+	cache *KnownAnswerCache
+
+	mu      sync.Mutex
+	pending []message.QuerySpec
+	timer   *time.Timer
+}
+
+// NewQueryAggregator creates a QueryAggregator that flushes accumulated
+// questions by calling send.
+func NewQueryAggregator(send func(packet []byte) error, cache *KnownAnswerCache) *QueryAggregator {
+	return &QueryAggregator{send: send, cache: cache}
+}
+
+// AddQuery queues name/recordType to go out in the next flush, starting the
+// 120ms aggregation window if this is the first question queued since the
+// last flush. A question already pending for the same name/recordType is
+// not deduplicated - RFC 6762 doesn't forbid repeating a question in one
+// packet, and the responder-side suppression that matters is the
+// Known-Answer list, not question dedup.
+func (a *QueryAggregator) AddQuery(name string, recordType RecordType) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pending = append(a.pending, message.QuerySpec{Name: name, Type: uint16(recordType)})
+	if a.timer == nil {
+		a.timer = time.AfterFunc(aggregationWindow, func() { _ = a.Flush() })
+	}
+}
+
+// Flush sends whatever is currently pending immediately, without waiting
+// out the rest of the aggregation window. A no-op if nothing is pending.
+// Safe to call concurrently with AddQuery and with the window's own timer.
+func (a *QueryAggregator) Flush() error {
+	a.mu.Lock()
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	questions := a.pending
+	a.pending = nil
+	a.mu.Unlock()
+
+	if len(questions) == 0 {
+		return nil
+	}
+
+	var knownAnswers []*message.ResourceRecord
+	if a.cache != nil {
+		for _, q := range questions {
+			knownAnswers = append(knownAnswers, a.cache.knownAnswers(q.Name, RecordType(q.Type), uint16(protocol.ClassIN))...)
+		}
+	}
+
+	packet, err := message.BuildMultiQuery(questions, knownAnswers)
+	if err != nil {
+		return err
+	}
+	return a.send(packet)
+}
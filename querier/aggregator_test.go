@@ -0,0 +1,108 @@
+package querier
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// TestQueryAggregator_Flush_PacksEveryPendingQuestion verifies that
+// questions queued via AddQuery before Flush is called all land in one
+// packet's QD section.
+func TestQueryAggregator_Flush_PacksEveryPendingQuestion(t *testing.T) {
+	var sent [][]byte
+	agg := NewQueryAggregator(func(packet []byte) error {
+		sent = append(sent, packet)
+		return nil
+	}, nil)
+
+	agg.AddQuery("a.local", RecordTypeA)
+	agg.AddQuery("b.local", RecordTypeAAAA)
+
+	if err := agg.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("len(sent) = %d, want 1", len(sent))
+	}
+
+	msg, err := message.ParseMessage(sent[0])
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if len(msg.Questions) != 2 {
+		t.Fatalf("len(Questions) = %d, want 2", len(msg.Questions))
+	}
+}
+
+// TestQueryAggregator_Flush_NoPendingIsNoop verifies that Flush with
+// nothing queued neither sends a packet nor errors.
+func TestQueryAggregator_Flush_NoPendingIsNoop(t *testing.T) {
+	called := false
+	agg := NewQueryAggregator(func([]byte) error {
+		called = true
+		return nil
+	}, nil)
+
+	if err := agg.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if called {
+		t.Error("send was called with nothing pending")
+	}
+}
+
+// TestQueryAggregator_Flush_AttachesKnownAnswers verifies that Flush
+// includes a cached record's Known-Answer in the packet sent.
+func TestQueryAggregator_Flush_AttachesKnownAnswers(t *testing.T) {
+	cache := NewKnownAnswerCache(10)
+	cache.Put(aRecord("a.local", 120))
+
+	var sent []byte
+	agg := NewQueryAggregator(func(packet []byte) error {
+		sent = packet
+		return nil
+	}, cache)
+
+	agg.AddQuery("a.local", RecordTypeA)
+	if err := agg.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	msg, err := message.ParseMessage(sent)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if len(msg.Answers) != 1 {
+		t.Fatalf("len(Answers) = %d, want 1 (Known-Answer)", len(msg.Answers))
+	}
+}
+
+// TestQueryAggregator_AddQuery_FlushesAfterWindow verifies that AddQuery's
+// 120ms window fires Flush on its own without an explicit call.
+func TestQueryAggregator_AddQuery_FlushesAfterWindow(t *testing.T) {
+	var mu sync.Mutex
+	var sent [][]byte
+	agg := NewQueryAggregator(func(packet []byte) error {
+		mu.Lock()
+		sent = append(sent, packet)
+		mu.Unlock()
+		return nil
+	}, nil)
+
+	agg.AddQuery("a.local", RecordTypeA)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(sent)
+		mu.Unlock()
+		if n == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("aggregation window did not flush automatically")
+}
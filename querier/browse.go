@@ -0,0 +1,137 @@
+package querier
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// ServiceInstance is one service instance discovered by BrowseSubtype,
+// combining its PTR target with the SRV and TXT records a caller typically
+// wants next, so callers don't have to chain the three Query calls
+// themselves (see examples/discover for the manual form this replaces).
+type ServiceInstance struct {
+	// InstanceName is the full service instance name the subtype PTR record
+	// pointed at (e.g., "My Printer._http._tcp.local").
+	InstanceName string
+
+	// Target is the SRV record's hostname, or empty if the instance's SRV
+	// record could not be resolved within ctx.
+	Target string
+
+	// Port is the SRV record's port, valid only when Target is non-empty.
+	Port uint16
+
+	// TXT holds the instance's TXT record entries, or nil if its TXT
+	// record could not be resolved within ctx.
+	TXT []string
+}
+
+// BrowseSubtype discovers service instances advertising the RFC 6763 §7.1
+// subtype "<subtype>._sub.<serviceType>" (e.g. BrowseSubtype(ctx,
+// "_printer", "_http._tcp.local") queries "_printer._sub._http._tcp.local"),
+// then resolves each returned instance's SRV and TXT records, mirroring the
+// manual PTR->SRV->TXT chain shown in examples/discover.
+//
+// A per-instance SRV or TXT lookup that fails or returns nothing is not
+// fatal: the instance is still returned, with Target/TXT left at their
+// zero values, since a caller can often still act on the PTR answer alone
+// - matching Query's own no-results-is-not-an-error convention.
+func (q *Querier) BrowseSubtype(ctx context.Context, subtype, serviceType string) ([]ServiceInstance, error) {
+	subtypeName := subtype + "._sub." + serviceType
+
+	ptrResponse, err := q.Query(ctx, subtypeName, RecordTypePTR)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]ServiceInstance, 0, len(ptrResponse.Records))
+	for _, record := range ptrResponse.Records {
+		instanceName := record.AsPTR()
+		if instanceName == "" {
+			continue
+		}
+
+		instances = append(instances, q.resolveInstance(ctx, instanceName))
+	}
+
+	return instances, nil
+}
+
+// resolveInstance queries instanceName's SRV and TXT records and merges them
+// into a ServiceInstance, the shared resolution step BrowseSubtype and
+// WatchServiceType both build on. A failed or empty SRV/TXT lookup is not
+// fatal: the field is left at its zero value, matching BrowseSubtype's
+// documented no-results-is-not-an-error convention.
+func (q *Querier) resolveInstance(ctx context.Context, instanceName string) ServiceInstance {
+	instance := ServiceInstance{InstanceName: instanceName}
+
+	if srvResponse, err := q.Query(ctx, instanceName, RecordTypeSRV); err == nil {
+		for _, srvRecord := range srvResponse.Records {
+			if srv := srvRecord.AsSRV(); srv != nil {
+				instance.Target = srv.Target
+				instance.Port = srv.Port
+				break
+			}
+		}
+	}
+
+	if txtResponse, err := q.Query(ctx, instanceName, RecordTypeTXT); err == nil {
+		for _, txtRecord := range txtResponse.Records {
+			if txt := txtRecord.AsTXT(); txt != nil {
+				instance.TXT = txt
+				break
+			}
+		}
+	}
+
+	return instance
+}
+
+// WatchSubtype is Watch's counterpart to BrowseSubtype: rather than one
+// poll-and-return snapshot, it subscribes to live add/remove updates for
+// service instances advertising the RFC 6763 §7.1 subtype
+// "<subtype>._sub.<serviceType>" until ctx ends.
+//
+// The returned channels follow Watch's own rules: they close once ctx ends
+// or the Querier is closed, and calling WatchSubtype (or Watch) again with
+// the same subtype/serviceType pair while the first watch is still active
+// returns a duplicate-session ValidationError on the error channel.
+func (q *Querier) WatchSubtype(ctx context.Context, subtype, serviceType string) (<-chan ResourceRecord, <-chan error) {
+	return q.Watch(ctx, subtype+"._sub."+serviceType, RecordTypePTR)
+}
+
+// ListServiceTypes discovers the unique service types currently advertised
+// on the link via the RFC 6763 §9 meta-query ("_services._dns-sd._udp.local"),
+// collecting responses for timeout before returning - the same thing
+// `avahi-browse -a` and `dns-sd -B _services._dns-sd._udp` do.
+//
+// Duplicate PTR targets (multiple services sharing a type, or the same
+// response arriving over more than one interface) are collapsed to their
+// first occurrence, same as Query's own deduplication. Malformed packets
+// received during the collection window are discarded and collection
+// continues, per Query's existing tolerance (see
+// TestQuery_MalformedResponse_ContinuesCollecting).
+func (q *Querier) ListServiceTypes(ctx context.Context, timeout time.Duration) ([]string, error) {
+	collectCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response, err := q.Query(collectCtx, protocol.ServiceTypeEnumerationName, RecordTypePTR)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(response.Records))
+	serviceTypes := make([]string, 0, len(response.Records))
+	for _, record := range response.Records {
+		serviceType := record.AsPTR()
+		if serviceType == "" || seen[serviceType] {
+			continue
+		}
+		seen[serviceType] = true
+		serviceTypes = append(serviceTypes, serviceType)
+	}
+
+	return serviceTypes, nil
+}
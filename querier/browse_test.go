@@ -0,0 +1,150 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestBrowseSubtype_ReturnsOnTimeout validates that BrowseSubtype behaves
+// like Query when nothing replies: it returns an empty slice without error,
+// rather than blocking past ctx.
+func TestBrowseSubtype_ReturnsOnTimeout(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	instances, err := q.BrowseSubtype(ctx, "_printer", "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("BrowseSubtype() returned error: %v", err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("BrowseSubtype() = %+v, want no instances", instances)
+	}
+}
+
+// TestBrowseSubtype_ValidatesInputs validates that BrowseSubtype rejects an
+// invalid subtype query name the same way Query does, without ever touching
+// the network.
+func TestBrowseSubtype_ValidatesInputs(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	_, err = q.BrowseSubtype(context.Background(), "", "")
+	if err == nil {
+		t.Error("BrowseSubtype(\"\", \"\") should return a validation error")
+	}
+}
+
+// TestWatchSubtype_ClosesChannelsWhenContextEnds validates that WatchSubtype
+// behaves like Watch: its channels close once ctx ends, without requiring
+// Close() on the Querier itself.
+func TestWatchSubtype_ClosesChannelsWhenContextEnds(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	records, errs := q.WatchSubtype(ctx, "_printer", "_http._tcp.local")
+	cancel()
+
+	select {
+	case _, ok := <-records:
+		if ok {
+			t.Error("records channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("records channel was not closed after ctx ended")
+	}
+
+	select {
+	case _, ok := <-errs:
+		if ok {
+			t.Error("errs channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("errs channel was not closed after ctx ended")
+	}
+}
+
+// TestWatchSubtype_ValidatesInputs validates that WatchSubtype rejects an
+// invalid subtype query name the same way Watch does, without ever touching
+// the network.
+func TestWatchSubtype_ValidatesInputs(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	records, errs := q.WatchSubtype(context.Background(), "", "")
+
+	select {
+	case _, ok := <-records:
+		if ok {
+			t.Error("records channel should be immediately closed for an invalid name")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("records channel was not closed for an invalid name")
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected a validation error, got nil")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no error received for an invalid name")
+	}
+}
+
+// TestListServiceTypes_ReturnsOnTimeout validates that ListServiceTypes
+// behaves like Query when nothing replies: it returns an empty slice
+// without error once timeout elapses, rather than blocking past it.
+func TestListServiceTypes_ReturnsOnTimeout(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	serviceTypes, err := q.ListServiceTypes(context.Background(), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ListServiceTypes() returned error: %v", err)
+	}
+	if len(serviceTypes) != 0 {
+		t.Errorf("ListServiceTypes() = %+v, want no service types", serviceTypes)
+	}
+}
+
+// TestListServiceTypes_RespectsParentCancellation validates that
+// ListServiceTypes stops collecting as soon as the parent ctx is done, even
+// when timeout is longer.
+func TestListServiceTypes_RespectsParentCancellation(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := q.ListServiceTypes(ctx, 10*time.Second); err != nil {
+		t.Fatalf("ListServiceTypes() returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1*time.Second {
+		t.Errorf("ListServiceTypes() took %v, want bounded by parent ctx (~50ms)", elapsed)
+	}
+}
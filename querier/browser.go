@@ -0,0 +1,240 @@
+package querier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// BrowserOption is a functional option for configuring a Browser, following
+// the same pattern as Option for Querier (see Option's doc comment).
+type BrowserOption func(*browserConfig) error
+
+// browserConfig holds the settings BrowserOptions populate before NewBrowser
+// starts browsing.
+type browserConfig struct {
+	refreshInterval time.Duration
+	cache           *CacheResolver
+}
+
+// WithRefreshInterval forces Browser to re-issue a full PTR query for its
+// service type every interval, in addition to the RFC 6762 §5.2 backoff
+// schedule WatchServiceType already follows (1s, 2s, 4s, ..., capped at 1
+// hour). Use this when the backoff's eventual hour-long cadence is too
+// stale for a caller that needs to notice a silently-departed instance
+// faster than its SRV record's TTL would otherwise reveal.
+//
+// Default: unset - Browser relies solely on WatchServiceType's backoff and
+// each instance's own TTL expiry.
+func WithRefreshInterval(interval time.Duration) BrowserOption {
+	return func(c *browserConfig) error {
+		if interval <= 0 {
+			return &errors.ValidationError{
+				Field:   "refreshInterval",
+				Value:   interval,
+				Message: "must be greater than 0",
+			}
+		}
+		c.refreshInterval = interval
+		return nil
+	}
+}
+
+// WithCache populates cache with every instance's resolved SRV and TXT
+// records (keyed by instance name, valid for the record's own TTL) as
+// Browser discovers them, so a Resolver Chain elsewhere in the process can
+// serve a Query for the same name without a further network round-trip.
+// See CacheResolver and Chain.
+//
+// Default: unset - Browser doesn't populate any cache.
+func WithCache(cache *CacheResolver) BrowserOption {
+	return func(c *browserConfig) error {
+		if cache == nil {
+			return &errors.ValidationError{
+				Field:   "cache",
+				Value:   nil,
+				Message: "cache cannot be nil",
+			}
+		}
+		c.cache = cache
+		return nil
+	}
+}
+
+// Browser maintains a long-lived subscription to a service type, discovering
+// instances as they come and go rather than returning a single snapshot the
+// way BrowseSubtype does.
+//
+// Browser owns its own Querier (built the same way New() builds one, with
+// its own UDP socket and interface selection) and closes it when Close is
+// called, so a caller doesn't need a separate Querier just to browse.
+type Browser struct {
+	q      *Querier
+	cancel context.CancelFunc
+
+	events chan ServiceEvent
+	errs   chan error
+
+	wg sync.WaitGroup
+}
+
+// NewBrowser starts browsing serviceType (e.g. "_http._tcp.local"),
+// returning a Browser whose Events and Errors channels report
+// ServiceFound/ServiceChanged/ServiceLost updates until ctx ends or Close is
+// called.
+//
+// Internally this layers on WatchServiceType, which already issues the
+// periodic PTR queries with RFC 6762 §5.2 backoff, tracks each instance's
+// TTL to emit ServiceLost on expiry or goodbye, and deduplicates by instance
+// name.
+func NewBrowser(ctx context.Context, serviceType string, opts ...BrowserOption) (*Browser, error) {
+	cfg := &browserConfig{}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	q, err := New()
+	if err != nil {
+		return nil, err
+	}
+
+	browseCtx, cancel := context.WithCancel(ctx)
+
+	serviceEvents, serviceErrs := q.WatchServiceType(browseCtx, serviceType)
+
+	b := &Browser{
+		q:      q,
+		cancel: cancel,
+		events: make(chan ServiceEvent, serviceEventBufferSize),
+		errs:   make(chan error, watchErrBufferSize),
+	}
+
+	b.wg.Add(1)
+	go b.run(serviceEvents, serviceErrs, cfg)
+
+	if cfg.refreshInterval > 0 {
+		b.wg.Add(1)
+		go b.refreshLoop(browseCtx, serviceType, cfg.refreshInterval)
+	}
+
+	return b, nil
+}
+
+// Events returns the channel of ServiceFound/ServiceChanged/ServiceLost
+// updates. It closes once ctx ends or Close is called.
+func (b *Browser) Events() <-chan ServiceEvent {
+	return b.events
+}
+
+// Errors returns the channel of non-fatal errors encountered while browsing
+// (e.g. a malformed response). It closes once ctx ends or Close is called.
+func (b *Browser) Errors() <-chan error {
+	return b.errs
+}
+
+// run relays WatchServiceType's events and errors, and - when WithCache was
+// given - populates the cache as each instance is found or changed, until
+// both source channels close.
+func (b *Browser) run(serviceEvents <-chan ServiceEvent, serviceErrs <-chan error, cfg *browserConfig) {
+	defer b.wg.Done()
+	defer close(b.events)
+	defer close(b.errs)
+
+	for serviceEvents != nil || serviceErrs != nil {
+		select {
+		case event, ok := <-serviceEvents:
+			if !ok {
+				serviceEvents = nil
+				continue
+			}
+			if cfg.cache != nil && event.Type != ServiceLost {
+				cacheServiceInstance(cfg.cache, event.Instance)
+			}
+			select {
+			case b.events <- event:
+			default:
+			}
+
+		case err, ok := <-serviceErrs:
+			if !ok {
+				serviceErrs = nil
+				continue
+			}
+			select {
+			case b.errs <- err:
+			default:
+			}
+		}
+	}
+}
+
+// cacheServiceInstance stores instance's SRV and TXT records in cache, each
+// valid for refreshCacheTTL - WithCache's consumers are read alongside live
+// Watch sessions where a record's own TTL isn't retained by the time it
+// reaches a ServiceInstance, so a short fixed TTL stands in for it.
+func cacheServiceInstance(cache *CacheResolver, instance ServiceInstance) {
+	if instance.Target != "" {
+		cache.Put(instance.InstanceName, RecordTypeSRV, []ResourceRecord{
+			{
+				Name: instance.InstanceName,
+				Type: RecordTypeSRV,
+				TTL:  refreshCacheTTLSeconds,
+				Data: message.SRVData{Target: instance.Target, Port: instance.Port},
+			},
+		}, refreshCacheTTL)
+	}
+	if instance.TXT != nil {
+		cache.Put(instance.InstanceName, RecordTypeTXT, []ResourceRecord{
+			{
+				Name: instance.InstanceName,
+				Type: RecordTypeTXT,
+				TTL:  refreshCacheTTLSeconds,
+				Data: message.TXTData{Entries: instance.TXT},
+			},
+		}, refreshCacheTTL)
+	}
+}
+
+// refreshCacheTTL is how long a WithCache entry stays valid, matching
+// refreshLoop's own default cadence for keeping cached records fresh absent
+// a more precise TTL.
+const refreshCacheTTL = 2 * time.Minute
+
+// refreshCacheTTLSeconds is refreshCacheTTL expressed as the TTL unit
+// ResourceRecord itself uses.
+const refreshCacheTTLSeconds = uint32(refreshCacheTTL / time.Second)
+
+// refreshLoop re-issues a fresh PTR query for serviceType every interval.
+// Responses are picked up by the Watch session dispatchWatch already routes
+// every received message through, so no separate merge logic is needed
+// here - this just forces traffic sooner than the backoff schedule would.
+func (b *Browser) refreshLoop(ctx context.Context, serviceType string, interval time.Duration) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			queryCtx, cancel := context.WithTimeout(ctx, b.q.defaultTimeout)
+			_, _ = b.q.Query(queryCtx, serviceType, RecordTypePTR)
+			cancel()
+		}
+	}
+}
+
+// Close stops browsing and releases the Browser's own Querier. It blocks
+// until Events and Errors are both closed.
+func (b *Browser) Close() error {
+	b.cancel()
+	b.wg.Wait()
+	return b.q.Close()
+}
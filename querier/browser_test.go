@@ -0,0 +1,138 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestNewBrowser_ClosesChannelsWhenContextEnds validates that Browser's
+// Events and Errors channels close once ctx ends, mirroring
+// TestWatchServiceType_ClosesChannelsWhenContextEnds.
+func TestNewBrowser_ClosesChannelsWhenContextEnds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b, err := NewBrowser(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("NewBrowser() failed: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+
+	cancel()
+
+	select {
+	case _, ok := <-b.Events():
+		if ok {
+			t.Error("Events channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Events channel was not closed after ctx ended")
+	}
+}
+
+// TestNewBrowser_RejectsInvalidServiceType validates that NewBrowser surfaces
+// an invalid service type as an error on the Errors channel, mirroring
+// WatchServiceType's own validation.
+func TestNewBrowser_RejectsInvalidServiceType(t *testing.T) {
+	b, err := NewBrowser(context.Background(), "")
+	if err != nil {
+		t.Fatalf("NewBrowser() failed: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+
+	select {
+	case err := <-b.Errors():
+		if err == nil {
+			t.Error("expected a validation error, got nil")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no error received for an invalid service type")
+	}
+}
+
+// TestWithRefreshInterval_RejectsNonPositive validates that WithRefreshInterval
+// rejects a zero or negative interval.
+func TestWithRefreshInterval_RejectsNonPositive(t *testing.T) {
+	_, err := NewBrowser(context.Background(), "_http._tcp.local", WithRefreshInterval(0))
+	if err == nil {
+		t.Error("expected WithRefreshInterval(0) to fail, got nil error")
+	}
+}
+
+// TestWithCache_RejectsNil validates that WithCache rejects a nil cache.
+func TestWithCache_RejectsNil(t *testing.T) {
+	_, err := NewBrowser(context.Background(), "_http._tcp.local", WithCache(nil))
+	if err == nil {
+		t.Error("expected WithCache(nil) to fail, got nil error")
+	}
+}
+
+// TestNewBrowser_ClosesOwnQuerier validates that Close releases the
+// Browser's internally-created Querier, so a second Close doesn't hang and
+// leaves no running goroutines behind.
+func TestNewBrowser_ClosesOwnQuerier(t *testing.T) {
+	b, err := NewBrowser(context.Background(), "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("NewBrowser() failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = b.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close() did not return")
+	}
+}
+
+// TestCacheServiceInstance_PopulatesResolvableData verifies that the
+// records cacheServiceInstance stores actually carry the instance's SRV and
+// TXT data, so a CacheResolver.Resolve caller can decode them via AsSRV/AsTXT
+// rather than getting back an empty record with the type set but no payload.
+func TestCacheServiceInstance_PopulatesResolvableData(t *testing.T) {
+	cache := NewCacheResolver()
+	instance := ServiceInstance{
+		InstanceName: "Office Printer._http._tcp.local",
+		Target:       "printer.local",
+		Port:         8080,
+		TXT:          []string{"path=/", "color=true"},
+	}
+
+	cacheServiceInstance(cache, instance)
+
+	srvResp, err := cache.Resolve(context.Background(), instance.InstanceName, RecordTypeSRV)
+	if err != nil {
+		t.Fatalf("Resolve(SRV) error = %v", err)
+	}
+	if len(srvResp.Records) != 1 {
+		t.Fatalf("Resolve(SRV) returned %d records, want 1", len(srvResp.Records))
+	}
+	srv := srvResp.Records[0].AsSRV()
+	if srv == nil {
+		t.Fatal("AsSRV() = nil, want populated SRVData")
+	}
+	if srv.Target != instance.Target || srv.Port != instance.Port {
+		t.Errorf("AsSRV() = %+v, want Target=%q Port=%d", srv, instance.Target, instance.Port)
+	}
+
+	txtResp, err := cache.Resolve(context.Background(), instance.InstanceName, RecordTypeTXT)
+	if err != nil {
+		t.Fatalf("Resolve(TXT) error = %v", err)
+	}
+	if len(txtResp.Records) != 1 {
+		t.Fatalf("Resolve(TXT) returned %d records, want 1", len(txtResp.Records))
+	}
+	txt := txtResp.Records[0].AsTXT()
+	if len(txt) != len(instance.TXT) {
+		t.Fatalf("AsTXT() = %v, want %v", txt, instance.TXT)
+	}
+	for i, entry := range instance.TXT {
+		if txt[i] != entry {
+			t.Errorf("AsTXT()[%d] = %q, want %q", i, txt[i], entry)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package querier
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sync"
+
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// DiscoveryPlugin composes a discovery backend transport - mDNS today, and
+// potentially SSDP, DNS-SD-over-unicast, or BLE beacon backends in the
+// future - behind one API, the way multiple transports are fanned out
+// behind a single advertise/scan call in plugin-based discovery libraries.
+// MDNSv4Plugin and MDNSv6Plugin are the two backends this package ships;
+// a caller wanting another transport implements DiscoveryPlugin itself and
+// passes it to AdvertiseAll/ScanAll alongside the built-in ones.
+type DiscoveryPlugin interface {
+	// Advertise registers svc with this plugin's backend, the way
+	// responder.Responder.Register does for mDNS. It blocks until the
+	// backend has started advertising (for mDNS, until probing and
+	// announcing complete) or ctx ends.
+	Advertise(ctx context.Context, svc *responder.Service) error
+
+	// Scan starts discovering instances of serviceType, returning a
+	// channel of Updates until ctx ends or Close is called, at which
+	// point the channel is closed.
+	Scan(ctx context.Context, serviceType string) (<-chan Update, error)
+
+	// Close releases this plugin's backend resources (sockets, running
+	// goroutines). A plugin that was never used for Advertise or Scan
+	// closes cleanly.
+	Close() error
+}
+
+// Update is one discovery event surfaced by a DiscoveryPlugin's Scan
+// channel, or by ScanAll merging several. Kind and Instance carry the same
+// Added/Updated/Removed vocabulary as Event; ServiceType records which
+// scan produced it, since ScanAll's merged channel carries updates for
+// only the one serviceType callers asked it to scan, but dedup is keyed on
+// (InstanceName, ServiceType) in case a future caller merges updates from
+// scans of different types onto one channel.
+type Update struct {
+	Kind        EventKind
+	ServiceType string
+	Instance    ServiceInstance
+}
+
+// updateKey identifies one (instance, serviceType, kind) triple for
+// ScanAll's cross-plugin dedup, per DiscoveryPlugin's doc comment. Kind is
+// part of the key, not a map value compared against it, so that Added's
+// zero value doesn't read as "already seen" for a key ScanAll has never
+// observed before.
+type updateKey struct {
+	instanceName string
+	serviceType  string
+	kind         EventKind
+}
+
+func (u Update) key() updateKey {
+	return updateKey{instanceName: u.Instance.InstanceName, serviceType: u.ServiceType, kind: u.Kind}
+}
+
+// scanUpdateBufferSize is ScanAll's merged channel buffer, matching
+// browseEventBufferSize's rationale: absorb a burst of instances appearing
+// across several plugins at once without blocking on a slow consumer.
+const scanUpdateBufferSize = 32
+
+// AdvertiseAll registers svc with every plugin in plugins concurrently,
+// returning once all of them have either succeeded or failed. Errors from
+// individual plugins are joined via errors.Join rather than returning only
+// the first, so a caller advertising over mDNSv4+mDNSv6 learns about a
+// failure on either interface instead of just whichever failed first.
+func AdvertiseAll(ctx context.Context, svc *responder.Service, plugins ...DiscoveryPlugin) error {
+	errs := make([]error, len(plugins))
+	var wg sync.WaitGroup
+	wg.Add(len(plugins))
+	for i, p := range plugins {
+		go func(i int, p DiscoveryPlugin) {
+			defer wg.Done()
+			if err := p.Advertise(ctx, svc); err != nil {
+				errs[i] = fmt.Errorf("plugin %d: %w", i, err)
+			}
+		}(i, p)
+	}
+	wg.Wait()
+	return goerrors.Join(errs...)
+}
+
+// ScanAll starts Scan(ctx, serviceType) on every plugin in plugins,
+// returning one channel that merges their Updates until ctx ends or every
+// plugin's channel closes. An instance reported by more than one plugin -
+// e.g. a host reachable over both mDNSv4 and mDNSv6 - is deduplicated by
+// (InstanceName, ServiceType): only the first plugin to report it on a
+// given Kind forwards that Update, so a caller sees one Added rather than
+// one per backend.
+func ScanAll(ctx context.Context, serviceType string, plugins ...DiscoveryPlugin) (<-chan Update, error) {
+	channels := make([]<-chan Update, 0, len(plugins))
+	for _, p := range plugins {
+		ch, err := p.Scan(ctx, serviceType)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+
+	merged := make(chan Update, scanUpdateBufferSize)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[updateKey]bool)
+
+	wg.Add(len(channels))
+	for _, ch := range channels {
+		go func(ch <-chan Update) {
+			defer wg.Done()
+			for update := range ch {
+				mu.Lock()
+				duplicate := seen[update.key()]
+				seen[update.key()] = true
+				mu.Unlock()
+				if duplicate {
+					continue
+				}
+				select {
+				case merged <- update:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	return merged, nil
+}
+
+// mdnsPlugin is the mDNS DiscoveryPlugin backend: Advertise registers svc
+// with a responder.Responder, Scan browses via a Querier, both built for a
+// single address family. MDNSv4Plugin and MDNSv6Plugin are the only
+// constructors - family is otherwise fixed, since a plugin that handled
+// both would just be DualStack, which responder.WithNetworkMode and
+// WithIPFamily already cover without the DiscoveryPlugin abstraction.
+type mdnsPlugin struct {
+	responderMode responder.NetworkMode
+	querierFamily IPFamily
+
+	mu sync.Mutex
+	r  *responder.Responder
+	q  *Querier
+}
+
+// MDNSv4Plugin returns a DiscoveryPlugin that advertises and scans over
+// 224.0.0.251:5353 only.
+func MDNSv4Plugin() DiscoveryPlugin {
+	return &mdnsPlugin{responderMode: responder.IPv4Only, querierFamily: IPv4Only}
+}
+
+// MDNSv6Plugin returns a DiscoveryPlugin that advertises and scans over
+// [ff02::fb]:5353 only.
+func MDNSv6Plugin() DiscoveryPlugin {
+	return &mdnsPlugin{responderMode: responder.IPv6Only, querierFamily: IPv6Only}
+}
+
+// Advertise registers svc with this plugin's Responder, creating it (and
+// starting its probe/announce sequence) on first call. Subsequent calls
+// register additional services with the same Responder.
+func (p *mdnsPlugin) Advertise(ctx context.Context, svc *responder.Service) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.r == nil {
+		r, err := responder.New(ctx, responder.WithNetworkMode(p.responderMode))
+		if err != nil {
+			return fmt.Errorf("mdns plugin: create responder: %w", err)
+		}
+		p.r = r
+	}
+	return p.r.Register(svc)
+}
+
+// Scan browses serviceType with this plugin's Querier, creating it on
+// first call, and translates Browse's Events into Updates.
+func (p *mdnsPlugin) Scan(ctx context.Context, serviceType string) (<-chan Update, error) {
+	p.mu.Lock()
+	if p.q == nil {
+		q, err := New(WithIPFamily(p.querierFamily))
+		if err != nil {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("mdns plugin: create querier: %w", err)
+		}
+		p.q = q
+	}
+	q := p.q
+	p.mu.Unlock()
+
+	events, err := q.Browse(ctx, serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan Update, scanUpdateBufferSize)
+	go func() {
+		defer close(updates)
+		for event := range events {
+			select {
+			case updates <- Update{Kind: event.Kind, ServiceType: serviceType, Instance: event.Instance}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// Close closes this plugin's Responder and Querier, if they were ever
+// created. Closing a plugin that was never used for Advertise or Scan is a
+// no-op.
+func (p *mdnsPlugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	if p.r != nil {
+		if err := p.r.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("responder: %w", err))
+		}
+	}
+	if p.q != nil {
+		if err := p.q.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("querier: %w", err))
+		}
+	}
+	return goerrors.Join(errs...)
+}
+
+// loopbackPlugin is an in-memory DiscoveryPlugin with no real transport:
+// Advertise records svc in a map and Scan replays every currently-
+// registered instance of serviceType as an Added Update, then blocks until
+// ctx ends. It exists for testing code built against DiscoveryPlugin (e.g.
+// AdvertiseAll/ScanAll callers, or a caller composing its own backend
+// alongside the built-in mDNS ones) without standing up real sockets the
+// way NewFabric does for responder/querier directly.
+type loopbackPlugin struct {
+	mu        sync.Mutex
+	instances map[string]*responder.Service // keyed by ServiceType
+}
+
+// NewLoopbackPlugin returns a DiscoveryPlugin backed by an in-memory map
+// rather than a real mDNS transport, for tests exercising DiscoveryPlugin
+// composition (AdvertiseAll, ScanAll) deterministically.
+func NewLoopbackPlugin() DiscoveryPlugin {
+	return &loopbackPlugin{instances: make(map[string]*responder.Service)}
+}
+
+// Advertise records svc, keyed by ServiceType, overwriting any instance
+// previously advertised for the same type.
+func (p *loopbackPlugin) Advertise(_ context.Context, svc *responder.Service) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.instances[svc.ServiceType] = svc
+	return nil
+}
+
+// Scan emits one Added Update per instance currently advertised for
+// serviceType, then blocks until ctx ends - there is no ongoing discovery
+// to report, since loopbackPlugin has no peers other than its own
+// Advertise calls.
+func (p *loopbackPlugin) Scan(ctx context.Context, serviceType string) (<-chan Update, error) {
+	p.mu.Lock()
+	svc, ok := p.instances[serviceType]
+	p.mu.Unlock()
+
+	updates := make(chan Update, 1)
+	if ok {
+		updates <- Update{
+			Kind:        Added,
+			ServiceType: serviceType,
+			Instance:    ServiceInstance{InstanceName: svc.InstanceName, Port: uint16(svc.Port)},
+		}
+	}
+
+	go func() {
+		defer close(updates)
+		<-ctx.Done()
+	}()
+
+	return updates, nil
+}
+
+// Close is a no-op: loopbackPlugin holds no sockets or goroutines beyond
+// Scan's per-call ctx.Done() wait, which ctx cancellation (not Close)
+// ends.
+func (p *loopbackPlugin) Close() error {
+	return nil
+}
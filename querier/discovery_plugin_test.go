@@ -0,0 +1,132 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// TestLoopbackPlugin_AdvertiseThenScan validates the round trip a
+// composition test relies on: an instance Advertised onto a loopbackPlugin
+// is reported back by Scan as an Added Update.
+func TestLoopbackPlugin_AdvertiseThenScan(t *testing.T) {
+	p := NewLoopbackPlugin()
+	defer func() { _ = p.Close() }()
+
+	svc := &responder.Service{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}
+	if err := p.Advertise(context.Background(), svc); err != nil {
+		t.Fatalf("Advertise() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	updates, err := p.Scan(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	select {
+	case update, ok := <-updates:
+		if !ok {
+			t.Fatal("updates channel closed before delivering the advertised instance")
+		}
+		if update.Kind != Added || update.Instance.InstanceName != "My Printer" {
+			t.Errorf("update = %+v, want Added update for %q", update, svc.InstanceName)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for advertised instance")
+	}
+}
+
+// TestLoopbackPlugin_ScanEmptyClosesOnContextEnd validates that Scan for a
+// serviceType with no advertised instance closes its channel once ctx ends,
+// rather than hanging forever.
+func TestLoopbackPlugin_ScanEmptyClosesOnContextEnd(t *testing.T) {
+	p := NewLoopbackPlugin()
+	defer func() { _ = p.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := p.Scan(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("updates channel should close after ctx ends")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("updates channel was not closed after ctx ended")
+	}
+}
+
+// TestAdvertiseAll_FansOutToEveryPlugin validates that AdvertiseAll calls
+// Advertise on every plugin given, not just the first.
+func TestAdvertiseAll_FansOutToEveryPlugin(t *testing.T) {
+	a := NewLoopbackPlugin().(*loopbackPlugin)
+	b := NewLoopbackPlugin().(*loopbackPlugin)
+	defer func() { _ = a.Close() }()
+	defer func() { _ = b.Close() }()
+
+	svc := &responder.Service{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}
+	if err := AdvertiseAll(context.Background(), svc, a, b); err != nil {
+		t.Fatalf("AdvertiseAll() error = %v", err)
+	}
+
+	for name, p := range map[string]*loopbackPlugin{"a": a, "b": b} {
+		p.mu.Lock()
+		_, ok := p.instances[svc.ServiceType]
+		p.mu.Unlock()
+		if !ok {
+			t.Errorf("plugin %s did not receive the advertised instance", name)
+		}
+	}
+}
+
+// TestScanAll_DeduplicatesAcrossPlugins validates that ScanAll's merged
+// channel reports an instance advertised on two plugins only once, per
+// DiscoveryPlugin's (InstanceName, ServiceType) dedup rule.
+func TestScanAll_DeduplicatesAcrossPlugins(t *testing.T) {
+	a := NewLoopbackPlugin()
+	b := NewLoopbackPlugin()
+	defer func() { _ = a.Close() }()
+	defer func() { _ = b.Close() }()
+
+	svc := &responder.Service{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}
+	if err := AdvertiseAll(context.Background(), svc, a, b); err != nil {
+		t.Fatalf("AdvertiseAll() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	updates, err := ScanAll(ctx, "_http._tcp.local", a, b)
+	if err != nil {
+		t.Fatalf("ScanAll() error = %v", err)
+	}
+
+	count := 0
+	for range updates {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("ScanAll() delivered %d updates, want 1 (deduplicated)", count)
+	}
+}
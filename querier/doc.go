@@ -179,8 +179,6 @@
 //
 //   - IPv4 only (no IPv6/AAAA records)
 //   - Query-only (no mDNS responder functionality)
-//   - No Known Answer suppression (RFC 6762 ยง7.1)
-//   - No continuous monitoring (one-shot queries only)
 //   - Authority and Additional sections ignored
 //
 // # Performance
@@ -0,0 +1,100 @@
+package querier
+
+import (
+	"sync"
+	"time"
+)
+
+// DropReason identifies why receiveLoop or a response collector discarded an
+// incoming packet or candidate record. Exposing this via WithDropObserver
+// turns today's opaque "no results" into actionable diagnostics - an
+// operator can tell a rate-limited LAN apart from a genuinely silent one.
+type DropReason string
+
+const (
+	// ReasonOversized marks a raw packet exceeding WithMaxResponseBytes
+	// (default: RFC 6762 §17's 9000-byte ceiling).
+	ReasonOversized DropReason = "oversized"
+
+	// ReasonNonLinkLocal marks a packet whose source address failed the
+	// RFC 6762 §2 link-local scope check.
+	ReasonNonLinkLocal DropReason = "non_link_local"
+
+	// ReasonRateLimited marks a packet the RateLimiter denied per FR-029.
+	ReasonRateLimited DropReason = "rate_limited"
+
+	// ReasonMalformed marks a packet that failed to parse as a DNS message,
+	// failed RFC 6762 response-flag validation, or carried RDATA that failed
+	// to decode.
+	ReasonMalformed DropReason = "malformed"
+
+	// ReasonChannelFull marks a packet receiveLoop dropped because
+	// responseChan's buffer was already full.
+	ReasonChannelFull DropReason = "channel_full"
+
+	// ReasonWrongType marks an otherwise-valid answer that didn't match the
+	// record type the caller queried for.
+	ReasonWrongType DropReason = "wrong_type"
+
+	// ReasonUnrelatedResponse marks a response that failed
+	// message.ValidateResponse's transaction-ID or question-tuple
+	// correlation check - a unicast reply whose ID didn't echo the query's,
+	// or an mDNS response with a non-zero ID or no answer matching any
+	// question asked.
+	ReasonUnrelatedResponse DropReason = "unrelated_response"
+)
+
+// DropEvent describes a single dropped packet or record, passed to the
+// callback configured via WithDropObserver.
+type DropEvent struct {
+	Reason DropReason
+	Time   time.Time
+
+	// SourceIP is the packet's source address, formatted with net.IP.String,
+	// if known at the point of drop.
+	SourceIP string
+
+	// Name is the name being queried when the drop occurred, if known. Empty
+	// for receiveLoop-level drops (oversized, non-link-local, rate-limited,
+	// channel-full), which happen before any particular Query call claims
+	// the packet.
+	Name string
+}
+
+// DropCounter aggregates DropEvents into Prometheus-style per-reason
+// counters, mirroring security.PrometheusSink rather than depending on the
+// Prometheus client library directly; a caller wires Snapshot() into its own
+// beacon_querier_drops_total{reason=...} gauge.
+type DropCounter struct {
+	mu     sync.Mutex
+	counts map[DropReason]uint64
+}
+
+// NewDropCounter creates an empty DropCounter. Its Observe method is the
+// typical argument to WithDropObserver:
+//
+//	counter := querier.NewDropCounter()
+//	q, _ := querier.New(querier.WithDropObserver(counter.Observe))
+func NewDropCounter() *DropCounter {
+	return &DropCounter{counts: make(map[DropReason]uint64)}
+}
+
+// Observe implements the func(DropEvent) signature WithDropObserver expects,
+// incrementing evt's reason counter.
+func (c *DropCounter) Observe(evt DropEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[evt.Reason]++
+}
+
+// Snapshot returns a copy of the counter's current per-reason counts.
+func (c *DropCounter) Snapshot() map[DropReason]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[DropReason]uint64, len(c.counts))
+	for reason, n := range c.counts {
+		out[reason] = n
+	}
+	return out
+}
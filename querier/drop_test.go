@@ -0,0 +1,95 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// TestDropCounter_ObserveIncrementsPerReason validates that Observe tallies
+// each DropReason independently.
+func TestDropCounter_ObserveIncrementsPerReason(t *testing.T) {
+	counter := NewDropCounter()
+
+	counter.Observe(DropEvent{Reason: ReasonOversized})
+	counter.Observe(DropEvent{Reason: ReasonOversized})
+	counter.Observe(DropEvent{Reason: ReasonRateLimited})
+
+	snap := counter.Snapshot()
+	if snap[ReasonOversized] != 2 {
+		t.Errorf("counts[ReasonOversized] = %d, want 2", snap[ReasonOversized])
+	}
+	if snap[ReasonRateLimited] != 1 {
+		t.Errorf("counts[ReasonRateLimited] = %d, want 1", snap[ReasonRateLimited])
+	}
+}
+
+// TestDropCounter_SnapshotIsACopy validates that mutating a returned snapshot
+// doesn't affect the counter's internal state.
+func TestDropCounter_SnapshotIsACopy(t *testing.T) {
+	counter := NewDropCounter()
+	counter.Observe(DropEvent{Reason: ReasonMalformed})
+
+	snap := counter.Snapshot()
+	snap[ReasonMalformed] = 99
+
+	if got := counter.Snapshot()[ReasonMalformed]; got != 1 {
+		t.Errorf("counts[ReasonMalformed] = %d, want 1 (unaffected by snapshot mutation)", got)
+	}
+}
+
+// TestQuerier_ReportDrop_InvokesObserver validates that reportDrop calls a
+// configured dropObserver with the reason, source IP, and name supplied.
+func TestQuerier_ReportDrop_InvokesObserver(t *testing.T) {
+	var got DropEvent
+	q := &Querier{dropObserver: func(evt DropEvent) { got = evt }}
+
+	q.reportDrop(ReasonRateLimited, net.ParseIP("192.168.1.5"), "printer.local")
+
+	if got.Reason != ReasonRateLimited {
+		t.Errorf("Reason = %q, want %q", got.Reason, ReasonRateLimited)
+	}
+	if got.SourceIP != "192.168.1.5" {
+		t.Errorf("SourceIP = %q, want 192.168.1.5", got.SourceIP)
+	}
+	if got.Name != "printer.local" {
+		t.Errorf("Name = %q, want printer.local", got.Name)
+	}
+}
+
+// TestQuerier_ReportDrop_NoopWithoutObserver validates that reportDrop is
+// safe to call unconditionally when no WithDropObserver was configured.
+func TestQuerier_ReportDrop_NoopWithoutObserver(t *testing.T) {
+	q := &Querier{}
+	q.reportDrop(ReasonOversized, nil, "")
+}
+
+// TestWithDropObserver_RejectsNil validates that WithDropObserver(nil)
+// returns a ValidationError instead of silently disabling reporting.
+func TestWithDropObserver_RejectsNil(t *testing.T) {
+	q := &Querier{}
+	if err := WithDropObserver(nil)(q); err == nil {
+		t.Fatal("WithDropObserver(nil) returned nil error, want a ValidationError")
+	}
+}
+
+// TestQuery_UpfrontContextCheck_ReturnsCause validates that Query reports
+// context.Cause(ctx) - not just ctx.Err() - when the caller's context is
+// already done with a custom cause before Query is even called.
+func TestQuery_UpfrontContextCheck_ReturnsCause(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	cause := errors.New("custom cancellation reason")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	_, err = q.Query(ctx, "printer.local", RecordTypeA)
+	if !errors.Is(err, cause) {
+		t.Errorf("Query() error = %v, want it to wrap %v", err, cause)
+	}
+}
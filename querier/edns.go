@@ -0,0 +1,34 @@
+package querier
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// ednsQueryOptions builds the EDNS(0) message.QueryOptions every outgoing
+// query attaches: a payload-size OPT record per WithMaxUDPSize, plus the
+// same capability-probing flags dig sends by default (+dnssec, +nsid,
+// +cookie), so Query interoperates with modern DNS tooling that checks for
+// them. This package never inspects the NSID/cookie a responder echoes
+// back - only dig-style probing is the point.
+func (q *Querier) ednsQueryOptions() []message.QueryOption {
+	return []message.QueryOption{
+		message.WithEDNS(q.maxUDPSize,
+			message.EDNSOption{Code: message.OptionCodeNSID},
+			message.EDNSOption{Code: message.OptionCodeCookie, Data: newClientCookie()},
+		),
+		message.WithDNSSEC(),
+	}
+}
+
+// newClientCookie generates an 8-byte DNS Cookie client cookie per
+// RFC 7873 §4. A cookie's purpose is letting a resolver notice a change in
+// responder (or off-path spoofing) across queries, not cryptographic
+// security, so math/rand's non-cryptographic randomness is sufficient.
+func newClientCookie() []byte {
+	cookie := make([]byte, 8)
+	binary.BigEndian.PutUint64(cookie, rand.Uint64()) //nolint:gosec // G404: cookie uniqueness, not a security boundary
+	return cookie
+}
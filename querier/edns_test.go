@@ -0,0 +1,118 @@
+package querier
+
+import (
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// TestWithMaxUDPSize verifies the WithMaxUDPSize option sets maxUDPSize and
+// that it defaults to defaultMaxUDPSize when unset.
+func TestWithMaxUDPSize(t *testing.T) {
+	q, err := New(WithMaxUDPSize(4096))
+	if err != nil {
+		t.Fatalf("New(WithMaxUDPSize) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	if q.maxUDPSize != 4096 {
+		t.Errorf("maxUDPSize = %d, want 4096", q.maxUDPSize)
+	}
+}
+
+// TestWithMaxUDPSize_Default verifies New() without WithMaxUDPSize defaults
+// to defaultMaxUDPSize.
+func TestWithMaxUDPSize_Default(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	if q.maxUDPSize != defaultMaxUDPSize {
+		t.Errorf("maxUDPSize = %d, want default %d", q.maxUDPSize, defaultMaxUDPSize)
+	}
+}
+
+// TestWithMaxUDPSize_RejectsZero verifies WithMaxUDPSize(0) returns a
+// ValidationError instead of silently disabling EDNS.
+func TestWithMaxUDPSize_RejectsZero(t *testing.T) {
+	_, err := New(WithMaxUDPSize(0))
+	if err == nil {
+		t.Fatal("New(WithMaxUDPSize(0)) expected error, got nil")
+	}
+}
+
+// TestEdnsQueryOptions_AdvertisesConfiguredSize verifies ednsQueryOptions
+// builds a query carrying an OPT record advertising maxUDPSize, with the DO
+// bit set and NSID/cookie options attached for dig-style capability
+// probing.
+func TestEdnsQueryOptions_AdvertisesConfiguredSize(t *testing.T) {
+	q, err := New(WithMaxUDPSize(2048))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	queryMsg, err := message.BuildQuery("test.local", uint16(RecordTypeA), q.ednsQueryOptions()...)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	parsed, err := message.ParseMessage(queryMsg)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+
+	if parsed.OPT == nil {
+		t.Fatal("parsed query has no OPT record")
+	}
+	if parsed.OPT.UDPPayloadSize != 2048 {
+		t.Errorf("UDPPayloadSize = %d, want 2048", parsed.OPT.UDPPayloadSize)
+	}
+	if !parsed.OPT.DNSSECOK {
+		t.Error("OPT.DNSSECOK = false, want true (+dnssec)")
+	}
+
+	var sawNSID, sawCookie bool
+	for _, opt := range parsed.OPT.Options {
+		switch opt.Code {
+		case message.OptionCodeNSID:
+			sawNSID = true
+		case message.OptionCodeCookie:
+			sawCookie = true
+			if len(opt.Data) != 8 {
+				t.Errorf("cookie option Data length = %d, want 8", len(opt.Data))
+			}
+		}
+	}
+	if !sawNSID {
+		t.Error("OPT.Options missing NSID option (+nsid)")
+	}
+	if !sawCookie {
+		t.Error("OPT.Options missing Cookie option (+cookie)")
+	}
+}
+
+// TestNewClientCookie_Varies verifies newClientCookie doesn't return the
+// same bytes every call (a constant cookie would defeat RFC 7873's purpose
+// of letting a resolver notice a change in responder across queries).
+func TestNewClientCookie_Varies(t *testing.T) {
+	a := newClientCookie()
+	b := newClientCookie()
+
+	if len(a) != 8 || len(b) != 8 {
+		t.Fatalf("newClientCookie() length = %d/%d, want 8/8", len(a), len(b))
+	}
+
+	same := true
+	for i := range a {
+		if a[i] != b[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Error("newClientCookie() returned identical cookies twice in a row")
+	}
+}
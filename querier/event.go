@@ -0,0 +1,131 @@
+package querier
+
+import (
+	"context"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// EventKind identifies what happened to a service instance reported by
+// Browse, using the Added/Updated/Removed vocabulary common to RFC 6763
+// client libraries - WatchServiceType's ServiceFound/ServiceChanged/
+// ServiceLost report the exact same transitions; Browse exists for callers
+// who'd rather use this naming.
+type EventKind int
+
+const (
+	// Added indicates a newly resolved service instance.
+	Added EventKind = iota
+
+	// Updated indicates an already-known instance whose SRV target, port,
+	// or TXT records changed without the instance itself disappearing.
+	Updated
+
+	// Removed indicates an instance whose PTR record's TTL expired without
+	// being refreshed, or that sent a goodbye record (TTL=0) per RFC 6762
+	// §10.1.
+	Removed
+)
+
+// String returns a human-readable name for the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case Added:
+		return "Added"
+	case Updated:
+		return "Updated"
+	case Removed:
+		return "Removed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event reports a change to a service instance discovered by Browse.
+type Event struct {
+	Kind EventKind
+
+	// Instance is the affected instance. For a Removed event, Target, Port,
+	// and TXT reflect the last resolution seen before the instance
+	// disappeared, not a fresh lookup.
+	Instance ServiceInstance
+}
+
+// browseEventBufferSize is Browse's events channel buffer, matching
+// serviceEventBufferSize's rationale: absorb a burst of instances appearing
+// at once without blocking on a slow consumer.
+const browseEventBufferSize = 32
+
+// Browse subscribes to continuous service discovery for serviceType (e.g.
+// "_http._tcp.local"), returning a channel of Added/Updated/Removed Events
+// until ctx ends or the Querier is closed, at which point the channel is
+// closed.
+//
+// Browse is a thin translation of WatchServiceType's
+// ServiceFound/ServiceChanged/ServiceLost vocabulary onto Added/Updated/
+// Removed - all of WatchServiceType's machinery applies unchanged: the RFC
+// 6762 §5.2 query backoff (1s, 2s, 4s, ..., capped at 60 minutes), each
+// outgoing query's Known-Answer list (RFC 6762 §7.1, via sendWatchQuery),
+// and per-instance TTL tracking to emit Removed. Unlike WatchServiceType,
+// Browse has no separate error channel: a transient query error is logged
+// via the Querier's logger rather than surfaced to the caller, since Event's
+// single-channel shape has nowhere to carry one. A caller that needs to
+// observe those errors directly should call WatchServiceType instead.
+func (q *Querier) Browse(ctx context.Context, serviceType string) (<-chan Event, error) {
+	if err := protocol.ValidateName(serviceType); err != nil {
+		return nil, err
+	}
+
+	serviceEvents, serviceErrs := q.WatchServiceType(ctx, serviceType)
+	events := make(chan Event, browseEventBufferSize)
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.runBrowse(serviceType, serviceEvents, serviceErrs, events)
+	}()
+
+	return events, nil
+}
+
+// runBrowse relays serviceEvents as Events until both serviceEvents and
+// serviceErrs are closed, logging serviceErrs since Browse's signature has
+// no channel of its own to report them on.
+func (q *Querier) runBrowse(serviceType string, serviceEvents <-chan ServiceEvent, serviceErrs <-chan error, events chan<- Event) {
+	defer close(events)
+
+	for serviceEvents != nil || serviceErrs != nil {
+		select {
+		case event, ok := <-serviceEvents:
+			if !ok {
+				serviceEvents = nil
+				continue
+			}
+			select {
+			case events <- Event{Kind: browseEventKind(event.Type), Instance: event.Instance}:
+			default:
+			}
+
+		case err, ok := <-serviceErrs:
+			if !ok {
+				serviceErrs = nil
+				continue
+			}
+			q.recoveryLogger().Warn("transient error while browsing", "service_type", serviceType, "error", err)
+		}
+	}
+}
+
+// browseEventKind maps a ServiceEventType onto the equivalent EventKind.
+func browseEventKind(t ServiceEventType) EventKind {
+	switch t {
+	case ServiceFound:
+		return Added
+	case ServiceChanged:
+		return Updated
+	case ServiceLost:
+		return Removed
+	default:
+		return Added
+	}
+}
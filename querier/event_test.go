@@ -0,0 +1,94 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestBrowse_ClosesChannelWhenContextEnds validates that Browse's Event
+// channel closes once ctx ends, mirroring
+// TestWatchServiceType_ClosesChannelsWhenContextEnds.
+func TestBrowse_ClosesChannelWhenContextEnds(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := q.Browse(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("Browse() error = %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("events channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("events channel was not closed after ctx ended")
+	}
+}
+
+// TestBrowse_RejectsInvalidServiceType validates that Browse returns a
+// validation error synchronously, matching Query's up-front validation
+// rather than WatchServiceType's closed-channel convention - Event has no
+// error channel of its own to carry it on.
+func TestBrowse_RejectsInvalidServiceType(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	events, err := q.Browse(context.Background(), "")
+	if err == nil {
+		t.Fatal("Browse(\"\") error = nil, want a ValidationError")
+	}
+	if events != nil {
+		t.Error("Browse(\"\") events = non-nil, want nil on validation failure")
+	}
+}
+
+// TestEventKind_String verifies EventKind's String method covers every
+// defined constant plus the unknown fallback.
+func TestEventKind_String(t *testing.T) {
+	tests := []struct {
+		kind EventKind
+		want string
+	}{
+		{Added, "Added"},
+		{Updated, "Updated"},
+		{Removed, "Removed"},
+		{EventKind(99), "Unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.kind.String(); got != tt.want {
+			t.Errorf("EventKind(%d).String() = %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+// TestBrowseEventKind_MapsEveryServiceEventType verifies browseEventKind
+// translates each ServiceEventType onto its Added/Updated/Removed
+// equivalent.
+func TestBrowseEventKind_MapsEveryServiceEventType(t *testing.T) {
+	tests := []struct {
+		in   ServiceEventType
+		want EventKind
+	}{
+		{ServiceFound, Added},
+		{ServiceChanged, Updated},
+		{ServiceLost, Removed},
+	}
+
+	for _, tt := range tests {
+		if got := browseEventKind(tt.in); got != tt.want {
+			t.Errorf("browseEventKind(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
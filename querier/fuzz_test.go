@@ -0,0 +1,143 @@
+package querier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// FuzzCollectResponse feeds arbitrary bytes through the same decode path
+// collectResponses uses on every packet pulled off the wire: ParseMessage,
+// ValidateResponse, the answer-name/question match check, and ParseRDATA.
+// The only requirement is that it never panics, hangs, or otherwise crashes
+// regardless of how adversarial the input is (RFC 6762 §11 applies no trust
+// to the network).
+//
+// Run with: go test -fuzz=FuzzCollectResponse -fuzztime=30s ./querier/
+func FuzzCollectResponse(f *testing.F) {
+	// Seed corpus: a well-formed A-record response to "test.local".
+	f.Add([]byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags (QR=1, AA=1)
+		0x00, 0x01, // QDCOUNT = 1
+		0x00, 0x01, // ANCOUNT = 1
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x00, // ARCOUNT = 0
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // QTYPE = A
+		0x00, 0x01, // QCLASS = IN
+		0xC0, 0x0C, // NAME: pointer to question
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 100,
+	})
+
+	// Seed corpus: spoofed answer for a name other than the question,
+	// the exact cache-poisoning shape the owner-name check must reject.
+	f.Add([]byte{
+		0x12, 0x34,
+		0x84, 0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00,
+		0x00, 0x00,
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x06, 's', 'p', 'o', 'o', 'f', 'e', 'd', // NAME: "spoofed" (not "test.local")
+		0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0x00, 0x04,
+		10, 0, 0, 1,
+	})
+
+	// Seed corpus: negative-looking TTL (high bit set - RFC 1035 TTL is
+	// unsigned, but nothing stops a hostile sender encoding 0xFFFFFFFF).
+	f.Add([]byte{
+		0x12, 0x34,
+		0x84, 0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00,
+		0x00, 0x00,
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0xC0, 0x0C,
+		0x00, 0x01,
+		0x00, 0x01,
+		0xFF, 0xFF, 0xFF, 0xFF, // TTL = 0xFFFFFFFF
+		0x00, 0x04,
+		192, 168, 1, 100,
+	})
+
+	// Seed corpus: truncated SRV RDATA (missing target name entirely).
+	f.Add([]byte{
+		0x12, 0x34,
+		0x84, 0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00,
+		0x00, 0x00,
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x21, // QTYPE = SRV
+		0x00, 0x01,
+		0xC0, 0x0C,
+		0x00, 0x21,
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0x00, 0x04, // RDLENGTH = 4, too short for priority+weight+port+target
+		0x00, 0x0A, 0x00, 0x14,
+	})
+
+	// Seed corpus: header claims far more records than the packet carries.
+	f.Add([]byte{
+		0x12, 0x34,
+		0x84, 0x00,
+		0x00, 0x01,
+		0xFF, 0xFF, // ANCOUNT = 65535
+		0xFF, 0xFF, // NSCOUNT = 65535
+		0xFF, 0xFF, // ARCOUNT = 65535
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parsedMsg, err := message.ParseMessage(data)
+		if err != nil {
+			return
+		}
+
+		if err := protocol.ValidateResponse(parsedMsg.Header.Flags); err != nil {
+			return
+		}
+
+		for _, question := range parsedMsg.Questions {
+			for _, answer := range parsedMsg.Answers {
+				if !strings.EqualFold(answer.NAME, question.QNAME) {
+					continue
+				}
+				// Decoding an accepted answer's RDATA must never panic,
+				// however malformed the bytes inside it are.
+				_, _ = message.ParseRDATA(answer.TYPE, answer.RDATA)
+			}
+		}
+	})
+}
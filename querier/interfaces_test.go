@@ -0,0 +1,135 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestQuerier_WithAllInterfaces_SetsFlag verifies the option's effect on the
+// Querier struct directly, without requiring real multicast sockets.
+func TestQuerier_WithAllInterfaces_SetsFlag(t *testing.T) {
+	q := &Querier{}
+	if err := WithAllInterfaces()(q); err != nil {
+		t.Fatalf("WithAllInterfaces()(q) failed: %v", err)
+	}
+	if !q.allInterfaces {
+		t.Error("allInterfaces = false, want true after WithAllInterfaces()")
+	}
+}
+
+// TestResolveInterfaces_PriorityOrder verifies explicitInterfaces (WithInterfaces)
+// wins over allInterfaces (WithAllInterfaces), which wins over interfaceFilter
+// (WithInterfaceFilter), and that none of the three set yields nil (defer to
+// each transport's own default filtering).
+func TestResolveInterfaces_PriorityOrder(t *testing.T) {
+	explicit := []net.Interface{{Name: "explicit0"}}
+
+	q := &Querier{explicitInterfaces: explicit, allInterfaces: true, interfaceFilter: func(net.Interface) bool { return true }}
+	got, err := q.resolveInterfaces()
+	if err != nil {
+		t.Fatalf("resolveInterfaces() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "explicit0" {
+		t.Errorf("resolveInterfaces() = %+v, want explicitInterfaces to win", got)
+	}
+
+	q = &Querier{allInterfaces: true, interfaceFilter: func(net.Interface) bool { return false }}
+	got, err = q.resolveInterfaces()
+	if err != nil {
+		t.Fatalf("resolveInterfaces() failed: %v", err)
+	}
+	want, _ := net.Interfaces()
+	if len(got) != len(want) {
+		t.Errorf("resolveInterfaces() returned %d interfaces, want all %d from net.Interfaces()", len(got), len(want))
+	}
+
+	q = &Querier{}
+	got, err = q.resolveInterfaces()
+	if err != nil {
+		t.Fatalf("resolveInterfaces() failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("resolveInterfaces() = %+v, want nil when no interface option is set", got)
+	}
+}
+
+// TestResolveInterfaces_FilterRejectingEverythingErrors verifies a
+// WithInterfaceFilter that matches nothing is reported, rather than silently
+// producing a Querier with zero interfaces to query.
+func TestResolveInterfaces_FilterRejectingEverything(t *testing.T) {
+	q := &Querier{interfaceFilter: func(net.Interface) bool { return false }}
+	if _, err := q.resolveInterfaces(); err == nil {
+		t.Error("resolveInterfaces() = nil error, want an error when the filter matches no interface")
+	}
+}
+
+// fakeJoinerTransport is a MockTransport that additionally implements
+// interfaceJoiner, for testing Querier.Interfaces() without a real
+// UDPv4Transport/UDPv6Transport socket.
+type fakeJoinerTransport struct {
+	*transport.MockTransport
+	joined []string
+}
+
+func (f *fakeJoinerTransport) JoinedInterfaces() []string { return f.joined }
+
+// TestQuerier_Interfaces_DedupsAcrossEndpoints verifies that Interfaces()
+// merges and sorts JoinedInterfaces() from every endpoint implementing
+// interfaceJoiner, deduplicating a name shared by more than one (as a
+// DualStack Querier's IPv4 and IPv6 endpoints typically do), and ignores an
+// endpoint whose transport doesn't implement it at all.
+func TestQuerier_Interfaces_DedupsAcrossEndpoints(t *testing.T) {
+	q := &Querier{endpoints: []endpoint{
+		{transport: &fakeJoinerTransport{MockTransport: transport.NewMockTransport(), joined: []string{"eth0", "wlan0"}}},
+		{transport: &fakeJoinerTransport{MockTransport: transport.NewMockTransport(), joined: []string{"eth0"}}},
+		{transport: transport.NewMockTransport()}, // doesn't implement interfaceJoiner
+	}}
+
+	got := q.Interfaces()
+	want := []string{"eth0", "wlan0"}
+	if len(got) != len(want) {
+		t.Fatalf("Interfaces() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Interfaces()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestQuerier_Interfaces_NoJoinersReturnsNil verifies Interfaces() returns
+// an empty result rather than panicking when no endpoint's transport tracks
+// membership (e.g. a Querier built with WithTransport).
+func TestQuerier_Interfaces_NoJoinersReturnsNil(t *testing.T) {
+	q := &Querier{endpoints: []endpoint{{transport: transport.NewMockTransport()}}}
+	if got := q.Interfaces(); len(got) != 0 {
+		t.Errorf("Interfaces() = %v, want empty", got)
+	}
+}
+
+// TestReceiveOne_FallsBackToReceive_WhenNotBatchReceiver verifies receiveOne
+// uses plain Receive (iface always nil) against a transport that doesn't
+// implement transport.BatchReceiver, like MockTransport.
+func TestReceiveOne_FallsBackToReceive_WhenNotBatchReceiver(t *testing.T) {
+	mock := transport.NewMockTransport()
+	src := &net.UDPAddr{IP: net.ParseIP("192.168.1.5"), Port: 5353}
+	mock.QueueReceive([]byte("packet"), src)
+
+	q := &Querier{}
+	data, addr, iface, err := q.receiveOne(context.Background(), mock)
+	if err != nil {
+		t.Fatalf("receiveOne() failed: %v", err)
+	}
+	if string(data) != "packet" {
+		t.Errorf("data = %q, want %q", data, "packet")
+	}
+	if addr.String() != src.String() {
+		t.Errorf("addr = %v, want %v", addr, src)
+	}
+	if iface != nil {
+		t.Errorf("iface = %v, want nil for a non-BatchReceiver transport", iface)
+	}
+}
@@ -0,0 +1,249 @@
+package querier
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
+)
+
+// defaultKnownAnswerCacheSize is the capacity New() gives the Known-Answer
+// cache it creates by default, overridable via WithKnownAnswerCache.
+const defaultKnownAnswerCacheSize = 256
+
+// KnownAnswerCache is a bounded, TTL-keyed cache of resource records a
+// Querier has already seen, keyed by (name, type, class, rdata). Query
+// consults it before every outbound send to build an RFC 6762 §7.1
+// Known-Answer list, and populates it from every answer it collects - so a
+// responder already holding the same data can suppress its own reply
+// instead of every query re-announcing what the network already told this
+// Querier. RDATA is part of the key (not just name/type/class) so that a
+// shared record type like PTR - where a single service type's answers carry
+// one RDATA per instance - keeps one entry per instance instead of each
+// Put() overwriting the last instance seen.
+//
+// Safe for concurrent use.
+type KnownAnswerCache struct {
+	mu      sync.Mutex
+	size    int
+	order   []string // insertion order of keys, oldest first, for FIFO eviction
+	entries map[string]*knownAnswerCacheEntry
+}
+
+// knownAnswerCacheEntry pairs a cached record with the RecordTTL tracking
+// its remaining lifetime, matching records.KnownAnswer's shape.
+type knownAnswerCacheEntry struct {
+	record *message.ResourceRecord
+	ttl    *records.RecordTTL
+}
+
+// NewKnownAnswerCache creates an empty KnownAnswerCache holding at most size
+// entries; once full, the oldest entry is evicted to make room for a new
+// key. size <= 0 is treated as unbounded.
+func NewKnownAnswerCache(size int) *KnownAnswerCache {
+	return &KnownAnswerCache{
+		size:    size,
+		entries: make(map[string]*knownAnswerCacheEntry),
+	}
+}
+
+// Put records record as known, valid for record.TTL seconds, overwriting any
+// existing entry for the same (Name, Type, Class, RDATA) and resetting its
+// remaining-TTL clock. record.TTL == 0 evicts any existing entry instead,
+// matching RFC 6762 §10.1's goodbye convention - for a shared record this
+// only removes the one instance the goodbye names, leaving its siblings
+// cached. record.CacheFlush additionally evicts every other entry sharing
+// (Name, Type, Class) regardless of RDATA before caching this one, per RFC
+// 6762 §10.2: a unique record's cache-flush announcement means this is now
+// the only valid answer, superseding whatever stale RDATA was cached under
+// the same name/type/class. Exposed (alongside Entries) so an application
+// can pre-seed the cache before a Querier's first query for a name it
+// already knows about from elsewhere.
+func (c *KnownAnswerCache) Put(record message.ResourceRecord) {
+	key := knownAnswerCacheKey(record.Name, record.Type, record.Class, record.Data)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if record.TTL == 0 {
+		c.deleteLocked(key)
+		return
+	}
+
+	if record.CacheFlush {
+		c.evictSiblingsLocked(record.Name, record.Type, record.Class, key)
+	}
+
+	if _, exists := c.entries[key]; !exists {
+		c.order = append(c.order, key)
+	}
+	rr := record
+	c.entries[key] = &knownAnswerCacheEntry{
+		record: &rr,
+		ttl:    records.NewRecordTTL(record.Type, record.TTL),
+	}
+	c.evictOverflowLocked()
+}
+
+// evictSiblingsLocked removes every entry matching (name, type, class) other
+// than keep, the key record.CacheFlush's Put is about to (re)write. Callers
+// must hold c.mu.
+func (c *KnownAnswerCache) evictSiblingsLocked(name string, recordType protocol.RecordType, class protocol.DNSClass, keep string) {
+	for key, entry := range c.entries {
+		if key == keep {
+			continue
+		}
+		if !strings.EqualFold(entry.record.Name, name) || entry.record.Type != recordType || entry.record.Class != class {
+			continue
+		}
+		c.deleteLocked(key)
+	}
+}
+
+// Entries returns every still-live cached record, for an application that
+// wants to inspect what a Querier already knows.
+func (c *KnownAnswerCache) Entries() []message.ResourceRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]message.ResourceRecord, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if entry.ttl.IsExpired() {
+			continue
+		}
+		rr := *entry.record
+		rr.TTL = entry.ttl.GetRemainingTTL()
+		out = append(out, rr)
+	}
+	return out
+}
+
+// knownAnswers returns name/recordType/class's cached entries that are still
+// more than half through their original TTL, as a Known-Answer list for
+// message.BuildQueryWithKnownAnswers - RFC 6762 §7.1's freshness rule: an
+// answer less than half-expired is worth sending to suppress a reply, while
+// one closer to expiring is left out so the record gets refreshed instead of
+// silently going stale. recordType == RecordTypeANY matches every cached
+// type for name.
+func (c *KnownAnswerCache) knownAnswers(name string, recordType RecordType, class uint16) []*message.ResourceRecord {
+	c.mu.Lock()
+	matches := c.matchingLiveEntriesLocked(name, recordType, class)
+	c.mu.Unlock()
+
+	if len(matches) == 0 {
+		return nil
+	}
+	live := make([]records.KnownAnswer, len(matches))
+	for i, entry := range matches {
+		live[i] = records.KnownAnswer{Record: entry.record, TTL: entry.ttl}
+	}
+	return records.BuildKnownAnswers(live)
+}
+
+// FreshAnswers returns name/recordType/class's cached records that are
+// still more than half through their original TTL - the same freshness
+// threshold knownAnswers applies when deciding what belongs in an outgoing
+// Known-Answer list - decoded and ready for a Response, with TTL set to
+// each record's remaining lifetime. Query treats a non-empty result as
+// license to answer from the cache instead of sending anything. Returns nil
+// if nothing cached is fresh enough, or every fresh entry's RDATA fails to
+// parse.
+func (c *KnownAnswerCache) FreshAnswers(name string, recordType RecordType, class uint16) []ResourceRecord {
+	c.mu.Lock()
+	matches := c.matchingLiveEntriesLocked(name, recordType, class)
+	c.mu.Unlock()
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	out := make([]ResourceRecord, 0, len(matches))
+	for _, entry := range matches {
+		data, err := message.ParseRDATA(uint16(entry.record.Type), entry.record.Data)
+		if err != nil {
+			continue
+		}
+		out = append(out, ResourceRecord{
+			Name:  entry.record.Name,
+			Type:  RecordType(entry.record.Type),
+			Class: uint16(entry.record.Class),
+			TTL:   entry.ttl.GetRemainingTTL(),
+			Data:  data,
+		})
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// matchingLiveEntriesLocked returns every cache entry matching
+// name/recordType/class that is still more than half through its original
+// TTL - the shared freshness filter knownAnswers and FreshAnswers both
+// apply, just for different output shapes (a wire Known-Answer list vs. a
+// short-circuited Response). Callers must hold c.mu.
+func (c *KnownAnswerCache) matchingLiveEntriesLocked(name string, recordType RecordType, class uint16) []*knownAnswerCacheEntry {
+	var live []*knownAnswerCacheEntry
+	for _, entry := range c.entries {
+		if !strings.EqualFold(entry.record.Name, name) {
+			continue
+		}
+		if recordType != RecordTypeANY && entry.record.Type != protocol.RecordType(recordType) {
+			continue
+		}
+		if uint16(entry.record.Class) != class {
+			continue
+		}
+		if entry.ttl.IsExpired() || entry.ttl.GetRemainingTTL()*2 <= entry.ttl.TTL {
+			continue
+		}
+		live = append(live, entry)
+	}
+	return live
+}
+
+// Flush discards every cached entry, used by Querier.FlushCache.
+func (c *KnownAnswerCache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*knownAnswerCacheEntry)
+	c.order = nil
+}
+
+// deleteLocked removes key from entries and order. Callers must hold c.mu.
+func (c *KnownAnswerCache) deleteLocked(key string) {
+	if _, ok := c.entries[key]; !ok {
+		return
+	}
+	delete(c.entries, key)
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// evictOverflowLocked drops the oldest entries until the cache is back
+// within size. Callers must hold c.mu.
+func (c *KnownAnswerCache) evictOverflowLocked() {
+	if c.size <= 0 {
+		return
+	}
+	for len(c.order) > c.size {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.entries, oldest)
+	}
+}
+
+// knownAnswerCacheKey returns the entries map key for name/type/class/rdata,
+// matching names case-insensitively per RFC 1035 §2.3.3. RDATA is included
+// verbatim (not case-folded) since it's opaque wire data, not a DNS name.
+func knownAnswerCacheKey(name string, recordType protocol.RecordType, class protocol.DNSClass, data []byte) string {
+	return fmt.Sprintf("%s|%d|%d|%x", strings.ToLower(name), recordType, class, data)
+}
@@ -0,0 +1,285 @@
+package querier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+func aRecord(name string, ttl uint32) message.ResourceRecord {
+	return message.ResourceRecord{
+		Name:  name,
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   ttl,
+		Data:  []byte{192, 168, 1, 1},
+	}
+}
+
+// TestKnownAnswerCache_KnownAnswers_FreshEntryIncluded verifies a
+// just-cached record (remaining TTL == original TTL) is returned as a
+// Known-Answer.
+func TestKnownAnswerCache_KnownAnswers_FreshEntryIncluded(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	c.Put(aRecord("host.local", 120))
+
+	got := c.knownAnswers("host.local", RecordTypeA, uint16(protocol.ClassIN))
+	if len(got) != 1 {
+		t.Fatalf("knownAnswers() returned %d entries, want 1", len(got))
+	}
+	if got[0].TTL != 120 {
+		t.Errorf("knownAnswers()[0].TTL = %d, want 120", got[0].TTL)
+	}
+}
+
+// TestKnownAnswerCache_KnownAnswers_OmitsEntryPastHalfTTL verifies RFC 6762
+// §7.1's freshness rule: an entry whose remaining TTL has dropped to half or
+// below its original TTL is no longer offered as a Known-Answer.
+func TestKnownAnswerCache_KnownAnswers_OmitsEntryPastHalfTTL(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	c.Put(aRecord("host.local", 120))
+
+	// Simulate elapsed time by rewriting CreatedAt rather than sleeping.
+	key := knownAnswerCacheKey("host.local", protocol.RecordTypeA, protocol.ClassIN, []byte{192, 168, 1, 1})
+	entry := c.entries[key]
+	entry.ttl.CreatedAt = entry.ttl.CreatedAt.Add(-61 * time.Second)
+
+	got := c.knownAnswers("host.local", RecordTypeA, uint16(protocol.ClassIN))
+	if len(got) != 0 {
+		t.Errorf("knownAnswers() returned %d entries past half-TTL, want 0", len(got))
+	}
+}
+
+// TestKnownAnswerCache_Put_DistinctRDATACoexist verifies that two records
+// sharing (Name, Type, Class) but differing by RDATA - the common case for
+// PTR records naming different instances under one service type - both
+// remain cached rather than the second Put overwriting the first.
+func TestKnownAnswerCache_Put_DistinctRDATACoexist(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	ptr := func(instance string) message.ResourceRecord {
+		return message.ResourceRecord{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte(instance),
+		}
+	}
+	c.Put(ptr("printer"))
+	c.Put(ptr("scanner"))
+
+	got := c.knownAnswers("_http._tcp.local", RecordTypePTR, uint16(protocol.ClassIN))
+	if len(got) != 2 {
+		t.Fatalf("knownAnswers() returned %d entries, want 2 (one per instance)", len(got))
+	}
+}
+
+// TestKnownAnswerCache_Put_TTLZeroEvicts verifies a goodbye record (TTL=0)
+// removes any existing entry instead of caching a zero-TTL Known-Answer.
+func TestKnownAnswerCache_Put_TTLZeroEvicts(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	c.Put(aRecord("host.local", 120))
+	c.Put(aRecord("host.local", 0))
+
+	if got := c.knownAnswers("host.local", RecordTypeA, uint16(protocol.ClassIN)); len(got) != 0 {
+		t.Errorf("knownAnswers() after TTL=0 Put returned %d entries, want 0", len(got))
+	}
+	if entries := c.Entries(); len(entries) != 0 {
+		t.Errorf("Entries() after TTL=0 Put returned %d entries, want 0", len(entries))
+	}
+}
+
+// TestKnownAnswerCache_Size_EvictsOldest verifies a cache bounded to size
+// entries drops the oldest key once a new one would exceed it.
+func TestKnownAnswerCache_Size_EvictsOldest(t *testing.T) {
+	c := NewKnownAnswerCache(2)
+	c.Put(aRecord("first.local", 120))
+	c.Put(aRecord("second.local", 120))
+	c.Put(aRecord("third.local", 120))
+
+	if len(c.Entries()) != 2 {
+		t.Fatalf("Entries() = %d, want 2 after exceeding size", len(c.Entries()))
+	}
+	if got := c.knownAnswers("first.local", RecordTypeA, uint16(protocol.ClassIN)); len(got) != 0 {
+		t.Errorf("knownAnswers(\"first.local\") = %d entries, want 0 (should have been evicted)", len(got))
+	}
+	if got := c.knownAnswers("third.local", RecordTypeA, uint16(protocol.ClassIN)); len(got) != 1 {
+		t.Errorf("knownAnswers(\"third.local\") = %d entries, want 1", len(got))
+	}
+}
+
+// TestKnownAnswerCache_KnownAnswers_ANYMatchesEveryType verifies
+// RecordTypeANY returns every cached type for name, not just one.
+func TestKnownAnswerCache_KnownAnswers_ANYMatchesEveryType(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	c.Put(aRecord("host.local", 120))
+	c.Put(message.ResourceRecord{
+		Name: "host.local", Type: protocol.RecordTypeTXT, Class: protocol.ClassIN,
+		TTL: 120, Data: []byte{0x00},
+	})
+
+	got := c.knownAnswers("host.local", RecordTypeANY, uint16(protocol.ClassIN))
+	if len(got) != 2 {
+		t.Errorf("knownAnswers(RecordTypeANY) = %d entries, want 2", len(got))
+	}
+}
+
+// TestWithKnownAnswerCache_ConfiguresSize verifies the option replaces
+// New()'s default-sized cache.
+func TestWithKnownAnswerCache_ConfiguresSize(t *testing.T) {
+	q, err := New(WithKnownAnswerCache(1))
+	if err != nil {
+		t.Fatalf("New(WithKnownAnswerCache) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	q.Cache().Put(aRecord("first.local", 120))
+	q.Cache().Put(aRecord("second.local", 120))
+
+	if len(q.Cache().Entries()) != 1 {
+		t.Errorf("Entries() = %d, want 1 (size=1 should have evicted the first Put)", len(q.Cache().Entries()))
+	}
+}
+
+// TestWithKnownAnswerCache_RejectsNonPositiveSize verifies WithKnownAnswerCache(0)
+// fails construction rather than silently building an unbounded cache.
+func TestWithKnownAnswerCache_RejectsNonPositiveSize(t *testing.T) {
+	if _, err := New(WithKnownAnswerCache(0)); err == nil {
+		t.Error("New(WithKnownAnswerCache(0)) expected error, got nil")
+	}
+}
+
+// TestKnownAnswerCache_FreshAnswers_DecodesLiveEntries verifies FreshAnswers
+// applies the same half-TTL freshness rule as knownAnswers, but returns
+// decoded ResourceRecords instead of wire-ready message.ResourceRecords.
+func TestKnownAnswerCache_FreshAnswers_DecodesLiveEntries(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	c.Put(aRecord("host.local", 120))
+
+	got := c.FreshAnswers("host.local", RecordTypeA, uint16(protocol.ClassIN))
+	if len(got) != 1 {
+		t.Fatalf("FreshAnswers() returned %d entries, want 1", len(got))
+	}
+	if got[0].Name != "host.local" || got[0].TTL != 120 {
+		t.Errorf("FreshAnswers()[0] = %+v, want host.local TTL=120", got[0])
+	}
+
+	key := knownAnswerCacheKey("host.local", protocol.RecordTypeA, protocol.ClassIN, []byte{192, 168, 1, 1})
+	entry := c.entries[key]
+	entry.ttl.CreatedAt = entry.ttl.CreatedAt.Add(-61 * time.Second)
+
+	if got := c.FreshAnswers("host.local", RecordTypeA, uint16(protocol.ClassIN)); len(got) != 0 {
+		t.Errorf("FreshAnswers() past half-TTL returned %d entries, want 0", len(got))
+	}
+}
+
+// TestKnownAnswerCache_Flush_DiscardsAllEntries verifies Flush empties the
+// cache regardless of how many names/types it holds.
+func TestKnownAnswerCache_Flush_DiscardsAllEntries(t *testing.T) {
+	c := NewKnownAnswerCache(10)
+	c.Put(aRecord("first.local", 120))
+	c.Put(aRecord("second.local", 120))
+
+	c.Flush()
+
+	if entries := c.Entries(); len(entries) != 0 {
+		t.Errorf("Entries() after Flush() = %d, want 0", len(entries))
+	}
+}
+
+// TestWithNoCache_DisablesCache verifies WithNoCache leaves Cache() nil and
+// FlushCache a harmless no-op.
+func TestWithNoCache_DisablesCache(t *testing.T) {
+	q, err := New(WithNoCache())
+	if err != nil {
+		t.Fatalf("New(WithNoCache) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	if q.Cache() != nil {
+		t.Errorf("Cache() = %v, want nil", q.Cache())
+	}
+	q.FlushCache() // must not panic
+}
+
+// TestQuerier_FlushCache_EmptiesCache verifies FlushCache discards whatever
+// the default cache is holding.
+func TestQuerier_FlushCache_EmptiesCache(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	q.Cache().Put(aRecord("host.local", 120))
+	q.FlushCache()
+
+	if entries := q.Cache().Entries(); len(entries) != 0 {
+		t.Errorf("Cache().Entries() after FlushCache() = %d, want 0", len(entries))
+	}
+}
+
+// TestAppendMatchingAnswers_CacheFlushEvictsPriorEntry verifies that
+// feeding appendMatchingAnswers a response whose Answer carries the
+// cache-flush bit (RFC 6762 §10.2) leaves the KnownAnswerCache holding only
+// that answer's data - the stale record a prior response cached for the
+// same name/type/class is gone, not merely appended alongside the new one.
+func TestAppendMatchingAnswers_CacheFlushEvictsPriorEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		respType uint16
+		oldData  []byte
+		newData  []byte
+	}{
+		{
+			name:     "A record cache-flush replaces stale address",
+			respType: uint16(protocol.RecordTypeA),
+			oldData:  []byte{192, 168, 1, 1},
+			newData:  []byte{192, 168, 1, 2},
+		},
+		{
+			name:     "TXT record cache-flush replaces stale data",
+			respType: uint16(protocol.RecordTypeTXT),
+			oldData:  []byte{4, 'o', 'l', 'd', '='},
+			newData:  []byte{4, 'n', 'e', 'w', '='},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewKnownAnswerCache(10)
+
+			old := &message.DNSMessage{
+				Answers: []message.Answer{
+					{NAME: "host.local", TYPE: tt.respType, CLASS: 0x0001, TTL: 120, RDATA: tt.oldData},
+				},
+			}
+			appendMatchingAnswers(&Response{}, map[string]bool{}, old, "host.local", RecordType(tt.respType), cache, nil, nil)
+
+			entries := cache.Entries()
+			if len(entries) != 1 || string(entries[0].Data) != string(tt.oldData) {
+				t.Fatalf("cache after first Put = %+v, want one entry with Data %v", entries, tt.oldData)
+			}
+
+			flush := &message.DNSMessage{
+				Answers: []message.Answer{
+					{NAME: "host.local", TYPE: tt.respType, CLASS: 0x8001, TTL: 120, RDATA: tt.newData},
+				},
+			}
+			if !flush.Answers[0].CacheFlush() {
+				t.Fatal("test answer's CLASS does not carry the cache-flush bit")
+			}
+			appendMatchingAnswers(&Response{}, map[string]bool{}, flush, "host.local", RecordType(tt.respType), cache, nil, nil)
+
+			entries = cache.Entries()
+			if len(entries) != 1 {
+				t.Fatalf("cache after cache-flush Put has %d entries, want 1 (stale entry not evicted)", len(entries))
+			}
+			if string(entries[0].Data) != string(tt.newData) {
+				t.Errorf("cache after cache-flush Put = %v, want %v", entries[0].Data, tt.newData)
+			}
+		})
+	}
+}
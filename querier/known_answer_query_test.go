@@ -0,0 +1,215 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestQuery_PopulatesCacheAndSendsKnownAnswerOnNextQuery validates the
+// end-to-end RFC 6762 §7.1 loop: a response Query collects is cached, and a
+// later QueryWithOptions call for the same name carries it as a
+// Known-Answer. Plain Query is not used for the second call because a fresh
+// cache entry now makes it short-circuit (see
+// TestQuery_ShortCircuitsFromFreshCache_NoWireTraffic) - QueryWithOptions
+// never does, so it's the entry point that still exercises the on-wire
+// Known-Answer path.
+func TestQuery_PopulatesCacheAndSendsKnownAnswerOnNextQuery(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	respPacket, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{192, 168, 1, 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse failed: %v", err)
+	}
+	mock.QueueReceive(respPacket, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "host.local", RecordTypeA); err != nil {
+		t.Fatalf("first Query failed: %v", err)
+	}
+
+	if entries := q.Cache().Entries(); len(entries) != 1 {
+		t.Fatalf("Cache().Entries() = %d, want 1 after collecting a response", len(entries))
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	if _, err := q.QueryWithOptions(ctx2, "host.local", RecordTypeA, QueryOptions{}); err != nil {
+		t.Fatalf("second query failed: %v", err)
+	}
+
+	calls := mock.SendCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 Send() calls, got %d", len(calls))
+	}
+
+	sentQuery, err := message.ParseMessage(calls[1].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage(second query) failed: %v", err)
+	}
+	if sentQuery.Header.ANCount != 1 {
+		t.Errorf("second query ANCount = %d, want 1 (Known-Answer)", sentQuery.Header.ANCount)
+	}
+	if len(sentQuery.Answers) != 1 || sentQuery.Answers[0].NAME != "host.local" {
+		t.Errorf("second query Answers = %+v, want one answer for host.local", sentQuery.Answers)
+	}
+}
+
+// TestQuery_ShortCircuitsFromFreshCache_NoWireTraffic validates the other
+// half of that same RFC 6762 §7.1 loop: once a record is cached and still
+// more than half through its TTL, Query answers from the cache directly and
+// never touches the wire at all.
+func TestQuery_ShortCircuitsFromFreshCache_NoWireTraffic(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	q.Cache().Put(message.ResourceRecord{
+		Name:  "host.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 1},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := q.Query(ctx, "host.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if len(mock.SendCalls()) != 0 {
+		t.Fatalf("expected 0 Send() calls from a fresh-cache short-circuit, got %d", len(mock.SendCalls()))
+	}
+	if len(resp.Records) != 1 || resp.Records[0].Name != "host.local" {
+		t.Errorf("Records = %+v, want the cached host.local record", resp.Records)
+	}
+}
+
+// TestQuery_KnownAnswerCache_PreSeeded validates that Cache().Put before a
+// Querier's first query for that name results in QueryWithOptions (which,
+// unlike Query, always transmits) offering that record as a Known-Answer
+// right away, without needing a prior response.
+func TestQuery_KnownAnswerCache_PreSeeded(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	q.Cache().Put(message.ResourceRecord{
+		Name:  "host.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 1},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.QueryWithOptions(ctx, "host.local", RecordTypeA, QueryOptions{}); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	calls := mock.SendCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 Send() call, got %d", len(calls))
+	}
+
+	sentQuery, err := message.ParseMessage(calls[0].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage failed: %v", err)
+	}
+	if sentQuery.Header.ANCount != 1 {
+		t.Errorf("query ANCount = %d, want 1 (pre-seeded Known-Answer)", sentQuery.Header.ANCount)
+	}
+}
+
+// TestQuery_KnownAnswerList_SplitsAcrossPacketsWithTC validates RFC 6762
+// §7.2: a Known-Answer list too large for one packet is split into multiple
+// packets sharing a transaction ID, every packet but the last carrying TC.
+// Uses QueryWithOptions so the seeded cache entries get sent as Known-
+// Answers rather than triggering Query's fresh-cache short-circuit.
+func TestQuery_KnownAnswerList_SplitsAcrossPacketsWithTC(t *testing.T) {
+	mock := transport.NewMockTransport()
+	// A small advertised UDP size leaves room for only 1 average-sized
+	// Known-Answer record per packet (see message.MaxKnownAnswersPerPacket),
+	// forcing the 3 cached record types below across multiple packets.
+	q, err := New(WithTransport(mock), WithMaxUDPSize(150))
+	if err != nil {
+		t.Fatalf("New(WithTransport, WithMaxUDPSize) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	// The cache is keyed by (name, type, class), so distinct cached entries
+	// under the same name require distinct types - seed one of each type
+	// Query(RecordTypeANY) can match at once. PTR/SRV are skipped: their
+	// RDATA must be a wire-encoded name, not the raw bytes this test seeds.
+	for _, rt := range []protocol.RecordType{
+		protocol.RecordTypeA, protocol.RecordTypeAAAA, protocol.RecordTypeTXT,
+	} {
+		q.Cache().Put(message.ResourceRecord{
+			Name:  "host.local",
+			Type:  rt,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{1, 2, 3, 4},
+		})
+	}
+
+	// RFC 6762 §7.2 follow-up packets are paced knownAnswerFollowupInterval
+	// apart, so the context must outlive both follow-ups (2 * 400ms) plus
+	// margin for this 3-packet split to actually get sent. QueryWithOptions
+	// is used instead of Query because these seeded entries are fresh enough
+	// that plain Query would short-circuit from the cache instead of
+	// transmitting at all.
+	ctx, cancel := context.WithTimeout(context.Background(), 1200*time.Millisecond)
+	defer cancel()
+	if _, err := q.QueryWithOptions(ctx, "host.local", RecordTypeANY, QueryOptions{}); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	calls := mock.SendCalls()
+	if len(calls) < 2 {
+		t.Fatalf("expected a split Known-Answer list to produce >1 Send() call, got %d", len(calls))
+	}
+
+	for i, call := range calls {
+		parsed, err := message.ParseMessage(call.Packet)
+		if err != nil {
+			t.Fatalf("ParseMessage(packet %d) failed: %v", i, err)
+		}
+		isLast := i == len(calls)-1
+		tc := parsed.Header.Flags&protocol.FlagTC != 0
+		if isLast && tc {
+			t.Errorf("last packet (%d) has TC set, want unset", i)
+		}
+		if !isLast && !tc {
+			t.Errorf("non-last packet (%d) has TC unset, want set", i)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package querier
+
+import (
+	"context"
+	"net"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// LegacyResolver sends a standard unicast DNS query directly to a single
+// peer instead of the mDNS multicast group. It exists for querying a
+// specific DNS-SD responder that multicast can't reach - across a routed
+// subnet, over a VPN, or simply a host the caller already knows the address
+// of - without sending any traffic to the rest of the LAN.
+//
+// Unlike Querier, LegacyResolver holds no long-lived socket or background
+// goroutine: each Resolve call binds a fresh ephemeral UDP socket, sends one
+// query, collects replies until ctx expires, and closes the socket.
+type LegacyResolver struct {
+	// Peer is the host:port to query, e.g. "printer.example.com:5353".
+	Peer string
+}
+
+// NewLegacyResolver creates a LegacyResolver that queries peer (host:port,
+// e.g. "192.0.2.10:5353") on every Resolve call.
+func NewLegacyResolver(peer string) *LegacyResolver {
+	return &LegacyResolver{Peer: peer}
+}
+
+// Resolve sends a standard DNS query for name/recordType to the resolver's
+// peer and aggregates responses received before ctx expires, the same way
+// Query does for multicast replies.
+//
+// Parameters:
+//   - ctx: Context for timeout/cancellation (use context.WithTimeout for custom timeout)
+//   - name: DNS name to query (e.g., "printer.local")
+//   - recordType: Type of record to query (RecordTypeA, RecordTypePTR, etc.)
+//
+// Returns:
+//   - *Response: Aggregated response with all records the peer returned
+//   - error: ValidationError for invalid inputs, a peer address resolution failure, or a NetworkError
+func (r *LegacyResolver) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	default:
+	}
+
+	if err := protocol.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if err := protocol.ValidateRecordType(uint16(recordType)); err != nil {
+		return nil, err
+	}
+
+	dest, err := net.ResolveUDPAddr("udp", r.Peer)
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "resolve legacy resolver peer",
+			Err:       err,
+			Details:   "invalid peer address \"" + r.Peer + "\"",
+		}
+	}
+
+	network := "udp4"
+	if dest.IP.To4() == nil {
+		network = "udp6"
+	}
+
+	ut, err := transport.NewUnicastUDPTransport(network)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = ut.Close() }()
+
+	queryMsg, err := message.BuildQuery(name, uint16(recordType))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsed back out so collectUnicastResponses can verify the peer echoed
+	// this query's ID (RFC 1035 §4.1.1) - see its doc and
+	// message.ValidateResponse.
+	parsedQuery, err := message.ParseMessage(queryMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ut.Send(ctx, queryMsg, dest); err != nil {
+		return nil, err
+	}
+
+	// unicast=true: this is a true point-to-point DNS query, so the peer is
+	// expected to echo queryMsg's transaction ID exactly.
+	return collectUnicastResponses(ctx, []*transport.UnicastUDPTransport{ut}, name, recordType, parsedQuery, true, nil, nil, false)
+}
@@ -0,0 +1,171 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// fakeMetrics is a minimal metrics.Metrics recorder for assertions, safe for
+// concurrent use since Query may call it from multiple goroutines.
+type fakeMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{counters: make(map[string]int)}
+}
+
+func (f *fakeMetrics) IncCounter(name string, _ map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counters[name]++
+}
+
+func (f *fakeMetrics) ObserveHistogram(name string, _ float64, _ map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counters[name]++
+}
+
+func (f *fakeMetrics) count(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counters[name]
+}
+
+// TestWithMetrics_RejectsNil validates that WithMetrics(nil) returns a
+// ValidationError instead of silently disabling instrumentation.
+func TestWithMetrics_RejectsNil(t *testing.T) {
+	q := &Querier{}
+	if err := WithMetrics(nil)(q); err == nil {
+		t.Fatal("WithMetrics(nil) returned nil error, want a ValidationError")
+	}
+}
+
+// TestQuery_WithMetrics_RecordsQueryAndCacheMiss validates that a Query call
+// with no cached Known-Answers reports a query, a cache miss, and a latency
+// observation.
+func TestQuery_WithMetrics_RecordsQueryAndCacheMiss(t *testing.T) {
+	mock := transport.NewMockTransport()
+	fm := newFakeMetrics()
+	q, err := New(WithTransport(mock), WithMetrics(fm))
+	if err != nil {
+		t.Fatalf("New(WithTransport, WithMetrics) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "host.local", RecordTypeA); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if got := fm.count("beacon_querier_queries_total"); got != 1 {
+		t.Errorf("beacon_querier_queries_total = %d, want 1", got)
+	}
+	if got := fm.count("beacon_querier_known_answer_cache_total"); got != 1 {
+		t.Errorf("beacon_querier_known_answer_cache_total = %d, want 1", got)
+	}
+	if got := fm.count("beacon_querier_query_duration_seconds"); got != 1 {
+		t.Errorf("beacon_querier_query_duration_seconds = %d, want 1", got)
+	}
+	if got := fm.count("beacon_querier_queries_sent_total"); got < 1 {
+		t.Errorf("beacon_querier_queries_sent_total = %d, want >= 1", got)
+	}
+}
+
+// TestQuery_WithMetrics_RecordsResponse validates that a collected response
+// increments beacon_querier_responses_total.
+func TestQuery_WithMetrics_RecordsResponse(t *testing.T) {
+	mock := transport.NewMockTransport()
+	fm := newFakeMetrics()
+	q, err := New(WithTransport(mock), WithMetrics(fm))
+	if err != nil {
+		t.Fatalf("New(WithTransport, WithMetrics) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	respPacket, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{192, 168, 1, 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse failed: %v", err)
+	}
+	mock.QueueReceive(respPacket, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "host.local", RecordTypeA); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if got := fm.count("beacon_querier_responses_total"); got != 1 {
+		t.Errorf("beacon_querier_responses_total = %d, want 1", got)
+	}
+}
+
+// TestWithMetricsNamespace_PrefixesReportedMetricNames validates that
+// WithMetricsNamespace wraps the Querier's metrics sink so every metric it
+// reports is prefixed, letting multiple Queriers sharing one metrics
+// backend produce distinguishable series.
+func TestWithMetricsNamespace_PrefixesReportedMetricNames(t *testing.T) {
+	mock := transport.NewMockTransport()
+	fm := newFakeMetrics()
+	q, err := New(WithTransport(mock), WithMetrics(fm), WithMetricsNamespace("eth0"))
+	if err != nil {
+		t.Fatalf("New(WithMetrics, WithMetricsNamespace) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "host.local", RecordTypeA); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if got := fm.count("eth0_beacon_querier_queries_total"); got != 1 {
+		t.Errorf("eth0_beacon_querier_queries_total = %d, want 1", got)
+	}
+	if got := fm.count("beacon_querier_queries_total"); got != 0 {
+		t.Errorf("beacon_querier_queries_total = %d, want 0 (should be namespaced)", got)
+	}
+}
+
+// TestWithConstLabels_AppliesToReportedMetrics validates that
+// WithConstLabels wraps the Querier's metrics sink so every metric it
+// reports carries the configured labels. fakeMetrics doesn't record labels
+// per-call, so this only checks the decorator doesn't break the forwarding
+// path the namespace test above already verifies by name.
+func TestWithConstLabels_AppliesToReportedMetrics(t *testing.T) {
+	mock := transport.NewMockTransport()
+	fm := newFakeMetrics()
+	q, err := New(WithTransport(mock), WithMetrics(fm), WithConstLabels(map[string]string{"instance": "eth0"}))
+	if err != nil {
+		t.Fatalf("New(WithMetrics, WithConstLabels) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "host.local", RecordTypeA); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if got := fm.count("beacon_querier_queries_total"); got != 1 {
+		t.Errorf("beacon_querier_queries_total = %d, want 1", got)
+	}
+}
@@ -1,10 +1,15 @@
 package querier
 
 import (
+	"log/slog"
 	"net"
 	"time"
 
 	"github.com/joshuafuller/beacon/internal/errors"
+	internalmetrics "github.com/joshuafuller/beacon/internal/metrics"
+	"github.com/joshuafuller/beacon/internal/transport"
+	"github.com/joshuafuller/beacon/metrics"
+	"github.com/joshuafuller/beacon/tap"
 )
 
 // Option is a functional option for configuring a Querier.
@@ -69,7 +74,8 @@ func WithInterfaces(ifaces []net.Interface) Option {
 // WithInterfaceFilter configures the Querier with a custom interface selection filter.
 // The filter function is called for each available interface; return true to include.
 //
-// This option is ignored if WithInterfaces() is also specified (explicit list takes priority).
+// This option is ignored if WithInterfaces() or WithAllInterfaces() is also
+// specified (both take priority over a filter).
 //
 // FR-012: System MUST provide WithInterfaceFilter(func(net.Interface) bool) functional option
 //
@@ -99,6 +105,28 @@ func WithInterfaceFilter(filter func(net.Interface) bool) Option {
 	}
 }
 
+// WithAllInterfaces configures the Querier to join the multicast group on
+// every interface net.Interfaces() returns, bypassing the default
+// VPN/Docker/loopback exclusion WithInterfaces and WithInterfaceFilter's
+// absence otherwise falls back to.
+//
+// This is the easy, not-fine-grained opposite of WithInterfaceFilter: use it
+// on a host where the default exclusion list is wrong for your setup (e.g.
+// mDNS traffic genuinely needs to reach a tunnel interface) instead of
+// writing a filter that just inverts the default one. WithInterfaces, if
+// also specified, takes priority over this option, the same way it takes
+// priority over WithInterfaceFilter.
+//
+// Example:
+//
+//	q, _ := querier.New(querier.WithAllInterfaces())
+func WithAllInterfaces() Option {
+	return func(q *Querier) error {
+		q.allInterfaces = true
+		return nil
+	}
+}
+
 // WithRateLimit enables or disables rate limiting.
 // Rate limiting protects against multicast storms by tracking per-source-IP query rates.
 //
@@ -173,27 +201,556 @@ func WithRateLimitCooldown(cooldown time.Duration) Option {
 	}
 }
 
-// TODO M2 (T100): Add WithTransport() option for test isolation
-// This would allow injecting MockTransport for unit testing without real network.
-// Current gap: All querier tests use real UDP sockets, making edge cases harder to test.
+// WithMaxResponseBytes sets the maximum size, in bytes, of a single raw
+// response packet the Querier will accept before parsing it; larger packets
+// are dropped by the receiver. This guards against oversized or malformed
+// jumbo payloads being used to inflate parsing cost.
+//
+// Default: 9000 bytes (RFC 6762 §17).
+//
+// Example (tighten the ceiling on constrained networks):
+//
+//	q, _ := querier.New(querier.WithMaxResponseBytes(1500))
+func WithMaxResponseBytes(maxBytes int) Option {
+	return func(q *Querier) error {
+		if maxBytes <= 0 {
+			return &errors.ValidationError{
+				Field:   "maxResponseBytes",
+				Value:   maxBytes,
+				Message: "maxResponseBytes must be greater than 0",
+			}
+		}
+
+		q.maxResponseBytes = maxBytes
+		return nil
+	}
+}
+
+// WithMaxUDPSize sets the UDP payload size advertised in every outgoing
+// query's EDNS(0) OPT record (RFC 6891 §6.1.2), telling a responder on a
+// jumbo-frame segment it's safe to reply with more than the traditional
+// 512-byte DNS default instead of hard-truncating per RFC 6762 §17.
+//
+// Default: 1440 bytes (fits a single untagged Ethernet frame).
+//
+// Example (advertise the full RFC 6762 §17 ceiling on a jumbo-frame LAN):
+//
+//	q, _ := querier.New(querier.WithMaxUDPSize(9000))
+func WithMaxUDPSize(n uint16) Option {
+	return func(q *Querier) error {
+		if n == 0 {
+			return &errors.ValidationError{
+				Field:   "maxUDPSize",
+				Value:   n,
+				Message: "maxUDPSize must be greater than 0",
+			}
+		}
+
+		q.maxUDPSize = n
+		return nil
+	}
+}
+
+// WithIPFamily selects which multicast address family(ies) the Querier sends
+// queries on and listens for responses on.
+//
+// Default: IPv4Only, matching the Querier's original (pre-dual-stack) behavior.
+//
+// Example (query both address families and merge the results):
+//
+//	q, _ := querier.New(querier.WithIPFamily(querier.DualStack))
+func WithIPFamily(family IPFamily) Option {
+	return func(q *Querier) error {
+		switch family {
+		case IPv4Only, IPv6Only, DualStack:
+			q.ipFamily = family
+			return nil
+		default:
+			return &errors.ValidationError{
+				Field:   "ipFamily",
+				Value:   family,
+				Message: "must be IPv4Only, IPv6Only, or DualStack",
+			}
+		}
+	}
+}
+
+// WithDualStack creates both an IPv4 and an IPv6 multicast transport, like
+// WithIPFamily(DualStack), but sends queries between them differently: a
+// query goes out on IPv4 first, and only falls back to IPv6 if nothing
+// answered within the race delay (default: 300ms, configurable via
+// WithRaceDelay), taking whichever family answers first. This follows the
+// "happy eyeballs" pattern tailscale uses to race a pair of DNS transports.
+//
+// This trades WithIPFamily(DualStack)'s every-responder aggregation for
+// lower latency and, on the common case of a network where IPv4 always gets
+// answered, one fewer multicast transmission per query - use
+// WithIPFamily(DualStack) instead when collecting every responder (not just
+// the first) matters more than latency.
+//
+// Default: disabled (WithIPFamily's IPv4Only default applies). Setting both
+// WithDualStack and WithIPFamily is order-dependent like any other option
+// pair - whichever is applied last wins.
+//
+// Example (query both families, preferring whichever answers fastest):
+//
+//	q, _ := querier.New(querier.WithDualStack())
+func WithDualStack() Option {
+	return func(q *Querier) error {
+		q.ipFamily = DualStack
+		q.raceDualStack = true
+		return nil
+	}
+}
+
+// WithRaceDelay sets how long a WithDualStack() query waits for an IPv4
+// answer before also sending on IPv6. Has no effect without WithDualStack.
+//
+// Default: defaultRaceDelay (300ms).
+//
+// Example (fall back to IPv6 sooner, for a network known to have fast
+// IPv4-only responders and the occasional IPv6-only one):
+//
+//	q, _ := querier.New(querier.WithDualStack(), querier.WithRaceDelay(100*time.Millisecond))
+func WithRaceDelay(delay time.Duration) Option {
+	return func(q *Querier) error {
+		if delay <= 0 {
+			return &errors.ValidationError{
+				Field:   "raceDelay",
+				Value:   delay,
+				Message: "must be greater than 0",
+			}
+		}
+
+		q.raceDelay = delay
+		return nil
+	}
+}
+
+// WithWatchInterfaces enables a background watcher that polls for network
+// interface changes (Wi-Fi reassociating, a VPN coming up, a cable being
+// unplugged) every few seconds for the lifetime of the Querier.
+//
+// Without this, interfaces are enumerated once in New() and never revisited:
+// a laptop that switches from Ethernet to Wi-Fi mid-session keeps querying
+// through whatever interfaces looked usable at construction time.
+//
+// When enabled, call InterfaceChanges() to receive InterfaceUp/InterfaceDown
+// events. Each reported interface has already been re-filtered through the
+// same VPN/Docker/loopback exclusion DefaultInterfaces applies, so a freshly
+// connected utun0 is never reported as usable.
+//
+// Default: disabled, since the extra goroutine is wasted on short-lived
+// queries that don't outlive a single network state.
+//
+// Example:
+//
+//	q, _ := querier.New(querier.WithWatchInterfaces(true))
+//	for change := range q.InterfaceChanges() {
+//	    fmt.Printf("%v: %s\n", change.Type, change.Interface.Name)
+//	}
+func WithWatchInterfaces(enabled bool) Option {
+	return func(q *Querier) error {
+		q.watchInterfaces = enabled
+		return nil
+	}
+}
+
+// WithWatchBackoff sets the retry policy Watch() sessions use after a
+// transient transport error (a socket rebind after an interface flap,
+// ENOBUFS, a receive loop restart), independent of the RFC 6762 §5.2 query
+// cadence Watch always follows.
+//
+// Default: DefaultWatchBackoffPolicy() - 1s base delay, 1.6x factor, 120s
+// ceiling, ±20% jitter, matching gRPC's connection backoff.
+//
+// Example (retry faster on a flaky network):
+//
+//	q, _ := querier.New(querier.WithWatchBackoff(querier.WatchBackoffPolicy{
+//	    BaseDelay: 250 * time.Millisecond,
+//	    Factor:    1.6,
+//	    MaxDelay:  30 * time.Second,
+//	    Jitter:    0.2,
+//	}))
+func WithWatchBackoff(policy WatchBackoffPolicy) Option {
+	return func(q *Querier) error {
+		if policy.BaseDelay <= 0 {
+			return &errors.ValidationError{
+				Field:   "watchBackoff.BaseDelay",
+				Value:   policy.BaseDelay,
+				Message: "must be greater than 0",
+			}
+		}
+		if policy.Factor <= 1 {
+			return &errors.ValidationError{
+				Field:   "watchBackoff.Factor",
+				Value:   policy.Factor,
+				Message: "must be greater than 1",
+			}
+		}
+		if policy.MaxDelay < policy.BaseDelay {
+			return &errors.ValidationError{
+				Field:   "watchBackoff.MaxDelay",
+				Value:   policy.MaxDelay,
+				Message: "must be greater than or equal to BaseDelay",
+			}
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return &errors.ValidationError{
+				Field:   "watchBackoff.Jitter",
+				Value:   policy.Jitter,
+				Message: "must be between 0 and 1",
+			}
+		}
+
+		q.watchBackoff = policy
+		return nil
+	}
+}
+
+// WithRetry sets the RetryPolicy Query() uses to re-send and re-collect when
+// an attempt receives zero records before its deadline, instead of returning
+// that empty result immediately. This is disabled by default - Query's
+// original single-attempt behavior is preserved unless WithRetry is set -
+// since a zero-record result is frequently a legitimate "nothing answered",
+// not a dropped packet, and most callers already loop or retry themselves.
+//
+// Example (retry up to 5 times on a flaky Wi-Fi network):
+//
+//	q, _ := querier.New(querier.WithRetry(querier.RetryPolicy{
+//	    BaseDelay:   200 * time.Millisecond,
+//	    MaxDelay:    2 * time.Second,
+//	    Multiplier:  1.6,
+//	    Jitter:      0.2,
+//	    MaxAttempts: 5,
+//	}))
+func WithRetry(policy RetryPolicy) Option {
+	return func(q *Querier) error {
+		if policy.BaseDelay <= 0 {
+			return &errors.ValidationError{
+				Field:   "retryPolicy.BaseDelay",
+				Value:   policy.BaseDelay,
+				Message: "must be greater than 0",
+			}
+		}
+		if policy.Multiplier <= 1 {
+			return &errors.ValidationError{
+				Field:   "retryPolicy.Multiplier",
+				Value:   policy.Multiplier,
+				Message: "must be greater than 1",
+			}
+		}
+		if policy.MaxDelay < policy.BaseDelay {
+			return &errors.ValidationError{
+				Field:   "retryPolicy.MaxDelay",
+				Value:   policy.MaxDelay,
+				Message: "must be greater than or equal to BaseDelay",
+			}
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return &errors.ValidationError{
+				Field:   "retryPolicy.Jitter",
+				Value:   policy.Jitter,
+				Message: "must be between 0 and 1",
+			}
+		}
+		if policy.MaxAttempts < 1 {
+			return &errors.ValidationError{
+				Field:   "retryPolicy.MaxAttempts",
+				Value:   policy.MaxAttempts,
+				Message: "must be at least 1",
+			}
+		}
+
+		q.retryPolicy = &policy
+		return nil
+	}
+}
+
+// WithDropObserver registers a callback invoked synchronously, from
+// receiveLoop or a response collector's goroutine, for every packet or
+// record dropped before it reached a Response - replacing today's silent
+// continue branches with actionable diagnostics. See DropReason for the set
+// of reasons reported.
 //
-// Proposed implementation:
-//   func WithTransport(t transport.Transport) Option {
-//       return func(q *Querier) error {
-//           q.transport = t
-//           return nil
-//       }
-//   }
+// The callback must not block or call back into the Querier (it runs inline
+// on the hot receive path); aggregate into a counter like DropCounter or
+// send to a buffered channel if the caller needs more processing.
 //
-// Usage in tests:
-//   mock := transport.NewMockTransport()
-//   q, _ := New(WithTransport(mock))
+// Default: nil (no observer, and no DropEvent allocation on any drop path).
 //
-// This enables:
-// - Testing without real network
-// - Mocking specific network failures
-// - Simulating exact mDNS responses
-// - Faster test execution
+// Example (count drops per reason for a /metrics endpoint):
 //
-// Decision: Deferred to M2 (current integration coverage adequate for M1.1)
-// See: specs/004-m1-1-architectural-hardening/tasks.md Phase 8, T100
+//	counter := querier.NewDropCounter()
+//	q, _ := querier.New(querier.WithDropObserver(counter.Observe))
+func WithDropObserver(observer func(DropEvent)) Option {
+	return func(q *Querier) error {
+		if observer == nil {
+			return &errors.ValidationError{
+				Field:   "dropObserver",
+				Value:   nil,
+				Message: "observer function cannot be nil",
+			}
+		}
+
+		q.dropObserver = observer
+		return nil
+	}
+}
+
+// WithTransport injects t as the Querier's only endpoint, replacing the UDP
+// multicast transport(s) New() would otherwise build via newEndpoints.
+//
+// This is for test isolation (inject a transport.MockTransport and exercise
+// Query()/Resolve() without a real network) and for persistent-connection
+// unicast transports that were never meant to join a multicast group, such
+// as transport.TCPTransport or transport.DoTTransport - both block Receive
+// until data arrives or the deadline passes, which is what the background
+// receiveLoop polls against.
+//
+// transport.DoHClientTransport and transport.DoQClientTransport don't fit
+// here: each Send/Receive pair is a single self-contained HTTP exchange or
+// QUIC stream rather than a connection receiveLoop can poll continuously, so
+// they're meant to be driven directly by a Resolver rather than installed
+// via WithTransport.
+//
+// Example:
+//
+//	mock := transport.NewMockTransport()
+//	q, _ := querier.New(querier.WithTransport(mock))
+func WithTransport(t transport.Transport) Option {
+	return func(q *Querier) error {
+		if t == nil {
+			return &errors.ValidationError{
+				Field:   "transport",
+				Value:   nil,
+				Message: "transport cannot be nil",
+			}
+		}
+
+		q.customTransport = t
+		return nil
+	}
+}
+
+// WithAllowPartialResponses changes how a malformed response packet is
+// handled: instead of dropping the whole packet (reported as
+// ReasonMalformed), the Querier retries it via
+// message.ParseMessageWithOptions with Lenient parsing, keeping whatever
+// records parsed cleanly. This matters most on a segment with several
+// responders sharing one multicast packet's worth of collected answers,
+// where one bad record (a malformed TXT from a cheap device, say) would
+// otherwise cost every other responder's good answers too.
+//
+// Default: false - a malformed packet is always dropped in full, as before.
+//
+// Example:
+//
+//	q, _ := querier.New(querier.WithAllowPartialResponses(true))
+func WithAllowPartialResponses(enabled bool) Option {
+	return func(q *Querier) error {
+		q.allowPartialResponses = enabled
+		return nil
+	}
+}
+
+// WithKnownAnswerCache sets the capacity of the Querier's Known-Answer
+// cache, replacing the size-defaultKnownAnswerCacheSize cache New() creates
+// by default. Known-Answer suppression itself (RFC 6762 §7.1) is on by
+// default - this option only resizes the cache backing it - so this is for a
+// caller that expects far more or far fewer distinct (name, type, class)
+// tuples than the default comfortably holds, or that wants a fresh, empty
+// cache to pre-seed via Cache().Put before the Querier's first query. See
+// WithNoCache to disable the cache entirely.
+//
+// Named distinctly from Browser's WithCache(*CacheResolver), which installs
+// a different, pre-existing cache shape (absolute expiry, no per-record
+// original-TTL tracking) into a separate part of the package.
+//
+// Example:
+//
+//	q, _ := querier.New(querier.WithKnownAnswerCache(1000))
+func WithKnownAnswerCache(size int) Option {
+	return func(q *Querier) error {
+		if size <= 0 {
+			return &errors.ValidationError{
+				Field:   "cacheSize",
+				Value:   size,
+				Message: "size must be greater than 0",
+			}
+		}
+
+		q.knownAnswerCache = NewKnownAnswerCache(size)
+		return nil
+	}
+}
+
+// WithNoCache disables the Querier's Known-Answer cache entirely: Query
+// always hits the wire (never short-circuits from a cached answer), never
+// builds a Known-Answer suppression list, and never retains answers it
+// collects. Cache() returns nil once this is set.
+//
+// Default: unset - New() always starts with a cache (see
+// defaultKnownAnswerCacheSize, overridable via WithKnownAnswerCache).
+//
+// Example (a one-shot CLI tool that never reuses a Querier, where the cache
+// would only ever hold entries it never gets to use):
+//
+//	q, _ := querier.New(querier.WithNoCache())
+func WithNoCache() Option {
+	return func(q *Querier) error {
+		q.knownAnswerCache = nil
+		return nil
+	}
+}
+
+// WithUnicastResponse enables RFC 6762 §5.4's QU-bit behavior for Query
+// itself: the first unicastResponseQueryLimit calls to Query (not
+// QueryWithOptions or QueryUnicast, which already have their own explicit
+// Unicast controls) set the QU bit and collect replies over an ephemeral
+// unicast transport, the way QueryUnicast always does; every Query call
+// after that falls back to Query's ordinary multicast behavior, per the
+// RFC's "the first query... should be sent with QU set... Subsequent
+// queries... should be sent conventionally" guidance - useful on a network
+// with heavy mDNS chatter, where a unicast reply to this Querier's first
+// couple of startup queries avoids adding to the multicast noise everyone
+// else's cache has to filter through.
+//
+// Default: false - Query's established always-multicast behavior is
+// unchanged unless a caller opts in.
+//
+// This is what lets Beacon coexist well with Avahi/Bonjour/
+// systemd-resolved sharing port 5353 (see tests/integration's
+// TestAvahiCoexistence): a QU query draws one unicast reply straight back
+// to this process instead of a multicast response every other listener on
+// the segment also has to receive and filter.
+//
+// Example:
+//
+//	q, _ := querier.New(querier.WithUnicastResponse(true))
+func WithUnicastResponse(enabled bool) Option {
+	return func(q *Querier) error {
+		q.unicastResponseEnabled = enabled
+		return nil
+	}
+}
+
+// WithMetrics installs m as the Querier's metrics.Metrics sink, so every
+// query sent, response collected, Known-Answer cache hit/miss, and query's
+// end-to-end latency is reported to it instead of being discarded.
+//
+// Default: metrics.NoOp{} - no metrics are collected or computed unless a
+// caller opts in.
+//
+// Example:
+//
+//	collector := prom.NewCollector()
+//	q, _ := querier.New(querier.WithMetrics(collector))
+//	http.Handle("/metrics", collector)
+func WithMetrics(m metrics.Metrics) Option {
+	return func(q *Querier) error {
+		if m == nil {
+			return &errors.ValidationError{
+				Field:   "metrics",
+				Value:   nil,
+				Message: "metrics cannot be nil",
+			}
+		}
+
+		q.metrics = m
+		return nil
+	}
+}
+
+// WithMetricsNamespace wraps the Querier's current metrics.Metrics sink
+// (WithMetrics's m, or metrics.NoOp{} by default) in an internal/metrics
+// decorator that prefixes every metric name this Querier reports with
+// namespace + "_", so multiple Queriers sharing one process's metrics
+// backend (e.g. one per network interface) produce distinguishable series.
+//
+// Like WithLogger/WithLogBuffer elsewhere in Beacon, this is order-sensitive:
+// apply it after WithMetrics, since a WithMetrics call after this one
+// replaces the decorator wholesale rather than wrapping it further.
+// Composes with WithConstLabels in either order - each wraps whatever sink
+// the other left in place.
+func WithMetricsNamespace(namespace string) Option {
+	return func(q *Querier) error {
+		q.metrics = internalmetrics.New(q.metrics, internalmetrics.WithNamespace(namespace))
+		return nil
+	}
+}
+
+// WithConstLabels wraps the Querier's current metrics.Metrics sink in an
+// internal/metrics decorator that merges labels into every metric this
+// Querier reports, underneath whatever labels the call site itself passes -
+// useful for tagging every series with e.g. {"instance": "eth0"} without
+// threading that label through every IncCounter/ObserveHistogram call site
+// by hand.
+//
+// See WithMetricsNamespace for the same ordering caveat relative to
+// WithMetrics.
+func WithConstLabels(labels map[string]string) Option {
+	return func(q *Querier) error {
+		q.metrics = internalmetrics.New(q.metrics, internalmetrics.WithConstLabels(labels))
+		return nil
+	}
+}
+
+// WithLogger installs logger as the Querier's recovery logger, so a panic
+// recoverPanic catches in receiveLoop is logged via it instead of
+// slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(q *Querier) error {
+		if logger == nil {
+			return &errors.ValidationError{
+				Field:   "logger",
+				Value:   nil,
+				Message: "logger cannot be nil",
+			}
+		}
+
+		q.logger = logger
+		return nil
+	}
+}
+
+// WithPanicHandler registers handler to be called, in addition to the
+// standard slog/metrics reporting, whenever the Querier recovers a panic
+// while processing an inbound packet in receiveLoop - useful for wiring in
+// Sentry/OTel error tracking without patching the library.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(q *Querier) error {
+		if handler == nil {
+			return &errors.ValidationError{
+				Field:   "panicHandler",
+				Value:   nil,
+				Message: "panicHandler cannot be nil",
+			}
+		}
+
+		q.panicHandler = handler
+		return nil
+	}
+}
+
+// WithTap installs t as the Querier's tap.Tap, so every query it sends
+// and every response it receives is reported to t - independent of the
+// metrics/logger instrumentation above - for dnstap-style observability.
+// See the tap package for the SlogTap and FrameStreamTap built-in
+// implementations.
+//
+// Default: tap.NoOp{} - no events are reported unless a caller opts in.
+func WithTap(t tap.Tap) Option {
+	return func(q *Querier) error {
+		if t == nil {
+			return &errors.ValidationError{
+				Field:   "tap",
+				Value:   nil,
+				Message: "tap cannot be nil",
+			}
+		}
+
+		q.tap = t
+		return nil
+	}
+}
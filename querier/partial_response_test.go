@@ -0,0 +1,107 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// buildPartiallyMalformedResponse builds a raw mDNS response for
+// "host.local": a valid A answer, a PTR answer whose RDATA holds a
+// compression pointer into nowhere (RDLENGTH and RDATA are in-bounds, but
+// the embedded name can't be decompressed), and a second valid A answer
+// after it - message.ParseMessage fails on the whole packet, but
+// message.ParseMessageWithOptions's Lenient mode recovers both A answers.
+func buildPartiallyMalformedResponse() []byte {
+	header := []byte{
+		0x00, 0x00, // ID = 0 (mDNS response)
+		0x84, 0x00, // Flags: QR=1, AA=1
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x03, // ANCOUNT = 3
+		0x00, 0x00, // NSCOUNT
+		0x00, 0x00, // ARCOUNT
+	}
+	validA := []byte{
+		0x04, 'h', 'o', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 1,
+	}
+	badPTR := []byte{
+		0x04, 'h', 'o', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x0C, // TYPE = PTR (12)
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x02, // RDLENGTH = 2
+		0xC0, 0xFF, // RDATA: compression pointer far past the message end
+	}
+
+	msg := make([]byte, 0)
+	msg = append(msg, header...)
+	msg = append(msg, validA...)
+	msg = append(msg, badPTR...)
+	msg = append(msg, validA...)
+	return msg
+}
+
+// TestQuery_DropsMalformedResponseByDefault validates that
+// WithAllowPartialResponses's zero value preserves Query's existing
+// behavior: a response that fails ParseMessage is dropped in full.
+func TestQuery_DropsMalformedResponseByDefault(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	mock.QueueReceive(buildPartiallyMalformedResponse(), &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := q.Query(ctx, "host.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(resp.Records) != 0 {
+		t.Errorf("len(Records) = %d, want 0 (malformed response dropped in full)", len(resp.Records))
+	}
+}
+
+// TestQuery_AllowPartialResponsesRecoversCleanRecords validates that
+// WithAllowPartialResponses(true) recovers the valid A record(s) from a
+// response packet whose PTR record can't be decompressed, instead of
+// dropping the whole packet. Both A records in the fixture are identical, so
+// the Querier's usual cross-responder dedup collapses them to one.
+func TestQuery_AllowPartialResponsesRecoversCleanRecords(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock), WithAllowPartialResponses(true))
+	if err != nil {
+		t.Fatalf("New(WithTransport, WithAllowPartialResponses) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	mock.QueueReceive(buildPartiallyMalformedResponse(), &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := q.Query(ctx, "host.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(resp.Records) != 1 {
+		t.Fatalf("len(Records) = %d, want 1 (malformed PTR skipped, the A record recovered)", len(resp.Records))
+	}
+	if resp.Records[0].Name != "host.local" {
+		t.Errorf("Records[0].Name = %q, want %q", resp.Records[0].Name, "host.local")
+	}
+}
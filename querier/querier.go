@@ -4,17 +4,63 @@ import (
 	"context"
 	goerrors "errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/joshuafuller/beacon/internal/errors"
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/race"
 	"github.com/joshuafuller/beacon/internal/security"
 	"github.com/joshuafuller/beacon/internal/transport"
+	"github.com/joshuafuller/beacon/metrics"
+	"github.com/joshuafuller/beacon/tap"
 )
 
+// maxMDNSPacketSize is the default ceiling on a single raw response packet
+// per RFC 6762 §17, and the default for WithMaxResponseBytes().
+const maxMDNSPacketSize = 9000
+
+// defaultMaxUDPSize is the UDP payload size every outgoing query advertises
+// via EDNS(0) (RFC 6891) unless overridden by WithMaxUDPSize. 1440 fits
+// within a single untagged Ethernet frame (1500-byte MTU minus IP/UDP
+// headers) without fragmenting, while still comfortably exceeding the
+// traditional 512-byte DNS default.
+const defaultMaxUDPSize uint16 = 1440
+
+// defaultRaceDelay is how long a WithDualStack() query waits for an IPv4
+// answer before also sending on IPv6, per WithRaceDelay's default.
+const defaultRaceDelay = 300 * time.Millisecond
+
+// IPFamily selects which IP multicast transport(s) a Querier uses to send
+// and receive mDNS traffic.
+type IPFamily int
+
+const (
+	// IPv4Only sends and receives on 224.0.0.251:5353 only. This is the
+	// default, matching the Querier's original (pre-dual-stack) behavior.
+	IPv4Only IPFamily = iota
+
+	// IPv6Only sends and receives on [ff02::fb]:5353 only.
+	IPv6Only
+
+	// DualStack sends queries on both the IPv4 and IPv6 multicast groups and
+	// aggregates responses received on either into a single Response.
+	DualStack
+)
+
+// endpoint pairs a transport with the multicast destination address Query()
+// should send to on it. A DualStack Querier holds one endpoint per address
+// family since IPv4 and IPv6 mDNS use distinct multicast groups.
+type endpoint struct {
+	transport transport.Transport
+	dest      *net.UDPAddr
+}
+
 // Querier provides high-level mDNS query functionality.
 //
 // Querier manages a UDP multicast socket and background receiver goroutine
@@ -47,9 +93,16 @@ import (
 // This reduces struct size from 144 → 120 bytes (16.7% memory savings).
 // Related fields are still documented together via comments.
 type Querier struct {
-	// transport is the network transport abstraction (UDP multicast for mDNS)
+	// endpoints holds one transport+destination pair per address family in
+	// use (one for IPv4Only/IPv6Only, two for DualStack).
 	// T031: Migrated from socket net.PacketConn to transport.Transport interface
-	transport transport.Transport
+	endpoints []endpoint
+
+	// customTransport, when set via WithTransport, replaces the UDP
+	// multicast endpoint(s) newEndpoints would otherwise build - for
+	// injecting a MockTransport in tests, or a unicast transport.Transport
+	// (TCPTransport, DoTTransport, ...) for a non-multicast resolver.
+	customTransport transport.Transport
 
 	// ctx is the lifecycle context for the Querier
 	ctx context.Context
@@ -73,12 +126,19 @@ type Querier struct {
 	cancel context.CancelFunc
 
 	// responseChan receives incoming mDNS responses from the receiver goroutine
-	responseChan chan []byte
+	responseChan chan receivedPacket
 
 	// interfaceFilter is a custom interface selection function (if set)
 	// Used only if explicitInterfaces is nil
 	interfaceFilter func(net.Interface) bool
 
+	// allInterfaces is WithAllInterfaces's setting: join every interface
+	// net.Interfaces() returns, bypassing both explicitInterfaces and
+	// interfaceFilter (and the transports' own default
+	// DefaultInterfaces-style VPN/Docker/loopback exclusion). Used only if
+	// explicitInterfaces is nil.
+	allInterfaces bool
+
 	// rateLimiter is the rate limiter instance (created in New() if enabled)
 	rateLimiter *security.RateLimiter
 
@@ -86,12 +146,193 @@ type Querier struct {
 	// Per FR-027: Configurable via WithRateLimitThreshold()
 	rateLimitThreshold int
 
+	// maxResponseBytes caps the size of a single raw response packet the
+	// receiver will accept before dropping it, independent of parsing.
+	// Default: maxMDNSPacketSize (9000, RFC 6762 §17). Configurable via
+	// WithMaxResponseBytes() for callers that want a tighter ceiling
+	// against oversized or fragmented-jumbo-frame attack payloads.
+	maxResponseBytes int
+
 	// mu protects concurrent access to Query operations
 	mu sync.Mutex
 
 	// rateLimitEnabled indicates whether rate limiting is enabled (default: true)
 	// Per FR-033: Configurable via WithRateLimit()
 	rateLimitEnabled bool
+
+	// ipFamily selects which multicast transport(s) are created in New()
+	// (default: IPv4Only, preserving pre-dual-stack behavior)
+	ipFamily IPFamily
+
+	// raceDualStack, set via WithDualStack(), makes queryMulticastLocked send
+	// on the IPv4 endpoint first and only fall back to IPv6 if nothing
+	// answered within raceDelay, taking whichever family answers first -
+	// instead of WithIPFamily(DualStack)'s default of sending (and
+	// aggregating responses from) both simultaneously.
+	raceDualStack bool
+
+	// raceDelay is how long a raceDualStack query waits for a v4 answer
+	// before also sending on v6. Default: defaultRaceDelay. Configurable via
+	// WithRaceDelay().
+	raceDelay time.Duration
+
+	// ifaceWatcher polls for interface changes (Wi-Fi reassociation, a VPN
+	// coming up, a cable unplugged) when watchInterfaces is enabled. Nil
+	// otherwise.
+	ifaceWatcher transport.InterfaceWatcher
+
+	// watchInterfaces enables ifaceWatcher. Per FR-013/FR-017/FR-018-style
+	// defaults elsewhere in this package, disabled unless requested via
+	// WithWatchInterfaces, since it's a background goroutine most callers
+	// (short-lived CLI queries) don't need.
+	watchInterfaces bool
+
+	// watchSessions holds one entry per active Watch() call, keyed by
+	// "name|recordType". Protected by watchMu rather than mu, since Watch
+	// sessions run independently of (and must not block on) mu-guarded
+	// Query/QueryUnicast calls.
+	watchSessions map[string]*watchSession
+
+	// watchMu protects watchSessions.
+	watchMu sync.Mutex
+
+	// watchBackoff configures how a Watch() session retries after a
+	// transient transport error. Configurable via WithWatchBackoff();
+	// defaults to DefaultWatchBackoffPolicy().
+	watchBackoff WatchBackoffPolicy
+
+	// retryPolicy, when non-nil, makes Query() re-send and re-collect up to
+	// MaxAttempts times when an attempt collects zero records, per
+	// WithRetry(). Nil (the default) preserves Query's original
+	// single-attempt behavior exactly.
+	retryPolicy *RetryPolicy
+
+	// state is the Querier's current connectivity/lifecycle State, and
+	// stateNotify is closed (and replaced) on every transition so
+	// WaitForStateChange can block on it. Protected by stateMu rather than
+	// mu, since state observation must never block on (or be blocked by) a
+	// mu-guarded Query/QueryUnicast call.
+	state                 State
+	stateNotify           chan struct{}
+	stateMu               sync.Mutex
+	degradeReasons        [numDegradeReasons]bool
+	rateLimitRecoverTimer *time.Timer
+
+	// chanDropStreak counts consecutive responseChan sends that found it
+	// full (see receiveLoop). Accessed via sync/atomic since every
+	// receiveLoop goroutine (one per transport) shares the same
+	// responseChan and may update it concurrently.
+	chanDropStreak int32
+
+	// dropObserver, when non-nil, is invoked synchronously by receiveLoop and
+	// the response collectors for every discarded packet or record. Set via
+	// WithDropObserver; nil (the default) skips the DropEvent allocation
+	// entirely.
+	dropObserver func(DropEvent)
+
+	// maxUDPSize is the UDP payload size advertised in every outgoing
+	// query's EDNS(0) OPT record (RFC 6891 §6.1.2), letting a responder on
+	// a jumbo-frame segment reply with more than the traditional 512-byte
+	// default instead of hard-truncating per RFC 6762 §17. Default:
+	// defaultMaxUDPSize. Configurable via WithMaxUDPSize().
+	maxUDPSize uint16
+
+	// knownAnswerCache holds records this Querier has already seen, keyed
+	// by (name, type, class). Query short-circuits from it (skipping the
+	// wire entirely) when it holds a still-fresh answer, consults it before
+	// every outbound send to build an RFC 6762 §7.1 Known-Answer list, and
+	// populates it from every answer collectResponses/collectUnicastResponses
+	// accept. Created with defaultKnownAnswerCacheSize unless
+	// WithKnownAnswerCache overrides its size, or nil if WithNoCache was
+	// given. See Cache().
+	knownAnswerCache *KnownAnswerCache
+
+	// metrics receives counter/histogram observations at Query's key points
+	// (queries sent, responses collected, Known-Answer cache hits/misses,
+	// query latency) for an operator's own monitoring backend. Defaults to
+	// metrics.NoOp{} unless WithMetrics overrides it; nil only for a
+	// Querier built without New() (e.g. a zero-value &Querier{} in tests),
+	// which is why every call site guards on metricsActive() rather than
+	// using this field directly.
+	metrics metrics.Metrics
+
+	// logger receives recoverPanic's log record for a panic recovered from
+	// receiveLoop. Set via WithLogger, or defaults to slog.Default().
+	logger *slog.Logger
+
+	// panicHandler, set via WithPanicHandler, is called in addition to the
+	// standard slog/metrics reporting whenever recoverPanic recovers a
+	// panic. Nil (the default) disables this extra reporting.
+	panicHandler PanicHandler
+
+	// tap receives every query this Querier sends and every response it
+	// receives, for dnstap-style observability independent of the
+	// metrics/logger instrumentation above. Defaults to tap.NoOp{} unless
+	// WithTap overrides it; nil only for a Querier built without New(),
+	// which is why sendQuery/processReceivedPacketSafely guard on
+	// tapActive() rather than using this field directly.
+	tap tap.Tap
+
+	// lastUnicastQueryAt is when QueryWithOptions last actually sent a QU
+	// query, zero until the first one. RFC 6762 §5.4 reserves QU for the
+	// first query in a burst; QueryWithOptions consults this (guarded by mu,
+	// same as every other Query-family call) to fall back to an ordinary
+	// multicast query within unicastFallbackWindow of the last one, rather
+	// than tracking per-network-interface state this package's
+	// address-family-level endpoints don't expose.
+	lastUnicastQueryAt time.Time
+
+	// unicastResponseEnabled is WithUnicastResponse's setting: when true,
+	// Query itself (not just QueryWithOptions/QueryUnicast) sets the QU bit
+	// for its first unicastResponseQueryLimit calls, per RFC 6762 §5.4.
+	unicastResponseEnabled bool
+
+	// unicastQueryCount is how many of Query's own calls have gone out with
+	// the QU bit set so far, guarded by mu like every other Query-family
+	// call. Consulted (and incremented) only when unicastResponseEnabled is
+	// set; Query falls back to its ordinary multicast behavior once this
+	// reaches unicastResponseQueryLimit.
+	unicastQueryCount int
+
+	// allowPartialResponses is WithAllowPartialResponses's setting: when
+	// true, a response packet that fails message.ParseMessage is retried via
+	// message.ParseMessageWithOptions(..., message.ParseOptions{Lenient:
+	// true}) instead of being dropped outright, recovering whatever records
+	// parsed cleanly from a packet where only one responder's record (on a
+	// shared multicast segment with several responders) was malformed.
+	allowPartialResponses bool
+}
+
+// unicastResponseQueryLimit is how many of a process's Query calls
+// WithUnicastResponse(true) sets the QU bit for, matching RFC 6762 §5.4's
+// "the first query... subsequent queries..." guidance literally rather than
+// tracking a time window the way QueryWithOptions's unicastFallbackWindow
+// does.
+const unicastResponseQueryLimit = 2
+
+// metricsActive reports whether a real metrics sink is configured, so hot
+// paths (per-packet drop reporting, per-endpoint send counting) can skip
+// building label maps when the default metrics.NoOp{} - or a Querier built
+// without New(), whose metrics field is nil - would just discard them.
+func (q *Querier) metricsActive() bool {
+	if q.metrics == nil {
+		return false
+	}
+	_, isNoOp := q.metrics.(metrics.NoOp)
+	return !isNoOp
+}
+
+// tapActive reports whether a real Tap is configured, so the per-packet
+// call sites in sendQuery/processReceivedPacketSafely can skip calling
+// time.Now() and building OnQuery/OnResponse's arguments when the default
+// tap.NoOp{} - or a Querier built without New(), whose tap field is nil -
+// would just discard them.
+func (q *Querier) tapActive() bool {
+	if q.tap == nil {
+		return false
+	}
+	_, isNoOp := q.tap.(tap.NoOp)
+	return !isNoOp
 }
 
 // New creates a new Querier with optional configuration.
@@ -114,56 +355,251 @@ type Querier struct {
 //
 //	q, err := querier.New(querier.WithTimeout(2 * time.Second))
 func New(opts ...Option) (*Querier, error) {
-	// T032: Create UDP multicast transport (migrated from network.CreateSocket)
-	tr, err := transport.NewUDPv4Transport()
-	if err != nil {
-		return nil, err // Already wrapped as NetworkError
-	}
-
 	// Create lifecycle context
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create querier with defaults
 	q := &Querier{
-		transport:          tr,
-		defaultTimeout:     1 * time.Second,        // SC-002: discover devices within 1 second
-		responseChan:       make(chan []byte, 100), // Buffer for incoming responses
+		defaultTimeout:     1 * time.Second,                // SC-002: discover devices within 1 second
+		responseChan:       make(chan receivedPacket, 100), // Buffer for incoming responses
 		ctx:                ctx,
 		cancel:             cancel,
 		rateLimitEnabled:   true,             // FR-033: Default enabled
 		rateLimitThreshold: 100,              // FR-027: Default 100 qps
 		rateLimitCooldown:  60 * time.Second, // FR-028: Default 60s
+		maxResponseBytes:   maxMDNSPacketSize,
+		ipFamily:           IPv4Only,
+		raceDelay:          defaultRaceDelay,
+		watchSessions:      make(map[string]*watchSession),
+		watchBackoff:       DefaultWatchBackoffPolicy(),
+		state:              StateStarting,
+		stateNotify:        make(chan struct{}),
+		maxUDPSize:         defaultMaxUDPSize,
+		knownAnswerCache:   NewKnownAnswerCache(defaultKnownAnswerCacheSize),
+		metrics:            metrics.NoOp{},
+		logger:             slog.Default(),
+		tap:                tap.NoOp{},
 	}
 
-	// Apply options
+	// Apply options (including WithIPFamily, which decides which transports
+	// newEndpoints builds below)
 	for _, opt := range opts {
 		if err := opt(q); err != nil {
-			cancel()       // Clean up context before returning error
-			_ = tr.Close() // Ignore error, already returning primary error
+			cancel() // Clean up context before returning error
 			return nil, err
 		}
 	}
 
+	// T032: Create the UDP multicast transport(s) for the configured
+	// IPFamily (migrated from network.CreateSocket), unless WithTransport
+	// supplied one already - a caller that injects its own transport is
+	// opting out of multicast group membership entirely (test isolation,
+	// or a unicast-only transport such as TCPTransport/DoTTransport).
+	if q.customTransport != nil {
+		q.endpoints = []endpoint{{transport: q.customTransport, dest: nil}}
+	} else {
+		ifaces, err := q.resolveInterfaces()
+		if err != nil {
+			cancel()
+			return nil, err // Already wrapped as NetworkError
+		}
+		endpoints, err := newEndpoints(q.ipFamily, ifaces)
+		if err != nil {
+			cancel()
+			return nil, err // Already wrapped as NetworkError
+		}
+		q.endpoints = endpoints
+	}
+
 	// Initialize rate limiter if enabled (after options applied)
 	if q.rateLimitEnabled {
-		q.rateLimiter = security.NewRateLimiter(
-			q.rateLimitThreshold,
-			q.rateLimitCooldown,
-			10000, // Max 10,000 source IPs tracked
-		)
+		// q itself is the event bus's sink (see HandleSecurityEvent in
+		// state.go), so a cooldown entering for any source reports
+		// degradeRateLimitCooldown without callers having to wire anything.
+		q.rateLimiter = security.NewRateLimiter(security.RateLimiterConfig{
+			Burst:            float64(q.rateLimitThreshold),
+			SustainedQPS:     float64(q.rateLimitThreshold),
+			IPv4CIDR:         32, // No aggregation: one bucket per exact source IP
+			IPv6CIDR:         128,
+			CooldownDuration: q.rateLimitCooldown,
+			MaxEntries:       10000, // Max 10,000 source IPs tracked
+		}, security.WithEventBus(security.NewSecurityEventBus(q)))
 
 		// Start periodic cleanup goroutine (every 5 minutes per FR-031)
 		q.wg.Add(1)
 		go q.cleanupLoop()
 	}
 
-	// Start background receiver goroutine per FR-006
-	q.wg.Add(1)
-	go q.receiveLoop()
+	// Start one background receiver goroutine per transport per FR-006
+	for _, ep := range q.endpoints {
+		q.wg.Add(1)
+		go q.receiveLoop(ep.transport)
+	}
 
+	// Start the interface watcher last, once the Querier is otherwise fully
+	// initialized, so InterfaceChanges() never returns a channel for a
+	// watcher that might still fail to spin up.
+	if q.watchInterfaces {
+		watcher, err := newInterfaceWatcher()
+		if err != nil {
+			cancel()
+			q.wg.Wait()
+			for _, ep := range q.endpoints {
+				_ = ep.transport.Close()
+			}
+			return nil, err
+		}
+		q.ifaceWatcher = watcher
+		q.startRebinding()
+	}
+
+	q.setState(StateReady)
 	return q, nil
 }
 
+// InterfaceChanges returns the channel of interface up/down transitions
+// reported by the background watcher started via WithWatchInterfaces, or
+// nil if watching wasn't enabled.
+//
+// Each reported interface has already been re-filtered through
+// network.DefaultInterfaces's VPN/Docker/loopback exclusion, so a freshly
+// connected utun0 never shows up here as an InterfaceUp.
+func (q *Querier) InterfaceChanges() <-chan transport.InterfaceChange {
+	if q.ifaceWatcher == nil {
+		return nil
+	}
+	return q.ifaceWatcher.Changes()
+}
+
+// Cache returns the Querier's Known-Answer cache - populated automatically
+// from every answer Query collects, and consulted before every outbound
+// query to build its RFC 6762 §7.1 Known-Answer list. Call Cache().Put to
+// pre-seed an entry (e.g. from a prior session) before the first query for
+// it goes out, or Cache().Entries() to inspect what's currently cached.
+// Returns nil if WithNoCache disabled the cache.
+func (q *Querier) Cache() *KnownAnswerCache {
+	return q.knownAnswerCache
+}
+
+// FlushCache discards every entry in the Querier's Known-Answer cache, so
+// the next Query for any name is guaranteed to hit the wire rather than
+// short-circuit from (or suppress a responder's reply with) a now-unwanted
+// cached answer. A no-op if WithNoCache disabled the cache.
+//
+// This is a coarser tool than the cache's own TTL expiry and RFC 6762
+// §10.1/§10.2 goodbye/cache-flush handling, which already retire individual
+// entries on their own - FlushCache exists for a caller that knows its own
+// cached data is stale for a reason the cache itself can't observe (e.g. the
+// local network just changed).
+func (q *Querier) FlushCache() {
+	if q.knownAnswerCache != nil {
+		q.knownAnswerCache.Flush()
+	}
+}
+
+// resolveInterfaces turns q's explicitInterfaces/interfaceFilter/
+// allInterfaces settings into the concrete interface list newEndpoints
+// should join the multicast group on, or nil to leave each transport's own
+// default filtering (network.DefaultInterfaces's VPN/Docker/loopback
+// exclusion) in charge. explicitInterfaces (WithInterfaces) wins over
+// allInterfaces (WithAllInterfaces), which wins over interfaceFilter
+// (WithInterfaceFilter), matching the fields' own doc comments.
+func (q *Querier) resolveInterfaces() ([]net.Interface, error) {
+	if len(q.explicitInterfaces) > 0 {
+		return q.explicitInterfaces, nil
+	}
+
+	if q.allInterfaces {
+		ifaces, err := net.Interfaces()
+		if err != nil {
+			return nil, &errors.NetworkError{
+				Operation: "list interfaces",
+				Err:       err,
+				Details:   "failed to enumerate interfaces for WithAllInterfaces",
+			}
+		}
+		return ifaces, nil
+	}
+
+	if q.interfaceFilter == nil {
+		return nil, nil
+	}
+
+	all, err := net.Interfaces()
+	if err != nil {
+		return nil, &errors.NetworkError{
+			Operation: "list interfaces",
+			Err:       err,
+			Details:   "failed to enumerate interfaces for WithInterfaceFilter",
+		}
+	}
+
+	filtered := make([]net.Interface, 0, len(all))
+	for _, ifi := range all {
+		if q.interfaceFilter(ifi) {
+			filtered = append(filtered, ifi)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, &errors.NetworkError{
+			Operation: "list interfaces",
+			Err:       fmt.Errorf("no interfaces matched"),
+			Details:   "WithInterfaceFilter rejected every available interface",
+		}
+	}
+	return filtered, nil
+}
+
+// newEndpoints creates the transport(s) and their multicast destination
+// addresses for the given IPFamily. ifaces, if non-nil, is joined on
+// exactly those interfaces (see resolveInterfaces); nil leaves each
+// transport's own default interface selection in charge. On partial failure
+// (DualStack with one family unavailable) it closes whatever it already
+// opened before returning the error, so New() never leaks a
+// half-constructed socket.
+func newEndpoints(family IPFamily, ifaces []net.Interface) ([]endpoint, error) {
+	var endpoints []endpoint
+
+	if family == IPv4Only || family == DualStack {
+		var tr *transport.UDPv4Transport
+		var err error
+		if ifaces != nil {
+			tr, err = transport.NewUDPv4TransportWithInterfaces(ifaces)
+		} else {
+			tr, err = transport.NewUDPv4Transport()
+		}
+		if err != nil {
+			return nil, err // Already wrapped as NetworkError
+		}
+		endpoints = append(endpoints, endpoint{
+			transport: tr,
+			dest:      &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353},
+		})
+	}
+
+	if family == IPv6Only || family == DualStack {
+		var tr *transport.UDPv6Transport
+		var err error
+		if ifaces != nil {
+			tr, err = transport.NewUDPv6TransportWithInterfaces(ifaces)
+		} else {
+			tr, err = transport.NewUDPv6Transport()
+		}
+		if err != nil {
+			for _, ep := range endpoints {
+				_ = ep.transport.Close()
+			}
+			return nil, err // Already wrapped as NetworkError
+		}
+		endpoints = append(endpoints, endpoint{
+			transport: tr,
+			dest:      protocol.MulticastGroupIPv6(""),
+		})
+	}
+
+	return endpoints, nil
+}
+
 // Query sends an mDNS query and returns all responses received within the timeout.
 //
 // Query validates inputs, builds the query message, sends it to the multicast group,
@@ -202,14 +638,31 @@ func New(opts ...Option) (*Querier, error) {
 //	    fmt.Printf("Found: %s → %v\n", record.Name, record.Data)
 //	}
 func (q *Querier) Query(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	// Metrics: count every call and time the full round trip, regardless of
+	// outcome, so a caller can see both query volume and latency (including
+	// the timeout-dominated common case) per record type. Guarded by
+	// metricsActive so a Querier with no WithMetrics sink never pays for the
+	// label map or the extra time.Now() call.
+	if q.metricsActive() {
+		metricLabels := map[string]string{"record_type": recordType.String()}
+		start := time.Now()
+		q.metrics.IncCounter("beacon_querier_queries_total", metricLabels)
+		defer func() {
+			q.metrics.ObserveHistogram("beacon_querier_query_duration_seconds", time.Since(start).Seconds(), metricLabels)
+		}()
+	}
+
 	// Protect concurrent query operations
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	// Check context cancellation upfront
+	// Check context cancellation upfront. context.Cause reports *why* ctx
+	// ended - a caller's WithCancelCause(err) reason, not just the generic
+	// context.Canceled/DeadlineExceeded - falling back to ctx.Err() itself
+	// when no cause was set, per context.Cause's own documented behavior.
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return nil, context.Cause(ctx)
 	default:
 	}
 
@@ -225,25 +678,237 @@ func (q *Querier) Query(ctx context.Context, name string, recordType RecordType)
 		return nil, err // Already wrapped as ValidationError
 	}
 
-	// FR-001: Build query message
-	queryMsg, err := message.BuildQuery(name, uint16(recordType))
+	// Short-circuit from the cache when it already holds a still-fresh
+	// answer - RFC 6762 §7.1's freshness rule, extended from "worth sending
+	// as a Known-Answer" to "worth answering from directly": a cache entry
+	// more than half through its TTL is treated as good enough to skip the
+	// network entirely, the same threshold knownAnswers uses to decide what
+	// belongs in a suppression list.
+	if q.knownAnswerCache != nil {
+		if cached := q.knownAnswerCache.FreshAnswers(name, recordType, uint16(protocol.ClassIN)); len(cached) > 0 {
+			if q.metricsActive() {
+				q.metrics.IncCounter("beacon_querier_cache_short_circuit_total", map[string]string{"record_type": recordType.String()})
+			}
+			return &Response{Records: cached, Status: StatusOK}, nil
+		}
+	}
+
+	// Per WithUnicastResponse(true): the first unicastResponseQueryLimit
+	// calls to Query go out with the QU bit set, the same path QueryUnicast
+	// always uses, before falling back to Query's ordinary multicast
+	// behavior for every call after that.
+	if q.unicastResponseEnabled && q.unicastQueryCount < unicastResponseQueryLimit {
+		q.unicastQueryCount++
+		return q.queryUnicastLocked(ctx, name, recordType, false)
+	}
+
+	// Per WithRetry(): re-send and re-collect up to MaxAttempts times when
+	// an attempt collects zero records, instead of the single-attempt path
+	// below. The retry path always sends a plain query with no Known-Answer
+	// list: resending the same list on every attempt would keep suppressing
+	// the very responses WithRetry is trying to elicit.
+	if q.retryPolicy != nil {
+		queryMsg, buildErr := message.BuildQuery(name, uint16(recordType), q.ednsQueryOptions()...)
+		if buildErr != nil {
+			return nil, buildErr
+		}
+		return q.queryWithRetry(ctx, queryMsg, name, recordType)
+	}
+
+	return q.queryMulticastLocked(ctx, name, recordType, false)
+}
+
+// queryMulticastLocked performs Query's send-and-collect work once
+// q.retryPolicy is known not to apply. Callers must already hold q.mu and
+// have validated name/recordType. It exists as its own method, mirroring
+// queryUnicastLocked, so QueryWithOptions can reuse it when opts.Unicast
+// falls back to a multicast query without duplicating Query's body.
+func (q *Querier) queryMulticastLocked(ctx context.Context, name string, recordType RecordType, firstAnswerWins bool) (*Response, error) {
+	// FR-001: Build query message, consulting the Known-Answer cache per
+	// RFC 6762 §7.1 first so a responder already holding the same data can
+	// suppress its own reply.
+	var knownAnswers []*message.ResourceRecord
+	if q.knownAnswerCache != nil {
+		knownAnswers = q.knownAnswerCache.knownAnswers(name, recordType, uint16(protocol.ClassIN))
+	}
+	if q.metricsActive() {
+		result := "hit"
+		if len(knownAnswers) == 0 {
+			result = "miss"
+		}
+		q.metrics.IncCounter("beacon_querier_known_answer_cache_total", map[string]string{"result": result})
+	}
+
+	var packets [][]byte
+	if len(knownAnswers) == 0 {
+		queryMsg, buildErr := message.BuildQuery(name, uint16(recordType), q.ednsQueryOptions()...)
+		if buildErr != nil {
+			return nil, buildErr
+		}
+		packets = [][]byte{queryMsg}
+	} else {
+		built, buildErr := q.buildKnownAnswerQuery(name, recordType, knownAnswers)
+		if buildErr != nil {
+			return nil, buildErr
+		}
+		packets = built
+	}
+
+	// Parsed back out purely so collectResponses can correlate replies
+	// against it via message.ValidateResponse - every packet in packets
+	// shares the same question and transaction ID (see
+	// message.QueryBuilder), so the first is representative.
+	parsedQuery, err := message.ParseMessage(packets[0])
 	if err != nil {
 		return nil, err
 	}
 
-	// FR-005: Send query to multicast group
-	// T033: Migrated from network.SendQuery to transport.Send()
-	mdnsAddr := &net.UDPAddr{
-		IP:   net.IPv4(224, 0, 0, 251),
-		Port: 5353,
+	// WithDualStack()'s racing send only applies to the common single-packet
+	// case - a Known-Answer list split across several packets (rare: it
+	// needs a cache large enough that MaxKnownAnswersPerPacket is exceeded)
+	// falls through to the ordinary simultaneous-send path below instead of
+	// teaching the follow-up sender which family won the race.
+	if q.raceDualStack && len(q.endpoints) == 2 && len(packets) == 1 {
+		return q.queryMulticastRaced(ctx, name, recordType, packets[0], parsedQuery)
 	}
-	err = q.transport.Send(ctx, queryMsg, mdnsAddr)
-	if err != nil {
-		return nil, err // Already wrapped as NetworkError
+
+	// FR-005: Send query to the multicast group(s) for the configured
+	// IPFamily. T033: Migrated from network.SendQuery to transport.Send()
+	if err := q.sendQuery(ctx, packets[0]); err != nil {
+		return nil, err
+	}
+
+	// RFC 6762 §7.2: a Known-Answer list too large for one packet sets TC
+	// on every packet but the last; send those follow-ups within the
+	// 400-1000ms window a responder that deferred its reply is waiting out.
+	if err := q.sendKnownAnswerFollowups(ctx, packets[1:]); err != nil {
+		return nil, err
 	}
 
 	// FR-008: Aggregate responses received within timeout window
-	return q.collectResponses(ctx, name, recordType)
+	return q.collectResponses(ctx, name, recordType, parsedQuery, firstAnswerWins)
+}
+
+// queryMulticastRaced implements WithDualStack()'s racing behavior: send
+// queryMsg on the IPv4 endpoint immediately, and - unless an answer already
+// arrived - send it on IPv6 too after q.raceDelay, taking whichever family
+// answers first via race.Race.
+//
+// Both attempts run their own collectResponses call with firstAnswerWins
+// forced on, but against the same q.responseChan: an IPv6 responder's packet
+// is just as welcome to the IPv4 attempt's collectResponses call as to IPv6's
+// own, since both are collecting toward the identical name/recordType
+// question - only whichever attempt's collectResponses returns first
+// matters, and race.Race cancels the other (which, if it hadn't started
+// sending yet, means it never sends at all). This trades WithIPFamily's
+// DualStack's every-responder aggregation for lower latency and, on the
+// common case of a network where IPv4 always gets answered, one fewer
+// multicast transmission.
+func (q *Querier) queryMulticastRaced(ctx context.Context, name string, recordType RecordType, queryMsg []byte, parsedQuery *message.DNSMessage) (*Response, error) {
+	v4, v6 := q.endpoints[0], q.endpoints[1]
+	metricsActive, tapActive := q.metricsActive(), q.tapActive()
+
+	attempt := func(ep endpoint) func(context.Context) (*Response, error) {
+		return func(attemptCtx context.Context) (*Response, error) {
+			if err := q.sendToEndpoint(attemptCtx, ep, queryMsg, metricsActive, tapActive); err != nil {
+				return nil, err
+			}
+			return q.collectResponses(attemptCtx, name, recordType, parsedQuery, true)
+		}
+	}
+
+	return race.Race(ctx, q.raceDelay, attempt(v4), attempt(v6))
+}
+
+// buildKnownAnswerQuery builds the query packet(s) carrying knownAnswers in
+// the Answer section per RFC 6762 §7.1. A list small enough to fit one
+// packet under the Querier's advertised maxUDPSize is a single
+// message.BuildQueryWithKnownAnswers packet; a larger list is split via
+// message.QueryBuilder, every packet but the last carrying TC per RFC 6762
+// §7.2.
+func (q *Querier) buildKnownAnswerQuery(name string, recordType RecordType, knownAnswers []*message.ResourceRecord) ([][]byte, error) {
+	if message.MaxKnownAnswersPerPacket(int(q.maxUDPSize)) >= len(knownAnswers) {
+		packet, err := message.BuildQueryWithKnownAnswers(name, uint16(recordType), knownAnswers)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{packet}, nil
+	}
+
+	qb, err := message.NewQueryBuilder(name, uint16(recordType), int(q.maxUDPSize))
+	if err != nil {
+		return nil, err
+	}
+	return qb.Build(knownAnswers)
+}
+
+// knownAnswerFollowupInterval is the delay between a split Known-Answer
+// query's packets, within the 400-1000ms window RFC 6762 §7.2 recommends so
+// a responder that deferred its reply waiting for the rest of the list
+// doesn't wait indefinitely.
+const knownAnswerFollowupInterval = 400 * time.Millisecond
+
+// sendKnownAnswerFollowups sends each of packets in turn, pausing
+// knownAnswerFollowupInterval between sends per RFC 6762 §7.2. A context
+// cancellation aborts early without error - the caller already sent the
+// first packet and has something to collect against regardless.
+func (q *Querier) sendKnownAnswerFollowups(ctx context.Context, packets [][]byte) error {
+	for _, packet := range packets {
+		timer := time.NewTimer(knownAnswerFollowupInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		if err := q.sendQuery(ctx, packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendQuery sends queryMsg to the multicast group(s) for the configured
+// IPFamily, returning an error only if every transport failed to send -
+// nothing was asked, so that's surfaced rather than silently collecting
+// zero responses.
+func (q *Querier) sendQuery(ctx context.Context, queryMsg []byte) error {
+	metricsActive := q.metricsActive()
+	tapActive := q.tapActive()
+	var sendErrs []error
+	for _, ep := range q.endpoints {
+		if sendErr := q.sendToEndpoint(ctx, ep, queryMsg, metricsActive, tapActive); sendErr != nil {
+			sendErrs = append(sendErrs, sendErr)
+		}
+	}
+	if len(sendErrs) == len(q.endpoints) {
+		return goerrors.Join(sendErrs...) // Already wrapped as NetworkError
+	}
+	return nil
+}
+
+// sendToEndpoint sends queryMsg to a single endpoint, recording the same
+// metrics/tap observations sendQuery's send-to-every-endpoint loop does -
+// metricsActive/tapActive are passed in rather than read from q so a caller
+// sending to several endpoints (sendQuery) only pays for the interface type
+// assertions once. Shared by sendQuery and queryMulticastRaced, which sends
+// to endpoints individually rather than all at once.
+func (q *Querier) sendToEndpoint(ctx context.Context, ep endpoint, queryMsg []byte, metricsActive, tapActive bool) error {
+	if err := ep.transport.Send(ctx, queryMsg, ep.dest); err != nil {
+		return err
+	}
+	if metricsActive {
+		family := "ipv4"
+		if ep.dest != nil && ep.dest.IP.To4() == nil {
+			family = "ipv6"
+		}
+		q.metrics.IncCounter("beacon_querier_queries_sent_total", map[string]string{"family": family})
+	}
+	if tapActive {
+		q.tap.OnQuery(queryMsg, nil, ep.dest, time.Now())
+	}
+	return nil
 }
 
 // collectResponses aggregates mDNS responses within the timeout window.
@@ -254,85 +919,705 @@ func (q *Querier) Query(ctx context.Context, name string, recordType RecordType)
 // FR-010: Filter answer section records
 // FR-011: Validate and discard malformed packets
 // FR-016: Continue collecting after discarding malformed packets
-func (q *Querier) collectResponses(ctx context.Context, _ string, queryType RecordType) (*Response, error) {
+//
+// firstAnswerWins, set via QueryWithOptions's QueryOptions.FirstAnswerWins,
+// returns as soon as a packet contributes at least one new matching record
+// instead of waiting out ctx's full deadline - trading FR-008's
+// every-responder aggregation for lower latency on the common case of a
+// single responder. A TC=1 escalation still runs to completion first (the
+// unicast retry is mid-flight by the time a first matching record could be
+// recognized, and it may itself be what supplies that record).
+func (q *Querier) collectResponses(ctx context.Context, name string, queryType RecordType, parsedQuery *message.DNSMessage, firstAnswerWins bool) (*Response, error) {
 	response := &Response{
 		Records: make([]ResourceRecord, 0),
 	}
 
 	// Deduplication map per FR-007
 	seen := make(map[string]bool)
+	seenAdditional := make(map[string]bool)
+
+	// Guards retryTruncatedOverUnicast so at most one unicast escalation
+	// happens per Query call, no matter how many TC=1 packets arrive.
+	retriedUnicast := false
+
+	// nxdomain and partial feed Response.Status at the end: nxdomain is set
+	// by an NSEC record proving name doesn't exist (RFC 6762 §6.1); partial
+	// is set if a TC=1 escalation's unicast-reply retry didn't finish
+	// draining its per-transport goroutines before ctx ended.
+	nxdomain := false
+	partial := false
 
 	// Collect responses until timeout or cancellation
 	for {
 		select {
 		case <-ctx.Done():
+			response.Status = finalStatus(response, nxdomain, partial)
 			// Timeout is NOT an error per FR-008 - return what we collected
 			return response, nil
 
-		case responseMsg := <-q.responseChan:
+		case pkt := <-q.responseChan:
+			responseMsg := pkt.data
 			// FR-009: Parse response message
 			parsedMsg, err := message.ParseMessage(responseMsg)
 			if err != nil {
-				// FR-011, FR-016: Log and continue on malformed packets
-				// In M1, we silently continue (production might log)
-				continue
+				if q.allowPartialResponses {
+					// A *errors.MultiError here still carries a usable
+					// parsedMsg - only a nil parsedMsg (a Header or
+					// Question failure, which Lenient can't resync past)
+					// means nothing was recovered.
+					parsedMsg, err = message.ParseMessageWithOptions(responseMsg, message.ParseOptions{Lenient: true})
+					if parsedMsg != nil {
+						err = nil
+					}
+				}
+				if err != nil {
+					// FR-011, FR-016: discard malformed packets and keep collecting
+					q.reportDrop(ReasonMalformed, nil, name)
+					continue
+				}
 			}
 
 			// FR-021, FR-022: Validate response flags
 			err = protocol.ValidateResponse(parsedMsg.Header.Flags)
 			if err != nil {
-				// Invalid response (QR=0 or RCODE≠0) - discard per FR-011
+				// Invalid response (QR=0 or RCODE≠0) - discard per FR-011,
+				// but a non-zero RCODE is itself informative (see Rcode's
+				// doc comment) even though the packet carrying it is never
+				// otherwise used.
+				if recordRcode(response, parsedMsg) {
+					nxdomain = true
+				}
+				q.reportDrop(ReasonMalformed, nil, name)
 				continue
 			}
 
+			// Guard against transaction-ID/question confusion: a stray
+			// mDNS response with a non-zero ID, or one that answers none
+			// of this query's questions, isn't a reply to parsedQuery.
+			if err := message.ValidateResponse(parsedQuery, parsedMsg, false); err != nil {
+				q.reportDrop(ReasonUnrelatedResponse, nil, name)
+				continue
+			}
+
+			if q.metricsActive() {
+				q.metrics.IncCounter("beacon_querier_responses_total", map[string]string{"record_type": queryType.String()})
+			}
+
 			// FR-010: Process only Answer section (ignore Authority, Additional)
-			for _, answer := range parsedMsg.Answers {
-				// Filter by query type (optional - could also return all types)
-				if RecordType(answer.TYPE) != queryType {
-					// Skip records of different type
-					// (Production might include related records)
-					continue
+			before := len(response.Records)
+			appendMatchingAnswers(response, seen, parsedMsg, name, queryType, q.knownAnswerCache, pkt.iface, func(reason DropReason) {
+				q.reportDrop(reason, nil, name)
+			})
+			appendAdditionals(response, seenAdditional, parsedMsg, pkt.iface)
+
+			if nsecAssertsNonexistence(parsedMsg, name, queryType) {
+				nxdomain = true
+			}
+
+			// Surface the first EDNS(0) OPT record (RFC 6891 §6.1.2) a
+			// responder sent, e.g. to confirm its negotiated UDP payload
+			// size or read back an echoed NSID/cookie option.
+			if parsedMsg.OPT != nil && response.OPT == nil {
+				response.OPT = parsedMsg.OPT
+			}
+
+			if firstAnswerWins && len(response.Records) > before && !parsedMsg.Header.Truncated {
+				response.Status = StatusOK
+				return response, nil
+			}
+
+			// RFC 6762 §18.5 / RFC 1035 §4.1.1: TC=1 says more records exist
+			// than this packet carried. Escalate once per Query call to the
+			// unicast-reply path, mirroring the retry-on-truncated pattern
+			// mainstream DNS resolvers use against TCP - mDNS has no TCP
+			// transport, so the unicast-reply query is Beacon's equivalent
+			// "ask again on a channel that isn't packet-size-limited".
+			if parsedMsg.Header.Truncated && !retriedUnicast {
+				retriedUnicast = true
+				retryNXDomain, retryPartial := q.retryTruncatedOverUnicast(ctx, response, seen, seenAdditional, name, queryType)
+				if retryNXDomain {
+					nxdomain = true
 				}
+				if retryPartial {
+					partial = true
+				}
+			}
+		}
+	}
+}
+
+// finalStatus derives a Response's terminal ResponseStatus once collection
+// stops: StatusNXDomain takes priority (a definitive negative answer is
+// worth reporting even if some unrelated records also came back),
+// StatusNoAnswer when nothing was collected at all, StatusPartial when a
+// truncated reply's unicast retry didn't finish before ctx ended, and
+// StatusOK otherwise.
+func finalStatus(response *Response, nxdomain, partial bool) ResponseStatus {
+	switch {
+	case nxdomain:
+		return StatusNXDomain
+	case len(response.Records) == 0:
+		return StatusNoAnswer
+	case partial:
+		return StatusPartial
+	default:
+		return StatusOK
+	}
+}
+
+// nsecAssertsNonexistence reports whether parsedMsg carries an NSEC record
+// (RFC 4034 §4.1, used per RFC 6762 §6.1 for mDNS negative responses) owned
+// by name whose type bitmap excludes queryType - i.e., a responder
+// authoritatively asserting that no record of that type exists at name.
+// RecordTypeANY has no single bit to check against an NSEC bitmap, so it
+// never reports a negative answer this way.
+func nsecAssertsNonexistence(parsedMsg *message.DNSMessage, name string, queryType RecordType) bool {
+	if queryType == RecordTypeANY {
+		return false
+	}
+
+	for _, section := range [][]message.Answer{parsedMsg.Answers, parsedMsg.Authorities} {
+		for _, a := range section {
+			if a.TYPE != uint16(protocol.RecordTypeNSEC) || !strings.EqualFold(a.NAME, name) {
+				continue
+			}
+
+			nsec, err := message.ParseNSEC(a.RDATA)
+			if err != nil {
+				continue
+			}
+
+			covered := false
+			for _, t := range nsec.TypeBitMap {
+				if t == uint16(queryType) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryTruncatedOverUnicast re-issues name/queryType as an RFC 6762 §5.4
+// unicast-reply query after collectResponses saw a TC=1 multicast response,
+// merging any new answers into response. Errors and empty results are
+// swallowed: the caller already has whatever the multicast response
+// collected, and a failed retry shouldn't turn a partial answer into a
+// query failure. Callers must already hold q.mu (collectResponses only
+// runs while Query holds it).
+//
+// It reports whether the unicast retry itself concluded with StatusNXDomain
+// (an authoritative nonexistence proof) or StatusPartial (its per-transport
+// goroutines hadn't all drained before ctx ended), so collectResponses can
+// fold either into its own Response.Status rather than silently reporting
+// StatusOK - or losing an NXDOMAIN - on the merge.
+func (q *Querier) retryTruncatedOverUnicast(ctx context.Context, response *Response, seen, seenAdditional map[string]bool, name string, queryType RecordType) (nxdomain, partial bool) {
+	retryCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	unicastResp, err := q.queryUnicastLocked(retryCtx, name, queryType, false)
+	if err != nil || unicastResp == nil {
+		return false, false
+	}
+
+	for _, rec := range unicastResp.Records {
+		dedupeKey := dedupeKeyFor(rec.Name, uint16(rec.Type), rec.Data)
+		if seen[dedupeKey] {
+			continue
+		}
+		seen[dedupeKey] = true
+		response.Records = append(response.Records, rec)
+	}
+	for _, rec := range unicastResp.Additionals {
+		dedupeKey := dedupeKeyFor(rec.Name, uint16(rec.Type), rec.Data)
+		if seenAdditional[dedupeKey] {
+			continue
+		}
+		seenAdditional[dedupeKey] = true
+		response.Additionals = append(response.Additionals, rec)
+	}
+	if unicastResp.Rcode != 0 {
+		response.Rcode = unicastResp.Rcode
+	}
+
+	return unicastResp.Status == StatusNXDomain, unicastResp.Status == StatusPartial
+}
+
+// recordRcode copies parsedMsg's RCODE into response.Rcode if non-zero and
+// reports whether it was RCodeNameError (RFC 1035 §4.1.1 NXDOMAIN) - shared
+// between collectResponses and collectUnicastResponses so their RCODE
+// handling can't drift.
+func recordRcode(response *Response, parsedMsg *message.DNSMessage) bool {
+	rcode := parsedMsg.Header.GetRCODE()
+	if rcode == 0 {
+		return false
+	}
+	response.Rcode = rcode
+	return uint16(rcode) == protocol.RCodeNameError
+}
+
+// dedupeKeyFor returns the name+type+data key collectResponses and Multiplex
+// use to recognize the same record reported more than once, whether that's
+// two responders answering the same query or two different Resolvers
+// reporting the same host.
+func dedupeKeyFor(name string, recordType uint16, data interface{}) string {
+	return fmt.Sprintf("%s|%d|%v", name, recordType, data)
+}
+
+// appendMatchingAnswers validates, filters, and deduplicates a parsed
+// message's Answer section against name/queryType, appending survivors to
+// response.Records. It is shared between collectResponses (multicast) and
+// collectUnicastResponses (RFC 6762 §5.4 QU-bit replies) so the two response
+// paths can't drift in what they accept. report, if non-nil, is called for
+// every answer discarded for a reason WithDropObserver callers care about
+// (malformed RDATA, wrong record type); the anti-spoofing name mismatch below
+// isn't reported since it isn't one of the DropReason enum's cases.
+//
+// cache, if non-nil, is populated with every answer that passes the name
+// check - regardless of queryType - so a later Query for the same or a
+// different record type on this name can still build a Known-Answer list
+// from it (RFC 6762 §7.1). LegacyResolver, a plain unicast DNS client with
+// no Known-Answer concept of its own, passes nil.
+//
+// iface is the network interface the packet carrying parsedMsg arrived on
+// (nil if the transport couldn't report one, or for a unicast response) -
+// copied onto every surviving ResourceRecord.Interface.
+func appendMatchingAnswers(response *Response, seen map[string]bool, parsedMsg *message.DNSMessage, name string, queryType RecordType, cache *KnownAnswerCache, iface *net.Interface, report func(DropReason)) {
+	for _, answer := range parsedMsg.Answers {
+		// Reject answers whose owner name doesn't match the question
+		// we asked. Answer-section records are never additional-section
+		// glue, so there's no legitimate reason for a mismatch here -
+		// this is how an off-path attacker would try to inject a
+		// spoofed record for an unrelated name into our result set.
+		if !strings.EqualFold(answer.NAME, name) {
+			continue
+		}
+
+		if cache != nil {
+			cache.Put(message.ResourceRecord{
+				Name:       answer.NAME,
+				Type:       protocol.RecordType(answer.TYPE),
+				Class:      protocol.DNSClass(answer.RRClass()),
+				TTL:        answer.TTL,
+				Data:       answer.RDATA,
+				CacheFlush: answer.CacheFlush(),
+			})
+		}
+
+		// Filter by query type, unless the caller asked for
+		// RecordTypeANY, in which case every answer type is kept.
+		if queryType != RecordTypeANY && RecordType(answer.TYPE) != queryType {
+			// Skip records of a different type than queried
+			// (Production might include related records)
+			if report != nil {
+				report(ReasonWrongType)
+			}
+			continue
+		}
+
+		// Parse type-specific RDATA
+		data, err := message.ParseRDATA(answer.TYPE, answer.RDATA)
+		if err != nil {
+			// Malformed RDATA - skip this record per FR-011
+			if report != nil {
+				report(ReasonMalformed)
+			}
+			continue
+		}
+
+		// FR-007: Deduplicate identical records
+		dedupeKey := dedupeKeyFor(answer.NAME, answer.TYPE, data)
+		if seen[dedupeKey] {
+			continue // Duplicate - skip
+		}
+		seen[dedupeKey] = true
+
+		// Convert to public ResourceRecord
+		record := ResourceRecord{
+			Name:      answer.NAME,
+			Type:      RecordType(answer.TYPE),
+			Class:     answer.CLASS,
+			TTL:       answer.TTL,
+			Data:      data,
+			Interface: iface,
+		}
+
+		response.Records = append(response.Records, record)
+	}
+}
+
+// appendAdditionals appends parsedMsg's Additional-section records to
+// response.Additionals, deduplicating the same way appendMatchingAnswers
+// does for Answers (seen is a separate map, so an Additional sharing a
+// dedupeKey with an already-seen Answer isn't mistakenly skipped). Unlike
+// appendMatchingAnswers, no NAME match against the query is required: an
+// Additional record legitimately names something other than what was
+// queried (e.g. a PTR query's response bundling the instance's SRV/TXT, or
+// an SRV target's A/AAAA, per RFC 6763 §12) - the anti-spoofing protection
+// already came from message.ValidateResponse confirming the whole packet
+// answers this Querier's outstanding query, before a caller ever reaches
+// this function. iface is copied onto every appended ResourceRecord.Interface
+// (see appendMatchingAnswers).
+func appendAdditionals(response *Response, seen map[string]bool, parsedMsg *message.DNSMessage, iface *net.Interface) {
+	for _, additional := range parsedMsg.Additionals {
+		data, err := message.ParseRDATA(additional.TYPE, additional.RDATA)
+		if err != nil {
+			continue
+		}
+
+		dedupeKey := dedupeKeyFor(additional.NAME, additional.TYPE, data)
+		if seen[dedupeKey] {
+			continue
+		}
+		seen[dedupeKey] = true
+
+		response.Additionals = append(response.Additionals, ResourceRecord{
+			Name:      additional.NAME,
+			Type:      RecordType(additional.TYPE),
+			Class:     additional.CLASS,
+			TTL:       additional.TTL,
+			Data:      data,
+			Interface: iface,
+		})
+	}
+}
+
+// reportDrop invokes the WithDropObserver callback, if one is configured,
+// with a DropEvent describing a packet or record that receiveLoop or a
+// response collector just discarded, and counts the drop against q.metrics
+// (covering rate-limit drops, among every other DropReason) so a caller
+// using WithMetrics doesn't also need to wire WithDropObserver just to see
+// drop volume. The metricsActive() check keeps this cheap on the
+// rate-limiting fast path when no real sink is configured.
+func (q *Querier) reportDrop(reason DropReason, srcIP net.IP, name string) {
+	if q.metricsActive() {
+		q.metrics.IncCounter("beacon_querier_drops_total", map[string]string{"reason": string(reason)})
+	}
+
+	if q.dropObserver == nil {
+		return
+	}
+
+	var ip string
+	if srcIP != nil {
+		ip = srcIP.String()
+	}
+
+	q.dropObserver(DropEvent{
+		Reason:   reason,
+		Time:     time.Now(),
+		SourceIP: ip,
+		Name:     name,
+	})
+}
+
+// QueryUnicast sends an mDNS query with the QU (unicast-response) bit set per
+// RFC 6762 §5.4, requesting that responders reply directly to this call's
+// ephemeral port instead of the multicast group. The query itself is still
+// sent to the multicast destination(s) for the Querier's configured
+// IPFamily - only the requested reply path changes.
+//
+// Unlike Query, QueryUnicast does not read from the Querier's shared
+// responseChan/receiveLoop machinery, since those only listen on the
+// long-lived multicast-joined endpoints. It opens its own short-lived
+// UnicastUDPTransport for the duration of the call and collects replies
+// arriving on it directly.
+//
+// RFC 6762 §5.4 reserves QU for the first query in a burst, when the querier
+// has nothing cached yet and wants an answer as fast as possible - repeated
+// queries for the same name should fall back to Query's normal multicast
+// reply path so other listeners on the LAN can passively learn the answer
+// too (RFC 6762 §5.2).
+func (q *Querier) QueryUnicast(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	default:
+	}
+
+	if err := protocol.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if err := protocol.ValidateRecordType(uint16(recordType)); err != nil {
+		return nil, err
+	}
+
+	return q.queryUnicastLocked(ctx, name, recordType, false)
+}
+
+// queryUnicastLocked performs QueryUnicast's send-and-collect work. Callers
+// must already hold q.mu and have validated name/recordType; it exists so
+// collectResponses's TC=1 retry (retryTruncatedOverUnicast) and
+// QueryWithOptions can reuse the same unicast-reply path without
+// recursively locking q.mu. firstAnswerWins is passed straight through to
+// collectUnicastResponses - see its doc.
+func (q *Querier) queryUnicastLocked(ctx context.Context, name string, recordType RecordType, firstAnswerWins bool) (*Response, error) {
+	queryMsg, err := message.BuildUnicastQuery(name, uint16(recordType))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsed back out so collectUnicastResponses can correlate replies
+	// against it via message.ValidateResponse - see that function's doc.
+	parsedQuery, err := message.ParseMessage(queryMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	var sendErrs []error
+	var transports []*transport.UnicastUDPTransport
+	defer func() {
+		for _, ut := range transports {
+			_ = ut.Close()
+		}
+	}()
+
+	if q.ipFamily == IPv4Only || q.ipFamily == DualStack {
+		ut, utErr := transport.NewUnicastUDPTransport("udp4")
+		if utErr != nil {
+			sendErrs = append(sendErrs, utErr)
+		} else {
+			transports = append(transports, ut)
+			if sendErr := ut.Send(ctx, queryMsg, protocol.MulticastGroupIPv4()); sendErr != nil {
+				sendErrs = append(sendErrs, sendErr)
+			}
+		}
+	}
+
+	if q.ipFamily == IPv6Only || q.ipFamily == DualStack {
+		ut, utErr := transport.NewUnicastUDPTransport("udp6")
+		if utErr != nil {
+			sendErrs = append(sendErrs, utErr)
+		} else {
+			transports = append(transports, ut)
+			if sendErr := ut.Send(ctx, queryMsg, protocol.MulticastGroupIPv6("")); sendErr != nil {
+				sendErrs = append(sendErrs, sendErr)
+			}
+		}
+	}
+
+	if len(transports) == 0 {
+		return nil, goerrors.Join(sendErrs...)
+	}
+
+	report := func(reason DropReason) { q.reportDrop(reason, nil, name) }
+	// unicast=false: this is a QU-bit request over mDNS, not a classic
+	// point-to-point query, so replies are correlated by question tuple
+	// rather than by echoed ID - see message.ValidateResponse's doc.
+	return collectUnicastResponses(ctx, transports, name, recordType, parsedQuery, false, q.knownAnswerCache, report, firstAnswerWins)
+}
+
+// collectUnicastResponses reads directly from each of transports (rather than
+// the Querier's shared responseChan) until ctx is done, aggregating answers
+// the same way collectResponses does. report, if non-nil, is called for every
+// dropped packet or record (see appendMatchingAnswers); LegacyResolver, which
+// has no Querier to report through, passes nil.
+//
+// parsedQuery/unicast are passed straight to message.ValidateResponse to
+// correlate each reply: unicast=true for LegacyResolver's true point-to-point
+// queries (strict ID echo), unicast=false for QueryUnicast's QU-bit mDNS
+// replies (question-tuple correlation, since this repo's own responder - and
+// any RFC 6762-compliant one - always replies with ID=0).
+//
+// cache is forwarded to appendMatchingAnswers; nil for LegacyResolver, which
+// has no Known-Answer cache of its own.
+//
+// firstAnswerWins mirrors collectResponses's option of the same name -
+// returns as soon as a reply contributes at least one new matching record,
+// instead of waiting for every per-transport goroutine to drain or time out.
+func collectUnicastResponses(ctx context.Context, transports []*transport.UnicastUDPTransport, name string, queryType RecordType, parsedQuery *message.DNSMessage, unicast bool, cache *KnownAnswerCache, report func(DropReason), firstAnswerWins bool) (*Response, error) {
+	response := &Response{
+		Records: make([]ResourceRecord, 0),
+	}
+	seen := make(map[string]bool)
+	seenAdditional := make(map[string]bool)
+
+	// A firstAnswerWins early return must stop the per-transport goroutines
+	// below promptly rather than let the deferred wg.Wait() block until the
+	// caller's own ctx eventually ends on its own - cancel derives a child
+	// ctx this function controls, so every deferred cleanup below runs
+	// against it instead of the caller's ctx directly.
+	ctx, cancel := context.WithCancel(ctx)
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	results := make(chan result, len(transports))
+
+	var wg sync.WaitGroup
+	for _, ut := range transports {
+		wg.Add(1)
+		go func(ut *transport.UnicastUDPTransport) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				// Receive with a short timeout, same as receiveLoop, so this
+				// goroutine notices ctx expiring even if ctx itself carries
+				// no deadline (a blocking socket read can't observe
+				// cancellation directly).
+				recvCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+				data, _, err := ut.Receive(recvCtx)
+				cancel()
 
-				// Parse type-specific RDATA
-				data, err := message.ParseRDATA(answer.TYPE, answer.RDATA)
 				if err != nil {
-					// Malformed RDATA - skip this record per FR-011
-					continue
+					continue // timeout or transient error - loop and recheck ctx
+				}
+
+				select {
+				case results <- result{data: data}:
+				case <-ctx.Done():
+					return
 				}
+			}
+		}(ut)
+	}
+	defer wg.Wait()
+	defer cancel()
 
-				// FR-007: Deduplicate identical records
-				// Key: name + type + data representation
-				dedupeKey := fmt.Sprintf("%s|%d|%v", answer.NAME, answer.TYPE, data)
-				if seen[dedupeKey] {
-					continue // Duplicate - skip
+	nxdomain := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			// len(transports) > 1 (DualStack) means a second per-transport
+			// goroutine may still have been mid-receive when ctx ended -
+			// genuinely partial, rather than the single-transport case
+			// where whatever this loop collected is simply everything
+			// there was going to be.
+			response.Status = finalStatus(response, nxdomain, len(transports) > 1)
+			return response, nil
+
+		case res := <-results:
+			if res.err != nil {
+				continue
+			}
+
+			parsedMsg, err := message.ParseMessage(res.data)
+			if err != nil {
+				if report != nil {
+					report(ReasonMalformed)
+				}
+				continue
+			}
+			if err := protocol.ValidateResponse(parsedMsg.Header.Flags); err != nil {
+				if recordRcode(response, parsedMsg) {
+					nxdomain = true
 				}
-				seen[dedupeKey] = true
-
-				// Convert to public ResourceRecord
-				record := ResourceRecord{
-					Name:  answer.NAME,
-					Type:  RecordType(answer.TYPE),
-					Class: answer.CLASS,
-					TTL:   answer.TTL,
-					Data:  data,
+				if report != nil {
+					report(ReasonMalformed)
 				}
+				continue
+			}
+
+			if err := message.ValidateResponse(parsedQuery, parsedMsg, unicast); err != nil {
+				if report != nil {
+					report(ReasonUnrelatedResponse)
+				}
+				continue
+			}
 
-				response.Records = append(response.Records, record)
+			before := len(response.Records)
+			// Unicast responses aren't attributed to an interface the way
+			// receiveLoop's multicast path is (see receiveOne) - a directed
+			// reply from a specific responder over an ephemeral unicast
+			// socket has no equivalent multi-interface ambiguity to resolve.
+			appendMatchingAnswers(response, seen, parsedMsg, name, queryType, cache, nil, report)
+			appendAdditionals(response, seenAdditional, parsedMsg, nil)
+
+			if nsecAssertsNonexistence(parsedMsg, name, queryType) {
+				nxdomain = true
+			}
+
+			if firstAnswerWins && len(response.Records) > before {
+				response.Status = StatusOK
+				return response, nil
 			}
 		}
 	}
 }
 
+// receivedPacket is what receiveLoop hands to processReceivedPacketSafely
+// and, from there, responseChan: the raw message bytes, its source address,
+// and (best-effort) the network interface it arrived on.
+type receivedPacket struct {
+	data  []byte
+	addr  net.Addr
+	iface *net.Interface
+}
+
+// receiveOne receives a single packet from tr, returning the interface it
+// arrived on when tr can report one. If tr implements transport.BatchReceiver
+// (UDPv4Transport, UDPv6Transport), it's received via a one-packet
+// ReceiveBatch call so Packet.Iface is populated; the batch's pooled buffer
+// is copied out and released immediately, preserving Receive's existing
+// caller-owns-the-result contract for everything downstream. Every other
+// transport (MockTransport, UnicastUDPTransport, DualStack, ...) falls back
+// to the plain Receive, with iface always nil.
+func (q *Querier) receiveOne(ctx context.Context, tr transport.Transport) (data []byte, addr net.Addr, iface *net.Interface, err error) {
+	br, ok := tr.(transport.BatchReceiver)
+	if !ok {
+		data, addr, err = tr.Receive(ctx)
+		return data, addr, nil, err
+	}
+
+	var pkt [1]transport.Packet
+	n, err := br.ReceiveBatch(ctx, pkt[:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if n == 0 {
+		// ReceiveBatch blocks until at least one packet arrives or ctx ends,
+		// so this shouldn't happen with err == nil - treated as a timeout so
+		// receiveLoop's err handling simply loops back around.
+		return nil, nil, nil, &errors.NetworkError{
+			Operation: "receive batch",
+			Details:   "timeout",
+		}
+	}
+
+	p := pkt[0]
+	result := make([]byte, len(p.Data))
+	copy(result, p.Data)
+	if p.ReturnFunc != nil {
+		p.ReturnFunc()
+	}
+
+	var srcAddr net.Addr
+	if p.Addr != nil {
+		srcAddr = p.Addr
+	}
+	return result, srcAddr, p.Iface, nil
+}
+
 // receiveLoop runs in a background goroutine to continuously receive mDNS responses.
 //
 // FR-006: System MUST receive responses with configurable timeout
 // FR-017: System MUST close socket after query completion
 //
 // nolint:gocyclo // Complexity 22 due to network packet handling with rate limiting, context management, source IP validation, and error recovery
-func (q *Querier) receiveLoop() {
+func (q *Querier) receiveLoop(tr transport.Transport) {
 	defer q.wg.Done()
 
+	// consecutiveErrs counts non-timeout errors in a row on this transport.
+	// Crossing receiveErrorDegradeThreshold reports degradeReceiveErrors; a
+	// subsequent successful receive clears it. Local to this goroutine - a
+	// DualStack Querier runs one receiveLoop per transport, each tracking
+	// its own streak independently.
+	consecutiveErrs := 0
+
 	for {
 		select {
 		case <-q.ctx.Done():
@@ -343,83 +1628,121 @@ func (q *Querier) receiveLoop() {
 			// FR-006: Receive with short timeout to check context periodically
 			// T034: Migrated from network.ReceiveResponse to transport.Receive()
 			ctx, cancel := context.WithTimeout(q.ctx, 100*time.Millisecond)
-			responseMsg, srcAddr, err := q.transport.Receive(ctx)
+			responseMsg, srcAddr, srcIface, err := q.receiveOne(ctx, tr)
 			cancel()
 
 			if err != nil {
 				// Timeout or network error - continue listening
 				// Check if it's a timeout (expected) or real error
 				var netErr *errors.NetworkError
-				if goerrors.As(err, &netErr) {
+				if goerrors.As(err, &netErr) && netErr.Details == "timeout" {
 					// Network timeout is expected - continue
 					continue
 				}
-				// Real network error - might want to log in production
+				// Real network error (socket gone, ENOBUFS, ...) - a few of
+				// these in a row means the transport itself is unhealthy,
+				// not that one packet was lost.
+				consecutiveErrs++
+				if consecutiveErrs == receiveErrorDegradeThreshold {
+					q.setDegraded(degradeReceiveErrors, true)
+				}
 				continue
 			}
 
-			// T077: Packet size validation per RFC 6762 §17 (FR-034)
-			// Fail fast - reject oversized packets before parsing
-			const maxMDNSPacketSize = 9000 // RFC 6762 §17
-			if len(responseMsg) > maxMDNSPacketSize {
-				// Packet exceeds RFC limit - drop it
-				// TODO T076: Add debug logging (source IP + size)
-				continue
+			if consecutiveErrs >= receiveErrorDegradeThreshold {
+				q.setDegraded(degradeReceiveErrors, false)
 			}
+			consecutiveErrs = 0
 
-			// Extract source IP for validation and rate limiting
-			var srcIP net.IP
-			var srcIPStr string
-			if udpAddr, ok := srcAddr.(*net.UDPAddr); ok {
-				srcIP = udpAddr.IP
-				srcIPStr = udpAddr.IP.String()
-			}
-
-			// T075: Basic source IP validation (link-local check)
-			// RFC 6762 §2: mDNS is link-local scope
-			// NOTE: Full per-interface source filtering deferred to M2 (requires per-interface transports)
-			// For M1.1, we implement conservative link-local validation:
-			// - Accept link-local addresses (169.254.0.0/16) - ALWAYS valid per RFC 3927
-			// - Accept private addresses (10.x, 172.16.x, 192.168.x) - likely same subnet
-			// - Reject public/routed IPs (8.8.8.8, etc.) - definitely not link-local
-			if srcIP != nil {
-				ip4 := srcIP.To4()
-				if ip4 != nil {
-					// Check if it's a public/routed IP (not private, not link-local)
-					isLinkLocal := ip4[0] == 169 && ip4[1] == 254
-					isPrivate := ip4[0] == 10 ||
-						(ip4[0] == 172 && ip4[1] >= 16 && ip4[1] <= 31) ||
-						(ip4[0] == 192 && ip4[1] == 168)
-
-					// Reject public/routed IPs (definitely not link-local scope)
-					if !isLinkLocal && !isPrivate {
-						// Public IP - drop packet (violates RFC 6762 §2 link-local scope)
-						// TODO T076: Add debug logging (source IP + reason)
-						continue
-					}
-				}
-			}
+			// Recovered via processReceivedPacketSafely so a malformed
+			// packet that panics deep in validation/dispatch can't take
+			// down this long-running receive goroutine.
+			q.processReceivedPacketSafely(responseMsg, srcAddr, srcIface)
+		}
+	}
+}
 
-			// Apply rate limiting if enabled (FR-029: drop packets from flooding sources)
-			if q.rateLimitEnabled && q.rateLimiter != nil && srcIPStr != "" {
-				if !q.rateLimiter.Allow(srcIPStr) {
-					// Rate limited - drop packet silently
-					// FR-030: Logging (first at warn, subsequent at debug) handled by caller
-					// TODO T063: Add logging in production
-					continue
-				}
-			}
+// processReceivedPacketSafely validates, rate-limits, and dispatches one
+// packet responseMsg received from srcAddr, recovering any panic so a
+// single malformed or adversarial response can't crash receiveLoop's
+// goroutine - every subsequent response would otherwise go unprocessed for
+// the life of the process.
+func (q *Querier) processReceivedPacketSafely(responseMsg []byte, srcAddr net.Addr, srcIface *net.Interface) {
+	defer q.recoverPanic("receiveLoop", srcAddr)
+
+	// Extract source IP for validation, rate limiting, and drop reporting.
+	var srcIP net.IP
+	if udpAddr, ok := srcAddr.(*net.UDPAddr); ok {
+		srcIP = udpAddr.IP
+	}
 
-			// Send response to channel (non-blocking)
-			select {
-			case q.responseChan <- responseMsg:
-				// Sent successfully
-			default:
-				// Channel full - drop packet (M1 behavior)
-				// Production might want to expand buffer or log
+	// T077: Packet size validation per RFC 6762 §17 (FR-034)
+	// Fail fast - reject oversized packets before parsing
+	if len(responseMsg) > q.maxResponseBytes {
+		// Packet exceeds the configured limit - drop it
+		q.reportDrop(ReasonOversized, srcIP, "")
+		return
+	}
+
+	// T075: Basic source IP validation (link-local check)
+	// RFC 6762 §2: mDNS is link-local scope
+	// NOTE: Full per-interface source filtering deferred to M2 (requires per-interface transports)
+	// For M1.1, we implement conservative link-local validation:
+	// - Accept link-local addresses (169.254.0.0/16) - ALWAYS valid per RFC 3927
+	// - Accept private addresses (10.x, 172.16.x, 192.168.x) - likely same subnet
+	// - Reject public/routed IPs (8.8.8.8, etc.) - definitely not link-local
+	if srcIP != nil {
+		ip4 := srcIP.To4()
+		if ip4 != nil {
+			// Check if it's a public/routed IP (not private, not link-local)
+			isLinkLocal := ip4[0] == 169 && ip4[1] == 254
+			isPrivate := ip4[0] == 10 ||
+				(ip4[0] == 172 && ip4[1] >= 16 && ip4[1] <= 31) ||
+				(ip4[0] == 192 && ip4[1] == 168)
+
+			// Reject public/routed IPs (definitely not link-local scope)
+			if !isLinkLocal && !isPrivate {
+				// Public IP - drop packet (violates RFC 6762 §2 link-local scope)
+				q.reportDrop(ReasonNonLinkLocal, srcIP, "")
+				return
 			}
 		}
 	}
+
+	// Apply rate limiting if enabled (FR-029: drop packets from flooding sources)
+	if q.rateLimitEnabled && q.rateLimiter != nil && srcIP != nil {
+		if !q.rateLimiter.Allow(srcIP) {
+			// Rate limited - drop packet silently
+			// FR-030: Logging (first at warn, subsequent at debug) handled by caller
+			q.reportDrop(ReasonRateLimited, srcIP, "")
+			return
+		}
+	}
+
+	if q.tapActive() {
+		q.tap.OnResponse(responseMsg, srcAddr, nil, time.Now())
+	}
+
+	// Hand the message to any active Watch() sessions before the shared
+	// responseChan Query/QueryUnicast drain from, so a Watch session sees
+	// it even if responseChan is momentarily full.
+	q.dispatchWatch(responseMsg)
+
+	// Send response to channel (non-blocking)
+	select {
+	case q.responseChan <- receivedPacket{data: responseMsg, addr: srcAddr, iface: srcIface}:
+		// Sent successfully
+		if atomic.SwapInt32(&q.chanDropStreak, 0) >= chanOverflowDegradeThreshold {
+			q.setDegraded(degradeResponseChanOverflow, false)
+		}
+	default:
+		// Channel full - drop packet (M1 behavior)
+		// Production might want to expand buffer or log
+		q.reportDrop(ReasonChannelFull, srcIP, "")
+		if atomic.AddInt32(&q.chanDropStreak, 1) == chanOverflowDegradeThreshold {
+			q.setDegraded(degradeResponseChanOverflow, true)
+		}
+	}
 }
 
 // cleanupLoop periodically cleans up stale rate limiter entries.
@@ -461,22 +1784,48 @@ func (q *Querier) cleanupLoop() {
 //	}
 //	defer q.Close() // Always close to release resources
 func (q *Querier) Close() error {
+	q.setState(StateClosing)
+
+	// Stop the interface watcher first so it can't report a change after
+	// the transports it would otherwise react to are closed.
+	if q.ifaceWatcher != nil {
+		q.ifaceWatcher.Stop()
+	}
+
+	q.stateMu.Lock()
+	if q.rateLimitRecoverTimer != nil {
+		q.rateLimitRecoverTimer.Stop()
+	}
+	q.stateMu.Unlock()
+
 	// Cancel lifecycle context (stops receiver goroutine)
 	q.cancel()
 
 	// Wait for receiver goroutine to exit
 	q.wg.Wait()
 
-	// Close transport per FR-017
+	// Close transport(s) per FR-017
 	// T035: Migrated from network.CloseSocket to transport.Close()
 	// FR-004 FIX: Now properly propagates errors (CloseSocket was swallowing them)
-	err := q.transport.Close()
-	if err != nil {
-		return err
+	var closeErrs []error
+	for _, ep := range q.endpoints {
+		if err := ep.transport.Close(); err != nil {
+			closeErrs = append(closeErrs, err)
+		}
+	}
+	if len(closeErrs) > 0 {
+		// Deliberately skip close(q.responseChan) below and the final
+		// setState(StateClosed) that follows it on the success path isn't
+		// reached either - matching the original behavior where a second
+		// Close() call (transport already closed, closeErrs non-empty)
+		// returns here without re-closing responseChan. The Querier is
+		// left in StateClosing rather than StateClosed in that case.
+		return goerrors.Join(closeErrs...)
 	}
 
 	// Close response channel
 	close(q.responseChan)
 
+	q.setState(StateClosed)
 	return nil
 }
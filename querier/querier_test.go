@@ -2,8 +2,13 @@ package querier
 
 import (
 	"context"
+	"net"
+	"net/netip"
 	"testing"
 	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/transport"
 )
 
 // BenchmarkQuery measures the query processing overhead per NFR-001.
@@ -167,7 +172,7 @@ func TestResourceRecordAccessors(t *testing.T) {
 	ptrRecord := ResourceRecord{
 		Name: "test.local",
 		Type: RecordTypePTR,
-		Data: "target.local",
+		Data: message.PTRData{Name: "target.local"},
 	}
 
 	if ip := ptrRecord.AsA(); ip != nil {
@@ -186,9 +191,32 @@ func TestResourceRecordAccessors(t *testing.T) {
 		t.Errorf("AsTXT() on PTR record returned %v, expected nil", txt)
 	}
 
+	if ip := ptrRecord.AsAAAA(); ip != nil {
+		t.Errorf("AsAAAA() on PTR record returned %v, expected nil", ip)
+	}
+
 	t.Log("✓ Type-safe accessors return nil/empty for wrong record types")
 }
 
+// TestResourceRecordAsAAAA validates that AsAAAA returns the IPv6 address
+// for an AAAA record.
+func TestResourceRecordAsAAAA(t *testing.T) {
+	ip := net.ParseIP("fe80::1")
+	aaaaRecord := ResourceRecord{
+		Name: "test.local",
+		Type: RecordTypeAAAA,
+		Data: message.AAAAData{IP: netip.MustParseAddr("fe80::1")},
+	}
+
+	if got := aaaaRecord.AsAAAA(); !got.Equal(ip) {
+		t.Errorf("AsAAAA() = %v, want %v", got, ip)
+	}
+
+	if got := aaaaRecord.AsA(); got != nil {
+		t.Errorf("AsA() on AAAA record returned %v, expected nil", got)
+	}
+}
+
 // ==============================================================================
 // M1-Refactoring Integration Tests (TDD - RED Phase)
 // ==============================================================================
@@ -198,39 +226,43 @@ func TestResourceRecordAccessors(t *testing.T) {
 // NOTE: Original TDD RED tests removed (T027, T028):
 // - TestQuerier_UsesTransportInterface: Obsolete, T031 is complete
 //   (Querier HAS transport field at querier.go:46-47, used throughout)
-// - TestQuerier_WorksWithMockTransport: Deferred to future milestone
-//   (WithTransport() option not implemented - all tests work without it)
 //
 // Transport interface abstraction is validated via:
 // - M1-Refactoring completion (see archive/m1-refactoring/)
 // - internal/transport/transport_test.go (interface contract tests)
 // - querier/querier.go:112 (New() creates UDPv4Transport)
-//
-// TODO M2 (T100): Add test for WithTransport() option
-// After implementing WithTransport() option (see querier/options.go TODO), add:
-//
-//   func TestQuerier_WithTransport_UsesMockTransport(t *testing.T) {
-//       mock := transport.NewMockTransport()
-//       q, err := New(WithTransport(mock))
-//       if err != nil {
-//           t.Fatalf("New(WithTransport) failed: %v", err)
-//       }
-//       defer func() { _ = q.Close() }()
-//
-//       ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
-//       defer cancel()
-//
-//       _, _ = q.Query(ctx, "test.local", RecordTypeA)
-//
-//       // Verify mock recorded the Send() call
-//       calls := mock.SendCalls()
-//       if len(calls) != 1 {
-//           t.Errorf("Expected 1 Send() call, got %d", len(calls))
-//       }
-//   }
-//
-// This enables testing without real network, mocking failures, simulating responses.
-// See: specs/004-m1-1-architectural-hardening/tasks.md Phase 8, T100
+
+// TestQuerier_WithTransport_UsesMockTransport validates that WithTransport
+// replaces New()'s default UDP multicast endpoint with the supplied
+// transport.Transport, enabling tests to exercise Query() without a real
+// network socket.
+func TestQuerier_WithTransport_UsesMockTransport(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _ = q.Query(ctx, "test.local", RecordTypeA)
+
+	calls := mock.SendCalls()
+	if len(calls) != 1 {
+		t.Errorf("Expected 1 Send() call, got %d", len(calls))
+	}
+}
+
+// TestWithTransport_RejectsNil validates that WithTransport(nil) fails
+// construction rather than silently leaving the Querier with no endpoint.
+func TestWithTransport_RejectsNil(t *testing.T) {
+	_, err := New(WithTransport(nil))
+	if err == nil {
+		t.Error("New(WithTransport(nil)) should fail, got nil error")
+	}
+}
 
 // ==============================================================================
 // Phase 3: Error Propagation Validation (T064) - FR-004
@@ -262,3 +294,192 @@ func TestQuerier_Close_PropagatesTransportErrors(t *testing.T) {
 		t.Logf("✓ FR-004 VALIDATED (end-to-end): Querier.Close() propagates transport error: %v", err)
 	}
 }
+
+// TestQuerier_WithWatchInterfaces validates that enabling WithWatchInterfaces
+// starts the background watcher and exposes a non-nil InterfaceChanges
+// channel, while a default Querier reports no watcher at all.
+func TestQuerier_WithWatchInterfaces(t *testing.T) {
+	plain, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = plain.Close() }()
+
+	if ch := plain.InterfaceChanges(); ch != nil {
+		t.Error("InterfaceChanges() non-nil without WithWatchInterfaces")
+	}
+
+	watching, err := New(WithWatchInterfaces(true))
+	if err != nil {
+		t.Fatalf("New(WithWatchInterfaces(true)) failed: %v", err)
+	}
+	defer func() { _ = watching.Close() }()
+
+	if ch := watching.InterfaceChanges(); ch == nil {
+		t.Error("InterfaceChanges() nil with WithWatchInterfaces(true)")
+	}
+}
+
+// TestQuerier_QueryUnicast_ReturnsOnTimeout validates that QueryUnicast
+// behaves like Query when no reply arrives: it returns an empty Response and
+// no error once ctx expires, rather than blocking or erroring.
+func TestQuerier_QueryUnicast_ReturnsOnTimeout(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resp, err := q.QueryUnicast(ctx, "nonexistent.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("QueryUnicast() returned error: %v", err)
+	}
+	if resp == nil || len(resp.Records) != 0 {
+		t.Errorf("QueryUnicast() = %+v, want empty response", resp)
+	}
+}
+
+// TestQuerier_QueryUnicast_ValidatesInputs validates that QueryUnicast
+// rejects an invalid name the same way Query does, without ever touching the
+// network.
+func TestQuerier_QueryUnicast_ValidatesInputs(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	_, err = q.QueryUnicast(context.Background(), "", RecordTypeA)
+	if err == nil {
+		t.Error("QueryUnicast(\"\") should return a validation error")
+	}
+}
+
+// TestQuerier_WithUnicastResponse_FallsBackAfterQueryLimit validates RFC
+// 6762 §5.4's "the first query... should be sent with QU set... Subsequent
+// queries... should be sent conventionally": WithUnicastResponse(true)
+// routes Query's first unicastResponseQueryLimit calls through
+// queryUnicastLocked (exercised indirectly here - both paths return an
+// empty Response on timeout since nothing answers - see
+// TestQuerier_QueryUnicast_ReturnsOnTimeout), then Query's own count gate
+// stops applying it.
+func TestQuerier_WithUnicastResponse_FallsBackAfterQueryLimit(t *testing.T) {
+	q, err := New(WithUnicastResponse(true))
+	if err != nil {
+		t.Fatalf("New(WithUnicastResponse) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	for i := 0; i < unicastResponseQueryLimit; i++ {
+		if q.unicastQueryCount != i {
+			t.Fatalf("before call %d: unicastQueryCount = %d, want %d", i, q.unicastQueryCount, i)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		if _, err := q.Query(ctx, "nonexistent.local", RecordTypeA); err != nil {
+			cancel()
+			t.Fatalf("Query() call %d returned error: %v", i, err)
+		}
+		cancel()
+	}
+
+	if q.unicastQueryCount != unicastResponseQueryLimit {
+		t.Fatalf("unicastQueryCount = %d, want %d after %d calls", q.unicastQueryCount, unicastResponseQueryLimit, unicastResponseQueryLimit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "nonexistent.local", RecordTypeA); err != nil {
+		t.Fatalf("Query() after limit returned error: %v", err)
+	}
+	if q.unicastQueryCount != unicastResponseQueryLimit {
+		t.Errorf("unicastQueryCount = %d after exceeding the limit, want unchanged %d", q.unicastQueryCount, unicastResponseQueryLimit)
+	}
+}
+
+// TestQuerier_WithUnicastResponse_Disabled verifies Query's established
+// always-multicast behavior is unchanged when WithUnicastResponse is left
+// at its default.
+func TestQuerier_WithUnicastResponse_Disabled(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := q.Query(ctx, "nonexistent.local", RecordTypeA); err != nil {
+		t.Fatalf("Query() returned error: %v", err)
+	}
+	if q.unicastQueryCount != 0 {
+		t.Errorf("unicastQueryCount = %d, want 0 when WithUnicastResponse is unset", q.unicastQueryCount)
+	}
+}
+
+// TestQuerier_RetryTruncatedOverUnicast_ReturnsOnTimeout validates that the
+// TC=1 escalation path collectResponses uses (retryTruncatedOverUnicast)
+// behaves like QueryUnicast itself when nothing replies: it returns without
+// error and leaves response untouched, rather than blocking past ctx or
+// turning a quiet LAN into a Query failure.
+func TestQuerier_RetryTruncatedOverUnicast_ReturnsOnTimeout(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	response := &Response{Records: make([]ResourceRecord, 0)}
+	seen := make(map[string]bool)
+	seenAdditional := make(map[string]bool)
+
+	q.retryTruncatedOverUnicast(ctx, response, seen, seenAdditional, "nonexistent.local", RecordTypeA)
+
+	if len(response.Records) != 0 {
+		t.Errorf("response.Records = %+v, want empty (nothing replied)", response.Records)
+	}
+}
+
+// TestLegacyResolver_Resolve_ReturnsOnTimeout validates that a LegacyResolver
+// querying a peer that never replies returns an empty Response once ctx
+// expires, mirroring Query/QueryUnicast's timeout behavior.
+func TestLegacyResolver_Resolve_ReturnsOnTimeout(t *testing.T) {
+	// A UDP socket bound on loopback with nothing listening behind it: sends
+	// succeed (UDP is connectionless) but nothing ever replies.
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("failed to reserve a test peer port: %v", err)
+	}
+	peer := conn.LocalAddr().String()
+	_ = conn.Close()
+
+	r := NewLegacyResolver(peer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resp, err := r.Resolve(ctx, "printer.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if resp == nil || len(resp.Records) != 0 {
+		t.Errorf("Resolve() = %+v, want empty response", resp)
+	}
+}
+
+// TestLegacyResolver_Resolve_InvalidPeer validates that an unresolvable peer
+// address is reported as an error rather than silently producing an empty
+// response.
+func TestLegacyResolver_Resolve_InvalidPeer(t *testing.T) {
+	r := NewLegacyResolver("not a valid peer address")
+
+	_, err := r.Resolve(context.Background(), "printer.local", RecordTypeA)
+	if err == nil {
+		t.Error("Resolve() with an invalid peer address should return an error")
+	}
+}
@@ -0,0 +1,64 @@
+package querier
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// unicastFallbackWindow is how long QueryWithOptions honors a QueryOptions.
+// Unicast request before silently falling back to an ordinary multicast
+// query, per RFC 6762 §5.4's "the first query... should be send with QU
+// set... Subsequent queries... should be sent conventionally" guidance.
+const unicastFallbackWindow = 10 * time.Second
+
+// QueryOptions configures QueryWithOptions beyond Query's and QueryUnicast's
+// fixed behavior.
+type QueryOptions struct {
+	// Unicast sets the QU bit (RFC 6762 §5.4) on the outbound question,
+	// requesting that responders reply directly to this call instead of the
+	// multicast group - see QueryUnicast. RFC 6762 §5.4 reserves QU for the
+	// first query in a burst, so QueryWithOptions automatically falls back
+	// to Query's ordinary multicast path if the Querier already sent a QU
+	// query within unicastFallbackWindow.
+	Unicast bool
+
+	// FirstAnswerWins returns as soon as a reply contributes at least one
+	// new matching record, instead of waiting out ctx's full deadline to
+	// aggregate every responder per FR-008. Useful paired with Unicast for
+	// the low-latency first-query case; a TC=1 escalation still runs to
+	// completion before this takes effect (see collectResponses's doc).
+	FirstAnswerWins bool
+}
+
+// QueryWithOptions is Query extended with QueryOptions: QU-bit unicast
+// replies (RFC 6762 §5.4) and/or returning on the first matching answer
+// instead of aggregating for the full timeout window. Plain Query and
+// QueryUnicast remain the simpler, fixed-behavior entry points; reach for
+// this one when a caller wants the §5.4 low-latency first-query path with
+// its automatic multicast fallback.
+func (q *Querier) QueryWithOptions(ctx context.Context, name string, recordType RecordType, opts QueryOptions) (*Response, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	default:
+	}
+
+	if err := protocol.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if err := protocol.ValidateRecordType(uint16(recordType)); err != nil {
+		return nil, err
+	}
+
+	if opts.Unicast && time.Since(q.lastUnicastQueryAt) >= unicastFallbackWindow {
+		q.lastUnicastQueryAt = time.Now()
+		return q.queryUnicastLocked(ctx, name, recordType, opts.FirstAnswerWins)
+	}
+
+	return q.queryMulticastLocked(ctx, name, recordType, opts.FirstAnswerWins)
+}
@@ -0,0 +1,111 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestQueryWithOptions_FirstAnswerWins_ReturnsPromptly validates that
+// FirstAnswerWins returns as soon as a matching answer arrives, rather than
+// waiting out the full context deadline the way Query always does.
+func TestQueryWithOptions_FirstAnswerWins_ReturnsPromptly(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	respPacket, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{192, 168, 1, 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse() failed: %v", err)
+	}
+	mock.QueueReceive(respPacket, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	response, err := q.QueryWithOptions(ctx, "host.local", RecordTypeA, QueryOptions{FirstAnswerWins: true})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("QueryWithOptions() error = %v", err)
+	}
+
+	if elapsed >= 2*time.Second {
+		t.Errorf("QueryWithOptions(FirstAnswerWins) took %v, want well under the 2s deadline", elapsed)
+	}
+	if len(response.Records) != 1 {
+		t.Fatalf("response.Records = %+v, want 1 record", response.Records)
+	}
+	if response.Status != StatusOK {
+		t.Errorf("response.Status = %v, want StatusOK", response.Status)
+	}
+}
+
+// TestQueryWithOptions_Unicast_FallsBackWithinWindow validates RFC 6762
+// §5.4's rule that only the first query in a burst uses QU: a second
+// QueryOptions{Unicast: true} call within unicastFallbackWindow of the
+// first must fall back to an ordinary multicast query instead of sending
+// another QU-bit request.
+func TestQueryWithOptions_Unicast_FallsBackWithinWindow(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	if !q.lastUnicastQueryAt.IsZero() {
+		t.Fatalf("lastUnicastQueryAt = %v, want zero before any unicast query", q.lastUnicastQueryAt)
+	}
+
+	ctx1, cancel1 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel1()
+	if _, err := q.QueryWithOptions(ctx1, "nonexistent.local", RecordTypeA, QueryOptions{Unicast: true}); err != nil {
+		t.Fatalf("QueryWithOptions() error = %v", err)
+	}
+
+	firstAt := q.lastUnicastQueryAt
+	if firstAt.IsZero() {
+		t.Fatal("lastUnicastQueryAt still zero after a QueryOptions{Unicast: true} call")
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	if _, err := q.QueryWithOptions(ctx2, "nonexistent.local", RecordTypeA, QueryOptions{Unicast: true}); err != nil {
+		t.Fatalf("QueryWithOptions() error = %v", err)
+	}
+
+	if !q.lastUnicastQueryAt.Equal(firstAt) {
+		t.Errorf("lastUnicastQueryAt changed to %v on a second call within unicastFallbackWindow, want unchanged from %v", q.lastUnicastQueryAt, firstAt)
+	}
+}
+
+// TestQueryWithOptions_ValidatesInputs validates that QueryWithOptions
+// rejects an invalid name the same way Query and QueryUnicast do.
+func TestQueryWithOptions_ValidatesInputs(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	_, err = q.QueryWithOptions(context.Background(), "", RecordTypeA, QueryOptions{})
+	if err == nil {
+		t.Error("QueryWithOptions(\"\") should return a validation error")
+	}
+}
@@ -0,0 +1,126 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// withSecondEndpoint appends a second mock endpoint to q and starts its
+// receiveLoop, giving a Querier built via New(WithTransport(v4)) the
+// two-endpoint shape WithDualStack's real newEndpoints(DualStack) would -
+// without opening real IPv4/IPv6 multicast sockets.
+func withSecondEndpoint(t *testing.T, q *Querier, tr transport.Transport) {
+	t.Helper()
+	q.endpoints = append(q.endpoints, endpoint{transport: tr, dest: &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: 5353}})
+	q.wg.Add(1)
+	go q.receiveLoop(tr)
+}
+
+func aRecordResponse(t *testing.T, ip net.IP) []byte {
+	t.Helper()
+	packet, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  ip.To4(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse() failed: %v", err)
+	}
+	return packet
+}
+
+// TestQuerier_WithDualStack_SkipsSecondFamilyWhenFirstAnswersQuickly verifies
+// a WithDualStack query that gets an IPv4 answer well before the race delay
+// never sends on IPv6 at all.
+func TestQuerier_WithDualStack_SkipsSecondFamilyWhenFirstAnswersQuickly(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	q, err := New(WithTransport(v4), WithDualStack(), WithRaceDelay(200*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	v6 := transport.NewMockTransport()
+	withSecondEndpoint(t, q, v6)
+
+	v4.QueueReceive(aRecordResponse(t, net.ParseIP("192.168.1.1")), &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	response, err := q.Query(ctx, "host.local", RecordTypeA)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if elapsed >= 200*time.Millisecond {
+		t.Errorf("Query() took %v, want well under the 200ms race delay", elapsed)
+	}
+	if len(response.Records) != 1 {
+		t.Fatalf("response.Records = %+v, want 1 record", response.Records)
+	}
+
+	if calls := v4.SendCalls(); len(calls) != 1 {
+		t.Errorf("v4 SendCalls() = %d, want 1", len(calls))
+	}
+	if calls := v6.SendCalls(); len(calls) != 0 {
+		t.Errorf("v6 SendCalls() = %d, want 0 - IPv4 already answered before the race delay", len(calls))
+	}
+}
+
+// TestQuerier_WithDualStack_FallsBackToSecondFamilyAfterDelay verifies a
+// WithDualStack query with no IPv4 answer sends on IPv6 once the race delay
+// elapses, and returns the answer IPv6 supplies.
+func TestQuerier_WithDualStack_FallsBackToSecondFamilyAfterDelay(t *testing.T) {
+	v4 := transport.NewMockTransport()
+	q, err := New(WithTransport(v4), WithDualStack(), WithRaceDelay(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	v6 := transport.NewMockTransport()
+	withSecondEndpoint(t, q, v6)
+
+	// Queue the IPv6 answer only once the race has actually fallen back to
+	// it (a real responder has nothing to answer until it's queried) -
+	// queuing it upfront would let receiveLoop(v6) deliver it into the
+	// shared response channel before the race delay even starts, which
+	// would let IPv4's attempt "win" on an answer IPv6 supplied without
+	// ever really exercising the fallback send this test is checking for.
+	go func() {
+		for len(v6.SendCalls()) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		v6.QueueReceive(aRecordResponse(t, net.ParseIP("192.168.1.2")), &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 5353})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	response, err := q.Query(ctx, "host.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(response.Records) != 1 {
+		t.Fatalf("response.Records = %+v, want 1 record", response.Records)
+	}
+
+	if calls := v4.SendCalls(); len(calls) != 1 {
+		t.Errorf("v4 SendCalls() = %d, want 1", len(calls))
+	}
+	if calls := v6.SendCalls(); len(calls) != 1 {
+		t.Errorf("v6 SendCalls() = %d, want 1 - IPv4 never answered, should have fallen back", len(calls))
+	}
+}
@@ -0,0 +1,130 @@
+package querier
+
+import (
+	"sort"
+
+	"github.com/joshuafuller/beacon/internal/network"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// rebinder is satisfied by *transport.UDPv4Transport and
+// *transport.UDPv6Transport, the only Transport implementations that track
+// per-interface multicast membership. An endpoint built from a custom
+// transport (WithTransport) or a DualStack that doesn't implement it is
+// simply left out of rebinding - InterfaceChanges() still reports the
+// event, an embedder that needs it to rebind a DualStack or custom
+// transport can still react to it directly.
+type rebinder interface {
+	Rebind(events <-chan transport.RebindEvent)
+}
+
+// interfaceJoiner is satisfied by the same transports as rebinder, and for
+// the same reason: only *transport.UDPv4Transport and *transport.
+// UDPv6Transport track which interfaces they actually joined (a custom
+// WithTransport or a transport that failed to join any interface doesn't).
+type interfaceJoiner interface {
+	JoinedInterfaces() []string
+}
+
+// Interfaces returns the names of the interfaces q has actually joined the
+// mDNS multicast group on, deduplicated across endpoints (a DualStack
+// Querier's IPv4 and IPv6 endpoints typically join the same interfaces).
+// An endpoint whose transport doesn't track membership (WithTransport, or a
+// transport that never implemented it) is silently skipped rather than
+// causing an error - this is a best-effort diagnostic, not something Query
+// depends on.
+func (q *Querier) Interfaces() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, ep := range q.endpoints {
+		ij, ok := ep.transport.(interfaceJoiner)
+		if !ok {
+			continue
+		}
+		for _, name := range ij.JoinedInterfaces() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newInterfaceWatcher starts the real interface watcher for New, wrapped as
+// a transport.InterfaceWatcher. It lives here rather than in querier.go so
+// that only this file - which already needs internal/network for the
+// rebindLoop translation below - has to import that package; querier.go
+// itself must not (FR-002, enforced by
+// TestLayerBoundaries_QuerierDoesNotImportInternalNetwork).
+func newInterfaceWatcher() (transport.InterfaceWatcher, error) {
+	return network.NewTransportInterfaceWatcher()
+}
+
+// startRebinding starts one Rebind goroutine per endpoint whose transport
+// implements rebinder, and a goroutine translating q.ifaceWatcher's
+// InterfaceUp/InterfaceDown events into RebindEvents for them - so a
+// Querier built with WithWatchInterfaces actually rejoins a newly-up
+// interface's multicast group and leaves a removed one's, instead of only
+// exposing the event via InterfaceChanges(). Does nothing if no endpoint's
+// transport is rebindable.
+func (q *Querier) startRebinding() {
+	var chans []chan transport.RebindEvent
+	for _, ep := range q.endpoints {
+		rb, ok := ep.transport.(rebinder)
+		if !ok {
+			continue
+		}
+		ch := make(chan transport.RebindEvent)
+		chans = append(chans, ch)
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			rb.Rebind(ch)
+		}()
+	}
+	if len(chans) == 0 {
+		return
+	}
+
+	q.wg.Add(1)
+	go q.rebindLoop(q.ifaceWatcher.Changes(), chans)
+}
+
+// rebindLoop drains changes until it's closed (q.ifaceWatcher.Stop() does
+// this), fanning each InterfaceUp/InterfaceDown out to every channel in
+// chans as a RebindEvent, then closes them so their Rebind goroutines
+// (started by startRebinding) exit too. Taking changes as a parameter
+// rather than reading q.ifaceWatcher directly keeps this testable without a
+// real transport.InterfaceWatcher.
+func (q *Querier) rebindLoop(changes <-chan transport.InterfaceChange, chans []chan transport.RebindEvent) {
+	defer q.wg.Done()
+	defer func() {
+		for _, ch := range chans {
+			close(ch)
+		}
+	}()
+
+	for change := range changes {
+		var ev transport.RebindEvent
+		switch change.Type {
+		case transport.InterfaceUp:
+			ev = transport.RebindEvent{Type: transport.RebindJoin, Interface: change.Interface}
+		case transport.InterfaceDown:
+			ev = transport.RebindEvent{Type: transport.RebindLeave, Interface: change.Interface}
+		default:
+			// AddressAdded/AddressRemoved don't change which interfaces are
+			// joined, only which addresses they carry - nothing to rebind.
+			continue
+		}
+
+		for _, ch := range chans {
+			select {
+			case ch <- ev:
+			case <-q.ctx.Done():
+				return
+			}
+		}
+	}
+}
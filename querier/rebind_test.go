@@ -0,0 +1,91 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestRebindLoop_TranslatesUpDownAndSkipsAddressEvents verifies
+// InterfaceUp/InterfaceDown become RebindJoin/RebindLeave fanned out to
+// every channel passed in, while AddressAdded/AddressRemoved (which don't
+// change which interfaces are joined) are not forwarded.
+func TestRebindLoop_TranslatesUpDownAndSkipsAddressEvents(t *testing.T) {
+	q := &Querier{ctx: context.Background()}
+
+	eth0 := net.Interface{Name: "eth0"}
+	changes := make(chan transport.InterfaceChange, 4)
+	changes <- transport.InterfaceChange{Type: transport.InterfaceUp, Interface: eth0}
+	changes <- transport.InterfaceChange{Type: transport.AddressAdded, Interface: eth0}
+	changes <- transport.InterfaceChange{Type: transport.InterfaceDown, Interface: eth0}
+	close(changes)
+
+	ch := make(chan transport.RebindEvent, 4)
+	q.wg.Add(1)
+	q.rebindLoop(changes, []chan transport.RebindEvent{ch})
+	q.wg.Wait()
+
+	var got []transport.RebindEvent
+	for ev := range ch {
+		got = append(got, ev)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d RebindEvents, want 2 (AddressAdded should be skipped): %+v", len(got), got)
+	}
+	if got[0].Type != transport.RebindJoin || got[0].Interface.Name != "eth0" {
+		t.Errorf("got[0] = %+v, want RebindJoin for eth0", got[0])
+	}
+	if got[1].Type != transport.RebindLeave || got[1].Interface.Name != "eth0" {
+		t.Errorf("got[1] = %+v, want RebindLeave for eth0", got[1])
+	}
+}
+
+// TestRebindLoop_FansOutToEveryChannel verifies a single InterfaceChange
+// reaches every channel passed in, not just the first - the DualStack case
+// where an IPv4 and an IPv6 endpoint both need to rejoin the same
+// interface.
+func TestRebindLoop_FansOutToEveryChannel(t *testing.T) {
+	q := &Querier{ctx: context.Background()}
+
+	changes := make(chan transport.InterfaceChange, 1)
+	changes <- transport.InterfaceChange{Type: transport.InterfaceUp, Interface: net.Interface{Name: "eth0"}}
+	close(changes)
+
+	chA := make(chan transport.RebindEvent, 1)
+	chB := make(chan transport.RebindEvent, 1)
+	q.wg.Add(1)
+	q.rebindLoop(changes, []chan transport.RebindEvent{chA, chB})
+	q.wg.Wait()
+
+	for _, ch := range []chan transport.RebindEvent{chA, chB} {
+		ev, ok := <-ch
+		if !ok {
+			t.Fatal("channel closed without receiving the RebindEvent")
+		}
+		if ev.Type != transport.RebindJoin {
+			t.Errorf("got %+v, want RebindJoin", ev)
+		}
+	}
+}
+
+// TestStartRebinding_NoEndpointSupportsRebind verifies startRebinding is a
+// no-op (doesn't block or panic) when no endpoint's transport implements
+// rebinder, e.g. a custom transport installed via WithTransport.
+func TestStartRebinding_NoEndpointSupportsRebind(t *testing.T) {
+	q := &Querier{
+		ctx:       context.Background(),
+		endpoints: []endpoint{{transport: transport.NewMockTransport()}},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		q.startRebinding()
+	}()
+	wg.Wait() // startRebinding must return promptly, not block forever
+}
@@ -4,7 +4,9 @@ package querier
 import (
 	"net"
 
+	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
 )
 
 // RecordType represents a DNS record type for querying.
@@ -14,6 +16,10 @@ import (
 //   - RecordTypePTR: Pointer records (service discovery)
 //   - RecordTypeSRV: Service records (hostname and port)
 //   - RecordTypeTXT: Text records (service metadata)
+//
+// Also supported, for IPv6 transport and combined resolution:
+//   - RecordTypeAAAA: IPv6 address records
+//   - RecordTypeANY: all of the above in one query
 type RecordType uint16
 
 const (
@@ -39,6 +45,20 @@ const (
 	// Used to get service hostname and port.
 	// Example: Query("webserver._http._tcp.local", RecordTypeSRV) → {Priority:0, Weight:0, Port:8080, Target:"server.local"}
 	RecordTypeSRV RecordType = RecordType(protocol.RecordTypeSRV)
+
+	// RecordTypeAAAA queries for IPv6 address records (type 28).
+	//
+	// Example: Query("printer.local", RecordTypeAAAA) → fe80::1
+	RecordTypeAAAA RecordType = RecordType(protocol.RecordTypeAAAA)
+
+	// RecordTypeANY queries for all record types at once (type 255).
+	//
+	// A response to an ANY query returns whatever A/AAAA/SRV/TXT/PTR
+	// records the responder has for name in a single round trip, so
+	// Query filters nothing out of the answer section when recordType is
+	// RecordTypeANY.
+	// Example: Query("webserver._http._tcp.local", RecordTypeANY) → mixed SRV/TXT/A/AAAA records
+	RecordTypeANY RecordType = RecordType(protocol.RecordTypeANY)
 )
 
 // String returns a human-readable name for the record type.
@@ -46,6 +66,52 @@ func (r RecordType) String() string {
 	return protocol.RecordType(r).String()
 }
 
+// ResponseStatus classifies how a Response concluded, distinguishing cases
+// an empty Records slice alone leaves ambiguous: nobody answered vs. a
+// responder authoritatively said the name doesn't exist. A caller that
+// wants to cache a negative result and stop retrying needs this distinction;
+// Records by itself can't provide it.
+type ResponseStatus int
+
+const (
+	// StatusOK means at least one responder answered and nothing suggested
+	// the name doesn't exist.
+	StatusOK ResponseStatus = iota
+
+	// StatusNoAnswer means the timeout window elapsed with no responders
+	// heard from at all - the ordinary, non-erroneous "nothing out there
+	// yet" case per FR-008.
+	StatusNoAnswer
+
+	// StatusNXDomain means a responder proved the name doesn't exist: an
+	// mDNS NSEC record (RFC 6762 §6.1) whose type bitmap excludes the
+	// queried type, or a unicast DNS-SD fallback reply with RCODE=NameError
+	// (see Rcode). Unlike StatusNoAnswer, this is safe to cache negatively.
+	StatusNXDomain
+
+	// StatusPartial means some responders answered but the context deadline
+	// was hit before a truncated (TC=1) reply's unicast-retry follow-up
+	// finished - Records holds whatever arrived before then, not the full
+	// picture.
+	StatusPartial
+)
+
+// String returns a human-readable name for the status.
+func (s ResponseStatus) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusNoAnswer:
+		return "NoAnswer"
+	case StatusNXDomain:
+		return "NXDomain"
+	case StatusPartial:
+		return "Partial"
+	default:
+		return "Unknown"
+	}
+}
+
 // Response represents the aggregated results from an mDNS query.
 //
 // Response contains all records received within the timeout window per FR-008.
@@ -59,6 +125,31 @@ type Response struct {
 	//
 	// Per FR-010, Authority records are ignored in M1.
 	Records []ResourceRecord
+
+	// Additionals holds every Additional-section record a response carried,
+	// regardless of queryType - unlike Records, which FR-010 restricts to
+	// the Answer section. A responder often bundles an instance's SRV/TXT,
+	// or an SRV target's A/AAAA, in Additionals alongside a PTR answer (RFC
+	// 6763 §12); ResolveInstances consults this to skip a query whose answer already
+	// arrived piggy-backed instead of re-asking the network for it.
+	Additionals []ResourceRecord
+
+	// Status classifies how this Response concluded - see ResponseStatus.
+	Status ResponseStatus
+
+	// Rcode is the 4-bit RCODE (RFC 1035 §4.1.1) of the most recent non-zero
+	// RCODE this Response's collection observed, or 0 if every response
+	// considered had RCODE=0 (or none arrived at all). A non-zero RCODE
+	// still causes the carrying packet to be dropped per FR-022/RFC 6762
+	// §18.11 - Rcode only records that it happened, for StatusNXDomain and
+	// diagnostics.
+	Rcode uint8
+
+	// OPT holds the EDNS(0) OPT pseudo-RR (RFC 6891 §6.1.2) from the first
+	// response that carried one, or nil if none did. Useful for reading a
+	// responder's negotiated UDP payload size, or an echoed NSID/DNSSEC-OK/
+	// cookie option sent via WithMaxUDPSize's EDNS probing.
+	OPT *message.OPTRecord
 }
 
 // ResourceRecord represents a single DNS resource record from an mDNS response.
@@ -66,13 +157,11 @@ type Response struct {
 // ResourceRecord provides access to both raw DNS fields and type-specific
 // parsed data through helper methods (AsA, AsPTR, AsSRV, AsTXT).
 type ResourceRecord struct {
-	// Data contains the type-specific parsed data:
-	//   - A record: net.IP (IPv4 address)
-	//   - PTR record: string (target domain name)
-	//   - SRV record: SRVData struct
-	//   - TXT record: []string (text strings)
+	// Data holds one of internal/message's RData implementations (AData,
+	// AAAAData, PTRData, SRVData, or TXTData), matching Type.
 	//
-	// Use AsA(), AsPTR(), AsSRV(), or AsTXT() for type-safe access.
+	// Use AsA(), AsAAAA(), AsPTR(), AsSRV(), or AsTXT() for type-safe access
+	// instead of asserting against internal/message's types directly.
 	Data interface{}
 
 	// Name is the domain name for this record (e.g., "printer.local").
@@ -88,6 +177,19 @@ type ResourceRecord struct {
 
 	// Class is the DNS class (typically IN=1 for Internet).
 	Class uint16
+
+	// Interface is the network interface this record's packet arrived on,
+	// when the underlying transport can report one (currently UDPv4Transport
+	// and UDPv6Transport, via their ReceiveBatch/transport.BatchReceiver
+	// implementation) - nil for a unicast response (QueryUnicast,
+	// QueryWithOptions{Unicast: true}, LegacyResolver) or any transport that
+	// only implements the plain single-packet Receive.
+	//
+	// Useful on a multi-homed host to tell apart two interfaces answering
+	// the same query with different addresses (e.g. a printer reachable on
+	// both Wi-Fi and a wired VLAN) instead of only seeing the merged record
+	// set.
+	Interface *net.Interface
 }
 
 // SRVData represents parsed SRV record data per RFC 2782.
@@ -124,12 +226,34 @@ func (r *ResourceRecord) AsA() net.IP {
 		return nil
 	}
 
-	ip, ok := r.Data.(net.IP)
+	data, ok := r.Data.(message.AData)
+	if !ok {
+		return nil
+	}
+
+	return net.IP(data.IP.AsSlice())
+}
+
+// AsAAAA returns the IPv6 address for an AAAA record, or nil if not an AAAA record.
+//
+// Example:
+//
+//	for _, record := range response.Records {
+//	    if ip := record.AsAAAA(); ip != nil {
+//	        fmt.Printf("Found IPv6: %s\n", ip)
+//	    }
+//	}
+func (r *ResourceRecord) AsAAAA() net.IP {
+	if r.Type != RecordTypeAAAA {
+		return nil
+	}
+
+	data, ok := r.Data.(message.AAAAData)
 	if !ok {
 		return nil
 	}
 
-	return ip
+	return net.IP(data.IP.AsSlice())
 }
 
 // AsPTR returns the target name for a PTR record, or empty string if not a PTR record.
@@ -146,12 +270,12 @@ func (r *ResourceRecord) AsPTR() string {
 		return ""
 	}
 
-	target, ok := r.Data.(string)
+	data, ok := r.Data.(message.PTRData)
 	if !ok {
 		return ""
 	}
 
-	return target
+	return data.Name
 }
 
 // AsSRV returns the SRV data for an SRV record, or nil if not an SRV record.
@@ -168,12 +292,17 @@ func (r *ResourceRecord) AsSRV() *SRVData {
 		return nil
 	}
 
-	srv, ok := r.Data.(SRVData)
+	srv, ok := r.Data.(message.SRVData)
 	if !ok {
 		return nil
 	}
 
-	return &srv
+	return &SRVData{
+		Target:   srv.Target,
+		Priority: srv.Priority,
+		Weight:   srv.Weight,
+		Port:     srv.Port,
+	}
 }
 
 // AsTXT returns the text strings for a TXT record, or nil if not a TXT record.
@@ -192,10 +321,39 @@ func (r *ResourceRecord) AsTXT() []string {
 		return nil
 	}
 
-	txt, ok := r.Data.([]string)
+	data, ok := r.Data.(message.TXTData)
+	if !ok {
+		return nil
+	}
+
+	return data.Entries
+}
+
+// AsTXTRecords returns the TXT record decoded into responder.TXTRecord's
+// structured form, or nil if not a TXT record. Unlike AsTXT, this preserves
+// the RFC 6763 §6.4 distinction between a boolean attribute ("key"), an
+// empty-value attribute ("key="), and "key=value", and lets binary values
+// (e.g. the Chromecast and HomeKit TXT keys) round-trip without mangling -
+// each value is the exact bytes that followed the entry's "=".
+//
+// Example:
+//
+//	for _, record := range response.Records {
+//	    if txt := record.AsTXTRecords(); txt != nil {
+//	        for _, r := range txt {
+//	            fmt.Printf("Metadata: %s=%s\n", r.Key, r.Value)
+//	        }
+//	    }
+//	}
+func (r *ResourceRecord) AsTXTRecords() []records.TXTRecord {
+	if r.Type != RecordTypeTXT {
+		return nil
+	}
+
+	data, ok := r.Data.(message.TXTData)
 	if !ok {
 		return nil
 	}
 
-	return txt
+	return records.DecodeTXTEntries(data.Entries)
 }
@@ -0,0 +1,73 @@
+package querier
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+)
+
+// PanicHandler is invoked, in addition to the Querier's standard
+// slog/metrics reporting, whenever recoverPanic recovers a panic from
+// receiveLoop while processing an inbound packet. recovered is the value
+// passed to panic; stack is the stack trace captured at the point of
+// recovery (runtime/debug.Stack()). Set via WithPanicHandler to wire in
+// Sentry/OTel error tracking without patching the library.
+type PanicHandler func(recovered any, stack []byte)
+
+// recoverPanic recovers a panic in the calling goroutine, if any, logging
+// it via q's logger with the stack trace and packet source address,
+// counting it against q's metrics (beacon_querier_handler_panics_total),
+// and invoking q.panicHandler if set. Call via defer as the first deferred
+// statement in any goroutine that processes attacker-controlled packet
+// data, so a single malformed response can't crash a long-running Querier
+// - analogous to a gRPC recovery interceptor. handlerName identifies the
+// call site (e.g. "receiveLoop") for the log record and counter; src, if
+// non-nil, is the packet's source address.
+func (q *Querier) recoverPanic(handlerName string, src net.Addr) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+
+	stack := debug.Stack()
+
+	attrs := []any{"handler", handlerName, "panic", fmt.Sprint(rec)}
+	if src != nil {
+		attrs = append(attrs, "source", src.String())
+	}
+	attrs = append(attrs, "stack", string(stack))
+	q.recoveryLogger().Error("recovered from panic in mDNS handler goroutine", attrs...)
+
+	if q.metricsActive() {
+		q.metrics.IncCounter("beacon_querier_handler_panics_total", map[string]string{"handler": handlerName})
+	}
+
+	q.invokePanicHandler(rec, stack)
+}
+
+// invokePanicHandler calls q.panicHandler, if set, guarding against a panic
+// inside the user-supplied handler itself - otherwise a buggy handler would
+// propagate an unrecovered panic out of the very goroutine this recovery
+// subsystem exists to protect.
+func (q *Querier) invokePanicHandler(rec any, stack []byte) {
+	if q.panicHandler == nil {
+		return
+	}
+	defer func() {
+		if handlerPanic := recover(); handlerPanic != nil {
+			q.recoveryLogger().Error("panicHandler itself panicked",
+				"panic", fmt.Sprint(handlerPanic), "stack", string(debug.Stack()))
+		}
+	}()
+	q.panicHandler(rec, stack)
+}
+
+// recoveryLogger returns q.logger, falling back to slog.Default() for a
+// Querier built without New() (whose logger field is still nil).
+func (q *Querier) recoveryLogger() *slog.Logger {
+	if q.logger == nil {
+		return slog.Default()
+	}
+	return q.logger
+}
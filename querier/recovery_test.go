@@ -0,0 +1,129 @@
+package querier
+
+import (
+	"net"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/security"
+)
+
+// TestQuerierRecoverPanic_InvokesHandlerAndMetrics verifies a recovered
+// panic increments beacon_querier_handler_panics_total and invokes
+// panicHandler with the recovered value and a non-empty stack trace.
+func TestQuerierRecoverPanic_InvokesHandlerAndMetrics(t *testing.T) {
+	fm := newFakeMetrics()
+	var gotRecovered any
+	var gotStack []byte
+	q := &Querier{
+		metrics: fm,
+		panicHandler: func(recovered any, stack []byte) {
+			gotRecovered = recovered
+			gotStack = stack
+		},
+	}
+
+	func() {
+		defer q.recoverPanic("testHandler", nil)
+		panic("boom")
+	}()
+
+	if gotRecovered != "boom" {
+		t.Errorf("panicHandler recovered = %v, want %q", gotRecovered, "boom")
+	}
+	if len(gotStack) == 0 {
+		t.Error("panicHandler stack = empty, want a captured stack trace")
+	}
+	if got := fm.count("beacon_querier_handler_panics_total"); got != 1 {
+		t.Errorf("beacon_querier_handler_panics_total = %d, want 1", got)
+	}
+}
+
+// TestQuerierRecoverPanic_NoopWithoutPanic verifies recoverPanic is a
+// no-op (no handler call, no metrics) when the deferred call site didn't
+// panic.
+func TestQuerierRecoverPanic_NoopWithoutPanic(t *testing.T) {
+	fm := newFakeMetrics()
+	called := false
+	q := &Querier{
+		metrics:      fm,
+		panicHandler: func(any, []byte) { called = true },
+	}
+
+	func() {
+		defer q.recoverPanic("testHandler", nil)
+	}()
+
+	if called {
+		t.Error("panicHandler called without a panic")
+	}
+	if got := fm.count("beacon_querier_handler_panics_total"); got != 0 {
+		t.Errorf("beacon_querier_handler_panics_total = %d, want 0", got)
+	}
+}
+
+// TestQuerierRecoverPanic_SafeOnZeroValueQuerier verifies recoverPanic
+// doesn't panic itself on a zero-value &Querier{} (nil logger/metrics/
+// panicHandler).
+func TestQuerierRecoverPanic_SafeOnZeroValueQuerier(t *testing.T) {
+	q := &Querier{}
+
+	func() {
+		defer q.recoverPanic("testHandler", &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+		panic("boom")
+	}()
+}
+
+// TestQuerierRecoverPanic_SurvivesPanicHandlerPanicking verifies a
+// panicHandler that itself panics doesn't escape recoverPanic and crash
+// the caller.
+func TestQuerierRecoverPanic_SurvivesPanicHandlerPanicking(t *testing.T) {
+	q := &Querier{
+		panicHandler: func(any, []byte) { panic("handler also broke") },
+	}
+
+	func() {
+		defer q.recoverPanic("testHandler", nil)
+		panic("boom")
+	}()
+}
+
+// TestProcessReceivedPacketSafely_RecoversPanic verifies that a panic deep
+// in rate-limit processing doesn't crash the caller, and is reported via
+// the configured panicHandler. A zero-value *security.RateLimiter (nil
+// internal map) panics on its first Allow call for an unseen source, which
+// is what actually exercises processReceivedPacketSafely's recoverPanic
+// guard - a nil/malformed packet alone doesn't reach any code that panics.
+func TestProcessReceivedPacketSafely_RecoversPanic(t *testing.T) {
+	var recovered any
+	q := &Querier{
+		maxResponseBytes: maxMDNSPacketSize,
+		rateLimitEnabled: true,
+		rateLimiter:      &security.RateLimiter{},
+		panicHandler:     func(rec any, _ []byte) { recovered = rec },
+	}
+
+	q.processReceivedPacketSafely([]byte("packet"), &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353}, nil)
+
+	if recovered == nil {
+		t.Fatal("panicHandler was not invoked, want processReceivedPacketSafely to recover the RateLimiter panic")
+	}
+}
+
+// TestQuerierWithPanicHandler_RejectsNil validates that
+// WithPanicHandler(nil) returns a ValidationError instead of silently
+// disabling reporting.
+func TestQuerierWithPanicHandler_RejectsNil(t *testing.T) {
+	q := &Querier{}
+	if err := WithPanicHandler(nil)(q); err == nil {
+		t.Fatal("WithPanicHandler(nil) returned nil error, want a ValidationError")
+	}
+}
+
+// TestQuerierWithLogger_RejectsNil validates that WithLogger(nil) returns
+// a ValidationError instead of silently disabling logging.
+func TestQuerierWithLogger_RejectsNil(t *testing.T) {
+	q := &Querier{}
+	if err := WithLogger(nil)(q); err == nil {
+		t.Fatal("WithLogger(nil) returned nil error, want a ValidationError")
+	}
+}
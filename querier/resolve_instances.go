@@ -0,0 +1,244 @@
+package querier
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/records"
+)
+
+// ResolvedInstance is one service instance resolved by Resolve, bundling its
+// SRV target/priority/weight, decoded TXT metadata, and resolved addresses -
+// the full PTR->SRV->TXT->A/AAAA chain - rather than BrowseSubtype's lighter
+// ServiceInstance, which stops at SRV target/port and raw TXT strings.
+type ResolvedInstance struct {
+	// Name is the full service instance name the PTR record pointed at
+	// (e.g., "My Printer._http._tcp.local").
+	Name string
+
+	// Host is the SRV record's target hostname, or empty if the instance's
+	// SRV record could not be resolved within ctx.
+	Host string
+
+	// Port is the SRV record's port, valid only when Host is non-empty.
+	Port uint16
+
+	// Priority and Weight are the SRV record's RFC 2782 fields, valid only
+	// when Host is non-empty.
+	Priority uint16
+	Weight   uint16
+
+	// TXT holds the instance's TXT record decoded into key/value pairs per
+	// RFC 6763 §6.4. A boolean key ("key", no "=") and an empty-value key
+	// ("key=") both appear here with an empty string value - use TXTRaw if
+	// that distinction, or a binary value, matters to the caller.
+	TXT map[string]string
+
+	// TXTRaw holds the same TXT record's entries as raw value bytes, in the
+	// order they appeared on the wire, for a caller that needs a binary
+	// value (e.g. the Chromecast and HomeKit TXT keys) or the
+	// boolean/empty-value distinction TXT's plain strings lose. It is not
+	// keyed: pair it positionally with records.DecodeTXTEntries(raw) on the
+	// instance's own TXT record if key alignment is needed.
+	TXTRaw [][]byte
+
+	// Addrs holds the instance's resolved A/AAAA addresses, or nil if none
+	// could be resolved within ctx.
+	Addrs []netip.Addr
+
+	// TTL is the PTR record's time-to-live, reflecting how long this
+	// instance listing itself stays valid (not the SRV/TXT/A records' own,
+	// potentially differing, TTLs).
+	TTL time.Duration
+}
+
+// ResolveInstances discovers every instance of serviceType (e.g.
+// "_http._tcp.local") and fully resolves each one's SRV, TXT, and A/AAAA
+// records, mirroring the manual PTR->SRV->TXT->A chain a caller would
+// otherwise have to run and correlate by hand. Named distinctly from
+// Resolver's Resolve(ctx, name, recordType) (*Response, error) - a single
+// record-type lookup used for Chain/Multiplex composition - since this
+// resolves a whole service type into fully-formed instances instead.
+//
+// Instances are resolved concurrently. Per RFC 6763 §12, a responder often
+// bundles an instance's SRV/TXT, or an SRV target's A/AAAA, as Additional
+// records alongside a PTR or SRV answer; ResolveInstances consults the PTR
+// response's Additionals (and, per instance, its own SRV response's
+// Additionals) before issuing a further query, so an already-piggy-backed
+// answer doesn't trigger a redundant round trip.
+//
+// A per-instance SRV, TXT, or address lookup that fails, times out, or
+// returns nothing is not fatal: the instance is still returned with the
+// affected field(s) left at their zero value (Host == "" and Port == 0 for
+// an unresolved SRV), matching Query's own no-results-is-not-an-error
+// convention and BrowseSubtype's partial-instance behavior.
+func (q *Querier) ResolveInstances(ctx context.Context, serviceType string) ([]ResolvedInstance, error) {
+	ptrResponse, err := q.Query(ctx, serviceType, RecordTypePTR)
+	if err != nil {
+		return nil, err
+	}
+
+	index := newAdditionalsIndex(ptrResponse.Additionals)
+
+	instances := make([]ResolvedInstance, len(ptrResponse.Records))
+	var wg sync.WaitGroup
+	for i, record := range ptrResponse.Records {
+		instanceName := record.AsPTR()
+		if instanceName == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, instanceName string, ttl uint32) {
+			defer wg.Done()
+			instances[i] = q.resolveInstanceFully(ctx, instanceName, ttl, index)
+		}(i, instanceName, record.TTL)
+	}
+	wg.Wait()
+
+	resolved := make([]ResolvedInstance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Name == "" {
+			continue // a non-PTR record sharing the response, skipped above
+		}
+		resolved = append(resolved, instance)
+	}
+	return resolved, nil
+}
+
+// resolveInstanceFully resolves instanceName's SRV, TXT, and address records,
+// consulting index (the PTR response's Additionals) before issuing a query
+// for data that already arrived piggy-backed.
+func (q *Querier) resolveInstanceFully(ctx context.Context, instanceName string, ttl uint32, index additionalsIndex) ResolvedInstance {
+	instance := ResolvedInstance{Name: instanceName, TTL: time.Duration(ttl) * time.Second}
+
+	srv, srvAdditionals := index.srv(instanceName)
+	if srv == nil {
+		srvResponse, err := q.Query(ctx, instanceName, RecordTypeSRV)
+		if err == nil {
+			for _, record := range srvResponse.Records {
+				if found := record.AsSRV(); found != nil {
+					srv = found
+					break
+				}
+			}
+			srvAdditionals = srvResponse.Additionals
+		}
+	}
+	if srv != nil {
+		instance.Host = srv.Target
+		instance.Port = srv.Port
+		instance.Priority = srv.Priority
+		instance.Weight = srv.Weight
+	}
+
+	if txt, found := index.txt(instanceName); found {
+		instance.TXT, instance.TXTRaw = decodeTXT(txt)
+	} else if txtResponse, err := q.Query(ctx, instanceName, RecordTypeTXT); err == nil {
+		for _, record := range txtResponse.Records {
+			if found := record.AsTXT(); found != nil {
+				instance.TXT, instance.TXTRaw = decodeTXT(found)
+				break
+			}
+		}
+	}
+
+	if instance.Host != "" {
+		if addrs, found := index.addrs(instance.Host); found {
+			instance.Addrs = addrs
+		} else if addrs, found := newAdditionalsIndex(srvAdditionals).addrs(instance.Host); found {
+			instance.Addrs = addrs
+		} else if addrResponse, err := q.Query(ctx, instance.Host, RecordTypeANY); err == nil {
+			instance.Addrs = resourceRecordAddrs(addrResponse.Records)
+		}
+	}
+
+	return instance
+}
+
+// decodeTXT decodes raw TXT record entries into ResolvedInstance's TXT/TXTRaw pair.
+func decodeTXT(raw []string) (map[string]string, [][]byte) {
+	decoded := records.DecodeTXTEntries(raw)
+	if len(decoded) == 0 {
+		return nil, nil
+	}
+
+	txt := make(map[string]string, len(decoded))
+	txtRaw := make([][]byte, 0, len(decoded))
+	for _, entry := range decoded {
+		txt[entry.Key] = string(entry.Value)
+		txtRaw = append(txtRaw, entry.Value)
+	}
+	return txt, txtRaw
+}
+
+// resourceRecordAddrs extracts every A/AAAA address from recs.
+func resourceRecordAddrs(recs []ResourceRecord) []netip.Addr {
+	var addrs []netip.Addr
+	for i := range recs {
+		if ip := recs[i].AsA(); ip != nil {
+			if addr, ok := netip.AddrFromSlice(ip); ok {
+				addrs = append(addrs, addr.Unmap())
+			}
+		}
+		if ip := recs[i].AsAAAA(); ip != nil {
+			if addr, ok := netip.AddrFromSlice(ip); ok {
+				addrs = append(addrs, addr.Unmap())
+			}
+		}
+	}
+	return addrs
+}
+
+// additionalsIndex looks up a Response's Additional-section records by name
+// and type, for ResolveInstances's RFC 6763 §12 piggy-backed-answer short-circuiting.
+type additionalsIndex struct {
+	records []ResourceRecord
+}
+
+func newAdditionalsIndex(recs []ResourceRecord) additionalsIndex {
+	return additionalsIndex{records: recs}
+}
+
+// srv returns name's SRV record from the index, if present, plus the same
+// index's records - reused by the caller as a fallback Additionals source
+// when resolving the SRV target's own address.
+func (idx additionalsIndex) srv(name string) (*SRVData, []ResourceRecord) {
+	for i := range idx.records {
+		if idx.records[i].Name == name {
+			if srv := idx.records[i].AsSRV(); srv != nil {
+				return srv, idx.records
+			}
+		}
+	}
+	return nil, nil
+}
+
+// txt returns name's TXT record's raw entries from the index, if present.
+func (idx additionalsIndex) txt(name string) ([]string, bool) {
+	for i := range idx.records {
+		if idx.records[i].Name == name {
+			if txt := idx.records[i].AsTXT(); txt != nil {
+				return txt, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// addrs returns every A/AAAA address the index holds for name, if any.
+func (idx additionalsIndex) addrs(name string) ([]netip.Addr, bool) {
+	var matching []ResourceRecord
+	for i := range idx.records {
+		if idx.records[i].Name == name {
+			matching = append(matching, idx.records[i])
+		}
+	}
+	if len(matching) == 0 {
+		return nil, false
+	}
+	addrs := resourceRecordAddrs(matching)
+	return addrs, len(addrs) > 0
+}
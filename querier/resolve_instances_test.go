@@ -0,0 +1,214 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// buildPTRWithAdditionals builds a response packet carrying a single PTR
+// answer for serviceType->instanceName, plus SRV/TXT/A records for the
+// instance in the Additional section (RFC 6763 §12), using the lower-level
+// MessageBuilder since message.BuildResponse only supports an Answers-only
+// packet.
+func buildPTRWithAdditionals(t *testing.T, serviceType, instanceName, host string, port uint16, txtEntries []string, ip net.IP) []byte {
+	t.Helper()
+
+	b := message.NewBuilder(message.DNSHeader{Flags: protocol.FlagQR | protocol.FlagAA})
+	if err := b.StartAnswers(); err != nil {
+		t.Fatalf("StartAnswers failed: %v", err)
+	}
+
+	ptrRDATA, err := message.PackRDATA(uint16(protocol.RecordTypePTR), message.PTRData{Name: instanceName})
+	if err != nil {
+		t.Fatalf("PackRDATA(PTR) failed: %v", err)
+	}
+	if err := b.Answer(message.Answer{NAME: serviceType, TYPE: uint16(protocol.RecordTypePTR), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: ptrRDATA}); err != nil {
+		t.Fatalf("Answer(PTR) failed: %v", err)
+	}
+
+	if err := b.StartAdditionals(); err != nil {
+		t.Fatalf("StartAdditionals failed: %v", err)
+	}
+
+	srvRDATA, err := message.PackRDATA(uint16(protocol.RecordTypeSRV), message.SRVData{Priority: 0, Weight: 0, Port: port, Target: host})
+	if err != nil {
+		t.Fatalf("PackRDATA(SRV) failed: %v", err)
+	}
+	if err := b.Answer(message.Answer{NAME: instanceName, TYPE: uint16(protocol.RecordTypeSRV), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: srvRDATA}); err != nil {
+		t.Fatalf("Answer(SRV, Additional) failed: %v", err)
+	}
+
+	txtRDATA, err := message.PackRDATA(uint16(protocol.RecordTypeTXT), message.TXTData{Entries: txtEntries})
+	if err != nil {
+		t.Fatalf("PackRDATA(TXT) failed: %v", err)
+	}
+	if err := b.Answer(message.Answer{NAME: instanceName, TYPE: uint16(protocol.RecordTypeTXT), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: txtRDATA}); err != nil {
+		t.Fatalf("Answer(TXT, Additional) failed: %v", err)
+	}
+
+	addr, _ := netip.AddrFromSlice(ip.To4())
+	aRDATA, err := message.PackRDATA(uint16(protocol.RecordTypeA), message.AData{IP: addr})
+	if err != nil {
+		t.Fatalf("PackRDATA(A) failed: %v", err)
+	}
+	if err := b.Answer(message.Answer{NAME: host, TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: aRDATA}); err != nil {
+		t.Fatalf("Answer(A, Additional) failed: %v", err)
+	}
+
+	packet, err := b.Finish()
+	if err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+	return packet
+}
+
+// TestResolveInstances_AdditionalSectionShortCircuits validates that
+// ResolveInstances consults the PTR response's Additionals before issuing
+// SRV/TXT/A follow-up queries, so a responder that bundled them per RFC
+// 6763 §12 elicits only the one PTR query instead of four.
+func TestResolveInstances_AdditionalSectionShortCircuits(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	packet := buildPTRWithAdditionals(t, "_http._tcp.local", "printer._http._tcp.local", "printer.local", 8080,
+		[]string{"version=1.0"}, net.IPv4(192, 168, 1, 1))
+	mock.QueueReceive(packet, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	instances, err := q.ResolveInstances(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("ResolveInstances() error = %v", err)
+	}
+
+	if len(instances) != 1 {
+		t.Fatalf("len(instances) = %d, want 1", len(instances))
+	}
+	instance := instances[0]
+	if instance.Host != "printer.local" || instance.Port != 8080 {
+		t.Errorf("instance Host/Port = %s:%d, want printer.local:8080", instance.Host, instance.Port)
+	}
+	if len(instance.Addrs) != 1 || instance.Addrs[0] != netip.MustParseAddr("192.168.1.1") {
+		t.Errorf("instance.Addrs = %v, want [192.168.1.1]", instance.Addrs)
+	}
+
+	if calls := mock.SendCalls(); len(calls) != 1 {
+		t.Errorf("SendCalls() = %d, want 1 (PTR only, SRV/TXT/A answered via Additionals)", len(calls))
+	}
+}
+
+// TestResolveInstances_DecodesTXT validates the RFC 6763 §6.4 TXT decoding
+// Resolve's contract calls for: a boolean key, an empty-value key, and a
+// binary value, the last of which only TXTRaw preserves losslessly.
+func TestResolveInstances_DecodesTXT(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	binaryValue := string([]byte{0x00, 0x01, 0xFF})
+	packet := buildPTRWithAdditionals(t, "_http._tcp.local", "printer._http._tcp.local", "printer.local", 8080,
+		[]string{"flag", "empty=", "bin=" + binaryValue}, net.IPv4(192, 168, 1, 1))
+	mock.QueueReceive(packet, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	instances, err := q.ResolveInstances(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("ResolveInstances() error = %v", err)
+	}
+	if len(instances) != 1 {
+		t.Fatalf("len(instances) = %d, want 1", len(instances))
+	}
+
+	instance := instances[0]
+	if v, ok := instance.TXT["flag"]; !ok || v != "" {
+		t.Errorf("TXT[flag] = %q, ok=%v, want \"\", true", v, ok)
+	}
+	if v, ok := instance.TXT["empty"]; !ok || v != "" {
+		t.Errorf("TXT[empty] = %q, ok=%v, want \"\", true", v, ok)
+	}
+	if v, ok := instance.TXT["bin"]; !ok || v != binaryValue {
+		t.Errorf("TXT[bin] = %q, ok=%v, want %q, true", v, ok, binaryValue)
+	}
+
+	found := false
+	for _, raw := range instance.TXTRaw {
+		if string(raw) == binaryValue {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("TXTRaw %v does not contain the binary value %q", instance.TXTRaw, []byte(binaryValue))
+	}
+}
+
+// TestResolveInstances_PartialTimeoutKeepsInstanceWithZeroPort validates
+// that an instance whose SRV/TXT follow-up queries get no reply within ctx
+// is still returned, with Host/Port left at their zero value, rather than
+// being dropped from the result - matching Query's own no-results-is-not-
+// an-error convention.
+func TestResolveInstances_PartialTimeoutKeepsInstanceWithZeroPort(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ptrPacket, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  mustEncodeName(t, "printer._http._tcp.local"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse() failed: %v", err)
+	}
+	mock.QueueReceive(ptrPacket, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	instances, err := q.ResolveInstances(ctx, "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("ResolveInstances() error = %v", err)
+	}
+
+	if len(instances) != 1 {
+		t.Fatalf("len(instances) = %d, want 1", len(instances))
+	}
+	instance := instances[0]
+	if instance.Name != "printer._http._tcp.local" {
+		t.Errorf("instance.Name = %q, want printer._http._tcp.local", instance.Name)
+	}
+	if instance.Host != "" || instance.Port != 0 {
+		t.Errorf("instance Host/Port = %q:%d, want \"\":0 (SRV never resolved)", instance.Host, instance.Port)
+	}
+}
+
+// mustEncodeName encodes name to wire format for use as a hand-built
+// message.ResourceRecord's Data field (PTR RDATA is an encoded name).
+func mustEncodeName(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := message.EncodeName(name)
+	if err != nil {
+		t.Fatalf("EncodeName(%q) failed: %v", name, err)
+	}
+	return encoded
+}
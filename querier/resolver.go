@@ -0,0 +1,49 @@
+package querier
+
+import "context"
+
+// Resolver resolves a name/recordType query to a Response, abstracting over
+// how the answer is actually obtained - mDNS multicast (*Querier itself),
+// unicast DNS (DNSResolver), a local hosts file (HostsResolver), an
+// in-memory TTL cache (CacheResolver), or a composition of several via Chain
+// or Multiplex. Modeled on gRPC's naming.Resolver abstraction.
+//
+// A Resolver follows Query's own convention: an empty Response with a nil
+// error means "nothing found", not a failure. A non-nil error means the
+// resolver itself couldn't attempt the lookup (invalid input, a hard
+// network failure, context cancellation).
+type Resolver interface {
+	Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error)
+}
+
+// WatchResolver is a Resolver that also supports long-lived observation via
+// Watch, mirroring Querier.Watch's channel-based API. This is kept as a
+// separate, optional interface rather than folded into Resolver since not
+// every Resolver can watch - a one-shot /etc/hosts lookup has nothing to
+// subscribe to.
+type WatchResolver interface {
+	Resolver
+	Watch(ctx context.Context, name string, recordType RecordType) (<-chan ResourceRecord, <-chan error)
+}
+
+// Resolve implements Resolver for *Querier by delegating to Query, so a
+// Querier can be used anywhere a Resolver is expected - directly, or as one
+// stage of a Chain/Multiplex alongside DNSResolver, HostsResolver, or
+// CacheResolver.
+func (q *Querier) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	return q.Query(ctx, name, recordType)
+}
+
+// Compile-time interface checks.
+var (
+	_ Resolver      = (*Querier)(nil)
+	_ WatchResolver = (*Querier)(nil)
+)
+
+// recordDedupeKey returns the same name+type+data dedupe key appendMatchingAnswers
+// computes from a raw answer, but for an already-decoded ResourceRecord -
+// used by Multiplex to merge multiple resolvers' results without reporting
+// the same record twice.
+func recordDedupeKey(r ResourceRecord) string {
+	return dedupeKeyFor(r.Name, uint16(r.Type), r.Data)
+}
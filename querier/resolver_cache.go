@@ -0,0 +1,84 @@
+package querier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheEntry is one CacheResolver entry: the records to serve and when they
+// stop being valid.
+type cacheEntry struct {
+	records []ResourceRecord
+	expires time.Time
+}
+
+// CacheResolver is an in-memory Resolver backed by a TTL-keyed cache,
+// populated via Put - typically with the records a wrapped resolver already
+// returned - and served without any further I/O until an entry expires.
+// Used as the first stage of a Chain so a frequently-resolved name doesn't
+// re-trigger a multicast query or a unicast DNS round-trip on every call.
+//
+// An expired or never-populated entry resolves to an empty Response (not an
+// error), matching Resolver's own "nothing found" convention, so a Chain
+// falls through to the next resolver rather than stopping on a cache miss.
+type CacheResolver struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewCacheResolver creates an empty CacheResolver.
+func NewCacheResolver() *CacheResolver {
+	return &CacheResolver{entries: make(map[string]cacheEntry)}
+}
+
+// Put stores records for name/recordType, valid for ttl. A ttl <= 0 expires
+// the entry immediately.
+func (c *CacheResolver) Put(name string, recordType RecordType, records []ResourceRecord, ttl time.Duration) {
+	key := cacheResolverKey(name, recordType)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{
+		records: records,
+		expires: time.Now().Add(ttl),
+	}
+}
+
+// Resolve implements Resolver, serving Put's most recent still-fresh entry
+// for name/recordType, or an empty Response if there isn't one.
+func (c *CacheResolver) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	default:
+	}
+
+	key := cacheResolverKey(name, recordType)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return &Response{Records: make([]ResourceRecord, 0)}, nil
+	}
+
+	records := make([]ResourceRecord, len(entry.records))
+	copy(records, entry.records)
+	return &Response{Records: records}, nil
+}
+
+// cacheResolverKey returns the entries map key for name/recordType, matching
+// names case-insensitively per RFC 1035 §2.3.3.
+func cacheResolverKey(name string, recordType RecordType) string {
+	return fmt.Sprintf("%s|%d", strings.ToLower(name), recordType)
+}
+
+var _ Resolver = (*CacheResolver)(nil)
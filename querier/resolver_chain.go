@@ -0,0 +1,107 @@
+package querier
+
+import (
+	"context"
+	goerrors "errors"
+)
+
+// chainResolver tries each of its Resolvers in order, returning the first
+// one's response once it reports at least one record.
+type chainResolver []Resolver
+
+// Chain returns a Resolver that tries each of resolvers in order, returning
+// the first response carrying at least one record. A resolver erroring
+// (rather than just reporting nothing found) is recorded but doesn't stop
+// the chain; if every resolver errors and none found anything, Chain joins
+// and returns all of their errors.
+//
+// Typical use: prefer a cheap local answer before falling through to the
+// network.
+//
+//	resolver := querier.Chain(hostsResolver, cacheResolver, q, dnsResolver)
+func Chain(resolvers ...Resolver) Resolver {
+	return chainResolver(resolvers)
+}
+
+// Resolve implements Resolver.
+func (c chainResolver) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	var errs []error
+	for _, r := range c {
+		resp, err := r.Resolve(ctx, name, recordType)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(resp.Records) > 0 {
+			return resp, nil
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, goerrors.Join(errs...)
+	}
+	return &Response{Records: make([]ResourceRecord, 0)}, nil
+}
+
+// multiplexResolver fans a query out to every one of its Resolvers
+// concurrently and merges the results.
+type multiplexResolver []Resolver
+
+// Multiplex returns a Resolver that queries every one of resolvers
+// concurrently and merges their responses into one, deduplicating records
+// the same way collectResponses does (by name+type+decoded-data), so a
+// mixed mDNS + unicast DNS setup doesn't report the same host twice.
+//
+// Typical use: query mDNS and unicast DNS at once and take whatever answers
+// first, rather than waiting for mDNS to time out before trying DNS.
+//
+//	resolver := querier.Multiplex(q, dnsResolver)
+func Multiplex(resolvers ...Resolver) Resolver {
+	return multiplexResolver(resolvers)
+}
+
+// Resolve implements Resolver.
+func (m multiplexResolver) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	type result struct {
+		resp *Response
+		err  error
+	}
+
+	results := make(chan result, len(m))
+	for _, r := range m {
+		go func(r Resolver) {
+			resp, err := r.Resolve(ctx, name, recordType)
+			results <- result{resp: resp, err: err}
+		}(r)
+	}
+
+	merged := &Response{Records: make([]ResourceRecord, 0)}
+	seen := make(map[string]bool)
+	var errs []error
+
+	for i := 0; i < len(m); i++ {
+		res := <-results
+		if res.err != nil {
+			errs = append(errs, res.err)
+			continue
+		}
+		for _, rec := range res.resp.Records {
+			key := recordDedupeKey(rec)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged.Records = append(merged.Records, rec)
+		}
+	}
+
+	if len(merged.Records) == 0 && len(errs) == len(m) && len(m) > 0 {
+		return nil, goerrors.Join(errs...)
+	}
+	return merged, nil
+}
+
+var (
+	_ Resolver = (chainResolver)(nil)
+	_ Resolver = (multiplexResolver)(nil)
+)
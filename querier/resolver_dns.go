@@ -0,0 +1,114 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// DNSResolver implements Resolver using standard unicast DNS (net.Resolver),
+// for names mDNS multicast can't reach - a DNS-SD zone published through a
+// regular recursive resolver, or a plain off-LAN lookup. It's the natural
+// second stage after a *Querier in a Chain: try mDNS first, fall back to
+// unicast DNS.
+//
+// Only RecordTypeA, RecordTypeAAAA, RecordTypeTXT, and RecordTypeANY are
+// supported, since those are the lookups net.Resolver exposes for a single
+// owner name; RecordTypePTR and RecordTypeSRV return a ValidationError,
+// since net.Resolver's LookupSRV takes a service/proto/name triple rather
+// than one DNS owner name and has no PTR-by-name equivalent at all.
+// RecordTypeANY here returns only address records (A/AAAA), unlike a
+// *Querier's ANY which returns every answer-section record type, since
+// net.Resolver has no single "give me everything" call.
+type DNSResolver struct {
+	// Resolver is the underlying resolver to use. Nil uses net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+// NewDNSResolver creates a DNSResolver using net.DefaultResolver.
+func NewDNSResolver() *DNSResolver {
+	return &DNSResolver{}
+}
+
+func (r *DNSResolver) resolver() *net.Resolver {
+	if r.Resolver != nil {
+		return r.Resolver
+	}
+	return net.DefaultResolver
+}
+
+// Resolve implements Resolver.
+func (r *DNSResolver) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	default:
+	}
+
+	if err := protocol.ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	response := &Response{Records: make([]ResourceRecord, 0)}
+
+	switch recordType {
+	case RecordTypeA, RecordTypeAAAA, RecordTypeANY:
+		network := "ip4"
+		if recordType == RecordTypeAAAA {
+			network = "ip6"
+		} else if recordType == RecordTypeANY {
+			network = "ip"
+		}
+
+		ips, err := r.resolver().LookupIP(ctx, network, name)
+		if err != nil {
+			// NXDOMAIN/no-answer is "nothing found", not a failure - mirrors
+			// Query's own FR-008 timeout convention.
+			return response, nil
+		}
+		for _, ip := range ips {
+			addr, ok := netip.AddrFromSlice(ip)
+			if !ok {
+				continue
+			}
+			addr = addr.Unmap()
+
+			rt := RecordTypeAAAA
+			data := interface{}(message.AAAAData{IP: addr})
+			if addr.Is4() {
+				rt = RecordTypeA
+				data = message.AData{IP: addr}
+			}
+			if recordType != RecordTypeANY && rt != recordType {
+				continue
+			}
+			response.Records = append(response.Records, ResourceRecord{
+				Name: name, Type: rt, Class: 1, Data: data,
+			})
+		}
+		return response, nil
+
+	case RecordTypeTXT:
+		txt, err := r.resolver().LookupTXT(ctx, name)
+		if err != nil {
+			return response, nil
+		}
+		response.Records = append(response.Records, ResourceRecord{
+			Name: name, Type: RecordTypeTXT, Class: 1, Data: message.TXTData{Entries: txt},
+		})
+		return response, nil
+
+	default:
+		return nil, &errors.ValidationError{
+			Field:   "recordType",
+			Value:   recordType,
+			Message: "DNSResolver only supports A, AAAA, TXT, and ANY lookups",
+		}
+	}
+}
+
+var _ Resolver = (*DNSResolver)(nil)
@@ -0,0 +1,104 @@
+package querier
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// HostsResolver implements Resolver by parsing a hosts-file-formatted
+// io.Reader (e.g. /etc/hosts) once at construction and answering A/AAAA
+// queries against it, for names pinned locally without any network traffic.
+// It's typically the first stage in a Chain, ahead of a *Querier or
+// DNSResolver, so an operator override always wins.
+type HostsResolver struct {
+	mu     sync.RWMutex
+	byName map[string][]net.IP
+}
+
+// NewHostsResolver parses r in hosts-file format (one "IP name1 name2 ..."
+// entry per line, "#" starting a comment) and returns a HostsResolver
+// serving it. Names are matched case-insensitively, per RFC 1035 §2.3.3.
+func NewHostsResolver(r io.Reader) (*HostsResolver, error) {
+	hr := &HostsResolver{byName: make(map[string][]net.IP)}
+	if err := hr.load(r); err != nil {
+		return nil, err
+	}
+	return hr, nil
+}
+
+func (hr *HostsResolver) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			continue
+		}
+
+		for _, name := range fields[1:] {
+			key := strings.ToLower(name)
+			hr.byName[key] = append(hr.byName[key], ip)
+		}
+	}
+	return scanner.Err()
+}
+
+// Resolve implements Resolver, matching A records against IPv4 entries and
+// AAAA against IPv6 entries; RecordTypeANY returns both.
+func (hr *HostsResolver) Resolve(ctx context.Context, name string, recordType RecordType) (*Response, error) {
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	default:
+	}
+
+	if err := protocol.ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	hr.mu.RLock()
+	ips := hr.byName[strings.ToLower(name)]
+	hr.mu.RUnlock()
+
+	response := &Response{Records: make([]ResourceRecord, 0, len(ips))}
+	for _, ip := range ips {
+		addr, ok := netip.AddrFromSlice(ip)
+		if !ok {
+			continue
+		}
+		addr = addr.Unmap()
+
+		rt := RecordTypeAAAA
+		data := interface{}(message.AAAAData{IP: addr})
+		if addr.Is4() {
+			rt = RecordTypeA
+			data = message.AData{IP: addr}
+		}
+		if recordType != RecordTypeANY && rt != recordType {
+			continue
+		}
+		response.Records = append(response.Records, ResourceRecord{
+			Name: name, Type: rt, Class: 1, Data: data,
+		})
+	}
+	return response, nil
+}
+
+var _ Resolver = (*HostsResolver)(nil)
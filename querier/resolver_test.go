@@ -0,0 +1,179 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// fakeResolver is a minimal Resolver for composing Chain/Multiplex tests
+// without any real network or Querier.
+type fakeResolver struct {
+	resp *Response
+	err  error
+}
+
+func (f *fakeResolver) Resolve(_ context.Context, _ string, _ RecordType) (*Response, error) {
+	return f.resp, f.err
+}
+
+func recordA(name string, ip net.IP) ResourceRecord {
+	addr, _ := netip.AddrFromSlice(ip.To4())
+	return ResourceRecord{Name: name, Type: RecordTypeA, Class: 1, Data: message.AData{IP: addr}}
+}
+
+// TestQuerier_ImplementsWatchResolver validates that *Querier satisfies both
+// Resolver and WatchResolver via its Query/Watch methods.
+func TestQuerier_ImplementsWatchResolver(t *testing.T) {
+	var _ WatchResolver = (*Querier)(nil)
+}
+
+// TestDNSResolver_RejectsUnsupportedRecordType validates that DNSResolver
+// returns a ValidationError for PTR/SRV, which net.Resolver has no
+// name-only lookup for.
+func TestDNSResolver_RejectsUnsupportedRecordType(t *testing.T) {
+	r := NewDNSResolver()
+
+	_, err := r.Resolve(context.Background(), "example.com", RecordTypeSRV)
+	if err == nil {
+		t.Fatal("Resolve() with RecordTypeSRV returned nil error, want a ValidationError")
+	}
+}
+
+// TestDNSResolver_ValidatesName validates that DNSResolver rejects an
+// invalid name before attempting any lookup.
+func TestDNSResolver_ValidatesName(t *testing.T) {
+	r := NewDNSResolver()
+
+	_, err := r.Resolve(context.Background(), "", RecordTypeA)
+	if err == nil {
+		t.Fatal("Resolve() with empty name returned nil error, want a ValidationError")
+	}
+}
+
+// TestHostsResolver_ResolvesConfiguredName validates that HostsResolver
+// answers an A query from parsed hosts-file content.
+func TestHostsResolver_ResolvesConfiguredName(t *testing.T) {
+	hr, err := NewHostsResolver(strings.NewReader("192.168.1.5 printer printer.local\n"))
+	if err != nil {
+		t.Fatalf("NewHostsResolver() failed: %v", err)
+	}
+
+	resp, err := hr.Resolve(context.Background(), "printer.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if len(resp.Records) != 1 || !resp.Records[0].AsA().Equal(net.IPv4(192, 168, 1, 5)) {
+		t.Errorf("Resolve() records = %+v, want one A record for 192.168.1.5", resp.Records)
+	}
+}
+
+// TestHostsResolver_UnknownNameReturnsEmpty validates that an unconfigured
+// name resolves to an empty Response rather than an error.
+func TestHostsResolver_UnknownNameReturnsEmpty(t *testing.T) {
+	hr, err := NewHostsResolver(strings.NewReader("192.168.1.5 printer.local\n"))
+	if err != nil {
+		t.Fatalf("NewHostsResolver() failed: %v", err)
+	}
+
+	resp, err := hr.Resolve(context.Background(), "unknown.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if len(resp.Records) != 0 {
+		t.Errorf("Resolve() records = %+v, want none", resp.Records)
+	}
+}
+
+// TestCacheResolver_ServesUntilExpiry validates that CacheResolver serves a
+// Put entry until its TTL elapses, then reports an empty Response.
+func TestCacheResolver_ServesUntilExpiry(t *testing.T) {
+	c := NewCacheResolver()
+	c.Put("printer.local", RecordTypeA, []ResourceRecord{recordA("printer.local", net.IPv4(10, 0, 0, 1))}, 50*time.Millisecond)
+
+	resp, err := c.Resolve(context.Background(), "printer.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if len(resp.Records) != 1 {
+		t.Fatalf("Resolve() records = %+v, want one cached record", resp.Records)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	resp, err = c.Resolve(context.Background(), "printer.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() after expiry failed: %v", err)
+	}
+	if len(resp.Records) != 0 {
+		t.Errorf("Resolve() after expiry records = %+v, want none", resp.Records)
+	}
+}
+
+// TestChain_ReturnsFirstNonEmptyResult validates that Chain stops at the
+// first resolver reporting at least one record, skipping earlier empty ones.
+func TestChain_ReturnsFirstNonEmptyResult(t *testing.T) {
+	empty := &fakeResolver{resp: &Response{Records: []ResourceRecord{}}}
+	hit := &fakeResolver{resp: &Response{Records: []ResourceRecord{recordA("printer.local", net.IPv4(10, 0, 0, 2))}}}
+	unreached := &fakeResolver{resp: &Response{Records: []ResourceRecord{recordA("printer.local", net.IPv4(10, 0, 0, 99))}}}
+
+	chain := Chain(empty, hit, unreached)
+
+	resp, err := chain.Resolve(context.Background(), "printer.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if len(resp.Records) != 1 || !resp.Records[0].AsA().Equal(net.IPv4(10, 0, 0, 2)) {
+		t.Errorf("Resolve() records = %+v, want the first non-empty resolver's record", resp.Records)
+	}
+}
+
+// TestChain_JoinsErrorsWhenNothingFound validates that Chain returns a
+// joined error when every resolver errors and none found anything.
+func TestChain_JoinsErrorsWhenNothingFound(t *testing.T) {
+	errA := errors.New("resolver A failed")
+	errB := errors.New("resolver B failed")
+	chain := Chain(&fakeResolver{err: errA}, &fakeResolver{err: errB})
+
+	_, err := chain.Resolve(context.Background(), "printer.local", RecordTypeA)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Resolve() error = %v, want it to wrap both %v and %v", err, errA, errB)
+	}
+}
+
+// TestMultiplex_MergesAndDedupes validates that Multiplex merges distinct
+// records from multiple resolvers and drops an exact duplicate.
+func TestMultiplex_MergesAndDedupes(t *testing.T) {
+	a := recordA("printer.local", net.IPv4(10, 0, 0, 3))
+	b := recordA("printer.local", net.IPv4(10, 0, 0, 4))
+
+	r1 := &fakeResolver{resp: &Response{Records: []ResourceRecord{a}}}
+	r2 := &fakeResolver{resp: &Response{Records: []ResourceRecord{a, b}}} // a is a duplicate of r1's
+
+	resp, err := Multiplex(r1, r2).Resolve(context.Background(), "printer.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if len(resp.Records) != 2 {
+		t.Errorf("Resolve() records = %+v, want 2 deduplicated records", resp.Records)
+	}
+}
+
+// TestMultiplex_AllErrorReturnsJoinedError validates that Multiplex returns
+// a joined error when every resolver fails.
+func TestMultiplex_AllErrorReturnsJoinedError(t *testing.T) {
+	errA := errors.New("resolver A failed")
+	errB := errors.New("resolver B failed")
+
+	_, err := Multiplex(&fakeResolver{err: errA}, &fakeResolver{err: errB}).
+		Resolve(context.Background(), "printer.local", RecordTypeA)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Resolve() error = %v, want it to wrap both %v and %v", err, errA, errB)
+	}
+}
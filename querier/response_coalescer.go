@@ -0,0 +1,104 @@
+package querier
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// coalesceWindow is how long ResponseCoalescer waits for a TC=1 source's
+// follow-up packets before delivering whatever it has, within the
+// "just a little while" RFC 6762 §6 asks a querier to wait for additional
+// response packets.
+const coalesceWindow = 450 * time.Millisecond
+
+// CoalescedResponse is what ResponseCoalescer delivers once a source's
+// response set is complete: every Answer collected across that source's
+// packets, paired with the source address they came from.
+type CoalescedResponse struct {
+	Source  net.Addr
+	Answers []message.Answer
+}
+
+// ResponseCoalescer buffers responses per source address, waiting up to
+// coalesceWindow for further packets the same source sends after setting
+// TC=1, before delivering the merged answer set to Sink.
+//
+// Unlike Querier.collectResponses' TC=1 handling - which escalates to a
+// fresh unicast-reply query, see retryTruncatedOverUnicast in querier.go -
+// ResponseCoalescer is for a passive listener with no outstanding Query of
+// its own to retry against (e.g. a cache-warming background reader), so it
+// can only wait for whatever arrives, not ask again.
+//
+// Safe for concurrent use.
+type ResponseCoalescer struct {
+	// Sink receives each source's merged response set once complete.
+	// Required.
+	Sink func(CoalescedResponse)
+
+	mu      sync.Mutex
+	pending map[string]*coalesceEntry
+}
+
+// coalesceEntry accumulates one source's answers while its TC=1 window is
+// open.
+type coalesceEntry struct {
+	source  net.Addr
+	answers []message.Answer
+	timer   *time.Timer
+}
+
+// NewResponseCoalescer creates a ResponseCoalescer that delivers each
+// source's merged response set to sink once complete.
+func NewResponseCoalescer(sink func(CoalescedResponse)) *ResponseCoalescer {
+	return &ResponseCoalescer{Sink: sink, pending: make(map[string]*coalesceEntry)}
+}
+
+// Add feeds one parsed response packet from src into the coalescer. If msg
+// sets TC (more records to follow from src), Add buffers msg.Answers and
+// (re)starts the coalesceWindow timer rather than delivering immediately;
+// otherwise it merges in whatever was already buffered for src and
+// delivers the result right away.
+func (c *ResponseCoalescer) Add(src net.Addr, msg *message.DNSMessage) {
+	key := src.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.pending[key]
+	if !ok {
+		entry = &coalesceEntry{source: src}
+		c.pending[key] = entry
+	}
+	entry.answers = append(entry.answers, msg.Answers...)
+
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+
+	if msg.Header.Truncated {
+		entry.timer = time.AfterFunc(coalesceWindow, func() { c.deliver(key) })
+		return
+	}
+
+	delete(c.pending, key)
+	c.Sink(CoalescedResponse{Source: entry.source, Answers: entry.answers})
+}
+
+// deliver flushes whatever is buffered for key once its coalesceWindow
+// elapses without a final (TC=0) packet arriving - a lost final packet
+// still surfaces everything collected so far rather than waiting forever.
+func (c *ResponseCoalescer) deliver(key string) {
+	c.mu.Lock()
+	entry, ok := c.pending[key]
+	if ok {
+		delete(c.pending, key)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.Sink(CoalescedResponse{Source: entry.source, Answers: entry.answers})
+	}
+}
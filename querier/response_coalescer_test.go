@@ -0,0 +1,94 @@
+package querier
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestResponseCoalescer_Add_NonTruncatedDeliversImmediately verifies that a
+// TC=0 packet is delivered to Sink right away, with no wait.
+func TestResponseCoalescer_Add_NonTruncatedDeliversImmediately(t *testing.T) {
+	delivered := make(chan CoalescedResponse, 1)
+	c := NewResponseCoalescer(func(r CoalescedResponse) { delivered <- r })
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.168.1.50"), Port: 5353}
+	msg := &message.DNSMessage{
+		Answers: []message.Answer{{NAME: "host.local", TYPE: uint16(protocol.RecordTypeA)}},
+	}
+
+	c.Add(src, msg)
+
+	select {
+	case r := <-delivered:
+		if len(r.Answers) != 1 {
+			t.Errorf("len(Answers) = %d, want 1", len(r.Answers))
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("Sink was not called for a non-truncated packet")
+	}
+}
+
+// TestResponseCoalescer_Add_TruncatedWaitsForFollowup verifies that a TC=1
+// packet is held back and merged with a follow-up from the same source
+// before Sink is called.
+func TestResponseCoalescer_Add_TruncatedWaitsForFollowup(t *testing.T) {
+	delivered := make(chan CoalescedResponse, 1)
+	c := NewResponseCoalescer(func(r CoalescedResponse) { delivered <- r })
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.168.1.50"), Port: 5353}
+	first := &message.DNSMessage{
+		Header:  message.DNSHeader{Truncated: true},
+		Answers: []message.Answer{{NAME: "a.local", TYPE: uint16(protocol.RecordTypeA)}},
+	}
+	second := &message.DNSMessage{
+		Answers: []message.Answer{{NAME: "b.local", TYPE: uint16(protocol.RecordTypeA)}},
+	}
+
+	c.Add(src, first)
+
+	select {
+	case <-delivered:
+		t.Fatal("Sink was called before the follow-up packet arrived")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.Add(src, second)
+
+	select {
+	case r := <-delivered:
+		if len(r.Answers) != 2 {
+			t.Errorf("len(Answers) = %d, want 2 (merged across both packets)", len(r.Answers))
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("Sink was not called after the follow-up packet")
+	}
+}
+
+// TestResponseCoalescer_Add_TruncatedDeliversAfterWindowOnLostFollowup
+// verifies that a TC=1 packet with no follow-up still delivers, once
+// coalesceWindow elapses, rather than waiting forever.
+func TestResponseCoalescer_Add_TruncatedDeliversAfterWindowOnLostFollowup(t *testing.T) {
+	delivered := make(chan CoalescedResponse, 1)
+	c := NewResponseCoalescer(func(r CoalescedResponse) { delivered <- r })
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.168.1.50"), Port: 5353}
+	msg := &message.DNSMessage{
+		Header:  message.DNSHeader{Truncated: true},
+		Answers: []message.Answer{{NAME: "a.local", TYPE: uint16(protocol.RecordTypeA)}},
+	}
+
+	c.Add(src, msg)
+
+	select {
+	case r := <-delivered:
+		if len(r.Answers) != 1 {
+			t.Errorf("len(Answers) = %d, want 1", len(r.Answers))
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("Sink was not called after coalesceWindow elapsed")
+	}
+}
@@ -0,0 +1,133 @@
+package querier
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// RetryPolicy configures how Query re-sends and re-collects when an attempt
+// receives zero records before its deadline, mirroring gRPC's BackoffConfig
+// shape. This targets lossy links (e.g. Wi-Fi) where a single ~300B
+// multicast query or reply is frequently dropped outright.
+//
+// The formula - delay = min(BaseDelay*Multiplier^attempt, MaxDelay) *
+// (1 + Jitter*rand[-1,1]) - matches gRPC's connection backoff, same as
+// WatchBackoffPolicy. Unlike WatchBackoffPolicy (which governs recovery from
+// transport errors across a long-lived Watch session), RetryPolicy bounds a
+// single Query call to MaxAttempts and never outlives ctx's deadline: each
+// attempt's collection window, and the backoff delay between attempts, are
+// cut short rather than extended when ctx.Deadline() is near.
+type RetryPolicy struct {
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed delay regardless of how many attempts have
+	// failed in a row.
+	MaxDelay time.Duration
+
+	// Multiplier is applied per additional zero-answer attempt.
+	Multiplier float64
+
+	// Jitter is the fraction of the computed delay to randomly perturb by,
+	// in either direction, so concurrent queriers retrying after the same
+	// dropped broadcast don't all re-query in lockstep (and risk a retry
+	// storm on the shared multicast group).
+	Jitter float64
+
+	// MaxAttempts is the maximum number of times Query sends and collects,
+	// including the first attempt. A Query that still has zero records
+	// after MaxAttempts attempts returns that empty result, same as
+	// Query's original single-attempt behavior.
+	MaxAttempts int
+}
+
+// DefaultRetryPolicy returns the default RetryPolicy: a 200ms base delay,
+// 1.6x growth multiplier, 2 second ceiling, ±20% jitter, and 3 attempts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Multiplier:  1.6,
+		Jitter:      0.2,
+		MaxAttempts: 3,
+	}
+}
+
+// next returns the delay to wait before retrying after the attempt-th
+// consecutive zero-answer attempt (0-indexed: the first retry is attempt 0).
+func (p RetryPolicy) next(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempt))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	jitter := 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(delay * jitter)
+}
+
+// queryWithRetry re-sends queryMsg and re-collects per q.retryPolicy until an
+// attempt collects at least one record or MaxAttempts is reached. It never
+// holds q.mu itself - the caller (Query) already does - and never extends
+// past ctx's own deadline: each attempt is bounded by q.defaultTimeout via
+// attemptDeadline, and the inter-attempt backoff is abandoned the moment ctx
+// is done.
+func (q *Querier) queryWithRetry(ctx context.Context, queryMsg []byte, name string, recordType RecordType) (*Response, error) {
+	policy := *q.retryPolicy
+
+	// Parsed once, outside the loop, since every attempt re-sends the same
+	// queryMsg bytes (and so the same ID) - see collectResponses's
+	// correlation against it via message.ValidateResponse.
+	parsedQuery, err := message.ParseMessage(queryMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *Response
+	for attempt := 0; ; attempt++ {
+		if err := q.sendQuery(ctx, queryMsg); err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := attemptDeadline(ctx, q.defaultTimeout)
+		resp, err := q.collectResponses(attemptCtx, name, recordType, parsedQuery, false)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		response = resp
+
+		if len(response.Records) > 0 || attempt+1 >= policy.MaxAttempts {
+			return response, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return response, nil
+		default:
+		}
+
+		timer := time.NewTimer(policy.next(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, nil
+		case <-timer.C:
+		}
+	}
+}
+
+// attemptDeadline bounds ctx to at most timeout for a single retry attempt,
+// so no attempt within queryWithRetry runs longer than the per-attempt
+// collection window - while context.WithTimeout's own min-of-parent-and-child
+// semantics still make ctx's own (nearer) deadline win when it's tighter.
+// timeout <= 0 (no WithTimeout configured) leaves ctx's deadline as-is.
+func attemptDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
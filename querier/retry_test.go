@@ -0,0 +1,54 @@
+package querier
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRetryPolicy_NextCapsAtMaxDelay validates that repeated zero-answer
+// attempts never exceed MaxDelay, even disregarding jitter.
+func TestRetryPolicy_NextCapsAtMaxDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 200 * time.Millisecond, Multiplier: 1.6, MaxDelay: 2 * time.Second, Jitter: 0}
+
+	delay := policy.next(20)
+	if delay != 2*time.Second {
+		t.Errorf("next(20) = %v, want exactly MaxDelay (2s) with zero jitter", delay)
+	}
+}
+
+// TestRetryPolicy_NextGrowsWithAttempt validates that the delay increases
+// across attempts before hitting the cap.
+func TestRetryPolicy_NextGrowsWithAttempt(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 200 * time.Millisecond, Multiplier: 1.6, MaxDelay: time.Minute, Jitter: 0}
+
+	d0 := policy.next(0)
+	d1 := policy.next(1)
+	if d0 != 200*time.Millisecond {
+		t.Errorf("next(0) = %v, want BaseDelay (200ms) with zero jitter", d0)
+	}
+	if d1 <= d0 {
+		t.Errorf("next(1) = %v, want greater than next(0) = %v", d1, d0)
+	}
+}
+
+// TestDefaultRetryPolicy_IsValid checks WithRetry accepts the documented
+// default without error, since that default is the one example callers are
+// most likely to copy verbatim.
+func TestDefaultRetryPolicy_IsValid(t *testing.T) {
+	err := WithRetry(DefaultRetryPolicy())(&Querier{})
+	if err != nil {
+		t.Errorf("WithRetry(DefaultRetryPolicy()) returned error: %v", err)
+	}
+}
+
+// TestWithRetry_RejectsInvalidMaxAttempts validates that a policy requesting
+// fewer than one attempt is rejected, since queryWithRetry's loop assumes at
+// least the initial attempt always runs.
+func TestWithRetry_RejectsInvalidMaxAttempts(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 0
+
+	if err := WithRetry(policy)(&Querier{}); err == nil {
+		t.Error("WithRetry with MaxAttempts=0 should return an error")
+	}
+}
@@ -0,0 +1,33 @@
+package querier
+
+import (
+	"context"
+
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// Serve blocks until ctx is done or r stops running on its own (Close,
+// Shutdown, or its own signal handler firing), so a process acting as both
+// a Querier and a Responder - RFC 6762 never assumes a device is only one or
+// the other - has a single call to block the main goroutine on instead of
+// wiring q and r's independent lifecycles together by hand. r must already
+// be running (built via responder.New, which starts it immediately); Serve
+// does not start or own r, and returns r's own Shutdown error if ctx ends
+// first.
+//
+// Example:
+//
+//	q, _ := querier.New()
+//	r, _ := responder.New(ctx, responder.WithHostname("printer"))
+//	r.Register(svc)
+//	if err := q.Serve(ctx, r); err != nil {
+//	    log.Fatal(err)
+//	}
+func (q *Querier) Serve(ctx context.Context, r *responder.Responder) error {
+	select {
+	case <-ctx.Done():
+		return r.Shutdown(context.Background())
+	case <-r.Done():
+		return nil
+	}
+}
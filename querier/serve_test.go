@@ -0,0 +1,73 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// TestQuerier_Serve_ReturnsWhenContextDone verifies Serve unblocks and shuts
+// r down once ctx ends, rather than waiting for r to stop on its own.
+func TestQuerier_Serve_ReturnsWhenContextDone(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := responder.New(ctx, responder.WithSignal(false))
+	if err != nil {
+		t.Fatalf("responder.New() failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- q.Serve(ctx, r) }()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() = %v, want nil after Shutdown", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve() did not return after ctx was cancelled")
+	}
+}
+
+// TestQuerier_Serve_ReturnsWhenResponderStopsOnItsOwn verifies Serve also
+// unblocks if r is closed independently of ctx.
+func TestQuerier_Serve_ReturnsWhenResponderStopsOnItsOwn(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, err := responder.New(ctx, responder.WithSignal(false))
+	if err != nil {
+		t.Fatalf("responder.New() failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- q.Serve(ctx, r) }()
+
+	if closeErr := r.Close(); closeErr != nil {
+		t.Fatalf("r.Close() failed: %v", closeErr)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() = %v, want nil after r stopped on its own", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve() did not return after r.Close()")
+	}
+}
@@ -0,0 +1,200 @@
+package querier
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/security"
+)
+
+// State represents the Querier's connectivity/lifecycle state, analogous to
+// gRPC's connectivity.State. A freshly constructed Querier starts at
+// StateStarting and reaches StateReady once New() has wired up its
+// transports; it never returns to StateIdle (this package has no lazy-connect
+// concept yet - StateIdle is reserved for that future distinction, same as
+// gRPC's ClientConn).
+type State int
+
+const (
+	// StateIdle is reserved for a Querier that hasn't started connecting yet.
+	// New() always starts a Querier connecting immediately, so no Querier
+	// returned from New() is ever observed in this state today.
+	StateIdle State = iota
+
+	// StateStarting indicates New() is still wiring up transports, the rate
+	// limiter, and background goroutines.
+	StateStarting
+
+	// StateReady indicates the Querier's transports are up and its receive
+	// loops are running with no active degradeReason.
+	StateReady
+
+	// StateDegraded indicates the Querier is still usable but impaired by at
+	// least one degradeReason: a transport's receive loop hitting persistent
+	// errors, the responseChan repeatedly overflowing, or the rate limiter
+	// having entered cooldown for some source.
+	StateDegraded
+
+	// StateClosing indicates Close() has been called and is shutting down
+	// background goroutines and transports.
+	StateClosing
+
+	// StateClosed indicates Close() has finished; the Querier is no longer
+	// usable.
+	StateClosed
+)
+
+// String returns the state's gRPC-style name.
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "Idle"
+	case StateStarting:
+		return "Starting"
+	case StateReady:
+		return "Ready"
+	case StateDegraded:
+		return "Degraded"
+	case StateClosing:
+		return "Closing"
+	case StateClosed:
+		return "Closed"
+	default:
+		return "Unknown"
+	}
+}
+
+// degradeReason is one of the independent conditions that can hold the
+// Querier in StateDegraded. Several can be active at once (e.g. a receive
+// error streak on one transport and a rate-limiter cooldown on some source);
+// the Querier only returns to StateReady once every reason has cleared.
+type degradeReason int
+
+const (
+	degradeReceiveErrors degradeReason = iota
+	degradeResponseChanOverflow
+	degradeRateLimitCooldown
+	numDegradeReasons
+)
+
+// receiveErrorDegradeThreshold is how many consecutive non-timeout receive
+// errors on one transport's receiveLoop are required before it's treated as
+// persistent (an interface gone, not a dropped packet) and reported via
+// degradeReceiveErrors.
+const receiveErrorDegradeThreshold = 5
+
+// chanOverflowDegradeThreshold is how many consecutive responseChan sends
+// must find it full before the backlog is treated as persistent (a stuck
+// collectResponses/Watch consumer, not a momentary burst) and reported via
+// degradeResponseChanOverflow.
+const chanOverflowDegradeThreshold = 5
+
+// State returns the Querier's current connectivity state.
+func (q *Querier) State() State {
+	q.stateMu.Lock()
+	defer q.stateMu.Unlock()
+	return q.state
+}
+
+// WaitForStateChange blocks until the Querier's state differs from prev, or
+// ctx is done, returning the new state or ctx.Err(). Analogous to gRPC's
+// ClientConn.WaitForStateChange: a caller that wants to notice a Querier
+// recovering after an interface flap, or that feeds a health endpoint, calls
+// this in a loop with the state State() last returned rather than polling.
+func (q *Querier) WaitForStateChange(ctx context.Context, prev State) (State, error) {
+	q.stateMu.Lock()
+	if q.state != prev {
+		cur := q.state
+		q.stateMu.Unlock()
+		return cur, nil
+	}
+	notify := q.stateNotify
+	q.stateMu.Unlock()
+
+	select {
+	case <-notify:
+		return q.State(), nil
+	case <-ctx.Done():
+		return prev, ctx.Err()
+	}
+}
+
+// setState unconditionally transitions to s, notifying any blocked
+// WaitForStateChange callers. Used for the lifecycle transitions
+// (Starting/Ready/Closing/Closed) that aren't governed by degradeReasons.
+func (q *Querier) setState(s State) {
+	q.stateMu.Lock()
+	if q.state == s {
+		q.stateMu.Unlock()
+		return
+	}
+	q.state = s
+	old := q.stateNotify
+	q.stateNotify = make(chan struct{})
+	q.stateMu.Unlock()
+	close(old)
+}
+
+// setDegraded marks (or clears) reason as an active cause of degradation and
+// recomputes the Querier's state: StateDegraded while any reason is active,
+// StateReady once all have cleared. A no-op while the Querier isn't
+// currently StateReady/StateDegraded, so a delayed receiveLoop error report
+// arriving after Close() started can't resurrect a closing/closed Querier.
+func (q *Querier) setDegraded(reason degradeReason, active bool) {
+	// Manual unlock required: the notify channel must be closed after
+	// releasing stateMu, matching the RateLimiter.Allow double-check
+	// pattern elsewhere in this codebase - a defer here would hold the lock
+	// across the close.
+	q.stateMu.Lock() // nosemgrep: beacon-mutex-defer-unlock
+
+	q.degradeReasons[reason] = active
+
+	if q.state != StateReady && q.state != StateDegraded {
+		q.stateMu.Unlock()
+		return
+	}
+
+	next := StateReady
+	for _, r := range q.degradeReasons {
+		if r {
+			next = StateDegraded
+			break
+		}
+	}
+	if next == q.state {
+		q.stateMu.Unlock()
+		return
+	}
+
+	q.state = next
+	old := q.stateNotify
+	q.stateNotify = make(chan struct{})
+	q.stateMu.Unlock()
+	close(old)
+}
+
+// HandleSecurityEvent implements security.SecurityEventSink so the Querier
+// can watch its own rate limiter directly: entering cooldown for any source
+// reports degradeRateLimitCooldown, cleared automatically once
+// rateLimitCooldown has elapsed without a further cooldown. A repeat
+// offender whose exponential-backoff cooldown runs longer than the base
+// rateLimitCooldown can therefore flip the Querier back to StateReady
+// slightly before that source's own cooldown actually expires - this is
+// connectivity telemetry, not a security control, so the imprecision is
+// acceptable.
+func (q *Querier) HandleSecurityEvent(evt security.SecurityEvent) {
+	if evt.Type != security.EventCooldownEntered {
+		return
+	}
+
+	q.setDegraded(degradeRateLimitCooldown, true)
+
+	q.stateMu.Lock()
+	if q.rateLimitRecoverTimer != nil {
+		q.rateLimitRecoverTimer.Stop()
+	}
+	q.rateLimitRecoverTimer = time.AfterFunc(q.rateLimitCooldown, func() {
+		q.setDegraded(degradeRateLimitCooldown, false)
+	})
+	q.stateMu.Unlock()
+}
@@ -0,0 +1,167 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/security"
+)
+
+// newTestQuerierForState builds a bare Querier with just enough state to
+// drive the State()/WaitForStateChange()/setDegraded() machinery directly,
+// bypassing New()'s network setup, mirroring newTestWatchSession.
+func newTestQuerierForState(initial State) *Querier {
+	return &Querier{
+		state:             initial,
+		stateNotify:       make(chan struct{}),
+		rateLimitCooldown: 50 * time.Millisecond,
+	}
+}
+
+// TestQuerier_SetState_NotifiesWaiters validates that WaitForStateChange
+// unblocks and returns the new state once setState transitions away from
+// the state the caller is waiting on.
+func TestQuerier_SetState_NotifiesWaiters(t *testing.T) {
+	q := newTestQuerierForState(StateStarting)
+
+	done := make(chan State, 1)
+	go func() {
+		s, err := q.WaitForStateChange(context.Background(), StateStarting)
+		if err != nil {
+			t.Errorf("WaitForStateChange returned error: %v", err)
+		}
+		done <- s
+	}()
+
+	// Give the goroutine a chance to start waiting before transitioning.
+	time.Sleep(10 * time.Millisecond)
+	q.setState(StateReady)
+
+	select {
+	case s := <-done:
+		if s != StateReady {
+			t.Errorf("WaitForStateChange returned %v, want StateReady", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitForStateChange did not return after setState")
+	}
+}
+
+// TestQuerier_WaitForStateChange_ReturnsImmediatelyOnMismatch validates that
+// WaitForStateChange returns right away, without blocking, when the current
+// state already differs from prev.
+func TestQuerier_WaitForStateChange_ReturnsImmediatelyOnMismatch(t *testing.T) {
+	q := newTestQuerierForState(StateReady)
+
+	s, err := q.WaitForStateChange(context.Background(), StateStarting)
+	if err != nil {
+		t.Fatalf("WaitForStateChange returned error: %v", err)
+	}
+	if s != StateReady {
+		t.Errorf("WaitForStateChange = %v, want StateReady", s)
+	}
+}
+
+// TestQuerier_WaitForStateChange_RespectsContext validates that
+// WaitForStateChange returns ctx.Err() once ctx is done without a
+// transition occurring.
+func TestQuerier_WaitForStateChange_RespectsContext(t *testing.T) {
+	q := newTestQuerierForState(StateReady)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	s, err := q.WaitForStateChange(ctx, StateReady)
+	if err != context.DeadlineExceeded {
+		t.Errorf("WaitForStateChange error = %v, want context.DeadlineExceeded", err)
+	}
+	if s != StateReady {
+		t.Errorf("WaitForStateChange = %v, want the unchanged prev state", s)
+	}
+}
+
+// TestQuerier_SetDegraded_RequiresAllReasonsClearToRecover validates that
+// the Querier only returns to StateReady once every active degradeReason
+// has cleared, not just the one most recently toggled off.
+func TestQuerier_SetDegraded_RequiresAllReasonsClearToRecover(t *testing.T) {
+	q := newTestQuerierForState(StateReady)
+
+	q.setDegraded(degradeReceiveErrors, true)
+	q.setDegraded(degradeResponseChanOverflow, true)
+	if got := q.State(); got != StateDegraded {
+		t.Fatalf("State() = %v after two reasons active, want StateDegraded", got)
+	}
+
+	q.setDegraded(degradeReceiveErrors, false)
+	if got := q.State(); got != StateDegraded {
+		t.Errorf("State() = %v with one reason still active, want StateDegraded", got)
+	}
+
+	q.setDegraded(degradeResponseChanOverflow, false)
+	if got := q.State(); got != StateReady {
+		t.Errorf("State() = %v once all reasons cleared, want StateReady", got)
+	}
+}
+
+// TestQuerier_SetDegraded_NoopWhenClosing validates that a degradeReason
+// reported after Close() has moved the Querier to StateClosing can't
+// resurrect it into StateDegraded.
+func TestQuerier_SetDegraded_NoopWhenClosing(t *testing.T) {
+	q := newTestQuerierForState(StateClosing)
+
+	q.setDegraded(degradeReceiveErrors, true)
+	if got := q.State(); got != StateClosing {
+		t.Errorf("State() = %v after setDegraded during StateClosing, want unchanged StateClosing", got)
+	}
+}
+
+// TestQuerier_HandleSecurityEvent_CooldownEnteredDegradesAndRecovers
+// validates that a RateLimiter CooldownEntered event degrades the Querier,
+// and that it self-recovers once rateLimitCooldown has elapsed.
+func TestQuerier_HandleSecurityEvent_CooldownEnteredDegradesAndRecovers(t *testing.T) {
+	q := newTestQuerierForState(StateReady)
+
+	q.HandleSecurityEvent(security.SecurityEvent{Type: security.EventCooldownEntered})
+	if got := q.State(); got != StateDegraded {
+		t.Fatalf("State() = %v after CooldownEntered, want StateDegraded", got)
+	}
+
+	deadline := time.After(time.Second)
+	for q.State() != StateReady {
+		select {
+		case <-deadline:
+			t.Fatal("Querier did not recover to StateReady after rateLimitCooldown elapsed")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// TestQuerier_HandleSecurityEvent_IgnoresOtherEventTypes validates that
+// event types other than CooldownEntered don't affect Querier state.
+func TestQuerier_HandleSecurityEvent_IgnoresOtherEventTypes(t *testing.T) {
+	q := newTestQuerierForState(StateReady)
+
+	q.HandleSecurityEvent(security.SecurityEvent{Type: security.EventRateLimitTriggered})
+	if got := q.State(); got != StateReady {
+		t.Errorf("State() = %v after EventRateLimitTriggered, want unaffected StateReady", got)
+	}
+}
+
+// TestState_String validates the gRPC-style names used in logs/metrics.
+func TestState_String(t *testing.T) {
+	cases := map[State]string{
+		StateIdle:     "Idle",
+		StateStarting: "Starting",
+		StateReady:    "Ready",
+		StateDegraded: "Degraded",
+		StateClosing:  "Closing",
+		StateClosed:   "Closed",
+		State(99):     "Unknown",
+	}
+	for state, want := range cases {
+		if got := state.String(); got != want {
+			t.Errorf("State(%d).String() = %q, want %q", int(state), got, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestQuery_NSECResponse_SetsStatusNXDomain validates that an mDNS negative
+// response - an NSEC record (RFC 6762 §6.1) owned by the queried name whose
+// type bitmap excludes the queried type - sets Response.Status to
+// StatusNXDomain, distinguishing "proven not to exist" from an ordinary
+// timeout.
+func TestQuery_NSECResponse_SetsStatusNXDomain(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	b := message.NewBuilder(message.DNSHeader{Flags: protocol.FlagQR | protocol.FlagAA})
+	if err := b.StartAnswers(); err != nil {
+		t.Fatalf("StartAnswers failed: %v", err)
+	}
+	// NSEC for "host.local" asserting only PTR and TXT exist - no A record.
+	nsecRDATA, err := message.EncodeNSEC(&message.NSECData{NextDomainName: "host.local", TypeBitMap: []uint16{uint16(protocol.RecordTypePTR), uint16(protocol.RecordTypeTXT)}})
+	if err != nil {
+		t.Fatalf("EncodeNSEC failed: %v", err)
+	}
+	if err := b.Answer(message.Answer{NAME: "host.local", TYPE: uint16(protocol.RecordTypeNSEC), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: nsecRDATA}); err != nil {
+		t.Fatalf("Answer(NSEC) failed: %v", err)
+	}
+	packet, err := b.Finish()
+	if err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+	mock.QueueReceive(packet, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	response, err := q.Query(ctx, "host.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if response.Status != StatusNXDomain {
+		t.Errorf("response.Status = %v, want StatusNXDomain", response.Status)
+	}
+}
+
+// TestQuery_Timeout_SetsStatusNoAnswer validates that a Query timing out
+// with no responses at all reports StatusNoAnswer, the ordinary case
+// StatusNXDomain must be distinguished from.
+func TestQuery_Timeout_SetsStatusNoAnswer(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	response, err := q.Query(ctx, "nonexistent.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if response.Status != StatusNoAnswer {
+		t.Errorf("response.Status = %v, want StatusNoAnswer", response.Status)
+	}
+}
+
+// TestQuery_WithAnswer_SetsStatusOK validates that a Query collecting a
+// normal answer reports StatusOK.
+func TestQuery_WithAnswer_SetsStatusOK(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	respPacket, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "host.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{192, 168, 1, 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse() failed: %v", err)
+	}
+	mock.QueueReceive(respPacket, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	response, err := q.Query(ctx, "host.local", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if response.Status != StatusOK {
+		t.Errorf("response.Status = %v, want StatusOK", response.Status)
+	}
+}
+
+// TestNsecAssertsNonexistence_SkipsRecordTypeANY validates that an ANY query
+// never reports a negative answer via NSEC, since ANY has no single bit to
+// check against an NSEC record's type bitmap.
+func TestNsecAssertsNonexistence_SkipsRecordTypeANY(t *testing.T) {
+	nsecRDATA, err := message.EncodeNSEC(&message.NSECData{NextDomainName: "host.local", TypeBitMap: nil})
+	if err != nil {
+		t.Fatalf("EncodeNSEC failed: %v", err)
+	}
+	parsedMsg := &message.DNSMessage{
+		Answers: []message.Answer{
+			{NAME: "host.local", TYPE: uint16(protocol.RecordTypeNSEC), RDATA: nsecRDATA},
+		},
+	}
+
+	if nsecAssertsNonexistence(parsedMsg, "host.local", RecordTypeANY) {
+		t.Error("nsecAssertsNonexistence() = true for RecordTypeANY, want false")
+	}
+}
+
+// TestResponseStatus_String validates every ResponseStatus has a non-empty,
+// distinct String().
+func TestResponseStatus_String(t *testing.T) {
+	statuses := []ResponseStatus{StatusOK, StatusNoAnswer, StatusNXDomain, StatusPartial}
+	seen := make(map[string]bool)
+	for _, s := range statuses {
+		str := s.String()
+		if str == "" || str == "Unknown" {
+			t.Errorf("ResponseStatus(%d).String() = %q, want a distinct name", s, str)
+		}
+		if seen[str] {
+			t.Errorf("ResponseStatus(%d).String() = %q, duplicates an earlier status", s, str)
+		}
+		seen[str] = true
+	}
+}
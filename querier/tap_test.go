@@ -0,0 +1,81 @@
+package querier
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTap is a tap.Tap recorder for assertions, safe for concurrent
+// use since sendQuery/processReceivedPacketSafely may call it from
+// multiple goroutines.
+type recordingTap struct {
+	mu        sync.Mutex
+	queries   int
+	responses int
+}
+
+func (r *recordingTap) OnQuery(_ []byte, _, _ net.Addr, _ time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+}
+
+func (r *recordingTap) OnResponse(_ []byte, _, _ net.Addr, _ time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses++
+}
+
+func (r *recordingTap) counts() (queries, responses int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queries, r.responses
+}
+
+// TestTapActive_FalseForNilOrNoOp validates tapActive's guard against both
+// a zero-value Querier (nil tap) and the default tap.NoOp{}.
+func TestTapActive_FalseForNilOrNoOp(t *testing.T) {
+	q := &Querier{}
+	if q.tapActive() {
+		t.Error("tapActive() = true for a nil tap, want false")
+	}
+
+	q2, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q2.Close() }()
+	if q2.tapActive() {
+		t.Error("tapActive() = true for the default tap.NoOp{}, want false")
+	}
+}
+
+// TestWithTap_RejectsNil validates that WithTap(nil) returns a
+// ValidationError instead of silently disabling tap reporting.
+func TestWithTap_RejectsNil(t *testing.T) {
+	q := &Querier{}
+	if err := WithTap(nil)(q); err == nil {
+		t.Fatal("WithTap(nil) returned nil error, want a ValidationError")
+	}
+}
+
+// TestSendQuery_ReportsToTap validates that a successful send reports an
+// OnQuery event to a configured tap.Tap.
+func TestSendQuery_ReportsToTap(t *testing.T) {
+	rec := &recordingTap{}
+	q, err := New(WithTap(rec))
+	if err != nil {
+		t.Fatalf("New(WithTap) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	if err := q.sendQuery(q.ctx, []byte("query")); err != nil {
+		t.Fatalf("sendQuery failed: %v", err)
+	}
+
+	if queries, _ := rec.counts(); queries < 1 {
+		t.Errorf("queries = %d, want at least 1", queries)
+	}
+}
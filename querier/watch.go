@@ -0,0 +1,546 @@
+package querier
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// watchRecordBufferSize is the Watch() records channel buffer. A modest
+// buffer absorbs a burst of answers (several responders replying to the
+// same query) without blocking dispatchWatch on a slow consumer, mirroring
+// browser.eventBufferSize.
+const watchRecordBufferSize = 32
+
+// watchErrBufferSize is the Watch() error channel buffer. Errors are
+// infrequent compared to records, so a small buffer is enough to avoid
+// losing one reported while the caller is still draining the previous one.
+const watchErrBufferSize = 4
+
+// maxWatchQueryDelay caps the RFC 6762 §5.2 continuous-querying backoff, the
+// same 60-minute ceiling internal/browser's nextQueryDelay uses for the
+// same provision: "...up to a maximum of 60 minutes."
+const maxWatchQueryDelay = 60 * time.Minute
+
+// nextWatchQueryDelay computes the next query interval per RFC 6762 §5.2's
+// doubling backoff: 0 (send immediately), then 1s, 2s, 4s, ..., capped at
+// 60 minutes, each perturbed by up to 2% extra jitter so many Watch sessions
+// querying the same name don't all transmit in lockstep.
+func nextWatchQueryDelay(prev time.Duration) time.Duration {
+	var next time.Duration
+	if prev == 0 {
+		next = 1 * time.Second
+	} else {
+		next = prev * 2
+		if next > maxWatchQueryDelay {
+			next = maxWatchQueryDelay
+		}
+	}
+
+	jittered := next + watchQueryJitter(next)
+	if jittered > maxWatchQueryDelay {
+		return maxWatchQueryDelay
+	}
+	return jittered
+}
+
+// watchQueryJitter returns a random extra delay of 0-2% of base, the jitter
+// RFC 6762 §5.2 recommends adding to the doubling backoff interval.
+func watchQueryJitter(base time.Duration) time.Duration {
+	return time.Duration(rand.Float64() * 0.02 * float64(base))
+}
+
+// WatchBackoffPolicy configures how Watch recovers from transient transport
+// errors (a socket rebind after an interface flap, ENOBUFS, a receive loop
+// restart) while resending its periodic query. This is independent of the
+// RFC 6762 §5.2 query cadence nextWatchQueryDelay implements: that schedule
+// only ever grows while a session keeps succeeding, whereas this policy
+// governs how fast Watch retries after something actually went wrong.
+//
+// The formula - delay = min(BaseDelay*Factor^attempt, MaxDelay) *
+// (1 + Jitter*rand[-1,1]) - matches gRPC's connection backoff.
+type WatchBackoffPolicy struct {
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+
+	// Factor is the multiplier applied per additional failed attempt.
+	Factor float64
+
+	// MaxDelay caps the computed delay regardless of how many attempts
+	// have failed in a row.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of the computed delay to randomly perturb by,
+	// in either direction, so many Watch sessions recovering from the same
+	// network event don't all retry in lockstep.
+	Jitter float64
+}
+
+// DefaultWatchBackoffPolicy returns the default WatchBackoffPolicy: a 1
+// second base delay, 1.6x growth factor, 120 second ceiling, and ±20%
+// jitter.
+func DefaultWatchBackoffPolicy() WatchBackoffPolicy {
+	return WatchBackoffPolicy{
+		BaseDelay: 1 * time.Second,
+		Factor:    1.6,
+		MaxDelay:  120 * time.Second,
+		Jitter:    0.2,
+	}
+}
+
+// next returns the delay to wait before retrying after the attempt-th
+// consecutive failure (0-indexed: the first retry is attempt 0).
+func (p WatchBackoffPolicy) next(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(p.Factor, float64(attempt))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	jitter := 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(delay * jitter)
+}
+
+// watchEntry tracks one cached record Watch has already emitted, so an
+// unchanged answer arriving again (every re-query re-receives the full
+// rrset) doesn't flood the caller's channel, while an expired one is
+// forgotten and will be re-emitted if seen again.
+type watchEntry struct {
+	record ResourceRecord
+	timers []*time.Timer
+}
+
+// ttlRefreshFractions is the schedule of elapsed-TTL fractions at which an
+// active Watch session proactively re-queries its name/recordType to refresh
+// a cached record before it expires, per RFC 6762 §5.2: "...the querier
+// should plan to issue a query at 80% of the record lifetime, and then
+// again at 85%, 90%, and 95%". This runs independently of (and usually well
+// ahead of) nextWatchQueryDelay's own exponential schedule, giving a record
+// nearing expiry several extra chances to be refreshed before a missed
+// refresh forces a silent expiry - evicted from Watch's own cache, or
+// surfaced as ServiceLost/Removed by WatchServiceType/Browse, which each
+// track an instance's TTL separately.
+var ttlRefreshFractions = []float64{0.80, 0.85, 0.90, 0.95}
+
+// armEntryTimers starts one timer per ttlRefreshFractions entry - each
+// re-querying s.name/s.recordType when it fires - plus a final timer at the
+// full TTL that expires the cache entry, returning every timer together so
+// the caller can stop them all at once if the entry is replaced or evicted
+// early (a goodbye record, a cache flush, or a fresher answer for the same
+// dedupeKey).
+func (s *watchSession) armEntryTimers(typeKey, dedupeKey string, ttl uint32) []*time.Timer {
+	full := time.Duration(ttl) * time.Second
+
+	timers := make([]*time.Timer, 0, len(ttlRefreshFractions)+1)
+	for _, frac := range ttlRefreshFractions {
+		timers = append(timers, time.AfterFunc(time.Duration(float64(full)*frac), s.refreshQuery))
+	}
+	timers = append(timers, time.AfterFunc(full, func() { s.expire(typeKey, dedupeKey) }))
+
+	return timers
+}
+
+// refreshQuery re-sends session's query once, opportunistically, when one of
+// its cached entries' TTL-fraction timers fires. Unlike sendWithBackoff (the
+// main loop's query, retried on failure and reported via the error channel),
+// a failure here is discarded rather than retried: the main loop's own
+// schedule will try again regardless, so this is purely a chance at an
+// earlier refresh, mirroring Browser.refreshLoop's own fire-and-forget query.
+func (s *watchSession) refreshQuery() {
+	_ = s.q.sendWatchQuery(s.ctx, s.name, s.recordType)
+}
+
+// stopEntryTimers stops every timer in timers, the shared cleanup used
+// whenever a watchEntry is replaced or evicted before its timers fire on
+// their own.
+func stopEntryTimers(timers []*time.Timer) {
+	for _, t := range timers {
+		t.Stop()
+	}
+}
+
+// watchSession tracks one Watch() call's continuous query, answer cache,
+// and output channels.
+type watchSession struct {
+	key        string // name + "|" + recordType, the watchSessions registry key
+	name       string
+	recordType RecordType
+	records    chan ResourceRecord
+	errs       chan error
+	ctx        context.Context
+	cancel     context.CancelFunc
+	q          *Querier
+
+	mu sync.Mutex
+	// cache is keyed by "name|type" (not just dedupeKey) so a cache-flush
+	// bit answer (RFC 6762 §10.2) can evict every other cached record that
+	// shares that exact name+type without touching unrelated types a
+	// RecordTypeANY watch also tracks.
+	cache map[string]map[string]*watchEntry
+}
+
+// Watch starts (or resumes) a continuous query for name/recordType and
+// returns a channel of matching records as they arrive, plus a channel of
+// transient errors encountered while keeping the query alive. Either
+// channel is closed once ctx is done or the Querier is closed.
+//
+// Under the hood, Watch re-sends its query at RFC 6762 §5.2's doubling
+// interval (1s, 2s, 4s, ..., capped at 60 minutes) for as long as the
+// session runs, rather than aggregating for a single fixed timeout the way
+// Query does. A record already seen and still within its TTL is not
+// re-emitted on an unchanged re-query; one with the cache-flush bit set
+// (RFC 6762 §10.2) replaces the rest of its name+type's cached rrset and is
+// always emitted; a TTL=0 goodbye record (RFC 6762 §10.1) evicts its cache
+// entry without being emitted itself.
+//
+// Only one Watch per (name, recordType) pair may be active at a time,
+// mirroring Browse's one-session-per-service-type rule.
+func (q *Querier) Watch(ctx context.Context, name string, recordType RecordType) (<-chan ResourceRecord, <-chan error) {
+	if err := protocol.ValidateName(name); err != nil {
+		return closedWatchChannels(err)
+	}
+	if err := protocol.ValidateRecordType(uint16(recordType)); err != nil {
+		return closedWatchChannels(err)
+	}
+
+	key := fmt.Sprintf("%s|%d", name, recordType)
+
+	q.watchMu.Lock()
+	if _, exists := q.watchSessions[key]; exists {
+		q.watchMu.Unlock()
+		return closedWatchChannels(&errors.ValidationError{
+			Field:   "name",
+			Value:   name,
+			Message: "already watching this name and record type",
+		})
+	}
+
+	sessionCtx, cancel := context.WithCancel(q.ctx)
+	session := &watchSession{
+		key:        key,
+		name:       name,
+		recordType: recordType,
+		records:    make(chan ResourceRecord, watchRecordBufferSize),
+		errs:       make(chan error, watchErrBufferSize),
+		ctx:        sessionCtx,
+		cancel:     cancel,
+		q:          q,
+		cache:      make(map[string]map[string]*watchEntry),
+	}
+	q.watchSessions[key] = session
+	q.watchMu.Unlock()
+
+	// Stop the session if the caller's context ends before the Querier's.
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-sessionCtx.Done():
+		}
+	}()
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		session.run()
+	}()
+
+	return session.records, session.errs
+}
+
+// closedWatchChannels returns a pair of already-closed Watch() channels
+// with err pre-delivered on the error channel, for validation failures that
+// happen before a session would otherwise start.
+func closedWatchChannels(err error) (<-chan ResourceRecord, <-chan error) {
+	records := make(chan ResourceRecord)
+	close(records)
+
+	errs := make(chan error, 1)
+	errs <- err
+	close(errs)
+
+	return records, errs
+}
+
+// run drives session's query cadence until its context is done, then tears
+// the session down.
+func (s *watchSession) run() {
+	defer s.q.endWatch(s)
+
+	queryDelay := time.Duration(0)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(queryDelay):
+		}
+
+		s.sendWithBackoff()
+		queryDelay = nextWatchQueryDelay(queryDelay)
+	}
+}
+
+// sendWithBackoff sends one query for session's name/recordType, retrying a
+// transient transport error using the Querier's WatchBackoffPolicy until it
+// succeeds or the session's context ends. Each failure is also reported on
+// the error channel so a caller can surface it (logging, metrics) without
+// the session itself giving up.
+func (s *watchSession) sendWithBackoff() {
+	attempt := 0
+	for {
+		err := s.q.sendWatchQuery(s.ctx, s.name, s.recordType)
+		if err == nil {
+			return
+		}
+		if s.ctx.Err() != nil {
+			// The session is already ending - run()'s first-iteration select
+			// can call sendWithBackoff just as s.ctx is canceled, so this
+			// failure is cancellation, not a transient transport error worth
+			// reporting on errs.
+			return
+		}
+		s.emitErr(err)
+
+		wait := s.q.watchBackoff.next(attempt)
+		attempt++
+		select {
+		case <-time.After(wait):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// sendWatchQuery builds and sends a query for name/recordType, consulting the
+// Known-Answer cache per RFC 6762 §7.1 first - exactly as Query does - so a
+// responder already holding data this session has already seen (either from
+// an earlier Watch answer or an unrelated Query) can suppress its own reply
+// instead of every re-query re-announcing the whole rrset. Reuses Query's
+// sendQuery/buildKnownAnswerQuery/sendKnownAnswerFollowups machinery so the
+// two query paths can't drift in how they build or send a packet.
+func (q *Querier) sendWatchQuery(ctx context.Context, name string, recordType RecordType) error {
+	var knownAnswers []*message.ResourceRecord
+	if q.knownAnswerCache != nil {
+		knownAnswers = q.knownAnswerCache.knownAnswers(name, recordType, uint16(protocol.ClassIN))
+	}
+
+	var packets [][]byte
+	if len(knownAnswers) == 0 {
+		queryMsg, err := message.BuildQuery(name, uint16(recordType), q.ednsQueryOptions()...)
+		if err != nil {
+			return err
+		}
+		packets = [][]byte{queryMsg}
+	} else {
+		built, err := q.buildKnownAnswerQuery(name, recordType, knownAnswers)
+		if err != nil {
+			return err
+		}
+		packets = built
+	}
+
+	if err := q.sendQuery(ctx, packets[0]); err != nil {
+		return err
+	}
+	return q.sendKnownAnswerFollowups(ctx, packets[1:])
+}
+
+// handleAnswer folds one parsed answer into session's cache, emitting it on
+// records when it's new, changed, or carries the cache-flush bit.
+func (s *watchSession) handleAnswer(a message.Answer) {
+	if !strings.EqualFold(a.NAME, s.name) {
+		return
+	}
+	if s.recordType != RecordTypeANY && RecordType(a.TYPE) != s.recordType {
+		return
+	}
+
+	data, err := message.ParseRDATA(a.TYPE, a.RDATA)
+	if err != nil {
+		return
+	}
+
+	typeKey := fmt.Sprintf("%s|%d", a.NAME, a.TYPE)
+	dedupeKey := fmt.Sprintf("%s|%d|%v", a.NAME, a.TYPE, data)
+
+	s.mu.Lock()
+	group, ok := s.cache[typeKey]
+	if !ok {
+		group = make(map[string]*watchEntry)
+		s.cache[typeKey] = group
+	}
+
+	// RFC 6762 §10.1: TTL=0 is a goodbye record - evict without emitting it.
+	if a.TTL == 0 {
+		if entry, exists := group[dedupeKey]; exists {
+			stopEntryTimers(entry.timers)
+			delete(group, dedupeKey)
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	// RFC 6762 §10.2: the cache-flush bit means this record replaces the
+	// entire rrset for name+type, so anything else cached under typeKey is
+	// now stale.
+	flush := a.CLASS&0x8000 != 0
+	if flush {
+		for k, entry := range group {
+			if k == dedupeKey {
+				continue
+			}
+			stopEntryTimers(entry.timers)
+			delete(group, k)
+		}
+	}
+
+	previous, alreadyCached := group[dedupeKey]
+	if alreadyCached {
+		// A fresh answer for an already-cached record re-arms its timers
+		// below with the new TTL, so the old schedule must not fire too.
+		stopEntryTimers(previous.timers)
+	}
+
+	record := ResourceRecord{
+		Name:  a.NAME,
+		Type:  RecordType(a.TYPE),
+		Class: a.CLASS,
+		TTL:   a.TTL,
+		Data:  data,
+	}
+	group[dedupeKey] = &watchEntry{
+		record: record,
+		timers: s.armEntryTimers(typeKey, dedupeKey, a.TTL),
+	}
+	s.mu.Unlock()
+
+	if alreadyCached && !flush {
+		// Unchanged and still fresh - already delivered once, don't resend
+		// it on every re-query.
+		return
+	}
+
+	s.emit(record)
+}
+
+// expire removes a cache entry once its TTL timer fires, so a record seen
+// again later (the responder is still alive, just due for a refresh) is
+// treated as new and re-emitted rather than silently deduplicated forever.
+func (s *watchSession) expire(typeKey, dedupeKey string) {
+	s.mu.Lock()
+	if group, ok := s.cache[typeKey]; ok {
+		delete(group, dedupeKey)
+		if len(group) == 0 {
+			delete(s.cache, typeKey)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// emit sends r on the records channel, dropping it rather than blocking if
+// the buffer is full - a slow consumer loses the deduplication benefit of a
+// dropped duplicate, not the record entirely, since the next re-query will
+// emit it again.
+func (s *watchSession) emit(r ResourceRecord) {
+	select {
+	case s.records <- r:
+	default:
+	}
+}
+
+// emitErr reports a transient send error on the errs channel, dropping it
+// rather than blocking if the buffer is full.
+func (s *watchSession) emitErr(err error) {
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
+
+// stopTimers cancels every outstanding TTL timer in session's cache, used
+// when the session is torn down.
+func (s *watchSession) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, group := range s.cache {
+		for _, entry := range group {
+			stopEntryTimers(entry.timers)
+		}
+	}
+}
+
+// endWatch removes session from the Querier's registry and closes its
+// output channels. Called exactly once, via session.run()'s defer.
+func (q *Querier) endWatch(s *watchSession) {
+	q.watchMu.Lock()
+	delete(q.watchSessions, s.key)
+	q.watchMu.Unlock()
+
+	s.stopTimers()
+	close(s.records)
+	close(s.errs)
+}
+
+// dispatchWatch routes one raw received message to every active Watch
+// session whose answers it might match. It parses data independently of
+// collectResponses's own parsing of the same bytes for any in-flight
+// Query/QueryUnicast call - the two paths serve different consumers and
+// neither should block waiting on the other.
+func (q *Querier) dispatchWatch(data []byte) {
+	q.watchMu.Lock()
+	if len(q.watchSessions) == 0 {
+		q.watchMu.Unlock()
+		return
+	}
+	sessions := make([]*watchSession, 0, len(q.watchSessions))
+	for _, s := range q.watchSessions {
+		sessions = append(sessions, s)
+	}
+	q.watchMu.Unlock()
+
+	parsedMsg, err := message.ParseMessage(data)
+	if err != nil {
+		return
+	}
+	if err := protocol.ValidateResponse(parsedMsg.Header.Flags); err != nil {
+		return
+	}
+
+	// Additional-section records are included (not just Answers) so a
+	// RecordTypeANY or RecordTypeSRV watch also sees the A/AAAA glue RFC
+	// 6763 §12 carries alongside an SRV answer, matching how
+	// internal/browser's dispatch treats the same two sections.
+	all := make([]message.Answer, 0, len(parsedMsg.Answers)+len(parsedMsg.Additionals))
+	all = append(all, parsedMsg.Answers...)
+	all = append(all, parsedMsg.Additionals...)
+
+	for _, s := range sessions {
+		for _, a := range all {
+			// Only cache an answer matching an active session's own name,
+			// mirroring appendMatchingAnswers's anti-spoofing name check for
+			// Query's response path: otherwise an off-path attacker could
+			// use an unrelated forged answer (or a forged TTL=0 goodbye) to
+			// poison or evict entries from the shared Known-Answer cache
+			// while merely one unrelated Watch session happens to be active.
+			if q.knownAnswerCache != nil && strings.EqualFold(a.NAME, s.name) {
+				q.knownAnswerCache.Put(message.ResourceRecord{
+					Name:       a.NAME,
+					Type:       protocol.RecordType(a.TYPE),
+					Class:      protocol.DNSClass(a.CLASS &^ 0x8000),
+					TTL:        a.TTL,
+					Data:       a.RDATA,
+					CacheFlush: a.CacheFlush(),
+				})
+			}
+			s.handleAnswer(a)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestSendWatchQuery_IncludesKnownAnswerFromCache validates that
+// sendWatchQuery, like Query, consults the Known-Answer cache per RFC 6762
+// §7.1 before sending, so a continuous watch session doesn't keep eliciting
+// a reply for data it already knows about.
+func TestSendWatchQuery_IncludesKnownAnswerFromCache(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New(WithTransport) failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	q.Cache().Put(message.ResourceRecord{
+		Name:  "printer.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 1},
+	})
+
+	if err := q.sendWatchQuery(context.Background(), "printer.local", RecordTypeA); err != nil {
+		t.Fatalf("sendWatchQuery() error = %v", err)
+	}
+
+	calls := mock.SendCalls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 Send() call, got %d", len(calls))
+	}
+
+	sentQuery, err := message.ParseMessage(calls[0].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage() failed: %v", err)
+	}
+	if sentQuery.Header.ANCount != 1 {
+		t.Errorf("sent query ANCount = %d, want 1 (Known-Answer)", sentQuery.Header.ANCount)
+	}
+	if len(sentQuery.Answers) != 1 || sentQuery.Answers[0].NAME != "printer.local" {
+		t.Errorf("sent query Answers = %+v, want one answer for printer.local", sentQuery.Answers)
+	}
+}
+
+// TestDispatchWatch_PopulatesKnownAnswerCache validates that an answer
+// routed through dispatchWatch to an active Watch session is also fed into
+// the Querier's Known-Answer cache, so a later sendWatchQuery or Query call
+// can offer it as a Known-Answer even though it arrived via Watch rather
+// than Query's own collectResponses path.
+func TestDispatchWatch_PopulatesKnownAnswerCache(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	records, _ := q.Watch(ctx, "printer.local", RecordTypeA)
+
+	respPacket, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "printer.local",
+			Type:  protocol.RecordTypeA,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{192, 168, 1, 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse() failed: %v", err)
+	}
+	q.dispatchWatch(respPacket)
+
+	select {
+	case <-records:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not emit the dispatched record")
+	}
+
+	if entries := q.Cache().Entries(); len(entries) != 1 {
+		t.Fatalf("Cache().Entries() = %d, want 1 after dispatchWatch", len(entries))
+	}
+}
@@ -0,0 +1,190 @@
+package querier
+
+import (
+	"context"
+	"time"
+)
+
+// serviceEventBufferSize is the WatchServiceType() events channel buffer,
+// matching watchRecordBufferSize's rationale: absorb a burst of instances
+// appearing at once without blocking on a slow consumer.
+const serviceEventBufferSize = 32
+
+// ServiceEventType identifies what happened to a service instance discovered
+// by WatchServiceType, mirroring the Found/Lost/Changed vocabulary used by
+// Vanadium's discovery library.
+type ServiceEventType int
+
+const (
+	// ServiceFound indicates a newly resolved service instance.
+	ServiceFound ServiceEventType = iota
+
+	// ServiceLost indicates an instance whose PTR record's TTL expired
+	// without being refreshed, or that sent a goodbye record (TTL=0) per
+	// RFC 6762 §10.1.
+	ServiceLost
+
+	// ServiceChanged indicates an already-found instance whose SRV target,
+	// port, or TXT records changed without the instance itself disappearing.
+	ServiceChanged
+)
+
+// String returns a human-readable name for the event type.
+func (t ServiceEventType) String() string {
+	switch t {
+	case ServiceFound:
+		return "Found"
+	case ServiceLost:
+		return "Lost"
+	case ServiceChanged:
+		return "Changed"
+	default:
+		return "Unknown"
+	}
+}
+
+// ServiceEvent reports a change to a service instance discovered by
+// WatchServiceType.
+type ServiceEvent struct {
+	Type ServiceEventType
+
+	// Instance is the affected instance. For a ServiceLost event, Target,
+	// Port, and TXT reflect the last resolution seen before the instance
+	// disappeared, not a fresh lookup.
+	Instance ServiceInstance
+}
+
+// WatchServiceType subscribes to continuous Found/Lost/Changed updates for
+// service instances of serviceType, resolving each instance's SRV and TXT
+// records the same way BrowseSubtype does for a one-shot snapshot.
+//
+// Under the hood this layers on Watch's PTR-level deduplication and TTL
+// tracking: each PTR answer Watch surfaces (new, changed, or cache-flushed)
+// is resolved into a ServiceInstance and emitted as Found (first time seen)
+// or Changed (already known, but SRV/TXT now differ). Unlike Watch, which
+// never surfaces an instance going away, WatchServiceType also tracks each
+// instance's own TTL and emits ServiceLost once it lapses without a
+// refresh - this is self-maintained rather than reused from Watch's cache,
+// since Watch swallows TTL=0 goodbye records and expiry internally without
+// surfacing either.
+//
+// The returned channels close once ctx ends or the Querier is closed, same
+// as Watch.
+func (q *Querier) WatchServiceType(ctx context.Context, serviceType string) (<-chan ServiceEvent, <-chan error) {
+	ptrRecords, ptrErrs := q.Watch(ctx, serviceType, RecordTypePTR)
+
+	events := make(chan ServiceEvent, serviceEventBufferSize)
+	errs := make(chan error, watchErrBufferSize)
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.runServiceWatch(ctx, ptrRecords, ptrErrs, events, errs)
+	}()
+
+	return events, errs
+}
+
+// runServiceWatch consumes serviceType's PTR updates and errors, resolving
+// and emitting ServiceEvents until both source channels are closed.
+func (q *Querier) runServiceWatch(ctx context.Context, ptrRecords <-chan ResourceRecord, ptrErrs <-chan error, events chan<- ServiceEvent, errs chan<- error) {
+	defer close(events)
+	defer close(errs)
+
+	known := make(map[string]ServiceInstance)
+	timers := make(map[string]*time.Timer)
+	defer func() {
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}()
+
+	lost := make(chan string, serviceEventBufferSize)
+
+	for ptrRecords != nil || ptrErrs != nil {
+		select {
+		case record, ok := <-ptrRecords:
+			if !ok {
+				ptrRecords = nil
+				continue
+			}
+			q.handleServiceRecord(ctx, record, known, timers, lost, events)
+
+		case err, ok := <-ptrErrs:
+			if !ok {
+				ptrErrs = nil
+				continue
+			}
+			select {
+			case errs <- err:
+			default:
+			}
+
+		case instanceName := <-lost:
+			if instance, ok := known[instanceName]; ok {
+				delete(known, instanceName)
+				delete(timers, instanceName)
+				emitServiceEvent(events, ServiceEvent{Type: ServiceLost, Instance: instance})
+			}
+		}
+	}
+}
+
+// handleServiceRecord resolves a PTR update into a ServiceInstance, emits
+// Found or Changed as appropriate, and (re)arms instanceName's TTL timer so
+// a later silence is reported as ServiceLost.
+func (q *Querier) handleServiceRecord(ctx context.Context, record ResourceRecord, known map[string]ServiceInstance, timers map[string]*time.Timer, lost chan<- string, events chan<- ServiceEvent) {
+	instanceName := record.AsPTR()
+	if instanceName == "" {
+		return
+	}
+
+	instance := q.resolveInstance(ctx, instanceName)
+
+	previous, wasKnown := known[instanceName]
+	known[instanceName] = instance
+
+	if timer, exists := timers[instanceName]; exists {
+		timer.Stop()
+	}
+	timers[instanceName] = time.AfterFunc(time.Duration(record.TTL)*time.Second, func() {
+		select {
+		case lost <- instanceName:
+		default:
+		}
+	})
+
+	switch {
+	case !wasKnown:
+		emitServiceEvent(events, ServiceEvent{Type: ServiceFound, Instance: instance})
+	case !sameServiceInstance(previous, instance):
+		emitServiceEvent(events, ServiceEvent{Type: ServiceChanged, Instance: instance})
+	}
+}
+
+// sameServiceInstance reports whether a and b resolved to the same SRV
+// target/port and TXT record set.
+func sameServiceInstance(a, b ServiceInstance) bool {
+	if a.Target != b.Target || a.Port != b.Port {
+		return false
+	}
+	if len(a.TXT) != len(b.TXT) {
+		return false
+	}
+	for i := range a.TXT {
+		if a.TXT[i] != b.TXT[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// emitServiceEvent sends e on events, dropping it rather than blocking if
+// the buffer is full, matching watchSession.emit's drop-on-backpressure
+// behavior.
+func emitServiceEvent(events chan<- ServiceEvent, e ServiceEvent) {
+	select {
+	case events <- e:
+	default:
+	}
+}
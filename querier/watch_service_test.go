@@ -0,0 +1,140 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWatchServiceType_ClosesChannelsWhenContextEnds validates that
+// WatchServiceType's channels close once ctx ends, mirroring
+// TestWatchSubtype_ClosesChannelsWhenContextEnds.
+func TestWatchServiceType_ClosesChannelsWhenContextEnds(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := q.WatchServiceType(ctx, "_http._tcp.local")
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("events channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("events channel was not closed after ctx ended")
+	}
+
+	// Drain any transient send errors (e.g. "context canceled" from a query
+	// racing the cancellation itself) until the channel closes, mirroring
+	// Watch's own at-most-once-delivery guarantee: the channel must
+	// eventually close, but may deliver errors first.
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-errs:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("errs channel was not closed after ctx ended")
+		}
+	}
+}
+
+// TestWatchServiceType_ValidatesInputs validates that WatchServiceType
+// rejects an invalid service type the same way Watch does.
+func TestWatchServiceType_ValidatesInputs(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	events, errs := q.WatchServiceType(context.Background(), "")
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("events channel should be immediately closed for an invalid service type")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("events channel was not closed for an invalid service type")
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected a validation error, got nil")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no error received for an invalid service type")
+	}
+}
+
+// TestServiceEventType_String verifies ServiceEventType's String method
+// covers every defined constant plus the unknown fallback.
+func TestServiceEventType_String(t *testing.T) {
+	tests := []struct {
+		eventType ServiceEventType
+		want      string
+	}{
+		{ServiceFound, "Found"},
+		{ServiceLost, "Lost"},
+		{ServiceChanged, "Changed"},
+		{ServiceEventType(99), "Unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.eventType.String(); got != tt.want {
+			t.Errorf("ServiceEventType(%d).String() = %q, want %q", tt.eventType, got, tt.want)
+		}
+	}
+}
+
+// TestSameServiceInstance verifies sameServiceInstance compares SRV
+// target/port and TXT contents, not just InstanceName.
+func TestSameServiceInstance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b ServiceInstance
+		want bool
+	}{
+		{
+			name: "identical",
+			a:    ServiceInstance{Target: "host.local", Port: 80, TXT: []string{"a=1"}},
+			b:    ServiceInstance{Target: "host.local", Port: 80, TXT: []string{"a=1"}},
+			want: true,
+		},
+		{
+			name: "different port",
+			a:    ServiceInstance{Target: "host.local", Port: 80},
+			b:    ServiceInstance{Target: "host.local", Port: 81},
+			want: false,
+		},
+		{
+			name: "different TXT",
+			a:    ServiceInstance{TXT: []string{"a=1"}},
+			b:    ServiceInstance{TXT: []string{"a=2"}},
+			want: false,
+		},
+		{
+			name: "different TXT length",
+			a:    ServiceInstance{TXT: []string{"a=1"}},
+			b:    ServiceInstance{TXT: []string{"a=1", "b=2"}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameServiceInstance(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameServiceInstance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package querier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// newTestWatchSession builds a watchSession bypassing Querier.Watch, so
+// tests can drive its cache logic directly without a real network
+// round-trip, mirroring internal/browser's newTestSession helper.
+func newTestWatchSession(t *testing.T, name string, recordType RecordType) *watchSession {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	session := &watchSession{
+		key:        name,
+		name:       name,
+		recordType: recordType,
+		records:    make(chan ResourceRecord, watchRecordBufferSize),
+		errs:       make(chan error, watchErrBufferSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		cache:      make(map[string]map[string]*watchEntry),
+	}
+	t.Cleanup(session.stopTimers)
+	return session
+}
+
+// TestWatchSession_EmitsRecordOnFirstAnswer validates that a fresh matching
+// answer is emitted on the records channel.
+func TestWatchSession_EmitsRecordOnFirstAnswer(t *testing.T) {
+	session := newTestWatchSession(t, "printer.local", RecordTypeA)
+
+	answer := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+	session.handleAnswer(answer)
+
+	select {
+	case rec := <-session.records:
+		if rec.Name != "printer.local" || rec.Type != RecordTypeA {
+			t.Errorf("record = %+v, want printer.local/A", rec)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no record received")
+	}
+}
+
+// TestWatchSession_IgnoresNonMatchingNameAndType validates that handleAnswer
+// drops answers for a different name or a different, non-ANY record type.
+func TestWatchSession_IgnoresNonMatchingNameAndType(t *testing.T) {
+	session := newTestWatchSession(t, "printer.local", RecordTypeA)
+
+	wrongName := message.Answer{NAME: "other.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: net.IPv4(1, 2, 3, 4).To4()}
+	wrongType := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeTXT), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: []byte{0}}
+	session.handleAnswer(wrongName)
+	session.handleAnswer(wrongType)
+
+	select {
+	case rec := <-session.records:
+		t.Fatalf("unexpected record received: %+v", rec)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestWatchSession_UnchangedAnswerNotReemitted validates that re-receiving
+// the same still-fresh record (as every RFC 6762 §5.2 re-query would)
+// doesn't flood the records channel.
+func TestWatchSession_UnchangedAnswerNotReemitted(t *testing.T) {
+	session := newTestWatchSession(t, "printer.local", RecordTypeA)
+
+	answer := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+	session.handleAnswer(answer)
+
+	select {
+	case <-session.records:
+	case <-time.After(1 * time.Second):
+		t.Fatal("no record received for first answer")
+	}
+
+	session.handleAnswer(answer)
+
+	select {
+	case rec := <-session.records:
+		t.Fatalf("unexpected re-emit of unchanged record: %+v", rec)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestWatchSession_GoodbyeRecordEvictsWithoutEmitting validates that a
+// TTL=0 answer (RFC 6762 §10.1 goodbye record) evicts its cache entry
+// without being emitted itself.
+func TestWatchSession_GoodbyeRecordEvictsWithoutEmitting(t *testing.T) {
+	session := newTestWatchSession(t, "printer.local", RecordTypeA)
+
+	answer := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+	session.handleAnswer(answer)
+	<-session.records
+
+	goodbye := answer
+	goodbye.TTL = 0
+	session.handleAnswer(goodbye)
+
+	select {
+	case rec := <-session.records:
+		t.Fatalf("goodbye record should not be emitted, got %+v", rec)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	session.mu.Lock()
+	_, exists := session.cache["printer.local|1"]["printer.local|1|192.168.1.5"]
+	session.mu.Unlock()
+	if exists {
+		t.Error("cache entry still present after goodbye record")
+	}
+
+	// Re-announcing after the goodbye is treated as new again.
+	session.handleAnswer(answer)
+	select {
+	case <-session.records:
+	case <-time.After(1 * time.Second):
+		t.Fatal("no record received after re-announce following goodbye")
+	}
+}
+
+// TestWatchSession_CacheFlushReplacesRRSet validates that a cache-flush-bit
+// answer (RFC 6762 §10.2) evicts the rest of its name+type's cached rrset
+// and is always emitted, even though its data differs from what's cached.
+func TestWatchSession_CacheFlushReplacesRRSet(t *testing.T) {
+	session := newTestWatchSession(t, "printer.local", RecordTypeA)
+
+	first := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+	session.handleAnswer(first)
+	<-session.records
+
+	flushed := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN) | 0x8000, TTL: 120, RDATA: net.IPv4(192, 168, 1, 9).To4()}
+	session.handleAnswer(flushed)
+
+	select {
+	case rec := <-session.records:
+		ip := rec.AsA()
+		if ip == nil || !ip.Equal(net.IPv4(192, 168, 1, 9)) {
+			t.Errorf("AsA() = %v, want 192.168.1.9", ip)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no record received for cache-flush answer")
+	}
+
+	session.mu.Lock()
+	group := session.cache["printer.local|1"]
+	session.mu.Unlock()
+	if len(group) != 1 {
+		t.Errorf("cache group has %d entries after flush, want 1", len(group))
+	}
+}
+
+// TestWatchSession_RecordTypeANY_MatchesMultipleTypes validates that a
+// RecordTypeANY watch accepts answers of differing types for the same name.
+func TestWatchSession_RecordTypeANY_MatchesMultipleTypes(t *testing.T) {
+	session := newTestWatchSession(t, "printer.local", RecordTypeANY)
+
+	a := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+	txt := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeTXT), CLASS: uint16(protocol.ClassIN), TTL: 120, RDATA: []byte{4, 'p', '=', '/', 'a'}}
+
+	session.handleAnswer(a)
+	session.handleAnswer(txt)
+
+	got := map[RecordType]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case rec := <-session.records:
+			got[rec.Type] = true
+		case <-time.After(1 * time.Second):
+			t.Fatal("expected two records for RecordTypeANY watch")
+		}
+	}
+	if !got[RecordTypeA] || !got[RecordTypeTXT] {
+		t.Errorf("got record types %v, want both A and TXT", got)
+	}
+}
+
+// TestWatchBackoffPolicy_NextCapsAtMaxDelay validates that repeated failures
+// never exceed MaxDelay, even disregarding jitter.
+func TestWatchBackoffPolicy_NextCapsAtMaxDelay(t *testing.T) {
+	policy := WatchBackoffPolicy{BaseDelay: time.Second, Factor: 1.6, MaxDelay: 10 * time.Second, Jitter: 0}
+
+	delay := policy.next(20)
+	if delay != 10*time.Second {
+		t.Errorf("next(20) = %v, want exactly MaxDelay (10s) with zero jitter", delay)
+	}
+}
+
+// TestWatchBackoffPolicy_NextGrowsWithAttempt validates that the delay
+// increases across attempts before hitting the cap.
+func TestWatchBackoffPolicy_NextGrowsWithAttempt(t *testing.T) {
+	policy := WatchBackoffPolicy{BaseDelay: time.Second, Factor: 1.6, MaxDelay: time.Minute, Jitter: 0}
+
+	d0 := policy.next(0)
+	d1 := policy.next(1)
+	if d0 != time.Second {
+		t.Errorf("next(0) = %v, want BaseDelay (1s) with zero jitter", d0)
+	}
+	if d1 <= d0 {
+		t.Errorf("next(1) = %v, want greater than next(0) = %v", d1, d0)
+	}
+}
+
+// TestNextWatchQueryDelay_DoublesAndCaps validates the RFC 6762 §5.2
+// doubling schedule (allowing for its up-to-2% jitter) and its 60-minute
+// ceiling.
+func TestNextWatchQueryDelay_DoublesAndCaps(t *testing.T) {
+	delay := time.Duration(0)
+	for i := 0; i < 3; i++ {
+		delay = nextWatchQueryDelay(delay)
+	}
+	// 1s -> 2s -> 4s, each step compounding up to 2% jitter.
+	if delay < 4*time.Second || delay > 4*time.Second*110/100 {
+		t.Errorf("delay after 3 steps = %v, want ~4s (+ cumulative up to ~2%% jitter per step)", delay)
+	}
+
+	huge := nextWatchQueryDelay(maxWatchQueryDelay)
+	if huge != maxWatchQueryDelay {
+		t.Errorf("nextWatchQueryDelay(maxWatchQueryDelay) = %v, want unchanged at the cap", huge)
+	}
+}
+
+// TestWatchQueryJitter_StaysWithinTwoPercent validates that the jitter added
+// to nextWatchQueryDelay's backoff never exceeds 2% of the base delay, and
+// is never negative.
+func TestWatchQueryJitter_StaysWithinTwoPercent(t *testing.T) {
+	base := 10 * time.Second
+	for i := 0; i < 100; i++ {
+		jitter := watchQueryJitter(base)
+		if jitter < 0 || jitter > base*2/100 {
+			t.Fatalf("watchQueryJitter(%v) = %v, want within [0, 2%%]", base, jitter)
+		}
+	}
+}
+
+// TestQuerier_Watch_RejectsDuplicateSession validates that a second Watch
+// call for the same (name, recordType) pair fails rather than starting a
+// competing session, mirroring Browse's one-session-per-service-type rule.
+func TestQuerier_Watch_RejectsDuplicateSession(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _ = q.Watch(ctx, "printer.local", RecordTypeA)
+	records, errs := q.Watch(ctx, "printer.local", RecordTypeA)
+
+	select {
+	case _, ok := <-records:
+		if ok {
+			t.Error("records channel should be immediately closed for a duplicate Watch")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("records channel was not closed for a duplicate Watch")
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected a duplicate-session error, got nil")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no error received for a duplicate Watch")
+	}
+}
+
+// TestQuerier_Watch_ClosesChannelsWhenContextEnds validates that Watch's
+// channels are closed once the caller's context ends, without requiring
+// Close() on the Querier itself.
+func TestQuerier_Watch_ClosesChannelsWhenContextEnds(t *testing.T) {
+	q, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	records, errs := q.Watch(ctx, "watched.local", RecordTypeA)
+	cancel()
+
+	select {
+	case _, ok := <-records:
+		if ok {
+			t.Error("records channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("records channel was not closed after ctx ended")
+	}
+
+	select {
+	case _, ok := <-errs:
+		if ok {
+			t.Error("errs channel should close after ctx ends")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("errs channel was not closed after ctx ended")
+	}
+}
+
+// TestQuerier_Watch_RefreshesNearTTLExpiry validates the RFC 6762 §5.2
+// active-refresh schedule: once a cached record's TTL has mostly elapsed,
+// Watch re-queries well before nextWatchQueryDelay's own backoff would have
+// resent anyway.
+func TestQuerier_Watch_RefreshesNearTTLExpiry(t *testing.T) {
+	mock := transport.NewMockTransport()
+	q, err := New(WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = q.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	records, _ := q.Watch(ctx, "printer.local", RecordTypeA)
+
+	deadline := time.Now().Add(1 * time.Second)
+	for len(mock.SendCalls()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(mock.SendCalls()) == 0 {
+		t.Fatal("Watch() never sent its initial query")
+	}
+
+	answer := message.Answer{NAME: "printer.local", TYPE: uint16(protocol.RecordTypeA), CLASS: uint16(protocol.ClassIN), TTL: 1, RDATA: net.IPv4(192, 168, 1, 5).To4()}
+	packet, err := message.BuildResponse([]*message.ResourceRecord{
+		{Name: answer.NAME, Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: answer.TTL, Data: answer.RDATA},
+	})
+	if err != nil {
+		t.Fatalf("BuildResponse() failed: %v", err)
+	}
+	mock.QueueReceive(packet, &net.UDPAddr{IP: net.IPv4(192, 168, 1, 5), Port: 5353})
+
+	select {
+	case <-records:
+	case <-time.After(1 * time.Second):
+		t.Fatal("Watch() never emitted the answer")
+	}
+
+	// 80% of the 1s TTL is 800ms, well before nextWatchQueryDelay's own ~1s
+	// (+jitter) resend - wait past it but short of the plain backoff resend.
+	time.Sleep(900 * time.Millisecond)
+
+	if calls := len(mock.SendCalls()); calls < 2 {
+		t.Errorf("SendCalls() = %d, want >= 2 (initial query + an 80%%-of-TTL refresh)", calls)
+	}
+}
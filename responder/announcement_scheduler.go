@@ -0,0 +1,103 @@
+package responder
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/state"
+)
+
+// AnnouncePolicy configures how many unsolicited announcements Register
+// sends once probing succeeds, and how widely spaced they are, per RFC 6762
+// §8.3. Set via WithAnnouncePolicy.
+type AnnouncePolicy = state.AnnouncePolicy
+
+// DefaultAnnouncePolicy returns RFC 6762 §8.3's mandatory minimum: exactly
+// the 2 announcements Register always sends, with no additional ones - the
+// behavior Register had before WithAnnouncePolicy existed, so a Responder
+// that never sets one sees no change.
+func DefaultAnnouncePolicy() AnnouncePolicy {
+	return state.DefaultAnnouncePolicy()
+}
+
+// WithAnnouncePolicy configures how many announcements beyond RFC 6762
+// §8.3's mandatory pair Register sends for each service, and how widely
+// spaced they are. Without this option, Register sends only the mandatory
+// pair (DefaultAnnouncePolicy).
+//
+// The mandatory pair itself (one second apart) is unaffected by this option
+// and always completes before Register returns; any additional sends
+// policy.Count allows run afterward on their own background schedule (see
+// startAnnouncementScheduler), so a large Count never makes Register block
+// for however long those extra sends are spaced out.
+//
+// Example:
+//
+//	r, err := New(ctx, WithAnnouncePolicy(state.AnnouncePolicy{
+//		Base: time.Second, Factor: 2, Max: time.Minute, Jitter: 0.2, Count: 4,
+//	}))
+func WithAnnouncePolicy(policy AnnouncePolicy) Option {
+	return func(r *Responder) error {
+		r.announcePolicy = policy
+		return nil
+	}
+}
+
+// startAnnouncementScheduler starts a background goroutine that sends
+// serviceName's additional announcements (RFC 6762 §8.3's "MAY send
+// additional announcements" allowance, beyond the mandatory pair Register
+// already sent), spaced per r.announcePolicy.Intervals(), unless one is
+// already running for it - which happens when a suspended service's
+// recovered check calls Register again.
+func (r *Responder) startAnnouncementScheduler(machine *state.Machine, serviceName string) {
+	r.announceMu.Lock()
+	defer r.announceMu.Unlock()
+
+	if _, running := r.announceCancels[serviceName]; running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.announceCancels[serviceName] = cancel
+	go r.runAnnouncementScheduler(ctx, machine)
+}
+
+// stopAnnouncementScheduler cancels serviceName's background scheduler, if
+// one is running. Unregister calls this so an explicitly torn-down service
+// stops sending further announcements for it.
+func (r *Responder) stopAnnouncementScheduler(serviceName string) {
+	r.announceMu.Lock()
+	defer r.announceMu.Unlock()
+
+	if cancel, ok := r.announceCancels[serviceName]; ok {
+		cancel()
+		delete(r.announceCancels, serviceName)
+	}
+}
+
+// runAnnouncementScheduler waits out each interval in r.announcePolicy's
+// schedule beyond the mandatory pair, then sends one additional announcement
+// through machine's Announcer via SendAdditional, until ctx ends.
+//
+// r.announcePolicy.Intervals() returns one interval per send from the
+// second announcement onward; the first (the mandatory pair's 1s gap) is
+// skipped here since Run already waited it out before Register returned.
+func (r *Responder) runAnnouncementScheduler(ctx context.Context, machine *state.Machine) {
+	intervals := r.announcePolicy.Intervals()
+	if len(intervals) < 2 {
+		return
+	}
+
+	announcer := machine.GetAnnouncer()
+	for _, interval := range intervals[1:] {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if err := announcer.SendAdditional(ctx); err != nil {
+			return
+		}
+	}
+}
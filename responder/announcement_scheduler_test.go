@@ -0,0 +1,142 @@
+package responder
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/state"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestResponder_New_WithAnnouncePolicy_Sets verifies WithAnnouncePolicy
+// stores the given AnnouncePolicy on the Responder.
+func TestResponder_New_WithAnnouncePolicy_Sets(t *testing.T) {
+	ctx := context.Background()
+	policy := AnnouncePolicy{
+		Base: 1 * time.Second, Factor: 2, Max: time.Minute, Jitter: 0.1, Count: 4,
+	}
+
+	responder, err := New(ctx, WithAnnouncePolicy(policy))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = responder.Close() }()
+
+	if responder.announcePolicy != policy {
+		t.Errorf("responder.announcePolicy = %+v, want %+v", responder.announcePolicy, policy)
+	}
+}
+
+// TestResponder_Register_DefaultAnnouncePolicy_NoScheduler verifies Register
+// never starts an announcement scheduler when AnnouncePolicy isn't
+// configured (the default Count of 2 means only the mandatory pair is ever
+// sent).
+func TestResponder_Register_DefaultAnnouncePolicy_NoScheduler(t *testing.T) {
+	ctx := context.Background()
+	mock := transport.NewMockTransport()
+
+	responder, err := New(ctx, WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = responder.Close() }()
+
+	service := &Service{InstanceName: "My Printer", ServiceType: "_http._tcp.local", Port: 8080}
+	if err := responder.Register(service); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	serviceName := service.InstanceName + "." + service.ServiceType
+	responder.announceMu.Lock()
+	_, running := responder.announceCancels[serviceName]
+	responder.announceMu.Unlock()
+
+	if running {
+		t.Error("announcement scheduler running, want none started for the default AnnouncePolicy")
+	}
+}
+
+// TestResponder_Register_WithAnnouncePolicy_SendsAdditionalAnnouncements
+// verifies Register starts a background scheduler that sends the additional
+// announcements an AnnouncePolicy.Count greater than 2 allows, beyond the
+// mandatory pair Register already sent synchronously.
+func TestResponder_Register_WithAnnouncePolicy_SendsAdditionalAnnouncements(t *testing.T) {
+	ctx := context.Background()
+
+	responder, err := New(ctx,
+		WithAnnouncePolicy(state.AnnouncePolicy{
+			Base: 20 * time.Millisecond, Factor: 1, Max: time.Second, Count: 3,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = responder.Close() }()
+
+	var announceCount atomic.Int32
+	responder.OnAnnounce(func() { announceCount.Add(1) })
+
+	service := &Service{InstanceName: "My Printer", ServiceType: "_http._tcp.local", Port: 8080}
+	if err := responder.Register(service); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	// Register already sent the mandatory pair; wait for the scheduler's one
+	// additional send (Count=3 means one send beyond the pair) to land.
+	deadline := time.After(2 * time.Second)
+	for {
+		if announceCount.Load() >= 3 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("announceCount = %d after 2s, want >= 3 (mandatory pair + 1 additional)", announceCount.Load())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// TestResponder_Unregister_StopsAnnouncementScheduler verifies Unregister
+// cancels a running announcement scheduler so it doesn't keep sending
+// announcements for a service that's no longer registered.
+func TestResponder_Unregister_StopsAnnouncementScheduler(t *testing.T) {
+	ctx := context.Background()
+	mock := transport.NewMockTransport()
+
+	responder, err := New(ctx,
+		WithTransport(mock),
+		WithAnnouncePolicy(state.AnnouncePolicy{
+			Base: 1 * time.Hour, Factor: 1, Max: time.Hour, Count: 3,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = responder.Close() }()
+
+	service := &Service{InstanceName: "My Printer", ServiceType: "_http._tcp.local", Port: 8080}
+	if err := responder.Register(service); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	serviceName := service.InstanceName + "." + service.ServiceType
+	responder.announceMu.Lock()
+	_, running := responder.announceCancels[serviceName]
+	responder.announceMu.Unlock()
+	if !running {
+		t.Fatal("announcement scheduler not running after Register(), want it started")
+	}
+
+	if err := responder.Unregister(service.InstanceName); err != nil {
+		t.Fatalf("Unregister() error = %v, want nil", err)
+	}
+
+	responder.announceMu.Lock()
+	_, stillRunning := responder.announceCancels[serviceName]
+	responder.announceMu.Unlock()
+	if stillRunning {
+		t.Error("announcement scheduler still running after Unregister(), want it stopped")
+	}
+}
@@ -0,0 +1,183 @@
+package responder
+
+import (
+	"context"
+	"net"
+
+	"github.com/joshuafuller/beacon/internal/browser"
+	"github.com/joshuafuller/beacon/internal/records"
+)
+
+// browseUpdateBufferSize matches internal/browser's own Events channel
+// buffer, since Browse just relabels that channel's entries one-for-one.
+const browseUpdateBufferSize = 32
+
+// BrowseUpdateKind identifies what a BrowseUpdate reports about a
+// discovered service instance.
+type BrowseUpdateKind int
+
+const (
+	// BrowseFound indicates a newly resolved service instance (SRV and at
+	// least one address have arrived; TXT is optional).
+	BrowseFound BrowseUpdateKind = iota
+
+	// BrowseUpdated indicates an already-found instance whose host, port,
+	// addresses, or TXT metadata changed without disappearing.
+	BrowseUpdated
+
+	// BrowseLost indicates an instance whose record expired (TTL reached
+	// zero) or was withdrawn via a goodbye packet per RFC 6762 §10.1.
+	BrowseLost
+)
+
+// String returns a human-readable name for the update kind.
+func (k BrowseUpdateKind) String() string {
+	switch k {
+	case BrowseFound:
+		return "Found"
+	case BrowseUpdated:
+		return "Updated"
+	case BrowseLost:
+		return "Lost"
+	default:
+		return "Unknown"
+	}
+}
+
+// BrowseUpdate reports a change to a service instance discovered by
+// Browse, merged from whatever combination of PTR/SRV/TXT/A/AAAA records
+// have arrived for it so far.
+type BrowseUpdate struct {
+	Kind BrowseUpdateKind
+
+	InstanceName string
+	Hostname     string
+	Port         int
+	IPs          []net.IP
+	TXT          map[string]string
+}
+
+// Browse starts (or resumes) continuous discovery of serviceType and
+// returns a channel of BrowseUpdates, closed when ctx is done or the
+// Responder is closed. Only one Browse per service type may be active at a
+// time, matching internal/browser.Browser.Browse.
+//
+// Browse shares this Responder's existing transport and query-handler
+// goroutine rather than opening a second socket: handleQuery already
+// receives every mDNS packet on the wire, so it hands any response (QR=1)
+// packet to the same browser.Browser this method drives, instead of the
+// plain drop that happened before chunk28-4.
+//
+// Internally this is RFC 6762 §5.2 continuous querying - PTR queries on a
+// 1s/2s/4s/... backoff, capped at 60 minutes - with a cache keyed by
+// instance name, expiring entries by each record's own TTL and emitting
+// BrowseLost on expiry or goodbye (TTL=0). BrowseFound fires only once SRV
+// and an address have both resolved; TXT and subsequent changes arrive as
+// BrowseUpdated.
+func (r *Responder) Browse(ctx context.Context, serviceType string) (<-chan BrowseUpdate, error) {
+	events, err := r.ensureBrowser().Browse(ctx, serviceType)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan BrowseUpdate, browseUpdateBufferSize)
+	go func() {
+		defer close(updates)
+		for ev := range events {
+			updates <- convertBrowseEvent(ev)
+		}
+	}()
+
+	return updates, nil
+}
+
+// convertBrowseEvent translates a browser.Event - built around
+// records.ServiceInfo, the same type Register's own registry uses - into
+// the BrowseUpdate this package's public API exposes.
+func convertBrowseEvent(ev browser.Event) BrowseUpdate {
+	var kind BrowseUpdateKind
+	switch ev.Type {
+	case browser.EventAdded:
+		kind = BrowseFound
+	case browser.EventRemoved:
+		kind = BrowseLost
+	case browser.EventUpdated:
+		kind = BrowseUpdated
+	}
+
+	return BrowseUpdate{
+		Kind:         kind,
+		InstanceName: ev.Instance.InstanceName,
+		Hostname:     ev.Instance.Hostname,
+		Port:         ev.Instance.Port,
+		IPs:          serviceInfoIPs(ev.Instance),
+		TXT:          txtRecordsToMap(ev.Instance.TXTRecords),
+	}
+}
+
+// serviceInfoIPs collects a records.ServiceInfo's single IPv4 address (if
+// any) and its IPv6 addresses into one net.IP slice.
+func serviceInfoIPs(info records.ServiceInfo) []net.IP {
+	var ips []net.IP
+	if len(info.IPv4Address) > 0 {
+		ips = append(ips, net.IP(info.IPv4Address))
+	}
+	for _, addr := range info.IPv6Addresses {
+		ips = append(ips, net.IP(addr))
+	}
+	return ips
+}
+
+// txtRecordsToMap flattens []records.TXTRecord down to the map[string]string
+// BrowseUpdate exposes, dropping a present-with-no-value (boolean) entry's
+// distinction from "absent" - callers needing that distinction can still
+// reach it via the lower-level querier package's AsTXTRecords.
+func txtRecordsToMap(txt []records.TXTRecord) map[string]string {
+	if len(txt) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(txt))
+	for _, entry := range txt {
+		if entry.Present {
+			m[entry.Key] = string(entry.Value)
+		}
+	}
+	return m
+}
+
+// ensureBrowser lazily creates r.browser the first time Browse is called,
+// built on r.transport (the single Transport every Responder sends and
+// receives through, even a multi-homed one built via WithTransports - see
+// newPluginGroupTransport) so discovery queries and answers ride the same
+// socket and query-handler goroutine as query handling.
+func (r *Responder) ensureBrowser() *browser.Browser {
+	r.browserMu.Lock()
+	defer r.browserMu.Unlock()
+
+	if r.browser == nil {
+		r.browser = browser.NewWithTransport(r.ctx, r.transport)
+	}
+	return r.browser
+}
+
+// browserOrNil returns r.browser without creating it, for handleQuery's
+// response-dispatch path - a Responder that never calls Browse shouldn't
+// pay to construct one just to immediately find it has no sessions.
+func (r *Responder) browserOrNil() *browser.Browser {
+	r.browserMu.Lock()
+	defer r.browserMu.Unlock()
+	return r.browser
+}
+
+// closeBrowser releases r.browser, if Browse was ever called, ending every
+// active Browse session. Called from Close/Shutdown; it does not touch
+// r.transport, which NewWithTransport never took ownership of.
+func (r *Responder) closeBrowser() {
+	r.browserMu.Lock()
+	b := r.browser
+	r.browserMu.Unlock()
+
+	if b != nil {
+		_ = b.Close()
+	}
+}
@@ -0,0 +1,156 @@
+package responder
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// encodeNameOrFatal encodes name as a PTR/SRV RDATA target, mirroring
+// internal/browser's own test helper of the same name.
+func encodeNameOrFatal(t *testing.T, name string) []byte {
+	t.Helper()
+	encoded, err := message.EncodeOwnerName(name)
+	if err != nil {
+		t.Fatalf("EncodeOwnerName(%q) failed: %v", name, err)
+	}
+	return encoded
+}
+
+func srvRDATA(t *testing.T, priority, weight, port uint16, target string) []byte {
+	t.Helper()
+	rdata := make([]byte, 6)
+	binary.BigEndian.PutUint16(rdata[0:2], priority)
+	binary.BigEndian.PutUint16(rdata[2:4], weight)
+	binary.BigEndian.PutUint16(rdata[4:6], port)
+	return append(rdata, encodeNameOrFatal(t, target)...)
+}
+
+func txtRDATA(kvs ...string) []byte {
+	var rdata []byte
+	for _, kv := range kvs {
+		rdata = append(rdata, byte(len(kv)))
+		rdata = append(rdata, kv...)
+	}
+	return rdata
+}
+
+// instanceResponsePacket builds the wire-format response a real responder
+// would send for one resolved instance: PTR/SRV/TXT/A answers, the same
+// shape instanceAnswers builds in internal/browser's own tests.
+func instanceResponsePacket(t *testing.T, serviceType, instanceName, hostname string, port uint16, ip net.IP) []byte {
+	t.Helper()
+
+	records := []*message.ResourceRecord{
+		{Name: serviceType, Type: protocol.RecordTypePTR, Class: protocol.ClassIN, TTL: 4500, Data: encodeNameOrFatal(t, instanceName)},
+		{Name: instanceName, Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: srvRDATA(t, 0, 0, port, hostname)},
+		{Name: instanceName, Type: protocol.RecordTypeTXT, Class: protocol.ClassIN, TTL: 4500, Data: txtRDATA("path=/")},
+		{Name: hostname, Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: ip.To4()},
+	}
+
+	packet, err := message.BuildResponse(records)
+	if err != nil {
+		t.Fatalf("BuildResponse() error = %v, want nil", err)
+	}
+	return packet
+}
+
+// TestResponder_Browse_FindsInstanceViaQueryHandler drives a response
+// packet through handleQuery - the same goroutine/transport query handling
+// uses - and checks Browse emits a BrowseFound update once the PTR's
+// SRV/TXT/A companions resolve it.
+func TestResponder_Browse_FindsInstanceViaQueryHandler(t *testing.T) {
+	mock := transport.NewMockTransport()
+	r, err := New(context.Background(), WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	updates, err := r.Browse(context.Background(), "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("Browse() error = %v, want nil", err)
+	}
+
+	packet := instanceResponsePacket(t, "_http._tcp.local", "printer._http._tcp.local", "printer.local", 8080, net.IPv4(192, 168, 1, 5))
+	if err := r.handleQuery(packet, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() error = %v, want nil", err)
+	}
+
+	select {
+	case update := <-updates:
+		if update.Kind != BrowseFound {
+			t.Errorf("Kind = %v, want BrowseFound", update.Kind)
+		}
+		if update.InstanceName != "printer._http._tcp.local" {
+			t.Errorf("InstanceName = %q", update.InstanceName)
+		}
+		if update.Hostname != "printer.local" {
+			t.Errorf("Hostname = %q, want printer.local", update.Hostname)
+		}
+		if update.Port != 8080 {
+			t.Errorf("Port = %d, want 8080", update.Port)
+		}
+		if len(update.IPs) != 1 || !update.IPs[0].Equal(net.IPv4(192, 168, 1, 5)) {
+			t.Errorf("IPs = %v, want [192.168.1.5]", update.IPs)
+		}
+		if update.TXT["path"] != "/" {
+			t.Errorf("TXT[path] = %q, want /", update.TXT["path"])
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("no BrowseUpdate received")
+	}
+}
+
+// TestResponder_Browse_SameServiceTypeTwiceErrors validates that Browse
+// rejects a second concurrent call for the same service type, matching
+// internal/browser.Browser.Browse's own restriction.
+func TestResponder_Browse_SameServiceTypeTwiceErrors(t *testing.T) {
+	r, err := New(context.Background(), WithTransport(transport.NewMockTransport()))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	if _, err := r.Browse(context.Background(), "_http._tcp.local"); err != nil {
+		t.Fatalf("Browse() #1 error = %v, want nil", err)
+	}
+
+	if _, err := r.Browse(context.Background(), "_http._tcp.local"); err == nil {
+		t.Fatal("Browse() #2 error = nil, want an already-browsing error")
+	}
+}
+
+// TestResponder_Close_WithActiveBrowseSession validates that Close tears
+// down an active Browse session instead of leaking its goroutines.
+func TestResponder_Close_WithActiveBrowseSession(t *testing.T) {
+	mock := transport.NewMockTransport()
+	r, err := New(context.Background(), WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+
+	updates, err := r.Browse(context.Background(), "_http._tcp.local")
+	if err != nil {
+		t.Fatalf("Browse() error = %v, want nil", err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("updates channel produced a value after Close(), want it closed")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("updates channel was not closed by Close()")
+	}
+}
@@ -0,0 +1,215 @@
+package responder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// ServiceConfig describes one service entry in a ConfigWatcher document.
+//
+// InstanceName, ServiceType and Port map directly onto Service. Hints lists
+// address hints (e.g. "192.168.1.50") that operators can pin for multi-homed
+// hosts; Beacon does not yet act on them (getLocalIPv4 still picks the
+// address), but they round-trip so config files stay forward-compatible as
+// that support lands.
+type ServiceConfig struct {
+	InstanceName string            `json:"instance_name" yaml:"instance_name"`
+	ServiceType  string            `json:"service_type" yaml:"service_type"`
+	Port         int               `json:"port" yaml:"port"`
+	TXT          map[string]string `json:"txt,omitempty" yaml:"txt,omitempty"`
+	Hostname     string            `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Hints        []string          `json:"hints,omitempty" yaml:"hints,omitempty"`
+}
+
+// ConfigFile is the top-level document a ConfigWatcher loads and reloads.
+type ConfigFile struct {
+	Services []ServiceConfig `json:"services" yaml:"services"`
+}
+
+// ConfigWatcher watches a YAML or JSON file describing a set of services and
+// keeps a Responder's registered services in sync with it.
+//
+// On each reload: new entries are Register()ed, removed entries are
+// unregistered (sending a goodbye packet with TTL=0 per RFC 6762 §10.1), and
+// entries whose fields changed are unregistered and re-registered so the
+// network sees a fresh probe/announce cycle for the new record set.
+//
+// This lets Beacon run as a sidecar/daemon whose advertised services are
+// managed declaratively, without restarting the process.
+type ConfigWatcher struct {
+	path      string
+	responder *Responder
+	watcher   *fsnotify.Watcher
+
+	mu      sync.Mutex
+	current map[string]ServiceConfig // keyed by serviceKey(InstanceName, ServiceType)
+
+	done chan struct{}
+}
+
+// NewConfigWatcher loads path and registers its services with responder, then
+// starts watching path for subsequent changes.
+//
+// path's directory (not the file itself) is watched, since editors and
+// config-management tools commonly replace the file via rename rather than
+// writing in place; events are filtered back down to path.
+func NewConfigWatcher(responder *Responder, path string) (*ConfigWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %q: %w", dir, err)
+	}
+
+	cw := &ConfigWatcher{
+		path:      path,
+		responder: responder,
+		watcher:   watcher,
+		current:   make(map[string]ServiceConfig),
+		done:      make(chan struct{}),
+	}
+
+	if err := cw.reload(); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	go cw.run()
+
+	return cw, nil
+}
+
+// Close stops watching the config file. It does not unregister the services
+// last applied from it.
+func (cw *ConfigWatcher) Close() error {
+	close(cw.done)
+	return cw.watcher.Close()
+}
+
+// run consumes fsnotify events for the watched directory, reloading the
+// config whenever the watched file itself is written, created or renamed
+// into place.
+func (cw *ConfigWatcher) run() {
+	for {
+		select {
+		case <-cw.done:
+			return
+		case event, ok := <-cw.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(cw.path) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			// Best-effort: a bad edit leaves the last-known-good services
+			// running rather than tearing them down.
+			_ = cw.reload()
+		case _, ok := <-cw.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// reload reads the config file, diffs it against the last-applied set, and
+// applies Register/Unregister calls for whatever changed.
+func (cw *ConfigWatcher) reload() error {
+	cfg, err := loadConfigFile(cw.path)
+	if err != nil {
+		return err
+	}
+
+	next := make(map[string]ServiceConfig, len(cfg.Services))
+	for _, svc := range cfg.Services {
+		next[serviceKey(svc.InstanceName, svc.ServiceType)] = svc
+	}
+
+	cw.mu.Lock()
+	prev := cw.current
+	cw.mu.Unlock()
+
+	// Removed entries: de-register first so a renamed-then-removed service
+	// never briefly double-registers.
+	for key, svc := range prev {
+		if _, ok := next[key]; !ok {
+			if err := cw.responder.Unregister(svc.InstanceName); err != nil {
+				return fmt.Errorf("failed to unregister %q: %w", svc.InstanceName, err)
+			}
+		}
+	}
+
+	// New and mutated entries.
+	for key, svc := range next {
+		prevSvc, existed := prev[key]
+		if existed && reflect.DeepEqual(prevSvc, svc) {
+			continue
+		}
+		if existed {
+			// Mutated: tear down the old record set so the rename/re-announce
+			// below reflects the new port/TXT/hostname/hints cleanly.
+			if err := cw.responder.Unregister(prevSvc.InstanceName); err != nil {
+				return fmt.Errorf("failed to unregister %q for reload: %w", prevSvc.InstanceName, err)
+			}
+		}
+
+		service := &Service{
+			InstanceName: svc.InstanceName,
+			ServiceType:  svc.ServiceType,
+			Port:         svc.Port,
+			TXTRecords:   NewTXTRecordsFromMap(svc.TXT),
+			Hostname:     svc.Hostname,
+		}
+		if err := cw.responder.Register(service); err != nil {
+			return fmt.Errorf("failed to register %q: %w", svc.InstanceName, err)
+		}
+	}
+
+	cw.mu.Lock()
+	cw.current = next
+	cw.mu.Unlock()
+
+	return nil
+}
+
+// loadConfigFile reads and parses path as YAML, except for a ".json"
+// extension, which is parsed as JSON.
+func loadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %q: %w", path, err)
+	}
+
+	cfg := &ConfigFile{}
+	if filepath.Ext(path) == ".json" {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %q as JSON: %w", path, err)
+		}
+		return cfg, nil
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %q as YAML: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// serviceKey identifies a ServiceConfig entry across reloads, independent of
+// fields (Port, TXT, Hostname, Hints) that a mutation might change.
+func serviceKey(instanceName, serviceType string) string {
+	return instanceName + "." + serviceType
+}
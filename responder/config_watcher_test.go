@@ -0,0 +1,91 @@
+package responder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes contents to a JSON config file in dir, creating or
+// replacing it atomically (write-then-rename) the way a config-management
+// tool would.
+func writeConfigFile(t *testing.T, dir, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "services.json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("failed to rename temp config into place: %v", err)
+	}
+	return path
+}
+
+// TestConfigWatcher_ReloadAddsAndRemovesServices verifies that editing the
+// watched config file registers new services, de-registers removed ones, and
+// leaves unrelated services alone.
+//
+// RFC 6762 §10.1: removed services must send goodbye packets (TTL=0), which
+// Responder.Unregister already does.
+func TestConfigWatcher_ReloadAddsAndRemovesServices(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing-sensitive test in short mode")
+	}
+
+	ctx := context.Background()
+	r, err := New(ctx)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	dir := t.TempDir()
+	path := writeConfigFile(t, dir, `{
+		"services": [
+			{"instance_name": "Printer", "service_type": "_http._tcp.local", "port": 8080},
+			{"instance_name": "Camera", "service_type": "_http._tcp.local", "port": 8081}
+		]
+	}`)
+
+	cw, err := NewConfigWatcher(r, path)
+	if err != nil {
+		t.Fatalf("NewConfigWatcher() error = %v, want nil", err)
+	}
+	defer func() { _ = cw.Close() }()
+
+	if _, found := r.GetService("Printer"); !found {
+		t.Error("Printer not registered after initial load")
+	}
+	if _, found := r.GetService("Camera"); !found {
+		t.Error("Camera not registered after initial load")
+	}
+
+	// Remove Camera, add Scanner, leave Printer untouched.
+	writeConfigFile(t, dir, `{
+		"services": [
+			{"instance_name": "Printer", "service_type": "_http._tcp.local", "port": 8080},
+			{"instance_name": "Scanner", "service_type": "_http._tcp.local", "port": 8082}
+		]
+	}`)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, cameraFound := r.GetService("Camera")
+		_, scannerFound := r.GetService("Scanner")
+		if !cameraFound && scannerFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("reload did not settle: camera registered = %v, scanner registered = %v", cameraFound, scannerFound)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if _, found := r.GetService("Printer"); !found {
+		t.Error("Printer should remain registered across reload")
+	}
+}
@@ -1,11 +1,12 @@
 package responder
 
 import (
-	"bytes"
 	"fmt"
-	"strings"
+	"sort"
+	"sync"
 
 	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
 )
 
 // ConflictDetector implements RFC 6762 §8.2 Simultaneous Probe Tiebreaking.
@@ -25,13 +26,91 @@ import (
 // it compares the data of that (those) resource record(s) with its own tentative
 // data... The two records are compared and the lexicographically later data wins."
 //
-// This implementation is stateless and safe for concurrent use by multiple
-// Prober instances (F-4: Concurrency and Context Management).
+// Safe for concurrent use by multiple Prober instances (F-4: Concurrency
+// and Context Management); the only mutable state is an optional observer
+// set via SetObserver.
 //
 // Task: T054-T058 (GREEN phase)
 // PRIMARY TECHNICAL AUTHORITY: RFC 6762 §8.2
 type ConflictDetector struct {
-	// Stateless - no fields needed
+	mu       sync.RWMutex
+	observer ConflictObserver
+	hooks    []EventHook
+}
+
+// ConflictObserver receives a ConflictEvent describing the outcome of a
+// single ConflictDetector comparison.
+type ConflictObserver func(ConflictEvent)
+
+// ConflictEvent describes the outcome of one DetectConflict or
+// DetectConflictSet comparison, so operators can plumb conflicts into
+// their own metrics/logging stack, or debug a misbehaving neighbor on the
+// LAN, without patching the library.
+type ConflictEvent struct {
+	// OurRecord and IncomingRecord are the specific pair of records that
+	// decided the outcome. For DetectConflictSet, this is the first
+	// differing pair in canonical order; when the sets tie in length but
+	// are not identical this is the zero value - see DecidedBy.
+	OurRecord      message.ResourceRecord
+	IncomingRecord message.ResourceRecord
+
+	// SourceIP is the address the incoming record was received from, if
+	// known to the caller. ConflictDetector itself never learns this (its
+	// API takes parsed records, not packets), so this is always empty for
+	// events it emits directly; it exists so callers one layer up (e.g. a
+	// Prober handling an incoming probe) can re-stamp the event before
+	// forwarding it to their own sink.
+	SourceIP string
+
+	// DecidedBy identifies which step of the RFC 6762 §8.2 comparison
+	// produced the outcome: "different-names" (not a candidate for
+	// conflict at all), "class", "type", "rdata", "length", or "tie" (no
+	// difference found - fault tolerance).
+	DecidedBy string
+
+	// RDATAIndex is the byte offset within RDATA that decided the
+	// outcome, when DecidedBy is "rdata". -1 otherwise.
+	RDATAIndex int
+
+	// WeWon is true when our record/set is lexicographically later (or
+	// the two tie) - i.e. no conflict, the incoming host must defer.
+	WeWon bool
+
+	// Conflict is true when we must defer to the incoming host.
+	Conflict bool
+}
+
+// SetObserver registers observer to be invoked for every subsequent call to
+// DetectConflict and DetectConflictSet, including the no-conflict "we won"
+// and "identical, fault-tolerant" branches. Passing nil disables observation.
+func (cd *ConflictDetector) SetObserver(observer ConflictObserver) {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	cd.observer = observer
+}
+
+// AddHook registers an additional EventHook, whose OnConflictDetected is
+// invoked for every subsequent DetectConflict/DetectConflictSet call,
+// alongside the single observer set via SetObserver.
+func (cd *ConflictDetector) AddHook(hook EventHook) {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	cd.hooks = append(cd.hooks, hook)
+}
+
+// notify invokes the registered observer and hooks, if any.
+func (cd *ConflictDetector) notify(evt ConflictEvent) {
+	cd.mu.RLock()
+	observer := cd.observer
+	hooks := cd.hooks
+	cd.mu.RUnlock()
+
+	if observer != nil {
+		observer(evt)
+	}
+	for _, hook := range hooks {
+		hook.OnConflictDetected(evt)
+	}
 }
 
 // DetectConflict checks if incomingRecord conflicts with ourRecord per RFC 6762 §8.2.
@@ -88,17 +167,36 @@ func (cd *ConflictDetector) DetectConflict(ourRecord, incomingRecord message.Res
 		return false, fmt.Errorf("invalid incomingRecord: %w", err)
 	}
 
-	// RFC 6762 §8.2: Only records with the same name can conflict
-	// DNS names are case-insensitive per RFC 1035 §2.3.3
-	if !strings.EqualFold(ourRecord.Name, incomingRecord.Name) {
+	// RFC 6762 §8.2: Only records with the same name can conflict.
+	// DNS names are case-insensitive per RFC 1035 §2.3.3; compare their
+	// RFC 4034 §6.2 canonical (lowercased) form rather than raw bytes.
+	if message.CanonicalizeName(ourRecord.Name) != message.CanonicalizeName(incomingRecord.Name) {
+		cd.notify(ConflictEvent{
+			OurRecord:      ourRecord,
+			IncomingRecord: incomingRecord,
+			DecidedBy:      "different-names",
+			RDATAIndex:     -1,
+			WeWon:          true,
+			Conflict:       false,
+		})
 		return false, nil // Different names - no conflict
 	}
 
 	// RFC 6762 §8.2: Compare records lexicographically
 	// Returns: -1 if we lose, 0 if tie, +1 if we win
-	cmp := cd.lexicographicCompare(ourRecord, incomingRecord)
+	cmp, decidedBy, rdataIndex := cd.lexicographicCompareTraced(ourRecord, incomingRecord)
+	conflict := cmp < 0
 
-	if cmp < 0 {
+	cd.notify(ConflictEvent{
+		OurRecord:      ourRecord,
+		IncomingRecord: incomingRecord,
+		DecidedBy:      decidedBy,
+		RDATAIndex:     rdataIndex,
+		WeWon:          !conflict,
+		Conflict:       conflict,
+	})
+
+	if conflict {
 		// We lose tie-break - CONFLICT (we must defer)
 		return true, nil
 	}
@@ -146,16 +244,25 @@ func (cd *ConflictDetector) validateRecord(record message.ResourceRecord) error
 //
 // Task: T056-T057
 func (cd *ConflictDetector) lexicographicCompare(ourRecord, incomingRecord message.ResourceRecord) int {
+	cmp, _, _ := cd.lexicographicCompareTraced(ourRecord, incomingRecord)
+	return cmp
+}
+
+// lexicographicCompareTraced is lexicographicCompare, additionally
+// reporting which step of the algorithm decided the outcome - "class",
+// "type", "rdata" (with the deciding byte's index), "length", or "tie" -
+// so DetectConflict can surface it on the ConflictEvent it emits.
+func (cd *ConflictDetector) lexicographicCompareTraced(ourRecord, incomingRecord message.ResourceRecord) (cmp int, decidedBy string, rdataIndex int) {
 	// Step 1: Compare class (excluding cache-flush bit)
 	// RFC 6762 §10.2: Cache-flush bit is bit 15 of the class field
-	ourClass := uint16(ourRecord.Class) & 0x7FFF // Clear bit 15
-	theirClass := uint16(incomingRecord.Class) & 0x7FFF
+	ourClass := canonicalClass(ourRecord.Class)
+	theirClass := canonicalClass(incomingRecord.Class)
 
 	if ourClass < theirClass {
-		return -1 // They win (numerically greater class)
+		return -1, "class", -1 // They win (numerically greater class)
 	}
 	if ourClass > theirClass {
-		return +1 // We win (numerically greater class)
+		return +1, "class", -1 // We win (numerically greater class)
 	}
 
 	// Step 2: Compare type
@@ -163,10 +270,10 @@ func (cd *ConflictDetector) lexicographicCompare(ourRecord, incomingRecord messa
 	theirType := uint16(incomingRecord.Type)
 
 	if ourType < theirType {
-		return -1 // They win (numerically greater type)
+		return -1, "type", -1 // They win (numerically greater type)
 	}
 	if ourType > theirType {
-		return +1 // We win (numerically greater type)
+		return +1, "type", -1 // We win (numerically greater type)
 	}
 
 	// Step 3: Compare RDATA bytewise
@@ -183,13 +290,267 @@ func (cd *ConflictDetector) lexicographicCompare(ourRecord, incomingRecord messa
 	//
 	// Example from RFC: 169.254.200.50 wins over 169.254.99.200
 	// (byte 200 > byte 99, even though 200 as signed would be -56)
-	cmp := bytes.Compare(ourRecord.Data, incomingRecord.Data)
+	minLen := len(ourRecord.Data)
+	if len(incomingRecord.Data) < minLen {
+		minLen = len(incomingRecord.Data)
+	}
+	for i := 0; i < minLen; i++ {
+		if ourRecord.Data[i] < incomingRecord.Data[i] {
+			return -1, "rdata", i // They win (greater byte, UNSIGNED)
+		}
+		if ourRecord.Data[i] > incomingRecord.Data[i] {
+			return +1, "rdata", i // We win (greater byte, UNSIGNED)
+		}
+	}
 
-	// bytes.Compare returns:
-	// - -1 if ourRecord.Data < incomingRecord.Data (we lose)
-	// -  0 if ourRecord.Data == incomingRecord.Data (tie)
-	// - +1 if ourRecord.Data > incomingRecord.Data (we win)
-	//
-	// bytes.Compare uses UNSIGNED byte comparison, which is exactly what RFC requires
+	if len(ourRecord.Data) < len(incomingRecord.Data) {
+		return -1, "length", -1 // They win (longer rdata)
+	}
+	if len(ourRecord.Data) > len(incomingRecord.Data) {
+		return +1, "length", -1 // We win (longer rdata)
+	}
+
+	return 0, "tie", -1 // Identical - no conflict (fault tolerance)
+}
+
+// canonicalClass strips the cache-flush bit (bit 15) from a record class,
+// per RFC 6762 §10.2: "In the Resource Record Class field of response
+// records ... the top bit is used as the 'cache-flush' bit ... The top bit
+// is not part of the class field, and a value of 1 MUST NOT be interpreted
+// as a Class value of 32769." RFC 6762 §8.2 requires this bit to be masked
+// out before the numeric class comparison used in tie-breaking, so a probe
+// that sets cache-flush on one side and not the other still compares equal.
+func canonicalClass(c protocol.DNSClass) uint16 {
+	return uint16(c) & 0x7FFF
+}
+
+// canonicalRecord pairs a resource record with the canonical form of its
+// name and RDATA, precomputed once so sorting and pairwise comparison
+// don't repeatedly reparse domain names embedded in RDATA.
+type canonicalRecord struct {
+	record message.ResourceRecord
+	name   string
+	data   []byte
+}
+
+// canonicalRDATA returns record's RDATA, with any domain name embedded in
+// it rewritten into RFC 4034 §6.2 canonical form: lowercased and re-encoded
+// with explicit length-prefixed labels rather than compression pointers.
+//
+// Without this, two hosts could probe with semantically identical SRV or
+// PTR records that differ only in the letter case of the embedded target
+// name (or in whether it happens to be compressed), and the raw-byte
+// comparison in lexicographicCompare would see them as different records
+// and declare a spurious winner instead of recognizing the tie.
+//
+// Record types without an embedded name (A, AAAA, TXT, ...) are returned
+// unchanged.
+func canonicalRDATA(record message.ResourceRecord) []byte {
+	switch record.Type {
+	case protocol.RecordTypePTR:
+		name, _, err := message.ParseName(record.Data, 0)
+		if err != nil {
+			return record.Data
+		}
+		encoded, err := message.EncodeName(message.CanonicalizeName(name))
+		if err != nil {
+			return record.Data
+		}
+		return encoded
+
+	case protocol.RecordTypeSRV:
+		// Priority, Weight, Port (2 bytes each) precede the target name.
+		if len(record.Data) < 6 {
+			return record.Data
+		}
+		target, _, err := message.ParseName(record.Data, 6)
+		if err != nil {
+			return record.Data
+		}
+		encodedTarget, err := message.EncodeName(message.CanonicalizeName(target))
+		if err != nil {
+			return record.Data
+		}
+		canonical := make([]byte, 6, 6+len(encodedTarget))
+		copy(canonical, record.Data[0:6])
+		return append(canonical, encodedTarget...)
+
+	default:
+		return record.Data
+	}
+}
+
+// canonicalizeRecordSet converts records into their canonical form and
+// sorts them per RFC 6762 §8.2.1: by name (RFC 4034 §6.2 canonical form),
+// then class (excluding the cache-flush bit), then type, then RDATA
+// compared bytewise as unsigned values.
+func canonicalizeRecordSet(records []message.ResourceRecord) []canonicalRecord {
+	sorted := make([]canonicalRecord, len(records))
+	for i, record := range records {
+		sorted[i] = canonicalRecord{
+			record: record,
+			name:   message.CanonicalizeName(record.Name),
+			data:   canonicalRDATA(record),
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareCanonicalRecords(sorted[i], sorted[j]) < 0
+	})
+	return sorted
+}
+
+// compareCanonicalRecords orders two canonicalized records per RFC 6762
+// §8.2.1's sort key: name, then class (excluding the cache-flush bit),
+// then type, then RDATA bytewise. It returns -1, 0, or +1, mirroring
+// lexicographicCompare's convention.
+func compareCanonicalRecords(a, b canonicalRecord) int {
+	cmp, _, _ := compareCanonicalRecordsTraced(a, b)
 	return cmp
 }
+
+// compareCanonicalRecordsTraced is compareCanonicalRecords, additionally
+// reporting which step of the sort key decided the outcome, so
+// DetectConflictSet can surface it on the ConflictEvent it emits.
+func compareCanonicalRecordsTraced(a, b canonicalRecord) (cmp int, decidedBy string, rdataIndex int) {
+	if a.name != b.name {
+		if a.name < b.name {
+			return -1, "name", -1
+		}
+		return 1, "name", -1
+	}
+
+	aClass := canonicalClass(a.record.Class)
+	bClass := canonicalClass(b.record.Class)
+	if aClass != bClass {
+		if aClass < bClass {
+			return -1, "class", -1
+		}
+		return 1, "class", -1
+	}
+
+	aType := uint16(a.record.Type)
+	bType := uint16(b.record.Type)
+	if aType != bType {
+		if aType < bType {
+			return -1, "type", -1
+		}
+		return 1, "type", -1
+	}
+
+	minLen := len(a.data)
+	if len(b.data) < minLen {
+		minLen = len(b.data)
+	}
+	for i := 0; i < minLen; i++ {
+		if a.data[i] < b.data[i] {
+			return -1, "rdata", i
+		}
+		if a.data[i] > b.data[i] {
+			return 1, "rdata", i
+		}
+	}
+
+	if len(a.data) != len(b.data) {
+		if len(a.data) < len(b.data) {
+			return -1, "length", -1
+		}
+		return 1, "length", -1
+	}
+
+	return 0, "tie", -1
+}
+
+// DetectConflictSet implements the RFC 6762 §8.2.1 simultaneous-probe
+// tiebreak for a set of records, rather than DetectConflict's single
+// record. This is needed when a probing host sees another host probing
+// for the same record(s) at the same instant: both sides must reach the
+// same winner/loser decision from their own copy of the two record sets,
+// without any further communication.
+//
+// Both sides sort their records into canonical order (see
+// canonicalizeRecordSet) and the two sorted lists are then compared
+// pairwise, in order, until a difference is found. That first differing
+// pair decides the winner, using the same class/type/RDATA rules as
+// DetectConflict. If one list runs out of records before a difference is
+// found, the longer list wins. If the lists are identical, there is no
+// conflict.
+//
+// Returns:
+//   - (true, nil):  Conflict detected - we lose the tiebreak and MUST defer
+//   - (false, nil): No conflict - we win the tiebreak, or the sets are identical
+//   - (_, error):   A record in either set failed validation
+//
+// RFC 6762 §8.2.1: "...the host's records and the tiebreaker records from
+// the message are each sorted into order, and the two sets of records are
+// compared pairwise, using the same comparison technique described above
+// [...] until a difference is found, and the host with the
+// lexicographically later data wins. If the two records are the same,
+// then [...] the host continues on to compare the next pair of records in
+// the two lists... If both lists run out of records at the same time
+// without any difference being found, then [...] there is, in fact, no
+// conflict."
+func (cd *ConflictDetector) DetectConflictSet(ours, incoming []message.ResourceRecord) (bool, error) {
+	for i := range ours {
+		if err := cd.validateRecord(ours[i]); err != nil {
+			return false, fmt.Errorf("invalid ours[%d]: %w", i, err)
+		}
+	}
+	for i := range incoming {
+		if err := cd.validateRecord(incoming[i]); err != nil {
+			return false, fmt.Errorf("invalid incoming[%d]: %w", i, err)
+		}
+	}
+
+	ourSorted := canonicalizeRecordSet(ours)
+	theirSorted := canonicalizeRecordSet(incoming)
+
+	minLen := len(ourSorted)
+	if len(theirSorted) < minLen {
+		minLen = len(theirSorted)
+	}
+
+	for i := 0; i < minLen; i++ {
+		cmp, decidedBy, rdataIndex := compareCanonicalRecordsTraced(ourSorted[i], theirSorted[i])
+		if cmp != 0 {
+			conflict := cmp < 0
+			cd.notify(ConflictEvent{
+				OurRecord:      ourSorted[i].record,
+				IncomingRecord: theirSorted[i].record,
+				DecidedBy:      decidedBy,
+				RDATAIndex:     rdataIndex,
+				WeWon:          !conflict,
+				Conflict:       conflict,
+			})
+			return conflict, nil // cmp < 0: their record at this position wins, we defer
+		}
+	}
+
+	// RFC 6762 §8.2.1: if one list is a prefix of the other, the longer
+	// list wins. Equal lengths here means every pair matched, so the sets
+	// are identical and there is no conflict.
+	if len(ourSorted) != len(theirSorted) {
+		conflict := len(ourSorted) < len(theirSorted)
+		evt := ConflictEvent{
+			DecidedBy:  "length",
+			RDATAIndex: -1,
+			WeWon:      !conflict,
+			Conflict:   conflict,
+		}
+		if minLen < len(ourSorted) {
+			evt.OurRecord = ourSorted[minLen].record
+		}
+		if minLen < len(theirSorted) {
+			evt.IncomingRecord = theirSorted[minLen].record
+		}
+		cd.notify(evt)
+		return conflict, nil
+	}
+
+	cd.notify(ConflictEvent{
+		DecidedBy:  "tie",
+		RDATAIndex: -1,
+		WeWon:      true,
+		Conflict:   false,
+	})
+	return false, nil
+}
@@ -1,7 +1,11 @@
 package responder
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
@@ -459,6 +463,421 @@ func TestConflictDetector_ErrorHandling(t *testing.T) {
 	}
 }
 
+// TestConflictDetector_DetectConflict_CacheFlushBitIgnored verifies that
+// the cache-flush bit (RFC 6762 §10.2, the top bit of the class field) is
+// masked out before the class comparison, so a record with the bit set on
+// one side and clear on the other is still treated as the same class and
+// the tiebreak falls through to type/RDATA instead of declaring a winner
+// on class alone.
+func TestConflictDetector_DetectConflict_CacheFlushBitIgnored(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	const cacheFlushBit = 0x8000
+
+	ourRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN | cacheFlushBit, // cache-flush set
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 50}, // lexicographically earlier
+	}
+
+	incomingRecord := message.ResourceRecord{
+		Name:  "myservice.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN, // cache-flush clear
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100}, // lexicographically later - they win
+	}
+
+	conflict, err := detector.DetectConflict(ourRecord, incomingRecord)
+	if err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+	if !conflict {
+		t.Errorf("DetectConflict() = false, want true (classes equal once cache-flush bit masked, RDATA decides and we lose)")
+	}
+}
+
+func TestCanonicalClass_MasksCacheFlushBit(t *testing.T) {
+	const cacheFlushBit = 0x8000
+
+	got := canonicalClass(protocol.ClassIN | cacheFlushBit)
+	if got != uint16(protocol.ClassIN) {
+		t.Errorf("canonicalClass(ClassIN|cacheFlushBit) = %#x, want %#x", got, uint16(protocol.ClassIN))
+	}
+}
+
+// TestConflictDetector_DetectConflict_NameCaseInsensitive verifies that
+// "MyService.local" and "myservice.LOCAL" are treated as the same name per
+// RFC 1035 §2.3.3 / RFC 6762 §8.1-§8.2, so the records are candidates for
+// conflict rather than being dismissed as unrelated.
+func TestConflictDetector_DetectConflict_NameCaseInsensitive(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	ourRecord := message.ResourceRecord{
+		Name:  "MyService.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 50},
+	}
+
+	incomingRecord := message.ResourceRecord{
+		Name:  "myservice.LOCAL",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, 100},
+	}
+
+	conflict, err := detector.DetectConflict(ourRecord, incomingRecord)
+	if err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+	if !conflict {
+		t.Errorf("DetectConflict() = false, want true (names differ only by case, same name, RDATA decides and we lose)")
+	}
+}
+
+// TestConflictDetector_SetObserver_AllThreeOutcomes verifies the observer
+// hook fires for DetectConflict's three branches: a lost tiebreak
+// (conflict), a won tiebreak (no conflict), and identical records
+// (fault-tolerant no conflict).
+func TestConflictDetector_SetObserver_AllThreeOutcomes(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	var events []ConflictEvent
+	detector.SetObserver(func(evt ConflictEvent) {
+		events = append(events, evt)
+	})
+
+	base := message.ResourceRecord{Name: "myservice.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120}
+
+	ourLoses := base
+	ourLoses.Data = []byte{192, 168, 1, 50}
+	theirsWins := base
+	theirsWins.Data = []byte{192, 168, 1, 100}
+	if _, err := detector.DetectConflict(ourLoses, theirsWins); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+
+	ourWins := base
+	ourWins.Data = []byte{192, 168, 1, 100}
+	theirsLoses := base
+	theirsLoses.Data = []byte{192, 168, 1, 50}
+	if _, err := detector.DetectConflict(ourWins, theirsLoses); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+
+	identical := base
+	identical.Data = []byte{192, 168, 1, 100}
+	if _, err := detector.DetectConflict(identical, identical); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("observer received %d events, want 3", len(events))
+	}
+
+	if !events[0].Conflict || events[0].WeWon {
+		t.Errorf("event[0] (lost tiebreak) = %+v, want Conflict=true WeWon=false", events[0])
+	}
+	if events[1].Conflict || !events[1].WeWon {
+		t.Errorf("event[1] (won tiebreak) = %+v, want Conflict=false WeWon=true", events[1])
+	}
+	if events[2].Conflict || !events[2].WeWon || events[2].DecidedBy != "tie" {
+		t.Errorf("event[2] (identical) = %+v, want Conflict=false WeWon=true DecidedBy=tie", events[2])
+	}
+}
+
+// TestConflictDetector_SetObserver_DetectConflictSet verifies the observer
+// hook also fires for DetectConflictSet.
+func TestConflictDetector_SetObserver_DetectConflictSet(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	var got *ConflictEvent
+	detector.SetObserver(func(evt ConflictEvent) {
+		got = &evt
+	})
+
+	ourRecord := message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 50}}
+	theirRecord := message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 100}}
+
+	conflict, err := detector.DetectConflictSet([]message.ResourceRecord{ourRecord}, []message.ResourceRecord{theirRecord})
+	if err != nil {
+		t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+	}
+	if !conflict {
+		t.Fatalf("DetectConflictSet() = false, want true")
+	}
+	if got == nil {
+		t.Fatal("observer was not invoked for DetectConflictSet")
+	}
+	if got.DecidedBy != "rdata" || !got.Conflict {
+		t.Errorf("event = %+v, want DecidedBy=rdata Conflict=true", *got)
+	}
+}
+
+// TestConflictDetector_SetObserver_Nil verifies that SetObserver(nil)
+// disables observation without panicking.
+func TestConflictDetector_SetObserver_Nil(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	called := false
+	detector.SetObserver(func(ConflictEvent) { called = true })
+	detector.SetObserver(nil)
+
+	ourRecord := message.ResourceRecord{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{1, 2, 3, 4}}
+	if _, err := detector.DetectConflict(ourRecord, ourRecord); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+	if called {
+		t.Errorf("observer invoked after SetObserver(nil)")
+	}
+}
+
+// TestConflictDetector_AddHook_FiresAlongsideObserver verifies AddHook's
+// OnConflictDetected fires for every DetectConflict/DetectConflictSet call,
+// alongside (not instead of) any observer set via SetObserver, and that
+// multiple hooks all receive the event.
+func TestConflictDetector_AddHook_FiresAlongsideObserver(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	var observerEvents []ConflictEvent
+	detector.SetObserver(func(evt ConflictEvent) {
+		observerEvents = append(observerEvents, evt)
+	})
+
+	hookA := &stubEventHook{}
+	hookB := &stubEventHook{}
+	detector.AddHook(hookA)
+	detector.AddHook(hookB)
+
+	ourRecord := message.ResourceRecord{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{1, 1, 1, 1}}
+	theirRecord := message.ResourceRecord{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{2, 2, 2, 2}}
+	if _, err := detector.DetectConflict(ourRecord, theirRecord); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+
+	if len(observerEvents) != 1 {
+		t.Fatalf("observer received %d events, want 1", len(observerEvents))
+	}
+	if len(hookA.conflicts) != 1 || len(hookB.conflicts) != 1 {
+		t.Fatalf("hookA got %d, hookB got %d conflict events, want 1 each", len(hookA.conflicts), len(hookB.conflicts))
+	}
+	if hookA.conflicts[0].DecidedBy != observerEvents[0].DecidedBy || hookA.conflicts[0].Conflict != observerEvents[0].Conflict {
+		t.Errorf("hook event = %+v, want it to match observer event %+v", hookA.conflicts[0], observerEvents[0])
+	}
+}
+
+// stubEventHook is a minimal EventHook recording every OnConflictDetected
+// call, for tests that only care about conflict notifications.
+type stubEventHook struct {
+	conflicts []ConflictEvent
+}
+
+func (h *stubEventHook) OnConflictDetected(evt ConflictEvent)                             { h.conflicts = append(h.conflicts, evt) }
+func (h *stubEventHook) OnProbeStart(name string)                                         {}
+func (h *stubEventHook) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {}
+func (h *stubEventHook) OnRename(oldName, newName string)                                 {}
+func (h *stubEventHook) OnProbeRateLimited(name string, delay time.Duration)              {}
+func (h *stubEventHook) OnAnnounce(serviceName string)                                    {}
+func (h *stubEventHook) OnGoodbye(serviceName string)                                     {}
+func (h *stubEventHook) OnKnownAnswerSuppressed(record message.ResourceRecord)            {}
+
+var _ EventHook = (*stubEventHook)(nil)
+
+// TestPrometheusObserver_PartitionsByTypeAndOutcome verifies
+// PrometheusObserver counts events by record type and outcome.
+func TestPrometheusObserver_PartitionsByTypeAndOutcome(t *testing.T) {
+	detector := &ConflictDetector{}
+	prom := NewPrometheusObserver()
+	detector.SetObserver(prom.Observe)
+
+	ourLoses := message.ResourceRecord{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{1, 1, 1, 1}}
+	theirsWins := message.ResourceRecord{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{2, 2, 2, 2}}
+	if _, err := detector.DetectConflict(ourLoses, theirsWins); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+	if _, err := detector.DetectConflict(ourLoses, theirsWins); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+
+	snapshot := prom.Snapshot()
+	if got := snapshot["A conflict"]; got != 2 {
+		t.Errorf("snapshot[\"A conflict\"] = %d, want 2 (snapshot: %+v)", got, snapshot)
+	}
+}
+
+// TestLoggingObserver_WritesOneJSONLinePerEvent verifies LoggingObserver
+// emits exactly one JSON line per ConflictEvent.
+func TestLoggingObserver_WritesOneJSONLinePerEvent(t *testing.T) {
+	detector := &ConflictDetector{}
+	var buf bytes.Buffer
+	logger := NewLoggingObserver(&buf)
+	detector.SetObserver(logger.Observe)
+
+	rr := message.ResourceRecord{Name: "a.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{1, 2, 3, 4}}
+	if _, err := detector.DetectConflict(rr, rr); err != nil {
+		t.Fatalf("DetectConflict() unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (output: %q)", len(lines), buf.String())
+	}
+
+	// Decode into a generic map rather than ConflictEvent: ResourceRecord
+	// implements encoding.TextUnmarshaler (for presentation-format
+	// round-tripping), which makes encoding/json reject its own
+	// object-shaped output on the way back in.
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if decoded["DecidedBy"] != "tie" {
+		t.Errorf("decoded[\"DecidedBy\"] = %v, want \"tie\"", decoded["DecidedBy"])
+	}
+}
+
+// TestConflictDetector_DetectConflictSet_EmptySets verifies that two empty
+// record sets are identical (no conflict) per RFC 6762 §8.2.1's
+// fault-tolerance rule.
+func TestConflictDetector_DetectConflictSet_EmptySets(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	conflict, err := detector.DetectConflictSet(nil, nil)
+	if err != nil {
+		t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+	}
+	if conflict {
+		t.Errorf("DetectConflictSet() = true, want false (two empty sets are identical)")
+	}
+}
+
+// TestConflictDetector_DetectConflictSet_PrefixLoses verifies that when one
+// side's sorted record list is a strict prefix of the other's, the shorter
+// (prefix) list loses per RFC 6762 §8.2.1.
+func TestConflictDetector_DetectConflictSet_PrefixLoses(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	a := message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 1}}
+	srv := message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x50, 0x00}}
+
+	ourRecords := []message.ResourceRecord{a}
+	theirRecords := []message.ResourceRecord{a, srv}
+
+	conflict, err := detector.DetectConflictSet(ourRecords, theirRecords)
+	if err != nil {
+		t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+	}
+	if !conflict {
+		t.Errorf("DetectConflictSet() = false, want true (our record set is a prefix of theirs, we lose)")
+	}
+
+	// Symmetric case: our set is the longer one, we win.
+	conflict, err = detector.DetectConflictSet(theirRecords, ourRecords)
+	if err != nil {
+		t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+	}
+	if conflict {
+		t.Errorf("DetectConflictSet() = true, want false (their record set is a prefix of ours, we win)")
+	}
+}
+
+// TestConflictDetector_DetectConflictSet_IdenticalSets verifies that two
+// identical record sets, supplied in different orders, produce no conflict
+// once both are sorted into canonical order.
+func TestConflictDetector_DetectConflictSet_IdenticalSets(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	a := message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 1}}
+	txt := message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeTXT, Class: protocol.ClassIN, TTL: 120, Data: []byte{0x03, 'f', 'o', 'o'}}
+
+	conflict, err := detector.DetectConflictSet([]message.ResourceRecord{a, txt}, []message.ResourceRecord{txt, a})
+	if err != nil {
+		t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+	}
+	if conflict {
+		t.Errorf("DetectConflictSet() = true, want false (identical sets regardless of input order)")
+	}
+}
+
+// TestConflictDetector_DetectConflictSet_CanonicalNameCasing verifies that
+// PTR/SRV RDATA embedding a domain name is compared under RFC 4034 §6.2
+// canonical form (lowercased, length-prefixed labels), so two records whose
+// target names differ only by letter case are treated as identical rather
+// than producing a spurious winner from a raw byte comparison.
+func TestConflictDetector_DetectConflictSet_CanonicalNameCasing(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	lowerPTR, err := message.EncodeName("printer.local")
+	if err != nil {
+		t.Fatalf("EncodeName() unexpected error: %v", err)
+	}
+	upperPTR, err := message.EncodeName("PRINTER.LOCAL")
+	if err != nil {
+		t.Fatalf("EncodeName() unexpected error: %v", err)
+	}
+
+	ourRecord := message.ResourceRecord{Name: "_ipp._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN, TTL: 120, Data: lowerPTR}
+	incomingRecord := message.ResourceRecord{Name: "_ipp._tcp.local", Type: protocol.RecordTypePTR, Class: protocol.ClassIN, TTL: 120, Data: upperPTR}
+
+	conflict, err := detector.DetectConflictSet([]message.ResourceRecord{ourRecord}, []message.ResourceRecord{incomingRecord})
+	if err != nil {
+		t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+	}
+	if conflict {
+		t.Errorf("DetectConflictSet() = true, want false (PTR targets differ only by letter case, canonical form ties them)")
+	}
+
+	// Raw bytes DO differ (uppercase ASCII sorts below lowercase ASCII),
+	// so failing to canonicalize would have produced a spurious conflict.
+	if bytes.Equal(lowerPTR, upperPTR) {
+		t.Fatalf("test invariant broken: raw encodings are expected to differ by case")
+	}
+}
+
+// TestConflictDetector_DetectConflictSet_SortOrderDeterminesWinner is RFC
+// 6762 §8.2.1's own example: a host probing for both an A and an AAAA
+// record for the same name. Type (A=1, AAAA=28) sorts before RDATA, so
+// canonicalizeRecordSet always pairs A with A and AAAA with AAAA regardless
+// of which order either side supplied them in - a peer that skipped
+// canonical sorting and compared the sets positionally, as received off
+// the wire, could pair our A against their AAAA and reach a different
+// (wrong) winner than one who sorted first.
+func TestConflictDetector_DetectConflictSet_SortOrderDeterminesWinner(t *testing.T) {
+	detector := &ConflictDetector{}
+
+	ourA := message.ResourceRecord{Name: "host.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 100}}
+	ourAAAA := message.ResourceRecord{Name: "host.local", Type: protocol.RecordTypeAAAA, Class: protocol.ClassIN, TTL: 120, Data: []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}
+
+	theirA := message.ResourceRecord{Name: "host.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 200}}
+	theirAAAA := message.ResourceRecord{Name: "host.local", Type: protocol.RecordTypeAAAA, Class: protocol.ClassIN, TTL: 120, Data: []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}}
+
+	// Our A loses to their A (100 < 200) but our AAAA beats their AAAA
+	// (...01 < ...02 is also a loss) - so whichever type sorts first
+	// decides the whole comparison. Type orders A before AAAA, so the A
+	// pair (where we lose) must decide it, regardless of the order either
+	// side happened to list its records in.
+	permutations := [][]message.ResourceRecord{
+		{ourA, ourAAAA},
+		{ourAAAA, ourA},
+	}
+	for _, ours := range permutations {
+		for _, theirs := range [][]message.ResourceRecord{{theirA, theirAAAA}, {theirAAAA, theirA}} {
+			conflict, err := detector.DetectConflictSet(ours, theirs)
+			if err != nil {
+				t.Fatalf("DetectConflictSet() unexpected error: %v", err)
+			}
+			if !conflict {
+				t.Errorf("DetectConflictSet(%v, %v) = false, want true (our A record is canonically first and loses)", ours, theirs)
+			}
+		}
+	}
+}
+
 // BenchmarkConflictDetector_DetectConflict benchmarks the full conflict detection path.
 //
 // T063: Benchmark ConflictDetector performance
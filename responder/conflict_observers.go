@@ -0,0 +1,170 @@
+package responder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// conflictOutcome classifies a ConflictEvent for counter partitioning:
+// "conflict" (we lost, must defer), "won" (we won outright), or "tie"
+// (identical records/sets - fault tolerance).
+func conflictOutcome(evt ConflictEvent) string {
+	switch {
+	case evt.Conflict:
+		return "conflict"
+	case evt.DecidedBy == "tie":
+		return "tie"
+	default:
+		return "won"
+	}
+}
+
+// prometheusObserverKey partitions PrometheusObserver's counters by record
+// type and outcome.
+type prometheusObserverKey struct {
+	recordType protocol.RecordType
+	outcome    string
+}
+
+// PrometheusObserver aggregates ConflictEvents into Prometheus-style
+// counters partitioned by record type and outcome, mirroring
+// security.PrometheusSink rather than depending on the Prometheus client
+// library directly; a responder wires Snapshot() into its own /metrics
+// endpoint.
+//
+// PrometheusObserver also implements EventHook, additionally tallying
+// probe/rename/announce/goodbye activity (see EventCounts), so it can be
+// registered via ConflictDetector.AddHook and Prober.AddHook as a single
+// built-in Prometheus adapter covering every hook event, not just
+// conflicts.
+type PrometheusObserver struct {
+	mu          sync.Mutex
+	counts      map[prometheusObserverKey]uint64
+	eventCounts map[string]uint64
+}
+
+// NewPrometheusObserver creates an empty PrometheusObserver.
+func NewPrometheusObserver() *PrometheusObserver {
+	return &PrometheusObserver{
+		counts:      make(map[prometheusObserverKey]uint64),
+		eventCounts: make(map[string]uint64),
+	}
+}
+
+// Observe implements the ConflictObserver signature; pass p.Observe to
+// ConflictDetector.SetObserver.
+func (p *PrometheusObserver) Observe(evt ConflictEvent) {
+	key := prometheusObserverKey{recordType: evt.OurRecord.Type, outcome: conflictOutcome(evt)}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counts[key]++
+}
+
+// Snapshot returns a point-in-time copy of the observer's counters, keyed
+// by "<type> <outcome>" (e.g. "SRV conflict").
+func (p *PrometheusObserver) Snapshot() map[string]uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := make(map[string]uint64, len(p.counts))
+	for key, count := range p.counts {
+		snapshot[fmt.Sprintf("%s %s", key.recordType, key.outcome)] = count
+	}
+	return snapshot
+}
+
+// OnConflictDetected implements EventHook, sharing Observe's counters.
+func (p *PrometheusObserver) OnConflictDetected(evt ConflictEvent) {
+	p.Observe(evt)
+}
+
+// OnProbeStart implements EventHook.
+func (p *PrometheusObserver) OnProbeStart(name string) {
+	p.incrementEventCount("probe_start")
+}
+
+// OnProbeConflict implements EventHook.
+func (p *PrometheusObserver) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+	p.incrementEventCount("probe_conflict")
+}
+
+// OnRename implements EventHook.
+func (p *PrometheusObserver) OnRename(oldName, newName string) {
+	p.incrementEventCount("rename")
+}
+
+// OnProbeRateLimited implements EventHook.
+func (p *PrometheusObserver) OnProbeRateLimited(name string, delay time.Duration) {
+	p.incrementEventCount("probe_rate_limited")
+}
+
+// OnAnnounce implements EventHook.
+func (p *PrometheusObserver) OnAnnounce(serviceName string) {
+	p.incrementEventCount("announce")
+}
+
+// OnGoodbye implements EventHook.
+func (p *PrometheusObserver) OnGoodbye(serviceName string) {
+	p.incrementEventCount("goodbye")
+}
+
+// OnKnownAnswerSuppressed implements EventHook.
+func (p *PrometheusObserver) OnKnownAnswerSuppressed(record message.ResourceRecord) {
+	p.incrementEventCount("known_answer_suppressed")
+}
+
+func (p *PrometheusObserver) incrementEventCount(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.eventCounts[name]++
+}
+
+// EventCounts returns a point-in-time copy of the probe/rename/announce/
+// goodbye counters tallied via the EventHook methods (conflict counts are
+// in Snapshot instead, partitioned by record type and outcome).
+func (p *PrometheusObserver) EventCounts() map[string]uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := make(map[string]uint64, len(p.eventCounts))
+	for name, count := range p.eventCounts {
+		snapshot[name] = count
+	}
+	return snapshot
+}
+
+var _ EventHook = (*PrometheusObserver)(nil)
+
+// LoggingObserver writes one structured (JSON) log line per ConflictEvent
+// to an io.Writer (e.g. os.Stderr or a log file), for operators who want to
+// grep conflict history without standing up Prometheus.
+type LoggingObserver struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLoggingObserver creates a LoggingObserver writing JSON lines to w.
+func NewLoggingObserver(w io.Writer) *LoggingObserver {
+	return &LoggingObserver{w: w}
+}
+
+// Observe implements the ConflictObserver signature; pass l.Observe to
+// ConflictDetector.SetObserver.
+func (l *LoggingObserver) Observe(evt ConflictEvent) {
+	line, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(line) // nosemgrep: beacon-error-swallowing
+}
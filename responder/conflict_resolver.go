@@ -0,0 +1,235 @@
+package responder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// RFC 6762 §8.1 conflict rate limit: a name that is renamed and re-probed
+// more than maxConflictsPerWindow times within conflictWindow is assumed to
+// be fighting another host in a tight loop rather than converging, and
+// backs off for conflictBackoff before probing is allowed to resume.
+const (
+	maxConflictsPerWindow = 15
+	conflictWindow        = 10 * time.Second
+	conflictBackoff       = 5 * time.Second
+)
+
+// NameMangler recognizes and produces one style of RFC 6762 §9 conflict
+// rename suffix for a particular kind of name (DNS-SD instance name vs.
+// A/AAAA host name use different conventions).
+type NameMangler interface {
+	// Split separates name into its unsuffixed base and the suffix
+	// currently applied, so Join(Split(name)) reproduces name. A name with
+	// no recognizable suffix has a suffix of 1 (i.e. unsuffixed).
+	Split(name string) (base string, suffix int)
+
+	// Join renders base with suffix applied. A suffix of 1 returns base
+	// unchanged.
+	Join(base string, suffix int) string
+}
+
+// instanceSuffixPattern matches the "Name (N)" suffix DNS-SD instance names
+// use per RFC 6762 §9 ("if the user configures any device with the name
+// 'Stuart's Printer', and a second device with the same name also exists,
+// then when the second device sees the first device's probe, it receives a
+// negative response and has to choose a new name, e.g. 'Stuart's Printer (2)'").
+var instanceSuffixPattern = regexp.MustCompile(`^(.*)\s+\((\d+)\)$`)
+
+// InstanceNameMangler renames DNS-SD service instance names by appending or
+// incrementing a parenthesized numeric suffix, e.g. "MyService" ->
+// "MyService (2)" -> "MyService (3)".
+type InstanceNameMangler struct{}
+
+// Split implements NameMangler.
+func (InstanceNameMangler) Split(name string) (base string, suffix int) {
+	matches := instanceSuffixPattern.FindStringSubmatch(name)
+	if matches == nil {
+		return name, 1
+	}
+	n, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return name, 1
+	}
+	return matches[1], n
+}
+
+// Join implements NameMangler.
+func (InstanceNameMangler) Join(base string, suffix int) string {
+	if suffix <= 1 {
+		return base
+	}
+	return fmt.Sprintf("%s (%d)", base, suffix)
+}
+
+// hostLabelSuffixPattern matches the "label-N" suffix host names use.
+var hostLabelSuffixPattern = regexp.MustCompile(`^(.*)-(\d+)$`)
+
+// HostNameMangler renames A/AAAA host names by appending or incrementing a
+// hyphenated numeric suffix on the leading label, e.g. "myhost.local" ->
+// "myhost-2.local" -> "myhost-3.local".
+type HostNameMangler struct{}
+
+// Split implements NameMangler.
+func (HostNameMangler) Split(name string) (base string, suffix int) {
+	label, rest, hasRest := cutFirstLabel(name)
+
+	matches := hostLabelSuffixPattern.FindStringSubmatch(label)
+	if matches == nil {
+		return name, 1
+	}
+	n, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return name, 1
+	}
+
+	if hasRest {
+		return matches[1] + "." + rest, n
+	}
+	return matches[1], n
+}
+
+// Join implements NameMangler.
+func (HostNameMangler) Join(base string, suffix int) string {
+	if suffix <= 1 {
+		return base
+	}
+
+	label, rest, hasRest := cutFirstLabel(base)
+	if hasRest {
+		return fmt.Sprintf("%s-%d.%s", label, suffix, rest)
+	}
+	return fmt.Sprintf("%s-%d", label, suffix)
+}
+
+// cutFirstLabel splits name into its leading label and the remaining
+// labels (without the separating dot).
+func cutFirstLabel(name string) (label, rest string, hasRest bool) {
+	idx := strings.IndexByte(name, '.')
+	if idx < 0 {
+		return name, "", false
+	}
+	return name[:idx], name[idx+1:], true
+}
+
+// ConflictRateLimitError is returned by ConflictResolver.Resolve when a
+// name has been renamed too many times in too short a window, per RFC 6762
+// §8.1's "excessive probing" guard.
+type ConflictRateLimitError struct {
+	// Name is the record name that hit the limit.
+	Name string
+
+	// RetryAfter is how long the caller should wait before probing again.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface for ConflictRateLimitError.
+func (e *ConflictRateLimitError) Error() string {
+	return fmt.Sprintf("conflict rate limit exceeded for %q: retry after %s", e.Name, e.RetryAfter)
+}
+
+// conflictHistory tracks how many times a base name has been renamed
+// within the current RFC 6762 §8.1 rate-limit window.
+type conflictHistory struct {
+	windowStart   time.Time
+	count         int
+	cooldownUntil time.Time
+}
+
+// ConflictResolver turns conflict signals from ConflictDetector into a new
+// candidate name for records the local host owns, per RFC 6762 §9: "the
+// Multicast DNS responder MUST rename the record to a name that does not
+// conflict ... typically by appending the digit '2' ... and incrementing
+// for subsequent conflicts."
+//
+// Safe for concurrent use by multiple goroutines.
+type ConflictResolver struct {
+	mangler NameMangler
+
+	mu            sync.Mutex
+	highestSuffix map[string]int // canonical base name -> highest suffix produced so far
+	history       map[string]*conflictHistory
+}
+
+// NewConflictResolver creates a ConflictResolver that renames names using
+// mangler (InstanceNameMangler for DNS-SD instance names, HostNameMangler
+// for A/AAAA host names).
+func NewConflictResolver(mangler NameMangler) *ConflictResolver {
+	return &ConflictResolver{
+		mangler:       mangler,
+		highestSuffix: make(map[string]int),
+		history:       make(map[string]*conflictHistory),
+	}
+}
+
+// Resolve returns rr renamed to the next candidate name, ready to re-probe.
+//
+// The highest suffix tried for rr's base name is remembered (both across
+// calls and from any suffix already present on rr.Name), so a renamed name
+// never regresses to an earlier suffix even if conflicts are reported out
+// of order.
+//
+// Returns a *ConflictRateLimitError if the base name has been renamed more
+// than 15 times within the last 10 seconds (RFC 6762 §8.1); the caller
+// should wait RetryAfter before probing again.
+func (cr *ConflictResolver) Resolve(rr message.ResourceRecord) (message.ResourceRecord, error) {
+	base, observedSuffix := cr.mangler.Split(rr.Name)
+	key := message.CanonicalizeName(base)
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if err := cr.checkRateLimit(key, rr.Name); err != nil {
+		return message.ResourceRecord{}, err
+	}
+
+	next := cr.highestSuffix[key]
+	if observedSuffix > next {
+		next = observedSuffix
+	}
+	next++
+	if next < 2 {
+		next = 2
+	}
+	cr.highestSuffix[key] = next
+
+	renamed := rr
+	renamed.Name = cr.mangler.Join(base, next)
+	return renamed, nil
+}
+
+// checkRateLimit enforces RFC 6762 §8.1's limit of 15 renames per 10
+// seconds per base name, applying a 5-second cooldown once exceeded.
+// Caller must hold cr.mu.
+func (cr *ConflictResolver) checkRateLimit(key, name string) error {
+	now := time.Now()
+
+	hist := cr.history[key]
+	if hist == nil {
+		hist = &conflictHistory{windowStart: now}
+		cr.history[key] = hist
+	}
+
+	if now.Before(hist.cooldownUntil) {
+		return &ConflictRateLimitError{Name: name, RetryAfter: hist.cooldownUntil.Sub(now)}
+	}
+
+	if now.Sub(hist.windowStart) >= conflictWindow {
+		hist.windowStart = now
+		hist.count = 0
+	}
+	hist.count++
+
+	if hist.count > maxConflictsPerWindow {
+		hist.cooldownUntil = now.Add(conflictBackoff)
+		return &ConflictRateLimitError{Name: name, RetryAfter: conflictBackoff}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package responder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+func TestInstanceNameMangler_SplitJoin(t *testing.T) {
+	m := InstanceNameMangler{}
+
+	tests := []struct {
+		name       string
+		wantBase   string
+		wantSuffix int
+	}{
+		{"MyService", "MyService", 1},
+		{"MyService (2)", "MyService", 2},
+		{"MyService (9)", "MyService", 9},
+		{"My Printer (10)", "My Printer", 10},
+	}
+
+	for _, tt := range tests {
+		base, suffix := m.Split(tt.name)
+		if base != tt.wantBase || suffix != tt.wantSuffix {
+			t.Errorf("Split(%q) = (%q, %d), want (%q, %d)", tt.name, base, suffix, tt.wantBase, tt.wantSuffix)
+		}
+		if got := m.Join(base, suffix); got != tt.name {
+			t.Errorf("Join(%q, %d) = %q, want %q", base, suffix, got, tt.name)
+		}
+	}
+
+	if got := m.Join("MyService", 3); got != "MyService (3)" {
+		t.Errorf("Join(\"MyService\", 3) = %q, want \"MyService (3)\"", got)
+	}
+}
+
+func TestHostNameMangler_SplitJoin(t *testing.T) {
+	m := HostNameMangler{}
+
+	tests := []struct {
+		name       string
+		wantBase   string
+		wantSuffix int
+	}{
+		{"myhost.local", "myhost.local", 1},
+		{"myhost-2.local", "myhost.local", 2},
+		{"myhost-9.local", "myhost.local", 9},
+		{"myhost", "myhost", 1},
+		{"myhost-2", "myhost", 2},
+	}
+
+	for _, tt := range tests {
+		base, suffix := m.Split(tt.name)
+		if base != tt.wantBase || suffix != tt.wantSuffix {
+			t.Errorf("Split(%q) = (%q, %d), want (%q, %d)", tt.name, base, suffix, tt.wantBase, tt.wantSuffix)
+		}
+		if got := m.Join(base, suffix); got != tt.name {
+			t.Errorf("Join(%q, %d) = %q, want %q", base, suffix, got, tt.name)
+		}
+	}
+
+	if got := m.Join("myhost.local", 3); got != "myhost-3.local" {
+		t.Errorf(`Join("myhost.local", 3) = %q, want "myhost-3.local"`, got)
+	}
+}
+
+func TestConflictResolver_Resolve_InstanceName_IncrementsSuffix(t *testing.T) {
+	resolver := NewConflictResolver(InstanceNameMangler{})
+
+	rr := message.ResourceRecord{Name: "MyService", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0, 0, 0, 0, 0, 0}}
+
+	renamed, err := resolver.Resolve(rr)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if renamed.Name != "MyService (2)" {
+		t.Errorf("Resolve() name = %q, want \"MyService (2)\"", renamed.Name)
+	}
+
+	renamed, err = resolver.Resolve(renamed)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if renamed.Name != "MyService (3)" {
+		t.Errorf("Resolve() name = %q, want \"MyService (3)\"", renamed.Name)
+	}
+}
+
+func TestConflictResolver_Resolve_HostName_IncrementsSuffix(t *testing.T) {
+	resolver := NewConflictResolver(HostNameMangler{})
+
+	rr := message.ResourceRecord{Name: "myhost.local", Type: protocol.RecordTypeA, Class: protocol.ClassIN, TTL: 120, Data: []byte{192, 168, 1, 1}}
+
+	renamed, err := resolver.Resolve(rr)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if renamed.Name != "myhost-2.local" {
+		t.Errorf("Resolve() name = %q, want \"myhost-2.local\"", renamed.Name)
+	}
+
+	renamed, err = resolver.Resolve(renamed)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if renamed.Name != "myhost-3.local" {
+		t.Errorf("Resolve() name = %q, want \"myhost-3.local\"", renamed.Name)
+	}
+}
+
+// TestConflictResolver_Resolve_DoesNotRegress verifies that a freshly
+// created resolver seeing an already-suffixed name doesn't propose a
+// smaller suffix than the one already in use.
+func TestConflictResolver_Resolve_DoesNotRegress(t *testing.T) {
+	resolver := NewConflictResolver(InstanceNameMangler{})
+
+	rr := message.ResourceRecord{Name: "MyService (7)", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0, 0, 0, 0, 0, 0}}
+
+	renamed, err := resolver.Resolve(rr)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if renamed.Name != "MyService (8)" {
+		t.Errorf("Resolve() name = %q, want \"MyService (8)\" (must not regress below the observed suffix)", renamed.Name)
+	}
+}
+
+// TestConflictResolver_Resolve_RateLimit verifies the RFC 6762 §8.1 limit
+// of 15 renames per 10 seconds per base name: the 16th call within the
+// window must fail with a ConflictRateLimitError instructing the caller to
+// back off for 5 seconds, instead of proposing another name.
+func TestConflictResolver_Resolve_RateLimit(t *testing.T) {
+	resolver := NewConflictResolver(InstanceNameMangler{})
+
+	rr := message.ResourceRecord{Name: "MyService", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0, 0, 0, 0, 0, 0}}
+
+	for i := 0; i < maxConflictsPerWindow; i++ {
+		var err error
+		rr, err = resolver.Resolve(rr)
+		if err != nil {
+			t.Fatalf("Resolve() call %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	_, err := resolver.Resolve(rr)
+	if err == nil {
+		t.Fatalf("Resolve() call %d: expected ConflictRateLimitError, got nil", maxConflictsPerWindow+1)
+	}
+
+	var rateLimitErr *ConflictRateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Resolve() error type = %T, want *ConflictRateLimitError", err)
+	}
+	if rateLimitErr.RetryAfter != conflictBackoff {
+		t.Errorf("RetryAfter = %v, want %v", rateLimitErr.RetryAfter, conflictBackoff)
+	}
+}
+
+// TestConflictResolver_Resolve_RateLimit_IsolatedPerName verifies that the
+// per-name rate limit doesn't affect unrelated base names.
+func TestConflictResolver_Resolve_RateLimit_IsolatedPerName(t *testing.T) {
+	resolver := NewConflictResolver(InstanceNameMangler{})
+
+	busy := message.ResourceRecord{Name: "Busy", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0, 0, 0, 0, 0, 0}}
+	for i := 0; i < maxConflictsPerWindow+1; i++ {
+		busy, _ = resolver.Resolve(busy)
+	}
+
+	quiet := message.ResourceRecord{Name: "Quiet", Type: protocol.RecordTypeSRV, Class: protocol.ClassIN, TTL: 120, Data: []byte{0, 0, 0, 0, 0, 0}}
+	renamed, err := resolver.Resolve(quiet)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error for unrelated name: %v", err)
+	}
+	if renamed.Name != "Quiet (2)" {
+		t.Errorf("Resolve() name = %q, want \"Quiet (2)\"", renamed.Name)
+	}
+}
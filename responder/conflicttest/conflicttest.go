@@ -0,0 +1,187 @@
+// Package conflicttest provides a deterministic harness for exercising
+// responder.ConflictDetector against scripted probe peers, mirroring the
+// mock-plugin pattern other discovery libraries use for their own
+// race-condition suites. It drives the tie-breaking logic directly (no
+// transport, no real sleeps, no responder.Responder) so RFC 6762 §8.2
+// scenarios - simultaneous probes, staggered probes, three-way races, a
+// peer whose RDATA keeps changing - are regression tests instead of
+// something only ever exercised by luck on a real LAN.
+package conflicttest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// Probe is one scripted probe a MockPeer sends: the record set it probes
+// with, arriving After relative to the start of a Run.
+type Probe struct {
+	Records []message.ResourceRecord
+	After   time.Duration
+}
+
+// MockPeer scripts the sequence of probes one simulated peer sends during a
+// Run. A peer that should "go silent" after some point simply has no more
+// Probes scheduled past it - Run never invents a probe a test didn't
+// script.
+type MockPeer struct {
+	// Name identifies this peer in a Result's Rounds, e.g. for asserting
+	// which peer's probe a conflict came from.
+	Name string
+
+	Probes []Probe
+}
+
+// NewMockPeer creates a named MockPeer with no scripted probes yet.
+func NewMockPeer(name string) *MockPeer {
+	return &MockPeer{Name: name}
+}
+
+// Probe appends one scripted probe with the given RDATA, arriving after
+// delay relative to the start of a Run, and returns p for chaining.
+func (p *MockPeer) Probe(after time.Duration, records ...message.ResourceRecord) *MockPeer {
+	p.Probes = append(p.Probes, Probe{Records: records, After: after})
+	return p
+}
+
+// Round is one scripted probe's outcome against our record set.
+type Round struct {
+	// Peer is the MockPeer.Name that sent this probe.
+	Peer string
+
+	// Conflict is DetectConflictSet's result: true means our side loses
+	// the RFC 6762 §8.2.1 tie-break and must rename.
+	Conflict bool
+}
+
+// Result is every round Run fed through the detector, in arrival order.
+type Result struct {
+	Rounds []Round
+}
+
+// Renamed reports whether any round in r lost its tie-break, i.e. whether
+// the service under test would have renamed (RFC 6762 §9) by the end of
+// the scripted scenario.
+func (r Result) Renamed() bool {
+	for _, round := range r.Rounds {
+		if round.Conflict {
+			return true
+		}
+	}
+	return false
+}
+
+// Run feeds every peer's scripted Probes into detector against ourRecords,
+// via DetectConflictSet, in arrival order (Probe.After, ties broken by the
+// order peers were passed in). It returns every round's outcome, or the
+// first error DetectConflictSet reports (e.g. a malformed scripted
+// record).
+//
+// Register a responder.EventHook (a Recorder, typically) via
+// detector.AddHook before calling Run to additionally capture the
+// responder.ConflictEvent each round produces.
+func Run(detector *responder.ConflictDetector, ourRecords []message.ResourceRecord, peers ...*MockPeer) (Result, error) {
+	type scheduled struct {
+		peer  string
+		probe Probe
+	}
+
+	var sched []scheduled
+	for _, p := range peers {
+		for _, probe := range p.Probes {
+			sched = append(sched, scheduled{peer: p.Name, probe: probe})
+		}
+	}
+	sort.SliceStable(sched, func(i, j int) bool {
+		return sched[i].probe.After < sched[j].probe.After
+	})
+
+	var result Result
+	for _, s := range sched {
+		conflict, err := detector.DetectConflictSet(ourRecords, s.probe.Records)
+		if err != nil {
+			return result, err
+		}
+		result.Rounds = append(result.Rounds, Round{Peer: s.peer, Conflict: conflict})
+	}
+	return result, nil
+}
+
+// SimultaneousProbe returns a MockPeer probing with rdata at the same
+// moment we do, RFC 6762 §8.2's baseline scenario.
+func SimultaneousProbe(rdata ...message.ResourceRecord) *MockPeer {
+	return NewMockPeer("simultaneous").Probe(0, rdata...)
+}
+
+// StaggeredProbe returns a MockPeer whose probe arrives delay after ours -
+// two devices starting up moments apart rather than in lockstep.
+func StaggeredProbe(delay time.Duration, rdata ...message.ResourceRecord) *MockPeer {
+	return NewMockPeer("staggered").Probe(delay, rdata...)
+}
+
+// ThreeWayRace returns three MockPeers ("race-a", "race-b", "race-c")
+// probing at the same moment with distinct RDATA, for exercising
+// DetectConflictSet against more than one simultaneous competitor.
+func ThreeWayRace(a, b, c []message.ResourceRecord) (peerA, peerB, peerC *MockPeer) {
+	return NewMockPeer("race-a").Probe(0, a...),
+		NewMockPeer("race-b").Probe(0, b...),
+		NewMockPeer("race-c").Probe(0, c...)
+}
+
+// FlappingPeer returns a MockPeer that probes, goes silent, then probes
+// again with different RDATA later - a peer whose address keeps changing
+// (DHCP churn, a flapping link) instead of settling after one conflict.
+func FlappingPeer(firstAfter time.Duration, first []message.ResourceRecord, secondAfter time.Duration, second []message.ResourceRecord) *MockPeer {
+	return NewMockPeer("flapping").Probe(firstAfter, first...).Probe(secondAfter, second...)
+}
+
+// Recorder implements responder.EventHook, capturing every ConflictEvent
+// delivered to it (via ConflictDetector.AddHook) for assertion. Other
+// EventHook methods are no-ops: Recorder only cares about conflict
+// outcomes.
+//
+// A zero-value Recorder is ready to use.
+type Recorder struct {
+	events []responder.ConflictEvent
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Events returns every ConflictEvent recorded so far, in delivery order.
+func (r *Recorder) Events() []responder.ConflictEvent {
+	return append([]responder.ConflictEvent(nil), r.events...)
+}
+
+// OnConflictDetected implements responder.EventHook.
+func (r *Recorder) OnConflictDetected(evt responder.ConflictEvent) {
+	r.events = append(r.events, evt)
+}
+
+// OnProbeStart implements responder.EventHook. Not recorded.
+func (r *Recorder) OnProbeStart(name string) {}
+
+// OnProbeConflict implements responder.EventHook. Not recorded.
+func (r *Recorder) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {}
+
+// OnRename implements responder.EventHook. Not recorded.
+func (r *Recorder) OnRename(oldName, newName string) {}
+
+// OnProbeRateLimited implements responder.EventHook. Not recorded.
+func (r *Recorder) OnProbeRateLimited(name string, delay time.Duration) {}
+
+// OnAnnounce implements responder.EventHook. Not recorded.
+func (r *Recorder) OnAnnounce(serviceName string) {}
+
+// OnGoodbye implements responder.EventHook. Not recorded.
+func (r *Recorder) OnGoodbye(serviceName string) {}
+
+// OnKnownAnswerSuppressed implements responder.EventHook. Not recorded.
+func (r *Recorder) OnKnownAnswerSuppressed(record message.ResourceRecord) {}
+
+var _ responder.EventHook = (*Recorder)(nil)
@@ -0,0 +1,146 @@
+package conflicttest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/responder"
+)
+
+func rr(data byte) message.ResourceRecord {
+	return message.ResourceRecord{
+		Name:  "printer.local",
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   120,
+		Data:  []byte{192, 168, 1, data},
+	}
+}
+
+// TestRun_SimultaneousProbe_LowerRDATAWinsLexicographicCompare verifies the
+// RFC 6762 §8.2.1 example: whichever side's RDATA sorts lexicographically
+// later wins, so the lower side must rename.
+func TestRun_SimultaneousProbe_LowerRDATAWinsLexicographicCompare(t *testing.T) {
+	detector := &responder.ConflictDetector{}
+	our := []message.ResourceRecord{rr(50)} // lexicographically lower
+	peer := SimultaneousProbe(rr(200))      // lexicographically higher, so peer wins
+
+	result, err := Run(detector, our, peer)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if !result.Renamed() {
+		t.Error("Result.Renamed() = false, want true (our lower RDATA loses the tie-break)")
+	}
+}
+
+// TestRun_SimultaneousProbe_HigherRDATAWins verifies we win (no rename)
+// when our RDATA sorts higher than the peer's.
+func TestRun_SimultaneousProbe_HigherRDATAWins(t *testing.T) {
+	detector := &responder.ConflictDetector{}
+	our := []message.ResourceRecord{rr(200)}
+	peer := SimultaneousProbe(rr(50))
+
+	result, err := Run(detector, our, peer)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if result.Renamed() {
+		t.Error("Result.Renamed() = true, want false (our higher RDATA wins the tie-break)")
+	}
+}
+
+// TestRun_StaggeredProbe_ArrivesAsItsOwnRound verifies a StaggeredProbe
+// produces exactly one round, regardless of its delay (Run doesn't sleep -
+// After only orders rounds relative to each other).
+func TestRun_StaggeredProbe_ArrivesAsItsOwnRound(t *testing.T) {
+	detector := &responder.ConflictDetector{}
+	our := []message.ResourceRecord{rr(200)}
+	peer := StaggeredProbe(100*time.Millisecond, rr(50))
+
+	result, err := Run(detector, our, peer)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if len(result.Rounds) != 1 {
+		t.Fatalf("len(Rounds) = %d, want 1", len(result.Rounds))
+	}
+	if result.Rounds[0].Peer != "staggered" {
+		t.Errorf("Rounds[0].Peer = %q, want %q", result.Rounds[0].Peer, "staggered")
+	}
+}
+
+// TestRun_ThreeWayRace_ReportsEveryCompetitorInOrder verifies all three
+// simultaneous peers each get their own round, and that a peer's win
+// doesn't short-circuit the others.
+func TestRun_ThreeWayRace_ReportsEveryCompetitorInOrder(t *testing.T) {
+	detector := &responder.ConflictDetector{}
+	our := []message.ResourceRecord{rr(100)}
+	a, b, c := ThreeWayRace([]message.ResourceRecord{rr(50)}, []message.ResourceRecord{rr(200)}, []message.ResourceRecord{rr(90)})
+
+	result, err := Run(detector, our, a, b, c)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if len(result.Rounds) != 3 {
+		t.Fatalf("len(Rounds) = %d, want 3", len(result.Rounds))
+	}
+
+	want := map[string]bool{"race-a": false, "race-b": true, "race-c": false}
+	for _, round := range result.Rounds {
+		if round.Conflict != want[round.Peer] {
+			t.Errorf("round %q: Conflict = %v, want %v", round.Peer, round.Conflict, want[round.Peer])
+		}
+	}
+}
+
+// TestRun_FlappingPeer_EachProbeIsItsOwnRound verifies a FlappingPeer's two
+// scripted probes - with a silent gap between them - each produce their
+// own round, evaluated independently against our fixed record set.
+func TestRun_FlappingPeer_EachProbeIsItsOwnRound(t *testing.T) {
+	detector := &responder.ConflictDetector{}
+	our := []message.ResourceRecord{rr(100)}
+	peer := FlappingPeer(0, []message.ResourceRecord{rr(50)}, 5*time.Second, []message.ResourceRecord{rr(200)})
+
+	result, err := Run(detector, our, peer)
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if len(result.Rounds) != 2 {
+		t.Fatalf("len(Rounds) = %d, want 2", len(result.Rounds))
+	}
+	if result.Rounds[0].Conflict {
+		t.Error("Rounds[0].Conflict = true, want false (first probe's RDATA is lower, we win)")
+	}
+	if !result.Rounds[1].Conflict {
+		t.Error("Rounds[1].Conflict = false, want true (second probe's RDATA is higher, we lose)")
+	}
+}
+
+// TestRecorder_CapturesConflictEventsFromRun verifies a Recorder registered
+// via AddHook captures one ConflictEvent per round Run feeds through the
+// detector.
+func TestRecorder_CapturesConflictEventsFromRun(t *testing.T) {
+	detector := &responder.ConflictDetector{}
+	recorder := NewRecorder()
+	detector.AddHook(recorder)
+
+	our := []message.ResourceRecord{rr(100)}
+	a, b, c := ThreeWayRace([]message.ResourceRecord{rr(50)}, []message.ResourceRecord{rr(200)}, []message.ResourceRecord{rr(90)})
+
+	if _, err := Run(detector, our, a, b, c); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	events := recorder.Events()
+	if len(events) != 3 {
+		t.Fatalf("len(Events()) = %d, want 3", len(events))
+	}
+	if !events[1].Conflict {
+		t.Error("Events()[1].Conflict = false, want true (race-b's higher RDATA beats us)")
+	}
+}
+
+var _ responder.EventHook = (*Recorder)(nil)
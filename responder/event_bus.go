@@ -0,0 +1,223 @@
+package responder
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// channelEventHookBufferSize matches browseUpdateBufferSize - a generous
+// per-subscriber buffer so a brief consumer stall doesn't immediately start
+// dropping events.
+const channelEventHookBufferSize = 32
+
+// LifecycleKind identifies what a LifecycleEvent reports about a service's
+// conflict/rename history - a coarser, consumer-facing summary of the raw
+// ConflictEvent comparisons ConflictDetector.AddHook's OnConflictDetected
+// already exposes.
+type LifecycleKind int
+
+const (
+	// LifecycleDetected indicates an incoming probe was flagged as a
+	// candidate conflict against one of our records (Prober's
+	// OnProbeConflict), before the RFC 6762 §8.2 tiebreak decides a winner.
+	LifecycleDetected LifecycleKind = iota
+
+	// LifecycleWonTiebreak indicates our record won the RFC 6762 §8.2
+	// lexicographic comparison - the incoming host must defer.
+	LifecycleWonTiebreak
+
+	// LifecycleLostTiebreak indicates we lost the comparison and must
+	// rename.
+	LifecycleLostTiebreak
+
+	// LifecycleRenamed indicates Register picked a new instance name after
+	// a LifecycleLostTiebreak, per RFC 6762 §9.
+	LifecycleRenamed
+
+	// LifecycleRateLimited indicates probeRateLimiter's RFC 6762 §8.1
+	// cutoff tripped for a name and the next probe attempt was delayed
+	// beyond its ordinary backoff.
+	LifecycleRateLimited
+
+	// LifecycleSuppressed indicates BuildResponse omitted a record from a
+	// response because the querier's own Known-Answer list already
+	// carried it, per RFC 6762 §7.1/§7.2.
+	LifecycleSuppressed
+)
+
+// String returns a human-readable name for the lifecycle kind.
+func (k LifecycleKind) String() string {
+	switch k {
+	case LifecycleDetected:
+		return "Detected"
+	case LifecycleWonTiebreak:
+		return "WonTiebreak"
+	case LifecycleLostTiebreak:
+		return "LostTiebreak"
+	case LifecycleRenamed:
+		return "Renamed"
+	case LifecycleRateLimited:
+		return "RateLimited"
+	case LifecycleSuppressed:
+		return "Suppressed"
+	default:
+		return "Unknown"
+	}
+}
+
+// LifecycleEvent reports one step of a service's conflict/rename history,
+// the shape ChannelEventHook publishes to its subscribers.
+type LifecycleEvent struct {
+	Kind LifecycleKind
+
+	// Name is the record or instance name most relevant to Kind: the
+	// conflicting record's name for LifecycleDetected/WonTiebreak/
+	// LostTiebreak, the renamed-from name for LifecycleRenamed, the
+	// rate-limited name for LifecycleRateLimited, or the suppressed
+	// record's name for LifecycleSuppressed.
+	Name string
+
+	// OldName and NewName are populated only for LifecycleRenamed.
+	OldName, NewName string
+
+	// Delay is populated only for LifecycleRateLimited: how long
+	// probeRateLimiter's cutoff made Register wait before retrying.
+	Delay time.Duration
+
+	// At is when the underlying EventHook callback fired.
+	At time.Time
+}
+
+// ChannelEventHook adapts EventHook to a pull-based fan-out: each
+// Subscribe call gets its own channel of LifecycleEvents, independent of
+// any other subscriber, instead of registering a callback. This suits a
+// consumer built around a select loop (a UI update loop, a CLI watcher)
+// rather than one that wants to implement EventHook directly, the way
+// SlogEventHook/PrometheusObserver/SyslogEventHook do.
+//
+// Publishing to a subscriber is non-blocking: a subscriber that isn't
+// keeping up has its oldest buffered event dropped to make room, rather
+// than stalling the goroutine that produced the event (the same goroutine
+// that's running Register's probe/rename loop). Dropped() reports how many
+// events were discarded this way, across every subscriber.
+//
+// Safe for concurrent use; a zero-value ChannelEventHook is not usable,
+// use NewChannelEventHook.
+type ChannelEventHook struct {
+	mu      sync.Mutex
+	subs    map[chan LifecycleEvent]struct{}
+	dropped atomic.Uint64
+}
+
+// NewChannelEventHook creates a ChannelEventHook with no subscribers.
+func NewChannelEventHook() *ChannelEventHook {
+	return &ChannelEventHook{subs: make(map[chan LifecycleEvent]struct{})}
+}
+
+// Subscribe returns a channel of every LifecycleEvent published after this
+// call, closed when ctx is done.
+func (h *ChannelEventHook) Subscribe(ctx context.Context) <-chan LifecycleEvent {
+	ch := make(chan LifecycleEvent, channelEventHookBufferSize)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Dropped reports how many events have been discarded so far because a
+// subscriber's buffer was full.
+func (h *ChannelEventHook) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// publish fans evt out to every current subscriber, dropping each
+// subscriber's oldest buffered event first if its buffer is full.
+func (h *ChannelEventHook) publish(evt LifecycleEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- evt:
+			continue
+		default:
+		}
+
+		select {
+		case <-ch:
+			h.dropped.Add(1)
+		default:
+		}
+
+		select {
+		case ch <- evt:
+		default:
+			// Another goroutine drained and refilled ch between our two
+			// selects above; give up on this publish rather than spin.
+		}
+	}
+}
+
+// OnConflictDetected implements EventHook, publishing LifecycleWonTiebreak
+// or LifecycleLostTiebreak. The "different-names" outcome (not a real
+// conflict candidate) is not published.
+func (h *ChannelEventHook) OnConflictDetected(evt ConflictEvent) {
+	if evt.DecidedBy == "different-names" {
+		return
+	}
+
+	kind := LifecycleWonTiebreak
+	if evt.Conflict {
+		kind = LifecycleLostTiebreak
+	}
+	h.publish(LifecycleEvent{Kind: kind, Name: evt.OurRecord.Name, At: time.Now()})
+}
+
+// OnProbeStart implements EventHook. Not published: no LifecycleKind
+// corresponds to it.
+func (h *ChannelEventHook) OnProbeStart(name string) {}
+
+// OnProbeConflict implements EventHook, publishing LifecycleDetected.
+func (h *ChannelEventHook) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+	h.publish(LifecycleEvent{Kind: LifecycleDetected, Name: ourRecord.Name, At: time.Now()})
+}
+
+// OnRename implements EventHook, publishing LifecycleRenamed.
+func (h *ChannelEventHook) OnRename(oldName, newName string) {
+	h.publish(LifecycleEvent{Kind: LifecycleRenamed, Name: oldName, OldName: oldName, NewName: newName, At: time.Now()})
+}
+
+// OnProbeRateLimited implements EventHook, publishing LifecycleRateLimited.
+func (h *ChannelEventHook) OnProbeRateLimited(name string, delay time.Duration) {
+	h.publish(LifecycleEvent{Kind: LifecycleRateLimited, Name: name, Delay: delay, At: time.Now()})
+}
+
+// OnAnnounce implements EventHook. Not published: no LifecycleKind
+// corresponds to it.
+func (h *ChannelEventHook) OnAnnounce(serviceName string) {}
+
+// OnGoodbye implements EventHook. Not published: no LifecycleKind
+// corresponds to it.
+func (h *ChannelEventHook) OnGoodbye(serviceName string) {}
+
+// OnKnownAnswerSuppressed implements EventHook, publishing
+// LifecycleSuppressed.
+func (h *ChannelEventHook) OnKnownAnswerSuppressed(record message.ResourceRecord) {
+	h.publish(LifecycleEvent{Kind: LifecycleSuppressed, Name: record.Name, At: time.Now()})
+}
+
+var _ EventHook = (*ChannelEventHook)(nil)
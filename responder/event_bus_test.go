@@ -0,0 +1,188 @@
+package responder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// TestChannelEventHook_OnConflictDetected_PublishesTiebreakOutcome verifies
+// OnConflictDetected maps Conflict to LifecycleLostTiebreak/WonTiebreak, and
+// skips the "different-names" outcome entirely.
+func TestChannelEventHook_OnConflictDetected_PublishesTiebreakOutcome(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := hook.Subscribe(ctx)
+
+	hook.OnConflictDetected(ConflictEvent{
+		OurRecord: message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeA},
+		Conflict:  true,
+		DecidedBy: "rdata",
+	})
+	hook.OnConflictDetected(ConflictEvent{
+		OurRecord: message.ResourceRecord{Name: "printer.local", Type: protocol.RecordTypeA},
+		Conflict:  false,
+		DecidedBy: "rdata",
+	})
+	hook.OnConflictDetected(ConflictEvent{
+		DecidedBy: "different-names",
+	})
+
+	want := []LifecycleKind{LifecycleLostTiebreak, LifecycleWonTiebreak}
+	for _, k := range want {
+		select {
+		case evt := <-events:
+			if evt.Kind != k {
+				t.Errorf("Kind = %v, want %v", evt.Kind, k)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no event received, want Kind %v", k)
+		}
+	}
+
+	select {
+	case evt := <-events:
+		t.Errorf("got unexpected event %+v, want none (different-names isn't published)", evt)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestChannelEventHook_OnProbeConflict_PublishesDetected verifies
+// OnProbeConflict publishes LifecycleDetected.
+func TestChannelEventHook_OnProbeConflict_PublishesDetected(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := hook.Subscribe(ctx)
+
+	hook.OnProbeConflict(message.ResourceRecord{Name: "printer.local"}, message.ResourceRecord{Name: "printer.local"})
+
+	select {
+	case evt := <-events:
+		if evt.Kind != LifecycleDetected || evt.Name != "printer.local" {
+			t.Errorf("event = %+v, want Kind=LifecycleDetected Name=printer.local", evt)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+}
+
+// TestChannelEventHook_OnRename_PublishesRenamed verifies OnRename
+// publishes LifecycleRenamed with both names populated.
+func TestChannelEventHook_OnRename_PublishesRenamed(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := hook.Subscribe(ctx)
+
+	hook.OnRename("My Printer", "My Printer-2")
+
+	select {
+	case evt := <-events:
+		if evt.Kind != LifecycleRenamed || evt.OldName != "My Printer" || evt.NewName != "My Printer-2" {
+			t.Errorf("event = %+v, want Kind=LifecycleRenamed OldName=%q NewName=%q", evt, "My Printer", "My Printer-2")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+}
+
+// TestChannelEventHook_OnProbeRateLimited_PublishesRateLimited verifies
+// OnProbeRateLimited publishes LifecycleRateLimited with the delay.
+func TestChannelEventHook_OnProbeRateLimited_PublishesRateLimited(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := hook.Subscribe(ctx)
+
+	hook.OnProbeRateLimited("My Printer", 5*time.Second)
+
+	select {
+	case evt := <-events:
+		if evt.Kind != LifecycleRateLimited || evt.Name != "My Printer" || evt.Delay != 5*time.Second {
+			t.Errorf("event = %+v, want Kind=LifecycleRateLimited Name=%q Delay=5s", evt, "My Printer")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+}
+
+// TestChannelEventHook_Subscribe_ClosesOnContextDone verifies a
+// subscriber's channel is closed once its context is done.
+func TestChannelEventHook_Subscribe_ClosesOnContextDone(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := hook.Subscribe(ctx)
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("events produced a value after context cancellation, want it closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("events channel was not closed after context cancellation")
+	}
+}
+
+// TestChannelEventHook_Publish_DropsOldestOnFullBuffer verifies a
+// subscriber that isn't draining its channel has its oldest buffered event
+// dropped (rather than blocking the publisher) once its buffer fills, and
+// that Dropped() counts it.
+func TestChannelEventHook_Publish_DropsOldestOnFullBuffer(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := hook.Subscribe(ctx)
+
+	// Fill the buffer, then publish one more than it holds.
+	for i := 0; i < channelEventHookBufferSize+1; i++ {
+		hook.OnRename("svc", "svc-2")
+	}
+
+	if got := hook.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+
+	drained := 0
+	for range events {
+		drained++
+		if drained == channelEventHookBufferSize {
+			break
+		}
+	}
+	if drained != channelEventHookBufferSize {
+		t.Errorf("drained %d events, want %d (buffer size, oldest dropped)", drained, channelEventHookBufferSize)
+	}
+}
+
+// TestChannelEventHook_MultipleSubscribers verifies every active
+// subscriber independently receives the same published event.
+func TestChannelEventHook_MultipleSubscribers(t *testing.T) {
+	hook := NewChannelEventHook()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := hook.Subscribe(ctx)
+	b := hook.Subscribe(ctx)
+
+	hook.OnRename("svc", "svc-2")
+
+	for name, ch := range map[string]<-chan LifecycleEvent{"a": a, "b": b} {
+		select {
+		case evt := <-ch:
+			if evt.Kind != LifecycleRenamed {
+				t.Errorf("subscriber %s: Kind = %v, want LifecycleRenamed", name, evt.Kind)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("subscriber %s: no event received", name)
+		}
+	}
+}
+
+var _ EventHook = (*ChannelEventHook)(nil)
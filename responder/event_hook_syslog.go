@@ -0,0 +1,68 @@
+//go:build !windows && !plan9 && !js
+
+package responder
+
+import (
+	"fmt"
+	"log/syslog"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// SyslogEventHook adapts EventHook to a *syslog.Writer, one INFO-priority
+// line per event, for operators who centralize logs via syslog rather than
+// log/slog or Prometheus. log/syslog has no Windows/Plan 9/JS
+// implementation, so this file (and type) only builds on platforms that
+// have one.
+type SyslogEventHook struct {
+	Writer *syslog.Writer
+}
+
+// NewSyslogEventHook creates a SyslogEventHook writing to w.
+func NewSyslogEventHook(w *syslog.Writer) *SyslogEventHook {
+	return &SyslogEventHook{Writer: w}
+}
+
+// OnConflictDetected implements EventHook.
+func (h *SyslogEventHook) OnConflictDetected(evt ConflictEvent) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns conflict detected: name=%s decided_by=%s we_won=%t conflict=%t",
+		evt.OurRecord.Name, evt.DecidedBy, evt.WeWon, evt.Conflict))
+}
+
+// OnProbeStart implements EventHook.
+func (h *SyslogEventHook) OnProbeStart(name string) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns probe start: name=%s", name))
+}
+
+// OnProbeConflict implements EventHook.
+func (h *SyslogEventHook) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns probe conflict: name=%s type=%s", ourRecord.Name, ourRecord.Type))
+}
+
+// OnRename implements EventHook.
+func (h *SyslogEventHook) OnRename(oldName, newName string) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns service renamed: old_name=%s new_name=%s", oldName, newName))
+}
+
+// OnProbeRateLimited implements EventHook.
+func (h *SyslogEventHook) OnProbeRateLimited(name string, delay time.Duration) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns probe rate limited: name=%s delay=%s", name, delay))
+}
+
+// OnAnnounce implements EventHook.
+func (h *SyslogEventHook) OnAnnounce(serviceName string) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns service announced: name=%s", serviceName))
+}
+
+// OnGoodbye implements EventHook.
+func (h *SyslogEventHook) OnGoodbye(serviceName string) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns service goodbye: name=%s", serviceName))
+}
+
+// OnKnownAnswerSuppressed implements EventHook.
+func (h *SyslogEventHook) OnKnownAnswerSuppressed(record message.ResourceRecord) {
+	_ = h.Writer.Info(fmt.Sprintf("mdns known-answer suppressed: name=%s type=%s", record.Name, record.Type))
+}
+
+var _ EventHook = (*SyslogEventHook)(nil)
@@ -0,0 +1,116 @@
+package responder
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// EventHook receives notifications for conflict, probing, rename,
+// announce, and goodbye events, so operators can wire conflict counts and
+// rename churn into their own observability stack without patching the
+// library. Every method is invoked synchronously from the goroutine that
+// produced the event; a slow hook delays that goroutine.
+//
+// EventHook is a narrow superset of internal/state's ProbeHook interface
+// (OnProbeStart, OnProbeConflict), so an EventHook value can be passed
+// directly to Prober.AddHook as well as ConflictDetector.AddHook.
+type EventHook interface {
+	// OnConflictDetected is called for every ConflictDetector.DetectConflict
+	// or DetectConflictSet outcome, including the no-conflict "we won" and
+	// "identical, fault-tolerant" branches.
+	OnConflictDetected(ConflictEvent)
+
+	// OnProbeStart is called once per Prober.Probe call, before the first
+	// probe is sent.
+	OnProbeStart(name string)
+
+	// OnProbeConflict is called when a probe response conflicts with one of
+	// our records per RFC 6762 §8.2.
+	OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord)
+
+	// OnRename is called when Register renames a service after losing a
+	// naming conflict (RFC 6762 §9).
+	OnRename(oldName, newName string)
+
+	// OnProbeRateLimited is called when probeRateLimiter's RFC 6762 §8.1
+	// cutoff trips for name and Register's rename loop backs off by delay
+	// instead of its ordinary configured backoff.
+	OnProbeRateLimited(name string, delay time.Duration)
+
+	// OnAnnounce is called once a service's state machine reaches
+	// StateEstablished (RFC 6762 §8.3).
+	OnAnnounce(serviceName string)
+
+	// OnGoodbye is called after a service's goodbye packets (RFC 6762
+	// §10.1) finish sending, whether from Unregister, Close, or Shutdown.
+	OnGoodbye(serviceName string)
+
+	// OnKnownAnswerSuppressed is called whenever BuildResponse omits a
+	// record from a response because the querier's own Known-Answer list
+	// already carries it per RFC 6762 §7.1/§7.2, the same outcome
+	// beacon_responder_known_answer_suppressed_total counts - so a test
+	// can assert suppression happened deterministically instead of
+	// sleeping and checking for the record's absence.
+	OnKnownAnswerSuppressed(record message.ResourceRecord)
+}
+
+// SlogEventHook adapts EventHook to a *slog.Logger, one structured log
+// record per event at Info level, for operators already standardized on
+// log/slog rather than standing up Prometheus.
+type SlogEventHook struct {
+	Logger *slog.Logger
+}
+
+// NewSlogEventHook creates a SlogEventHook logging to logger.
+func NewSlogEventHook(logger *slog.Logger) *SlogEventHook {
+	return &SlogEventHook{Logger: logger}
+}
+
+// OnConflictDetected implements EventHook.
+func (h *SlogEventHook) OnConflictDetected(evt ConflictEvent) {
+	h.Logger.Info("mdns conflict detected",
+		"name", evt.OurRecord.Name,
+		"decided_by", evt.DecidedBy,
+		"we_won", evt.WeWon,
+		"conflict", evt.Conflict,
+	)
+}
+
+// OnProbeStart implements EventHook.
+func (h *SlogEventHook) OnProbeStart(name string) {
+	h.Logger.Info("mdns probe start", "name", name)
+}
+
+// OnProbeConflict implements EventHook.
+func (h *SlogEventHook) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+	h.Logger.Info("mdns probe conflict", "name", ourRecord.Name, "type", ourRecord.Type)
+}
+
+// OnRename implements EventHook.
+func (h *SlogEventHook) OnRename(oldName, newName string) {
+	h.Logger.Info("mdns service renamed", "old_name", oldName, "new_name", newName)
+}
+
+// OnProbeRateLimited implements EventHook.
+func (h *SlogEventHook) OnProbeRateLimited(name string, delay time.Duration) {
+	h.Logger.Info("mdns probe rate limited", "name", name, "delay", delay)
+}
+
+// OnAnnounce implements EventHook.
+func (h *SlogEventHook) OnAnnounce(serviceName string) {
+	h.Logger.Info("mdns service announced", "name", serviceName)
+}
+
+// OnGoodbye implements EventHook.
+func (h *SlogEventHook) OnGoodbye(serviceName string) {
+	h.Logger.Info("mdns service goodbye", "name", serviceName)
+}
+
+// OnKnownAnswerSuppressed implements EventHook.
+func (h *SlogEventHook) OnKnownAnswerSuppressed(record message.ResourceRecord) {
+	h.Logger.Info("mdns known-answer suppressed", "name", record.Name, "type", record.Type)
+}
+
+var _ EventHook = (*SlogEventHook)(nil)
@@ -0,0 +1,145 @@
+package responder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestResponder_HandleQuery_DirectAAAAOnIPv4OnlyHost_GetsNSEC verifies RFC
+// 6762 §6.1's negative-response rule: a direct AAAA query against this
+// Responder's own hostname, on a host with no IPv6 address configured (the
+// sandbox this test runs in), gets back an empty Answer section plus an
+// NSEC record in Additionals listing the type(s) the host DOES have (A),
+// rather than silence.
+func TestResponder_HandleQuery_DirectAAAAOnIPv4OnlyHost_GetsNSEC(t *testing.T) {
+	mock := transport.NewMockTransport()
+
+	r, err := New(context.Background(), WithTransport(mock), WithIPv6(false))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	queryPacket, err := message.BuildQuery(r.hostname, uint16(protocol.RecordTypeAAAA))
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v, want nil", err)
+	}
+
+	if err := r.handleQuery(queryPacket, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() error = %v, want nil", err)
+	}
+
+	sent := waitForSendCalls(mock, 1, time.Second)
+	if len(sent) != 1 {
+		t.Fatalf("SendCalls() = %d, want 1", len(sent))
+	}
+
+	resp, err := message.ParseMessage(sent[0].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v, want nil", err)
+	}
+	if len(resp.Answers) != 0 {
+		t.Errorf("Answers = %v, want none (host has no AAAA to answer with)", resp.Answers)
+	}
+	if len(resp.Additionals) != 1 || resp.Additionals[0].TYPE != uint16(protocol.RecordTypeNSEC) {
+		t.Fatalf("Additionals = %v, want exactly one NSEC record", resp.Additionals)
+	}
+
+	parsed, err := message.ParseNSEC(resp.Additionals[0].RDATA)
+	if err != nil {
+		t.Fatalf("ParseNSEC() error = %v, want nil", err)
+	}
+	if parsed.NextDomainName != r.hostname {
+		t.Errorf("NextDomainName = %q, want %q", parsed.NextDomainName, r.hostname)
+	}
+	if len(parsed.TypeBitMap) != 1 || parsed.TypeBitMap[0] != uint16(protocol.RecordTypeA) {
+		t.Errorf("TypeBitMap = %v, want [A] (the one type this host actually has)", parsed.TypeBitMap)
+	}
+}
+
+// TestResponder_HandleQuery_DirectAQuestion_AnswersDirectly verifies a
+// direct A query against this Responder's own hostname, on a host that
+// does have an IPv4 address (every test in this sandbox relies on one
+// being available - see getLocalIPv4), gets answered with the A record
+// itself rather than an NSEC negative response.
+func TestResponder_HandleQuery_DirectAQuestion_AnswersDirectly(t *testing.T) {
+	mock := transport.NewMockTransport()
+
+	r, err := New(context.Background(), WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	queryPacket, err := message.BuildQuery(r.hostname, uint16(protocol.RecordTypeA))
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v, want nil", err)
+	}
+
+	if err := r.handleQuery(queryPacket, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() error = %v, want nil", err)
+	}
+
+	sent := waitForSendCalls(mock, 1, time.Second)
+	if len(sent) != 1 {
+		t.Fatalf("SendCalls() = %d, want 1", len(sent))
+	}
+
+	resp, err := message.ParseMessage(sent[0].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v, want nil", err)
+	}
+	if len(resp.Answers) != 1 || resp.Answers[0].TYPE != uint16(protocol.RecordTypeA) {
+		t.Fatalf("Answers = %v, want exactly one A record", resp.Answers)
+	}
+	for _, additional := range resp.Additionals {
+		if additional.TYPE == uint16(protocol.RecordTypeNSEC) {
+			t.Error("Additionals contains an NSEC record, want none (the queried type exists)")
+		}
+	}
+}
+
+// TestResponder_HandleQuery_DirectAQuestion_SuppressedByKnownAnswer verifies
+// RFC 6762 §7.1 known-answer suppression applies to answerHostAddressQuestion
+// exactly as it already does to the PTR-matched path: a direct A query that
+// already lists our own A record, at a fresh-enough TTL, in its own
+// Known-Answer section gets no answer back.
+func TestResponder_HandleQuery_DirectAQuestion_SuppressedByKnownAnswer(t *testing.T) {
+	mock := transport.NewMockTransport()
+
+	r, err := New(context.Background(), WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	ipv4, err := getLocalIPv4(r.interfaces)
+	if err != nil {
+		t.Fatalf("getLocalIPv4() error = %v, want an address (required for this test)", err)
+	}
+
+	knownAnswer := &message.ResourceRecord{
+		Name:  r.hostname,
+		Type:  protocol.RecordTypeA,
+		Class: protocol.ClassIN,
+		TTL:   protocol.TTLHostname,
+		Data:  ipv4,
+	}
+	queryPacket, err := message.BuildQueryWithKnownAnswers(r.hostname, uint16(protocol.RecordTypeA), []*message.ResourceRecord{knownAnswer})
+	if err != nil {
+		t.Fatalf("BuildQueryWithKnownAnswers() error = %v, want nil", err)
+	}
+
+	if err := r.handleQuery(queryPacket, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() error = %v, want nil", err)
+	}
+
+	if sent := waitForSendCalls(mock, 1, 300*time.Millisecond); len(sent) != 0 {
+		t.Fatalf("SendCalls() = %d, want 0 (the querier already knows the only answer we'd give)", len(sent))
+	}
+}
@@ -0,0 +1,77 @@
+package responder
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Snapshot returns a copy of this Responder's retained log records, oldest
+// first, for post-mortem inspection - e.g. to print the Debug/Info detail
+// leading up to an intermittent bug a user has just reproduced. Returns
+// nil if WithLogBuffer wasn't given.
+func (r *Responder) Snapshot() []slog.Record {
+	if r.logBuffer == nil {
+		return nil
+	}
+	return r.logBuffer.Snapshot()
+}
+
+// DebugHandler returns an http.Handler that dumps Snapshot as a JSON array
+// of {time, level, message, attrs} objects, suitable for wiring into an
+// operator-only debug endpoint alongside probe.Probe's Handler. Responds
+// with an empty JSON array if WithLogBuffer wasn't given.
+func (r *Responder) DebugHandler() http.Handler {
+	return http.HandlerFunc(r.serveDebugLog)
+}
+
+// debugLogEntry is Snapshot's JSON wire format: slog.Record itself isn't
+// JSON-marshalable (its fields are unexported), so DebugHandler converts
+// each record to this shape first.
+type debugLogEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs"`
+}
+
+func (r *Responder) serveDebugLog(w http.ResponseWriter, _ *http.Request) {
+	records := r.Snapshot()
+	entries := make([]debugLogEntry, len(records))
+	for i, rec := range records {
+		attrs := make(map[string]any, rec.NumAttrs())
+		rec.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.Any()
+			return true
+		})
+		entries[i] = debugLogEntry{
+			Time:    rec.Time,
+			Level:   rec.Level.String(),
+			Message: rec.Message,
+			Attrs:   attrs,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		r.panicLogger().Error("logbuffer debug handler failed to encode JSON response", "error", err)
+	}
+}
+
+// flushLogBuffer replays r's retained log records to r's logger at Error
+// level (see logbuf.Buffer.FlushTo), if WithLogBuffer was given. Call it
+// when a panic is recovered or a fatal probe/announce error occurs, so
+// the Debug/Info detail leading up to the failure becomes visible even
+// though it was only captured below the logger's configured level.
+func (r *Responder) flushLogBuffer() {
+	if r.logBuffer == nil {
+		return
+	}
+	logger := r.logBufferFlushLogger
+	if logger == nil {
+		logger = r.panicLogger()
+	}
+	r.logBuffer.FlushTo(context.Background(), logger)
+}
@@ -0,0 +1,132 @@
+package responder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSnapshot_NilWithoutLogBuffer validates that Snapshot returns nil for
+// a Responder built without WithLogBuffer.
+func TestSnapshot_NilWithoutLogBuffer(t *testing.T) {
+	ctx := context.Background()
+	r, err := New(ctx)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = r.Shutdown(ctx) }()
+
+	if got := r.Snapshot(); got != nil {
+		t.Errorf("Snapshot() = %v, want nil", got)
+	}
+}
+
+// TestWithLogBuffer_CapturesLoggedRecords validates that a Responder
+// configured with WithLogBuffer retains records logged through r.logger,
+// even below the level its underlying handler is configured to show.
+func TestWithLogBuffer_CapturesLoggedRecords(t *testing.T) {
+	ctx := context.Background()
+	underlying := slog.New(slog.NewTextHandler(discardWriter{}, &slog.HandlerOptions{Level: slog.LevelError}))
+	r, err := New(ctx, WithLogger(underlying), WithLogBuffer(10))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = r.Shutdown(ctx) }()
+
+	r.logger.Debug("probe started", "service", "example._http._tcp.local")
+
+	records := r.Snapshot()
+	if len(records) != 1 {
+		t.Fatalf("Snapshot() = %d records, want 1", len(records))
+	}
+	if records[0].Message != "probe started" {
+		t.Errorf("Snapshot()[0].Message = %q, want %q", records[0].Message, "probe started")
+	}
+}
+
+// TestDebugHandler_ServesSnapshotAsJSON validates that DebugHandler dumps
+// Snapshot's contents as a JSON array.
+func TestDebugHandler_ServesSnapshotAsJSON(t *testing.T) {
+	ctx := context.Background()
+	r, err := New(ctx, WithLogBuffer(10))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = r.Shutdown(ctx) }()
+
+	r.logger.Info("announced", "service", "example._http._tcp.local")
+
+	rec := httptest.NewRecorder()
+	r.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/log", nil))
+
+	var entries []debugLogEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode DebugHandler response: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("DebugHandler response has %d entries, want 1", len(entries))
+	}
+	if entries[0].Message != "announced" {
+		t.Errorf("entries[0].Message = %q, want %q", entries[0].Message, "announced")
+	}
+	if got := entries[0].Attrs["service"]; got != "example._http._tcp.local" {
+		t.Errorf("entries[0].Attrs[\"service\"] = %v, want %q", got, "example._http._tcp.local")
+	}
+}
+
+// TestFlushLogBuffer_ReplaysToLoggerAtErrorLevel validates that
+// flushLogBuffer (invoked by recoverPanic - see recovery_test.go's
+// TestRecoverPanic_InvokesHandlerAndMetrics-adjacent coverage) replays
+// buffered records to r.logger at Error level.
+func TestFlushLogBuffer_ReplaysToLoggerAtErrorLevel(t *testing.T) {
+	var buf bytes.Buffer
+	underlying := slog.New(slog.NewTextHandler(&buf, nil))
+	r := &Responder{logger: underlying}
+	if err := WithLogBuffer(10)(r); err != nil {
+		t.Fatalf("WithLogBuffer(10) failed: %v", err)
+	}
+
+	r.logger.Debug("probe started")
+	r.flushLogBuffer()
+
+	if got := buf.String(); got == "" {
+		t.Fatal("flushLogBuffer did not write anything to the underlying handler")
+	}
+}
+
+// TestFlushLogBuffer_DoesNotReenterItsOwnBuffer guards against
+// flushLogBuffer replaying through r.logger (the logbuf.Tee-wrapped
+// logger), which would re-capture each replayed Error-level record back
+// into r.logBuffer instead of leaving it holding the original entries.
+func TestFlushLogBuffer_DoesNotReenterItsOwnBuffer(t *testing.T) {
+	var buf bytes.Buffer
+	underlying := slog.New(slog.NewTextHandler(&buf, nil))
+	r := &Responder{logger: underlying}
+	if err := WithLogBuffer(10)(r); err != nil {
+		t.Fatalf("WithLogBuffer(10) failed: %v", err)
+	}
+
+	r.logger.Debug("first")
+	r.logger.Debug("second")
+
+	before := r.Snapshot()
+	r.flushLogBuffer()
+	after := r.Snapshot()
+
+	if len(after) != len(before) {
+		t.Fatalf("Snapshot() has %d records after flushLogBuffer, want %d (unchanged)", len(after), len(before))
+	}
+	for i := range before {
+		if after[i].Message != before[i].Message {
+			t.Errorf("record %d message changed from %q to %q after flushLogBuffer", i, before[i].Message, after[i].Message)
+		}
+	}
+}
+
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
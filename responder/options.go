@@ -1,5 +1,43 @@
 package responder
 
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/logbuf"
+	"github.com/joshuafuller/beacon/internal/message"
+	internalmetrics "github.com/joshuafuller/beacon/internal/metrics"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/responder"
+	"github.com/joshuafuller/beacon/internal/state"
+	"github.com/joshuafuller/beacon/internal/transport"
+	"github.com/joshuafuller/beacon/metrics"
+	"github.com/joshuafuller/beacon/probe"
+	"github.com/joshuafuller/beacon/tap"
+)
+
+// NetworkMode selects which multicast transport(s) a Responder uses to
+// receive queries and send responses/announcements, mirroring
+// querier.IPFamily.
+type NetworkMode int
+
+const (
+	// IPv4Only joins 224.0.0.251:5353 only. This is the default, preserving
+	// the Responder's original (pre-dual-stack) behavior.
+	IPv4Only NetworkMode = iota
+
+	// IPv6Only joins [ff02::fb]:5353 only.
+	IPv6Only
+
+	// DualStack joins both the IPv4 and IPv6 multicast groups, receiving
+	// queries from either and multicasting responses/announcements to both.
+	DualStack
+)
+
 // Option is a functional option for configuring a Responder.
 //
 // This pattern allows flexible configuration without breaking API compatibility.
@@ -24,7 +62,777 @@ type Option func(*Responder) error
 // T044: WithHostname option
 func WithHostname(hostname string) Option {
 	return func(r *Responder) error {
+		if hostname != "" {
+			if err := protocol.ValidateName(hostname); err != nil {
+				return err
+			}
+		}
 		r.hostname = hostname
 		return nil
 	}
 }
+
+// WithInterfaces restricts the addresses a Responder advertises to those
+// configured on the given interfaces, rather than any non-loopback address
+// on the host (the default). This mirrors querier.WithInterfaces.
+//
+// A dual-stack host with (say) an IPv6-only guest NIC and an IPv4-only LAN
+// NIC can use this so A records only ever carry the LAN NIC's address and
+// AAAA records only carry the guest NIC's - each call to Register()
+// re-derives the address set from these interfaces' current addresses.
+//
+// If the provided list is empty, New() returns an error.
+func WithInterfaces(ifaces []net.Interface) Option {
+	return func(r *Responder) error {
+		if len(ifaces) == 0 {
+			return &errors.ValidationError{
+				Field:   "interfaces",
+				Value:   ifaces,
+				Message: "interface list cannot be empty",
+				Code:    errors.CodeInvalidInterfaceList,
+			}
+		}
+
+		r.interfaces = ifaces
+		return nil
+	}
+}
+
+// WithNetworkMode selects which multicast address family(ies) the Responder
+// joins and advertises over - IPv4Only (the default), IPv6Only, or
+// DualStack. This mirrors querier.WithIPFamily.
+//
+// Example:
+//
+//	r, err := New(ctx, WithNetworkMode(responder.DualStack))
+func WithNetworkMode(mode NetworkMode) Option {
+	return func(r *Responder) error {
+		switch mode {
+		case IPv4Only, IPv6Only, DualStack:
+			r.networkMode = mode
+			return nil
+		default:
+			return &errors.ValidationError{
+				Field:   "networkMode",
+				Value:   mode,
+				Message: "must be IPv4Only, IPv6Only, or DualStack",
+				Code:    errors.CodeInvalidNetworkMode,
+			}
+		}
+	}
+}
+
+// WithTransport injects t as the Responder's transport, replacing the UDP
+// multicast transport New() would otherwise build per WithNetworkMode.
+//
+// This is for test isolation (inject a transport.MockTransport, or an
+// in-memory fabric like testutil/mdnstest.Fabric, and exercise Register/
+// Close without a real network) - mirroring querier.WithTransport. A
+// transport installed this way isn't a rebinder (see responder/rebind.go),
+// so WithWatchInterfaces won't try to rejoin multicast groups on it across
+// an interface change; that's expected for a non-UDP transport.
+//
+// Example:
+//
+//	mock := transport.NewMockTransport()
+//	r, err := New(ctx, WithTransport(mock))
+func WithTransport(t transport.Transport) Option {
+	return func(r *Responder) error {
+		if t == nil {
+			return &errors.ValidationError{
+				Field:   "transport",
+				Value:   nil,
+				Message: "transport cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+
+		r.customTransport = t
+		r.transportPlugins = nil
+		return nil
+	}
+}
+
+// WithTransports configures plugins as the Responder's multi-homed
+// transport set, replacing both the UDP multicast transport New() would
+// otherwise build and any WithTransport override. Each plugin's own
+// Interface/IPv4/IPv6 lets handleQuery answer a query with that interface's
+// address and send the reply back out through the same plugin's Transport,
+// instead of always using getLocalIPv4(r.interfaces)'s first non-loopback
+// address the way a single-transport Responder does - see TransportPlugin's
+// doc comment.
+//
+// runQueryHandler spawns one goroutine per plugin once New returns, each
+// receiving on that plugin's own Transport. Register's probe/announce
+// traffic and Close continue to use a single Transport (every plugin's
+// Transport composed together) since state.Prober/state.Announcer aren't
+// plugin-aware.
+//
+// Example:
+//
+//	plugins := []responder.TransportPlugin{
+//	    {Transport: eth0Transport, Interface: eth0, IPv4: eth0Addr},
+//	    {Transport: wlan0Transport, Interface: wlan0, IPv4: wlan0Addr},
+//	}
+//	r, err := New(ctx, WithTransports(plugins...))
+func WithTransports(plugins ...TransportPlugin) Option {
+	return func(r *Responder) error {
+		if len(plugins) == 0 {
+			return &errors.ValidationError{
+				Field:   "transports",
+				Value:   plugins,
+				Message: "transport plugin list cannot be empty",
+				Code:    errors.CodeInvalidTransportList,
+			}
+		}
+		for i, plugin := range plugins {
+			if plugin.Transport == nil {
+				return &errors.ValidationError{
+					Field:   "transports",
+					Value:   i,
+					Message: "transport plugin's Transport cannot be nil",
+					Code:    errors.CodeInvalidTransportList,
+				}
+			}
+			if len(plugin.IPv4) == 0 && len(plugin.IPv6) == 0 {
+				return &errors.ValidationError{
+					Field:   "transports",
+					Value:   i,
+					Message: "transport plugin must set IPv4 and/or IPv6",
+					Code:    errors.CodeInvalidTransportList,
+				}
+			}
+		}
+
+		r.transportPlugins = plugins
+		r.customTransport = newPluginGroupTransport(plugins)
+		return nil
+	}
+}
+
+// WithIPv6 controls whether Register advertises AAAA records for the host's
+// IPv6 addresses. Defaults to true, preserving prior behavior.
+//
+// This is independent of WithNetworkMode: networkMode picks which
+// multicast transport(s) the Responder joins and sends over, while WithIPv6
+// only controls whether IPv6 addresses are included in the records a
+// Service announces - useful for suppressing AAAA records on a host that
+// has IPv6 configured but doesn't want those addresses advertised, without
+// also giving up the ability to receive queries over IPv6 (WithNetworkMode
+// still governs that).
+//
+// Example:
+//
+//	r, err := New(ctx, WithIPv6(false))
+func WithIPv6(enabled bool) Option {
+	return func(r *Responder) error {
+		r.ipv6Enabled = enabled
+		return nil
+	}
+}
+
+// WithMaxUDPSize sets the UDP payload size BuildResponse advertises in a
+// response's EDNS(0) OPT record (RFC 6891 §6.1.2) and, when it negotiates
+// smaller than a query's own advertised size, packs the response's
+// Answer/Additional sections to instead of hard-truncating at the fixed
+// RFC 6762 §17 9000-byte ceiling. Mirrors querier.WithMaxUDPSize.
+//
+// Default: 1440 bytes (fits a single untagged Ethernet frame).
+//
+// Example (advertise the full RFC 6762 §17 ceiling on a jumbo-frame LAN):
+//
+//	r, err := New(ctx, WithMaxUDPSize(9000))
+func WithMaxUDPSize(n uint16) Option {
+	return func(r *Responder) error {
+		if n == 0 {
+			return &errors.ValidationError{
+				Field:   "maxUDPSize",
+				Value:   n,
+				Message: "maxUDPSize must be greater than 0",
+				Code:    errors.CodeInvalidValue,
+			}
+		}
+
+		r.responseBuilder = r.responseBuilder.WithMaxUDPSize(n)
+		return nil
+	}
+}
+
+// WithUniqueRecordSuppression controls whether RFC 6762 §10.2 unique
+// records (SRV, TXT, A/AAAA, HTTPS) are subject to RFC 6762 §7.1
+// known-answer suppression at all, not just the shared PTR record.
+//
+// RFC 6762 §7.1: "Generally, this applies only to Shared records, not
+// Unique records, since if a Multicast DNS querier already has at least
+// one Unique record in its cache then it should not be expecting further
+// different answers to this question." Default: true, suppressing unique
+// records the same as shared ones - pass false to always include them
+// regardless of a query's known-answer list.
+//
+// Example:
+//
+//	r, err := New(ctx, WithUniqueRecordSuppression(false))
+func WithUniqueRecordSuppression(enabled bool) Option {
+	return func(r *Responder) error {
+		r.responseBuilder = r.responseBuilder.WithUniqueRecordSuppression(enabled)
+		return nil
+	}
+}
+
+// WithRateLimitObserver registers observer to be called once per record a
+// multicast query response considers, reporting the RFC 6762 §6.2 per-record
+// rate limiting decision sendQueryResponse made for it: suppressed true if
+// the record repeated within its bucket's interval and was dropped,
+// suppressed false if it was sent. A unicast reply (legacy or QU-bit) never
+// calls observer, since it isn't subject to this rate limit at all.
+//
+// Without this option, rate limiting still applies - there's just nothing
+// counting the suppressions.
+//
+// Example:
+//
+//	suppressed := 0
+//	r, err := New(ctx, WithRateLimitObserver(func(rr *message.ResourceRecord, wasSuppressed bool) {
+//		if wasSuppressed {
+//			suppressed++
+//		}
+//	}))
+func WithRateLimitObserver(observer func(rr *message.ResourceRecord, suppressed bool)) Option {
+	return func(r *Responder) error {
+		r.rateLimitObserver = observer
+		return nil
+	}
+}
+
+// WithBackoff configures the delay Register waits between a naming
+// conflict (RFC 6762 §9) and its next rename-and-retry attempt, per
+// state.BackoffConfig's gRPC-style exponential backoff with jitter. Without
+// this option, Register retries immediately (the zero BackoffConfig).
+//
+// This matters on a LAN where a probe storm makes multiple responders lose
+// simultaneous-probe tiebreaking at once: without jittered backoff they'd
+// all rename to the same "-2" suffix and collide again.
+//
+// Example:
+//
+//	r, err := New(ctx, WithBackoff(state.DefaultBackoffConfig()))
+func WithBackoff(cfg state.BackoffConfig) Option {
+	return func(r *Responder) error {
+		r.backoffConfig = cfg
+		return nil
+	}
+}
+
+// WithLameDuckTimeout bounds how long Shutdown waits for every registered
+// service's goodbye packets (RFC 6762 §10.1) to flush before force-closing
+// the transport. Without this option, Shutdown uses defaultLameDuckTimeout.
+//
+// Example:
+//
+//	r, err := New(ctx, WithLameDuckTimeout(10*time.Second))
+func WithLameDuckTimeout(timeout time.Duration) Option {
+	return func(r *Responder) error {
+		if timeout <= 0 {
+			return &errors.ValidationError{
+				Field:   "lameDuckTimeout",
+				Value:   timeout,
+				Message: "timeout must be positive",
+				Code:    errors.CodeInvalidValue,
+			}
+		}
+
+		r.lameDuckTimeout = timeout
+		return nil
+	}
+}
+
+// WithZone sets the domain suffix New() appends to the system hostname when
+// WithHostname wasn't given (e.g. "home.arpa" instead of the default
+// "local"), and the Domain field handleQuery reports in its responses, for
+// hybrid Bonjour/unicast-DNS deployments per RFC 6763 §11.
+//
+// This does NOT relax Service.ServiceType's own "must end in .local"
+// validation (responder/service.go's validateServiceType) - a Service
+// registered under a non-.local zone must still set ServiceType to a
+// string ending in ".local" today.
+//
+// Example:
+//
+//	r, err := New(ctx, WithZone("home.arpa"))
+func WithZone(zone string) Option {
+	return func(r *Responder) error {
+		zone = strings.TrimSuffix(zone, ".")
+		if err := protocol.ValidateName(zone); err != nil {
+			return err
+		}
+
+		r.zone = zone
+		return nil
+	}
+}
+
+// WithTTL overrides the 120-second default TTL (RFC 6762 §10) Register's
+// PTR/SRV/TXT records advertise. The A/AAAA records' own 4500-second
+// (75-minute) TTL is unaffected.
+//
+// ttl must be positive and no greater than 75 minutes - the longest TTL
+// this package otherwise advertises for any record (see
+// protocol.TTLHostname) - since a longer mDNS TTL works against the
+// protocol's assumption that multicast-local data stays fresh via frequent
+// reannouncement rather than long caching.
+//
+// Example:
+//
+//	r, err := New(ctx, WithTTL(30*time.Second))
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Responder) error {
+		if ttl <= 0 {
+			return &errors.ValidationError{
+				Field:   "ttl",
+				Value:   ttl,
+				Message: "ttl must be positive",
+				Code:    errors.CodeInvalidValue,
+			}
+		}
+		if ttl > protocol.TTLHostname*time.Second {
+			return &errors.ValidationError{
+				Field:   "ttl",
+				Value:   ttl,
+				Message: "ttl must not exceed 75 minutes (protocol.TTLHostname)",
+				Code:    errors.CodeInvalidValue,
+			}
+		}
+
+		r.serviceTTL = uint32(ttl / time.Second)
+		return nil
+	}
+}
+
+// WithUnicastResponse controls whether handleQuery honors a query's RFC
+// 6762 §5.4 QU bit, replying via unicast directly to the querier that set
+// it rather than always multicasting. Defaults to true.
+//
+// Example (always multicast, ignoring QU - matches pre-this-option
+// behavior):
+//
+//	r, err := New(ctx, WithUnicastResponse(false))
+func WithUnicastResponse(enabled bool) Option {
+	return func(r *Responder) error {
+		r.unicastResponseEnabled = enabled
+		return nil
+	}
+}
+
+// WithBeforeStart registers a hook run just before New() binds its mDNS
+// transport. Multiple WithBeforeStart options chain in registration order;
+// the first to return an error aborts New() with that error, and no later
+// BeforeStart hook, nor the transport bind itself, runs. This mirrors
+// go-micro's web.Options lifecycle hooks, letting an embedder open an
+// ancillary resource (e.g. an admin HTTP endpoint, a metrics sink) whose
+// own failure should prevent the responder from starting at all.
+//
+// Example:
+//
+//	r, err := New(ctx, WithBeforeStart(metricsSink.Open))
+func WithBeforeStart(hook func() error) Option {
+	return func(r *Responder) error {
+		if hook == nil {
+			return &errors.ValidationError{
+				Field:   "hook",
+				Value:   hook,
+				Message: "hook cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+		r.beforeStart = append(r.beforeStart, hook)
+		return nil
+	}
+}
+
+// WithAfterStart registers a hook run just after New() has bound its
+// transport and started the query handler. Multiple WithAfterStart options
+// chain in registration order; the first to return an error tears the
+// transport back down and makes New() return that error.
+//
+// Example:
+//
+//	r, err := New(ctx, WithAfterStart(systemdNotifier.Ready))
+func WithAfterStart(hook func() error) Option {
+	return func(r *Responder) error {
+		if hook == nil {
+			return &errors.ValidationError{
+				Field:   "hook",
+				Value:   hook,
+				Message: "hook cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+		r.afterStart = append(r.afterStart, hook)
+		return nil
+	}
+}
+
+// WithBeforeStop registers a hook run at the very start of Close or
+// Shutdown, before the query handler stops, goodbye packets go out, or the
+// transport closes. Multiple WithBeforeStop options chain in registration
+// order; the first to return an error aborts the stop entirely, leaving the
+// responder running, and Close/Shutdown returns that error.
+//
+// Example:
+//
+//	r, err := New(ctx, WithBeforeStop(metricsSink.Drain))
+func WithBeforeStop(hook func() error) Option {
+	return func(r *Responder) error {
+		if hook == nil {
+			return &errors.ValidationError{
+				Field:   "hook",
+				Value:   hook,
+				Message: "hook cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+		r.beforeStop = append(r.beforeStop, hook)
+		return nil
+	}
+}
+
+// WithAfterStop registers a hook run once Close or Shutdown has finished
+// tearing the responder down (goodbyes sent, transport closed). Multiple
+// WithAfterStop options chain in registration order, stopping at the first
+// error; whatever error results is joined (errors.Join) with
+// Close/Shutdown's own return value rather than replacing it.
+//
+// Example:
+//
+//	r, err := New(ctx, WithAfterStop(metricsSink.Close))
+func WithAfterStop(hook func() error) Option {
+	return func(r *Responder) error {
+		if hook == nil {
+			return &errors.ValidationError{
+				Field:   "hook",
+				Value:   hook,
+				Message: "hook cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+		r.afterStop = append(r.afterStop, hook)
+		return nil
+	}
+}
+
+// WithRegisterCheck registers check to run inside Register, right after
+// service validation but before any probe traffic goes out - a failing check
+// aborts registration entirely, analogous to go-micro's RegisterCheck. If
+// WithRegisterCheckInterval is also given, Register additionally starts a
+// background goroutine that re-runs check on that interval for as long as
+// the service stays registered: a failing check sends a goodbye and removes
+// the service from the registry until a later check succeeds, at which
+// point it's re-probed and re-announced automatically. Without
+// WithRegisterCheckInterval, check only ever runs once, at registration
+// time.
+//
+// Example:
+//
+//	r, err := New(ctx,
+//	    WithRegisterCheck(readiness.Check),
+//	    WithRegisterCheckInterval(10*time.Second),
+//	)
+func WithRegisterCheck(check func(ctx context.Context, service *Service) error) Option {
+	return func(r *Responder) error {
+		if check == nil {
+			return &errors.ValidationError{
+				Field:   "check",
+				Value:   check,
+				Message: "check cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+		r.registerCheck = check
+		return nil
+	}
+}
+
+// WithRegisterCheckInterval sets how often the monitor started by
+// WithRegisterCheck re-runs its check for each registered service. Without
+// this option, that check only runs once, at registration time.
+//
+// Example:
+//
+//	r, err := New(ctx, WithRegisterCheck(readiness.Check), WithRegisterCheckInterval(10*time.Second))
+func WithRegisterCheckInterval(interval time.Duration) Option {
+	return func(r *Responder) error {
+		if interval <= 0 {
+			return &errors.ValidationError{
+				Field:   "interval",
+				Value:   interval,
+				Message: "interval must be positive",
+				Code:    errors.CodeInvalidValue,
+			}
+		}
+		r.registerCheckInterval = interval
+		return nil
+	}
+}
+
+// WithSignal controls whether New installs a SIGINT/SIGTERM handler that
+// calls Shutdown, matching go-micro's web.Options default of true. Pass
+// WithSignal(false) for a library user that runs its own signal loop and
+// wants to call Shutdown itself.
+//
+// Example:
+//
+//	r, err := New(ctx, WithSignal(false))
+func WithSignal(enabled bool) Option {
+	return func(r *Responder) error {
+		r.signalEnabled = enabled
+		return nil
+	}
+}
+
+// WithShutdownTimeout is an alias for WithLameDuckTimeout, named to match
+// WithSignal's vocabulary: it bounds how long the Shutdown started by
+// WithSignal's SIGINT/SIGTERM handler waits for every registered service's
+// goodbye packets to flush before force-closing the transport. Both options
+// set the same underlying deadline.
+//
+// Example:
+//
+//	r, err := New(ctx, WithShutdownTimeout(10*time.Second))
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return WithLameDuckTimeout(timeout)
+}
+
+// WithProbe wires p into the Responder so Register/Unregister update it as
+// each service moves through Probing/Announcing (probe.StatusPreparing),
+// Established (probe.StatusRunning), a failed or abandoned registration
+// (probe.StatusFailed), or Unregister/Close/Shutdown (probe.StatusStopped),
+// keyed by the same "Instance._type.local" name used elsewhere. The
+// transport itself is tracked the same way under a reserved component name,
+// so p.Handler()'s /healthz and /readyz reflect both service and transport
+// state.
+//
+// Example:
+//
+//	p := probe.New()
+//	r, err := New(ctx, WithProbe(p))
+//	go http.ListenAndServe(":8080", p.Handler())
+func WithProbe(p *probe.Probe) Option {
+	return func(r *Responder) error {
+		if p == nil {
+			return &errors.ValidationError{
+				Field:   "probe",
+				Value:   p,
+				Message: "probe cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+		r.probe = p
+		return nil
+	}
+}
+
+// WithLogger registers logger to receive one structured log record per
+// conflict/probe/rename/announce/goodbye event, via the same EventHook
+// mechanism AddEventHook exposes (see SlogEventHook) - a constructor-time
+// convenience for the common case of "just log everything to this logger"
+// instead of calling AddEventHook(NewSlogEventHook(logger)) separately.
+//
+// Example:
+//
+//	r, err := New(ctx, WithLogger(slog.Default()))
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *Responder) error {
+		if logger == nil {
+			return &errors.ValidationError{
+				Field:   "logger",
+				Value:   logger,
+				Message: "logger cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+
+		r.hooks = append(r.hooks, NewSlogEventHook(logger))
+		r.logger = logger
+		return nil
+	}
+}
+
+// WithMetrics installs m as the Responder's metrics.Metrics sink, so a
+// panic recovered from a packet-processing or background goroutine (see
+// PanicHandler) increments beacon_handler_panics_total on it instead of
+// being discarded.
+//
+// Default: metrics.NoOp{} - no metrics are collected unless a caller opts
+// in.
+func WithMetrics(m metrics.Metrics) Option {
+	return func(r *Responder) error {
+		if m == nil {
+			return &errors.ValidationError{
+				Field:   "metrics",
+				Value:   nil,
+				Message: "metrics cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+
+		r.metrics = m
+		r.responseBuilder = r.responseBuilder.WithMetrics(m)
+		return nil
+	}
+}
+
+// WithMetricsNamespace wraps the Responder's current metrics.Metrics sink
+// (WithMetrics's m, or metrics.NoOp{} by default) in an internal/metrics
+// decorator that prefixes every metric name this Responder reports with
+// namespace + "_" - e.g. WithMetricsNamespace("eth0") turns
+// "beacon_handler_panics_total" into "eth0_beacon_handler_panics_total" -
+// so multiple Responders sharing one process's metrics backend (e.g. one
+// per network interface) produce distinguishable series.
+//
+// Like WithLogger/WithLogBuffer, this is order-sensitive: apply it after
+// WithMetrics, since a WithMetrics call after this one replaces the
+// decorator wholesale rather than wrapping it further. Composes with
+// WithConstLabels in either order - each wraps whatever sink the other left
+// in place.
+func WithMetricsNamespace(namespace string) Option {
+	return func(r *Responder) error {
+		r.metrics = internalmetrics.New(r.metrics, internalmetrics.WithNamespace(namespace))
+		r.responseBuilder = r.responseBuilder.WithMetrics(r.metrics)
+		return nil
+	}
+}
+
+// WithConstLabels wraps the Responder's current metrics.Metrics sink in an
+// internal/metrics decorator that merges labels into every metric this
+// Responder reports, underneath whatever labels the call site itself
+// passes - useful for tagging every series with e.g. {"instance": "eth0"}
+// without threading that label through every IncCounter/ObserveHistogram
+// call site by hand.
+//
+// See WithMetricsNamespace for the same ordering caveat relative to
+// WithMetrics.
+func WithConstLabels(labels map[string]string) Option {
+	return func(r *Responder) error {
+		r.metrics = internalmetrics.New(r.metrics, internalmetrics.WithConstLabels(labels))
+		r.responseBuilder = r.responseBuilder.WithMetrics(r.metrics)
+		return nil
+	}
+}
+
+// WithPanicHandler registers handler to be called, in addition to the
+// standard slog/metrics reporting, whenever the Responder recovers a panic
+// from a packet-processing or background goroutine (the query handler
+// loop, a Shutdown goodbye goroutine, a register-check monitor) - useful
+// for wiring in Sentry/OTel error tracking without patching the library.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(r *Responder) error {
+		if handler == nil {
+			return &errors.ValidationError{
+				Field:   "panicHandler",
+				Value:   nil,
+				Message: "panicHandler cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+
+		r.panicHandler = handler
+		return nil
+	}
+}
+
+// WithLogBuffer retains the last size log records this Responder emits
+// (via r.logger) in an in-memory ring buffer, available for post-mortem
+// inspection through Snapshot and DebugHandler, and automatically
+// replayed to r.logger at Error level whenever a panic is recovered or
+// Register hits a fatal probe/announce error - a "flight recorder" for
+// intermittent mDNS bugs that doesn't require running at Debug level in
+// production. A non-positive size falls back to logbuf.DefaultSize
+// (1000).
+//
+// WithLogBuffer wraps r.logger's handler as configured so far, so pass it
+// after WithLogger if both are used - an option applied afterward that
+// replaces r.logger wholesale (including a later WithLogger) undoes the
+// buffering.
+func WithLogBuffer(size int) Option {
+	return func(r *Responder) error {
+		buf := logbuf.NewBuffer(size)
+		r.logBuffer = buf
+		r.logBufferFlushLogger = r.logger
+		r.logger = slog.New(logbuf.Tee(buf, r.logger.Handler()))
+		return nil
+	}
+}
+
+// WithTap installs t as the Responder's tap.Tap, so every query it
+// receives and every response it sends is reported to t - independent of
+// the metrics/logger instrumentation above - for dnstap-style
+// observability. See the tap package for the SlogTap and FrameStreamTap
+// built-in implementations.
+//
+// Default: tap.NoOp{} - no events are reported unless a caller opts in.
+func WithTap(t tap.Tap) Option {
+	return func(r *Responder) error {
+		if t == nil {
+			return &errors.ValidationError{
+				Field:   "tap",
+				Value:   nil,
+				Message: "tap cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+
+		r.tap = t
+		return nil
+	}
+}
+
+// WithWatchInterfaces enables a background watcher that polls for network
+// interface changes (Wi-Fi reassociating, a VPN coming up, a cable being
+// unplugged) every few seconds for the lifetime of the Responder, mirroring
+// querier.WithWatchInterfaces.
+//
+// Without this, interfaces are enumerated once when a service is registered
+// (via getLocalIPv4/responderIPv6Addresses) and the multicast group is
+// joined once in New(): a laptop that switches from Ethernet to Wi-Fi
+// mid-session keeps answering through whatever interface looked usable at
+// construction time, and peers on the new interface never see it until
+// something else prompts a query.
+//
+// When enabled, a newly-up interface (already filtered through the same
+// VPN/Docker/loopback exclusion DefaultInterfaces applies) has its
+// multicast membership joined and every registered service re-announced
+// per RFC 6762 §8.4; a removed interface has its membership left. A
+// transport that doesn't track per-interface membership (anything other
+// than the single-family UDP transports) only gets the re-announcement.
+//
+// Default: disabled, since the extra goroutine is wasted on a Responder
+// that doesn't outlive a single network state.
+func WithWatchInterfaces(enabled bool) Option {
+	return func(r *Responder) error {
+		r.watchInterfaces = enabled
+		return nil
+	}
+}
+
+// WithRegistry replaces the Responder's default in-memory service
+// registry with reg, typically a fleet-aware backend such as
+// NewEtcdRegistry or NewGossipRegistry from the internal/responder
+// package, so a Responder can answer queries for services registered by
+// peer hosts in addition to its own.
+//
+// Default: responder.NewRegistry() - a process-local store.
+func WithRegistry(reg responder.Registry) Option {
+	return func(r *Responder) error {
+		if reg == nil {
+			return &errors.ValidationError{
+				Field:   "registry",
+				Value:   nil,
+				Message: "registry cannot be nil",
+				Code:    errors.CodeNilDependency,
+			}
+		}
+
+		r.registry = reg
+		return nil
+	}
+}
@@ -0,0 +1,124 @@
+package responder
+
+import (
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/state"
+)
+
+// probeConflictWindow and probeConflictThreshold implement RFC 6762 §8.1's
+// probe-storm cutoff: "if the number of probe conflicts in a given
+// probing session is 15 or more within any ten-second period, it should
+// impose a random delay of between 2 and 5 seconds before continuing."
+// beacon rounds that to a flat 5s floor (see probeCooldownBackoff) rather
+// than the 2-5s jittered range, since the backoff's own Jitter field
+// already randomizes it some.
+const (
+	probeConflictWindow    = 10 * time.Second
+	probeConflictThreshold = 15
+)
+
+// probeNormalInterval is NextProbeDelay's return value before a name has
+// tripped probeConflictThreshold - the same 250ms spacing Prober.Probe
+// already uses between its own three probe queries, for a caller pacing
+// attempts between probe *sessions* (e.g. Register's rename loop) rather
+// than between queries within one.
+const probeNormalInterval = 250 * time.Millisecond
+
+// probeCooldownBackoff grows the RFC 6762 §8.1 cutoff's delay on each
+// consecutive trip for the same name, so a peer that keeps conflicting
+// past the first cooldown doesn't get re-probed every five seconds
+// forever.
+var probeCooldownBackoff = state.BackoffConfig{
+	BaseDelay: 5 * time.Second,
+	Factor:    2,
+	MaxDelay:  60 * time.Second,
+}
+
+// probeHistory is one name's sliding window of recent conflict timestamps,
+// plus how many times in a row that window has tripped the cutoff.
+type probeHistory struct {
+	conflicts    []time.Time
+	cooldownTrip int
+}
+
+// ProbeRateLimiter enforces RFC 6762 §8.1's probe-conflict cutoff: if 15 or
+// more conflicts are recorded against an instance name within a 10-second
+// window, NextProbeDelay switches from the normal 250ms probe spacing to
+// an exponentially growing cooldown starting at 5 seconds, on the theory
+// that a peer conflicting this persistently is pathological rather than a
+// one-off simultaneous probe race (RFC 6762 §8.2).
+//
+// State is keyed by instance name and is safe for concurrent use; a
+// zero-value ProbeRateLimiter is not usable, use NewProbeRateLimiter.
+type ProbeRateLimiter struct {
+	mu      sync.Mutex
+	history map[string]*probeHistory
+}
+
+// NewProbeRateLimiter creates an empty ProbeRateLimiter.
+func NewProbeRateLimiter() *ProbeRateLimiter {
+	return &ProbeRateLimiter{history: make(map[string]*probeHistory)}
+}
+
+// RecordConflict records that name's probe was just conflicted against,
+// for NextProbeDelay's sliding-window count.
+func (p *ProbeRateLimiter) RecordConflict(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h := p.history[name]
+	if h == nil {
+		h = &probeHistory{}
+		p.history[name] = h
+	}
+	h.conflicts = append(h.conflicts, time.Now())
+}
+
+// NextProbeDelay returns how long to wait before name's next probe:
+// probeNormalInterval ordinarily, or probeCooldownBackoff's next delay once
+// probeConflictThreshold conflicts have landed within probeConflictWindow.
+// Conflicts older than the window are pruned here (not just in
+// RecordConflict) so a name that stops conflicting ages back out of
+// cooldown once its window empties, resetting cooldownTrip.
+func (p *ProbeRateLimiter) NextProbeDelay(name string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h := p.history[name]
+	if h == nil {
+		return probeNormalInterval
+	}
+
+	h.conflicts = pruneConflictsBefore(h.conflicts, time.Now().Add(-probeConflictWindow))
+	if len(h.conflicts) < probeConflictThreshold {
+		h.cooldownTrip = 0
+		return probeNormalInterval
+	}
+
+	delay := probeCooldownBackoff.Delay(h.cooldownTrip)
+	h.cooldownTrip++
+	return delay
+}
+
+// pruneConflictsBefore drops every timestamp at or before cutoff from ts,
+// reusing ts's backing array since RecordConflict always appends, so ts is
+// already in chronological order.
+func pruneConflictsBefore(ts []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(ts) && !ts[i].After(cutoff) {
+		i++
+	}
+	return ts[i:]
+}
+
+// Reset clears name's conflict history, for a successful probe claim: RFC
+// 6762 §8.1's cutoff only matters while a name keeps losing, so once it
+// wins (or Register gives up and the name is abandoned) past conflicts
+// shouldn't count against whatever probes that name next.
+func (p *ProbeRateLimiter) Reset(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.history, name)
+}
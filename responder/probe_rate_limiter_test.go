@@ -0,0 +1,143 @@
+package responder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestProbeRateLimiter_NormalInterval verifies a name with no recorded
+// conflicts gets the plain RFC 6762 §8.1 250ms probe spacing.
+func TestProbeRateLimiter_NormalInterval(t *testing.T) {
+	p := NewProbeRateLimiter()
+
+	if got := p.NextProbeDelay("My Printer"); got != probeNormalInterval {
+		t.Errorf("NextProbeDelay() = %v, want %v", got, probeNormalInterval)
+	}
+}
+
+// TestProbeRateLimiter_BurstTripsCooldown verifies that once
+// probeConflictThreshold conflicts land within probeConflictWindow,
+// NextProbeDelay switches from the normal 250ms spacing to
+// probeCooldownBackoff's cooldown.
+func TestProbeRateLimiter_BurstTripsCooldown(t *testing.T) {
+	p := NewProbeRateLimiter()
+
+	for i := 0; i < probeConflictThreshold; i++ {
+		p.RecordConflict("Pathological Peer")
+	}
+
+	got := p.NextProbeDelay("Pathological Peer")
+	if got < probeCooldownBackoff.BaseDelay {
+		t.Errorf("NextProbeDelay() after %d conflicts = %v, want >= %v (cooldown)", probeConflictThreshold, got, probeCooldownBackoff.BaseDelay)
+	}
+}
+
+// TestProbeRateLimiter_BelowThresholdStaysNormal verifies that fewer than
+// probeConflictThreshold conflicts within the window don't trip the
+// cutoff.
+func TestProbeRateLimiter_BelowThresholdStaysNormal(t *testing.T) {
+	p := NewProbeRateLimiter()
+
+	for i := 0; i < probeConflictThreshold-1; i++ {
+		p.RecordConflict("Occasional Conflict")
+	}
+
+	if got := p.NextProbeDelay("Occasional Conflict"); got != probeNormalInterval {
+		t.Errorf("NextProbeDelay() after %d conflicts = %v, want %v", probeConflictThreshold-1, got, probeNormalInterval)
+	}
+}
+
+// TestProbeRateLimiter_SlidingWindowRollover verifies that conflicts aged
+// past probeConflictWindow no longer count toward the threshold - a name
+// that stops conflicting should fall back out of cooldown.
+func TestProbeRateLimiter_SlidingWindowRollover(t *testing.T) {
+	p := NewProbeRateLimiter()
+
+	for i := 0; i < probeConflictThreshold; i++ {
+		p.RecordConflict("Once Pathological")
+	}
+
+	// White-box: backdate every recorded conflict past the window, as if
+	// they'd all happened - and then stopped - over ten seconds ago.
+	p.mu.Lock()
+	h := p.history["Once Pathological"]
+	for i := range h.conflicts {
+		h.conflicts[i] = time.Now().Add(-probeConflictWindow - time.Second)
+	}
+	p.mu.Unlock()
+
+	if got := p.NextProbeDelay("Once Pathological"); got != probeNormalInterval {
+		t.Errorf("NextProbeDelay() after window rollover = %v, want %v", got, probeNormalInterval)
+	}
+}
+
+// TestProbeRateLimiter_CooldownGrowsOnRepeatedTrips verifies consecutive
+// cooldown trips for the same name back off further each time, per
+// probeCooldownBackoff's exponential growth.
+func TestProbeRateLimiter_CooldownGrowsOnRepeatedTrips(t *testing.T) {
+	p := NewProbeRateLimiter()
+
+	for i := 0; i < probeConflictThreshold; i++ {
+		p.RecordConflict("Repeat Offender")
+	}
+
+	first := p.NextProbeDelay("Repeat Offender")
+
+	// The window hasn't emptied (no time has actually passed), so the next
+	// call still sees >= threshold conflicts and should back off further.
+	second := p.NextProbeDelay("Repeat Offender")
+
+	if second <= first {
+		t.Errorf("second cooldown (%v) <= first (%v), want growth on repeated trips", second, first)
+	}
+}
+
+// TestProbeRateLimiter_Reset verifies Reset clears a name's history, so it
+// starts back at the normal interval.
+func TestProbeRateLimiter_Reset(t *testing.T) {
+	p := NewProbeRateLimiter()
+
+	for i := 0; i < probeConflictThreshold; i++ {
+		p.RecordConflict("Claimed Eventually")
+	}
+	p.Reset("Claimed Eventually")
+
+	if got := p.NextProbeDelay("Claimed Eventually"); got != probeNormalInterval {
+		t.Errorf("NextProbeDelay() after Reset() = %v, want %v", got, probeNormalInterval)
+	}
+}
+
+// TestResponder_Register_ProbeRateLimiterResetsOnSuccess drives Register
+// through the real StateConflictDetected path (via InjectProbeConflicts,
+// which forces the state machine's own tie-breaking result rather than
+// bypassing it) and verifies probeRateLimiter's history for the service's
+// original name is cleared once registration finally succeeds.
+func TestResponder_Register_ProbeRateLimiterResetsOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	r, err := New(ctx)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	r.InjectProbeConflicts(2)
+
+	service := &Service{
+		InstanceName: "Rate Limited Printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}
+
+	if err := r.Register(service); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	r.probeRateLimiter.mu.Lock()
+	_, stillTracked := r.probeRateLimiter.history["Rate Limited Printer"]
+	r.probeRateLimiter.mu.Unlock()
+
+	if stillTracked {
+		t.Error("probeRateLimiter still tracks the original name after a successful Register(), want Reset")
+	}
+}
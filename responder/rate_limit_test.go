@@ -0,0 +1,112 @@
+package responder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
+	internalresponder "github.com/joshuafuller/beacon/internal/responder"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// waitForSendCalls polls mock until it has at least want SendCalls recorded,
+// or deadline elapses - the same pattern
+// TestResponder_HandleQuery_WithTransports_AnswersViaOriginatingPlugin uses
+// to tolerate sendQueryResponse's randomized shared-record delay.
+func waitForSendCalls(mock *transport.MockTransport, want int, deadline time.Duration) []transport.SendCall {
+	until := time.Now().Add(deadline)
+	var sent []transport.SendCall
+	for time.Now().Before(until) {
+		sent = mock.SendCalls()
+		if len(sent) >= want {
+			return sent
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return sent
+}
+
+// TestHandleQuery_RateLimitsRepeatedMulticastResponses drives a Responder
+// with the same query three times: the first response goes out, an
+// immediate repeat is suppressed per RFC 6762 §6.2 (the record was already
+// multicast within its bucket's interval), and a third repeat after that
+// interval elapses goes out again. r.recordSet is swapped for a fast
+// RateLimitConfig so the test doesn't have to wait out the real 1-second
+// default.
+func TestHandleQuery_RateLimitsRepeatedMulticastResponses(t *testing.T) {
+	mock := transport.NewMockTransport()
+
+	var mu sync.Mutex
+	var suppressedCount, allowedCount int
+
+	r, err := New(context.Background(), WithTransport(mock), WithRateLimitObserver(
+		func(_ *message.ResourceRecord, suppressed bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			if suppressed {
+				suppressedCount++
+			} else {
+				allowedCount++
+			}
+		}))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	r.recordSet = records.NewRecordSetWithConfig(records.RateLimitConfig{
+		MinInterval:   50 * time.Millisecond,
+		BurstCapacity: 1,
+		MaxEntries:    100,
+	})
+
+	if err := r.registry.Register(&internalresponder.Service{
+		InstanceName: "printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}); err != nil {
+		t.Fatalf("registry.Register() error = %v, want nil", err)
+	}
+
+	queryPacket, err := message.BuildQuery("_http._tcp.local", uint16(protocol.RecordTypePTR))
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v, want nil", err)
+	}
+
+	if err := r.handleQuery(queryPacket, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() #1 error = %v, want nil", err)
+	}
+	if sent := waitForSendCalls(mock, 1, time.Second); len(sent) != 1 {
+		t.Fatalf("SendCalls() after first query = %d, want 1", len(sent))
+	}
+
+	if err := r.handleQuery(queryPacket, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() #2 error = %v, want nil", err)
+	}
+	// Give a would-be (wrongly unsuppressed) second send time to land
+	// before asserting it didn't.
+	time.Sleep(150 * time.Millisecond)
+	if sent := mock.SendCalls(); len(sent) != 1 {
+		t.Fatalf("SendCalls() after immediate repeat = %d, want 1 (suppressed)", len(sent))
+	}
+
+	if err := r.handleQuery(queryPacket, nil, r.transport); err != nil {
+		t.Fatalf("handleQuery() #3 error = %v, want nil", err)
+	}
+	if sent := waitForSendCalls(mock, 2, time.Second); len(sent) != 2 {
+		t.Fatalf("SendCalls() after interval elapsed = %d, want 2", len(sent))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if suppressedCount == 0 {
+		t.Error("rate limit observer reported 0 suppressions, want at least 1")
+	}
+	if allowedCount == 0 {
+		t.Error("rate limit observer reported 0 allowed sends, want at least 1")
+	}
+}
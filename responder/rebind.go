@@ -0,0 +1,103 @@
+package responder
+
+import (
+	"github.com/joshuafuller/beacon/internal/network"
+	"github.com/joshuafuller/beacon/internal/state"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// rebinder is satisfied by *transport.UDPv4Transport and
+// *transport.UDPv6Transport, the only Transport implementations that track
+// per-interface multicast membership. A DualStack or caller-supplied
+// transport (WithTransport isn't exposed on Responder today, but a future
+// one would behave the same) that doesn't implement it is simply left
+// alone by watchInterfaceChanges, same as querier's analogous glue.
+type rebinder interface {
+	Rebind(events <-chan transport.RebindEvent)
+}
+
+// startWatchingInterfaces starts a network.InterfaceWatcher and a goroutine
+// that reacts to the InterfaceUp/InterfaceDown events it reports: joining
+// or leaving r.transport's multicast membership for the affected interface,
+// and - for InterfaceUp - re-announcing every currently registered service
+// per RFC 6762 §8.4 ("Whenever a Multicast DNS responder receives any
+// Multicast DNS response... it SHOULD perform the appropriate steps"),
+// since a newly-up interface is exactly the kind of network change that
+// section has in mind.
+func (r *Responder) startWatchingInterfaces() error {
+	watcher := network.NewInterfaceWatcher()
+	if err := watcher.Start(); err != nil {
+		return err
+	}
+	r.ifaceWatcher = watcher
+
+	var rebindCh chan transport.RebindEvent
+	if rb, ok := r.transport.(rebinder); ok {
+		rebindCh = make(chan transport.RebindEvent)
+		go rb.Rebind(rebindCh)
+	}
+
+	go r.watchInterfaceChanges(watcher.Changes(), rebindCh)
+	return nil
+}
+
+// watchInterfaceChanges drains changes until it's closed (ifaceWatcher.Stop()
+// does this, at which point this goroutine and the Rebind goroutine
+// consuming rebindCh both exit). rebindCh is nil when r.transport doesn't
+// implement rebinder, in which case interface changes still trigger
+// re-announcement but never a join/leave. Taking changes as a parameter
+// rather than reading r.ifaceWatcher directly keeps this testable without a
+// real network.InterfaceWatcher.
+func (r *Responder) watchInterfaceChanges(changes <-chan network.InterfaceChange, rebindCh chan<- transport.RebindEvent) {
+	defer func() {
+		if rebindCh != nil {
+			close(rebindCh)
+		}
+	}()
+
+	for change := range changes {
+		switch change.Type {
+		case network.InterfaceUp:
+			r.sendRebind(rebindCh, transport.RebindEvent{Type: transport.RebindJoin, Interface: change.Interface})
+			r.reannounceServices()
+		case network.InterfaceDown:
+			r.sendRebind(rebindCh, transport.RebindEvent{Type: transport.RebindLeave, Interface: change.Interface})
+		default:
+			// AddressAdded/AddressRemoved don't change which interfaces are
+			// joined, only which addresses they carry - nothing to do here.
+		}
+	}
+}
+
+// sendRebind forwards ev to rebindCh, unless rebindCh is nil (no rebindable
+// transport) or r.ctx ends first.
+func (r *Responder) sendRebind(rebindCh chan<- transport.RebindEvent, ev transport.RebindEvent) {
+	if rebindCh == nil {
+		return
+	}
+	select {
+	case rebindCh <- ev:
+	case <-r.ctx.Done():
+	}
+}
+
+// reannounceServices re-sends an RFC 6762 §8.3 announcement (two unsolicited
+// multicasts, one second apart) for every currently established service, via
+// the same state.Machine/Announcer each was originally registered through.
+// Failures are logged rather than returned - watchInterfaceChanges has no
+// caller to report them to, and one service's failed re-announcement
+// shouldn't stop the others from going out.
+func (r *Responder) reannounceServices() {
+	r.machinesMu.Lock()
+	machines := make(map[string]*state.Machine, len(r.machines))
+	for name, machine := range r.machines {
+		machines[name] = machine
+	}
+	r.machinesMu.Unlock()
+
+	for name, machine := range machines {
+		if err := machine.GetAnnouncer().Announce(r.ctx, name, nil); err != nil {
+			r.logger.Warn("re-announce after interface change failed", "service", name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package responder
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/network"
+	"github.com/joshuafuller/beacon/internal/state"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestWatchInterfaceChanges_TranslatesUpDownAndSkipsAddressEvents verifies
+// InterfaceUp/InterfaceDown become RebindJoin/RebindLeave on rebindCh, while
+// AddressAdded/AddressRemoved (which don't change interface membership) are
+// not forwarded.
+func TestWatchInterfaceChanges_TranslatesUpDownAndSkipsAddressEvents(t *testing.T) {
+	r := &Responder{ctx: context.Background(), machines: map[string]*state.Machine{}}
+
+	eth0 := net.Interface{Name: "eth0"}
+	changes := make(chan network.InterfaceChange, 4)
+	changes <- network.InterfaceChange{Type: network.InterfaceUp, Interface: eth0}
+	changes <- network.InterfaceChange{Type: network.AddressAdded, Interface: eth0}
+	changes <- network.InterfaceChange{Type: network.InterfaceDown, Interface: eth0}
+	close(changes)
+
+	rebindCh := make(chan transport.RebindEvent, 4)
+	r.watchInterfaceChanges(changes, rebindCh)
+
+	var got []transport.RebindEvent
+	for ev := range rebindCh {
+		got = append(got, ev)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d RebindEvents, want 2 (AddressAdded should be skipped): %+v", len(got), got)
+	}
+	if got[0].Type != transport.RebindJoin || got[0].Interface.Name != "eth0" {
+		t.Errorf("got[0] = %+v, want RebindJoin for eth0", got[0])
+	}
+	if got[1].Type != transport.RebindLeave || got[1].Interface.Name != "eth0" {
+		t.Errorf("got[1] = %+v, want RebindLeave for eth0", got[1])
+	}
+}
+
+// TestWatchInterfaceChanges_NilRebindChStillReannounces verifies a
+// Responder whose transport doesn't implement rebinder (rebindCh is nil)
+// still re-announces on InterfaceUp instead of panicking on a nil send.
+func TestWatchInterfaceChanges_ReannouncesOnInterfaceUp(t *testing.T) {
+	clock := state.NewFakeClock()
+	machine := state.NewMachine()
+	machine.SetClock(clock)
+
+	var sendCount int
+	var mu sync.Mutex
+	machine.GetAnnouncer().SetOnSendAnnouncement(func() {
+		mu.Lock()
+		sendCount++
+		mu.Unlock()
+	})
+
+	r := &Responder{
+		ctx:      context.Background(),
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		machines: map[string]*state.Machine{"My Service._http._tcp.local": machine},
+	}
+
+	changes := make(chan network.InterfaceChange, 1)
+	changes <- network.InterfaceChange{Type: network.InterfaceUp, Interface: net.Interface{Name: "eth0"}}
+	close(changes)
+
+	done := make(chan struct{})
+	go func() {
+		r.watchInterfaceChanges(changes, nil)
+		close(done)
+	}()
+
+	// Announce sends twice, 1s apart per RFC 6762 §8.3 - advance the fake
+	// clock past both waits instead of sleeping for real.
+	deadline := time.After(2 * time.Second)
+	for {
+		mu.Lock()
+		count := sendCount
+		mu.Unlock()
+		if count >= 2 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("Announce sent %d messages before timeout, want 2", count)
+		default:
+			if clock.Waiters() > 0 {
+				clock.Advance(1 * time.Second)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	<-done
+}
+
+// TestResponder_WithWatchInterfaces verifies the option actually starts
+// r.ifaceWatcher, and that a Responder built without it leaves
+// r.ifaceWatcher nil - mirroring querier.TestQuerier_WithWatchInterfaces.
+func TestResponder_WithWatchInterfaces(t *testing.T) {
+	ctx := context.Background()
+
+	plain, err := New(ctx)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer func() { _ = plain.Close() }()
+
+	if plain.ifaceWatcher != nil {
+		t.Error("ifaceWatcher non-nil without WithWatchInterfaces")
+	}
+
+	watching, err := New(ctx, WithWatchInterfaces(true))
+	if err != nil {
+		t.Fatalf("New(WithWatchInterfaces(true)) failed: %v", err)
+	}
+	defer func() { _ = watching.Close() }()
+
+	if watching.ifaceWatcher == nil {
+		t.Error("ifaceWatcher nil with WithWatchInterfaces(true)")
+	}
+}
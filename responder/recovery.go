@@ -0,0 +1,88 @@
+package responder
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+
+	"github.com/joshuafuller/beacon/metrics"
+)
+
+// PanicHandler is invoked, in addition to Responder's standard slog/metrics
+// reporting, whenever recoverPanic recovers a panic from a packet-
+// processing or background goroutine. recovered is the value passed to
+// panic; stack is the stack trace captured at the point of recovery
+// (runtime/debug.Stack()). Set via WithPanicHandler to wire in Sentry/OTel
+// error tracking without patching the library.
+type PanicHandler func(recovered any, stack []byte)
+
+// recoverPanic recovers a panic in the calling goroutine, if any, and
+// reports it via reportRecoveredPanic. Call it via defer as the first
+// deferred statement in any goroutine that processes attacker-controlled
+// packet data, so a single malformed packet can't crash a long-running
+// Responder - analogous to a gRPC recovery interceptor. handlerName
+// identifies the call site (e.g. "handleQuery") for the log record and the
+// beacon_handler_panics_total counter; src, if non-nil, is the packet's
+// source address.
+func (r *Responder) recoverPanic(handlerName string, src net.Addr) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	r.reportRecoveredPanic(handlerName, src, rec, debug.Stack())
+}
+
+// reportRecoveredPanic logs rec and stack via r's logger, counts it against
+// r's metrics, and invokes r.panicHandler if set. Shared by recoverPanic
+// (the defer-and-recover-in-one-step case) and call sites like
+// runRegisterCheckSafely that need to recover in their own defer (to also
+// turn the panic into a returned error) but still want the same reporting.
+func (r *Responder) reportRecoveredPanic(handlerName string, src net.Addr, rec any, stack []byte) {
+	attrs := []any{"handler", handlerName, "panic", fmt.Sprint(rec)}
+	if src != nil {
+		attrs = append(attrs, "source", src.String())
+	}
+	attrs = append(attrs, "stack", string(stack))
+	r.panicLogger().Error("recovered from panic in mDNS handler goroutine", attrs...)
+
+	r.panicMetrics().IncCounter("beacon_handler_panics_total", map[string]string{"handler": handlerName})
+
+	r.flushLogBuffer()
+	r.invokePanicHandler(rec, stack)
+}
+
+// invokePanicHandler calls r.panicHandler, if set, guarding against a panic
+// inside the user-supplied handler itself - otherwise a buggy handler would
+// propagate an unrecovered panic out of the very goroutine this recovery
+// subsystem exists to protect.
+func (r *Responder) invokePanicHandler(rec any, stack []byte) {
+	if r.panicHandler == nil {
+		return
+	}
+	defer func() {
+		if handlerPanic := recover(); handlerPanic != nil {
+			r.panicLogger().Error("panicHandler itself panicked",
+				"panic", fmt.Sprint(handlerPanic), "stack", string(debug.Stack()))
+		}
+	}()
+	r.panicHandler(rec, stack)
+}
+
+// panicLogger returns r.logger, falling back to slog.Default() for a
+// Responder built without New() (whose logger field is still nil).
+func (r *Responder) panicLogger() *slog.Logger {
+	if r.logger == nil {
+		return slog.Default()
+	}
+	return r.logger
+}
+
+// panicMetrics returns r.metrics, falling back to metrics.NoOp{} for a
+// Responder built without New() (whose metrics field is still nil).
+func (r *Responder) panicMetrics() metrics.Metrics {
+	if r.metrics == nil {
+		return metrics.NoOp{}
+	}
+	return r.metrics
+}
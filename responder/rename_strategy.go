@@ -0,0 +1,173 @@
+package responder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+)
+
+// RenameStrategy picks the next candidate name for a Service after a
+// probing conflict, per RFC 6762 §9's "...typically by appending the digit
+// '2' ... and incrementing for subsequent conflicts" - generalized so a
+// deployment can swap in a different renaming convention: a MAC-derived
+// hash suffix that survives reboots (HashSuffixResolver), a user-supplied
+// callback (FuncResolver, e.g. "MyPrinter on office-2"), or randomized
+// suffixes to avoid a thundering herd of identical devices all renaming
+// the same way at once.
+type RenameStrategy interface {
+	// Resolve returns the next name to probe for a service whose current
+	// name is current, after its attempt'th conflict (1 for the first
+	// conflict seen for this Service, incrementing by one per subsequent
+	// conflict).
+	Resolve(current string, attempt int) string
+
+	// SuffixLen reports how many trailing bytes of renamed (a value just
+	// returned by Resolve) are the suffix it appended, so Service.Rename's
+	// 63-octet truncation can shorten the base name without cutting into
+	// the suffix. A strategy with no well-defined suffix (e.g. a fully
+	// free-form FuncResolver) can return 0, which truncates renamed as a
+	// whole instead.
+	SuffixLen(renamed string) int
+}
+
+// numericSuffixPattern matches the "-N" suffix NumericSuffixResolver uses,
+// e.g. "My Service-2", "Printer-10".
+var numericSuffixPattern = regexp.MustCompile(`^(.+)-(\d+)$`)
+
+// NumericSuffixResolver is the RFC 6762 §9 default renaming convention:
+// appends "-2" on the first conflict, then increments the trailing number
+// on each subsequent one ("My Service" -> "My Service-2" -> "My Service-3").
+type NumericSuffixResolver struct{}
+
+// Resolve implements RenameStrategy. attempt is ignored: the next suffix is
+// always derived from whatever suffix (if any) is already present on
+// current, so repeated calls stay idempotent regardless of attempt
+// bookkeeping.
+func (NumericSuffixResolver) Resolve(current string, _ int) string {
+	if matches := numericSuffixPattern.FindStringSubmatch(current); matches != nil {
+		// Error is impossible because the regex ensures matches[2] contains only digits.
+		suffix, _ := strconv.Atoi(matches[2]) // nosemgrep: beacon-error-swallowing
+		return fmt.Sprintf("%s-%d", matches[1], suffix+1)
+	}
+	return current + "-2"
+}
+
+// SuffixLen implements RenameStrategy.
+func (NumericSuffixResolver) SuffixLen(renamed string) int {
+	matches := numericSuffixPattern.FindStringSubmatch(renamed)
+	if matches == nil {
+		return 0
+	}
+	return len(renamed) - len(matches[1])
+}
+
+// hashSuffixPattern matches the "-xxxxxxxx" suffix HashSuffixResolver uses:
+// a hyphen followed by 8 lowercase hex digits.
+var hashSuffixPattern = regexp.MustCompile(`^(.*)-[0-9a-f]{8}$`)
+
+// HashSuffixResolver derives a suffix from a stable per-device identifier
+// (typically the host's MAC address) so a machine that reboots and
+// re-probes the same configured name lands on the same renamed name it
+// used before, rather than climbing to a new numeric suffix every time it
+// finds its own stale record still sitting on the LAN.
+type HashSuffixResolver struct {
+	// ID seeds the hash, e.g. a host's MAC address via
+	// net.Interface.HardwareAddr. Any stable per-device byte string works.
+	ID []byte
+}
+
+// NewHashSuffixResolver creates a HashSuffixResolver seeded by id (e.g. a
+// MAC address).
+func NewHashSuffixResolver(id []byte) *HashSuffixResolver {
+	return &HashSuffixResolver{ID: id}
+}
+
+// Resolve implements RenameStrategy. The suffix is derived from current's
+// base name, ID, and attempt: attempt resets to 1 each run, so the first
+// conflict after a reboot always proposes the same name, while repeated
+// conflicts within one run still advance to a fresh suffix.
+func (h *HashSuffixResolver) Resolve(current string, attempt int) string {
+	base := current
+	if matches := hashSuffixPattern.FindStringSubmatch(current); matches != nil {
+		base = matches[1]
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(base)) // hash.Hash.Write never errors
+	_, _ = hasher.Write(h.ID)
+	_ = binary.Write(hasher, binary.BigEndian, int32(attempt)) // nosemgrep: beacon-error-swallowing
+
+	return fmt.Sprintf("%s-%08x", base, hasher.Sum32())
+}
+
+// SuffixLen implements RenameStrategy: the suffix is always a hyphen
+// followed by 8 hex digits.
+func (*HashSuffixResolver) SuffixLen(string) int {
+	return 9
+}
+
+// hostnameSuffixPattern matches the " on <hostname>" suffix
+// HostnameSuffixResolver uses, e.g. "My Printer on kitchen-pi".
+var hostnameSuffixPattern = regexp.MustCompile(`^(.+) on (.+)$`)
+
+// HostnameSuffixResolver derives a suffix from the device's own hostname
+// instead of a numeric counter or an opaque hash, so a conflict on a LAN
+// with human-readable hostnames (e.g. a household's Raspberry Pis) proposes
+// a name users can recognize at a glance, such as "My Printer on
+// kitchen-pi", rather than "My Printer-2".
+type HostnameSuffixResolver struct {
+	// Hostname is the suffix to append, e.g. a host's short hostname
+	// without its ".local" domain.
+	Hostname string
+}
+
+// NewHostnameSuffixResolver creates a HostnameSuffixResolver suffixing
+// every renamed name with hostname.
+func NewHostnameSuffixResolver(hostname string) *HostnameSuffixResolver {
+	return &HostnameSuffixResolver{Hostname: hostname}
+}
+
+// Resolve implements RenameStrategy. attempt is ignored: the suffix is
+// always h.Hostname, so repeated conflicts for the same base name propose
+// the same renamed name, matching NumericSuffixResolver's idempotence for
+// a strategy with no incrementing counter of its own.
+func (h *HostnameSuffixResolver) Resolve(current string, _ int) string {
+	base := current
+	if matches := hostnameSuffixPattern.FindStringSubmatch(current); matches != nil {
+		base = matches[1]
+	}
+	return fmt.Sprintf("%s on %s", base, h.Hostname)
+}
+
+// SuffixLen implements RenameStrategy.
+func (h *HostnameSuffixResolver) SuffixLen(renamed string) int {
+	return len(" on ") + len(h.Hostname)
+}
+
+// FuncResolver adapts a plain function into a RenameStrategy, for callers
+// that want full control over the renamed name (e.g. "MyPrinter on
+// office-2") without declaring a named type.
+type FuncResolver struct {
+	// Func computes the next candidate name. Required.
+	Func func(current string, attempt int) string
+
+	// SuffixLenFunc optionally reports how many trailing bytes of a Func
+	// result are a preservable suffix. If nil, truncation falls back to
+	// cutting the end of the whole result.
+	SuffixLenFunc func(renamed string) int
+}
+
+// Resolve implements RenameStrategy.
+func (f FuncResolver) Resolve(current string, attempt int) string {
+	return f.Func(current, attempt)
+}
+
+// SuffixLen implements RenameStrategy.
+func (f FuncResolver) SuffixLen(renamed string) int {
+	if f.SuffixLenFunc == nil {
+		return 0
+	}
+	return f.SuffixLenFunc(renamed)
+}
@@ -0,0 +1,255 @@
+package responder
+
+import (
+	"testing"
+)
+
+// TestNumericSuffixResolver_Resolve verifies the default RFC 6762 §9
+// "-2", "-3", ... convention, independent of the attempt argument.
+func TestNumericSuffixResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{"no_suffix", "My Service", "My Service-2"},
+		{"increments_suffix", "My Service-2", "My Service-3"},
+		{"double_digit", "My Service-10", "My Service-11"},
+		{"hyphen_not_suffix", "My-Service", "My-Service-2"},
+	}
+
+	var resolver NumericSuffixResolver
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.Resolve(tt.current, 1); got != tt.want {
+				t.Errorf("Resolve(%q, 1) = %q, want %q", tt.current, got, tt.want)
+			}
+			// attempt is ignored by this strategy, so any value produces
+			// the same result for the same current name.
+			if got := resolver.Resolve(tt.current, 7); got != tt.want {
+				t.Errorf("Resolve(%q, 7) = %q, want %q (attempt should not matter)", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNumericSuffixResolver_Resolve_Idempotent verifies that resolving the
+// same current name repeatedly always proposes the same next name - no
+// hidden state is carried between calls.
+func TestNumericSuffixResolver_Resolve_Idempotent(t *testing.T) {
+	var resolver NumericSuffixResolver
+	const current = "My Service-4"
+
+	first := resolver.Resolve(current, 1)
+	for i := 0; i < 5; i++ {
+		if got := resolver.Resolve(current, i+1); got != first {
+			t.Errorf("Resolve(%q, %d) = %q, want %q (repeated resolves of the same name must agree)",
+				current, i+1, got, first)
+		}
+	}
+}
+
+// TestHashSuffixResolver_Resolve_StableAcrossReboots verifies that the same
+// ID and base name always produce the same first-attempt suffix, so a
+// device that reboots and re-probes its configured name proposes the same
+// renamed name it used before.
+func TestHashSuffixResolver_Resolve_StableAcrossReboots(t *testing.T) {
+	mac := []byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}
+	resolver := NewHashSuffixResolver(mac)
+
+	first := resolver.Resolve("My Printer", 1)
+
+	// Simulate a reboot: a fresh resolver with the same ID, attempt reset
+	// to 1, starting from the same originally configured name.
+	rebooted := NewHashSuffixResolver(mac)
+	second := rebooted.Resolve("My Printer", 1)
+
+	if first != second {
+		t.Errorf("Resolve() across reboots = %q then %q, want identical", first, second)
+	}
+}
+
+// TestHashSuffixResolver_Resolve_DifferentPerAttempt verifies that repeated
+// conflicts within a single run advance to a fresh suffix rather than
+// looping on the same name forever.
+func TestHashSuffixResolver_Resolve_DifferentPerAttempt(t *testing.T) {
+	resolver := NewHashSuffixResolver([]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02})
+
+	first := resolver.Resolve("My Printer", 1)
+	second := resolver.Resolve(first, 2)
+
+	if first == second {
+		t.Errorf("Resolve() attempt 1 and 2 produced the same name %q, want different", first)
+	}
+}
+
+// TestHashSuffixResolver_Resolve_DifferentID verifies that two devices
+// with different IDs proposing the same base name land on different
+// suffixes, so they don't collide with each other.
+func TestHashSuffixResolver_Resolve_DifferentID(t *testing.T) {
+	a := NewHashSuffixResolver([]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x01})
+	b := NewHashSuffixResolver([]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02})
+
+	if got := a.Resolve("My Printer", 1); got == b.Resolve("My Printer", 1) {
+		t.Errorf("two different IDs both resolved to %q, want different suffixes", got)
+	}
+}
+
+// TestHashSuffixResolver_Resolve_ReplacesPriorHashSuffix verifies that
+// resolving a name that already carries a hash suffix strips it first, so
+// repeated conflicts don't accumulate multiple suffixes.
+func TestHashSuffixResolver_Resolve_ReplacesPriorHashSuffix(t *testing.T) {
+	resolver := NewHashSuffixResolver([]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02})
+
+	once := resolver.Resolve("My Printer", 1)
+	twice := resolver.Resolve(once, 2)
+
+	if got := numberOfHyphens(twice); got != 1 {
+		t.Errorf("Resolve() on an already-suffixed name = %q, has %d hyphenated suffixes, want 1", twice, got)
+	}
+}
+
+func numberOfHyphens(s string) int {
+	matches := hashSuffixPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return 0
+	}
+	count := 0
+	for _, c := range matches[1] {
+		if c == '-' {
+			count++
+		}
+	}
+	return count + 1
+}
+
+// TestFuncResolver_Resolve verifies that FuncResolver delegates to its Func
+// field, for callers who want full control over the renamed name.
+func TestFuncResolver_Resolve(t *testing.T) {
+	resolver := FuncResolver{
+		Func: func(current string, attempt int) string {
+			return current + " on office-" + string(rune('0'+attempt))
+		},
+	}
+
+	if got, want := resolver.Resolve("MyPrinter", 2), "MyPrinter on office-2"; got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+
+	// With no SuffixLenFunc set, SuffixLen falls back to 0 (whole-string
+	// truncation).
+	if got := resolver.SuffixLen("MyPrinter on office-2"); got != 0 {
+		t.Errorf("SuffixLen() = %d, want 0 (no SuffixLenFunc configured)", got)
+	}
+}
+
+// TestService_Rename_UsesConfiguredStrategy verifies that Service.Rename
+// delegates to RenameStrategy when one is set, instead of always using
+// NumericSuffixResolver.
+func TestService_Rename_UsesConfiguredStrategy(t *testing.T) {
+	service := &Service{
+		InstanceName: "My Printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+		RenameStrategy: FuncResolver{
+			Func: func(current string, attempt int) string {
+				return "Custom Name"
+			},
+		},
+	}
+
+	service.Rename(1)
+
+	if service.InstanceName != "Custom Name" {
+		t.Errorf("Rename() InstanceName = %q, want %q", service.InstanceName, "Custom Name")
+	}
+}
+
+// TestTruncateToFit_PreservesArbitrarySuffixLength verifies that
+// truncateToFit preserves whatever suffix length a RenameStrategy reports,
+// not just the hardcoded "-N" numeric suffix the original implementation
+// special-cased.
+func TestTruncateToFit_PreservesArbitrarySuffixLength(t *testing.T) {
+	longBase := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	suffix := "-deadbeef" // 9-byte HashSuffixResolver-style suffix
+	name := longBase + suffix
+
+	got := truncateToFit(name, 63, len(suffix))
+
+	if len(got) != 63 {
+		t.Fatalf("truncateToFit() length = %d, want 63", len(got))
+	}
+	if got[len(got)-len(suffix):] != suffix {
+		t.Errorf("truncateToFit() = %q, want suffix %q preserved", got, suffix)
+	}
+}
+
+// TestHostnameSuffixResolver_Resolve verifies the " on <hostname>"
+// convention, including replacing a prior hostname suffix instead of
+// accumulating multiple " on ..." segments across repeated conflicts.
+func TestHostnameSuffixResolver_Resolve(t *testing.T) {
+	resolver := NewHostnameSuffixResolver("kitchen-pi")
+
+	if got, want := resolver.Resolve("My Printer", 1), "My Printer on kitchen-pi"; got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+
+	if got, want := resolver.Resolve("My Printer on kitchen-pi", 2), "My Printer on kitchen-pi"; got != want {
+		t.Errorf("Resolve() on an already-suffixed name = %q, want %q (idempotent)", got, want)
+	}
+}
+
+// FuzzService_Rename_NeverEmptyOrOverLength drives Service.Rename through
+// every built-in RenameStrategy with arbitrary starting names and attempt
+// counts, and checks the two invariants every strategy (including a
+// third-party FuncResolver) must uphold: the renamed name is never empty,
+// and never exceeds the RFC 1035 §2.3.4 63-octet label limit - regardless
+// of how long the starting name or a strategy's own suffix is.
+func FuzzService_Rename_NeverEmptyOrOverLength(f *testing.F) {
+	f.Add("My Service", 1)
+	f.Add("", 1)
+	f.Add("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 5)
+	f.Add("My Service-2", 0)
+	f.Add("My Service-99999999", -1)
+
+	strategies := []RenameStrategy{
+		NumericSuffixResolver{},
+		NewHashSuffixResolver([]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}),
+		NewHostnameSuffixResolver("kitchen-pi"),
+		FuncResolver{Func: func(current string, attempt int) string { return current + " on office" }},
+	}
+
+	f.Fuzz(func(t *testing.T, current string, attempt int) {
+		if current == "" {
+			// Rename is only ever called on a Service that already passed
+			// Validate (non-empty InstanceName); an empty starting name is
+			// outside every strategy's contract.
+			t.Skip("empty starting name is outside Rename's documented contract")
+		}
+
+		for _, strategy := range strategies {
+			service := &Service{InstanceName: current, RenameStrategy: strategy}
+			service.Rename(attempt)
+
+			if service.InstanceName == "" {
+				t.Errorf("%T.Rename(%q, %d) produced an empty name", strategy, current, attempt)
+			}
+			if len(service.InstanceName) > 63 {
+				t.Errorf("%T.Rename(%q, %d) produced %q (%d bytes), want <= 63", strategy, current, attempt, service.InstanceName, len(service.InstanceName))
+			}
+		}
+	})
+}
+
+// TestTruncateToFit_NoSuffixTruncatesWhole verifies that a suffixLen of 0
+// (a strategy with no well-defined suffix, e.g. a bare FuncResolver) falls
+// back to truncating the whole name.
+func TestTruncateToFit_NoSuffixTruncatesWhole(t *testing.T) {
+	name := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	got := truncateToFit(name, 63, 0)
+
+	if got != name[:63] {
+		t.Errorf("truncateToFit() = %q, want %q", got, name[:63])
+	}
+}
@@ -2,16 +2,31 @@ package responder
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"log/slog"
+	"math/rand"
 	"net"
 	"os"
-
+	"os/signal"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/browser"
+	"github.com/joshuafuller/beacon/internal/logbuf"
 	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/network"
 	"github.com/joshuafuller/beacon/internal/protocol"
 	"github.com/joshuafuller/beacon/internal/records"
 	"github.com/joshuafuller/beacon/internal/responder"
 	"github.com/joshuafuller/beacon/internal/state"
 	"github.com/joshuafuller/beacon/internal/transport"
+	"github.com/joshuafuller/beacon/metrics"
+	"github.com/joshuafuller/beacon/probe"
+	"github.com/joshuafuller/beacon/tap"
 )
 
 // Responder manages mDNS service registration and response per RFC 6762.
@@ -21,65 +36,508 @@ import (
 type Responder struct {
 	ctx              context.Context
 	transport        transport.Transport
-	registry         *responder.Registry
+	registry         responder.Registry
 	hostname         string
 	injectConflict   bool                       // Test hook: inject conflict during probing
 	responseBuilder  *responder.ResponseBuilder // RFC 6762 §6 response construction
 	recordSet        *records.RecordSet         // Per-record rate limiting tracker
 	queryHandlerDone chan struct{}              // Signal query handler shutdown
 
+	// rateLimitObserver, set via WithRateLimitObserver, is called once per
+	// record considered for a multicast query response - after
+	// answerQuestions consults recordSet, so it reports exactly what
+	// sendQueryResponse's RFC 6762 §6 rate limiting decided: suppressed
+	// true if the record was dropped for repeating within its bucket's
+	// interval, false if it was sent (or force-sent under the TTL/4
+	// staleness override). Nil (the default) skips the call entirely.
+	rateLimitObserver func(rr *message.ResourceRecord, suppressed bool)
+
+	// browserMu guards browser, since Browse() may be called concurrently
+	// for different service types and the first call lazily creates it.
+	browserMu sync.Mutex
+
+	// browser backs Browse(): a *browser.Browser built on r.transport (no
+	// socket of its own) the first time Browse() is called. handleQuery
+	// feeds it every response packet the query handler goroutine receives,
+	// per chunk28-4's "reuse the existing transport and query-handler
+	// goroutine" requirement. Nil until then, and for a Responder that
+	// never calls Browse().
+	browser *browser.Browser
+
+	// knownAnswerCache reassembles a truncated (TC=1) query's Known-Answer
+	// continuation packets per RFC 6762 §7.2 before handleQuery answers it,
+	// so suppression runs against the union of every packet's Answer
+	// section rather than just the first. Constructed in New() (after
+	// options, so it shares r.metrics with any WithMetrics override).
+	knownAnswerCache *responder.KnownAnswerCache
+
+	// conflictDetector decides RFC 6762 §8.2/§8.2.1 probe tie-breaks for
+	// every service this Responder registers: Register wires it into each
+	// attempt's Prober via SetConflictDetector/SetOurRecords before running
+	// the state machine, so a probe response carrying a conflicting record
+	// (or RRSet, for a service with more than one record under its name)
+	// drives the existing rename-and-retry loop below instead of silently
+	// going undetected. Shared across services/attempts rather than
+	// recreated, so AddEventHook only needs to register a hook once (see
+	// its r.conflictDetector.AddHook call) for it to observe every
+	// subsequent probe's conflict events.
+	conflictDetector *ConflictDetector
+
+	// passiveCache observes every peer response handleQuery sees - not just
+	// answers to our own queries - so a Prober can skip its wire probes per
+	// RFC 6762 §8.1 when a conflicting (or already-free) record for the name
+	// it's about to probe has already been seen on the wire. Constructed in
+	// New() and wired into each attempt's Prober alongside conflictDetector
+	// below; nil for a Responder built directly, same as knownAnswerCache.
+	passiveCache *state.MemoryKnownAnswerCache
+
+	// probeRateLimiter enforces RFC 6762 §8.1's probe-conflict cutoff
+	// alongside conflictDetector's tie-breaking: Register records every
+	// StateConflictDetected against the service's original instance name
+	// and paces the next rename-and-retry attempt by whichever is longer,
+	// probeRateLimiter.NextProbeDelay or backoffConfig.Delay, so a peer
+	// that conflicts unusually often gets backed off harder than a normal
+	// simultaneous-probe race. Constructed in New(); nil for a Responder
+	// built directly, same as knownAnswerCache.
+	probeRateLimiter *ProbeRateLimiter
+
 	// US2 GREEN: Store last machine for message capture (contract test support)
 	lastMachine *state.Machine // Last state machine used for registration
 
+	// machinesMu guards machines, since Register/Unregister/Close may be
+	// called concurrently for different services (R001: goroutine-per-service).
+	machinesMu sync.Mutex
+
+	// machines holds the established state.Machine for each currently
+	// registered service, keyed by full service name ("Instance._type.local"),
+	// so Unregister/Close can send RFC 6762 §10.1 goodbye packets through the
+	// same machine that announced the service.
+	machines map[string]*state.Machine
+
 	// US2 GREEN: Store callbacks for applying to new machines
 	onProbeCallback    func() // Callback for probe events
 	onAnnounceCallback func() // Callback for announce events
 
 	// US2 GREEN: Store last announced records for contract test validation
 	lastAnnouncedRecords []*ResourceRecord // Last record set announced
+
+	// interfaces, if set via WithInterfaces, restricts which interfaces'
+	// addresses feed the A/AAAA records built by Register. Nil (the
+	// default) scans every non-loopback address on the host.
+	interfaces []net.Interface
+
+	// networkMode, set via WithNetworkMode, selects which multicast
+	// transport(s) New constructs. Defaults to IPv4Only, preserving prior
+	// behavior.
+	networkMode NetworkMode
+
+	// customTransport, when set via WithTransport, replaces the UDP
+	// multicast transport newTransport(networkMode) would otherwise build -
+	// for test isolation (a transport.MockTransport or an in-memory fabric)
+	// or a persistent-connection transport never meant to join a multicast
+	// group, mirroring querier.Querier's customTransport. A caller wanting
+	// RFC 6762 §18 TCP fallback (sendQueryResponse's TC-bit path) passes a
+	// transport.NewMultiTransport built with a transport.TCPListenerTransport
+	// here - newTransport(networkMode) never adds one on its own.
+	customTransport transport.Transport
+
+	// transportPlugins, set via WithTransports, is the multi-homed transport
+	// set a Responder fans probing/announcing/query-handling across instead
+	// of the single r.transport every other Responder uses. Nil (the
+	// default) leaves query handling on r.transport alone, exactly as
+	// before WithTransports existed.
+	transportPlugins []TransportPlugin
+
+	// ipv6Enabled, set via WithIPv6, controls whether Register advertises
+	// AAAA records for the host's IPv6 addresses. Defaults to true,
+	// preserving prior behavior - unlike networkMode, this only affects
+	// which records get built and announced, not which multicast
+	// transport(s) are joined.
+	ipv6Enabled bool
+
+	// ifaceWatcher polls for interface changes (Wi-Fi reassociation, a VPN
+	// coming up, a cable unplugged) when watchInterfaces is enabled. Nil
+	// otherwise. Started last in New(), stopped first in Close()/Shutdown(),
+	// mirroring querier.Querier's ifaceWatcher.
+	ifaceWatcher *network.InterfaceWatcher
+
+	// watchInterfaces enables ifaceWatcher, and with it automatic multicast
+	// rejoin/leave and RFC 6762 §8.4 re-announcement on interface changes.
+	// Disabled by default, same rationale as querier.WithWatchInterfaces:
+	// the extra goroutine is wasted on a Responder that doesn't outlive a
+	// single network state. Set via WithWatchInterfaces.
+	watchInterfaces bool
+
+	// backoffConfig delays each rename-and-retry attempt in Register's
+	// conflict loop per state.BackoffConfig, set via WithBackoff. The zero
+	// value (the default) retries immediately, preserving prior behavior -
+	// set via WithBackoff so multiple responders that lost a probe storm on
+	// the same LAN don't all rename and retry in lockstep.
+	backoffConfig state.BackoffConfig
+
+	// lameDuckMu guards lameDuck, set by Shutdown so a Register call racing
+	// with a graceful shutdown is rejected instead of probing and announcing
+	// a service that's about to be torn down again.
+	lameDuckMu sync.Mutex
+	lameDuck   bool
+
+	// lameDuckTimeout bounds how long Shutdown waits for every registered
+	// service's goodbye flush before force-closing, set via
+	// WithLameDuckTimeout. Zero (the default) uses defaultLameDuckTimeout.
+	lameDuckTimeout time.Duration
+
+	// hooksMu guards hooks, set via AddEventHook. Register fans a new
+	// machine's Prober out to these (EventHook satisfies state.ProbeHook by
+	// method set), and Register/unregister call their Rename/Announce/
+	// Goodbye methods directly.
+	hooksMu sync.Mutex
+	hooks   []EventHook
+
+	// zone, set via WithZone, is the domain suffix New() appends to the
+	// system hostname when WithHostname wasn't given, and the Domain field
+	// handleQuery reports in its responses. Defaults to "local". This does
+	// NOT relax Service.ServiceType's own "must end in .local" validation
+	// (see responder/service.go's validateServiceType) - full non-.local
+	// service-type support would also need that regex updated.
+	zone string
+
+	// serviceTTL, set via WithTTL, overrides the PTR/SRV/TXT records'
+	// default 120-second TTL (RFC 6762 §10). Zero (the default) leaves
+	// BuildRecordSet's own default in place. A/AAAA records are unaffected.
+	serviceTTL uint32
+
+	// unicastResponseEnabled, set via WithUnicastResponse, controls whether
+	// handleQuery honors a query's RFC 6762 §5.4 QU bit by replying via
+	// unicast directly to the querier instead of always multicasting.
+	// Defaults to true.
+	unicastResponseEnabled bool
+
+	// beforeStart, afterStart, beforeStop, afterStop are lifecycle hooks
+	// registered via WithBeforeStart/WithAfterStart/WithBeforeStop/
+	// WithAfterStop, run in registration order around New()'s transport
+	// bind and Close/Shutdown's teardown.
+	beforeStart []func() error
+	afterStart  []func() error
+	beforeStop  []func() error
+	afterStop   []func() error
+
+	// registerCheck, set via WithRegisterCheck, is invoked by Register right
+	// after validation but before any probe traffic, giving an embedder a
+	// chance to veto registration (e.g. a readiness check that isn't passing
+	// yet). If registerCheckInterval is also set, Register additionally
+	// starts a background goroutine that re-runs this check on that interval
+	// while the service stays registered; a failing check sends a goodbye
+	// and removes the service from the registry (suspending it) until a
+	// later check succeeds, at which point the monitor calls Register again
+	// to re-probe and re-announce it. There is no dedicated "Suspended"
+	// state.Machine state - suspension is tracked entirely at this layer,
+	// reusing the goodbye/probe/announce machinery a plain Unregister/
+	// Register cycle already provides.
+	registerCheck func(ctx context.Context, service *Service) error
+
+	// registerCheckInterval, set via WithRegisterCheckInterval, is how often
+	// the background monitor described above re-runs registerCheck. Zero
+	// (the default) disables periodic re-checking; registerCheck still runs
+	// once at registration time either way.
+	registerCheckInterval time.Duration
+
+	// registerCheckMu guards registerCheckCancels.
+	registerCheckMu sync.Mutex
+
+	// registerCheckCancels holds the cancel func for each currently
+	// registered service's monitor goroutine (see startRegisterCheckMonitor),
+	// keyed by full service name ("Instance._type.local").
+	registerCheckCancels map[string]context.CancelFunc
+
+	// announcePolicy, set via WithAnnouncePolicy, configures how many
+	// announcements beyond RFC 6762 §8.3's mandatory pair Register sends, and
+	// how widely spaced they are. Defaults to state.DefaultAnnouncePolicy
+	// (Count 2, i.e. just the mandatory pair - no additional announcements).
+	announcePolicy state.AnnouncePolicy
+
+	// announceMu guards announceCancels.
+	announceMu sync.Mutex
+
+	// announceCancels holds the cancel func for each currently registered
+	// service's additional-announcement scheduler goroutine (see
+	// startAnnouncementScheduler), keyed by full service name
+	// ("Instance._type.local").
+	announceCancels map[string]context.CancelFunc
+
+	// probe, set via WithProbe, is updated by Register/unregister as
+	// services move through Probing/Announcing/Established/gone, and by New/
+	// Close/Shutdown for the transport itself. Nil (the default) disables
+	// this entirely - every call site below is a no-op when probe is nil.
+	probe *probe.Probe
+
+	// signalEnabled, set via WithSignal, controls whether New starts
+	// awaitSignal to call Shutdown on SIGINT/SIGTERM. Defaults to true,
+	// matching go-micro's web.Options; library users running their own
+	// signal loop should pass WithSignal(false).
+	signalEnabled bool
+
+	// doneOnce guards done, since both Close and Shutdown close it, and
+	// either may run more than once.
+	doneOnce sync.Once
+
+	// done is closed once Close or Shutdown finishes tearing the responder
+	// down, whether triggered directly or by awaitSignal. Done() exposes it.
+	done chan struct{}
+
+	// injectMu guards injectProbeConflicts and injectAnnounceLoss.
+	injectMu sync.Mutex
+
+	// injectProbeConflicts, set via InjectProbeConflicts, is how many of the
+	// next probe/announce attempts Register should force into
+	// state.StateConflictDetected regardless of the real probe outcome,
+	// decrementing by one per attempt that consumes it. Unlike the older,
+	// coarser injectConflict bool above (which forces every attempt forever
+	// while enabled), this lets a test express "conflict for the first N
+	// attempts, then let probing behave normally" - e.g. to exercise the
+	// rename loop's success path rather than just its max-attempts path.
+	injectProbeConflicts int
+
+	// injectAnnounceLoss, set via InjectAnnounceLoss, is how many of the
+	// next announcement sends the Announcer should silently drop (simulate
+	// not reaching any peer) instead of actually delivering, per RFC 6762
+	// §8.3's retry behavior. Decremented by the Announcer itself as it
+	// consumes it; Register reads the remainder back after each attempt so
+	// a loss budget that outlives one attempt carries over to the next.
+	injectAnnounceLoss int
+
+	// injectSimultaneousProbeOurData/injectSimultaneousProbeTheirData, set
+	// via InjectSimultaneousProbe, simulate a real RFC 6762 §8.2.1 tie-break
+	// against a second host probing for the same name at the same time:
+	// the next attempt's Prober compares these two byte strings
+	// lexicographically instead of only reacting to a real conflicting
+	// response, loses or wins accordingly, and - like injectProbeConflicts -
+	// is consumed after one attempt so a losing tie-break's rename-and-retry
+	// can then succeed normally.
+	injectSimultaneousProbeOurData   []byte
+	injectSimultaneousProbeTheirData []byte
+
+	// logger receives recoverPanic's log record for a panic recovered from
+	// a packet-processing or background goroutine. Set via WithLogger
+	// (shared with that option's EventHook registration) or defaults to
+	// slog.Default().
+	logger *slog.Logger
+
+	// metrics receives recoverPanic's beacon_handler_panics_total counter.
+	// Defaults to metrics.NoOp{} unless WithMetrics overrides it.
+	metrics metrics.Metrics
+
+	// panicHandler, set via WithPanicHandler, is called in addition to the
+	// standard slog/metrics reporting whenever recoverPanic recovers a
+	// panic. Nil (the default) disables this extra reporting.
+	panicHandler PanicHandler
+
+	// tap receives every query/response this Responder sends or receives,
+	// for dnstap-style observability independent of the metrics/logger
+	// instrumentation above. Defaults to tap.NoOp{} unless WithTap
+	// overrides it.
+	tap tap.Tap
+
+	// logBuffer, set via WithLogBuffer, retains the last N log records this
+	// Responder emitted so they can be inspected (Snapshot/DebugHandler) or
+	// replayed at Error level when something goes badly wrong (see
+	// flushLogBuffer). Nil unless WithLogBuffer was given.
+	logBuffer *logbuf.Buffer
+
+	// logBufferFlushLogger is the logger flushLogBuffer replays logBuffer's
+	// contents to: logger as configured just before WithLogBuffer wrapped
+	// its handler in a logbuf.Tee, so flushing doesn't feed the replayed
+	// records straight back into the buffer it just read them from.
+	logBufferFlushLogger *slog.Logger
 }
 
+// probeTransportComponent is the component name Probe tracks the Responder's
+// own transport under, alongside one entry per registered service (see
+// WithProbe).
+const probeTransportComponent = "transport"
+
+// defaultLameDuckTimeout is used by Shutdown when WithLameDuckTimeout wasn't
+// given. Each service's goodbye is two multicasts one second apart (RFC 6762
+// §8.4), sent concurrently across services, so this comfortably covers that
+// with margin for a handful of registered services.
+const defaultLameDuckTimeout = 5 * time.Second
+
 // New creates a new mDNS responder.
 //
 // T036: Responder.New() implementation
 // T080: Start query handler goroutine
 func New(ctx context.Context, opts ...Option) (*Responder, error) {
-	// Get system hostname if not provided
-	hostname, err := os.Hostname()
-	if err != nil {
-		hostname = "localhost"
-	}
-	hostname = hostname + ".local"
-
-	// Create transport
-	t, err := transport.NewUDPv4Transport()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transport: %w", err)
-	}
-
 	r := &Responder{
-		ctx:              ctx,
-		transport:        t,
-		registry:         responder.NewRegistry(),
-		hostname:         hostname,
-		responseBuilder:  responder.NewResponseBuilder(),
-		recordSet:        records.NewRecordSet(),
-		queryHandlerDone: make(chan struct{}),
+		ctx:                    ctx,
+		registry:               responder.NewRegistry(),
+		responseBuilder:        responder.NewResponseBuilder(),
+		recordSet:              records.NewRecordSet(),
+		conflictDetector:       &ConflictDetector{},
+		machines:               make(map[string]*state.Machine),
+		registerCheckCancels:   make(map[string]context.CancelFunc),
+		announcePolicy:         state.DefaultAnnouncePolicy(),
+		announceCancels:        make(map[string]context.CancelFunc),
+		queryHandlerDone:       make(chan struct{}),
+		done:                   make(chan struct{}),
+		networkMode:            IPv4Only,
+		ipv6Enabled:            true,
+		zone:                   "local",
+		unicastResponseEnabled: true,
+		signalEnabled:          true,
+		logger:                 slog.Default(),
+		metrics:                metrics.NoOp{},
+		tap:                    tap.NoOp{},
 	}
 
-	// Apply options
+	// Apply options (including WithNetworkMode, which decides which
+	// transport is constructed below, and WithZone, which the system
+	// hostname default below needs)
 	for _, opt := range opts {
 		if err := opt(r); err != nil {
 			return nil, fmt.Errorf("failed to apply option: %w", err)
 		}
 	}
 
+	// Send a goodbye for any service the registry removes on its own - a
+	// lease (RegisterWithLease) that wasn't renewed, or (for a fleet-aware
+	// backend installed via WithRegistry) a peer's service disappearing -
+	// so a crashed or partitioned publisher's records don't linger past
+	// their TTL on this node's responses. Wired after options are applied
+	// so a WithRegistry backend is covered, not just the default.
+	r.registry.OnExpire(func(svc *responder.Service) {
+		_ = r.sendGoodbyeForService(r.ctx, svc.InstanceName, svc.ServiceType)
+	})
+
+	// Known-Answer continuation packets (RFC 6762 §7.2) arrive without a
+	// live net.Addr to answer through once the hold timer fires, so
+	// onKnownAnswersReady reconstructs one from the cache's string-keyed
+	// source address.
+	r.knownAnswerCache = responder.NewKnownAnswerCache(r.onKnownAnswersReady, r.metrics)
+	r.probeRateLimiter = NewProbeRateLimiter()
+	r.passiveCache = state.NewMemoryKnownAnswerCache()
+	r.responseBuilder.WithOnSuppressed(r.notifyKnownAnswerSuppressed)
+
+	// Get system hostname if WithHostname didn't already set one
+	if r.hostname == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "localhost"
+		}
+		r.hostname = hostname + "." + r.zone
+	}
+
+	// Run BeforeStart hooks (WithBeforeStart) before binding the socket, so
+	// an embedder's own setup (e.g. an admin HTTP endpoint) can fail fast
+	// without ever standing up the responder's transport.
+	if err := runLifecycleHooks(r.beforeStart); err != nil {
+		return nil, fmt.Errorf("beforeStart hook: %w", err)
+	}
+
+	// Create transport per r.networkMode, unless WithTransport already
+	// supplied one.
+	t := r.customTransport
+	if t == nil {
+		var err error
+		t, err = newTransport(r.networkMode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+	}
+	r.transport = t
+	if r.probe != nil {
+		r.probe.UpdateStatus(probeTransportComponent, probe.StatusRunning)
+	}
+
 	// Start query handler goroutine (T080)
 	go r.runQueryHandler()
 
+	// Run AfterStart hooks (WithAfterStart) now that the responder is
+	// receiving queries. A failing hook tears the transport back down
+	// rather than returning a half-started Responder.
+	if err := runLifecycleHooks(r.afterStart); err != nil {
+		close(r.queryHandlerDone)
+		_ = r.transport.Close()
+		return nil, fmt.Errorf("afterStart hook: %w", err)
+	}
+
+	// Install the SIGINT/SIGTERM handler (WithSignal(false) opts out).
+	if r.signalEnabled {
+		go r.awaitSignal()
+	}
+
+	// Start the interface watcher last, once the Responder is otherwise
+	// fully up, mirroring querier.New's ordering.
+	if r.watchInterfaces {
+		if err := r.startWatchingInterfaces(); err != nil {
+			close(r.queryHandlerDone)
+			_ = r.transport.Close()
+			return nil, fmt.Errorf("failed to start interface watcher: %w", err)
+		}
+	}
+
 	return r, nil
 }
 
+// awaitSignal blocks until SIGINT or SIGTERM arrives and then calls Shutdown
+// to gracefully retire the responder, matching go-micro's default
+// signal-handling behavior. It also returns once r.done closes (Close or
+// Shutdown ran some other way) or ctx ends, so it never outlives the
+// responder it was started for.
+func (r *Responder) awaitSignal() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	select {
+	case <-sigCh:
+		_ = r.Shutdown(context.Background())
+	case <-r.ctx.Done():
+	case <-r.done:
+	}
+}
+
+// Done returns a channel that's closed once Close or Shutdown finishes
+// tearing the responder down, whether triggered by an embedder directly or
+// by awaitSignal's SIGINT/SIGTERM handler - letting an embedding program
+// block on shutdown completion instead of polling.
+func (r *Responder) Done() <-chan struct{} {
+	return r.done
+}
+
+// runLifecycleHooks calls each hook in order, stopping at (and returning)
+// the first error.
+func runLifecycleHooks(hooks []func() error) error {
+	for _, hook := range hooks {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTransport constructs the multicast transport for mode, matching
+// querier.newEndpoints's IPv4Only/IPv6Only/DualStack selection.
+//
+// RFC 6762 §3's requirement to also listen on the IPv6 link-local group
+// ff02::fb:5353 is already covered end to end: transport.UDPv6Transport
+// joins it with IPV6_JOIN_GROUP/IPV6_MULTICAST_IF/IPV6_MULTICAST_HOPS=255
+// (see socket_linux.go/socket_darwin.go), transport.DualStack fans a
+// nil-dest Send out to both families' multicast groups and demultiplexes
+// Receive by each packet's source address type, and WithNetworkMode(DualStack)
+// wires it in here (and in querier.newEndpoints) without any v4/v6-specific
+// code in Prober or the query/response handling above this function.
+func newTransport(mode NetworkMode) (transport.Transport, error) {
+	switch mode {
+	case IPv6Only:
+		return transport.NewUDPv6Transport()
+	case DualStack:
+		return transport.NewUDPDualStackTransport()
+	case IPv4Only:
+		return transport.NewUDPv4Transport()
+	default:
+		return transport.NewUDPv4Transport()
+	}
+}
+
 // maxRenameAttempts is the maximum number of times to rename a service on conflict.
 //
 // RFC 6762 §9: No explicit limit specified, but we use 10 as a reasonable maximum
@@ -110,33 +568,78 @@ func (r *Responder) Register(service *Service) error {
 		return fmt.Errorf("service cannot be nil")
 	}
 
+	r.lameDuckMu.Lock()
+	lameDuck := r.lameDuck
+	r.lameDuckMu.Unlock()
+	if lameDuck {
+		return fmt.Errorf("responder is shutting down: cannot register service %q", service.InstanceName)
+	}
+
 	// Validate service parameters
 	if err := service.Validate(); err != nil {
 		return err
 	}
 
+	// Run the registration check (WithRegisterCheck), if any, before any
+	// probe traffic goes out - a failing check aborts registration entirely
+	// rather than probing and announcing a service that isn't ready.
+	if r.registerCheck != nil {
+		if err := r.registerCheck(r.ctx, service); err != nil {
+			if r.probe != nil {
+				r.probe.UpdateStatus(service.InstanceName+"."+service.ServiceType, probe.StatusNotReady)
+			}
+			return fmt.Errorf("register check failed for %q: %w", service.InstanceName, err)
+		}
+	}
+
 	// Set hostname if not provided
 	if service.Hostname == "" {
 		service.Hostname = r.hostname
 	}
 
-	// Get local IPv4 address (simplified - use first non-loopback)
-	ipv4, err := getLocalIPv4()
+	// RFC 5891: message.EncodeName (used by BuildRecordSet to put Hostname on
+	// the wire) only accepts ASCII labels, so a non-ASCII hostname needs
+	// IDNA/Punycode encoding first. service.Hostname itself is left as given,
+	// so GetService still returns the original UTF-8 form; only the record
+	// set built below sees the ASCII-safe one.
+	wireHostname, err := protocol.EncodeName(service.Hostname)
+	if err != nil {
+		return fmt.Errorf("encoding hostname %q: %w", service.Hostname, err)
+	}
+
+	// Get local IPv4 address (simplified - use first non-loopback), scoped
+	// to r.interfaces if WithInterfaces was given.
+	ipv4, err := getLocalIPv4(r.interfaces)
 	if err != nil {
 		return fmt.Errorf("failed to get local IPv4: %w", err)
 	}
 
+	// Get local IPv6 addresses (all non-loopback, link-local through global),
+	// likewise scoped to r.interfaces. Unlike getLocalIPv4, having none is
+	// not an error - plenty of networks are IPv4-only.
+	ipv6s := r.responderIPv6Addresses()
+
+	// originalName keys probeRateLimiter's conflict history across the
+	// whole rename loop below, so conflicts against "Office Printer",
+	// "Office Printer-2", "Office Printer-3", ... all count toward the same
+	// RFC 6762 §8.1 sliding window instead of each rename starting a fresh
+	// one.
+	originalName := service.InstanceName
+
 	// RFC 6762 §9: Rename loop on conflict (max 10 attempts)
 	// Attempt probing up to maxRenameAttempts times
 	for attempt := 1; attempt <= maxRenameAttempts; attempt++ {
 		// Build record set for this service (with current name)
 		serviceInfo := &records.ServiceInfo{
-			InstanceName: service.InstanceName,
-			ServiceType:  service.ServiceType,
-			Hostname:     service.Hostname,
-			Port:         service.Port,
-			IPv4Address:  ipv4,
-			TXTRecords:   service.TXTRecords,
+			InstanceName:  service.InstanceName,
+			ServiceType:   service.ServiceType,
+			Hostname:      wireHostname,
+			Port:          service.Port,
+			IPv4Address:   ipv4,
+			IPv6Addresses: ipv6s,
+			TXTRecords:    service.TXTRecords,
+			Subtypes:      service.Subtypes,
+			TTL:           r.serviceTTL,
 		}
 		recordSet := records.BuildRecordSet(serviceInfo)
 
@@ -144,11 +647,26 @@ func (r *Responder) Register(service *Service) error {
 		r.lastAnnouncedRecords = recordSet
 
 		// Create and run state machine
-		machine := state.NewMachine()
+		machine := state.NewMachineWithBackoff(r.backoffConfig)
 		serviceName := service.InstanceName + "." + service.ServiceType
+		if r.probe != nil {
+			r.probe.UpdateStatus(serviceName, probe.StatusPreparing)
+		}
 
 		// Apply test hooks (if any)
-		if r.injectConflict {
+		r.injectMu.Lock()
+		forceConflict := r.injectProbeConflicts > 0
+		if forceConflict {
+			r.injectProbeConflicts--
+		}
+		lossBudget := r.injectAnnounceLoss
+		simultaneousOurData := r.injectSimultaneousProbeOurData
+		simultaneousTheirData := r.injectSimultaneousProbeTheirData
+		r.injectSimultaneousProbeOurData = nil
+		r.injectSimultaneousProbeTheirData = nil
+		r.injectMu.Unlock()
+
+		if r.injectConflict || forceConflict {
 			machine.SetInjectConflict(true)
 		}
 
@@ -173,11 +691,54 @@ func (r *Responder) Register(service *Service) error {
 		announcer := machine.GetAnnouncer()
 		if announcer != nil {
 			announcer.SetRecords(recordSet)
+			announcer.SetRateLimiter(r.recordSet)
+			if lossBudget > 0 {
+				announcer.SetSimulatedLoss(lossBudget)
+			}
 		}
 
+		// Wire this attempt's Prober to r.conflictDetector so an incoming
+		// probe response is tie-broken against recordSet per RFC 6762
+		// §8.2/§8.2.1 instead of only reacting to the injectConflict test
+		// hooks above.
+		if prober := machine.GetProber(); prober != nil {
+			ourRecords := make([]message.ResourceRecord, len(recordSet))
+			for i, rr := range recordSet {
+				ourRecords[i] = *rr
+			}
+			prober.SetOurRecords(ourRecords)
+			prober.SetConflictDetector(r.conflictDetector)
+			if r.passiveCache != nil {
+				prober.SetKnownAnswerCache(r.passiveCache)
+			}
+			if simultaneousOurData != nil || simultaneousTheirData != nil {
+				prober.SetSimultaneousProbeInjection(simultaneousOurData, simultaneousTheirData)
+			}
+		}
+
+		// Fan this machine's Prober out to every hook registered via
+		// AddEventHook. An EventHook satisfies state.ProbeHook by method
+		// set, so no adapter is needed.
+		r.hooksMu.Lock()
+		for _, hook := range r.hooks {
+			machine.GetProber().AddHook(hook)
+		}
+		r.hooksMu.Unlock()
+
 		// Run state machine (probing + announcing)
 		err = machine.Run(r.ctx, serviceName)
+
+		// Carry any unconsumed loss budget over to the next attempt (or back
+		// out to the caller, via InjectAnnounceLoss's own accounting, once
+		// registration finishes).
+		if announcer != nil {
+			r.injectMu.Lock()
+			r.injectAnnounceLoss = announcer.GetSimulatedLoss()
+			r.injectMu.Unlock()
+		}
+
 		if err != nil {
+			r.flushLogBuffer()
 			return fmt.Errorf("state machine failed: %w", err)
 		}
 
@@ -185,36 +746,98 @@ func (r *Responder) Register(service *Service) error {
 		finalState := machine.GetState()
 
 		if finalState == state.StateConflictDetected {
+			r.panicLogger().Warn("probing: got conflicting response", "instance", serviceName, "attempt", attempt)
+			r.probeRateLimiter.RecordConflict(originalName)
+
 			// Conflict detected - rename and retry (unless max attempts reached)
 			if attempt >= maxRenameAttempts {
 				// Max attempts exceeded - give up
+				if r.probe != nil {
+					r.probe.UpdateStatus(serviceName, probe.StatusFailed)
+				}
+				r.flushLogBuffer()
+				r.probeRateLimiter.Reset(originalName)
 				return fmt.Errorf("max rename attempts (%d) exceeded for service %q",
 					maxRenameAttempts, service.InstanceName)
 			}
 
-			// Rename service and try again
-			service.Rename() // Appends "-2", "-3", etc.
-			continue         // Retry with new name
+			// Back off before retrying (WithBackoff's zero-value default
+			// retries immediately) so responders that lost a simultaneous
+			// probe on the same LAN don't all rename and retry in lockstep.
+			// RFC 6762 §8.1: if originalName has conflicted 15+ times within
+			// the last 10 seconds, NextProbeDelay trips from its ordinary
+			// probeNormalInterval to a growing cooldown (5s, 10s, ...). Only
+			// that tripped cooldown ever raises delay - an untripped
+			// probeNormalInterval must not turn into a new 250ms floor on
+			// every ordinary conflict for responders that left WithBackoff
+			// at its zero-value immediate-retry default.
+			delay := r.backoffConfig.Delay(attempt - 1)
+			if cooldown := r.probeRateLimiter.NextProbeDelay(originalName); cooldown > probeNormalInterval && cooldown > delay {
+				delay = cooldown
+				r.notifyProbeRateLimited(originalName, delay)
+			}
+			select {
+			case <-r.ctx.Done():
+				return r.ctx.Err()
+			case <-time.After(delay):
+			}
+
+			// Rename service and try again, via its RenameStrategy
+			// (NumericSuffixResolver's "-2", "-3", ... by default).
+			oldName := service.InstanceName
+			service.Rename(attempt)
+			r.panicLogger().Info("renaming service after conflict", "old", oldName, "new", service.InstanceName, "attempt", attempt)
+			r.notifyRename(oldName, service.InstanceName)
+			continue // Retry with new name
 		}
 
 		if finalState != state.StateEstablished {
+			if r.probe != nil {
+				r.probe.UpdateStatus(serviceName, probe.StatusFailed)
+			}
+			r.flushLogBuffer()
 			// This is NOT wrapping an error - finalState is state.State (int), not error type.
 			// Using %v here is correct for formatting the state value.
 			return fmt.Errorf("unexpected final state: %v", finalState) // nosemgrep: beacon-error-wrap-percent-v
 		}
 
+		r.notifyAnnounce(serviceName)
+		if r.probe != nil {
+			r.probe.UpdateStatus(serviceName, probe.StatusRunning)
+		}
+		r.probeRateLimiter.Reset(originalName)
+
 		// Success! Add to registry
 		internalService := &responder.Service{
 			InstanceName: service.InstanceName,
 			ServiceType:  service.ServiceType,
 			Port:         service.Port,
 			TXT:          service.TXTRecords, // US5: Store TXT records for UpdateService support
+			Subtypes:     service.Subtypes,
 		}
 		err = r.registry.Register(internalService)
 		if err != nil {
 			return fmt.Errorf("failed to add to registry: %w", err)
 		}
 
+		// Keep the established machine around so Unregister/Close can send
+		// goodbye packets (RFC 6762 §10.1) through it.
+		r.machinesMu.Lock()
+		r.machines[serviceName] = machine
+		r.machinesMu.Unlock()
+
+		// Start (or, on a suspended service's recovery, leave running) the
+		// periodic register-check monitor.
+		if r.registerCheck != nil && r.registerCheckInterval > 0 {
+			r.startRegisterCheckMonitor(service, serviceName)
+		}
+
+		// Send any additional announcements RFC 6762 §8.3 allows beyond the
+		// mandatory pair Run already sent, per r.announcePolicy.
+		if r.announcePolicy.Count > 2 {
+			r.startAnnouncementScheduler(machine, serviceName)
+		}
+
 		return nil // Successfully registered
 	}
 
@@ -236,6 +859,105 @@ func (r *Responder) Register(service *Service) error {
 //
 // T042: Implement Unregister() with goodbye packets
 func (r *Responder) Unregister(serviceID string) error {
+	if svc, found := r.GetService(serviceID); found {
+		serviceName := svc.InstanceName + "." + svc.ServiceType
+		r.stopRegisterCheckMonitor(serviceName)
+		r.stopAnnouncementScheduler(serviceName)
+	}
+	return r.unregister(r.ctx, serviceID)
+}
+
+// startRegisterCheckMonitor starts serviceName's background register-check
+// monitor, unless one is already running for it - which happens when a
+// suspended service's recovered check calls Register again, and that call
+// reaches here a second time for the same service.
+func (r *Responder) startRegisterCheckMonitor(service *Service, serviceName string) {
+	r.registerCheckMu.Lock()
+	defer r.registerCheckMu.Unlock()
+
+	if _, running := r.registerCheckCancels[serviceName]; running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.registerCheckCancels[serviceName] = cancel
+	go r.runRegisterCheckMonitor(ctx, service)
+}
+
+// stopRegisterCheckMonitor cancels serviceName's background monitor, if one
+// is running. Unregister and Shutdown call this so an explicitly torn-down
+// service stops re-checking (and potentially re-registering itself) for
+// good; the monitor's own suspend/recover cycle, below, never calls this -
+// a suspended service keeps being monitored so it can come back.
+func (r *Responder) stopRegisterCheckMonitor(serviceName string) {
+	r.registerCheckMu.Lock()
+	defer r.registerCheckMu.Unlock()
+
+	if cancel, ok := r.registerCheckCancels[serviceName]; ok {
+		cancel()
+		delete(r.registerCheckCancels, serviceName)
+	}
+}
+
+// runRegisterCheckMonitor re-runs registerCheck every registerCheckInterval
+// until ctx ends. A failing check, while the service is currently
+// registered, sends a goodbye and removes it from the registry (suspending
+// it); a later successful check, while suspended, re-registers the service
+// to re-probe and re-announce it.
+//
+// Recovery registers service.clone(), not service itself: Register's
+// Validate call writes InstanceName in place (NFC normalization, and
+// Rename's suffixing on a conflict), and service is the same *Service the
+// original caller passed to Register - which that caller may still be
+// holding and reading after Register returns. Registering a clone keeps
+// every write after the first Register call off the caller's copy, so
+// there's nothing left for a concurrent read of it to race with.
+func (r *Responder) runRegisterCheckMonitor(ctx context.Context, service *Service) {
+	ticker := time.NewTicker(r.registerCheckInterval)
+	defer ticker.Stop()
+
+	suspended := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := r.runRegisterCheckSafely(ctx, service)
+		switch {
+		case err != nil && !suspended:
+			if unregErr := r.unregister(ctx, service.InstanceName); unregErr == nil {
+				suspended = true
+			}
+		case err == nil && suspended:
+			if regErr := r.Register(service.clone()); regErr == nil {
+				suspended = false
+			}
+		}
+	}
+}
+
+// runRegisterCheckSafely calls r.registerCheck with a recoverPanic guard,
+// so a panicking user-supplied check can't crash runRegisterCheckMonitor's
+// goroutine and silently stop monitoring service forever. A recovered
+// panic is reported as a non-nil error, so the monitor treats it the same
+// as a failing check (suspends the service) rather than as success.
+func (r *Responder) runRegisterCheckSafely(ctx context.Context, service *Service) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.reportRecoveredPanic("registerCheck", nil, rec, debug.Stack())
+			err = fmt.Errorf("registerCheck panicked: %v", rec)
+		}
+	}()
+	return r.registerCheck(ctx, service)
+}
+
+// unregister is Unregister's implementation, parameterized on ctx so
+// Shutdown can bound the goodbye send with its own lame-duck deadline
+// instead of r.ctx.
+func (r *Responder) unregister(ctx context.Context, serviceID string) error {
 	// Lookup service to get instance name (handles both full ID and instance name)
 	svc, found := r.GetService(serviceID)
 	if !found {
@@ -248,9 +970,42 @@ func (r *Responder) Unregister(serviceID string) error {
 		return fmt.Errorf("service %q not registered", serviceID)
 	}
 
-	// TODO: Send goodbye packets (TTL=0)
-	// This requires building records with TTL=0 and sending via transport
-	// For now, just remove from registry
+	return r.sendGoodbyeForService(ctx, svc.InstanceName, svc.ServiceType)
+}
+
+// sendGoodbyeForService sends goodbye packets (TTL=0) through the machine
+// that announced instanceName/serviceType, if any. Shutdown is a no-op if
+// the machine never reached StateEstablished, so there's nothing to do
+// for a service that failed registration partway through.
+//
+// This is shared between unregister (the service was already removed
+// from the registry by the caller) and the registry's OnExpire handler
+// (the registry removed the service itself, e.g. a lease that wasn't
+// renewed).
+func (r *Responder) sendGoodbyeForService(ctx context.Context, instanceName, serviceType string) error {
+	serviceName := instanceName + "." + serviceType
+	r.machinesMu.Lock()
+	machine, ok := r.machines[serviceName]
+	delete(r.machines, serviceName)
+	r.machinesMu.Unlock()
+
+	if ok {
+		if err := machine.Shutdown(ctx, serviceName); err != nil {
+			return fmt.Errorf("failed to send goodbye for %q: %w", serviceName, err)
+		}
+		// Reflect the TTL=0 goodbye set in GetLastAnnouncedRecords, same
+		// accessor Register's normal announcement uses, so a test (or any
+		// other caller) doesn't need a second, goodbye-specific getter to
+		// see what was actually sent.
+		if sent := machine.GetAnnouncer().GetLastSentRecords(); sent != nil {
+			r.lastAnnouncedRecords = sent
+		}
+		r.notifyGoodbye(serviceName)
+	}
+
+	if r.probe != nil {
+		r.probe.UpdateStatus(serviceName, probe.StatusStopped)
+	}
 
 	return nil
 }
@@ -267,7 +1022,21 @@ func (r *Responder) Unregister(serviceID string) error {
 //
 // T043: Implement Close()
 // T080: Stop query handler
+//
+// If a BeforeStop hook (WithBeforeStop) returns an error, Close aborts
+// before touching the query handler, registered services, or transport,
+// and returns that error.
 func (r *Responder) Close() error {
+	if err := runLifecycleHooks(r.beforeStop); err != nil {
+		return fmt.Errorf("beforeStop hook: %w", err)
+	}
+
+	// Stop the interface watcher first so it can't report a change after
+	// the transport it would otherwise react to is closed.
+	if r.ifaceWatcher != nil {
+		r.ifaceWatcher.Stop()
+	}
+
 	// Stop query handler goroutine (T080)
 	close(r.queryHandlerDone)
 
@@ -278,20 +1047,158 @@ func (r *Responder) Close() error {
 		_ = r.Unregister(instanceName)
 	}
 
+	r.closeBrowser()
+
 	// Close transport
+	var closeErr error
 	if r.transport != nil {
-		return r.transport.Close()
+		closeErr = r.transport.Close()
 	}
-	return nil
+	if r.probe != nil {
+		r.probe.UpdateStatus(probeTransportComponent, probe.StatusStopped)
+	}
+
+	afterStopErr := runLifecycleHooks(r.afterStop)
+	r.doneOnce.Do(func() { close(r.done) })
+
+	return goerrors.Join(closeErr, afterStopErr)
+}
+
+// ShutdownError is returned by Shutdown when its context or LameDuckTimeout
+// expired before every registered service's goodbye packets finished
+// flushing, forcing a force-close instead.
+type ShutdownError struct {
+	// Pending lists the services (by instance name) whose goodbye packets
+	// did not finish flushing before the deadline.
+	Pending []string
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("shutdown deadline exceeded before goodbye flushed for: %s", strings.Join(e.Pending, ", "))
+}
+
+// Shutdown gracefully retires the responder per RFC 6762 §10.1/§8.4: it
+// stops accepting new registrations, sends two goodbye announcements
+// (TTL=0) one second apart for every currently registered service
+// concurrently, waits for them to drain, and only then closes the
+// transport.
+//
+// If ctx is cancelled or the responder's LameDuckTimeout (WithLameDuckTimeout,
+// defaultLameDuckTimeout if unset) elapses first, Shutdown force-closes the
+// transport immediately and returns a *ShutdownError naming the services
+// whose goodbye never finished flushing.
+//
+// If a BeforeStop hook (WithBeforeStop) returns an error, Shutdown aborts
+// before entering the lame-duck state and returns that error.
+func (r *Responder) Shutdown(ctx context.Context) error {
+	if err := runLifecycleHooks(r.beforeStop); err != nil {
+		return fmt.Errorf("beforeStop hook: %w", err)
+	}
+
+	// Stop the interface watcher first, same rationale as Close().
+	if r.ifaceWatcher != nil {
+		r.ifaceWatcher.Stop()
+	}
+
+	r.lameDuckMu.Lock()
+	r.lameDuck = true
+	r.lameDuckMu.Unlock()
+
+	close(r.queryHandlerDone)
+
+	timeout := r.lameDuckTimeout
+	if timeout <= 0 {
+		timeout = defaultLameDuckTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	services := r.registry.List()
+
+	var (
+		mu      sync.Mutex
+		flushed = make(map[string]bool, len(services))
+		wg      sync.WaitGroup
+	)
+	for _, instanceName := range services {
+		wg.Add(1)
+		go func(instanceName string) {
+			defer wg.Done()
+			defer r.recoverPanic("shutdownUnregister", nil)
+			if svc, found := r.GetService(instanceName); found {
+				r.stopRegisterCheckMonitor(svc.InstanceName + "." + svc.ServiceType)
+			}
+			if err := r.unregister(shutdownCtx, instanceName); err == nil {
+				mu.Lock()
+				flushed[instanceName] = true
+				mu.Unlock()
+			}
+		}(instanceName)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+	}
+
+	r.closeBrowser()
+
+	closeErr := r.transport.Close()
+	if r.probe != nil {
+		r.probe.UpdateStatus(probeTransportComponent, probe.StatusStopped)
+	}
+
+	mu.Lock()
+	var pending []string
+	for _, instanceName := range services {
+		if !flushed[instanceName] {
+			pending = append(pending, instanceName)
+		}
+	}
+	mu.Unlock()
+
+	afterStopErr := runLifecycleHooks(r.afterStop)
+	r.doneOnce.Do(func() { close(r.done) })
+
+	if len(pending) > 0 {
+		return goerrors.Join(&ShutdownError{Pending: pending}, afterStopErr)
+	}
+	return goerrors.Join(closeErr, afterStopErr)
+}
+
+// localAddresses returns the addresses configured on ifaces, or on every
+// interface on the host if ifaces is empty (net.InterfaceAddrs(), as used
+// before WithInterfaces existed).
+func localAddresses(ifaces []net.Interface) ([]net.Addr, error) {
+	if len(ifaces) == 0 {
+		return net.InterfaceAddrs()
+	}
+
+	var addrs []net.Addr
+	for _, iface := range ifaces {
+		ifaceAddrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("listing addresses for interface %q: %w", iface.Name, err)
+		}
+		addrs = append(addrs, ifaceAddrs...)
+	}
+	return addrs, nil
 }
 
-// getLocalIPv4 gets the first non-loopback IPv4 address.
+// getLocalIPv4 gets the first non-loopback IPv4 address among ifaces (every
+// interface on the host if ifaces is empty).
 //
 // Returns:
 //   - []byte: IPv4 address (4 bytes)
 //   - error: if no suitable address found
-func getLocalIPv4() ([]byte, error) {
-	addrs, err := net.InterfaceAddrs()
+func getLocalIPv4(ifaces []net.Interface) ([]byte, error) {
+	addrs, err := localAddresses(ifaces)
 	if err != nil {
 		return nil, err
 	}
@@ -307,6 +1214,51 @@ func getLocalIPv4() ([]byte, error) {
 	return nil, fmt.Errorf("no non-loopback IPv4 address found")
 }
 
+// getLocalIPv6 gets all non-loopback IPv6 addresses (link-local, ULA, and
+// global scope alike) among ifaces (every interface on the host if ifaces is
+// empty), one AAAA record being built per address by records.BuildRecordSet.
+//
+// Unlike getLocalIPv4, an empty result is not an error - IPv6 may simply not
+// be configured on this host.
+//
+// TODO: Beacon's transport is a single multicast socket with no per-interface
+// send, so even when WithInterfaces scopes which addresses are advertised,
+// the resulting records still go out over every joined interface rather than
+// just the ones they were derived from (RFC 6762 §11 zone-index handling).
+// responderIPv6Addresses returns getLocalIPv6(r.interfaces), or nil if
+// WithIPv6(false) disabled AAAA advertising - suppressing IPv6 address
+// records even on a host that has them configured, independent of whether
+// the Responder's own transport (networkMode) is dual-stack.
+func (r *Responder) responderIPv6Addresses() [][]byte {
+	if !r.ipv6Enabled {
+		return nil
+	}
+	return getLocalIPv6(r.interfaces)
+}
+
+func getLocalIPv6(ifaces []net.Interface) [][]byte {
+	addrs, err := localAddresses(ifaces)
+	if err != nil {
+		return nil
+	}
+
+	var ipv6s [][]byte
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || ipnet.IP.IsLoopback() {
+			continue
+		}
+		if ipnet.IP.To4() != nil {
+			continue // IPv4 address, handled by getLocalIPv4
+		}
+		if ipv6 := ipnet.IP.To16(); ipv6 != nil {
+			ipv6s = append(ipv6s, ipv6)
+		}
+	}
+
+	return ipv6s
+}
+
 // OnProbe sets a callback to be called when a probe is sent.
 //
 // US2 GREEN: Contract test support for RFC 6762 §8.1 validation
@@ -466,14 +1418,121 @@ func (r *Responder) UpdateService(serviceID string, txtRecords map[string]string
 	}
 
 	// Update TXT records
-	internalSvc.TXT = txtRecords
+	newTXT := records.TXTRecordsFromMap(txtRecords)
+	internalSvc.TXT = newTXT
+
+	// Rebuild just the TXT record (cache-flush bit always set - it's
+	// unique per service instance, see records.BuildTXTRecord) and splice
+	// it into the announcer's record set in place of the old one, then
+	// multicast it unsolicited per RFC 6762 §8.4 so peers flush their
+	// cached TXT entry instead of serving it stale until its TTL expires.
+	serviceName := svc.InstanceName + "." + svc.ServiceType
+	r.machinesMu.Lock()
+	machine, ok := r.machines[serviceName]
+	r.machinesMu.Unlock()
+	if !ok {
+		return nil
+	}
+	announcer := machine.GetAnnouncer()
+	if announcer == nil {
+		return nil
+	}
 
-	// TODO US5-LATER: Send announcement with updated TXT record
-	// For now, just updating the registry is sufficient for tests
+	newTXTRecord := records.BuildTXTRecord(&records.ServiceInfo{
+		InstanceName: svc.InstanceName,
+		ServiceType:  svc.ServiceType,
+		TXTRecords:   newTXT,
+		TTL:          r.serviceTTL,
+	})
+
+	recordSet := announcer.GetRecords()
+	for i, rr := range recordSet {
+		if rr.Type == protocol.RecordTypeTXT {
+			recordSet[i] = newTXTRecord
+			break
+		}
+	}
+	announcer.SetRecords(recordSet)
+
+	if err := announcer.SendAdditional(r.ctx); err != nil {
+		return fmt.Errorf("announcing updated TXT record for %q: %w", serviceName, err)
+	}
 
 	return nil
 }
 
+// AddEventHook registers an additional EventHook. Every subsequent Register
+// call fans its service's Prober out to hook (see Prober.AddHook) and calls
+// hook.OnRename/OnAnnounce on conflict rename and successful announcement;
+// Unregister, Close, and Shutdown call hook.OnGoodbye once a service's
+// goodbye packets finish sending. hook.OnConflictDetected is invoked only
+// if hook is also registered on a ConflictDetector via its own AddHook -
+// Responder doesn't own one itself.
+func (r *Responder) AddEventHook(hook EventHook) {
+	r.hooksMu.Lock()
+	defer r.hooksMu.Unlock()
+	r.hooks = append(r.hooks, hook)
+	r.conflictDetector.AddHook(hook)
+}
+
+// notifyRename invokes OnRename on every registered hook.
+func (r *Responder) notifyRename(oldName, newName string) {
+	r.hooksMu.Lock()
+	hooks := r.hooks
+	r.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook.OnRename(oldName, newName)
+	}
+}
+
+// notifyProbeRateLimited invokes OnProbeRateLimited on every registered hook.
+func (r *Responder) notifyProbeRateLimited(name string, delay time.Duration) {
+	r.hooksMu.Lock()
+	hooks := r.hooks
+	r.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook.OnProbeRateLimited(name, delay)
+	}
+}
+
+// notifyAnnounce invokes OnAnnounce on every registered hook.
+func (r *Responder) notifyAnnounce(serviceName string) {
+	r.hooksMu.Lock()
+	hooks := r.hooks
+	r.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook.OnAnnounce(serviceName)
+	}
+}
+
+// notifyGoodbye invokes OnGoodbye on every registered hook.
+func (r *Responder) notifyGoodbye(serviceName string) {
+	r.hooksMu.Lock()
+	hooks := r.hooks
+	r.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook.OnGoodbye(serviceName)
+	}
+}
+
+// notifyKnownAnswerSuppressed invokes OnKnownAnswerSuppressed on every
+// registered hook. Passed to responseBuilder.WithOnSuppressed in New(), so
+// it runs on whatever goroutine is building a response - the same
+// synchronous-dispatch contract every other notify* method has.
+func (r *Responder) notifyKnownAnswerSuppressed(record *message.ResourceRecord) {
+	r.hooksMu.Lock()
+	hooks := r.hooks
+	r.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook.OnKnownAnswerSuppressed(*record)
+	}
+}
+
 // InjectConflictDuringProbing is a test hook to inject conflicts during probing.
 //
 // When enabled, the state machine will always report StateConflictDetected,
@@ -484,18 +1543,67 @@ func (r *Responder) InjectConflictDuringProbing(inject bool) {
 	r.injectConflict = inject
 }
 
-// InjectSimultaneousProbe is a test hook for injecting simultaneous probe scenarios.
+// InjectSimultaneousProbe is a test hook that simulates a second host probing
+// for the same name at the same instant: the next Register attempt's Prober
+// compares ourData against theirData lexicographically per RFC 6762 §8.2.1
+// instead of waiting for a real conflicting response, and reports a conflict
+// if ourData loses. Like InjectProbeConflicts, this is consumed after one
+// attempt, so a lost tie-break's rename-and-retry (see Register's rename
+// loop) proceeds normally and succeeds rather than tie-breaking forever.
 //
-// This method is currently a stub placeholder for future simultaneous probe testing
-// per RFC 6762 §8.2 tiebreaking. It will be implemented when detailed conflict
-// resolution testing is added.
+// T062: Test hook infrastructure for conflict scenarios
+func (r *Responder) InjectSimultaneousProbe(ourData, theirData []byte) {
+	r.injectMu.Lock()
+	defer r.injectMu.Unlock()
+	r.injectSimultaneousProbeOurData = ourData
+	r.injectSimultaneousProbeTheirData = theirData
+}
+
+// InjectProbeConflicts is a test hook that forces the next n probe attempts
+// Register makes to report state.StateConflictDetected, regardless of the
+// real probe outcome, then lets probing behave normally again.
 //
-// Parameters:
-//   - First parameter: Our probe packet (currently unused)
-//   - Second parameter: Incoming probe packet (currently unused)
+// Unlike InjectConflictDuringProbing above (which, once enabled, forces
+// every attempt to conflict until explicitly disabled), this counts down:
+// it lets a test express "conflict on the first n attempts, then succeed",
+// exercising the rename loop's success path - e.g. verifying a service
+// renamed to "My Service-2" registers cleanly - rather than only its
+// max-rename-attempts failure path.
 //
-// T062: Test hook infrastructure for conflict scenarios
-func (r *Responder) InjectSimultaneousProbe([]byte, []byte) {}
+// Like InjectConflictDuringProbing and InjectSimultaneousProbe, this is a
+// plain exported method with no build-tag gating: it is test-hook
+// infrastructure that happens to live on the public Responder type, matching
+// the existing precedent set by those two methods rather than introducing a
+// new, inconsistent gating scheme for this one feature.
+func (r *Responder) InjectProbeConflicts(n int) {
+	r.injectMu.Lock()
+	defer r.injectMu.Unlock()
+	r.injectProbeConflicts = n
+}
+
+// InjectAnnounceLoss is a test hook that causes the next n announcement
+// sends (across Announce's two RFC 6762 §8.3 multicasts, and across rename
+// attempts if the budget outlives one) to be silently dropped - the
+// Announcer builds the message and waits out the normal inter-announcement
+// interval, but never hands it to the transport - simulating a lossy
+// network rather than a conflict. Once the budget is exhausted, sends
+// resume normally.
+//
+// See InjectProbeConflicts above for why this is an ungated exported method.
+func (r *Responder) InjectAnnounceLoss(n int) {
+	r.injectMu.Lock()
+	defer r.injectMu.Unlock()
+	r.injectAnnounceLoss = n
+}
+
+// HandlePacket is a test hook that runs packet through the same query
+// handling path runQueryHandler feeds every packet it receives from the
+// transport, without going through an actual socket. This lets fuzz targets
+// (e.g. FuzzHandlePacket) and tests drive handleQuery directly with
+// arbitrary bytes.
+func (r *Responder) HandlePacket(packet []byte) error {
+	return r.handleQuery(packet, nil, r.transport)
+}
 
 // ResourceRecord is a type alias for records.ResourceRecord.
 //
@@ -526,7 +1634,33 @@ type ResourceRecord = records.ResourceRecord
 //  6. Send response (unicast or multicast based on QU bit)
 //
 // T080: Query handler goroutine
+//
+// When WithTransports configured transportPlugins, runQueryHandler instead
+// spawns one goroutine per plugin (each running the same receive loop
+// against that plugin's own Transport) and waits for all of them, so a
+// multi-homed Responder keeps answering on every interface even if one
+// plugin's Transport errors out or is closed independently.
 func (r *Responder) runQueryHandler() {
+	if len(r.transportPlugins) == 0 {
+		r.runQueryHandlerOn(r.transport)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, plugin := range r.transportPlugins {
+		wg.Add(1)
+		go func(via transport.Transport) {
+			defer wg.Done()
+			r.runQueryHandlerOn(via)
+		}(plugin.Transport)
+	}
+	wg.Wait()
+}
+
+// runQueryHandlerOn receives queries from via until r.ctx or
+// r.queryHandlerDone fires, handling each one through handleQuerySafely
+// with via as the transport replies should be sent back out on.
+func (r *Responder) runQueryHandlerOn(via transport.Transport) {
 	for {
 		select {
 		case <-r.ctx.Done():
@@ -535,7 +1669,7 @@ func (r *Responder) runQueryHandler() {
 			return
 		default:
 			// Receive query with timeout
-			packet, _, err := r.transport.Receive(r.ctx)
+			packet, src, err := via.Receive(r.ctx)
 			if err != nil {
 				// Context cancelled or transport closed
 				select {
@@ -545,16 +1679,28 @@ func (r *Responder) runQueryHandler() {
 					return
 				default:
 					// Other error - continue receiving
+					r.panicLogger().Debug("transport receive error", "error", err)
 					continue
 				}
 			}
 
-			// Handle query (T079)
-			_ = r.handleQuery(packet)
+			// Handle query (T079). Recovered via handleQuerySafely so a
+			// malformed packet that panics deep in parsing/response-building
+			// can't take down this long-running handler goroutine.
+			r.handleQuerySafely(packet, src, via)
 		}
 	}
 }
 
+// handleQuerySafely calls handleQuery with a recoverPanic guard, so a
+// single malformed or adversarial packet can't crash runQueryHandler's
+// goroutine - every subsequent query would otherwise go unanswered for the
+// life of the process.
+func (r *Responder) handleQuerySafely(packet []byte, src net.Addr, via transport.Transport) {
+	defer r.recoverPanic("handleQuery", src)
+	_ = r.handleQuery(packet, src, via)
+}
+
 // handleQuery processes a single mDNS query and sends response.
 //
 // RFC 6762 §6: "When a Multicast DNS responder receives a query, it must determine
@@ -573,95 +1719,767 @@ func (r *Responder) runQueryHandler() {
 //   - error: parse error or send error (logged, not propagated)
 //
 // T079: Implement handleQuery()
-func (r *Responder) handleQuery(packet []byte) error {
+func (r *Responder) handleQuery(packet []byte, src net.Addr, via transport.Transport) error {
 	// Import message parser
 	msg, err := parseMessage(packet)
 	if err != nil {
 		// Malformed query - ignore per RFC 6762 §6
+		r.panicLogger().Debug("ignoring malformed mDNS packet", "source", src, "error", err)
 		return err
 	}
 
-	// Ignore responses (QR=1)
+	// Responses (QR=1) aren't for answerQuestions - they're answers to
+	// someone's query, including our own Browse sessions' if any are
+	// running. browser is nil until the first Browse() call, so this is a
+	// no-op for a Responder that never browses.
 	if msg.Header.IsResponse() {
+		if r.passiveCache != nil {
+			for _, rr := range convertAnswersToResourceRecords(msg.Answers) {
+				r.passiveCache.ObserveRecord(*rr)
+			}
+		}
+		if b := r.browserOrNil(); b != nil {
+			b.Dispatch(msg)
+		}
+		return nil
+	}
+
+	if r.tapActive() {
+		r.tap.OnQuery(packet, src, nil, time.Now())
+	}
+
+	// RFC 6762 §7.2: a truncated (TC=1) query announces that its
+	// Known-Answer list continues in further packets sharing this
+	// transaction ID. Buffer it rather than answering immediately -
+	// onKnownAnswersReady answers once a later packet merges in (or, if
+	// none ever arrives, once the cache's hold timer gives up waiting).
+	// knownAnswerCache is nil for a Responder built directly rather than
+	// via New() (as several tests do); treat that the same as "nothing
+	// buffered" rather than panicking.
+	if r.knownAnswerCache != nil && msg.Header.IsTruncated() {
+		r.knownAnswerCache.Merge(addrKey(src), msg.Header.ID, msg.Questions, convertAnswersToResourceRecords(msg.Answers))
 		return nil
 	}
 
-	// Process each question
-	for _, question := range msg.Questions {
-		// Only handle PTR queries for now (T076 implementation)
+	// This packet isn't truncated, but it may itself be the final packet
+	// of a sequence that started with one or more TC=1 packets - fold in
+	// anything already buffered for this (source, transaction ID) before
+	// answering, so suppression sees the union of every packet's
+	// Known-Answer list rather than just this one's.
+	if r.knownAnswerCache != nil {
+		if questions, knownAnswers, found := r.knownAnswerCache.Take(addrKey(src), msg.Header.ID); found {
+			msg.Questions = append(questions, msg.Questions...)
+			msg.Answers = append(resourceRecordsToAnswers(knownAnswers), msg.Answers...)
+		}
+	}
+
+	r.answerQuestions(msg, src, via)
+
+	return nil
+}
+
+// pluginFor returns the TransportPlugin whose Transport is via, so
+// answerQuestions can advertise that plugin's own IPv4/IPv6 address instead
+// of getLocalIPv4(r.interfaces)'s host-wide first address. Returns false
+// when transportPlugins is empty (the single-transport case) or via doesn't
+// match any configured plugin.
+func (r *Responder) pluginFor(via transport.Transport) (TransportPlugin, bool) {
+	for _, plugin := range r.transportPlugins {
+		if plugin.Transport == via {
+			return plugin, true
+		}
+	}
+	return TransportPlugin{}, false
+}
+
+// answerQuestions processes every question in query against the registry and
+// sends a response for each match, the way handleQuery always has. It's
+// split out so the Known-Answer reassembly fallback in
+// onKnownAnswersReady - which has no packet to parse, only a query message
+// merged from buffered continuation packets - can answer through the exact
+// same path as the immediate, single-packet case.
+func (r *Responder) answerQuestions(query *message.DNSMessage, src net.Addr, via transport.Transport) {
+	for _, question := range query.Questions {
+		// A direct A/AAAA query against our own hostname is the one
+		// non-PTR case answered here - including, per RFC 6762 §6.1, with
+		// an NSEC record when the hostname is ours but the queried type
+		// isn't. A direct SRV/TXT question for a registered service's own
+		// owner name (T076's remaining gap) still falls through to the
+		// PTR-only matching below and goes unanswered, since every
+		// registered service's SRV/TXT are always built together - there's
+		// no "exists but not this type" case for them the way there is for
+		// a host that's A-only or AAAA-only.
+		if question.QTYPE == uint16(protocol.RecordTypeA) || question.QTYPE == uint16(protocol.RecordTypeAAAA) {
+			r.answerHostAddressQuestion(question, query, src, via)
+			continue
+		}
+
 		if question.QTYPE != uint16(protocol.RecordTypePTR) {
 			continue
 		}
 
+		// RFC 6763 §9: "_services._dns-sd._udp.local" is a meta-query for the
+		// list of unique service types currently advertised, answered
+		// separately from the per-service-type matching below.
+		if question.QNAME == protocol.ServiceTypeEnumerationName {
+			r.respondServiceTypeEnum(question, query, src, via)
+			continue
+		}
+
 		// Check if we have a service matching this query
 		// Query is for "_http._tcp.local", we need to find services of that type
 		serviceType := question.QNAME
 
-		// Get all registered services
-		services := r.registry.List()
-		for _, instanceName := range services {
-			service, found := r.registry.Get(instanceName)
-			if !found {
-				continue
-			}
-
-			// Check if service type matches query
-			if service.ServiceType != serviceType {
-				continue
-			}
+		// RFC 6763 §7.1 subtype queries ("<subtype>._sub.<servicetype>")
+		// can't use the exact-type index, since the service's
+		// advertised ServiceType never equals the subtype query name;
+		// everything else hits registry.ListByType's indexed fast path.
+		var services []*responder.Service
+		if strings.Contains(serviceType, "._sub.") {
+			services = r.registry.Query(responder.Query{Subtype: serviceType})
+		} else {
+			services = r.registry.ListByType(serviceType)
+		}
 
+		for _, service := range services {
 			// We have a match! Build response
-			// Convert to ServiceWithIP for ResponseBuilder
-			ipv4, err := getLocalIPv4()
-			if err != nil {
-				continue
+			// Convert to ServiceWithIP for ResponseBuilder. A plugin
+			// matching via (i.e. a multi-homed Responder configured with
+			// WithTransports) advertises its own interface's address
+			// instead of the host-wide getLocalIPv4(r.interfaces)/
+			// responderIPv6Addresses(), so a query answered on eth0 carries
+			// eth0's address rather than whichever interface happened to be
+			// first.
+			ipv4 := []byte(nil)
+			ipv6 := r.responderIPv6Addresses()
+			if plugin, ok := r.pluginFor(via); ok {
+				ipv4 = plugin.IPv4
+				ipv6 = plugin.IPv6
+			} else {
+				var err error
+				ipv4, err = getLocalIPv4(r.interfaces)
+				if err != nil {
+					continue
+				}
 			}
 
 			serviceWithIP := &responder.ServiceWithIP{
-				InstanceName: service.InstanceName,
-				ServiceType:  service.ServiceType,
-				Domain:       "local",
-				Port:         service.Port,
-				IPv4Address:  ipv4,
-				TXTRecords:   service.TXT, // internal.Service uses TXT field
-				Hostname:     r.hostname,
+				InstanceName:  service.InstanceName,
+				ServiceType:   service.ServiceType,
+				Domain:        r.zone,
+				Port:          service.Port,
+				IPv4Address:   ipv4,
+				IPv6Addresses: ipv6,
+				TXTRecords:    service.TXT, // internal.Service uses TXT field
+				Hostname:      r.hostname,
 			}
 
 			// Build response (T076)
-			response, err := r.responseBuilder.BuildResponse(serviceWithIP, msg)
+			response, err := r.responseBuilder.BuildResponse(serviceWithIP, query)
 			if err != nil {
 				continue
 			}
 
-			// TODO: T082 - Implement QU bit + 1/4 TTL logic for unicast vs multicast
-			// For now, always multicast
+			// Per-record rate limiting (RFC 6762 §6.2) happens inside
+			// sendQueryResponse, once it knows the response is actually
+			// going out multicast rather than unicast to a QU-bit querier.
 
-			// TODO: T083 - Apply per-record rate limiting before sending
-			// For now, skip rate limiting
-
-			// Send response via multicast
-			responsePacket := buildResponsePacket(response)
-			_ = r.transport.Send(r.ctx, responsePacket, nil) // nil = multicast
+			r.sendQueryResponse(response, question, src, via)
 
 			// Only respond once per query
 			break
 		}
 	}
+}
 
-	return nil
+// answerHostAddressQuestion answers a direct A/AAAA query for this
+// responder's own hostname. If this host actually has the queried address
+// type, it's answered directly; if the hostname is ours but the queried
+// type isn't one we have (e.g. an AAAA query against an IPv4-only host),
+// RFC 6762 §6.1 calls for an NSEC record listing the type(s) we DO have in
+// the Additional section instead, so the querier can cache the negative
+// answer rather than re-querying. A question for any other name isn't
+// answered here - this responder has no standalone claim on it outside a
+// PTR-matched service's own address records.
+func (r *Responder) answerHostAddressQuestion(question message.Question, query *message.DNSMessage, src net.Addr, via transport.Transport) {
+	if !strings.EqualFold(question.QNAME, r.hostname) {
+		return
+	}
+
+	// Same per-plugin address selection as the PTR-matched path above, so a
+	// multi-homed Responder answers a direct query on eth0 with eth0's own
+	// address rather than whichever interface happened to be first.
+	ipv4 := []byte(nil)
+	ipv6 := r.responderIPv6Addresses()
+	if plugin, ok := r.pluginFor(via); ok {
+		ipv4 = plugin.IPv4
+		ipv6 = plugin.IPv6
+	} else if addr, err := getLocalIPv4(r.interfaces); err == nil {
+		ipv4 = addr
+	}
+
+	var existingTypes []protocol.RecordType
+	if len(ipv4) == 4 {
+		existingTypes = append(existingTypes, protocol.RecordTypeA)
+	}
+	if len(ipv6) > 0 {
+		existingTypes = append(existingTypes, protocol.RecordTypeAAAA)
+	}
+	if len(existingTypes) == 0 {
+		// No address of any kind to be authoritative about yet (e.g. no
+		// interface is up) - nothing to answer, positive or negative.
+		return
+	}
+
+	response := &message.DNSMessage{
+		Header:      message.DNSHeader{ID: query.Header.ID, Flags: 0x8400}, // QR=1, AA=1
+		Questions:   []message.Question{},
+		Authorities: []message.Answer{},
+		Additionals: []message.Answer{},
+	}
+
+	// RFC 6762 §7.1: don't answer with a record the query's own
+	// Known-Answer section already lists at a fresh-enough TTL - the same
+	// suppression BuildResponse applies to the PTR-matched path below.
+	knownAnswers := convertAnswersToResourceRecords(query.Answers)
+
+	switch {
+	case question.QTYPE == uint16(protocol.RecordTypeA) && len(ipv4) == 4:
+		response.Answers = resourceRecordsToAnswers(r.suppressKnownAnswers(records.BuildHostAddressRecords(r.hostname, ipv4, nil), knownAnswers))
+	case question.QTYPE == uint16(protocol.RecordTypeAAAA) && len(ipv6) > 0:
+		response.Answers = resourceRecordsToAnswers(r.suppressKnownAnswers(records.BuildHostAddressRecords(r.hostname, nil, ipv6), knownAnswers))
+	default:
+		nsec, err := records.BuildNSECRecord(r.hostname, protocol.TTLHostname, existingTypes)
+		if err != nil {
+			return
+		}
+		response.Additionals = resourceRecordsToAnswers([]*message.ResourceRecord{nsec})
+	}
+
+	if len(response.Answers) == 0 && len(response.Additionals) == 0 {
+		// Known-answer suppression left nothing worth sending - e.g. the
+		// query already listed our own A record at a fresh-enough TTL.
+		return
+	}
+
+	response.Header.ANCount = uint16(len(response.Answers))
+	response.Header.ARCount = uint16(len(response.Additionals))
+
+	r.sendQueryResponse(response, question, src, via)
+}
+
+// suppressKnownAnswers filters candidates down to those ResponseBuilder's
+// ApplyKnownAnswerSuppression says are still worth sending, given
+// knownAnswers from the query's own Answer section - the same RFC 6762
+// §7.1 rule the PTR-matched path in answerQuestions gets via
+// BuildResponse, applied here for answerHostAddressQuestion's direct
+// A/AAAA answers.
+func (r *Responder) suppressKnownAnswers(candidates []*message.ResourceRecord, knownAnswers []*message.ResourceRecord) []*message.ResourceRecord {
+	out := make([]*message.ResourceRecord, 0, len(candidates))
+	for _, rr := range candidates {
+		if r.responseBuilder.ApplyKnownAnswerSuppression(rr, knownAnswers) {
+			out = append(out, rr)
+		}
+	}
+	return out
+}
+
+// onKnownAnswersReady answers a query reassembled from a truncated packet
+// plus its Known-Answer continuation packets (RFC 6762 §7.2), once
+// knownAnswerCache's hold timer concludes no more are coming. There is no
+// live net.Addr for this path - it's reconstructed from the cache's
+// string-keyed source address, which is the addr.String() handleQuery's
+// addrKey recorded when the packets arrived.
+//
+// knownAnswerCache doesn't track which plugin's Transport a buffered
+// continuation packet arrived on, so this always answers through
+// r.transport rather than a specific plugin - a multi-homed Responder
+// configured via WithTransports answers a TC=1 query's reassembled
+// continuation on whichever plugin pluginGroupTransport's broadcast Send
+// reaches, rather than the originating interface specifically.
+func (r *Responder) onKnownAnswersReady(sourceAddr string, id uint16, questions []message.Question, knownAnswers []*message.ResourceRecord) {
+	var src net.Addr
+	if sourceAddr != "" {
+		addr, err := net.ResolveUDPAddr("udp", sourceAddr)
+		if err != nil {
+			return
+		}
+		src = addr
+	}
+
+	query := &message.DNSMessage{
+		Header:    message.DNSHeader{ID: id},
+		Questions: questions,
+		Answers:   resourceRecordsToAnswers(knownAnswers),
+	}
+	r.answerQuestions(query, src, r.transport)
+}
+
+// addrKey returns src.String(), or "" for a nil src (e.g. HandlePacket's
+// loopback test harness, which has no real source address) - the string
+// knownAnswerCache indexes continuation packets under.
+func addrKey(src net.Addr) string {
+	if src == nil {
+		return ""
+	}
+	return src.String()
+}
+
+// respondServiceTypeEnum answers an RFC 6763 §9 service-type enumeration
+// query ("_services._dns-sd._udp.local") with one PTR record per unique
+// registered service type, deduplicated via r.registry.ListServiceTypes. An
+// empty registry still gets a response, with ANCount=0 - §9 treats an empty
+// answer as acceptable, and it tells a querier this responder has nothing
+// registered rather than leaving it to assume the query was lost.
+//
+// Unlike handleQuery's per-service-type path, this doesn't go through
+// ResponseBuilder: the answer set isn't a single service's record set, just
+// a flat list of PTR records, so the message is assembled directly.
+//
+// question and src are the enumeration question and the querier's address,
+// passed to unicastDest to decide the response destination the same way
+// handleQuery's per-service-type path does.
+func (r *Responder) respondServiceTypeEnum(question message.Question, query *message.DNSMessage, src net.Addr, via transport.Transport) {
+	serviceTypes := r.registry.ListServiceTypes()
+
+	response := &message.DNSMessage{
+		Header: message.DNSHeader{
+			ID:    query.Header.ID,
+			Flags: 0x8400, // QR=1, AA=1
+		},
+		Questions:   []message.Question{},
+		Authorities: []message.Answer{},
+		Additionals: []message.Answer{},
+	}
+
+	for _, serviceType := range serviceTypes {
+		rr, err := records.BuildServiceTypeEnumRecord(serviceType)
+		if err != nil {
+			continue
+		}
+		response.Answers = append(response.Answers, message.Answer{
+			NAME:     rr.Name,
+			TYPE:     uint16(rr.Type),
+			CLASS:    uint16(rr.Class),
+			TTL:      rr.TTL,
+			RDLENGTH: uint16(len(rr.Data)),
+			RDATA:    rr.Data,
+		})
+	}
+	response.Header.ANCount = uint16(len(response.Answers))
+
+	r.sendQueryResponse(response, question, src, via)
+}
+
+// sendQueryResponse serializes response, sends it to unicastDest's chosen
+// destination (src if the QU bit is honored, nil for multicast), and records
+// the send for RFC 6762 §5.4's 1/4 TTL tracking and tap observability.
+// handleQuery and respondServiceTypeEnum share this rather than each
+// inlining it, so a future change here (e.g. T083's per-record rate
+// limiting) can't be applied to one call site and silently missed on the
+// other.
+//
+// RFC 6762 §6: "information that is not unique [...] may produce a flood
+// of simultaneous responses from multiple hosts on the network, all
+// contending for the same bandwidth [...] a Multicast DNS responder SHOULD
+// delay its response by a small amount of time chosen with uniform random
+// distribution in the range 20-120 ms". A multicast response carrying only
+// unique (cache-flush) records skips the delay - it's already answering a
+// direct, non-colliding query for records this responder alone owns.
+//
+// via is the Transport the query was received on (r.transport for a
+// single-transport Responder, or a specific TransportPlugin's Transport for
+// one configured via WithTransports), and is what the reply is sent back
+// out on - so a multi-homed Responder answers each query on the interface
+// it arrived from rather than always through a single shared transport.
+func (r *Responder) sendQueryResponse(response *message.DNSMessage, question message.Question, src net.Addr, via transport.Transport) {
+	legacyUnicast := isLegacyUnicastSource(src)
+	if legacyUnicast {
+		// RFC 6762 §6.7: a query from a non-5353 source port came from a
+		// conventional unicast resolver, not another mDNS responder - it
+		// must get a unicast reply regardless of the QU bit, with the
+		// question section copied back (conventional unicast DNS replies
+		// echo the question, unlike RFC 6762 §6's question-less multicast
+		// responses) and every answer's TTL clamped to at most
+		// legacyUnicastMaxTTL, so a resolver that caches by the reply's own
+		// TTL rather than re-querying doesn't hold an mDNS-lifetime answer
+		// long after the underlying record changes.
+		response.Questions = []message.Question{question}
+		clampLegacyUnicastTTLs(response)
+	}
+
+	responseRecords := responseResourceRecords(response)
+	dest := src
+	if !legacyUnicast {
+		dest = r.unicastDest(question, src, responseRecords)
+	}
+
+	// RFC 6762 §6/§6.2: only the shared multicast channel is rate-limited -
+	// a direct unicast reply (legacy or QU-bit) never repeats onto the
+	// wire the way an unsolicited multicast would, so it always goes out
+	// in full.
+	if dest == nil {
+		hadAnswers := len(response.Answers) > 0
+		response.Answers = r.rateLimitAnswers(response.Answers)
+		// Only bail out when rate limiting suppressed every answer -
+		// respondServiceTypeEnum's deliberate ANCount=0 response for an
+		// empty registry (RFC 6763 §9) must still go out.
+		if hadAnswers && len(response.Answers) == 0 {
+			return
+		}
+		response.Additionals = r.rateLimitAnswers(response.Additionals)
+	}
+
+	responsePacket := buildResponsePacket(response)
+	if responsePacket == nil {
+		return
+	}
+
+	if !responseFitsUDP(responsePacket, dest) {
+		truncated := buildTruncatedResponsePacket(response)
+		if truncated == nil {
+			return
+		}
+		responsePacket = truncated
+	}
+
+	send := func() {
+		if sendErr := via.Send(r.ctx, responsePacket, dest); sendErr == nil {
+			if dest == nil && r.recordSet != nil {
+				for _, rr := range responseRecords {
+					r.recordSet.RecordMulticast(rr, responderInterfaceID)
+				}
+			}
+			if r.tapActive() {
+				r.tap.OnResponse(responsePacket, nil, dest, time.Now())
+			}
+		}
+	}
+
+	if dest == nil && hasSharedRecord(responseRecords) {
+		go func() {
+			select {
+			case <-time.After(sharedResponseDelay()):
+				send()
+			case <-r.ctx.Done():
+			}
+		}()
+		return
+	}
+
+	send()
+}
+
+// sharedResponseDelayMin and sharedResponseDelayMax bound RFC 6762 §6's
+// randomized delay before multicasting a response containing a shared
+// (non-cache-flush) record, so simultaneous responders on the link don't
+// all answer in lockstep and collide.
+const (
+	sharedResponseDelayMin = 20 * time.Millisecond
+	sharedResponseDelayMax = 120 * time.Millisecond
+)
+
+// hasSharedRecord reports whether any of records lacks the cache-flush bit -
+// RFC 6762 §10.2's marker for a record this responder has sole ownership
+// of. A PTR answer (always shared, since multiple instances can coexist
+// under one service type) triggers sendQueryResponse's delay; an all-unique
+// A/AAAA/SRV/TXT answer set does not.
+func hasSharedRecord(records []*message.ResourceRecord) bool {
+	for _, rr := range records {
+		if !rr.CacheFlush {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedResponseDelay returns a random duration in
+// [sharedResponseDelayMin, sharedResponseDelayMax) per RFC 6762 §6.
+func sharedResponseDelay() time.Duration {
+	span := sharedResponseDelayMax - sharedResponseDelayMin
+	return sharedResponseDelayMin + time.Duration(rand.Int63n(int64(span))) //nolint:gosec // G404: timing jitter, not security-sensitive
+}
+
+// udpResponseMTU is the response size above which sendQueryResponse falls
+// back to RFC 6762 §18's TC bit instead of sending the full packet: a
+// 1500-byte Ethernet MTU minus a 20-byte IPv4 header and an 8-byte UDP
+// header. A response already addressed to a *net.TCPAddr (a querier that
+// already reconnected after seeing TC) has no such ceiling -
+// tcpMaxMessageSize's much larger limit applies there instead, so
+// responseFitsUDP treats it as always fitting.
+const udpResponseMTU = 1472
+
+// responseFitsUDP reports whether packet can be sent over UDP as-is, or
+// whether sendQueryResponse must fall back to a truncated, TC-bit response
+// instead. A response going out over TCP already passed through the
+// fallback path once (dest is the querier's TCP connection), so it never
+// needs truncating again.
+func responseFitsUDP(packet []byte, dest net.Addr) bool {
+	if _, ok := dest.(*net.TCPAddr); ok {
+		return true
+	}
+	return len(packet) <= udpResponseMTU
+}
+
+// buildTruncatedResponsePacket builds the RFC 6762 §18.5 TC-bit signal
+// sendQueryResponse falls back to when the full response wouldn't fit in
+// udpResponseMTU: response's header with the TC bit set and every section
+// emptied, so a querier that receives it knows to open a TCP connection to
+// fetch the full, uncompressed record set rather than believing the empty
+// answer section is the complete answer.
+func buildTruncatedResponsePacket(response *message.DNSMessage) []byte {
+	truncated := *response
+	truncated.Header.Flags |= protocol.FlagTC
+	truncated.Header.ANCount = 0
+	truncated.Header.NSCount = 0
+	truncated.Header.ARCount = 0
+	truncated.Answers = nil
+	truncated.Authorities = nil
+	truncated.Additionals = nil
+	return buildResponsePacket(&truncated)
+}
+
+// isLegacyUnicastSource reports whether src is a non-5353 UDP source port -
+// RFC 6762 §6.7's signal that a query arrived from a conventional
+// unicast-only DNS resolver rather than another mDNS responder/querier.
+func isLegacyUnicastSource(src net.Addr) bool {
+	udpAddr, ok := src.(*net.UDPAddr)
+	return ok && udpAddr.Port != protocol.Port
+}
+
+// legacyUnicastMaxTTL is the ceiling RFC 6762 §6.7 imposes on every answer's
+// TTL when replying to a legacy unicast resolver (see
+// sendQueryResponse/isLegacyUnicastSource).
+const legacyUnicastMaxTTL = 10
+
+// clampLegacyUnicastTTLs lowers every Answer/Additional record's TTL in
+// response to at most legacyUnicastMaxTTL, in place.
+func clampLegacyUnicastTTLs(response *message.DNSMessage) {
+	for i := range response.Answers {
+		if response.Answers[i].TTL > legacyUnicastMaxTTL {
+			response.Answers[i].TTL = legacyUnicastMaxTTL
+		}
+	}
+	for i := range response.Additionals {
+		if response.Additionals[i].TTL > legacyUnicastMaxTTL {
+			response.Additionals[i].TTL = legacyUnicastMaxTTL
+		}
+	}
+}
+
+// responderInterfaceID is the interfaceID records.RecordSet buckets are
+// keyed under for tracking when a record was last multicast (see
+// unicastDest's 1/4 TTL check). Like state.Announcer and browser.Browser,
+// the responder sends through a single transport regardless of how many
+// interfaces responder.WithInterfaces scopes addresses to, so there is only
+// one multicast path to track.
+const responderInterfaceID = "default"
+
+// unicastDest returns src if question's RFC 6762 §5.4 QU bit (QCLASS bit 15)
+// is set, r.unicastResponseEnabled allows honoring it, src is known, and none
+// of responseRecords has gone stale in peer caches - or nil (multicast)
+// otherwise.
+//
+// This supersedes a fixed post-startup window: rather than always
+// multicasting for the first 10 seconds after the responder starts,
+// recordStaleSinceLastMulticast treats a record that has never been
+// multicast at all as stale, which forces multicast for every record's
+// first response regardless of how long the responder has been running -
+// the same outcome §5.4's startup guidance is after, without a wall-clock
+// special case.
+//
+// RFC 6762 §5.4: "if the responder has not multicast that record recently
+// (within one quarter of its TTL), then the responder SHOULD instead
+// multicast the response so as to keep all the peer caches up to date, and
+// to permit passive conflict detection." This is checked across every
+// record the response carries, not just the first - a response answering a
+// fresh PTR plus a stale SRV/A should multicast so the stale records reach
+// every peer's cache too, not just the querier that sent the QU bit. An
+// empty responseRecords or a Responder without a recordSet (e.g. one built
+// directly rather than via New()) always honors the QU bit instead.
+//
+// This is this package's whole unicast-vs-multicast routing decision;
+// sendQueryResponse calls it and builds the answer records (with their
+// cache-flush bits) around the result, rather than through a separate
+// exported planning type.
+func (r *Responder) unicastDest(question message.Question, src net.Addr, responseRecords []*message.ResourceRecord) net.Addr {
+	const quBit = 0x8000
+	if !r.unicastResponseEnabled || question.QCLASS&quBit == 0 || src == nil {
+		return nil
+	}
+	for _, rr := range responseRecords {
+		if r.recordStaleSinceLastMulticast(rr) {
+			return nil
+		}
+	}
+	return src
+}
+
+// recordStaleSinceLastMulticast reports whether rr hasn't been multicast
+// within the last TTL/4 (RFC 6762 §5.4), including never having been
+// multicast at all. Returns false (never force multicast) when rr is nil or
+// r.recordSet is unset.
+func (r *Responder) recordStaleSinceLastMulticast(rr *message.ResourceRecord) bool {
+	if rr == nil || r.recordSet == nil {
+		return false
+	}
+	last, ok := r.recordSet.GetLastMulticast(rr, responderInterfaceID)
+	if !ok {
+		return true
+	}
+	return time.Since(last) >= time.Duration(rr.TTL)*time.Second/4
+}
+
+// rateLimitAnswers filters answers down to the ones sendQueryResponse's
+// multicast path (dest == nil) may actually send, per RFC 6762 §6.2: a
+// record already multicast within its bucket's interval is suppressed
+// unless recordStaleSinceLastMulticast's TTL/4 override forces it through
+// regardless, the same staleness rule unicastDest applies on the unicast
+// side. r.rateLimitObserver, if set, is told about every answer either way.
+//
+// This only ever drops entries - it never reorders or adds any - so it's
+// safe to call on both the Answer and Additional sections independently.
+func (r *Responder) rateLimitAnswers(answers []message.Answer) []message.Answer {
+	if r.recordSet == nil {
+		return answers
+	}
+
+	kept := answers[:0]
+	for _, a := range answers {
+		rr := answerToResourceRecord(a)
+		allowed := r.recordSet.CanMulticast(rr, responderInterfaceID) || r.recordStaleSinceLastMulticast(rr)
+		if r.rateLimitObserver != nil {
+			r.rateLimitObserver(rr, !allowed)
+		}
+		if allowed {
+			kept = append(kept, a)
+		} else {
+			r.panicLogger().Debug("rate limit: suppressing repeated answer", "name", rr.Name, "type", rr.Type)
+		}
+	}
+	return kept
+}
+
+// responseResourceRecords converts every record in response's Answer and
+// Additional sections to *message.ResourceRecord, for unicastDest's 1/4 TTL
+// check and sendQueryResponse's post-multicast bookkeeping.
+func responseResourceRecords(response *message.DNSMessage) []*message.ResourceRecord {
+	records := make([]*message.ResourceRecord, 0, len(response.Answers)+len(response.Additionals))
+	for _, a := range response.Answers {
+		records = append(records, answerToResourceRecord(a))
+	}
+	for _, a := range response.Additionals {
+		records = append(records, answerToResourceRecord(a))
+	}
+	return records
+}
+
+// answerToResourceRecord converts a message.Answer (the wire-oriented shape
+// DNSMessage sections use) to a *message.ResourceRecord (the shape
+// records.RecordSet and known-answer suppression operate on).
+func answerToResourceRecord(a message.Answer) *message.ResourceRecord {
+	return &message.ResourceRecord{
+		Name:       a.NAME,
+		Type:       protocol.RecordType(a.TYPE),
+		Class:      protocol.DNSClass(a.CLASS),
+		TTL:        a.TTL,
+		Data:       a.RDATA,
+		CacheFlush: (a.CLASS & 0x8000) != 0,
+	}
+}
+
+// convertAnswersToResourceRecords converts a query's Answer section (its
+// Known-Answer list, in RFC 6762 §7.1 terms) to *message.ResourceRecord,
+// matching answerToResourceRecord's conversion so knownAnswerCache stores the
+// same representation handleQuery's immediate path builds inline.
+func convertAnswersToResourceRecords(answers []message.Answer) []*message.ResourceRecord {
+	out := make([]*message.ResourceRecord, 0, len(answers))
+	for _, a := range answers {
+		out = append(out, answerToResourceRecord(a))
+	}
+	return out
+}
+
+// resourceRecordsToAnswers is convertAnswersToResourceRecords' inverse, used
+// to fold knownAnswerCache's reassembled Known-Answer list back into a
+// message.DNSMessage's Answer section so a multi-packet query, once merged,
+// flows through answerQuestions/ResponseBuilder.BuildResponse exactly like a
+// single-packet one.
+func resourceRecordsToAnswers(rrs []*message.ResourceRecord) []message.Answer {
+	out := make([]message.Answer, 0, len(rrs))
+	for _, rr := range rrs {
+		out = append(out, message.Answer{
+			NAME:     rr.Name,
+			TYPE:     uint16(rr.Type),
+			CLASS:    uint16(rr.Class),
+			TTL:      rr.TTL,
+			RDLENGTH: uint16(len(rr.Data)),
+			RDATA:    rr.Data,
+		})
+	}
+	return out
 }
 
 // parseMessage is a wrapper around message.ParseMessage for easier imports.
+// message.ParseMessage is this package's sole inbound entry point for both
+// queries and responses off the wire - it decompresses names per RFC 1035
+// §4.1.4 (with a bounded pointer-hop count and 255/63-byte length checks),
+// decodes RDATA per type (A/AAAA to net.netip.Addr, PTR/NS/CNAME to a
+// domain name, SRV to its priority/weight/port/target, TXT to key/value
+// pairs per RFC 6763 §6), and splits a Question's QU bit out of QCLASS.
+// handleQuery calls this to get the msg.Questions answerQuestions then
+// matches against every registered service.
 func parseMessage(packet []byte) (*message.DNSMessage, error) {
 	return message.ParseMessage(packet)
 }
 
-// buildResponsePacket serializes a DNSMessage to wire format.
-//
-// TODO: Implement proper serialization
-// For now, return empty packet (stub)
+// buildResponsePacket serializes msg to wire format via MessageBuilder,
+// compressing names across every section the same way BuildResponse does.
+// A serialization error (e.g. a name too long to encode) yields a nil
+// packet, which sendQueryResponse's Send call will then fail on rather than
+// transmitting a truncated or empty packet.
 func buildResponsePacket(msg *message.DNSMessage) []byte {
-	// This is a stub - proper implementation needs message serialization
-	// which is not yet implemented in the codebase
-	return []byte{}
+	b := message.NewBuilder(msg.Header)
+
+	if err := b.StartQuestions(); err != nil {
+		return nil
+	}
+	for _, q := range msg.Questions {
+		if err := b.Question(q); err != nil {
+			return nil
+		}
+	}
+
+	if err := b.StartAnswers(); err != nil {
+		return nil
+	}
+	for _, a := range msg.Answers {
+		if err := b.Answer(a); err != nil {
+			return nil
+		}
+	}
+
+	if err := b.StartAuthorities(); err != nil {
+		return nil
+	}
+	for _, a := range msg.Authorities {
+		if err := b.Answer(a); err != nil {
+			return nil
+		}
+	}
+
+	if err := b.StartAdditionals(); err != nil {
+		return nil
+	}
+	for _, a := range msg.Additionals {
+		if err := b.Answer(a); err != nil {
+			return nil
+		}
+	}
+
+	packet, err := b.Finish()
+	if err != nil {
+		return nil
+	}
+	return packet
 }
@@ -1,12 +1,51 @@
 // Package responder implements mDNS service registration and response per RFC 6762.
+//
+// This is the responder-side counterpart to some other mDNS libraries'
+// Zone/MDNSService/Server split (e.g. micro/mdns): Responder is the Server,
+// New's RFC 6762 §8 probe-then-announce sequence (state.Machine, driven by
+// ProbeInterval) is the same three-probes-250ms-apart plus two-announcements-
+// 1s-apart handshake, Service is the MDNSService, and Registry.ListByType/
+// Query play the role of Zone.Records(question) - matching a question
+// against the registered set rather than exposing a single-method interface
+// for it, since ResponseBuilder already needs the richer Service value (for
+// SRV/TXT/A/AAAA, not just the matched PTR) to build a full response.
+// Register's conflict detection renames via "-2", "-3" suffixes per RFC
+// 6762 §9, the same as that convention, through state.Machine's rename loop
+// rather than a bespoke implementation in this package.
 package responder
 
 import (
 	"fmt"
 	"regexp"
-	"strconv"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
 )
 
+// TXTRecord is one key/value (or boolean) attribute of a Service's TXT
+// record per RFC 6763 §6.4.
+//
+// Present distinguishes the three forms RFC 6763 §6.4 defines:
+//   - "key" (boolean attribute): Present is false; Value is ignored.
+//   - "key=" (empty-value attribute): Present is true; Value is empty.
+//   - "key=value": Present is true; Value holds value.
+//
+// Value is a byte slice rather than a string so a service can carry binary
+// metadata - e.g. the Chromecast and HomeKit TXT keys that aren't valid
+// UTF-8.
+type TXTRecord = records.TXTRecord
+
+// NewTXTRecordsFromMap builds a []TXTRecord from a map[string]string, for
+// callers migrating from Service.TXTRecords' old map[string]string shape.
+// Every entry becomes a "key=value" record (Present: true). Map iteration
+// order is unspecified, so keys are sorted first to make the resulting TXT
+// record's wire encoding deterministic.
+func NewTXTRecordsFromMap(m map[string]string) []TXTRecord {
+	return records.TXTRecordsFromMap(m)
+}
+
 // Service represents an mDNS service to be registered per RFC 6763.
 //
 // RFC 6763 §4: Service Instance Names
@@ -29,14 +68,38 @@ type Service struct {
 	// Port is the service port number (1-65535).
 	Port int
 
-	// TXTRecords contains optional service metadata as key-value pairs.
-	// RFC 6763 §6.2: Total size SHOULD NOT exceed 1300 bytes.
-	// RFC 6763 §6: If empty, a single TXT record with 0x00 byte MUST be created.
-	TXTRecords map[string]string
+	// TXTRecords contains optional service metadata, one entry per TXT
+	// attribute. RFC 6763 §6.2: Total size SHOULD NOT exceed 1300 bytes.
+	// RFC 6763 §6: If empty, a single TXT record with 0x00 byte MUST be
+	// created. Use NewTXTRecordsFromMap to build this from a plain
+	// map[string]string.
+	TXTRecords []TXTRecord
 
 	// Hostname is the hostname for the A/AAAA record (optional).
 	// If not provided, system hostname will be used.
 	Hostname string
+
+	// Subtypes lists RFC 6763 §7.1 DNS-SD subtypes this service advertises
+	// (e.g., "_printer" for a printer advertising "_printer._sub._http._tcp.local").
+	// Each entry is just the subtype label, without the "._sub." separator
+	// or the parent ServiceType.
+	Subtypes []string
+
+	// RenameStrategy picks the next InstanceName when Rename is called
+	// after a probing conflict. If nil, NumericSuffixResolver is used,
+	// matching RFC 6762 §9's default "-2", "-3", ... convention.
+	RenameStrategy RenameStrategy
+}
+
+// clone returns a shallow copy of s, so a caller that needs to mutate
+// InstanceName (Validate's NFC normalization, Rename's suffixing) without
+// touching the *Service the original caller is holding - and may still be
+// reading - can operate on its own copy instead. Slice fields (TXTRecords,
+// Subtypes) are copied by reference, not deep-copied: Validate and Register
+// only read them.
+func (s *Service) clone() *Service {
+	c := *s
+	return &c
 }
 
 // Validate validates the service fields per RFC 6762/6763 requirements.
@@ -56,9 +119,21 @@ func (s *Service) Validate() error {
 		return fmt.Errorf("instance name cannot be empty")
 	}
 
-	// RFC 1035 §2.3.4: Labels are 1-63 octets
-	if len(s.InstanceName) > 63 {
-		return fmt.Errorf("instance name exceeds 63 octets (got %d)", len(s.InstanceName))
+	// RFC 6763 §4.1: instance names are Net-Unicode (NFC-normalized UTF-8).
+	// Normalize in place - unlike Hostname (see Register's wireHostname,
+	// which IDNA-encodes a throwaway copy and leaves service.Hostname as
+	// given), InstanceName doubles as the registry key and the owner label
+	// Register puts on the wire, so every later lookup/comparison against
+	// it (GetService, the rename loop, goodbye) needs to see the same
+	// canonical form the 63-octet check below validates - not whatever
+	// denormalized form the caller happened to type.
+	s.InstanceName = norm.NFC.String(s.InstanceName)
+
+	// RFC 6763 §4.1.1: reject invalid UTF-8 and control characters
+	// (U+0000-U+001F, U+007F); RFC 1035 §2.3.4: labels are 1-63 octets,
+	// measured here in UTF-8 bytes per §4.1.1.
+	if err := protocol.ValidateInstanceLabelUTF8(s.InstanceName); err != nil {
+		return err
 	}
 
 	// Validate ServiceType format
@@ -71,104 +146,76 @@ func (s *Service) Validate() error {
 		return fmt.Errorf("port must be in range 1-65535 (got %d)", s.Port)
 	}
 
-	// Validate TXT records size
-	if err := validateTXTRecordsSize(s.TXTRecords); err != nil {
+	// Validate TXT records
+	if err := validateTXTRecords(s.TXTRecords); err != nil {
 		return err
 	}
 
+	// Validate Subtypes
+	for _, subtype := range s.Subtypes {
+		if err := validateSubtype(subtype); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// Rename renames the service by appending or incrementing a numeric suffix per RFC 6762 §9.
+// Rename renames the service per RFC 6762 §9, using RenameStrategy (or
+// NumericSuffixResolver if unset) to pick the next candidate name.
 //
 // RFC 6762 §9: "If a host receives a response containing a record that conflicts
 // with one of its unique records, the host MUST immediately rename the record by
 // appending a numeric suffix (starting with '-2') to the instance name."
 //
-// Renaming algorithm:
-//  1. If name has no suffix (e.g., "My Service") → append "-2"
-//  2. If name has suffix (e.g., "My Service-2") → increment to "-3"
-//  3. Truncate if needed to stay within 63-octet limit (RFC 1035 §2.3.4)
+// attempt is the 1-based count of conflicts seen for this Service so far
+// (1 for the first conflict, 2 for the second, ...); it's passed straight
+// through to RenameStrategy.Resolve. The result is truncated to stay
+// within the 63-octet limit (RFC 1035 §2.3.4), cutting the base name
+// rather than the strategy's suffix.
 //
-// Examples:
+// Examples (NumericSuffixResolver):
 //   - "My Service" → "My Service-2"
 //   - "My Service-2" → "My Service-3"
 //   - "My Service-10" → "My Service-11"
 //
 // FR-030: System MUST rename service on conflict (US2)
-// T061: Implement Service.Rename() (GREEN phase)
-func (s *Service) Rename() {
-	// Pattern: matches "-N" suffix at end of string where N is a positive integer
-	// E.g., "My Service-2", "Printer-10"
-	suffixPattern := regexp.MustCompile(`^(.+)-(\d+)$`)
-
-	if matches := suffixPattern.FindStringSubmatch(s.InstanceName); matches != nil {
-		// Name already has a suffix - increment it
-		baseName := matches[1]  // "My Service"
-		suffixStr := matches[2] // "2"
-
-		// Parse existing suffix (guaranteed to be valid digits by regex)
-		// Error is impossible because regex ensures suffixStr contains only digits
-		suffix, _ := strconv.Atoi(suffixStr) // nosemgrep: beacon-error-swallowing
-		suffix++                             // Increment: 2 → 3
-
-		// Reconstruct name with incremented suffix
-		newName := fmt.Sprintf("%s-%d", baseName, suffix)
-
-		// Truncate if needed to fit within 63-octet limit
-		s.InstanceName = truncateToFit(newName, 63)
-	} else {
-		// Name has no suffix - append "-2"
-		newName := s.InstanceName + "-2"
-
-		// Truncate if needed to fit within 63-octet limit
-		s.InstanceName = truncateToFit(newName, 63)
+func (s *Service) Rename(attempt int) {
+	strategy := s.RenameStrategy
+	if strategy == nil {
+		strategy = NumericSuffixResolver{}
 	}
+
+	renamed := strategy.Resolve(s.InstanceName, attempt)
+	s.InstanceName = truncateToFit(renamed, 63, strategy.SuffixLen(renamed))
 }
 
-// truncateToFit truncates a name to fit within maxLen octets while preserving suffix.
+// truncateToFit truncates name to fit within maxLen octets, preserving its
+// trailing suffixLen bytes (the RenameStrategy-produced suffix) by cutting
+// the base name instead.
 //
 // RFC 1035 §2.3.4: Labels are 1-63 octets
 // RFC 6762 §9: Renaming must respect label length limits
 //
-// Algorithm:
-//  1. If name fits within maxLen, return as-is
-//  2. If name is too long, truncate the base name (not the suffix)
-//
 // Examples:
-//   - truncateToFit("Short-2", 63) → "Short-2" (no change)
-//   - truncateToFit("VeryLongNameThatExceedsLimit...-2", 63) → "VeryLongNameThatExceedsLi-2" (truncated)
-//
-// T061: Truncation logic for 63-octet limit
-func truncateToFit(name string, maxLen int) string {
+//   - truncateToFit("Short-2", 63, 2) → "Short-2" (no change)
+//   - truncateToFit("VeryLongNameThatExceedsLimit...-2", 63, 2) → "VeryLongNameThatExceedsLi-2" (truncated)
+func truncateToFit(name string, maxLen, suffixLen int) string {
 	if len(name) <= maxLen {
 		return name // Fits within limit
 	}
 
-	// Name is too long - need to truncate
-	// Strategy: Preserve the suffix (e.g., "-2"), truncate the base name
-
-	// Find the suffix
-	suffixPattern := regexp.MustCompile(`^(.+?)(-\d+)$`)
-	if matches := suffixPattern.FindStringSubmatch(name); matches != nil {
-		baseName := matches[1] // "VeryLongNameThatExceedsLimit..."
-		suffix := matches[2]   // "-2"
-
-		// Calculate how much space we have for the base name
-		maxBaseLen := maxLen - len(suffix)
-
-		if maxBaseLen < 1 {
-			// Edge case: suffix itself is too long (shouldn't happen in practice)
-			// Just truncate the whole thing
-			return name[:maxLen]
-		}
-
-		// Truncate base name and append suffix
-		return baseName[:maxBaseLen] + suffix
+	if suffixLen <= 0 || suffixLen >= maxLen {
+		// No preservable suffix (or the suffix alone doesn't fit) - just
+		// cut the whole name.
+		return name[:maxLen]
 	}
 
-	// No suffix found (shouldn't happen in Rename() flow, but handle it)
-	return name[:maxLen]
+	baseName := name[:len(name)-suffixLen]
+	suffix := name[len(name)-suffixLen:]
+	maxBaseLen := maxLen - suffixLen
+
+	return baseName[:maxBaseLen] + suffix
 }
 
 // serviceTypeRegex matches valid service type patterns per RFC 6763 §4.
@@ -199,25 +246,79 @@ func validateServiceType(serviceType string) error {
 	return nil
 }
 
-// validateTXTRecordsSize validates that TXT records don't exceed RFC limits.
+// subtypeRegex matches a valid RFC 6763 §7.1 subtype label: the same
+// underscore-prefixed alphanumeric+hyphen form as a service type's first
+// label, without the "._sub." separator or parent service type.
+var subtypeRegex = regexp.MustCompile(`^_[a-z0-9-]+$`)
+
+// validateSubtype validates a single DNS-SD subtype label per RFC 6763 §7.1.
+//
+// Format: "_<sub>" (e.g., "_printer")
+//
+// Requirements:
+//   - Must start with underscore "_"
+func validateSubtype(subtype string) error {
+	if subtype == "" {
+		return fmt.Errorf("subtype cannot be empty")
+	}
+
+	if !subtypeRegex.MatchString(subtype) {
+		return fmt.Errorf("invalid subtype format (must be _subtype, e.g., \"_printer\")")
+	}
+
+	return nil
+}
+
+// txtKeyRegex matches a valid TXT record key per RFC 6763 §6.4: printable
+// US-ASCII (0x20-0x7E), excluding '=' since the first '=' in an entry always
+// separates key from value.
+var txtKeyRegex = regexp.MustCompile(`^[\x20-\x3c\x3e-\x7e]+$`)
+
+// validateTXTRecords validates TXT records per RFC 6763 §6.2/§6.4 and RFC
+// 1035 §3.3.14.
 //
 // RFC 6763 §6.2: "The total size of a typical DNS-SD TXT record is intended to be
 // small -- 200 bytes or less. In cases where more data is justified, the maximum
 // SHOULD NOT exceed 1300 bytes."
+// RFC 6763 §6.4: keys must be printable US-ASCII and not contain '='; if a
+// key appears more than once, only the first occurrence counts.
+// RFC 1035 §3.3.14: each TXT string is length-prefixed by a single octet, so
+// an encoded entry cannot exceed 255 octets.
 //
 // T032: TXT record size validation
-func validateTXTRecordsSize(txtRecords map[string]string) error {
+func validateTXTRecords(txtRecords []TXTRecord) error {
 	if len(txtRecords) == 0 {
 		// Empty TXT is valid - will create mandatory 0x00 byte per RFC 6763 §6
 		return nil
 	}
 
-	// Calculate total size: length byte + key=value for each pair
+	seen := make(map[string]bool, len(txtRecords))
 	totalSize := 0
-	for key, value := range txtRecords {
-		// Each entry: length byte + "key=value"
-		entrySize := 1 + len(key) + 1 + len(value) // 1 for '=', 1 for length prefix
-		totalSize += entrySize
+	for _, record := range txtRecords {
+		if record.Key == "" {
+			return fmt.Errorf("TXT record key cannot be empty")
+		}
+		if !txtKeyRegex.MatchString(record.Key) {
+			return fmt.Errorf("TXT record key %q must be printable US-ASCII and not contain '='", record.Key)
+		}
+
+		if seen[record.Key] {
+			// RFC 6763 §6.4: "a client MUST silently ignore... all but the
+			// first occurrence of that attribute" - only the first
+			// occurrence counts against the size limits below.
+			continue
+		}
+		seen[record.Key] = true
+
+		// Each entry: length byte + "key" or "key=value"
+		entryLen := len(record.Key)
+		if record.Present {
+			entryLen += 1 + len(record.Value) // 1 for '='
+		}
+		if entryLen > 255 {
+			return fmt.Errorf("TXT record %q exceeds 255 octets (got %d)", record.Key, entryLen)
+		}
+		totalSize += 1 + entryLen // 1 for the length-prefix byte
 	}
 
 	// RFC 6763 §6.2: SHOULD NOT exceed 1300 bytes
@@ -1,6 +1,8 @@
 package responder
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -130,7 +132,74 @@ func TestService_Validate_InstanceName(t *testing.T) {
 			name:         "invalid - 64 characters (exceeds max)",
 			instanceName: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", // 64 chars
 			wantErr:      true,
-			errContains:  "instance name exceeds 63 octets",
+			errContains:  "exceeds maximum length",
+		},
+		{
+			// "Büro" - Latin-1 supplement character (ü, U+00FC), well under
+			// the 63-octet limit even at 2 bytes/char.
+			name:         "valid - Latin-1 (Büro)",
+			instanceName: "Büro",
+			wantErr:      false,
+		},
+		{
+			// CJK ideographs are 3 bytes each in UTF-8.
+			name:         "valid - CJK (日本語プリンター)",
+			instanceName: "日本語プリンター",
+			wantErr:      false,
+		},
+		{
+			// Emoji (U+1F5A8 PRINTER) is 4 bytes in UTF-8.
+			name:         "valid - emoji (🖨️ Office Printer)",
+			instanceName: "🖨️ Office Printer",
+			wantErr:      false,
+		},
+		{
+			// "cafe" + combining acute accent (U+0065 U+0301) NFC-composes
+			// to "café" with the single precomposed code point U+00E9 - one
+			// byte shorter in UTF-8 (2 bytes vs. the decomposed "é"'s
+			// 1+2=3 bytes) - exercises the normalize-before-length-check
+			// ordering.
+			name:         "valid - combining character sequence (cafe + U+0301)",
+			instanceName: "café",
+			wantErr:      false,
+		},
+		{
+			// 63 "e"+combining-accent pairs ("é", 3 bytes each
+			// pre-NFC = 189 bytes, over the limit) NFC-compose to 63
+			// precomposed "é" (2 bytes each = 126 bytes, still over the
+			// 63-octet limit) - confirms the check runs on the composed
+			// form rather than the pre-normalization byte count.
+			name:         "invalid - combining sequence still exceeds limit after NFC",
+			instanceName: strings.Repeat("é", 63),
+			wantErr:      true,
+			errContains:  "exceeds maximum length",
+		},
+		{
+			// 31 "e"+combining-accent pairs (93 bytes pre-NFC, over the
+			// limit) compose to 31 precomposed "é" (62 bytes post-NFC,
+			// under the limit) - the boundary case a byte-length-only check
+			// on the raw input would wrongly reject.
+			name:         "valid - combining pairs only fit after NFC composition",
+			instanceName: strings.Repeat("é", 31),
+			wantErr:      false,
+		},
+		{
+			name:         "invalid - not valid UTF-8",
+			instanceName: "Office\xff\xfePrinter",
+			wantErr:      true,
+			errContains:  "not valid UTF-8",
+		},
+		{
+			name:         "invalid - control character (NUL)",
+			instanceName: "Office\x00Printer",
+			wantErr:      true,
+			errContains:  "control character",
+		},
+		{
+			name:         "invalid - control character (DEL, U+007F)",
+			instanceName: "Office\x7fPrinter",
+			wantErr:      true,
+			errContains:  "control character",
 		},
 	}
 
@@ -231,30 +300,34 @@ func TestService_Validate_Port(t *testing.T) {
 func TestService_Validate_TXTRecords(t *testing.T) {
 	tests := []struct {
 		name        string
-		txtRecords  map[string]string
+		txtRecords  []TXTRecord
 		wantErr     bool
 		errContains string
 	}{
 		{
 			name: "valid - small TXT records",
-			txtRecords: map[string]string{
+			txtRecords: NewTXTRecordsFromMap(map[string]string{
 				"version": "1.0",
 				"path":    "/api",
-			},
+			}),
 			wantErr: false,
 		},
 		{
 			name:       "valid - empty TXT (will add 0x00 byte per RFC 6763 §6)",
-			txtRecords: map[string]string{},
+			txtRecords: nil,
 			wantErr:    false,
 		},
 		{
-			name: "invalid - TXT records exceed 1300 bytes",
-			txtRecords: map[string]string{
-				"large": string(make([]byte, 1400)), // 1400 bytes > 1300 limit
-			},
+			name:        "invalid - single TXT entry exceeds 255 octets",
+			txtRecords:  []TXTRecord{{Key: "large", Value: make([]byte, 250), Present: true}}, // 5 + 1 + 250 = 256 octets
 			wantErr:     true,
-			errContains: "TXT records exceed 1300 bytes",
+			errContains: "exceeds 255 octets",
+		},
+		{
+			name:        "invalid - TXT records exceed 1300 bytes total",
+			txtRecords:  manyTXTRecords(7, 200), // 7 * (1 length-prefix + 2 key + 1 '=' + 200 value) = 1428 bytes
+			wantErr:     true,
+			errContains: "exceed 1300 bytes",
 		},
 	}
 
@@ -284,6 +357,17 @@ func TestService_Validate_TXTRecords(t *testing.T) {
 	}
 }
 
+// manyTXTRecords builds n "key=value" records with distinct short keys
+// ("k0", "k1", ...) and valueLen-byte values, for exercising the 1300-byte
+// total-size limit without tripping the per-entry 255-octet cap.
+func manyTXTRecords(n, valueLen int) []TXTRecord {
+	txtRecords := make([]TXTRecord, n)
+	for i := range txtRecords {
+		txtRecords[i] = TXTRecord{Key: fmt.Sprintf("k%d", i), Value: make([]byte, valueLen), Present: true}
+	}
+	return txtRecords
+}
+
 // TestService_Rename tests the Rename() method per RFC 6762 §9 conflict resolution.
 //
 // TDD Phase: RED - These tests will FAIL until we implement Service.Rename()
@@ -364,7 +448,7 @@ func TestService_Rename(t *testing.T) {
 				Port:         8080,
 			}
 
-			service.Rename()
+			service.Rename(1)
 
 			if service.InstanceName != tt.wantAfter {
 				t.Errorf("Rename() InstanceName = %q, want %q (%s)",
@@ -419,7 +503,7 @@ func TestService_Rename_MaxLength(t *testing.T) {
 				Port:         8080,
 			}
 
-			service.Rename()
+			service.Rename(1)
 
 			if len(service.InstanceName) > 63 {
 				t.Errorf("Rename() InstanceName length = %d, want ≤63 (RFC 1035 §2.3.4 violation)",
@@ -432,6 +516,70 @@ func TestService_Rename_MaxLength(t *testing.T) {
 	}
 }
 
+// TestService_Validate_Subtypes tests subtype validation per RFC 6763 §7.1.
+//
+// RFC 6763 §7.1: Subtype labels take the form "_<sub>", used as
+// "_<sub>._sub.<servicetype>".
+func TestService_Validate_Subtypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		subtypes    []string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "valid - single subtype",
+			subtypes: []string{"_printer"},
+			wantErr:  false,
+		},
+		{
+			name:     "valid - multiple subtypes",
+			subtypes: []string{"_printer", "_universal"},
+			wantErr:  false,
+		},
+		{
+			name:     "valid - no subtypes",
+			subtypes: nil,
+			wantErr:  false,
+		},
+		{
+			name:        "invalid - missing leading underscore",
+			subtypes:    []string{"printer"},
+			wantErr:     true,
+			errContains: "invalid subtype format",
+		},
+		{
+			name:        "invalid - empty subtype",
+			subtypes:    []string{""},
+			wantErr:     true,
+			errContains: "subtype cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &Service{
+				InstanceName: "Test Service",
+				ServiceType:  "_http._tcp.local",
+				Port:         8080,
+				Subtypes:     tt.subtypes,
+			}
+
+			err := service.Validate()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error containing %q", tt.errContains)
+				} else if tt.errContains != "" && !contains(err.Error(), tt.errContains) {
+					t.Errorf("Validate() error = %q, want error containing %q", err.Error(), tt.errContains)
+				}
+			} else if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
 // Helper function for substring checking
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && hasSubstring(s, substr))
@@ -0,0 +1,16 @@
+package responder
+
+import "github.com/joshuafuller/beacon/tap"
+
+// tapActive reports whether a real Tap is configured, so the per-packet
+// call sites in handleQuery/respondServiceTypeEnum can skip calling
+// time.Now() and building OnQuery/OnResponse's arguments when the default
+// tap.NoOp{} - or a Responder built without New(), whose tap field is nil
+// - would just discard them.
+func (r *Responder) tapActive() bool {
+	if r.tap == nil {
+		return false
+	}
+	_, isNoOp := r.tap.(tap.NoOp)
+	return !isNoOp
+}
@@ -0,0 +1,79 @@
+package responder
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/responder"
+)
+
+// recordingTap is a tap.Tap recorder for assertions, safe for concurrent
+// use since handleQuery/respondServiceTypeEnum may call it from multiple
+// goroutines.
+type recordingTap struct {
+	mu        sync.Mutex
+	queries   int
+	responses int
+}
+
+func (r *recordingTap) OnQuery(_ []byte, _, _ net.Addr, _ time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+}
+
+func (r *recordingTap) OnResponse(_ []byte, _, _ net.Addr, _ time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses++
+}
+
+func (r *recordingTap) counts() (queries, responses int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queries, r.responses
+}
+
+// TestTapActive_FalseForNilOrNoOp validates tapActive's guard against both
+// a zero-value Responder (nil tap) and the default tap.NoOp{}.
+func TestTapActive_FalseForNilOrNoOp(t *testing.T) {
+	r := &Responder{}
+	if r.tapActive() {
+		t.Error("tapActive() = true for a nil tap, want false")
+	}
+}
+
+// TestWithTap_RejectsNil validates that WithTap(nil) returns a
+// ValidationError instead of silently disabling tap reporting.
+func TestWithTap_RejectsNil(t *testing.T) {
+	r := &Responder{}
+	if err := WithTap(nil)(r); err == nil {
+		t.Fatal("WithTap(nil) returned nil error, want a ValidationError")
+	}
+}
+
+// TestHandleQuery_ReportsToTap validates that a parsed query reports an
+// OnQuery event to a configured tap.Tap, even when it matches no
+// registered service.
+func TestHandleQuery_ReportsToTap(t *testing.T) {
+	rec := &recordingTap{}
+	r := &Responder{
+		registry: responder.NewRegistry(),
+		tap:      rec,
+	}
+
+	queryMsg, err := message.BuildQuery("_http._tcp.local", uint16(protocol.RecordTypePTR))
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	_ = r.handleQuery(queryMsg, &net.UDPAddr{IP: net.ParseIP("192.168.1.5"), Port: 5353}, nil)
+
+	if queries, _ := rec.counts(); queries != 1 {
+		t.Errorf("queries = %d, want 1", queries)
+	}
+}
@@ -0,0 +1,107 @@
+package responder
+
+import (
+	"context"
+	goerrors "errors"
+	"net"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TransportPlugin pairs a transport.Transport with the interface and
+// address(es) it sends and receives on, so a multi-homed Responder
+// configured via WithTransports can answer a query on the same interface
+// (and with that interface's own address) it arrived on, instead of
+// always using the host's first non-loopback address the way a single
+// r.transport Responder does.
+//
+// IPv4 and/or IPv6 may be set depending on which families Transport joins -
+// a plugin bound to an IPv6-only interface leaves IPv4 nil, and vice versa.
+type TransportPlugin struct {
+	// Transport sends and receives mDNS packets for this plugin - typically
+	// a transport.UDPv4Transport/UDPv6Transport scoped to Interface via
+	// transport.NewUDPv4TransportWithInterfaces, or a transport.MockTransport
+	// in tests.
+	Transport transport.Transport
+
+	// Interface is the network interface Transport is bound to.
+	Interface net.Interface
+
+	// IPv4 is this interface's link-local IPv4 address, or nil if it has
+	// none (or IPv6-only operation is desired for it).
+	IPv4 []byte
+
+	// IPv6 is this interface's IPv6 address(es), or nil if it has none.
+	IPv6 [][]byte
+}
+
+// pluginGroupTransport composes every configured TransportPlugin's
+// Transport behind a single transport.Transport, so WithTransports can
+// still satisfy Responder.transport's existing single-Transport call sites
+// (currently only Close, plus Register/Unregister's probe/announce Sends,
+// which always pass a nil dest per state.Prober/state.Announcer) without
+// those call sites needing to know how many plugins are configured.
+//
+// Receive is intentionally unsupported: runQueryHandler, when
+// transportPlugins is non-empty, receives directly from each plugin's own
+// Transport instead of going through this wrapper, so a query's reply can
+// be routed back out the same interface it arrived on (see handleQuery's
+// via parameter). Nothing else calls Receive on r.transport.
+type pluginGroupTransport struct {
+	plugins []TransportPlugin
+}
+
+// newPluginGroupTransport wraps plugins behind a single Transport. Every
+// plugin's Transport is owned by the returned pluginGroupTransport from
+// this point on: Close closes all of them.
+func newPluginGroupTransport(plugins []TransportPlugin) *pluginGroupTransport {
+	return &pluginGroupTransport{plugins: plugins}
+}
+
+// Send broadcasts packet to every plugin's Transport when dest is nil (the
+// only case state.Prober/state.Announcer's probe/announce Sends use),
+// joining any per-plugin errors together. A non-nil dest is sent on every
+// plugin too, since this wrapper has no basis to prefer one plugin's
+// Transport over another's for a specific destination address - a caller
+// that needs destination-aware routing (e.g. a query response) should send
+// directly on the originating plugin's Transport instead of through this
+// wrapper.
+func (p *pluginGroupTransport) Send(ctx context.Context, packet []byte, dest net.Addr) error {
+	var errs []error
+	for _, plugin := range p.plugins {
+		if err := plugin.Transport.Send(ctx, packet, dest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return goerrors.Join(errs...)
+	}
+	return nil
+}
+
+// Receive always fails - see the type doc comment for why.
+func (p *pluginGroupTransport) Receive(_ context.Context) ([]byte, net.Addr, error) {
+	return nil, nil, &errors.NetworkError{
+		Operation: "receive",
+		Err:       goerrors.New("pluginGroupTransport does not support Receive; receive from each TransportPlugin's own Transport instead"),
+	}
+}
+
+// Close closes every plugin's Transport, joining their close errors if more
+// than one fails.
+func (p *pluginGroupTransport) Close() error {
+	var errs []error
+	for _, plugin := range p.plugins {
+		if err := plugin.Transport.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return goerrors.Join(errs...)
+	}
+	return nil
+}
+
+// Compile-time verification that pluginGroupTransport implements Transport.
+var _ transport.Transport = (*pluginGroupTransport)(nil)
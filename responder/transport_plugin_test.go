@@ -0,0 +1,136 @@
+package responder
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	internalresponder "github.com/joshuafuller/beacon/internal/responder"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// TestWithTransports_RejectsEmptyList verifies WithTransports(), with no
+// plugins, fails construction rather than silently leaving the Responder
+// with no way to send or receive.
+func TestWithTransports_RejectsEmptyList(t *testing.T) {
+	if _, err := New(context.Background(), WithTransports()); err == nil {
+		t.Error("New(WithTransports()) expected error, got nil")
+	}
+}
+
+// TestWithTransports_RejectsNilTransport verifies a plugin with a nil
+// Transport fails construction instead of panicking the first time
+// runQueryHandler tries to Receive on it.
+func TestWithTransports_RejectsNilTransport(t *testing.T) {
+	plugin := TransportPlugin{Interface: net.Interface{Name: "eth0"}}
+	if _, err := New(context.Background(), WithTransports(plugin)); err == nil {
+		t.Error("New(WithTransports(plugin with nil Transport)) expected error, got nil")
+	}
+}
+
+// TestWithTransports_RejectsNoAddress verifies a plugin with neither IPv4
+// nor IPv6 set fails construction, rather than later answering queries with
+// an address-less A/AAAA-less response.
+func TestWithTransports_RejectsNoAddress(t *testing.T) {
+	plugin := TransportPlugin{Transport: transport.NewMockTransport(), Interface: net.Interface{Name: "eth0"}}
+	if _, err := New(context.Background(), WithTransports(plugin)); err == nil {
+		t.Error("New(WithTransports(plugin with no IPv4/IPv6)) expected error, got nil")
+	}
+}
+
+// TestWithTransport_AfterWithTransports_ClearsPlugins verifies WithTransport
+// applied after WithTransports fully replaces the plugin set rather than
+// leaving a stale transportPlugins list that runQueryHandler would still
+// fan out to alongside the new single transport.
+func TestWithTransport_AfterWithTransports_ClearsPlugins(t *testing.T) {
+	plugin := TransportPlugin{Transport: transport.NewMockTransport(), Interface: net.Interface{Name: "eth0"}, IPv4: []byte{10, 0, 0, 1}}
+	mock := transport.NewMockTransport()
+
+	r, err := New(context.Background(), WithTransports(plugin), WithTransport(mock))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	if len(r.transportPlugins) != 0 {
+		t.Errorf("transportPlugins = %d entries, want 0 after a later WithTransport", len(r.transportPlugins))
+	}
+	if r.transport != mock {
+		t.Error("r.transport != the WithTransport mock")
+	}
+}
+
+// TestResponder_HandleQuery_WithTransports_AnswersViaOriginatingPlugin
+// verifies a multi-homed Responder configured via WithTransports answers a
+// query back out the same plugin's Transport it arrived on, carrying that
+// plugin's own IPv4 address in the A record - not the other plugin's.
+func TestResponder_HandleQuery_WithTransports_AnswersViaOriginatingPlugin(t *testing.T) {
+	eth0 := transport.NewMockTransport()
+	wlan0 := transport.NewMockTransport()
+
+	plugins := []TransportPlugin{
+		{Transport: eth0, Interface: net.Interface{Name: "eth0"}, IPv4: []byte{192, 168, 1, 10}},
+		{Transport: wlan0, Interface: net.Interface{Name: "wlan0"}, IPv4: []byte{192, 168, 2, 10}},
+	}
+
+	r, err := New(context.Background(), WithTransports(plugins...))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	if err := r.registry.Register(&internalresponder.Service{
+		InstanceName: "printer",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}); err != nil {
+		t.Fatalf("registry.Register() error = %v, want nil", err)
+	}
+
+	queryPacket, err := message.BuildQuery("_http._tcp.local", uint16(protocol.RecordTypePTR))
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v, want nil", err)
+	}
+
+	if err := r.handleQuery(queryPacket, nil, wlan0); err != nil {
+		t.Fatalf("handleQuery() error = %v, want nil", err)
+	}
+
+	// A PTR answer is a shared record, so sendQueryResponse multicasts it
+	// after RFC 6762 §6's randomized 20-120ms delay rather than immediately.
+	var sent []transport.SendCall
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		sent = wlan0.SendCalls()
+		if len(sent) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(eth0.SendCalls()) != 0 {
+		t.Errorf("eth0.SendCalls() = %d, want 0 (query arrived on wlan0)", len(eth0.SendCalls()))
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("wlan0.SendCalls() = %d, want 1", len(sent))
+	}
+
+	resp, err := message.ParseMessage(sent[0].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage(sent packet) error = %v, want nil", err)
+	}
+
+	var aRecordData []byte
+	for _, rr := range append(resp.Answers, resp.Additionals...) {
+		if rr.TYPE == uint16(protocol.RecordTypeA) {
+			aRecordData = rr.RDATA
+		}
+	}
+	if string(aRecordData) != string(plugins[1].IPv4) {
+		t.Errorf("A record data = %v, want wlan0's address %v", aRecordData, plugins[1].IPv4)
+	}
+}
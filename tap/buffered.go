@@ -0,0 +1,114 @@
+package tap
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// event is one OnQuery/OnResponse call queued onto a Buffered's channel.
+type event struct {
+	isResponse bool
+	msg        []byte
+	src, dst   net.Addr
+	at         time.Time
+}
+
+// Buffered wraps an inner Tap so OnQuery/OnResponse never block the
+// caller: every event is queued onto a bounded channel and delivered to
+// inner from a single background goroutine, so a slow consumer (a full
+// disk, a stalled Unix socket peer) degrades to dropped events instead of
+// stalling the responder/querier that owns this Tap.
+//
+// Use NewBuffered to construct one; the zero value is not usable.
+type Buffered struct {
+	inner   Tap
+	events  chan event
+	dropped atomic.Uint64
+	done    chan struct{}
+	closeMu sync.Mutex
+	closed  bool
+}
+
+var _ Tap = (*Buffered)(nil)
+
+// NewBuffered wraps inner in a Buffered with room for bufferSize queued
+// events before OnQuery/OnResponse start dropping events (and counting
+// them in Dropped) rather than blocking. Call Close when the Tap is no
+// longer needed to stop the delivery goroutine.
+func NewBuffered(inner Tap, bufferSize int) *Buffered {
+	b := &Buffered{
+		inner:  inner,
+		events: make(chan event, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// OnQuery implements Tap by queuing the event for delivery, dropping it
+// (and incrementing Dropped) if the buffer is full.
+func (b *Buffered) OnQuery(msg []byte, src, dst net.Addr, at time.Time) {
+	b.enqueue(event{isResponse: false, msg: msg, src: src, dst: dst, at: at})
+}
+
+// OnResponse implements Tap by queuing the event for delivery, dropping
+// it (and incrementing Dropped) if the buffer is full.
+func (b *Buffered) OnResponse(msg []byte, src, dst net.Addr, at time.Time) {
+	b.enqueue(event{isResponse: true, msg: msg, src: src, dst: dst, at: at})
+}
+
+func (b *Buffered) enqueue(e event) {
+	select {
+	case b.events <- e:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped so far because the buffer
+// was full when OnQuery/OnResponse was called.
+func (b *Buffered) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// Close stops the delivery goroutine. Events still queued when Close is
+// called are delivered before it returns; Close is idempotent.
+func (b *Buffered) Close() error {
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	return nil
+}
+
+func (b *Buffered) run() {
+	for {
+		select {
+		case e := <-b.events:
+			b.deliver(e)
+		case <-b.done:
+			// Drain whatever was queued before Close was called.
+			for {
+				select {
+				case e := <-b.events:
+					b.deliver(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *Buffered) deliver(e event) {
+	if e.isResponse {
+		b.inner.OnResponse(e.msg, e.src, e.dst, e.at)
+		return
+	}
+	b.inner.OnQuery(e.msg, e.src, e.dst, e.at)
+}
@@ -0,0 +1,126 @@
+package tap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncWriteCloser is a bytes.Buffer wrapped in a mutex with a Close flag,
+// since FrameStreamTap writes from its own delivery goroutine (via
+// Buffered) while tests read it back from the test goroutine.
+type syncWriteCloser struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (w *syncWriteCloser) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *syncWriteCloser) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *syncWriteCloser) bytes() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]byte(nil), w.buf.Bytes()...)
+}
+
+func (w *syncWriteCloser) isClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
+
+func TestFrameStreamTap_WritesLengthPrefixedFrame(t *testing.T) {
+	w := &syncWriteCloser{}
+	ft := NewFrameStreamTap(w, 8)
+	defer func() { _ = ft.Close() }()
+
+	src := &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353}
+	msg := []byte("hello")
+	ft.OnQuery(msg, src, nil, time.Now())
+
+	var frame []byte
+	deadline := time.After(time.Second)
+	for {
+		frame = w.bytes()
+		if len(frame) > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("no frame written within 1s")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	if len(frame) < 4 {
+		t.Fatalf("frame = %d bytes, want at least a 4-byte length prefix", len(frame))
+	}
+	frameLen := binary.BigEndian.Uint32(frame[:4])
+	payload := frame[4:]
+	if int(frameLen) != len(payload) {
+		t.Fatalf("frame length prefix = %d, want %d (actual payload length)", frameLen, len(payload))
+	}
+	if payload[0] != 0 {
+		t.Errorf("kind byte = %d, want 0 (query)", payload[0])
+	}
+	if !bytes.HasSuffix(payload, msg) {
+		t.Errorf("payload = %x, want it to end with the message bytes %x", payload, msg)
+	}
+}
+
+func TestFrameStreamTap_Close_ClosesUnderlyingWriter(t *testing.T) {
+	w := &syncWriteCloser{}
+	ft := NewFrameStreamTap(w, 8)
+
+	if err := ft.Close(); err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+	if !w.isClosed() {
+		t.Error("underlying writer was not closed")
+	}
+}
+
+func TestFrameStreamTap_FailedWrites_CountsWriteErrors(t *testing.T) {
+	ft := NewFrameStreamTap(&alwaysFailWriter{}, 8)
+	defer func() { _ = ft.Close() }()
+
+	ft.OnQuery([]byte("q"), nil, nil, time.Now())
+
+	deadline := time.After(time.Second)
+	for {
+		if ft.FailedWrites() > 0 {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("FailedWrites() = 0, want at least 1 within 1s")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+type alwaysFailWriter struct{}
+
+func (alwaysFailWriter) Write([]byte) (int, error) {
+	return 0, errAlwaysFail
+}
+
+var errAlwaysFail = errWriteFailed("simulated write failure")
+
+type errWriteFailed string
+
+func (e errWriteFailed) Error() string { return string(e) }
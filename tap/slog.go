@@ -0,0 +1,71 @@
+package tap
+
+import (
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// SlogTap renders every Tap event through an injected *slog.Logger at
+// Debug level, with fields for the message's transaction ID and
+// question/answer counts - a lightweight alternative to FrameStreamTap for
+// operators already standardized on log/slog who just want to see mDNS
+// traffic go by, without standing up a Frame Streams consumer.
+//
+// SlogTap is internally non-blocking (see Buffered): a slow or backed-up
+// logger degrades to dropped events rather than stalling the
+// responder/querier that owns it.
+type SlogTap struct {
+	*Buffered
+}
+
+// NewSlogTap creates a SlogTap logging through logger, buffering up to
+// bufferSize queued events before it starts dropping them under load.
+func NewSlogTap(logger *slog.Logger, bufferSize int) *SlogTap {
+	return &SlogTap{Buffered: NewBuffered(&slogRenderer{logger: logger}, bufferSize)}
+}
+
+// slogRenderer is SlogTap's inner Tap, wrapped in a Buffered by
+// NewSlogTap so the logging itself never runs on the caller's goroutine.
+type slogRenderer struct {
+	logger *slog.Logger
+}
+
+// OnQuery implements Tap.
+func (r *slogRenderer) OnQuery(msg []byte, src, dst net.Addr, at time.Time) {
+	r.log("query", msg, src, dst, at)
+}
+
+// OnResponse implements Tap.
+func (r *slogRenderer) OnResponse(msg []byte, src, dst net.Addr, at time.Time) {
+	r.log("response", msg, src, dst, at)
+}
+
+func (r *slogRenderer) log(kind string, msg []byte, src, dst net.Addr, at time.Time) {
+	parsed, err := message.ParseMessage(msg)
+	if err != nil {
+		r.logger.Debug("mdns tap event", "kind", kind, "src", addrString(src), "dst", addrString(dst), "at", at, "error", err)
+		return
+	}
+
+	r.logger.Debug("mdns tap event",
+		"kind", kind,
+		"id", parsed.Header.ID,
+		"questions", len(parsed.Questions),
+		"answers", len(parsed.Answers),
+		"src", addrString(src),
+		"dst", addrString(dst),
+		"at", at,
+	)
+}
+
+// addrString returns addr.String(), or "" for a nil addr (e.g. the dst of
+// a received query, which isn't tracked).
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
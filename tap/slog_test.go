@@ -0,0 +1,89 @@
+package tap
+
+import (
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+)
+
+// syncBuffer is a strings.Builder wrapped in a mutex, since SlogTap writes
+// from its own delivery goroutine (via Buffered) while tests read it back
+// from the test goroutine.
+type syncBuffer struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.String()
+}
+
+func TestSlogTap_LogsQueryWithQuestionCount(t *testing.T) {
+	sb := &syncBuffer{}
+	logger := slog.New(slog.NewTextHandler(sb, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	st := NewSlogTap(logger, 8)
+	defer func() { _ = st.Close() }()
+
+	queryMsg, err := message.BuildQuery("host.local", 1)
+	if err != nil {
+		t.Fatalf("BuildQuery failed: %v", err)
+	}
+
+	st.OnQuery(queryMsg, &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 5353}, nil, time.Now())
+
+	waitForLogLine(t, sb, "mdns tap event")
+
+	got := sb.String()
+	for _, want := range []string{"kind=query", "questions=1", "192.168.1.1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log output = %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestSlogTap_LogsUnparsableMessageWithError(t *testing.T) {
+	sb := &syncBuffer{}
+	logger := slog.New(slog.NewTextHandler(sb, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	st := NewSlogTap(logger, 8)
+	defer func() { _ = st.Close() }()
+
+	st.OnResponse([]byte{0x01}, nil, nil, time.Now())
+
+	waitForLogLine(t, sb, "mdns tap event")
+
+	if got := sb.String(); !strings.Contains(got, "kind=response") || !strings.Contains(got, "error=") {
+		t.Errorf("log output = %q, want kind=response and an error field", got)
+	}
+}
+
+// waitForLogLine polls sb for substr, since SlogTap delivers asynchronously
+// via Buffered.
+func waitForLogLine(t *testing.T, sb *syncBuffer, substr string) {
+	t.Helper()
+	deadline := time.After(time.Second)
+	for {
+		if strings.Contains(sb.String(), substr) {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("log output = %q, want it to contain %q within 1s", sb.String(), substr)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Package tap defines a pluggable hook for observing every mDNS message a
+// responder.Responder or querier.Querier sends or receives, independent of
+// the metrics and slog/EventHook instrumentation those packages already
+// expose - inspired by CoreDNS's dnstap plugin, for operators who want a
+// stream of raw query/response events (e.g. to feed existing DNS analysis
+// tooling) rather than aggregated counters or per-lifecycle-event logs.
+package tap
+
+import (
+	"net"
+	"time"
+)
+
+// Tap receives every mDNS message a Responder or Querier sends or
+// receives. msg is the raw wire-format packet; src and dst are the
+// packet's source and destination addresses where known (nil when not
+// applicable - e.g. dst for a received query is this process's own
+// address and isn't tracked); at is the time the event was observed.
+//
+// Implementations must be safe for concurrent use and must not block the
+// caller: OnQuery/OnResponse are called from the responder/querier's
+// packet-processing goroutines, so a slow implementation would stall mDNS
+// traffic. NewBuffered wraps any Tap to make this guarantee mechanical
+// rather than something every implementation must reimplement.
+type Tap interface {
+	// OnQuery is called for every query sent or received.
+	OnQuery(msg []byte, src, dst net.Addr, at time.Time)
+
+	// OnResponse is called for every response sent or received.
+	OnResponse(msg []byte, src, dst net.Addr, at time.Time)
+}
+
+// NoOp is a Tap that discards every event. It is the default Tap for a
+// Responder or Querier that hasn't been given one via WithTap.
+type NoOp struct{}
+
+// OnQuery implements Tap.
+func (NoOp) OnQuery([]byte, net.Addr, net.Addr, time.Time) {}
+
+// OnResponse implements Tap.
+func (NoOp) OnResponse([]byte, net.Addr, net.Addr, time.Time) {}
+
+var _ Tap = NoOp{}
@@ -0,0 +1,104 @@
+package tap
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTap is a Tap that records every OnQuery/OnResponse call, safe
+// for concurrent use since Buffered delivers from its own goroutine.
+type recordingTap struct {
+	mu        sync.Mutex
+	queries   int
+	responses int
+	lastMsg   []byte
+}
+
+func (r *recordingTap) OnQuery(msg []byte, _, _ net.Addr, _ time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+	r.lastMsg = msg
+}
+
+func (r *recordingTap) OnResponse(msg []byte, _, _ net.Addr, _ time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses++
+	r.lastMsg = msg
+}
+
+func (r *recordingTap) counts() (queries, responses int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queries, r.responses
+}
+
+func TestNoOp_DiscardsEvents(t *testing.T) {
+	var n NoOp
+	n.OnQuery([]byte("q"), nil, nil, time.Now())
+	n.OnResponse([]byte("r"), nil, nil, time.Now())
+}
+
+func TestBuffered_DeliversEventsToInner(t *testing.T) {
+	rec := &recordingTap{}
+	b := NewBuffered(rec, 8)
+	defer func() { _ = b.Close() }()
+
+	b.OnQuery([]byte("query"), nil, nil, time.Now())
+	b.OnResponse([]byte("response"), nil, nil, time.Now())
+
+	deadline := time.After(time.Second)
+	for {
+		if q, r := rec.counts(); q == 1 && r == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			q, r := rec.counts()
+			t.Fatalf("queries=%d responses=%d, want 1 and 1", q, r)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestBuffered_DropsWhenFull(t *testing.T) {
+	// blockingTap never reads from a release channel, simulating a
+	// consumer stuck mid-delivery so Buffered's channel backs up.
+	release := make(chan struct{})
+	blocking := &blockingTap{release: release}
+	defer close(release)
+
+	b := NewBuffered(blocking, 1)
+	defer func() { _ = b.Close() }()
+
+	// The delivery goroutine picks up the first event immediately and
+	// blocks in blockingTap.OnQuery, so the channel buffer (size 1) plus
+	// this loop's sends should overflow and start dropping.
+	for i := 0; i < 10; i++ {
+		b.OnQuery([]byte("q"), nil, nil, time.Now())
+	}
+
+	if got := b.Dropped(); got == 0 {
+		t.Error("Dropped() = 0, want at least one dropped event under a full buffer")
+	}
+}
+
+type blockingTap struct {
+	release chan struct{}
+}
+
+func (b *blockingTap) OnQuery([]byte, net.Addr, net.Addr, time.Time)    { <-b.release }
+func (b *blockingTap) OnResponse([]byte, net.Addr, net.Addr, time.Time) { <-b.release }
+
+func TestBuffered_CloseIsIdempotent(t *testing.T) {
+	b := NewBuffered(&recordingTap{}, 1)
+	if err := b.Close(); err != nil {
+		t.Fatalf("first Close() error = %v, want nil", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("second Close() error = %v, want nil", err)
+	}
+}
@@ -95,7 +95,7 @@ func TestQuery_NetworkError_SendFailure(t *testing.T) {
 // TestQuery_ValidationError_UnsupportedRecordType validates that Query() returns
 // ValidationError for unsupported record types per FR-002, FR-014.
 //
-// FR-002: System MUST support A, PTR, SRV, TXT record types (M1)
+// FR-002: System MUST support A, AAAA, PTR, SRV, TXT record types
 // FR-014: System MUST return ValidationError for unsupported record types
 // NFR-006: Error messages MUST include actionable context
 //
@@ -115,10 +115,9 @@ func TestQuery_ValidationError_UnsupportedRecordType(t *testing.T) {
 		name       string
 		recordType querier.RecordType
 	}{
-		{"AAAA (28)", querier.RecordType(28)}, // IPv6 - not supported in M1
-		{"MX (15)", querier.RecordType(15)},   // Mail exchange - not supported
-		{"CNAME (5)", querier.RecordType(5)},  // Canonical name - not supported
-		{"NS (2)", querier.RecordType(2)},     // Name server - not supported
+		{"MX (15)", querier.RecordType(15)},  // Mail exchange - not supported
+		{"SPF (99)", querier.RecordType(99)}, // Sender Policy Framework - not supported
+		{"SIG (24)", querier.RecordType(24)}, // Signature (obsoleted by RRSIG) - not supported
 	}
 
 	for _, tt := range unsupportedTypes {
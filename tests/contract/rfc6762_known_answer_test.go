@@ -96,8 +96,16 @@ func TestRFC6762_KnownAnswerSuppression_TTLThreshold(t *testing.T) {
 				ptrRecord.TTL,
 				tt.shouldSuppress)
 
-			// Skip for now - will implement once query handling supports known-answers
-			t.Skip("Deferred until query handling with known-answer support implemented")
+			// Query handling now parses a query's known-answer section and
+			// applies this exact TTL threshold - see
+			// internal/responder.ResponseBuilder.ApplyKnownAnswerSuppression
+			// and its table-driven coverage in
+			// internal/responder/known_answer_test.go. What's still missing
+			// is response serialization (responder.go's buildResponsePacket
+			// is a stub returning an empty packet), so there's no way for
+			// this black-box contract test to drive a real query/response
+			// round trip over the wire yet and assert on the result here.
+			t.Skip("Deferred until response serialization (buildResponsePacket) is implemented")
 		})
 	}
 }
@@ -127,6 +135,15 @@ func TestRFC6762_KnownAnswerSuppression_SharedVsUnique(t *testing.T) {
 	//   - If querier already has the unique answer, it shouldn't be querying
 	//   - Suppression less relevant in practice
 
+	// ResponseBuilder now encodes this distinction explicitly:
+	// CacheFlush (RFC 6762 §10.2) marks SRV/TXT/A/AAAA/HTTPS as unique, PTR
+	// as shared, and responder.WithUniqueRecordSuppression(false) exempts
+	// unique records from suppression entirely - see
+	// TestWithUniqueRecordSuppression_ExemptsUniqueRecords in
+	// internal/responder/known_answer_test.go for the exercised behavior.
+	// This test stays informational because asserting on it here would
+	// require the same real query/response round trip
+	// TestRFC6762_KnownAnswerSuppression_TTLThreshold is blocked on.
 	t.Skip("Informational test - documents RFC 6762 §7.1 shared vs unique distinction")
 }
 
@@ -169,14 +186,18 @@ func TestRFC6762_KnownAnswerSuppression_NetworkBandwidth(t *testing.T) {
 	// 1. Initial query - no known answers → full response (3 PTR records)
 	// 2. Repeated query with known-answers → suppressed response (0-1 PTR records if TTLs stale)
 
-	// TODO: Implement once query handling with known-answer support exists
-	// Measure response sizes:
+	// TODO: Measure response sizes once response serialization exists:
 	// - initialResponseSize: without known-answers
 	// - suppressedResponseSize: with known-answers
 	// - reduction = (1 - suppressed/initial) * 100
 	// - assert reduction ≥ 30% (SC-009)
+	//
+	// beacon_responder_known_answer_suppressed_total (see WithMetrics) is
+	// the production-facing signal SC-009 asks for; this benchmark is a
+	// second, synthetic cross-check that still needs real wire-format
+	// responses to measure actual bytes saved.
 
-	t.Skip("Deferred until query handling with known-answer support implemented")
+	t.Skip("Deferred until response serialization (buildResponsePacket) is implemented")
 }
 
 // TestRFC6762_KnownAnswerSuppression_OneFourthTTL_RED tests the interaction
@@ -209,5 +230,15 @@ func TestRFC6762_KnownAnswerSuppression_OneFourthTTL(t *testing.T) {
 	// - §5.4: TTL < 25% → multicast (override QU bit)
 	// - Result: Send multicast response
 
-	t.Skip("Deferred until QU bit + known-answer interaction implemented")
+	// The two rules are independently implemented and composed at
+	// different layers: §7.1's TTL threshold in
+	// ResponseBuilder.ApplyKnownAnswerSuppression decides whether a record
+	// is answered at all, and §5.4's 1/4 TTL exception in
+	// Responder.unicastDest (responder/responder.go, backed by
+	// records.RecordSet.GetLastMulticast) decides unicast vs multicast for
+	// whatever is answered - see TestUnicastDest_OneFourthTTLForcesMulticast
+	// in responder/responder_test.go. Exercising the full interaction here
+	// needs the same real query/response round trip the other tests in
+	// this file are blocked on.
+	t.Skip("Deferred until response serialization (buildResponsePacket) is implemented")
 }
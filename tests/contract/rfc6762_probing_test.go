@@ -3,12 +3,51 @@ package contract
 import (
 	"context"
 	"encoding/binary"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/responder"
 )
 
+// renameRecordingHook is a minimal responder.EventHook recording the
+// newName passed to OnRename, for tests verifying Register's rename loop
+// runs in response to a lost naming conflict (RFC 6762 §9).
+type renameRecordingHook struct {
+	mu      sync.Mutex
+	newName string
+	count   int
+}
+
+func (h *renameRecordingHook) OnConflictDetected(responder.ConflictEvent) {}
+func (h *renameRecordingHook) OnProbeStart(name string)                   {}
+func (h *renameRecordingHook) OnProbeConflict(ourRecord, incomingRecord message.ResourceRecord) {
+}
+func (h *renameRecordingHook) OnProbeRateLimited(name string, delay time.Duration)   {}
+func (h *renameRecordingHook) OnAnnounce(serviceName string)                         {}
+func (h *renameRecordingHook) OnGoodbye(serviceName string)                          {}
+func (h *renameRecordingHook) OnKnownAnswerSuppressed(record message.ResourceRecord) {}
+
+func (h *renameRecordingHook) OnRename(oldName, newName string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.newName = newName
+	h.count++
+}
+
+func (h *renameRecordingHook) lastNewName() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.newName
+}
+
+func (h *renameRecordingHook) renameCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
 // TestRFC6762_Probing_ThreeQueries_RED tests RFC 6762 §8.1 probing compliance.
 //
 // TDD Phase: RED - These tests will FAIL until probing is implemented
@@ -223,3 +262,58 @@ func TestRFC6762_Probing_TieBreaking(t *testing.T) {
 		t.Error("registered service is nil")
 	}
 }
+
+// TestRFC6762_Probing_TieBreaking_Lose tests that losing a simultaneous-probe
+// tie-break per RFC 6762 §8.2.1 triggers exactly one rename, after which the
+// renamed name registers cleanly rather than tie-breaking forever.
+//
+// RFC 6762 §8.2.1: "...the host that is probing SHOULD defer to the other
+// host... if the simultaneous probe tie-break is lost, the probing host
+// MUST choose a new name."
+//
+// T028: Test tie-breaking loss for simultaneous probes
+func TestRFC6762_Probing_TieBreaking_Lose(t *testing.T) {
+	ctx := context.Background()
+	r, err := responder.New(ctx)
+	if err != nil {
+		t.Fatalf("responder.New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	service := &responder.Service{
+		InstanceName: "Tie Break Lose Test",
+		ServiceType:  "_http._tcp.local",
+		Port:         8080,
+	}
+
+	hook := &renameRecordingHook{}
+	r.AddEventHook(hook)
+
+	// Simulate a simultaneous probe where we LOSE (our data < their data) -
+	// this should only force one rename, not a permanent tie-break loop.
+	ourData := []byte{192, 168, 1, 50}
+	theirData := []byte{192, 168, 1, 100} // Lexicographically later, so they win
+	r.InjectSimultaneousProbe(ourData, theirData)
+
+	err = r.Register(service)
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil (should recover via rename after losing tie-break)", err)
+	}
+
+	if got := hook.renameCount(); got != 1 {
+		t.Errorf("OnRename called %d times, want exactly 1 after losing tie-break once", got)
+	}
+	renamedTo := hook.lastNewName()
+	if renamedTo == "" {
+		t.Fatal("OnRename was never called; want exactly one rename after losing tie-break")
+	}
+
+	registered, exists := r.GetService(renamedTo)
+	if !exists {
+		t.Errorf("renamed service %q not found in registry", renamedTo)
+	}
+	if registered != nil && registered.InstanceName != renamedTo {
+		t.Errorf("registered service InstanceName = %q, want renamed name %q",
+			registered.InstanceName, renamedTo)
+	}
+}
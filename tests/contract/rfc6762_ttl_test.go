@@ -35,7 +35,7 @@ func TestRFC6762_TTL_ServiceRecords(t *testing.T) {
 		InstanceName: "Test Service",
 		ServiceType:  "_http._tcp.local",
 		Port:         8080,
-		TXTRecords:   map[string]string{"version": "1.0"},
+		TXTRecords:   responder.NewTXTRecordsFromMap(map[string]string{"version": "1.0"}),
 	}
 
 	err = r.Register(svc)
@@ -159,8 +159,6 @@ func TestRFC6762_TTL_HostnameRecords(t *testing.T) {
 // FR-033: System MUST send goodbye announcements (TTL=0) on service removal
 // T115: Contract test for RFC 6762 §10.1 goodbye packets
 func TestRFC6762_TTL_GoodbyePackets(t *testing.T) {
-	t.Skip("Deferred: Goodbye packet functionality not yet implemented (TODO in Responder.Unregister)")
-
 	ctx := context.Background()
 	r, err := responder.New(ctx)
 	if err != nil {
@@ -180,16 +178,53 @@ func TestRFC6762_TTL_GoodbyePackets(t *testing.T) {
 		t.Fatalf("Register() error = %v, want nil", err)
 	}
 
+	// Sanity-check the normal announcement's TTLs, so the assertions below
+	// are proven to be looking at the goodbye records, not records that
+	// happened to already be zero.
+	announced := r.GetLastAnnouncedRecords()
+	if len(announced) == 0 {
+		t.Fatalf("GetLastAnnouncedRecords() before Unregister = 0 records, want >0")
+	}
+	for _, rr := range announced {
+		if rr.TTL == 0 {
+			t.Fatalf("announced record %v has TTL=0 before Unregister, want nonzero", rr.Type)
+		}
+	}
+
 	// Unregister the service
 	err = r.Unregister("Test Service")
 	if err != nil {
 		t.Fatalf("Unregister() error = %v, want nil", err)
 	}
 
-	// TODO: Capture goodbye packets
-	// When goodbye packet functionality is implemented:
-	// 1. Capture the last multicast message
-	// 2. Parse the message
-	// 3. Verify all records have TTL = 0
-	// 4. Verify record types match original announcement (PTR, SRV, TXT, A)
+	// GetLastAnnouncedRecords now reflects the goodbye set Unregister sent.
+	goodbye := r.GetLastAnnouncedRecords()
+	if len(goodbye) == 0 {
+		t.Fatalf("GetLastAnnouncedRecords() after Unregister = 0 records, want >0")
+	}
+
+	foundPTR, foundSRV, foundTXT := false, false, false
+	for _, rr := range goodbye {
+		if rr.TTL != 0 {
+			t.Errorf("goodbye record %v TTL = %d, want 0 (RFC 6762 §10.1)", rr.Type, rr.TTL)
+		}
+		switch rr.Type {
+		case protocol.RecordTypePTR:
+			foundPTR = true
+		case protocol.RecordTypeSRV:
+			foundSRV = true
+		case protocol.RecordTypeTXT:
+			foundTXT = true
+		}
+	}
+
+	if !foundPTR {
+		t.Error("no PTR record found in goodbye records")
+	}
+	if !foundSRV {
+		t.Error("no SRV record found in goodbye records")
+	}
+	if !foundTXT {
+		t.Error("no TXT record found in goodbye records")
+	}
 }
@@ -3,142 +3,182 @@ package contract
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/transport"
 	"github.com/joshuafuller/beacon/responder"
 )
 
+// enumResponseWait bounds how long lastEnumResponse polls for a response.
+// The enumeration PTR answer is a shared (non-cache-flush) record, so per
+// RFC 6762 §6 sendQueryResponse multicasts it after a randomized 20-120ms
+// delay rather than immediately - comfortably under one second.
+const enumResponseWait = time.Second
+
+// lastEnumResponse registers each of services on r, sends an RFC 6763 §9
+// service-type enumeration query ("_services._dns-sd._udp.local") through
+// r.HandlePacket, and returns the parsed response mock last sent - or nil if
+// nothing was sent.
+func lastEnumResponse(t *testing.T, services []*responder.Service) *message.DNSMessage {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping RFC contract test in short mode")
+	}
+
+	mock := transport.NewMockTransport()
+	ctx := context.Background()
+	r, err := responder.New(ctx, responder.WithTransport(mock))
+	if err != nil {
+		t.Fatalf("responder.New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	for _, svc := range services {
+		if err := r.Register(svc); err != nil {
+			t.Fatalf("Register(%q) error = %v, want nil", svc.InstanceName, err)
+		}
+	}
+
+	query, err := message.BuildQuery(protocol.ServiceTypeEnumerationName, uint16(protocol.RecordTypePTR))
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v, want nil", err)
+	}
+	if err := r.HandlePacket(query); err != nil {
+		t.Fatalf("HandlePacket() error = %v, want nil", err)
+	}
+
+	deadline := time.Now().Add(enumResponseWait)
+	var calls []transport.SendCall
+	for time.Now().Before(deadline) {
+		calls = mock.SendCalls()
+		if len(calls) > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(calls) == 0 {
+		return nil
+	}
+
+	response, err := message.ParseMessage(calls[len(calls)-1].Packet)
+	if err != nil {
+		t.Fatalf("ParseMessage(response) error = %v, want nil", err)
+	}
+	return response
+}
+
 // TestRFC6763_ServiceEnumeration_MetaQuery tests RFC 6763 §9 service type enumeration.
 //
-// TDD Phase: RED - This test will FAIL until _services._dns-sd._udp.local support is implemented
-//
 // RFC 6763 §9: "A DNS query for PTR records with the name '_services._dns-sd._udp.<Domain>'
 // yields a set of PTR records, where the rdata of each PTR record is the two-label <Service>
 // name, plus the same domain, e.g., '_http._tcp.<Domain>'."
 //
 // FR-027: System MUST respond to "_services._dns-sd._udp.local" PTR queries with a list
-// of all registered service types
-//
-// T103: Contract test for service enumeration
+// of all registered service types.
 func TestRFC6763_ServiceEnumeration_MetaQuery(t *testing.T) {
-	ctx := context.Background()
-	r, err := responder.New(ctx)
-	if err != nil {
-		t.Fatalf("responder.New() error = %v, want nil", err)
+	services := []*responder.Service{
+		{InstanceName: "Web Server", ServiceType: "_http._tcp.local", Port: 8080},
+		{InstanceName: "SSH Server", ServiceType: "_ssh._tcp.local", Port: 22},
+		{InstanceName: "FTP Server", ServiceType: "_ftp._tcp.local", Port: 21},
 	}
-	defer func() { _ = r.Close() }()
 
-	// Register 3 services with DIFFERENT service types
-	services := []*responder.Service{
-		{
-			InstanceName: "Web Server",
-			ServiceType:  "_http._tcp.local",
-			Port:         8080,
-		},
-		{
-			InstanceName: "SSH Server",
-			ServiceType:  "_ssh._tcp.local",
-			Port:         22,
-		},
-		{
-			InstanceName: "FTP Server",
-			ServiceType:  "_ftp._tcp.local",
-			Port:         21,
-		},
+	response := lastEnumResponse(t, services)
+	if response == nil {
+		t.Fatal("HandlePacket() sent no response, want one PTR answer per registered service type")
+	}
+
+	if int(response.Header.ANCount) != len(services) {
+		t.Errorf("ANCount = %d, want %d", response.Header.ANCount, len(services))
+	}
+	if len(response.Answers) != len(services) {
+		t.Fatalf("len(Answers) = %d, want %d", len(response.Answers), len(services))
 	}
 
+	wantTypes := map[string]bool{}
 	for _, svc := range services {
-		err = r.Register(svc)
-		if err != nil {
-			t.Fatalf("Register(%q) error = %v, want nil", svc.InstanceName, err)
-		}
+		wantTypes[svc.ServiceType] = true
 	}
 
-	// RFC 6763 §9: Test that responder can enumerate service types
-	// This requires implementing:
-	// 1. Recognizing "_services._dns-sd._udp.local" as a meta-query
-	// 2. Collecting unique service types from registry
-	// 3. Returning PTR records pointing to each service type
-	//
-	// For now, this is a placeholder test that documents the requirement.
-	// Full implementation requires:
-	// - T107: Implement _services._dns-sd._udp.local response in ResponseBuilder
-	// - DNS message serialization (to send actual query/response over wire)
+	gotTypes := map[string]bool{}
+	for _, answer := range response.Answers {
+		if answer.TYPE != uint16(protocol.RecordTypePTR) {
+			t.Errorf("answer TYPE = %d, want PTR (%d)", answer.TYPE, protocol.RecordTypePTR)
+		}
+		// RFC 6762 §10.2: the service-type enumeration PTR is a shared
+		// record (multiple service types coexist under the same meta-query
+		// name), so it must not set the cache-flush bit.
+		if answer.CLASS&0x8000 != 0 {
+			t.Errorf("answer CLASS = 0x%04x, want cache-flush bit (0x8000) clear", answer.CLASS)
+		}
+		if answer.TTL != uint32(protocol.TTLService) {
+			t.Errorf("answer TTL = %d, want %d", answer.TTL, protocol.TTLService)
+		}
 
-	// TODO: Once query/response mechanism is fully wired:
-	// 1. Send PTR query for "_services._dns-sd._udp.local"
-	// 2. Verify response contains 3 PTR records
-	// 3. Verify each PTR record points to a registered service type
+		gotTypes[decodePTRTarget(t, answer)] = true
+	}
 
-	t.Skip("Deferred until _services._dns-sd._udp.local response implementation (T107) and DNS message serialization")
+	for wantType := range wantTypes {
+		if !gotTypes[wantType] {
+			t.Errorf("enumeration response missing PTR for service type %q", wantType)
+		}
+	}
 }
 
 // TestRFC6763_ServiceEnumeration_DuplicateTypes tests that duplicate service types
 // only appear once in enumeration response.
 //
-// TDD Phase: RED
-//
 // RFC 6763 §9: Service type enumeration lists unique service types, not instances.
 // If 3 services all use "_http._tcp.local", enumeration should list "_http._tcp.local" once.
-//
-// T103: Edge case - duplicate service types
 func TestRFC6763_ServiceEnumeration_DuplicateTypes(t *testing.T) {
-	ctx := context.Background()
-	r, err := responder.New(ctx)
-	if err != nil {
-		t.Fatalf("responder.New() error = %v, want nil", err)
-	}
-	defer func() { _ = r.Close() }()
-
-	// Register 3 services with SAME service type
 	services := []*responder.Service{
-		{
-			InstanceName: "Web Server 1",
-			ServiceType:  "_http._tcp.local",
-			Port:         8080,
-		},
-		{
-			InstanceName: "Web Server 2",
-			ServiceType:  "_http._tcp.local",
-			Port:         8081,
-		},
-		{
-			InstanceName: "Web Server 3",
-			ServiceType:  "_http._tcp.local",
-			Port:         8082,
-		},
+		{InstanceName: "Web Server 1", ServiceType: "_http._tcp.local", Port: 8080},
+		{InstanceName: "Web Server 2", ServiceType: "_http._tcp.local", Port: 8081},
+		{InstanceName: "Web Server 3", ServiceType: "_http._tcp.local", Port: 8082},
 	}
 
-	for _, svc := range services {
-		err = r.Register(svc)
-		if err != nil {
-			t.Fatalf("Register(%q) error = %v, want nil", svc.InstanceName, err)
-		}
+	response := lastEnumResponse(t, services)
+	if response == nil {
+		t.Fatal("HandlePacket() sent no response, want one deduplicated PTR answer")
 	}
 
-	// RFC 6763 §9: Should return exactly 1 PTR record for "_http._tcp.local"
-	// NOT 3 PTR records (one per instance)
-	// Expected behavior: _services._dns-sd._udp.local query → 1 PTR → "_http._tcp.local"
-
-	t.Skip("Deferred until _services._dns-sd._udp.local response implementation (T107) and DNS message serialization")
+	if len(response.Answers) != 1 {
+		t.Fatalf("len(Answers) = %d, want 1 (deduplicated)", len(response.Answers))
+	}
+	if got := decodePTRTarget(t, response.Answers[0]); got != "_http._tcp.local" {
+		t.Errorf("answer target = %q, want %q", got, "_http._tcp.local")
+	}
 }
 
 // TestRFC6763_ServiceEnumeration_EmptyRegistry tests enumeration when no services registered.
 //
-// TDD Phase: RED
-//
-// RFC 6763 §9: If no services are registered, enumeration query should return empty response.
-//
-// T103: Edge case - empty registry
+// RFC 6763 §9: an empty answer is acceptable, so an empty registry still
+// gets a response - with ANCOUNT=0 - rather than no response at all.
 func TestRFC6763_ServiceEnumeration_EmptyRegistry(t *testing.T) {
-	ctx := context.Background()
-	r, err := responder.New(ctx)
-	if err != nil {
-		t.Fatalf("responder.New() error = %v, want nil", err)
+	response := lastEnumResponse(t, nil)
+	if response == nil {
+		t.Fatal("HandlePacket() sent no response for an empty registry, want one with ANCOUNT=0")
 	}
-	defer func() { _ = r.Close() }()
-
-	// No services registered - empty registry
-	// RFC 6763 §9: Query for "_services._dns-sd._udp.local" should return empty response
+	if response.Header.ANCount != 0 {
+		t.Errorf("ANCount = %d, want 0", response.Header.ANCount)
+	}
+	if len(response.Answers) != 0 {
+		t.Errorf("len(Answers) = %d, want 0", len(response.Answers))
+	}
+}
 
-	t.Skip("Deferred until _services._dns-sd._udp.local response implementation (T107) and DNS message serialization")
+// decodePTRTarget decodes answer's RDATA (a label-encoded domain name,
+// already decompressed by ParseMessage) into its string form.
+func decodePTRTarget(t *testing.T, answer message.Answer) string {
+	t.Helper()
+	rdata, err := message.ParseRDATA(answer.TYPE, answer.RDATA)
+	if err != nil {
+		t.Fatalf("ParseRDATA(%x) error = %v, want nil", answer.RDATA, err)
+	}
+	ptr, ok := rdata.(message.PTRData)
+	if !ok {
+		t.Fatalf("ParseRDATA returned %T, want message.PTRData", rdata)
+	}
+	return ptr.Name
 }
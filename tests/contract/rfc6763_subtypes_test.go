@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// TestRFC6763_Subtypes_PTRInAnnouncedRecords tests RFC 6763 §7.1 subtype PTR
+// records.
+//
+// RFC 6763 §7.1: "DNS-SD defines a mechanism by which a service can
+// additionally be found using more specific subtype names, of the form
+// '<sub>._sub.<service>.<domain>'." A service advertising a subtype must
+// emit this additional PTR record, pointing at the same instance name as
+// its primary "_service._proto.local" PTR, alongside the standard one.
+//
+// FR-027 (extended): System MUST announce one PTR per registered subtype.
+func TestRFC6763_Subtypes_PTRInAnnouncedRecords(t *testing.T) {
+	ctx := context.Background()
+	r, err := responder.New(ctx)
+	if err != nil {
+		t.Fatalf("responder.New() error = %v, want nil", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	svc := &responder.Service{
+		InstanceName: "Office Printer",
+		ServiceType:  "_ipp._tcp.local",
+		Port:         631,
+		Subtypes:     []string{"_universal", "_printer"},
+	}
+
+	if err := r.Register(svc); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	recordSet := r.GetLastAnnouncedRecords()
+	if len(recordSet) == 0 {
+		t.Fatal("GetLastAnnouncedRecords() = 0 records, want >0")
+	}
+
+	wantNames := map[string]bool{
+		"_universal._sub._ipp._tcp.local": false,
+		"_printer._sub._ipp._tcp.local":   false,
+	}
+	foundPrimaryPTR := false
+
+	for _, rr := range recordSet {
+		if rr.Type != protocol.RecordTypePTR {
+			continue
+		}
+		if rr.Name == svc.ServiceType {
+			foundPrimaryPTR = true
+			continue
+		}
+		if _, want := wantNames[rr.Name]; want {
+			wantNames[rr.Name] = true
+			if rr.CacheFlush {
+				t.Errorf("subtype PTR %q has CacheFlush=true, want false (PTR is shared)", rr.Name)
+			}
+			if rr.TTL != uint32(protocol.TTLService) {
+				t.Errorf("subtype PTR %q TTL = %d, want %d", rr.Name, rr.TTL, protocol.TTLService)
+			}
+		}
+	}
+
+	if !foundPrimaryPTR {
+		t.Errorf("no primary PTR record %q found alongside subtype PTRs", svc.ServiceType)
+	}
+	for name, found := range wantNames {
+		if !found {
+			t.Errorf("no subtype PTR record found for %q", name)
+		}
+	}
+}
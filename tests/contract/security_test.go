@@ -2,6 +2,7 @@ package contract
 
 import (
 	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/joshuafuller/beacon/internal/security"
@@ -94,10 +95,7 @@ func TestSourceIPFiltering_RFC6762_LinkLocalScope(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ip := net.ParseIP(tt.sourceIP)
-			if ip == nil {
-				t.Fatalf("Failed to parse IP: %s", tt.sourceIP)
-			}
+			ip := netip.MustParseAddr(tt.sourceIP)
 
 			got := sf.IsValid(ip)
 
@@ -0,0 +1,130 @@
+// Package fuzz provides fuzz testing for DNS name compression.
+//
+// Fuzz testing validates that compressed message building round-trips
+// correctly and that the parser's compression-pointer guards never panic,
+// regardless of how many records share or diverge in their name suffixes.
+package fuzz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+// FuzzCompressionRoundTrip tests that BuildResponse's RFC 1035 §4.1.4 name
+// compression never changes what a name decodes to, across a fuzzed set of
+// names that may or may not share suffixes with each other.
+//
+// Names aren't compared against the fuzzed input directly: EncodeOwnerName
+// already normalizes things like a trailing root dot or empty labels before
+// compression ever sees them, and that normalization isn't what's under
+// test here. Instead each name is first round-tripped on its own (a single
+// record can't trigger cross-record compression) to get the name compression
+// must preserve, then compared against the same name encoded alongside a
+// second record that may share its suffix.
+//
+// The fuzzer tests:
+//   - Records whose names share no suffix (no compression opportunity)
+//   - Records whose names are identical (pointer-only compression)
+//   - Records whose names share a partial suffix (e.g. same "local" domain)
+//
+// Expected behavior:
+//   - BuildResponse never panics, regardless of input names
+//   - If BuildResponse succeeds, ParseMessage MUST parse the result and
+//     recover, case-insensitively, the same owner name compression would
+//     have produced without any sharing (no data loss from compression;
+//     case may fold to an earlier occurrence's per RFC 1035 §2.3.3)
+//
+// NFR-003: System MUST handle malformed/adversarial input without panics
+//
+// Run with: go test -fuzz=FuzzCompressionRoundTrip -fuzztime=10000x ./tests/fuzz/
+func FuzzCompressionRoundTrip(f *testing.F) {
+	// Seed corpus: two records with an identical name (pointer-only compression)
+	f.Add("_http._tcp.local", "_http._tcp.local")
+
+	// Seed corpus: names sharing only the "local" suffix
+	f.Add("printer.local", "scanner.local")
+
+	// Seed corpus: one name a strict suffix of the other
+	f.Add("tcp.local", "_http._tcp.local")
+
+	// Seed corpus: names with no shared suffix at all
+	f.Add("printer.local", "_http._tcp.example")
+
+	// Seed corpus: empty and root names
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, name1, name2 string) {
+		uncompressedName := func(name string) (string, bool) {
+			wire, err := message.BuildResponse([]*message.ResourceRecord{{
+				Name:  name,
+				Type:  protocol.RecordTypeA,
+				Class: protocol.ClassIN,
+				TTL:   120,
+				Data:  []byte{192, 168, 1, 1},
+			}})
+			if err != nil {
+				return "", false
+			}
+			parsed, err := message.ParseMessage(wire)
+			if err != nil || len(parsed.Answers) != 1 {
+				return "", false
+			}
+			return parsed.Answers[0].NAME, true
+		}
+
+		want1, ok1 := uncompressedName(name1)
+		want2, ok2 := uncompressedName(name2)
+		if !ok1 || !ok2 {
+			// One of the names is invalid on its own; BuildResponse below
+			// should reject it too, not panic.
+		}
+
+		answers := []*message.ResourceRecord{
+			{
+				Name:  name1,
+				Type:  protocol.RecordTypeA,
+				Class: protocol.ClassIN,
+				TTL:   120,
+				Data:  []byte{192, 168, 1, 1},
+			},
+			{
+				Name:  name2,
+				Type:  protocol.RecordTypeA,
+				Class: protocol.ClassIN,
+				TTL:   120,
+				Data:  []byte{192, 168, 1, 2},
+			},
+		}
+
+		wire, err := message.BuildResponse(answers)
+		if err != nil {
+			// Invalid names (e.g. too long, bad characters) are expected to
+			// error, not panic.
+			return
+		}
+		if !ok1 || !ok2 {
+			t.Fatalf("BuildResponse succeeded with a name that failed alone: name1=%q ok1=%v, name2=%q ok2=%v", name1, ok1, name2, ok2)
+		}
+
+		parsed, parseErr := message.ParseMessage(wire)
+		if parseErr != nil {
+			t.Fatalf("ParseMessage failed on BuildResponse's own output: %v\nwire: % x", parseErr, wire)
+		}
+
+		if len(parsed.Answers) != len(answers) {
+			t.Fatalf("got %d answers, want %d", len(parsed.Answers), len(answers))
+		}
+		for i, want := range []string{want1, want2} {
+			// Compression matches suffixes case-insensitively per RFC 1035
+			// §2.3.3, so a name whose only difference from an earlier one is
+			// case may come back decoded in that earlier occurrence's case
+			// (the same behavior as a real authoritative server's compressor).
+			if got := parsed.Answers[i].NAME; !strings.EqualFold(got, want) {
+				t.Errorf("Answers[%d].NAME = %q, want %q (case-insensitive; compression must be lossless)", i, got, want)
+			}
+		}
+	})
+}
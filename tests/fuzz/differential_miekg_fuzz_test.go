@@ -0,0 +1,159 @@
+//go:build differential_fuzz
+
+// Package fuzz provides fuzz testing for DNS message parsing.
+//
+// This file is gated behind the differential_fuzz build tag so the default
+// build (and default `go test ./...`) stays free of the github.com/miekg/dns
+// dependency; it is only pulled in when deliberately hunting for wire-format
+// divergences.
+package fuzz
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/errors"
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/miekg/dns"
+)
+
+// FuzzParseAgainstMiekg differentially fuzzes internal/message.ParseMessage
+// against github.com/miekg/dns.Msg.Unpack, a mature, widely deployed DNS
+// parser. Both parsers run on the same fuzzed bytes; a self-consistent bug in
+// Beacon's parser (compression pointer loops, off-by-one label lengths, EDNS
+// OPT mishandling) will often still "succeed" on malformed input that miekg
+// rejects, or vice versa, and such divergences are the signal this fuzzer
+// looks for.
+//
+// Run with: go test -tags differential_fuzz -fuzz=FuzzParseAgainstMiekg -fuzztime=10000x ./tests/fuzz/
+func FuzzParseAgainstMiekg(f *testing.F) {
+	// Seed corpus: a valid DNS response message (same as FuzzParseMessage's
+	// seed, so both fuzzers start from a known-good wire packet).
+	validMessage := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags (QR=1, AA=1)
+		0x00, 0x01, // QDCOUNT = 1
+		0x00, 0x01, // ANCOUNT = 1
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x00, // ARCOUNT = 0
+
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // QTYPE = A
+		0x00, 0x01, // QCLASS = IN
+
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 100, // RDATA = 192.168.1.100
+	}
+	f.Add(validMessage)
+
+	// Seed corpus: a name with a compression pointer, which is where the two
+	// parsers' decompression logic is most likely to disagree.
+	compressed := []byte{
+		0x00, 0x00, 0x84, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0x00, 0x04,
+		192, 168, 1, 101,
+	}
+	f.Add(compressed)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		beaconMsg, beaconErr := message.ParseMessage(data)
+
+		miekgMsg := new(dns.Msg)
+		miekgErr := miekgMsg.Unpack(data)
+
+		beaconOK := beaconErr == nil
+		miekgOK := miekgErr == nil
+
+		if beaconOK != miekgOK {
+			// Beacon deliberately rejects messages declaring more records
+			// than protocol.MaxRecordsPerMessage as a DoS-hardening measure
+			// (see internal/message/parser.go); miekg/dns has no equivalent
+			// cap and happily unpacks them. That's an intentional
+			// stricter-than-miekg divergence, not a parser bug, so it's
+			// excluded from the disagreements this fuzzer is looking for.
+			if !beaconOK && miekgOK && goerrors.Is(beaconErr, errors.ErrMessageTooLarge) {
+				return
+			}
+			t.Fatalf("parser disagreement on acceptance: beacon ok=%v (err=%v), miekg ok=%v (err=%v), data=%x",
+				beaconOK, beaconErr, miekgOK, miekgErr, data)
+		}
+
+		if !beaconOK {
+			// Both parsers rejected the input; nothing further to compare.
+			return
+		}
+
+		if len(beaconMsg.Questions) != len(miekgMsg.Question) {
+			t.Fatalf("question count mismatch: beacon=%d, miekg=%d, data=%x",
+				len(beaconMsg.Questions), len(miekgMsg.Question), data)
+		}
+		if len(beaconMsg.Answers) != len(miekgMsg.Answer) {
+			t.Fatalf("answer count mismatch: beacon=%d, miekg=%d, data=%x",
+				len(beaconMsg.Answers), len(miekgMsg.Answer), data)
+		}
+
+		for i, q := range beaconMsg.Questions {
+			want := canonicalName(miekgMsg.Question[i].Name)
+			got := canonicalName(q.QNAME)
+			if got != want {
+				t.Fatalf("question[%d] name mismatch: beacon=%q, miekg=%q, data=%x", i, got, want, data)
+			}
+		}
+
+		for i, a := range beaconMsg.Answers {
+			want := normalizeRR(miekgMsg.Answer[i])
+			got := canonicalName(a.NAME)
+			if got != want.name {
+				t.Fatalf("answer[%d] name mismatch: beacon=%q, miekg=%q, data=%x", i, got, want.name, data)
+			}
+		}
+	})
+}
+
+// normalizedRR is the subset of a dns.RR's canonical presentation form that
+// FuzzParseAgainstMiekg compares against the equivalent message.Answer field.
+type normalizedRR struct {
+	name string
+}
+
+// normalizeRR reduces a miekg dns.RR to its canonical (fully-qualified,
+// lower-cased, trailing-dot-stripped) name, matching how
+// message.ResourceRecord/Answer represent names.
+func normalizeRR(rr dns.RR) normalizedRR {
+	return normalizedRR{name: canonicalName(rr.Header().Name)}
+}
+
+// canonicalName normalizes a presentation-form domain name for comparison:
+// lower-cased, with any single trailing root dot removed, matching
+// message.ParseMessage's output (which never emits a trailing dot for
+// "local" names).
+func canonicalName(name string) string {
+	lower := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		lower = append(lower, c)
+	}
+	normalized := string(lower)
+	if len(normalized) > 0 && normalized[len(normalized)-1] == '.' {
+		normalized = normalized[:len(normalized)-1]
+	}
+	return normalized
+}
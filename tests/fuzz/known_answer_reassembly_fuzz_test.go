@@ -0,0 +1,109 @@
+// Package fuzz provides fuzz testing for multi-packet Known-Answer
+// reassembly.
+//
+// Fuzz testing validates that reassembling a Known-Answer list split across
+// RFC 6762 §7.2 TC=1 continuation packets handles arbitrary packet counts
+// and MTUs without crashes or panics per NFR-003.
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/responder"
+)
+
+// FuzzKnownAnswerReassembly tests that a Known-Answer list split across
+// multiple TC=1 continuation packets by message.QueryBuilder (RFC 6762 §7.2)
+// is parsed and reassembled by internal/responder.KnownAnswerCache's
+// Merge/Take without panicking, and recovers exactly the known-answers that
+// were sent - for any fuzzed record count and packet MTU.
+//
+// NFR-003: System MUST handle malformed/adversarial input without crashes
+// or panics
+//
+// Run with: go test -fuzz=FuzzKnownAnswerReassembly -fuzztime=10s ./tests/fuzz/
+func FuzzKnownAnswerReassembly(f *testing.F) {
+	f.Add(1, 1500)
+	f.Add(0, 1500)  // no known-answers: a single, non-truncated packet
+	f.Add(50, 1500) // fits in one packet at a normal MTU
+	f.Add(5, 100)   // small MTU forces a split with few records
+	f.Add(200, 60)  // many records, tiny MTU forces many continuation packets
+	f.Add(10, 0)    // MTU too small to hold even a header and question
+
+	f.Fuzz(func(t *testing.T, numKnownAnswers, mtu int) {
+		// Bound the fuzzed values so one iteration can't spend its whole
+		// budget building an absurdly large packet set; NewQueryBuilder
+		// and Build already validate mtu/recordType on their own terms.
+		if numKnownAnswers < 0 {
+			numKnownAnswers = -numKnownAnswers
+		}
+		numKnownAnswers %= 500
+		if mtu < 0 {
+			mtu = -mtu
+		}
+		mtu %= 1500
+
+		qb, err := message.NewQueryBuilder("_http._tcp.local", uint16(protocol.RecordTypePTR), mtu)
+		if err != nil {
+			// An MTU too small for a header and question is a validation
+			// error, not a panic - nothing left to reassemble.
+			return
+		}
+
+		knownAnswers := make([]*message.ResourceRecord, 0, numKnownAnswers)
+		for i := 0; i < numKnownAnswers; i++ {
+			knownAnswers = append(knownAnswers, &message.ResourceRecord{
+				Name:  "_http._tcp.local",
+				Type:  protocol.RecordTypePTR,
+				Class: protocol.ClassIN,
+				TTL:   4500,
+				Data:  []byte{0x03, 'f', 'o', 'o', 0x00},
+			})
+		}
+
+		packets, err := qb.Build(knownAnswers)
+		if err != nil {
+			return
+		}
+
+		cache := responder.NewKnownAnswerCache(func(string, uint16, []message.Question, []*message.ResourceRecord) {}, nil)
+
+		const sourceAddr = "192.0.2.1:5353"
+		var reassembled []*message.ResourceRecord
+		for _, packet := range packets {
+			msg, err := message.ParseMessage(packet)
+			if err != nil {
+				t.Fatalf("ParseMessage failed on a packet QueryBuilder itself produced: %v", err)
+			}
+
+			known := make([]*message.ResourceRecord, 0, len(msg.Answers))
+			for _, a := range msg.Answers {
+				known = append(known, &message.ResourceRecord{
+					Name:  a.NAME,
+					Type:  protocol.RecordType(a.TYPE),
+					Class: protocol.DNSClass(a.CLASS),
+					TTL:   a.TTL,
+					Data:  a.RDATA,
+				})
+			}
+
+			if msg.Header.IsTruncated() {
+				cache.Merge(sourceAddr, msg.Header.ID, msg.Questions, known)
+				continue
+			}
+
+			// Final packet: fold in anything buffered by earlier
+			// continuation packets, the same way handleQuery does.
+			if _, prior, found := cache.Take(sourceAddr, msg.Header.ID); found {
+				reassembled = append(reassembled, prior...)
+			}
+			reassembled = append(reassembled, known...)
+		}
+
+		if len(reassembled) != numKnownAnswers {
+			t.Errorf("reassembled %d known-answers across %d packet(s), want %d", len(reassembled), len(packets), numKnownAnswers)
+		}
+	})
+}
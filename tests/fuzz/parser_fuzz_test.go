@@ -8,8 +8,19 @@ import (
 	"testing"
 
 	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
 )
 
+// mustEncodeName encodes name to wire format, panicking on error. Only used
+// to build this file's own seed corpus from names known to be valid.
+func mustEncodeName(name string) []byte {
+	encoded, err := message.EncodeName(name)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
 // FuzzParseMessage tests ParseMessage with random inputs to ensure it handles
 // malformed packets without crashes or panics per NFR-003.
 //
@@ -246,6 +257,176 @@ func FuzzParseMessage(f *testing.F) {
 	}
 	f.Add(compressionLoop)
 
+	// Seed corpus: Valid OPT record (RFC 6891) with NSID and Cookie options
+	optMessage := []byte{
+		// Header: response, ARCOUNT=1
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x00, // ANCOUNT = 0
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x01, // ARCOUNT = 1
+
+		// OPT: root name, TYPE=41, CLASS=4096 (UDP payload size),
+		// TTL: ext RCODE=0, version=0, DO=1
+		0x00,       // NAME = root
+		0x00, 0x29, // TYPE = 41 (OPT)
+		0x10, 0x00, // CLASS = 4096
+		0x00, 0x00, 0x80, 0x00, // TTL
+		0x00, 0x0E, // RDLENGTH = 14
+		0x00, 0x03, 0x00, 0x00, // NSID option, length 0
+		0x00, 0x0A, 0x00, 0x06, 'a', 'b', 'c', 'd', 'e', 'f', // Cookie option, 6 bytes
+	}
+	f.Add(optMessage)
+
+	// Seed corpus: OPT record whose option-length overruns the RDATA
+	optOptionLengthOverrun := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x00, // ANCOUNT = 0
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x01, // ARCOUNT = 1
+
+		0x00,       // NAME = root
+		0x00, 0x29, // TYPE = 41 (OPT)
+		0x10, 0x00, // CLASS = 4096
+		0x00, 0x00, 0x00, 0x00, // TTL
+		0x00, 0x04, // RDLENGTH = 4
+		0x00, 0x0C, 0x00, 0xFF, // PADDING option claims 255 bytes, none present
+	}
+	f.Add(optOptionLengthOverrun)
+
+	// Seed corpus: OPT record with an unrecognized option code
+	optUnknownOptionCode := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x00, // ANCOUNT = 0
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x01, // ARCOUNT = 1
+
+		0x00,       // NAME = root
+		0x00, 0x29, // TYPE = 41 (OPT)
+		0x10, 0x00, // CLASS = 4096
+		0x00, 0x00, 0x00, 0x00, // TTL
+		0x00, 0x04, // RDLENGTH = 4
+		0xFF, 0xFF, 0x00, 0x00, // Unknown option code 65535, length 0
+	}
+	f.Add(optUnknownOptionCode)
+
+	// Seed corpus: OPT record with a DAU option of odd length (RFC 6975 §3
+	// defines DAU/DHU/N3U as a list of single-octet algorithm numbers, so an
+	// odd length is unusual but not itself malformed wire format)
+	optDAUOddLength := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x00, // ANCOUNT = 0
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x01, // ARCOUNT = 1
+
+		0x00,       // NAME = root
+		0x00, 0x29, // TYPE = 41 (OPT)
+		0x10, 0x00, // CLASS = 4096
+		0x00, 0x00, 0x00, 0x00, // TTL
+		0x00, 0x05, // RDLENGTH = 5
+		0x00, 0x05, 0x00, 0x01, 0x08, // DAU option, 1 byte (algorithm 8)
+	}
+	f.Add(optDAUOddLength)
+
+	// Seed corpus: OPT record truncated mid-option (missing option-length)
+	optTruncatedOptionHeader := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x00, // ANCOUNT = 0
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x01, // ARCOUNT = 1
+
+		0x00,       // NAME = root
+		0x00, 0x29, // TYPE = 41 (OPT)
+		0x10, 0x00, // CLASS = 4096
+		0x00, 0x00, 0x00, 0x00, // TTL
+		0x00, 0x02, // RDLENGTH = 2
+		0x00, 0x03, // Option code only, no length/data
+	}
+	f.Add(optTruncatedOptionHeader)
+
+	// Seed corpus: Valid NSEC3 record (RFC 5155 §3) with a salt, a hashed next
+	// owner name, and a type bit map spanning two windows.
+	nsec3Message := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x01, // ANCOUNT = 1
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x00, // ARCOUNT = 0
+
+		0x00,       // NAME = root
+		0x00, 0x32, // TYPE = 50 (NSEC3)
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x10, // RDLENGTH = 16
+		0x01,       // Hash Algorithm = SHA-1
+		0x01,       // Flags = Opt-Out
+		0x00, 0x0A, // Iterations = 10
+		0x02, 0xAA, 0xBB, // Salt length=2, salt
+		0x04, 0x01, 0x02, 0x03, 0x04, // Hash length=4, next hashed owner name
+		0x00, 0x02, 0x40, 0x01, // Window 0, length 2, bitmap with bits 1 and 15 set
+	}
+	f.Add(nsec3Message)
+
+	// Seed corpus: NSEC3 record whose type bit map is truncated mid-window
+	// (bitmap-length claims more bytes than RDLENGTH leaves available).
+	nsec3TruncatedBitmap := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x01, // ANCOUNT = 1
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x00, // ARCOUNT = 0
+
+		0x00,       // NAME = root
+		0x00, 0x32, // TYPE = 50 (NSEC3)
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x0A, // RDLENGTH = 10
+		0x01,       // Hash Algorithm = SHA-1
+		0x00,       // Flags
+		0x00, 0x00, // Iterations = 0
+		0x00,       // Salt length = 0
+		0x01, 0xFF, // Hash length=1, next hashed owner name
+		0x00, 0x20, 0x01, // Window 0 claims bitmap length 32, only 1 byte present
+	}
+	f.Add(nsec3TruncatedBitmap)
+
+	// Seed corpus: NSEC3 record with out-of-order windows (window 1 emitted
+	// before window 0) - parseNSECTypeBitMap imposes no ordering requirement,
+	// so this must parse successfully rather than error or panic.
+	nsec3OutOfOrderWindows := []byte{
+		0x12, 0x34, // ID
+		0x84, 0x00, // Flags
+		0x00, 0x00, // QDCOUNT = 0
+		0x00, 0x01, // ANCOUNT = 1
+		0x00, 0x00, // NSCOUNT = 0
+		0x00, 0x00, // ARCOUNT = 0
+
+		0x00,       // NAME = root
+		0x00, 0x32, // TYPE = 50 (NSEC3)
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x0D, // RDLENGTH = 13
+		0x01,       // Hash Algorithm = SHA-1
+		0x00,       // Flags
+		0x00, 0x00, // Iterations = 0
+		0x00,       // Salt length = 0
+		0x01, 0xFF, // Hash length=1, next hashed owner name
+		0x01, 0x01, 0x01, // Window 1 (type 256+7), length 1, bit 7 set
+		0x00, 0x01, 0x40, // Window 0 (type 1 = A), length 1, bit 0 set
+	}
+	f.Add(nsec3OutOfOrderWindows)
+
 	// Seed corpus: Empty message (just header, no sections)
 	emptyMessage := []byte{
 		0x12, 0x34, // ID
@@ -257,6 +438,36 @@ func FuzzParseMessage(f *testing.F) {
 	}
 	f.Add(emptyMessage)
 
+	// Seed corpus: a response built through message.Compressor, the same
+	// writer BuildResponse uses, with PTR/SRV/TXT records that all share the
+	// "_http._tcp.local" suffix so the wire form actually exercises a chain
+	// of compression pointers rather than just one.
+	if compressed, err := message.BuildResponse([]*message.ResourceRecord{
+		{
+			Name:  "_http._tcp.local",
+			Type:  protocol.RecordTypePTR,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  mustEncodeName("printer._http._tcp.local"),
+		},
+		{
+			Name:  "printer._http._tcp.local",
+			Type:  protocol.RecordTypeSRV,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  append([]byte{0x00, 0x00, 0x00, 0x00, 0x1F, 0x90}, mustEncodeName("printer.local")...),
+		},
+		{
+			Name:  "printer._http._tcp.local",
+			Type:  protocol.RecordTypeTXT,
+			Class: protocol.ClassIN,
+			TTL:   120,
+			Data:  []byte{0x09, 'k', 'e', 'y', '=', 'v', 'a', 'l', 'u', 'e'},
+		},
+	}); err == nil {
+		f.Add(compressed)
+	}
+
 	// Fuzz function: ParseMessage must not panic on any input
 	f.Fuzz(func(_ *testing.T, data []byte) {
 		// The critical requirement is: NO PANICS (NFR-003)
@@ -24,6 +24,7 @@ import (
 //   - Malformed service types (missing underscores, invalid protocol)
 //   - Invalid TXT record sizes (> 1300 bytes)
 //   - Special characters and UTF-8 in instance names
+//   - Malformed RFC 6763 §7.1 subtypes (missing underscore, embedded spaces)
 //   - Random byte sequences interpreted as strings
 //
 // Expected behavior:
@@ -36,20 +37,45 @@ import (
 // Run with: go test -fuzz=FuzzServiceRegistration -fuzztime=10s ./tests/fuzz/
 func FuzzServiceRegistration(f *testing.F) {
 	// Seed corpus: Valid service
-	f.Add("My Service", "_http._tcp.local", 8080, "key1", "value1")
+	f.Add("My Service", "_http._tcp.local", 8080, "key1", "value1", "", "")
 
 	// Seed corpus: Edge cases
-	f.Add("", "_http._tcp.local", 8080, "", "")                                                           // Empty instance name
-	f.Add("Service", "", 8080, "", "")                                                                    // Empty service type
-	f.Add("Service", "_http._tcp.local", 0, "", "")                                                       // Port = 0
-	f.Add("Service", "_http._tcp.local", 65536, "", "")                                                   // Port > 65535
-	f.Add("Service", "_http._tcp.local", -1, "", "")                                                      // Port < 0
-	f.Add("VeryLongNameThatExceeds63OctetsLimitPerRFC1035Section2.3.4", "_http._tcp.local", 8080, "", "") // Long name
-	f.Add("Service", "no-underscore.tcp.local", 8080, "", "")                                             // Missing underscore
-	f.Add("Service", "_http._invalid.local", 8080, "", "")                                                // Invalid protocol
-	f.Add("Service With Spaces", "_http._tcp.local", 8080, "", "")                                        // Spaces in name (valid per RFC 6763 §4.1)
-
-	f.Fuzz(func(t *testing.T, instanceName, serviceType string, port int, txtKey, txtValue string) {
+	f.Add("", "_http._tcp.local", 8080, "", "", "", "")                                                           // Empty instance name
+	f.Add("Service", "", 8080, "", "", "", "")                                                                    // Empty service type
+	f.Add("Service", "_http._tcp.local", 0, "", "", "", "")                                                       // Port = 0
+	f.Add("Service", "_http._tcp.local", 65536, "", "", "", "")                                                   // Port > 65535
+	f.Add("Service", "_http._tcp.local", -1, "", "", "", "")                                                      // Port < 0
+	f.Add("VeryLongNameThatExceeds63OctetsLimitPerRFC1035Section2.3.4", "_http._tcp.local", 8080, "", "", "", "") // Long name
+	f.Add("Service", "no-underscore.tcp.local", 8080, "", "", "", "")                                             // Missing underscore
+	f.Add("Service", "_http._invalid.local", 8080, "", "", "", "")                                                // Invalid protocol
+	f.Add("Service With Spaces", "_http._tcp.local", 8080, "", "", "", "")                                        // Spaces in name (valid per RFC 6763 §4.1)
+
+	// Seed corpus: IPv6 literals in TXT and hostname positions, exercising
+	// IPv6-shaped string parsing outside the address-detection helpers that
+	// normally only ever see net.IP values, not arbitrary user strings.
+	f.Add("Service", "_http._tcp.local", 8080, "ipv6", "::1", "", "")                                                 // Loopback
+	f.Add("Service", "_http._tcp.local", 8080, "ipv6", "2001:db8::1", "", "")                                         // Global unicast
+	f.Add("Service", "_http._tcp.local", 8080, "ipv6", "fe80::1%eth0", "", "")                                        // Link-local with zone
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "::1", "")                                                     // IPv6 literal as hostname
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "2001:db8::1", "")                                             // IPv6 literal as hostname
+	f.Add("Service", "_http._tcp.local", 8080, "ipv6", "2001:0db8:85a3:0000:0000:8a2e:0370:7334", "fe80::1%eth0", "") // Full + zoned hostname
+
+	// Seed corpus: non-ASCII instance names and hostnames (RFC 6763 §4.1
+	// permits arbitrary UTF-8 in instance names; hostnames go through
+	// protocol.EncodeName's IDNA/Punycode path instead).
+	f.Add("🎉 Party Speaker", "_http._tcp.local", 8080, "", "", "", "")      // Emoji instance name
+	f.Add("客厅电视", "_http._tcp.local", 8080, "", "", "", "")                // CJK instance name
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "café.local", "")    // Non-ASCII hostname
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "🎉.local", "")       // Emoji hostname (not valid IDNA)
+
+	// Seed corpus: RFC 6763 §7.1 subtypes, including malformed ones that
+	// validateSubtype should reject rather than panic on.
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "", "_printer")  // Valid subtype
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "", "printer")   // Missing leading underscore
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "", "_sub")      // Subtype literally "_sub"
+	f.Add("Service", "_http._tcp.local", 8080, "", "", "", "_print er") // Space in subtype
+
+	f.Fuzz(func(t *testing.T, instanceName, serviceType string, port int, txtKey, txtValue, hostname, subtype string) {
 		// Create responder (each iteration gets its own responder for isolation)
 		ctx := context.Background()
 		r, err := responder.New(ctx)
@@ -68,12 +94,19 @@ func FuzzServiceRegistration(f *testing.F) {
 			txtRecords[txtKey] = txtValue
 		}
 
+		var subtypes []string
+		if subtype != "" {
+			subtypes = []string{subtype}
+		}
+
 		// Construct service from fuzz inputs
 		svc := &responder.Service{
 			InstanceName: instanceName,
 			ServiceType:  serviceType,
 			Port:         port,
-			TXTRecords:   txtRecords,
+			TXTRecords:   responder.NewTXTRecordsFromMap(txtRecords),
+			Hostname:     hostname,
+			Subtypes:     subtypes,
 		}
 
 		// Attempt registration - should NEVER panic
@@ -9,6 +9,7 @@ import (
 
 	"github.com/joshuafuller/beacon/internal/message"
 	"github.com/joshuafuller/beacon/internal/protocol"
+	"github.com/joshuafuller/beacon/internal/records"
 	"github.com/joshuafuller/beacon/internal/responder"
 )
 
@@ -110,7 +111,7 @@ func FuzzResponseBuilder(f *testing.F) {
 			Domain:       "local",
 			Port:         8080,
 			IPv4Address:  []byte{192, 168, 1, 100},
-			TXTRecords:   map[string]string{"version": "1.0"},
+			TXTRecords:   records.TXTRecordsFromMap(map[string]string{"version": "1.0"}),
 			Hostname:     "test.local",
 		}
 
@@ -166,6 +167,46 @@ func FuzzMessageBuilding(f *testing.F) {
 	// Seed corpus: Invalid type
 	f.Add("test.local", uint16(0xFFFF), []byte{0x01, 0x02})
 
+	// Seed corpus: HTTPS record (RFC 9460) with alpn + port SvcParams
+	httpsRDATA, _ := message.EncodeSVCB(&message.SVCBData{
+		Priority: 1,
+		Target:   "test.local",
+		Params: []message.SvcParam{
+			message.BuildALPNParam([]string{"h2", "http/1.1"}),
+			message.BuildPortParam(8080),
+		},
+	})
+	f.Add("_http._tcp.local", uint16(protocol.RecordTypeHTTPS), httpsRDATA)
+
+	// Seed corpus: SVCB record with no params
+	f.Add("test.local", uint16(protocol.RecordTypeSVCB), []byte{0x00, 0x00, 0x00})
+
+	// Seed corpus: RRSIG record (RFC 4034 §3.1) covering an A record
+	rrsigRDATA, _ := message.EncodeRRSIG(&message.RRSIGData{
+		TypeCovered:         uint16(protocol.RecordTypeA),
+		Algorithm:           message.AlgorithmECDSAP256SHA256,
+		Labels:              2,
+		OriginalTTL:         4500,
+		SignatureExpiration: 2000,
+		SignatureInception:  1000,
+		KeyTag:              12345,
+		SignerName:          "host.local",
+		Signature:           make([]byte, 64),
+	})
+	f.Add("host.local", uint16(protocol.RecordTypeRRSIG), rrsigRDATA)
+
+	// Seed corpus: truncated RRSIG (shorter than the 18-byte fixed prefix)
+	f.Add("host.local", uint16(protocol.RecordTypeRRSIG), []byte{0x00, 0x01, 0x0D})
+
+	// Seed corpus: DNSKEY record (RFC 4034 §2.1)
+	dnskeyRDATA := message.EncodeDNSKEY(&message.DNSKEYData{
+		Flags:     message.DNSKEYFlagZoneKey,
+		Protocol:  message.DNSKEYProtocol,
+		Algorithm: message.AlgorithmED25519,
+		PublicKey: make([]byte, 32),
+	})
+	f.Add("host.local", uint16(protocol.RecordTypeDNSKEY), dnskeyRDATA)
+
 	f.Fuzz(func(t *testing.T, name string, recordType uint16, data []byte) {
 		// Construct a resource record from fuzz inputs
 		rr := &message.ResourceRecord{
@@ -0,0 +1,168 @@
+// Package fuzz provides fuzz testing for DNS wire-format decoding.
+//
+// Unlike parser_fuzz_test.go's FuzzParseMessage, which drives the top-level
+// decoder, these targets feed raw bytes directly into the lower-level
+// section decoders (ParseQuestion, ParseAnswer) and into the responder's
+// packet-handling path, so a bug confined to one of those layers doesn't
+// need a fully-formed message around it to be found.
+package fuzz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/responder"
+)
+
+// FuzzParseQuestion tests message.ParseQuestion with random buffers and
+// offsets per NFR-003. Known Go-DNS-parser crashers are seeded explicitly:
+// a bare compression pointer (0xC0) with no second byte, a self-referential
+// pointer, and reserved label-length bits (0x40-0xBF).
+func FuzzParseQuestion(f *testing.F) {
+	validQuestion := []byte{
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // QTYPE = A
+		0x00, 0x01, // QCLASS = IN
+	}
+	f.Add(validQuestion, 0)
+
+	// Truncated pointer: 0xC0 with no following offset byte.
+	f.Add([]byte{0xC0}, 0)
+
+	// Self-referential compression pointer (offset 0 points to itself).
+	f.Add([]byte{0xC0, 0x00, 0x00, 0x01, 0x00, 0x01}, 0)
+
+	// Reserved label-length bits (0x40-0xBF are neither a plain label nor a
+	// compression pointer per RFC 1035 §4.1.4).
+	f.Add([]byte{0x40, 0x00, 0x01, 0x00, 0x01}, 0)
+	f.Add([]byte{0xBF, 0x00, 0x01, 0x00, 0x01}, 0)
+
+	// Negative and out-of-range offsets.
+	f.Add(validQuestion, -1)
+	f.Add(validQuestion, 1000)
+
+	f.Fuzz(func(_ *testing.T, data []byte, offset int) {
+		_, _, _ = message.ParseQuestion(data, offset)
+	})
+}
+
+// FuzzParseResourceRecord tests message.ParseAnswer with random buffers and
+// offsets per NFR-003, including an oversized RDLENGTH that claims more
+// bytes than the buffer has remaining.
+func FuzzParseResourceRecord(f *testing.F) {
+	validAnswer := []byte{
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01, // TYPE = A
+		0x00, 0x01, // CLASS = IN
+		0x00, 0x00, 0x00, 0x78, // TTL = 120
+		0x00, 0x04, // RDLENGTH = 4
+		192, 168, 1, 100,
+	}
+	f.Add(validAnswer, 0)
+
+	// RDLENGTH claims far more data than is actually present.
+	oversizedRDLENGTH := []byte{
+		0x04, 't', 'e', 's', 't',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x01,
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x78,
+		0xFF, 0xFF, // RDLENGTH = 65535, but no RDATA follows
+	}
+	f.Add(oversizedRDLENGTH, 0)
+
+	// Truncated pointer and self-referential pointer as the NAME.
+	f.Add([]byte{0xC0}, 0)
+	f.Add([]byte{0xC0, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x78, 0x00, 0x00}, 0)
+
+	f.Add(validAnswer, -1)
+	f.Add(validAnswer, 1000)
+
+	f.Fuzz(func(_ *testing.T, data []byte, offset int) {
+		_, _, _ = message.ParseAnswer(data, offset)
+	})
+}
+
+// FuzzHandlePacket pushes raw UDP payloads through the responder's query
+// handling path (Responder.HandlePacket, the same one runQueryHandler feeds
+// every packet it receives), bypassing the socket entirely. Seeds cover
+// valid PTR queries plus the same compression-pointer and malformed-length
+// crashers as FuzzParseQuestion/FuzzParseResourceRecord, applied to a whole
+// message rather than a single section.
+func FuzzHandlePacket(f *testing.F) {
+	validPTRQuery := []byte{
+		0x12, 0x34, // ID
+		0x00, 0x00, // Flags: standard query
+		0x00, 0x01, // QDCOUNT = 1
+		0x00, 0x00,
+		0x00, 0x00,
+		0x00, 0x00,
+		0x05, '_', 'h', 't', 't', 'p',
+		0x04, '_', 't', 'c', 'p',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x0C, // QTYPE = PTR
+		0x00, 0x01, // QCLASS = IN
+	}
+	f.Add(validPTRQuery)
+
+	serviceEnumQuery := []byte{
+		0x12, 0x34,
+		0x00, 0x00,
+		0x00, 0x01,
+		0x00, 0x00,
+		0x00, 0x00,
+		0x00, 0x00,
+		0x09, '_', 's', 'e', 'r', 'v', 'i', 'c', 'e', 's',
+		0x07, '_', 'd', 'n', 's', '-', 's', 'd',
+		0x04, '_', 'u', 'd', 'p',
+		0x05, 'l', 'o', 'c', 'a', 'l',
+		0x00,
+		0x00, 0x0C,
+		0x00, 0x01,
+	}
+	f.Add(serviceEnumQuery)
+
+	// Truncated label length (0xC0 pointer with no second byte).
+	f.Add([]byte{
+		0x12, 0x34, 0x00, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xC0,
+	})
+
+	// Self-referential compression pointer.
+	f.Add([]byte{
+		0x12, 0x34, 0x00, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xC0, 0x0C, 0x00, 0x0C, 0x00, 0x01,
+	})
+
+	// Reserved label-length byte (0x40-0xBF).
+	f.Add([]byte{
+		0x12, 0x34, 0x00, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x40, 0x00, 0x0C, 0x00, 0x01,
+	})
+
+	// Too short to even contain a header.
+	f.Add([]byte{0x12, 0x34})
+
+	f.Fuzz(func(t *testing.T, packet []byte) {
+		ctx := context.Background()
+		r, err := responder.New(ctx)
+		if err != nil {
+			t.Skip("Failed to create responder:", err)
+		}
+		defer func() { _ = r.Close() }()
+
+		// NO PANIC is the only requirement (NFR-003); a malformed or
+		// non-matching packet is expected to return an error or be ignored.
+		_ = r.HandlePacket(packet)
+	})
+}
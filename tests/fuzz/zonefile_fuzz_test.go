@@ -0,0 +1,59 @@
+// Package fuzz provides fuzz testing for the zonefile package.
+//
+// Fuzz testing validates that zone file parsing handles malformed
+// presentation-format input without crashes or panics per NFR-003, and that
+// parse -> serialize -> parse round trips never panic and, when the first
+// parse succeeds, always produce equivalent ResourceRecords.
+package fuzz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/joshuafuller/beacon/internal/zonefile"
+)
+
+// FuzzZoneParse tests zonefile.Parse/Write round trips with random inputs.
+//
+// NFR-003: System MUST handle malformed packets without crashes or panics
+//
+// Run with: go test -fuzz=FuzzZoneParse -fuzztime=10000x ./tests/fuzz/
+func FuzzZoneParse(f *testing.F) {
+	f.Add("test.local. 120 IN A 192.168.1.100")
+	f.Add("_http._tcp.local. 120 IN PTR My Printer._http._tcp.local")
+	f.Add("My Printer._http._tcp.local. 120 IN SRV 0 0 8080 myhost.local")
+	f.Add(`My Printer._http._tcp.local. 120 IN TXT "path=/" "version=1.0"`)
+	f.Add("test.local. 120 IN TYPE65 \\# 4 00010203")
+	f.Add("; just a comment")
+	f.Add("")
+	f.Add("garbage garbage garbage")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		records, err := zonefile.Parse(strings.NewReader(line))
+		if err != nil {
+			// Malformed input is expected to error, not panic.
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := zonefile.Write(&buf, records); err != nil {
+			t.Fatalf("Write() failed on output of successful Parse(): %v", err)
+		}
+
+		reparsed, err := zonefile.Parse(&buf)
+		if err != nil {
+			t.Fatalf("re-Parse() failed on our own Write() output: %v", err)
+		}
+
+		if len(reparsed) != len(records) {
+			t.Fatalf("round trip changed record count: %d != %d", len(reparsed), len(records))
+		}
+		for i := range records {
+			a, b := records[i], reparsed[i]
+			if a.Name != b.Name || a.Type != b.Type || a.Class != b.Class || a.TTL != b.TTL || !bytes.Equal(a.Data, b.Data) {
+				t.Fatalf("round trip produced non-equivalent record: %+v != %+v", a, b)
+			}
+		}
+	})
+}
@@ -46,10 +46,10 @@ func TestAvahiBrowse_ServiceDiscoverable(t *testing.T) {
 		InstanceName: "Beacon Integration Test",
 		ServiceType:  "_http._tcp.local",
 		Port:         8080,
-		TXTRecords: map[string]string{
+		TXTRecords: responder.NewTXTRecordsFromMap(map[string]string{
 			"version": "1.0",
 			"test":    "integration",
-		},
+		}),
 	}
 
 	start := time.Now()
@@ -299,11 +299,11 @@ func TestAvahiBrowse_TXTRecords(t *testing.T) {
 		InstanceName: "Beacon TXT Test",
 		ServiceType:  "_http._tcp.local",
 		Port:         8080,
-		TXTRecords: map[string]string{
+		TXTRecords: responder.NewTXTRecordsFromMap(map[string]string{
 			"version": "2.0",
 			"path":    "/api/v2",
 			"secure":  "true",
-		},
+		}),
 	}
 
 	err = r.Register(service)
@@ -331,8 +331,8 @@ func TestAvahiBrowse_TXTRecords(t *testing.T) {
 			// Format: =;interface;protocol;name;type;domain;host;address;port;txt...
 
 			// Check if TXT record key-value pairs appear in the line
-			for key, value := range service.TXTRecords {
-				expectedTXT := key + "=" + value
+			for _, record := range service.TXTRecords {
+				expectedTXT := record.Key + "=" + string(record.Value)
 				if !strings.Contains(line, expectedTXT) {
 					t.Errorf("TXT record %q not found in avahi-browse output", expectedTXT)
 				} else {
@@ -89,7 +89,7 @@ func TestQueryResponse_PTRQueryWithAdditionalRecords(t *testing.T) {
 		InstanceName: "TestService",
 		ServiceType:  "_http._tcp.local",
 		Port:         8080,
-		TXTRecords:   map[string]string{"txtvers": "1", "path": "/"},
+		TXTRecords:   responder.NewTXTRecordsFromMap(map[string]string{"txtvers": "1", "path": "/"}),
 	}
 
 	err = r.Register(service)
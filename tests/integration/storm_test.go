@@ -3,12 +3,24 @@ package integration
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/joshuafuller/beacon/internal/security"
 )
 
+func testRateLimiterConfig() security.RateLimiterConfig {
+	return security.RateLimiterConfig{
+		Burst:            100,
+		SustainedQPS:     100,
+		IPv4CIDR:         32,
+		IPv6CIDR:         128,
+		CooldownDuration: 60 * time.Second,
+		MaxEntries:       10000,
+	}
+}
+
 // TestMulticastStormSimulation validates rate limiter protects against multicast storms.
 // Per SC-005: CPU <20% under 1000 qps storm.
 // Per SC-006: Cooldown applied within 1 second of threshold breach.
@@ -19,10 +31,10 @@ func TestMulticastStormSimulation(t *testing.T) {
 	}
 
 	// Create rate limiter with default settings (100 qps threshold, 60s cooldown)
-	rl := security.NewRateLimiter(100, 60*time.Second, 10000)
+	rl := security.NewRateLimiter(testRateLimiterConfig())
 
-	floodingIP := "192.168.1.200"
-	legitimateIP := "192.168.1.50"
+	floodingIP := net.ParseIP("192.168.1.200")
+	legitimateIP := net.ParseIP("192.168.1.50")
 
 	// Phase 1: Simulate multicast storm (1000 queries/second from flooding source)
 	stormDuration := 2 * time.Second
@@ -113,7 +125,7 @@ stormComplete:
 // TestRateLimiterConcurrentAccess validates rate limiter handles concurrent queries safely.
 // Per data-model.md: RWMutex allows concurrent reads for hot path performance.
 func TestRateLimiterConcurrentAccess(t *testing.T) {
-	rl := security.NewRateLimiter(100, 60*time.Second, 10000)
+	rl := security.NewRateLimiter(testRateLimiterConfig())
 
 	// Simulate concurrent queries from multiple goroutines
 	numGoroutines := 10
@@ -123,7 +135,7 @@ func TestRateLimiterConcurrentAccess(t *testing.T) {
 
 	for g := 0; g < numGoroutines; g++ {
 		go func(goroutineID int) {
-			sourceIP := fmt.Sprintf("192.168.1.%d", goroutineID)
+			sourceIP := net.ParseIP(fmt.Sprintf("192.168.1.%d", goroutineID))
 			for i := 0; i < queriesPerGoroutine; i++ {
 				rl.Allow(sourceIP)
 			}
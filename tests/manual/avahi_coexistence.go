@@ -64,10 +64,10 @@ func main() {
 		InstanceName: "Beacon Test Service",
 		ServiceType:  "_http._tcp.local",
 		Port:         8080,
-		TXTRecords: map[string]string{
+		TXTRecords: responder.NewTXTRecordsFromMap(map[string]string{
 			"test":    "avahi-coexistence",
 			"version": "0.1.0",
-		},
+		}),
 	}
 
 	if err := r.Register(svc); err != nil {
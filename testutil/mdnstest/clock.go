@@ -0,0 +1,84 @@
+package mdnstest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time.Now/time.After, mirroring the
+// github.com/jonboulle/clockwork pattern, so a timing test can substitute
+// FakeClock for the wall clock instead of sleeping in real time.
+type Clock interface {
+	// Now returns the clock's current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time once d has
+	// elapsed on this clock.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock, backed by the real wall clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// fakeTimer is one pending FakeClock.After call awaiting Advance.
+type fakeTimer struct {
+	fireAt time.Time
+	ch     chan time.Time
+}
+
+// FakeClock is a Clock that only advances when Advance is called, letting a
+// test step through RFC 6762 timing (the §6.2 1-second rate limit, the
+// §8.3 250ms/1s/2s/4s/8s probe retransmit schedule, ...) instantly instead
+// of via real sleeps.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+// NewFakeClock creates a FakeClock starting at a fixed instant rather than
+// time.Now(), so two runs of the same test never disagree about elapsed
+// time because of when they happened to execute.
+func NewFakeClock() *FakeClock {
+	return &FakeClock{now: time.Unix(1700000000, 0)}
+}
+
+// Now returns the clock's current, fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that fires once Advance has moved the clock past
+// c.Now()+d, the fake analogue of time.After.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, fakeTimer{fireAt: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing every pending After timer
+// whose deadline has now passed (in the order they were scheduled).
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	remaining := c.timers[:0]
+	for _, t := range c.timers {
+		if !t.fireAt.After(c.now) {
+			t.ch <- c.now
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+	c.timers = remaining
+}
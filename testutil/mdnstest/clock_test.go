@@ -0,0 +1,64 @@
+package mdnstest
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFakeClock_NowIsFixedUntilAdvance verifies Now() never moves on its own.
+func TestFakeClock_NowIsFixedUntilAdvance(t *testing.T) {
+	c := NewFakeClock()
+	start := c.Now()
+
+	if got := c.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want unchanged %v", got, start)
+	}
+
+	c.Advance(5 * time.Second)
+	if got := c.Now(); !got.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("Now() after Advance(5s) = %v, want %v", got, start.Add(5*time.Second))
+	}
+}
+
+// TestFakeClock_AfterFiresOnceDeadlinePasses verifies After's channel only
+// receives once Advance has moved the clock past the requested duration, not
+// before.
+func TestFakeClock_AfterFiresOnceDeadlinePasses(t *testing.T) {
+	c := NewFakeClock()
+	ch := c.After(10 * time.Second)
+
+	c.Advance(5 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("After(10s) fired after only 5s elapsed")
+	default:
+	}
+
+	c.Advance(5 * time.Second)
+	select {
+	case <-ch:
+	default:
+		t.Fatal("After(10s) did not fire once 10s elapsed")
+	}
+}
+
+// TestFakeClock_AdvancePastMultipleTimers verifies a single Advance call
+// fires every pending timer whose deadline it crosses, not just the first.
+func TestFakeClock_AdvancePastMultipleTimers(t *testing.T) {
+	c := NewFakeClock()
+	short := c.After(1 * time.Second)
+	long := c.After(100 * time.Second)
+
+	c.Advance(2 * time.Second)
+
+	select {
+	case <-short:
+	default:
+		t.Error("After(1s) did not fire after Advance(2s)")
+	}
+	select {
+	case <-long:
+		t.Error("After(100s) fired after only 2s elapsed")
+	default:
+	}
+}
@@ -0,0 +1,234 @@
+// Package mdnstest provides an in-memory multicast fabric and fake clock for
+// exercising responder.Responder and querier.Querier together without real
+// sockets or real sleeps - the in-process analogue of standing up actual
+// peers on a LAN, mirroring the pattern of a black-box deterministic test
+// server (e.g. Consul's testutil.NewTestServerConfig).
+//
+// Fabric implements transport.Transport per participant (via Join), so it
+// can be injected into either package through their existing WithTransport
+// option in place of a real 224.0.0.251/FF02::FB socket, letting contract
+// tests drive cross-host behavior - probe conflicts, known-answer
+// suppression, cache-flush semantics on a peer - that responder.New's
+// GetLastAnnounceMessage/GetLastAnnounceDest alone can't observe.
+package mdnstest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/transport"
+)
+
+// fabricEndpointBuffer bounds how many undelivered packets a participant's
+// Receive queue holds before Fabric starts dropping new ones for it, the
+// same best-effort-delivery behavior a real multicast socket has under
+// load.
+const fabricEndpointBuffer = 64
+
+// Frame is one packet captured by Fabric.Capture: the raw bytes Send() was
+// given, parsed into a message.DNSMessage when they decode as one, and
+// timestamped against the Fabric's Clock.
+type Frame struct {
+	// From is the Join name of the participant that sent this frame.
+	From string
+
+	// Dest is the destination address Send() was given (often nil,
+	// meaning "the fabric's implicit multicast group").
+	Dest net.Addr
+
+	// Raw is the packet's wire bytes.
+	Raw []byte
+
+	// Message is Raw parsed via message.ParseMessage, or nil if it didn't
+	// decode as a DNS message.
+	Message *message.DNSMessage
+
+	// Sent is when the Fabric's Clock reports this frame was sent.
+	Sent time.Time
+}
+
+// fabricAddr is the net.Addr Fabric reports as a received packet's source:
+// a participant's Join name, since the fabric has no real network address
+// to offer.
+type fabricAddr string
+
+func (a fabricAddr) Network() string { return "mdnstest" }
+func (a fabricAddr) String() string  { return string(a) }
+
+// Fabric is an in-memory multicast medium: every participant's Join'd
+// transport.Transport broadcasts its Sends to every other current
+// participant, the fan-out a real multicast group provides without opening
+// a socket. Capture records every frame any participant has sent so far,
+// for assertions; InjectResponse delivers a packet to one participant as if
+// a peer outside the fabric had sent it.
+//
+// A Fabric's zero value is not usable - construct one with NewFabric or
+// NewFabricWithClock.
+type Fabric struct {
+	mu           sync.Mutex
+	participants map[string]*fabricEndpoint
+	frames       []Frame
+	clock        Clock
+}
+
+// NewFabric creates an empty Fabric using the real wall clock. Use
+// NewFabricWithClock(NewFakeClock()) instead for a timing test that needs
+// AdvanceClock rather than real sleeps.
+func NewFabric() *Fabric {
+	return NewFabricWithClock(realClock{})
+}
+
+// NewFabricWithClock creates an empty Fabric that timestamps Capture'd
+// frames using clock.
+func NewFabricWithClock(clock Clock) *Fabric {
+	return &Fabric{participants: make(map[string]*fabricEndpoint), clock: clock}
+}
+
+// Join admits a new participant named name to the fabric and returns its
+// transport.Transport handle: Send broadcasts to every other currently
+// joined participant, and Receive blocks for a packet broadcast by one of
+// them or delivered via InjectResponse. name labels this participant's
+// frames in Capture and is InjectResponse's routing key - it has no
+// relation to a real network address, and two participants must not share
+// one.
+func (f *Fabric) Join(name string) transport.Transport {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ep := &fabricEndpoint{
+		name:   name,
+		fabric: f,
+		recvCh: make(chan receivedPacket, fabricEndpointBuffer),
+	}
+	f.participants[name] = ep
+	return ep
+}
+
+// Capture returns every frame sent by any participant so far, in send
+// order. The returned slice is a snapshot; later sends don't affect it.
+func (f *Fabric) Capture() []Frame {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := make([]Frame, len(f.frames))
+	copy(out, f.frames)
+	return out
+}
+
+// InjectResponse delivers packet to the participant named to as though it
+// arrived from src (or from a synthetic "injected" address, if src is nil),
+// without involving any other participant - for simulating a peer's
+// response without modeling that peer as a full fabric participant.
+//
+// It returns an error if no participant named to has Join'd, or if that
+// participant's receive queue is full.
+func (f *Fabric) InjectResponse(to string, packet []byte, src net.Addr) error {
+	f.mu.Lock()
+	ep, ok := f.participants[to]
+	f.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("mdnstest: InjectResponse: no participant named %q has joined", to)
+	}
+
+	if src == nil {
+		src = fabricAddr("injected")
+	}
+
+	select {
+	case ep.recvCh <- receivedPacket{data: append([]byte(nil), packet...), src: src}:
+		return nil
+	default:
+		return fmt.Errorf("mdnstest: InjectResponse: participant %q's receive queue is full", to)
+	}
+}
+
+// AdvanceClock moves the fabric's clock forward by d. It's a no-op on a
+// Fabric built with the default real-time Clock (NewFabric) - use
+// NewFabricWithClock(NewFakeClock()) for a test that needs this to matter.
+func (f *Fabric) AdvanceClock(d time.Duration) {
+	if fc, ok := f.clock.(*FakeClock); ok {
+		fc.Advance(d)
+	}
+}
+
+// broadcast records a Frame for packet and delivers a copy to every
+// participant other than from.
+func (f *Fabric) broadcast(from string, packet []byte, dest net.Addr) {
+	f.mu.Lock()
+	frame := Frame{
+		From: from,
+		Dest: dest,
+		Raw:  append([]byte(nil), packet...),
+		Sent: f.clock.Now(),
+	}
+	if parsed, err := message.ParseMessage(packet); err == nil {
+		frame.Message = parsed
+	}
+	f.frames = append(f.frames, frame)
+
+	targets := make([]*fabricEndpoint, 0, len(f.participants))
+	for name, ep := range f.participants {
+		if name == from {
+			continue
+		}
+		targets = append(targets, ep)
+	}
+	f.mu.Unlock()
+
+	src := fabricAddr(from)
+	for _, ep := range targets {
+		select {
+		case ep.recvCh <- receivedPacket{data: append([]byte(nil), packet...), src: src}:
+		default:
+			// Drop rather than block the sender on a participant that isn't
+			// draining Receive, the same best-effort delivery a real
+			// multicast socket gives an overwhelmed listener.
+		}
+	}
+}
+
+// receivedPacket is one packet queued for a fabricEndpoint's Receive, via
+// either Fabric.broadcast or Fabric.InjectResponse.
+type receivedPacket struct {
+	data []byte
+	src  net.Addr
+}
+
+// fabricEndpoint is one participant's transport.Transport handle into a
+// Fabric, returned by Fabric.Join.
+type fabricEndpoint struct {
+	name   string
+	fabric *Fabric
+	recvCh chan receivedPacket
+}
+
+// Send broadcasts packet to every other participant currently joined to
+// e's fabric and records it in Fabric.Capture. dest is recorded on the
+// resulting Frame but otherwise ignored - a Fabric is a single flat
+// multicast domain, the same way a real mDNS multicast group doesn't
+// address individual peers.
+func (e *fabricEndpoint) Send(_ context.Context, packet []byte, dest net.Addr) error {
+	e.fabric.broadcast(e.name, packet, dest)
+	return nil
+}
+
+// Receive blocks until a packet broadcast by another participant (or
+// delivered via Fabric.InjectResponse) is available, or ctx is done.
+func (e *fabricEndpoint) Receive(ctx context.Context) ([]byte, net.Addr, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case pkt := <-e.recvCh:
+		return pkt.data, pkt.src, nil
+	}
+}
+
+// Close is a no-op: a fabricEndpoint holds no resources of its own beyond
+// its buffered channel, which garbage collects normally once unreferenced.
+func (e *fabricEndpoint) Close() error {
+	return nil
+}
@@ -0,0 +1,153 @@
+package mdnstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshuafuller/beacon/internal/message"
+	"github.com/joshuafuller/beacon/internal/protocol"
+)
+
+func mustBuildQuery(t *testing.T, name string) []byte {
+	t.Helper()
+	pkt, err := message.BuildQuery(name, uint16(protocol.RecordTypeA))
+	if err != nil {
+		t.Fatalf("BuildQuery(%q): %v", name, err)
+	}
+	return pkt
+}
+
+// TestFabric_BroadcastsToOtherParticipants verifies a Send from one
+// participant is delivered to every other joined participant, but not back
+// to the sender.
+func TestFabric_BroadcastsToOtherParticipants(t *testing.T) {
+	f := NewFabric()
+	a := f.Join("a")
+	b := f.Join("b")
+	c := f.Join("c")
+
+	pkt := mustBuildQuery(t, "myservice._http._tcp.local")
+	if err := a.Send(context.Background(), pkt, nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	gotB, srcB, err := b.Receive(ctx)
+	if err != nil {
+		t.Fatalf("b.Receive: %v", err)
+	}
+	if string(gotB) != string(pkt) {
+		t.Error("b received different bytes than a sent")
+	}
+	if srcB.String() != "a" {
+		t.Errorf("b's packet source = %q, want %q", srcB.String(), "a")
+	}
+
+	if _, _, err := c.Receive(ctx); err != nil {
+		t.Fatalf("c.Receive: %v", err)
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	if _, _, err := a.Receive(ctx2); err == nil {
+		t.Error("a received its own broadcast back")
+	}
+}
+
+// TestFabric_Capture verifies Capture records every frame sent so far,
+// including its parsed message.DNSMessage, and that the returned slice is a
+// snapshot unaffected by later sends.
+func TestFabric_Capture(t *testing.T) {
+	clock := NewFakeClock()
+	f := NewFabricWithClock(clock)
+	a := f.Join("a")
+	_ = f.Join("b")
+
+	pkt := mustBuildQuery(t, "myservice._http._tcp.local")
+	if err := a.Send(context.Background(), pkt, nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	frames := f.Capture()
+	if len(frames) != 1 {
+		t.Fatalf("Capture() returned %d frames, want 1", len(frames))
+	}
+	frame := frames[0]
+	if frame.From != "a" {
+		t.Errorf("frame.From = %q, want %q", frame.From, "a")
+	}
+	if frame.Message == nil {
+		t.Fatal("frame.Message is nil, want the parsed query")
+	}
+	if len(frame.Message.Questions) != 1 || frame.Message.Questions[0].QNAME != "myservice._http._tcp.local" {
+		t.Errorf("frame.Message.Questions = %+v, want one question for myservice._http._tcp.local", frame.Message.Questions)
+	}
+	if !frame.Sent.Equal(clock.Now()) {
+		t.Errorf("frame.Sent = %v, want clock's time %v", frame.Sent, clock.Now())
+	}
+
+	if err := a.Send(context.Background(), mustBuildQuery(t, "other.local"), nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(frames) != 1 {
+		t.Error("earlier Capture() snapshot changed after a later Send")
+	}
+}
+
+// TestFabric_InjectResponse verifies InjectResponse delivers a packet to one
+// named participant without involving any other participant or recording a
+// Capture frame.
+func TestFabric_InjectResponse(t *testing.T) {
+	f := NewFabric()
+	a := f.Join("a")
+	_ = f.Join("b")
+
+	pkt := mustBuildQuery(t, "myservice._http._tcp.local")
+	if err := f.InjectResponse("a", pkt, nil); err != nil {
+		t.Fatalf("InjectResponse: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, src, err := a.Receive(ctx)
+	if err != nil {
+		t.Fatalf("a.Receive: %v", err)
+	}
+	if string(got) != string(pkt) {
+		t.Error("a received different bytes than were injected")
+	}
+	if src.String() != "injected" {
+		t.Errorf("injected packet's source = %q, want %q", src.String(), "injected")
+	}
+
+	if len(f.Capture()) != 0 {
+		t.Error("InjectResponse recorded a Capture frame, want none")
+	}
+
+	if err := f.InjectResponse("nobody", pkt, nil); err == nil {
+		t.Error("InjectResponse to an unjoined participant returned nil error, want one")
+	}
+}
+
+// TestFabric_AdvanceClock verifies AdvanceClock moves a fabric built with a
+// FakeClock forward, affecting the Sent timestamp on later Capture'd frames.
+func TestFabric_AdvanceClock(t *testing.T) {
+	clock := NewFakeClock()
+	f := NewFabricWithClock(clock)
+	a := f.Join("a")
+	_ = f.Join("b")
+
+	start := clock.Now()
+	f.AdvanceClock(30 * time.Second)
+
+	if err := a.Send(context.Background(), mustBuildQuery(t, "myservice._http._tcp.local"), nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	frames := f.Capture()
+	if !frames[0].Sent.Equal(start.Add(30 * time.Second)) {
+		t.Errorf("frame.Sent = %v, want %v", frames[0].Sent, start.Add(30*time.Second))
+	}
+}